@@ -0,0 +1,68 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backupcmd
+
+import (
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app        *application.Avalanche
+	cliVersion string
+)
+
+// avalanche backup
+func NewCmd(injectedApp *application.Avalanche, version string) *cobra.Command {
+	app = injectedApp
+	cliVersion = version
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Backup and restore the CLI state",
+		Long: `The backup command suite creates and restores encrypted backups of the CLI state, so
+that it can be moved to a new machine or recovered after a loss.
+
+A backup covers stored keys, blockchain sidecars and configs, cluster configs, and network state
+(devnets and local network snapshots). Backups are passphrase encrypted with the age format
+(https://age-encryption.org), and contain a manifest used to check their integrity before
+restoring them.
+
+The passphrase is prompted for, or taken from the ` + constants.BackupPassphraseEnvVarName + `
+environment variable.`,
+		RunE: cobrautils.CommandSuiteUsage,
+		Args: cobrautils.ExactArgs(0),
+	}
+	// backup create
+	cmd.AddCommand(newCreateCmd())
+	// backup restore
+	cmd.AddCommand(newRestoreCmd())
+	return cmd
+}
+
+func getPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(constants.BackupPassphraseEnvVarName); passphrase != "" {
+		return passphrase, nil
+	}
+	for {
+		passphrase, err := app.Prompt.CapturePassword("Backup passphrase")
+		if err != nil {
+			return "", err
+		}
+		if !confirm {
+			return passphrase, nil
+		}
+		confirmation, err := app.Prompt.CapturePassword("Confirm backup passphrase")
+		if err != nil {
+			return "", err
+		}
+		if passphrase == confirmation {
+			return passphrase, nil
+		}
+		ux.Logger.RedXToUser("Passphrases do not match")
+	}
+}
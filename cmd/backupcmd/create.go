@@ -0,0 +1,71 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backupcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/backup"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outputPath string
+	only       []string
+	force      bool
+)
+
+// avalanche backup create
+func newCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an encrypted backup of the CLI state",
+		Long: `The backup create command writes an encrypted backup of the CLI state into the given output file.
+
+By default all the state is backed up. Use --only to back up some categories only.`,
+		RunE: createBackup,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&outputPath, "output", "", "file to write the backup to (e.g. backup.tar.age)")
+	cmd.Flags().StringSliceVar(&only, "only", nil, fmt.Sprintf("only back up the given categories (%s)", strings.Join(backup.Categories, ", ")))
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite the output file if it exists")
+	return cmd
+}
+
+func createBackup(_ *cobra.Command, _ []string) error {
+	var err error
+	if outputPath == "" {
+		outputPath, err = app.Prompt.CaptureNewFilepath("Backup output file")
+		if err != nil {
+			return err
+		}
+	}
+	outputPath = utils.ExpandHome(outputPath)
+	if utils.FileExists(outputPath) && !force {
+		return fmt.Errorf("file %s already exists. use --force to overwrite it", outputPath)
+	}
+	categories := only
+	if len(categories) == 0 {
+		categories = backup.Categories
+	}
+	passphrase, err := getPassphrase(true)
+	if err != nil {
+		return err
+	}
+	manifest, err := backup.Create(app.GetBaseDir(), outputPath, passphrase, cliVersion, categories)
+	if err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser(
+		"Backup of %s written to %s (%d files)",
+		strings.Join(manifest.Categories, ", "),
+		outputPath,
+		len(manifest.Files),
+	)
+	ux.Logger.PrintToUser("The backup can only be restored with its passphrase. Keep it safe")
+	return nil
+}
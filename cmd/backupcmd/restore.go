@@ -0,0 +1,71 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backupcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/backup"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var inputPath string
+
+// avalanche backup restore
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the CLI state from an encrypted backup",
+		Long: `The backup restore command restores the CLI state from a backup created with backup create.
+
+The whole backup is decrypted and checked against its manifest before anything is written. Files
+already present with a different content are not overwritten, unless --force is given. Use --only
+to restore some categories only, e.g. --only keys.`,
+		RunE: restoreBackup,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&inputPath, "input", "", "backup file to restore")
+	cmd.Flags().StringSliceVar(&only, "only", nil, fmt.Sprintf("only restore the given categories (%s)", strings.Join(backup.Categories, ", ")))
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite existing files with the backup content")
+	return cmd
+}
+
+func restoreBackup(_ *cobra.Command, _ []string) error {
+	var err error
+	if inputPath == "" {
+		inputPath, err = app.Prompt.CaptureExistingFilepath("Backup file")
+		if err != nil {
+			return err
+		}
+	}
+	inputPath = utils.ExpandHome(inputPath)
+	if !utils.FileExists(inputPath) {
+		return fmt.Errorf("backup file %s not found", inputPath)
+	}
+	passphrase, err := getPassphrase(false)
+	if err != nil {
+		return err
+	}
+	result, err := backup.Restore(app.GetBaseDir(), inputPath, passphrase, only, force)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser(
+		"Backup created at %s by CLI %s",
+		result.Manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"),
+		result.Manifest.CLIVersion,
+	)
+	for _, filePath := range result.Restored {
+		ux.Logger.PrintToUser("  restored %s", filePath)
+	}
+	ux.Logger.GreenCheckmarkToUser(
+		"%d files restored, %d already up to date",
+		len(result.Restored),
+		len(result.Unchanged),
+	)
+	return nil
+}
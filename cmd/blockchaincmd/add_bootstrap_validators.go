@@ -0,0 +1,459 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	warpMessage "github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	addBootstrapValidatorsSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Fuji,
+		networkoptions.Mainnet,
+	}
+	addBootstrapValidatorsDryRun bool
+)
+
+// avalanche blockchain addBootstrapValidators
+func newAddBootstrapValidatorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "addBootstrapValidators [blockchainName]",
+		Short: "Register the bootstrap validators left out of the L1 conversion",
+		Long: `The blockchain addBootstrapValidators command registers on the validator manager the
+bootstrap validators that were left out of the ConvertSubnetToL1Tx.
+
+When an L1 is deployed or converted with more bootstrap validators than --bootstrap-batch-size,
+only the heaviest ones are set on the conversion, and the remaining ones are registered
+afterwards. Registrations are split into batches that fit in the churn limit of the validator
+manager, waiting for the next churn period between batches when the manager has one.
+
+Progress is saved after each registered validator, so an interrupted execution is resumed by
+running the command again. Registrations interrupted after being initialized on the validator
+manager are completed instead of initialized again.
+
+Deploy and convert run these registrations once the validator manager is initialized.
+Use --dry-run to only show the planned batches.`,
+		RunE: addBootstrapValidators,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, addBootstrapValidatorsSupportedNetworkOptions)
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use to pay for the validators P-Chain balance [fuji/devnet only]")
+	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [fuji/devnet only]")
+	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji/devnet)")
+	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().StringVar(&rpcURL, "rpc", "", "connect to the L1 at the given rpc endpoint")
+	cmd.Flags().StringSliceVar(&aggregatorExtraEndpoints, "aggregator-extra-endpoints", nil, "endpoints for extra nodes that are needed in signature aggregation")
+	cmd.Flags().BoolVar(&aggregatorAllowPrivatePeers, "aggregator-allow-private-peers", true, "allow the signature aggregator to connect to peers with private IP")
+	cmd.Flags().StringVar(&aggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	interchain.AddAggregationFlagsToCmd(cmd, &aggregationFlags)
+	cmd.Flags().BoolVar(&addBootstrapValidatorsDryRun, "dry-run", false, "only show the planned registration batches")
+	return cmd
+}
+
+func addBootstrapValidators(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.GetNetworkFromSidecar(sc, addBootstrapValidatorsSupportedNetworkOptions),
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		clusterNameFlagValue = network.ClusterName
+		network = models.ConvertClusterToNetwork(network)
+	}
+	networkData := sc.Networks[network.Name()]
+	if networkData.SubnetID == ids.Empty {
+		return fmt.Errorf("blockchain %s has not been deployed to %s", blockchainName, network.Name())
+	}
+	pending := networkData.PendingBootstrapValidators
+	if len(pending) == 0 {
+		ux.Logger.PrintToUser("No bootstrap validators of %s are pending registration on %s", blockchainName, network.Name())
+		return nil
+	}
+	if addBootstrapValidatorsDryRun {
+		if rpcURL == "" {
+			rpcURL, _, err = contract.GetBlockchainEndpoints(app, network, contract.ChainSpec{BlockchainName: blockchainName}, true, false)
+			if err != nil {
+				return err
+			}
+		}
+		state, err := validatormanager.GetManagerState(rpcURL, validatormanager.GetValidatorManagerAddress(networkData))
+		if err != nil {
+			return err
+		}
+		batches, err := validatormanager.PlanChurnBatches(state, uint64(time.Now().Unix()), getValidatorWeights(pending))
+		if err != nil {
+			return err
+		}
+		printBootstrapBatches(pending, batches)
+		return nil
+	}
+	requiredBalance := uint64(0)
+	for _, validator := range pending {
+		requiredBalance += validator.Balance
+	}
+	fee := network.GenesisParams().TxFeeConfig.StaticFeeConfig.TxFee
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		"to pay for the validators P-Chain balance",
+		network,
+		keyName,
+		useEwoq,
+		useLedger,
+		ledgerAddresses,
+		fee+requiredBalance,
+	)
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewPublicDeployer(app, kc, network)
+	return registerPendingBootstrapValidators(deployer, network, blockchainName)
+}
+
+// splitBootstrapValidators keeps the [batchSize] heaviest of [validators] for the conversion
+// tx, so the L1 starts with as much weight as possible, and returns the remaining ones to be
+// registered afterwards. Fails if those can not be registered within the churn limit a new
+// validator manager starts with
+func splitBootstrapValidators(
+	sc models.Sidecar,
+	validators []models.SubnetValidator,
+	batchSize int,
+) ([]models.SubnetValidator, []models.SubnetValidator, error) {
+	if batchSize <= 0 {
+		return nil, nil, fmt.Errorf("bootstrap batch size must be positive")
+	}
+	if len(validators) <= batchSize {
+		return validators, nil, nil
+	}
+	if sc.PoS() {
+		return nil, nil, fmt.Errorf(
+			"proof of stake L1s require all %d bootstrap validators on the conversion. increase --bootstrap-batch-size",
+			len(validators),
+		)
+	}
+	sorted := append([]models.SubnetValidator{}, validators...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Weight > sorted[j].Weight })
+	initial, pending := sorted[:batchSize], sorted[batchSize:]
+	initialWeight := uint64(0)
+	for _, validator := range initial {
+		initialWeight += validator.Weight
+	}
+	state := validatormanager.ManagerState{
+		ChurnPeriodSeconds:     validatorManagerSDK.DefaultChurnPeriodSeconds,
+		MaximumChurnPercentage: validatorManagerSDK.DefaultMaximumChurnPercentage,
+		ChurnPeriodTotalWeight: initialWeight,
+	}
+	if _, err := validatormanager.PlanChurnBatches(state, uint64(time.Now().Unix()), getValidatorWeights(pending)); err != nil {
+		return nil, nil, fmt.Errorf("bootstrap validators left out of the conversion can not be registered: %w. increase --bootstrap-batch-size", err)
+	}
+	ux.Logger.PrintToUser(
+		"%d of the %d bootstrap validators are set on the conversion. The remaining %d are registered after the validator manager is initialized",
+		len(initial),
+		len(validators),
+		len(pending),
+	)
+	return initial, pending, nil
+}
+
+// registerPendingBootstrapValidators registers the pending bootstrap validators of [blockchainName]
+// on its validator manager, in batches that fit in the manager churn limit. The sidecar is updated
+// after each registration, so the remaining ones can be resumed if interrupted
+func registerPendingBootstrapValidators(
+	deployer *subnet.PublicDeployer,
+	network models.Network,
+	blockchainName string,
+) error {
+	chainSpec := contract.ChainSpec{
+		BlockchainName: blockchainName,
+	}
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if sc.PoS() {
+		return fmt.Errorf("bootstrap validators can only be registered after the conversion on proof of authority L1s")
+	}
+	networkData := sc.Networks[network.Name()]
+	if rpcURL == "" {
+		rpcURL, _, err = contract.GetBlockchainEndpoints(app, network, chainSpec, true, false)
+		if err != nil {
+			return err
+		}
+	}
+	ownerPrivateKeyFound, _, _, ownerPrivateKey, err := contract.SearchForManagedKey(
+		app,
+		network,
+		common.HexToAddress(sc.ValidatorManagerOwner),
+		true,
+	)
+	if err != nil {
+		return err
+	}
+	if !ownerPrivateKeyFound {
+		return fmt.Errorf("private key for Validator manager owner %s is not found", sc.ValidatorManagerOwner)
+	}
+	if clusterNameFlagValue == "" {
+		clusterNameFlagValue = networkData.ClusterName
+	}
+	extraAggregatorPeers, err := GetAggregatorExtraPeers(clusterNameFlagValue, aggregatorExtraEndpoints)
+	if err != nil {
+		return err
+	}
+	managerAddress := validatormanager.GetValidatorManagerAddress(networkData)
+
+	total := len(networkData.PendingBootstrapValidators)
+	registered := 0
+	for len(networkData.PendingBootstrapValidators) > 0 {
+		pending := networkData.PendingBootstrapValidators
+		// the plan is done again for each batch, as the churn period starts on the first
+		// registration made after the previous one ended
+		state, err := validatormanager.GetManagerState(rpcURL, managerAddress)
+		if err != nil {
+			return err
+		}
+		batches, err := validatormanager.PlanChurnBatches(state, uint64(time.Now().Unix()), getValidatorWeights(pending))
+		if err != nil {
+			return err
+		}
+		if registered == 0 {
+			printBootstrapBatches(pending, batches)
+		}
+		batch := batches[0]
+		if wait := time.Until(time.Unix(int64(batch.NotBefore), 0)); wait > 0 {
+			if _, err := ux.TimedProgressBar(
+				wait,
+				"Waiting for the next churn period of the validator manager ...",
+				0,
+			); err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("")
+		}
+		for _, index := range batch.Indexes {
+			validator := pending[index]
+			registered++
+			ux.Logger.PrintToUser("Registering bootstrap validator %s (%d/%d) ...", validator.NodeID, registered, total)
+			if err := registerBootstrapValidator(
+				deployer,
+				network,
+				chainSpec,
+				ownerPrivateKey,
+				extraAggregatorPeers,
+				validator,
+			); err != nil {
+				ux.Logger.RedXToUser(
+					"%d bootstrap validators are pending registration. Resume with avalanche blockchain addBootstrapValidators %s",
+					len(networkData.PendingBootstrapValidators),
+					blockchainName,
+				)
+				return fmt.Errorf("failure registering bootstrap validator %s: %w", validator.NodeID, err)
+			}
+			networkData.PendingBootstrapValidators = utils.Filter(
+				networkData.PendingBootstrapValidators,
+				func(v models.SubnetValidator) bool { return v.NodeID != validator.NodeID },
+			)
+			sc.Networks[network.Name()] = networkData
+			if err := app.UpdateSidecar(&sc); err != nil {
+				return err
+			}
+		}
+	}
+	ux.Logger.GreenCheckmarkToUser("%d bootstrap validators registered on %s", total, blockchainName)
+	return nil
+}
+
+// registerBootstrapValidator registers [validator] on the validator manager and P-Chain. A
+// registration of it initialized by a previous execution is completed instead
+func registerBootstrapValidator(
+	deployer *subnet.PublicDeployer,
+	network models.Network,
+	chainSpec contract.ChainSpec,
+	ownerPrivateKey string,
+	extraAggregatorPeers []info.Peer,
+	validator models.SubnetValidator,
+) error {
+	aggregationConfig, err := interchain.GetAggregationConfig(app, aggregationFlags)
+	if err != nil {
+		return err
+	}
+	registrations, err := app.LoadValidatorRegistrations()
+	if err != nil {
+		return err
+	}
+	for _, registration := range registrations.Registrations {
+		if registration.BlockchainName != chainSpec.BlockchainName || registration.Network != network.Name() || registration.NodeID != validator.NodeID {
+			continue
+		}
+		validationID, err := ids.FromString(registration.ValidationID)
+		if err != nil {
+			return err
+		}
+		registeredOnPChain, err := IsRegisteredOnPChain(network, validationID)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Completing the registration initialized by a previous execution")
+		return ReissueValidatorRegistration(
+			network,
+			deployer,
+			registration,
+			validationID,
+			registeredOnPChain,
+			rpcURL,
+			aggregatorExtraEndpoints,
+			aggregatorAllowPrivatePeers,
+			aggregatorLogLevel,
+			aggregationConfig,
+		)
+	}
+
+	nodeID, err := ids.NodeIDFromString(validator.NodeID)
+	if err != nil {
+		return err
+	}
+	blsInfo, err := GetBLSInfo(validator.BLSPublicKey, validator.BLSProofOfPossession)
+	if err != nil {
+		return fmt.Errorf("failure parsing BLS info: %w", err)
+	}
+	ownerAddrIDs, err := address.ParseToIDs([]string{validator.ChangeOwnerAddr})
+	if err != nil {
+		return fmt.Errorf("failure parsing change owner address %s: %w", validator.ChangeOwnerAddr, err)
+	}
+	// same owners the bootstrap validators set on the conversion get
+	owners := warpMessage.PChainOwner{
+		Threshold: 1,
+		Addresses: ownerAddrIDs,
+	}
+	blockchainTimestamp, err := getBlockchainTimestamp(network)
+	if err != nil {
+		return fmt.Errorf("failed to get blockchain timestamp: %w", err)
+	}
+	expiry := uint64(blockchainTimestamp.Add(constants.DefaultValidationIDExpiryDuration).Unix())
+	signedMessage, validationID, err := validatormanager.InitValidatorRegistration(
+		app,
+		network,
+		rpcURL,
+		chainSpec,
+		ownerPrivateKey,
+		nodeID,
+		blsInfo.PublicKey[:],
+		expiry,
+		owners,
+		owners,
+		validator.Weight,
+		extraAggregatorPeers,
+		aggregatorAllowPrivatePeers,
+		aggregatorLogLevel,
+		aggregationConfig,
+		false,
+		0,
+		0,
+		big.NewInt(0),
+		false,
+	)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("ValidationID: %s", validationID)
+	trackValidatorRegistration(
+		network,
+		chainSpec.BlockchainName,
+		nodeID,
+		validationID,
+		signedMessage,
+		validator.BLSPublicKey,
+		validator.BLSProofOfPossession,
+		validator.Balance,
+	)
+	txID, _, err := deployer.RegisterL1Validator(validator.Balance, blsInfo, signedMessage)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("RegisterL1ValidatorTx ID: %s", txID)
+	if err := UpdatePChainHeight(
+		"Waiting for P-Chain to update validator information ...",
+	); err != nil {
+		return err
+	}
+	if err := validatormanager.FinishValidatorRegistration(
+		app,
+		network,
+		rpcURL,
+		chainSpec,
+		ownerPrivateKey,
+		validationID,
+		extraAggregatorPeers,
+		aggregatorAllowPrivatePeers,
+		aggregatorLogLevel,
+		aggregationConfig,
+	); err != nil {
+		return err
+	}
+	if err := app.UntrackValidatorRegistration(validationID.String()); err != nil {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("failure removing the completed validator registration from tracking: %s"), err)
+	}
+	ux.Logger.GreenCheckmarkToUser("Validator %s registered", validator.NodeID)
+	return nil
+}
+
+func getValidatorWeights(validators []models.SubnetValidator) []uint64 {
+	return utils.Map(validators, func(v models.SubnetValidator) uint64 { return v.Weight })
+}
+
+func printBootstrapBatches(validators []models.SubnetValidator, batches []validatormanager.ChurnBatch) {
+	ux.Logger.PrintToUser("%d bootstrap validators pending registration, in %d batches:", len(validators), len(batches))
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Batch", "Validators", "Weight", "Not Before"})
+	table.SetRowLine(true)
+	for i, batch := range batches {
+		notBefore := "now"
+		if t := time.Unix(int64(batch.NotBefore), 0); t.After(time.Now()) {
+			notBefore = t.Format(constants.TimeParseLayout)
+		}
+		table.Append([]string{
+			fmt.Sprintf("%d", i+1),
+			fmt.Sprintf("%d", len(batch.Indexes)),
+			fmt.Sprintf("%d", batch.Weight),
+			notBefore,
+		})
+	}
+	table.Render()
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/events"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
 	"github.com/ava-labs/avalanche-cli/pkg/keychain"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
@@ -31,6 +33,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	warpMessage "github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
 	"github.com/spf13/cobra"
 )
@@ -69,6 +72,7 @@ var (
 	ErrNotPermissionedSubnet            = errors.New("subnet is not permissioned")
 	aggregatorExtraEndpoints            []string
 	aggregatorAllowPrivatePeers         bool
+	aggregationFlags                    interchain.AggregationFlags
 	clusterNameFlagValue                string
 
 	createLocalValidator bool
@@ -98,6 +102,7 @@ Testnet or Mainnet.`,
 	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [fuji/devnet only]")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji/devnet)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().StringSliceVar(&custodyAddresses, "custody-addrs", nil, "(for non-SOV blockchain only) pay with the given P-Chain addresses of keys kept by custodial signers, saving the tx for them to sign")
 	cmd.Flags().StringVar(&nodeIDStr, "node-id", "", "node-id of the validator to add")
 	cmd.Flags().StringVar(&publicKey, "bls-public-key", "", "set the BLS public key of the validator to add")
 	cmd.Flags().StringVar(&pop, "bls-proof-of-possession", "", "set the BLS proof of possession of the validator to add")
@@ -111,6 +116,7 @@ Testnet or Mainnet.`,
 	privateKeyFlags.AddToCmd(cmd, "to pay fees for completing the validator's registration (blockchain gas token)")
 	cmd.Flags().StringVar(&rpcURL, "rpc", "", "connect to validator manager at the given rpc endpoint")
 	cmd.Flags().StringVar(&aggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	interchain.AddAggregationFlagsToCmd(cmd, &aggregationFlags)
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long this validator will be staking")
 	cmd.Flags().BoolVar(&useDefaultStartTime, "default-start-time", false, "(for Subnets, not L1s) use default start time for subnet validator (5 minutes later for fuji & mainnet, 30 seconds later for devnet)")
 	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "(for Subnets, not L1s) UTC start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format")
@@ -121,6 +127,7 @@ Testnet or Mainnet.`,
 	cmd.Flags().BoolVar(&waitForTxAcceptance, "wait-for-tx-acceptance", true, "(for Subnets, not L1s) just issue the add validator tx, without waiting for its acceptance")
 	cmd.Flags().Uint64Var(&stakeAmount, "stake-amount", 0, "(PoS only) amount of tokens to stake")
 	cmd.Flags().Uint16Var(&delegationFee, "delegation-fee", 100, "(PoS only) delegation fee (in bips)")
+	cmd.Flags().BoolVar(&simulate, "simulate", false, "(for L1s only) "+simulateFlagDescription)
 
 	return cmd
 }
@@ -175,22 +182,36 @@ func addValidator(_ *cobra.Command, args []string) error {
 		clusterNameFlagValue = sc.Networks[network.Name()].ClusterName
 	}
 
+	sovereign := sc.Sovereign
 	fee := network.GenesisParams().TxFeeConfig.StaticFeeConfig.AddSubnetValidatorFee
-	kc, err := keychain.GetKeychainFromCmdLineFlags(
-		app,
-		"to pay for transaction fees on P-Chain",
-		network,
-		keyName,
-		useEwoq,
-		useLedger,
-		ledgerAddresses,
-		fee,
-	)
+	var kc *keychain.Keychain
+	if len(custodyAddresses) > 0 {
+		if sovereign {
+			return fmt.Errorf("--custody-addrs is only supported for non-SOV (Subnet-Only Validators) blockchains")
+		}
+		if keyName != "" || useEwoq || useLedger || len(ledgerAddresses) > 0 {
+			return keychain.ErrCustodyWithKeySource
+		}
+		kc, err = keychain.GetCustodyKeychain(network, custodyAddresses)
+	} else {
+		kc, err = keychain.GetKeychainFromCmdLineFlags(
+			app,
+			"to pay for transaction fees on P-Chain",
+			network,
+			keyName,
+			useEwoq,
+			useLedger,
+			ledgerAddresses,
+			fee,
+		)
+	}
 	if err != nil {
 		return err
 	}
 
-	sovereign := sc.Sovereign
+	if simulate && !sovereign {
+		return fmt.Errorf("--simulate is only supported for L1s")
+	}
 
 	if nodeEndpoint != "" {
 		nodeIDStr, publicKey, pop, err = node.GetNodeData(nodeEndpoint)
@@ -216,6 +237,10 @@ func addValidator(_ *cobra.Command, args []string) error {
 		}
 	}
 
+	if simulate && createLocalValidator {
+		return fmt.Errorf("cannot simulate the addition of a new local validator")
+	}
+
 	// if user chose to upsize a local node to add another local validator
 	if createLocalValidator {
 		anrSettings := node.ANRSettings{}
@@ -323,7 +348,7 @@ func CallAddValidator(
 	if err != nil {
 		return err
 	}
-	blsInfo, err := getBLSInfo(publicKey, pop)
+	blsInfo, err := GetBLSInfo(publicKey, pop)
 	if err != nil {
 		return fmt.Errorf("failure parsing BLS info: %w", err)
 	}
@@ -361,7 +386,7 @@ func CallAddValidator(
 		// should take input prior to here for stake amount, delegation fee, and min stake duration
 		if stakeAmount == 0 {
 			stakeAmount, err = app.Prompt.CaptureUint64Compare(
-				fmt.Sprintf("Enter the amount of %s to stake ", sc.TokenName),
+				fmt.Sprintf("Enter the amount of %s to stake ", sc.StakedTokenName()),
 				[]prompts.Comparator{
 					{
 						Label: "Positive",
@@ -382,6 +407,9 @@ func CallAddValidator(
 		}
 	}
 	ux.Logger.PrintToUser(logging.Yellow.Wrap("Validation manager owner %s pays for the initialization of the validator's registration (Blockchain gas token)"), sc.ValidatorManagerOwner)
+	if sc.ERC20Staking() {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Validation manager owner %s stakes the ERC20 staking token, approving it to the Validator Manager if needed"), sc.ValidatorManagerOwner)
+	}
 
 	if rpcURL == "" {
 		rpcURL, _, err = contract.GetBlockchainEndpoints(
@@ -451,10 +479,37 @@ func CallAddValidator(
 		Addresses: disableOwnerAddrID,
 	}
 
+	if simulate {
+		report, err := validatormanager.SimulateValidatorRegistration(
+			rpcURL,
+			ownerPrivateKey,
+			nodeID,
+			blsInfo.PublicKey[:],
+			expiry,
+			remainingBalanceOwners,
+			disableOwners,
+			weight,
+			pos,
+			delegationFee,
+			duration,
+			big.NewInt(int64(stakeAmount)),
+			sc.ERC20Staking(),
+		)
+		if err != nil {
+			return err
+		}
+		printSimulationReport(report)
+		return nil
+	}
+
 	extraAggregatorPeers, err := GetAggregatorExtraPeers(clusterNameFlagValue, aggregatorExtraEndpoints)
 	if err != nil {
 		return err
 	}
+	aggregationConfig, err := interchain.GetAggregationConfig(app, aggregationFlags)
+	if err != nil {
+		return err
+	}
 	signedMessage, validationID, err := validatormanager.InitValidatorRegistration(
 		app,
 		network,
@@ -470,15 +525,18 @@ func CallAddValidator(
 		extraAggregatorPeers,
 		aggregatorAllowPrivatePeers,
 		aggregatorLogLevel,
+		aggregationConfig,
 		pos,
 		delegationFee,
 		duration,
 		big.NewInt(int64(stakeAmount)),
+		sc.ERC20Staking(),
 	)
 	if err != nil {
 		return err
 	}
 	ux.Logger.PrintToUser("ValidationID: %s", validationID)
+	trackValidatorRegistration(network, blockchainName, nodeID, validationID, signedMessage, publicKey, pop, balance)
 
 	txID, _, err := deployer.RegisterL1Validator(balance, blsInfo, signedMessage)
 	if err != nil {
@@ -502,9 +560,13 @@ func CallAddValidator(
 		extraAggregatorPeers,
 		aggregatorAllowPrivatePeers,
 		aggregatorLogLevel,
+		aggregationConfig,
 	); err != nil {
 		return err
 	}
+	if err := app.UntrackValidatorRegistration(validationID.String()); err != nil {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("failure removing the completed validator registration from tracking: %s"), err)
+	}
 
 	ux.Logger.PrintToUser("  NodeID: %s", nodeID)
 	ux.Logger.PrintToUser("  Network: %s", network.Name())
@@ -514,10 +576,47 @@ func CallAddValidator(
 	}
 	ux.Logger.PrintToUser("  Balance: %d", balance/units.Avax)
 	ux.Logger.GreenCheckmarkToUser("Validator successfully added to the L1")
+	events.Emit(app, events.ValidatorAdded, fmt.Sprintf("Validator %s added to %s on %s", nodeID, blockchainName, network.Name()), map[string]interface{}{
+		"blockchain":   blockchainName,
+		"network":      network.Name(),
+		"nodeID":       nodeID.String(),
+		"validationID": validationID.String(),
+	})
 
 	return nil
 }
 
+// trackValidatorRegistration records the initialized registration until it is completed, so
+// that avalanche validator watch can warn before its expiry, and re-issue it if interrupted
+func trackValidatorRegistration(
+	network models.Network,
+	blockchainName string,
+	nodeID ids.NodeID,
+	validationID ids.ID,
+	signedMessage *warp.Message,
+	publicKey string,
+	pop string,
+	balance uint64,
+) {
+	expiry, err := validatormanager.GetRegistrationExpiry(signedMessage)
+	if err == nil {
+		err = app.TrackValidatorRegistration(models.ValidatorRegistration{
+			BlockchainName:       blockchainName,
+			Network:              network.Name(),
+			NodeID:               nodeID.String(),
+			ValidationID:         validationID.String(),
+			Expiry:               expiry,
+			BLSPublicKey:         publicKey,
+			BLSProofOfPossession: pop,
+			Balance:              balance,
+			RPCURL:               rpcURL,
+		})
+	}
+	if err != nil {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("failure tracking the validator registration expiry: %s"), err)
+	}
+}
+
 func CallAddValidatorNonSOV(
 	deployer *subnet.PublicDeployer,
 	network models.Network,
@@ -652,19 +751,9 @@ func CallAddValidatorNonSOV(
 
 func PromptDuration(start time.Time, network models.Network, isPos bool) (time.Duration, error) {
 	for {
-		txt := "How long should this validator be validating? Enter a duration, e.g. 8760h. Valid time units are \"ns\", \"us\" (or \"µs\"), \"ms\", \"s\", \"m\", \"h\""
-		var d time.Duration
-		var err error
-		switch {
-		case network.Kind == models.Fuji:
-			d, err = app.Prompt.CaptureFujiDuration(txt)
-		case network.Kind == models.Mainnet && isPos:
-			d, err = app.Prompt.CaptureMainnetL1StakingDuration(txt)
-		case network.Kind == models.Mainnet && !isPos:
-			d, err = app.Prompt.CaptureMainnetDuration(txt)
-		default:
-			d, err = app.Prompt.CaptureDuration(txt)
-		}
+		txt := "How long should this validator be validating? Enter a duration, e.g. 8760h or 365d, or an end UTC datetime in 'YYYY-MM-DD HH:MM:SS' or RFC3339 format"
+		minDuration, maxDuration := prompts.StakingDurationRange(network.Kind, isPos)
+		d, err := app.Prompt.CaptureDurationInRange(txt, start, minDuration, maxDuration)
 		if err != nil {
 			return 0, err
 		}
@@ -777,7 +866,7 @@ func getTimeParameters(network models.Network, nodeID ids.NodeID, isValidator bo
 }
 
 func promptStart() (time.Time, error) {
-	txt := "When should the validator start validating? Enter a UTC datetime in 'YYYY-MM-DD HH:MM:SS' format"
+	txt := "When should the validator start validating? Enter a UTC datetime in 'YYYY-MM-DD HH:MM:SS' or RFC3339 format, or a duration from now, e.g. 10m"
 	return app.Prompt.CaptureDate(txt)
 }
 
@@ -0,0 +1,133 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	icmgenesis "github.com/ava-labs/avalanche-cli/pkg/interchain/genesis"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// parseAirdropFlags parses the --airdrop flags (address=amount, in token units of
+// [decimals]) into a genesis allocation
+func parseAirdropFlags(airdrops []string, decimals uint8) (core.GenesisAlloc, error) {
+	if len(airdrops) == 0 {
+		return nil, nil
+	}
+	alloc := core.GenesisAlloc{}
+	for _, airdrop := range airdrops {
+		addressStr, amountStr, found := strings.Cut(airdrop, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid airdrop %q: expected address=amount", airdrop)
+		}
+		addressStr = strings.TrimSpace(addressStr)
+		if !common.IsHexAddress(addressStr) {
+			return nil, fmt.Errorf("invalid airdrop address %q", addressStr)
+		}
+		amount, err := strconv.ParseUint(strings.TrimSpace(amountStr), 10, 64)
+		if err != nil || amount == 0 {
+			return nil, fmt.Errorf("invalid airdrop amount %q: expected a positive amount of tokens", amountStr)
+		}
+		address := common.HexToAddress(addressStr)
+		if _, ok := alloc[address]; ok {
+			return nil, fmt.Errorf("address %s airdropped more than once", address.Hex())
+		}
+		alloc[address] = core.GenesisAccount{
+			Balance: vm.TokensToBaseUnits(amount, decimals),
+		}
+	}
+	return alloc, nil
+}
+
+// getAddressPlanRoles returns the roles of the addresses the CLI derives for
+// the blockchain [sc], to be labeled on its address plan
+func getAddressPlanRoles(
+	sc *models.Sidecar,
+	genesisBytes []byte,
+	icmInfo *interchain.ICMInfo,
+	airdrop core.GenesisAlloc,
+) (map[common.Address][]string, error) {
+	roles := map[common.Address][]string{}
+	addRole := func(address string, role string) {
+		if address == "" {
+			return
+		}
+		roles[common.HexToAddress(address)] = append(roles[common.HexToAddress(address)], role)
+	}
+	addRole(sc.ValidatorManagerOwner, "Validator Manager owner")
+	addRole(sc.ProxyContractOwner, "Validator Manager proxy admin owner")
+	for address := range airdrop {
+		addRole(address.Hex(), "Airdrop (--airdrop)")
+	}
+	keyName, airdropAddress, _, err := subnet.GetDefaultSubnetAirdropKeyInfo(app, sc.Name)
+	if err != nil {
+		return nil, err
+	}
+	if airdropAddress != "" {
+		addRole(airdropAddress, fmt.Sprintf("Main funded account (key %s)", keyName))
+	}
+	if sc.TeleporterReady {
+		addRole(icmInfo.FundedAddress, fmt.Sprintf("ICM funding (key %s)", sc.TeleporterKey))
+		addRole(icmInfo.RelayerAddress, "ICM relayer")
+		genesis, err := utils.ByteSliceToSubnetEvmGenesis(genesisBytes)
+		if err != nil {
+			return nil, err
+		}
+		// contracts not on genesis are deployed at fixed addresses together with the blockchain
+		if _, ok := genesis.Alloc[common.HexToAddress(icmgenesis.MessengerContractAddress)]; !ok {
+			addRole(icmgenesis.MessengerContractAddress, "ICM messenger (deployed after genesis)")
+			addRole(icmInfo.MessengerDeployerAddress, "ICM messenger deployer (funded on deploy)")
+		}
+	}
+	return roles, nil
+}
+
+// printAddressPlan shows every address placed on genesis [genesisBytes] or derived by the
+// CLI for the blockchain [sc], with its roles and funding
+func printAddressPlan(
+	sc *models.Sidecar,
+	genesisBytes []byte,
+	icmInfo *interchain.ICMInfo,
+	airdrop core.GenesisAlloc,
+) error {
+	roles, err := getAddressPlanRoles(sc, genesisBytes, icmInfo, airdrop)
+	if err != nil {
+		return err
+	}
+	plan, err := vm.GetAddressPlan(genesisBytes, roles)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	t := ux.DefaultTable(
+		"Address Plan",
+		table.Row{"Address", "Roles", "Kind", fmt.Sprintf("Funding (%s)", sc.TokenSymbol)},
+	)
+	for _, entry := range plan {
+		kind := "account"
+		switch {
+		case !entry.InGenesis:
+			kind = "not on genesis"
+		case entry.Contract:
+			kind = "contract"
+		}
+		funding := ""
+		if entry.Balance != nil {
+			funding = utils.FormatAmount(entry.Balance, sc.GetTokenDecimals())
+		}
+		t.AppendRow(table.Row{entry.Address.Hex(), strings.Join(entry.Roles, "\n"), kind, funding})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	return nil
+}
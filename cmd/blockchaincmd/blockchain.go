@@ -3,6 +3,7 @@
 package blockchaincmd
 
 import (
+	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd/feeconfigcmd"
 	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd/upgradecmd"
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
@@ -40,8 +41,12 @@ manage your Blockchain configurations and live deployments.`,
 	cmd.AddCommand(newJoinCmd())
 	// blockchain addValidator
 	cmd.AddCommand(newAddValidatorCmd())
+	// blockchain addBootstrapValidators
+	cmd.AddCommand(newAddBootstrapValidatorsCmd())
 	// blockchain export
 	cmd.AddCommand(newExportCmd())
+	// blockchain export-state
+	cmd.AddCommand(newExportStateCmd())
 	// blockchain import
 	cmd.AddCommand(newImportCmd())
 	// blockchain publish
@@ -62,5 +67,23 @@ manage your Blockchain configurations and live deployments.`,
 	cmd.AddCommand(newChangeOwnerCmd())
 	// blockchain changeWeight
 	cmd.AddCommand(newChangeWeightCmd())
+	// blockchain updateConfig
+	cmd.AddCommand(newUpdateConfigCmd())
+	// blockchain feeconfig
+	cmd.AddCommand(feeconfigcmd.NewCmd(app))
+	// blockchain proxy
+	cmd.AddCommand(newProxyCmd())
+	// blockchain load
+	cmd.AddCommand(newLoadCmd())
+	// blockchain recover
+	cmd.AddCommand(newRecoverCmd())
+	// blockchain govern
+	cmd.AddCommand(newGovernCmd())
+	// blockchain convert
+	cmd.AddCommand(newConvertCmd())
+	// blockchain init-dapp
+	cmd.AddCommand(newInitDappCmd())
+	// blockchain replay
+	cmd.AddCommand(newReplayCmd())
 	return cmd
 }
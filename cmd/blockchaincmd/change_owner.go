@@ -37,6 +37,7 @@ func newChangeOwnerCmd() *cobra.Command {
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, changeOwnerSupportedNetworkOptions)
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji/devnet)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().StringSliceVar(&custodyAddresses, "custody-addrs", nil, "pay with the given P-Chain addresses of keys kept by custodial signers, saving the tx for them to sign")
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji/devnet]")
 	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [fuji/devnet]")
 	cmd.Flags().StringSliceVar(&subnetAuthKeys, "auth-keys", nil, "control keys that will be used to authenticate transfer blockchain ownership tx")
@@ -64,16 +65,24 @@ func changeOwner(_ *cobra.Command, args []string) error {
 	}
 
 	fee := network.GenesisParams().TxFeeConfig.StaticFeeConfig.TxFee
-	kc, err := keychain.GetKeychainFromCmdLineFlags(
-		app,
-		"pay fees",
-		network,
-		keyName,
-		useEwoq,
-		useLedger,
-		ledgerAddresses,
-		fee,
-	)
+	var kc *keychain.Keychain
+	if len(custodyAddresses) > 0 {
+		if keyName != "" || useEwoq || useLedger || len(ledgerAddresses) > 0 {
+			return keychain.ErrCustodyWithKeySource
+		}
+		kc, err = keychain.GetCustodyKeychain(network, custodyAddresses)
+	} else {
+		kc, err = keychain.GetKeychainFromCmdLineFlags(
+			app,
+			"pay fees",
+			network,
+			keyName,
+			useEwoq,
+			useLedger,
+			ledgerAddresses,
+			fee,
+		)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,582 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/networkupgrades"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	blockchainSDK "github.com/ava-labs/avalanche-cli/sdk/blockchain"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+type ConvertFlags struct {
+	PrivateKeyFlags       contract.PrivateKeyFlags
+	rpcEndpoint           string
+	validatorManagerOwner string
+	balanceAVAX           float64
+	yes                   bool
+}
+
+var (
+	convertSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Fuji,
+		networkoptions.Mainnet,
+	}
+	convertFlags ConvertFlags
+
+	errConvertCancelled = errors.New("conversion cancelled")
+)
+
+// avalanche blockchain convert
+func newConvertCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert [blockchainName]",
+		Short: "Convert a deployed permissioned subnet into a sovereign L1",
+		Long: `The blockchain convert command migrates a Subnet-EVM blockchain deployed on a permissioned
+subnet, before ACP-77, into a sovereign L1 managed by a Proof of Authority validator manager.
+
+It sets up the validator manager, issues the ConvertSubnetToL1Tx with the current validators
+of the subnet keeping their weights, and initializes the manager validator set with the
+P-Chain signed conversion message, aggregating the signatures of the validators.
+
+Blockchains created without a validator manager on their genesis get one deployed by the
+command. Its address is recorded for the network, and used by the validator commands
+afterwards.
+
+The BLS keys of the current validators are taken from the nodes of the local network or of
+the cluster the blockchain was deployed to, from the nodes at --bootstrap-endpoints, or
+prompted. Use --bootstrap-filepath to set a different initial validator set.
+
+The conversion can not be undone, so a summary of it is shown and confirmation is asked
+before any change is made, unless --yes is given. The Warp precompile must be active on the
+blockchain, as the validator manager is initialized with a Warp message.
+
+If the conversion tx needs more control key signatures, it is saved to be signed by the
+other control keys, and the validator manager initialization can be finished with
+avalanche contract initValidatorManager once it is committed.`,
+		RunE: convertBlockchain,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, convertSupportedNetworkOptions)
+	convertFlags.PrivateKeyFlags.SetFlagNames("blockchain-private-key", "blockchain-key", "blockchain-genesis-key")
+	convertFlags.PrivateKeyFlags.AddToCmd(cmd, "to deploy and initialize the validator manager")
+	cmd.Flags().StringVar(&convertFlags.rpcEndpoint, "rpc", "", "use the given blockchain rpc endpoint")
+	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji/devnet)")
+	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji/devnet]")
+	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [fuji/devnet]")
+	cmd.Flags().StringSliceVar(&subnetAuthKeys, "auth-keys", nil, "control keys that will be used to authenticate the conversion tx")
+	cmd.Flags().StringVar(&outputTxPath, "output-tx-path", "", "file path of the conversion tx")
+	cmd.Flags().StringVar(&bootstrapValidatorsJSONFilePath, "bootstrap-filepath", "", "JSON file path that provides details about the L1 initial validators, instead of the current subnet validators")
+	cmd.Flags().StringSliceVar(&bootstrapEndpoints, "bootstrap-endpoints", nil, "take the BLS info of the current validators from the given endpoints")
+	cmd.Flags().IntVar(&bootstrapBatchSize, "bootstrap-batch-size", constants.DefaultBootstrapValidatorsBatchSize, "max number of bootstrap validators set on the conversion tx. the remaining ones are registered afterwards, within the validator manager churn limit")
+	cmd.Flags().StringVar(&changeOwnerAddress, "change-owner-address", "", "address that will receive change if node is no longer L1 validator")
+	cmd.Flags().Float64Var(
+		&convertFlags.balanceAVAX,
+		"balance",
+		float64(constants.BootstrapValidatorBalanceNanoAVAX)/float64(units.Avax),
+		"set the AVAX balance of each validator that will be used for continuous fee on P-Chain",
+	)
+	cmd.Flags().StringVar(&convertFlags.validatorManagerOwner, "validator-manager-owner", "", "EVM address that controls Validator Manager Owner")
+	cmd.Flags().BoolVarP(&convertFlags.yes, "yes", "y", false, "do not ask to confirm the conversion")
+	cmd.Flags().StringVar(&aggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	interchain.AddAggregationFlagsToCmd(cmd, &aggregationFlags)
+	cmd.Flags().StringSliceVar(&aggregatorExtraEndpoints, "aggregator-extra-endpoints", nil, "endpoints for extra nodes that are needed in signature aggregation")
+	cmd.Flags().BoolVar(&aggregatorAllowPrivatePeers, "aggregator-allow-private-peers", true, "allow the signature aggregator to connect to peers with private IP")
+	return cmd
+}
+
+func convertBlockchain(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	chainSpec := contract.ChainSpec{
+		BlockchainName: blockchainName,
+	}
+
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		convertSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+
+	if outputTxPath != "" {
+		if utils.FileExists(outputTxPath) {
+			return fmt.Errorf("outputTxPath %q already exists", outputTxPath)
+		}
+	}
+
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return err
+	}
+	if sc.Sovereign {
+		return fmt.Errorf("%s is already a sovereign L1", blockchainName)
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("only Subnet-EVM blockchains can be converted, as the validator manager is an EVM contract")
+	}
+	if sc.PoS() {
+		return fmt.Errorf("only conversions into Proof of Authority L1s are supported")
+	}
+	networkData := sc.Networks[network.Name()]
+	if networkData.SubnetID == ids.Empty {
+		return errNoSubnetID
+	}
+	if networkData.BlockchainID == ids.Empty {
+		return fmt.Errorf("blockchain has not been deployed to %s", network.Name())
+	}
+	if err := networkupgrades.CheckFeature(app, network, networkupgrades.SubnetToL1Conversion); err != nil {
+		return err
+	}
+	subnetID := networkData.SubnetID
+	blockchainID := networkData.BlockchainID
+
+	isPermissioned, currentControlKeys, currentThreshold, err := txutils.GetOwners(network, subnetID)
+	if err != nil {
+		return err
+	}
+	if !isPermissioned {
+		return ErrNotPermissionedSubnet
+	}
+
+	deployBalance := uint64(convertFlags.balanceAVAX * float64(units.Avax))
+	var bootstrapValidators []models.SubnetValidator
+	if bootstrapValidatorsJSONFilePath != "" {
+		bootstrapValidators, err = LoadBootstrapValidator(bootstrapValidatorsJSONFilePath)
+		if err != nil {
+			return err
+		}
+	} else {
+		bootstrapValidators, err = getCurrentSubnetValidators(network, subnetID, networkData.ClusterName, deployBalance)
+		if err != nil {
+			return err
+		}
+	}
+	if len(bootstrapValidators) == 0 {
+		return fmt.Errorf("no validators to convert %s with", blockchainName)
+	}
+
+	bootstrapValidators, pendingBootstrapValidators, err := splitBootstrapValidators(sc, bootstrapValidators, bootstrapBatchSize)
+	if err != nil {
+		return err
+	}
+	fees, err := newPChainFeeEstimator(network)
+	if err != nil {
+		return fmt.Errorf("failure getting P-Chain fees: %w", err)
+	}
+	fee, err := fees.fee(sampleConvertSubnetToL1Tx(len(bootstrapValidators), currentThreshold))
+	if err != nil {
+		return err
+	}
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		"pay fees",
+		network,
+		keyName,
+		useEwoq,
+		useLedger,
+		ledgerAddresses,
+		fee+deployBalance*uint64(len(bootstrapValidators)),
+	)
+	if err != nil {
+		return err
+	}
+
+	// add control keys to the keychain whenever possible
+	if err := kc.AddAddresses(currentControlKeys); err != nil {
+		return err
+	}
+	kcKeys, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return err
+	}
+	if subnetAuthKeys != nil {
+		if err := prompts.CheckSubnetAuthKeys(kcKeys, subnetAuthKeys, currentControlKeys, currentThreshold); err != nil {
+			return err
+		}
+	} else {
+		subnetAuthKeys, err = prompts.GetSubnetAuthKeys(app.Prompt, kcKeys, currentControlKeys, currentThreshold)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("Your auth keys for the conversion tx: %s", subnetAuthKeys)
+
+	if convertFlags.validatorManagerOwner == "" {
+		convertFlags.validatorManagerOwner = sc.ValidatorManagerOwner
+	}
+	if convertFlags.validatorManagerOwner == "" {
+		convertFlags.validatorManagerOwner, err = getValidatorContractManagerAddr()
+		if err != nil {
+			return err
+		}
+	}
+	if !common.IsHexAddress(convertFlags.validatorManagerOwner) {
+		return fmt.Errorf("invalid validator manager owner address %s", convertFlags.validatorManagerOwner)
+	}
+
+	if convertFlags.rpcEndpoint == "" {
+		convertFlags.rpcEndpoint, _, err = contract.GetBlockchainEndpoints(
+			app,
+			network,
+			chainSpec,
+			true,
+			false,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), convertFlags.rpcEndpoint)
+	if err := checkWarpActive(convertFlags.rpcEndpoint, blockchainName, blockchainID); err != nil {
+		return err
+	}
+	genesisAddress, genesisPrivateKey, err := contract.GetEVMSubnetPrefundedKey(
+		app,
+		network,
+		chainSpec,
+	)
+	if err != nil {
+		return err
+	}
+	privateKey, err := convertFlags.PrivateKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	if err != nil {
+		return err
+	}
+	if privateKey == "" {
+		privateKey, err = prompts.PromptPrivateKey(
+			app.Prompt,
+			"pay for the validator manager deploy and initialization? (Uses Blockchain gas token)",
+			app.GetKeyDir(),
+			app.GetKey,
+			genesisAddress,
+			genesisPrivateKey,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := confirmConversion(
+		network,
+		blockchainName,
+		subnetID,
+		bootstrapValidators,
+		pendingBootstrapValidators,
+		fee,
+	); err != nil {
+		return err
+	}
+
+	// the validator manager must exist before the conversion, as the P-Chain hands it the
+	// L1 validator set
+	predeployed, err := validatormanager.ValidatorManagerPredeployed(convertFlags.rpcEndpoint)
+	if err != nil {
+		return err
+	}
+	switch {
+	case predeployed:
+		networkData.ValidatorManagerAddress = ""
+	case networkData.ValidatorManagerAddress != "":
+		ux.Logger.PrintToUser("Using the Validator Manager deployed at %s on a previous conversion attempt", networkData.ValidatorManagerAddress)
+	default:
+		ux.Logger.PrintToUser("Deploying a Proof of Authority Validator Manager contract on blockchain %s ...", blockchainName)
+		managerAddress, err := validatormanager.DeployPoAValidatorManager(convertFlags.rpcEndpoint, privateKey)
+		if err != nil {
+			return fmt.Errorf("failure deploying the validator manager: %w", err)
+		}
+		ux.Logger.GreenCheckmarkToUser("Validator Manager deployed at %s", managerAddress.Hex())
+		networkData.ValidatorManagerAddress = managerAddress.Hex()
+		sc.Networks[network.Name()] = networkData
+		if err := app.UpdateSidecar(&sc); err != nil {
+			return err
+		}
+	}
+	managerAddress := validatormanager.GetValidatorManagerAddress(networkData)
+
+	avaGoBootstrapValidators, err := ConvertToAvalancheGoSubnetValidator(bootstrapValidators)
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewPublicDeployer(app, kc, network)
+	isFullySigned, convertL1TxID, tx, remainingSubnetAuthKeys, err := deployer.ConvertL1(
+		currentControlKeys,
+		subnetAuthKeys,
+		subnetID,
+		blockchainID,
+		managerAddress,
+		avaGoBootstrapValidators,
+	)
+	if err != nil {
+		ux.Logger.RedXToUser("error converting blockchain: %s. fix the issue and try again with a new convert cmd", err)
+		return err
+	}
+	ux.Logger.PrintToUser("ConvertSubnetToL1Tx ID: %s", convertL1TxID)
+
+	setBootstrapValidatorValidationID(avaGoBootstrapValidators, bootstrapValidators, subnetID)
+	sc.Sovereign = true
+	sc.ValidatorManagement = models.ProofOfAuthority
+	sc.ValidatorManagerOwner = convertFlags.validatorManagerOwner
+	networkData.BootstrapValidators = bootstrapValidators
+	networkData.PendingBootstrapValidators = pendingBootstrapValidators
+	sc.Networks[network.Name()] = networkData
+
+	if !isFullySigned {
+		if err := SaveNotFullySignedTx(
+			"ConvertSubnetToL1Tx",
+			tx,
+			blockchainName,
+			subnetAuthKeys,
+			remainingSubnetAuthKeys,
+			outputTxPath,
+			false,
+		); err != nil {
+			return err
+		}
+		if err := app.UpdateSidecar(&sc); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Once the tx is committed, call `avalanche contract initValidatorManager %s` to finish the conversion to sovereign L1", blockchainName)
+		if len(pendingBootstrapValidators) > 0 {
+			ux.Logger.PrintToUser("Then call `avalanche blockchain addBootstrapValidators %s` to register the remaining bootstrap validators", blockchainName)
+		}
+		return nil
+	}
+
+	_, err = ux.TimedProgressBar(
+		30*time.Second,
+		"Waiting for the subnet to be converted into a sovereign L1 ...",
+		0,
+	)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+
+	extraAggregatorPeers, err := GetAggregatorExtraPeers(networkData.ClusterName, aggregatorExtraEndpoints)
+	if err != nil {
+		return err
+	}
+	aggregationConfig, err := interchain.GetAggregationConfig(app, aggregationFlags)
+	if err != nil {
+		return err
+	}
+	ownerAddress := common.HexToAddress(sc.ValidatorManagerOwner)
+	subnetSDK := blockchainSDK.Subnet{
+		SubnetID:                subnetID,
+		BlockchainID:            blockchainID,
+		OwnerAddress:            &ownerAddress,
+		RPC:                     convertFlags.rpcEndpoint,
+		BootstrapValidators:     avaGoBootstrapValidators,
+		Logger:                  ux.Logger.WithUserWarnings(app.Log),
+		ValidatorManagerAddress: &managerAddress,
+		AggregationConfig:       aggregationConfig,
+	}
+	logLvl, err := logging.ToLevel(aggregatorLogLevel)
+	if err != nil {
+		logLvl = logging.Off
+	}
+	ux.Logger.PrintToUser("Initializing Proof of Authority Validator Manager contract on blockchain %s ...", blockchainName)
+	if err := subnetSDK.InitializeProofOfAuthority(
+		network.SDKNetwork(),
+		privateKey,
+		extraAggregatorPeers,
+		aggregatorAllowPrivatePeers,
+		logLvl,
+	); err != nil {
+		ux.Logger.RedXToUser("the subnet was converted, but the validator manager could not be initialized. Retry with avalanche contract initValidatorManager %s", blockchainName)
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Proof of Authority Validator Manager contract successfully initialized on blockchain %s", blockchainName)
+	if len(pendingBootstrapValidators) > 0 {
+		rpcURL = convertFlags.rpcEndpoint
+		if err := registerPendingBootstrapValidators(deployer, network, blockchainName); err != nil {
+			return err
+		}
+	}
+	ux.Logger.GreenCheckmarkToUser("%s converted into a sovereign L1", blockchainName)
+	return nil
+}
+
+// checkWarpActive checks that the Warp precompile of the blockchain at [rpcEndpoint] is
+// active, as the validator manager is initialized with the Warp signed conversion message
+func checkWarpActive(rpcEndpoint string, blockchainName string, blockchainID ids.ID) error {
+	warpBlockchainID, err := interchain.GetWarpBlockchainID(rpcEndpoint)
+	if err != nil {
+		return fmt.Errorf(
+			"the Warp precompile is not active on %s, and it is needed to initialize the validator manager. "+
+				"Activate it with a precompile upgrade (avalanche blockchain upgrade generate %s) before converting: %w",
+			blockchainName,
+			blockchainName,
+			err,
+		)
+	}
+	if warpBlockchainID != blockchainID {
+		return fmt.Errorf(
+			"the RPC endpoint %s serves blockchain %s, not %s (%s)",
+			rpcEndpoint,
+			warpBlockchainID,
+			blockchainName,
+			blockchainID,
+		)
+	}
+	return nil
+}
+
+// confirmConversion shows what the conversion of [blockchainName] does, and asks to confirm
+// it unless --yes is given. Without a terminal, --yes is required, as it can not be undone
+func confirmConversion(
+	network models.Network,
+	blockchainName string,
+	subnetID ids.ID,
+	bootstrapValidators []models.SubnetValidator,
+	pendingBootstrapValidators []models.SubnetValidator,
+	fee uint64,
+) error {
+	t := ux.DefaultTable(fmt.Sprintf("Conversion of %s into a sovereign L1 on %s", blockchainName, network.Name()), nil)
+	t.AppendRow(table.Row{"Subnet ID", subnetID})
+	t.AppendRow(table.Row{"Validator Management", models.ProofOfAuthority})
+	t.AppendRow(table.Row{"Validator Manager Owner", convertFlags.validatorManagerOwner})
+	balance := uint64(0)
+	for _, validator := range bootstrapValidators {
+		t.AppendRow(table.Row{"Initial Validator", fmt.Sprintf("%s (weight %d)", validator.NodeID, validator.Weight)})
+		balance += validator.Balance
+	}
+	for _, validator := range pendingBootstrapValidators {
+		t.AppendRow(table.Row{"Validator Registered Afterwards", fmt.Sprintf("%s (weight %d)", validator.NodeID, validator.Weight)})
+	}
+	t.AppendRow(table.Row{"ConvertSubnetToL1Tx Fee", fmt.Sprintf("%.9f AVAX", float64(fee)/float64(units.Avax))})
+	t.AppendRow(table.Row{"Initial Validators Balance", fmt.Sprintf("%.9f AVAX", float64(balance)/float64(units.Avax))})
+	ux.Logger.PrintToUser(t.Render())
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("The conversion can not be undone: the subnet control keys will no longer manage its validators"))
+	if convertFlags.yes {
+		return nil
+	}
+	if !prompts.IsInteractive() {
+		return fmt.Errorf("no terminal to confirm the conversion. use --yes to convert %s", blockchainName)
+	}
+	yes, err := app.Prompt.CaptureYesNo(fmt.Sprintf("Proceed with the conversion of %s?", blockchainName))
+	if err != nil {
+		return err
+	}
+	if !yes {
+		return errConvertCancelled
+	}
+	return nil
+}
+
+// getCurrentSubnetValidators returns the current validators of [subnetID] with their weights,
+// to keep them as the L1 initial validator set. BLS info is taken from the nodes of the local
+// network or [clusterName], from the nodes at --bootstrap-endpoints, or prompted
+func getCurrentSubnetValidators(
+	network models.Network,
+	subnetID ids.ID,
+	clusterName string,
+	balance uint64,
+) ([]models.SubnetValidator, error) {
+	pClient := platformvm.NewClient(network.Endpoint)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	currentValidators, err := pClient.GetCurrentValidators(ctx, subnetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(currentValidators) == 0 {
+		return nil, errors.New("the subnet has no validators. Set the L1 initial validators with --bootstrap-filepath")
+	}
+	endpoints := bootstrapEndpoints
+	switch {
+	case clusterName != "":
+		clusterEndpoints, err := GetAggregatorNetworkUris(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, clusterEndpoints...)
+	case network.Kind == models.Local:
+		localEndpoints, err := getLocalBootstrapEndpoints()
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, localEndpoints...)
+	}
+	type blsInfo struct {
+		publicKey         string
+		proofOfPossession string
+	}
+	nodesBLSInfo := map[ids.NodeID]blsInfo{}
+	for _, endpoint := range endpoints {
+		infoClient := info.NewClient(endpoint)
+		ctx, cancel := utils.GetAPIContext()
+		nodeID, proofOfPossession, err := infoClient.GetNodeID(ctx)
+		cancel()
+		if err != nil {
+			ux.Logger.RedXToUser("could not get the node info from %s: %s", endpoint, err)
+			continue
+		}
+		nodesBLSInfo[nodeID] = blsInfo{
+			publicKey:         "0x" + hex.EncodeToString(proofOfPossession.PublicKey[:]),
+			proofOfPossession: "0x" + hex.EncodeToString(proofOfPossession.ProofOfPossession[:]),
+		}
+	}
+	if changeOwnerAddress == "" {
+		changeOwnerAddress, err = getKeyForChangeOwner(network)
+		if err != nil {
+			return nil, err
+		}
+	}
+	validators := []models.SubnetValidator{}
+	for _, validator := range currentValidators {
+		nodeBLSInfo, ok := nodesBLSInfo[validator.NodeID]
+		if !ok {
+			ux.Logger.PrintToUser("BLS info of validator %s was not found on the known nodes", validator.NodeID)
+			nodeBLSInfo.publicKey, nodeBLSInfo.proofOfPossession, err = promptProofOfPossession(true, true)
+			if err != nil {
+				return nil, err
+			}
+		}
+		validators = append(validators, models.SubnetValidator{
+			NodeID:               validator.NodeID.String(),
+			Weight:               validator.Weight,
+			Balance:              balance,
+			BLSPublicKey:         nodeBLSInfo.publicKey,
+			BLSProofOfPossession: nodeBLSInfo.proofOfPossession,
+			ChangeOwnerAddr:      changeOwnerAddress,
+		})
+	}
+	return validators, nil
+}
@@ -16,15 +16,17 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/interchain"
-	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/metrics"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/preset"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/logging"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 	"golang.org/x/mod/semver"
 )
@@ -40,6 +42,7 @@ type CreateFlags struct {
 	useCustomVM                   bool
 	chainID                       uint64
 	tokenSymbol                   string
+	tokenDecimals                 uint8
 	useTestDefaults               bool
 	useProductionDefaults         bool
 	useWarp                       bool
@@ -50,27 +53,37 @@ type CreateFlags struct {
 	useExternalGasToken           bool
 	addICMRegistryToGenesis       bool
 	proofOfStake                  bool
+	erc20Staking                  bool
 	proofOfAuthority              bool
 	rewardBasisPoints             uint64
 	validatorManagerOwner         string
 	proxyContractOwner            string
 	enableDebugging               bool
+	maxSupply                     uint64
+	icmKeyName                    string
+	airdrops                      []string
+	addressPlanOnly               bool
 }
 
 var (
-	createFlags CreateFlags
-	forceCreate bool
-	genesisPath string
-	vmFile      string
-	useRepo     bool
-	sovereign   bool
+	createFlags      CreateFlags
+	forceCreate      bool
+	genesisPath      string
+	scratchChainName string
+	presetRef        string
+	presetRegistry   string
+	presetSHA256     string
+	selectedPreset   *preset.Preset
+	vmFile           string
+	useRepo          bool
+	sovereign        bool
 
 	errEmptyBlockchainName                        = errors.New("invalid empty name")
 	errIllegalNameCharacter                       = errors.New("illegal name character: only letters, no special characters allowed")
 	errMutuallyExlusiveVersionOptions             = errors.New("version flags --latest,--pre-release,vm-version are mutually exclusive")
 	errMutuallyExclusiveVMConfigOptions           = errors.New("--genesis flag disables --evm-chain-id,--evm-defaults,--production-defaults,--test-defaults")
 	errMutuallyExlusiveValidatorManagementOptions = errors.New("validator management type flags --proof-of-authority,--proof-of-stake are mutually exclusive")
-	errSOVFlagsOnly                               = errors.New("flags --proof-of-authority, --proof-of-stake, --poa-manager-owner --proxy-contract-owner are only applicable to Subnet Only Validator (SOV) blockchains")
+	errSOVFlagsOnly                               = errors.New("flags --proof-of-authority, --proof-of-stake, --erc20-staking, --poa-manager-owner --proxy-contract-owner are only applicable to Subnet Only Validator (SOV) blockchains")
 )
 
 // avalanche blockchain create
@@ -86,6 +99,21 @@ The tool supports deploying Subnet-EVM, and custom VMs. You
 can create a custom, user-generated genesis with a custom VM by providing
 the path to your genesis and VM binaries with the --genesis and --vm flags.
 
+Ecosystems can publish recommended Subnet-EVM configurations (genesis params,
+fee config, precompiles, ICM defaults) on a preset registry. Use them with
+--preset name[@version]. Presets are verified against the checksums of the
+registry index. Set the registry with avalanche config preset-registry, or
+with --preset-registry. The genesis allocations and precompile roles of the
+preset are shown for confirmation, unless its sha256 is pinned with
+--preset-sha256, so that a reviewed preset can be reused without prompts.
+
+Before the configuration is saved, an address plan lists every address placed
+on the Subnet-EVM genesis or derived for the blockchain (validator manager and
+proxy contracts, ICM contracts and keys, funded accounts), with its roles and
+funding. Use --address-plan to only show it. The default addresses can be
+replaced with --validator-manager-owner, --proxy-contract-owner, --icm-key and
+--airdrop.
+
 By default, running the command with a blockchainName that already exists
 causes the command to fail. If you'd like to overwrite an existing
 configuration, pass the -f flag.`,
@@ -94,6 +122,10 @@ configuration, pass the -f flag.`,
 		PersistentPostRun: handlePostRun,
 	}
 	cmd.Flags().StringVar(&genesisPath, "genesis", "", "file path of genesis to use")
+	cmd.Flags().StringVar(&scratchChainName, "from-scratch-chain", "", "use the genesis and Subnet-EVM version of the given scratch chain (see avalanche network scratch-evm)")
+	cmd.Flags().StringVar(&presetRef, "preset", "", "use the genesis, fee config, precompiles and ICM defaults of the given registry preset (name[@version])")
+	cmd.Flags().StringVar(&presetSHA256, "preset-sha256", "", "require the preset file to have the given sha256, and skip the confirmation of its contents")
+	cmd.Flags().StringVar(&presetRegistry, "preset-registry", "", "preset registry to use: HTTPS index, git repository (with optional #ref) or local dir. Defaults to the one set with avalanche config preset-registry")
 	cmd.Flags().BoolVar(&createFlags.useSubnetEvm, "evm", false, "use the Subnet-EVM as the base template")
 	cmd.Flags().BoolVar(&createFlags.useCustomVM, "custom", false, "use a custom VM template")
 	cmd.Flags().StringVar(&createFlags.vmVersion, "vm-version", "", "version of Subnet-EVM template to use")
@@ -101,6 +133,7 @@ configuration, pass the -f flag.`,
 	cmd.Flags().BoolVar(&createFlags.useLatestReleasedVMVersion, latest, false, "use latest Subnet-EVM released version, takes precedence over --vm-version")
 	cmd.Flags().Uint64Var(&createFlags.chainID, "evm-chain-id", 0, "chain ID to use with Subnet-EVM")
 	cmd.Flags().StringVar(&createFlags.tokenSymbol, "evm-token", "", "token symbol to use with Subnet-EVM")
+	cmd.Flags().Uint8Var(&createFlags.tokenDecimals, "token-decimals", constants.DefaultTokenDecimals, "decimals of the native token, used to display and enter amounts (1 to 18)")
 	cmd.Flags().BoolVar(&createFlags.useProductionDefaults, "evm-defaults", false, "deprecation notice: use '--production-defaults'")
 	cmd.Flags().BoolVar(&createFlags.useProductionDefaults, "production-defaults", false, "use default production settings for your blockchain")
 	cmd.Flags().BoolVar(&createFlags.useTestDefaults, "test-defaults", false, "use default test settings for your blockchain")
@@ -118,11 +151,16 @@ configuration, pass the -f flag.`,
 	cmd.Flags().BoolVar(&createFlags.addICMRegistryToGenesis, "icm-registry-at-genesis", false, "setup ICM registry smart contract on genesis [experimental]")
 	cmd.Flags().BoolVar(&createFlags.proofOfAuthority, "proof-of-authority", false, "use proof of authority(PoA) for validator management")
 	cmd.Flags().BoolVar(&createFlags.proofOfStake, "proof-of-stake", false, "use proof of stake(PoS) for validator management")
+	cmd.Flags().BoolVar(&createFlags.erc20Staking, "erc20-staking", false, "(PoS only) stake an ERC20 token, instead of the native token, on the validator manager")
 	cmd.Flags().StringVar(&createFlags.validatorManagerOwner, "validator-manager-owner", "", "EVM address that controls Validator Manager Owner")
 	cmd.Flags().StringVar(&createFlags.proxyContractOwner, "proxy-contract-owner", "", "EVM address that controls ProxyAdmin for TransparentProxy of ValidatorManager contract")
 	cmd.Flags().BoolVar(&sovereign, "sovereign", true, "set to false if creating non-sovereign blockchain")
 	cmd.Flags().Uint64Var(&createFlags.rewardBasisPoints, "reward-basis-points", 100, "(PoS only) reward basis points for PoS Reward Calculator")
 	cmd.Flags().BoolVar(&createFlags.enableDebugging, "debug", true, "enable blockchain debugging")
+	cmd.Flags().Uint64Var(&createFlags.maxSupply, "max-supply", 0, "maximum total supply of the native token (in token units) the initial token allocation must not exceed")
+	cmd.Flags().StringVar(&createFlags.icmKeyName, "icm-key", constants.ICMKeyName, "key to be funded on genesis to pay for ICM operations")
+	cmd.Flags().StringSliceVar(&createFlags.airdrops, "airdrop", nil, "fund the given address on genesis instead of the default allocation (address=amount, in token units). Can be repeated")
+	cmd.Flags().BoolVar(&createFlags.addressPlanOnly, "address-plan", false, "show the addresses to be placed on genesis, with their roles and funding, and exit without creating the blockchain")
 	return cmd
 }
 
@@ -150,6 +188,7 @@ func CallCreate(
 	createFlags.vmVersion = vmVersionParam
 	createFlags.chainID = evmChainIDParam
 	createFlags.tokenSymbol = tokenSymbolParam
+	createFlags.tokenDecimals = constants.DefaultTokenDecimals
 	createFlags.useProductionDefaults = useProductionDefaultsParam
 	createFlags.useTestDefaults = useTestDefaultsParam
 	createFlags.useLatestReleasedVMVersion = useLatestReleasedVMVersionParam
@@ -167,7 +206,7 @@ func handlePostRun(_ *cobra.Command, _ []string) {}
 func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 	blockchainName := args[0]
 
-	if app.GenesisExists(blockchainName) && !forceCreate {
+	if app.GenesisExists(blockchainName) && !forceCreate && !createFlags.addressPlanOnly {
 		return errors.New("configuration already exists. Use --" + forceFlag + " parameter to overwrite")
 	}
 
@@ -184,6 +223,19 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 		return errMutuallyExlusiveVersionOptions
 	}
 
+	if scratchChainName != "" {
+		if err := useScratchChain(scratchChainName); err != nil {
+			return err
+		}
+	}
+
+	selectedPreset = nil
+	if presetRef != "" {
+		if err := usePreset(presetRef); err != nil {
+			return err
+		}
+	}
+
 	defaultsKind := vm.NoDefaults
 	if createFlags.useTestDefaults {
 		defaultsKind = vm.TestDefaults
@@ -208,7 +260,7 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 	}
 
 	if !sovereign {
-		if createFlags.proofOfAuthority || createFlags.proofOfStake || createFlags.validatorManagerOwner != "" || createFlags.proxyContractOwner != "" {
+		if createFlags.proofOfAuthority || createFlags.proofOfStake || createFlags.erc20Staking || createFlags.validatorManagerOwner != "" || createFlags.proxyContractOwner != "" {
 			return errSOVFlagsOnly
 		}
 	}
@@ -216,16 +268,37 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 	if !flags.EnsureMutuallyExclusive([]bool{createFlags.proofOfAuthority, createFlags.proofOfStake}) {
 		return errMutuallyExlusiveValidatorManagementOptions
 	}
+	if createFlags.erc20Staking && createFlags.proofOfAuthority {
+		return errors.New("--erc20-staking is only applicable to Proof of Stake blockchains")
+	}
 
 	if createFlags.rewardBasisPoints == 0 && createFlags.proofOfStake {
 		return fmt.Errorf("reward basis points cannot be zero")
 	}
 
+	if createFlags.tokenDecimals == 0 || createFlags.tokenDecimals > constants.DefaultTokenDecimals {
+		return fmt.Errorf("--token-decimals must be between 1 and %d", constants.DefaultTokenDecimals)
+	}
+
+	airdrop, err := parseAirdropFlags(createFlags.airdrops, createFlags.tokenDecimals)
+	if err != nil {
+		return err
+	}
+	if airdrop != nil && (genesisPath != "" || createFlags.useExternalGasToken) {
+		return errors.New("--airdrop can't be used together with --genesis, --external-gas-token or a preset")
+	}
+	if createFlags.icmKeyName == "" {
+		return errors.New("--icm-key can't be empty")
+	}
+
 	// get vm kind
 	vmType, err := vm.PromptVMType(app, createFlags.useSubnetEvm, createFlags.useCustomVM)
 	if err != nil {
 		return err
 	}
+	if createFlags.addressPlanOnly && vmType != models.SubnetEvm {
+		return errors.New("--address-plan is only available for Subnet-EVM blockchains")
+	}
 
 	var (
 		genesisBytes        []byte
@@ -243,14 +316,21 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 	if flag := cmd.Flags().Lookup(flagName); flag != nil && flag.Changed {
 		useICMFlag = &createFlags.useICM
 	}
+	// presets may recommend ICM, unless set by flag
+	if useICMFlag == nil && selectedPreset != nil {
+		useICMFlag = selectedPreset.ICM
+	}
 
 	// get ICM info
-	icmInfo, err := interchain.GetICMInfo(app)
+	icmInfo, err := interchain.GetICMInfoForKey(app, createFlags.icmKeyName)
 	if err != nil {
 		return err
 	}
 
-	sc := &models.Sidecar{}
+	sc := &models.Sidecar{TokenDecimals: createFlags.tokenDecimals}
+	if createFlags.tokenDecimals != constants.DefaultTokenDecimals {
+		printTokenDecimalsWarnings(createFlags.tokenDecimals)
+	}
 
 	if sovereign {
 		if err = promptValidatorManagementType(app, sc); err != nil {
@@ -311,6 +391,7 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 		}
 
 		var tokenSymbol string
+		maxSupply := createFlags.maxSupply
 
 		if genesisPath != "" {
 			if evmCompatibleGenesis, err := utils.FileIsSubnetEVMGenesis(genesisPath); err != nil {
@@ -344,10 +425,13 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 				defaultsKind,
 				createFlags.useWarp,
 				createFlags.useExternalGasToken,
+				maxSupply,
+				airdrop,
 			)
 			if err != nil {
 				return err
 			}
+			maxSupply = params.MaxSupply
 			deployICM = params.UseICM
 			useExternalGasToken = params.UseExternalGasToken
 			genesisBytes, err = vm.CreateEVMGenesis(
@@ -372,6 +456,7 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 		); err != nil {
 			return err
 		}
+		sc.MaxSupply = maxSupply
 	} else {
 		if genesisPath == "" {
 			genesisPath, err = app.Prompt.CaptureExistingFilepath("Enter path to custom genesis")
@@ -414,7 +499,7 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 		sc.TeleporterReady = true
 		sc.RunRelayer = true // TODO: remove this once deploy asks if deploying relayer
 		sc.ExternalToken = useExternalGasToken
-		sc.TeleporterKey = constants.ICMKeyName
+		sc.TeleporterKey = createFlags.icmKeyName
 		sc.TeleporterVersion = icmInfo.Version
 		if genesisPath != "" {
 			if evmCompatibleGenesis, err := utils.FileIsSubnetEVMGenesis(genesisPath); err != nil {
@@ -433,6 +518,33 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if vmType == models.SubnetEvm {
+		if sc.MaxSupply != 0 {
+			genesis, err := utils.ByteSliceToSubnetEvmGenesis(genesisBytes)
+			if err != nil {
+				return err
+			}
+			if err := vm.CheckSupplyCap(
+				genesis.Alloc,
+				nil,
+				vm.MaxSupplyFromTokens(sc.MaxSupply, sc.GetTokenDecimals()),
+				sc.GetTokenDecimals(),
+			); err != nil {
+				return err
+			}
+		}
+		// refuse subnet-evm versions lacking features used by the genesis
+		if err := vm.SetSubnetEVMVersionConstraint(sc, genesisBytes); err != nil {
+			return err
+		}
+		if err := printAddressPlan(sc, genesisBytes, icmInfo, airdrop); err != nil {
+			return err
+		}
+		if createFlags.addressPlanOnly {
+			return nil
+		}
+	}
+
 	if err = app.WriteGenesisFile(blockchainName, genesisBytes); err != nil {
 		return err
 	}
@@ -442,7 +554,15 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 	if hasSubnetEVMGenesis, _, err := app.HasSubnetEVMGenesis(blockchainName); err != nil {
 		return err
 	} else if hasSubnetEVMGenesis {
-		if createFlags.enableDebugging {
+		if selectedPreset != nil && len(selectedPreset.ChainConfig) > 0 {
+			if err := SetBlockchainConf(
+				blockchainName,
+				selectedPreset.ChainConfig,
+				constants.ChainConfigFileName,
+			); err != nil {
+				return err
+			}
+		} else if createFlags.enableDebugging {
 			if err := SetBlockchainConf(
 				blockchainName,
 				vm.EvmDebugConfig,
@@ -461,6 +581,15 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if selectedPreset != nil {
+		sc.Preset = models.PresetInfo{
+			Name:     selectedPreset.Name,
+			Version:  selectedPreset.Version,
+			Registry: presetRegistry,
+			SHA256:   selectedPreset.SHA256,
+		}
+	}
+
 	if err = app.CreateSidecar(sc); err != nil {
 		return err
 	}
@@ -476,6 +605,144 @@ func createBlockchainConfig(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// useScratchChain sets the create flags to promote the scratch chain [chainName]
+// into a Subnet-EVM blockchain definition
+func useScratchChain(chainName string) error {
+	if genesisPath != "" {
+		return errors.New("flags --genesis,--from-scratch-chain are mutually exclusive")
+	}
+	if createFlags.useCustomVM {
+		return errors.New("flags --custom,--from-scratch-chain are mutually exclusive")
+	}
+	if !app.ScratchChainExists(chainName) {
+		return fmt.Errorf("scratch chain %s not found. Start it with avalanche network scratch-evm %s", chainName, chainName)
+	}
+	chain, err := app.LoadScratchChain(chainName)
+	if err != nil {
+		return err
+	}
+	genesisPath = app.GetScratchChainGenesisPath(chainName)
+	createFlags.useSubnetEvm = true
+	if createFlags.vmVersion == "" && !createFlags.useLatestReleasedVMVersion && !createFlags.useLatestPreReleasedVMVersion {
+		createFlags.vmVersion = chain.VMVersion
+	}
+	if createFlags.tokenSymbol == "" {
+		createFlags.tokenSymbol = chain.TokenSymbol
+	}
+	ux.Logger.PrintToUser("Using the genesis of scratch chain %s (chain ID %d)", chain.Name, chain.ChainID)
+	return nil
+}
+
+// usePreset sets the create flags to use the registry preset [ref] (name[@version]),
+// fetched from the registry given by flag or set on the CLI config
+func usePreset(ref string) error {
+	if scratchChainName != "" {
+		return errors.New("flags --from-scratch-chain,--preset are mutually exclusive")
+	}
+	if genesisPath != "" {
+		return errors.New("flags --genesis,--preset are mutually exclusive")
+	}
+	if createFlags.useCustomVM {
+		return errors.New("flags --custom,--preset are mutually exclusive")
+	}
+	if createFlags.useTestDefaults || createFlags.useProductionDefaults {
+		return errors.New("--preset flag disables --evm-defaults,--production-defaults,--test-defaults")
+	}
+	registry, err := preset.GetRegistry(app, presetRegistry)
+	if err != nil {
+		return err
+	}
+	presetRegistry = registry
+	p, err := preset.Fetch(app, registry, ref, presetSHA256)
+	if err != nil {
+		return err
+	}
+	if err := confirmPreset(p, registry); err != nil {
+		return err
+	}
+	// the preset genesis takes the chain ID given by flag, if any
+	genesisPath, err = preset.WriteGenesis(app, p, createFlags.chainID)
+	if err != nil {
+		return err
+	}
+	createFlags.chainID = 0
+	createFlags.useSubnetEvm = true
+	if createFlags.vmVersion == "" && !createFlags.useLatestReleasedVMVersion && !createFlags.useLatestPreReleasedVMVersion {
+		createFlags.vmVersion = p.VMVersion
+	}
+	if createFlags.tokenSymbol == "" {
+		createFlags.tokenSymbol = p.TokenSymbol
+	}
+	selectedPreset = p
+	return nil
+}
+
+// confirmPreset shows the genesis allocations and precompile roles of [p], and asks
+// to confirm them, unless the preset sha256 was pinned by flag
+func confirmPreset(p *preset.Preset, registry string) error {
+	ux.Logger.PrintToUser("Using preset %s@%s (sha256 %s) from %s", p.Name, p.Version, p.SHA256, registry)
+	if p.Description != "" {
+		ux.Logger.PrintToUser("  %s", p.Description)
+	}
+	allocations, err := p.Allocations()
+	if err != nil {
+		return err
+	}
+	roles, err := p.PrecompileRoles()
+	if err != nil {
+		return err
+	}
+	if len(allocations) > 0 {
+		t := ux.DefaultTable("Preset Genesis Allocations", table.Row{"Address", "Balance", "Contract"})
+		for _, allocation := range allocations {
+			contract := ""
+			if allocation.HasCode {
+				contract = "yes"
+			}
+			t.AppendRow(table.Row{allocation.Address, utils.FormatAmount(allocation.Balance, constants.DefaultTokenDecimals), contract})
+		}
+		ux.Logger.PrintToUser(t.Render())
+	}
+	if len(roles) > 0 {
+		t := ux.DefaultTable("Preset Precompile Roles", table.Row{"Precompile", "Role", "Addresses"})
+		for _, role := range roles {
+			t.AppendRow(table.Row{role.Precompile, role.Role, strings.Join(role.Addresses, "\n")})
+		}
+		ux.Logger.PrintToUser(t.Render())
+	}
+	if presetSHA256 != "" {
+		return nil
+	}
+	ux.Logger.PrintToUser("Review the accounts funded and the addresses granted precompile roles by the preset")
+	confirmed, err := app.Prompt.CaptureYesNo(fmt.Sprintf("Create the blockchain with preset %s@%s?", p.Name, p.Version))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return fmt.Errorf("preset %s@%s not confirmed. pin its sha256 with --preset-sha256 to skip the confirmation", p.Name, p.Version)
+	}
+	return nil
+}
+
+// printTokenDecimalsWarnings explains the implications of a native token not using
+// the EVM standard of 18 decimals
+func printTokenDecimalsWarnings(decimals uint8) {
+	unitsShift := constants.DefaultTokenDecimals - decimals
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("Native token amounts will be entered and shown with %d decimals. Take into account that:"), decimals)
+	ux.Logger.PrintToUser(logging.Yellow.Wrap(
+		"- the EVM, wallets and explorers always assume 18 decimals for the native token, so they will show balances 10^%d times smaller"),
+		unitsShift,
+	)
+	ux.Logger.PrintToUser(logging.Yellow.Wrap(
+		"- gas prices on the fee config are set in base units (10^-%d tokens). Review them so that transactions stay affordable"),
+		decimals,
+	)
+	ux.Logger.PrintToUser(logging.Yellow.Wrap(
+		"- ICM token transferrers (ICTT) handle the native token as having 18 decimals. Set the decimals of remote tokens accordingly"))
+	ux.Logger.PrintToUser("")
+}
+
 func addSubnetEVMGenesisPrefundedAddress(genesisBytes []byte, address string, balance string) ([]byte, error) {
 	var genesisMap map[string]interface{}
 	if err := json.Unmarshal(genesisBytes, &genesisMap); err != nil {
@@ -537,7 +804,7 @@ func validateValidatorManagerOwnerFlag(input string) error {
 	// if flag value is a key name, we get the C Chain address of the key and set it as the value of
 	// the validator manager address
 	if !common.IsHexAddress(input) {
-		k, err := key.LoadSoft(models.UndefinedNetwork.ID, app.GetKeyPath(input))
+		k, err := app.GetKey(input, models.UndefinedNetwork, false)
 		if err != nil {
 			return err
 		}
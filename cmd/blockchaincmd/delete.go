@@ -6,26 +6,150 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/sdk/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	forceDelete bool
+	untrack     bool
 )
 
 // avalanche blockchain delete
 func newDeleteCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "delete [blockchainName]",
 		Short: "Delete a blockchain configuration",
-		Long:  "The blockchain delete command deletes an existing blockchain configuration.",
-		RunE:  deleteBlockchain,
-		Args:  cobrautils.ExactArgs(1),
+		Long: `The blockchain delete command deletes an existing blockchain configuration.
+
+If the blockchain is still deployed, on a local network, a cluster or a public network,
+the command shows where, and refuses to delete it. Use --untrack to make the clusters
+that are syncing the blockchain stop tracking it, and --force to remove the local
+configuration anyway, leaving the deployments as they are.`,
+		RunE: deleteBlockchain,
+		Args: cobrautils.ExactArgs(1),
 	}
+	cmd.Flags().BoolVarP(&forceDelete, forceFlag, "f", false, "only remove the local configuration, even if the blockchain is still deployed")
+	cmd.Flags().BoolVar(&untrack, "untrack", false, "stop tracking the blockchain on the clusters that are syncing it")
+	return cmd
+}
+
+type blockchainDeployment struct {
+	Where       string
+	Details     string
+	ClusterName string
 }
 
 func deleteBlockchain(_ *cobra.Command, args []string) error {
-	return CallDeleteBlockchain(args[0])
+	blockchainName := args[0]
+	if app.SidecarExists(blockchainName) {
+		deployments, err := getBlockchainDeployments(blockchainName)
+		if err != nil {
+			return err
+		}
+		if len(deployments) > 0 {
+			printBlockchainDeployments(blockchainName, deployments)
+			if untrack {
+				if deployments, err = untrackBlockchain(blockchainName, deployments); err != nil {
+					return err
+				}
+			}
+		}
+		if len(deployments) > 0 {
+			if !forceDelete {
+				return fmt.Errorf(
+					"blockchain %s is still deployed. Use --force to only remove its local configuration, leaving the deployments as they are",
+					blockchainName,
+				)
+			}
+			ux.Logger.PrintToUser(logging.Yellow.Wrap("Removing only the local configuration of %s. Deployed nodes will keep validating it"), blockchainName)
+		}
+	}
+	return CallDeleteBlockchain(blockchainName)
+}
+
+// getBlockchainDeployments returns the networks where [blockchainName] was deployed, and
+// the clusters that are syncing it
+func getBlockchainDeployments(blockchainName string) ([]blockchainDeployment, error) {
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return nil, err
+	}
+	deployments := []blockchainDeployment{}
+	for _, networkName := range maps.Keys(sc.Networks) {
+		networkData := sc.Networks[networkName]
+		if networkData.SubnetID == ids.Empty && networkData.BlockchainID == ids.Empty {
+			continue
+		}
+		details := fmt.Sprintf("SubnetID %s, BlockchainID %s", networkData.SubnetID, networkData.BlockchainID)
+		if networkName == models.Local.String() {
+			if running, err := localnet.Deployed(blockchainName); err == nil && running {
+				details += " (running)"
+			}
+		}
+		deployments = append(deployments, blockchainDeployment{
+			Where:   networkName,
+			Details: details,
+		})
+	}
+	clustersConfig, err := app.GetClustersConfig()
+	if err != nil {
+		return nil, err
+	}
+	for _, clusterName := range maps.Keys(clustersConfig.Clusters) {
+		clusterConfig := clustersConfig.Clusters[clusterName]
+		if !slices.Contains(clusterConfig.Subnets, blockchainName) {
+			continue
+		}
+		deployments = append(deployments, blockchainDeployment{
+			Where:       "Cluster " + clusterName,
+			Details:     fmt.Sprintf("tracked by %d node(s)", len(clusterConfig.Nodes)),
+			ClusterName: clusterName,
+		})
+	}
+	sort.Slice(deployments, func(i, j int) bool {
+		return deployments[i].Where < deployments[j].Where
+	})
+	return deployments, nil
+}
+
+func printBlockchainDeployments(blockchainName string, deployments []blockchainDeployment) {
+	t := ux.DefaultTable(fmt.Sprintf("%s Deployments", blockchainName), table.Row{"Where", "Details"})
+	for _, deployment := range deployments {
+		t.AppendRow(table.Row{deployment.Where, deployment.Details})
+	}
+	ux.Logger.PrintToUser(t.Render())
+}
+
+// untrackBlockchain makes all clusters syncing [blockchainName] to stop tracking it, and
+// returns the deployments that remain
+func untrackBlockchain(blockchainName string, deployments []blockchainDeployment) ([]blockchainDeployment, error) {
+	remaining := []blockchainDeployment{}
+	for _, deployment := range deployments {
+		if deployment.ClusterName == "" {
+			remaining = append(remaining, deployment)
+			continue
+		}
+		ux.Logger.PrintToUser("Stopping tracking of %s on cluster %s", blockchainName, deployment.ClusterName)
+		if err := node.UntrackSubnet(app, deployment.ClusterName, blockchainName); err != nil {
+			return nil, err
+		}
+		ux.Logger.GreenCheckmarkToUser("Cluster %s stopped tracking %s", deployment.ClusterName, blockchainName)
+	}
+	return remaining, nil
 }
 
 func CallDeleteBlockchain(blockchainName string) error {
@@ -20,14 +20,18 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/events"
 	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
 	"github.com/ava-labs/avalanche-cli/pkg/keychain"
 	"github.com/ava-labs/avalanche-cli/pkg/localnet"
 	"github.com/ava-labs/avalanche-cli/pkg/metrics"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/networkupgrades"
 	"github.com/ava-labs/avalanche-cli/pkg/node"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/signing"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/txutils"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
@@ -39,7 +43,6 @@ import (
 	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/config"
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/ava-labs/avalanchego/network/peer"
 	avagoutils "github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -76,6 +79,7 @@ var (
 	useLocalMachine                 bool
 	useEwoq                         bool
 	ledgerAddresses                 []string
+	custodyAddresses                []string
 	subnetIDStr                     string
 	mainnetChainID                  uint32
 	skipCreatePrompt                bool
@@ -90,6 +94,7 @@ var (
 	bootstrapValidatorsJSONFilePath string
 	privateKeyFlags                 contract.PrivateKeyFlags
 	bootstrapEndpoints              []string
+	bootstrapBatchSize              int
 	convertOnly                     bool
 	numNodes                        uint32
 	relayerAmount                   float64
@@ -99,6 +104,8 @@ var (
 	icmKeyName                      string
 	cchainIcmKeyName                string
 	relayerAllowPrivateIPs          bool
+	deployYes                       bool
+	fromStatePath                   string
 
 	poSMinimumStakeAmount     uint64
 	poSMaximumStakeAmount     uint64
@@ -106,6 +113,8 @@ var (
 	poSMinimumDelegationFee   uint16
 	poSMaximumStakeMultiplier uint8
 	poSWeightToValueFactor    uint64
+	poSStakingTokenAddress    string
+	poSDeployStakingToken     bool
 	deployBalanceAVAX         float64
 
 	errMutuallyExlusiveControlKeys = errors.New("--control-keys and --same-control-key are mutually exclusive")
@@ -117,7 +126,7 @@ var (
 // avalanche blockchain deploy
 func newDeployCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "deploy [blockchainName]",
+		Use:   "deploy [blockchainName | --all | --manifest <file>]",
 		Short: "Deploys a blockchain configuration",
 		Long: `The blockchain deploy command deploys your Blockchain configuration locally, to Fuji Testnet, or to Mainnet.
 
@@ -128,10 +137,22 @@ attempts to deploy the same Blockchain to the same network (local, Fuji, Mainnet
 allowed. If you'd like to redeploy a Blockchain locally for testing, you must first call
 avalanche network clean to reset all deployed chain state. Subsequent local deploys
 redeploy the chain with fresh state. You can deploy the same Blockchain to multiple networks,
-so you can take your locally tested Blockchain and deploy it on Fuji or Mainnet.`,
+so you can take your locally tested Blockchain and deploy it on Fuji or Mainnet.
+
+Several Blockchains can be deployed in one call with --all, that deploys all the configured
+Blockchains not yet deployed to the network, or with --manifest, that deploys the Blockchains
+listed on a JSON file, after the ones they depend on:
+
+{"blockchains": [{"name": "chain1"}, {"name": "chain2", "dependsOn": ["chain1"]}]}
+
+A single relayer is then set up for all of them, and a summary of the deploys is printed.
+
+With --from-state, the EVM state written by blockchain export-state is added to the genesis
+allocations of the new deployment, so it starts with the same accounts, contracts and storage.
+Contracts predeployed on the genesis, as validator managers, are kept as they are.`,
 		RunE:              deployBlockchain,
 		PersistentPostRun: handlePostRun,
-		Args:              cobrautils.ExactArgs(1),
+		Args:              cobrautils.RangeArgs(0, 1),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, deploySupportedNetworkOptions)
 	privateKeyFlags.SetFlagNames("blockchain-private-key", "blockchain-key", "blockchain-genesis-key")
@@ -195,11 +216,14 @@ so you can take your locally tested Blockchain and deploy it on Fuji or Mainnet.
 	cmd.Flags().BoolVar(&generateNodeID, "generate-node-id", false, "whether to create new node id for bootstrap validators (Node-ID and BLS values in bootstrap JSON file will be overridden if --bootstrap-filepath flag is used)")
 	cmd.Flags().StringSliceVar(&bootstrapEndpoints, "bootstrap-endpoints", nil, "take validator node info from the given endpoints")
 	cmd.Flags().BoolVar(&convertOnly, "convert-only", false, "avoid node track, restart and poa manager setup")
+	cmd.Flags().BoolVarP(&deployYes, "yes", "y", false, "do not ask to confirm the deploy costs and balances shown before a Fuji or Mainnet deploy")
 	cmd.Flags().StringVar(&aggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	interchain.AddAggregationFlagsToCmd(cmd, &aggregationFlags)
 	cmd.Flags().StringSliceVar(&aggregatorExtraEndpoints, "aggregator-extra-endpoints", nil, "endpoints for extra nodes that are needed in signature aggregation")
 	cmd.Flags().BoolVar(&aggregatorAllowPrivatePeers, "aggregator-allow-private-peers", true, "allow the signature aggregator to connect to peers with private IP")
 	cmd.Flags().BoolVar(&useLocalMachine, "use-local-machine", false, "use local machine as a blockchain validator")
 	cmd.Flags().IntVar(&numBootstrapValidators, "num-bootstrap-validators", 0, "(only if --generate-node-id is true) number of bootstrap validators to set up in sovereign L1 validator)")
+	cmd.Flags().IntVar(&bootstrapBatchSize, "bootstrap-batch-size", constants.DefaultBootstrapValidatorsBatchSize, "max number of bootstrap validators set on the conversion tx. the remaining ones are registered afterwards, within the validator manager churn limit")
 	cmd.Flags().Float64Var(
 		&deployBalanceAVAX,
 		"balance",
@@ -215,9 +239,14 @@ so you can take your locally tested Blockchain and deploy it on Fuji or Mainnet.
 	cmd.Flags().Uint16Var(&poSMinimumDelegationFee, "pos-minimum-delegation-fee", 1, "minimum delegation fee")
 	cmd.Flags().Uint8Var(&poSMaximumStakeMultiplier, "pos-maximum-stake-multiplier", 1, "maximum stake multiplier")
 	cmd.Flags().Uint64Var(&poSWeightToValueFactor, "pos-weight-to-value-factor", 1, "weight to value factor")
+	cmd.Flags().StringVar(&poSStakingTokenAddress, "staking-token-address", "", "ERC20 token to be staked on the validator manager (ERC20 token PoS only)")
+	cmd.Flags().BoolVar(&poSDeployStakingToken, "deploy-staking-token", false, "deploy a mintable example ERC20 token to be staked on the validator manager (ERC20 token PoS only, local and devnet)")
 
 	cmd.Flags().BoolVar(&partialSync, "partial-sync", true, "set primary network partial sync for new validators")
 	cmd.Flags().Uint32Var(&numNodes, "num-nodes", constants.LocalNetworkNumNodes, "number of nodes to be created on local network deploy")
+	cmd.Flags().BoolVar(&deployAll, "all", false, "deploy all the configured blockchains not yet deployed to the network")
+	cmd.Flags().StringVar(&deployManifestPath, "manifest", "", "deploy the blockchains listed on the given JSON manifest file")
+	cmd.Flags().StringVar(&fromStatePath, "from-state", "", "seed the genesis with the EVM state exported to the given file by blockchain export-state")
 	return cmd
 }
 
@@ -333,6 +362,24 @@ func updateSubnetEVMGenesisChainID(genesisBytes []byte, newChainID uint) ([]byte
 	return json.MarshalIndent(genesisMap, "", "  ")
 }
 
+// seedGenesisFromState adds the EVM state exported to [statePath] to the allocations of
+// [genesisBytes]
+func seedGenesisFromState(genesisBytes []byte, statePath string) ([]byte, error) {
+	state, err := loadStateExport(statePath)
+	if err != nil {
+		return nil, err
+	}
+	genesisBytes, kept, err := evm.SeedGenesisAlloc(genesisBytes, state)
+	if err != nil {
+		return nil, err
+	}
+	ux.Logger.PrintToUser("Seeding genesis with %d accounts exported from chain %d at height %d", len(state.Accounts), state.ChainID, state.Height)
+	for _, address := range kept {
+		ux.Logger.PrintToUser("  keeping genesis contract at %s instead of the exported one", address.Hex())
+	}
+	return genesisBytes, nil
+}
+
 // updates sidecar with genesis mainnet id to use
 // given either by cmdline flag, original genesis id, or id obtained from the user
 func getSubnetEVMMainnetChainID(sc *models.Sidecar, blockchainName string) error {
@@ -396,6 +443,43 @@ func getSubnetEVMMainnetChainID(sc *models.Sidecar, blockchainName string) error
 
 // deployBlockchain is the cobra command run for deploying subnets
 func deployBlockchain(cmd *cobra.Command, args []string) error {
+	if deployAll || deployManifestPath != "" {
+		if fromStatePath != "" {
+			return fmt.Errorf("--from-state can only be used to deploy a single blockchain")
+		}
+		return deployBlockchains(cmd, args)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("blockchain name is required, or --all/--manifest to deploy several blockchains")
+	}
+	lastDeployResult = blockchainDeployResult{}
+	err := deploySingleBlockchain(cmd, args)
+	emitDeployResult(args[0], err)
+	return err
+}
+
+// emitDeployResult emits the outcome event of a deploy that got to start
+func emitDeployResult(blockchainName string, err error) {
+	network := lastDeployResult.network
+	if network.IsUndefined() {
+		return
+	}
+	data := map[string]interface{}{
+		"blockchain": blockchainName,
+		"network":    network.Name(),
+	}
+	if err != nil {
+		data["error"] = err.Error()
+		events.Emit(app, events.DeployFailed, fmt.Sprintf("Deploy of %s to %s failed: %s", blockchainName, network.Name(), err), data)
+		return
+	}
+	data["blockchainID"] = lastDeployResult.blockchainID.String()
+	data["icmDeployed"] = lastDeployResult.icmDeployed
+	events.Emit(app, events.DeploySucceeded, fmt.Sprintf("%s successfully deployed to %s", blockchainName, network.Name()), data)
+}
+
+// deploySingleBlockchain deploys the blockchain named by [args]
+func deploySingleBlockchain(cmd *cobra.Command, args []string) error {
 	blockchainName := args[0]
 
 	if err := CreateBlockchainFirst(cmd, blockchainName, skipCreatePrompt); err != nil {
@@ -463,11 +547,19 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 	if sidecar.VM == models.SubnetEvm && !isEVMGenesis {
 		return fmt.Errorf("failed to validate SubnetEVM genesis format: %w", validationErr)
 	}
+	if err := vm.CheckSidecarSubnetEVMVersion(app, sidecar, sidecar.VMVersion); err != nil {
+		return err
+	}
 
 	chainGenesis, err := app.LoadRawGenesis(chain)
 	if err != nil {
 		return err
 	}
+	if network.Kind != models.Local {
+		if err := signing.CheckDeployable(app, sidecar, chainGenesis); err != nil {
+			return err
+		}
+	}
 
 	if isEVMGenesis {
 		// is is a subnet evm or a custom vm based on subnet evm
@@ -487,7 +579,28 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if fromStatePath != "" {
+		if !isEVMGenesis {
+			return fmt.Errorf("--from-state is only supported on EVM blockchains")
+		}
+		chainGenesis, err = seedGenesisFromState(chainGenesis, fromStatePath)
+		if err != nil {
+			return err
+		}
+		// the seeded genesis is the one deployed, so it must pass the same checks
+		if network.Kind != models.Local {
+			if err := signing.CheckDeployable(app, sidecar, chainGenesis); err != nil {
+				return fmt.Errorf("genesis seeded from %s can't be deployed: %w", fromStatePath, err)
+			}
+		}
+	}
+
 	ux.Logger.PrintToUser("Deploying %s to %s", chains, network.Name())
+	lastDeployResult.network = network
+	events.Emit(app, events.DeployStarted, fmt.Sprintf("Deploying %s to %s", blockchainName, network.Name()), map[string]interface{}{
+		"blockchain": blockchainName,
+		"network":    network.Name(),
+	})
 
 	if network.Kind == models.Local {
 		app.Log.Debug("Deploy local")
@@ -523,7 +636,7 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 
 		// check if blockchain rpc version matches what is currently running
 		// for the case version or binary was provided
-		_, _, networkRPCVersion, err := localnet.GetVersion()
+		networkRPCVersion, err := networkcmd.GetLocalNetworkRPCVersion()
 		if err != nil {
 			return err
 		}
@@ -548,6 +661,14 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	upgradeFeature := networkupgrades.NonSovereignSubnets
+	if sidecar.Sovereign {
+		upgradeFeature = networkupgrades.SovereignL1
+	}
+	if err := networkupgrades.CheckFeature(app, network, upgradeFeature); err != nil {
+		return err
+	}
+
 	createSubnet := true
 	var subnetID ids.ID
 	if subnetIDStr != "" {
@@ -603,7 +724,18 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 				ux.Logger.PrintToUser("Using [%s] to be set as a change owner for leftover AVAX", changeOwnerAddress)
 			}
 		}
-		if !generateNodeID {
+		useDockerNetwork := network.Kind == models.Local && app.UseLocalDockerNetwork()
+		if !generateNodeID && useDockerNetwork && len(bootstrapEndpoints) == 0 {
+			// the node containers of the local network become the L1 bootstrap validators
+			bootstrapEndpoints, err = networkcmd.GetDockerNetworkEndpoints()
+			if err != nil {
+				return err
+			}
+			if numLocalNodes > 0 && numLocalNodes < len(bootstrapEndpoints) {
+				bootstrapEndpoints = bootstrapEndpoints[:numLocalNodes]
+			}
+		}
+		if !generateNodeID && !useDockerNetwork {
 			if network.Kind == models.Local {
 				useLocalMachine = true
 			}
@@ -868,6 +1000,26 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 	}
 	ux.Logger.PrintToUser("Your blockchain auth keys for chain creation: %s", subnetAuthKeys)
 
+	if err := deployPreflight(
+		network,
+		blockchainName,
+		sidecar,
+		kc,
+		availableBalance,
+		createSubnet,
+		bootstrapValidators,
+	); err != nil {
+		return err
+	}
+
+	var pendingBootstrapValidators []models.SubnetValidator
+	if sidecar.Sovereign {
+		bootstrapValidators, pendingBootstrapValidators, err = splitBootstrapValidators(sidecar, bootstrapValidators, bootstrapBatchSize)
+		if err != nil {
+			return err
+		}
+	}
+
 	// deploy to public network
 	deployer := subnet.NewPublicDeployer(app, kc, network)
 
@@ -988,6 +1140,14 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 		); err != nil {
 			return err
 		}
+		if len(pendingBootstrapValidators) > 0 {
+			networkData := sidecar.Networks[network.Name()]
+			networkData.PendingBootstrapValidators = pendingBootstrapValidators
+			sidecar.Networks[network.Name()] = networkData
+			if err := app.UpdateSidecar(&sidecar); err != nil {
+				return err
+			}
+		}
 
 		if !convertOnly && !generateNodeID {
 			clusterName := clusterNameFlagValue
@@ -1000,6 +1160,10 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 				}
 			}
 			switch {
+			case network.Kind == models.Local && app.UseLocalDockerNetwork():
+				if err := networkcmd.TrackSubnet(blockchainName, "", true); err != nil {
+					return err
+				}
 			case useLocalMachine:
 				if err := node.TrackSubnetWithLocalMachine(
 					app,
@@ -1065,22 +1229,44 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return err
 			}
+			aggregationConfig, err := interchain.GetAggregationConfig(app, aggregationFlags)
+			if err != nil {
+				return err
+			}
 			ownerAddress := common.HexToAddress(sidecar.ValidatorManagerOwner)
 			subnetSDK := blockchainSDK.Subnet{
-				SubnetID:            subnetID,
-				BlockchainID:        blockchainID,
-				OwnerAddress:        &ownerAddress,
-				RPC:                 rpcURL,
-				BootstrapValidators: avaGoBootstrapValidators,
+				SubnetID:             subnetID,
+				BlockchainID:         blockchainID,
+				OwnerAddress:         &ownerAddress,
+				RPC:                  rpcURL,
+				BootstrapValidators:  avaGoBootstrapValidators,
+				Logger:               ux.Logger.WithUserWarnings(app.Log),
+				SignatureAggregators: deployAggregators,
+				AggregationConfig:    aggregationConfig,
 			}
 			logLvl, err := logging.ToLevel(aggregatorLogLevel)
 			if err != nil {
 				logLvl = logging.Off
 			}
 			if sidecar.ValidatorManagement == models.ProofOfStake {
-				ux.Logger.PrintToUser("Initializing Native Token Proof of Stake Validator Manager contract on blockchain %s ...", blockchainName)
+				stakingToken, stakingTokenAddress := models.NativeTokenStaking, ""
+				if sidecar.ERC20Staking() {
+					stakingToken = models.ERC20TokenStaking
+					stakingTokenAddress, err = SetupStakingToken(
+						&sidecar,
+						network,
+						rpcURL,
+						genesisPrivateKey,
+						poSStakingTokenAddress,
+						poSDeployStakingToken,
+					)
+					if err != nil {
+						return err
+					}
+				}
+				ux.Logger.PrintToUser("Initializing %s Proof of Stake Validator Manager contract on blockchain %s ...", stakingToken, blockchainName)
 				if err := subnetSDK.InitializeProofOfStake(
-					network,
+					network.SDKNetwork(),
 					genesisPrivateKey,
 					extraAggregatorPeers,
 					aggregatorAllowPrivatePeers,
@@ -1093,6 +1279,7 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 						MaximumStakeMultiplier:  poSMaximumStakeMultiplier,
 						WeightToValueFactor:     big.NewInt(int64(poSWeightToValueFactor)),
 						RewardCalculatorAddress: validatorManagerSDK.RewardCalculatorAddress,
+						StakingTokenAddress:     stakingTokenAddress,
 					},
 				); err != nil {
 					return err
@@ -1101,7 +1288,7 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 			} else {
 				ux.Logger.PrintToUser("Initializing Proof of Authority Validator Manager contract on blockchain %s ...", blockchainName)
 				if err := subnetSDK.InitializeProofOfAuthority(
-					network,
+					network.SDKNetwork(),
 					genesisPrivateKey,
 					extraAggregatorPeers,
 					aggregatorAllowPrivatePeers,
@@ -1111,11 +1298,19 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 				}
 				ux.Logger.GreenCheckmarkToUser("Proof of Authority Validator Manager contract successfully initialized on blockchain %s", blockchainName)
 			}
+			if len(pendingBootstrapValidators) > 0 {
+				if err := registerPendingBootstrapValidators(deployer, network, blockchainName); err != nil {
+					return err
+				}
+			}
 		} else {
 			ux.Logger.GreenCheckmarkToUser("Converted blockchain successfully generated")
 			ux.Logger.PrintToUser("To finish conversion to sovereign L1, create the corresponding Avalanche node(s) with the provided Node ID and BLS Info")
 			ux.Logger.PrintToUser("Created Node ID and BLS Info can be found at %s", app.GetSidecarPath(blockchainName))
 			ux.Logger.PrintToUser("Once the Avalanche Node(s) are created and are tracking the blockchain, call `avalanche contract initValidatorManager %s` to finish conversion to sovereign L1", blockchainName)
+			if len(pendingBootstrapValidators) > 0 {
+				ux.Logger.PrintToUser("Then call `avalanche blockchain addBootstrapValidators %s` to register the remaining bootstrap validators", blockchainName)
+			}
 		}
 	} else {
 		if err := app.UpdateSidecarNetworks(
@@ -1153,16 +1348,24 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 		ux.Logger.PrintToUser(logging.Green.Wrap("At this point your are able to interact with your L1!"))
 	}
 
+	lastDeployResult.network = network
+	lastDeployResult.blockchainID = blockchainID
+
 	var icmErr, relayerErr error
 	if sidecar.TeleporterReady && tracked && !icmSpec.SkipICMDeploy {
 		chainSpec := contract.ChainSpec{
 			BlockchainName: blockchainName,
 		}
 		chainSpec.SetEnabled(true, false, false, false, false)
+		// use the key funded on genesis, unless other given by flag
+		deployICMKeyName := icmKeyName
+		if deployICMKeyName == constants.ICMKeyName && sidecar.TeleporterKey != "" {
+			deployICMKeyName = sidecar.TeleporterKey
+		}
 		deployICMFlags := messengercmd.DeployFlags{
 			ChainFlags: chainSpec,
 			PrivateKeyFlags: contract.PrivateKeyFlags{
-				KeyName: icmKeyName,
+				KeyName: deployICMKeyName,
 			},
 			DeployMessenger:              true,
 			DeployRegistry:               true,
@@ -1180,7 +1383,8 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 			ux.Logger.RedXToUser("Interchain Messaging is not deployed due to: %v", icmErr)
 		} else {
 			ux.Logger.GreenCheckmarkToUser("ICM is successfully deployed")
-			if network.Kind != models.Local && !useLocalMachine {
+			lastDeployResult.icmDeployed = true
+			if network.Kind != models.Local && !useLocalMachine && !deployingBlockchains {
 				if flag := cmd.Flags().Lookup(skipRelayerFlagName); flag != nil && !flag.Changed {
 					ux.Logger.PrintToUser("")
 					yes, err := app.Prompt.CaptureYesNo("Do you want to setup local relayer for the messages to be interchanged, as Interchain Messaging was deployed to your blockchain?")
@@ -1190,28 +1394,9 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 					icmSpec.SkipRelayerDeploy = !yes
 				}
 			}
-			if !icmSpec.SkipRelayerDeploy && network.Kind != models.Mainnet {
-				deployRelayerFlags := relayercmd.DeployFlags{
-					Version:            icmSpec.RelayerVersion,
-					BinPath:            icmSpec.RelayerBinPath,
-					LogLevel:           icmSpec.RelayerLogLevel,
-					RelayCChain:        relayCChain,
-					CChainFundingKey:   cChainFundingKey,
-					BlockchainsToRelay: []string{blockchainName},
-					Key:                relayerKeyName,
-					Amount:             relayerAmount,
-					AllowPrivateIPs:    relayerAllowPrivateIPs,
-				}
-				if network.Kind == models.Local || useLocalMachine {
-					deployRelayerFlags.Key = constants.ICMRelayerKeyName
-					deployRelayerFlags.Amount = constants.DefaultRelayerAmount
-					deployRelayerFlags.BlockchainFundingKey = constants.ICMKeyName
-				}
-				if network.Kind == models.Local {
-					deployRelayerFlags.CChainFundingKey = "ewoq"
-					deployRelayerFlags.CChainAmount = constants.DefaultRelayerAmount
-				}
-				if err := relayercmd.CallDeploy(nil, deployRelayerFlags, network); err != nil {
+			// on multiple blockchains deploys, a single relayer is set up at the end
+			if !icmSpec.SkipRelayerDeploy && network.Kind != models.Mainnet && !deployingBlockchains {
+				if err := deployRelayer(network, []string{blockchainName}); err != nil {
 					relayerErr = err
 					ux.Logger.RedXToUser("Relayer is not deployed due to: %v", relayerErr)
 				} else {
@@ -1225,7 +1410,14 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 	flags[constants.MetricsNetwork] = network.Name()
 	metrics.HandleTracking(cmd, constants.MetricsSubnetDeployCommand, app, flags)
 
+	syncDappProjects(blockchainName)
+
 	if network.Kind == models.Local && !simulatedPublicNetwork() {
+		if err := localnet.RefreshLocalDNS(app); errors.Is(err, localnet.ErrLocalDNSNeedsAdmin) {
+			ux.Logger.PrintToUser(err.Error())
+		} else if err != nil {
+			ux.Logger.RedXToUser("could not register local DNS name: %s", err)
+		}
 		ux.Logger.PrintToUser("")
 		_ = PrintSubnetInfo(blockchainName, true)
 	}
@@ -1248,6 +1440,37 @@ func deployBlockchain(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// deployRelayer sets up a local relayer for [blockchains] and the C-Chain
+func deployRelayer(network models.Network, blockchains []string) error {
+	deployRelayerFlags := relayercmd.DeployFlags{
+		Version:            icmSpec.RelayerVersion,
+		BinPath:            icmSpec.RelayerBinPath,
+		LogLevel:           icmSpec.RelayerLogLevel,
+		RelayCChain:        relayCChain,
+		CChainFundingKey:   cChainFundingKey,
+		BlockchainsToRelay: blockchains,
+		Key:                relayerKeyName,
+		Amount:             relayerAmount,
+		AllowPrivateIPs:    relayerAllowPrivateIPs,
+	}
+	if network.Kind == models.Local || useLocalMachine {
+		deployRelayerFlags.Key = constants.ICMRelayerKeyName
+		deployRelayerFlags.Amount = constants.DefaultRelayerAmount
+		deployRelayerFlags.BlockchainFundingKey = constants.ICMKeyName
+		if len(blockchains) == 1 {
+			// use the key funded on the blockchain genesis
+			if sc, err := app.LoadSidecar(blockchains[0]); err == nil && sc.TeleporterKey != "" {
+				deployRelayerFlags.BlockchainFundingKey = sc.TeleporterKey
+			}
+		}
+	}
+	if network.Kind == models.Local {
+		deployRelayerFlags.CChainFundingKey = "ewoq"
+		deployRelayerFlags.CChainAmount = constants.DefaultRelayerAmount
+	}
+	return relayercmd.CallDeploy(nil, deployRelayerFlags, network)
+}
+
 func setBootstrapValidatorValidationID(avaGoBootstrapValidators []*txs.ConvertSubnetToL1Validator, bootstrapValidators []models.SubnetValidator, subnetID ids.ID) {
 	for index, avagoValidator := range avaGoBootstrapValidators {
 		for bootstrapValidatorIndex, validator := range bootstrapValidators {
@@ -1297,7 +1520,7 @@ func getClusterBootstrapValidators(
 	return subnetValidators, nil
 }
 
-func getBLSInfo(publicKey, proofOfPossesion string) (signer.ProofOfPossession, error) {
+func GetBLSInfo(publicKey, proofOfPossesion string) (signer.ProofOfPossession, error) {
 	type jsonProofOfPossession struct {
 		PublicKey         string
 		ProofOfPossession string
@@ -1326,7 +1549,7 @@ func ConvertToAvalancheGoSubnetValidator(subnetValidators []models.SubnetValidat
 		if err != nil {
 			return nil, err
 		}
-		blsInfo, err := getBLSInfo(validator.BLSPublicKey, validator.BLSProofOfPossession)
+		blsInfo, err := GetBLSInfo(validator.BLSPublicKey, validator.BLSProofOfPossession)
 		if err != nil {
 			return nil, fmt.Errorf("failure parsing BLS info: %w", err)
 		}
@@ -1380,8 +1603,14 @@ func SaveNotFullySignedTx(
 	forceOverwrite bool,
 ) error {
 	signedCount := len(subnetAuthKeys) - len(remainingSubnetAuthKeys)
+	missingSignatures, _, err := txutils.GetMissingSignatures(tx)
+	if err != nil {
+		return err
+	}
+	// inputs paid by custodial addresses are left for them to sign
+	missingInputSignatures := missingSignatures - len(remainingSubnetAuthKeys)
 	ux.Logger.PrintToUser("")
-	if signedCount == len(subnetAuthKeys) {
+	if missingSignatures == 0 {
 		ux.Logger.PrintToUser("All %d required %s signatures have been signed. "+
 			"Saving tx to disk to enable commit.", len(subnetAuthKeys), txName)
 	} else {
@@ -1390,7 +1619,6 @@ func SaveNotFullySignedTx(
 	}
 	if outputTxPath == "" {
 		ux.Logger.PrintToUser("")
-		var err error
 		if forceOverwrite {
 			outputTxPath, err = app.Prompt.CaptureString("Path to export partially signed tx to")
 		} else {
@@ -1407,11 +1635,16 @@ func SaveNotFullySignedTx(
 	if err := txutils.SaveToDisk(tx, outputTxPath, forceOverwrite); err != nil {
 		return err
 	}
-	if signedCount == len(subnetAuthKeys) {
+	if missingSignatures == 0 {
 		PrintReadyToSignMsg(blockchainName, outputTxPath)
-	} else {
+		return nil
+	}
+	if len(remainingSubnetAuthKeys) > 0 {
 		PrintRemainingToSignMsg(blockchainName, remainingSubnetAuthKeys, outputTxPath)
 	}
+	if missingInputSignatures > 0 {
+		PrintCustodySignMsg(blockchainName, outputTxPath)
+	}
 	return nil
 }
 
@@ -1451,6 +1684,26 @@ func PrintRemainingToSignMsg(
 	}
 	ux.Logger.PrintToUser(cmdline)
 	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("To sign elsewhere, copy the tx or show it as QR code with:")
+	ux.Logger.PrintToUser("  avalanche transaction share --input-tx-filepath %s --copy --qr", outputTxPath)
+	ux.Logger.PrintToUser("")
+}
+
+// PrintCustodySignMsg shows how to get a tx signed by custodial signers
+func PrintCustodySignMsg(
+	blockchainName string,
+	outputTxPath string,
+) {
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Signatures of keys kept by custodial signers, as the ones of custodial addresses paying the fee,")
+	ux.Logger.PrintToUser("are added by exporting the signing payloads for them, and importing the signatures they return:")
+	args := fmt.Sprintf("%s --input-tx-filepath %s", blockchainName, outputTxPath)
+	if blockchainName == "" {
+		args = fmt.Sprintf("--input-tx-filepath %s", outputTxPath)
+	}
+	ux.Logger.PrintToUser("  avalanche transaction export %s", args)
+	ux.Logger.PrintToUser("  avalanche transaction import-signatures %s --signatures <signatures file>", args)
+	ux.Logger.PrintToUser("")
 }
 
 func PrintDeployResults(blockchainName string, subnetID ids.ID, blockchainID ids.ID) error {
@@ -1503,32 +1756,8 @@ func LoadBootstrapValidator(filepath string) ([]models.SubnetValidator, error) {
 	return subnetValidators, nil
 }
 
-func UrisToPeers(uris []string) ([]info.Peer, error) {
-	peers := []info.Peer{}
-	ctx, cancel := utils.GetANRContext()
-	defer cancel()
-	for _, uri := range uris {
-		client := info.NewClient(uri)
-		nodeID, _, err := client.GetNodeID(ctx)
-		if err != nil {
-			return nil, err
-		}
-		ip, err := client.GetNodeIP(ctx)
-		if err != nil {
-			return nil, err
-		}
-		peers = append(peers, info.Peer{
-			Info: peer.Info{
-				ID:       nodeID,
-				PublicIP: ip,
-			},
-		})
-	}
-	return peers, nil
-}
-
 func ConvertURIToPeers(uris []string) ([]info.Peer, error) {
-	aggregatorPeers, err := UrisToPeers(uris)
+	aggregatorPeers, err := interchain.URIsToPeers(uris)
 	if err != nil {
 		return nil, err
 	}
@@ -1565,7 +1794,7 @@ func GetAggregatorExtraPeers(
 	uris = append(uris, extraURIs...)
 	urisSet := set.Of(uris...)
 	uris = urisSet.List()
-	return UrisToPeers(uris)
+	return interchain.URIsToPeers(uris)
 }
 
 func GetAggregatorNetworkUris(clusterName string) ([]string, error) {
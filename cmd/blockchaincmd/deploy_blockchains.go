@@ -0,0 +1,385 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	sdkinterchain "github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	deployAll          bool
+	deployManifestPath string
+	// set while deploying several blockchains in one call
+	deployingBlockchains bool
+	// outcome of the last deployBlockchain call
+	lastDeployResult blockchainDeployResult
+	// signature aggregators shared by the blockchains deployed in one call
+	deployAggregators *sdkinterchain.SignatureAggregatorPool
+)
+
+type blockchainDeployResult struct {
+	network      models.Network
+	blockchainID ids.ID
+	icmDeployed  bool
+}
+
+type blockchainDeploySummary struct {
+	blockchainName string
+	status         string
+	result         blockchainDeployResult
+	err            error
+}
+
+const (
+	deployStatusDeployed = "Deployed"
+	deployStatusFailed   = "Failed"
+	deployStatusSkipped  = "Skipped"
+)
+
+// deployBlockchains deploys the blockchains selected by --all or --manifest, in
+// dependency order, and sets up a single relayer for all of them
+func deployBlockchains(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("blockchain name can not be given together with --all/--manifest")
+	}
+	if deployAll && deployManifestPath != "" {
+		return fmt.Errorf("--all and --manifest are mutually exclusive")
+	}
+	if subnetIDStr != "" || outputTxPath != "" || bootstrapValidatorsJSONFilePath != "" {
+		return fmt.Errorf("--subnet-id, --output-tx-path and --bootstrap-filepath are not supported when deploying several blockchains")
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		deploySupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	// avoid prompting for the network again on each deploy
+	switch network.Kind {
+	case models.Local:
+		globalNetworkFlags.UseLocal = true
+	case models.Fuji:
+		globalNetworkFlags.UseFuji = true
+	case models.Mainnet:
+		globalNetworkFlags.UseMainnet = true
+	case models.Devnet:
+		if globalNetworkFlags.ClusterName == "" {
+			globalNetworkFlags.UseDevnet = true
+			globalNetworkFlags.Endpoint = network.Endpoint
+		}
+	}
+	manifest, err := getDeployManifest(network)
+	if err != nil {
+		return err
+	}
+	order, err := manifest.DeployOrder()
+	if err != nil {
+		return err
+	}
+	if len(order) == 0 {
+		ux.Logger.PrintToUser("No blockchains to deploy to %s", network.Name())
+		return nil
+	}
+	dependencies := map[string][]string{}
+	for _, blockchain := range manifest.Blockchains {
+		dependencies[blockchain.Name] = blockchain.DependsOn
+	}
+	ux.Logger.PrintToUser("Deploying blockchains %s to %s", order, network.Name())
+
+	deployingBlockchains = true
+	deployAggregators = sdkinterchain.NewSignatureAggregatorPool()
+	initialFlags := saveDeployFlagValues()
+	defer func() {
+		deployingBlockchains = false
+		deployAggregators.Shutdown()
+		deployAggregators = nil
+		initialFlags.restore()
+	}()
+	summaries := map[string]*blockchainDeploySummary{}
+	relayed := []string{}
+	var relayerNetwork models.Network
+	for _, blockchainName := range order {
+		summary := &blockchainDeploySummary{blockchainName: blockchainName}
+		summaries[blockchainName] = summary
+		if failed := slices.IndexFunc(dependencies[blockchainName], func(dependency string) bool {
+			return summaries[dependency].status != deployStatusDeployed
+		}); failed != -1 {
+			summary.status = deployStatusSkipped
+			summary.err = fmt.Errorf("dependency %s was not deployed", dependencies[blockchainName][failed])
+			continue
+		}
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("=== Deploying blockchain %s ===", blockchainName)
+		ux.Logger.PrintToUser("")
+		// each deploy starts from the flags given by the user
+		initialFlags.restore()
+		err := deployBlockchain(cmd, []string{blockchainName})
+		summary.result = lastDeployResult
+		if err == nil && lastDeployResult.blockchainID == ids.Empty {
+			err = errors.New("blockchain was not created")
+		}
+		if err != nil {
+			ux.Logger.RedXToUser("failure deploying blockchain %s: %s", blockchainName, err)
+			summary.status = deployStatusFailed
+			summary.err = err
+			continue
+		}
+		summary.status = deployStatusDeployed
+		if summary.result.icmDeployed {
+			relayed = append(relayed, blockchainName)
+			relayerNetwork = summary.result.network
+		}
+	}
+
+	var relayerErr error
+	if len(relayed) > 0 && !icmSpec.SkipRelayerDeploy && relayerNetwork.Kind != models.Mainnet {
+		deploy := true
+		if relayerNetwork.Kind != models.Local && !useLocalMachine {
+			if flag := cmd.Flags().Lookup(skipRelayerFlagName); flag != nil && !flag.Changed {
+				ux.Logger.PrintToUser("")
+				deploy, err = app.Prompt.CaptureYesNo("Do you want to setup local relayer for the messages to be interchanged between the deployed blockchains?")
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if deploy {
+			ux.Logger.PrintToUser("")
+			if relayerErr = deployRelayer(relayerNetwork, relayed); relayerErr != nil {
+				ux.Logger.RedXToUser("Relayer is not deployed due to: %v", relayerErr)
+			} else {
+				ux.Logger.GreenCheckmarkToUser("Relayer is successfully deployed for %s", relayed)
+			}
+		}
+	}
+
+	ux.Logger.PrintToUser("")
+	t := ux.DefaultTable("Blockchains deploy summary", table.Row{"Blockchain", "Status", "Blockchain ID", "ICM", "Error"})
+	numFailed := 0
+	for _, blockchainName := range order {
+		summary := summaries[blockchainName]
+		blockchainID := ""
+		if summary.result.blockchainID != ids.Empty {
+			blockchainID = summary.result.blockchainID.String()
+		}
+		icm := "No"
+		if summary.result.icmDeployed {
+			icm = "Yes"
+			if relayerErr == nil && len(relayed) > 0 {
+				icm = "Yes, relayed"
+			}
+		}
+		errMsg := ""
+		if summary.err != nil {
+			numFailed++
+			errMsg = summary.err.Error()
+		}
+		t.AppendRow(table.Row{blockchainName, summary.status, blockchainID, icm, errMsg})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	if numFailed > 0 {
+		return fmt.Errorf("%d of %d blockchains were not deployed", numFailed, len(order))
+	}
+	return nil
+}
+
+// getDeployManifest returns the manifest given by --manifest, or one listing all
+// the blockchains not yet deployed to [network] for --all
+func getDeployManifest(network models.Network) (models.DeployManifest, error) {
+	if deployManifestPath != "" {
+		return models.LoadDeployManifest(deployManifestPath)
+	}
+	blockchainNames, err := app.GetBlockchainNames()
+	if err != nil {
+		return models.DeployManifest{}, err
+	}
+	deployed, err := app.GetBlockchainNamesOnNetwork(network, false)
+	if err != nil {
+		return models.DeployManifest{}, err
+	}
+	manifest := models.DeployManifest{}
+	for _, blockchainName := range blockchainNames {
+		if slices.Contains(deployed, blockchainName) {
+			ux.Logger.PrintToUser("Skipping blockchain %s, already deployed to %s", blockchainName, network.Name())
+			continue
+		}
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			return models.DeployManifest{}, err
+		}
+		if sc.ImportedFromAPM {
+			continue
+		}
+		manifest.Blockchains = append(manifest.Blockchains, models.DeployManifestBlockchain{Name: blockchainName})
+	}
+	return manifest, nil
+}
+
+// deployFlagValues holds the deploy settings that deployBlockchain may change
+// while deploying a blockchain
+type deployFlagValues struct {
+	networkFlags             networkoptions.NetworkFlags
+	sameControlKey           bool
+	keyName                  string
+	threshold                uint32
+	controlKeys              []string
+	subnetAuthKeys           []string
+	userProvidedAvagoVersion string
+	useLedger                bool
+	useLocalMachine          bool
+	useEwoq                  bool
+	ledgerAddresses          []string
+	mainnetChainID           uint32
+	avagoBinaryPath          string
+	numBootstrapValidators   int
+	numLocalNodes            int
+	partialSync              bool
+	changeOwnerAddress       string
+	subnetOnly               bool
+	icmSpec                  subnet.ICMSpec
+	generateNodeID           bool
+	privateKeyFlags          contract.PrivateKeyFlags
+	bootstrapEndpoints       []string
+	bootstrapBatchSize       int
+	convertOnly              bool
+	numNodes                 uint32
+	relayerAmount            float64
+	relayerKeyName           string
+	relayCChain              bool
+	cChainFundingKey         string
+	icmKeyName               string
+	cchainIcmKeyName         string
+	relayerAllowPrivateIPs   bool
+	deployYes                bool
+	fromStatePath            string
+	poSMinimumStakeAmount    uint64
+	poSMaximumStakeAmount    uint64
+	poSMinimumStakeDuration  uint64
+	poSMinimumDelegationFee  uint16
+	poSMaxStakeMultiplier    uint8
+	poSWeightToValueFactor   uint64
+	poSStakingTokenAddress   string
+	poSDeployStakingToken    bool
+	deployBalanceAVAX        float64
+	aggregatorLogLevel       string
+	aggregatorExtraEndpoints []string
+	aggregatorAllowPrivate   bool
+}
+
+func saveDeployFlagValues() deployFlagValues {
+	return deployFlagValues{
+		networkFlags:             globalNetworkFlags,
+		sameControlKey:           sameControlKey,
+		keyName:                  keyName,
+		threshold:                threshold,
+		controlKeys:              slices.Clone(controlKeys),
+		subnetAuthKeys:           slices.Clone(subnetAuthKeys),
+		userProvidedAvagoVersion: userProvidedAvagoVersion,
+		useLedger:                useLedger,
+		useLocalMachine:          useLocalMachine,
+		useEwoq:                  useEwoq,
+		ledgerAddresses:          slices.Clone(ledgerAddresses),
+		mainnetChainID:           mainnetChainID,
+		avagoBinaryPath:          avagoBinaryPath,
+		numBootstrapValidators:   numBootstrapValidators,
+		numLocalNodes:            numLocalNodes,
+		partialSync:              partialSync,
+		changeOwnerAddress:       changeOwnerAddress,
+		subnetOnly:               subnetOnly,
+		icmSpec:                  icmSpec,
+		generateNodeID:           generateNodeID,
+		privateKeyFlags:          privateKeyFlags,
+		bootstrapEndpoints:       slices.Clone(bootstrapEndpoints),
+		bootstrapBatchSize:       bootstrapBatchSize,
+		convertOnly:              convertOnly,
+		numNodes:                 numNodes,
+		relayerAmount:            relayerAmount,
+		relayerKeyName:           relayerKeyName,
+		relayCChain:              relayCChain,
+		cChainFundingKey:         cChainFundingKey,
+		icmKeyName:               icmKeyName,
+		cchainIcmKeyName:         cchainIcmKeyName,
+		relayerAllowPrivateIPs:   relayerAllowPrivateIPs,
+		deployYes:                deployYes,
+		fromStatePath:            fromStatePath,
+		poSMinimumStakeAmount:    poSMinimumStakeAmount,
+		poSMaximumStakeAmount:    poSMaximumStakeAmount,
+		poSMinimumStakeDuration:  poSMinimumStakeDuration,
+		poSMinimumDelegationFee:  poSMinimumDelegationFee,
+		poSMaxStakeMultiplier:    poSMaximumStakeMultiplier,
+		poSWeightToValueFactor:   poSWeightToValueFactor,
+		poSStakingTokenAddress:   poSStakingTokenAddress,
+		poSDeployStakingToken:    poSDeployStakingToken,
+		deployBalanceAVAX:        deployBalanceAVAX,
+		aggregatorLogLevel:       aggregatorLogLevel,
+		aggregatorExtraEndpoints: slices.Clone(aggregatorExtraEndpoints),
+		aggregatorAllowPrivate:   aggregatorAllowPrivatePeers,
+	}
+}
+
+func (v deployFlagValues) restore() {
+	globalNetworkFlags = v.networkFlags
+	sameControlKey = v.sameControlKey
+	keyName = v.keyName
+	threshold = v.threshold
+	controlKeys = slices.Clone(v.controlKeys)
+	subnetAuthKeys = slices.Clone(v.subnetAuthKeys)
+	userProvidedAvagoVersion = v.userProvidedAvagoVersion
+	useLedger = v.useLedger
+	useLocalMachine = v.useLocalMachine
+	useEwoq = v.useEwoq
+	ledgerAddresses = slices.Clone(v.ledgerAddresses)
+	mainnetChainID = v.mainnetChainID
+	avagoBinaryPath = v.avagoBinaryPath
+	numBootstrapValidators = v.numBootstrapValidators
+	numLocalNodes = v.numLocalNodes
+	partialSync = v.partialSync
+	changeOwnerAddress = v.changeOwnerAddress
+	subnetOnly = v.subnetOnly
+	icmSpec = v.icmSpec
+	generateNodeID = v.generateNodeID
+	privateKeyFlags = v.privateKeyFlags
+	bootstrapEndpoints = slices.Clone(v.bootstrapEndpoints)
+	bootstrapBatchSize = v.bootstrapBatchSize
+	convertOnly = v.convertOnly
+	numNodes = v.numNodes
+	relayerAmount = v.relayerAmount
+	relayerKeyName = v.relayerKeyName
+	relayCChain = v.relayCChain
+	cChainFundingKey = v.cChainFundingKey
+	icmKeyName = v.icmKeyName
+	cchainIcmKeyName = v.cchainIcmKeyName
+	relayerAllowPrivateIPs = v.relayerAllowPrivateIPs
+	deployYes = v.deployYes
+	fromStatePath = v.fromStatePath
+	poSMinimumStakeAmount = v.poSMinimumStakeAmount
+	poSMaximumStakeAmount = v.poSMaximumStakeAmount
+	poSMinimumStakeDuration = v.poSMinimumStakeDuration
+	poSMinimumDelegationFee = v.poSMinimumDelegationFee
+	poSMaximumStakeMultiplier = v.poSMaxStakeMultiplier
+	poSWeightToValueFactor = v.poSWeightToValueFactor
+	poSStakingTokenAddress = v.poSStakingTokenAddress
+	poSDeployStakingToken = v.poSDeployStakingToken
+	deployBalanceAVAX = v.deployBalanceAVAX
+	aggregatorLogLevel = v.aggregatorLogLevel
+	aggregatorExtraEndpoints = slices.Clone(v.aggregatorExtraEndpoints)
+	aggregatorAllowPrivatePeers = v.aggregatorAllowPrivate
+}
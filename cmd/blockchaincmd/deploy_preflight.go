@@ -0,0 +1,273 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/preflight"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	avagofee "github.com/ava-labs/avalanchego/vms/platformvm/txs/fee"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	pwallet "github.com/ava-labs/avalanchego/wallet/chain/p"
+	"github.com/ava-labs/avalanchego/wallet/chain/p/builder"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	pChainAccount = "p-chain"
+	l1Account     = "l1"
+	// denomination of AVAX on P-Chain, in nAVAX
+	avaxDecimals = 9
+)
+
+var errDeployCancelled = errors.New("deploy cancelled")
+
+// deployPreflight computes the fees and balances needed by the whole deploy flow of
+// [blockchainName] into Fuji or Mainnet: subnet and chain creation, L1 conversion, bootstrap
+// validators balances, and contract deploys on the new L1. All of them are shown on a single
+// screen that the user needs to confirm, unless --yes is given or there is no terminal to
+// answer. As fees are estimated, balance shortfalls are warned about, and left for the
+// transactions to fail on
+func deployPreflight(
+	network models.Network,
+	blockchainName string,
+	sidecar models.Sidecar,
+	kc *keychain.Keychain,
+	availableBalance uint64,
+	createSubnet bool,
+	bootstrapValidators []models.SubnetValidator,
+) error {
+	if network.Kind != models.Fuji && network.Kind != models.Mainnet {
+		return nil
+	}
+	report, err := getDeployCostReport(network, blockchainName, sidecar, kc, availableBalance, createSubnet, bootstrapValidators)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(report.Render())
+	if err := report.Err(); err != nil {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Warning: %s"), err)
+	}
+	if deployYes || simulatedPublicNetwork() {
+		return nil
+	}
+	if !prompts.IsInteractive() {
+		ux.Logger.PrintToUser("No terminal to confirm the deploy costs, proceeding")
+		return nil
+	}
+	yes, err := app.Prompt.CaptureYesNo(fmt.Sprintf("Proceed with the deploy of %s to %s?", blockchainName, network.Name()))
+	if err != nil {
+		return err
+	}
+	if !yes {
+		return errDeployCancelled
+	}
+	return nil
+}
+
+// getDeployCostReport aggregates the costs of the deploy flow, per paying account
+func getDeployCostReport(
+	network models.Network,
+	blockchainName string,
+	sidecar models.Sidecar,
+	kc *keychain.Keychain,
+	availableBalance uint64,
+	createSubnet bool,
+	bootstrapValidators []models.SubnetValidator,
+) (*preflight.Report, error) {
+	report := preflight.NewReport(fmt.Sprintf("%s deploy costs on %s", blockchainName, network.Name()))
+	payerAddresses, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return nil, err
+	}
+	report.AddAccount(pChainAccount, preflight.Account{
+		Chain:    "P-Chain",
+		Address:  strings.Join(payerAddresses, "\n"),
+		Symbol:   "AVAX",
+		Decimals: avaxDecimals,
+		Balance:  new(big.Int).SetUint64(availableBalance),
+	})
+	fees, err := newPChainFeeEstimator(network)
+	if err != nil {
+		return nil, fmt.Errorf("failure getting P-Chain fees: %w", err)
+	}
+	subnetAuth := &secp256k1fx.Input{SigIndices: make([]uint32, threshold)}
+	if createSubnet {
+		fee, err := fees.fee(&txs.CreateSubnetTx{
+			BaseTx: sampleBaseTx(),
+			Owner:  &secp256k1fx.OutputOwners{Threshold: threshold, Addrs: make([]ids.ShortID, len(controlKeys))},
+		})
+		if err != nil {
+			return nil, err
+		}
+		report.AddCost(pChainAccount, "CreateSubnetTx fee", new(big.Int).SetUint64(fee), true)
+	}
+	if subnetOnly {
+		return report, nil
+	}
+	genesisData, err := app.LoadRawGenesis(blockchainName)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := fees.fee(&txs.CreateChainTx{
+		BaseTx:      sampleBaseTx(),
+		ChainName:   blockchainName,
+		GenesisData: genesisData,
+		SubnetAuth:  subnetAuth,
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.AddCost(pChainAccount, "CreateChainTx fee", new(big.Int).SetUint64(fee), true)
+	if !sidecar.Sovereign {
+		return report, nil
+	}
+	fee, err = fees.fee(sampleConvertSubnetToL1Tx(len(bootstrapValidators), threshold))
+	if err != nil {
+		return nil, err
+	}
+	report.AddCost(pChainAccount, "ConvertSubnetToL1Tx fee", new(big.Int).SetUint64(fee), true)
+	for _, validator := range bootstrapValidators {
+		report.AddCost(
+			pChainAccount,
+			fmt.Sprintf("Balance of bootstrap validator %s", validator.NodeID),
+			new(big.Int).SetUint64(validator.Balance),
+			false,
+		)
+	}
+	if convertOnly || generateNodeID || sidecar.VM != models.SubnetEvm {
+		return report, nil
+	}
+	if err := addL1ContractCosts(report, network, blockchainName, sidecar); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// addL1ContractCosts adds the contract deploys done on the new L1, that are paid by the
+// CLI managed key funded on the L1 genesis
+func addL1ContractCosts(
+	report *preflight.Report,
+	network models.Network,
+	blockchainName string,
+	sidecar models.Sidecar,
+) error {
+	genesisData, err := app.LoadRawGenesis(blockchainName)
+	if err != nil {
+		return err
+	}
+	keyName, address, _, err := contract.GetBlockchainAirdropKeyInfo(app, network, blockchainName, genesisData)
+	if err != nil {
+		return err
+	}
+	if address == "" {
+		report.AddProblem("no key managed by the CLI is funded on %s genesis, so the validator manager can not be initialized", blockchainName)
+		return nil
+	}
+	genesis, err := utils.ByteSliceToSubnetEvmGenesis(genesisData)
+	if err != nil {
+		return err
+	}
+	balance := big.NewInt(0)
+	if alloc, ok := genesis.Alloc[common.HexToAddress(address)]; ok && alloc.Balance != nil {
+		balance = alloc.Balance
+	}
+	report.AddAccount(l1Account, preflight.Account{
+		Chain:    fmt.Sprintf("%s (genesis)", blockchainName),
+		Address:  fmt.Sprintf("%s\n(key %s)", address, keyName),
+		Symbol:   sidecar.TokenSymbol,
+		Decimals: sidecar.GetTokenDecimals(),
+		Balance:  balance,
+	})
+	if balance.Sign() == 0 {
+		report.AddProblem("key %s has no balance on %s genesis to pay for contract deploys", keyName, blockchainName)
+	}
+	// gas usage of contract deploys is not known in advance, so only the payer is shown
+	report.AddCost(l1Account, "Validator manager initialization gas", nil, false)
+	if sidecar.TeleporterReady && !icmSpec.SkipICMDeploy {
+		report.AddCost(l1Account, "ICM messenger and registry deploy gas", nil, false)
+	}
+	return nil
+}
+
+// pChainFeeEstimator computes the fees of P-Chain txs with the fee rules currently in effect
+// on the network: the dynamic gas price once Etna is active, the static fees before
+type pChainFeeEstimator struct {
+	context    *builder.Context
+	calculator avagofee.Calculator
+}
+
+func newPChainFeeEstimator(network models.Network) (*pChainFeeEstimator, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	pContext, err := pwallet.NewContextFromURI(ctx, network.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	estimator := &pChainFeeEstimator{context: pContext}
+	if pContext.GasPrice != 0 {
+		estimator.calculator = avagofee.NewDynamicCalculator(pContext.ComplexityWeights, pContext.GasPrice)
+	} else {
+		estimator.calculator = avagofee.NewStaticCalculator(pContext.StaticFeeConfig)
+	}
+	return estimator, nil
+}
+
+// fee returns the fee of [tx]. Txs unknown to the static fee rules pay the base tx fee
+func (e *pChainFeeEstimator) fee(tx txs.UnsignedTx) (uint64, error) {
+	fee, err := e.calculator.CalculateFee(tx)
+	if errors.Is(err, avagofee.ErrUnsupportedTx) {
+		return e.context.StaticFeeConfig.TxFee, nil
+	}
+	return fee, err
+}
+
+// sampleConvertSubnetToL1Tx has the size of a ConvertSubnetToL1Tx setting [numValidators]
+// validators, authorized by [numAuthSigners] control keys
+func sampleConvertSubnetToL1Tx(numValidators int, numAuthSigners uint32) *txs.ConvertSubnetToL1Tx {
+	validators := []*txs.ConvertSubnetToL1Validator{}
+	for range numValidators {
+		owner := message.PChainOwner{Threshold: 1, Addresses: []ids.ShortID{{}}}
+		validators = append(validators, &txs.ConvertSubnetToL1Validator{
+			NodeID:                make([]byte, ids.NodeIDLen),
+			RemainingBalanceOwner: owner,
+			DeactivationOwner:     owner,
+		})
+	}
+	return &txs.ConvertSubnetToL1Tx{
+		BaseTx:     sampleBaseTx(),
+		Address:    make([]byte, common.AddressLength),
+		Validators: validators,
+		SubnetAuth: &secp256k1fx.Input{SigIndices: make([]uint32, numAuthSigners)},
+	}
+}
+
+// sampleBaseTx has the inputs and outputs a deploy tx usually has: a single UTXO spent,
+// and its change. Their size, not their values, is what the fee depends on
+func sampleBaseTx() txs.BaseTx {
+	return txs.BaseTx{BaseTx: avax.BaseTx{
+		Ins: []*avax.TransferableInput{{
+			In: &secp256k1fx.TransferInput{Input: secp256k1fx.Input{SigIndices: []uint32{0}}},
+		}},
+		Outs: []*avax.TransferableOutput{{
+			Out: &secp256k1fx.TransferOutput{
+				OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{{}}},
+			},
+		}},
+	}}
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/gas"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	avagofee "github.com/ava-labs/avalanchego/vms/platformvm/txs/fee"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/chain/p/builder"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPChainFeeEstimator(t *testing.T) {
+	require := require.New(t)
+	staticContext := &builder.Context{StaticFeeConfig: avagofee.StaticConfig{TxFee: 1, CreateSubnetTxFee: 2, CreateBlockchainTxFee: 3}}
+	static := &pChainFeeEstimator{context: staticContext, calculator: avagofee.NewStaticCalculator(staticContext.StaticFeeConfig)}
+	dynamicContext := &builder.Context{ComplexityWeights: gas.Dimensions{1, 1, 1, 1}, GasPrice: 10}
+	dynamic := &pChainFeeEstimator{context: dynamicContext, calculator: avagofee.NewDynamicCalculator(dynamicContext.ComplexityWeights, dynamicContext.GasPrice)}
+
+	subnetAuth := &secp256k1fx.Input{SigIndices: []uint32{0}}
+	createChain := func(genesisData []byte) *txs.CreateChainTx {
+		return &txs.CreateChainTx{BaseTx: sampleBaseTx(), ChainName: "chain", GenesisData: genesisData, SubnetAuth: subnetAuth}
+	}
+	fee, err := static.fee(createChain(nil))
+	require.NoError(err)
+	require.Equal(uint64(3), fee)
+	// no static fee for conversions, the base tx fee is paid
+	fee, err = static.fee(&txs.ConvertSubnetToL1Tx{BaseTx: sampleBaseTx(), SubnetAuth: subnetAuth})
+	require.NoError(err)
+	require.Equal(uint64(1), fee)
+
+	// dynamic fees grow with the tx size
+	smallFee, err := dynamic.fee(createChain(nil))
+	require.NoError(err)
+	require.NotZero(smallFee)
+	bigFee, err := dynamic.fee(createChain(make([]byte, 1000)))
+	require.NoError(err)
+	require.Equal(smallFee+1000*10, bigFee)
+	_, err = dynamic.fee(&txs.CreateSubnetTx{
+		BaseTx: sampleBaseTx(),
+		Owner:  &secp256k1fx.OutputOwners{Threshold: 1, Addrs: make([]ids.ShortID, 2)},
+	})
+	require.NoError(err)
+}
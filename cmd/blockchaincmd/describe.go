@@ -7,26 +7,33 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
 	icmgenesis "github.com/ava-labs/avalanche-cli/pkg/interchain/genesis"
-	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/localnet"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/txutils"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
 	anr_utils "github.com/ava-labs/avalanche-network-runner/utils"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/api"
 	"github.com/ava-labs/subnet-evm/core"
-	"github.com/ava-labs/subnet-evm/params"
+	subnetEvmPlugin "github.com/ava-labs/subnet-evm/plugin/evm"
 	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
 	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
 	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
@@ -38,6 +45,7 @@ import (
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
 )
 
 var printGenesisOnly bool
@@ -49,7 +57,11 @@ func newDescribeCmd() *cobra.Command {
 		Short: "Print a summary of the blockchain’s configuration",
 		Long: `The blockchain describe command prints the details of a Blockchain configuration to the console.
 By default, the command prints a summary of the configuration. By providing the --genesis
-flag, the command instead prints out the raw genesis file.`,
+flag, the command instead prints out the raw genesis file.
+
+For deployed sovereign L1s, the summary also includes the live validator set as seen by the
+P-Chain and the L1 nodes (weights, balances, uptime), the validator manager owner and churn
+settings, and the validator set changes initiated on the manager that are still pending.`,
 		RunE: describe,
 		Args: cobrautils.ExactArgs(1),
 	}
@@ -60,7 +72,122 @@ flag, the command instead prints out the raw genesis file.`,
 		false,
 		"Print the genesis to the console directly instead of the summary",
 	)
-	return cmd
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+// l1Deployment is a sovereign L1 deployment whose live state is to be described
+type l1Deployment struct {
+	network        models.Network
+	subnetID       ids.ID
+	rpcURL         string
+	managerAddress common.Address
+}
+
+// printL1ValidatorSet prints the current validators of the L1 [deployment] as seen by the
+// P-Chain and the L1 nodes, the state of its validator manager, and the validator set changes
+// initiated on the manager that were not completed yet
+func printL1ValidatorSet(deployment l1Deployment) error {
+	pClient := platformvm.NewClient(deployment.network.Endpoint)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	validators, err := pClient.GetValidatorsAt(ctx, deployment.subnetID, api.ProposedHeight)
+	if err != nil {
+		return err
+	}
+	// uptime is only known by the L1 nodes
+	l1Validators := map[ids.NodeID]subnetEvmPlugin.CurrentValidator{}
+	if currentValidators, err := utils.GetL1Validators(deployment.rpcURL); err == nil {
+		for _, validator := range currentValidators {
+			l1Validators[validator.NodeID] = validator
+		}
+	}
+	managerAddress := deployment.managerAddress
+	net := deployment.network.Name()
+
+	nodeIDs := maps.Keys(validators)
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i].String() < nodeIDs[j].String() })
+	t := ux.DefaultTable(
+		fmt.Sprintf("Validators (%s)", net),
+		table.Row{"Node ID", "Validation ID", "Weight", "Balance (AVAX)", "Uptime", "Connected"},
+	)
+	for _, nodeID := range nodeIDs {
+		validationID := ids.Empty
+		uptime := constants.NotAvailableLabel
+		connected := constants.NotAvailableLabel
+		if l1Validator, ok := l1Validators[nodeID]; ok {
+			validationID = l1Validator.ValidationID
+			uptime = fmt.Sprintf("%.2f%%", l1Validator.UptimePercentage)
+			connected = strconv.FormatBool(l1Validator.IsConnected)
+		} else if registeredID, err := validatormanager.GetRegisteredValidator(deployment.rpcURL, managerAddress, nodeID); err == nil {
+			validationID = registeredID
+		}
+		balance := constants.NotAvailableLabel
+		if validationID != ids.Empty {
+			if pChainBalance, err := txutils.GetValidatorPChainBalanceValidationID(deployment.network, validationID); err == nil {
+				balance = fmt.Sprintf("%.9f", float64(pChainBalance)/float64(units.Avax))
+			}
+		}
+		t.AppendRow(table.Row{nodeID, validationID, validators[nodeID].Weight, balance, uptime, connected})
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(t.Render())
+
+	t = ux.DefaultTable(fmt.Sprintf("Validator Manager (%s)", net), nil)
+	t.AppendRow(table.Row{"Address", managerAddress.Hex()})
+	if owner, err := validatormanager.GetManagerOwner(deployment.rpcURL, managerAddress); err == nil {
+		t.AppendRow(table.Row{"Owner", owner.Hex()})
+	}
+	if state, err := validatormanager.GetManagerState(deployment.rpcURL, managerAddress); err == nil {
+		churnPeriod := "none"
+		if state.ChurnPeriodSeconds > 0 {
+			churnPeriod = (time.Duration(state.ChurnPeriodSeconds) * time.Second).String()
+		}
+		t.AppendRow(table.Row{"Churn Period", churnPeriod})
+		t.AppendRow(table.Row{"Max Churn Per Period", fmt.Sprintf("%d%%", state.MaximumChurnPercentage)})
+		if state.ChurnPeriodStartedAt > 0 {
+			t.AppendRow(table.Row{"Current Churn Period Start", time.Unix(int64(state.ChurnPeriodStartedAt), 0).UTC().Format(constants.TimeParseLayout)})
+			t.AppendRow(table.Row{"Current Churn Period Usage", fmt.Sprintf("%d of initial weight %d", state.ChurnPeriodChurnAmount, state.ChurnPeriodInitialWeight)})
+		}
+		t.AppendRow(table.Row{"Total Weight", state.ChurnPeriodTotalWeight})
+	} else {
+		ux.Logger.RedXToUser("could not get the validator manager state: %s", err)
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(t.Render())
+
+	client, err := evm.GetClient(deployment.rpcURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	events, err := validatormanager.GetValidatorManagerEvents(client, managerAddress, 0, nil)
+	if err != nil {
+		return err
+	}
+	pendingChanges := validatormanager.GetPendingValidatorChanges(events)
+	if len(pendingChanges) == 0 {
+		ux.Logger.PrintToUser("No pending validator set changes on %s", net)
+		return nil
+	}
+	t = ux.DefaultTable(
+		fmt.Sprintf("Pending Validator Set Changes (%s)", net),
+		table.Row{"Change", "Validator", "Weight", "Initiated On Tx"},
+	)
+	for _, change := range pendingChanges {
+		validator := change.ValidationID.String()
+		// removed validators are still known by the P-Chain
+		if nodeID, err := txutils.GetValidatorNodeIDValidationID(deployment.network, change.ValidationID); err == nil {
+			validator = nodeID.String()
+		}
+		weight := ""
+		if change.Change == validatormanager.PendingAddition {
+			weight = strconv.FormatUint(change.Weight, 10)
+		}
+		t.AppendRow(table.Row{change.Change, validator, weight, change.TxHash.Hex()})
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(t.Render())
+	return nil
 }
 
 func printGenesis(blockchainName string) error {
@@ -108,6 +235,7 @@ func PrintSubnetInfo(blockchainName string, onlyLocalnetInfo bool) error {
 	locallyDeployed := false
 	localEndpoint := ""
 	localChainID := ""
+	l1Deployments := []l1Deployment{}
 	for net, data := range sc.Networks {
 		network, err := app.GetNetworkFromSidecarNetworkName(net)
 		if err != nil {
@@ -178,6 +306,19 @@ func PrintSubnetInfo(blockchainName string, onlyLocalnetInfo bool) error {
 			localEndpoint = endpoint
 		}
 		t.AppendRow(table.Row{net, "RPC Endpoint", endpoint})
+		if sc.Sovereign && data.SubnetID != ids.Empty {
+			l1Deployments = append(l1Deployments, l1Deployment{
+				network:        network,
+				subnetID:       data.SubnetID,
+				rpcURL:         endpoint,
+				managerAddress: validatormanager.GetValidatorManagerAddress(data),
+			})
+		}
+		if network.Kind == models.Local {
+			if localDNSEndpoint := localnet.GetLocalDNSURL(app, endpoint, sc.Name); localDNSEndpoint != "" {
+				t.AppendRow(table.Row{net, "RPC Endpoint (Local DNS)", localDNSEndpoint})
+			}
+		}
 	}
 	ux.Logger.PrintToUser(t.Render())
 
@@ -206,17 +347,39 @@ func PrintSubnetInfo(blockchainName string, onlyLocalnetInfo bool) error {
 			t.AppendRow(table.Row{net, "ICM Registry Address", data.TeleporterRegistryAddress})
 			hasICMInfo = true
 		}
+		versions := maps.Keys(data.ICMMessengers)
+		sort.Strings(versions)
+		for _, version := range versions {
+			messenger := data.ICMMessengers[version]
+			registryInfo := "not registered"
+			if messenger.RegistryVersion != 0 {
+				registryInfo = fmt.Sprintf("registry version %d", messenger.RegistryVersion)
+			}
+			t.AppendRow(table.Row{net, fmt.Sprintf("ICM Messenger %s", version), fmt.Sprintf("%s (%s)", messenger.Address, registryInfo)})
+			hasICMInfo = true
+		}
 	}
 	if hasICMInfo {
 		ux.Logger.PrintToUser("")
 		ux.Logger.PrintToUser(t.Render())
 	}
 
+	// Live validator set
+	for _, deployment := range l1Deployments {
+		if err := printL1ValidatorSet(deployment); err != nil {
+			ux.Logger.PrintToUser("")
+			ux.Logger.RedXToUser("could not get the validator set of %s on %s: %s", sc.Name, deployment.network.Name(), err)
+		}
+	}
+
 	// Token
 	ux.Logger.PrintToUser("")
 	t = ux.DefaultTable("Token", nil)
 	t.AppendRow(table.Row{"Token Name", sc.TokenName})
 	t.AppendRow(table.Row{"Token Symbol", sc.TokenSymbol})
+	if sc.MaxSupply != 0 {
+		t.AppendRow(table.Row{"Max Supply", fmt.Sprintf("%d %s", sc.MaxSupply, sc.TokenSymbol)})
+	}
 	ux.Logger.PrintToUser(t.Render())
 
 	if utils.ByteSliceIsSubnetEvmGenesis(genesisBytes) {
@@ -228,6 +391,7 @@ func PrintSubnetInfo(blockchainName string, onlyLocalnetInfo bool) error {
 			return err
 		}
 		printSmartContracts(sc, genesis)
+		printVestingSchedules(sc, genesis, localEndpoint)
 		printPrecompiles(genesis)
 	}
 
@@ -251,6 +415,11 @@ func PrintSubnetInfo(blockchainName string, onlyLocalnetInfo bool) error {
 			)
 		}
 
+		if localDNSEndpoint := localnet.GetLocalDNSURL(app, localEndpoint, sc.Name); localDNSEndpoint != "" && codespaceEndpoint == "" {
+			// stays valid across redeploys of the local network
+			localEndpoint = localDNSEndpoint
+		}
+
 		// Wallet
 		t = ux.DefaultTable("Wallet Connection", nil)
 		t.AppendRow(table.Row{"Network RPC URL", localEndpoint})
@@ -268,7 +437,7 @@ func PrintSubnetInfo(blockchainName string, onlyLocalnetInfo bool) error {
 func printAllocations(sc models.Sidecar, genesis core.Genesis) error {
 	icmKeyAddress := ""
 	if sc.TeleporterReady {
-		k, err := key.LoadSoft(models.NewLocalNetwork().ID, app.GetKeyPath(sc.TeleporterKey))
+		k, err := app.GetKey(sc.TeleporterKey, app.GetLocalNetwork(), false)
 		if err != nil {
 			return err
 		}
@@ -295,7 +464,7 @@ func printAllocations(sc models.Sidecar, genesis core.Genesis) error {
 			if amount == nil || big.NewInt(0).Cmp(amount) == 0 {
 				continue
 			}
-			formattedAmount := new(big.Int).Div(amount, big.NewInt(params.Ether))
+			formattedAmount := new(big.Int).Div(amount, vm.TokenUnit(sc.GetTokenDecimals()))
 			description := ""
 			privKey := ""
 			switch address.Hex() {
@@ -309,12 +478,14 @@ func printAllocations(sc models.Sidecar, genesis core.Genesis) error {
 				description = logging.Orange.Wrap("Validator Manager Owner")
 			case sc.ProxyContractOwner:
 				description = logging.Orange.Wrap("Proxy Admin Owner")
+			case common.HexToAddress(vm.VestingContractAddress).Hex():
+				description = logging.Orange.Wrap("Vesting Contract (locked)")
 			}
 			var (
 				found bool
 				name  string
 			)
-			found, name, _, privKey, err = contract.SearchForManagedKey(app, models.NewLocalNetwork(), address, true)
+			found, name, _, privKey, err = contract.SearchForManagedKey(app, app.GetLocalNetwork(), address, true)
 			if err != nil {
 				return err
 			}
@@ -359,12 +530,42 @@ func printSmartContracts(sc models.Sidecar, genesis core.Genesis) {
 			deployer = sc.ProxyContractOwner
 		case address == common.HexToAddress(validatorManagerSDK.RewardCalculatorAddress):
 			description = "Reward Calculator"
+		case address == common.HexToAddress(vm.VestingContractAddress):
+			description = "Vesting Contract"
 		}
 		t.AppendRow(table.Row{description, address.Hex(), deployer})
 	}
 	ux.Logger.PrintToUser(t.Render())
 }
 
+func printVestingSchedules(sc models.Sidecar, genesis core.Genesis, rpcURL string) {
+	schedules := vm.GetVestingSchedules(genesis.Alloc)
+	if len(schedules) == 0 {
+		return
+	}
+	ux.Logger.PrintToUser("")
+	t := ux.DefaultTable(
+		"Vesting Schedules",
+		table.Row{"Beneficiary", fmt.Sprintf("Amount (%s)", sc.TokenSymbol), "Start", "Cliff", "End"},
+	)
+	formatTime := func(timestamp uint64) string {
+		return time.Unix(int64(timestamp), 0).UTC().Format(time.RFC3339)
+	}
+	for _, schedule := range schedules {
+		t.AppendRow(table.Row{
+			schedule.Beneficiary.Hex(),
+			utils.FormatAmount(schedule.Amount, sc.GetTokenDecimals()),
+			formatTime(schedule.Start),
+			formatTime(schedule.Cliff),
+			formatTime(schedule.End()),
+		})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	for _, line := range vm.VestingClaimInstructions(rpcURL) {
+		ux.Logger.PrintToUser(line)
+	}
+}
+
 func printPrecompiles(genesis core.Genesis) {
 	ux.Logger.PrintToUser("")
 	t := ux.DefaultTable(
@@ -6,20 +6,43 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/ava-labs/avalanche-cli/pkg/chainregistry"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/signing"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/spf13/cobra"
 )
 
+const cliExportFormat = "cli"
+
+var exportSupportedNetworkOptions = []networkoptions.NetworkOption{
+	networkoptions.Local,
+	networkoptions.Devnet,
+	networkoptions.Fuji,
+	networkoptions.Mainnet,
+}
+
 var (
 	exportOutput        string
 	customVMRepoURL     string
 	customVMBranch      string
 	customVMBuildScript string
+	exportSignKey       string
+	exportFormat        string
+	exportRPCURLs       []string
+	exportExplorerURL   string
+	exportInfoURL       string
+	exportIconURL       string
+	exportShortName     string
 )
 
 // avalanche blockchain export
@@ -30,7 +53,16 @@ func newExportCmd() *cobra.Command {
 		Long: `The blockchain export command write the details of an existing Blockchain deploy to a file.
 
 The command prompts for an output path. You can also provide one with
-the --output flag.`,
+the --output flag.
+
+With --sign-key, the export is also signed with the given minisign secret key,
+writing the signature next to the export file with a .minisig extension.
+
+With --format eip3085 or --format chainlist, the command instead exports the metadata of
+the EVM chain deployed on the selected network, as needed by wallets and chain registries:
+the wallet_addEthereumChain parameter (EIP-3085), to add the L1 to MetaMask with one click,
+or a chainlist compatible entry, to submit it to the ethereum-lists/chains registry. They are
+printed if no output path is given.`,
 		RunE: exportSubnet,
 		Args: cobrautils.ExactArgs(1),
 	}
@@ -45,6 +77,14 @@ the --output flag.`,
 	cmd.Flags().StringVar(&customVMRepoURL, "custom-vm-repo-url", "", "custom vm repository url")
 	cmd.Flags().StringVar(&customVMBranch, "custom-vm-branch", "", "custom vm branch")
 	cmd.Flags().StringVar(&customVMBuildScript, "custom-vm-build-script", "", "custom vm build-script")
+	cmd.Flags().StringVar(&exportSignKey, "sign-key", "", "sign the export with the given minisign secret key file")
+	cmd.Flags().StringVar(&exportFormat, "format", cliExportFormat, fmt.Sprintf("export format (%s, %s, %s)", cliExportFormat, chainregistry.EIP3085Format, chainregistry.ChainlistFormat))
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, exportSupportedNetworkOptions)
+	cmd.Flags().StringSliceVar(&exportRPCURLs, "rpc-url", nil, "public RPC URLs of the chain (default: the known ones) [eip3085/chainlist]")
+	cmd.Flags().StringVar(&exportExplorerURL, "explorer-url", "", "block explorer URL of the chain (default: routescan on fuji/mainnet) [eip3085/chainlist]")
+	cmd.Flags().StringVar(&exportInfoURL, "info-url", "", "URL of the chain website [chainlist]")
+	cmd.Flags().StringVar(&exportIconURL, "icon-url", "", "URL of the chain icon [eip3085]")
+	cmd.Flags().StringVar(&exportShortName, "short-name", "", "short name of the chain (default: derived from its name) [chainlist]")
 	return cmd
 }
 
@@ -54,6 +94,12 @@ func CallExportSubnet(blockchainName, exportPath string) error {
 }
 
 func exportSubnet(_ *cobra.Command, args []string) error {
+	if exportFormat != "" && exportFormat != cliExportFormat {
+		if !utils.Belongs(chainregistry.Formats, exportFormat) {
+			return fmt.Errorf("unsupported format %q, expected one of %s", exportFormat, strings.Join(append([]string{cliExportFormat}, chainregistry.Formats...), ", "))
+		}
+		return exportChainMetadata(args[0], exportFormat)
+	}
 	var err error
 	if exportOutput == "" {
 		pathPrompt := "Enter file path to write export data to"
@@ -170,5 +216,105 @@ func exportSubnet(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(exportOutput, exportBytes, constants.WriteReadReadPerms)
+	if err := os.WriteFile(exportOutput, exportBytes, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	if exportSignKey != "" {
+		return signing.SignArtifact(app, exportBytes, exportOutput, exportSignKey)
+	}
+	return nil
+}
+
+// exportChainMetadata exports the metadata of the EVM chain of [blockchainName] deployed on
+// the selected network, in the chain registry [format]
+func exportChainMetadata(blockchainName string, format string) error {
+	if !app.SidecarExists(blockchainName) {
+		return fmt.Errorf("invalid blockchain %q", blockchainName)
+	}
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return err
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"Which deployment of the chain do you want to export?",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.GetNetworkFromSidecar(sc, exportSupportedNetworkOptions),
+		blockchainName,
+	)
+	if err != nil {
+		return err
+	}
+	networkData, ok := sc.Networks[network.Name()]
+	if !ok || networkData.BlockchainID == ids.Empty {
+		return fmt.Errorf("blockchain %s has not been deployed to %s", blockchainName, network.Name())
+	}
+	genesis, err := app.LoadEvmGenesis(blockchainName)
+	if err != nil {
+		return err
+	}
+	if genesis.Config == nil || genesis.Config.ChainID == nil {
+		return fmt.Errorf("EVM chain ID not found on the genesis of %s", blockchainName)
+	}
+	chainID := genesis.Config.ChainID.Uint64()
+	rpcURLs := exportRPCURLs
+	if len(rpcURLs) == 0 {
+		rpcURLs = networkData.RPCEndpoints
+	}
+	if len(rpcURLs) == 0 {
+		rpcURLs = []string{network.BlockchainEndpoint(networkData.BlockchainID.String())}
+	}
+	explorerURL := exportExplorerURL
+	if explorerURL == "" {
+		if explorer := getDappExplorer(network, chainID, ""); explorer != nil {
+			explorerURL = explorer.BrowserURL
+		}
+	}
+	tokenName := sc.TokenName
+	if tokenName == "" {
+		tokenName = sc.TokenSymbol + " Token"
+	}
+	chain := chainregistry.Chain{
+		Name:    blockchainName,
+		ChainID: chainID,
+		NativeCurrency: chainregistry.NativeCurrency{
+			Name:     tokenName,
+			Symbol:   sc.TokenSymbol,
+			Decimals: sc.GetTokenDecimals(),
+		},
+		RPCURLs:     utils.Unique(rpcURLs),
+		ExplorerURL: explorerURL,
+		InfoURL:     exportInfoURL,
+		IconURL:     exportIconURL,
+		ShortName:   exportShortName,
+	}
+	out, err := chain.Export(format)
+	if err != nil {
+		return err
+	}
+	exportBytes, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	if exportOutput == "" {
+		// warnings go to stderr, so the printed metadata can be piped
+		for _, issue := range chain.Validate(format) {
+			fmt.Fprintln(os.Stderr, logging.Yellow.Wrap("Warning: "+issue))
+		}
+		fmt.Println(string(exportBytes))
+		return nil
+	}
+	for _, issue := range chain.Validate(format) {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Warning: %s"), issue)
+	}
+	if err := os.WriteFile(exportOutput, exportBytes, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("%s metadata of %s on %s written to %s", format, blockchainName, network.Name(), exportOutput)
+	if exportSignKey != "" {
+		return signing.SignArtifact(app, exportBytes, exportOutput, exportSignKey)
+	}
+	return nil
 }
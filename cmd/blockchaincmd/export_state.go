@@ -0,0 +1,140 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+type ExportStateFlags struct {
+	Network     networkoptions.NetworkFlags
+	rpcEndpoint string
+	height      uint64
+	output      string
+}
+
+var (
+	exportStateSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Fuji,
+	}
+	exportStateFlags ExportStateFlags
+)
+
+// avalanche blockchain export-state
+func newExportStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-state [blockchainName]",
+		Short: "Export the EVM state of a deployed blockchain",
+		Long: `The blockchain export-state command writes all the accounts, balances, contract code
+and contract storage of an EVM Blockchain at the given height to a file. By default the
+state of the last accepted block is exported.
+
+The exported state can seed a new deployment of a blockchain with blockchain deploy
+--from-state, that adds it to the genesis allocations. This way a long lived devnet
+state can be promoted to a fresh Fuji deployment.
+
+The blockchain must have the debug APIs enabled (blockchain create --debug), and must
+record state preimages ("preimages-enabled": true on its chain config, set by default
+with --debug). Heights older than the pruning window of the node are not available.`,
+		RunE: exportState,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &exportStateFlags.Network, true, exportStateSupportedNetworkOptions)
+	cmd.Flags().StringVar(&exportStateFlags.rpcEndpoint, "rpc", "", "export the state from the given rpc endpoint")
+	cmd.Flags().Uint64Var(&exportStateFlags.height, "height", 0, "export the state at the given block height (default last accepted block)")
+	cmd.Flags().StringVarP(&exportStateFlags.output, "output", "o", "", "write the state to the provided file path")
+	return cmd
+}
+
+func exportState(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("state export is only supported on %s blockchains", models.SubnetEvm)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		exportStateFlags.Network,
+		true,
+		false,
+		exportStateSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	if exportStateFlags.output == "" {
+		exportStateFlags.output, err = app.Prompt.CaptureString("Enter file path to write the state to")
+		if err != nil {
+			return err
+		}
+	}
+	if exportStateFlags.rpcEndpoint == "" {
+		exportStateFlags.rpcEndpoint, _, err = contract.GetBlockchainEndpoints(
+			app,
+			network,
+			contract.ChainSpec{
+				BlockchainName: blockchainName,
+			},
+			true,
+			false,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), exportStateFlags.rpcEndpoint)
+	state, err := evm.ExportState(exportStateFlags.rpcEndpoint, exportStateFlags.height)
+	if err != nil {
+		return err
+	}
+	bs, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(exportStateFlags.output, bs, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser(
+		"Exported %d accounts of %s at height %d to %s",
+		len(state.Accounts),
+		blockchainName,
+		state.Height,
+		exportStateFlags.output,
+	)
+	return nil
+}
+
+// loadStateExport reads a state written by blockchain export-state
+func loadStateExport(path string) (*evm.StateExport, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	state := evm.StateExport{}
+	if err := json.Unmarshal(bs, &state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	return &state, nil
+}
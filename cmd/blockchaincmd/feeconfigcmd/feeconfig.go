@@ -0,0 +1,26 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package feeconfigcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche blockchain feeconfig
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "feeconfig",
+		Short: "Manage the fee config of running Blockchains",
+		Long: `The blockchain feeconfig command suite provides tools to review and adjust
+the dynamic fee parameters of deployed Subnet-EVM Blockchains.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	app = injectedApp
+	// blockchain feeconfig tune
+	cmd.AddCommand(newTuneCmd())
+	return cmd
+}
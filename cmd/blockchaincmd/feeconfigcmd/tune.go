@@ -0,0 +1,270 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package feeconfigcmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/precompiles"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+type TuneFlags struct {
+	Network         networkoptions.NetworkFlags
+	PrivateKeyFlags contract.PrivateKeyFlags
+	rpcEndpoint     string
+	numBlocks       uint64
+	apply           bool
+	// allow mainnet operations with stored keys not tagged as mainnet-approved
+	allowUnapprovedKey bool
+}
+
+var (
+	tuneSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Fuji,
+		networkoptions.Mainnet,
+	}
+	tuneFlags TuneFlags
+)
+
+const defaultNumBlocksToSample = 300
+
+// avalanche blockchain feeconfig tune
+func newTuneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tune [blockchainName]",
+		Short: "Recommend fee config adjustments based on recent block utilization",
+		Long: `The blockchain feeconfig tune command samples the recent blocks of a running
+Subnet-EVM Blockchain, and recommends target gas and base fee change denominator
+adjustments for the observed load, showing the predicted impact on the base fee.
+
+The prediction replays the sampled gas usage under the current and recommended
+fee configs, so it does not account for changes in user behavior.
+
+With --apply, the recommended fee config is set using the FeeManager precompile,
+which needs to be enabled on the Blockchain, with a key allowed to use it.`,
+		RunE: tune,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &tuneFlags.Network, true, tuneSupportedNetworkOptions)
+	tuneFlags.PrivateKeyFlags.AddToCmd(cmd, "to apply the fee config")
+	cmd.Flags().StringVar(&tuneFlags.rpcEndpoint, "rpc", "", "use the given rpc endpoint")
+	cmd.Flags().Uint64Var(&tuneFlags.numBlocks, "blocks", defaultNumBlocksToSample, "number of recent blocks to sample")
+	cmd.Flags().BoolVar(&tuneFlags.apply, "apply", false, "apply the recommended fee config using the FeeManager precompile")
+	keychain.AddAllowUnapprovedKeyFlag(cmd, &tuneFlags.allowUnapprovedKey)
+	return cmd
+}
+
+func tune(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("fee config tuning is only supported for Subnet-EVM blockchains")
+	}
+	if tuneFlags.numBlocks < 2 {
+		return fmt.Errorf("at least 2 blocks are needed to sample utilization")
+	}
+	chainSpec := contract.ChainSpec{
+		BlockchainName: blockchainName,
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		tuneFlags.Network,
+		true,
+		false,
+		tuneSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	if tuneFlags.rpcEndpoint == "" {
+		tuneFlags.rpcEndpoint, _, err = contract.GetBlockchainEndpoints(
+			app,
+			network,
+			chainSpec,
+			true,
+			false,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), tuneFlags.rpcEndpoint)
+
+	client, err := evm.GetClient(tuneFlags.rpcEndpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	ux.Logger.PrintToUser("Sampling the last %d blocks...", tuneFlags.numBlocks)
+	var (
+		feeConfig commontype.FeeConfig
+		headers   []*types.Header
+	)
+	eg := &errgroup.Group{}
+	eg.Go(func() error {
+		var err error
+		feeConfig, err = evm.GetFeeConfig(client)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		headers, err = evm.GetLastHeaders(client, tuneFlags.numBlocks)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	samples := []vm.BlockFeeSample{}
+	for _, header := range headers {
+		samples = append(samples, vm.BlockFeeSample{
+			Number:  header.Number.Uint64(),
+			Time:    header.Time,
+			GasUsed: header.GasUsed,
+			BaseFee: header.BaseFee,
+		})
+	}
+	if len(samples) < 2 {
+		return fmt.Errorf("not enough blocks on %s to sample utilization", blockchainName)
+	}
+
+	usage := vm.GetFeeUsage(samples, feeConfig)
+	recommendation := vm.RecommendFeeConfig(feeConfig, usage)
+	printUsage(usage, feeConfig)
+	if len(recommendation.Reasons) == 0 {
+		ux.Logger.GreenCheckmarkToUser("Current fee config fits the observed utilization. No changes recommended")
+		return nil
+	}
+	printRecommendation(samples, feeConfig, recommendation)
+	if feeConfigEquals(feeConfig, recommendation.FeeConfig) {
+		return nil
+	}
+	if !tuneFlags.apply {
+		ux.Logger.PrintToUser("Use --apply to set the recommended fee config using the FeeManager precompile")
+		return nil
+	}
+	return applyFeeConfig(network, chainSpec, recommendation.FeeConfig)
+}
+
+func applyFeeConfig(network models.Network, chainSpec contract.ChainSpec, feeConfig commontype.FeeConfig) error {
+	genesisAddress, genesisPrivateKey, err := contract.GetEVMSubnetPrefundedKey(
+		app,
+		network,
+		chainSpec,
+	)
+	if err != nil {
+		return err
+	}
+	privateKey, err := tuneFlags.PrivateKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	if err != nil {
+		return err
+	}
+	if privateKey == "" {
+		privateKey, err = prompts.PromptPrivateKey(
+			app.Prompt,
+			"set the fee config",
+			app.GetKeyDir(),
+			app.GetKey,
+			genesisAddress,
+			genesisPrivateKey,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if err := keychain.CheckPrivateKeyOnNetwork(app, network, privateKey, tuneFlags.allowUnapprovedKey); err != nil {
+		return err
+	}
+	address, err := utils.PrivateKeyToAddress(privateKey)
+	if err != nil {
+		return err
+	}
+	role, err := precompiles.ReadAllowList(tuneFlags.rpcEndpoint, precompiles.FeeManagerPrecompile, address)
+	if err != nil {
+		return fmt.Errorf("failure reading FeeManager allow list, is the precompile enabled on the blockchain?: %w", err)
+	}
+	if role.Sign() == 0 {
+		return fmt.Errorf("address %s is not allowed to use the FeeManager precompile", address.Hex())
+	}
+	if err := precompiles.SetFeeConfig(tuneFlags.rpcEndpoint, privateKey, feeConfig); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Fee config successfully updated")
+	return nil
+}
+
+func feeConfigEquals(a commontype.FeeConfig, b commontype.FeeConfig) bool {
+	return a.TargetGas.Cmp(b.TargetGas) == 0 &&
+		a.BaseFeeChangeDenominator.Cmp(b.BaseFeeChangeDenominator) == 0
+}
+
+func formatGwei(n *big.Int) string {
+	if n == nil {
+		return "-"
+	}
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(n), big.NewFloat(1e9)).Float64()
+	return fmt.Sprintf("%.2f gwei", gwei)
+}
+
+func printUsage(usage vm.FeeUsage, feeConfig commontype.FeeConfig) {
+	ux.Logger.PrintToUser("")
+	t := ux.DefaultTable("Observed Utilization", nil)
+	t.AppendRow(table.Row{"Sampled Blocks", usage.NumBlocks})
+	t.AppendRow(table.Row{"Sampled Period", fmt.Sprintf("%ds", usage.Period)})
+	t.AppendRow(table.Row{"Avg Block Time", fmt.Sprintf("%.2fs (target %ds)", usage.AvgBlockTime, feeConfig.TargetBlockRate)})
+	t.AppendRow(table.Row{"Avg Block Utilization", fmt.Sprintf("%.1f%% of gas limit %d", usage.AvgBlockUtilization*100, feeConfig.GasLimit)})
+	t.AppendRow(table.Row{"Full Blocks", usage.FullBlocks})
+	t.AppendRow(table.Row{"Avg Gas per 10s Window", fmt.Sprintf("%d (target %d)", usage.AvgWindowGas, feeConfig.TargetGas)})
+	t.AppendRow(table.Row{"P90 Gas per 10s Window", usage.PeakWindowGas})
+	t.AppendRow(table.Row{"Base Fee (min/avg/max)", fmt.Sprintf(
+		"%s / %s / %s",
+		formatGwei(usage.BaseFees.Min),
+		formatGwei(usage.BaseFees.Avg),
+		formatGwei(usage.BaseFees.Max),
+	)})
+	t.AppendRow(table.Row{"Max Base Fee Change per Block", fmt.Sprintf("%.2f%%", usage.MaxBaseFeeChange*100)})
+	ux.Logger.PrintToUser(t.Render())
+}
+
+func printRecommendation(samples []vm.BlockFeeSample, feeConfig commontype.FeeConfig, recommendation vm.FeeRecommendation) {
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Recommendations:")
+	for _, reason := range recommendation.Reasons {
+		ux.Logger.PrintToUser("  - %s", reason)
+	}
+	currentBaseFees := vm.GetBaseFeeStats(vm.SimulateBaseFees(samples, feeConfig))
+	recommendedBaseFees := vm.GetBaseFeeStats(vm.SimulateBaseFees(samples, recommendation.FeeConfig))
+	ux.Logger.PrintToUser("")
+	t := ux.DefaultTable("Fee Config", table.Row{"Parameter", "Current", "Recommended"})
+	t.AppendRow(table.Row{"Target Gas", feeConfig.TargetGas, recommendation.FeeConfig.TargetGas})
+	t.AppendRow(table.Row{"Base Fee Change Denominator", feeConfig.BaseFeeChangeDenominator, recommendation.FeeConfig.BaseFeeChangeDenominator})
+	t.AppendRow(table.Row{"Predicted Avg Base Fee", formatGwei(currentBaseFees.Avg), formatGwei(recommendedBaseFees.Avg)})
+	t.AppendRow(table.Row{"Predicted Max Base Fee", formatGwei(currentBaseFees.Max), formatGwei(recommendedBaseFees.Max)})
+	ux.Logger.PrintToUser(t.Render())
+}
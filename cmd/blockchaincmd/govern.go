@@ -0,0 +1,557 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/precompiles"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+type GovernFlags struct {
+	PrivateKeyFlags contract.PrivateKeyFlags
+	rpcEndpoint     string
+	roles           []string
+	mints           []string
+	feeConfigPath   string
+	safeAddress     string
+	safeExportPath  string
+	dryRun          bool
+	yes             bool
+	// allow mainnet operations with stored keys not tagged as mainnet-approved
+	allowUnapprovedKey bool
+}
+
+var (
+	governSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Fuji,
+		networkoptions.Mainnet,
+	}
+	governFlags GovernFlags
+)
+
+// avalanche blockchain govern
+func newGovernCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "govern [blockchainName]",
+		Short: "Prepare and execute precompile admin transactions as a batch",
+		Long: fmt.Sprintf(`The blockchain govern command prepares a batch of admin transactions for the
+allow list precompiles of a running Subnet-EVM Blockchain: granting or revoking
+roles (eg enabling new minters, changing fee managers), minting native tokens,
+and setting a new fee config.
+
+Actions are given with --role precompile:role:address, --mint address=amount and
+--fee-config, or interactively if none is given. Precompiles: %s.
+Roles: admin, manager, enabled, none.
+
+Before executing, the command shows the effect of each action (role, balance and
+fee config changes) and checks that the signer holds the allow list role each
+action needs, taking into account the role changes done earlier in the batch.
+
+If the precompiles are administered by a Gnosis Safe, use --safe together with
+--safe-export to check the batch against the Safe roles, and write it as a Safe
+Transaction Builder file, to be imported and proposed on the Safe{Wallet} app.`, strings.Join(precompiles.AllowListPrecompileNames(), ", ")),
+		RunE: govern,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, governSupportedNetworkOptions)
+	governFlags.PrivateKeyFlags.AddToCmd(cmd, "to sign the admin transactions")
+	cmd.Flags().StringVar(&governFlags.rpcEndpoint, "rpc", "", "use the given rpc endpoint")
+	cmd.Flags().StringSliceVar(&governFlags.roles, "role", nil, "set the allow list role of an address, as precompile:role:address. Can be repeated")
+	cmd.Flags().StringSliceVar(&governFlags.mints, "mint", nil, "mint native tokens with the native minter, as address=amount (in token units). Can be repeated")
+	cmd.Flags().StringVar(&governFlags.feeConfigPath, "fee-config", "", "set the fee config on the given JSON file with the fee manager. Missing fields keep their current value")
+	cmd.Flags().StringVar(&governFlags.safeAddress, "safe", "", "address of the Gnosis Safe administering the precompiles")
+	cmd.Flags().StringVar(&governFlags.safeExportPath, "safe-export", "", "write the batch as a Safe Transaction Builder file instead of executing it")
+	cmd.Flags().BoolVar(&governFlags.dryRun, "dry-run", false, "only show the effects of the batch")
+	cmd.Flags().BoolVarP(&governFlags.yes, "yes", "y", false, "execute the batch without asking for confirmation")
+	keychain.AddAllowUnapprovedKeyFlag(cmd, &governFlags.allowUnapprovedKey)
+	return cmd
+}
+
+func govern(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("precompile governance is only supported for Subnet-EVM blockchains")
+	}
+	if (governFlags.safeAddress == "") != (governFlags.safeExportPath == "") {
+		return errors.New("--safe and --safe-export must be given together")
+	}
+	if governFlags.safeAddress != "" && !common.IsHexAddress(governFlags.safeAddress) {
+		return fmt.Errorf("invalid Safe address %q", governFlags.safeAddress)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.GetNetworkFromSidecar(sc, governSupportedNetworkOptions),
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	chainSpec := contract.ChainSpec{
+		BlockchainName: blockchainName,
+	}
+	if governFlags.rpcEndpoint == "" {
+		governFlags.rpcEndpoint, _, err = contract.GetBlockchainEndpoints(
+			app,
+			network,
+			chainSpec,
+			true,
+			false,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), governFlags.rpcEndpoint)
+
+	client, err := evm.GetClient(governFlags.rpcEndpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	decimals := sc.GetTokenDecimals()
+	actions, err := getGovernanceActions(client, decimals)
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		ux.Logger.PrintToUser("No actions to execute")
+		return nil
+	}
+
+	var (
+		executor   common.Address
+		privateKey string
+	)
+	if governFlags.safeAddress != "" {
+		executor = common.HexToAddress(governFlags.safeAddress)
+	} else {
+		privateKey, err = getGovernancePrivateKey(network, chainSpec)
+		if err != nil {
+			return err
+		}
+		executor, err = utils.PrivateKeyToAddress(privateKey)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("Executor: %s", executor.Hex())
+	effects, err := getGovernanceEffects(client, executor, actions, decimals)
+	if err != nil {
+		return err
+	}
+	printGovernanceEffects(actions, effects, sc.TokenSymbol, decimals)
+	failed := false
+	for _, effect := range effects {
+		if effect.err != nil {
+			failed = true
+		}
+	}
+	if failed {
+		return fmt.Errorf("%s can't execute the batch", executor.Hex())
+	}
+	if governFlags.dryRun {
+		return nil
+	}
+
+	if governFlags.safeExportPath != "" {
+		chainID, err := evm.GetChainID(client)
+		if err != nil {
+			return err
+		}
+		bundle, err := precompiles.NewSafeTxBundle(
+			chainID,
+			executor,
+			fmt.Sprintf("%s precompile governance", blockchainName),
+			actions,
+			time.Now(),
+		)
+		if err != nil {
+			return err
+		}
+		bundleBytes, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(governFlags.safeExportPath, bundleBytes, constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+		ux.Logger.GreenCheckmarkToUser("Safe transaction batch written to %s", governFlags.safeExportPath)
+		ux.Logger.PrintToUser("Import it on the Safe{Wallet} Transaction Builder app of Safe %s to propose it to the owners", executor.Hex())
+		return nil
+	}
+
+	if !governFlags.yes {
+		yes, err := app.Prompt.CaptureYesNo(fmt.Sprintf("Execute the %d admin transactions?", len(actions)))
+		if err != nil {
+			return err
+		}
+		if !yes {
+			return nil
+		}
+	}
+	for i, action := range actions {
+		methodSpec, params := action.MethodSpec()
+		tx, _, err := contract.TxToMethod(
+			governFlags.rpcEndpoint,
+			privateKey,
+			action.Precompile,
+			nil,
+			action.Description(),
+			nil,
+			methodSpec,
+			params...,
+		)
+		if err != nil {
+			if i > 0 {
+				ux.Logger.PrintToUser("The first %d actions of the batch were already executed", i)
+			}
+			return fmt.Errorf("failure to %s: %w", action.Description(), err)
+		}
+		ux.Logger.GreenCheckmarkToUser("%s (tx %s)", action.Description(), tx.Hash().Hex())
+	}
+	return nil
+}
+
+// getGovernanceActions returns the actions given by flag, or prompts for them if none
+// is given. Mint amounts are given in token units of [decimals]
+func getGovernanceActions(client ethclient.Client, decimals uint8) ([]precompiles.Action, error) {
+	actions := []precompiles.Action{}
+	for _, role := range governFlags.roles {
+		action, err := parseRoleAction(role)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	for _, mint := range governFlags.mints {
+		action, err := parseMintAction(mint, decimals)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	if governFlags.feeConfigPath != "" {
+		action, err := loadFeeConfigAction(client, governFlags.feeConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	if len(actions) > 0 {
+		return actions, nil
+	}
+	return promptGovernanceActions(client, decimals)
+}
+
+// parseRoleAction parses a --role flag, given as precompile:role:address
+func parseRoleAction(role string) (precompiles.Action, error) {
+	parts := strings.Split(role, ":")
+	if len(parts) != 3 {
+		return precompiles.Action{}, fmt.Errorf("invalid role %q: expected precompile:role:address", role)
+	}
+	precompile, err := precompiles.GetAllowListPrecompile(parts[0])
+	if err != nil {
+		return precompiles.Action{}, err
+	}
+	if !common.IsHexAddress(parts[2]) {
+		return precompiles.Action{}, fmt.Errorf("invalid address %q on role %q", parts[2], role)
+	}
+	return precompiles.NewRoleAction(precompile, parts[1], common.HexToAddress(parts[2]))
+}
+
+// parseMintAction parses a --mint flag, given as address=amount
+func parseMintAction(mint string, decimals uint8) (precompiles.Action, error) {
+	addressStr, amountStr, found := strings.Cut(mint, "=")
+	if !found || !common.IsHexAddress(addressStr) {
+		return precompiles.Action{}, fmt.Errorf("invalid mint %q: expected address=amount", mint)
+	}
+	amount, err := strconv.ParseUint(amountStr, 10, 64)
+	if err != nil || amount == 0 {
+		return precompiles.Action{}, fmt.Errorf("invalid mint amount %q: expected a positive amount of tokens", amountStr)
+	}
+	return precompiles.NewMintAction(
+		common.HexToAddress(addressStr),
+		vm.TokensToBaseUnits(amount, decimals),
+	), nil
+}
+
+// loadFeeConfigAction returns the action setting the fee config on [path], on top of
+// the current one
+func loadFeeConfigAction(client ethclient.Client, path string) (precompiles.Action, error) {
+	feeConfig, err := evm.GetFeeConfig(client)
+	if err != nil {
+		return precompiles.Action{}, err
+	}
+	feeConfigBytes, err := os.ReadFile(path)
+	if err != nil {
+		return precompiles.Action{}, err
+	}
+	if err := json.Unmarshal(feeConfigBytes, &feeConfig); err != nil {
+		return precompiles.Action{}, fmt.Errorf("invalid fee config file %s: %w", path, err)
+	}
+	if err := feeConfig.Verify(); err != nil {
+		return precompiles.Action{}, fmt.Errorf("invalid fee config on %s: %w", path, err)
+	}
+	return precompiles.NewFeeConfigAction(feeConfig), nil
+}
+
+func promptGovernanceActions(client ethclient.Client, decimals uint8) ([]precompiles.Action, error) {
+	const (
+		roleOption      = "Change an allow list role"
+		mintOption      = "Mint native tokens"
+		feeConfigOption = "Set the fee config from a file"
+		doneOption      = "Done"
+	)
+	actions := []precompiles.Action{}
+	for {
+		option, err := app.Prompt.CaptureList(
+			"Add an action to the batch",
+			[]string{roleOption, mintOption, feeConfigOption, doneOption},
+		)
+		if err != nil {
+			return nil, err
+		}
+		switch option {
+		case roleOption:
+			precompileName, err := app.Prompt.CaptureList("Which precompile?", precompiles.AllowListPrecompileNames())
+			if err != nil {
+				return nil, err
+			}
+			precompile, err := precompiles.GetAllowListPrecompile(precompileName)
+			if err != nil {
+				return nil, err
+			}
+			role, err := app.Prompt.CaptureList("Which role?", []string{"admin", "manager", "enabled", "none"})
+			if err != nil {
+				return nil, err
+			}
+			address, err := app.Prompt.CaptureAddress(fmt.Sprintf("Address to set as %s", role))
+			if err != nil {
+				return nil, err
+			}
+			action, err := precompiles.NewRoleAction(precompile, role, address)
+			if err != nil {
+				return nil, err
+			}
+			actions = append(actions, action)
+		case mintOption:
+			address, err := app.Prompt.CaptureAddress("Address to mint to")
+			if err != nil {
+				return nil, err
+			}
+			amount, err := app.Prompt.CaptureUint64("Amount of tokens to mint")
+			if err != nil {
+				return nil, err
+			}
+			actions = append(actions, precompiles.NewMintAction(
+				address,
+				vm.TokensToBaseUnits(amount, decimals),
+			))
+		case feeConfigOption:
+			path, err := app.Prompt.CaptureExistingFilepath("Fee config JSON file")
+			if err != nil {
+				return nil, err
+			}
+			action, err := loadFeeConfigAction(client, path)
+			if err != nil {
+				return nil, err
+			}
+			actions = append(actions, action)
+		case doneOption:
+			return actions, nil
+		}
+	}
+}
+
+func getGovernancePrivateKey(network models.Network, chainSpec contract.ChainSpec) (string, error) {
+	genesisAddress, genesisPrivateKey, err := contract.GetEVMSubnetPrefundedKey(
+		app,
+		network,
+		chainSpec,
+	)
+	if err != nil {
+		return "", err
+	}
+	privateKey, err := governFlags.PrivateKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	if err != nil {
+		return "", err
+	}
+	if privateKey == "" {
+		privateKey, err = prompts.PromptPrivateKey(
+			app.Prompt,
+			"sign the admin transactions",
+			app.GetKeyDir(),
+			app.GetKey,
+			genesisAddress,
+			genesisPrivateKey,
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := keychain.CheckPrivateKeyOnNetwork(app, network, privateKey, governFlags.allowUnapprovedKey); err != nil {
+		return "", err
+	}
+	return privateKey, nil
+}
+
+type governanceEffect struct {
+	description string
+	err         error
+}
+
+// getGovernanceEffects returns the effect of each action of the batch when executed
+// by [executor], in order, checking that the executor holds the needed roles
+func getGovernanceEffects(
+	client ethclient.Client,
+	executor common.Address,
+	actions []precompiles.Action,
+	decimals uint8,
+) ([]governanceEffect, error) {
+	type roleKey struct {
+		precompile common.Address
+		address    common.Address
+	}
+	roles := map[roleKey]allowlist.Role{}
+	getRole := func(precompile common.Address, address common.Address) (allowlist.Role, error) {
+		key := roleKey{precompile, address}
+		if role, ok := roles[key]; ok {
+			return role, nil
+		}
+		role, err := precompiles.ReadRole(governFlags.rpcEndpoint, precompile, address)
+		if err != nil {
+			return allowlist.NoRole, fmt.Errorf("failure reading %s allow list, is the precompile enabled on the blockchain?: %w", precompiles.GetPrecompileName(precompile), err)
+		}
+		roles[key] = role
+		return role, nil
+	}
+	balances := map[common.Address]*big.Int{}
+	effects := make([]governanceEffect, 0, len(actions))
+	for _, action := range actions {
+		executorRole, err := getRole(action.Precompile, executor)
+		if err != nil {
+			return nil, err
+		}
+		var effect governanceEffect
+		switch action.Kind {
+		case precompiles.MintAction:
+			balance, ok := balances[action.Address]
+			if !ok {
+				balance, err = evm.GetAddressBalance(client, action.Address.Hex())
+				if err != nil {
+					return nil, err
+				}
+			}
+			newBalance := new(big.Int).Add(balance, action.Amount)
+			balances[action.Address] = newBalance
+			effect.description = fmt.Sprintf("balance %s -> %s", utils.FormatAmount(balance, decimals), utils.FormatAmount(newBalance, decimals))
+			effect.err = action.CheckSignerRole(executorRole, allowlist.NoRole)
+		case precompiles.SetFeeConfigAction:
+			feeConfig, err := evm.GetFeeConfig(client)
+			if err != nil {
+				return nil, err
+			}
+			changes := getFeeConfigChanges(feeConfig, *action.FeeConfig)
+			effect.description = "no changes"
+			if len(changes) > 0 {
+				effect.description = strings.Join(changes, "\n")
+			}
+			effect.err = action.CheckSignerRole(executorRole, allowlist.NoRole)
+		default:
+			targetRole, err := getRole(action.Precompile, action.Address)
+			if err != nil {
+				return nil, err
+			}
+			newRole, _ := action.NewRole()
+			effect.description = fmt.Sprintf("role %s -> %s", targetRole, newRole)
+			effect.err = action.CheckSignerRole(executorRole, targetRole)
+			if effect.err == nil {
+				roles[roleKey{action.Precompile, action.Address}] = newRole
+			}
+		}
+		effects = append(effects, effect)
+	}
+	return effects, nil
+}
+
+// getFeeConfigChanges lists the fields changed from [current] to [next]
+func getFeeConfigChanges(current commontype.FeeConfig, next commontype.FeeConfig) []string {
+	fields := []struct {
+		name    string
+		current *big.Int
+		next    *big.Int
+	}{
+		{"gasLimit", current.GasLimit, next.GasLimit},
+		{"targetBlockRate", new(big.Int).SetUint64(current.TargetBlockRate), new(big.Int).SetUint64(next.TargetBlockRate)},
+		{"minBaseFee", current.MinBaseFee, next.MinBaseFee},
+		{"targetGas", current.TargetGas, next.TargetGas},
+		{"baseFeeChangeDenominator", current.BaseFeeChangeDenominator, next.BaseFeeChangeDenominator},
+		{"minBlockGasCost", current.MinBlockGasCost, next.MinBlockGasCost},
+		{"maxBlockGasCost", current.MaxBlockGasCost, next.MaxBlockGasCost},
+		{"blockGasCostStep", current.BlockGasCostStep, next.BlockGasCostStep},
+	}
+	changes := []string{}
+	for _, field := range fields {
+		if field.current.Cmp(field.next) != 0 {
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", field.name, field.current, field.next))
+		}
+	}
+	return changes
+}
+
+func printGovernanceEffects(actions []precompiles.Action, effects []governanceEffect, tokenSymbol string, decimals uint8) {
+	ux.Logger.PrintToUser("")
+	t := ux.DefaultTable("Governance Batch", table.Row{"#", "Action", "Effect", "Executable"})
+	for i, action := range actions {
+		description := action.Description()
+		if action.Kind == precompiles.MintAction {
+			description = fmt.Sprintf("mint %s %s to %s", utils.FormatAmount(action.Amount, decimals), tokenSymbol, action.Address.Hex())
+		}
+		executable := logging.Green.Wrap("yes")
+		if effects[i].err != nil {
+			executable = logging.Red.Wrap(effects[i].err.Error())
+		}
+		t.AppendRow(table.Row{i + 1, description, effects[i].description, executable})
+	}
+	ux.Logger.PrintToUser(t.Render())
+}
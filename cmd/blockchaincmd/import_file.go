@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/signing"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/spf13/cobra"
@@ -25,6 +26,7 @@ var (
 	repoOrURL       string
 	subnetAlias     string
 	branch          string
+	importSignature string
 )
 
 // avalanche blockchain import file
@@ -40,7 +42,11 @@ To import from a file, you can optionally provide the path as a command-line arg
 Alternatively, running the command without any arguments triggers an interactive wizard.
 To import from a repository, go through the wizard. By default, an imported Blockchain doesn't 
 overwrite an existing Blockchain with the same name. To allow overwrites, provide the --force
-flag.`,
+flag.
+
+If a signature is found next to the import file (with a .minisig extension), or is given
+with --signature, it is verified against the trusted keys of the CLI config. Unsigned files
+are rejected if signatures are required (see avalanche config signing).`,
 	}
 	cmd.Flags().BoolVarP(
 		&overwriteImport,
@@ -67,6 +73,12 @@ flag.`,
 		"",
 		"the blockchain configuration to import from the provided repo",
 	)
+	cmd.Flags().StringVar(
+		&importSignature,
+		"signature",
+		"",
+		"minisign signature of the import file (defaults to the import file path with a .minisig extension)",
+	)
 	return cmd
 }
 
@@ -110,11 +122,27 @@ func importFromFile(importPath string) error {
 		return err
 	}
 
+	if importSignature == "" {
+		importSignature = importPath + signing.SignatureExtension
+	}
+	signingKey, err := signing.VerifyArtifact(app, importFileBytes, importSignature)
+	if err != nil {
+		return err
+	}
+
 	importable := models.Exportable{}
 	err = json.Unmarshal(importFileBytes, &importable)
 	if err != nil {
 		return err
 	}
+	// only record signatures verified here
+	importable.Sidecar.ImportSignature = models.ImportSignature{}
+	if signingKey != nil {
+		importable.Sidecar.ImportSignature = models.ImportSignature{
+			KeyID:       signingKey.ID.String(),
+			GenesisHash: signing.GenesisHash(importable.Genesis),
+		}
+	}
 
 	blockchainName := importable.Sidecar.Name
 	if blockchainName == "" {
@@ -150,6 +178,12 @@ func importFromFile(importPath string) error {
 		}
 	}
 
+	if importable.Sidecar.VM == models.SubnetEvm {
+		if err := vm.SetSubnetEVMVersionConstraint(&importable.Sidecar, importable.Genesis); err != nil {
+			return err
+		}
+	}
+
 	if err := app.WriteGenesisFile(blockchainName, importable.Genesis); err != nil {
 		return err
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ava-labs/coreth/core"
 	"github.com/spf13/cobra"
@@ -203,6 +204,15 @@ func importPublic(*cobra.Command, []string) error {
 			return err
 		}
 		sc.ChainID = genesis.Config.ChainID.String()
+		// the VM version is the one running on the network, so a mismatch is only reported
+		constraint, err := vm.GetSubnetEVMVersionConstraint(genBytes)
+		if err != nil {
+			return err
+		}
+		if err := vm.CheckSubnetEVMVersion(constraint, sc.VMVersion); err != nil {
+			ux.Logger.PrintToUser(logging.Yellow.Wrap("Warning: %s"), err)
+		}
+		sc.VMVersionConstraint = constraint
 	}
 
 	if err := app.CreateSidecar(sc); err != nil {
@@ -0,0 +1,249 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/dapp"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+var (
+	dappFramework       string
+	dappProjectDir      string
+	dappKeyName         string
+	dappKeystoreAccount string
+	dappVerifierURL     string
+)
+
+// avalanche blockchain init-dapp
+func newInitDappCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init-dapp [blockchainName]",
+		Short: "Wire a Foundry or Hardhat project to the blockchain deployments",
+		Long: `The blockchain init-dapp command writes the configuration a Foundry or Hardhat project needs to
+deploy contracts to the blockchain, for every network the blockchain is deployed on: the RPC URL,
+the EVM chain ID, the deployer key, and the explorer API to verify contracts with.
+
+Each network is named <blockchainName>_<network> on the project. For Foundry projects the
+command sets the rpc_endpoints and etherscan entries of foundry.toml. For Hardhat projects it
+writes avalanche.networks.json, to be imported from hardhat.config as shown by the command.
+
+Deployer keys are written to the project .env file, that is added to .gitignore. Use --key to
+write a CLI stored key, or --keystore to reference a Foundry keystore account. Local network
+deployments use the key funded on genesis by default.
+
+The project is remembered, and its configuration is updated after each blockchain deploy.`,
+		RunE: initDapp,
+		Args: cobrautils.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&dappFramework, "framework", "", "framework of the project, foundry or hardhat (default: detected from the project files)")
+	cmd.Flags().StringVar(&dappProjectDir, "project-dir", ".", "directory of the dapp project")
+	cmd.Flags().StringVar(&dappKeyName, "key", "", "write the given CLI key as deployer key")
+	cmd.Flags().StringVar(&dappKeystoreAccount, "keystore", "", "use the given Foundry keystore account as deployer")
+	cmd.Flags().StringVar(&dappVerifierURL, "verifier-url", "", "etherscan compatible API to verify contracts with (default: Routescan on Fuji and Mainnet)")
+	return cmd
+}
+
+func initDapp(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	if dappKeyName != "" && dappKeystoreAccount != "" {
+		return fmt.Errorf("--key and --keystore are mutually exclusive")
+	}
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return err
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("init-dapp only supports Subnet-EVM blockchains")
+	}
+	projectDir, err := filepath.Abs(utils.ExpandHome(dappProjectDir))
+	if err != nil {
+		return err
+	}
+	framework := dapp.Framework(strings.ToLower(dappFramework))
+	switch framework {
+	case "":
+		framework, err = dapp.DetectFramework(projectDir)
+		if err != nil {
+			return fmt.Errorf("%w. Use --framework, or --project-dir to point to the project", err)
+		}
+	case dapp.Foundry:
+		if !utils.FileExists(filepath.Join(projectDir, dapp.FoundryConfigFile)) {
+			return fmt.Errorf("%s not found at %s. Create the project with forge init first", dapp.FoundryConfigFile, projectDir)
+		}
+	case dapp.Hardhat:
+		if dapp.HardhatConfigPath(projectDir) == "" {
+			return fmt.Errorf("hardhat.config not found at %s. Create the project with npx hardhat init first", projectDir)
+		}
+	default:
+		return fmt.Errorf("unsupported framework %q, expected foundry or hardhat", dappFramework)
+	}
+	if framework == dapp.Hardhat && dappKeystoreAccount != "" {
+		return fmt.Errorf("--keystore is only supported on Foundry projects")
+	}
+	if dappKeyName != "" && !app.KeyExists(dappKeyName) {
+		return fmt.Errorf("key %s does not exist", dappKeyName)
+	}
+	project := models.DappProject{
+		Dir:             projectDir,
+		Framework:       string(framework),
+		KeyName:         dappKeyName,
+		KeystoreAccount: dappKeystoreAccount,
+		VerifierURL:     dappVerifierURL,
+	}
+	sc.DappProjects = utils.Filter(sc.DappProjects, func(p models.DappProject) bool {
+		return p.Dir != projectDir
+	})
+	sc.DappProjects = append(sc.DappProjects, project)
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+	aliases, err := writeDappProject(sc, project)
+	if err != nil {
+		return err
+	}
+	if len(aliases) == 0 {
+		ux.Logger.PrintToUser("%s is not deployed yet. The %s project at %s will be configured after each deploy", blockchainName, framework, projectDir)
+		return nil
+	}
+	ux.Logger.GreenCheckmarkToUser("%s project at %s configured for networks %s", framework, projectDir, strings.Join(aliases, ", "))
+	ux.Logger.PrintToUser("It will be updated after each deploy of %s", blockchainName)
+	if framework == dapp.Foundry {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Deploy with forge script <script> --rpc-url %s --broadcast", aliases[0])
+		return nil
+	}
+	configPath := dapp.HardhatConfigPath(projectDir)
+	if content, err := utils.ReadFile(configPath); err == nil && !strings.Contains(content, dapp.HardhatNetworksFile) {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Import the networks on %s with:", filepath.Base(configPath))
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser(dapp.HardhatConfigSnippet)
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Deploy with npx hardhat run <script> --network %s", aliases[0])
+	return nil
+}
+
+// syncDappProjects updates the dapp projects wired to [blockchainName] with its current
+// deployments. Failures are reported without failing, as the deploy itself succeeded
+func syncDappProjects(blockchainName string) {
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil || len(sc.DappProjects) == 0 {
+		return
+	}
+	for _, project := range sc.DappProjects {
+		if _, err := writeDappProject(sc, project); err != nil {
+			ux.Logger.RedXToUser("could not update dapp project %s: %s", project.Dir, err)
+			continue
+		}
+		ux.Logger.GreenCheckmarkToUser("Dapp project %s updated", project.Dir)
+	}
+}
+
+// writeDappProject writes the deployments of [sc] into the config of [project], and returns
+// the network names used on the project
+func writeDappProject(sc models.Sidecar, project models.DappProject) ([]string, error) {
+	if info, err := os.Stat(project.Dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("project directory %s not found", project.Dir)
+	}
+	genesis, err := app.LoadEvmGenesis(sc.Name)
+	if err != nil {
+		return nil, err
+	}
+	if genesis.Config == nil || genesis.Config.ChainID == nil {
+		return nil, fmt.Errorf("EVM chain ID not found on the genesis of %s", sc.Name)
+	}
+	chainID := genesis.Config.ChainID.Uint64()
+	var deployerKey string
+	if project.KeyName != "" {
+		k, err := app.GetKey(project.KeyName, models.UndefinedNetwork, false)
+		if err != nil {
+			return nil, err
+		}
+		deployerKey = k.PrivKeyHex()
+	}
+	networkNames := maps.Keys(sc.Networks)
+	sort.Strings(networkNames)
+	configs := []dapp.NetworkConfig{}
+	for _, networkName := range networkNames {
+		networkData := sc.Networks[networkName]
+		if networkData.BlockchainID == ids.Empty {
+			continue
+		}
+		network, err := app.GetNetworkFromSidecarNetworkName(networkName)
+		if err != nil {
+			continue
+		}
+		rpcURL := network.BlockchainEndpoint(networkData.BlockchainID.String())
+		if len(networkData.RPCEndpoints) > 0 {
+			rpcURL = networkData.RPCEndpoints[0]
+		}
+		aliasNetworkName := strings.ToLower(network.Kind.String())
+		switch {
+		case networkData.ClusterName != "":
+			aliasNetworkName = networkData.ClusterName
+		case network.Kind == models.Local:
+			aliasNetworkName = "local"
+		}
+		config := dapp.NetworkConfig{
+			Alias:           dapp.NetworkAlias(sc.Name, aliasNetworkName),
+			RPCURL:          rpcURL,
+			ChainID:         chainID,
+			Explorer:        getDappExplorer(network, chainID, project.VerifierURL),
+			PrivateKey:      deployerKey,
+			KeystoreAccount: project.KeystoreAccount,
+		}
+		if config.PrivateKey == "" && config.KeystoreAccount == "" && network.Kind == models.Local {
+			chainSpec := contract.ChainSpec{BlockchainName: sc.Name}
+			if _, genesisPrivateKey, err := contract.GetEVMSubnetPrefundedKey(app, network, chainSpec); err == nil {
+				config.PrivateKey = genesisPrivateKey
+			}
+		}
+		if config.PrivateKey != "" && !strings.HasPrefix(config.PrivateKey, "0x") {
+			config.PrivateKey = "0x" + config.PrivateKey
+		}
+		configs = append(configs, config)
+	}
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	if err := dapp.Write(project.Dir, dapp.Framework(project.Framework), configs); err != nil {
+		return nil, err
+	}
+	return utils.Map(configs, func(c dapp.NetworkConfig) string { return c.Alias }), nil
+}
+
+// getDappExplorer returns the explorer to verify contracts on [network], if known.
+// Routescan indexes the L1s on Fuji and Mainnet
+func getDappExplorer(network models.Network, chainID uint64, verifierURL string) *dapp.Explorer {
+	if verifierURL != "" {
+		return &dapp.Explorer{APIURL: verifierURL, BrowserURL: verifierURL}
+	}
+	switch network.Kind {
+	case models.Fuji:
+		return &dapp.Explorer{
+			APIURL:     fmt.Sprintf("https://api.routescan.io/v2/network/testnet/evm/%d/etherscan", chainID),
+			BrowserURL: "https://testnet.routescan.io",
+		}
+	case models.Mainnet:
+		return &dapp.Explorer{
+			APIURL:     fmt.Sprintf("https://api.routescan.io/v2/network/mainnet/evm/%d/etherscan", chainID),
+			BrowserURL: "https://routescan.io",
+		}
+	}
+	return nil
+}
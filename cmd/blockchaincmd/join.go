@@ -295,7 +295,8 @@ func writeAvagoChainConfigFiles(
 		_ = os.RemoveAll(subnetConfigPath)
 	}
 
-	if blockchainID != ids.Empty && app.ChainConfigExists(blockchainName) || app.NetworkUpgradeExists(blockchainName) {
+	networkUpgradesFile := app.GetNetworkUpgradeSetFilePath(sc, network.Name())
+	if blockchainID != ids.Empty && app.ChainConfigExists(blockchainName) || utils.FileExists(networkUpgradesFile) {
 		chainConfigsPath := filepath.Join(configsPath, "chains", blockchainID.String())
 		if err := os.MkdirAll(chainConfigsPath, constants.DefaultPerms755); err != nil {
 			return err
@@ -313,8 +314,8 @@ func writeAvagoChainConfigFiles(
 			_ = os.RemoveAll(chainConfigPath)
 		}
 		networkUpgradesPath := filepath.Join(chainConfigsPath, "upgrade.json")
-		if app.NetworkUpgradeExists(blockchainName) {
-			networkUpgrades, err := app.LoadRawNetworkUpgrades(blockchainName)
+		if utils.FileExists(networkUpgradesFile) {
+			networkUpgrades, err := os.ReadFile(networkUpgradesFile)
 			if err != nil {
 				return err
 			}
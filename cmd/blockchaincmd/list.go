@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
@@ -32,7 +33,7 @@ shows additional information including the VMID, BlockchainID and SubnetID.`,
 		RunE: listBlockchains,
 	}
 	cmd.Flags().BoolVar(&deployed, "deployed", false, "show additional deploy information")
-	return cmd
+	return cobrautils.MarkReadOnly(cmd)
 }
 
 type subnetMatrix [][]string
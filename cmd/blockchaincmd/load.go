@@ -0,0 +1,226 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/loadgen"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+type LoadFlags struct {
+	Network         networkoptions.NetworkFlags
+	PrivateKeyFlags contract.PrivateKeyFlags
+	rpcEndpoint     string
+	tps             float64
+	duration        time.Duration
+	txType          string
+	keys            int
+	receiptTimeout  time.Duration
+}
+
+var (
+	loadSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Fuji,
+	}
+	loadFlags LoadFlags
+)
+
+const (
+	// expected tps each load key can sustain, used to size the key pool
+	tpsPerLoadKey = 4
+	// whole token supply of the ERC20 deployed for erc20 loads
+	loadTokenSupply = 1_000_000_000
+	loadTokenSymbol = "LOAD"
+)
+
+// avalanche blockchain load
+func newLoadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "load [blockchainName]",
+		Short: "Generate sustained transaction load on a blockchain",
+		Long: `The blockchain load command issues transactions to the given EVM Blockchain at
+a sustained rate, during the given duration, and reports the achieved throughput,
+the distribution of the confirmation latency, and the causes of the failed txs.
+
+The load is issued from a pool of keys deterministically derived from the funding
+key, so the same pool is reused on subsequent loads. The pool keys are topped up
+from the funding key before the load starts, to cover the expected fees.
+
+--tx-type transfer sends native token transfers. --tx-type erc20 deploys an ERC20
+token, distributes it to the pool, and sends token transfers. Blob transactions are
+not supported, as Subnet-EVM chains do not accept them.`,
+		RunE: load,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &loadFlags.Network, true, loadSupportedNetworkOptions)
+	loadFlags.PrivateKeyFlags.AddToCmd(cmd, "to fund the load keys")
+	cmd.Flags().StringVar(&loadFlags.rpcEndpoint, "rpc", "", "generate the load on the given rpc endpoint")
+	cmd.Flags().Float64Var(&loadFlags.tps, "tps", 10, "target transactions per second")
+	cmd.Flags().DurationVar(&loadFlags.duration, "duration", time.Minute, "duration of the load")
+	cmd.Flags().StringVar(&loadFlags.txType, "tx-type", string(loadgen.TransferTx), "type of the load txs (transfer, erc20)")
+	cmd.Flags().IntVar(&loadFlags.keys, "keys", 0, "number of keys to issue the load from (default depends on --tps)")
+	cmd.Flags().DurationVar(&loadFlags.receiptTimeout, "receipt-timeout", time.Minute, "time to wait for the receipt of each tx")
+	return cmd
+}
+
+func load(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	txType, err := loadgen.ParseTxType(loadFlags.txType)
+	if err != nil {
+		return err
+	}
+	if loadFlags.tps <= 0 {
+		return errors.New("--tps must be positive")
+	}
+	if loadFlags.duration <= 0 {
+		return errors.New("--duration must be positive")
+	}
+	if loadFlags.keys < 0 {
+		return errors.New("--keys can not be negative")
+	}
+	if loadFlags.keys == 0 {
+		loadFlags.keys = max(1, int(loadFlags.tps/tpsPerLoadKey))
+	}
+	if _, err := app.LoadSidecar(blockchainName); err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		loadFlags.Network,
+		true,
+		false,
+		loadSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	chainSpec := contract.ChainSpec{
+		BlockchainName: blockchainName,
+	}
+	if loadFlags.rpcEndpoint == "" {
+		loadFlags.rpcEndpoint, _, err = contract.GetBlockchainEndpoints(
+			app,
+			network,
+			chainSpec,
+			true,
+			false,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), loadFlags.rpcEndpoint)
+	genesisAddress, genesisPrivateKey, err := contract.GetEVMSubnetPrefundedKey(
+		app,
+		network,
+		chainSpec,
+	)
+	if err != nil {
+		return err
+	}
+	privateKey, err := loadFlags.PrivateKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	if err != nil {
+		return err
+	}
+	if privateKey == "" {
+		ux.Logger.PrintToUser("A private key is needed to fund the keys that issue the load.")
+		privateKey, err = prompts.PromptPrivateKey(
+			app.Prompt,
+			"fund the load",
+			app.GetKeyDir(),
+			app.GetKey,
+			genesisAddress,
+			genesisPrivateKey,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	funder, err := crypto.HexToECDSA(privateKey)
+	if err != nil {
+		return err
+	}
+	client, err := evm.GetClient(loadFlags.rpcEndpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	config := loadgen.Config{
+		TPS:            loadFlags.tps,
+		Duration:       loadFlags.duration,
+		TxType:         txType,
+		ReceiptTimeout: loadFlags.receiptTimeout,
+	}
+	if txType == loadgen.ERC20Tx {
+		funderAddress := crypto.PubkeyToAddress(funder.PublicKey)
+		ux.Logger.PrintToUser("Deploying %s token for the load...", loadTokenSymbol)
+		config.Token, err = contract.DeployERC20(
+			loadFlags.rpcEndpoint,
+			privateKey,
+			loadTokenSymbol,
+			funderAddress,
+			big.NewInt(loadTokenSupply),
+		)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Token deployed at %s", config.Token.Hex())
+	}
+	keys, err := loadgen.DeriveKeys(funder, loadFlags.keys)
+	if err != nil {
+		return err
+	}
+	if err := loadgen.FundKeys(
+		loadFlags.rpcEndpoint,
+		client,
+		funder,
+		keys,
+		txType,
+		config.Token,
+		config.ExpectedTxsPerKey(len(keys)),
+	); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ux.Logger.PrintToUser(
+		"Issuing %s txs to %s at %.2f TPS during %s from %d keys. Press Ctrl+C to stop",
+		txType,
+		blockchainName,
+		loadFlags.tps,
+		loadFlags.duration,
+		len(keys),
+	)
+	stats, err := loadgen.Run(ctx, client, keys, config)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(stats.Render(loadFlags.tps))
+	return nil
+}
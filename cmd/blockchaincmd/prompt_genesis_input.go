@@ -62,8 +62,11 @@ func promptValidatorManagementType(
 	sidecar *models.Sidecar,
 ) error {
 	explainOption := "Explain the difference"
-	if createFlags.proofOfStake {
+	if createFlags.proofOfStake || createFlags.erc20Staking {
 		sidecar.ValidatorManagement = models.ProofOfStake
+		if createFlags.erc20Staking {
+			sidecar.StakingToken = models.ERC20TokenStaking
+		}
 		return nil
 	}
 	if createFlags.proofOfAuthority {
@@ -85,6 +88,9 @@ func promptValidatorManagementType(
 			sidecar.ValidatorManagement = models.ValidatorManagementTypeFromString(option)
 		case models.ProofOfStake:
 			sidecar.ValidatorManagement = models.ValidatorManagementTypeFromString(option)
+			if err := promptStakingToken(app, sidecar); err != nil {
+				return err
+			}
 		case explainOption:
 			continue
 		}
@@ -93,6 +99,25 @@ func promptValidatorManagementType(
 	return nil
 }
 
+// promptStakingToken asks for the asset validators and delegators stake on a PoS blockchain
+func promptStakingToken(
+	app *application.Avalanche,
+	sidecar *models.Sidecar,
+) error {
+	option, err := app.Prompt.CaptureList(
+		"Which token will validators and delegators stake?",
+		[]string{models.NativeTokenStaking, models.ERC20TokenStaking},
+	)
+	if err != nil {
+		return err
+	}
+	if models.StakingTokenTypeFromString(option) == models.ERC20TokenStaking {
+		sidecar.StakingToken = models.ERC20TokenStaking
+		ux.Logger.PrintToUser("The ERC20 staking token is deployed, or chosen, when the blockchain is deployed")
+	}
+	return nil
+}
+
 // generateNewNodeAndBLS returns node id, bls public key and bls pop
 func generateNewNodeAndBLS() (string, string, string, error) {
 	certBytes, _, err := staking.NewCertAndKeyBytes()
@@ -0,0 +1,287 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/rpcproxy"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+type ProxyFlags struct {
+	Network        networkoptions.NetworkFlags
+	rpcEndpoint    string
+	listen         string
+	capturePath    string
+	replayPath     string
+	methods        []string
+	excludeMethods []string
+	errorsOnly     bool
+	logBodies      bool
+	quiet          bool
+	replayWrites   bool
+}
+
+var (
+	proxySupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Fuji,
+	}
+	proxyFlags ProxyFlags
+)
+
+const (
+	// local only, on a free port, to not collide with the local network nodes
+	defaultProxyListenAddress = "127.0.0.1:0"
+	// max size of a request or response body printed with --log-bodies
+	maxLoggedBodySize = 1024
+)
+
+// avalanche blockchain proxy
+func newProxyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy [blockchainName]",
+		Short: "Proxy and log JSON-RPC requests to a blockchain",
+		Long: `The blockchain proxy command serves a JSON-RPC endpoint that forwards all
+requests to the RPC endpoint of the given Blockchain, logging each request and
+response. Point a dapp or wallet to the proxy to debug its interaction with the chain.
+
+The logged requests can be filtered by method with --methods and --exclude-methods,
+or restricted to failed requests with --errors-only. With --capture, the logged
+requests and responses are appended to the given file, one JSON exchange per line.
+
+The proxy listens on a free localhost port by default. Use --listen to set the address.
+If its port is in use, the processes using it are reported.
+
+With --replay, the requests of a previous capture (passing the same filters) are
+sent again to the Blockchain, in order, reporting the ones whose response changed.
+Requests that send or sign transactions are not replayed unless --replay-writes is given.`,
+		RunE: proxy,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &proxyFlags.Network, true, proxySupportedNetworkOptions)
+	cmd.Flags().StringVar(&proxyFlags.rpcEndpoint, "rpc", "", "proxy to the given rpc endpoint")
+	cmd.Flags().StringVar(&proxyFlags.listen, "listen", defaultProxyListenAddress, "address to serve the proxy on")
+	cmd.Flags().StringVar(&proxyFlags.capturePath, "capture", "", "append the logged requests and responses to the given file")
+	cmd.Flags().StringVar(&proxyFlags.replayPath, "replay", "", "replay the requests captured on the given file, instead of serving the proxy")
+	cmd.Flags().BoolVar(&proxyFlags.replayWrites, "replay-writes", false, "also replay requests that send or sign transactions")
+	cmd.Flags().StringSliceVar(&proxyFlags.methods, "methods", nil, "only log the given JSON-RPC methods")
+	cmd.Flags().StringSliceVar(&proxyFlags.excludeMethods, "exclude-methods", nil, "do not log the given JSON-RPC methods")
+	cmd.Flags().BoolVar(&proxyFlags.errorsOnly, "errors-only", false, "only log requests that failed")
+	cmd.Flags().BoolVar(&proxyFlags.logBodies, "log-bodies", false, "print request and response bodies")
+	cmd.Flags().BoolVar(&proxyFlags.quiet, "quiet", false, "do not print requests, only capture them")
+	return cmd
+}
+
+func proxy(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	if proxyFlags.quiet && proxyFlags.capturePath == "" && proxyFlags.replayPath == "" {
+		return errors.New("--quiet requires --capture")
+	}
+	if proxyFlags.capturePath != "" && proxyFlags.replayPath != "" {
+		return errors.New("--capture and --replay are mutually exclusive")
+	}
+	if _, err := app.LoadSidecar(blockchainName); err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		proxyFlags.Network,
+		true,
+		false,
+		proxySupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	if proxyFlags.rpcEndpoint == "" {
+		proxyFlags.rpcEndpoint, _, err = contract.GetBlockchainEndpoints(
+			app,
+			network,
+			contract.ChainSpec{
+				BlockchainName: blockchainName,
+			},
+			true,
+			false,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), proxyFlags.rpcEndpoint)
+	filter := rpcproxy.Filter{
+		Methods:        proxyFlags.methods,
+		ExcludeMethods: proxyFlags.excludeMethods,
+		ErrorsOnly:     proxyFlags.errorsOnly,
+	}
+	if proxyFlags.replayPath != "" {
+		return replayCapture(proxyFlags.rpcEndpoint, proxyFlags.replayPath, filter)
+	}
+	return serveProxy(blockchainName, proxyFlags.rpcEndpoint, filter)
+}
+
+func serveProxy(blockchainName string, rpcEndpoint string, filter rpcproxy.Filter) error {
+	var capture *os.File
+	if proxyFlags.capturePath != "" {
+		var err error
+		capture, err = os.OpenFile(proxyFlags.capturePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.WriteReadUserOnlyPerms)
+		if err != nil {
+			return err
+		}
+		defer capture.Close()
+	}
+	onExchange := func(exchange rpcproxy.Exchange) {
+		if capture != nil {
+			if err := rpcproxy.WriteExchange(capture, exchange); err != nil {
+				ux.Logger.RedXToUser("failure capturing request: %s", err)
+			}
+		}
+		if !proxyFlags.quiet {
+			printExchange(exchange)
+		}
+	}
+	listener, err := net.Listen("tcp", proxyFlags.listen)
+	if err != nil {
+		return proxyListenError(proxyFlags.listen, err)
+	}
+	server := &http.Server{
+		Handler:           rpcproxy.NewProxy(rpcEndpoint, filter, onExchange),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Serve(listener)
+	}()
+	ux.Logger.GreenCheckmarkToUser("Proxying %s JSON-RPC requests from http://%s", blockchainName, listener.Addr())
+	if capture != nil {
+		ux.Logger.PrintToUser("Capturing requests to %s", proxyFlags.capturePath)
+	}
+	ux.Logger.PrintToUser("Press Ctrl+C to stop")
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(signals)
+	select {
+	case err := <-serverErr:
+		return err
+	case <-signals:
+		ux.Logger.PrintToUser("")
+		return server.Close()
+	}
+}
+
+// proxyListenError reports the processes using the port of [listenAddress] if the
+// proxy failed to listen on it because it is in use
+func proxyListenError(listenAddress string, err error) error {
+	if !utils.IsPortInUseError(err) {
+		return err
+	}
+	_, portStr, splitErr := net.SplitHostPort(listenAddress)
+	if splitErr != nil {
+		return err
+	}
+	port, parseErr := strconv.ParseUint(portStr, 10, 16)
+	if parseErr != nil {
+		return err
+	}
+	report := utils.PortConflictsReport([]utils.PortConflict{utils.NewPortConflict("blockchain proxy", uint16(port))})
+	return fmt.Errorf("%s\nstop the process using it, or use --listen to set another address", report)
+}
+
+func printExchange(exchange rpcproxy.Exchange) {
+	status := logging.Green.Wrap("OK")
+	if exchange.Failed() {
+		status = logging.Red.Wrap("FAILED")
+	}
+	ux.Logger.PrintToUser(
+		"%s %s %s (%dms)",
+		exchange.Timestamp.Local().Format(time.TimeOnly),
+		strings.Join(exchange.Methods, ","),
+		status,
+		exchange.LatencyMs,
+	)
+	if exchange.Error != "" {
+		ux.Logger.PrintToUser("  error: %s", exchange.Error)
+	}
+	if proxyFlags.logBodies {
+		ux.Logger.PrintToUser("  request: %s", truncateBody(exchange.Request))
+		ux.Logger.PrintToUser("  response: %s", truncateBody(exchange.Response))
+	}
+}
+
+func truncateBody(body []byte) string {
+	if len(body) <= maxLoggedBodySize {
+		return string(body)
+	}
+	return fmt.Sprintf("%s... (%d bytes)", body[:maxLoggedBodySize], len(body))
+}
+
+func replayCapture(rpcEndpoint string, capturePath string, filter rpcproxy.Filter) error {
+	exchanges, err := rpcproxy.ReadCapture(capturePath)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Replaying requests from %s", capturePath)
+	results, skipped := rpcproxy.Replay(&http.Client{Timeout: time.Minute}, rpcEndpoint, exchanges, filter, proxyFlags.replayWrites)
+	if skipped > 0 {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Skipped %d requests that send or sign transactions. Use --replay-writes to replay them"), skipped)
+	}
+	if len(results) == 0 {
+		ux.Logger.PrintToUser("No captured requests match the given filters")
+		return nil
+	}
+	t := ux.DefaultTable("Replay", table.Row{"#", "Methods", "Captured", "Replayed", "Response"})
+	changed := 0
+	for i, result := range results {
+		response := "same"
+		if !result.SameResponse() {
+			response = logging.Yellow.Wrap("changed")
+			changed++
+		}
+		t.AppendRow(table.Row{
+			i + 1,
+			strings.Join(result.Captured.Methods, ","),
+			exchangeStatus(result.Captured),
+			exchangeStatus(result.Replayed),
+			response,
+		})
+		if proxyFlags.logBodies && !result.SameResponse() {
+			ux.Logger.PrintToUser("#%d captured response: %s", i+1, truncateBody(result.Captured.Response))
+			ux.Logger.PrintToUser("#%d replayed response: %s", i+1, truncateBody(result.Replayed.Response))
+		}
+	}
+	ux.Logger.PrintToUser(t.Render())
+	ux.Logger.PrintToUser("Replayed %d requests, %d with changed responses", len(results), changed)
+	return nil
+}
+
+func exchangeStatus(exchange rpcproxy.Exchange) string {
+	if exchange.Failed() {
+		return logging.Red.Wrap("failed")
+	}
+	return logging.Green.Wrap("ok")
+}
@@ -0,0 +1,484 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/recovery"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+type RecoverFlags struct {
+	minBalance   float64
+	balance      float64
+	upgradeDelay time.Duration
+	dryRun       bool
+	yes          bool
+}
+
+var (
+	recoverSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Fuji,
+		networkoptions.Mainnet,
+	}
+	recoverFlags RecoverFlags
+)
+
+// avalanche blockchain recover
+func newRecoverCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recover [blockchainName]",
+		Short: "Diagnose and remediate a halted L1",
+		Long: `The blockchain recover command diagnoses the common causes of a halted L1, and
+offers to remediate them. It checks:
+
+- that enough of the L1 validator weight is online for the L1 to make progress
+- that the validators have P-Chain balance to pay for the continuous fee
+- that the validator registrations initialized by the CLI were completed
+- that the upgrade file can be parsed, keeps the applied upgrades, and schedules
+  the new ones in the future
+
+Found problems are listed together with their remediation. The ones that can be
+automated are applied after confirmation: validator balances are increased from
+the given key, pending registrations are issued again, expired ones are invalidated
+on the validator manager, and corrected upgrade files are generated with new
+activation times, keeping a backup of the original one. With --yes, --balance must
+be given if validator balances need to be increased.
+
+Use --dry-run to only diagnose the L1.`,
+		RunE: recoverBlockchain,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, recoverSupportedNetworkOptions)
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use to pay for the remediation txs [fuji/devnet only]")
+	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji/devnet)")
+	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().StringVar(&rpcURL, "rpc", "", "connect to the L1 at the given rpc endpoint")
+	cmd.Flags().StringSliceVar(&aggregatorExtraEndpoints, "aggregator-extra-endpoints", nil, "endpoints for extra nodes that are needed in signature aggregation")
+	cmd.Flags().BoolVar(&aggregatorAllowPrivatePeers, "aggregator-allow-private-peers", true, "allow the signature aggregator to connect to peers with private IP")
+	cmd.Flags().StringVar(&aggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	interchain.AddAggregationFlagsToCmd(cmd, &aggregationFlags)
+	cmd.Flags().Float64Var(&recoverFlags.minBalance, "min-balance", 0.1, "warn about validators with a P-Chain balance below this amount of AVAX")
+	cmd.Flags().Float64Var(&recoverFlags.balance, "balance", 0, "amount of AVAX to increase the balance of each validator by (default: prompt)")
+	cmd.Flags().DurationVar(&recoverFlags.upgradeDelay, "upgrade-delay", 10*time.Minute, "schedule the new upgrades of corrected upgrade files this time from now")
+	cmd.Flags().BoolVar(&recoverFlags.dryRun, "dry-run", false, "only diagnose the L1, without applying any remediation")
+	cmd.Flags().BoolVarP(&recoverFlags.yes, "yes", "y", false, "apply the automated remediations without asking for confirmation")
+	return cmd
+}
+
+func recoverBlockchain(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.GetNetworkFromSidecar(sc, recoverSupportedNetworkOptions),
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	if sc.Networks[network.Name()].SubnetID == ids.Empty {
+		return fmt.Errorf("blockchain %s has not been deployed to %s", blockchainName, network.Name())
+	}
+
+	findings := []recovery.Finding{}
+	if sc.Sovereign {
+		validatorFindings, err := diagnoseL1Validators(network, blockchainName)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, validatorFindings...)
+	} else {
+		ux.Logger.PrintToUser("%s is not an L1, its validators are not checked", blockchainName)
+	}
+	upgradeFindings, err := diagnoseUpgradeFile(blockchainName)
+	if err != nil {
+		return err
+	}
+	findings = append(findings, upgradeFindings...)
+
+	if len(findings) == 0 {
+		ux.Logger.GreenCheckmarkToUser("No problems found on %s", blockchainName)
+		return nil
+	}
+	printRecoveryFindings(blockchainName, findings)
+	if recoverFlags.dryRun {
+		return nil
+	}
+	return applyRemediations(network, blockchainName, findings)
+}
+
+// diagnoseL1Validators checks the validator set of [blockchainName] on [network], and the
+// registrations of validators to it that were not completed
+func diagnoseL1Validators(network models.Network, blockchainName string) ([]recovery.Finding, error) {
+	findings := []recovery.Finding{}
+	if rpcURL == "" {
+		var err error
+		rpcURL, _, err = contract.GetBlockchainEndpoints(
+			app,
+			network,
+			contract.ChainSpec{
+				BlockchainName: blockchainName,
+			},
+			false,
+			false,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var validators []recovery.ValidatorStatus
+	if rpcURL == "" {
+		findings = append(findings, recovery.Finding{
+			Check:       recovery.RPCCheck,
+			Severity:    recovery.Critical,
+			Description: "no rpc endpoint is known for the L1, its validator set can not be checked",
+			Remediation: "provide the L1 rpc endpoint with --rpc",
+		})
+	} else if l1Validators, err := utils.GetL1Validators(rpcURL); err != nil {
+		findings = append(findings, recovery.Finding{
+			Check:       recovery.RPCCheck,
+			Severity:    recovery.Critical,
+			Description: fmt.Sprintf("the L1 rpc endpoint %s does not answer: %s", rpcURL, err),
+			Remediation: "check that the L1 nodes are running, or provide a working rpc endpoint with --rpc",
+		})
+	} else {
+		for _, validator := range l1Validators {
+			balance, err := txutils.GetValidatorPChainBalanceValidationID(network, validator.ValidationID)
+			if err != nil {
+				return nil, fmt.Errorf("failure obtaining P-Chain balance of validator %s: %w", validator.NodeID, err)
+			}
+			validators = append(validators, recovery.ValidatorStatus{
+				NodeID:       validator.NodeID,
+				ValidationID: validator.ValidationID,
+				Weight:       validator.Weight,
+				Balance:      balance,
+				Active:       validator.IsActive,
+				Connected:    validator.IsConnected,
+			})
+		}
+		findings = append(findings, recovery.DiagnoseValidators(validators, recovery.Thresholds{
+			QuorumPercentage: validatormanager.DefaultRemovalSafetyThresholds.QuorumPercentage,
+			MinBalance:       uint64(recoverFlags.minBalance * float64(units.Avax)),
+		})...)
+	}
+
+	registrations, err := app.LoadValidatorRegistrations()
+	if err != nil {
+		return nil, err
+	}
+	pending := []models.ValidatorRegistration{}
+	registered := map[string]bool{}
+	for _, registration := range registrations.Registrations {
+		if registration.Network != network.Name() || registration.BlockchainName != blockchainName {
+			continue
+		}
+		validationID, err := ids.FromString(registration.ValidationID)
+		if err != nil {
+			return nil, err
+		}
+		isRegistered, err := IsRegisteredOnPChain(network, validationID)
+		if err != nil {
+			return nil, err
+		}
+		registered[registration.ValidationID] = isRegistered
+		pending = append(pending, registration)
+	}
+	registrationFindings, err := recovery.DiagnoseRegistrations(pending, registered, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, registrationFindings...), nil
+}
+
+// diagnoseUpgradeFile checks the upgrade file of [blockchainName], if any, against its lock file
+func diagnoseUpgradeFile(blockchainName string) ([]recovery.Finding, error) {
+	upgradeFilePath := app.GetUpgradeBytesFilePath(blockchainName)
+	if !utils.FileExists(upgradeFilePath) {
+		return nil, nil
+	}
+	upgradeBytes, err := app.ReadUpgradeFile(blockchainName)
+	if err != nil {
+		return nil, err
+	}
+	lockBytes, err := readUpgradeLockFile(blockchainName)
+	if err != nil {
+		return nil, err
+	}
+	return recovery.DiagnoseUpgradeFile(upgradeBytes, lockBytes, time.Now()), nil
+}
+
+func readUpgradeLockFile(blockchainName string) ([]byte, error) {
+	if !utils.FileExists(app.GetUpgradeBytesFilePath(blockchainName) + constants.UpgradeBytesLockExtension) {
+		return nil, nil
+	}
+	return app.ReadLockUpgradeFile(blockchainName)
+}
+
+func printRecoveryFindings(blockchainName string, findings []recovery.Finding) {
+	t := ux.DefaultTable(
+		fmt.Sprintf("Problems found on %s", blockchainName),
+		table.Row{"Severity", "Check", "Finding", "Remediation"},
+	)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 3, WidthMax: 60},
+		{Number: 4, WidthMax: 50},
+	})
+	for _, finding := range findings {
+		severity := logging.Yellow.Wrap(string(finding.Severity))
+		if finding.Severity == recovery.Critical {
+			severity = logging.Red.Wrap(string(finding.Severity))
+		}
+		remediation := finding.Remediation
+		if finding.Action != recovery.NoAction {
+			remediation += " (automated)"
+		}
+		t.AppendRow(table.Row{severity, finding.Check, finding.Description, remediation})
+	}
+	ux.Logger.PrintToUser(t.Render())
+}
+
+// applyRemediations applies, after confirmation, the automated remediations of [findings].
+// Failed remediations are reported, and do not stop the remaining ones
+func applyRemediations(network models.Network, blockchainName string, findings []recovery.Finding) error {
+	var (
+		deployer          *subnet.PublicDeployer
+		kc                *keychain.Keychain
+		upgradeCorrected  bool
+		failedRemediation bool
+	)
+	getDeployer := func() (*subnet.PublicDeployer, *keychain.Keychain, error) {
+		if deployer != nil {
+			return deployer, kc, nil
+		}
+		fee := network.GenesisParams().TxFeeConfig.StaticFeeConfig.TxFee
+		var err error
+		kc, err = keychain.GetKeychainFromCmdLineFlags(
+			app,
+			constants.PayTxsFeesMsg,
+			network,
+			keyName,
+			useEwoq,
+			useLedger,
+			ledgerAddresses,
+			fee,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		deployer = subnet.NewPublicDeployer(app, kc, network)
+		return deployer, kc, nil
+	}
+	if recoverFlags.yes && recoverFlags.balance == 0 && slices.ContainsFunc(findings, func(finding recovery.Finding) bool {
+		return finding.Action == recovery.IncreaseBalanceAction
+	}) {
+		return fmt.Errorf("--balance is required to increase validator balances with --yes")
+	}
+	for _, finding := range findings {
+		if finding.Action == recovery.NoAction || (finding.Action == recovery.CorrectUpgradeFileAction && upgradeCorrected) {
+			continue
+		}
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("%s", finding.Description)
+		if !recoverFlags.yes {
+			target := ""
+			if finding.NodeID != ids.EmptyNodeID {
+				target = fmt.Sprintf(" of %s", finding.NodeID)
+			}
+			yes, err := app.Prompt.CaptureYesNo(fmt.Sprintf("Do you want to %s%s?", finding.Action, target))
+			if err != nil {
+				return err
+			}
+			if !yes {
+				continue
+			}
+		}
+		var err error
+		switch finding.Action {
+		case recovery.IncreaseBalanceAction:
+			err = recoverValidatorBalance(network, finding, getDeployer)
+		case recovery.ReissueRegistrationAction:
+			err = recoverValidatorRegistration(network, finding, getDeployer)
+		case recovery.ExpireRegistrationAction:
+			err = expireValidatorRegistration(network, finding)
+		case recovery.CorrectUpgradeFileAction:
+			upgradeCorrected = true
+			err = correctUpgradeFile(blockchainName)
+		}
+		if err != nil {
+			failedRemediation = true
+			ux.Logger.RedXToUser("failure trying to %s: %s", finding.Action, err)
+		}
+	}
+	if failedRemediation {
+		return fmt.Errorf("some remediations failed. Run avalanche blockchain recover %s again once their causes are fixed", blockchainName)
+	}
+	return nil
+}
+
+func recoverValidatorBalance(
+	network models.Network,
+	finding recovery.Finding,
+	getDeployer func() (*subnet.PublicDeployer, *keychain.Keychain, error),
+) error {
+	deployer, kc, err := getDeployer()
+	if err != nil {
+		return err
+	}
+	balance := uint64(recoverFlags.balance * float64(units.Avax))
+	if balance == 0 {
+		availableBalance, err := utils.GetNetworkBalance(kc.Addresses().List(), network.Endpoint)
+		if err != nil {
+			return err
+		}
+		balance, err = app.Prompt.CaptureValidatorBalance(
+			"How many AVAX do you want to increase the balance of this validator by?",
+			availableBalance/units.Avax,
+			0,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := deployer.IncreaseValidatorPChainBalance(finding.ValidationID, balance); err != nil {
+		return err
+	}
+	deployer.CleanCacheWallet()
+	balance, err = txutils.GetValidatorPChainBalanceValidationID(network, finding.ValidationID)
+	if err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("New balance of validator %s: %.5f AVAX", finding.NodeID, float64(balance)/float64(units.Avax))
+	return nil
+}
+
+func recoverValidatorRegistration(
+	network models.Network,
+	finding recovery.Finding,
+	getDeployer func() (*subnet.PublicDeployer, *keychain.Keychain, error),
+) error {
+	registrations, err := app.LoadValidatorRegistrations()
+	if err != nil {
+		return err
+	}
+	registration, ok := registrations.Registrations[finding.ValidationID.String()]
+	if !ok {
+		return fmt.Errorf("registration of validator %s is no longer tracked", finding.NodeID)
+	}
+	registered, err := IsRegisteredOnPChain(network, finding.ValidationID)
+	if err != nil {
+		return err
+	}
+	deployer, _, err := getDeployer()
+	if err != nil {
+		return err
+	}
+	aggregationConfig, err := interchain.GetAggregationConfig(app, aggregationFlags)
+	if err != nil {
+		return err
+	}
+	return ReissueValidatorRegistration(
+		network,
+		deployer,
+		registration,
+		finding.ValidationID,
+		registered,
+		rpcURL,
+		aggregatorExtraEndpoints,
+		aggregatorAllowPrivatePeers,
+		aggregatorLogLevel,
+		aggregationConfig,
+	)
+}
+
+// expireValidatorRegistration invalidates on the validator manager the expired registration
+// of [finding], so the validator can be added again
+func expireValidatorRegistration(network models.Network, finding recovery.Finding) error {
+	registrations, err := app.LoadValidatorRegistrations()
+	if err != nil {
+		return err
+	}
+	registration, ok := registrations.Registrations[finding.ValidationID.String()]
+	if !ok {
+		return fmt.Errorf("registration of validator %s is no longer tracked", finding.NodeID)
+	}
+	aggregationConfig, err := interchain.GetAggregationConfig(app, aggregationFlags)
+	if err != nil {
+		return err
+	}
+	if err := ExpireValidatorRegistration(
+		network,
+		registration,
+		finding.ValidationID,
+		rpcURL,
+		aggregatorExtraEndpoints,
+		aggregatorAllowPrivatePeers,
+		aggregatorLogLevel,
+		aggregationConfig,
+	); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Add the validator again with avalanche blockchain addValidator %s", registration.BlockchainName)
+	return nil
+}
+
+// correctUpgradeFile replaces the upgrade file of [blockchainName] by a corrected one,
+// keeping a backup of the original one
+func correctUpgradeFile(blockchainName string) error {
+	upgradeBytes, err := app.ReadUpgradeFile(blockchainName)
+	if err != nil {
+		return err
+	}
+	lockBytes, err := readUpgradeLockFile(blockchainName)
+	if err != nil {
+		return err
+	}
+	activation := time.Now().Add(recoverFlags.upgradeDelay)
+	corrected, err := recovery.CorrectUpgradeFile(upgradeBytes, lockBytes, activation)
+	if err != nil {
+		return err
+	}
+	backupPath := app.GetUpgradeBytesFilePath(blockchainName) + constants.UpgradeBytesBackupExtension
+	if err := os.WriteFile(backupPath, upgradeBytes, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	if err := app.WriteUpgradeFile(blockchainName, corrected); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Corrected upgrade file written. The original one was saved to %s", backupPath)
+	ux.Logger.PrintToUser("New upgrades are scheduled from %s on", activation.UTC().Format(time.RFC1123))
+	ux.Logger.PrintToUser("Apply it to the L1 nodes with avalanche blockchain upgrade apply %s", blockchainName)
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	sdkinterchain "github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// IsRegisteredOnPChain indicates if P-Chain already accepted the registration of [validationID]
+func IsRegisteredOnPChain(network models.Network, validationID ids.ID) (bool, error) {
+	_, err := txutils.GetL1Validator(network, validationID)
+	if errors.Is(err, txutils.ErrL1ValidatorNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// registrationSetup holds what is needed to complete or expire a tracked validator
+// registration on the validator manager
+type registrationSetup struct {
+	chainSpec            contract.ChainSpec
+	ownerPrivateKey      string
+	rpcURL               string
+	extraAggregatorPeers []info.Peer
+}
+
+// getRegistrationSetup loads the validator manager owner key, endpoint and aggregator peers
+// for [registration]. [rpcEndpoint] overrides the endpoint recorded on [registration]
+func getRegistrationSetup(
+	network models.Network,
+	registration models.ValidatorRegistration,
+	rpcEndpoint string,
+	extraAggregatorEndpoints []string,
+) (registrationSetup, error) {
+	setup := registrationSetup{
+		chainSpec: contract.ChainSpec{
+			BlockchainName: registration.BlockchainName,
+		},
+	}
+	sc, err := app.LoadSidecar(registration.BlockchainName)
+	if err != nil {
+		return setup, fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	ownerPrivateKeyFound, _, _, ownerPrivateKey, err := contract.SearchForManagedKey(
+		app,
+		network,
+		common.HexToAddress(sc.ValidatorManagerOwner),
+		true,
+	)
+	if err != nil {
+		return setup, err
+	}
+	if !ownerPrivateKeyFound {
+		return setup, fmt.Errorf("private key for Validator manager owner %s is not found", sc.ValidatorManagerOwner)
+	}
+	setup.ownerPrivateKey = ownerPrivateKey
+	setup.rpcURL = rpcEndpoint
+	if setup.rpcURL == "" {
+		setup.rpcURL = registration.RPCURL
+	}
+	if setup.rpcURL == "" {
+		setup.rpcURL, _, err = contract.GetBlockchainEndpoints(app, network, setup.chainSpec, true, false)
+		if err != nil {
+			return setup, err
+		}
+	}
+	setup.extraAggregatorPeers, err = GetAggregatorExtraPeers(sc.Networks[network.Name()].ClusterName, extraAggregatorEndpoints)
+	if err != nil {
+		return setup, err
+	}
+	return setup, nil
+}
+
+// ReissueValidatorRegistration issues the registration message of [registration] again to
+// P-Chain, unless it was already accepted, and completes it on the validator manager.
+// [rpcEndpoint] overrides the validator manager endpoint recorded on [registration]
+func ReissueValidatorRegistration(
+	network models.Network,
+	deployer *subnet.PublicDeployer,
+	registration models.ValidatorRegistration,
+	validationID ids.ID,
+	registered bool,
+	rpcEndpoint string,
+	extraAggregatorEndpoints []string,
+	allowPrivatePeers bool,
+	logLevel string,
+	aggregationConfig sdkinterchain.AggregationConfig,
+) error {
+	setup, err := getRegistrationSetup(network, registration, rpcEndpoint, extraAggregatorEndpoints)
+	if err != nil {
+		return err
+	}
+	nodeID, err := ids.NodeIDFromString(registration.NodeID)
+	if err != nil {
+		return err
+	}
+	if !registered {
+		blsInfo, err := GetBLSInfo(registration.BLSPublicKey, registration.BLSProofOfPossession)
+		if err != nil {
+			return fmt.Errorf("failure parsing BLS info: %w", err)
+		}
+		signedMessage, _, err := validatormanager.ReissueValidatorRegistration(
+			app,
+			network,
+			setup.rpcURL,
+			setup.chainSpec,
+			nodeID,
+			setup.extraAggregatorPeers,
+			allowPrivatePeers,
+			logLevel,
+			aggregationConfig,
+		)
+		if err != nil {
+			return err
+		}
+		txID, _, err := deployer.RegisterL1Validator(registration.Balance, blsInfo, signedMessage)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("RegisterL1ValidatorTx ID: %s", txID)
+		if err := UpdatePChainHeight(
+			"Waiting for P-Chain to update validator information ...",
+		); err != nil {
+			return err
+		}
+	}
+	if err := validatormanager.FinishValidatorRegistration(
+		app,
+		network,
+		setup.rpcURL,
+		setup.chainSpec,
+		setup.ownerPrivateKey,
+		validationID,
+		setup.extraAggregatorPeers,
+		allowPrivatePeers,
+		logLevel,
+		aggregationConfig,
+	); err != nil {
+		return err
+	}
+	if err := app.UntrackValidatorRegistration(registration.ValidationID); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Validator %s successfully added to %s", registration.NodeID, registration.BlockchainName)
+	return nil
+}
+
+// ExpireValidatorRegistration invalidates on the validator manager the expired registration
+// [registration], using the P-Chain attestation that [validationID] was never registered,
+// so the validator manager stops considering the validator as pending to be added.
+// [rpcEndpoint] overrides the validator manager endpoint recorded on [registration]
+func ExpireValidatorRegistration(
+	network models.Network,
+	registration models.ValidatorRegistration,
+	validationID ids.ID,
+	rpcEndpoint string,
+	extraAggregatorEndpoints []string,
+	allowPrivatePeers bool,
+	logLevel string,
+	aggregationConfig sdkinterchain.AggregationConfig,
+) error {
+	setup, err := getRegistrationSetup(network, registration, rpcEndpoint, extraAggregatorEndpoints)
+	if err != nil {
+		return err
+	}
+	if err := validatormanager.FinishValidatorRemoval(
+		app,
+		network,
+		setup.rpcURL,
+		setup.chainSpec,
+		setup.ownerPrivateKey,
+		validationID,
+		setup.extraAggregatorPeers,
+		allowPrivatePeers,
+		logLevel,
+		aggregationConfig,
+	); err != nil {
+		return err
+	}
+	if err := app.UntrackValidatorRegistration(registration.ValidationID); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Expired registration of validator %s to %s invalidated on the validator manager", registration.NodeID, registration.BlockchainName)
+	return nil
+}
@@ -12,7 +12,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/events"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
 	"github.com/ava-labs/avalanche-cli/pkg/keychain"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
@@ -25,6 +28,8 @@ import (
 	validatormanagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	"github.com/spf13/cobra"
@@ -51,7 +56,16 @@ func newRemoveValidatorCmd() *cobra.Command {
 validating your deployed Blockchain.
 
 To remove the validator from the Subnet's allow list, provide the validator's unique NodeID. You can bypass
-these prompts by providing the values with flags.`,
+these prompts by providing the values with flags.
+
+For L1s, the validator set resulting from the removal is analyzed first. Removals that leave the L1 with
+fewer active validators than the configured minimum (L1MinValidators in the config file), with a single
+validator holding more than 33% of the weight, or with less than 67% of the weight connected, must be
+confirmed or forced with --force.
+
+On removal, the P-Chain refunds the validator remaining balance to its remaining balance owner.
+The refund is verified and recorded. Use --refund-address to move it to another P-Chain address,
+signing with the stored key owning it. Refunds can also be moved later with avalanche validator refund.`,
 		RunE: removeValidator,
 		Args: cobrautils.ExactArgs(1),
 	}
@@ -61,6 +75,7 @@ these prompts by providing the values with flags.`,
 	cmd.Flags().StringVar(&outputTxPath, "output-tx-path", "", "(for non-SOV blockchain only) file path of the removeValidator tx")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().StringSliceVar(&custodyAddresses, "custody-addrs", nil, "(for non-SOV blockchain only) pay with the given P-Chain addresses of keys kept by custodial signers, saving the tx for them to sign")
 	cmd.Flags().StringVar(&nodeIDStr, "node-id", "", "node-id of the validator")
 	cmd.Flags().StringVar(&nodeEndpoint, "node-endpoint", "", "remove validator that responds to the given endpoint")
 	cmd.Flags().StringSliceVar(&aggregatorExtraEndpoints, "aggregator-extra-endpoints", nil, "endpoints for extra nodes that are needed in signature aggregation")
@@ -68,8 +83,11 @@ these prompts by providing the values with flags.`,
 	privateKeyFlags.AddToCmd(cmd, "to pay fees for completing the validator's removal (blockchain gas token)")
 	cmd.Flags().StringVar(&rpcURL, "rpc", "", "connect to validator manager at the given rpc endpoint")
 	cmd.Flags().StringVar(&aggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	interchain.AddAggregationFlagsToCmd(cmd, &aggregationFlags)
 	cmd.Flags().Uint64Var(&uptimeSec, "uptime", 0, "validator's uptime in seconds. If not provided, it will be automatically calculated")
-	cmd.Flags().BoolVar(&force, "force", false, "force validator removal even if it's not getting rewarded")
+	cmd.Flags().BoolVar(&force, "force", false, "force validator removal even if it's not getting rewarded or it compromises the L1 safety")
+	cmd.Flags().BoolVar(&simulate, "simulate", false, "(for L1s only) "+simulateFlagDescription)
+	cmd.Flags().StringVar(&refundAddress, "refund-address", "", "(for L1s only) P-Chain address to move the validator remaining balance to, once refunded to its remaining balance owner")
 	return cmd
 }
 
@@ -102,22 +120,36 @@ func removeValidator(_ *cobra.Command, args []string) error {
 		network = models.ConvertClusterToNetwork(network)
 	}
 	fee := network.GenesisParams().TxFeeConfig.StaticFeeConfig.TxFee
-	kc, err := keychain.GetKeychainFromCmdLineFlags(
-		app,
-		"to pay for transaction fees on P-Chain",
-		network,
-		keyName,
-		useEwoq,
-		useLedger,
-		ledgerAddresses,
-		fee,
-	)
+	var kc *keychain.Keychain
+	if len(custodyAddresses) > 0 {
+		if sc.Sovereign {
+			return fmt.Errorf("--custody-addrs is only supported for non-SOV (Subnet-Only Validators) blockchains")
+		}
+		if keyName != "" || useEwoq || useLedger || len(ledgerAddresses) > 0 {
+			return keychain.ErrCustodyWithKeySource
+		}
+		kc, err = keychain.GetCustodyKeychain(network, custodyAddresses)
+	} else {
+		kc, err = keychain.GetKeychainFromCmdLineFlags(
+			app,
+			"to pay for transaction fees on P-Chain",
+			network,
+			keyName,
+			useEwoq,
+			useLedger,
+			ledgerAddresses,
+			fee,
+		)
+	}
 	if err != nil {
 		return err
 	}
 	network.HandlePublicNetworkSimulation()
 
 	if !sc.Sovereign {
+		if simulate {
+			return errors.New("--simulate flag cannot be used for non-SOV (Subnet-Only Validators) blockchains")
+		}
 		if outputTxPath != "" {
 			return errors.New("--output-tx-path flag cannot be used for non-SOV (Subnet-Only Validators) blockchains")
 		}
@@ -125,6 +157,14 @@ func removeValidator(_ *cobra.Command, args []string) error {
 		if len(subnetAuthKeys) > 0 {
 			return errors.New("--subnetAuthKeys flag cannot be used for non-SOV (Subnet-Only Validators) blockchains")
 		}
+		if refundAddress != "" {
+			return errors.New("--refund-address flag cannot be used for non-SOV (Subnet-Only Validators) blockchains")
+		}
+	}
+	if refundAddress != "" {
+		if _, err := address.ParseToID(refundAddress); err != nil {
+			return fmt.Errorf("invalid refund address %s: %w", refundAddress, err)
+		}
 	}
 	if outputTxPath != "" {
 		if _, err := os.Stat(outputTxPath); err == nil {
@@ -191,6 +231,15 @@ func removeValidator(_ *cobra.Command, args []string) error {
 	); err != nil {
 		return err
 	}
+	if simulate {
+		return nil
+	}
+	// reload, as the removal records the validator refund
+	sc, err = app.LoadSidecar(blockchainName)
+	if err != nil {
+		return err
+	}
+	scNetwork = sc.Networks[network.Name()]
 	// remove the validator from the list of bootstrap validators
 	newBootstrapValidators := utils.Filter(scNetwork.BootstrapValidators, func(b models.SubnetValidator) bool {
 		if id, _ := ids.NodeIDFromString(b.NodeID); id != nodeID {
@@ -261,6 +310,9 @@ func removeValidatorSOV(
 		}
 	}
 	ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), rpcURL)
+	if err := checkValidatorRemovalSafety(rpcURL, nodeID, force); err != nil {
+		return err
+	}
 	clusterName := sc.Networks[network.Name()].ClusterName
 	extraAggregatorPeers, err := GetAggregatorExtraPeers(clusterName, aggregatorExtraEndpoints)
 	if err != nil {
@@ -270,10 +322,29 @@ func removeValidatorSOV(
 		ux.Logger.PrintToUser(logging.Yellow.Wrap("Forcing removal of %s as it is a PoS bootstrap validator"), nodeID)
 	}
 
+	if simulate {
+		report, err := validatormanager.SimulateValidatorRemoval(
+			rpcURL,
+			ownerPrivateKey,
+			nodeID,
+			sc.PoS(),
+			isBootstrapValidator || force,
+		)
+		if err != nil {
+			return err
+		}
+		printSimulationReport(report)
+		return nil
+	}
+
 	var (
 		signedMessage *warp.Message
 		validationID  ids.ID
 	)
+	aggregationConfig, err := interchain.GetAggregationConfig(app, aggregationFlags)
+	if err != nil {
+		return err
+	}
 	// try to remove the validator. If err is "delegator ineligible for rewards" confirm with user and force remove
 	signedMessage, validationID, err = validatormanager.InitValidatorRemoval(
 		app,
@@ -285,6 +356,7 @@ func removeValidatorSOV(
 		extraAggregatorPeers,
 		aggregatorAllowPrivatePeers,
 		aggregatorLogLevel,
+		aggregationConfig,
 		sc.PoS(),
 		uptimeSec,
 		isBootstrapValidator || force,
@@ -308,6 +380,7 @@ func removeValidatorSOV(
 			extraAggregatorPeers,
 			aggregatorAllowPrivatePeers,
 			aggregatorLogLevel,
+			aggregationConfig,
 			sc.PoS(),
 			uptimeSec,
 			true, // force
@@ -320,11 +393,24 @@ func removeValidatorSOV(
 	}
 
 	ux.Logger.PrintToUser("ValidationID: %s", validationID)
-	txID, _, err := deployer.SetL1ValidatorWeight(signedMessage)
+	// the P-Chain refunds the validator remaining balance on removal
+	l1Validator, l1ValidatorErr := txutils.GetL1Validator(network, validationID)
+	if l1ValidatorErr == nil && l1Validator.Balance > 0 {
+		ux.Logger.PrintToUser("Remaining validator balance to refund: %.9f AVAX", float64(l1Validator.Balance)/float64(units.Avax))
+	}
+	txID, tx, err := deployer.SetL1ValidatorWeight(signedMessage)
 	if err != nil {
 		return err
 	}
 	ux.Logger.PrintToUser("SetL1ValidatorWeightTx ID: %s", txID)
+	var removed *models.RemovedValidator
+	if l1ValidatorErr == nil {
+		r, err := trackValidatorRefund(network, nodeID, validationID, l1Validator, tx)
+		if err != nil {
+			ux.Logger.RedXToUser("%s", err)
+		}
+		removed = &r
+	}
 
 	if err := UpdatePChainHeight(
 		"Waiting for P-Chain to update validator information ...",
@@ -342,11 +428,65 @@ func removeValidatorSOV(
 		extraAggregatorPeers,
 		aggregatorAllowPrivatePeers,
 		aggregatorLogLevel,
+		aggregationConfig,
 	); err != nil {
 		return err
 	}
 	ux.Logger.GreenCheckmarkToUser("Validator successfully removed from the Subnet")
+	if removed != nil {
+		if refundAddress != "" && removed.Refund > 0 {
+			// the validator is already removed, so a failed claim is reported without failing
+			if err := ClaimValidatorRefund(network, removed, refundAddress); err != nil {
+				ux.Logger.RedXToUser("failure claiming the validator refund: %s. Retry with avalanche validator refund", err)
+			}
+		}
+		if err := recordRemovedValidator(blockchainName, network, *removed); err != nil {
+			return err
+		}
+	}
+	events.Emit(app, events.ValidatorRemoved, fmt.Sprintf("Validator %s removed from %s on %s", nodeID, blockchainName, network.Name()), map[string]interface{}{
+		"blockchain":   blockchainName,
+		"network":      network.Name(),
+		"nodeID":       nodeID.String(),
+		"validationID": validationID.String(),
+	})
+
+	return nil
+}
 
+// checkValidatorRemovalSafety analyzes the L1 validator set resulting from removing [nodeID],
+// asking the user for confirmation if the removal compromises the L1 safety and [force] is not set
+func checkValidatorRemovalSafety(rpcURL string, nodeID ids.NodeID, force bool) error {
+	validators, err := utils.GetL1Validators(rpcURL)
+	if err != nil {
+		return fmt.Errorf("failure getting L1 validators to analyze the removal safety: %w", err)
+	}
+	thresholds := validatormanager.DefaultRemovalSafetyThresholds
+	if app.Conf.ConfigValueIsSet(constants.ConfigL1MinValidatorsKey) {
+		thresholds.MinValidators = app.Conf.GetConfigIntValue(constants.ConfigL1MinValidatorsKey)
+	}
+	report, err := validatormanager.AnalyzeValidatorRemoval(validators, nodeID, thresholds)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("After the removal, the L1 would have %d active validators with a total weight of %d", report.RemainingValidators, report.RemainingWeight)
+	if report.IsSafe() {
+		return nil
+	}
+	for _, warning := range report.Warnings {
+		ux.Logger.RedXToUser("%s", warning)
+	}
+	if force {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Forcing removal of %s"), nodeID)
+		return nil
+	}
+	confirmed, err := app.Prompt.CaptureNoYes("Removing this validator can halt the L1. Do you want to continue with validator removal?")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return fmt.Errorf("removal of validator %s compromises the L1 safety. Use --force flag to force removal", nodeID)
+	}
 	return nil
 }
 
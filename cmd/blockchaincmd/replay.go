@@ -0,0 +1,247 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/replay"
+	"github.com/ava-labs/avalanche-cli/pkg/scratchevm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+const replayChainPrefix = "replay-"
+
+var (
+	replayTransactionsPath   string
+	replayGoldenPath         string
+	replayUpdateGolden       bool
+	replaySkipStateRoots     bool
+	replayAvalancheGoVersion string
+	replayHTTPPort           uint32
+	replayKeepChain          bool
+)
+
+// avalanche blockchain replay
+func newReplayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay [blockchainName]",
+		Short: "Replay a recorded transaction set on an ephemeral instance of the blockchain",
+		Long: `The blockchain replay command starts an ephemeral single node instance of the blockchain, from
+its genesis and VM, replays a recorded transaction set on it, and compares the receipts and state
+roots obtained against golden outputs. It gives VM and precompile developers a regression harness:
+a change of behavior on the VM shows as a mismatch, and the command fails.
+
+The transaction set is a JSON file with the signed transactions to replay, in order:
+
+  {"transactions": [{"description": "deploy token", "rawTx": "0x02f8..."}]}
+
+Raw transactions can be obtained from any chain with eth_getRawTransactionByHash. Each transaction
+is issued after the previous one is accepted, so it gets its own block.
+
+Golden outputs are read from <transactions>.golden.json by default. Use --update-golden to record
+them from the replay instead of comparing. State roots only reproduce if block timestamps don't
+affect the state, and if fees are fixed (legacy transactions, or max fee equal to the tip). Use
+--skip-state-roots otherwise.`,
+		RunE: replayBlockchain,
+		Args: cobrautils.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&replayTransactionsPath, "transactions", "", "file with the transaction set to replay")
+	cmd.Flags().StringVar(&replayGoldenPath, "golden", "", "file with the golden outputs (default: <transactions>.golden.json)")
+	cmd.Flags().BoolVar(&replayUpdateGolden, "update-golden", false, "write the golden outputs from the replay instead of comparing against them")
+	cmd.Flags().BoolVar(&replaySkipStateRoots, "skip-state-roots", false, "do not compare state roots")
+	cmd.Flags().StringVar(&replayAvalancheGoVersion, "avalanchego-version", "", "use this version of avalanchego (defaults to the latest one compatible with the VM)")
+	cmd.Flags().Uint32Var(&replayHTTPPort, "http-port", 9680, "http port of the ephemeral node. the staking port is the next one")
+	cmd.Flags().BoolVar(&replayKeepChain, "keep-chain", false, "keep the ephemeral chain files after the replay, to inspect the node logs")
+	return cmd
+}
+
+func replayBlockchain(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	if replayTransactionsPath == "" {
+		return fmt.Errorf("--transactions is required")
+	}
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return err
+	}
+	if isEVM, _, err := app.HasSubnetEVMGenesis(blockchainName); err != nil {
+		return err
+	} else if !isEVM {
+		return fmt.Errorf("replay only supports blockchains with a Subnet-EVM compatible genesis")
+	}
+	transactionsPath := utils.ExpandHome(replayTransactionsPath)
+	set, err := replay.LoadTransactionSet(transactionsPath)
+	if err != nil {
+		return err
+	}
+	goldenPath := utils.ExpandHome(replayGoldenPath)
+	if goldenPath == "" {
+		goldenPath = replay.GoldenPath(transactionsPath)
+	}
+	var golden replay.Golden
+	if !replayUpdateGolden {
+		if !utils.FileExists(goldenPath) {
+			return fmt.Errorf("golden outputs %s not found. Record them with --update-golden", goldenPath)
+		}
+		golden, err = replay.LoadGolden(goldenPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	chain, avalancheGoBinPath, err := setupReplayChain(sc)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Starting ephemeral node of %s (avalanchego %s)", blockchainName, chain.AvalancheGoVersion)
+	node, err := scratchevm.Launch(app, &chain, avalancheGoBinPath)
+	if err != nil {
+		return err
+	}
+	results, err := runReplay(chain, set)
+	if stopErr := node.Stop(); stopErr != nil {
+		ux.Logger.RedXToUser("failure stopping ephemeral node: %s", stopErr)
+	}
+	if err != nil {
+		return fmt.Errorf("%w. See %s", err, scratchevm.LogPath(app, chain))
+	}
+	if replayKeepChain {
+		ux.Logger.PrintToUser("Ephemeral chain files kept at %s", app.GetScratchChainDir(chain.Name))
+	} else if err := os.RemoveAll(app.GetScratchChainDir(chain.Name)); err != nil {
+		return err
+	}
+
+	printReplayResults(set, results)
+	if replayUpdateGolden {
+		if err := replay.WriteGolden(goldenPath, replay.Golden{Results: results}); err != nil {
+			return err
+		}
+		ux.Logger.GreenCheckmarkToUser("Golden outputs written to %s", goldenPath)
+		return nil
+	}
+	mismatches := replay.Compare(golden.Results, results, !replaySkipStateRoots)
+	if len(mismatches) > 0 {
+		printReplayMismatches(set, mismatches)
+		return fmt.Errorf("replay of %s does not match the golden outputs %s", filepath.Base(transactionsPath), goldenPath)
+	}
+	ux.Logger.GreenCheckmarkToUser("Replay of %d transactions matches the golden outputs", len(results))
+	return nil
+}
+
+// setupReplayChain defines a fresh scratch chain with the genesis and VM of [sc], and
+// returns it together with the avalanchego binary to run it with
+func setupReplayChain(sc models.Sidecar) (models.ScratchChain, string, error) {
+	avagoVersion := replayAvalancheGoVersion
+	if avagoVersion == "" {
+		if sc.RPCVersion == 0 {
+			return models.ScratchChain{}, "", fmt.Errorf("RPC version of %s unknown. Use --avalanchego-version", sc.Name)
+		}
+		var err error
+		avagoVersion, err = vm.GetLatestAvalancheGoByProtocolVersion(
+			app,
+			sc.RPCVersion,
+			constants.AvalancheGoCompatibilityURL,
+		)
+		if err != nil {
+			return models.ScratchChain{}, "", err
+		}
+	}
+	chainName := replayChainPrefix + sc.Name
+	if len(chainName) > 32 {
+		chainName = chainName[:32]
+	}
+	chain := models.ScratchChain{
+		Name:               chainName,
+		VMVersion:          sc.VMVersion,
+		AvalancheGoVersion: avagoVersion,
+		TokenSymbol:        sc.TokenSymbol,
+		HTTPPort:           replayHTTPPort,
+	}
+	if err := os.RemoveAll(app.GetScratchChainDir(chain.Name)); err != nil {
+		return models.ScratchChain{}, "", err
+	}
+	genesisBytes, err := app.LoadRawGenesis(sc.Name)
+	if err != nil {
+		return models.ScratchChain{}, "", err
+	}
+	if err := app.WriteScratchChain(chain); err != nil {
+		return models.ScratchChain{}, "", err
+	}
+	if err := os.WriteFile(app.GetScratchChainGenesisPath(chain.Name), genesisBytes, constants.WriteReadReadPerms); err != nil {
+		return models.ScratchChain{}, "", err
+	}
+	var vmBinPath string
+	switch sc.VM {
+	case models.SubnetEvm:
+		if _, vmBinPath, err = binutils.SetupSubnetEVM(app, sc.VMVersion); err != nil {
+			return models.ScratchChain{}, "", fmt.Errorf("failed to install subnet-evm: %w", err)
+		}
+	default:
+		vmBinPath = app.GetCustomVMPath(sc.Name)
+		if !utils.FileExists(vmBinPath) {
+			return models.ScratchChain{}, "", fmt.Errorf("VM binary of %s not found at %s", sc.Name, vmBinPath)
+		}
+	}
+	if err := scratchevm.InstallVM(app, chain, vmBinPath); err != nil {
+		return models.ScratchChain{}, "", err
+	}
+	_, avagoDir, err := binutils.SetupAvalanchego(app, avagoVersion)
+	if err != nil {
+		return models.ScratchChain{}, "", fmt.Errorf("failed to install avalanchego: %w", err)
+	}
+	return chain, filepath.Join(avagoDir, "avalanchego"), nil
+}
+
+func runReplay(chain models.ScratchChain, set replay.TransactionSet) ([]replay.Result, error) {
+	client, err := evm.GetClient(chain.RPCURL())
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	ux.Logger.PrintToUser("Replaying %d transactions", len(set.Transactions))
+	return replay.Replay(client, set)
+}
+
+func printReplayResults(set replay.TransactionSet, results []replay.Result) {
+	t := ux.DefaultTable("Replay Results", table.Row{"#", "Description", "Tx Hash", "Status", "Gas Used", "State Root"})
+	for i, result := range results {
+		status := "failed"
+		switch {
+		case result.Error != "":
+			status = result.Error
+		case result.Status == 1:
+			status = "success"
+		}
+		stateRoot := ""
+		if result.Error == "" {
+			stateRoot = result.StateRoot.Hex()
+		}
+		t.AppendRow(table.Row{i + 1, set.Transactions[i].Description, result.TxHash.Hex(), status, result.GasUsed, stateRoot})
+	}
+	ux.Logger.PrintToUser(t.Render())
+}
+
+func printReplayMismatches(set replay.TransactionSet, mismatches []replay.Mismatch) {
+	t := ux.DefaultTable("Mismatches", table.Row{"#", "Description", "Field", "Golden", "Replay"})
+	for _, mismatch := range mismatches {
+		index, description := "-", ""
+		if mismatch.Index >= 0 {
+			index = fmt.Sprint(mismatch.Index + 1)
+			description = set.Transactions[mismatch.Index].Description
+		}
+		t.AppendRow(table.Row{index, description, mismatch.Field, mismatch.Expected, mismatch.Actual})
+	}
+	ux.Logger.PrintToUser(t.Render())
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/olekukonko/tablewriter"
+)
+
+var simulate bool
+
+const simulateFlagDescription = "execute the validator manager transactions on top of the current L1 state and report the outcome, without touching the real network"
+
+func printSimulationReport(report *validatormanager.SimulationReport) {
+	ux.Logger.PrintLineSeparator()
+	ux.Logger.PrintToUser("Simulated call (gas used %d)", report.GasUsed)
+	ux.Logger.PrintToUser("Events:")
+	for _, event := range report.Events {
+		switch {
+		case event.ValidationID == ids.Empty:
+			ux.Logger.PrintToUser("  %s", event.Name)
+		case event.Weight == 0:
+			ux.Logger.PrintToUser("  %s validationID=%s", event.Name, event.ValidationID)
+		default:
+			ux.Logger.PrintToUser("  %s validationID=%s weight=%d", event.Name, event.ValidationID, event.Weight)
+		}
+	}
+	ux.Logger.PrintToUser("Resulting validator set:")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"NodeID", "ValidationID", "Weight", "Status"})
+	for _, validator := range report.Validators {
+		table.Append([]string{
+			validator.NodeID.String(),
+			validator.ValidationID.String(),
+			fmt.Sprintf("%d", validator.Weight),
+			validator.Status,
+		})
+	}
+	table.Render()
+	ux.Logger.PrintToUser("Not simulated:")
+	for _, step := range report.SkippedSteps {
+		ux.Logger.PrintToUser("  %s", step)
+	}
+	ux.Logger.GreenCheckmarkToUser("Simulation finished. No transaction was issued on the real network")
+}
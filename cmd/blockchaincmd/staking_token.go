@@ -0,0 +1,88 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var errMutuallyExclusiveStakingTokenFlags = errors.New("--staking-token-address and --deploy-staking-token are mutually exclusive")
+
+// SetupStakingToken returns the ERC20 token to be staked on the validator manager of the
+// blockchain of [sc] on [network], and saves it on the sidecar. The token is the one given
+// by [tokenAddress], a new example token deployed with [privateKey] if [deployToken] is set,
+// or the one set on a previous attempt. Prompts for it otherwise
+func SetupStakingToken(
+	sc *models.Sidecar,
+	network models.Network,
+	rpcURL string,
+	privateKey string,
+	tokenAddress string,
+	deployToken bool,
+) (string, error) {
+	if tokenAddress != "" && deployToken {
+		return "", errMutuallyExclusiveStakingTokenFlags
+	}
+	networkData := sc.Networks[network.Name()]
+	if tokenAddress == "" && !deployToken {
+		if networkData.StakingTokenAddress != "" {
+			ux.Logger.PrintToUser("Using staking token %s", networkData.StakingTokenAddress)
+			return networkData.StakingTokenAddress, nil
+		}
+		deployOption := "Deploy a new mintable example token (local and devnet only)"
+		existingOption := "Use an existing ERC20 token"
+		options := []string{existingOption}
+		if canDeployExampleStakingToken(network) {
+			options = []string{deployOption, existingOption}
+		}
+		option, err := app.Prompt.CaptureList("Which ERC20 token will validators and delegators stake?", options)
+		if err != nil {
+			return "", err
+		}
+		if option == deployOption {
+			deployToken = true
+		} else {
+			address, err := app.Prompt.CaptureAddress("Enter the address of the staking token")
+			if err != nil {
+				return "", err
+			}
+			tokenAddress = address.Hex()
+		}
+	}
+	if deployToken {
+		if !canDeployExampleStakingToken(network) {
+			return "", fmt.Errorf("the example staking token can be minted by anyone, so it can't be deployed on %s", network.Name())
+		}
+		ux.Logger.PrintToUser("Deploying example staking token ...")
+		address, err := validatormanager.DeployExampleStakingToken(rpcURL, privateKey)
+		if err != nil {
+			return "", err
+		}
+		tokenAddress = address.Hex()
+		ux.Logger.GreenCheckmarkToUser("Example staking token deployed at %s", tokenAddress)
+		ux.Logger.PrintToUser("The deployer key holds its initial supply. Anyone can mint it, so use it for testing only")
+	} else {
+		if !common.IsHexAddress(tokenAddress) {
+			return "", fmt.Errorf("invalid staking token address %q", tokenAddress)
+		}
+		if err := validatormanager.CheckStakingTokenContract(rpcURL, common.HexToAddress(tokenAddress)); err != nil {
+			return "", err
+		}
+		ux.Logger.PrintToUser("The staking token must let the validator manager mint rewards through mint(address,uint256)")
+	}
+	networkData.StakingTokenAddress = tokenAddress
+	sc.Networks[network.Name()] = networkData
+	return tokenAddress, app.UpdateSidecar(sc)
+}
+
+// canDeployExampleStakingToken returns true if the example staking token, that anyone can
+// mint, can be deployed on [network]: only on local and devnet networks
+func canDeployExampleStakingToken(network models.Network) bool {
+	return network.Kind == models.Local || network.Kind == models.Devnet
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var (
+	updateConfigSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Cluster,
+	}
+	updateConfigSkipRestart bool
+)
+
+// avalanche blockchain updateConfig
+func newUpdateConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "updateConfig [blockchainName]",
+		Short: "Push the blockchain configs to the nodes of a cluster",
+		Long: `The blockchain updateConfig command uploads the node config, subnet config, chain config
+and per node chain configs currently set for the blockchain (see blockchain configure) to all the
+nodes of the given cluster.
+
+Files are only uploaded to the nodes where they differ from the current ones. As AvalancheGo reads
+these files at startup, only the avalanchego service of the nodes whose files changed is restarted.
+Per node chain configs are matched against the node cloud ID or IP.`,
+		RunE: updateConfig,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, updateConfigSupportedNetworkOptions)
+	cmd.Flags().BoolVar(&updateConfigSkipRestart, "skip-restart", false, "upload the configs without restarting the nodes")
+	return cmd
+}
+
+func updateConfig(_ *cobra.Command, args []string) error {
+	chains, err := ValidateSubnetNameAndGetChains(args)
+	if err != nil {
+		return err
+	}
+	blockchainName := chains[0]
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		false,
+		false,
+		updateConfigSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName == "" {
+		return fmt.Errorf("a cluster must be specified with --cluster")
+	}
+	ux.Logger.PrintToUser("Updating configs of blockchain %s on cluster %s...", blockchainName, network.ClusterName)
+	results, err := node.UpdateBlockchainConfigs(app, network.ClusterName, blockchainName, updateConfigSkipRestart)
+	if err != nil {
+		return err
+	}
+	nodeResults := results.GetResults()
+	sort.Slice(nodeResults, func(i, j int) bool { return nodeResults[i].NodeID < nodeResults[j].NodeID })
+	header := table.Row{"Cloud ID", "Configs", "Restart", "Status"}
+	t := ux.DefaultTable(fmt.Sprintf("%s configs update", blockchainName), header)
+	for _, result := range nodeResults {
+		update := result.Value.(node.BlockchainConfigUpdate)
+		configs := "unchanged"
+		if update.Changed {
+			configs = "updated"
+		}
+		restart := "not needed"
+		switch {
+		case update.Restarted:
+			restart = "restarted"
+		case update.Changed && updateConfigSkipRestart:
+			restart = "skipped"
+		case update.Changed:
+			restart = "failed"
+		}
+		status := logging.Green.Wrap("OK")
+		if result.Err != nil {
+			status = logging.Red.Wrap(result.Err.Error())
+		}
+		t.AppendRow(table.Row{result.NodeID, configs, restart, status})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	if results.HasErrors() {
+		return fmt.Errorf("failed to update configs for node(s) %s", results.GetErrorHosts())
+	}
+	if updateConfigSkipRestart {
+		ux.Logger.PrintToUser("Restart avalanchego on the updated nodes for the changes to take effect")
+	}
+	return nil
+}
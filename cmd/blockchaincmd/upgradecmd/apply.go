@@ -16,6 +16,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/events"
 	"github.com/ava-labs/avalanche-cli/pkg/localnet"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
@@ -71,7 +72,12 @@ to upgrade your node manually.
 
 After you update your validator's configuration, you need to restart your validator manually.
 If you provide the --avalanchego-chain-config-dir flag, this command attempts to write the upgrade file at that path.
-Refer to https://docs.avax.network/nodes/maintain/chain-config-flags#subnet-chain-configs for related documentation.`,
+Refer to https://docs.avax.network/nodes/maintain/chain-config-flags#subnet-chain-configs for related documentation.
+
+Before applying, the command checks the upgrade path against the nodes of the network: that their VM
+version supports the precompiles of the upgrade, that moving them to the configured VM version needs no
+pending migration, that their avalanchego RPC protocol is compatible, and that new activations leave
+enough time (--min-lead-time) for all validators to install the upgrade. It aborts with a report otherwise.`,
 		RunE: applyCmd,
 		Args: cobrautils.ExactArgs(1),
 	}
@@ -84,6 +90,8 @@ Refer to https://docs.avax.network/nodes/maintain/chain-config-flags#subnet-chai
 	cmd.Flags().BoolVar(&print, "print", false, "if true, print the manual config without prompting (for public networks only)")
 	cmd.Flags().BoolVar(&force, "force", false, "If true, don't prompt for confirmation of timestamps in the past")
 	cmd.Flags().StringVar(&avalanchegoChainConfigDir, avalanchegoChainConfigFlag, os.ExpandEnv(avalanchegoChainConfigDirDefault), "avalanchego's chain config file directory")
+	cmd.Flags().DurationVar(&minLeadTime, "min-lead-time", 0, fmt.Sprintf("minimum time between now and new upgrade activations (default %s for public networks, none for local)", defaultPublicUpgradeLeadTime))
+	cmd.Flags().BoolVar(&skipPathChecks, "skip-path-checks", false, "apply the upgrade even if the upgrade path checks fail")
 
 	return cmd
 }
@@ -105,16 +113,32 @@ func applyCmd(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	var networkKey string
 	switch networkToUpgrade {
 	// update a locally running network
 	case localDeployment:
-		return applyLocalNetworkUpgrade(blockchainName, models.Local.String(), &sc)
+		networkKey = models.Local.String()
+		err = applyLocalNetworkUpgrade(blockchainName, networkKey, &sc)
 	case fujiDeployment:
-		return applyPublicNetworkUpgrade(blockchainName, models.Fuji.String(), &sc)
+		networkKey = models.Fuji.String()
+		err = applyPublicNetworkUpgrade(blockchainName, networkKey, &sc)
 	case mainnetDeployment:
-		return applyPublicNetworkUpgrade(blockchainName, models.Mainnet.String(), &sc)
+		networkKey = models.Mainnet.String()
+		err = applyPublicNetworkUpgrade(blockchainName, networkKey, &sc)
+	default:
+		return nil
 	}
-
+	if err != nil {
+		return err
+	}
+	if print && networkToUpgrade != localDeployment {
+		// public network upgrades are only printed with --print, not applied
+		return nil
+	}
+	events.Emit(app, events.UpgradeApplied, fmt.Sprintf("Upgrade applied to %s on %s", blockchainName, networkKey), map[string]interface{}{
+		"blockchain": blockchainName,
+		"network":    networkKey,
+	})
 	return nil
 }
 
@@ -172,6 +196,14 @@ func applyLocalNetworkUpgrade(blockchainName, networkKey string, sc *models.Side
 		return subnetNotYetDeployed()
 	}
 
+	lockUpgradeBytes, err := app.ReadLockUpgradeFile(blockchainName)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := checkUpgradePath(*sc, networkKey, []byte(strNetUpgrades), lockUpgradeBytes); err != nil {
+		return err
+	}
+
 	// get the blockchainID from the sidecar
 	blockchainID := sc.Networks[networkKey].BlockchainID
 	if blockchainID == ids.Empty {
@@ -265,7 +297,7 @@ func applyPublicNetworkUpgrade(blockchainName, networkKey string, sc *models.Sid
 		ux.Logger.PrintToUser("   If you are using a different chain config dir for your node, use that one.")
 		ux.Logger.PrintToUser("2. Create a directory with the blockchainID in the configured chain-config-dir (e.g. $HOME/.avalanchego/chains/%s) if doesn't already exist.", blockchainIDstr)
 		ux.Logger.PrintToUser("3. Create an `upgrade.json` file in the blockchain directory with the content of your upgrade file.")
-		upgr, err := app.ReadUpgradeFile(blockchainName)
+		upgr, err := os.ReadFile(app.GetNetworkUpgradeSetFilePath(*sc, networkKey))
 		if err == nil {
 			var prettyJSON bytes.Buffer
 			if err := json.Indent(&prettyJSON, upgr, "", "    "); err == nil {
@@ -282,10 +314,14 @@ func applyPublicNetworkUpgrade(blockchainName, networkKey string, sc *models.Sid
 		ux.Logger.PrintToUser("   *************************************************************************************************************")
 		return nil
 	}
-	_, _, err := validateUpgrade(blockchainName, networkKey, sc, force)
+	_, strNetUpgrades, err := validateUpgrade(blockchainName, networkKey, sc, force)
 	if err != nil {
 		return err
 	}
+	// the lock file is not used on public networks
+	if err := checkUpgradePath(*sc, networkKey, []byte(strNetUpgrades), nil); err != nil {
+		return err
+	}
 
 	ux.Logger.PrintToUser("The chain config dir avalanchego uses is set at %s", avalanchegoChainConfigDir)
 	// give the user the chance to check if they indeed want to use the default
@@ -310,7 +346,7 @@ func applyPublicNetworkUpgrade(blockchainName, networkKey string, sc *models.Sid
 		return fmt.Errorf("failed to create blockchain directory: %w", err)
 	}
 
-	if err := binutils.CopyFile(app.GetUpgradeBytesFilePath(blockchainName), destPath); err != nil {
+	if err := binutils.CopyFile(app.GetNetworkUpgradeSetFilePath(*sc, networkKey), destPath); err != nil {
 		return fmt.Errorf("failed to install the upgrades path at the provided destination: %w", err)
 	}
 	ux.Logger.PrintToUser("Successfully installed upgrade file")
@@ -327,7 +363,11 @@ func validateUpgrade(blockchainName, networkKey string, sc *models.Sidecar, skip
 		return nil, "", errors.New(ErrSubnetNotDeployedOutput)
 	}
 	// let's check update bytes actually exist
-	netUpgradeBytes, err := app.ReadUpgradeFile(blockchainName)
+	upgradeSet := sc.UpgradeSets[networkKey]
+	if upgradeSet != "" {
+		ux.Logger.PrintToUser("Using upgrade set %q selected for %s", upgradeSet, networkKey)
+	}
+	netUpgradeBytes, err := app.ReadUpgradeSetFile(blockchainName, upgradeSet)
 	if err != nil {
 		if err == os.ErrNotExist {
 			ux.Logger.PrintToUser("No file with upgrade specs for the given blockchain has been found")
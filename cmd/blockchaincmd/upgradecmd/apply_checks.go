@@ -0,0 +1,357 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/exp/maps"
+	"golang.org/x/mod/semver"
+)
+
+// default minimum time between applying an upgrade file and its activations, so that all
+// validators of a public network have time to install it
+const defaultPublicUpgradeLeadTime = time.Hour
+
+var (
+	minLeadTime    time.Duration
+	skipPathChecks bool
+
+	errUpgradePathChecksFailed = errors.New("upgrade path checks failed")
+)
+
+// nodeVMVersion holds the versions reported by a node of the network to upgrade
+type nodeVMVersion struct {
+	NodeID      string
+	AvalancheGo string
+	RPCVersion  uint32
+	// empty if the node does not report a version for the blockchain VM
+	VMVersion string
+}
+
+// upgradePathCheck is the result of one of the checks run before applying an upgrade file
+type upgradePathCheck struct {
+	Name    string
+	Passed  bool
+	Skipped bool
+	Details []string
+}
+
+// checkUpgradePath runs the upgrade path checks of [upgradeBytes] against the nodes of
+// [networkKey], prints a report, and fails if any of them did not pass
+func checkUpgradePath(sc models.Sidecar, networkKey string, upgradeBytes []byte, lockBytes []byte) error {
+	leadTime := minLeadTime
+	if leadTime == 0 && networkKey != models.Local.String() {
+		leadTime = defaultPublicUpgradeLeadTime
+	}
+	nodeVersions, source, err := getNetworkNodeVersions(sc, networkKey)
+	if err != nil {
+		return err
+	}
+	var vmRPCVersions map[string]int
+	if sc.VM == models.SubnetEvm {
+		if vmRPCVersions, err = vm.GetRPCProtocolVersions(app, sc.VM); err != nil {
+			ux.Logger.PrintToUser(logging.Yellow.Wrap("failure obtaining the RPC protocol of %s releases: %s"), sc.VM, err)
+		}
+	}
+	checks, err := getUpgradePathChecks(sc, upgradeBytes, lockBytes, nodeVersions, vmRPCVersions, time.Now(), leadTime)
+	if err != nil {
+		return err
+	}
+	printUpgradePathChecks(checks, source)
+	for _, check := range checks {
+		if !check.Passed && !check.Skipped {
+			if skipPathChecks {
+				ux.Logger.PrintToUser(logging.Yellow.Wrap("Upgrade path checks failed. Continuing as requested by --skip-path-checks"))
+				return nil
+			}
+			ux.Logger.PrintToUser("Aborting this command. No changes applied. Use --skip-path-checks to apply anyway")
+			return errUpgradePathChecksFailed
+		}
+	}
+	return nil
+}
+
+// getUpgradePathChecks checks that:
+// - the VM version run by the nodes supports all precompiles configured by the upgrade
+// - the nodes moving to the VM version configured for the blockchain need no migration, and
+// keep the RPC protocol of their avalanchego, as given by [vmRPCVersions]
+// - the avalanchego RPC protocol of the nodes is the one of the configured VM
+// - new activations are at least [leadTime] after [now]
+// Upgrades already present on [lockBytes] are considered applied
+func getUpgradePathChecks(
+	sc models.Sidecar,
+	upgradeBytes []byte,
+	lockBytes []byte,
+	nodeVersions []nodeVMVersion,
+	vmRPCVersions map[string]int,
+	now time.Time,
+	leadTime time.Duration,
+) ([]upgradePathCheck, error) {
+	checks := []upgradePathCheck{}
+	if sc.VM == models.SubnetEvm {
+		constraint, err := vm.GetSubnetEVMUpgradeConstraint(upgradeBytes)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, checkNodesVMSupportUpgrade(sc, constraint, nodeVersions))
+		checks = append(checks, checkNodesVMTransition(sc, nodeVersions, vmRPCVersions))
+	}
+	checks = append(checks, checkNodesRPCVersion(sc, nodeVersions))
+	leadTimeCheck, err := checkUpgradeLeadTime(upgradeBytes, lockBytes, now, leadTime)
+	if err != nil {
+		return nil, err
+	}
+	return append(checks, leadTimeCheck), nil
+}
+
+func checkNodesVMSupportUpgrade(sc models.Sidecar, constraint models.VMVersionConstraint, nodeVersions []nodeVMVersion) upgradePathCheck {
+	check := upgradePathCheck{Name: "VM supports upgrade", Passed: true}
+	if constraint.MinVersion == "" {
+		check.Details = append(check.Details, "upgrade uses no version dependent precompiles")
+		return check
+	}
+	check.Details = append(check.Details, fmt.Sprintf("%s requires %s %s or later", strings.Join(constraint.Features, ", "), sc.VM, constraint.MinVersion))
+	versions := map[string]string{}
+	for _, nodeVersion := range nodeVersions {
+		if nodeVersion.VMVersion != "" {
+			versions[nodeVersion.NodeID] = nodeVersion.VMVersion
+		}
+	}
+	if len(versions) == 0 {
+		// no info from the nodes, rely on the configured version
+		versions["configured VM"] = sc.VMVersion
+	}
+	nodeIDs := maps.Keys(versions)
+	sort.Strings(nodeIDs)
+	for _, nodeID := range nodeIDs {
+		if err := vm.CheckSubnetEVMVersion(constraint, versions[nodeID]); err != nil {
+			check.Passed = false
+			check.Details = append(check.Details, fmt.Sprintf("%s runs %s", nodeID, versions[nodeID]))
+		}
+	}
+	return check
+}
+
+// checkNodesVMTransition checks that the nodes can move from the VM version they run to the
+// one configured for the blockchain: it is not a downgrade, it needs no database migration,
+// and the new version speaks the RPC protocol of the avalanchego they run, so that the VM
+// can be replaced without also replacing avalanchego
+func checkNodesVMTransition(sc models.Sidecar, nodeVersions []nodeVMVersion, vmRPCVersions map[string]int) upgradePathCheck {
+	check := upgradePathCheck{Name: "VM version transition", Passed: true}
+	if !semver.IsValid(sc.VMVersion) {
+		check.Skipped = true
+		check.Details = append(check.Details, fmt.Sprintf("configured VM version %q is not a release", sc.VMVersion))
+		return check
+	}
+	for _, nodeVersion := range nodeVersions {
+		if nodeVersion.VMVersion == "" || nodeVersion.VMVersion == sc.VMVersion || !semver.IsValid(nodeVersion.VMVersion) {
+			continue
+		}
+		migration, err := vm.GetSubnetEVMMigration(nodeVersion.VMVersion, sc.VMVersion)
+		if err != nil {
+			check.Passed = false
+			check.Details = append(check.Details, fmt.Sprintf("%s: %s", nodeVersion.NodeID, err))
+			continue
+		}
+		if migration.Kind != vm.MigrationNone {
+			check.Passed = false
+			check.Details = append(check.Details, fmt.Sprintf(
+				"%s runs %s: moving to %s requires a %s (%s). Run 'avalanche blockchain upgrade vm' first",
+				nodeVersion.NodeID,
+				nodeVersion.VMVersion,
+				sc.VMVersion,
+				migration.Kind,
+				migration.Version,
+			))
+			continue
+		}
+		if targetRPCVersion, ok := vmRPCVersions[sc.VMVersion]; !ok {
+			check.Details = append(check.Details, fmt.Sprintf("RPC protocol of %s is unknown, its compatibility with %s was not checked", sc.VMVersion, nodeVersion.NodeID))
+		} else if uint32(targetRPCVersion) != nodeVersion.RPCVersion {
+			check.Passed = false
+			check.Details = append(check.Details, fmt.Sprintf(
+				"%s runs %s with RPC protocol %d: %s needs RPC protocol %d, so avalanchego must be upgraded together with the VM",
+				nodeVersion.NodeID,
+				nodeVersion.AvalancheGo,
+				nodeVersion.RPCVersion,
+				sc.VMVersion,
+				targetRPCVersion,
+			))
+			continue
+		}
+		check.Details = append(check.Details, fmt.Sprintf("%s runs %s, can move to %s without migration", nodeVersion.NodeID, nodeVersion.VMVersion, sc.VMVersion))
+	}
+	if len(check.Details) == 0 {
+		check.Details = append(check.Details, fmt.Sprintf("no transition needed from %s", sc.VMVersion))
+	}
+	return check
+}
+
+func checkNodesRPCVersion(sc models.Sidecar, nodeVersions []nodeVMVersion) upgradePathCheck {
+	check := upgradePathCheck{Name: "AvalancheGo compatibility", Passed: true}
+	if len(nodeVersions) == 0 {
+		check.Skipped = true
+		check.Details = append(check.Details, "no nodes could be queried")
+		return check
+	}
+	for _, nodeVersion := range nodeVersions {
+		if nodeVersion.RPCVersion != uint32(sc.RPCVersion) {
+			check.Passed = false
+			check.Details = append(check.Details, fmt.Sprintf(
+				"%s runs %s with RPC protocol %d, %s needs %d",
+				nodeVersion.NodeID,
+				nodeVersion.AvalancheGo,
+				nodeVersion.RPCVersion,
+				sc.VMVersion,
+				sc.RPCVersion,
+			))
+		}
+	}
+	if check.Passed {
+		check.Details = append(check.Details, fmt.Sprintf("all %d nodes use RPC protocol %d", len(nodeVersions), sc.RPCVersion))
+	}
+	return check
+}
+
+func checkUpgradeLeadTime(upgradeBytes []byte, lockBytes []byte, now time.Time, leadTime time.Duration) (upgradePathCheck, error) {
+	check := upgradePathCheck{Name: "Activation lead time", Passed: true}
+	upgrades, err := getAllUpgrades(upgradeBytes)
+	if err != nil {
+		return check, err
+	}
+	applied := map[string]bool{}
+	if len(lockBytes) > 0 {
+		if lockUpgrades, err := getAllUpgrades(lockBytes); err == nil {
+			for _, lockUpgrade := range lockUpgrades {
+				applied[fmt.Sprintf("%s %d", lockUpgrade.Key(), *lockUpgrade.Timestamp())] = true
+			}
+		}
+	}
+	earliest := now.Add(leadTime)
+	for _, upgrade := range upgrades {
+		if upgrade.Timestamp() == nil || applied[fmt.Sprintf("%s %d", upgrade.Key(), *upgrade.Timestamp())] {
+			continue
+		}
+		activation := time.Unix(int64(*upgrade.Timestamp()), 0)
+		// past activations are handled by the timestamp validation of the upgrade file
+		if activation.Before(now) {
+			continue
+		}
+		if activation.Before(earliest) {
+			check.Passed = false
+			check.Details = append(check.Details, fmt.Sprintf(
+				"%s activates at %s, less than %s from now",
+				upgrade.Key(),
+				activation.Local().Format(constants.TimeParseLayout),
+				leadTime,
+			))
+		}
+	}
+	if check.Passed {
+		check.Details = append(check.Details, fmt.Sprintf("all new activations are at least %s from now", leadTime))
+	}
+	return check, nil
+}
+
+func printUpgradePathChecks(checks []upgradePathCheck, source string) {
+	t := ux.DefaultTable("Upgrade Path Checks", table.Row{"Check", "Result", "Details"})
+	for _, check := range checks {
+		result := logging.Green.Wrap("ok")
+		switch {
+		case check.Skipped:
+			result = logging.Yellow.Wrap("skipped")
+		case !check.Passed:
+			result = logging.Red.Wrap("failed")
+		}
+		t.AppendRow(table.Row{check.Name, result, strings.Join(check.Details, "\n")})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	ux.Logger.PrintToUser("Node versions obtained from %s", source)
+}
+
+// getNetworkNodeVersions queries the avalanchego and VM versions of the nodes of the network
+// to upgrade: the local network nodes, the nodes of the cluster the blockchain was deployed
+// with, or the node running on this machine. It also returns a description of the source
+func getNetworkNodeVersions(sc models.Sidecar, networkKey string) ([]nodeVMVersion, string, error) {
+	vmID, err := sc.GetVMID()
+	if err != nil {
+		return nil, "", err
+	}
+	toNodeVMVersion := func(nodeID string, reply info.GetNodeVersionReply) nodeVMVersion {
+		return nodeVMVersion{
+			NodeID:      nodeID,
+			AvalancheGo: reply.Version,
+			RPCVersion:  uint32(reply.RPCProtocolVersion),
+			VMVersion:   reply.VMVersions[vmID],
+		}
+	}
+	if networkKey == models.Local.String() {
+		clusterInfo, err := localnet.GetClusterInfo()
+		if err != nil {
+			return nil, "", err
+		}
+		nodeVersions := []nodeVMVersion{}
+		for _, nodeName := range clusterInfo.NodeNames {
+			reply, err := getNodeVersion(clusterInfo.NodeInfos[nodeName].Uri)
+			if err != nil {
+				return nil, "", err
+			}
+			nodeVersions = append(nodeVersions, toNodeVMVersion(nodeName, reply))
+		}
+		return nodeVersions, "local network nodes", nil
+	}
+	if clusterName := sc.Networks[networkKey].ClusterName; clusterName != "" {
+		hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+		if err != nil {
+			return nil, "", err
+		}
+		defer node.DisconnectHosts(hosts)
+		nodeVersions := []nodeVMVersion{}
+		for _, host := range hosts {
+			resp, err := ssh.RunSSHCheckAvalancheGoVersion(host)
+			if err != nil {
+				return nil, "", fmt.Errorf("failure querying version of node %s: %w", host.GetCloudID(), err)
+			}
+			reply, err := node.ParseNodeVersionOutput(resp)
+			if err != nil {
+				return nil, "", err
+			}
+			nodeVersions = append(nodeVersions, toNodeVMVersion(host.GetCloudID(), reply))
+		}
+		return nodeVersions, fmt.Sprintf("cluster %s nodes", clusterName), nil
+	}
+	// upgrade files for public networks are installed on the node running on this machine
+	reply, err := getNodeVersion(constants.LocalAPIEndpoint)
+	if err != nil {
+		app.Log.Debug("failure querying node at " + constants.LocalAPIEndpoint + ": " + err.Error())
+		return nil, "no node (none reachable at " + constants.LocalAPIEndpoint + ")", nil
+	}
+	return []nodeVMVersion{toNodeVMVersion("local validator", reply)}, "node at " + constants.LocalAPIEndpoint, nil
+}
+
+func getNodeVersion(uri string) (info.GetNodeVersionReply, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	reply, err := info.NewClient(uri).GetNodeVersion(ctx)
+	if err != nil {
+		return info.GetNodeVersionReply{}, err
+	}
+	return *reply, nil
+}
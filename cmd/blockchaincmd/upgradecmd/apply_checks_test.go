@@ -0,0 +1,110 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradePathChecks(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	upgrade := func(timestamps ...int64) []byte {
+		return []byte(fmt.Sprintf(`{"precompileUpgrades":[
+{"feeManagerConfig":{"adminAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"],"blockTimestamp":%d,"initialFeeConfig":{}}},
+{"txAllowListConfig":{"adminAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"],"blockTimestamp":%d}}
+]}`, timestamps[0], timestamps[1]))
+	}
+	sc := models.Sidecar{
+		Name:       "test",
+		VM:         models.SubnetEvm,
+		VMVersion:  "v0.6.12",
+		RPCVersion: 38,
+	}
+	upToDateNodes := []nodeVMVersion{
+		{NodeID: "node1", AvalancheGo: "avalanchego/1.11.13", RPCVersion: 38, VMVersion: "v0.6.12"},
+		{NodeID: "node2", AvalancheGo: "avalanchego/1.11.13", RPCVersion: 38, VMVersion: "v0.6.12"},
+	}
+	vmRPCVersions := map[string]int{"v0.5.2": 26, "v0.6.11": 37, "v0.6.12": 38, "v0.6.13": 38}
+	checksPassed := func(checks []upgradePathCheck) map[string]bool {
+		passed := map[string]bool{}
+		for _, check := range checks {
+			passed[check.Name] = check.Passed
+		}
+		return passed
+	}
+
+	farUpgrade := upgrade(now.Add(2*time.Hour).Unix(), now.Add(3*time.Hour).Unix())
+	checks, err := getUpgradePathChecks(sc, farUpgrade, nil, upToDateNodes, vmRPCVersions, now, time.Hour)
+	require.NoError(err)
+	require.Equal(map[string]bool{
+		"VM supports upgrade":       true,
+		"VM version transition":     true,
+		"AvalancheGo compatibility": true,
+		"Activation lead time":      true,
+	}, checksPassed(checks))
+
+	// an activation too close in the future
+	nearUpgrade := upgrade(now.Add(10*time.Minute).Unix(), now.Add(3*time.Hour).Unix())
+	checks, err = getUpgradePathChecks(sc, nearUpgrade, nil, upToDateNodes, vmRPCVersions, now, time.Hour)
+	require.NoError(err)
+	require.False(checksPassed(checks)["Activation lead time"])
+
+	// the near activation was already applied
+	appliedUpgrade := upgrade(now.Add(10*time.Minute).Unix(), now.Add(-time.Hour).Unix())
+	checks, err = getUpgradePathChecks(sc, nearUpgrade, appliedUpgrade, upToDateNodes, vmRPCVersions, now, time.Hour)
+	require.NoError(err)
+	require.True(checksPassed(checks)["Activation lead time"])
+
+	// nodes running an old VM, an incompatible avalanchego, or a newer VM
+	outdatedNodes := []nodeVMVersion{
+		{NodeID: "node1", AvalancheGo: "avalanchego/1.10.0", RPCVersion: 26, VMVersion: "v0.5.2"},
+		{NodeID: "node2", AvalancheGo: "avalanchego/1.11.13", RPCVersion: 38, VMVersion: "v0.6.13"},
+	}
+	checks, err = getUpgradePathChecks(sc, farUpgrade, nil, outdatedNodes, vmRPCVersions, now, time.Hour)
+	require.NoError(err)
+	passed := checksPassed(checks)
+	require.True(passed["VM supports upgrade"])
+	require.False(passed["VM version transition"])
+	require.False(passed["AvalancheGo compatibility"])
+
+	managerUpgrade := []byte(fmt.Sprintf(`{"precompileUpgrades":[
+{"txAllowListConfig":{"managerAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"],"blockTimestamp":%d}}
+]}`, now.Add(2*time.Hour).Unix()))
+	checks, err = getUpgradePathChecks(sc, managerUpgrade, nil, outdatedNodes, vmRPCVersions, now, time.Hour)
+	require.NoError(err)
+	require.False(checksPassed(checks)["VM supports upgrade"])
+
+	// nodes on an older VM release that speaks their avalanchego RPC protocol can move to
+	// the configured one, but not if the configured one needs a newer avalanchego
+	oldVMNodes := []nodeVMVersion{
+		{NodeID: "node1", AvalancheGo: "avalanchego/1.11.13", RPCVersion: 38, VMVersion: "v0.6.11"},
+	}
+	checks, err = getUpgradePathChecks(sc, farUpgrade, nil, oldVMNodes, vmRPCVersions, now, time.Hour)
+	require.NoError(err)
+	require.True(checksPassed(checks)["VM version transition"])
+	oldVMNodes[0].RPCVersion = 37
+	oldVMNodes[0].AvalancheGo = "avalanchego/1.11.11"
+	checks, err = getUpgradePathChecks(sc, farUpgrade, nil, oldVMNodes, vmRPCVersions, now, time.Hour)
+	require.NoError(err)
+	require.False(checksPassed(checks)["VM version transition"])
+	// the transition is not blocked if the RPC protocol of the releases is unknown
+	checks, err = getUpgradePathChecks(sc, farUpgrade, nil, oldVMNodes, nil, now, time.Hour)
+	require.NoError(err)
+	require.True(checksPassed(checks)["VM version transition"])
+
+	// without node info, compatibility is not checked
+	checks, err = getUpgradePathChecks(sc, farUpgrade, nil, nil, vmRPCVersions, now, time.Hour)
+	require.NoError(err)
+	for _, check := range checks {
+		if check.Name == "AvalancheGo compatibility" {
+			require.True(check.Skipped)
+		}
+	}
+}
@@ -7,11 +7,15 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/signing"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
 
-var force bool
+var (
+	force          bool
+	upgradeSignKey string
+)
 
 // avalanche blockchain upgrade import
 func newUpgradeExportCmd() *cobra.Command {
@@ -25,6 +29,8 @@ func newUpgradeExportCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&upgradeBytesFilePath, upgradeBytesFilePathKey, "", "Export upgrade bytes file to location of choice on disk")
 	cmd.Flags().BoolVar(&force, "force", false, "If true, overwrite a possibly existing file without prompting")
+	cmd.Flags().StringVar(&upgradeSignKey, "sign-key", "", "sign the exported file with the given minisign secret key file")
+	addUpgradeSetFlag(cmd, "export the given named upgrade set")
 
 	return cmd
 }
@@ -35,6 +41,9 @@ func upgradeExportCmd(_ *cobra.Command, args []string) error {
 		ux.Logger.PrintToUser("The provided blockchain name %q does not exist", blockchainName)
 		return nil
 	}
+	if err := checkUpgradeSetExists(blockchainName, upgradeSet); err != nil {
+		return err
+	}
 
 	if upgradeBytesFilePath == "" {
 		var err error
@@ -59,7 +68,7 @@ func upgradeExportCmd(_ *cobra.Command, args []string) error {
 		}
 	}
 
-	fileBytes, err := app.ReadUpgradeFile(blockchainName)
+	fileBytes, err := app.ReadUpgradeSetFile(blockchainName, upgradeSet)
 	if err != nil {
 		return err
 	}
@@ -70,5 +79,8 @@ func upgradeExportCmd(_ *cobra.Command, args []string) error {
 	}
 
 	ux.Logger.PrintToUser("File written successfully.")
+	if upgradeSignKey != "" {
+		return signing.SignArtifact(app, fileBytes, upgradeBytesFilePath, upgradeSignKey)
+	}
 	return nil
 }
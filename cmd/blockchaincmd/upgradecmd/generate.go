@@ -3,6 +3,7 @@
 package upgradecmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -12,6 +13,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/rpcauth"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
@@ -19,6 +21,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/coreth/ethclient"
+	"github.com/ava-labs/coreth/rpc"
 	"github.com/ava-labs/subnet-evm/commontype"
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
@@ -59,6 +62,7 @@ guides the user through the process using an interactive wizard.`,
 		RunE: upgradeGenerateCmd,
 		Args: cobrautils.ExactArgs(1),
 	}
+	addUpgradeSetFlag(cmd, "write the upgrade file as the given named upgrade set")
 	return cmd
 }
 
@@ -68,6 +72,9 @@ func upgradeGenerateCmd(_ *cobra.Command, args []string) error {
 		ux.Logger.PrintToUser("The provided blockchain name %q does not exist", blockchainName)
 		return nil
 	}
+	if err := validateUpgradeSetName(upgradeSet); err != nil {
+		return err
+	}
 	// print some warning/info message
 	ux.Logger.PrintToUser(logging.Bold.Wrap(logging.Yellow.Wrap(
 		"Performing a network upgrade requires coordinating the upgrade network-wide.")))
@@ -148,7 +155,7 @@ func upgradeGenerateCmd(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	return app.WriteUpgradeFile(blockchainName, jsonBytes)
+	return app.WriteUpgradeSetFile(blockchainName, upgradeSet, jsonBytes)
 }
 
 func queryActivationTimestamp() (time.Time, error) {
@@ -179,7 +186,7 @@ func queryActivationTimestamp() (time.Time, error) {
 		date = now.Add(14 * 24 * time.Hour)
 	case custom:
 		date, err = app.Prompt.CaptureFutureDate(
-			"Enter the block activation UTC datetime in 'YYYY-MM-DD HH:MM:SS' format", time.Now().Add(time.Minute).UTC())
+			"Enter the block activation UTC datetime in 'YYYY-MM-DD HH:MM:SS' or RFC3339 format, or a duration from now, e.g. 3h30m", time.Now().Add(time.Minute).UTC())
 		if err != nil {
 			return time.Time{}, err
 		}
@@ -530,10 +537,12 @@ func promptTxAllowListParams(
 }
 
 func getCClient(apiEndpoint string, blockchainID string) (ethclient.Client, error) {
-	cClient, err := ethclient.Dial(fmt.Sprintf("%s/ext/bc/%s/rpc", apiEndpoint, blockchainID))
+	rpcURL := fmt.Sprintf("%s/ext/bc/%s/rpc", apiEndpoint, blockchainID)
+	rpcClient, err := rpc.DialOptions(context.Background(), rpcURL, rpc.WithHeaders(rpcauth.Tokens.Headers(rpcURL)))
 	if err != nil {
 		return nil, err
 	}
+	cClient := ethclient.NewClient(rpcClient)
 	return cClient, nil
 }
 
@@ -7,11 +7,15 @@ import (
 	"os"
 
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/signing"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
 
-var upgradeBytesFilePath string
+var (
+	upgradeBytesFilePath string
+	upgradeSignature     string
+)
 
 const upgradeBytesFilePathKey = "upgrade-filepath"
 
@@ -20,12 +24,18 @@ func newUpgradeImportCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "import [blockchainName]",
 		Short: "Import the upgrade bytes file into the local environment",
-		Long:  `Import the upgrade bytes file into the local environment`,
-		RunE:  upgradeImportCmd,
-		Args:  cobrautils.ExactArgs(1),
+		Long: `Import the upgrade bytes file into the local environment.
+
+If a signature is found next to the upgrade file (with a .minisig extension), or is given
+with --signature, it is verified against the trusted keys of the CLI config. Unsigned files
+are rejected if signatures are required (see avalanche config signing).`,
+		RunE: upgradeImportCmd,
+		Args: cobrautils.ExactArgs(1),
 	}
 
 	cmd.Flags().StringVar(&upgradeBytesFilePath, upgradeBytesFilePathKey, "", "Import upgrade bytes file into local environment")
+	addUpgradeSetFlag(cmd, "import the upgrade file as the given named upgrade set")
+	cmd.Flags().StringVar(&upgradeSignature, "signature", "", "minisign signature of the upgrade file (defaults to the upgrade file path with a .minisig extension)")
 
 	return cmd
 }
@@ -36,6 +46,9 @@ func upgradeImportCmd(_ *cobra.Command, args []string) error {
 		ux.Logger.PrintToUser("The provided blockchain name %q does not exist", blockchainName)
 		return nil
 	}
+	if err := validateUpgradeSetName(upgradeSet); err != nil {
+		return err
+	}
 
 	if upgradeBytesFilePath == "" {
 		var err error
@@ -57,5 +70,12 @@ func upgradeImportCmd(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to read the provided upgrade file: %w", err)
 	}
 
-	return app.WriteUpgradeFile(blockchainName, fileBytes)
+	if upgradeSignature == "" {
+		upgradeSignature = upgradeBytesFilePath + signing.SignatureExtension
+	}
+	if _, err := signing.VerifyArtifact(app, fileBytes, upgradeSignature); err != nil {
+		return err
+	}
+
+	return app.WriteUpgradeSetFile(blockchainName, upgradeSet, fileBytes)
 }
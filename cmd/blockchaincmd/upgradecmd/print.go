@@ -20,6 +20,7 @@ func newUpgradePrintCmd() *cobra.Command {
 		RunE:  upgradePrintCmd,
 		Args:  cobrautils.ExactArgs(1),
 	}
+	addUpgradeSetFlag(cmd, "print the given named upgrade set")
 
 	return cmd
 }
@@ -30,8 +31,11 @@ func upgradePrintCmd(_ *cobra.Command, args []string) error {
 		ux.Logger.PrintToUser("The provided blockchain name %q does not exist", blockchainName)
 		return nil
 	}
+	if err := checkUpgradeSetExists(blockchainName, upgradeSet); err != nil {
+		return err
+	}
 
-	fileBytes, err := app.ReadUpgradeFile(blockchainName)
+	fileBytes, err := app.ReadUpgradeSetFile(blockchainName, upgradeSet)
 	if err != nil {
 		return err
 	}
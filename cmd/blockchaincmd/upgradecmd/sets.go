@@ -0,0 +1,316 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+const upgradeSetFlag = "set"
+
+var (
+	upgradeSet string
+
+	upgradeSetNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+	upgradeSetsNetworkFlags            networkoptions.NetworkFlags
+	upgradeSetsSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Fuji,
+		networkoptions.Mainnet,
+	}
+)
+
+// upgradeSetDiffEntry is a difference found between two upgrade sets, on a given
+// top level [Section] of the upgrade file (eg precompileUpgrades)
+type upgradeSetDiffEntry struct {
+	Section string
+	// "-" for content only present on the first set, "+" for content only present on
+	// the second set
+	Kind  string
+	Value string
+}
+
+func addUpgradeSetFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().StringVar(&upgradeSet, upgradeSetFlag, constants.DefaultUpgradeSetName, usage)
+}
+
+func validateUpgradeSetName(setName string) error {
+	if !upgradeSetNameRegex.MatchString(setName) {
+		return fmt.Errorf("invalid upgrade set name %q: only letters, digits, '_', '.' and '-' are allowed", setName)
+	}
+	return nil
+}
+
+func checkUpgradeSetExists(blockchainName string, setName string) error {
+	if err := validateUpgradeSetName(setName); err != nil {
+		return err
+	}
+	if !app.UpgradeSetExists(blockchainName, setName) {
+		return fmt.Errorf("upgrade set %q not found for blockchain %s", setName, blockchainName)
+	}
+	return nil
+}
+
+// avalanche blockchain upgrade sets
+func newUpgradeSetsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sets",
+		Short: "Manage named upgrade sets of a blockchain",
+		Long: `The blockchain upgrade sets command suite manages multiple named upgrade files per blockchain
+(e.g. "fuji-activation", "mainnet-activation"), so that each network gets the upgrade file with
+the activation timestamps meant for it.
+
+Named sets are created with the --set flag of the generate and import commands. The set selected
+for a network is the one installed at deploy time and by upgrade apply. Networks without a
+selection use the default upgrade file.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	// blockchain upgrade sets list
+	cmd.AddCommand(newUpgradeSetsListCmd())
+	// blockchain upgrade sets diff
+	cmd.AddCommand(newUpgradeSetsDiffCmd())
+	// blockchain upgrade sets select
+	cmd.AddCommand(newUpgradeSetsSelectCmd())
+	return cmd
+}
+
+// avalanche blockchain upgrade sets list
+func newUpgradeSetsListCmd() *cobra.Command {
+	return cobrautils.MarkReadOnly(&cobra.Command{
+		Use:   "list [blockchainName]",
+		Short: "List the upgrade sets of a blockchain",
+		Long:  `List the upgrade sets of a blockchain, together with the networks each of them is selected for`,
+		RunE:  upgradeSetsListCmd,
+		Args:  cobrautils.ExactArgs(1),
+	})
+}
+
+func upgradeSetsListCmd(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("unable to load sidecar: %w", err)
+	}
+	setNames, err := app.GetUpgradeSets(blockchainName)
+	if err != nil {
+		return err
+	}
+	if len(setNames) == 0 {
+		ux.Logger.PrintToUser("No upgrade sets found for blockchain %s", blockchainName)
+		return nil
+	}
+	selectedFor := map[string][]string{}
+	for networkName, setName := range sc.UpgradeSets {
+		selectedFor[setName] = append(selectedFor[setName], networkName)
+	}
+	t := ux.DefaultTable(fmt.Sprintf("%s upgrade sets", blockchainName), []interface{}{"Set", "Selected For", "File"})
+	for _, setName := range setNames {
+		networks := selectedFor[setName]
+		sort.Strings(networks)
+		if setName == constants.DefaultUpgradeSetName {
+			networks = append(networks, "(networks without selection)")
+		}
+		t.AppendRow([]interface{}{setName, strings.Join(networks, "\n"), app.GetUpgradeSetFilePath(blockchainName, setName)})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	return nil
+}
+
+// avalanche blockchain upgrade sets diff
+func newUpgradeSetsDiffCmd() *cobra.Command {
+	return cobrautils.MarkReadOnly(&cobra.Command{
+		Use:   "diff [blockchainName] [setA] [setB]",
+		Short: "Show the differences between two upgrade sets",
+		Long: `Show the differences between two upgrade sets of a blockchain. Entries only present on the
+first set are prefixed with '-', and entries only present on the second set with '+'.`,
+		RunE: upgradeSetsDiffCmd,
+		Args: cobrautils.ExactArgs(3),
+	})
+}
+
+func upgradeSetsDiffCmd(_ *cobra.Command, args []string) error {
+	blockchainName, setA, setB := args[0], args[1], args[2]
+	for _, setName := range []string{setA, setB} {
+		if err := checkUpgradeSetExists(blockchainName, setName); err != nil {
+			return err
+		}
+	}
+	setABytes, err := app.ReadUpgradeSetFile(blockchainName, setA)
+	if err != nil {
+		return err
+	}
+	setBBytes, err := app.ReadUpgradeSetFile(blockchainName, setB)
+	if err != nil {
+		return err
+	}
+	diff, err := diffUpgradeSets(setABytes, setBBytes)
+	if err != nil {
+		return err
+	}
+	if len(diff) == 0 {
+		ux.Logger.PrintToUser("Upgrade sets %q and %q are equivalent", setA, setB)
+		return nil
+	}
+	ux.Logger.PrintToUser("--- %s", setA)
+	ux.Logger.PrintToUser("+++ %s", setB)
+	section := ""
+	for _, entry := range diff {
+		if entry.Section != section {
+			section = entry.Section
+			ux.Logger.PrintToUser("%s:", section)
+		}
+		line := fmt.Sprintf("  %s %s", entry.Kind, entry.Value)
+		if entry.Kind == "-" {
+			line = logging.Red.Wrap(line)
+		} else {
+			line = logging.Green.Wrap(line)
+		}
+		ux.Logger.PrintToUser(line)
+	}
+	return nil
+}
+
+// diffUpgradeSets compares the top level sections of two upgrade files. List sections
+// (eg precompileUpgrades, stateUpgrades) are compared entry by entry, regardless of order.
+// Other sections (eg networkUpgradeOverrides) are compared as a whole
+func diffUpgradeSets(setABytes []byte, setBBytes []byte) ([]upgradeSetDiffEntry, error) {
+	setA := map[string]interface{}{}
+	if err := json.Unmarshal(setABytes, &setA); err != nil {
+		return nil, fmt.Errorf("failed parsing JSON of first upgrade set: %w", err)
+	}
+	setB := map[string]interface{}{}
+	if err := json.Unmarshal(setBBytes, &setB); err != nil {
+		return nil, fmt.Errorf("failed parsing JSON of second upgrade set: %w", err)
+	}
+	sections := maps.Keys(setA)
+	for section := range setB {
+		if _, ok := setA[section]; !ok {
+			sections = append(sections, section)
+		}
+	}
+	sort.Strings(sections)
+	diff := []upgradeSetDiffEntry{}
+	for _, section := range sections {
+		entriesA, err := upgradeSetSectionEntries(setA[section])
+		if err != nil {
+			return nil, err
+		}
+		entriesB, err := upgradeSetSectionEntries(setB[section])
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entriesA {
+			if !removeEntry(&entriesB, entry) {
+				diff = append(diff, upgradeSetDiffEntry{Section: section, Kind: "-", Value: entry})
+			}
+		}
+		for _, entry := range entriesB {
+			diff = append(diff, upgradeSetDiffEntry{Section: section, Kind: "+", Value: entry})
+		}
+	}
+	return diff, nil
+}
+
+// upgradeSetSectionEntries returns the canonical JSON of each entry of a list section,
+// or of the full section if it is not a list
+func upgradeSetSectionEntries(section interface{}) ([]string, error) {
+	if section == nil {
+		return nil, nil
+	}
+	values, ok := section.([]interface{})
+	if !ok {
+		values = []interface{}{section}
+	}
+	entries := []string{}
+	for _, value := range values {
+		// map keys are sorted on marshal, so equivalent entries give equal strings
+		bs, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, string(bs))
+	}
+	return entries, nil
+}
+
+// removeEntry removes the first occurrence of [entry] from [entries], returning
+// true if it was found
+func removeEntry(entries *[]string, entry string) bool {
+	for i, e := range *entries {
+		if e == entry {
+			*entries = append((*entries)[:i], (*entries)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// avalanche blockchain upgrade sets select
+func newUpgradeSetsSelectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "select [blockchainName] [setName]",
+		Short: "Select the upgrade set to be installed on a network",
+		Long: `Select the upgrade set to be installed on the given network, both at deploy time and
+by the upgrade apply command. Select the "default" set to go back to the default upgrade file.`,
+		RunE: upgradeSetsSelectCmd,
+		Args: cobrautils.ExactArgs(2),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &upgradeSetsNetworkFlags, false, upgradeSetsSupportedNetworkOptions)
+	return cmd
+}
+
+func upgradeSetsSelectCmd(_ *cobra.Command, args []string) error {
+	blockchainName, setName := args[0], args[1]
+	if err := validateUpgradeSetName(setName); err != nil {
+		return err
+	}
+	if setName != constants.DefaultUpgradeSetName && !app.UpgradeSetExists(blockchainName, setName) {
+		return fmt.Errorf("upgrade set %q not found for blockchain %s", setName, blockchainName)
+	}
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("unable to load sidecar: %w", err)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"On what network do you want to install the upgrade set?",
+		upgradeSetsNetworkFlags,
+		true,
+		false,
+		upgradeSetsSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if setName == constants.DefaultUpgradeSetName {
+		delete(sc.UpgradeSets, network.Name())
+	} else {
+		if sc.UpgradeSets == nil {
+			sc.UpgradeSets = map[string]string{}
+		}
+		sc.UpgradeSets[network.Name()] = setName
+	}
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Upgrade set %q selected for %s", setName, network.Name())
+	if !sc.NetworkDataIsEmpty(network.Name()) {
+		ux.Logger.PrintToUser("%s is already deployed on %s. Use `avalanche blockchain upgrade apply` to install the set on its nodes", blockchainName, network.Name())
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffUpgradeSets(t *testing.T) {
+	require := require.New(t)
+
+	fujiSet := []byte(`{"precompileUpgrades":[
+{"txAllowListConfig":{"blockTimestamp":1700000000,"adminAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"]}},
+{"feeManagerConfig":{"blockTimestamp":1700000100,"disable":true}}
+]}`)
+	// same upgrades, different order and key order
+	fujiSetReordered := []byte(`{"precompileUpgrades":[
+{"feeManagerConfig":{"disable":true,"blockTimestamp":1700000100}},
+{"txAllowListConfig":{"adminAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"],"blockTimestamp":1700000000}}
+]}`)
+	mainnetSet := []byte(`{"precompileUpgrades":[
+{"txAllowListConfig":{"blockTimestamp":1800000000,"adminAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"]}},
+{"feeManagerConfig":{"blockTimestamp":1700000100,"disable":true}}
+],"networkUpgradeOverrides":{"durangoTimestamp":1800000000}}`)
+
+	diff, err := diffUpgradeSets(fujiSet, fujiSetReordered)
+	require.NoError(err)
+	require.Empty(diff)
+
+	diff, err = diffUpgradeSets(fujiSet, mainnetSet)
+	require.NoError(err)
+	require.Equal([]upgradeSetDiffEntry{
+		{Section: "networkUpgradeOverrides", Kind: "+", Value: `{"durangoTimestamp":1800000000}`},
+		{Section: "precompileUpgrades", Kind: "-", Value: `{"txAllowListConfig":{"adminAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"],"blockTimestamp":1700000000}}`},
+		{Section: "precompileUpgrades", Kind: "+", Value: `{"txAllowListConfig":{"adminAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"],"blockTimestamp":1800000000}}`},
+	}, diff)
+
+	_, err = diffUpgradeSets(fujiSet, []byte("not json"))
+	require.Error(err)
+}
+
+func TestValidateUpgradeSetName(t *testing.T) {
+	require := require.New(t)
+	for _, name := range []string{"default", "fuji-activation", "mainnet_2024.1"} {
+		require.NoError(validateUpgradeSetName(name))
+	}
+	for _, name := range []string{"", "../fuji", "fuji/activation", "-fuji", "fuji activation"} {
+		require.Error(validateUpgradeSetName(name))
+	}
+}
@@ -32,5 +32,7 @@ updating your developmental and deployed Blockchains.`,
 	cmd.AddCommand(newUpgradePrintCmd())
 	// blockchain upgrade apply
 	cmd.AddCommand(newUpgradeApplyCmd())
+	// blockchain upgrade sets
+	cmd.AddCommand(newUpgradeSetsCmd())
 	return cmd
 }
@@ -5,11 +5,13 @@ package upgradecmd
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
 	"github.com/ava-labs/avalanche-cli/pkg/plugins"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
@@ -17,6 +19,8 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/ava-labs/avalanche-network-runner/server"
 	anrutils "github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +29,7 @@ const (
 	localDeployment   = "Existing local deployment"
 	fujiDeployment    = "Fuji"
 	mainnetDeployment = "Mainnet"
+	clusterDeployment = "Cluster"
 )
 
 var (
@@ -32,6 +37,7 @@ var (
 
 	useFuji       bool
 	useMainnet    bool
+	clusterName   string
 	useLocal      bool
 	useConfig     bool
 	useManual     bool
@@ -49,7 +55,13 @@ func newUpgradeVMCmd() *cobra.Command {
 can upgrade both local Blockchains and publicly deployed Blockchains on Fuji and Mainnet.
 
 The command walks the user through an interactive wizard. The user can skip the wizard by providing
-command line flags.`,
+command line flags.
+
+When upgrading Subnet-EVM, the command checks whether the version change requires a database migration
+or a resync. For clusters (--cluster), the required steps are orchestrated on each node: the node is
+stopped, its chain data is backed up (and moved away if a resync is required), the new VM is installed
+and the node is started again. For other deployments, the required steps are printed.
+Subnet-EVM downgrades are refused, as databases written by newer versions may not be readable by older ones.`,
 		RunE: upgradeVM,
 		Args: cobrautils.ExactArgs(1),
 	}
@@ -59,6 +71,7 @@ command line flags.`,
 	cmd.Flags().BoolVar(&useFuji, "fuji", false, "upgrade existing `fuji` deployment (alias for `testnet`)")
 	cmd.Flags().BoolVar(&useFuji, "testnet", false, "upgrade existing `testnet` deployment (alias for `fuji`)")
 	cmd.Flags().BoolVar(&useMainnet, "mainnet", false, "upgrade existing `mainnet` deployment")
+	cmd.Flags().StringVar(&clusterName, "cluster", "", "upgrade the VM on all the nodes of the given cluster")
 
 	cmd.Flags().BoolVar(&useManual, "print", false, "print instructions for upgrading")
 	cmd.Flags().StringVar(&pluginDir, "plugin-dir", "", "plugin directory to automatically upgrade VM")
@@ -71,8 +84,9 @@ command line flags.`,
 }
 
 func atMostOneNetworkSelected() bool {
+	useCluster := clusterName != ""
 	return !(useConfig && useLocal || useConfig && useFuji || useConfig && useMainnet || useLocal && useFuji ||
-		useLocal && useMainnet || useFuji && useMainnet)
+		useLocal && useMainnet || useFuji && useMainnet || useCluster && (useConfig || useLocal || useFuji || useMainnet))
 }
 
 func atMostOneVersionSelected() bool {
@@ -115,14 +129,16 @@ func upgradeVM(_ *cobra.Command, args []string) error {
 	}
 
 	// if upgrading local, check that the network is off otherwise fail here
-	serverRunning, err := isServerRunning()
-	if err != nil {
-		return err
-	}
+	if networkToUpgrade != clusterDeployment {
+		serverRunning, err := isServerRunning()
+		if err != nil {
+			return err
+		}
 
-	if serverRunning {
-		ux.Logger.PrintToUser("Please stop network before upgrading local VMs")
-		return errors.New("network is still running")
+		if serverRunning {
+			ux.Logger.PrintToUser("Please stop network before upgrading local VMs")
+			return errors.New("network is still running")
+		}
 	}
 
 	vmType := sc.VM
@@ -146,6 +162,8 @@ func selectNetworkToUpgrade(sc models.Sidecar, upgradeOptions []string) (string,
 		return fujiDeployment, nil
 	case useMainnet:
 		return mainnetDeployment, nil
+	case clusterName != "":
+		return clusterDeployment, nil
 	}
 
 	updatePrompt := "What deployment would you like to upgrade"
@@ -266,6 +284,20 @@ func updateToSpecificVersion(sc models.Sidecar, networkToUpgrade string) error {
 }
 
 func updateVMByNetwork(sc models.Sidecar, targetVersion string, networkToUpgrade string) error {
+	if err := vm.CheckSidecarSubnetEVMVersion(app, sc, targetVersion); err != nil {
+		return err
+	}
+	migration := vm.VMMigration{Kind: vm.MigrationNone}
+	if networkToUpgrade != futureDeployment {
+		var (
+			cancel bool
+			err    error
+		)
+		migration, cancel, err = checkVMMigration(sc, targetVersion, networkToUpgrade)
+		if err != nil || cancel {
+			return err
+		}
+	}
 	switch networkToUpgrade {
 	case futureDeployment:
 		return updateFutureVM(sc, targetVersion)
@@ -275,6 +307,8 @@ func updateVMByNetwork(sc models.Sidecar, targetVersion string, networkToUpgrade
 		return chooseManualOrAutomatic(sc, targetVersion)
 	case mainnetDeployment:
 		return chooseManualOrAutomatic(sc, targetVersion)
+	case clusterDeployment:
+		return updateClusterVM(sc, targetVersion, migration)
 	default:
 		return errors.New("unknown deployment")
 	}
@@ -356,6 +390,80 @@ func updateExistingLocalVM(sc models.Sidecar, targetVersion string) error {
 	return nil
 }
 
+// checkVMMigration detects if upgrading the subnet-evm VM of [sc] to [targetVersion] needs
+// a database migration or a resync, and if so, describes the required steps and asks the user
+// for confirmation. It returns true if the user cancels the upgrade
+func checkVMMigration(sc models.Sidecar, targetVersion string, networkToUpgrade string) (vm.VMMigration, bool, error) {
+	noMigration := vm.VMMigration{Kind: vm.MigrationNone}
+	if sc.VM != models.SubnetEvm || targetVersion == "" || sc.VMVersion == "" || sc.VMVersion == "latest" {
+		return noMigration, false, nil
+	}
+	migration, err := vm.GetSubnetEVMMigration(sc.VMVersion, targetVersion)
+	if err != nil {
+		return noMigration, false, err
+	}
+	if migration.Kind == vm.MigrationNone {
+		return migration, false, nil
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("Upgrading %s from %s to %s requires a %s"), sc.VM, sc.VMVersion, targetVersion, migration.Kind)
+	if migration.Description != "" {
+		ux.Logger.PrintToUser(migration.Description)
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Required steps on each node:")
+	ux.Logger.PrintToUser("  1. Stop the node")
+	switch migration.Kind {
+	case vm.MigrationOnStart:
+		ux.Logger.PrintToUser("  2. Back up the node database and the chain data dir of the blockchain")
+		ux.Logger.PrintToUser("  3. Install the new VM binary")
+		ux.Logger.PrintToUser("  4. Start the node. The VM will migrate its database on first start")
+	case vm.MigrationResync:
+		ux.Logger.PrintToUser("  2. Move the node database and the chain data dir of the blockchain to a backup location")
+		ux.Logger.PrintToUser("  3. Install the new VM binary")
+		ux.Logger.PrintToUser("  4. Start the node. It will resync from scratch, including the primary network")
+	}
+	ux.Logger.PrintToUser("")
+	if networkToUpgrade == clusterDeployment {
+		ux.Logger.PrintToUser("These steps will be automatically performed on the nodes of cluster %s", clusterName)
+	} else {
+		ux.Logger.PrintToUser("These steps need to be manually performed on the nodes validating the blockchain")
+	}
+	yes, err := app.Prompt.CaptureYesNo("Do you want to continue with the upgrade?")
+	if err != nil {
+		return noMigration, false, err
+	}
+	return migration, !yes, nil
+}
+
+func updateClusterVM(sc models.Sidecar, targetVersion string, migration vm.VMMigration) error {
+	sc.VMVersion = targetVersion
+	ux.Logger.PrintToUser("Upgrading %s VM on cluster %s...", sc.Name, clusterName)
+	results, err := node.UpgradeClusterVM(app, clusterName, sc, migration)
+	if err != nil {
+		return err
+	}
+	nodeResults := results.GetResults()
+	sort.Slice(nodeResults, func(i, j int) bool { return nodeResults[i].NodeID < nodeResults[j].NodeID })
+	header := table.Row{"Cloud ID", "Backup", "Status"}
+	t := ux.DefaultTable(fmt.Sprintf("%s VM upgrade to %s", sc.Name, targetVersion), header)
+	for _, result := range nodeResults {
+		status := logging.Green.Wrap("OK")
+		if result.Err != nil {
+			status = logging.Red.Wrap(result.Err.Error())
+		}
+		t.AppendRow(table.Row{result.NodeID, result.Value, status})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	if results.HasErrors() {
+		return fmt.Errorf("failed to upgrade VM on nodes %v", results.GetErrorHosts())
+	}
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("VM upgraded to %s on cluster %s", targetVersion, clusterName)
+	return nil
+}
+
 func chooseManualOrAutomatic(sc models.Sidecar, targetVersion string) error {
 	switch {
 	case useManual:
@@ -0,0 +1,170 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package blockchaincmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+// address to move the refund of a removed validator to
+var refundAddress string
+
+// trackValidatorRefund verifies that the P-Chain refunded the remaining balance of
+// [l1Validator] on its removal with [removalTx], and returns the record to keep track of it
+func trackValidatorRefund(
+	network models.Network,
+	nodeID ids.NodeID,
+	validationID ids.ID,
+	l1Validator platformvm.L1Validator,
+	removalTx *txs.Tx,
+) (models.RemovedValidator, error) {
+	removed := models.RemovedValidator{
+		NodeID:            nodeID.String(),
+		ValidationID:      validationID.String(),
+		RemovalTxID:       removalTx.ID().String(),
+		RefundOutputIndex: uint32(len(removalTx.Unsigned.Outputs())),
+	}
+	if l1Validator.RemainingBalanceOwner == nil {
+		return removed, nil
+	}
+	owners := l1Validator.RemainingBalanceOwner.Addrs
+	for _, owner := range owners {
+		ownerStr, err := address.Format("P", key.GetHRP(network.ID), owner[:])
+		if err != nil {
+			return removed, err
+		}
+		removed.RefundOwners = append(removed.RefundOwners, ownerStr)
+	}
+	refund, found, err := txutils.GetRefundUnspentAmount(network, removed.RefundUTXOID(), owners)
+	if err != nil {
+		return removed, fmt.Errorf("failure verifying the validator refund: %w", err)
+	}
+	switch {
+	case found:
+		removed.Refund = refund
+		ux.Logger.GreenCheckmarkToUser(
+			"Remaining balance of %.9f AVAX refunded to %s",
+			float64(refund)/float64(units.Avax),
+			strings.Join(removed.RefundOwners, ", "),
+		)
+	case l1Validator.Balance == 0:
+		ux.Logger.PrintToUser("The validator had no remaining balance to refund")
+	default:
+		ux.Logger.RedXToUser(
+			"Refund of the validator remaining balance not found at %s",
+			strings.Join(removed.RefundOwners, ", "),
+		)
+	}
+	return removed, nil
+}
+
+// ClaimValidatorRefund moves the refund of [removed] to the P-Chain address [claimAddress],
+// signing with the stored key owning it. The tx fee is paid out of the refund
+func ClaimValidatorRefund(
+	network models.Network,
+	removed *models.RemovedValidator,
+	claimAddress string,
+) error {
+	claimAddressID, err := address.ParseToID(claimAddress)
+	if err != nil {
+		return fmt.Errorf("invalid P-Chain address %s: %w", claimAddress, err)
+	}
+	owners, err := address.ParseToIDs(removed.RefundOwners)
+	if err != nil {
+		return err
+	}
+	if utils.Belongs(owners, claimAddressID) {
+		ux.Logger.PrintToUser("The refund of validator %s is already owned by %s", removed.NodeID, claimAddress)
+		return nil
+	}
+	if len(owners) != 1 {
+		return fmt.Errorf("refund of validator %s is owned by %d addresses. Only single owner refunds can be claimed", removed.NodeID, len(owners))
+	}
+	refund, found, err := txutils.GetRefundUnspentAmount(network, removed.RefundUTXOID(), owners)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("refund of validator %s is not unspent at %s", removed.NodeID, removed.RefundOwners[0])
+	}
+	keyName, err := getPChainAddressKeyName(network, owners[0])
+	if err != nil {
+		return err
+	}
+	if keyName == "" {
+		return fmt.Errorf("no stored key owns %s. Move the refund with the wallet owning it", removed.RefundOwners[0])
+	}
+	kc, err := keychain.GetKeychain(app, false, false, nil, keyName, network, 0)
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewPublicDeployer(app, kc, network)
+	claimTxID, claimed, err := deployer.PChainTransferFeeIncluded(claimAddressID, refund)
+	if err != nil {
+		return err
+	}
+	received, found, err := txutils.GetTxUnspentAmount(network, claimTxID, []ids.ShortID{claimAddressID})
+	if err != nil {
+		return fmt.Errorf("failure verifying the claim of the validator refund: %w", err)
+	}
+	if !found || received != claimed {
+		return fmt.Errorf("claim tx %s did not transfer %.9f AVAX to %s", claimTxID, float64(claimed)/float64(units.Avax), claimAddress)
+	}
+	removed.ClaimTxID = claimTxID.String()
+	removed.ClaimAddress = claimAddress
+	ux.Logger.GreenCheckmarkToUser(
+		"Refund of validator %s claimed: %.9f AVAX received by %s (tx %s)",
+		removed.NodeID,
+		float64(received)/float64(units.Avax),
+		claimAddress,
+		claimTxID,
+	)
+	return nil
+}
+
+// getPChainAddressKeyName returns the stored key owning the P-Chain address [addr], or
+// empty if there is none
+func getPChainAddressKeyName(network models.Network, addr ids.ShortID) (string, error) {
+	keyNames, err := utils.GetKeyNames(app.GetKeyDir(), network.Kind != models.Mainnet)
+	if err != nil {
+		return "", err
+	}
+	for _, keyName := range keyNames {
+		k, err := app.GetKey(keyName, network, false)
+		if err != nil {
+			return "", err
+		}
+		if utils.Belongs(k.Addresses(), addr) {
+			return keyName, nil
+		}
+	}
+	return "", nil
+}
+
+// recordRemovedValidator adds [removed] to the removed validators of [blockchainName] on [network]
+func recordRemovedValidator(blockchainName string, network models.Network, removed models.RemovedValidator) error {
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return err
+	}
+	networkData := sc.Networks[network.Name()]
+	networkData.RemovedValidators = append(utils.Filter(networkData.RemovedValidators, func(r models.RemovedValidator) bool {
+		return r.RemovalTxID != removed.RemovalTxID
+	}), removed)
+	sc.Networks[network.Name()] = networkData
+	return app.UpdateSidecar(&sc)
+}
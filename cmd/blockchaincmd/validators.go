@@ -37,7 +37,7 @@ several statistics about them.`,
 		Args: cobrautils.ExactArgs(1),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, validatorsSupportedNetworkOptions)
-	return cmd
+	return cobrautils.MarkReadOnly(cmd)
 }
 
 func printValidators(_ *cobra.Command, args []string) error {
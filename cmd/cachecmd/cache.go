@@ -0,0 +1,33 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package cachecmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche cache
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of downloaded binaries",
+		Long: `The cache command suite manages the cache where downloaded AvalancheGo, Subnet-EVM and
+ICM relayer binaries are stored.
+
+Each downloaded version is extracted once into the cache, and linked from the install dirs
+that use it. The cache keeps track of the local network and local clusters that run each
+binary, so that the binaries no longer used can be removed with cache gc.`,
+		RunE: cobrautils.CommandSuiteUsage,
+		Args: cobrautils.ExactArgs(0),
+	}
+	// cache list
+	cmd.AddCommand(newListCmd())
+	// cache gc
+	cmd.AddCommand(newGCCmd())
+	return cmd
+}
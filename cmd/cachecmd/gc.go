@@ -0,0 +1,67 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package cachecmd
+
+import (
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/bincache"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var gcDryRun bool
+
+// avalanche cache gc
+func newGCCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Remove the cached binaries no longer used",
+		Long: `The cache gc command removes from the cache the binary versions that are not used by
+the local network nor by any local cluster, together with their links on the install dirs.
+
+References of local clusters that no longer exist are dropped first. Use --dry-run to
+only report the binaries to be removed, and the space that would be reclaimed.`,
+		RunE: gcCache,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "report what would be removed, without removing anything")
+	return cmd
+}
+
+func gcCache(_ *cobra.Command, _ []string) error {
+	result, err := bincache.New(app.GetBinaryCacheDir()).GC(isStaleOwner, gcDryRun)
+	if err != nil {
+		return err
+	}
+	if len(result.Removed) == 0 {
+		ux.Logger.PrintToUser("Nothing to remove, cached binaries use %s", utils.FormatSize(result.Kept))
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Component", "Version", "Size"})
+	for _, entry := range result.Removed {
+		table.Append([]string{entry.Component, entry.Version, utils.FormatSize(entry.Size)})
+	}
+	table.SetFooter([]string{"Total", "", utils.FormatSize(result.Reclaimed)})
+	table.Render()
+	if gcDryRun {
+		ux.Logger.PrintToUser("%s can be reclaimed, %s is in use", utils.FormatSize(result.Reclaimed), utils.FormatSize(result.Kept))
+		return nil
+	}
+	ux.Logger.GreenCheckmarkToUser("Reclaimed %s, %s is in use", utils.FormatSize(result.Reclaimed), utils.FormatSize(result.Kept))
+	return nil
+}
+
+// isStaleOwner returns true if [owner] is a local cluster that no longer exists
+func isStaleOwner(owner string) bool {
+	clusterName, ok := bincache.ClusterName(owner)
+	if !ok {
+		return false
+	}
+	exists, err := app.ClusterExists(clusterName)
+	return err == nil && !exists
+}
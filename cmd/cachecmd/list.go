@@ -0,0 +1,54 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package cachecmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/bincache"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// avalanche cache list
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the cached binaries",
+		Long: `The cache list command lists the binary versions stored in the cache, with their size
+and the local network or clusters that use them.`,
+		RunE: listCache,
+		Args: cobrautils.ExactArgs(0),
+	}
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func listCache(_ *cobra.Command, _ []string) error {
+	entries, err := bincache.New(app.GetBinaryCacheDir()).List()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		ux.Logger.PrintToUser("No cached binaries found")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Component", "Version", "Size", "Used By"})
+	table.SetRowLine(true)
+	totalSize := int64(0)
+	for _, entry := range entries {
+		usedBy := "-"
+		if len(entry.Refs) > 0 {
+			usedBy = strings.Join(entry.Refs, "\n")
+		}
+		table.Append([]string{entry.Component, entry.Version, utils.FormatSize(entry.Size), usedBy})
+		totalSize += entry.Size
+	}
+	table.SetFooter([]string{"Total", "", utils.FormatSize(totalSize), ""})
+	table.Render()
+	return nil
+}
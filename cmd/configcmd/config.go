@@ -24,5 +24,16 @@ func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 	cmd.AddCommand(newMigrateCmd())
 	cmd.AddCommand(newAuthorizeCloudAccessCmd())
 	cmd.AddCommand(newSnapshotsAutoSaveCmd())
+	cmd.AddCommand(newLocalNetworkBackendCmd())
+	cmd.AddCommand(newReadOnlyCmd())
+	cmd.AddCommand(newWebhookCmd())
+	cmd.AddCommand(newEventSinkCmd())
+	cmd.AddCommand(newStateBackendCmd())
+	cmd.AddCommand(newSigningCmd())
+	cmd.AddCommand(newSetDefaultCmd())
+	cmd.AddCommand(newPolicyCmd())
+	cmd.AddCommand(newApproveCmd())
+	cmd.AddCommand(newPresetRegistryCmd())
+	cmd.AddCommand(newProxyCmd())
 	return cmd
 }
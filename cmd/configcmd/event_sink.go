@@ -0,0 +1,108 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/events"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	disableEventSink bool
+	testEventSink    bool
+)
+
+// avalanche config event-sink command
+func newEventSinkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "event-sink [url]",
+		Short: "publish events of lifecycle operations to a message queue",
+		Long: fmt.Sprintf(`set a NATS, Kafka or AMQP sink where a structured JSON event is published each time
+one of the following happens: %v.
+
+Supported sink URLs:
+  %s
+
+NATS events are published on subject <subject>.<event type> (eg avalanche.deploy.succeeded).
+Kafka events are produced through a Kafka REST Proxy, with the event type as record key.
+AMQP events are published as persistent messages, with the event type as default routing key,
+and must be confirmed by the broker and routed to at least one queue to be considered delivered.
+
+Credentials are not kept on the config file, so the sink URL can't include them. They are
+taken from the %s and %s env vars. For NATS, a user without
+password is used as auth token.
+
+The events are the same ones sent to the webhook and script hooks (see avalanche config webhook).
+Delivery failures never fail the command emitting the event. Use --test to check the setup.`,
+			supportedHooks,
+			strings.Join(events.SupportedSinkURLs, "\n  "),
+			constants.EventSinkUserEnvVarName,
+			constants.EventSinkPasswordEnvVarName,
+		),
+		RunE: eventSink,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+	cmd.Flags().BoolVar(&disableEventSink, "disable", false, "remove the message queue sink")
+	cmd.Flags().BoolVar(&testEventSink, "test", false, "publish a test event to the configured sink")
+	return cmd
+}
+
+func eventSink(cmd *cobra.Command, args []string) error {
+	switch {
+	case disableEventSink:
+		if len(args) > 0 {
+			return errors.New("--disable can't be used together with a sink URL")
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigEventSinkURLKey, ""); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Event sink disabled")
+		return nil
+	case len(args) == 0 && !testEventSink:
+		ux.Logger.PrintToUser(cmd.UsageString())
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Event Sink URL: %s", valueOrNone(redactURL(app.Conf.GetConfigStringValue(constants.ConfigEventSinkURLKey))))
+		return nil
+	}
+	if len(args) == 1 {
+		if err := events.ValidateSinkURL(args[0]); err != nil {
+			return err
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigEventSinkURLKey, args[0]); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Event sink set to %s", redactURL(args[0]))
+	}
+	if testEventSink {
+		if app.Conf.GetConfigStringValue(constants.ConfigEventSinkURLKey) == "" {
+			return errors.New("no event sink configured")
+		}
+		if err := events.Deliver(app, events.Event{
+			Type:      events.Test,
+			Timestamp: time.Now().UTC(),
+			Text:      "Avalanche-CLI test event",
+		}); err != nil {
+			return err
+		}
+		ux.Logger.GreenCheckmarkToUser("Test event published")
+	}
+	return nil
+}
+
+// redactURL hides the password of the sink URL credentials
+func redactURL(sinkURL string) string {
+	u, err := url.Parse(sinkURL)
+	if err != nil {
+		return sinkURL
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodeCPUs   string
+	nodeMemory string
+)
+
+// avalanche config localNetworkBackend command
+func newLocalNetworkBackendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "localNetworkBackend [process | docker]",
+		Short: "select how local network nodes are run",
+		Long: `set how the nodes of the local network are run: as processes managed by the network runner
+(process, default), or each one in its own avalanchego docker container (docker).
+
+The docker backend isolates node ports and state inside a compose project, and supports per
+node resource limits with --node-cpus and --node-memory. Limits apply to networks created
+after they are set.
+
+Blockchain deploy installs the VM on the node containers, so on the docker backend it requires
+a linux host. L1s deployed locally use the node containers as their bootstrap validators.`,
+		RunE: localNetworkBackend,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+	cmd.Flags().StringVar(&nodeCPUs, "node-cpus", "", "docker backend: cpu limit of each node container (eg 1.5)")
+	cmd.Flags().StringVar(&nodeMemory, "node-memory", "", "docker backend: memory limit of each node container (eg 2g)")
+	return cmd
+}
+
+func localNetworkBackend(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !cmd.Flags().Changed("node-cpus") && !cmd.Flags().Changed("node-memory") {
+		ux.Logger.PrintToUser(cmd.UsageString())
+		ux.Logger.PrintToUser("")
+		backend := app.Conf.GetConfigStringValue(constants.ConfigLocalNetworkBackendKey)
+		if backend == "" {
+			backend = constants.LocalNetworkProcessBackend
+		}
+		ux.Logger.PrintToUser("Current Setting: %s", backend)
+		if cpus := app.Conf.GetConfigStringValue(constants.ConfigLocalNetworkNodeCPUsKey); cpus != "" {
+			ux.Logger.PrintToUser("Node CPU Limit: %s", cpus)
+		}
+		if memory := app.Conf.GetConfigStringValue(constants.ConfigLocalNetworkNodeMemKey); memory != "" {
+			ux.Logger.PrintToUser("Node Memory Limit: %s", memory)
+		}
+		return nil
+	}
+	if len(args) == 1 {
+		switch args[0] {
+		case constants.LocalNetworkProcessBackend, constants.LocalNetworkDockerBackend:
+		default:
+			return errors.New("Invalid argument '" + args[0] + "'")
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigLocalNetworkBackendKey, args[0]); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Local network backend set to %s. Changes apply to the next network start, stop a running network first", args[0])
+	}
+	if cmd.Flags().Changed("node-cpus") {
+		if err := app.Conf.SetConfigValue(constants.ConfigLocalNetworkNodeCPUsKey, nodeCPUs); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("node-memory") {
+		if err := app.Conf.SetConfigValue(constants.ConfigLocalNetworkNodeMemKey, nodeMemory); err != nil {
+			return err
+		}
+	}
+	if nodeCPUs != "" || nodeMemory != "" {
+		ux.Logger.PrintToUser("Node resource limits apply to %s backend networks created from now on", constants.LocalNetworkDockerBackend)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/policy"
+	"github.com/ava-labs/avalanche-cli/pkg/signing"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policyNetworks  []string
+	policyPhrase    string
+	policyApprovals int
+	policyWait      string
+	unsetPolicy     bool
+	approveSignKey  string
+	approveOutput   string
+)
+
+// avalanche config policy command
+func newPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy [command]",
+		Short: "set confirmation policies for dangerous operations",
+		Long: `set a confirmation policy for a given command, that is enforced before the command runs.
+The command is identified by its subcommands joined by dots, eg:
+
+  avalanche config policy blockchain.deploy --networks mainnet --phrase "deploy to mainnet" --wait 1m
+  avalanche config policy blockchain.removeValidator --networks mainnet,fuji --approvals 2
+  avalanche config policy network.clean --phrase clean
+
+A policy can require to type a confirmation phrase, to wait a period of time that can be
+aborted with Ctrl+C, and to present approvals of the operation signed by a number of
+different keys trusted with config signing. Approvals are made with config approve, over
+the operation description printed by the command, and are given to it with --approval.
+
+--networks limits the policy to operations on the given networks. The policy is also
+enforced when the network is not given by a flag, as it is not known yet.
+
+Without arguments, lists the configured policies. Use --unset to remove one. Changing or
+removing a policy goes through the policy being changed.`,
+		RunE: setPolicy,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+	cmd.Flags().StringSliceVar(&policyNetworks, "networks", nil, "only enforce the policy on the given networks [local, devnet, fuji, mainnet]")
+	cmd.Flags().StringVar(&policyPhrase, "phrase", "", "require to type the given phrase to confirm the operation")
+	cmd.Flags().IntVar(&policyApprovals, "approvals", 0, "require approvals of the operation signed by the given number of trusted keys")
+	cmd.Flags().StringVar(&policyWait, "wait", "", "wait the given duration (eg 30s, 5m) before running the operation")
+	cmd.Flags().BoolVar(&unsetPolicy, "unset", false, "remove the policy of the given command")
+	return cmd
+}
+
+// avalanche config approve command
+func newApproveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve [operation]",
+		Short: "approve an operation under a confirmation policy",
+		Long: `sign the description of an operation that requires approvals by its confirmation policy.
+
+The operation description is printed by the command when run without enough approvals. It
+includes the command, its arguments and flags, and the day of execution, so approvals can't
+be reused for other operations. The approval file is given to the command with --approval.`,
+		RunE: approveOperation,
+		Args: cobrautils.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&approveSignKey, "sign-key", "", "sign the approval with the given minisign secret key")
+	cmd.Flags().StringVarP(&approveOutput, "output", "o", "", "write the approval to the given file path")
+	return cmd
+}
+
+func setPolicy(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return printPolicies()
+	}
+	target, err := cobrautils.FindCommandByConfigPath(cmd.Root(), args[0])
+	if err != nil {
+		return err
+	}
+	// normalize aliases into command names
+	commandPath := cobrautils.CommandConfigPath(target)
+	// the current policy also guards its own change or removal
+	if err := enforceCurrentPolicy(cmd, args, commandPath); err != nil {
+		return err
+	}
+	if unsetPolicy {
+		if err := policy.SetPolicy(app, commandPath, policy.ConfirmationPolicy{}); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Confirmation policy for %s removed", commandPath)
+		return nil
+	}
+	for _, network := range policyNetworks {
+		switch network {
+		case "local", "devnet", "fuji", "mainnet":
+		default:
+			return fmt.Errorf("invalid network %q: expected one of local, devnet, fuji, mainnet", network)
+		}
+	}
+	confirmationPolicy := policy.ConfirmationPolicy{
+		Networks:  policyNetworks,
+		Phrase:    policyPhrase,
+		Approvals: policyApprovals,
+		Wait:      policyWait,
+	}
+	if err := confirmationPolicy.Validate(); err != nil {
+		return err
+	}
+	if confirmationPolicy.Approvals > 0 {
+		keys, err := signing.TrustedKeys(app)
+		if err != nil {
+			return err
+		}
+		if len(keys) < confirmationPolicy.Approvals {
+			return fmt.Errorf(
+				"%d approvals require at least %d trusted signing keys, found %d. trust keys with config signing --trust",
+				confirmationPolicy.Approvals,
+				confirmationPolicy.Approvals,
+				len(keys),
+			)
+		}
+	}
+	if err := policy.SetPolicy(app, commandPath, confirmationPolicy); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Confirmation policy for %s set to %s", commandPath, confirmationPolicy)
+	return nil
+}
+
+// enforceCurrentPolicy makes the change of the policy of [commandPath] go through that
+// same policy, if there is one
+func enforceCurrentPolicy(cmd *cobra.Command, args []string, commandPath string) error {
+	currentPolicy, found, err := policy.GetPolicy(app, commandPath)
+	if err != nil || !found {
+		return err
+	}
+	approvalPaths, err := cmd.Flags().GetStringSlice(constants.ApprovalFlag)
+	if err != nil {
+		return err
+	}
+	return policy.Enforce(app, currentPolicy, policy.Operation(cmd, args, "", time.Now()), approvalPaths)
+}
+
+func printPolicies() error {
+	policies, err := policy.GetPolicies(app)
+	if err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		ux.Logger.PrintToUser("No confirmation policies configured")
+		return nil
+	}
+	commandPaths := make([]string, 0, len(policies))
+	for commandPath := range policies {
+		commandPaths = append(commandPaths, commandPath)
+	}
+	sort.Strings(commandPaths)
+	for _, commandPath := range commandPaths {
+		ux.Logger.PrintToUser("%s: %s", commandPath, policies[commandPath])
+	}
+	return nil
+}
+
+func approveOperation(_ *cobra.Command, args []string) error {
+	if approveSignKey == "" {
+		return errors.New("--sign-key is required")
+	}
+	sk, err := signing.LoadSecretKey(app, approveSignKey)
+	if err != nil {
+		return err
+	}
+	approval := policy.Approve(sk, args[0])
+	if approveOutput == "" {
+		fmt.Print(string(approval))
+		return nil
+	}
+	if err := os.WriteFile(approveOutput, approval, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Approval signed with key %s written to %s", sk.ID, approveOutput)
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/preset"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var unsetPresetRegistry bool
+
+// avalanche config preset-registry command
+func newPresetRegistryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preset-registry [registry]",
+		Short: "set the registry of blockchain presets",
+		Long: `set the default registry of the presets used by avalanche blockchain create --preset.
+
+The registry can be an HTTPS index (https://example.com/presets/index.json), a git repository
+(https://github.com/org/presets.git, optionally pinned with #branch, #tag or #commit, with
+index.json at its root), or a local dir. Without arguments, shows the registry and lists
+its presets.`,
+		RunE: presetRegistry,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+	cmd.Flags().BoolVar(&unsetPresetRegistry, "unset", false, "remove the preset registry")
+	return cmd
+}
+
+func presetRegistry(_ *cobra.Command, args []string) error {
+	if unsetPresetRegistry {
+		if len(args) > 0 {
+			return errors.New("--unset can't be used together with a registry")
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigPresetRegistryKey, ""); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Preset registry removed")
+		return nil
+	}
+	if len(args) == 1 {
+		// check that the registry is usable before saving it
+		if _, err := preset.List(app, args[0]); err != nil {
+			return fmt.Errorf("invalid preset registry %s: %w", args[0], err)
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigPresetRegistryKey, args[0]); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Preset registry set to %s", args[0])
+		return nil
+	}
+	registry, err := preset.GetRegistry(app, "")
+	if err != nil {
+		return err
+	}
+	index, err := preset.List(app, registry)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Preset Registry: %s", registry)
+	names := make([]string, 0, len(index.Presets))
+	for name := range index.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry := index.Presets[name]
+		ux.Logger.PrintToUser("  %s [%s] %s", name, strings.Join(entry.SortedVersions(), ", "), entry.Description)
+	}
+	return nil
+}
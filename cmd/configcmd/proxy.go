@@ -0,0 +1,97 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+type proxyFlags struct {
+	httpProxy  string
+	httpsProxy string
+	noProxy    string
+	sshProxy   string
+	unset      bool
+}
+
+var proxyCmdFlags proxyFlags
+
+// avalanche config proxy command
+func newProxyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "set the proxies used for outbound connections",
+		Long: `set the proxies used for the outbound connections of the CLI: downloads, RPC calls,
+cloud APIs, and SSH connections to cluster nodes.
+
+If not set, the standard environment variables are used: HTTP_PROXY, HTTPS_PROXY and
+NO_PROXY for HTTP(S) connections, and ALL_PROXY for SSH connections. The configured proxies
+are also passed to the processes the CLI spawns (eg ansible).
+
+SSH connections can go through socks5:// or http:// (CONNECT) proxies. The ssh and scp
+commands run or printed by the CLI use the CLI itself as ProxyCommand to do the same, so
+proxy credentials are kept in the config and never given on the command line.
+
+Without flags, shows the proxies in use.`,
+		RunE: proxyConfig,
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&proxyCmdFlags.httpProxy, "http", "", "proxy for http requests (http://, https:// or socks5:// URL)")
+	cmd.Flags().StringVar(&proxyCmdFlags.httpsProxy, "https", "", "proxy for https requests (http://, https:// or socks5:// URL)")
+	cmd.Flags().StringVar(&proxyCmdFlags.noProxy, "no-proxy", "", "comma separated hosts, domains, IPs or CIDRs to be reached directly")
+	cmd.Flags().StringVar(&proxyCmdFlags.sshProxy, "ssh", "", "proxy for SSH connections (socks5:// or http:// URL)")
+	cmd.Flags().BoolVar(&proxyCmdFlags.unset, "unset", false, "remove the configured proxies, falling back to the environment")
+	return cmd
+}
+
+func proxyConfig(cmd *cobra.Command, _ []string) error {
+	settings := []struct {
+		flag  string
+		key   string
+		value string
+	}{
+		{"http", constants.ConfigHTTPProxyKey, proxyCmdFlags.httpProxy},
+		{"https", constants.ConfigHTTPSProxyKey, proxyCmdFlags.httpsProxy},
+		{"no-proxy", constants.ConfigNoProxyKey, proxyCmdFlags.noProxy},
+		{"ssh", constants.ConfigSSHProxyKey, proxyCmdFlags.sshProxy},
+	}
+	if proxyCmdFlags.unset {
+		for _, setting := range settings {
+			if err := app.Conf.SetConfigValue(setting.key, ""); err != nil {
+				return err
+			}
+		}
+		ux.Logger.PrintToUser("Proxies removed")
+		return nil
+	}
+	changed := false
+	for _, setting := range settings {
+		if !cmd.Flags().Changed(setting.flag) {
+			continue
+		}
+		if setting.key != constants.ConfigNoProxyKey {
+			if err := utils.ValidateProxyURL(setting.value); err != nil {
+				return err
+			}
+		}
+		if err := app.Conf.SetConfigValue(setting.key, setting.value); err != nil {
+			return err
+		}
+		changed = true
+	}
+	if changed {
+		if err := utils.SetProxyConfig(app.Conf.GetProxyConfig()); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Proxies updated")
+	}
+	proxies := utils.GetProxyConfig()
+	ux.Logger.PrintToUser("HTTP Proxy:  %s", valueOrNone(proxies.HTTPProxy))
+	ux.Logger.PrintToUser("HTTPS Proxy: %s", valueOrNone(proxies.HTTPSProxy))
+	ux.Logger.PrintToUser("No Proxy:    %s", valueOrNone(proxies.NoProxy))
+	ux.Logger.PrintToUser("SSH Proxy:   %s", valueOrNone(proxies.SSHProxy))
+	return nil
+}
@@ -0,0 +1,27 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+// avalanche config readOnly command
+func newReadOnlyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "readOnly [enable | disable]",
+		Short: "opt in or out of read-only mode",
+		Long: `set user preference between running every command in read-only mode or not.
+
+In read-only mode only commands that inspect state (describe, list, status, ...) are allowed,
+and no local state is written. To disable it again, run this command with --read-only=false.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return handleBooleanSetting(cmd, constants.ConfigReadOnlyKey, args)
+		},
+		Args: cobrautils.MaximumNArgs(1),
+	}
+
+	return cmd
+}
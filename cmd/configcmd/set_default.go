@@ -0,0 +1,118 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var unsetDefault bool
+
+// avalanche config set-default command
+func newSetDefaultCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-default [command.flag] [value]",
+		Short: "set persistent default values for command flags",
+		Long: `set a default value for a flag of a given command, used whenever the flag is not given
+on the command line. The command is identified by its subcommands joined by dots, followed by
+the flag name, eg:
+
+  avalanche config set-default blockchain.deploy.network fuji
+  avalanche config set-default blockchain.create.evm-token GAS
+
+network is a special flag name that selects one of the network flags of the command
+(local, devnet, fuji, testnet, mainnet), or a named devnet. It is not applied if any
+network flag is given on the command line.
+
+Without arguments, lists the configured defaults. Use --unset to remove one.`,
+		RunE: setDefault,
+		Args: cobrautils.RangeArgs(0, 2),
+	}
+	cmd.Flags().BoolVar(&unsetDefault, "unset", false, "remove the default of the given command flag")
+	return cmd
+}
+
+func setDefault(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		printFlagDefaults()
+		return nil
+	}
+	key := args[0]
+	sep := strings.LastIndex(key, ".")
+	if sep <= 0 || sep == len(key)-1 {
+		return fmt.Errorf("invalid command flag %q: expected <command>.<flag>, eg blockchain.deploy.network", key)
+	}
+	commandPath, flagName := key[:sep], key[sep+1:]
+	target, err := cobrautils.FindCommandByConfigPath(cmd.Root(), commandPath)
+	if err != nil {
+		return err
+	}
+	// normalize aliases into command names
+	key = cobrautils.CommandConfigPath(target) + "." + flagName
+	configKey := constants.ConfigFlagDefaultsKey + "." + key
+	if unsetDefault {
+		if len(args) != 1 {
+			return errors.New("--unset does not take a value")
+		}
+		if err := app.Conf.SetConfigValue(configKey, ""); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Default for %s removed", key)
+		return nil
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("missing value for %s", key)
+	}
+	value := args[1]
+	if err := cobrautils.ValidateFlagDefault(target, flagName, value); err != nil {
+		return err
+	}
+	if err := app.Conf.SetConfigValue(configKey, value); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Default for %s set to %s", key, value)
+	return nil
+}
+
+// printFlagDefaults lists the configured flag defaults, as command.flag = value
+func printFlagDefaults() {
+	defaults := map[string]string{}
+	collectFlagDefaults("", app.Conf.GetConfigStringMapValue(constants.ConfigFlagDefaultsKey), defaults)
+	if len(defaults) == 0 {
+		ux.Logger.PrintToUser("No flag defaults configured")
+		return
+	}
+	keys := make([]string, 0, len(defaults))
+	for key := range defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		ux.Logger.PrintToUser("%s = %s", key, defaults[key])
+	}
+}
+
+// collectFlagDefaults flattens the nested config [values] found under [prefix] into [defaults]
+func collectFlagDefaults(prefix string, values map[string]interface{}, defaults map[string]string) {
+	for name, value := range values {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			collectFlagDefaults(key, nested, defaults)
+			continue
+		}
+		if str := fmt.Sprint(value); str != "" {
+			defaults[key] = str
+		}
+	}
+}
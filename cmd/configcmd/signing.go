@@ -0,0 +1,146 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/signing"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trustKeys          []string
+	untrustKeyIDs      []string
+	generateSigningKey string
+)
+
+// avalanche config signing command
+func newSigningCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signing [enable | disable]",
+		Short: "manage the keys trusted to sign blockchain exports and upgrade files",
+		Long: `manage the team keys trusted to sign exported blockchain definitions and upgrade files.
+
+Signatures are minisign compatible, so keys generated with minisign -G can be used, and
+signatures can also be checked with minisign -V. Secret keys must be password protected.
+Use --generate-key to create a new key pair, and --trust to trust a public key (a minisign
+public key file, or its base64 encoded key).
+
+blockchain export and blockchain upgrade export sign with --sign-key. blockchain import file and
+blockchain upgrade import verify the signature found next to the imported file. Enabling this
+setting rejects unsigned imports, and deploys to non local networks of blockchains not imported
+from a signed export, or whose genesis was modified after import.`,
+		RunE: signingSettings,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+	cmd.Flags().StringSliceVar(&trustKeys, "trust", nil, "trust the given public key file or base64 encoded public key")
+	cmd.Flags().StringSliceVar(&untrustKeyIDs, "untrust", nil, "stop trusting the public key with the given key id")
+	cmd.Flags().StringVar(&generateSigningKey, "generate-key", "", "generate a new key pair, saving the secret key at the given path and the public key with an additional .pub extension")
+	return cmd
+}
+
+func signingSettings(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && len(trustKeys) == 0 && len(untrustKeyIDs) == 0 && generateSigningKey == "" {
+		ux.Logger.PrintToUser(cmd.UsageString())
+		ux.Logger.PrintToUser("")
+		if signing.SignaturesRequired(app) {
+			ux.Logger.PrintToUser("Signatures Required: Enabled")
+		} else {
+			ux.Logger.PrintToUser("Signatures Required: Disabled")
+		}
+		return printTrustedKeys()
+	}
+	if generateSigningKey != "" {
+		if err := generateKeyPair(generateSigningKey); err != nil {
+			return err
+		}
+	}
+	for _, trustKey := range trustKeys {
+		encodedKey := trustKey
+		if utils.FileExists(trustKey) {
+			keyBytes, err := os.ReadFile(trustKey)
+			if err != nil {
+				return err
+			}
+			encodedKey = string(keyBytes)
+		}
+		pk, err := signing.ParsePublicKey(encodedKey)
+		if err != nil {
+			return fmt.Errorf("invalid public key %s: %w", trustKey, err)
+		}
+		if err := signing.AddTrustedKey(app, pk); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Key %s is now trusted", pk.ID)
+	}
+	for _, keyID := range untrustKeyIDs {
+		if err := signing.RemoveTrustedKey(app, keyID); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Key %s is no longer trusted", keyID)
+	}
+	if len(args) == 1 {
+		if args[0] == constants.Enable {
+			keys, err := signing.TrustedKeys(app)
+			if err != nil {
+				return err
+			}
+			if len(keys) == 0 {
+				return fmt.Errorf("trust at least one key before requiring signatures")
+			}
+		}
+		return handleBooleanSetting(cmd, constants.ConfigRequireSignaturesKey, args)
+	}
+	return nil
+}
+
+func generateKeyPair(secretKeyPath string) error {
+	publicKeyPath := secretKeyPath + ".pub"
+	if utils.FileExists(secretKeyPath) || utils.FileExists(publicKeyPath) {
+		return fmt.Errorf("key file %s or %s already exists", secretKeyPath, publicKeyPath)
+	}
+	password, err := app.Prompt.CapturePassword("Password for the new secret key")
+	if err != nil {
+		return err
+	}
+	sk, err := signing.GenerateKey()
+	if err != nil {
+		return err
+	}
+	skBytes, err := sk.Encode(password)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(secretKeyPath, skBytes, constants.WriteReadUserOnlyPerms); err != nil {
+		return err
+	}
+	if err := os.WriteFile(publicKeyPath, sk.Public().Encode(), constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Secret key written to %s", secretKeyPath)
+	ux.Logger.PrintToUser("Public key %s written to %s", sk.ID, publicKeyPath)
+	ux.Logger.PrintToUser("Share the public key with your team, to be trusted with avalanche config signing --trust %s", publicKeyPath)
+	return nil
+}
+
+func printTrustedKeys() error {
+	keys, err := signing.TrustedKeys(app)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		ux.Logger.PrintToUser("Trusted Keys: none")
+		return nil
+	}
+	ux.Logger.PrintToUser("Trusted Keys:")
+	for _, pk := range keys {
+		ux.Logger.PrintToUser("  %s %s", pk.ID, pk)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/statebackend"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var disableStateBackend bool
+
+// avalanche config state-backend command
+func newStateBackendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state-backend [url]",
+		Short: "share the state of clusters with a team through a cloud storage bucket",
+		Long: fmt.Sprintf(`set an S3 or GCS bucket where cluster definitions, node configs and deployment
+records are synced, so several teammates can manage the same cluster from different machines.
+
+Supported state backend URLs:
+  %s
+
+Commands changing a cluster (eg node create, node sync, node upgrade) lock the cluster on the
+bucket, pull its latest state, and push the resulting state when they succeed. Commands run on
+a cluster locked by a teammate fail until the lock is released. Locks are renewed while the
+command runs, and expire %s after the last renewal (eg: the command was killed).
+Use avalanche node state to pull, push or unlock clusters manually.
+
+Credentials are taken from the AWS shared config and environment variables, or from the
+Google application default credentials. Node staking and BLS keys are only shared
+if $%s is set, encrypted with it before upload. Otherwise they
+stay on the machine that created the nodes. SSH key paths under the home dir are shared relative to it, so each teammate
+needs the cluster SSH key at the same place in their own home dir.`,
+			strings.Join(statebackend.SupportedURLs, "\n  "),
+			constants.StateBackendLockTTL,
+			constants.StateBackendPassphraseEnvVarName,
+		),
+		RunE: stateBackend,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+	cmd.Flags().BoolVar(&disableStateBackend, "disable", false, "stop syncing clusters with the state backend")
+	return cmd
+}
+
+func stateBackend(cmd *cobra.Command, args []string) error {
+	switch {
+	case disableStateBackend:
+		if len(args) > 0 {
+			return errors.New("--disable can't be used together with a state backend URL")
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigStateBackendKey, ""); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("State backend disabled")
+		return nil
+	case len(args) == 0:
+		ux.Logger.PrintToUser(cmd.UsageString())
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("State Backend: %s", valueOrNone(app.Conf.GetConfigStringValue(constants.ConfigStateBackendKey)))
+		return nil
+	}
+	backendURL := args[0]
+	ctx, cancel := context.WithTimeout(context.Background(), constants.StateBackendTimeout)
+	defer cancel()
+	backend, err := statebackend.New(ctx, backendURL)
+	if err != nil {
+		return err
+	}
+	// reading a missing object checks both the credentials and the bucket access
+	if _, err := backend.Get(ctx, "access-check"); err != nil && !errors.Is(err, statebackend.ErrNotFound) {
+		return fmt.Errorf("failure accessing state backend %s: %w", backendURL, err)
+	}
+	if err := app.Conf.SetConfigValue(constants.ConfigStateBackendKey, backendURL); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("State backend set to %s", backendURL)
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/events"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hookScript     string
+	disableHooks   bool
+	testHooks      bool
+	supportedHooks = []events.Type{
+		events.DeployStarted,
+		events.DeploySucceeded,
+		events.DeployFailed,
+		events.ValidatorAdded,
+		events.ValidatorRemoved,
+		events.UpgradeApplied,
+		events.NodeUnhealthy,
+		events.ValidatorSetChanged,
+	}
+)
+
+// avalanche config webhook command
+func newWebhookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "webhook [url]",
+		Short: "send events of long running operations to a webhook or script",
+		Long: fmt.Sprintf(`set a webhook URL, and/or a local script hook, to receive a JSON event each time one of the
+following happens: %v.
+
+Events are POSTed to the webhook URL. Each event includes a human readable "text" field, so the
+URL can be a Slack incoming webhook. The script hook receives the event on its standard input,
+and its type on the AVALANCHE_CLI_EVENT environment variable, and can forward it anywhere
+(eg PagerDuty). node.unhealthy is emitted by node status, so run it periodically on monitored
+clusters. validator.changed is emitted by validator events --watch --forward.
+
+Delivery failures never fail the command emitting the event. Use --test to check the setup.`, supportedHooks),
+		RunE: webhook,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+	cmd.Flags().StringVar(&hookScript, "script", "", "local script to execute for each event")
+	cmd.Flags().BoolVar(&disableHooks, "disable", false, "remove the webhook URL and the script hook")
+	cmd.Flags().BoolVar(&testHooks, "test", false, "send a test event to the configured hooks")
+	return cmd
+}
+
+func webhook(cmd *cobra.Command, args []string) error {
+	switch {
+	case disableHooks:
+		if len(args) > 0 || hookScript != "" {
+			return errors.New("--disable can't be used together with a webhook URL or --script")
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigWebhookURLKey, ""); err != nil {
+			return err
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigHookScriptKey, ""); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Event hooks disabled")
+		return nil
+	case len(args) == 0 && hookScript == "" && !testHooks:
+		ux.Logger.PrintToUser(cmd.UsageString())
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Webhook URL: %s", valueOrNone(app.Conf.GetConfigStringValue(constants.ConfigWebhookURLKey)))
+		ux.Logger.PrintToUser("Script Hook: %s", valueOrNone(app.Conf.GetConfigStringValue(constants.ConfigHookScriptKey)))
+		return nil
+	}
+	if len(args) == 1 {
+		webhookURL, err := url.ParseRequestURI(args[0])
+		if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") {
+			return fmt.Errorf("invalid webhook URL %q", args[0])
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigWebhookURLKey, args[0]); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Webhook URL set to %s", args[0])
+	}
+	if hookScript != "" {
+		if !utils.FileExists(hookScript) {
+			return fmt.Errorf("script hook %s not found", hookScript)
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigHookScriptKey, hookScript); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Script hook set to %s", hookScript)
+	}
+	if testHooks {
+		if !events.Configured(app) {
+			return errors.New("no webhook URL nor script hook configured")
+		}
+		if err := events.Deliver(app, events.Event{
+			Type:      events.Test,
+			Timestamp: time.Now().UTC(),
+			Text:      "Avalanche-CLI test event",
+		}); err != nil {
+			return err
+		}
+		ux.Logger.GreenCheckmarkToUser("Test event delivered")
+	}
+	return nil
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
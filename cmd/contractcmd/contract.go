@@ -16,7 +16,7 @@ func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 		Use:   "contract",
 		Short: "Manage smart contracts",
 		Long: `The contract command suite provides a collection of tools for deploying
-and interacting with smart contracts.`,
+and interacting with smart contracts, and for encoding and decoding contract calls.`,
 		RunE: cobrautils.CommandSuiteUsage,
 	}
 	app = injectedApp
@@ -24,5 +24,9 @@ and interacting with smart contracts.`,
 	cmd.AddCommand(newDeployCmd())
 	// contract initValidatorManager
 	cmd.AddCommand(newInitValidatorManagerCmd())
+	// contract encode
+	cmd.AddCommand(newEncodeCmd())
+	// contract decode
+	cmd.AddCommand(newDecodeCmd())
 	return cmd
 }
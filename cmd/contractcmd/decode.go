@@ -0,0 +1,111 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package contractcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	validatormanagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/spf13/cobra"
+)
+
+type DecodeFlags struct {
+	calldata   string
+	revert     string
+	topics     []string
+	data       string
+	signatures []string
+}
+
+var decodeFlags DecodeFlags
+
+// avalanche contract decode
+func newDecodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decode",
+		Short: "Decode calldata, revert data, or event logs",
+		Long: `The contract decode command decodes either method calldata (--calldata), revert
+data (--revert), or an event log (--topics and --data).
+
+The method, error, or event is identified among the signatures given with --signature, and
+the ones of the validator manager, ownable, and proxy admin contracts. Builtin solidity
+errors are always recognized. Indexed event parameters are marked in the signature, eg
+--signature "Transfer(address indexed,address indexed,uint256)".`,
+		RunE: decode,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&decodeFlags.calldata, "calldata", "", "decode the given hex encoded method calldata")
+	cmd.Flags().StringVar(&decodeFlags.revert, "revert", "", "decode the given hex encoded revert data")
+	cmd.Flags().StringSliceVar(&decodeFlags.topics, "topics", nil, "decode an event log with the given topics")
+	cmd.Flags().StringVar(&decodeFlags.data, "data", "", "hex encoded data of the event log to decode")
+	cmd.Flags().StringArrayVar(&decodeFlags.signatures, "signature", nil, "additional method, error or event signature to decode with")
+	return cmd
+}
+
+func decode(_ *cobra.Command, _ []string) error {
+	set := 0
+	for _, defined := range []bool{decodeFlags.calldata != "", decodeFlags.revert != "", len(decodeFlags.topics) > 0} {
+		if defined {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of --calldata, --revert or --topics must be given")
+	}
+	var (
+		decoded *contract.Decoded
+		err     error
+	)
+	switch {
+	case decodeFlags.calldata != "":
+		calldata, err := hexutil.Decode(decodeFlags.calldata)
+		if err != nil {
+			return fmt.Errorf("invalid calldata: %w", err)
+		}
+		decoded, err = contract.DecodeCalldata(append(decodeFlags.signatures, validatormanagerSDK.MethodSignatures...), calldata)
+		if err != nil {
+			return err
+		}
+	case decodeFlags.revert != "":
+		revertData, err := hexutil.Decode(decodeFlags.revert)
+		if err != nil {
+			return fmt.Errorf("invalid revert data: %w", err)
+		}
+		decoded, err = contract.DecodeRevert(append(decodeFlags.signatures, validatormanagerSDK.ErrorSignatures()...), revertData)
+		if err != nil {
+			return err
+		}
+	default:
+		topics := make([]common.Hash, len(decodeFlags.topics))
+		for i, topic := range decodeFlags.topics {
+			topicBytes, err := hexutil.Decode(topic)
+			if err != nil || len(topicBytes) != common.HashLength {
+				return fmt.Errorf("invalid topic %q", topic)
+			}
+			topics[i] = common.BytesToHash(topicBytes)
+		}
+		data := []byte{}
+		if decodeFlags.data != "" {
+			data, err = hexutil.Decode(decodeFlags.data)
+			if err != nil {
+				return fmt.Errorf("invalid log data: %w", err)
+			}
+		}
+		decoded, err = contract.DecodeLog(append(decodeFlags.signatures, validatormanagerSDK.EventSignatures...), topics, data)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("%s", decoded.Signature.Canonical)
+	for i, value := range decoded.Values {
+		input := decoded.Signature.Inputs[i]
+		ux.Logger.PrintToUser("  %s (%s): %s", input.Name, input.Type, contract.FormatABIValue(value))
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package contractcmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/custody"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/spf13/cobra"
+)
+
+type EncodeFlags struct {
+	method       string
+	args         []string
+	to           string
+	from         string
+	rpcURL       string
+	value        string
+	outputTxPath string
+}
+
+var encodeFlags EncodeFlags
+
+// avalanche contract encode
+func newEncodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encode",
+		Short: "Encode calldata for a contract method call",
+		Long: `The contract encode command prints the ABI encoded calldata to call a contract method,
+so it can be executed by external signing tools, eg multisig wallets.
+
+The method is given by its signature, eg --method "transferOwnership(address)". Tuple types
+are given as "(type1,type2)". Each argument is given with its own --args flag, in order.
+Numbers can be decimal or 0x prefixed hex, bytes are hex encoded, and tuple and array
+arguments are given as "(value1,value2)" and "[value1,value2]".
+
+With --output-tx-path, instead of printing the calldata, an unsigned tx from --from to the
+contract at --to is built against --rpc and saved, so its signing payload can be exported
+with 'avalanche transaction export' for custodial signers.`,
+		RunE: encode,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&encodeFlags.method, "method", "", "signature of the method to call")
+	cmd.Flags().StringArrayVar(&encodeFlags.args, "args", nil, "method argument. repeat for each argument, in order")
+	cmd.Flags().StringVar(&encodeFlags.outputTxPath, "output-tx-path", "", "save an unsigned tx calling the method to this file")
+	cmd.Flags().StringVar(&encodeFlags.to, "to", "", "contract address to call (with --output-tx-path)")
+	cmd.Flags().StringVar(&encodeFlags.from, "from", "", "address that will sign the tx (with --output-tx-path)")
+	cmd.Flags().StringVar(&encodeFlags.rpcURL, "rpc", "", "rpc endpoint of the chain the tx is for (with --output-tx-path)")
+	cmd.Flags().StringVar(&encodeFlags.value, "value", "0", "amount of native tokens, in wei, to send with the call (with --output-tx-path)")
+	return cmd
+}
+
+func encode(_ *cobra.Command, _ []string) error {
+	if encodeFlags.method == "" {
+		return fmt.Errorf("--method is required")
+	}
+	calldata, err := contract.EncodeCalldata(encodeFlags.method, encodeFlags.args)
+	if err != nil {
+		return err
+	}
+	if encodeFlags.outputTxPath != "" {
+		return saveUnsignedTx(calldata)
+	}
+	ux.Logger.PrintToUser("%s", hexutil.Encode(calldata))
+	return nil
+}
+
+func saveUnsignedTx(calldata []byte) error {
+	switch {
+	case !common.IsHexAddress(encodeFlags.to):
+		return fmt.Errorf("--to must be a valid address")
+	case !common.IsHexAddress(encodeFlags.from):
+		return fmt.Errorf("--from must be a valid address")
+	case encodeFlags.rpcURL == "":
+		return fmt.Errorf("--rpc is required")
+	}
+	value, ok := new(big.Int).SetString(encodeFlags.value, 10)
+	if !ok || value.Sign() < 0 {
+		return fmt.Errorf("invalid value %q", encodeFlags.value)
+	}
+	client, err := evm.GetClient(encodeFlags.rpcURL)
+	if err != nil {
+		return err
+	}
+	from := common.HexToAddress(encodeFlags.from)
+	tx, err := evm.GetUnsignedTx(client, from, common.HexToAddress(encodeFlags.to), calldata, value)
+	if err != nil {
+		return err
+	}
+	if err := custody.SaveEVMTx(custody.EVMTx{
+		RPCURL: encodeFlags.rpcURL,
+		From:   from,
+		Tx:     tx,
+	}, encodeFlags.outputTxPath, false); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Unsigned tx saved to %s", encodeFlags.outputTxPath)
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
@@ -29,6 +31,8 @@ type ValidatorManagerFlags struct {
 	aggregatorLogLevel          string
 	aggregatorExtraEndpoints    []string
 	aggregatorAllowPrivatePeers bool
+	aggregationFlags            interchain.AggregationFlags
+	allowUnapprovedKey          bool
 }
 
 type POSManagerSpecFlags struct {
@@ -39,6 +43,8 @@ type POSManagerSpecFlags struct {
 	minimumDelegationFee    uint16
 	maximumStakeMultiplier  uint8
 	weightToValueFactor     uint64 // big.Int
+	stakingTokenAddress     string
+	deployStakingToken      bool
 }
 
 var (
@@ -67,6 +73,8 @@ func newInitValidatorManagerCmd() *cobra.Command {
 	cmd.Flags().StringSliceVar(&validatorManagerFlags.aggregatorExtraEndpoints, "aggregator-extra-endpoints", nil, "endpoints for extra nodes that are needed in signature aggregation")
 	cmd.Flags().BoolVar(&validatorManagerFlags.aggregatorAllowPrivatePeers, "aggregator-allow-private-peers", true, "allow the signature aggregator to connect to peers with private IP")
 	cmd.Flags().StringVar(&validatorManagerFlags.aggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	interchain.AddAggregationFlagsToCmd(cmd, &validatorManagerFlags.aggregationFlags)
+	keychain.AddAllowUnapprovedKeyFlag(cmd, &validatorManagerFlags.allowUnapprovedKey)
 
 	cmd.Flags().StringVar(&initPOSManagerFlags.rewardCalculatorAddress, "pos-reward-calculator-address", "", "(PoS only) initialize the ValidatorManager with reward calculator address")
 	cmd.Flags().Uint64Var(&initPOSManagerFlags.minimumStakeAmount, "pos-minimum-stake-amount", 1, "(PoS only) minimum stake amount")
@@ -75,6 +83,8 @@ func newInitValidatorManagerCmd() *cobra.Command {
 	cmd.Flags().Uint16Var(&initPOSManagerFlags.minimumDelegationFee, "pos-minimum-delegation-fee", 1, "(PoS only) minimum delegation fee")
 	cmd.Flags().Uint8Var(&initPOSManagerFlags.maximumStakeMultiplier, "pos-maximum-stake-multiplier", 1, "(PoS only )maximum stake multiplier")
 	cmd.Flags().Uint64Var(&initPOSManagerFlags.weightToValueFactor, "pos-weight-to-value-factor", 1, "(PoS only) weight to value factor")
+	cmd.Flags().StringVar(&initPOSManagerFlags.stakingTokenAddress, "staking-token-address", "", "(ERC20 token PoS only) ERC20 token to be staked on the validator manager")
+	cmd.Flags().BoolVar(&initPOSManagerFlags.deployStakingToken, "deploy-staking-token", false, "(ERC20 token PoS only, local and devnet) deploy a mintable example ERC20 token to be staked on the validator manager")
 	return cmd
 }
 
@@ -136,6 +146,9 @@ func initValidatorManager(_ *cobra.Command, args []string) error {
 			return err
 		}
 	}
+	if err := keychain.CheckPrivateKeyOnNetwork(app, network, privateKey, validatorManagerFlags.allowUnapprovedKey); err != nil {
+		return err
+	}
 	sc, err := app.LoadSidecar(chainSpec.BlockchainName)
 	if err != nil {
 		return fmt.Errorf("failed to load sidecar: %w", err)
@@ -171,12 +184,24 @@ func initValidatorManager(_ *cobra.Command, args []string) error {
 		return err
 	}
 	ownerAddress := common.HexToAddress(sc.ProxyContractOwner)
+	if sc.ProxyContractOwner == "" {
+		// converted legacy subnets have no proxy
+		ownerAddress = common.HexToAddress(sc.ValidatorManagerOwner)
+	}
+	managerAddress := validatormanager.GetValidatorManagerAddress(scNetwork)
+	aggregationConfig, err := interchain.GetAggregationConfig(app, validatorManagerFlags.aggregationFlags)
+	if err != nil {
+		return err
+	}
 	subnetSDK := blockchainSDK.Subnet{
-		SubnetID:            subnetID,
-		BlockchainID:        blockchainID,
-		BootstrapValidators: avaGoBootstrapValidators,
-		OwnerAddress:        &ownerAddress,
-		RPC:                 validatorManagerFlags.rpcEndpoint,
+		SubnetID:                subnetID,
+		BlockchainID:            blockchainID,
+		BootstrapValidators:     avaGoBootstrapValidators,
+		OwnerAddress:            &ownerAddress,
+		RPC:                     validatorManagerFlags.rpcEndpoint,
+		Logger:                  ux.Logger.WithUserWarnings(app.Log),
+		ValidatorManagerAddress: &managerAddress,
+		AggregationConfig:       aggregationConfig,
 	}
 	switch {
 	case sc.PoA(): // PoA
@@ -193,7 +218,22 @@ func initValidatorManager(_ *cobra.Command, args []string) error {
 		}
 		ux.Logger.GreenCheckmarkToUser("Proof of Authority Validator Manager contract successfully initialized on blockchain %s", blockchainName)
 	case sc.PoS(): // PoS
-		ux.Logger.PrintToUser(logging.Yellow.Wrap("Initializing Proof of Stake Validator Manager contract on blockchain %s"), blockchainName)
+		stakingToken, stakingTokenAddress := models.NativeTokenStaking, ""
+		if sc.ERC20Staking() {
+			stakingToken = models.ERC20TokenStaking
+			stakingTokenAddress, err = blockchaincmd.SetupStakingToken(
+				&sc,
+				network,
+				validatorManagerFlags.rpcEndpoint,
+				privateKey,
+				initPOSManagerFlags.stakingTokenAddress,
+				initPOSManagerFlags.deployStakingToken,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Initializing %s Proof of Stake Validator Manager contract on blockchain %s"), stakingToken, blockchainName)
 		if initPOSManagerFlags.rewardCalculatorAddress == "" {
 			initPOSManagerFlags.rewardCalculatorAddress = validatorManagerSDK.RewardCalculatorAddress
 		}
@@ -212,11 +252,12 @@ func initValidatorManager(_ *cobra.Command, args []string) error {
 				MaximumStakeMultiplier:  initPOSManagerFlags.maximumStakeMultiplier,
 				WeightToValueFactor:     big.NewInt(int64(initPOSManagerFlags.weightToValueFactor)),
 				RewardCalculatorAddress: initPOSManagerFlags.rewardCalculatorAddress,
+				StakingTokenAddress:     stakingTokenAddress,
 			},
 		); err != nil {
 			return err
 		}
-		ux.Logger.GreenCheckmarkToUser("Native Token Proof of Stake Validator Manager contract successfully initialized on blockchain %s", blockchainName)
+		ux.Logger.GreenCheckmarkToUser("%s Proof of Stake Validator Manager contract successfully initialized on blockchain %s", stakingToken, blockchainName)
 	default: // unsupported
 		return fmt.Errorf("only PoA and PoS supported")
 	}
@@ -0,0 +1,131 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package doctorcmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/cmd/flags"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/doctor"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+const localEnvironmentOnly = "Only the local environment"
+
+var (
+	app            *application.Avalanche
+	blockchainName string
+	clusterName    string
+	localOnly      bool
+)
+
+// avalanche doctor
+func NewCmd(injectedApp *application.Avalanche, version string) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Troubleshoot the local environment and deployed resources",
+		Long: `The doctor command inspects the local environment the CLI runs on, and optionally a
+deployed blockchain or node cluster, and prints the problems found ranked by severity,
+together with the command or action that fixes each of them.
+
+On the local environment it checks the ports the local network needs, the free disk space,
+the CLI and local network avalanchego versions, stale backend processes, corrupted
+blockchain and cluster files, and stored keys that can't be loaded.
+
+For a blockchain it checks its local network deployment and that its RPC endpoints answer.
+For a cluster it checks the SSH keys of the nodes, that they are reachable, and that
+avalanchego is healthy on them.
+
+Without --blockchain or --cluster, asks which deployed resource to inspect. The command
+fails if critical problems are found.`,
+		RunE:    runDoctor,
+		Args:    cobrautils.ExactArgs(0),
+		Version: version,
+	}
+	cmd.Flags().StringVar(&blockchainName, "blockchain", "", "also inspect the deployments of the given blockchain")
+	cmd.Flags().StringVar(&clusterName, "cluster", "", "also inspect the nodes of the given cluster")
+	cmd.Flags().BoolVar(&localOnly, "local-only", false, "only inspect the local environment")
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, _ []string) error {
+	if !flags.EnsureMutuallyExclusive([]bool{blockchainName != "", clusterName != "", localOnly}) {
+		return fmt.Errorf("--blockchain, --cluster and --local-only are mutually exclusive")
+	}
+	if blockchainName == "" && clusterName == "" && !localOnly {
+		if err := promptResource(); err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("Inspecting the local environment...")
+	findings := doctor.CheckLocalEnvironment(app, cmd.Version)
+	if blockchainName != "" {
+		ux.Logger.PrintToUser("Inspecting blockchain %s...", blockchainName)
+		findings = append(findings, doctor.CheckBlockchain(app, blockchainName)...)
+	}
+	if clusterName != "" {
+		ux.Logger.PrintToUser("Inspecting cluster %s...", clusterName)
+		findings = append(findings, doctor.CheckCluster(app, clusterName)...)
+	}
+	ux.Logger.PrintToUser("")
+	if len(findings) == 0 {
+		ux.Logger.GreenCheckmarkToUser("No problems found")
+		return nil
+	}
+	header := table.Row{"#", "Severity", "Area", "Problem", "Remediation"}
+	t := ux.DefaultTable("Findings", header)
+	for i, finding := range doctor.RankFindings(findings) {
+		remediation := finding.Remediation
+		if remediation == "" {
+			remediation = "-"
+		}
+		t.AppendRow(table.Row{i + 1, finding.Severity.String(), finding.Area, finding.Problem, remediation})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	if doctor.HasCritical(findings) {
+		return fmt.Errorf("critical problems found")
+	}
+	return nil
+}
+
+// promptResource asks for the deployed blockchain or cluster to inspect, if there is any.
+// Blockchains and clusters whose files can't be read are left out, as they are reported
+// by the local environment checks
+func promptResource() error {
+	blockchainNames, _ := app.GetBlockchainNames()
+	clusterNames := []string{}
+	if clustersConfig, err := app.GetClustersConfig(); err == nil {
+		clusterNames = maps.Keys(clustersConfig.Clusters)
+		sort.Strings(clusterNames)
+	}
+	if len(blockchainNames) == 0 && len(clusterNames) == 0 {
+		return nil
+	}
+	options := []string{localEnvironmentOnly}
+	resources := map[string]func(){}
+	for _, name := range blockchainNames {
+		option := fmt.Sprintf("Blockchain %s", name)
+		options = append(options, option)
+		resources[option] = func() { blockchainName = name }
+	}
+	for _, name := range clusterNames {
+		option := fmt.Sprintf("Cluster %s", name)
+		options = append(options, option)
+		resources[option] = func() { clusterName = name }
+	}
+	option, err := app.Prompt.CaptureList("Which deployed resource do you want to inspect besides the local environment?", options)
+	if err != nil {
+		return err
+	}
+	if setResource, ok := resources[option]; ok {
+		setResource()
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package messengercmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+const (
+	benchFormatTable = "table"
+	benchFormatJSON  = "json"
+	benchFormatCSV   = "csv"
+
+	benchArrivalCheckInterval = 200 * time.Millisecond
+)
+
+type BenchFlags struct {
+	Network           networkoptions.NetworkFlags
+	PrivateKeyFlags   contract.PrivateKeyFlags
+	Source            string
+	Dest              string
+	SourceRPCEndpoint string
+	DestRPCEndpoint   string
+	Messages          int
+	Rate              float64
+	Timeout           time.Duration
+	Format            string
+	Output            string
+}
+
+var benchFlags BenchFlags
+
+// pending message to be checked for arrival at destination
+type benchPendingMessage struct {
+	index      int
+	messageID  ids.ID
+	acceptedAt time.Time
+}
+
+// avalanche interchain messenger bench
+func NewBenchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure ICM message delivery latency between two blockchains",
+		Long: `The bench command sends a batch of ICM test messages from a source blockchain to a
+destination blockchain, and measures the delivery latency of each one, reporting
+latency percentiles and failure counts.
+
+Messages are sent from a single key, one after the other, at up to --rate messages
+per second. The achieved send rate is included in the report, and can be lower if
+the source blockchain takes longer than 1/rate to accept each send tx.
+
+Latencies are reported as:
+  - source: from issuing the send tx until it is accepted at source
+  - delivery: from source acceptance until the message is received at destination,
+    which measures the relayer
+  - end to end: the sum of both
+
+Delivery is checked every 200ms, which bounds the latency measurement precision.
+With --format json or csv, the full per message results are also written, to stdout
+or to the file given by --output. JSON latencies are given in nanoseconds, CSV ones
+in milliseconds. When written to stdout, progress messages and the summary are
+printed to stderr, so stdout only holds the results.`,
+		RunE: bench,
+		Args: cobrautils.ExactArgs(0),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &benchFlags.Network, true, msgSupportedNetworkOptions)
+	benchFlags.PrivateKeyFlags.AddToCmd(cmd, "as message originator and to pay source blockchain fees")
+	cmd.Flags().StringVar(&benchFlags.Source, "source", "", "source blockchain name (or c-chain)")
+	cmd.Flags().StringVar(&benchFlags.Dest, "dest", "", "destination blockchain name (or c-chain)")
+	cmd.Flags().StringVar(&benchFlags.SourceRPCEndpoint, "source-rpc", "", "use the given source blockchain rpc endpoint")
+	cmd.Flags().StringVar(&benchFlags.DestRPCEndpoint, "dest-rpc", "", "use the given destination blockchain rpc endpoint")
+	cmd.Flags().IntVar(&benchFlags.Messages, "messages", 100, "number of messages to send")
+	cmd.Flags().Float64Var(&benchFlags.Rate, "rate", 10, "max number of messages to send per second")
+	cmd.Flags().DurationVar(&benchFlags.Timeout, "timeout", 30*time.Second, "max time to wait for each message to be delivered")
+	cmd.Flags().StringVar(&benchFlags.Format, "format", benchFormatTable, "output format (table, json or csv)")
+	cmd.Flags().StringVar(&benchFlags.Output, "output", "", "write json or csv results to the given file instead of stdout")
+	return cmd
+}
+
+func bench(_ *cobra.Command, _ []string) error {
+	switch benchFlags.Format {
+	case benchFormatTable, benchFormatJSON, benchFormatCSV:
+	default:
+		return fmt.Errorf("invalid format %q: expected one of %s, %s, %s", benchFlags.Format, benchFormatTable, benchFormatJSON, benchFormatCSV)
+	}
+	if benchFlags.Output != "" && benchFlags.Format == benchFormatTable {
+		return fmt.Errorf("--output requires --format %s or %s", benchFormatJSON, benchFormatCSV)
+	}
+	if benchFlags.Messages <= 0 {
+		return fmt.Errorf("--messages must be positive")
+	}
+	if benchFlags.Rate <= 0 {
+		return fmt.Errorf("--rate must be positive")
+	}
+	if benchFlags.Source == "" || benchFlags.Dest == "" {
+		return fmt.Errorf("--source and --dest blockchains must be given")
+	}
+	if benchFlags.Format != benchFormatTable && benchFlags.Output == "" {
+		// keep stdout for the results only
+		prevWriter := ux.Logger.SetWriter(os.Stderr)
+		defer ux.Logger.SetWriter(prevWriter)
+	}
+
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		benchFlags.Network,
+		true,
+		false,
+		msgSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	sourceRPCEndpoint, _, sourceMessengerAddress, err := getICMChainInfo(
+		network,
+		benchFlags.Source,
+		benchFlags.SourceRPCEndpoint,
+	)
+	if err != nil {
+		return err
+	}
+	destRPCEndpoint, destBlockchainID, destMessengerAddress, err := getICMChainInfo(
+		network,
+		benchFlags.Dest,
+		benchFlags.DestRPCEndpoint,
+	)
+	if err != nil {
+		return err
+	}
+	if sourceMessengerAddress != destMessengerAddress {
+		return fmt.Errorf("different ICM messenger addresses among blockchains: %s vs %s", sourceMessengerAddress, destMessengerAddress)
+	}
+
+	genesisAddress, genesisPrivateKey, err := contract.GetEVMSubnetPrefundedKey(
+		app,
+		network,
+		contract.ChainSpec{
+			BlockchainName: benchFlags.Source,
+			CChain:         isCChain(benchFlags.Source),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	privateKey, err := benchFlags.PrivateKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	if err != nil {
+		return err
+	}
+	if privateKey == "" {
+		privateKey, err = prompts.PromptPrivateKey(
+			app.Prompt,
+			"pay for fees at source blockchain",
+			app.GetKeyDir(),
+			app.GetKey,
+			genesisAddress,
+			genesisPrivateKey,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	ux.Logger.PrintToUser(
+		"Sending %d messages from %s to %s at up to %.2f messages per second",
+		benchFlags.Messages,
+		benchFlags.Source,
+		benchFlags.Dest,
+		benchFlags.Rate,
+	)
+	results := make([]interchain.BenchMessageResult, benchFlags.Messages)
+	pending := make(chan benchPendingMessage, benchFlags.Messages)
+	pollerDone := make(chan struct{})
+	go func() {
+		defer close(pollerDone)
+		waitBenchDeliveries(
+			destRPCEndpoint,
+			common.HexToAddress(destMessengerAddress),
+			benchFlags.Timeout,
+			pending,
+			results,
+		)
+	}()
+
+	start := time.Now()
+	interval := time.Duration(float64(time.Second) / benchFlags.Rate)
+	for i := range results {
+		time.Sleep(time.Until(start.Add(time.Duration(i) * interval)))
+		results[i].Index = i
+		results[i].SentAt = time.Now()
+		messageID, err := sendBenchMessage(
+			sourceRPCEndpoint,
+			common.HexToAddress(sourceMessengerAddress),
+			privateKey,
+			destBlockchainID,
+			[]byte(fmt.Sprintf("avalanche-cli icm bench message %d", i)),
+		)
+		if err != nil {
+			results[i].Error = err.Error()
+			ux.Logger.RedXToUser("failure sending message %d: %s", i, err)
+			continue
+		}
+		acceptedAt := time.Now()
+		results[i].MessageID = messageID
+		results[i].SourceLatency = acceptedAt.Sub(results[i].SentAt)
+		pending <- benchPendingMessage{
+			index:      i,
+			messageID:  messageID,
+			acceptedAt: acceptedAt,
+		}
+		if (i+1)%10 == 0 {
+			ux.Logger.PrintToUser("Sent %d/%d messages", i+1, len(results))
+		}
+	}
+	close(pending)
+	ux.Logger.PrintToUser("Waiting for pending messages to be delivered")
+	<-pollerDone
+
+	report := interchain.BenchReport{
+		Summary: interchain.SummarizeBench(results, time.Since(start)),
+		Results: results,
+	}
+	printBenchSummary(report.Summary)
+	if benchFlags.Format == benchFormatTable {
+		return nil
+	}
+	var w io.Writer = os.Stdout
+	if benchFlags.Output != "" {
+		f, err := os.Create(benchFlags.Output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if benchFlags.Format == benchFormatJSON {
+		err = interchain.WriteBenchJSON(w, report)
+	} else {
+		err = interchain.WriteBenchCSV(w, report.Results)
+	}
+	if err != nil {
+		return err
+	}
+	if benchFlags.Output != "" {
+		ux.Logger.PrintToUser("Results written to %s", benchFlags.Output)
+	}
+	return nil
+}
+
+// sendBenchMessage sends [message] from the source blockchain, waiting for the tx to be
+// accepted, and returns the ICM message ID
+func sendBenchMessage(
+	rpcURL string,
+	messengerAddress common.Address,
+	privateKey string,
+	destBlockchainID ids.ID,
+	message []byte,
+) (ids.ID, error) {
+	_, receipt, err := interchain.SendCrossChainMessage(
+		rpcURL,
+		messengerAddress,
+		privateKey,
+		destBlockchainID,
+		common.Address{},
+		message,
+	)
+	if err != nil {
+		return ids.Empty, err
+	}
+	event, err := evm.GetEventFromLogs(receipt.Logs, interchain.ParseSendCrossChainMessage)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return ids.ID(event.MessageID), nil
+}
+
+// waitBenchDeliveries checks the pending messages for arrival at destination, filling
+// the delivery info on [results], until [pending] is closed and all messages were
+// either delivered or timed out
+func waitBenchDeliveries(
+	rpcURL string,
+	messengerAddress common.Address,
+	timeout time.Duration,
+	pending <-chan benchPendingMessage,
+	results []interchain.BenchMessageResult,
+) {
+	waiting := []benchPendingMessage{}
+	pendingClosed := false
+	for !pendingClosed || len(waiting) > 0 {
+		// collect new messages without blocking
+	collect:
+		for {
+			select {
+			case msg, ok := <-pending:
+				if !ok {
+					pendingClosed = true
+					break collect
+				}
+				waiting = append(waiting, msg)
+			default:
+				break collect
+			}
+		}
+		stillWaiting := []benchPendingMessage{}
+		for _, msg := range waiting {
+			received, err := interchain.MessageReceived(rpcURL, messengerAddress, msg.messageID)
+			now := time.Now()
+			switch {
+			case err == nil && received:
+				results[msg.index].Delivered = true
+				results[msg.index].DeliveryLatency = now.Sub(msg.acceptedAt)
+			case now.Sub(msg.acceptedAt) > timeout:
+				results[msg.index].Error = "timeout waiting for message to be delivered"
+				if err != nil {
+					results[msg.index].Error += ": " + err.Error()
+				}
+			default:
+				if err != nil {
+					ux.Logger.Info("failure checking message %s arrival: %s", msg.messageID, err)
+				}
+				stillWaiting = append(stillWaiting, msg)
+			}
+		}
+		waiting = stillWaiting
+		time.Sleep(benchArrivalCheckInterval)
+	}
+}
+
+func printBenchSummary(summary interchain.BenchSummary) {
+	ux.Logger.PrintToUser("")
+	t := ux.DefaultTable("ICM Bench Summary", nil)
+	t.AppendRow(table.Row{"Messages", summary.Messages})
+	t.AppendRow(table.Row{"Delivered", summary.Delivered})
+	t.AppendRow(table.Row{"Send Failures", summary.SendFailures})
+	t.AppendRow(table.Row{"Delivery Timeouts", summary.DeliveryTimeout})
+	t.AppendRow(table.Row{"Duration", summary.Duration.Round(time.Millisecond)})
+	t.AppendRow(table.Row{"Achieved Send Rate", fmt.Sprintf("%.2f msg/s", summary.SendRate)})
+	ux.Logger.PrintToUser(t.Render())
+	if summary.Delivered == 0 {
+		return
+	}
+	t = ux.DefaultTable("Latency", table.Row{"", "Min", "Avg", "P50", "P90", "P99", "Max"})
+	for _, row := range []struct {
+		name  string
+		stats interchain.LatencyStats
+	}{
+		{"Source", summary.SourceLatency},
+		{"Delivery", summary.DeliveryLatency},
+		{"End to End", summary.EndToEndLatency},
+	} {
+		t.AppendRow(table.Row{
+			row.name,
+			row.stats.Min.Round(time.Millisecond),
+			row.stats.Avg.Round(time.Millisecond),
+			row.stats.P50.Round(time.Millisecond),
+			row.stats.P90.Round(time.Millisecond),
+			row.stats.P99.Round(time.Millisecond),
+			row.stats.Max.Round(time.Millisecond),
+		})
+	}
+	ux.Logger.PrintToUser(t.Render())
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
 	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
 	"github.com/ava-labs/avalanche-cli/pkg/localnet"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
@@ -35,6 +36,7 @@ type DeployFlags struct {
 	PrivateKeyFlags              contract.PrivateKeyFlags
 	IncludeCChain                bool
 	CChainKeyName                string
+	AllowUnapprovedKey           bool
 }
 
 const (
@@ -75,6 +77,7 @@ func NewDeployCmd() *cobra.Command {
 	cmd.Flags().StringVar(&deployFlags.RegistryBydecodePath, "registry-bytecode-path", "", "path to a registry bytecode file")
 	cmd.Flags().BoolVar(&deployFlags.IncludeCChain, "include-cchain", false, "deploy ICM also to C-Chain")
 	cmd.Flags().StringVar(&deployFlags.CChainKeyName, "cchain-key", "", "key to be used to pay fees to deploy ICM to C-Chain")
+	keychain.AddAllowUnapprovedKeyFlag(cmd, &deployFlags.AllowUnapprovedKey)
 	return cmd
 }
 
@@ -152,6 +155,9 @@ func CallDeploy(_ []string, flags DeployFlags, network models.Network) error {
 			return err
 		}
 	}
+	if err := keychain.CheckPrivateKeyOnNetwork(app, network, privateKey, flags.AllowUnapprovedKey); err != nil {
+		return err
+	}
 	var icmVersion string
 	switch {
 	case flags.MessengerContractAddressPath != "" || flags.MessengerDeployerAddressPath != "" || flags.MessengerDeployerTxPath != "" || flags.RegistryBydecodePath != "":
@@ -216,6 +222,14 @@ func CallDeploy(_ []string, flags DeployFlags, network models.Network) error {
 		if registryAddress != "" {
 			networkInfo.TeleporterRegistryAddress = registryAddress
 		}
+		if icmVersion != "" && messengerAddress != "" {
+			// a registry deployed together with the messenger has it as its first version
+			registryVersion := networkInfo.ICMMessengers[icmVersion].RegistryVersion
+			if registryAddress != "" {
+				registryVersion = 1
+			}
+			networkInfo.SetICMMessenger(icmVersion, messengerAddress, registryVersion)
+		}
 		sc.Networks[network.Name()] = networkInfo
 		if err := app.UpdateSidecar(&sc); err != nil {
 			return err
@@ -0,0 +1,218 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package messengercmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/spf13/cobra"
+)
+
+type ImportFlags struct {
+	Network                 networkoptions.NetworkFlags
+	ChainFlags              contract.ChainSpec
+	RPCURL                  string
+	MessengerAddress        string
+	RegistryAddress         string
+	Version                 string
+	MessengerDeployerTxPath string
+	SetDefault              bool
+}
+
+var (
+	importSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Local,
+		networkoptions.Devnet,
+		networkoptions.Fuji,
+		networkoptions.Mainnet,
+	}
+	importFlags ImportFlags
+)
+
+// avalanche interchain messenger import
+func NewImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Uses an ICM Messenger and Registry already deployed into a given L1",
+		Long: `Records an ICM Messenger, and optionally an ICM Registry, deployed into a given L1 by
+other means, so the CLI ICM tooling (sendMsg, relayer) uses them instead of deploying its own.
+
+The messenger code is verified on-chain against the code of the known ICM releases, and its
+release is detected out of it. Releases are downloaded as needed; on air-gapped environments,
+give the messenger deployment tx of the expected release with --messenger-deployer-tx-path
+and --version. The registry is verified to belong to the L1, and the protocol version the
+messenger is registered with is read out of it.
+
+Unless --set-default=false, the messenger becomes the default one used by the CLI for the L1.`,
+		RunE: importMessenger,
+		Args: cobrautils.ExactArgs(0),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &importFlags.Network, true, importSupportedNetworkOptions)
+	importFlags.ChainFlags.SetEnabled(true, false, false, false, false)
+	importFlags.ChainFlags.AddToCmd(cmd, "import ICM on %s")
+	cmd.Flags().StringVar(&importFlags.RPCURL, "rpc-url", "", "use the given RPC URL to connect to the L1")
+	cmd.Flags().StringVar(&importFlags.MessengerAddress, "messenger-address", "", "address of the deployed ICM Messenger")
+	cmd.Flags().StringVar(&importFlags.RegistryAddress, "registry-address", "", "address of the deployed ICM Registry")
+	cmd.Flags().StringVar(&importFlags.Version, "version", "", "expected ICM Messenger release (default: detect it out of the known releases)")
+	cmd.Flags().StringVar(&importFlags.MessengerDeployerTxPath, "messenger-deployer-tx-path", "", "path to the ICM Messenger deployment tx of --version, to verify the messenger without downloads")
+	cmd.Flags().BoolVar(&importFlags.SetDefault, "set-default", true, "use the imported messenger as the default one of the L1")
+	return cmd
+}
+
+func importMessenger(_ *cobra.Command, _ []string) error {
+	if importFlags.MessengerAddress == "" {
+		return fmt.Errorf("--messenger-address is required")
+	}
+	if !common.IsHexAddress(importFlags.MessengerAddress) {
+		return fmt.Errorf("invalid messenger address %s", importFlags.MessengerAddress)
+	}
+	if importFlags.RegistryAddress != "" && !common.IsHexAddress(importFlags.RegistryAddress) {
+		return fmt.Errorf("invalid registry address %s", importFlags.RegistryAddress)
+	}
+	if importFlags.MessengerDeployerTxPath != "" && importFlags.Version == "" {
+		return fmt.Errorf("--messenger-deployer-tx-path requires --version")
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"On what Network do you want to import the ICM Messenger?",
+		importFlags.Network,
+		true,
+		false,
+		importSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if !importFlags.ChainFlags.Defined() {
+		prompt := "Which Blockchain would you like to import ICM on?"
+		if cancel, err := contract.PromptChain(
+			app,
+			network,
+			prompt,
+			"",
+			&importFlags.ChainFlags,
+		); err != nil {
+			return err
+		} else if cancel {
+			return nil
+		}
+	}
+	blockchainName := importFlags.ChainFlags.BlockchainName
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	networkInfo := sc.Networks[network.Name()]
+	if networkInfo.BlockchainID == ids.Empty {
+		return fmt.Errorf("blockchain has not been deployed to %s", network.Name())
+	}
+	rpcURL := importFlags.RPCURL
+	if rpcURL == "" {
+		rpcURL, _, err = contract.GetBlockchainEndpoints(app, network, importFlags.ChainFlags, true, false)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), rpcURL)
+	}
+	messengerAddress := common.HexToAddress(importFlags.MessengerAddress)
+
+	knownCodeHashes, err := getKnownMessengerCodeHashes()
+	if err != nil {
+		return err
+	}
+	icmVersion, err := interchain.GetDeployedMessengerVersion(rpcURL, messengerAddress, knownCodeHashes)
+	if err != nil {
+		return fmt.Errorf("failure verifying ICM Messenger %s: %w", messengerAddress, err)
+	}
+	if importFlags.Version != "" && icmVersion != importFlags.Version {
+		return fmt.Errorf("ICM Messenger %s is of release %s, not %s", messengerAddress, icmVersion, importFlags.Version)
+	}
+	ux.Logger.GreenCheckmarkToUser("ICM Messenger %s verified as release %s", messengerAddress, icmVersion)
+
+	registryVersion := uint64(0)
+	if importFlags.RegistryAddress != "" {
+		registryAddress := common.HexToAddress(importFlags.RegistryAddress)
+		registryVersions, err := interchain.VerifyRegistry(rpcURL, registryAddress, networkInfo.BlockchainID)
+		if err != nil {
+			return fmt.Errorf("failure verifying ICM Registry %s: %w", registryAddress, err)
+		}
+		for version, registeredAddress := range registryVersions {
+			if registeredAddress == messengerAddress {
+				registryVersion = version
+			}
+		}
+		if registryVersion == 0 {
+			ux.Logger.PrintToUser(logging.Yellow.Wrap("ICM Messenger %s is not registered on ICM Registry %s"), messengerAddress, registryAddress)
+		} else {
+			ux.Logger.GreenCheckmarkToUser("ICM Registry %s verified, messenger registered as version %d", registryAddress, registryVersion)
+		}
+	}
+
+	// keep track of the messenger used up to now
+	if sc.TeleporterVersion != "" && networkInfo.TeleporterMessengerAddress != "" {
+		if _, ok := networkInfo.ICMMessengers[sc.TeleporterVersion]; !ok {
+			networkInfo.SetICMMessenger(sc.TeleporterVersion, networkInfo.TeleporterMessengerAddress, 0)
+		}
+	}
+	networkInfo.SetICMMessenger(icmVersion, messengerAddress.Hex(), registryVersion)
+	if importFlags.SetDefault {
+		sc.TeleporterReady = true
+		sc.TeleporterVersion = icmVersion
+		networkInfo.TeleporterMessengerAddress = messengerAddress.Hex()
+		if importFlags.RegistryAddress != "" {
+			networkInfo.TeleporterRegistryAddress = common.HexToAddress(importFlags.RegistryAddress).Hex()
+		}
+	}
+	sc.Networks[network.Name()] = networkInfo
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("ICM Messenger %s imported on %s", icmVersion, blockchainName)
+	return nil
+}
+
+// getKnownMessengerCodeHashes returns the messenger code hashes to verify the imported
+// messenger against: the one of the given deployment tx, or else the ones of the cached
+// releases, after downloading the expected (or default) release if missing
+func getKnownMessengerCodeHashes() (map[string]common.Hash, error) {
+	if importFlags.MessengerDeployerTxPath != "" {
+		messengerDeployerTx, err := os.ReadFile(importFlags.MessengerDeployerTxPath)
+		if err != nil {
+			return nil, err
+		}
+		codeHash, err := interchain.GetMessengerCodeHash(string(messengerDeployerTx))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]common.Hash{importFlags.Version: codeHash}, nil
+	}
+	icmVersion := importFlags.Version
+	if icmVersion == "" {
+		icmVersion = constants.ICMVersion
+	}
+	knownCodeHashes, err := interchain.GetKnownMessengerCodeHashes(app.GetICMContractsBinDir())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := knownCodeHashes[icmVersion]; !ok {
+		td := interchain.ICMDeployer{}
+		if err := td.DownloadAssets(app.GetICMContractsBinDir(), icmVersion); err != nil {
+			ux.Logger.PrintToUser(logging.Yellow.Wrap("failure downloading ICM release %s: %s"), icmVersion, err)
+		} else if knownCodeHashes, err = interchain.GetKnownMessengerCodeHashes(app.GetICMContractsBinDir()); err != nil {
+			return nil, err
+		}
+	}
+	return knownCodeHashes, nil
+}
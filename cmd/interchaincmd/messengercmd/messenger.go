@@ -24,5 +24,11 @@ with ICM messenger contracts.`,
 	cmd.AddCommand(NewSendMsgCmd())
 	// interchain messenger deploy
 	cmd.AddCommand(NewDeployCmd())
+	// interchain messenger upgrade
+	cmd.AddCommand(NewUpgradeCmd())
+	// interchain messenger import
+	cmd.AddCommand(NewImportCmd())
+	// interchain messenger bench
+	cmd.AddCommand(NewBenchCmd())
 	return cmd
 }
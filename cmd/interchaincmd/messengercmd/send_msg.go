@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
 	"github.com/ava-labs/avalanche-cli/pkg/evm"
 	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -21,14 +22,29 @@ import (
 )
 
 type MsgFlags struct {
-	Network            networkoptions.NetworkFlags
-	DestinationAddress string
-	HexEncodedMessage  bool
-	PrivateKeyFlags    contract.PrivateKeyFlags
-	SourceRPCEndpoint  string
-	DestRPCEndpoint    string
+	Network                     networkoptions.NetworkFlags
+	DestinationAddress          string
+	HexEncodedMessage           bool
+	PrivateKeyFlags             contract.PrivateKeyFlags
+	DestPrivateKeyFlags         contract.PrivateKeyFlags
+	SourceRPCEndpoint           string
+	DestRPCEndpoint             string
+	Mode                        string
+	SourceAddress               string
+	DestinationMethod           string
+	AggregatorLogLevel          string
+	AggregatorExtraEndpoints    []string
+	AggregatorAllowPrivatePeers bool
+	AggregationFlags            interchain.AggregationFlags
+	Version                     string
 }
 
+const (
+	teleporterMode    = "teleporter"
+	addressedCallMode = "addressed-call"
+	offChainMode      = "off-chain"
+)
+
 var (
 	msgSupportedNetworkOptions = []networkoptions.NetworkOption{
 		networkoptions.Local,
@@ -43,16 +59,43 @@ func NewSendMsgCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "sendMsg [sourceBlockchainName] [destinationBlockchainName] [messageContent]",
 		Short: "Verifies exchange of ICM message between two blockchains",
-		Long:  `Sends and wait reception for a ICM msg between two blockchains.`,
-		RunE:  sendMsg,
-		Args:  cobrautils.ExactArgs(3),
+		Long: `Sends and wait reception for a ICM msg between two blockchains.
+
+By default the message is sent through the ICM messenger contracts, and delivered by a relayer.
+When several messenger releases are deployed on the blockchains (see avalanche interchain
+messenger upgrade), --version selects the one to use.
+Raw warp messages can also be sent with --mode:
+
+- addressed-call: the message is emitted as an addressed call by the warp precompile of the
+  source blockchain, on a tx from the originator key.
+- off-chain: the addressed call is built locally, coming from --source-address. Source
+  validators only sign it if it is listed in the warp-off-chain-messages field of their
+  chain config.
+
+On both modes the signatures are collected with the signature aggregator, and the signed
+message is delivered to the contract at --destination-address on the destination
+blockchain, by calling --destination-method with the index of the message in the tx
+predicates, on a tx paid by the destination key (--dest-key). If no destination address
+is given, the unsigned and signed messages are printed instead.`,
+		RunE: sendMsg,
+		Args: cobrautils.ExactArgs(3),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &msgFlags.Network, true, msgSupportedNetworkOptions)
 	msgFlags.PrivateKeyFlags.AddToCmd(cmd, "as message originator and to pay source blockchain fees")
+	msgFlags.DestPrivateKeyFlags.SetFlagNames("dest-private-key", "dest-key", "dest-genesis-key")
+	msgFlags.DestPrivateKeyFlags.AddToCmd(cmd, "to pay destination blockchain fees (addressed-call and off-chain modes)")
 	cmd.Flags().BoolVar(&msgFlags.HexEncodedMessage, "hex-encoded", false, "given message is hex encoded")
 	cmd.Flags().StringVar(&msgFlags.DestinationAddress, "destination-address", "", "deliver the message to the given contract destination address")
 	cmd.Flags().StringVar(&msgFlags.SourceRPCEndpoint, "source-rpc", "", "use the given source blockchain rpc endpoint")
 	cmd.Flags().StringVar(&msgFlags.DestRPCEndpoint, "dest-rpc", "", "use the given destination blockchain rpc endpoint")
+	cmd.Flags().StringVar(&msgFlags.Mode, "mode", teleporterMode, fmt.Sprintf("message flow to use (%s, %s, %s)", teleporterMode, addressedCallMode, offChainMode))
+	cmd.Flags().StringVar(&msgFlags.SourceAddress, "source-address", "", "source address of the addressed call (off-chain mode)")
+	cmd.Flags().StringVar(&msgFlags.DestinationMethod, "destination-method", interchain.DefaultWarpReceiverMethod, "method to call at the destination contract (addressed-call and off-chain modes)")
+	cmd.Flags().StringVar(&msgFlags.AggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	cmd.Flags().StringSliceVar(&msgFlags.AggregatorExtraEndpoints, "aggregator-extra-endpoints", nil, "endpoints for extra nodes that are needed in signature aggregation")
+	cmd.Flags().BoolVar(&msgFlags.AggregatorAllowPrivatePeers, "aggregator-allow-private-peers", true, "allow the signature aggregator to connect to peers with private IP")
+	interchain.AddAggregationFlagsToCmd(cmd, &msgFlags.AggregationFlags)
+	cmd.Flags().StringVar(&msgFlags.Version, "version", "", "send through the ICM messenger of the given release (eg v1.0.0) instead of the default one of the blockchains")
 	return cmd
 }
 
@@ -74,95 +117,44 @@ func sendMsg(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	sourceChainSpec := contract.ChainSpec{}
-	if isCChain(sourceBlockchainName) {
-		sourceChainSpec.CChain = true
-	} else {
-		sourceChainSpec.BlockchainName = sourceBlockchainName
-	}
-	sourceRPCEndpoint := msgFlags.SourceRPCEndpoint
-	if sourceRPCEndpoint == "" {
-		sourceRPCEndpoint, _, err = contract.GetBlockchainEndpoints(app, network, sourceChainSpec, true, false)
-		if err != nil {
-			return err
-		}
-	}
-
-	destChainSpec := contract.ChainSpec{}
-	if isCChain(destBlockchainName) {
-		destChainSpec.CChain = true
-	} else {
-		destChainSpec.BlockchainName = destBlockchainName
-	}
-	destRPCEndpoint := msgFlags.DestRPCEndpoint
-	if destRPCEndpoint == "" {
-		destRPCEndpoint, _, err = contract.GetBlockchainEndpoints(app, network, destChainSpec, true, false)
-		if err != nil {
-			return err
-		}
+	switch msgFlags.Mode {
+	case teleporterMode:
+	case addressedCallMode, offChainMode:
+		return sendWarpMsg(network, sourceBlockchainName, destBlockchainName, message)
+	default:
+		return fmt.Errorf("invalid mode %q, must be one of %s, %s, %s", msgFlags.Mode, teleporterMode, addressedCallMode, offChainMode)
 	}
 
-	genesisAddress, genesisPrivateKey, err := contract.GetEVMSubnetPrefundedKey(
-		app,
+	sourceRPCEndpoint, sourceBlockchainID, sourceMessengerAddress, err := getICMChainInfo(
 		network,
-		contract.ChainSpec{
-			BlockchainName: sourceBlockchainName,
-			CChain:         isCChain(sourceBlockchainName),
-		},
+		sourceBlockchainName,
+		msgFlags.SourceRPCEndpoint,
 	)
 	if err != nil {
 		return err
 	}
-	privateKey, err := msgFlags.PrivateKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	destRPCEndpoint, destBlockchainID, destMessengerAddress, err := getICMChainInfo(
+		network,
+		destBlockchainName,
+		msgFlags.DestRPCEndpoint,
+	)
 	if err != nil {
 		return err
 	}
-	if privateKey == "" {
-		privateKey, err = prompts.PromptPrivateKey(
-			app.Prompt,
-			"pay for fees at source blockchain",
-			app.GetKeyDir(),
-			app.GetKey,
-			genesisAddress,
-			genesisPrivateKey,
-		)
-		if err != nil {
-			return err
-		}
+	if sourceMessengerAddress != destMessengerAddress {
+		return fmt.Errorf("different ICM messenger addresses among blockchains: %s vs %s", sourceMessengerAddress, destMessengerAddress)
 	}
 
-	sourceBlockchainID, err := contract.GetBlockchainID(app, network, sourceChainSpec)
-	if err != nil {
-		return err
-	}
-	_, sourceMessengerAddress, err := contract.GetICMInfo(app, network, sourceChainSpec, false, false, true)
-	if err != nil {
-		return err
-	}
-	destBlockchainID, err := contract.GetBlockchainID(app, network, destChainSpec)
+	privateKey, err := getMsgPrivateKey(network, sourceBlockchainName, msgFlags.PrivateKeyFlags, "pay for fees at source blockchain")
 	if err != nil {
 		return err
 	}
-	_, destMessengerAddress, err := contract.GetICMInfo(app, network, destChainSpec, false, false, true)
+
+	encodedMessage := getEncodedMessage(message)
+	destAddr, err := getDestinationAddress()
 	if err != nil {
 		return err
 	}
-
-	if sourceMessengerAddress != destMessengerAddress {
-		return fmt.Errorf("different ICM messenger addresses among blockchains: %s vs %s", sourceMessengerAddress, destMessengerAddress)
-	}
-
-	encodedMessage := []byte(message)
-	if msgFlags.HexEncodedMessage {
-		encodedMessage = common.FromHex(message)
-	}
-	destAddr := common.Address{}
-	if msgFlags.DestinationAddress != "" {
-		if err := prompts.ValidateAddress(msgFlags.DestinationAddress); err != nil {
-			return fmt.Errorf("failure validating address %s: %w", msgFlags.DestinationAddress, err)
-		}
-		destAddr = common.HexToAddress(msgFlags.DestinationAddress)
-	}
 	// send tx to the ICM contract at the source
 	ux.Logger.PrintToUser("Delivering message %q from source blockchain %q (%s)", message, sourceBlockchainName, sourceBlockchainID)
 	tx, receipt, err := interchain.SendCrossChainMessage(
@@ -231,6 +223,148 @@ func sendMsg(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+// getICMChainInfo returns the rpc endpoint, blockchain ID and ICM messenger address
+// for [blockchainName], which can also refer to the C-Chain. If [rpcEndpoint] is
+// not empty, it is used instead of the endpoint known for the blockchain
+func getICMChainInfo(
+	network models.Network,
+	blockchainName string,
+	rpcEndpoint string,
+) (string, ids.ID, string, error) {
+	rpcEndpoint, blockchainID, err := getChainInfo(network, blockchainName, rpcEndpoint)
+	if err != nil {
+		return "", ids.Empty, "", err
+	}
+	if msgFlags.Version != "" {
+		messengerAddress, err := getVersionedMessengerAddress(blockchainName, rpcEndpoint, msgFlags.Version)
+		if err != nil {
+			return "", ids.Empty, "", err
+		}
+		return rpcEndpoint, blockchainID, messengerAddress, nil
+	}
+	_, messengerAddress, err := contract.GetICMInfo(app, network, getChainSpec(blockchainName), false, false, true)
+	if err != nil {
+		return "", ids.Empty, "", err
+	}
+	return rpcEndpoint, blockchainID, messengerAddress, nil
+}
+
+// getVersionedMessengerAddress returns the address of the ICM messenger release [version],
+// checking that it is deployed on [blockchainName]
+func getVersionedMessengerAddress(
+	blockchainName string,
+	rpcEndpoint string,
+	version string,
+) (string, error) {
+	messengerAddress, err := interchain.GetMessengerAddress(app, version)
+	if err != nil {
+		return "", err
+	}
+	client, err := evm.GetClient(rpcEndpoint)
+	if err != nil {
+		return "", err
+	}
+	if deployed, err := evm.ContractAlreadyDeployed(client, messengerAddress); err != nil {
+		return "", fmt.Errorf("failure making a request to %s: %w", rpcEndpoint, err)
+	} else if !deployed {
+		return "", fmt.Errorf(
+			"ICM messenger %s is not deployed on %s. Deploy it with avalanche interchain messenger upgrade",
+			version,
+			blockchainName,
+		)
+	}
+	return messengerAddress, nil
+}
+
+// getChainInfo returns the rpc endpoint and blockchain ID for [blockchainName], which
+// can also refer to the C-Chain. If [rpcEndpoint] is not empty, it is used instead
+// of the endpoint known for the blockchain
+func getChainInfo(
+	network models.Network,
+	blockchainName string,
+	rpcEndpoint string,
+) (string, ids.ID, error) {
+	chainSpec := getChainSpec(blockchainName)
+	var err error
+	if rpcEndpoint == "" {
+		rpcEndpoint, _, err = contract.GetBlockchainEndpoints(app, network, chainSpec, true, false)
+		if err != nil {
+			return "", ids.Empty, err
+		}
+	}
+	blockchainID, err := contract.GetBlockchainID(app, network, chainSpec)
+	if err != nil {
+		return "", ids.Empty, err
+	}
+	return rpcEndpoint, blockchainID, nil
+}
+
+func getChainSpec(blockchainName string) contract.ChainSpec {
+	chainSpec := contract.ChainSpec{}
+	if isCChain(blockchainName) {
+		chainSpec.CChain = true
+	} else {
+		chainSpec.BlockchainName = blockchainName
+	}
+	return chainSpec
+}
+
+// getMsgPrivateKey returns the private key given by [privateKeyFlags], or prompts for one,
+// offering the prefunded key of [blockchainName] as default
+func getMsgPrivateKey(
+	network models.Network,
+	blockchainName string,
+	privateKeyFlags contract.PrivateKeyFlags,
+	goal string,
+) (string, error) {
+	genesisAddress, genesisPrivateKey, err := contract.GetEVMSubnetPrefundedKey(
+		app,
+		network,
+		contract.ChainSpec{
+			BlockchainName: blockchainName,
+			CChain:         isCChain(blockchainName),
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	privateKey, err := privateKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	if err != nil {
+		return "", err
+	}
+	if privateKey == "" {
+		privateKey, err = prompts.PromptPrivateKey(
+			app.Prompt,
+			goal,
+			app.GetKeyDir(),
+			app.GetKey,
+			genesisAddress,
+			genesisPrivateKey,
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+	return privateKey, nil
+}
+
+func getEncodedMessage(message string) []byte {
+	if msgFlags.HexEncodedMessage {
+		return common.FromHex(message)
+	}
+	return []byte(message)
+}
+
+func getDestinationAddress() (common.Address, error) {
+	if msgFlags.DestinationAddress == "" {
+		return common.Address{}, nil
+	}
+	if err := prompts.ValidateAddress(msgFlags.DestinationAddress); err != nil {
+		return common.Address{}, fmt.Errorf("failure validating address %s: %w", msgFlags.DestinationAddress, err)
+	}
+	return common.HexToAddress(msgFlags.DestinationAddress), nil
+}
+
 func isCChain(subnetName string) bool {
 	return strings.ToLower(subnetName) == "c-chain" || strings.ToLower(subnetName) == "cchain"
 }
@@ -0,0 +1,156 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package messengercmd
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	sdkinterchain "github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// sendWarpMsg sends [message] as a raw warp addressed call from [sourceBlockchainName],
+// either emitted by the warp precompile or built off-chain, collects the source
+// validator signatures, and delivers it to the destination contract
+func sendWarpMsg(
+	network models.Network,
+	sourceBlockchainName string,
+	destBlockchainName string,
+	message string,
+) error {
+	sourceRPCEndpoint, sourceBlockchainID, err := getChainInfo(
+		network,
+		sourceBlockchainName,
+		msgFlags.SourceRPCEndpoint,
+	)
+	if err != nil {
+		return err
+	}
+	destRPCEndpoint, destBlockchainID, err := getChainInfo(
+		network,
+		destBlockchainName,
+		msgFlags.DestRPCEndpoint,
+	)
+	if err != nil {
+		return err
+	}
+	sourceSubnetID, err := contract.GetSubnetID(app, network, getChainSpec(sourceBlockchainName))
+	if err != nil {
+		return err
+	}
+	destAddr, err := getDestinationAddress()
+	if err != nil {
+		return err
+	}
+	aggregatorLogLevel, err := logging.ToLevel(msgFlags.AggregatorLogLevel)
+	if err != nil {
+		aggregatorLogLevel = logging.Off
+	}
+	aggregationConfig, err := interchain.GetAggregationConfig(app, msgFlags.AggregationFlags)
+	if err != nil {
+		return err
+	}
+
+	aggregatorExtraPeers, err := interchain.URIsToPeers(msgFlags.AggregatorExtraEndpoints)
+	if err != nil {
+		return err
+	}
+
+	sourcePrivateKey := ""
+	if msgFlags.Mode == addressedCallMode {
+		sourcePrivateKey, err = getMsgPrivateKey(
+			network,
+			sourceBlockchainName,
+			msgFlags.PrivateKeyFlags,
+			"pay for warp message fees at source blockchain",
+		)
+		if err != nil {
+			return err
+		}
+	}
+	destPrivateKey := ""
+	if msgFlags.DestinationAddress != "" {
+		destPrivateKey, err = getMsgPrivateKey(
+			network,
+			destBlockchainName,
+			msgFlags.DestPrivateKeyFlags,
+			"pay for warp message delivery fees at destination blockchain",
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	payload := getEncodedMessage(message)
+	var unsignedMessage *warp.UnsignedMessage
+	switch msgFlags.Mode {
+	case addressedCallMode:
+		ux.Logger.PrintToUser("Sending warp message %q from source blockchain %q (%s)", message, sourceBlockchainName, sourceBlockchainID)
+		_, receipt, err := interchain.SendWarpMessage(sourceRPCEndpoint, sourcePrivateKey, payload)
+		if err != nil {
+			return err
+		}
+		unsignedMessage, err = interchain.GetWarpMessageFromReceipt(receipt)
+		if err != nil {
+			return err
+		}
+	case offChainMode:
+		var sourceAddress []byte
+		if msgFlags.SourceAddress != "" {
+			if err := prompts.ValidateAddress(msgFlags.SourceAddress); err != nil {
+				return fmt.Errorf("failure validating address %s: %w", msgFlags.SourceAddress, err)
+			}
+			sourceAddress = common.HexToAddress(msgFlags.SourceAddress).Bytes()
+		}
+		unsignedMessage, err = interchain.NewAddressedCallMessage(network.ID, sourceBlockchainID, sourceAddress, payload)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Built off-chain warp message %q for source blockchain %q (%s)", message, sourceBlockchainName, sourceBlockchainID)
+		ux.Logger.PrintToUser("Source validators must list it on the warp-off-chain-messages field of their chain config to sign it")
+	}
+	ux.Logger.PrintToUser("Unsigned message ID: %s", unsignedMessage.ID())
+	ux.Logger.PrintToUser("Unsigned message: 0x%s", hex.EncodeToString(unsignedMessage.Bytes()))
+
+	ux.Logger.PrintToUser("Collecting signatures from source blockchain validators")
+	signedMessage, err := sdkinterchain.SignMessage(
+		network.SDKNetwork(),
+		aggregatorLogLevel,
+		sourceSubnetID,
+		aggregationConfig,
+		msgFlags.AggregatorAllowPrivatePeers,
+		aggregatorExtraPeers,
+		unsignedMessage,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	if msgFlags.DestinationAddress == "" {
+		ux.Logger.PrintToUser("Signed message: 0x%s", hex.EncodeToString(signedMessage.Bytes()))
+		return nil
+	}
+
+	ux.Logger.PrintToUser("Delivering message to %s at destination blockchain %q (%s)", destAddr, destBlockchainName, destBlockchainID)
+	tx, _, err := interchain.DeliverWarpMessage(
+		destRPCEndpoint,
+		destPrivateKey,
+		destAddr,
+		signedMessage,
+		msgFlags.DestinationMethod,
+	)
+	if err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Warp message delivered on tx %s", tx.Hash())
+	return nil
+}
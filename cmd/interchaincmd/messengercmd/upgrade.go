@@ -0,0 +1,270 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package messengercmd
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	sdkinterchain "github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/spf13/cobra"
+)
+
+type UpgradeFlags struct {
+	Network            networkoptions.NetworkFlags
+	ChainFlags         contract.ChainSpec
+	PrivateKeyFlags    contract.PrivateKeyFlags
+	RPCURL             string
+	Version            string
+	SetDefault         bool
+	AggregatorLogLevel string
+	AggregationFlags   interchain.AggregationFlags
+	AllowUnapprovedKey bool
+}
+
+var upgradeFlags UpgradeFlags
+
+// avalanche interchain messenger upgrade
+func NewUpgradeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Deploys a newer ICM Messenger into a given L1 and registers it",
+		Long: `Deploys the ICM Messenger of the given release into a given L1, next to the ones already
+deployed, and registers it as the next protocol version of the L1 ICM Registry.
+
+The registry only accepts new versions through an off-chain warp message signed by the L1
+validators, coming from the zero address. The command prints the message, that validators
+must list on the warp-off-chain-messages field of their chain config before signing it.
+
+Messengers of previous releases keep working, and can be used with
+avalanche interchain messenger sendMsg --version. Unless --set-default=false, the new
+messenger becomes the default one used by the CLI for the L1.`,
+		RunE: upgrade,
+		Args: cobrautils.ExactArgs(0),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &upgradeFlags.Network, true, deploySupportedNetworkOptions)
+	upgradeFlags.PrivateKeyFlags.AddToCmd(cmd, "to fund ICM deploy and registration")
+	upgradeFlags.ChainFlags.SetEnabled(true, false, false, false, false)
+	upgradeFlags.ChainFlags.AddToCmd(cmd, "upgrade ICM on %s")
+	cmd.Flags().StringVar(&upgradeFlags.RPCURL, "rpc-url", "", "use the given RPC URL to connect to the L1")
+	cmd.Flags().StringVar(&upgradeFlags.Version, "version", "latest", "ICM Messenger release to deploy")
+	cmd.Flags().BoolVar(&upgradeFlags.SetDefault, "set-default", true, "use the new messenger as the default one of the L1")
+	cmd.Flags().StringVar(&upgradeFlags.AggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	interchain.AddAggregationFlagsToCmd(cmd, &upgradeFlags.AggregationFlags)
+	keychain.AddAllowUnapprovedKeyFlag(cmd, &upgradeFlags.AllowUnapprovedKey)
+	return cmd
+}
+
+func upgrade(_ *cobra.Command, _ []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"On what Network do you want to upgrade the ICM Messenger?",
+		upgradeFlags.Network,
+		true,
+		false,
+		deploySupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if !upgradeFlags.ChainFlags.Defined() {
+		prompt := "Which Blockchain would you like to upgrade ICM on?"
+		if cancel, err := contract.PromptChain(
+			app,
+			network,
+			prompt,
+			"",
+			&upgradeFlags.ChainFlags,
+		); err != nil {
+			return err
+		} else if cancel {
+			return nil
+		}
+	}
+	blockchainName := upgradeFlags.ChainFlags.BlockchainName
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	networkInfo := sc.Networks[network.Name()]
+	if networkInfo.BlockchainID == ids.Empty {
+		return fmt.Errorf("blockchain has not been deployed to %s", network.Name())
+	}
+	icmVersion := upgradeFlags.Version
+	if icmVersion == "" || icmVersion == "latest" {
+		icmVersion = constants.ICMVersion
+	}
+	rpcURL := upgradeFlags.RPCURL
+	if rpcURL == "" {
+		rpcURL, _, err = contract.GetBlockchainEndpoints(app, network, upgradeFlags.ChainFlags, true, false)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), rpcURL)
+	}
+	genesisAddress, genesisPrivateKey, err := contract.GetEVMSubnetPrefundedKey(
+		app,
+		network,
+		upgradeFlags.ChainFlags,
+	)
+	if err != nil {
+		return err
+	}
+	privateKey, err := upgradeFlags.PrivateKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	if err != nil {
+		return err
+	}
+	if privateKey == "" {
+		privateKey, err = prompts.PromptPrivateKey(
+			app.Prompt,
+			"upgrade ICM",
+			app.GetKeyDir(),
+			app.GetKey,
+			genesisAddress,
+			genesisPrivateKey,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if err := keychain.CheckPrivateKeyOnNetwork(app, network, privateKey, upgradeFlags.AllowUnapprovedKey); err != nil {
+		return err
+	}
+
+	td := interchain.ICMDeployer{}
+	if err := td.DownloadAssets(app.GetICMContractsBinDir(), icmVersion); err != nil {
+		return err
+	}
+	_, messengerAddress, err := td.DeployMessenger(blockchainName, rpcURL, privateKey)
+	if err != nil {
+		return err
+	}
+	// keep track of the messenger used up to now
+	if sc.TeleporterVersion != "" && networkInfo.TeleporterMessengerAddress != "" {
+		if _, ok := networkInfo.ICMMessengers[sc.TeleporterVersion]; !ok {
+			networkInfo.SetICMMessenger(sc.TeleporterVersion, networkInfo.TeleporterMessengerAddress, 0)
+		}
+	}
+	networkInfo.SetICMMessenger(icmVersion, messengerAddress, 0)
+
+	var registerErr error
+	if networkInfo.TeleporterRegistryAddress == "" {
+		ux.Logger.PrintToUser("No ICM Registry is known for %s, skipping registration of ICM Messenger %s", blockchainName, icmVersion)
+	} else {
+		registerErr = registerMessenger(network, &networkInfo, rpcURL, privateKey, messengerAddress)
+	}
+	if registerErr == nil && upgradeFlags.SetDefault {
+		sc.TeleporterVersion = icmVersion
+		networkInfo.TeleporterMessengerAddress = messengerAddress
+	}
+	sc.Networks[network.Name()] = networkInfo
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+	if registerErr != nil {
+		return registerErr
+	}
+	ux.Logger.GreenCheckmarkToUser("ICM Messenger %s is ready on %s", icmVersion, blockchainName)
+	return nil
+}
+
+// registerMessenger adds [messengerAddress] as the next protocol version of the ICM registry
+// of the blockchain, if not already registered, and updates the registry versions of the
+// messengers tracked on [networkInfo]
+func registerMessenger(
+	network models.Network,
+	networkInfo *models.NetworkData,
+	rpcURL string,
+	privateKey string,
+	messengerAddress string,
+) error {
+	registryAddress := common.HexToAddress(networkInfo.TeleporterRegistryAddress)
+	registryVersions, err := interchain.GetRegistryVersions(rpcURL, registryAddress)
+	if err != nil {
+		return fmt.Errorf("failure reading ICM Registry %s: %w", registryAddress, err)
+	}
+	updateRegistryVersions := func() {
+		for version, messenger := range networkInfo.ICMMessengers {
+			for registryVersion, registeredAddress := range registryVersions {
+				if registeredAddress == common.HexToAddress(messenger.Address) {
+					networkInfo.SetICMMessenger(version, messenger.Address, registryVersion)
+				}
+			}
+		}
+	}
+	latestVersion := uint64(0)
+	for registryVersion, registeredAddress := range registryVersions {
+		if registeredAddress == common.HexToAddress(messengerAddress) {
+			ux.Logger.PrintToUser("ICM Messenger %s is already registered as version %d", messengerAddress, registryVersion)
+			updateRegistryVersions()
+			return nil
+		}
+		latestVersion = max(latestVersion, registryVersion)
+	}
+	newVersion := latestVersion + 1
+	unsignedMessage, err := interchain.NewRegistryEntryMessage(
+		network.ID,
+		networkInfo.BlockchainID,
+		registryAddress,
+		newVersion,
+		common.HexToAddress(messengerAddress),
+	)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Registering ICM Messenger %s as version %d of ICM Registry %s", messengerAddress, newVersion, registryAddress)
+	ux.Logger.PrintToUser("Unsigned message ID: %s", unsignedMessage.ID())
+	ux.Logger.PrintToUser("Unsigned message: 0x%s", hex.EncodeToString(unsignedMessage.Bytes()))
+	aggregatorLogLevel, err := logging.ToLevel(upgradeFlags.AggregatorLogLevel)
+	if err != nil {
+		aggregatorLogLevel = logging.Off
+	}
+	aggregationConfig, err := interchain.GetAggregationConfig(app, upgradeFlags.AggregationFlags)
+	if err != nil {
+		return err
+	}
+	signedMessage, err := sdkinterchain.SignMessage(
+		network.SDKNetwork(),
+		aggregatorLogLevel,
+		networkInfo.SubnetID,
+		aggregationConfig,
+		true,
+		nil,
+		unsignedMessage,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failure collecting validator signatures for the registry message. Validators must list it on the warp-off-chain-messages field of their chain config before running this command again: %w",
+			err,
+		)
+	}
+	tx, _, err := interchain.DeliverWarpMessage(
+		rpcURL,
+		privateKey,
+		registryAddress,
+		signedMessage,
+		interchain.RegistryAddProtocolVersionMethod,
+	)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("ICM Messenger registered as version %d on tx %s", newVersion, tx.Hash())
+	registryVersions[newVersion] = common.HexToAddress(messengerAddress)
+	updateRegistryVersions()
+	return nil
+}
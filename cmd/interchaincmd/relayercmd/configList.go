@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
@@ -32,6 +33,8 @@ type DestinationSpec struct {
 	blockchainID   string
 	subnetID       string
 	privateKey     string
+	// decimals of the native token, to show and enter funding amounts
+	tokenDecimals uint8
 }
 
 type ConfigSpec struct {
@@ -242,12 +245,17 @@ func addDestination(
 			return ConfigSpec{}, err
 		}
 	}
+	tokenDecimals := uint8(constants.DefaultTokenDecimals)
+	if chainSpec.BlockchainName != "" {
+		tokenDecimals = app.GetTokenDecimals(chainSpec.BlockchainName)
+	}
 	configSpec.destinations = append(configSpec.destinations, DestinationSpec{
 		blockchainDesc: blockchainDesc,
 		blockchainID:   blockchainID.String(),
 		subnetID:       subnetID.String(),
 		privateKey:     privateKey,
 		rpcEndpoint:    rpcEndpoint,
+		tokenDecimals:  tokenDecimals,
 	})
 	return configSpec, nil
 }
@@ -248,7 +248,7 @@ func CallDeploy(_ []string, flags DeployFlags, network models.Network) error {
 					return err
 				}
 				balanceFlt := new(big.Float).SetInt(balance)
-				balanceFlt = balanceFlt.Quo(balanceFlt, new(big.Float).SetInt(vm.OneAvax))
+				balanceFlt = balanceFlt.Quo(balanceFlt, new(big.Float).SetInt(vm.TokenUnit(destination.tokenDecimals)))
 				ux.Logger.PrintToUser("Relayer private key on destination %s has a balance of %.9f", destination.blockchainDesc, balanceFlt)
 			}
 			ux.Logger.PrintToUser("")
@@ -312,7 +312,7 @@ func CallDeploy(_ []string, flags DeployFlags, network models.Network) error {
 					return err
 				}
 				balanceFlt := new(big.Float).SetInt(balance)
-				balanceFlt = balanceFlt.Quo(balanceFlt, new(big.Float).SetInt(vm.OneAvax))
+				balanceFlt = balanceFlt.Quo(balanceFlt, new(big.Float).SetInt(vm.TokenUnit(destination.tokenDecimals)))
 				prompt := fmt.Sprintf("Do you want to fund relayer for destination %s (balance=%.9f)?", destination.blockchainDesc, balanceFlt)
 				yesOption := "Yes, I will send funds to it"
 				noOption := "Not now"
@@ -385,7 +385,7 @@ func CallDeploy(_ []string, flags DeployFlags, network models.Network) error {
 					return fmt.Errorf("destination %s funding key as no balance", destination.blockchainDesc)
 				}
 				balanceBigFlt := new(big.Float).SetInt(balance)
-				balanceBigFlt = balanceBigFlt.Quo(balanceBigFlt, new(big.Float).SetInt(vm.OneAvax))
+				balanceBigFlt = balanceBigFlt.Quo(balanceBigFlt, new(big.Float).SetInt(vm.TokenUnit(destination.tokenDecimals)))
 				balanceFlt, _ := balanceBigFlt.Float64()
 				balanceFlt -= aproxFundingFee
 				var amountFlt float64
@@ -420,7 +420,7 @@ func CallDeploy(_ []string, flags DeployFlags, network models.Network) error {
 					)
 				}
 				amountBigFlt := new(big.Float).SetFloat64(amountFlt)
-				amountBigFlt = amountBigFlt.Mul(amountBigFlt, new(big.Float).SetInt(vm.OneAvax))
+				amountBigFlt = amountBigFlt.Mul(amountBigFlt, new(big.Float).SetInt(vm.TokenUnit(destination.tokenDecimals)))
 				amount, _ := amountBigFlt.Int(nil)
 				if err := evm.FundAddress(client, privateKey, addr.Hex(), amount); err != nil {
 					return err
@@ -507,6 +507,7 @@ func CallDeploy(_ []string, flags DeployFlags, network models.Network) error {
 			logPath,
 			runFilePath,
 			storageDir,
+			localnet.GetRunningNetworkPorts(),
 		)
 		if err != nil {
 			if bs, err := os.ReadFile(logPath); err == nil {
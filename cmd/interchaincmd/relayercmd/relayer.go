@@ -24,6 +24,7 @@ and configuring an ICM relayers.`,
 	cmd.AddCommand(newLogsCmd())
 	cmd.AddCommand(newStartCmd())
 	cmd.AddCommand(newStopCmd())
+	cmd.AddCommand(newValidateCmd())
 	// TODO: config
 	// TODO: fund
 	return cmd
@@ -107,6 +107,7 @@ func start(_ *cobra.Command, _ []string) error {
 			app.GetLocalRelayerLogPath(network.Kind),
 			app.GetLocalRelayerRunPath(network.Kind),
 			app.GetLocalRelayerStorageDir(network.Kind),
+			localnet.GetRunningNetworkPorts(),
 		); err != nil {
 			return err
 		} else if network.Kind == models.Local {
@@ -0,0 +1,67 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package relayercmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var validateConfigPath string
+
+// avalanche interchain relayer validate
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "validates an ICM relayer config against the chains it references",
+		Long: `Validates an ICM relayer config file without sending any transaction.
+
+Besides checking the config format, it verifies that the P-Chain, info and blockchain
+endpoints are reachable, that each endpoint serves the configured blockchain and subnet,
+that the relayer keys are funded on every destination chain, and that the messages of
+each source can be delivered to each of its destinations.`,
+		RunE: validate,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&validateConfigPath, "config", "", "path to the relayer config file")
+	return cmd
+}
+
+func validate(_ *cobra.Command, _ []string) error {
+	if validateConfigPath == "" {
+		return fmt.Errorf("--config is required")
+	}
+	if !utils.FileExists(validateConfigPath) {
+		return fmt.Errorf("relayer config file %s not found", validateConfigPath)
+	}
+	checks, err := interchain.ValidateRelayerConfig(app, validateConfigPath)
+	if err != nil {
+		return err
+	}
+	t := ux.DefaultTable("Relayer Config Validation", table.Row{"Target", "Check", "Status"})
+	failed := 0
+	for _, check := range checks {
+		status := logging.Green.Wrap("OK")
+		if check.Info != "" {
+			status = fmt.Sprintf("%s %s", status, check.Info)
+		}
+		if check.Err != nil {
+			failed++
+			status = logging.Red.Wrap(check.Err.Error())
+		}
+		t.AppendRow(table.Row{check.Target, check.Check, status})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	if failed > 0 {
+		return fmt.Errorf("relayer config %s failed %d of %d checks", validateConfigPath, failed, len(checks))
+	}
+	ux.Logger.GreenCheckmarkToUser("Relayer config %s is valid", validateConfigPath)
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package signwarpcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+)
+
+var exportOutput string
+
+// avalanche interchain sign-warp export
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [messageID]",
+		Short: "Export a warp message to be signed by validator operators",
+		Long: `Export the signing request of a warp message that failed signature aggregation, to be
+sent to the validator operators. The file is the --message input of sign-warp share and merge.`,
+		RunE: export,
+		Args: cobrautils.ExactArgs(1),
+	}
+	cmd.Flags().StringVarP(&exportOutput, "output", "o", "", "write the signing request to this file (default <messageID>.json)")
+	return cmd
+}
+
+func export(_ *cobra.Command, args []string) error {
+	messageID, err := ids.FromString(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid message ID %s: %w", args[0], err)
+	}
+	requestPath := interchain.GetSigningRequestPath(app, messageID)
+	if !utils.FileExists(requestPath) {
+		return fmt.Errorf("there is no signing request for warp message %s", messageID)
+	}
+	request, err := interchain.LoadSigningRequest(requestPath)
+	if err != nil {
+		return err
+	}
+	if exportOutput == "" {
+		exportOutput = messageID.String() + ".json"
+	}
+	// operators only need the unsigned message
+	request.SignedMessage = ""
+	if err := interchain.SaveSigningRequest(exportOutput, request); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Signing request exported to %s", exportOutput)
+	ux.Logger.PrintToUser("Validator operators can sign it with: avalanche interchain sign-warp share --message %s --signer-key <node signer.key>", exportOutput)
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package signwarpcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+// avalanche interchain sign-warp list
+func newListCmd() *cobra.Command {
+	return cobrautils.MarkReadOnly(&cobra.Command{
+		Use:   "list",
+		Short: "List the warp messages pending manual signing",
+		Long:  `List the warp messages that failed signature aggregation, and whether they were already manually signed`,
+		RunE:  list,
+		Args:  cobrautils.ExactArgs(0),
+	})
+}
+
+func list(_ *cobra.Command, _ []string) error {
+	requests, err := interchain.LoadSigningRequests(app)
+	if err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		ux.Logger.PrintToUser("No warp messages pending manual signing")
+		return nil
+	}
+	t := ux.DefaultTable("Warp Signing Requests", table.Row{"Message ID", "Subnet ID", "Network Endpoint", "Status"})
+	for _, request := range requests {
+		status := "pending"
+		if request.SignedMessage != "" {
+			status = "signed"
+		}
+		t.AppendRow(table.Row{request.MessageID, request.SubnetID, request.NetworkEndpoint, status})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	return nil
+}
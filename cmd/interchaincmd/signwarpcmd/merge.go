@@ -0,0 +1,96 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package signwarpcmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	sdkinterchain "github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/api"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/spf13/cobra"
+)
+
+var sharePaths []string
+
+// avalanche interchain sign-warp merge
+func newMergeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge",
+		Short: "Merge signature shares into a signed warp message",
+		Long: `Verify the signature shares produced by sign-warp share against the current validator set of
+the subnet, and merge them into a signed warp message. The shares must add up to the quorum
+of the validator weight.
+
+The signed message is stored with the signing request: rerun the command that failed
+signature aggregation, and it will use it. The signed message is also printed, hex encoded.`,
+		RunE: merge,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&messagePath, "message", "", "signing request file, as produced by sign-warp export")
+	cmd.Flags().StringSliceVar(&sharePaths, "shares", nil, "signature share files, as produced by sign-warp share")
+	return cmd
+}
+
+func merge(_ *cobra.Command, _ []string) error {
+	if messagePath == "" {
+		return errors.New("--message is required")
+	}
+	if len(sharePaths) == 0 {
+		return errors.New("--shares is required")
+	}
+	request, err := interchain.LoadSigningRequest(messagePath)
+	if err != nil {
+		return err
+	}
+	msg, err := request.GetUnsignedMessage()
+	if err != nil {
+		return err
+	}
+	shares := []sdkinterchain.SignatureShare{}
+	for _, sharePath := range sharePaths {
+		sharesBytes, err := os.ReadFile(sharePath)
+		if err != nil {
+			return err
+		}
+		fileShares := []sdkinterchain.SignatureShare{}
+		if err := json.Unmarshal(sharesBytes, &fileShares); err != nil {
+			return fmt.Errorf("invalid signature shares file %s: %w", sharePath, err)
+		}
+		shares = append(shares, fileShares...)
+	}
+	pClient := platformvm.NewClient(request.NetworkEndpoint)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	vdrSet, err := pClient.GetValidatorsAt(ctx, request.SubnetID, api.ProposedHeight)
+	if err != nil {
+		return fmt.Errorf("failure getting validators of subnet %s: %w", request.SubnetID, err)
+	}
+	vdrs, totalWeight, err := warp.FlattenValidatorSet(vdrSet)
+	if err != nil {
+		return err
+	}
+	signedMessage, err := sdkinterchain.MergeShares(msg, vdrs, totalWeight, request.QuorumPercentage, shares)
+	if err != nil {
+		return err
+	}
+	request.SignedMessage = hex.EncodeToString(signedMessage.Bytes())
+	// store with the app requests, where the failing flows look for it
+	requestPath := interchain.GetSigningRequestPath(app, request.MessageID)
+	if err := interchain.SaveSigningRequest(requestPath, request); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Warp message %s signed by %d validators", request.MessageID, len(shares))
+	ux.Logger.PrintToUser("Signed message: %s", request.SignedMessage)
+	ux.Logger.PrintToUser("Rerun the command that failed signature aggregation to continue")
+	return nil
+}
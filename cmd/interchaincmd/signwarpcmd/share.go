@@ -0,0 +1,129 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package signwarpcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	sdkinterchain "github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpMessage "github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
+	warpPayload "github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/spf13/cobra"
+)
+
+var (
+	messagePath     string
+	signerKeyPaths  []string
+	shareCluster    string
+	shareOutputPath string
+)
+
+// avalanche interchain sign-warp share
+func newShareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "share",
+		Short: "Sign a warp message with a node BLS key",
+		Long: `Sign the warp message of a signing request with the BLS key of a validator node, producing
+a signature share to be sent back to whoever merges the shares.
+
+The BLS key is the node signer.key file (by default at ~/.avalanchego/staking/signer.key). Nodes
+of a cluster created by Avalanche-CLI can be signed for at once with --cluster.
+
+Check the message summary before sending the share: a signature is an attestation of the
+message by the validator.`,
+		RunE: share,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&messagePath, "message", "", "signing request file, as produced by sign-warp export")
+	cmd.Flags().StringSliceVar(&signerKeyPaths, "signer-key", nil, "node BLS signer key file(s) to sign with")
+	cmd.Flags().StringVar(&shareCluster, "cluster", "", "sign with the BLS keys of the nodes of this cluster")
+	cmd.Flags().StringVarP(&shareOutputPath, "output", "o", "", "write the signature shares to this file (default <messageID>-shares.json)")
+	return cmd
+}
+
+func share(_ *cobra.Command, _ []string) error {
+	if messagePath == "" {
+		return errors.New("--message is required")
+	}
+	if len(signerKeyPaths) == 0 && shareCluster == "" {
+		return errors.New("either --signer-key or --cluster must be given")
+	}
+	request, err := interchain.LoadSigningRequest(messagePath)
+	if err != nil {
+		return err
+	}
+	msg, err := request.GetUnsignedMessage()
+	if err != nil {
+		return err
+	}
+	printMessageSummary(msg)
+	keyPaths := signerKeyPaths
+	if shareCluster != "" {
+		clusterConfig, err := app.GetClusterConfig(shareCluster)
+		if err != nil {
+			return err
+		}
+		for _, instanceID := range clusterConfig.Nodes {
+			keyPath := app.GetNodeBLSSecretKeyPath(instanceID)
+			if !utils.FileExists(keyPath) {
+				ux.Logger.RedXToUser("BLS key of node %s not found at %s", instanceID, keyPath)
+				continue
+			}
+			keyPaths = append(keyPaths, keyPath)
+		}
+	}
+	shares := []sdkinterchain.SignatureShare{}
+	for _, keyPath := range keyPaths {
+		keyBytes, err := os.ReadFile(keyPath)
+		if err != nil {
+			return err
+		}
+		sk, err := bls.SecretKeyFromBytes(keyBytes)
+		if err != nil {
+			return fmt.Errorf("invalid BLS signer key %s: %w", keyPath, err)
+		}
+		share := sdkinterchain.SignShare(msg, sk)
+		ux.Logger.PrintToUser("Signed with BLS public key %s", share.PublicKey)
+		shares = append(shares, share)
+	}
+	if len(shares) == 0 {
+		return errors.New("no BLS key to sign with")
+	}
+	if shareOutputPath == "" {
+		shareOutputPath = request.MessageID.String() + "-shares.json"
+	}
+	sharesBytes, err := json.MarshalIndent(shares, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(shareOutputPath, sharesBytes, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Signature shares written to %s", shareOutputPath)
+	return nil
+}
+
+// printMessageSummary prints what is being signed
+func printMessageSummary(msg *warp.UnsignedMessage) {
+	ux.Logger.PrintToUser("Warp message %s", msg.ID())
+	ux.Logger.PrintToUser("  Network ID: %d", msg.NetworkID)
+	ux.Logger.PrintToUser("  Source Chain ID: %s", msg.SourceChainID)
+	payloadType := "unknown"
+	if addressedCall, err := warpPayload.ParseAddressedCall(msg.Payload); err == nil {
+		if payload, err := warpMessage.Parse(addressedCall.Payload); err == nil {
+			payloadType = strings.TrimPrefix(fmt.Sprintf("%T", payload), "*message.")
+		}
+	}
+	ux.Logger.PrintToUser("  Payload: %s", payloadType)
+}
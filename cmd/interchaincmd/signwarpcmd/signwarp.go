@@ -0,0 +1,37 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package signwarpcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche interchain sign-warp
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-warp",
+		Short: "Manually sign warp messages when signature aggregation fails",
+		Long: `The sign-warp command suite provides a manual recovery path for the flows (L1 conversion,
+validator registration, removal and weight changes) that get stuck because not enough
+validator signatures could be aggregated for a warp message.
+
+When aggregation fails, the unsigned message is saved as a signing request, and the failing
+command prints its location. From there:
+
+  1. export the request and send it to the validator operators
+  2. each operator signs it with their node BLS key (sign-warp share), and sends back the share
+  3. merge the shares into a signed message (sign-warp merge)
+  4. rerun the failing command: it uses the signed message instead of aggregating signatures`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	app = injectedApp
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newExportCmd())
+	cmd.AddCommand(newShareCmd())
+	cmd.AddCommand(newMergeCmd())
+	return cmd
+}
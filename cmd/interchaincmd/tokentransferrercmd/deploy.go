@@ -146,6 +146,13 @@ func getHomeKeyAndAddress(app *application.Avalanche, network models.Network, ho
 
 func CallDeploy(_ []string, flags DeployFlags) error {
 	if !ictt.FoundryIsInstalled() {
+		yes, err := app.Prompt.CaptureYesNo("Foundry is needed to compile the Token Transferrer contracts, but it is not installed. Do you want to install it? (runs the installer from https://foundry.paradigm.xyz)")
+		if err != nil {
+			return err
+		}
+		if !yes {
+			return fmt.Errorf("foundry is not installed. Please follow install instructions at https://book.getfoundry.sh/getting-started/installation and try again")
+		}
 		if err := ictt.InstallFoundry(); err != nil {
 			return err
 		}
@@ -553,6 +560,7 @@ func CallDeploy(_ []string, flags DeployFlags) error {
 		if err != nil {
 			return err
 		}
+		warnOnNativeTokenDecimals(flags.homeFlags.chainFlags.BlockchainName, flags.homeFlags.chainFlags.CChain)
 		wrappedNativeTokenAddress, err := ictt.DeployWrappedNativeToken(
 			icttSrcDir,
 			homeRPCEndpoint,
@@ -659,6 +667,7 @@ func CallDeploy(_ []string, flags DeployFlags) error {
 		if err != nil {
 			return err
 		}
+		warnOnNativeTokenDecimals(flags.remoteFlags.chainFlags.BlockchainName, flags.remoteFlags.chainFlags.CChain)
 		remoteSupply, err = contract.GetEVMSubnetGenesisSupply(
 			app,
 			network,
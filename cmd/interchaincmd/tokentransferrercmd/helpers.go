@@ -6,8 +6,11 @@ import (
 	_ "embed"
 	"fmt"
 
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
 )
 
 func validateSubnet(network models.Network, subnetName string) error {
@@ -32,3 +35,22 @@ func getNativeTokenSymbol(subnetName string, isCChain bool) (string, error) {
 	}
 	return nativeTokenSymbol, nil
 }
+
+// warnOnNativeTokenDecimals warns when the native token of [subnetName] is not handled with
+// 18 decimals, as the native token transferrers always assume 18 decimals for it
+func warnOnNativeTokenDecimals(subnetName string, isCChain bool) {
+	if isCChain {
+		return
+	}
+	decimals := app.GetTokenDecimals(subnetName)
+	if decimals == constants.DefaultTokenDecimals {
+		return
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap(
+		"Warning: %s native token amounts are shown by the CLI with %d decimals, but the Transferrer handles it as an 18 decimals token. "+
+			"Amounts sent to or received from other chains are scaled by 10^%d with respect to what the CLI shows"),
+		subnetName,
+		decimals,
+		constants.DefaultTokenDecimals-decimals,
+	)
+}
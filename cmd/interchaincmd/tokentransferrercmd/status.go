@@ -0,0 +1,321 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package tokentransferrercmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/ictt"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type StatusFlags struct {
+	Network     networkoptions.NetworkFlags
+	chainFlags  contract.ChainSpec
+	homeAddress string
+	rpcEndpoint string
+	remoteRPCs  []string
+	blocks      uint64
+	fromBlock   uint64
+}
+
+var statusFlags StatusFlags
+
+// remoteChain is a blockchain where remotes of the token home may live
+type remoteChain struct {
+	name        string
+	rpcEndpoint string
+}
+
+// avalanche interchain tokenTransferrer status
+func NewStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows the state of a Token Transferrer Home and its Remotes",
+		Long: `Shows, for each Remote registered on the given Transferrer Home, the amount of tokens
+transferred into it, its supply, its collateralization, its paused state, and the
+volume transferred from and to it over the last blocks.
+
+Remotes are looked up on the C-Chain, on the blockchains deployed with the CLI, and
+on the blockchains served by the RPC endpoints given with --remote-rpc.`,
+		RunE: status,
+		Args: cobrautils.ExactArgs(0),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &statusFlags.Network, true, deploySupportedNetworkOptions)
+	statusFlags.chainFlags.SetFlagNames(
+		"home-blockchain",
+		"c-chain-home",
+		"",
+		"",
+		"",
+	)
+	statusFlags.chainFlags.AddToCmd(cmd, "get the status of a Transferrer Home on %s")
+	cmd.Flags().StringVar(&statusFlags.homeAddress, "token", "", "address of the Transferrer Home")
+	cmd.Flags().StringVar(&statusFlags.rpcEndpoint, "home-rpc", "", "use the given RPC URL to connect to the home blockchain")
+	cmd.Flags().StringSliceVar(&statusFlags.remoteRPCs, "remote-rpc", nil, "use the given RPC URLs to look for remotes on blockchains not deployed with the CLI")
+	cmd.Flags().Uint64Var(&statusFlags.blocks, "blocks", 10000, "number of recent blocks to compute the transfer volume on")
+	cmd.Flags().Uint64Var(&statusFlags.fromBlock, "from-block", 0, "block to start looking for remote registrations from (defaults to the Home deployment block)")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func status(_ *cobra.Command, _ []string) error {
+	return CallStatus(statusFlags)
+}
+
+func CallStatus(flags StatusFlags) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"On what Network is the Transferrer deployed?",
+		flags.Network,
+		true,
+		false,
+		deploySupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if err := flags.chainFlags.CheckMutuallyExclusiveFields(); err != nil {
+		return err
+	}
+	if !flags.chainFlags.Defined() {
+		prompt := "Where is the Transferrer Home?"
+		if cancel, err := contract.PromptChain(app, network, prompt, "", &flags.chainFlags); err != nil {
+			return err
+		} else if cancel {
+			return nil
+		}
+	}
+	homeRPCEndpoint := flags.rpcEndpoint
+	if homeRPCEndpoint == "" {
+		homeRPCEndpoint, _, err = contract.GetBlockchainEndpoints(app, network, flags.chainFlags, true, false)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.homeAddress == "" {
+		addr, err := app.Prompt.CaptureAddress("Enter the address of the Home")
+		if err != nil {
+			return err
+		}
+		flags.homeAddress = addr.Hex()
+	}
+	if !common.IsHexAddress(flags.homeAddress) {
+		return fmt.Errorf("invalid Transferrer Home address %q", flags.homeAddress)
+	}
+	homeAddress := common.HexToAddress(flags.homeAddress)
+	homeKind, err := ictt.GetEndpointKind(homeRPCEndpoint, homeAddress)
+	if err != nil {
+		return fmt.Errorf("failure getting Transferrer kind at %s: %w", homeAddress, err)
+	}
+	if homeKind != ictt.ERC20TokenHome && homeKind != ictt.NativeTokenHome {
+		return fmt.Errorf("%s is not a Transferrer Home", homeAddress)
+	}
+	homeBlockchainID, err := interchain.GetWarpBlockchainID(homeRPCEndpoint)
+	if err != nil {
+		return err
+	}
+	homeDecimals, err := ictt.TokenHomeGetDecimals(homeRPCEndpoint, homeAddress)
+	if err != nil {
+		return err
+	}
+	fromBlock := flags.fromBlock
+	if fromBlock == 0 {
+		fromBlock, err = ictt.GetDeploymentBlock(homeRPCEndpoint, homeAddress)
+		if err != nil {
+			return err
+		}
+	}
+	remotes, err := ictt.TokenHomeGetRegisteredRemotes(homeRPCEndpoint, homeAddress, fromBlock)
+	if err != nil {
+		return err
+	}
+	chains, err := getRemoteChains(network, flags.remoteRPCs)
+	if err != nil {
+		return err
+	}
+
+	homePaused := "n/a"
+	if paused, supported := ictt.GetPaused(homeRPCEndpoint, homeAddress); supported {
+		homePaused = fmt.Sprintf("%t", paused)
+	}
+	ux.Logger.PrintToUser("Home: %s (%s)", homeAddress, endpointKindDesc(homeKind))
+	ux.Logger.PrintToUser("Home Blockchain: %s", homeBlockchainID)
+	ux.Logger.PrintToUser("Paused: %s", homePaused)
+	ux.Logger.PrintToUser("Registered Remotes: %d", len(remotes))
+	if len(remotes) == 0 {
+		return nil
+	}
+	ux.Logger.PrintToUser("")
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{
+		"Blockchain",
+		"Remote",
+		"Kind",
+		"Transferred",
+		"Remote Supply",
+		"Collateral Needed",
+		"Paused",
+		fmt.Sprintf("Sent (last %d blocks)", flags.blocks),
+		fmt.Sprintf("Returned (last %d blocks)", flags.blocks),
+	})
+	unreachable := []string{}
+	for _, remote := range remotes {
+		row, err := getRemoteStatusRow(
+			homeRPCEndpoint,
+			homeAddress,
+			homeBlockchainID,
+			homeDecimals,
+			remote,
+			chains[remote.BlockchainID],
+			flags.blocks,
+		)
+		if err != nil {
+			return err
+		}
+		if _, ok := chains[remote.BlockchainID]; !ok {
+			unreachable = append(unreachable, remote.BlockchainID.String())
+		}
+		table.Append(row)
+	}
+	table.Render()
+	if len(unreachable) > 0 {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("No RPC endpoint known for blockchains %v. Use --remote-rpc to get their remote side status", unreachable)
+	}
+	return nil
+}
+
+// getRemoteStatusRow gathers the status of [remote] from the home side and, if [chain]
+// is known, from the remote side
+func getRemoteStatusRow(
+	homeRPCEndpoint string,
+	homeAddress common.Address,
+	homeBlockchainID ids.ID,
+	homeDecimals uint8,
+	remote ictt.RemoteRegistration,
+	chain remoteChain,
+	blocks uint64,
+) ([]string, error) {
+	registeredRemote, err := ictt.TokenHomeGetRegisteredRemote(homeRPCEndpoint, homeAddress, remote.BlockchainID, remote.Address)
+	if err != nil {
+		return nil, err
+	}
+	transferred, err := ictt.TokenHomeGetTransferredBalance(homeRPCEndpoint, homeAddress, remote.BlockchainID, remote.Address)
+	if err != nil {
+		return nil, err
+	}
+	sent, err := ictt.GetSentVolume(homeRPCEndpoint, homeAddress, remote.BlockchainID, remote.Address, blocks)
+	if err != nil {
+		return nil, err
+	}
+	blockchainDesc := remote.BlockchainID.String()
+	kind, supply, paused, returned := "unknown", "unknown", "unknown", "unknown"
+	if chain.rpcEndpoint != "" {
+		blockchainDesc = chain.name
+		remoteKind, err := ictt.GetEndpointKind(chain.rpcEndpoint, remote.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failure getting Transferrer kind of remote %s on %s: %w", remote.Address, chain.name, err)
+		}
+		kind = endpointKindDesc(remoteKind)
+		var remoteSupply *big.Int
+		if remoteKind == ictt.NativeTokenRemote {
+			remoteSupply, err = ictt.NativeTokenRemoteGetTotalNativeAssetSupply(chain.rpcEndpoint, remote.Address)
+		} else {
+			remoteSupply, err = ictt.ERC20TokenRemoteGetTotalSupply(chain.rpcEndpoint, remote.Address)
+		}
+		if err != nil {
+			return nil, err
+		}
+		supply = utils.FormatAmount(remoteSupply, remote.Decimals)
+		paused = "n/a"
+		if isPaused, supported := ictt.GetPaused(chain.rpcEndpoint, remote.Address); supported {
+			paused = fmt.Sprintf("%t", isPaused)
+		}
+		returnedVolume, err := ictt.GetSentVolume(chain.rpcEndpoint, remote.Address, homeBlockchainID, homeAddress, blocks)
+		if err != nil {
+			return nil, err
+		}
+		returned = transferVolumeDesc(returnedVolume, remote.Decimals)
+	}
+	collateral := "fully collateralized"
+	if registeredRemote.CollateralNeeded.Sign() > 0 {
+		collateral = utils.FormatAmount(registeredRemote.CollateralNeeded, homeDecimals)
+	}
+	return []string{
+		blockchainDesc,
+		remote.Address.Hex(),
+		kind,
+		utils.FormatAmount(transferred, homeDecimals),
+		supply,
+		collateral,
+		paused,
+		transferVolumeDesc(sent, homeDecimals),
+		returned,
+	}, nil
+}
+
+// getRemoteChains returns the blockchains with a known RPC endpoint on [network], by blockchain ID
+func getRemoteChains(network models.Network, remoteRPCs []string) (map[ids.ID]remoteChain, error) {
+	chains := map[ids.ID]remoteChain{}
+	cChainID, err := utils.GetChainID(network.Endpoint, "C")
+	if err != nil {
+		return nil, err
+	}
+	chains[cChainID] = remoteChain{name: "C-Chain", rpcEndpoint: network.CChainEndpoint()}
+	blockchainNames, err := app.GetBlockchainNamesOnNetwork(network, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, blockchainName := range blockchainNames {
+		chainSpec := contract.ChainSpec{BlockchainName: blockchainName}
+		rpcEndpoint, _, err := contract.GetBlockchainEndpoints(app, network, chainSpec, false, false)
+		if err != nil || rpcEndpoint == "" {
+			continue
+		}
+		blockchainID, err := contract.GetBlockchainID(app, network, chainSpec)
+		if err != nil {
+			continue
+		}
+		chains[blockchainID] = remoteChain{name: blockchainName, rpcEndpoint: rpcEndpoint}
+	}
+	for _, rpcEndpoint := range remoteRPCs {
+		blockchainID, err := interchain.GetWarpBlockchainID(rpcEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failure getting blockchain ID of %s: %w", rpcEndpoint, err)
+		}
+		chains[blockchainID] = remoteChain{name: blockchainID.String(), rpcEndpoint: rpcEndpoint}
+	}
+	return chains, nil
+}
+
+func endpointKindDesc(kind ictt.EndpointKind) string {
+	switch kind {
+	case ictt.ERC20TokenHome:
+		return "ERC20 Home"
+	case ictt.NativeTokenHome:
+		return "Native Home"
+	case ictt.ERC20TokenRemote:
+		return "ERC20 Remote"
+	case ictt.NativeTokenRemote:
+		return "Native Remote"
+	}
+	return "unknown"
+}
+
+func transferVolumeDesc(volume ictt.TransferVolume, decimals uint8) string {
+	return fmt.Sprintf("%s (%d txs)", utils.FormatAmount(volume.Amount, decimals), volume.Transfers)
+}
@@ -21,5 +21,7 @@ func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 	app = injectedApp
 	// tokenTransferrer deploy
 	cmd.AddCommand(NewDeployCmd())
+	// tokenTransferrer status
+	cmd.AddCommand(NewStatusCmd())
 	return cmd
 }
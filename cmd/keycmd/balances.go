@@ -0,0 +1,325 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/balances"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+type BalancesFlags struct {
+	Network     networkoptions.NetworkFlags
+	blockchains []string
+	showUSD     bool
+}
+
+var balancesFlags BalancesFlags
+
+const (
+	// max number of balance queries running at the same time
+	balancesParallelism = 16
+	avaxDecimals        = 9
+	evmDecimals         = 18
+)
+
+// avalanche key balances
+func newBalancesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "balances [keyName]",
+		Short: "Show the balances of stored keys across networks and chains",
+		Long: `The key balances command queries, concurrently, the balances of all the stored
+keys (or of the given one) on the P-Chain, X-Chain, C-Chain, and on all the EVM
+Blockchains deployed to each network, and prints one balance matrix per network.
+
+By default Local Network, Fuji and Mainnet are queried. Use the network flags to
+restrict the query to one network, and --blockchains to restrict it to some chains.
+With --usd, the AVAX balances of Mainnet are also estimated in USD.`,
+		RunE: showBalances,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &balancesFlags.Network, false, listSupportedNetworkOptions)
+	cmd.Flags().StringSliceVar(
+		&balancesFlags.blockchains,
+		"blockchains",
+		[]string{},
+		"chains to query (p=p-chain, x=x-chain, c=c-chain, and blockchain names) (default p,x,c and all deployed blockchains)",
+	)
+	cmd.Flags().BoolVar(&balancesFlags.showUSD, "usd", false, "estimate the USD value of Mainnet AVAX balances")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func showBalances(_ *cobra.Command, args []string) error {
+	keyNames, err := utils.GetKeyNames(app.GetKeyDir(), true)
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		if !utils.Belongs(keyNames, args[0]) {
+			return fmt.Errorf("key %s does not exist", args[0])
+		}
+		keyNames = []string{args[0]}
+	}
+	networks, err := getBalancesNetworks()
+	if err != nil {
+		return err
+	}
+	queries := []balances.Query{}
+	clients := []ethclient.Client{}
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+	for _, network := range networks {
+		networkQueries, networkClients, err := getNetworkBalanceQueries(network, keyNames)
+		if err != nil {
+			return err
+		}
+		queries = append(queries, networkQueries...)
+		clients = append(clients, networkClients...)
+	}
+	results := balances.FetchAll(queries, balancesParallelism)
+	avaxPrice := 0.0
+	if balancesFlags.showUSD {
+		avaxPrice, err = balances.GetAVAXPriceUSD(constants.AVAXPriceURL)
+		if err != nil {
+			ux.Logger.RedXToUser("failure obtaining AVAX price, USD estimates are not shown: %s", err)
+		}
+	}
+	for _, matrix := range balances.BuildMatrices(results) {
+		printBalancesMatrix(matrix, avaxPrice)
+	}
+	printBalancesErrors(results)
+	return nil
+}
+
+func getBalancesNetworks() ([]models.Network, error) {
+	flags := balancesFlags.Network
+	switch {
+	case flags.UseLocal:
+		return []models.Network{app.GetLocalNetwork()}, nil
+	case flags.UseFuji:
+		return []models.Network{models.NewFujiNetwork()}, nil
+	case flags.UseMainnet:
+		return []models.Network{models.NewMainnetNetwork()}, nil
+	case flags.UseDevnet || flags.Endpoint != "" || flags.ClusterName != "":
+		network, err := networkoptions.GetNetworkFromCmdLineFlags(
+			app,
+			"",
+			flags,
+			true,
+			false,
+			listSupportedNetworkOptions,
+			"",
+		)
+		if err != nil {
+			return nil, err
+		}
+		return []models.Network{network}, nil
+	default:
+		return []models.Network{
+			app.GetLocalNetwork(),
+			models.NewFujiNetwork(),
+			models.NewMainnetNetwork(),
+		}, nil
+	}
+}
+
+// getNetworkBalanceQueries returns the balance queries of [keyNames] on the chains of
+// [network] selected by --blockchains, together with the EVM clients they use
+func getNetworkBalanceQueries(network models.Network, keyNames []string) ([]balances.Query, []ethclient.Client, error) {
+	chains := balancesFlags.blockchains
+	if len(chains) == 0 {
+		blockchainNames, err := app.GetBlockchainNamesOnNetwork(network, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		chains = append([]string{"p", "x", "c"}, blockchainNames...)
+	}
+	queries := []balances.Query{}
+	clients := []ethclient.Client{}
+	pClient := platformvm.NewClient(network.Endpoint)
+	xClient := avm.NewClient(network.Endpoint, "X")
+	for _, chain := range chains {
+		var (
+			chainName = chain
+			token     = "AVAX"
+			decimals  = uint8(evmDecimals)
+			rpcURL    string
+		)
+		switch chain {
+		case "p", "x":
+		case "c":
+			chainName = balances.CChain
+			rpcURL = network.CChainEndpoint()
+		default:
+			isEVM, _, err := app.HasSubnetEVMGenesis(chain)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !isEVM {
+				continue
+			}
+			rpcURL, _, err = contract.GetBlockchainEndpoints(
+				app,
+				network,
+				contract.ChainSpec{
+					BlockchainName: chain,
+				},
+				false,
+				false,
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+			if rpcURL == "" {
+				continue
+			}
+			token = app.GetTokenSymbol(chain)
+			decimals = app.GetTokenDecimals(chain)
+		}
+		var client ethclient.Client
+		var clientErr error
+		if rpcURL != "" {
+			if client, clientErr = evm.GetClient(rpcURL); clientErr == nil {
+				clients = append(clients, client)
+			}
+		}
+		for _, keyName := range keyNames {
+			sk, err := app.GetKey(keyName, network, false)
+			if err != nil {
+				return nil, nil, err
+			}
+			query := balances.Query{
+				Network: network.Name(),
+				Key:     keyName,
+				Chain:   chainName,
+				Token:   token,
+				AVAX:    token == "AVAX",
+			}
+			switch chain {
+			case "p":
+				query.Chain = balances.PChain
+				query.Decimals = avaxDecimals
+				for _, addr := range sk.P() {
+					query.Fetch = getPChainBalanceFetcher(pClient, addr)
+					queries = append(queries, query)
+				}
+			case "x":
+				query.Chain = balances.XChain
+				query.Decimals = avaxDecimals
+				for _, addr := range sk.X() {
+					query.Fetch = getXChainBalanceFetcher(xClient, addr)
+					queries = append(queries, query)
+				}
+			default:
+				query.Decimals = decimals
+				query.AVAX = chain == "c"
+				query.Fetch = getEVMBalanceFetcher(client, clientErr, sk.C())
+				queries = append(queries, query)
+			}
+		}
+	}
+	return queries, clients, nil
+}
+
+func getPChainBalanceFetcher(pClient platformvm.Client, addr string) func(context.Context) (*big.Int, error) {
+	return func(ctx context.Context) (*big.Int, error) {
+		balance, err := getPChainBalance(ctx, pClient, addr)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetUint64(balance), nil
+	}
+}
+
+func getXChainBalanceFetcher(xClient avm.Client, addr string) func(context.Context) (*big.Int, error) {
+	return func(ctx context.Context) (*big.Int, error) {
+		balance, err := getXChainBalance(ctx, xClient, addr)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetUint64(balance), nil
+	}
+}
+
+func getEVMBalanceFetcher(client ethclient.Client, clientErr error, addr string) func(context.Context) (*big.Int, error) {
+	return func(ctx context.Context) (*big.Int, error) {
+		if clientErr != nil {
+			return nil, clientErr
+		}
+		return getEVMBalance(ctx, client, addr)
+	}
+}
+
+// printBalancesMatrix prints the balances of [matrix], with the USD estimate of the
+// AVAX balances if the network is Mainnet and [avaxPrice] is known
+func printBalancesMatrix(matrix balances.Matrix, avaxPrice float64) {
+	showUSD := balancesFlags.showUSD && avaxPrice > 0 && matrix.Network == models.NewMainnetNetwork().Name()
+	header := table.Row{"Key"}
+	for _, column := range matrix.Columns {
+		header = append(header, column.String())
+	}
+	if showUSD {
+		header = append(header, "AVAX in USD")
+	}
+	t := ux.DefaultTable(fmt.Sprintf("Balances on %s", matrix.Network), header)
+	for _, row := range matrix.Rows {
+		tableRow := table.Row{row.Key}
+		for _, column := range matrix.Columns {
+			cell, ok := row.Cells[column]
+			switch {
+			case !ok:
+				tableRow = append(tableRow, "-")
+			case cell.Err != nil:
+				tableRow = append(tableRow, logging.Red.Wrap("error"))
+			default:
+				tableRow = append(tableRow, balances.FormatBalance(cell.Balance, cell.Decimals))
+			}
+		}
+		if showUSD {
+			total, complete := row.AVAXTotal()
+			usd, _ := total.Float64()
+			usdStr := fmt.Sprintf("$%.2f", usd*avaxPrice)
+			if !complete {
+				usdStr = ">= " + usdStr
+			}
+			tableRow = append(tableRow, usdStr)
+		}
+		t.AppendRow(tableRow)
+	}
+	ux.Logger.PrintToUser(t.Render())
+}
+
+// printBalancesErrors shows the first error found on each chain of each network
+func printBalancesErrors(results []balances.Result) {
+	reported := map[string]bool{}
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		chainDesc := fmt.Sprintf("%s %s", result.Network, result.Chain)
+		if reported[chainDesc] {
+			continue
+		}
+		reported[chainDesc] = true
+		ux.Logger.RedXToUser("failure obtaining balances on %s: %s", chainDesc, result.Err)
+	}
+}
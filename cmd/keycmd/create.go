@@ -5,11 +5,14 @@ package keycmd
 import (
 	"errors"
 	"regexp"
+	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +24,7 @@ var (
 	forceCreate  bool
 	skipBalances bool
 	filename     string
+	hdWallet     bool
 )
 
 func createKey(_ *cobra.Command, args []string) error {
@@ -30,9 +34,20 @@ func createKey(_ *cobra.Command, args []string) error {
 		return errors.New("key name contains whitespace")
 	}
 
+	if hdWallet {
+		return createHDWallet(keyName)
+	}
+
 	if app.KeyExists(keyName) && !forceCreate {
 		return errors.New("key already exists. Use --" + forceFlag + " parameter to overwrite")
 	}
+	if app.HDWalletExists(keyName) {
+		return errors.New("an HD wallet with the same name already exists")
+	}
+	// an overwritten key must be approved again for mainnet
+	if err := clearKeyTag(keyName); err != nil {
+		return err
+	}
 
 	if filename == "" {
 		// Create key from scratch
@@ -74,6 +89,48 @@ func createKey(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+func createHDWallet(walletName string) error {
+	if strings.Contains(walletName, "/") {
+		return errors.New("HD wallet name contains /")
+	}
+	if app.HDWalletExists(walletName) && !forceCreate {
+		return errors.New("HD wallet already exists. Use --" + forceFlag + " parameter to overwrite")
+	}
+	if utils.FileExists(app.GetKeyPath(walletName)) {
+		return errors.New("a key with the same name already exists")
+	}
+	var (
+		w   *key.HDWallet
+		err error
+	)
+	if filename == "" {
+		ux.Logger.PrintToUser("Generating new HD wallet...")
+		w, err = key.NewHDWallet()
+	} else {
+		ux.Logger.PrintToUser("Loading mnemonic...")
+		w, err = key.LoadHDWallet(utils.ExpandHome(filename))
+	}
+	if err != nil {
+		return err
+	}
+	if err := w.Save(app.GetHDWalletPath(walletName)); err != nil {
+		return err
+	}
+	if filename == "" {
+		ux.Logger.PrintToUser("HD wallet created. Write down its mnemonic and keep it safe, as anyone knowing it")
+		ux.Logger.PrintToUser("controls all of the wallet accounts. It can be shown again with avalanche key export %s", walletName)
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("  %s", logging.Yellow.Wrap(w.Mnemonic()))
+		ux.Logger.PrintToUser("")
+	} else {
+		ux.Logger.PrintToUser("HD wallet imported")
+	}
+	ux.Logger.PrintToUser("Its accounts can be used as keys named:")
+	ux.Logger.PrintToUser("  %s/<index> for the X/P-Chain account at m/44'/9000'/0'/0/<index>", walletName)
+	ux.Logger.PrintToUser("  %s/evm/<index> for the EVM account at m/44'/60'/0'/0/<index>", walletName)
+	return nil
+}
+
 func newCreateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create [keyName]",
@@ -86,7 +143,13 @@ The command works by generating a secp256 key and storing it with the provided k
 can use this key in other commands by providing this keyName.
 
 If you'd like to import an existing key instead of generating one from scratch, provide the
---file flag.`,
+--file flag.
+
+With the --hd flag, a BIP-39 mnemonic is stored instead, from which any number of deterministic
+accounts are derived on demand. Account <index> is used in other commands as keyName/<index>
+(X/P-Chain path m/44'/9000'/0'/0/<index>) or keyName/evm/<index> (EVM path m/44'/60'/0'/0/<index>).
+The generated mnemonic is shown once created, and with key export. To import an existing
+mnemonic, provide the file holding it with --file.`,
 		Args: cobrautils.ExactArgs(1),
 		RunE: createKey,
 	}
@@ -95,7 +158,7 @@ If you'd like to import an existing key instead of generating one from scratch,
 		&filename,
 		"file",
 		"",
-		"import the key from an existing key file",
+		"import the key from an existing key file, or the mnemonic from a mnemonic file with --hd",
 	)
 	cmd.Flags().BoolVarP(
 		&forceCreate,
@@ -104,6 +167,12 @@ If you'd like to import an existing key instead of generating one from scratch,
 		false,
 		"overwrite an existing key with the same name",
 	)
+	cmd.Flags().BoolVar(
+		&hdWallet,
+		"hd",
+		false,
+		"generate a mnemonic to derive keyName/<index> and keyName/evm/<index> accounts from",
+	)
 	cmd.Flags().BoolVar(
 		&skipBalances,
 		"skip-balances",
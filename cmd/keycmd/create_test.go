@@ -0,0 +1,40 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/testutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateKeyForceClearsTag(t *testing.T) {
+	require := require.New(t)
+	app = testutils.SetupTestInTempDir(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	require.NoError(os.MkdirAll(filepath.Dir(app.GetKeyPath("mykey")), constants.DefaultPerms755))
+
+	require.NoError(createKey(nil, []string{"mykey"}))
+	require.NoError(tagKey(nil, []string{"mykey", string(key.TagMainnetApproved)}))
+	approved, err := app.GetKey("mykey", app.GetLocalNetwork(), false)
+	require.NoError(err)
+
+	forceCreate = true
+	t.Cleanup(func() { forceCreate = false })
+	require.NoError(createKey(nil, []string{"mykey"}))
+	overwritten, err := app.GetKey("mykey", app.GetLocalNetwork(), false)
+	require.NoError(err)
+	require.NotEqual(approved.PrivKeyHex(), overwritten.PrivKeyHex())
+
+	tags, err := key.LoadTags(app.GetKeyTagsPath())
+	require.NoError(err)
+	require.Equal(key.TagTestOnly, tags.Get("mykey"))
+}
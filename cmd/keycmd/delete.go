@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/spf13/cobra"
 )
@@ -18,10 +19,11 @@ func newDeleteCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "delete [keyName]",
 		Short: "Delete a signing key",
-		Long: `The key delete command deletes an existing signing key.
+		Long: `The key delete command deletes an existing signing key, or HD wallet.
 
 To delete a key, provide the keyName. The command prompts for confirmation
-before deleting the key. To skip the confirmation, provide the --force flag.`,
+before deleting the key, listing the places where the key is referenced (see key describe).
+To skip the confirmation, provide the --force flag.`,
 		RunE: deleteKey,
 		Args: cobrautils.ExactArgs(1),
 	}
@@ -41,11 +43,26 @@ func deleteKey(_ *cobra.Command, args []string) error {
 
 	// Check file exists
 	_, err := os.Stat(keyPath)
-	if err != nil {
+	isHDWallet := err != nil && app.HDWalletExists(keyName)
+	if isHDWallet {
+		keyPath = app.GetHDWalletPath(keyName)
+	} else if err != nil {
 		return errors.New("key does not exist")
 	}
 
 	if !forceDelete {
+		if isHDWallet {
+			ux.Logger.PrintToUser("All of the accounts derived from the HD wallet will be lost, unless its mnemonic is backed up")
+		} else if sk, err := key.LoadSoft(app.GetLocalNetwork().ID, keyPath); err == nil {
+			usages, err := getKeyUsages(keyName, sk)
+			if len(usages) > 0 {
+				ux.Logger.PrintToUser("The key is referenced in the following places:")
+				printKeyUsages(usages)
+			}
+			if err != nil {
+				ux.Logger.RedXToUser("some configs could not be checked for references to the key: %s", err)
+			}
+		}
 		confStr := "Are you sure you want to delete " + keyName + "?"
 		conf, err := app.Prompt.CaptureNoYes(confStr)
 		if err != nil {
@@ -63,6 +80,10 @@ func deleteKey(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := clearKeyTag(keyName); err != nil {
+		return err
+	}
+
 	ux.Logger.PrintToUser("Key deleted")
 
 	return nil
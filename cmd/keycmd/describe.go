@@ -0,0 +1,322 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+// keyUsage is a place where a stored key is referenced
+type keyUsage struct {
+	where   string
+	usage   string
+	details string
+}
+
+// avalanche key describe
+func newDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe [keyName]",
+		Short: "Show where a signing key is used, and its balances",
+		Long: `The key describe command reports every place a stored key is referenced: blockchain
+genesis airdrops, validator manager and proxy admin owners, bootstrap validator change owners,
+ICM deployer and relayer keys, local and cluster relayer configs, and devnet funded keys.
+
+It also prints the key balances on all known networks: Fuji, Mainnet, the local network if running,
+added devnets, and the networks of the configured clusters.
+
+Use --copy or --qr to share the key EVM address, for example to receive funds from a mobile wallet.`,
+		RunE: describeKey,
+		Args: cobrautils.ExactArgs(1),
+	}
+	cmd.Flags().BoolVar(&skipBalances, "skip-balances", false, "do not query the key balances")
+	cmd.Flags().BoolVar(&copyAddress, "copy", false, "copy the key EVM address to the clipboard")
+	cmd.Flags().BoolVar(&showQRCode, "qr", false, "render the key EVM address as a QR code")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func describeKey(_ *cobra.Command, args []string) error {
+	keyName := args[0]
+	if !app.KeyExists(keyName) {
+		return errors.New("key does not exist")
+	}
+	sk, err := app.GetKey(keyName, app.GetLocalNetwork(), false)
+	if err != nil {
+		return err
+	}
+	tags, err := key.LoadTags(app.GetKeyTagsPath())
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Key %s (%s)", keyName, tags.Get(keyName))
+	ux.Logger.PrintToUser("EVM Address: %s", sk.C())
+	if err := ux.ShareValue("EVM Address", sk.C(), copyAddress, showQRCode); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+
+	usages, err := getKeyUsages(keyName, sk)
+	printKeyUsages(usages)
+	if err != nil {
+		ux.Logger.RedXToUser("some configs could not be checked for references to the key: %s", err)
+	}
+
+	if skipBalances {
+		return nil
+	}
+	ux.Logger.PrintToUser("")
+	showNativeToken = true
+	tokenAddresses = nil
+	addrInfos := []addressInfo{}
+	for _, network := range getKnownNetworks() {
+		clients, err := getClients([]models.Network{network}, true, true, true, nil)
+		if err != nil {
+			ux.Logger.RedXToUser("failure connecting to %s: %s", network.Name(), err)
+			continue
+		}
+		networkAddrInfos, err := getStoredKeyInfo(clients, []models.Network{network}, keyName)
+		if err != nil {
+			ux.Logger.RedXToUser("failure obtaining balances on %s: %s", network.Name(), err)
+			continue
+		}
+		addrInfos = append(addrInfos, networkAddrInfos...)
+	}
+	printAddrInfos(addrInfos)
+	return nil
+}
+
+func printKeyUsages(usages []keyUsage) {
+	if len(usages) == 0 {
+		ux.Logger.PrintToUser("No references to this key were found in blockchain, relayer, cluster or devnet configs")
+		return
+	}
+	t := ux.DefaultTable("Key Usages", table.Row{"Where", "Usage", "Details"})
+	for _, u := range usages {
+		t.AppendRow(table.Row{u.where, u.usage, u.details})
+	}
+	ux.Logger.PrintToUser(t.Render())
+}
+
+// getKeyUsages scans blockchain sidecars and genesis, relayer configs, cluster configs and
+// devnet configs for references to the stored key [keyName]. A config that can't be
+// read does not stop the scan: the usages found on the others are returned, together
+// with the errors found
+func getKeyUsages(keyName string, sk *key.SoftKey) ([]keyUsage, error) {
+	usages, errs := getBlockchainKeyUsages(keyName, sk)
+	if keyName == constants.ICMRelayerKeyName {
+		usages = append(usages, keyUsage{
+			where:   "ICM relayer",
+			usage:   "relayer funding key",
+			details: "funds message delivery of relayers deployed by the CLI",
+		})
+	}
+	relayerUsages, err := getRelayerKeyUsages(sk)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	usages = append(usages, relayerUsages...)
+	devnetUsages, err := getDevnetKeyUsages(sk)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return append(usages, devnetUsages...), errors.Join(errs...)
+}
+
+// getBlockchainKeyUsages looks for [sk] on the blockchain configs, skipping the ones
+// that can't be loaded and returning their errors
+func getBlockchainKeyUsages(keyName string, sk *key.SoftKey) ([]keyUsage, []error) {
+	blockchainNames, err := app.GetBlockchainNames()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+	evmAddr := common.HexToAddress(sk.C())
+	usages := []keyUsage{}
+	errs := []error{}
+	for _, blockchainName := range blockchainNames {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("blockchain %s: %w", blockchainName, err))
+			continue
+		}
+		where := "blockchain " + blockchainName
+		if sc.TeleporterReady && sc.TeleporterKey == keyName {
+			usages = append(usages, keyUsage{where: where, usage: "ICM deployer key", details: "deploys and funds ICM contracts"})
+		}
+		if isSubnetEVM, _, err := app.HasSubnetEVMGenesis(blockchainName); err == nil && isSubnetEVM {
+			genesis, err := app.LoadEvmGenesis(blockchainName)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("blockchain %s genesis: %w", blockchainName, err))
+			} else if alloc, ok := genesis.Alloc[evmAddr]; ok && alloc.Balance != nil {
+				usages = append(usages, keyUsage{
+					where:   where,
+					usage:   "genesis airdrop",
+					details: fmt.Sprintf("%s %s", utils.FormatAmount(alloc.Balance, sc.GetTokenDecimals()), sc.TokenSymbol),
+				})
+			}
+		}
+		if sc.ValidatorManagerOwner != "" && common.HexToAddress(sc.ValidatorManagerOwner) == evmAddr {
+			usages = append(usages, keyUsage{where: where, usage: "validator manager owner"})
+		}
+		if sc.ProxyContractOwner != "" && common.HexToAddress(sc.ProxyContractOwner) == evmAddr {
+			usages = append(usages, keyUsage{where: where, usage: "proxy admin owner"})
+		}
+		networkNames := maps.Keys(sc.Networks)
+		sort.Strings(networkNames)
+		for _, networkName := range networkNames {
+			for _, validator := range sc.Networks[networkName].BootstrapValidators {
+				if isKeyPChainAddr(sk, validator.ChangeOwnerAddr) {
+					usages = append(usages, keyUsage{
+						where:   fmt.Sprintf("%s on %s", where, networkName),
+						usage:   "bootstrap validator change owner",
+						details: "node " + validator.NodeID,
+					})
+				}
+			}
+		}
+	}
+	return usages, errs
+}
+
+// isKeyPChainAddr checks if [addr] is a P-Chain address of [sk], on any network
+func isKeyPChainAddr(sk *key.SoftKey, addr string) bool {
+	if addr == "" {
+		return false
+	}
+	shortID, err := address.ParseToID(addr)
+	if err != nil {
+		return false
+	}
+	for _, keyShortID := range sk.Addresses() {
+		if keyShortID == shortID {
+			return true
+		}
+	}
+	return false
+}
+
+// getRelayerKeyUsages looks for [sk] as the funding key of relayer destinations, both
+// on relayers run by the CLI on this machine, and on relayers of cloud clusters
+func getRelayerKeyUsages(sk *key.SoftKey) ([]keyUsage, error) {
+	relayerConfigs := map[string]string{}
+	for _, networkKind := range []models.NetworkKind{models.Local, models.Devnet, models.Fuji, models.Mainnet} {
+		relayerConfigs[app.GetLocalRelayerConfigPath(networkKind, "")] = fmt.Sprintf("local relayer (%s)", networkKind)
+	}
+	if clusterInfo, err := localnet.GetClusterInfo(); err == nil {
+		relayerConfigs[app.GetLocalRelayerConfigPath(models.Local, clusterInfo.GetRootDataDir())] = fmt.Sprintf("local relayer (%s)", models.Local)
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return nil, err
+	}
+	for clusterName, clusterConfig := range clustersConfig.Clusters {
+		for _, nodeName := range clusterConfig.Nodes {
+			configPath := app.GetICMRelayerServiceConfigPath(app.GetNodeInstanceDirPath(nodeName))
+			relayerConfigs[configPath] = fmt.Sprintf("cluster %s relayer (node %s)", clusterName, nodeName)
+		}
+	}
+	configPaths := maps.Keys(relayerConfigs)
+	sort.Strings(configPaths)
+	usages := []keyUsage{}
+	for _, configPath := range configPaths {
+		if !utils.FileExists(configPath) {
+			continue
+		}
+		blockchainIDs, err := interchain.GetRelayerConfigKeyDestinations(configPath, sk.PrivKeyHex())
+		if err != nil {
+			ux.Logger.RedXToUser("failure reading relayer config %s: %s", configPath, err)
+			continue
+		}
+		if len(blockchainIDs) > 0 {
+			usages = append(usages, keyUsage{
+				where:   relayerConfigs[configPath],
+				usage:   "relayer funding key",
+				details: "pays delivery to " + strings.Join(blockchainIDs, ", "),
+			})
+		}
+	}
+	return usages, nil
+}
+
+// getDevnetKeyUsages looks for [sk] on the funded keys (eg faucets) of added devnets
+func getDevnetKeyUsages(sk *key.SoftKey) ([]keyUsage, error) {
+	devnetsConfig, err := app.LoadDevnetsConfig()
+	if err != nil {
+		return nil, err
+	}
+	evmAddr := common.HexToAddress(sk.C())
+	devnetNames := maps.Keys(devnetsConfig.Devnets)
+	sort.Strings(devnetNames)
+	usages := []keyUsage{}
+	for _, devnetName := range devnetNames {
+		for _, devnetKey := range devnetsConfig.Devnets[devnetName].Keys {
+			if (devnetKey.CChainAddr != "" && common.HexToAddress(devnetKey.CChainAddr) == evmAddr) ||
+				isKeyPChainAddr(sk, devnetKey.PChainAddr) {
+				usages = append(usages, keyUsage{
+					where:   "devnet " + devnetName,
+					usage:   "funded key " + devnetKey.Name,
+					details: devnetKey.Description,
+				})
+			}
+		}
+	}
+	return usages, nil
+}
+
+// getKnownNetworks returns Fuji, Mainnet, the local network if it is running, added
+// devnets and the networks of the configured clusters, without repetitions
+func getKnownNetworks() []models.Network {
+	networks := []models.Network{models.NewFujiNetwork(), models.NewMainnetNetwork()}
+	if _, err := localnet.GetClusterInfo(); err == nil {
+		networks = append(networks, app.GetLocalNetwork())
+	}
+	endpoints := map[string]bool{}
+	for _, network := range networks {
+		endpoints[network.Endpoint] = true
+	}
+	addNetwork := func(network models.Network) {
+		if network.Endpoint == "" || endpoints[network.Endpoint] {
+			return
+		}
+		endpoints[network.Endpoint] = true
+		networks = append(networks, network)
+	}
+	if devnetsConfig, err := app.LoadDevnetsConfig(); err == nil {
+		devnetNames := maps.Keys(devnetsConfig.Devnets)
+		sort.Strings(devnetNames)
+		for _, devnetName := range devnetNames {
+			addNetwork(devnetsConfig.Devnets[devnetName].Network())
+		}
+	}
+	if clustersConfig, err := app.LoadClustersConfig(); err == nil {
+		clusterNames := maps.Keys(clustersConfig.Clusters)
+		sort.Strings(clusterNames)
+		for _, clusterName := range clusterNames {
+			if clustersConfig.Clusters[clusterName].Local {
+				continue
+			}
+			addNetwork(clustersConfig.Clusters[clusterName].Network)
+		}
+	}
+	return networks
+}
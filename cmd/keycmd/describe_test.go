@@ -0,0 +1,80 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/testutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKeyUsages(t *testing.T) {
+	require := require.New(t)
+	app = testutils.SetupTestInTempDir(t)
+
+	sk, err := key.NewSoft(models.NewLocalNetwork().ID)
+	require.NoError(err)
+	other, err := key.NewSoft(models.NewLocalNetwork().ID)
+	require.NoError(err)
+
+	require.NoError(app.CreateSidecar(&models.Sidecar{
+		Name:                  "owned",
+		ValidatorManagerOwner: sk.C(),
+		ProxyContractOwner:    other.C(),
+		Networks: map[string]models.NetworkData{
+			models.Local.String(): {
+				BootstrapValidators: []models.SubnetValidator{
+					{NodeID: "NodeID-1", ChangeOwnerAddr: sk.P()[0]},
+				},
+			},
+		},
+	}))
+	// a config that can't be parsed must not hide the references found on the others
+	brokenSidecarPath := app.GetSidecarPath("broken")
+	require.NoError(os.MkdirAll(filepath.Dir(brokenSidecarPath), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(brokenSidecarPath, []byte("{"), constants.WriteReadReadPerms))
+	require.NoError(app.WriteDevnetsConfigFile(&models.DevnetsConfig{
+		Devnets: map[string]models.DevnetConfig{
+			"dev": {
+				Name:     "dev",
+				Endpoint: "http://127.0.0.1:9650",
+				Keys:     []models.DevnetKey{{Name: "faucet", CChainAddr: sk.C(), Description: "faucet key"}},
+			},
+		},
+	}))
+
+	usages, err := getKeyUsages("mykey", sk)
+	require.ErrorContains(err, "blockchain broken")
+	require.Equal([]keyUsage{
+		{where: "blockchain owned", usage: "validator manager owner"},
+		{where: "blockchain owned on " + models.Local.String(), usage: "bootstrap validator change owner", details: "node NodeID-1"},
+		{where: "devnet dev", usage: "funded key faucet", details: "faucet key"},
+	}, usages)
+
+	usages, err = getKeyUsages("other", other)
+	require.ErrorContains(err, "blockchain broken")
+	require.Equal([]keyUsage{{where: "blockchain owned", usage: "proxy admin owner"}}, usages)
+}
+
+func TestIsKeyPChainAddr(t *testing.T) {
+	require := require.New(t)
+	sk, err := key.NewSoft(models.NewFujiNetwork().ID)
+	require.NoError(err)
+	other, err := key.NewSoft(models.NewFujiNetwork().ID)
+	require.NoError(err)
+	// the address matches on any network HRP
+	localKey, err := key.NewSoft(models.NewLocalNetwork().ID, key.WithPrivateKey(sk.PrivKey()))
+	require.NoError(err)
+
+	require.True(isKeyPChainAddr(sk, sk.P()[0]))
+	require.True(isKeyPChainAddr(sk, localKey.P()[0]))
+	require.False(isKeyPChainAddr(sk, other.P()[0]))
+	require.False(isKeyPChainAddr(sk, ""))
+	require.False(isKeyPChainAddr(sk, "P-invalid"))
+}
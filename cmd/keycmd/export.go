@@ -8,6 +8,8 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
 
 	"github.com/spf13/cobra"
 )
@@ -20,7 +22,10 @@ func newExportCmd() *cobra.Command {
 applications or import it into another instance of Avalanche-CLI.
 
 By default, the tool writes the hex encoded key to stdout. If you provide the --output
-flag, the command writes the key to a file of your choosing.`,
+flag, the command writes the key to a file of your choosing.
+
+For an HD wallet, the command exports its mnemonic, and for an account of it (eg
+wallet/0 or wallet/evm/0), the hex encoded key of the account.`,
 		Args: cobrautils.ExactArgs(1),
 		RunE: exportKey,
 	}
@@ -39,10 +44,25 @@ flag, the command writes the key to a file of your choosing.`,
 func exportKey(_ *cobra.Command, args []string) error {
 	keyName := args[0]
 
-	keyPath := app.GetKeyPath(keyName)
-	keyBytes, err := os.ReadFile(keyPath)
-	if err != nil {
-		return err
+	var keyBytes []byte
+	if _, _, _, ok := key.ParseDerivedKeyName(keyName); ok {
+		k, err := app.GetKey(keyName, app.GetLocalNetwork(), false)
+		if err != nil {
+			return err
+		}
+		keyBytes = []byte(k.PrivKeyHex())
+	} else if !utils.FileExists(app.GetKeyPath(keyName)) && app.HDWalletExists(keyName) {
+		w, err := key.LoadHDWallet(app.GetHDWalletPath(keyName))
+		if err != nil {
+			return err
+		}
+		keyBytes = []byte(w.Mnemonic())
+	} else {
+		var err error
+		keyBytes, err = os.ReadFile(app.GetKeyPath(keyName))
+		if err != nil {
+			return err
+		}
 	}
 
 	if filename == "" {
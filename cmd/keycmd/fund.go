@@ -0,0 +1,234 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/faucet"
+	"github.com/ava-labs/avalanche-cli/pkg/ictt"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/coreth/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+type fundFlags struct {
+	chain        string
+	erc20        string
+	couponID     string
+	captchaToken string
+	faucetURL    string
+	timeout      time.Duration
+}
+
+// decimals of P-Chain AVAX amounts, given in nAVAX
+const pChainDecimals = 9
+
+var (
+	fundSupportedNetworkOptions = []networkoptions.NetworkOption{
+		networkoptions.Fuji,
+	}
+	fundCmdFlags fundFlags
+)
+
+// avalanche key fund
+func newFundCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fund [keyName]",
+		Short: "Request testnet funds for a stored key",
+		Long: `The key fund command requests testnet AVAX for the given stored key from the
+official faucet, waits for the funds to arrive, and shows the resulting balance.
+
+Funds are sent to the C-Chain address of the key, unless --chain p is given. Use
+--erc20 to request a C-Chain ERC20 token offered by the faucet instead of AVAX, and
+--coupon to redeem a faucet coupon code.
+
+The faucet is protected with a captcha and rate limits. Give the captcha answer obtained
+from the faucet web page with --captcha-token when it is required. To not hammer the
+faucet, a new request for the same address is refused until 24 hours after the last
+successful one.`,
+		RunE: fundKey,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, fundSupportedNetworkOptions)
+	cmd.Flags().StringVar(&fundCmdFlags.chain, "chain", "c", "chain to receive the funds on [c, p]")
+	cmd.Flags().StringVar(&fundCmdFlags.erc20, "erc20", "", "address of the C-Chain ERC20 token to request, instead of AVAX")
+	cmd.Flags().StringVar(&fundCmdFlags.couponID, "coupon", "", "faucet coupon code to redeem")
+	cmd.Flags().StringVar(&fundCmdFlags.captchaToken, "captcha-token", "", "captcha answer obtained from the faucet web page")
+	cmd.Flags().StringVar(&fundCmdFlags.faucetURL, "faucet-url", constants.FujiFaucetURL, "faucet to request the funds from")
+	cmd.Flags().DurationVar(&fundCmdFlags.timeout, "timeout", constants.FaucetFundsWaitTimeout, "how long to wait for the funds to arrive")
+	return cmd
+}
+
+func fundKey(_ *cobra.Command, args []string) error {
+	keyName := args[0]
+	var faucetChain faucet.Chain
+	switch strings.ToLower(fundCmdFlags.chain) {
+	case "c":
+		faucetChain = faucet.CChain
+	case "p":
+		faucetChain = faucet.PChain
+	default:
+		return fmt.Errorf("invalid chain %q: must be one of [c, p]", fundCmdFlags.chain)
+	}
+	if !app.KeyExists(keyName) {
+		return fmt.Errorf("key %q does not exist", keyName)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"On what Network do you want to fund the key?",
+		globalNetworkFlags,
+		false,
+		false,
+		fundSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	sk, err := app.GetKey(keyName, network, false)
+	if err != nil {
+		return err
+	}
+	addr := sk.C()
+	getBalance := func() (*big.Int, error) { return getFundCChainBalance(network, addr) }
+	decimals := uint8(constants.DefaultTokenDecimals)
+	symbol := "AVAX"
+	switch {
+	case faucetChain == faucet.PChain:
+		if fundCmdFlags.erc20 != "" {
+			return fmt.Errorf("--erc20 can only be used with C-Chain funds")
+		}
+		addr = sk.P()[0]
+		getBalance = func() (*big.Int, error) { return getFundPChainBalance(network, addr) }
+		decimals = pChainDecimals
+	case fundCmdFlags.erc20 != "":
+		if !common.IsHexAddress(fundCmdFlags.erc20) {
+			return fmt.Errorf("invalid ERC20 token address %q", fundCmdFlags.erc20)
+		}
+		tokenAddress := common.HexToAddress(fundCmdFlags.erc20)
+		symbol, _, decimals, err = ictt.GetTokenParams(network.CChainEndpoint(), tokenAddress)
+		if err != nil {
+			return fmt.Errorf("failure getting params of ERC20 token %s: %w", tokenAddress, err)
+		}
+		getBalance = func() (*big.Int, error) { return getFundERC20Balance(network, tokenAddress, addr) }
+	}
+	request := faucet.Request{
+		Chain:        faucetChain,
+		Address:      addr,
+		ERC20:        fundCmdFlags.erc20,
+		CouponID:     fundCmdFlags.couponID,
+		CaptchaToken: fundCmdFlags.captchaToken,
+	}
+	requestsLogPath := filepath.Join(app.GetBaseDir(), constants.FaucetRequestsFileName)
+	if err := faucet.CheckCooldown(requestsLogPath, fundCmdFlags.faucetURL, request, constants.FaucetRequestCooldown, time.Now()); err != nil {
+		return err
+	}
+	initialBalance, err := getBalance()
+	if err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("Requesting funds for %s on %s-Chain of %s", addr, faucetChain, network.Name())
+	ctx, cancel := context.WithTimeout(context.Background(), constants.FaucetRequestTimeout)
+	txHash, err := faucet.RequestFunds(ctx, fundCmdFlags.faucetURL, request)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if err := faucet.RecordRequest(requestsLogPath, fundCmdFlags.faucetURL, request, constants.FaucetRequestCooldown, time.Now()); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Faucet transaction: %s", txHash)
+
+	spinSession := ux.NewUserSpinner()
+	spinner := spinSession.SpinToUser("Waiting for funds to arrive")
+	balance, err := waitForBalanceIncrease(getBalance, initialBalance, fundCmdFlags.timeout)
+	if err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		spinSession.Stop()
+		return err
+	}
+	ux.SpinComplete(spinner)
+	spinSession.Stop()
+
+	received := new(big.Int).Sub(balance, initialBalance)
+	ux.Logger.GreenCheckmarkToUser("Received %s %s", utils.FormatAmount(received, decimals), symbol)
+	ux.Logger.PrintToUser("Balance of %s: %s %s", addr, utils.FormatAmount(balance, decimals), symbol)
+	return nil
+}
+
+// waitForBalanceIncrease polls [getBalance] until it goes over [initialBalance], returning
+// the new balance, or fails after [timeout]
+func waitForBalanceIncrease(
+	getBalance func() (*big.Int, error),
+	initialBalance *big.Int,
+	timeout time.Duration,
+) (*big.Int, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		balance, err := getBalance()
+		if err == nil && balance.Cmp(initialBalance) > 0 {
+			return balance, nil
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return nil, fmt.Errorf("funds not received after %s: %w", timeout, err)
+			}
+			return nil, fmt.Errorf("funds not received after %s", timeout)
+		}
+		time.Sleep(constants.FaucetPollInterval)
+	}
+}
+
+func getFundCChainBalance(network models.Network, addr string) (*big.Int, error) {
+	client, err := ethclient.Dial(network.CChainEndpoint())
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	return client.BalanceAt(ctx, common.HexToAddress(addr), nil)
+}
+
+func getFundERC20Balance(network models.Network, tokenAddress common.Address, addr string) (*big.Int, error) {
+	out, err := contract.CallToMethod(network.CChainEndpoint(), tokenAddress, "balanceOf(address)->(uint256)", common.HexToAddress(addr))
+	if err != nil {
+		return nil, err
+	}
+	balance, ok := out[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("error at balanceOf call, expected *big.Int, got %T", out[0])
+	}
+	return balance, nil
+}
+
+func getFundPChainBalance(network models.Network, addr string) (*big.Int, error) {
+	pID, err := address.ParseToID(addr)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	resp, err := platformvm.NewClient(network.Endpoint).GetBalance(ctx, []ids.ShortID{pID})
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(uint64(resp.Balance)), nil
+}
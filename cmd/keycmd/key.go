@@ -18,8 +18,8 @@ func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 		Short: "Create and manage testnet signing keys",
 		Long: `The key command suite provides a collection of tools for creating and managing
 signing keys. You can use these keys to deploy Subnets to the Fuji Testnet,
-but these keys are NOT suitable to use in production environments. DO NOT use
-these keys on Mainnet.
+but these keys are NOT suitable to use in production environments. Keys can not
+be used on Mainnet unless they are tagged as mainnet-approved with key tag.
 
 To get started, use the key create command.`,
 		RunE: cobrautils.CommandSuiteUsage,
@@ -40,5 +40,17 @@ To get started, use the key create command.`,
 	// avalanche key transfer
 	cmd.AddCommand(newTransferCmd())
 
+	// avalanche key tag
+	cmd.AddCommand(newTagCmd())
+
+	// avalanche key describe
+	cmd.AddCommand(newDescribeCmd())
+
+	// avalanche key fund
+	cmd.AddCommand(newFundCmd())
+
+	// avalanche key balances
+	cmd.AddCommand(newBalancesCmd())
+
 	return cmd
 }
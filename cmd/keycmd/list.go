@@ -3,15 +3,19 @@
 package keycmd
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"os"
 
 	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/rpcauth"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
@@ -21,8 +25,10 @@ import (
 	"github.com/ava-labs/avalanchego/vms/avm"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 	"github.com/ava-labs/coreth/ethclient"
+	corethrpc "github.com/ava-labs/coreth/rpc"
 	"github.com/ethereum/go-ethereum/common"
 	goethereumethclient "github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/liyue201/erc20-go/erc20"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -58,6 +64,8 @@ var (
 	subnetToken     string
 	subnets         []string
 	showNativeToken bool
+	copyAddress     bool
+	showQRCode      bool
 )
 
 // avalanche blockchain list
@@ -66,7 +74,10 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List stored signing keys or ledger addresses",
 		Long: `The key list command prints information for all stored signing
-keys or for the ledger addresses associated to certain indices.`,
+keys or for the ledger addresses associated to certain indices.
+
+When the list has a single address, --copy and --qr share it to receive funds, for example
+key list --keys mykey --blockchains c --qr.`,
 		RunE: listKeys,
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, listSupportedNetworkOptions)
@@ -120,7 +131,7 @@ keys or for the ledger addresses associated to certain indices.`,
 		&keys,
 		keysFlag,
 		[]string{},
-		"list addresses for the given keys",
+		"list addresses for the given keys. HD wallet accounts are given as wallet/<index> or wallet/evm/<index>",
 	)
 	cmd.Flags().StringSliceVar(
 		&subnets,
@@ -140,7 +151,19 @@ keys or for the ledger addresses associated to certain indices.`,
 		[]string{"Native"},
 		"provide balance information for the given token contract addresses (Evm only)",
 	)
-	return cmd
+	cmd.Flags().BoolVar(
+		&copyAddress,
+		"copy",
+		false,
+		"copy the listed address to the clipboard (requires the list to have a single address)",
+	)
+	cmd.Flags().BoolVar(
+		&showQRCode,
+		"qr",
+		false,
+		"render the listed address as a QR code (requires the list to have a single address)",
+	)
+	return cobrautils.MarkReadOnly(cmd)
 }
 
 type Clients struct {
@@ -219,7 +242,7 @@ func getClients(networks []models.Network, pchain bool, cchain bool, xchain bool
 						if !b {
 							evmClients[network] = map[string]ethclient.Client{}
 						}
-						evmClients[network][subnetName], err = ethclient.Dial(endpoint)
+						evmClients[network][subnetName], err = dialBlockchain(endpoint)
 						if err != nil {
 							return nil, err
 						}
@@ -228,7 +251,7 @@ func getClients(networks []models.Network, pchain bool, cchain bool, xchain bool
 							if !b {
 								evmGethClients[network] = map[string]*goethereumethclient.Client{}
 							}
-							evmGethClients[network][subnetName], err = goethereumethclient.Dial(endpoint)
+							evmGethClients[network][subnetName], err = dialGethBlockchain(endpoint)
 							if err != nil {
 								return nil, err
 							}
@@ -249,6 +272,25 @@ func getClients(networks []models.Network, pchain bool, cchain bool, xchain bool
 	}, nil
 }
 
+// dialBlockchain connects to the RPC of a blockchain at [endpoint], sending the RPC auth
+// token registered for it (if any)
+func dialBlockchain(endpoint string) (ethclient.Client, error) {
+	client, err := corethrpc.DialOptions(context.Background(), endpoint, corethrpc.WithHeaders(rpcauth.Tokens.Headers(endpoint)))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// dialGethBlockchain is dialBlockchain for the go-ethereum client used by the token bindings
+func dialGethBlockchain(endpoint string) (*goethereumethclient.Client, error) {
+	client, err := gethrpc.DialOptions(context.Background(), endpoint, gethrpc.WithHeaders(rpcauth.Tokens.Headers(endpoint)))
+	if err != nil {
+		return nil, err
+	}
+	return goethereumethclient.NewClient(client), nil
+}
+
 type addressInfo struct {
 	kind    string
 	name    string
@@ -263,7 +305,7 @@ func listKeys(*cobra.Command, []string) error {
 	var addrInfos []addressInfo
 	networks := []models.Network{}
 	if globalNetworkFlags.UseLocal || all {
-		networks = append(networks, models.NewLocalNetwork())
+		networks = append(networks, app.GetLocalNetwork())
 	}
 	if globalNetworkFlags.UseFuji || all {
 		networks = append(networks, models.NewFujiNetwork())
@@ -335,9 +377,30 @@ func listKeys(*cobra.Command, []string) error {
 		}
 	}
 	printAddrInfos(addrInfos)
+	if copyAddress || showQRCode {
+		return shareListedAddress(addrInfos)
+	}
 	return nil
 }
 
+// shareListedAddress copies to the clipboard and/or renders as QR code the address
+// listed on [addrInfos], that must contain a single one
+func shareListedAddress(addrInfos []addressInfo) error {
+	addresses := []string{}
+	for _, addrInfo := range addrInfos {
+		if !utils.Belongs(addresses, addrInfo.address) {
+			addresses = append(addresses, addrInfo.address)
+		}
+	}
+	if len(addresses) != 1 {
+		return fmt.Errorf(
+			"--copy and --qr need the list to contain a single address, but it has %d. Narrow it with --keys, --ledger or --blockchains (p, x or c)",
+			len(addresses),
+		)
+	}
+	return ux.ShareValue("Address "+addresses[0], addresses[0], copyAddress, showQRCode)
+}
+
 func getStoredKeysInfo(
 	clients *Clients,
 	networks []models.Network,
@@ -346,8 +409,25 @@ func getStoredKeysInfo(
 	if err != nil {
 		return nil, err
 	}
+	// HD wallets are listed by their first X/P-Chain and EVM accounts
+	walletNames, err := utils.GetHDWalletNames(app.GetKeyDir())
+	if err != nil {
+		return nil, err
+	}
+	for _, walletName := range walletNames {
+		keyNames = append(keyNames, key.DerivedKeyName(walletName, false, 0), key.DerivedKeyName(walletName, true, 0))
+	}
 	if len(keys) != 0 {
-		keyNames = utils.Filter(keyNames, func(keyName string) bool { return utils.Belongs(keys, keyName) })
+		keyNames = utils.Filter(keyNames, func(keyName string) bool {
+			walletName, _, _, ok := key.ParseDerivedKeyName(keyName)
+			return utils.Belongs(keys, keyName) || (ok && utils.Belongs(keys, walletName))
+		})
+		// any other account of a wallet is listed if asked for
+		for _, keyName := range keys {
+			if walletName, _, _, ok := key.ParseDerivedKeyName(keyName); ok && app.HDWalletExists(walletName) && !utils.Belongs(keyNames, keyName) {
+				keyNames = append(keyNames, keyName)
+			}
+		}
 	}
 	addrInfos := []addressInfo{}
 	for _, keyName := range keyNames {
@@ -377,6 +457,7 @@ func getStoredKeyInfo(
 				addrInfo, err := getEvmBasedChainAddrInfo(
 					subnetName,
 					subnetToken,
+					app.GetTokenDecimals(subnetName),
 					clients.evm[network][subnetName],
 					clients.evmGeth[network][subnetName],
 					network,
@@ -397,7 +478,7 @@ func getStoredKeyInfo(
 		}
 		if _, ok := clients.c[network]; ok {
 			cChainAddr := sk.C()
-			addrInfo, err := getEvmBasedChainAddrInfo("C-Chain", "AVAX", clients.c[network], clients.cGeth[network], network, cChainAddr, "stored", keyName)
+			addrInfo, err := getEvmBasedChainAddrInfo("C-Chain", "AVAX", constants.DefaultTokenDecimals, clients.c[network], clients.cGeth[network], network, cChainAddr, "stored", keyName)
 			if err != nil {
 				return nil, err
 			}
@@ -535,6 +616,7 @@ func getXChainAddrInfo(
 func getEvmBasedChainAddrInfo(
 	chainName string,
 	chainToken string,
+	chainTokenDecimals uint8,
 	cClient ethclient.Client,
 	cGethClient *goethereumethclient.Client,
 	network models.Network,
@@ -544,7 +626,7 @@ func getEvmBasedChainAddrInfo(
 ) ([]addressInfo, error) {
 	addressInfos := []addressInfo{}
 	if showNativeToken {
-		cChainBalance, err := getCChainBalanceStr(cClient, cChainAddr)
+		cChainBalance, err := getCChainBalanceStr(cClient, cChainAddr, chainTokenDecimals)
 		if err != nil {
 			// just ignore local network errors
 			if network.Kind != models.Local {
@@ -635,21 +717,34 @@ func printAddrInfos(addrInfos []addressInfo) {
 	table.Render()
 }
 
-func getCChainBalanceStr(cClient ethclient.Client, addrStr string) (string, error) {
-	addr := common.HexToAddress(addrStr)
+func getCChainBalanceStr(cClient ethclient.Client, addrStr string, decimals uint8) (string, error) {
 	ctx, cancel := utils.GetAPIContext()
-	balance, err := cClient.BalanceAt(ctx, addr, nil)
+	balance, err := getEVMBalance(ctx, cClient, addrStr)
 	cancel()
 	if err != nil {
 		return "", err
 	}
-	return formatCChainBalance(balance)
+	return formatCChainBalance(balance, decimals)
+}
+
+// evmBalanceClient is the part of the C-Chain and Subnet-EVM clients used to query balances
+type evmBalanceClient interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// getEVMBalance returns the native token balance of [addrStr] on the EVM chain of [client]
+func getEVMBalance(ctx context.Context, client evmBalanceClient, addrStr string) (*big.Int, error) {
+	return client.BalanceAt(ctx, common.HexToAddress(addrStr), nil)
 }
 
-func formatCChainBalance(balance *big.Int) (string, error) {
+func formatCChainBalance(balance *big.Int, decimals uint8) (string, error) {
 	if useGwei {
 		return fmt.Sprintf("%d", balance), nil
 	}
+	if decimals != constants.DefaultTokenDecimals {
+		// L1 native token with custom decimals, nAVAX units do not apply
+		return utils.FormatAmount(balance, decimals), nil
+	}
 	// convert to nAvax
 	balance = balance.Div(balance, big.NewInt(int64(units.Avax)))
 	if balance.Cmp(big.NewInt(0)) == 0 {
@@ -665,53 +760,61 @@ func formatCChainBalance(balance *big.Int) (string, error) {
 }
 
 func getPChainBalanceStr(pClient platformvm.Client, addr string) (string, error) {
-	pID, err := address.ParseToID(addr)
-	if err != nil {
-		return "", err
-	}
 	ctx, cancel := utils.GetAPIContext()
-	resp, err := pClient.GetBalance(ctx, []ids.ShortID{pID})
+	balance, err := getPChainBalance(ctx, pClient, addr)
 	cancel()
 	if err != nil {
 		return "", err
 	}
-	if resp.Balance == 0 {
-		return "0", nil
+	return formatAVAXBalance(balance), nil
+}
+
+// getPChainBalance returns the AVAX balance of [addr] on the P-Chain, in nAVAX
+func getPChainBalance(ctx context.Context, pClient platformvm.Client, addr string) (uint64, error) {
+	pID, err := address.ParseToID(addr)
+	if err != nil {
+		return 0, err
 	}
-	balanceStr := ""
-	if useNanoAvax {
-		balanceStr = fmt.Sprintf("%9d", resp.Balance)
-	} else {
-		balanceStr = fmt.Sprintf("%.9f", float64(resp.Balance)/float64(units.Avax))
+	resp, err := pClient.GetBalance(ctx, []ids.ShortID{pID})
+	if err != nil {
+		return 0, err
 	}
-	return balanceStr, nil
+	return uint64(resp.Balance), nil
 }
 
 func getXChainBalanceStr(xClient avm.Client, addr string) (string, error) {
-	xID, err := address.ParseToID(addr)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	balance, err := getXChainBalance(ctx, xClient, addr)
 	if err != nil {
 		return "", err
 	}
-	ctx, cancel := utils.GetAPIContext()
-	defer cancel()
+	return formatAVAXBalance(balance), nil
+}
+
+// getXChainBalance returns the AVAX balance of [addr] on the X-Chain, in nAVAX
+func getXChainBalance(ctx context.Context, xClient avm.Client, addr string) (uint64, error) {
+	xID, err := address.ParseToID(addr)
+	if err != nil {
+		return 0, err
+	}
 	asset, err := xClient.GetAssetDescription(ctx, "AVAX")
 	if err != nil {
-		return "", err
+		return 0, err
 	}
-	ctx, cancel = utils.GetAPIContext()
-	defer cancel()
 	resp, err := xClient.GetBalance(ctx, xID, asset.AssetID.String(), false)
 	if err != nil {
-		return "", err
+		return 0, err
 	}
-	if resp.Balance == 0 {
-		return "0", nil
+	return uint64(resp.Balance), nil
+}
+
+func formatAVAXBalance(balance uint64) string {
+	if balance == 0 {
+		return "0"
 	}
-	balanceStr := ""
 	if useNanoAvax {
-		balanceStr = fmt.Sprintf("%9d", resp.Balance)
-	} else {
-		balanceStr = fmt.Sprintf("%.9f", float64(resp.Balance)/float64(units.Avax))
+		return fmt.Sprintf("%9d", balance)
 	}
-	return balanceStr, nil
+	return fmt.Sprintf("%.9f", float64(balance)/float64(units.Avax))
 }
@@ -0,0 +1,85 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche key tag
+func newTagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag keyName [test-only|mainnet-approved]",
+		Short: "Tag a signing key as test-only or mainnet-approved",
+		Long: fmt.Sprintf(`The key tag command sets the networks a stored key can be used on.
+
+Keys are %s by default, and can not sign mainnet operations unless tagged
+as %s, or unless --%s is given to the command using them.
+P-Chain operations on mainnet always require a ledger.
+The ewoq key and other well known test keys can never be used on mainnet.
+
+If no tag is given, the command prints the current tag of the key.`,
+			key.TagTestOnly,
+			key.TagMainnetApproved,
+			constants.AllowUnapprovedKeyFlag,
+		),
+		RunE: tagKey,
+		Args: cobrautils.RangeArgs(1, 2),
+	}
+	return cmd
+}
+
+func tagKey(_ *cobra.Command, args []string) error {
+	keyName := args[0]
+	if !app.KeyExists(keyName) {
+		return errors.New("key does not exist")
+	}
+	tags, err := key.LoadTags(app.GetKeyTagsPath())
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		ux.Logger.PrintToUser("Key %s is %s", keyName, tags.Get(keyName))
+		return nil
+	}
+	tag, err := key.ParseTag(args[1])
+	if err != nil {
+		return err
+	}
+	if tag == key.TagMainnetApproved {
+		k, err := app.GetKey(keyName, app.GetLocalNetwork(), false)
+		if err != nil {
+			return err
+		}
+		if key.IsWellKnownTestKey(k.PrivKeyHex()) {
+			return fmt.Errorf("key %s is a well known test key and can not be approved for mainnet", keyName)
+		}
+	}
+	tags[keyName] = tag
+	if err := tags.Save(app.GetKeyTagsPath()); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Key %s tagged as %s", keyName, tag)
+	return nil
+}
+
+// clearKeyTag drops the tag of [keyName], so that a new key stored with the same name
+// does not inherit it
+func clearKeyTag(keyName string) error {
+	tags, err := key.LoadTags(app.GetKeyTagsPath())
+	if err != nil {
+		return err
+	}
+	if _, ok := tags[keyName]; !ok {
+		return nil
+	}
+	delete(tags, keyName)
+	return tags.Save(app.GetKeyTagsPath())
+}
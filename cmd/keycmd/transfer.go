@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
 	clievm "github.com/ava-labs/avalanche-cli/pkg/evm"
 	"github.com/ava-labs/avalanche-cli/pkg/ictt"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
+	clikeychain "github.com/ava-labs/avalanche-cli/pkg/keychain"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
@@ -65,6 +67,7 @@ var (
 	//
 	senderChainFlags   contract.ChainSpec
 	receiverChainFlags contract.ChainSpec
+	allowUnapprovedKey bool
 )
 
 func newTransferCmd() *cobra.Command {
@@ -150,6 +153,7 @@ func newTransferCmd() *cobra.Command {
 		"receiver-blockchain-id",
 	)
 	receiverChainFlags.AddToCmd(cmd, "receive at %s")
+	clikeychain.AddAllowUnapprovedKeyFlag(cmd, &allowUnapprovedKey)
 	return cmd
 }
 
@@ -250,6 +254,11 @@ func transferF(*cobra.Command, []string) error {
 	var kc keychain.Keychain
 	var sk *key.SoftKey
 	if keyName != "" {
+		if network.Kind == models.Mainnet {
+			if err := clikeychain.CheckStoredKeyOnMainnet(app, keyName, allowUnapprovedKey); err != nil {
+				return err
+			}
+		}
 		sk, err = app.GetKey(keyName, network, false)
 		if err != nil {
 			return err
@@ -369,6 +378,9 @@ func intraEvmSend(
 	if err != nil {
 		return err
 	}
+	if err := clikeychain.CheckPrivateKeyOnNetwork(app, network, privateKey, allowUnapprovedKey); err != nil {
+		return err
+	}
 	destinationAddr, err := prompts.PromptAddress(
 		app.Prompt,
 		"destination address",
@@ -394,8 +406,12 @@ func intraEvmSend(
 	if err != nil {
 		return err
 	}
+	decimals := uint8(constants.DefaultTokenDecimals)
+	if senderChain.BlockchainName != "" {
+		decimals = app.GetTokenDecimals(senderChain.BlockchainName)
+	}
 	amountBigFlt := new(big.Float).SetFloat64(amountFlt)
-	amountBigFlt = amountBigFlt.Mul(amountBigFlt, new(big.Float).SetInt(vm.OneAvax))
+	amountBigFlt = amountBigFlt.Mul(amountBigFlt, new(big.Float).SetInt(vm.TokenUnit(decimals)))
 	amount, _ := amountBigFlt.Int(nil)
 	senderURL, _, err := contract.GetBlockchainEndpoints(
 		app,
@@ -469,6 +485,11 @@ func interEvmSend(
 			return err
 		}
 	}
+	if network.Kind == models.Mainnet {
+		if err := clikeychain.CheckStoredKeyOnMainnet(app, keyName, allowUnapprovedKey); err != nil {
+			return err
+		}
+	}
 	originK, err := app.GetKey(keyName, network, false)
 	if err != nil {
 		return err
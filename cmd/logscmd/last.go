@@ -0,0 +1,78 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package logscmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/logs"
+	"github.com/spf13/cobra"
+)
+
+var (
+	raw      bool
+	pathOnly bool
+)
+
+// avalanche logs last
+func newLastCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "last",
+		Short: "Show the log of the previous CLI invocation",
+		Long: `The logs last command shows the structured debug log of the previous CLI invocation,
+one entry per line, with its additional fields. Use --raw to get the json entries as written,
+or --path to get the path of the log file to attach it to a bug report.`,
+		RunE: showLastLog,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().BoolVar(&raw, "raw", false, "raw json log output")
+	cmd.Flags().BoolVar(&pathOnly, "path", false, "only print the path of the log file")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func showLastLog(_ *cobra.Command, _ []string) error {
+	logFiles, err := logs.GetCommandLogFiles(app.GetCommandLogsDir())
+	if err != nil {
+		return err
+	}
+	logPath := ""
+	for _, logFile := range logFiles {
+		// skip the log of this same invocation
+		if logFile != app.CommandLogPath {
+			logPath = logFile
+			break
+		}
+	}
+	if logPath == "" {
+		return errors.New("no logs of previous invocations were found")
+	}
+	if pathOnly {
+		fmt.Println(logPath)
+		return nil
+	}
+	if raw {
+		bs, err := os.ReadFile(logPath)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(bs))
+		return nil
+	}
+	f, err := os.Open(logPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	entries, err := logs.ReadEntries(f)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Log file: %s\n\n", logPath)
+	for _, entry := range entries {
+		fmt.Println(entry.String())
+	}
+	return nil
+}
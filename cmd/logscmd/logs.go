@@ -0,0 +1,28 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package logscmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche logs
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show the logs of previous CLI invocations",
+		Long: `The logs command suite shows the structured debug logs the CLI writes for each of its
+invocations, regardless of the console verbosity. They are kept as json files under the logs
+dir of the CLI base dir, for the latest invocations only, and are useful to attach to bug reports.`,
+		RunE: cobrautils.CommandSuiteUsage,
+		Args: cobrautils.ExactArgs(0),
+	}
+	// logs last
+	cmd.AddCommand(newLastCmd())
+	return cmd
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/spf13/cobra"
+)
+
+var (
+	devnetName       string
+	devnetEndpoint   string
+	devnetNetworkID  uint32
+	devnetFundedKeys []string
+	devnetFile       string
+	devnetForce      bool
+)
+
+var devnetNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// avalanche network add-devnet
+func newAddDevnetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-devnet",
+		Short: "Register a remote devnet by name",
+		Long: `The network add-devnet command registers a remote devnet under the given name, storing its
+endpoint, network ID and funded test keys information. Commands that operate on devnets accept
+the name with --network, instead of the devnet endpoint.
+
+The devnet can be given either by flags, or by a devnet descriptor file exported by a teammate
+with network export-devnet.`,
+		RunE: addDevnet,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&devnetName, "name", "", "name to register the devnet under")
+	cmd.Flags().StringVar(&devnetEndpoint, "endpoint", "", "API endpoint of the devnet")
+	cmd.Flags().Uint32Var(&devnetNetworkID, "network-id", 0, "network ID of the devnet (obtained from the endpoint if not given)")
+	cmd.Flags().StringSliceVar(&devnetFundedKeys, "funded-key", nil, "funded test key info, as name=address (P-Chain or EVM address). can be repeated")
+	cmd.Flags().StringVar(&devnetFile, "file", "", "register the devnet from the given devnet descriptor file")
+	cmd.Flags().BoolVar(&devnetForce, "force", false, "overwrite a devnet already registered with the same name")
+	return cmd
+}
+
+func addDevnet(_ *cobra.Command, _ []string) error {
+	var devnetConfig models.DevnetConfig
+	if devnetFile != "" {
+		if devnetEndpoint != "" || devnetNetworkID != 0 || len(devnetFundedKeys) != 0 {
+			return fmt.Errorf("--file can't be used together with --endpoint, --network-id or --funded-key")
+		}
+		descriptorBytes, err := os.ReadFile(devnetFile)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(descriptorBytes, &devnetConfig); err != nil {
+			return fmt.Errorf("invalid devnet descriptor %s: %w", devnetFile, err)
+		}
+		if devnetName != "" {
+			devnetConfig.Name = devnetName
+		}
+	} else {
+		keys, err := parseDevnetFundedKeys(devnetFundedKeys)
+		if err != nil {
+			return err
+		}
+		devnetConfig = models.DevnetConfig{
+			Name:      devnetName,
+			Endpoint:  devnetEndpoint,
+			NetworkID: devnetNetworkID,
+			Keys:      keys,
+		}
+	}
+	var err error
+	if devnetConfig.Name == "" {
+		devnetConfig.Name, err = app.Prompt.CaptureString("Devnet name")
+		if err != nil {
+			return err
+		}
+	}
+	if !devnetNameRegexp.MatchString(devnetConfig.Name) {
+		return fmt.Errorf("invalid devnet name %q: only letters, digits, '-' and '_' are allowed", devnetConfig.Name)
+	}
+	if devnetConfig.Endpoint == "" {
+		devnetConfig.Endpoint, err = app.Prompt.CaptureURL("Devnet Endpoint", false)
+		if err != nil {
+			return err
+		}
+	}
+	devnetConfig.Endpoint = strings.TrimRight(devnetConfig.Endpoint, "/")
+	if devnetConfig.NetworkID == 0 {
+		infoClient := info.NewClient(devnetConfig.Endpoint)
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		devnetConfig.NetworkID, err = infoClient.GetNetworkID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get network ID from %s: %w", devnetConfig.Endpoint, err)
+		}
+	}
+	devnetsConfig, err := app.LoadDevnetsConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := devnetsConfig.Devnets[devnetConfig.Name]; ok && !devnetForce {
+		return fmt.Errorf("devnet %q is already registered. use --force to overwrite it", devnetConfig.Name)
+	}
+	devnetsConfig.Devnets[devnetConfig.Name] = devnetConfig
+	if err := app.WriteDevnetsConfigFile(&devnetsConfig); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Devnet %s registered (endpoint %s, network ID %d)", devnetConfig.Name, devnetConfig.Endpoint, devnetConfig.NetworkID)
+	ux.Logger.PrintToUser("Use --network %s to operate on it", devnetConfig.Name)
+	return nil
+}
+
+// parseDevnetFundedKeys parses funded key infos given as name=address. P-Chain
+// and EVM addresses given for the same name are merged into the same key
+func parseDevnetFundedKeys(fundedKeys []string) ([]models.DevnetKey, error) {
+	keys := []models.DevnetKey{}
+	keyIndex := map[string]int{}
+	for _, fundedKey := range fundedKeys {
+		name, addr, found := strings.Cut(fundedKey, "=")
+		name = strings.TrimSpace(name)
+		addr = strings.TrimSpace(addr)
+		if !found || name == "" || addr == "" {
+			return nil, fmt.Errorf("invalid funded key %q: expected name=address", fundedKey)
+		}
+		index, ok := keyIndex[name]
+		if !ok {
+			index = len(keys)
+			keyIndex[name] = index
+			keys = append(keys, models.DevnetKey{Name: name})
+		}
+		switch {
+		case strings.HasPrefix(addr, "0x"):
+			keys[index].CChainAddr = addr
+		case strings.HasPrefix(addr, "P-"):
+			keys[index].PChainAddr = addr
+		default:
+			return nil, fmt.Errorf("invalid funded key address %q: expected a P-Chain (P-...) or EVM (0x...) address", addr)
+		}
+	}
+	return keys, nil
+}
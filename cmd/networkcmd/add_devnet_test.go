@@ -0,0 +1,37 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDevnetFundedKeys(t *testing.T) {
+	require := require.New(t)
+
+	keys, err := parseDevnetFundedKeys([]string{
+		"faucet=P-custom1abc",
+		"faucet=0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC",
+		"alice = 0x0000000000000000000000000000000000000001",
+	})
+	require.NoError(err)
+	require.Equal([]models.DevnetKey{
+		{
+			Name:       "faucet",
+			PChainAddr: "P-custom1abc",
+			CChainAddr: "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC",
+		},
+		{
+			Name:       "alice",
+			CChainAddr: "0x0000000000000000000000000000000000000001",
+		},
+	}, keys)
+
+	_, err = parseDevnetFundedKeys([]string{"faucet"})
+	require.Error(err)
+	_, err = parseDevnetFundedKeys([]string{"faucet=X-custom1abc"})
+	require.Error(err)
+}
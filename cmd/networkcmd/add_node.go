@@ -0,0 +1,333 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/admin"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/spf13/cobra"
+)
+
+type AddNodeFlags struct {
+	name               string
+	validator          bool
+	stake              uint64
+	validationDuration time.Duration
+	keyName            string
+	useEwoq            bool
+	useLedger          bool
+	ledgerAddresses    []string
+}
+
+var addNodeFlags AddNodeFlags
+
+// localBlockchain is a blockchain deployed on the local network
+type localBlockchain struct {
+	name         string
+	subnetID     ids.ID
+	blockchainID ids.ID
+	sovereign    bool
+}
+
+// avalanche network add-node
+func newAddNodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-node",
+		Short: "Adds a node to the running local network",
+		Long: `The network add-node command adds a new node to the running local network, without
+restarting it.
+
+The node tracks all the blockchains deployed on the local network, and their RPC endpoints on
+the node are added to the blockchains configuration. Unless --validator=false is given, the node
+is also added as a Primary Network validator, staked with the given key or ledger, and as a
+validator of the non sovereign subnets. Sovereign L1 validators must be added with blockchain addValidator.`,
+		RunE: addNode,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&addNodeFlags.name, "name", "", "name for the new node (default is the next free nodeN)")
+	cmd.Flags().BoolVar(&addNodeFlags.validator, "validator", true, "add the node as a Primary Network validator and as a validator of the non sovereign subnets")
+	cmd.Flags().Uint64Var(&addNodeFlags.stake, "stake-amount", 0, "nAVAX to stake on the Primary Network validator (default is the minimum stake)")
+	cmd.Flags().DurationVar(&addNodeFlags.validationDuration, "staking-period", 0, "Primary Network validation duration (default is the minimum staking duration)")
+	cmd.Flags().StringVarP(&addNodeFlags.keyName, "key", "k", "", "select the key to stake with")
+	cmd.Flags().BoolVarP(&addNodeFlags.useEwoq, "ewoq", "e", false, "stake with the ewoq key")
+	cmd.Flags().BoolVarP(&addNodeFlags.useLedger, "ledger", "g", false, "stake with a ledger")
+	cmd.Flags().StringSliceVar(&addNodeFlags.ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	return cmd
+}
+
+func addNode(*cobra.Command, []string) error {
+	return AddNode(addNodeFlags)
+}
+
+func AddNode(flags AddNodeFlags) error {
+	if app.UseLocalDockerNetwork() {
+		return fmt.Errorf("add-node is not supported on the docker local network backend")
+	}
+	cli, err := binutils.NewGRPCClientWithEndpoint(
+		binutils.LocalNetworkGRPCServerEndpoint,
+		binutils.WithAvoidRPCVersionCheck(true),
+		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
+	)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("local network is not running: %w", err)
+	}
+	clusterInfo := status.ClusterInfo
+	nodeName := flags.name
+	if nodeName == "" {
+		nodeName = getNextLocalNodeName(clusterInfo)
+	}
+	if _, ok := clusterInfo.NodeInfos[nodeName]; ok {
+		return fmt.Errorf("node %s already exists on the local network", nodeName)
+	}
+	referenceNode, ok := clusterInfo.NodeInfos["node1"]
+	if !ok {
+		return fmt.Errorf("node1 not found on local network")
+	}
+	network := app.GetLocalNetwork()
+	blockchains, err := getLocalBlockchains(network)
+	if err != nil {
+		return err
+	}
+	anrOpts, err := getAddNodeOpts(blockchains, referenceNode.PluginDir)
+	if err != nil {
+		return err
+	}
+	var kc *keychain.Keychain
+	if flags.validator {
+		if flags.stake == 0 {
+			flags.stake = network.GenesisParams().MinValidatorStake
+		}
+		kc, err = keychain.GetKeychainFromCmdLineFlags(
+			app,
+			"stake for the new node Primary Network validation",
+			network,
+			flags.keyName,
+			flags.useEwoq,
+			flags.useLedger,
+			flags.ledgerAddresses,
+			flags.stake,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	spinSession := ux.NewUserSpinner()
+	spinner := spinSession.SpinToUser("Adding node %s to the local network", nodeName)
+	if _, err := cli.AddNode(ctx, nodeName, referenceNode.ExecPath, anrOpts...); err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		spinSession.Stop()
+		return fmt.Errorf("failure adding node %s: %w", nodeName, err)
+	}
+	clusterInfo, err = subnet.WaitForHealthy(ctx, cli)
+	if err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		spinSession.Stop()
+		return fmt.Errorf("failure waiting for node %s to be healthy: %w", nodeName, err)
+	}
+	ux.SpinComplete(spinner)
+	spinSession.Stop()
+	nodeInfo := clusterInfo.NodeInfos[nodeName]
+
+	for _, blockchain := range blockchains {
+		if err := IsBootstrapped(cli, blockchain.blockchainID.String()); err != nil {
+			return err
+		}
+		adminClient := admin.NewClient(nodeInfo.Uri)
+		if err := adminClient.AliasChain(ctx, blockchain.blockchainID.String(), blockchain.name); err != nil {
+			return err
+		}
+		if err := updateLocalNodeEndpoints(blockchain.name, network, nodeInfo.Uri, true); err != nil {
+			return err
+		}
+	}
+
+	if flags.validator {
+		if err := addLocalPrimaryValidator(network, nodeInfo, kc, flags.stake, flags.validationDuration); err != nil {
+			return err
+		}
+		for _, blockchain := range blockchains {
+			if blockchain.sovereign {
+				ux.Logger.PrintToUser("Use blockchain addValidator %s to make %s a validator of L1 %s", blockchain.name, nodeName, blockchain.name)
+				continue
+			}
+			if err := AddNoSovereignValidators(cli, blockchain.subnetID); err != nil {
+				return err
+			}
+			if err := WaitNoSovereignValidators(cli, blockchain.subnetID); err != nil {
+				return err
+			}
+		}
+	}
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Node name: %s", nodeName)
+	ux.Logger.PrintToUser("URI: %s", nodeInfo.Uri)
+	ux.Logger.PrintToUser("Node-ID: %s", nodeInfo.Id)
+	ux.Logger.PrintToUser("Logs directory: %s", nodeInfo.LogDir)
+	ux.Logger.GreenCheckmarkToUser("Node %s added to the local network", nodeName)
+	return nil
+}
+
+// getNextLocalNodeName returns the first nodeN name not used on the local network
+func getNextLocalNodeName(clusterInfo *rpcpb.ClusterInfo) string {
+	for i := len(clusterInfo.NodeNames) + 1; ; i++ {
+		name := fmt.Sprintf("node%d", i)
+		if _, ok := clusterInfo.NodeInfos[name]; !ok {
+			return name
+		}
+	}
+}
+
+// getLocalBlockchains returns the blockchains deployed on the local network
+func getLocalBlockchains(network models.Network) ([]localBlockchain, error) {
+	blockchainNames, err := app.GetBlockchainNamesOnNetwork(network, false)
+	if err != nil {
+		return nil, err
+	}
+	blockchains := []localBlockchain{}
+	for _, blockchainName := range blockchainNames {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			return nil, err
+		}
+		networkInfo := sc.Networks[network.Name()]
+		if networkInfo.BlockchainID == ids.Empty {
+			continue
+		}
+		blockchains = append(blockchains, localBlockchain{
+			name:         blockchainName,
+			subnetID:     networkInfo.SubnetID,
+			blockchainID: networkInfo.BlockchainID,
+			sovereign:    sc.Sovereign,
+		})
+	}
+	return blockchains, nil
+}
+
+// getAddNodeOpts returns the options for a new node to track [blockchains], with
+// their chain and subnet configs
+func getAddNodeOpts(blockchains []localBlockchain, pluginDir string) ([]client.OpOption, error) {
+	subnetIDs := set.Set[ids.ID]{}
+	chainConfigs := map[string]string{}
+	subnetConfigs := map[string]string{}
+	for _, blockchain := range blockchains {
+		subnetIDs.Add(blockchain.subnetID)
+		if app.ChainConfigExists(blockchain.name) {
+			chainConfig, err := os.ReadFile(app.GetChainConfigPath(blockchain.name))
+			if err != nil {
+				return nil, err
+			}
+			chainConfigs[blockchain.blockchainID.String()] = string(chainConfig)
+		}
+		if app.AvagoSubnetConfigExists(blockchain.name) {
+			subnetConfig, err := os.ReadFile(app.GetAvagoSubnetConfigPath(blockchain.name))
+			if err != nil {
+				return nil, err
+			}
+			subnetConfigs[blockchain.subnetID.String()] = string(subnetConfig)
+		}
+	}
+	nodeConfig := map[string]interface{}{}
+	if subnetIDs.Len() > 0 {
+		nodeConfig[config.TrackSubnetsKey] = strings.Join(utils.Map(subnetIDs.List(), ids.ID.String), ",")
+	}
+	nodeConfigBytes, err := json.Marshal(nodeConfig)
+	if err != nil {
+		return nil, err
+	}
+	return []client.OpOption{
+		client.WithGlobalNodeConfig(string(nodeConfigBytes)),
+		client.WithPluginDir(pluginDir),
+		client.WithChainConfigs(chainConfigs),
+		client.WithSubnetConfigs(subnetConfigs),
+	}, nil
+}
+
+// addLocalPrimaryValidator stakes [stake] nAVAX from [kc] to make [nodeInfo]
+// a Primary Network validator for [duration]
+func addLocalPrimaryValidator(
+	network models.Network,
+	nodeInfo *rpcpb.NodeInfo,
+	kc *keychain.Keychain,
+	stake uint64,
+	duration time.Duration,
+) error {
+	if duration == 0 {
+		duration = network.GenesisParams().MinStakeDuration
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	nodeID, proofOfPossession, err := info.NewClient(nodeInfo.Uri).GetNodeID(ctx)
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewPublicDeployer(app, kc, network)
+	// leave time for the tx to be issued before the validation starts
+	start := time.Now().Add(constants.PrimaryNetworkValidatingStartLeadTimeNodeCmd)
+	ux.Logger.PrintToUser("Adding %s as a Primary Network validator", nodeID)
+	if _, _, _, err := deployer.AddPrimaryNetworkValidator(
+		nodeID,
+		stake,
+		uint64(start.Unix()),
+		uint64(start.Add(duration).Unix()),
+		kc.Addresses().List()[0],
+		network.GenesisParams().MinDelegationFee,
+		proofOfPossession,
+		nil,
+	); err != nil {
+		return fmt.Errorf("failure adding %s as a Primary Network validator: %w", nodeID, err)
+	}
+	ux.Logger.PrintToUser("Waiting for the node %s to start as a Primary Network Validator...", nodeID)
+	return subnet.WaitForSubnetValidator(ids.Empty, nodeID, network, 5*time.Minute)
+}
+
+// updateLocalNodeEndpoints adds, or removes, the RPC and WS endpoints of [blockchainName]
+// on the node at [uri] to its sidecar
+func updateLocalNodeEndpoints(blockchainName string, network models.Network, uri string, add bool) error {
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return err
+	}
+	networkInfo := sc.Networks[network.Name()]
+	rpcEndpoint := models.GetRPCEndpoint(uri, networkInfo.BlockchainID.String())
+	wsEndpoint := models.GetWSEndpoint(uri, networkInfo.BlockchainID.String())
+	rpcEndpoints := set.Of(networkInfo.RPCEndpoints...)
+	wsEndpoints := set.Of(networkInfo.WSEndpoints...)
+	if add {
+		rpcEndpoints.Add(rpcEndpoint)
+		wsEndpoints.Add(wsEndpoint)
+	} else {
+		rpcEndpoints.Remove(rpcEndpoint)
+		wsEndpoints.Remove(wsEndpoint)
+	}
+	networkInfo.RPCEndpoints = rpcEndpoints.List()
+	networkInfo.WSEndpoints = wsEndpoints.List()
+	sc.Networks[network.Name()] = networkInfo
+	return app.UpdateSidecar(&sc)
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/testutils"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNextLocalNodeName(t *testing.T) {
+	require := require.New(t)
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeNames: []string{"node1", "node2"},
+		NodeInfos: map[string]*rpcpb.NodeInfo{"node1": {}, "node2": {}},
+	}
+	require.Equal("node3", getNextLocalNodeName(clusterInfo))
+	// a removed node leaves a gap that is not reused, to keep logs and data dirs apart
+	clusterInfo = &rpcpb.ClusterInfo{
+		NodeNames: []string{"node1", "node3"},
+		NodeInfos: map[string]*rpcpb.NodeInfo{"node1": {}, "node3": {}},
+	}
+	require.Equal("node4", getNextLocalNodeName(clusterInfo))
+}
+
+func TestLocalBlockchainsAndEndpoints(t *testing.T) {
+	require := require.New(t)
+	app = testutils.SetupTestInTempDir(t)
+	network := models.NewLocalNetwork()
+	blockchainID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID()
+	require.NoError(app.CreateSidecar(&models.Sidecar{
+		Name:      "deployed",
+		Sovereign: true,
+		Networks: map[string]models.NetworkData{
+			network.Name(): {SubnetID: subnetID, BlockchainID: blockchainID},
+		},
+	}))
+	require.NoError(app.CreateSidecar(&models.Sidecar{
+		Name:     "notDeployed",
+		Networks: map[string]models.NetworkData{},
+	}))
+
+	blockchains, err := getLocalBlockchains(network)
+	require.NoError(err)
+	require.Equal([]localBlockchain{{
+		name:         "deployed",
+		subnetID:     subnetID,
+		blockchainID: blockchainID,
+		sovereign:    true,
+	}}, blockchains)
+
+	uri := "http://127.0.0.1:9660"
+	require.NoError(updateLocalNodeEndpoints("deployed", network, uri, true))
+	sc, err := app.LoadSidecar("deployed")
+	require.NoError(err)
+	require.Equal([]string{models.GetRPCEndpoint(uri, blockchainID.String())}, sc.Networks[network.Name()].RPCEndpoints)
+	require.Equal([]string{models.GetWSEndpoint(uri, blockchainID.String())}, sc.Networks[network.Name()].WSEndpoints)
+
+	require.NoError(updateLocalNodeEndpoints("deployed", network, uri, false))
+	sc, err = app.LoadSidecar("deployed")
+	require.NoError(err)
+	require.Empty(sc.Networks[network.Name()].RPCEndpoints)
+	require.Empty(sc.Networks[network.Name()].WSEndpoints)
+}
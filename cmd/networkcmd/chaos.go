@@ -0,0 +1,149 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/docker"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/spf13/cobra"
+)
+
+// how long a fault lasts before being undone. zero means until healed
+var chaosDuration time.Duration
+
+// avalanche network chaos
+func newChaosCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chaos",
+		Short: "Inject faults into the local network",
+		Long: `The network chaos command suite injects faults into the nodes of the running local
+network, so that applications can be tested against them from scripts.
+
+Nodes can be paused and resumed on both local network backends. Partitions and latency
+need the docker backend, where each node has its own network namespace, see
+'avalanche config localNetworkBackend docker'. They are applied from a network tools
+container (` + constants.NetworkToolsDockerImage + `) attached to the node containers.
+On the process backend all nodes share the host network, so their peer traffic can't be
+told apart without root access.
+
+Blockchains are deployed to the docker backend as to the process one, with blockchain
+deploy --local. Their VMs are installed on the node containers, and L1s use the node
+containers as bootstrap validators, so the faults reach every blockchain of the network.
+
+Faults last until removed with network chaos heal, or, if --duration is given, the command
+waits for it and then removes the fault it injected.
+
+Snowman chains are final, so faults do not cause reorgs: blockchains keep going while
+a stake majority can communicate, and halt otherwise. Avalanchego provides no hook to make
+a block proposer misbehave; missed proposer windows can be exercised by pausing or
+isolating validators, so that other validators propose in their place.`,
+		RunE: cobrautils.CommandSuiteUsage,
+		Args: cobrautils.ExactArgs(0),
+	}
+	// network chaos pause
+	cmd.AddCommand(newChaosPauseCmd())
+	// network chaos resume
+	cmd.AddCommand(newChaosResumeCmd())
+	// network chaos partition
+	cmd.AddCommand(newChaosPartitionCmd())
+	// network chaos latency
+	cmd.AddCommand(newChaosLatencyCmd())
+	// network chaos heal
+	cmd.AddCommand(newChaosHealCmd())
+	return cmd
+}
+
+func addChaosDurationFlag(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&chaosDuration, "duration", 0, "remove the fault after this time, waiting for it (default is to keep it until healed)")
+}
+
+// undoChaosAfterDuration waits for --duration, if given, and then calls [undo]
+func undoChaosAfterDuration(undo func() error) error {
+	if chaosDuration == 0 {
+		return nil
+	}
+	ux.Logger.PrintToUser("Waiting %s before removing the fault", chaosDuration)
+	time.Sleep(chaosDuration)
+	if err := undo(); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Fault removed")
+	return nil
+}
+
+// checkDockerChaos fails if the local network is not run with the docker backend, needed
+// by network faults
+func checkDockerChaos(cmdName string) error {
+	if !app.UseLocalDockerNetwork() {
+		return fmt.Errorf(
+			"network chaos %s needs the %s local network backend, as nodes of the %s backend share the host network.\n"+
+				"Switch to it with 'avalanche network clean', 'avalanche config localNetworkBackend %s', and "+
+				"'avalanche blockchain deploy --local' for each blockchain, that starts the network on it",
+			cmdName,
+			constants.LocalNetworkDockerBackend,
+			constants.LocalNetworkProcessBackend,
+			constants.LocalNetworkDockerBackend,
+		)
+	}
+	if !docker.LocalNetworkExists(app.GetLocalDockerNetworkDir()) {
+		return fmt.Errorf("local network is not running")
+	}
+	return nil
+}
+
+// checkLocalNetworkNodes fails if any of [nodeNames] is not a node of the local network
+func checkLocalNetworkNodes(nodeNames []string) error {
+	var available []string
+	if app.UseLocalDockerNetwork() {
+		nodes, err := docker.GetLocalNetworkNodes(app.GetLocalDockerNetworkDir())
+		if err != nil {
+			return err
+		}
+		for _, node := range nodes {
+			available = append(available, node.Name)
+		}
+	} else {
+		cli, clusterInfo, err := getLocalNetworkCluster()
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+		available = clusterInfo.NodeNames
+	}
+	for _, nodeName := range nodeNames {
+		if !utils.Belongs(available, nodeName) {
+			return fmt.Errorf("node %s not found on the local network. Available nodes: %v", nodeName, available)
+		}
+	}
+	return nil
+}
+
+// getLocalNetworkCluster returns a client of the network runner of the local network, and
+// its cluster info. Fails if the network is not running
+func getLocalNetworkCluster() (client.Client, *rpcpb.ClusterInfo, error) {
+	cli, err := binutils.NewGRPCClientWithEndpoint(
+		binutils.LocalNetworkGRPCServerEndpoint,
+		binutils.WithAvoidRPCVersionCheck(true),
+		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		cli.Close()
+		return nil, nil, fmt.Errorf("local network is not running: %w", err)
+	}
+	return cli, status.ClusterInfo, nil
+}
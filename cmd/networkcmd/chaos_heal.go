@@ -0,0 +1,53 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/docker"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network chaos heal
+func newChaosHealCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "heal",
+		Short: "Removes all the faults injected into the local network",
+		Long: `The network chaos heal command resumes the paused nodes of the local network, and, on the
+docker backend, also removes its partitions and latencies.`,
+		RunE: chaosHeal,
+		Args: cobrautils.ExactArgs(0),
+	}
+}
+
+func chaosHeal(*cobra.Command, []string) error {
+	if app.UseLocalDockerNetwork() {
+		if err := checkDockerChaos("heal"); err != nil {
+			return err
+		}
+		if err := docker.HealLocalNetwork(app.GetLocalDockerNetworkDir()); err != nil {
+			return err
+		}
+		ux.Logger.GreenCheckmarkToUser("Local network healed")
+		return nil
+	}
+	cli, clusterInfo, err := getLocalNetworkCluster()
+	if err != nil {
+		return err
+	}
+	cli.Close()
+	pausedNodes := []string{}
+	for _, nodeName := range clusterInfo.NodeNames {
+		if clusterInfo.NodeInfos[nodeName].Paused {
+			pausedNodes = append(pausedNodes, nodeName)
+		}
+	}
+	if len(pausedNodes) > 0 {
+		if err := ResumeNodes(pausedNodes); err != nil {
+			return err
+		}
+	}
+	ux.Logger.GreenCheckmarkToUser("Local network healed")
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/docker"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	latencyDelay  time.Duration
+	latencyJitter time.Duration
+)
+
+// avalanche network chaos latency
+func newChaosLatencyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "latency [nodeName...]",
+		Short: "Adds latency to the peer traffic of nodes of the local network",
+		Long: `The network chaos latency command delays every message the given nodes send to their
+peers by --delay, varying by up to --jitter. API traffic is not delayed. A zero --delay
+removes the latency of the nodes.
+
+Latencies are removed with network chaos heal. Needs the docker local network backend.`,
+		RunE: chaosLatency,
+		Args: cobrautils.MinimumNArgs(1),
+	}
+	cmd.Flags().DurationVar(&latencyDelay, "delay", 200*time.Millisecond, "delay to add to the messages sent by the nodes")
+	cmd.Flags().DurationVar(&latencyJitter, "jitter", 0, "random variation of the delay")
+	addChaosDurationFlag(cmd)
+	return cmd
+}
+
+func chaosLatency(_ *cobra.Command, nodeNames []string) error {
+	if err := checkDockerChaos("latency"); err != nil {
+		return err
+	}
+	if latencyDelay < 0 || latencyJitter < 0 {
+		return fmt.Errorf("--delay and --jitter can't be negative")
+	}
+	if err := checkLocalNetworkNodes(nodeNames); err != nil {
+		return err
+	}
+	setLatency := func(delay time.Duration, jitter time.Duration) error {
+		for _, nodeName := range nodeNames {
+			if err := docker.SetLocalNetworkNodeLatency(nodeName, delay, jitter); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := setLatency(latencyDelay, latencyJitter); err != nil {
+		return err
+	}
+	if latencyDelay == 0 {
+		ux.Logger.GreenCheckmarkToUser("Removed latency of %s", strings.Join(nodeNames, ", "))
+		return nil
+	}
+	ux.Logger.GreenCheckmarkToUser("Added %s latency to %s", latencyDelay, strings.Join(nodeNames, ", "))
+	return undoChaosAfterDuration(func() error {
+		return setLatency(0, 0)
+	})
+}
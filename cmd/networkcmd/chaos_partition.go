@@ -0,0 +1,56 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/docker"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var partitionGroups []string
+
+// avalanche network chaos partition
+func newChaosPartitionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "partition",
+		Short: "Splits the local network into groups of nodes that can't reach each other",
+		Long: `The network chaos partition command splits the nodes of the local network into groups,
+dropping all traffic between nodes of different groups. Each group is given with its own
+--group flag, as a comma separated list of node names. Nodes not included in any group form
+an additional group, so a single --group isolates its nodes from the rest of the network.
+
+A new partition replaces the previous one. Partitions are removed with network chaos heal.
+Needs the docker local network backend.`,
+		RunE: chaosPartition,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringArrayVar(&partitionGroups, "group", nil, "comma separated node names of a group. repeat for each group")
+	addChaosDurationFlag(cmd)
+	return cmd
+}
+
+func chaosPartition(_ *cobra.Command, _ []string) error {
+	if err := checkDockerChaos("partition"); err != nil {
+		return err
+	}
+	if len(partitionGroups) == 0 {
+		return fmt.Errorf("at least one --group is required")
+	}
+	groups := [][]string{}
+	for _, group := range partitionGroups {
+		groups = append(groups, strings.Split(strings.ReplaceAll(group, " ", ""), ","))
+	}
+	rootDir := app.GetLocalDockerNetworkDir()
+	if err := docker.PartitionLocalNetwork(rootDir, groups); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Local network partitioned: %s", strings.Join(partitionGroups, " | "))
+	return undoChaosAfterDuration(func() error {
+		return docker.RemoveLocalNetworkPartition(rootDir)
+	})
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/docker"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network chaos pause
+func newChaosPauseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pause [nodeName...]",
+		Short: "Pauses nodes of the local network",
+		Long: `The network chaos pause command pauses the given nodes of the local network, until
+resumed with network chaos resume or network chaos heal.
+
+On the docker backend the node processes are frozen, keeping their connections open but not
+answering, as a hung node does. On the process backend the nodes are stopped, as a crashed
+node does, and restarted with their state on resume.`,
+		RunE: chaosPause,
+		Args: cobrautils.MinimumNArgs(1),
+	}
+	addChaosDurationFlag(cmd)
+	return cmd
+}
+
+// avalanche network chaos resume
+func newChaosResumeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume [nodeName...]",
+		Short: "Resumes paused nodes of the local network",
+		Long:  `The network chaos resume command resumes the given nodes, paused with network chaos pause.`,
+		RunE:  chaosResume,
+		Args:  cobrautils.MinimumNArgs(1),
+	}
+}
+
+func chaosPause(_ *cobra.Command, nodeNames []string) error {
+	if err := checkLocalNetworkNodes(nodeNames); err != nil {
+		return err
+	}
+	if err := PauseNodes(nodeNames); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Paused %s", strings.Join(nodeNames, ", "))
+	return undoChaosAfterDuration(func() error {
+		return ResumeNodes(nodeNames)
+	})
+}
+
+func chaosResume(_ *cobra.Command, nodeNames []string) error {
+	if err := checkLocalNetworkNodes(nodeNames); err != nil {
+		return err
+	}
+	if err := ResumeNodes(nodeNames); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Resumed %s", strings.Join(nodeNames, ", "))
+	return nil
+}
+
+// PauseNodes pauses [nodeNames] on the local network
+func PauseNodes(nodeNames []string) error {
+	if app.UseLocalDockerNetwork() {
+		return docker.PauseLocalNetworkNodes(app.GetLocalDockerNetworkDir(), nodeNames)
+	}
+	cli, _, err := getLocalNetworkCluster()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	for _, nodeName := range nodeNames {
+		if _, err := cli.PauseNode(ctx, nodeName); err != nil {
+			return fmt.Errorf("failure pausing node %s: %w", nodeName, err)
+		}
+	}
+	return nil
+}
+
+// ResumeNodes resumes the paused [nodeNames] on the local network
+func ResumeNodes(nodeNames []string) error {
+	if app.UseLocalDockerNetwork() {
+		return docker.ResumeLocalNetworkNodes(app.GetLocalDockerNetworkDir(), nodeNames)
+	}
+	cli, _, err := getLocalNetworkCluster()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	for _, nodeName := range nodeNames {
+		if _, err := cli.ResumeNode(ctx, nodeName); err != nil {
+			return fmt.Errorf("failure resuming node %s: %w", nodeName, err)
+		}
+	}
+	return nil
+}
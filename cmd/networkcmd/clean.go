@@ -3,24 +3,47 @@
 package networkcmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 
+	"github.com/ava-labs/avalanche-cli/pkg/bincache"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/node"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/shirou/gopsutil/process"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
-var hard bool
+const snapshotDirPrefix = "anr-snapshot-"
+
+var (
+	hard             bool
+	cleanBlockchains []string
+	cleanRelayer     bool
+	cleanSnapshots   bool
+	cleanBinaries    bool
+	cleanDryRun      bool
+)
+
+// cleanTarget is a resource removed by network clean
+type cleanTarget struct {
+	description string
+	// local path of the resource, if any
+	path string
+}
 
 func newCleanCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -28,7 +51,17 @@ func newCleanCmd() *cobra.Command {
 		Short: "Stop the running local network and delete state",
 		Long: `The network clean command shuts down your local, multi-node network. All deployed Subnets
 shutdown and delete their state. You can restart the network by deploying a new Subnet
-configuration.`,
+configuration.
+
+To reset only part of the local environment, without stopping the network, use:
+
+  --blockchain     forget the local deployment of the given blockchains, so they can be
+                   deployed again from scratch
+  --relayer        stop the local relayer and remove its state
+  --snapshots      remove the snapshots saved by name with network stop --snapshot-name
+  --binaries       remove the downloaded avalanchego, VM and relayer binaries
+
+Use --dry-run to list what would be removed, together with its size on disk.`,
 		RunE: clean,
 		Args: cobrautils.ExactArgs(0),
 	}
@@ -39,11 +72,63 @@ configuration.`,
 		false,
 		"Also clean downloaded avalanchego and plugin binaries",
 	)
+	cmd.Flags().StringSliceVar(
+		&cleanBlockchains,
+		"blockchain",
+		nil,
+		"only forget the local deployment of the given blockchains",
+	)
+	cmd.Flags().BoolVar(
+		&cleanRelayer,
+		"relayer",
+		false,
+		"only stop the local relayer and remove its state",
+	)
+	cmd.Flags().BoolVar(
+		&cleanSnapshots,
+		"snapshots",
+		false,
+		"only remove the snapshots saved by name",
+	)
+	cmd.Flags().BoolVar(
+		&cleanBinaries,
+		"binaries",
+		false,
+		"only remove the downloaded binaries",
+	)
+	cmd.Flags().BoolVar(
+		&cleanDryRun,
+		"dry-run",
+		false,
+		"list what would be removed, without removing anything",
+	)
 
 	return cmd
 }
 
 func clean(*cobra.Command, []string) error {
+	selective := len(cleanBlockchains) > 0 || cleanRelayer || cleanSnapshots || cleanBinaries
+	if selective && hard {
+		return errors.New("--hard cleans everything, and can't be used together with --blockchain, --relayer, --snapshots or --binaries")
+	}
+	if cleanDryRun {
+		targets, err := getCleanTargets(selective)
+		if err != nil {
+			return err
+		}
+		printCleanTargets(targets)
+		return nil
+	}
+	if selective {
+		return selectiveClean()
+	}
+	if app.UseLocalDockerNetwork() {
+		return cleanDockerNetwork()
+	}
+	if err := removeSupervisor(); err != nil {
+		return err
+	}
+
 	app.Log.Info("killing gRPC server process...")
 
 	if err := binutils.KillgRPCServerProcess(
@@ -64,6 +149,14 @@ func clean(*cobra.Command, []string) error {
 		return err
 	}
 
+	if err := localnet.StopLocalDNSProxy(app); err != nil {
+		app.Log.Warn("failed stopping local DNS proxy", zap.Error(err))
+	}
+
+	if err := bincache.New(app.GetBinaryCacheDir()).Unref(bincache.LocalNetworkOwner); err != nil {
+		return err
+	}
+
 	if hard {
 		ux.Logger.PrintToUser("hard clean requested via flag, removing all downloaded avalanchego and plugin binaries")
 		binDir := filepath.Join(app.GetBaseDir(), constants.AvalancheCliBinDir)
@@ -83,18 +176,191 @@ func clean(*cobra.Command, []string) error {
 	if err := os.RemoveAll(snapshotPath); err != nil {
 		return err
 	}
+	if err := localnet.RemoveCustomGenesis(app); err != nil {
+		return err
+	}
 
 	return node.DestroyLocalNetworkConnectedCluster(app)
 }
 
+// selectiveClean removes only the resources given by flags, leaving the local network running
+func selectiveClean() error {
+	if len(cleanBlockchains) > 0 {
+		if err := checkLocallyDeployed(cleanBlockchains); err != nil {
+			return err
+		}
+		if err := removeLocalDeployInfo(cleanBlockchains); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Local deployment of %s removed. Deploying again creates a new blockchain", strings.Join(cleanBlockchains, ", "))
+	}
+	if cleanRelayer {
+		if err := interchain.RelayerCleanup(
+			app.GetLocalRelayerRunPath(models.Local),
+			app.GetLocalRelayerLogPath(models.Local),
+			app.GetLocalRelayerStorageDir(models.Local),
+		); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Local relayer stopped and its state removed.")
+	}
+	if cleanSnapshots {
+		snapshotPaths, err := getNamedSnapshotPaths()
+		if err != nil {
+			return err
+		}
+		for _, snapshotPath := range snapshotPaths {
+			if err := os.RemoveAll(snapshotPath); err != nil {
+				return err
+			}
+		}
+		ux.Logger.PrintToUser("%d snapshots removed.", len(snapshotPaths))
+	}
+	if cleanBinaries {
+		cleanBins(filepath.Join(app.GetBaseDir(), constants.AvalancheCliBinDir))
+	}
+	return nil
+}
+
+// getCleanTargets returns the resources network clean would remove
+func getCleanTargets(selective bool) ([]cleanTarget, error) {
+	relayerTargets := []cleanTarget{
+		{description: "local relayer state", path: app.GetLocalRelayerStorageDir(models.Local)},
+		{description: "local relayer logs", path: app.GetLocalRelayerLogPath(models.Local)},
+	}
+	binTarget := cleanTarget{description: "downloaded binaries", path: filepath.Join(app.GetBaseDir(), constants.AvalancheCliBinDir)}
+	targets := []cleanTarget{}
+	if selective {
+		if len(cleanBlockchains) > 0 {
+			if err := checkLocallyDeployed(cleanBlockchains); err != nil {
+				return nil, err
+			}
+			for _, blockchainName := range cleanBlockchains {
+				targets = append(targets, cleanTarget{description: "local deployment of " + blockchainName})
+			}
+		}
+		if cleanRelayer {
+			targets = append(targets, relayerTargets...)
+		}
+		if cleanSnapshots {
+			snapshotPaths, err := getNamedSnapshotPaths()
+			if err != nil {
+				return nil, err
+			}
+			for _, snapshotPath := range snapshotPaths {
+				snapshotName := strings.TrimPrefix(filepath.Base(snapshotPath), snapshotDirPrefix)
+				targets = append(targets, cleanTarget{description: "snapshot " + snapshotName, path: snapshotPath})
+			}
+		}
+		if cleanBinaries {
+			targets = append(targets, binTarget)
+		}
+		return targets, nil
+	}
+	if app.UseLocalDockerNetwork() {
+		return []cleanTarget{{description: "local docker network"}}, nil
+	}
+	targets = append(targets, cleanTarget{description: "running local network"})
+	targets = append(targets, relayerTargets...)
+	if hard {
+		targets = append(targets, binTarget)
+	}
+	targets = append(targets, cleanTarget{description: "local network VM plugins", path: app.GetPluginsDir()})
+	deployedBlockchains, err := subnet.GetLocallyDeployedSubnetsFromFile(app)
+	if err != nil {
+		return nil, err
+	}
+	for _, blockchainName := range deployedBlockchains {
+		targets = append(targets, cleanTarget{description: "local deployment of " + blockchainName})
+	}
+	targets = append(targets,
+		cleanTarget{description: "local network state", path: app.GetSnapshotPath(constants.DefaultSnapshotName)},
+		cleanTarget{description: "local network custom genesis", path: app.GetLocalNetworkGenesisPath()},
+	)
+	if isLocal, clusterName, err := node.ConnectedToLocalNetwork(app); err != nil {
+		return nil, err
+	} else if isLocal {
+		targets = append(targets, cleanTarget{description: "local cluster " + clusterName})
+	}
+	return targets, nil
+}
+
+func printCleanTargets(targets []cleanTarget) {
+	t := ux.DefaultTable("Resources to remove", table.Row{"Resource", "Path", "Size"})
+	var totalSize int64
+	for _, target := range targets {
+		path, size := "", ""
+		if target.path != "" {
+			if _, err := os.Stat(target.path); err != nil {
+				// nothing to remove
+				continue
+			}
+			bytes, err := utils.SizeInKB(target.path)
+			if err != nil {
+				app.Log.Warn("failed computing size", zap.String("path", target.path), zap.Error(err))
+			}
+			totalSize += bytes
+			path, size = target.path, utils.FormatSize(bytes)
+		}
+		t.AppendRow(table.Row{target.description, path, size})
+	}
+	if t.Length() == 0 {
+		ux.Logger.PrintToUser("Nothing to remove")
+		return
+	}
+	t.AppendFooter(table.Row{"Total", "", utils.FormatSize(totalSize)})
+	ux.Logger.PrintToUser(t.Render())
+}
+
+// getNamedSnapshotPaths returns the snapshots saved by name, excluding the default one
+// that holds the current local network state
+func getNamedSnapshotPaths() ([]string, error) {
+	entries, err := os.ReadDir(app.GetSnapshotsDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defaultSnapshotPath := app.GetSnapshotPath(constants.DefaultSnapshotName)
+	snapshotPaths := []string{}
+	for _, entry := range entries {
+		snapshotPath := filepath.Join(app.GetSnapshotsDir(), entry.Name())
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), snapshotDirPrefix) && snapshotPath != defaultSnapshotPath {
+			snapshotPaths = append(snapshotPaths, snapshotPath)
+		}
+	}
+	return snapshotPaths, nil
+}
+
+// checkLocallyDeployed fails if any of [blockchainNames] is not deployed on the local network
+func checkLocallyDeployed(blockchainNames []string) error {
+	for _, blockchainName := range blockchainNames {
+		if !app.SidecarExists(blockchainName) {
+			return fmt.Errorf("blockchain %s does not exist", blockchainName)
+		}
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			return err
+		}
+		if _, ok := sc.Networks[models.Local.String()]; !ok {
+			return fmt.Errorf("blockchain %s is not deployed on the local network", blockchainName)
+		}
+	}
+	return nil
+}
+
 func removeLocalDeployInfoFromSidecars() error {
 	// Remove all local deployment info from sidecar files
 	deployedSubnets, err := subnet.GetLocallyDeployedSubnetsFromFile(app)
 	if err != nil {
 		return err
 	}
+	return removeLocalDeployInfo(deployedSubnets)
+}
 
-	for _, subnet := range deployedSubnets {
+func removeLocalDeployInfo(blockchainNames []string) error {
+	for _, subnet := range blockchainNames {
 		sc, err := app.LoadSidecar(subnet)
 		if err != nil {
 			return err
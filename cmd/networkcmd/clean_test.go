@@ -4,9 +4,11 @@ package networkcmd
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ava-labs/avalanche-cli/internal/testutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
@@ -59,3 +61,20 @@ func Test_removeLocalDeployInfoFromSidecars(t *testing.T) {
 	require.NoError(t, err)
 	require.NotContains(t, loadedSC.Networks, models.Local.String())
 }
+
+func TestGetNamedSnapshotPaths(t *testing.T) {
+	require := require.New(t)
+	app = testutils.SetupTestInTempDir(t)
+
+	snapshotPaths, err := getNamedSnapshotPaths()
+	require.NoError(err)
+	require.Empty(snapshotPaths)
+
+	require.NoError(os.MkdirAll(app.GetSnapshotPath(constants.DefaultSnapshotName), constants.DefaultPerms755))
+	require.NoError(os.MkdirAll(app.GetSnapshotPath("saved"), constants.DefaultPerms755))
+	require.NoError(os.MkdirAll(filepath.Join(app.GetSnapshotsDir(), "other"), constants.DefaultPerms755))
+
+	snapshotPaths, err = getNamedSnapshotPaths()
+	require.NoError(err)
+	require.Equal([]string{app.GetSnapshotPath("saved")}, snapshotPaths)
+}
@@ -0,0 +1,266 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/ava-labs/avalanche-cli/cmd/flags"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type dnsFlags struct {
+	enable        bool
+	disable       bool
+	hostsFilePath string
+	proxyPort     uint16
+	// hidden flags used by the processes the command starts
+	serveProxy bool
+	writeNames []string
+}
+
+var dnsCmdFlags dnsFlags
+
+// avalanche network dns
+func newDNSCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dns",
+		Short: "Manage friendly local DNS names for the local network endpoints",
+		Long: `The network dns command manages friendly DNS names for the local network RPC endpoints,
+eg mychain.` + localnet.LocalDNSDomain + ` for blockchain mychain and ` + localnet.LocalDNSDomain + ` for the primary nodes.
+
+The names are registered on a section of the hosts file managed by the CLI, and are kept up to
+date when blockchains are deployed and when the local network is started. A local DNS proxy,
+listening on a fixed localhost port (default ` + strconv.Itoa(constants.LocalDNSProxyPort) + `), routes the requests to each name to
+the current endpoint of its blockchain, serving its RPC endpoint at /rpc and its websocket
+endpoint at /ws. So the URLs (eg http://mychain.` + localnet.LocalDNSDomain + `:` + strconv.Itoa(constants.LocalDNSProxyPort) + `/rpc) keep working when
+the blockchain is redeployed or the node ports change. Once enabled, the URLs are shown on the
+endpoints printed by blockchain describe, blockchain deploy and network status, so dapp configs
+can refer to the blockchains by name.
+
+Writing the system hosts file usually requires admin rights. If the hosts file is not writable,
+--enable and --disable update it through sudo. Blockchains deployed afterwards need admin rights
+to have their names registered, so deploy prints a reminder to run --enable again instead.
+
+Without flags, shows the registered names.`,
+		RunE: dns,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().BoolVar(&dnsCmdFlags.enable, "enable", false, "register the local DNS names and keep them up to date")
+	cmd.Flags().BoolVar(&dnsCmdFlags.disable, "disable", false, "remove the local DNS names")
+	cmd.Flags().StringVar(&dnsCmdFlags.hostsFilePath, "hosts-file", "", fmt.Sprintf("hosts file to register the names on (default %s)", localnet.DefaultHostsFilePath()))
+	cmd.Flags().Uint16Var(&dnsCmdFlags.proxyPort, "proxy-port", 0, fmt.Sprintf("localhost port of the local DNS proxy (default %d)", constants.LocalDNSProxyPort))
+	cmd.Flags().BoolVar(&dnsCmdFlags.serveProxy, "serve-proxy", false, "run the local DNS proxy in the current process")
+	cmd.Flags().StringSliceVar(&dnsCmdFlags.writeNames, "write-names", nil, "only write the given names on the hosts file")
+	_ = cmd.Flags().MarkHidden("serve-proxy")
+	_ = cmd.Flags().MarkHidden("write-names")
+	return cmd
+}
+
+func dns(cmd *cobra.Command, _ []string) error {
+	switch {
+	case dnsCmdFlags.serveProxy:
+		return serveLocalDNSProxy()
+	case cmd.Flags().Changed("write-names"):
+		if dnsCmdFlags.hostsFilePath == "" {
+			return fmt.Errorf("--hosts-file is required with --write-names")
+		}
+		return localnet.WriteHostsFileNames(dnsCmdFlags.hostsFilePath, utils.Filter(dnsCmdFlags.writeNames, func(name string) bool { return name != "" }))
+	}
+	if !flags.EnsureMutuallyExclusive([]bool{dnsCmdFlags.enable, dnsCmdFlags.disable}) {
+		return fmt.Errorf("--enable and --disable are mutually exclusive")
+	}
+	switch {
+	case dnsCmdFlags.enable:
+		hostsFilePath := dnsCmdFlags.hostsFilePath
+		if hostsFilePath == "" {
+			hostsFilePath = localnet.DefaultHostsFilePath()
+		}
+		if cmd.Flags().Changed("proxy-port") {
+			if err := app.Conf.SetConfigValue(constants.ConfigLocalDNSProxyPortKey, dnsCmdFlags.proxyPort); err != nil {
+				return err
+			}
+			// restarted below with the new port
+			if err := localnet.StopLocalDNSProxy(app); err != nil {
+				return err
+			}
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigLocalDNSHostsFileKey, hostsFilePath); err != nil {
+			return err
+		}
+		if err := enableLocalDNS(hostsFilePath); err != nil {
+			_ = app.Conf.SetConfigValue(constants.ConfigLocalDNSHostsFileKey, "")
+			return err
+		}
+		ux.Logger.PrintToUser("Local DNS names registered on %s", hostsFilePath)
+		ux.Logger.PrintToUser("Local DNS proxy listening on port %d", localnet.GetLocalDNSProxyPort(app))
+		ux.Logger.PrintToUser("")
+		return printDNSNames()
+	case dnsCmdFlags.disable:
+		hostsFilePath := localnet.GetLocalDNSHostsFile(app)
+		if hostsFilePath == "" {
+			ux.Logger.PrintToUser("Local DNS names are not enabled")
+			return nil
+		}
+		if err := localnet.StopLocalDNSProxy(app); err != nil {
+			return err
+		}
+		if err := writeHostsFileNames(hostsFilePath, nil); err != nil {
+			return err
+		}
+		if err := app.Conf.SetConfigValue(constants.ConfigLocalDNSHostsFileKey, ""); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Local DNS names removed from %s", hostsFilePath)
+		return nil
+	default:
+		if dnsCmdFlags.hostsFilePath != "" {
+			return fmt.Errorf("--hosts-file can only be used together with --enable")
+		}
+		if cmd.Flags().Changed("proxy-port") {
+			return fmt.Errorf("--proxy-port can only be used together with --enable")
+		}
+		return printDNSNames()
+	}
+}
+
+// enableLocalDNS registers the local DNS names on [hostsFilePath], and starts the local DNS proxy
+func enableLocalDNS(hostsFilePath string) error {
+	names, err := localnet.GetLocalDNSNames(app)
+	if err != nil {
+		return err
+	}
+	if err := writeHostsFileNames(hostsFilePath, maps.Keys(names)); err != nil {
+		return err
+	}
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return localnet.StartLocalDNSProxy(app, execPath)
+}
+
+// writeHostsFileNames writes [names] on the section of [hostsFilePath] managed by the CLI. If
+// the current user can't write the file, it is written by this same binary run through sudo
+func writeHostsFileNames(hostsFilePath string, names []string) error {
+	err := localnet.WriteHostsFileNames(hostsFilePath, names)
+	if !errors.Is(err, os.ErrPermission) || runtime.GOOS == "windows" {
+		return err
+	}
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Writing %s needs admin rights. Updating it through sudo", hostsFilePath)
+	sudoCmd := exec.Command(
+		"sudo",
+		execPath,
+		"network",
+		"dns",
+		"--hosts-file",
+		hostsFilePath,
+		"--write-names="+strings.Join(names, ","),
+		"--"+constants.SkipUpdateFlag,
+	)
+	sudoCmd.Stdin = os.Stdin
+	sudoCmd.Stdout = os.Stdout
+	sudoCmd.Stderr = os.Stderr
+	if err := sudoCmd.Run(); err != nil {
+		return fmt.Errorf("could not update hosts file %s through sudo: %w", hostsFilePath, err)
+	}
+	return nil
+}
+
+// serveLocalDNSProxy runs the local DNS proxy until the process is interrupted
+func serveLocalDNSProxy() error {
+	// keep running after the terminal that started it is closed
+	signal.Ignore(syscall.SIGHUP)
+	if err := localnet.SaveLocalDNSProxyPID(app, os.Getpid()); err != nil {
+		return err
+	}
+	defer func() {
+		_ = localnet.RemoveLocalDNSProxyPID(app)
+	}()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return localnet.ServeLocalDNSProxy(ctx, app)
+}
+
+func printDNSNames() error {
+	hostsFilePath := localnet.GetLocalDNSHostsFile(app)
+	if hostsFilePath == "" {
+		ux.Logger.PrintToUser("Local DNS names are not enabled. Use avalanche network dns --enable to register them")
+		return nil
+	}
+	names, err := localnet.GetLocalDNSNames(app)
+	if err != nil {
+		return err
+	}
+	registeredNames, err := localnet.ReadHostsFileNames(hostsFilePath)
+	if err != nil {
+		return err
+	}
+	sortedNames := maps.Keys(names)
+	sort.Strings(sortedNames)
+	header := table.Row{"Name", "Refers To", "RPC URL", "Registered"}
+	t := ux.DefaultTable(fmt.Sprintf("Local DNS Names (%s)", hostsFilePath), header)
+	for _, name := range sortedNames {
+		blockchainName := names[name]
+		refersTo := "Primary Nodes"
+		endpoint := constants.LocalAPIEndpoint
+		if blockchainName != "" {
+			refersTo = blockchainName
+			endpoint, _, err = contract.GetBlockchainEndpoints(
+				app,
+				app.GetLocalNetwork(),
+				contract.ChainSpec{
+					BlockchainName: blockchainName,
+				},
+				false,
+				false,
+			)
+			if err != nil {
+				endpoint = ""
+			}
+		}
+		rpcURL := localnet.GetLocalDNSURL(app, endpoint, blockchainName)
+		if rpcURL == "" {
+			rpcURL = "-"
+		}
+		registered := "No"
+		if slices.Contains(registeredNames, name) {
+			registered = "Yes"
+		}
+		t.AppendRow(table.Row{name, refersTo, rpcURL, registered})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	proc, err := localnet.GetLocalDNSProxyProcess(app)
+	if err != nil {
+		return err
+	}
+	if proc != nil {
+		ux.Logger.PrintToUser("Local DNS proxy running on port %d, pid %d", localnet.GetLocalDNSProxyPort(app), proc.Pid)
+	} else {
+		ux.Logger.PrintToUser("Local DNS proxy not running. It is started by avalanche network start")
+	}
+	return nil
+}
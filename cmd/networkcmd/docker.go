@@ -0,0 +1,280 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/docker"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+)
+
+// startDockerNetwork starts the local network with the docker backend, running each node
+// in its own container. A network previously stopped is resumed with its state
+func startDockerNetwork(flags StartFlags, printEndpoints bool) error {
+	if flags.Persistent {
+		return fmt.Errorf("--persistent is not needed with the %s backend: node containers are restarted by the docker daemon", constants.LocalNetworkDockerBackend)
+	}
+	if flags.AvagoBinaryPath != "" {
+		return fmt.Errorf("--avalanchego-path is not supported with the %s backend, use --avalanchego-version instead", constants.LocalNetworkDockerBackend)
+	}
+	if err := docker.CheckLocalNetworkRequirements(); err != nil {
+		return err
+	}
+	rootDir := app.GetLocalDockerNetworkDir()
+	if docker.LocalNetworkExists(rootDir) {
+		if flags.GenesisPath != "" {
+			return fmt.Errorf("a custom genesis can only be used to start a new network, but one already exists. clean it first")
+		}
+		ux.Logger.PrintToUser("Starting previously stopped network")
+	} else {
+		nodeConfig, err := app.Conf.LoadNodeConfig()
+		if err != nil {
+			return err
+		}
+		nodeConfig, err = utils.SetJSONKey(nodeConfig, config.ProposerVMUseCurrentHeightKey, true)
+		if err != nil {
+			return err
+		}
+		if flags.NumNodes == 0 {
+			flags.NumNodes = constants.LocalNetworkNumNodes
+		}
+		networkID := uint32(constants.LocalNetworkID)
+		if flags.GenesisPath != "" {
+			customGenesis, err := localnet.LoadCustomGenesis(flags.GenesisPath, flags.NumNodes)
+			if err != nil {
+				return err
+			}
+			if !localnet.FundsDefaultKey(customGenesis) {
+				ux.Logger.PrintToUser(logging.Yellow.Wrap(
+					"The custom genesis does not fund the default ewoq key, so local flows that pay fees with it will fail. Use --key with a key funded by the genesis instead",
+				))
+			}
+			networkID = customGenesis.NetworkID
+		}
+		if err := docker.CreateLocalNetwork(rootDir, docker.LocalNetworkParams{
+			NumNodes:           flags.NumNodes,
+			NetworkID:          networkID,
+			GenesisPath:        flags.GenesisPath,
+			AvalanchegoVersion: flags.UserProvidedAvagoVersion,
+			CPUs:               app.Conf.GetConfigStringValue(constants.ConfigLocalNetworkNodeCPUsKey),
+			Memory:             app.Conf.GetConfigStringValue(constants.ConfigLocalNetworkNodeMemKey),
+			NodeConfig:         nodeConfig,
+			Upgrade:            upgradeData,
+		}); err != nil {
+			return err
+		}
+		if flags.GenesisPath != "" {
+			if err := localnet.SaveCustomGenesis(app, flags.GenesisPath); err != nil {
+				return err
+			}
+		} else if err := localnet.RemoveCustomGenesis(app); err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("Booting Network. Wait until healthy...")
+	if err := docker.StartLocalNetwork(rootDir, constants.ANRRequestTimeout); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Network ready to use (%s backend)", constants.LocalNetworkDockerBackend)
+	if printEndpoints {
+		return printDockerNetworkEndpoints(rootDir)
+	}
+	return nil
+}
+
+// stopDockerNetwork removes the node containers of the docker backend, keeping
+// the network state
+func stopDockerNetwork() error {
+	rootDir := app.GetLocalDockerNetworkDir()
+	if !docker.LocalNetworkExists(rootDir) {
+		ux.Logger.PrintToUser("No local network running")
+		return nil
+	}
+	if err := docker.StopLocalNetwork(rootDir); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Network stopped successfully.")
+	return nil
+}
+
+// cleanDockerNetwork removes the node containers and the state of the docker backend
+func cleanDockerNetwork() error {
+	if err := docker.RemoveLocalNetwork(app.GetLocalDockerNetworkDir()); err != nil {
+		return err
+	}
+	if err := localnet.RemoveCustomGenesis(app); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Network containers and state removed.")
+	return nil
+}
+
+func printDockerNetworkStatus() error {
+	rootDir := app.GetLocalDockerNetworkDir()
+	if !docker.LocalNetworkExists(rootDir) {
+		ux.Logger.PrintToUser("No local network running")
+		return nil
+	}
+	status, err := docker.GetLocalNetworkStatus(rootDir)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Local network backend: %s", constants.LocalNetworkDockerBackend)
+	ux.Logger.PrintToUser(status)
+	return printDockerNetworkEndpoints(rootDir)
+}
+
+func printDockerNetworkEndpoints(rootDir string) error {
+	endpoints, err := docker.GetLocalNetworkEndpoints(rootDir)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Node API endpoints:")
+	for _, endpoint := range endpoints {
+		ux.Logger.PrintToUser("  %s", endpoint)
+	}
+	return nil
+}
+
+// getDockerNetworkNodes returns the nodes of the local network run with the docker backend
+func getDockerNetworkNodes() ([]localNetworkNode, error) {
+	rootDir := app.GetLocalDockerNetworkDir()
+	if !docker.LocalNetworkExists(rootDir) {
+		return nil, fmt.Errorf("local network is not running")
+	}
+	dockerNodes, err := docker.GetLocalNetworkNodes(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	nodes := []localNetworkNode{}
+	for _, dockerNode := range dockerNodes {
+		uri := fmt.Sprintf("http://127.0.0.1:%d", dockerNode.HTTPPort)
+		nodeID, _, err := info.NewClient(uri).GetNodeID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failure querying %s: %w", dockerNode.Name, err)
+		}
+		nodes = append(nodes, localNetworkNode{
+			name:   dockerNode.Name,
+			nodeID: nodeID,
+			uri:    uri,
+		})
+	}
+	return nodes, nil
+}
+
+// GetDockerNetworkEndpoints returns the API endpoints of the nodes of the local network
+// run with the docker backend
+func GetDockerNetworkEndpoints() ([]string, error) {
+	return docker.GetLocalNetworkEndpoints(app.GetLocalDockerNetworkDir())
+}
+
+// GetLocalNetworkRPCVersion returns the RPC protocol version of the local network nodes,
+// on any backend
+func GetLocalNetworkRPCVersion() (int, error) {
+	if !app.UseLocalDockerNetwork() {
+		_, _, rpcVersion, err := localnet.GetVersion()
+		return rpcVersion, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	versionResponse, err := info.NewClient(constants.LocalAPIEndpoint).GetNodeVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int(versionResponse.RPCProtocolVersion), nil
+}
+
+// alreadyDeployedOnDockerNetwork checks if the local blockchain of [blockchainName] exists
+// on the P-Chain of the local network run with the docker backend
+func alreadyDeployedOnDockerNetwork(blockchainName string) (bool, error) {
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return false, err
+	}
+	blockchainID := sc.Networks[app.GetLocalNetwork().Name()].BlockchainID
+	if blockchainID == ids.Empty {
+		return false, nil
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	blockchainStatus, err := platformvm.NewClient(constants.LocalAPIEndpoint).GetBlockchainStatus(ctx, blockchainID.String())
+	if err != nil {
+		return false, err
+	}
+	return blockchainStatus != status.UnknownChain, nil
+}
+
+// trackSubnetOnDockerNetwork is TrackSubnet for the docker backend: installs [vmBin] and the
+// blockchain configs on the node containers, and restarts them tracking the blockchain subnet
+func trackSubnetOnDockerNetwork(
+	sc models.Sidecar,
+	blockchainName string,
+	vmID ids.ID,
+	vmBin string,
+	sovereign bool,
+) error {
+	if runtime.GOOS != "linux" {
+		// the VM binary installed by the CLI is built for the host, but runs inside the containers
+		return fmt.Errorf("deploying blockchains on the %s local network backend requires a linux host", constants.LocalNetworkDockerBackend)
+	}
+	networkInfo := sc.Networks[app.GetLocalNetwork().Name()]
+	chain := docker.LocalNetworkChain{
+		SubnetID:            networkInfo.SubnetID,
+		BlockchainID:        networkInfo.BlockchainID,
+		VMID:                vmID,
+		VMBinaryPath:        vmBin,
+		PerNodeChainConfigs: map[string][]byte{},
+	}
+	var err error
+	if app.ChainConfigExists(blockchainName) {
+		if chain.ChainConfig, err = os.ReadFile(app.GetChainConfigPath(blockchainName)); err != nil {
+			return err
+		}
+	}
+	if app.AvagoSubnetConfigExists(blockchainName) {
+		if chain.SubnetConfig, err = os.ReadFile(app.GetAvagoSubnetConfigPath(blockchainName)); err != nil {
+			return err
+		}
+	}
+	perNodeChainConfigPath := filepath.Join(app.GetSubnetDir(), blockchainName, constants.PerNodeChainConfigFileName)
+	if utils.FileExists(perNodeChainConfigPath) {
+		perNodeChainConfig, err := utils.ReadJSON(perNodeChainConfigPath)
+		if err != nil {
+			return err
+		}
+		for nodeName, cfg := range perNodeChainConfig {
+			if chain.PerNodeChainConfigs[nodeName], err = json.Marshal(cfg); err != nil {
+				return err
+			}
+		}
+	}
+	ux.Logger.PrintToUser("Restarting node containers to track subnet")
+	if err := docker.TrackLocalNetworkChain(app.GetLocalDockerNetworkDir(), chain, constants.ANRRequestTimeout); err != nil {
+		return err
+	}
+	nodes, err := getDockerNetworkNodes()
+	if err != nil {
+		return err
+	}
+	if err := waitTrackingNodes(&sc, nodes); err != nil {
+		return err
+	}
+	return finishTracking(&sc, blockchainName, nodes, sovereign)
+}
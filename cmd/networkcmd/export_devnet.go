@@ -0,0 +1,50 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var devnetOutputFile string
+
+// avalanche network export-devnet
+func newExportDevnetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-devnet [devnetName]",
+		Short: "Export the descriptor of a registered devnet",
+		Long: `The network export-devnet command exports the descriptor of a devnet registered with
+network add-devnet, so it can be shared and registered by others with network add-devnet --file.
+The descriptor contains the devnet endpoint, network ID and funded key addresses, but no secrets.`,
+		RunE: exportDevnet,
+		Args: cobrautils.ExactArgs(1),
+	}
+	cmd.Flags().StringVarP(&devnetOutputFile, "output", "o", "", "write the descriptor to the given file instead of stdout")
+	return cmd
+}
+
+func exportDevnet(_ *cobra.Command, args []string) error {
+	devnetConfig, err := app.GetDevnetConfig(args[0])
+	if err != nil {
+		return err
+	}
+	descriptorBytes, err := json.MarshalIndent(devnetConfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	if devnetOutputFile == "" {
+		ux.Logger.PrintToUser(string(descriptorBytes))
+		return nil
+	}
+	if err := os.WriteFile(devnetOutputFile, descriptorBytes, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Devnet %s descriptor exported to %s", devnetConfig.Name, devnetOutputFile)
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -39,7 +40,7 @@ func TrackSubnet(
 	if err != nil {
 		return err
 	}
-	network := models.NewLocalNetwork()
+	network := app.GetLocalNetwork()
 	if sc.Networks[network.Name()].BlockchainID == ids.Empty {
 		return fmt.Errorf("blockchain %s has not been deployed to %s", blockchainName, network.Name())
 	}
@@ -62,6 +63,10 @@ func TrackSubnet(
 		return fmt.Errorf("unknown vm: %s", sc.VM)
 	}
 
+	if app.UseLocalDockerNetwork() {
+		return trackSubnetOnDockerNetwork(sc, blockchainName, vmID, vmBin, sovereign)
+	}
+
 	pluginPath := filepath.Join(app.GetPluginsDir(), vmID.String())
 	if err := utils.FileCopy(vmBin, pluginPath); err != nil {
 		return err
@@ -84,7 +89,6 @@ func TrackSubnet(
 	if err != nil {
 		return err
 	}
-	publicEndpoints := []string{}
 	for _, nodeInfo := range status.ClusterInfo.NodeInfos {
 		if app.ChainConfigExists(blockchainName) {
 			inputChainConfigPath := app.GetChainConfigPath(blockchainName)
@@ -159,62 +163,118 @@ func TrackSubnet(
 		if _, err := cli.RestartNode(ctx, nodeInfo.Name, opts...); err != nil {
 			return err
 		}
-		publicEndpoints = append(publicEndpoints, nodeInfo.Uri)
 	}
+	nodes, err := getANRNodes(cli)
+	if err != nil {
+		return err
+	}
+	if err := waitTrackingNodes(&sc, nodes); err != nil {
+		return err
+	}
+	if _, err := cli.UpdateStatus(ctx); err != nil {
+		return err
+	}
+	return finishTracking(&sc, blockchainName, nodes, sovereign)
+}
+
+// waitTrackingNodes adds the RPC endpoints of [nodes] to the local network data of [sc],
+// and waits until the nodes serve them and are bootstrapped
+func waitTrackingNodes(sc *models.Sidecar, nodes []localNetworkNode) error {
+	network := app.GetLocalNetwork()
 	networkInfo := sc.Networks[network.Name()]
 	rpcEndpoints := set.Of(networkInfo.RPCEndpoints...)
 	wsEndpoints := set.Of(networkInfo.WSEndpoints...)
-	for _, publicEndpoint := range publicEndpoints {
-		rpcEndpoints.Add(models.GetRPCEndpoint(publicEndpoint, networkInfo.BlockchainID.String()))
-		wsEndpoints.Add(models.GetWSEndpoint(publicEndpoint, networkInfo.BlockchainID.String()))
+	for _, node := range nodes {
+		rpcEndpoints.Add(models.GetRPCEndpoint(node.uri, networkInfo.BlockchainID.String()))
+		wsEndpoints.Add(models.GetWSEndpoint(node.uri, networkInfo.BlockchainID.String()))
 	}
 	networkInfo.RPCEndpoints = rpcEndpoints.List()
 	networkInfo.WSEndpoints = wsEndpoints.List()
+	sc.Networks[network.Name()] = networkInfo
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
 	for _, rpcURL := range networkInfo.RPCEndpoints {
 		ux.Logger.PrintToUser("Waiting for %s to be available", rpcURL)
 		if err := evm.WaitForRPC(ctx, rpcURL); err != nil {
 			return err
 		}
 	}
-	if err := IsBootstrapped(cli, blockchainID.String()); err != nil {
-		return err
-	}
-	if err := IsBootstrapped(cli, "P"); err != nil {
-		return err
-	}
-	if _, err := cli.UpdateStatus(ctx); err != nil {
+	if err := waitBootstrapped(nodes, networkInfo.BlockchainID.String()); err != nil {
 		return err
 	}
-	if err := SetAlias(cli, blockchainID.String(), blockchainName); err != nil {
+	return waitBootstrapped(nodes, "P")
+}
+
+// finishTracking aliases the blockchain of [sc] on [nodes], makes them validators
+// if the blockchain is not sovereign, and saves [sc]
+func finishTracking(sc *models.Sidecar, blockchainName string, nodes []localNetworkNode, sovereign bool) error {
+	network := app.GetLocalNetwork()
+	networkInfo := sc.Networks[network.Name()]
+	if err := setAlias(nodes, networkInfo.BlockchainID.String(), blockchainName); err != nil {
 		return err
 	}
 	if !sovereign {
-		if err := AddNoSovereignValidators(cli, subnetID); err != nil {
+		if err := addNoSovereignValidators(nodes, networkInfo.SubnetID); err != nil {
 			return err
 		}
-		if err := WaitNoSovereignValidators(cli, subnetID); err != nil {
+		if err := waitNoSovereignValidators(nodes, networkInfo.SubnetID); err != nil {
 			return err
 		}
 	}
-	sc.Networks[network.Name()] = networkInfo
-	if err := app.UpdateSidecar(&sc); err != nil {
+	if err := app.UpdateSidecar(sc); err != nil {
 		return err
 	}
 	ux.Logger.GreenCheckmarkToUser("%s successfully tracking %s", network.Name(), blockchainName)
 	return nil
 }
 
-func IsBootstrapped(cli client.Client, blockchainID string) error {
-	blockchainBootstrapCheckFrequency := time.Second
+// localNetworkNode is a node of the local network, on any of its backends
+type localNetworkNode struct {
+	name   string
+	nodeID ids.NodeID
+	uri    string
+}
+
+// getANRNodes returns the nodes of the local network run by the network runner server
+func getANRNodes(cli client.Client) ([]localNetworkNode, error) {
 	ctx, cancel := utils.GetANRContext()
 	defer cancel()
 	status, err := cli.Status(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	nodes := []localNetworkNode{}
 	for _, nodeInfo := range status.ClusterInfo.NodeInfos {
+		nodeID, err := ids.NodeIDFromString(nodeInfo.GetId())
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, localNetworkNode{
+			name:   nodeInfo.Name,
+			nodeID: nodeID,
+			uri:    nodeInfo.GetUri(),
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].name < nodes[j].name })
+	return nodes, nil
+}
+
+func IsBootstrapped(cli client.Client, blockchainID string) error {
+	nodes, err := getANRNodes(cli)
+	if err != nil {
+		return err
+	}
+	return waitBootstrapped(nodes, blockchainID)
+}
+
+// waitBootstrapped waits until all [nodes] are bootstrapped on [blockchainID]
+func waitBootstrapped(nodes []localNetworkNode, blockchainID string) error {
+	blockchainBootstrapCheckFrequency := time.Second
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	for _, node := range nodes {
 		for {
-			infoClient := info.NewClient(nodeInfo.GetUri())
+			infoClient := info.NewClient(node.uri)
 			boostrapped, err := infoClient.IsBootstrapped(ctx, blockchainID)
 			if err != nil && !strings.Contains(err.Error(), "there is no chain with alias/ID") {
 				return err
@@ -229,18 +289,22 @@ func IsBootstrapped(cli client.Client, blockchainID string) error {
 			}
 		}
 	}
-	return err
+	return nil
 }
 
 func SetAlias(cli client.Client, blockchainID string, alias string) error {
-	ctx, cancel := utils.GetANRContext()
-	defer cancel()
-	status, err := cli.Status(ctx)
+	nodes, err := getANRNodes(cli)
 	if err != nil {
 		return err
 	}
-	for _, nodeInfo := range status.ClusterInfo.NodeInfos {
-		adminClient := admin.NewClient(nodeInfo.GetUri())
+	return setAlias(nodes, blockchainID, alias)
+}
+
+func setAlias(nodes []localNetworkNode, blockchainID string, alias string) error {
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	for _, node := range nodes {
+		adminClient := admin.NewClient(node.uri)
 		if err := adminClient.AliasChain(ctx, blockchainID, alias); err != nil {
 			return err
 		}
@@ -249,17 +313,22 @@ func SetAlias(cli client.Client, blockchainID string, alias string) error {
 }
 
 func AddNoSovereignValidators(cli client.Client, subnetID ids.ID) error {
-	ctx, cancel := utils.GetANRContext()
-	defer cancel()
-	status, err := cli.Status(ctx)
+	nodes, err := getANRNodes(cli)
 	if err != nil {
 		return err
 	}
-	nodeInfo, ok := status.ClusterInfo.NodeInfos["node1"]
-	if !ok {
-		return fmt.Errorf("node1 not found on local network")
+	return addNoSovereignValidators(nodes, subnetID)
+}
+
+// addNoSovereignValidators adds all [nodes] as validators of the non sovereign [subnetID],
+// paying with the ewoq key
+func addNoSovereignValidators(nodes []localNetworkNode, subnetID ids.ID) error {
+	if len(nodes) == 0 {
+		return fmt.Errorf("no nodes found on local network")
 	}
-	pClient := platformvm.NewClient(nodeInfo.GetUri())
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	pClient := platformvm.NewClient(nodes[0].uri)
 	vs, err := pClient.GetCurrentValidators(ctx, avagoConstants.PrimaryNetworkID, nil)
 	if err != nil {
 		return err
@@ -276,7 +345,7 @@ func AddNoSovereignValidators(cli client.Client, subnetID ids.ID) error {
 	for _, v := range vs {
 		subnetValidators.Add(v.NodeID)
 	}
-	k, err := app.GetKey("ewoq", models.NewLocalNetwork(), false)
+	k, err := app.GetKey("ewoq", app.GetLocalNetwork(), false)
 	if err != nil {
 		return err
 	}
@@ -292,20 +361,15 @@ func AddNoSovereignValidators(cli client.Client, subnetID ids.ID) error {
 	if err != nil {
 		return err
 	}
-	for _, nodeInfo := range status.ClusterInfo.NodeInfos {
-		nodeIDStr := nodeInfo.GetId()
-		nodeID, err := ids.NodeIDFromString(nodeIDStr)
-		if err != nil {
-			return err
-		}
-		if isValidator := subnetValidators.Contains(nodeID); isValidator {
+	for _, node := range nodes {
+		if isValidator := subnetValidators.Contains(node.nodeID); isValidator {
 			continue
 		}
 		if _, err := wallet.P().IssueAddSubnetValidatorTx(
 			&txs.SubnetValidator{
 				Validator: txs.Validator{
-					NodeID: nodeID,
-					End:    uint64(primaryValidatorsEndtime[nodeID].Unix()),
+					NodeID: node.nodeID,
+					End:    uint64(primaryValidatorsEndtime[node.nodeID].Unix()),
 					Wght:   1000,
 				},
 				Subnet: subnetID,
@@ -320,19 +384,23 @@ func AddNoSovereignValidators(cli client.Client, subnetID ids.ID) error {
 }
 
 func WaitNoSovereignValidators(cli client.Client, subnetID ids.ID) error {
-	checkFrequency := time.Second
-	ctx, cancel := utils.GetANRContext()
-	defer cancel()
-	status, err := cli.Status(ctx)
+	nodes, err := getANRNodes(cli)
 	if err != nil {
 		return err
 	}
-	nodeInfo, ok := status.ClusterInfo.NodeInfos["node1"]
-	if !ok {
-		return fmt.Errorf("node1 not found on local network")
+	return waitNoSovereignValidators(nodes, subnetID)
+}
+
+// waitNoSovereignValidators waits until all [nodes] are validators of [subnetID]
+func waitNoSovereignValidators(nodes []localNetworkNode, subnetID ids.ID) error {
+	if len(nodes) == 0 {
+		return fmt.Errorf("no nodes found on local network")
 	}
-	pClient := platformvm.NewClient(nodeInfo.GetUri())
-	for _, nodeInfo := range status.ClusterInfo.NodeInfos {
+	checkFrequency := time.Second
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	pClient := platformvm.NewClient(nodes[0].uri)
+	for _, node := range nodes {
 		for {
 			vs, err := pClient.GetCurrentValidators(ctx, subnetID, nil)
 			if err != nil {
@@ -342,12 +410,7 @@ func WaitNoSovereignValidators(cli client.Client, subnetID ids.ID) error {
 			for _, v := range vs {
 				subnetValidators.Add(v.NodeID)
 			}
-			nodeIDStr := nodeInfo.GetId()
-			nodeID, err := ids.NodeIDFromString(nodeIDStr)
-			if err != nil {
-				return err
-			}
-			if subnetValidators.Contains(nodeID) {
+			if subnetValidators.Contains(node.nodeID) {
 				break
 			}
 			select {
@@ -357,10 +420,13 @@ func WaitNoSovereignValidators(cli client.Client, subnetID ids.ID) error {
 			}
 		}
 	}
-	return err
+	return nil
 }
 
 func AlreadyDeployed(blockchainName string) (bool, error) {
+	if app.UseLocalDockerNetwork() {
+		return alreadyDeployedOnDockerNetwork(blockchainName)
+	}
 	chainVMID, err := anrutils.VMID(blockchainName)
 	if err != nil {
 		return false, fmt.Errorf("failed to create VM ID from %s: %w", blockchainName, err)
@@ -0,0 +1,58 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+// avalanche network list-devnets
+func newListDevnetsCmd() *cobra.Command {
+	return cobrautils.MarkReadOnly(&cobra.Command{
+		Use:   "list-devnets",
+		Short: "List the registered devnets",
+		Long:  `The network list-devnets command lists the devnets registered with network add-devnet.`,
+		RunE:  listDevnets,
+		Args:  cobrautils.ExactArgs(0),
+	})
+}
+
+func listDevnets(_ *cobra.Command, _ []string) error {
+	devnetsConfig, err := app.LoadDevnetsConfig()
+	if err != nil {
+		return err
+	}
+	if len(devnetsConfig.Devnets) == 0 {
+		ux.Logger.PrintToUser("No devnets registered. Use avalanche network add-devnet to register one")
+		return nil
+	}
+	names := maps.Keys(devnetsConfig.Devnets)
+	sort.Strings(names)
+	header := table.Row{"Name", "Endpoint", "Network ID", "Funded Keys"}
+	t := ux.DefaultTable("Devnets", header)
+	for _, name := range names {
+		devnetConfig := devnetsConfig.Devnets[name]
+		keys := utils.Map(devnetConfig.Keys, func(k models.DevnetKey) string {
+			addrs := []string{}
+			if k.PChainAddr != "" {
+				addrs = append(addrs, k.PChainAddr)
+			}
+			if k.CChainAddr != "" {
+				addrs = append(addrs, k.CChainAddr)
+			}
+			return k.Name + ": " + strings.Join(addrs, ", ")
+		})
+		t.AppendRow(table.Row{name, devnetConfig.Endpoint, devnetConfig.NetworkID, strings.Join(keys, "\n")})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	return nil
+}
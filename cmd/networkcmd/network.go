@@ -22,7 +22,10 @@ When you deploy a Blockchain locally, it runs on a local, multi-node Avalanche n
 blockchain deploy command starts this network in the background. This command suite allows you
 to shutdown, restart, and clear that network.
 
-This network currently supports multiple, concurrently deployed Blockchains.`,
+This network currently supports multiple, concurrently deployed Blockchains.
+
+The suite also allows to register remote devnets by name, so that commands operating on devnets
+can refer to them with --network instead of their endpoints.`,
 		RunE: cobrautils.CommandSuiteUsage,
 		Args: cobrautils.ExactArgs(0),
 	}
@@ -34,5 +37,29 @@ This network currently supports multiple, concurrently deployed Blockchains.`,
 	cmd.AddCommand(newCleanCmd())
 	// network status
 	cmd.AddCommand(newStatusCmd())
+	// network upgrade
+	cmd.AddCommand(newUpgradeCmd())
+	// network supervise
+	cmd.AddCommand(newSuperviseCmd())
+	// network add-node
+	cmd.AddCommand(newAddNodeCmd())
+	// network remove-node
+	cmd.AddCommand(newRemoveNodeCmd())
+	// network add-devnet
+	cmd.AddCommand(newAddDevnetCmd())
+	// network list-devnets
+	cmd.AddCommand(newListDevnetsCmd())
+	// network remove-devnet
+	cmd.AddCommand(newRemoveDevnetCmd())
+	// network export-devnet
+	cmd.AddCommand(newExportDevnetCmd())
+	// network scratch-evm
+	cmd.AddCommand(newScratchEVMCmd())
+	// network dns
+	cmd.AddCommand(newDNSCmd())
+	// network probe
+	cmd.AddCommand(newProbeCmd())
+	// network chaos
+	cmd.AddCommand(newChaosCmd())
 	return cmd
 }
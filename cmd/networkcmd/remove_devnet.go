@@ -0,0 +1,40 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network remove-devnet
+func newRemoveDevnetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove-devnet [devnetName]",
+		Short: "Remove a registered devnet",
+		Long: `The network remove-devnet command removes a devnet registered with network add-devnet.
+Blockchain deployment records on the devnet are kept.`,
+		RunE: removeDevnet,
+		Args: cobrautils.ExactArgs(1),
+	}
+}
+
+func removeDevnet(_ *cobra.Command, args []string) error {
+	name := args[0]
+	devnetsConfig, err := app.LoadDevnetsConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := devnetsConfig.Devnets[name]; !ok {
+		return fmt.Errorf("devnet %q is not registered", name)
+	}
+	delete(devnetsConfig.Devnets, name)
+	if err := app.WriteDevnetsConfigFile(&devnetsConfig); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Devnet %s removed", name)
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+)
+
+var removeNodeForce bool
+
+// avalanche network remove-node
+func newRemoveNodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-node [nodeName]",
+		Short: "Removes a node from the running local network",
+		Long: `The network remove-node command stops and removes the given node from the running
+local network, without restarting it, and removes its RPC endpoints from the blockchains
+configuration.
+
+node1 can't be removed, as it is used as the entrypoint of the local network. A Primary
+Network validator is only removed if --force is given: its stake is not withdrawn and its
+validation keeps counting as offline until its end time.`,
+		RunE: removeNode,
+		Args: cobrautils.ExactArgs(1),
+	}
+	cmd.Flags().BoolVar(&removeNodeForce, "force", false, "remove the node even if it is a Primary Network validator")
+	return cmd
+}
+
+func removeNode(_ *cobra.Command, args []string) error {
+	return RemoveNode(args[0], removeNodeForce)
+}
+
+func RemoveNode(nodeName string, force bool) error {
+	if app.UseLocalDockerNetwork() {
+		return fmt.Errorf("remove-node is not supported on the docker local network backend")
+	}
+	if nodeName == "node1" {
+		return fmt.Errorf("node1 can't be removed from the local network")
+	}
+	cli, err := binutils.NewGRPCClientWithEndpoint(
+		binutils.LocalNetworkGRPCServerEndpoint,
+		binutils.WithAvoidRPCVersionCheck(true),
+		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
+	)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("local network is not running: %w", err)
+	}
+	nodeInfo, ok := status.ClusterInfo.NodeInfos[nodeName]
+	if !ok {
+		return fmt.Errorf("node %s not found on the local network. Available nodes: %v", nodeName, status.ClusterInfo.NodeNames)
+	}
+	network := app.GetLocalNetwork()
+	if !force {
+		nodeID, err := ids.NodeIDFromString(nodeInfo.Id)
+		if err != nil {
+			return err
+		}
+		isValidator, err := subnet.IsSubnetValidator(ids.Empty, nodeID, network)
+		if err != nil {
+			return fmt.Errorf("failure checking if node %s is a Primary Network validator: %w", nodeName, err)
+		}
+		if isValidator {
+			return fmt.Errorf("node %s is a Primary Network validator, and removing it reduces the online stake of the local network. Use --force to remove it anyway", nodeName)
+		}
+	}
+	if _, err := cli.RemoveNode(ctx, nodeName); err != nil {
+		return fmt.Errorf("failure removing node %s: %w", nodeName, err)
+	}
+	blockchains, err := getLocalBlockchains(network)
+	if err != nil {
+		return err
+	}
+	for _, blockchain := range blockchains {
+		if err := updateLocalNodeEndpoints(blockchain.name, network, nodeInfo.Uri, false); err != nil {
+			return err
+		}
+	}
+	ux.Logger.GreenCheckmarkToUser("Node %s removed from the local network", nodeName)
+	return nil
+}
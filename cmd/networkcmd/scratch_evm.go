@@ -0,0 +1,214 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/scratchevm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+const defaultScratchChainName = "scratch"
+
+type scratchEVMFlags struct {
+	vmVersion          string
+	avalancheGoVersion string
+	chainID            uint64
+	tokenSymbol        string
+	httpPort           uint32
+	reset              bool
+}
+
+var scratchFlags scratchEVMFlags
+
+// avalanche network scratch-evm
+func newScratchEVMCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scratch-evm [chainName]",
+		Short: "Start a single node Subnet-EVM chain for contract iteration",
+		Long: `The network scratch-evm command instantly starts a single node Subnet-EVM chain, that
+builds blocks as soon as transactions arrive, to iterate on smart contracts.
+
+The chain has no blockchain definition nor validator management to take care of. Its genesis
+uses the test defaults, prefunding the ewoq key and all the keys of the CLI key store. The
+command runs until interrupted with Ctrl+C, and the chain state is kept for the next run,
+unless --reset is given. The node runs apart from the local network, so both can be used
+at the same time.
+
+Once the contracts are ready, promote the scratch chain genesis into a full blockchain
+definition with avalanche blockchain create <blockchainName> --from-scratch-chain <chainName>.`,
+		RunE: scratchEVM,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+	cmd.Flags().StringVar(&scratchFlags.vmVersion, "vm-version", "latest", "version of Subnet-EVM to use on chain creation")
+	cmd.Flags().StringVar(&scratchFlags.avalancheGoVersion, "avalanchego-version", "", "use this version of avalanchego (defaults to the latest one compatible with the Subnet-EVM version)")
+	cmd.Flags().Uint64Var(&scratchFlags.chainID, "evm-chain-id", 31337, "chain ID to use on chain creation")
+	cmd.Flags().StringVar(&scratchFlags.tokenSymbol, "evm-token", "TEST", "token symbol to use on chain creation")
+	cmd.Flags().Uint32Var(&scratchFlags.httpPort, "http-port", 9670, "http port of the node. the staking port is the next one")
+	cmd.Flags().BoolVar(&scratchFlags.reset, "reset", false, "discard the previous chain state and genesis, and create the chain again")
+	return cmd
+}
+
+func scratchEVM(_ *cobra.Command, args []string) error {
+	chainName := defaultScratchChainName
+	if len(args) == 1 {
+		chainName = args[0]
+	}
+	if scratchFlags.reset {
+		if err := os.RemoveAll(app.GetScratchChainDir(chainName)); err != nil {
+			return err
+		}
+	}
+	chain, err := loadOrCreateScratchChain(chainName)
+	if err != nil {
+		return err
+	}
+	_, avagoDir, err := binutils.SetupAvalanchego(app, chain.AvalancheGoVersion)
+	if err != nil {
+		return fmt.Errorf("failed to install avalanchego: %w", err)
+	}
+	avalancheGoBinPath := filepath.Join(avagoDir, "avalanchego")
+	_, vmBinPath, err := binutils.SetupSubnetEVM(app, chain.VMVersion)
+	if err != nil {
+		return fmt.Errorf("failed to install subnet-evm: %w", err)
+	}
+	if err := scratchevm.InstallVM(app, chain, vmBinPath); err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("Starting scratch chain %s node (avalanchego %s, Subnet-EVM %s)", chain.Name, chain.AvalancheGoVersion, chain.VMVersion)
+	node, err := scratchevm.Launch(app, &chain, avalancheGoBinPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ux.Logger.PrintToUser("Stopping scratch chain %s node", chain.Name)
+		if err := node.Stop(); err != nil {
+			ux.Logger.RedXToUser("failure stopping scratch chain node: %s", err)
+		}
+	}()
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.GreenCheckmarkToUser("Scratch chain %s is ready", chain.Name)
+	ux.Logger.PrintToUser("  RPC URL:  %s", chain.RPCURL())
+	ux.Logger.PrintToUser("  Chain ID: %d", chain.ChainID)
+	ux.Logger.PrintToUser("  Token:    %s", chain.TokenSymbol)
+	ux.Logger.PrintToUser("  Funded:   ewoq (%s) and the CLI stored keys", vm.PrefundedEwoqAddress.Hex())
+	ux.Logger.PrintToUser("  Node log: %s", scratchevm.LogPath(app, chain))
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Promote it to a blockchain definition with avalanche blockchain create <blockchainName> --from-scratch-chain %s", chain.Name)
+	ux.Logger.PrintToUser("Press Ctrl+C to stop")
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(signals)
+	select {
+	case err := <-node.Done():
+		return fmt.Errorf("%w: %v. See %s", scratchevm.ErrNodeExited, err, scratchevm.LogPath(app, chain))
+	case <-signals:
+		ux.Logger.PrintToUser("")
+		return nil
+	}
+}
+
+// loadOrCreateScratchChain loads the scratch chain [chainName], or creates its
+// definition and genesis if it does not exist
+func loadOrCreateScratchChain(chainName string) (models.ScratchChain, error) {
+	if app.ScratchChainExists(chainName) {
+		return app.LoadScratchChain(chainName)
+	}
+	if err := checkInvalidScratchChainName(chainName); err != nil {
+		return models.ScratchChain{}, err
+	}
+	vmVersion, err := vm.PromptVMVersion(app, constants.SubnetEVMRepoName, scratchFlags.vmVersion)
+	if err != nil {
+		return models.ScratchChain{}, err
+	}
+	avagoVersion := scratchFlags.avalancheGoVersion
+	if avagoVersion == "" {
+		rpcVersion, err := vm.GetRPCProtocolVersion(app, models.SubnetEvm, vmVersion)
+		if err != nil {
+			return models.ScratchChain{}, err
+		}
+		avagoVersion, err = vm.GetLatestAvalancheGoByProtocolVersion(
+			app,
+			rpcVersion,
+			constants.AvalancheGoCompatibilityURL,
+		)
+		if err != nil {
+			return models.ScratchChain{}, err
+		}
+	}
+	chain := models.ScratchChain{
+		Name:               chainName,
+		VMVersion:          vmVersion,
+		AvalancheGoVersion: avagoVersion,
+		ChainID:            scratchFlags.chainID,
+		TokenSymbol:        scratchFlags.tokenSymbol,
+		HTTPPort:           scratchFlags.httpPort,
+	}
+	fundedAddresses, err := getStoredKeysCChainAddresses(scratchevm.Network(chain))
+	if err != nil {
+		return models.ScratchChain{}, err
+	}
+	genesisBytes, err := vm.CreateScratchEVMGenesis(
+		app,
+		chain.VMVersion,
+		chain.Name,
+		chain.ChainID,
+		chain.TokenSymbol,
+		fundedAddresses,
+	)
+	if err != nil {
+		return models.ScratchChain{}, err
+	}
+	if err := app.WriteScratchChain(chain); err != nil {
+		return models.ScratchChain{}, err
+	}
+	if err := os.WriteFile(app.GetScratchChainGenesisPath(chain.Name), genesisBytes, constants.WriteReadReadPerms); err != nil {
+		return models.ScratchChain{}, err
+	}
+	return chain, nil
+}
+
+// getStoredKeysCChainAddresses returns the C-Chain addresses of the CLI stored keys
+func getStoredKeysCChainAddresses(network models.Network) ([]common.Address, error) {
+	keyNames, err := utils.GetKeyNames(app.GetKeyDir(), false)
+	if err != nil {
+		return nil, err
+	}
+	addresses := []common.Address{}
+	for _, keyName := range keyNames {
+		k, err := app.GetKey(keyName, network, false)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, common.HexToAddress(k.C()))
+	}
+	return addresses, nil
+}
+
+// checkInvalidScratchChainName checks [chainName] can be used as VM name, and as dir name
+func checkInvalidScratchChainName(chainName string) error {
+	if chainName == "" || len(chainName) > 32 {
+		return fmt.Errorf("scratch chain name %q must have between 1 and 32 characters", chainName)
+	}
+	for _, c := range chainName {
+		if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '-' && c != '_' {
+			return fmt.Errorf("scratch chain name %q contains invalid character %q", chainName, c)
+		}
+	}
+	return nil
+}
@@ -4,10 +4,12 @@ package networkcmd
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/ava-labs/avalanche-cli/pkg/bincache"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
@@ -23,6 +25,8 @@ import (
 	"github.com/ava-labs/avalanche-network-runner/client"
 	anrutils "github.com/ava-labs/avalanche-network-runner/utils"
 	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/utils/logging"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -38,6 +42,9 @@ type StartFlags struct {
 	RelayerBinaryPath        string
 	RelayerVersion           string
 	NumNodes                 uint32
+	Persistent               bool
+	GenesisPath              string
+	AutoPorts                bool
 }
 
 var startFlags StartFlags
@@ -50,12 +57,37 @@ func newStartCmd() *cobra.Command {
 
 By default, the command loads the default snapshot. If you provide the --snapshot-name
 flag, the network loads that snapshot instead. The command fails if the local network is
-already running.`,
+already running.
+
+If you provide the --persistent flag, the network is kept running by a per-user service
+(systemd on Linux, launchd on macOS) that starts it on boot, restarts crashed nodes, and
+saves its state on shutdown. network stop removes the service.
+
+If you provide the --genesis flag, a new network is started from the given custom primary
+network genesis, instead of the default one. The genesis sets the network ID, initial stakers
+(that must be nodes of the local network) and C-Chain genesis. All the following commands
+operate on the local network using its network ID, until the network is cleaned or a new one
+is started with the default genesis.
+
+If the docker local network backend is configured (avalanche config localNetworkBackend docker),
+each node runs in its own avalanchego container instead, with the resource limits set on
+that command.
+
+Before booting, the command checks that the ports of the nodes are free, and fails with a
+report of the processes using them otherwise. If you provide the --auto-ports flag, nodes
+with busy ports get alternative ones, which are saved on the snapshot. The ports of the
+local relayer are always moved to free ones, and saved on its config.`,
 
 		RunE: start,
 		Args: cobrautils.ExactArgs(0),
 	}
+	addStartFlags(cmd)
+	cmd.Flags().BoolVar(&startFlags.Persistent, "persistent", false, "keep the network running across reboots, restarting crashed nodes")
+	return cmd
+}
 
+// addStartFlags adds the flags that define how the network is started
+func addStartFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(
 		&startFlags.UserProvidedAvagoVersion,
 		"avalanchego-version",
@@ -66,14 +98,14 @@ already running.`,
 	cmd.Flags().StringVar(&startFlags.RelayerBinaryPath, "relayer-path", "", "use this relayer binary path")
 	cmd.Flags().StringVar(&startFlags.SnapshotName, "snapshot-name", constants.DefaultSnapshotName, "name of snapshot to use to start the network from")
 	cmd.Flags().Uint32Var(&startFlags.NumNodes, "num-nodes", constants.LocalNetworkNumNodes, "number of nodes to be created on local network")
+	cmd.Flags().StringVar(&startFlags.GenesisPath, "genesis", "", "start a new network from this custom primary network genesis")
+	cmd.Flags().BoolVar(&startFlags.AutoPorts, "auto-ports", false, "assign alternative ports to nodes whose ports are in use")
 	cmd.Flags().StringVar(
 		&startFlags.RelayerVersion,
 		"relayer-version",
 		constants.LatestPreReleaseVersionTag,
 		"use this relayer version",
 	)
-
-	return cmd
 }
 
 func start(*cobra.Command, []string) error {
@@ -81,6 +113,25 @@ func start(*cobra.Command, []string) error {
 }
 
 func Start(flags StartFlags, printEndpoints bool) error {
+	if app.UseLocalDockerNetwork() {
+		return startDockerNetwork(flags, printEndpoints)
+	}
+	var customGenesis *genesis.Config
+	if flags.GenesisPath != "" {
+		var err error
+		customGenesis, err = localnet.LoadCustomGenesis(flags.GenesisPath, flags.NumNodes)
+		if err != nil {
+			return err
+		}
+		if !localnet.FundsDefaultKey(customGenesis) {
+			ux.Logger.PrintToUser(logging.Yellow.Wrap(
+				"The custom genesis does not fund the default ewoq key, so local flows that pay fees with it will fail. Use --key with a key funded by the genesis instead",
+			))
+		}
+	}
+	if flags.Persistent {
+		return startPersistent(flags, printEndpoints)
+	}
 	sd := subnet.NewLocalDeployer(app, flags.UserProvidedAvagoVersion, flags.AvagoBinaryPath, "", false)
 
 	// this takes about 2 secs
@@ -117,6 +168,9 @@ func Start(flags StartFlags, printEndpoints bool) error {
 	if err != nil {
 		return err
 	}
+	if err := bincache.New(app.GetBinaryCacheDir()).Ref(bincache.LocalNetworkOwner, avalancheGoBinPath); err != nil {
+		return err
+	}
 
 	autoSave := app.Conf.GetConfigBoolValue(constants.ConfigSnapshotsAutoSaveKey)
 
@@ -148,7 +202,24 @@ func Start(flags StartFlags, printEndpoints bool) error {
 	}
 
 	snapshotPath := app.GetSnapshotPath(flags.SnapshotName)
+	nodePorts, err := localnet.GetLocalNetworkPorts(snapshotPath, flags.NumNodes)
+	if err != nil {
+		return err
+	}
+	if err := checkLocalNetworkPorts(nodePorts, flags.AutoPorts); err != nil {
+		return err
+	}
 	if sdkutils.DirExists(snapshotPath) {
+		// a custom genesis is only accepted again if the snapshot was started from it
+		if customGenesis != nil {
+			networkID, err := app.GetLocalNetworkID()
+			if err != nil {
+				return err
+			}
+			if networkID != customGenesis.NetworkID {
+				return fmt.Errorf("a custom genesis can only be used to start a new network, but snapshot %s already exists. clean it first", flags.SnapshotName)
+			}
+		}
 		ux.Logger.PrintToUser("Starting previously deployed and stopped snapshot")
 
 		if !autoSave {
@@ -184,7 +255,7 @@ func Start(flags StartFlags, printEndpoints bool) error {
 			client.WithExecPath(avalancheGoBinPath),
 			client.WithRootDataDir(rootDir),
 			client.WithLogRootDir(logDir),
-			client.WithReassignPortsIfUsed(false),
+			client.WithReassignPortsIfUsed(flags.AutoPorts),
 			client.WithPluginDir(pluginDir),
 			client.WithGlobalNodeConfig(nodeConfig),
 		); err != nil {
@@ -197,7 +268,7 @@ func Start(flags StartFlags, printEndpoints bool) error {
 					app.Log.Warn("tried to kill the gRPC server process but it failed", zap.Error(innerErr))
 				}
 			}
-			return fmt.Errorf("failed to start network with the persisted snapshot: %w", err)
+			return addPortConflictsReport(nodePorts, fmt.Errorf("failed to start network with the persisted snapshot: %w", err))
 		}
 
 		if err := startLocalCluster(avalancheGoBinPath); err != nil {
@@ -220,10 +291,13 @@ func Start(flags StartFlags, printEndpoints bool) error {
 				app.GetLocalRelayerLogPath(models.Local),
 				app.GetLocalRelayerRunPath(models.Local),
 				app.GetLocalRelayerStorageDir(models.Local),
+				localnet.GetRunningNetworkPorts(),
 			); err != nil {
 				return err
 			} else if err := localnet.WriteExtraLocalNetworkData("", relayerBinPath, "", ""); err != nil {
 				return err
+			} else if err := bincache.New(app.GetBinaryCacheDir()).Ref(bincache.LocalNetworkOwner, relayerBinPath); err != nil {
+				return err
 			}
 		}
 	} else {
@@ -256,18 +330,31 @@ func Start(flags StartFlags, printEndpoints bool) error {
 
 		ux.Logger.PrintToUser("AvalancheGo path: %s\n", avalancheGoBinPath)
 
-		ux.Logger.PrintToUser("Booting Network. Wait until healthy...")
-		if _, err := cli.Start(
-			ctx,
-			avalancheGoBinPath,
+		startOpts := []client.OpOption{
 			client.WithNumNodes(flags.NumNodes),
 			client.WithExecPath(avalancheGoBinPath),
 			client.WithRootDataDir(rootDir),
 			client.WithLogRootDir(logDir),
-			client.WithReassignPortsIfUsed(false),
+			client.WithReassignPortsIfUsed(flags.AutoPorts),
 			client.WithPluginDir(pluginDir),
 			client.WithGlobalNodeConfig(nodeConfig),
 			client.WithUpgradePath(upgradePath),
+		}
+		if customGenesis != nil {
+			// the network runner server does not run from the current dir
+			genesisPath, err := filepath.Abs(flags.GenesisPath)
+			if err != nil {
+				return err
+			}
+			startOpts = append(startOpts, client.WithNetworkID(customGenesis.NetworkID), client.WithGenesisPath(genesisPath))
+			ux.Logger.PrintToUser("Using custom genesis %s with network ID %d\n", flags.GenesisPath, customGenesis.NetworkID)
+		}
+
+		ux.Logger.PrintToUser("Booting Network. Wait until healthy...")
+		if _, err := cli.Start(
+			ctx,
+			avalancheGoBinPath,
+			startOpts...,
 		); err != nil {
 			if sd.BackendStartedHere() {
 				if innerErr := binutils.KillgRPCServerProcess(
@@ -278,7 +365,15 @@ func Start(flags StartFlags, printEndpoints bool) error {
 					app.Log.Warn("tried to kill the gRPC server process but it failed", zap.Error(innerErr))
 				}
 			}
-			return fmt.Errorf("failed to start network: %w", err)
+			return addPortConflictsReport(nodePorts, fmt.Errorf("failed to start network: %w", err))
+		}
+
+		if customGenesis != nil {
+			if err := localnet.SaveCustomGenesis(app, flags.GenesisPath); err != nil {
+				return err
+			}
+		} else if err := localnet.RemoveCustomGenesis(app); err != nil {
+			return err
 		}
 	}
 
@@ -297,6 +392,12 @@ func Start(flags StartFlags, printEndpoints bool) error {
 	ux.Logger.PrintToUser("Network ready to use.")
 	ux.Logger.PrintToUser("")
 
+	if err := localnet.RefreshLocalDNS(app); errors.Is(err, localnet.ErrLocalDNSNeedsAdmin) {
+		ux.Logger.PrintToUser(err.Error())
+	} else if err != nil {
+		ux.Logger.RedXToUser("could not register local DNS names: %s", err)
+	}
+
 	if printEndpoints {
 		if err := localnet.PrintEndpoints(app, ux.Logger.PrintToUser, ""); err != nil {
 			return err
@@ -306,6 +407,36 @@ func Start(flags StartFlags, printEndpoints bool) error {
 	return nil
 }
 
+// checkLocalNetworkPorts verifies the [nodePorts] the nodes are going to use are free.
+// Busy ports are an error, unless alternative ones are to be assigned
+func checkLocalNetworkPorts(nodePorts map[uint16]string, autoPorts bool) error {
+	conflicts := utils.FindPortConflicts(nodePorts)
+	if len(conflicts) == 0 {
+		return nil
+	}
+	report := utils.PortConflictsReport(conflicts)
+	if !autoPorts {
+		return fmt.Errorf("local network ports are in use:\n%s\nstop the processes using them, or use --auto-ports to assign alternative ports", report)
+	}
+	ux.Logger.PrintToUser("%s", report)
+	ux.Logger.PrintToUser("Alternative ports will be assigned to the affected nodes")
+	ux.Logger.PrintToUser("")
+	return nil
+}
+
+// addPortConflictsReport adds the report of the conflicts on [nodePorts] to [err], if the
+// network failed to start because a node port was taken after it was checked
+func addPortConflictsReport(nodePorts map[uint16]string, err error) error {
+	if !utils.IsPortInUseError(err) {
+		return err
+	}
+	conflicts := utils.FindPortConflicts(nodePorts)
+	if len(conflicts) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w\n%s\nstop the processes using them, or use --auto-ports to assign alternative ports", err, utils.PortConflictsReport(conflicts))
+}
+
 func startLocalCluster(avalancheGoBinPath string) error {
 	names, err := localnet.GetBlockchainNames()
 	if err != nil {
@@ -327,7 +458,7 @@ func startLocalCluster(avalancheGoBinPath string) error {
 				nil,
 				node.ANRSettings{},
 				node.AvalancheGoVersionSettings{},
-				models.NewLocalNetwork(),
+				app.GetLocalNetwork(),
 				networkoptions.NetworkFlags{},
 				nil,
 			); err != nil {
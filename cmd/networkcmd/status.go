@@ -11,7 +11,7 @@ import (
 )
 
 func newStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	return cobrautils.MarkReadOnly(&cobra.Command{
 		Use:   "status",
 		Short: "Prints the status of the local network",
 		Long: `The network status command prints whether or not a local Avalanche
@@ -19,10 +19,16 @@ network is running and some basic stats about the network.`,
 
 		RunE: networkStatus,
 		Args: cobrautils.ExactArgs(0),
-	}
+	})
 }
 
 func networkStatus(*cobra.Command, []string) error {
+	if app.UseLocalDockerNetwork() {
+		return printDockerNetworkStatus()
+	}
+	if err := printSupervisorStatus(); err != nil {
+		return err
+	}
 	clusterInfo, err := localnet.GetClusterInfo()
 	if err != nil {
 		if server.IsServerError(err, server.ErrNotBootstrapped) {
@@ -50,3 +56,20 @@ func networkStatus(*cobra.Command, []string) error {
 
 	return nil
 }
+
+// printSupervisorStatus prints the state of the supervisor of a persistent network, if any
+func printSupervisorStatus() error {
+	status, err := localnet.GetSupervisorStatus()
+	if err != nil {
+		return err
+	}
+	if !status.Installed {
+		return nil
+	}
+	ux.Logger.PrintToUser("Persistent network supervisor (%s):", status.Kind)
+	ux.Logger.PrintToUser("  Unit: %s", status.UnitPath)
+	ux.Logger.PrintToUser("  Running: %t", status.Running)
+	ux.Logger.PrintToUser("  Starts On Boot: %t", status.StartsOnBoot)
+	ux.Logger.PrintToUser("")
+	return nil
+}
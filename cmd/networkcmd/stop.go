@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/node"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
@@ -51,6 +52,12 @@ func stop(*cobra.Command, []string) error {
 }
 
 func Stop(flags StopFlags) error {
+	if app.UseLocalDockerNetwork() {
+		return stopDockerNetwork()
+	}
+	if err := removeSupervisor(); err != nil {
+		return err
+	}
 	if err := stopAndSaveNetwork(flags); err != nil {
 		if errors.Is(err, binutils.ErrGRPCTimeout) {
 			// no server to kill
@@ -80,9 +87,30 @@ func Stop(flags StopFlags) error {
 		return err
 	}
 
+	if err := localnet.StopLocalDNSProxy(app); err != nil {
+		app.Log.Warn("failed stopping local DNS proxy", zap.Error(err))
+	}
+
 	return nil
 }
 
+// removeSupervisor removes the supervisor of a persistent network, if any, so that the
+// network is not restarted after being stopped
+func removeSupervisor() error {
+	// the supervisor itself stops the network through this code path
+	if supervised {
+		return nil
+	}
+	status, err := localnet.GetSupervisorStatus()
+	if err != nil {
+		return err
+	}
+	if !status.Installed {
+		return nil
+	}
+	return detachSupervisor()
+}
+
 func stopAndSaveNetwork(flags StopFlags) error {
 	cli, err := binutils.NewGRPCClient(
 		binutils.WithAvoidRPCVersionCheck(true),
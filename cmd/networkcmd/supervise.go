@@ -0,0 +1,221 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+// set when running as the network supervisor
+var supervised bool
+
+const (
+	superviseCmdName = "supervise"
+	// how often to check if a persistent network finished booting
+	persistentStartPollInterval = 5 * time.Second
+)
+
+// avalanche network supervise
+func newSuperviseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   superviseCmdName,
+		Short: "Starts the local network and keeps it running",
+		Long: `The network supervise command starts the local network, and then restarts its nodes
+if they crash. On termination, it stops the network saving its state.
+
+It is meant to be executed by the service manager of the host, as set up by network start --persistent.`,
+		RunE:   supervise,
+		Args:   cobrautils.ExactArgs(0),
+		Hidden: true,
+	}
+	addStartFlags(cmd)
+	return cmd
+}
+
+func supervise(*cobra.Command, []string) error {
+	// stale detach requests from a previous supervisor are not to be honored
+	if err := os.Remove(supervisorDetachPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	flags := startFlags
+	flags.Persistent = false
+	if err := Start(flags, false); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Supervising local network")
+	ticker := time.NewTicker(constants.LocalNetworkSupervisorCheckInterval)
+	defer ticker.Stop()
+	nodeFailures := map[string]int{}
+	for {
+		select {
+		case <-signals:
+			return stopSupervisedNetwork(flags.SnapshotName)
+		case <-ticker.C:
+			// failing here makes the service manager restart the supervisor, and so the network
+			if err := checkSupervisedNetwork(nodeFailures); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkSupervisedNetwork restarts the network nodes that failed the last
+// [constants.LocalNetworkSupervisorNodeFailures] liveness checks
+func checkSupervisedNetwork(nodeFailures map[string]int) error {
+	cli, err := binutils.NewGRPCClient(binutils.WithAvoidRPCVersionCheck(true))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("local network is down: %w", err)
+	}
+	for name, nodeInfo := range status.ClusterInfo.NodeInfos {
+		if nodeInfo.Paused || isNodeAlive(nodeInfo.Uri) {
+			nodeFailures[name] = 0
+			continue
+		}
+		nodeFailures[name]++
+		if nodeFailures[name] < constants.LocalNetworkSupervisorNodeFailures {
+			continue
+		}
+		ux.Logger.PrintToUser("Node %s is not responding. Restarting it", name)
+		if _, err := cli.RestartNode(ctx, name); err != nil {
+			ux.Logger.RedXToUser("failure restarting node %s: %s", name, err)
+			continue
+		}
+		nodeFailures[name] = 0
+	}
+	return nil
+}
+
+func isNodeAlive(uri string) bool {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	_, err := health.NewClient(uri).Liveness(ctx, nil)
+	return err == nil
+}
+
+// stopSupervisedNetwork saves and stops the network, unless the supervisor was
+// asked to detach from it
+func stopSupervisedNetwork(snapshotName string) error {
+	if _, err := os.Stat(supervisorDetachPath()); err == nil {
+		ux.Logger.PrintToUser("Detaching from local network")
+		return os.Remove(supervisorDetachPath())
+	}
+	ux.Logger.PrintToUser("Stopping local network")
+	supervised = true
+	return Stop(StopFlags{snapshotName: snapshotName})
+}
+
+func supervisorDetachPath() string {
+	return filepath.Join(app.GetRunDir(), constants.LocalNetworkSupervisorDetachFileName)
+}
+
+// startPersistent installs a service that runs the network supervisor, and waits
+// for the network to be ready
+func startPersistent(flags StartFlags, printEndpoints bool) error {
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{
+		"network",
+		superviseCmdName,
+		"--" + constants.SkipUpdateFlag,
+		"--avalanchego-version", flags.UserProvidedAvagoVersion,
+		"--snapshot-name", flags.SnapshotName,
+		"--num-nodes", strconv.FormatUint(uint64(flags.NumNodes), 10),
+		"--relayer-version", flags.RelayerVersion,
+	}
+	// the service does not run from the current dir
+	for _, binFlag := range []struct {
+		name string
+		path string
+	}{
+		{name: "--avalanchego-path", path: flags.AvagoBinaryPath},
+		{name: "--relayer-path", path: flags.RelayerBinaryPath},
+		{name: "--genesis", path: flags.GenesisPath},
+	} {
+		if binFlag.path == "" {
+			continue
+		}
+		absPath, err := filepath.Abs(binFlag.path)
+		if err != nil {
+			return err
+		}
+		args = append(args, binFlag.name, absPath)
+	}
+	if err := os.MkdirAll(app.GetRunDir(), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	logPath := filepath.Join(app.GetRunDir(), constants.LocalNetworkSupervisorLogFileName)
+	if err := localnet.InstallSupervisor(execPath, args, logPath); err != nil {
+		return err
+	}
+	status, err := localnet.GetSupervisorStatus()
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Local network supervisor (%s) installed at %s", status.Kind, status.UnitPath)
+	ux.Logger.PrintToUser("Supervisor output at: %s", logPath)
+	if status.Kind == localnet.SystemdSupervisor && !status.StartsOnBoot {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("User services are only started on login. To start the network on boot, execute: loginctl enable-linger"))
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Booting Network. Wait until healthy...")
+	if err := waitForLocalNetwork(); err != nil {
+		return fmt.Errorf("%w. check the supervisor output at %s", err, logPath)
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Network ready to use.")
+	ux.Logger.PrintToUser("")
+	if printEndpoints {
+		return localnet.PrintEndpoints(app, ux.Logger.PrintToUser, "")
+	}
+	return nil
+}
+
+func waitForLocalNetwork() error {
+	deadline := time.Now().Add(constants.ANRRequestTimeout)
+	for time.Now().Before(deadline) {
+		if clusterInfo, err := localnet.GetClusterInfo(); err == nil && clusterInfo != nil && clusterInfo.Healthy {
+			return nil
+		}
+		time.Sleep(persistentStartPollInterval)
+	}
+	return fmt.Errorf("local network not ready after %s", constants.ANRRequestTimeout)
+}
+
+// detachSupervisor removes the supervisor service, leaving the network running
+func detachSupervisor() error {
+	if err := os.WriteFile(supervisorDetachPath(), nil, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	if err := localnet.UninstallSupervisor(); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Local network supervisor removed")
+	return nil
+}
@@ -0,0 +1,214 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	sdkutils "github.com/ava-labs/avalanche-cli/sdk/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/mod/semver"
+)
+
+type UpgradeFlags struct {
+	avalancheGoVersion string
+	avalancheGoPath    string
+	force              bool
+}
+
+var upgradeFlags UpgradeFlags
+
+func newUpgradeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the avalanchego version of the local network, preserving its state",
+		Long: `The network upgrade command switches the local network nodes to a new avalanchego version,
+keeping their databases and the deployed blockchains.
+
+The network is stopped (saving its state to the default snapshot), the config flags no longer
+accepted by the new binary are migrated, and the network is started again with the new
+binary, keeping its number of nodes and its relayer. Renamed flags get their new name, and
+flags not listed by the new binary --help are dropped. A persistent network keeps being
+supervised after the upgrade.
+
+The command fails if the new version is not RPC compatible with the VMs of the locally
+deployed blockchains, unless --force is given.`,
+		RunE: upgrade,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&upgradeFlags.avalancheGoVersion, "avalanchego-version", "", "upgrade to this version of avalanchego (ex: v1.12.1)")
+	cmd.Flags().StringVar(&upgradeFlags.avalancheGoPath, "avalanchego-path", "", "upgrade to this avalanchego binary")
+	cmd.Flags().BoolVar(&upgradeFlags.force, "force", false, "upgrade even if the new version is not compatible with the deployed VMs")
+	return cmd
+}
+
+func upgrade(*cobra.Command, []string) error {
+	if app.UseLocalDockerNetwork() {
+		return fmt.Errorf("network upgrade is not supported on the docker local network backend")
+	}
+	version := upgradeFlags.avalancheGoVersion
+	binaryPath := upgradeFlags.avalancheGoPath
+	switch {
+	case version == "" && binaryPath == "":
+		return fmt.Errorf("one of --avalanchego-version or --avalanchego-path is required")
+	case version != "" && binaryPath != "":
+		return fmt.Errorf("--avalanchego-version and --avalanchego-path are mutually exclusive")
+	case version != "" && !semver.IsValid(version):
+		return fmt.Errorf("invalid avalanchego version %q: a version like v1.12.1 is expected", version)
+	case binaryPath != "":
+		binaryPath = utils.ExpandHome(binaryPath)
+		if !utils.FileExists(binaryPath) {
+			return fmt.Errorf("avalanchego binary %s not found", binaryPath)
+		}
+	}
+
+	running, currentVersion, _, err := localnet.GetVersion()
+	if err != nil {
+		return err
+	}
+	snapshotPath := app.GetSnapshotPath(constants.DefaultSnapshotName)
+	if !running && !sdkutils.DirExists(snapshotPath) {
+		return fmt.Errorf("there is no local network to upgrade. Use avalanche network start instead")
+	}
+	if running && version != "" && currentVersion == version {
+		ux.Logger.PrintToUser("Local network is already running avalanchego %s", version)
+		return nil
+	}
+	if version != "" {
+		if err := checkLocalBlockchainsCompatibility(version); err != nil {
+			if !upgradeFlags.force {
+				return fmt.Errorf("%w. Use --force to upgrade anyway", err)
+			}
+			ux.Logger.RedXToUser("%s", err)
+		}
+	}
+	supervisorStatus, err := localnet.GetSupervisorStatus()
+	if err != nil {
+		return err
+	}
+	// the new binary is obtained before stopping the network, and tells which flags it accepts
+	newBinaryPath, err := subnet.NewLocalDeployer(app, version, binaryPath, "", false).SetupLocalEnv()
+	if err != nil {
+		return err
+	}
+	target, err := localnet.GetNodeFlagsTarget(newBinaryPath)
+	if err != nil {
+		return err
+	}
+
+	if running {
+		if currentVersion != "" {
+			ux.Logger.PrintToUser("Stopping local network running avalanchego %s", currentVersion)
+		}
+		if err := Stop(StopFlags{snapshotName: constants.DefaultSnapshotName}); err != nil {
+			return err
+		}
+	}
+	changes, err := localnet.MigrateSnapshotNodeFlags(snapshotPath, target)
+	if err != nil {
+		return err
+	}
+	printFlagMigrations(changes)
+	if err := migrateGlobalNodeConfig(target); err != nil {
+		return err
+	}
+	// the network keeps its nodes and its relayer binary
+	numNodes, err := localnet.GetSnapshotNumNodes(snapshotPath)
+	if err != nil {
+		return err
+	}
+	_, extraLocalNetworkData, err := localnet.GetExtraLocalNetworkData(snapshotPath)
+	if err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("")
+	// the relayer version is only used if no relayer binary was recorded, as on network start
+	flags := StartFlags{
+		UserProvidedAvagoVersion: version,
+		AvagoBinaryPath:          binaryPath,
+		SnapshotName:             constants.DefaultSnapshotName,
+		NumNodes:                 numNodes,
+		RelayerBinaryPath:        extraLocalNetworkData.RelayerPath,
+		RelayerVersion:           constants.LatestPreReleaseVersionTag,
+		Persistent:               supervisorStatus.Installed,
+	}
+	if flags.UserProvidedAvagoVersion == "" {
+		flags.UserProvidedAvagoVersion = constants.DefaultAvalancheGoVersion
+	}
+	if err := Start(flags, true); err != nil {
+		return fmt.Errorf("failed to start the upgraded network: %w", err)
+	}
+	if _, newVersion, _, err := localnet.GetVersion(); err == nil && newVersion != "" {
+		ux.Logger.PrintToUser("Local network upgraded to avalanchego %s", logging.Green.Wrap(newVersion))
+	}
+	return nil
+}
+
+// checkLocalBlockchainsCompatibility verifies the VMs of the blockchains deployed to the
+// local network can be run by avalanchego [version]
+func checkLocalBlockchainsCompatibility(version string) error {
+	blockchainNames, err := app.GetBlockchainNamesOnNetwork(app.GetLocalNetwork(), false)
+	if err != nil {
+		return err
+	}
+	for _, blockchainName := range blockchainNames {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			return err
+		}
+		if sc.RPCVersion == 0 {
+			// custom VMs without known RPC version
+			continue
+		}
+		compatibleVersions, err := vm.GetAvalancheGoVersionsForRPC(app, sc.RPCVersion, constants.AvalancheGoCompatibilityURL)
+		if err != nil {
+			return err
+		}
+		if !utils.Belongs(compatibleVersions, version) {
+			return fmt.Errorf("avalanchego %s is not compatible with RPC version %d of the VM of blockchain %s", version, sc.RPCVersion, blockchainName)
+		}
+	}
+	return nil
+}
+
+// migrateGlobalNodeConfig migrates the node config set on the CLI config, that is
+// given to all the local network nodes on start
+func migrateGlobalNodeConfig(target localnet.NodeFlagsTarget) error {
+	nodeConfig, err := app.Conf.LoadNodeConfig()
+	if err != nil {
+		return err
+	}
+	flags := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(nodeConfig), &flags); err != nil {
+		return err
+	}
+	changes := localnet.MigrateNodeFlags(flags, target)
+	if len(changes) == 0 {
+		return nil
+	}
+	printFlagMigrations(map[string][]string{"CLI node config": changes})
+	return app.Conf.SetConfigValue(constants.ConfigNodeConfigKey, flags)
+}
+
+func printFlagMigrations(changes map[string][]string) {
+	names := maps.Keys(changes)
+	sort.Strings(names)
+	for _, name := range names {
+		ux.Logger.PrintToUser("Migrated config flags of %s:", name)
+		for _, change := range changes[name] {
+			ux.Logger.PrintToUser("  %s", change)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/docker"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/remoteconfig"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/spf13/cobra"
+)
+
+type alertsFlags struct {
+	nodeDownMinutes     uint
+	diskUsagePercent    uint
+	chainStalledMinutes uint
+	minValidatorBalance float64
+	emailTo             string
+	emailFrom           string
+	smtpHost            string
+	smtpUsername        string
+	smtpPassword        string
+	slackWebhookURL     string
+	slackChannel        string
+	exportDir           string
+}
+
+var alertsCmdFlags alertsFlags
+
+func newAlertsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alerts [clusterName]",
+		Short: "(ALPHA Warning) Configure the alerts of the cluster monitoring",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node alerts command configures the Prometheus alerting rules of the cluster monitoring host,
+and the Alertmanager receivers that alerts are sent to. Alerts are raised when:
+- a node is down
+- a node disk is filling
+- a chain has blocks processing but none accepted
+- an L1 validator balance is low, so it is about to be removed
+
+Thresholds and receivers given are stored for the cluster, so only the changed ones need to be
+given on later calls. The SMTP password and Slack webhook URL are stored apart from the cluster
+config, in a file only readable by the user. They can instead be given on each call with the
+` + constants.AlertSMTPPasswordEnvVarName + ` and ` + constants.AlertSlackWebhookURLEnvVarName + `
+environment variables, that take precedence over the stored ones. The alerting configuration is then pushed to the monitoring host, or exported
+to --export-dir to be used on another Prometheus setup.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: alerts,
+	}
+	cmd.Flags().UintVar(&alertsCmdFlags.nodeDownMinutes, "node-down-minutes", remoteconfig.DefaultAlertNodeDownMinutes, "minutes a node must be unreachable before alerting")
+	cmd.Flags().UintVar(&alertsCmdFlags.diskUsagePercent, "disk-usage-percent", remoteconfig.DefaultAlertDiskUsagePercent, "disk usage percentage that triggers an alert")
+	cmd.Flags().UintVar(&alertsCmdFlags.chainStalledMinutes, "chain-stalled-minutes", remoteconfig.DefaultAlertChainStalledMinutes, "minutes without accepted blocks before alerting")
+	cmd.Flags().Float64Var(&alertsCmdFlags.minValidatorBalance, "min-validator-balance", float64(remoteconfig.DefaultAlertMinValidatorBalanceNAVAX)/float64(units.Avax), "L1 validator balance (in AVAX) below which to alert")
+	cmd.Flags().StringVar(&alertsCmdFlags.emailTo, "email-to", "", "send alerts to this email address")
+	cmd.Flags().StringVar(&alertsCmdFlags.emailFrom, "email-from", "", "sender address of the alert emails")
+	cmd.Flags().StringVar(&alertsCmdFlags.smtpHost, "smtp-host", "", "SMTP server used to send the alert emails, as host:port")
+	cmd.Flags().StringVar(&alertsCmdFlags.smtpUsername, "smtp-username", "", "SMTP server username")
+	cmd.Flags().StringVar(&alertsCmdFlags.smtpPassword, "smtp-password", "", fmt.Sprintf("SMTP server password (or set %s)", constants.AlertSMTPPasswordEnvVarName))
+	cmd.Flags().StringVar(&alertsCmdFlags.slackWebhookURL, "slack-webhook-url", "", fmt.Sprintf("send alerts to this Slack incoming webhook (or set %s)", constants.AlertSlackWebhookURLEnvVarName))
+	cmd.Flags().StringVar(&alertsCmdFlags.slackChannel, "slack-channel", "", "Slack channel to send the alerts to, if not the webhook default one")
+	cmd.Flags().StringVar(&alertsCmdFlags.exportDir, "export-dir", "", "write the alerting configuration to this directory instead of pushing it to the monitoring host")
+	return cobrautils.MarkClusterState(cmd)
+}
+
+func alerts(cmd *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if clusterConfig.Local {
+		return notImplementedForLocal("alerts")
+	}
+	alerting := clusterConfig.Alerting
+	if cmd.Flags().Changed("node-down-minutes") {
+		alerting.NodeDownMinutes = alertsCmdFlags.nodeDownMinutes
+	}
+	if cmd.Flags().Changed("disk-usage-percent") {
+		alerting.DiskUsagePercent = alertsCmdFlags.diskUsagePercent
+	}
+	if cmd.Flags().Changed("chain-stalled-minutes") {
+		alerting.ChainStalledMinutes = alertsCmdFlags.chainStalledMinutes
+	}
+	if cmd.Flags().Changed("min-validator-balance") {
+		alerting.MinValidatorBalance = alertsCmdFlags.minValidatorBalance
+	}
+	if cmd.Flags().Changed("email-to") {
+		alerting.Email.To = alertsCmdFlags.emailTo
+	}
+	if cmd.Flags().Changed("email-from") {
+		alerting.Email.From = alertsCmdFlags.emailFrom
+	}
+	if cmd.Flags().Changed("smtp-host") {
+		alerting.Email.SMTPHost = alertsCmdFlags.smtpHost
+	}
+	if cmd.Flags().Changed("smtp-username") {
+		alerting.Email.SMTPUsername = alertsCmdFlags.smtpUsername
+	}
+	if cmd.Flags().Changed("slack-channel") {
+		alerting.Slack.Channel = alertsCmdFlags.slackChannel
+	}
+	if err := validateAlertingConfig(alerting); err != nil {
+		return err
+	}
+	clusterConfig.Alerting = alerting
+	if err := app.SetClusterConfig(clusterName, clusterConfig); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("smtp-password") || cmd.Flags().Changed("slack-webhook-url") {
+		secrets, err := app.LoadClusterSecrets(clusterName)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("smtp-password") {
+			secrets.SMTPPassword = alertsCmdFlags.smtpPassword
+		}
+		if cmd.Flags().Changed("slack-webhook-url") {
+			secrets.SlackWebhookURL = alertsCmdFlags.slackWebhookURL
+		}
+		if err := app.WriteClusterSecrets(clusterName, secrets); err != nil {
+			return err
+		}
+	}
+	if alertsCmdFlags.exportDir != "" {
+		return exportAlertingConfig(clusterName, alertsCmdFlags.exportDir)
+	}
+	if clusterConfig.MonitoringInstance == "" {
+		return fmt.Errorf("cluster %s has no monitoring host. Use --export-dir to get the alerting configuration for another Prometheus setup", clusterName)
+	}
+	monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetMonitoringInventoryDir(clusterName))
+	if err != nil {
+		return err
+	}
+	if len(monitoringHosts) == 0 {
+		return fmt.Errorf("monitoring host for cluster %s not found", clusterName)
+	}
+	monitoringHost := monitoringHosts[0]
+	if err := monitoringHost.Connect(0); err != nil {
+		return err
+	}
+	defer node.DisconnectHosts([]*models.Host{monitoringHost})
+	spinSession := ux.NewUserSpinner()
+	spinner := spinSession.SpinToUser(utils.ScriptLog(monitoringHost.NodeID, "Setup Alerting"))
+	if err := setupAlerting(monitoringHost, clusterName); err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		spinSession.Stop()
+		return err
+	}
+	ux.SpinComplete(spinner)
+	spinSession.Stop()
+	ux.Logger.GreenCheckmarkToUser("Alerting configured for cluster %s", clusterName)
+	return nil
+}
+
+func validateAlertingConfig(alerting models.AlertingConfig) error {
+	if alerting.DiskUsagePercent > 100 {
+		return fmt.Errorf("disk usage percent must be at most 100")
+	}
+	if alerting.MinValidatorBalance < 0 {
+		return fmt.Errorf("min validator balance must not be negative")
+	}
+	email := alerting.Email
+	if email.To != "" && (email.From == "" || email.SMTPHost == "") {
+		return fmt.Errorf("email alerts need --email-from and --smtp-host")
+	}
+	return nil
+}
+
+// getAlertingInputs returns the settings to render the alerting configuration of the
+// cluster, using the defaults for the thresholds not set
+func getAlertingInputs(clusterName string) (
+	remoteconfig.AlertingRulesInputs,
+	remoteconfig.AlertmanagerInputs,
+	remoteconfig.ValidatorBalanceExporterInputs,
+	error,
+) {
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return remoteconfig.AlertingRulesInputs{}, remoteconfig.AlertmanagerInputs{}, remoteconfig.ValidatorBalanceExporterInputs{}, err
+	}
+	alerting := clusterConfig.Alerting
+	rulesInputs := remoteconfig.PrepareAlertingRulesInputs()
+	if alerting.NodeDownMinutes != 0 {
+		rulesInputs.NodeDownMinutes = alerting.NodeDownMinutes
+	}
+	if alerting.DiskUsagePercent != 0 {
+		rulesInputs.DiskUsagePercent = alerting.DiskUsagePercent
+	}
+	if alerting.ChainStalledMinutes != 0 {
+		rulesInputs.ChainStalledMinutes = alerting.ChainStalledMinutes
+	}
+	if alerting.MinValidatorBalance != 0 {
+		rulesInputs.MinValidatorBalanceNAVAX = uint64(alerting.MinValidatorBalance * float64(units.Avax))
+	}
+	secrets, err := app.LoadClusterSecrets(clusterName)
+	if err != nil {
+		return remoteconfig.AlertingRulesInputs{}, remoteconfig.AlertmanagerInputs{}, remoteconfig.ValidatorBalanceExporterInputs{}, err
+	}
+	if smtpPassword := os.Getenv(constants.AlertSMTPPasswordEnvVarName); smtpPassword != "" {
+		secrets.SMTPPassword = smtpPassword
+	}
+	if slackWebhookURL := os.Getenv(constants.AlertSlackWebhookURLEnvVarName); slackWebhookURL != "" {
+		secrets.SlackWebhookURL = slackWebhookURL
+	}
+	alertmanagerInputs := remoteconfig.AlertmanagerInputs{
+		EmailTo:         alerting.Email.To,
+		EmailFrom:       alerting.Email.From,
+		SMTPHost:        alerting.Email.SMTPHost,
+		SMTPUsername:    alerting.Email.SMTPUsername,
+		SMTPPassword:    secrets.SMTPPassword,
+		SlackWebhookURL: secrets.SlackWebhookURL,
+		SlackChannel:    alerting.Slack.Channel,
+	}
+	// validator balances are only kept for L1s
+	subnetIDs := []string{}
+	for _, blockchainName := range clusterConfig.Subnets {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			return remoteconfig.AlertingRulesInputs{}, remoteconfig.AlertmanagerInputs{}, remoteconfig.ValidatorBalanceExporterInputs{}, err
+		}
+		if !sc.Sovereign {
+			continue
+		}
+		if networkData, ok := sc.Networks[clusterConfig.Network.Name()]; ok && networkData.SubnetID != ids.Empty {
+			subnetIDs = append(subnetIDs, networkData.SubnetID.String())
+		}
+	}
+	exporterInputs := remoteconfig.PrepareValidatorBalanceExporterInputs(clusterConfig.Network.Endpoint, subnetIDs)
+	return rulesInputs, alertmanagerInputs, exporterInputs, nil
+}
+
+// setupAlerting pushes the alerting configuration of the cluster to its monitoring host,
+// and restarts the services using it
+func setupAlerting(monitoringHost *models.Host, clusterName string) error {
+	rulesInputs, alertmanagerInputs, exporterInputs, err := getAlertingInputs(clusterName)
+	if err != nil {
+		return err
+	}
+	if err := ssh.RunSSHSetupAlertingConfig(monitoringHost, rulesInputs, alertmanagerInputs, exporterInputs); err != nil {
+		return err
+	}
+	// also updates prometheus config and services of monitoring hosts setup before alerting was available
+	avalancheGoPorts, machinePorts, ltPorts, err := getPrometheusTargets(clusterName)
+	if err != nil {
+		return err
+	}
+	targetRegions, err := getPrometheusTargetRegions(clusterName)
+	if err != nil {
+		return err
+	}
+	if err := ssh.RunSSHSetupPrometheusConfig(monitoringHost, avalancheGoPorts, machinePorts, ltPorts, targetRegions); err != nil {
+		return err
+	}
+	if err := docker.ComposeSSHSetupMonitoring(monitoringHost); err != nil {
+		return err
+	}
+	for _, service := range []string{"prometheus", "alertmanager", "validator-balance-exporter"} {
+		if err := docker.RestartDockerComposeService(monitoringHost, utils.GetRemoteComposeFile(), service, constants.SSHLongRunningScriptTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportAlertingConfig(clusterName string, exportDir string) error {
+	rulesInputs, alertmanagerInputs, exporterInputs, err := getAlertingInputs(clusterName)
+	if err != nil {
+		return err
+	}
+	rules, err := remoteconfig.RenderPrometheusAlertingRules(rulesInputs)
+	if err != nil {
+		return err
+	}
+	alertmanagerConfig, err := remoteconfig.RenderAlertmanagerConfig(alertmanagerInputs)
+	if err != nil {
+		return err
+	}
+	exporterScript, err := remoteconfig.RenderValidatorBalanceExporterScript(exporterInputs)
+	if err != nil {
+		return err
+	}
+	exportDir = utils.ExpandHome(exportDir)
+	if err := os.MkdirAll(exportDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	for fileName, content := range map[string][]byte{
+		filepath.Base(remoteconfig.GetRemotePrometheusAlertingRules()):        rules,
+		filepath.Base(remoteconfig.GetRemoteAlertmanagerConfig()):             alertmanagerConfig,
+		filepath.Base(remoteconfig.GetRemoteValidatorBalanceExporterScript()): exporterScript,
+	} {
+		// alertmanager config may hold the SMTP password
+		if err := os.WriteFile(filepath.Join(exportDir, fileName), content, constants.WriteReadUserOnlyPerms); err != nil {
+			return err
+		}
+	}
+	ux.Logger.GreenCheckmarkToUser("Alerting configuration for cluster %s exported to %s", clusterName, exportDir)
+	return nil
+}
@@ -0,0 +1,762 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/remoteconfig"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	blueGreenDefaultSyncTimeout = 2 * time.Hour
+	blueGreenDefaultDNSTimeout  = 30 * time.Minute
+	blueGreenPoolTime           = 30 * time.Second
+)
+
+type blueGreenFlags struct {
+	greenCluster string
+	rollback     bool
+	keepBlue     bool
+	syncTimeout  time.Duration
+	dnsTimeout   time.Duration
+	certFile     string
+	keyFile      string
+}
+
+var blueGreenCmdFlags blueGreenFlags
+
+// avalanche node bluegreen
+func newBlueGreenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bluegreen [clusterName]",
+		Short: "(ALPHA Warning) Upgrade a cluster by replacing it with a new set of nodes",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node bluegreen command upgrades a Fuji or Mainnet cluster (blue) without downtime, by
+replacing it with a new cluster (green):
+
+  1. The green cluster is created with the new avalanchego version, mirroring the cloud,
+     regions and number of nodes of the blue one unless given otherwise.
+  2. The green nodes bootstrap the primary network and sync all the blockchains tracked by
+     the blue cluster, using the VM versions currently set on the blockchains.
+  3. RPC traffic is shifted to the green cluster. If the blue cluster serves its API over
+     HTTPS (see avalanche node ssl setup), the green nodes serve it at the same domains,
+     and the command waits for their DNS records to be updated to the green IPs. If it
+     is served by a load balancer, the blue load balancer forwards to the green nodes
+     at once, and the green cluster gets its own load balancer on its monitoring host,
+     that the domain DNS record is moved to. The blockchain RPC endpoints are updated
+     to the green ones.
+  4. The blue cluster is destroyed, unless --keep-blue is given or any of its nodes is a
+     validator of the primary network, or of the subnets and L1s of the cluster.
+
+If any step before the decommission fails, the deployment is rolled back: traffic is shifted
+back to the blue cluster and the green one is destroyed. A completed deployment with
+--keep-blue can be rolled back later with --rollback, or finished with avalanche node destroy.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: blueGreen,
+	}
+	cmd.Flags().StringVar(&blueGreenCmdFlags.greenCluster, "green-cluster", "", "name of the new cluster (defaults to <clusterName>-green)")
+	cmd.Flags().BoolVar(&blueGreenCmdFlags.rollback, "rollback", false, "shift traffic back to the cluster and destroy the one it was shifted to")
+	cmd.Flags().BoolVar(&blueGreenCmdFlags.keepBlue, "keep-blue", false, "keep the cluster after shifting traffic, to be able to roll back")
+	cmd.Flags().DurationVar(&blueGreenCmdFlags.syncTimeout, "sync-timeout", blueGreenDefaultSyncTimeout, "time to wait for the new nodes to bootstrap and sync")
+	cmd.Flags().DurationVar(&blueGreenCmdFlags.dnsTimeout, "dns-timeout", blueGreenDefaultDNSTimeout, "time to wait for the DNS records of the HTTPS domains to be updated")
+	cmd.Flags().StringVar(&blueGreenCmdFlags.certFile, "cert-file", "", "use the given PEM certificate (chain) for the HTTPS domains instead of Let's Encrypt")
+	cmd.Flags().StringVar(&blueGreenCmdFlags.keyFile, "key-file", "", "PEM private key of --cert-file")
+	cmd.Flags().BoolVar(&authorizeRemove, "authorize-remove", false, "authorize CLI to remove all local files related to the destroyed cluster")
+	cmd.Flags().BoolVar(&useStaticIP, "use-static-ip", true, "attach static Public IP on cloud servers")
+	cmd.Flags().BoolVar(&useAWS, "aws", false, "create the new nodes in AWS cloud")
+	cmd.Flags().BoolVar(&useGCP, "gcp", false, "create the new nodes in GCP cloud")
+	cmd.Flags().StringSliceVar(&cmdLineRegion, "region", []string{}, "create the new nodes in given region(s). Use comma to separate multiple regions")
+	cmd.Flags().IntSliceVar(&numValidatorsNodes, "num-validators", []int{}, "number of nodes to create per region(s). Use comma to separate multiple numbers for each region in the same order as --region flag")
+	cmd.Flags().IntSliceVar(&numAPINodes, "num-apis", []int{}, "number of API nodes(nodes without stake) to create per region(s)")
+	cmd.Flags().StringVar(&nodeType, "node-type", "", "cloud instance type. Use 'default' to use recommended default instance type")
+	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to create and release cloud resources")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	cmd.Flags().StringVar(&cmdLineGCPCredentialsPath, "gcp-credentials", "", "use given GCP credentials (service account key or workload identity federation config)")
+	cmd.Flags().StringVar(&cmdLineGCPProjectName, "gcp-project", "", "use given GCP project")
+	cmd.Flags().StringVar(&cmdLineGCPImpersonateServiceAccount, "gcp-impersonate-service-account", "", "impersonate given GCP service account (defaults to $"+constants.GCPImpersonateEnvVar+")")
+	cmd.Flags().StringVar(&cmdLineAlternativeKeyPairName, "alternative-key-pair-name", "", "key pair name to use if default one generates conflicts")
+	cmd.Flags().BoolVar(&replaceKeyPair, "auto-replace-keypair", false, "automatically replaces key pair to access node if previous key pair is not found")
+	cmd.Flags().BoolVar(&useSSHAgent, "use-ssh-agent", false, "use ssh agent(ex: Yubikey) for ssh auth")
+	cmd.Flags().StringVar(&sshIdentity, "ssh-agent-identity", "", "use given ssh identity(only for ssh agent). If not set, default will be used")
+	cmd.Flags().BoolVar(&useLatestAvalanchegoReleaseVersion, "latest-avalanchego-version", false, "install latest avalanchego release version on the new nodes")
+	cmd.Flags().BoolVar(&useLatestAvalanchegoPreReleaseVersion, "latest-avalanchego-pre-release-version", false, "install latest avalanchego pre-release version on the new nodes")
+	cmd.Flags().StringVar(&useCustomAvalanchegoVersion, "custom-avalanchego-version", "", "install given avalanchego version on the new nodes")
+	cmd.Flags().StringVar(&useAvalanchegoVersionFromSubnet, "avalanchego-version-from-subnet", "", "install latest avalanchego version, that is compatible with the given subnet, on the new nodes")
+	cmd.Flags().StringVar(&provisioningMode, "provisioning", constants.DockerProvisioning, "how avalanchego is installed on the new nodes: docker (docker compose service) or binary (native service, no docker required)")
+	cmd.Flags().BoolVar(&addMonitoring, enableMonitoringFlag, false, "set up monitoring on the new cluster (required if the cluster HTTPS endpoint is served by a load balancer)")
+	return cobrautils.MarkClusterState(cmd)
+}
+
+// blueGreenUndo keeps the steps that revert a blue/green deployment failing midway
+type blueGreenUndo []func() error
+
+func (u *blueGreenUndo) add(step func() error) {
+	*u = append(*u, step)
+}
+
+// run executes the undo steps in reverse order. A failing step is reported and the next
+// ones still executed, to revert as much as possible
+func (u blueGreenUndo) run() {
+	for i := len(u) - 1; i >= 0; i-- {
+		if err := u[i](); err != nil {
+			ux.Logger.RedXToUser("failure rolling back: %s", err)
+		}
+	}
+}
+
+func blueGreen(cmd *cobra.Command, args []string) error {
+	blueCluster := args[0]
+	if err := node.CheckCluster(app, blueCluster); err != nil {
+		return err
+	}
+	blueConfig, err := app.GetClusterConfig(blueCluster)
+	if err != nil {
+		return err
+	}
+	if blueConfig.Local {
+		return notImplementedForLocal("bluegreen")
+	}
+	if blueConfig.External {
+		return fmt.Errorf("cannot replace the nodes of external cluster %s", blueCluster)
+	}
+	if blueGreenCmdFlags.rollback {
+		return blueGreenRollback(blueCluster, blueConfig)
+	}
+	if blueConfig.BlueGreenCluster != "" {
+		return fmt.Errorf(
+			"traffic of cluster %s was already shifted to cluster %s. Roll it back with --rollback, or finish with avalanche node destroy %s",
+			blueCluster,
+			blueConfig.BlueGreenCluster,
+			blueCluster,
+		)
+	}
+	switch blueConfig.Network.Kind {
+	case models.Fuji:
+		globalNetworkFlags.UseFuji = true
+	case models.Mainnet:
+		globalNetworkFlags.UseMainnet = true
+	default:
+		return fmt.Errorf("blue/green deployment is only supported for Fuji and Mainnet clusters, but %s is on %s", blueCluster, blueConfig.Network.Name())
+	}
+	greenCluster := blueGreenCmdFlags.greenCluster
+	if greenCluster == "" {
+		greenCluster = blueCluster + "-green"
+	}
+	if exists, err := app.ClusterExists(greenCluster); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("cluster %s already exists. Use --green-cluster to give another name", greenCluster)
+	}
+	userCert := blueGreenCmdFlags.certFile != "" || blueGreenCmdFlags.keyFile != ""
+	if userCert {
+		if blueGreenCmdFlags.certFile == "" || blueGreenCmdFlags.keyFile == "" {
+			return fmt.Errorf("--cert-file and --key-file must be given together")
+		}
+		for _, path := range []string{blueGreenCmdFlags.certFile, blueGreenCmdFlags.keyFile} {
+			if !utils.FileExists(utils.ExpandHome(path)) {
+				return fmt.Errorf("file %s not found", path)
+			}
+		}
+	}
+	if blueConfig.HTTPSLoadBalancer != "" {
+		// the green load balancer runs on the green monitoring host
+		if (cmd.Flags().Changed(enableMonitoringFlag) && !addMonitoring) || provisioningMode == constants.BinaryProvisioning {
+			return fmt.Errorf("cluster %s serves its HTTPS endpoint from a load balancer, that needs --%s and --provisioning %s on the green cluster", blueCluster, enableMonitoringFlag, constants.DockerProvisioning)
+		}
+		if err := cmd.Flags().Set(enableMonitoringFlag, "true"); err != nil {
+			return err
+		}
+	}
+	blueHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(blueCluster))
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts(blueHosts)
+	if err := node.CheckManagedLayout(app, blueHosts, "node bluegreen"); err != nil {
+		return err
+	}
+	if err := setBlueGreenCreateParams(blueConfig); err != nil {
+		return err
+	}
+
+	undo := blueGreenUndo{}
+	if err := shiftToGreen(cmd, blueCluster, blueConfig, blueHosts, greenCluster, userCert, &undo); err != nil {
+		ux.Logger.PrintToUser("")
+		ux.Logger.RedXToUser("blue/green deployment failed: %s", err)
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Rolling back to cluster %s"), blueCluster)
+		undo.run()
+		return err
+	}
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(logging.Green.Wrap("Traffic of cluster %s shifted to cluster %s"), blueCluster, greenCluster)
+	if blueGreenCmdFlags.keepBlue {
+		ux.Logger.PrintToUser("Cluster %s is kept. Roll back with avalanche node bluegreen %s --rollback,", blueCluster, blueCluster)
+		ux.Logger.PrintToUser("or decommission it with avalanche node destroy %s", blueCluster)
+		return nil
+	}
+	validators, err := getValidatorHosts(blueConfig, blueHosts)
+	if err != nil {
+		return err
+	}
+	if len(validators) > 0 {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Cluster %s is kept, as some of its nodes are validators:"), blueCluster)
+		for _, validator := range validators {
+			ux.Logger.PrintToUser("  %s", validator)
+		}
+		ux.Logger.PrintToUser("Decommission it with avalanche node destroy %s once their validation ends", blueCluster)
+		return nil
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(logging.Green.Wrap("Decommissioning cluster %s"), blueCluster)
+	return destroyNodes(nil, []string{blueCluster})
+}
+
+// shiftToGreen creates [greenCluster], syncs it with the blockchains of [blueCluster] and
+// shifts the traffic to it, registering on [undo] how to revert each step
+func shiftToGreen(
+	cmd *cobra.Command,
+	blueCluster string,
+	blueConfig models.ClusterConfig,
+	blueHosts []*models.Host,
+	greenCluster string,
+	userCert bool,
+	undo *blueGreenUndo,
+) error {
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(logging.Green.Wrap("Creating cluster %s"), greenCluster)
+	ux.Logger.PrintToUser("")
+	undo.add(func() error {
+		if exists, err := app.ClusterExists(greenCluster); err != nil || !exists {
+			return err
+		}
+		return CallDestroyNode(greenCluster)
+	})
+	if err := createNodes(cmd, []string{greenCluster}); err != nil {
+		return err
+	}
+	if err := waitForBootstrappedCluster(greenCluster, blueGreenCmdFlags.syncTimeout); err != nil {
+		return err
+	}
+
+	if len(blueConfig.Subnets) > 0 {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser(logging.Green.Wrap("Syncing cluster %s with blockchains %s"), greenCluster, strings.Join(blueConfig.Subnets, ", "))
+		ux.Logger.PrintToUser("")
+		undo.add(func() error {
+			return node.ShiftBlockchainEndpoints(app, greenCluster, blueCluster)
+		})
+		for _, blockchainName := range blueConfig.Subnets {
+			if err := node.SyncSubnet(app, greenCluster, blockchainName, false, nil); err != nil {
+				return err
+			}
+		}
+		if err := node.WaitForHealthyCluster(app, greenCluster, blueGreenCmdFlags.syncTimeout, blueGreenPoolTime); err != nil {
+			return err
+		}
+	}
+
+	if len(blueConfig.HTTPSEndpoints) > 0 {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser(logging.Green.Wrap("Moving HTTPS domains to cluster %s"), greenCluster)
+		ux.Logger.PrintToUser("")
+		moveDomains := moveHTTPSDomains
+		if blueConfig.HTTPSLoadBalancer != "" {
+			moveDomains = moveHTTPSLoadBalancer
+		}
+		if err := moveDomains(blueCluster, blueConfig, blueHosts, greenCluster, userCert, undo); err != nil {
+			return err
+		}
+	}
+
+	if err := node.ShiftBlockchainEndpoints(app, blueCluster, greenCluster); err != nil {
+		return err
+	}
+	blueConfig.BlueGreenCluster = greenCluster
+	return app.SetClusterConfig(blueCluster, blueConfig)
+}
+
+// moveHTTPSDomains serves the HTTPS domains of the blue cluster from the nodes of
+// [greenCluster], waiting for their DNS records to point to the green nodes. With a user
+// certificate the green proxies are set up before the DNS update, so there is no downtime.
+// Let's Encrypt requires the DNS update to come first
+func moveHTTPSDomains(
+	blueCluster string,
+	blueConfig models.ClusterConfig,
+	blueHosts []*models.Host,
+	greenCluster string,
+	userCert bool,
+	undo *blueGreenUndo,
+) error {
+	greenConfig, err := app.GetClusterConfig(greenCluster)
+	if err != nil {
+		return err
+	}
+	authTokens, err := node.GetRPCAuthTokens(app, blueCluster, blueConfig)
+	if err != nil {
+		return err
+	}
+	if err := node.SetRPCAuthTokens(app, greenCluster, authTokens); err != nil {
+		return err
+	}
+	allGreenHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(greenCluster))
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts(allGreenHosts)
+	blueSSLHosts := utils.Filter(getSSLHosts(blueConfig, blueHosts), func(h *models.Host) bool {
+		_, ok := blueConfig.HTTPSEndpoints[h.GetCloudID()]
+		return ok
+	})
+	domains := utils.Map(blueSSLHosts, func(h *models.Host) string {
+		return strings.TrimPrefix(blueConfig.HTTPSEndpoints[h.GetCloudID()], node.GetHTTPSEndpoint(""))
+	})
+	greenHosts := getSSLHosts(greenConfig, allGreenHosts)
+	if len(greenHosts) < len(domains) {
+		return fmt.Errorf("cluster %s has %d nodes to serve the %d HTTPS domains of the blue cluster", greenCluster, len(greenHosts), len(domains))
+	}
+	greenHosts = greenHosts[:len(domains)]
+	if err := openHTTPSPorts(greenCluster); err != nil {
+		return err
+	}
+	proxies := []reverseProxy{}
+	for i, host := range greenHosts {
+		proxies = append(proxies, reverseProxy{host: host, domain: domains[i]})
+	}
+	setupProxies := func() error {
+		return setupReverseProxies(
+			authTokens,
+			proxies,
+			blueConfig.HTTPSEmail,
+			userCert,
+			utils.ExpandHome(blueGreenCmdFlags.certFile),
+			utils.ExpandHome(blueGreenCmdFlags.keyFile),
+		)
+	}
+	if userCert {
+		if err := setupProxies(); err != nil {
+			return err
+		}
+	}
+	undo.add(func() error {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("DNS records are not managed by the CLI. Point them back to the blue cluster if already updated"))
+		printDNSUpdates(domains, greenHosts, blueSSLHosts)
+		return nil
+	})
+	printDNSUpdates(domains, blueSSLHosts, greenHosts)
+	if err := waitForDNSUpdates(domains, greenHosts, blueGreenCmdFlags.dnsTimeout); err != nil {
+		return err
+	}
+	if !userCert {
+		if err := setupProxies(); err != nil {
+			return err
+		}
+	}
+	httpsEndpoints := map[string]string{}
+	for i, host := range greenHosts {
+		httpsEndpoints[host.GetCloudID()] = node.GetHTTPSEndpoint(domains[i])
+	}
+	return node.SetClusterHTTPSEndpoints(app, greenCluster, greenHosts, httpsEndpoints, blueConfig.HTTPSEmail, "")
+}
+
+// moveHTTPSLoadBalancer serves the HTTPS domain of the load balancer of the blue cluster from
+// a load balancer on the monitoring host of [greenCluster]. The blue load balancer forwards
+// to the green nodes first, so traffic is shifted while the DNS record is being updated
+func moveHTTPSLoadBalancer(
+	blueCluster string,
+	blueConfig models.ClusterConfig,
+	blueHosts []*models.Host,
+	greenCluster string,
+	userCert bool,
+	undo *blueGreenUndo,
+) error {
+	greenConfig, err := app.GetClusterConfig(greenCluster)
+	if err != nil {
+		return err
+	}
+	authTokens, err := node.GetRPCAuthTokens(app, blueCluster, blueConfig)
+	if err != nil {
+		return err
+	}
+	if err := node.SetRPCAuthTokens(app, greenCluster, authTokens); err != nil {
+		return err
+	}
+	allGreenHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(greenCluster))
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts(allGreenHosts)
+	blueSSLHosts := utils.Filter(getSSLHosts(blueConfig, blueHosts), func(h *models.Host) bool {
+		_, ok := blueConfig.HTTPSEndpoints[h.GetCloudID()]
+		return ok
+	})
+	if len(blueSSLHosts) == 0 {
+		return fmt.Errorf("no node of cluster %s is served by its load balancer", blueCluster)
+	}
+	domain := strings.TrimPrefix(blueConfig.HTTPSEndpoints[blueSSLHosts[0].GetCloudID()], node.GetHTTPSEndpoint(""))
+	greenHosts := getSSLHosts(greenConfig, allGreenHosts)
+	blueLBHost, err := getLoadBalancerHost(blueCluster, blueConfig)
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts([]*models.Host{blueLBHost})
+	greenLBHost, err := getLoadBalancerHost(greenCluster, greenConfig)
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts([]*models.Host{greenLBHost})
+
+	ux.Logger.PrintToUser("Forwarding the load balancer of cluster %s to the nodes of cluster %s", blueCluster, greenCluster)
+	undo.add(func() error {
+		return setLoadBalancerUpstreams(blueLBHost, domain, blueConfig.HTTPSEmail, authTokens, blueSSLHosts)
+	})
+	if err := setLoadBalancerUpstreams(blueLBHost, domain, blueConfig.HTTPSEmail, authTokens, greenHosts); err != nil {
+		return err
+	}
+
+	if err := openHTTPSPorts(greenCluster); err != nil {
+		return err
+	}
+	proxies := []reverseProxy{{
+		host:        greenLBHost,
+		domain:      domain,
+		upstreamIPs: utils.Map(greenHosts, func(h *models.Host) string { return h.IP }),
+	}}
+	setupProxies := func() error {
+		return setupReverseProxies(
+			authTokens,
+			proxies,
+			blueConfig.HTTPSEmail,
+			userCert,
+			utils.ExpandHome(blueGreenCmdFlags.certFile),
+			utils.ExpandHome(blueGreenCmdFlags.keyFile),
+		)
+	}
+	if userCert {
+		if err := setupProxies(); err != nil {
+			return err
+		}
+	}
+	undo.add(func() error {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("DNS records are not managed by the CLI. Point them back to the blue cluster if already updated"))
+		printDNSUpdates([]string{domain}, []*models.Host{greenLBHost}, []*models.Host{blueLBHost})
+		return nil
+	})
+	printDNSUpdates([]string{domain}, []*models.Host{blueLBHost}, []*models.Host{greenLBHost})
+	if err := waitForDNSUpdates([]string{domain}, []*models.Host{greenLBHost}, blueGreenCmdFlags.dnsTimeout); err != nil {
+		return err
+	}
+	if !userCert {
+		if err := setupProxies(); err != nil {
+			return err
+		}
+	}
+	httpsEndpoints := map[string]string{}
+	for _, host := range greenHosts {
+		httpsEndpoints[host.GetCloudID()] = node.GetHTTPSEndpoint(domain)
+	}
+	return node.SetClusterHTTPSEndpoints(app, greenCluster, greenHosts, httpsEndpoints, blueConfig.HTTPSEmail, greenLBHost.GetCloudID())
+}
+
+// setLoadBalancerUpstreams reconfigures the load balancer on [lbHost], serving [domain], to
+// forward to [hosts]. Its certificate is kept
+func setLoadBalancerUpstreams(
+	lbHost *models.Host,
+	domain string,
+	email string,
+	authTokens map[string]string,
+	hosts []*models.Host,
+) error {
+	userCert, err := ssh.ReverseProxyHasUserCert(lbHost)
+	if err != nil {
+		return err
+	}
+	inputs := remoteconfig.PrepareReverseProxyInputs(domain, email, userCert, utils.Map(hosts, func(h *models.Host) string { return h.IP }), authTokens)
+	if err := ssh.RunSSHSetupReverseProxy(lbHost, inputs, "", ""); err != nil {
+		return err
+	}
+	return waitForHTTPSEndpoint(node.GetHTTPSEndpoint(domain))
+}
+
+// blueGreenRollback shifts the traffic back to [blueCluster] from the cluster it was shifted
+// to, and destroys the latter
+func blueGreenRollback(blueCluster string, blueConfig models.ClusterConfig) error {
+	greenCluster := blueConfig.BlueGreenCluster
+	if greenCluster == "" {
+		return fmt.Errorf("traffic of cluster %s was not shifted to another cluster", blueCluster)
+	}
+	if err := node.CheckCluster(app, greenCluster); err != nil {
+		return err
+	}
+	greenConfig, err := app.GetClusterConfig(greenCluster)
+	if err != nil {
+		return err
+	}
+	if greenConfig.HTTPSLoadBalancer != "" && blueConfig.HTTPSLoadBalancer != "" {
+		if err := rollbackHTTPSLoadBalancer(blueCluster, blueConfig, greenCluster, greenConfig); err != nil {
+			return err
+		}
+	} else if len(greenConfig.HTTPSEndpoints) > 0 {
+		blueHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(blueCluster))
+		if err != nil {
+			return err
+		}
+		defer node.DisconnectHosts(blueHosts)
+		greenHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(greenCluster))
+		if err != nil {
+			return err
+		}
+		defer node.DisconnectHosts(greenHosts)
+		domains, fromHosts, toHosts := []string{}, []*models.Host{}, []*models.Host{}
+		for _, greenHost := range greenHosts {
+			greenEndpoint, ok := greenConfig.HTTPSEndpoints[greenHost.GetCloudID()]
+			if !ok {
+				continue
+			}
+			blueHostIndex := slices.IndexFunc(blueHosts, func(h *models.Host) bool {
+				return blueConfig.HTTPSEndpoints[h.GetCloudID()] == greenEndpoint
+			})
+			if blueHostIndex < 0 {
+				continue
+			}
+			domains = append(domains, strings.TrimPrefix(greenEndpoint, node.GetHTTPSEndpoint("")))
+			fromHosts = append(fromHosts, greenHost)
+			toHosts = append(toHosts, blueHosts[blueHostIndex])
+		}
+		if len(domains) > 0 {
+			printDNSUpdates(domains, fromHosts, toHosts)
+			if err := waitForDNSUpdates(domains, toHosts, blueGreenCmdFlags.dnsTimeout); err != nil {
+				return err
+			}
+		}
+	}
+	if err := node.ShiftBlockchainEndpoints(app, greenCluster, blueCluster); err != nil {
+		return err
+	}
+	blueConfig.BlueGreenCluster = ""
+	if err := app.SetClusterConfig(blueCluster, blueConfig); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Traffic shifted back to cluster %s", blueCluster)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser(logging.Green.Wrap("Destroying cluster %s"), greenCluster)
+	return destroyNodes(nil, []string{greenCluster})
+}
+
+// rollbackHTTPSLoadBalancer forwards the load balancer of [blueCluster] back to its nodes, and
+// waits for the DNS record of its domain to point back to it
+func rollbackHTTPSLoadBalancer(
+	blueCluster string,
+	blueConfig models.ClusterConfig,
+	greenCluster string,
+	greenConfig models.ClusterConfig,
+) error {
+	blueHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(blueCluster))
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts(blueHosts)
+	blueSSLHosts := utils.Filter(getSSLHosts(blueConfig, blueHosts), func(h *models.Host) bool {
+		_, ok := blueConfig.HTTPSEndpoints[h.GetCloudID()]
+		return ok
+	})
+	if len(blueSSLHosts) == 0 {
+		return fmt.Errorf("no node of cluster %s is served by its load balancer", blueCluster)
+	}
+	domain := strings.TrimPrefix(blueConfig.HTTPSEndpoints[blueSSLHosts[0].GetCloudID()], node.GetHTTPSEndpoint(""))
+	blueLBHost, err := getLoadBalancerHost(blueCluster, blueConfig)
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts([]*models.Host{blueLBHost})
+	greenLBHost, err := getLoadBalancerHost(greenCluster, greenConfig)
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts([]*models.Host{greenLBHost})
+	authTokens, err := node.GetRPCAuthTokens(app, blueCluster, blueConfig)
+	if err != nil {
+		return err
+	}
+	if err := setLoadBalancerUpstreams(blueLBHost, domain, blueConfig.HTTPSEmail, authTokens, blueSSLHosts); err != nil {
+		return err
+	}
+	printDNSUpdates([]string{domain}, []*models.Host{greenLBHost}, []*models.Host{blueLBHost})
+	return waitForDNSUpdates([]string{domain}, []*models.Host{blueLBHost}, blueGreenCmdFlags.dnsTimeout)
+}
+
+// setBlueGreenCreateParams sets the node create params not given by the user to mirror the
+// blue cluster: cloud, regions and number of validator and API nodes per region. The
+// avalanchego version defaults to the latest one compatible with the blockchains tracked
+func setBlueGreenCreateParams(blueConfig models.ClusterConfig) error {
+	if len(useCustomAvalanchegoVersion) == 0 && useAvalanchegoVersionFromSubnet == "" &&
+		!useLatestAvalanchegoReleaseVersion && !useLatestAvalanchegoPreReleaseVersion {
+		if len(blueConfig.Subnets) > 0 {
+			useAvalanchegoVersionFromSubnet = blueConfig.Subnets[0]
+		} else {
+			useLatestAvalanchegoReleaseVersion = true
+		}
+	}
+	if len(cmdLineRegion) > 0 || len(numValidatorsNodes) > 0 {
+		return nil
+	}
+	regions := []string{}
+	validatorsPerRegion := map[string]int{}
+	apisPerRegion := map[string]int{}
+	for _, cloudID := range blueConfig.Nodes {
+		nodeConfig, err := app.LoadClusterNodeConfig(cloudID)
+		if err != nil {
+			return err
+		}
+		if !useAWS && !useGCP {
+			useAWS = nodeConfig.CloudService == "" || nodeConfig.CloudService == constants.AWSCloudService
+			useGCP = nodeConfig.CloudService == constants.GCPCloudService
+		}
+		if !slices.Contains(regions, nodeConfig.Region) {
+			regions = append(regions, nodeConfig.Region)
+		}
+		if blueConfig.IsAPIHost(cloudID) {
+			apisPerRegion[nodeConfig.Region]++
+		} else {
+			validatorsPerRegion[nodeConfig.Region]++
+		}
+	}
+	cmdLineRegion = regions
+	numValidatorsNodes = utils.Map(regions, func(region string) int { return validatorsPerRegion[region] })
+	if len(blueConfig.APINodes) > 0 && len(numAPINodes) == 0 {
+		numAPINodes = utils.Map(regions, func(region string) int { return apisPerRegion[region] })
+	}
+	return nil
+}
+
+// waitForBootstrappedCluster waits until all the nodes of [clusterName] bootstrapped the
+// primary network
+func waitForBootstrappedCluster(clusterName string, timeout time.Duration) error {
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts(hosts)
+	startTime := time.Now()
+	spinSession := ux.NewUserSpinner()
+	spinner := spinSession.SpinToUser("Waiting for node(s) to bootstrap the primary network...")
+	defer spinSession.Stop()
+	for {
+		notBootstrapped, err := node.GetNotBootstrappedNodes(hosts)
+		if err == nil && len(notBootstrapped) == 0 {
+			ux.SpinComplete(spinner)
+			return nil
+		}
+		if time.Since(startTime) > timeout {
+			if err == nil {
+				err = fmt.Errorf("node(s) %s not bootstrapped after %s", notBootstrapped, timeout)
+			}
+			ux.SpinFailWithError(spinner, "", err)
+			return err
+		}
+		time.Sleep(blueGreenPoolTime)
+	}
+}
+
+// printDNSUpdates shows the DNS records to update for [domains] to move from [fromHosts]
+// to [toHosts]
+func printDNSUpdates(domains []string, fromHosts []*models.Host, toHosts []*models.Host) {
+	ux.Logger.PrintToUser("Update the DNS records of the HTTPS domains:")
+	for i, domain := range domains {
+		ux.Logger.PrintToUser("  %s: %s -> %s", domain, fromHosts[i].IP, logging.Green.Wrap(toHosts[i].IP))
+	}
+}
+
+// waitForDNSUpdates waits until each of [domains] resolves to the IP of the host at the same
+// index of [hosts]
+func waitForDNSUpdates(domains []string, hosts []*models.Host, timeout time.Duration) error {
+	startTime := time.Now()
+	spinSession := ux.NewUserSpinner()
+	spinner := spinSession.SpinToUser("Waiting for the DNS records to be updated...")
+	defer spinSession.Stop()
+	for {
+		pending := []string{}
+		for i, domain := range domains {
+			if ips, err := net.LookupHost(domain); err != nil || !slices.Contains(ips, hosts[i].IP) {
+				pending = append(pending, domain)
+			}
+		}
+		if len(pending) == 0 {
+			ux.SpinComplete(spinner)
+			return nil
+		}
+		if time.Since(startTime) > timeout {
+			err := fmt.Errorf("DNS records of %s not updated after %s", strings.Join(pending, ", "), timeout)
+			ux.SpinFailWithError(spinner, "", err)
+			return err
+		}
+		time.Sleep(blueGreenPoolTime)
+	}
+}
+
+// getValidatorHosts describes the avalanchego [hosts] of [clusterConfig] that are validators
+// of the primary network, or of the subnets and L1s of the cluster blockchains, eg:
+// "NodeID-xxx: primary network, L1 chain1"
+func getValidatorHosts(clusterConfig models.ClusterConfig, hosts []*models.Host) ([]string, error) {
+	validatedSubnets := map[ids.ID]string{ids.Empty: "primary network"}
+	for _, blockchainName := range clusterConfig.Subnets {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			return nil, err
+		}
+		subnetID := sc.Networks[clusterConfig.Network.Name()].SubnetID
+		if subnetID == ids.Empty {
+			continue
+		}
+		if sc.Sovereign {
+			validatedSubnets[subnetID] = "L1 " + blockchainName
+		} else {
+			validatedSubnets[subnetID] = "subnet " + blockchainName
+		}
+	}
+	subnetIDs := maps.Keys(validatedSubnets)
+	slices.SortFunc(subnetIDs, func(a, b ids.ID) int { return a.Compare(b) })
+	validators := []string{}
+	for _, host := range hosts {
+		if !clusterConfig.IsAvalancheGoHost(host.GetCloudID()) {
+			continue
+		}
+		nodeID, err := getNodeID(app.GetNodeInstanceDirPath(host.GetCloudID()))
+		if err != nil {
+			return nil, err
+		}
+		validated := []string{}
+		for _, subnetID := range subnetIDs {
+			isValidator, err := subnet.IsSubnetValidator(subnetID, nodeID, clusterConfig.Network)
+			if err != nil {
+				return nil, err
+			}
+			if isValidator {
+				validated = append(validated, validatedSubnets[subnetID])
+			}
+		}
+		if len(validated) > 0 {
+			validators = append(validators, fmt.Sprintf("%s: %s", nodeID, strings.Join(validated, ", ")))
+		}
+	}
+	return validators, nil
+}
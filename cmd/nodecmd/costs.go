@@ -0,0 +1,241 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"sort"
+
+	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
+	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/gcp"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/net/context"
+)
+
+var costsClusterName string
+
+func newCostsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "costs",
+		Short: "(ALPHA Warning) Estimate the monthly cloud spend of clusters",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node costs command queries the cloud service for the current setup of every instance
+of a cluster (instance type, running state, attached disks and public IP), including
+monitoring and load test hosts, and estimates its monthly spend, per instance and per cluster.
+
+If --cluster is not given, all cloud clusters are reported.
+
+Estimates use the on demand list prices of the region of each instance, from the AWS price
+list API and the GCP cloud billing catalog. If those can't be queried, approximate prices of
+a reference region (us-east-1 for AWS, us-central1 for GCP) are used, and the instance is
+marked as approximate. Estimates do not account for discounts, data transfer or snapshots,
+so check the cloud billing console for the actual spend.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: costs,
+	}
+	cmd.Flags().StringVar(&costsClusterName, "cluster", "", "report costs of the given cluster only")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+// clusterInstanceCost is the estimated cost of a cluster instance
+type clusterInstanceCost struct {
+	instanceID string
+	role       string
+	cloud      string
+	region     string
+	cost       models.CloudInstanceCost
+}
+
+func costs(_ *cobra.Command, _ []string) error {
+	clustersConfig, err := app.GetClustersConfig()
+	if err != nil {
+		return err
+	}
+	clusterNames := []string{costsClusterName}
+	if costsClusterName == "" {
+		clusterNames = maps.Keys(clustersConfig.Clusters)
+		sort.Strings(clusterNames)
+	} else if _, ok := clustersConfig.Clusters[costsClusterName]; !ok {
+		return fmt.Errorf("cluster %q does not exist", costsClusterName)
+	}
+	awsClouds := map[string]*awsAPI.AwsCloud{}
+	var gcpCloud *gcpAPI.GcpCloud
+	var gcpPriceCatalog *gcpAPI.PriceCatalog
+	getInstanceCost := func(nodeConfig models.NodeConfig) (models.CloudInstanceCost, error) {
+		switch nodeConfig.CloudService {
+		case "", constants.AWSCloudService:
+			if _, ok := awsClouds[nodeConfig.Region]; !ok {
+				awsCloud, err := awsAPI.NewAwsCloud(awsProfile, nodeConfig.Region)
+				if err != nil {
+					return models.CloudInstanceCost{}, err
+				}
+				awsClouds[nodeConfig.Region] = awsCloud
+			}
+			return awsClouds[nodeConfig.Region].GetInstanceMonthlyCost(nodeConfig.NodeID)
+		case constants.GCPCloudService:
+			if gcpCloud == nil {
+				gcpClient, projectName, auth, err := getGCPCloudCredentials()
+				if err != nil {
+					return models.CloudInstanceCost{}, err
+				}
+				if gcpCloud, err = gcpAPI.NewGcpCloud(gcpClient, projectName, context.Background()); err != nil {
+					return models.CloudInstanceCost{}, err
+				}
+				if gcpPriceCatalog, err = getGCPPriceCatalog(auth); err != nil {
+					ux.Logger.Info("using approximate GCP prices: %s", err)
+				}
+			}
+			return gcpCloud.GetInstanceMonthlyCost(gcpPriceCatalog, nodeConfig.Region, nodeConfig.NodeID)
+		default:
+			return models.CloudInstanceCost{}, fmt.Errorf("cloud service %s is not supported", nodeConfig.CloudService)
+		}
+	}
+	reported := false
+	grandTotal := 0.0
+	for _, clusterName := range clusterNames {
+		clusterConfig := clustersConfig.Clusters[clusterName]
+		if clusterConfig.Local || clusterConfig.External {
+			if costsClusterName != "" {
+				return fmt.Errorf("cluster %s is not hosted by Avalanche-CLI on a cloud service", clusterName)
+			}
+			continue
+		}
+		instanceCosts := []clusterInstanceCost{}
+		spinSession := ux.NewUserSpinner()
+		for _, instanceID := range getClusterInstanceIDs(clusterConfig) {
+			spinner := spinSession.SpinToUser(utils.ScriptLog(instanceID, "Querying instance setup"))
+			if !utils.FileExists(app.GetNodeConfigPath(instanceID)) {
+				ux.SpinFailWithError(spinner, "", fmt.Errorf("node config not found"))
+				continue
+			}
+			nodeConfig, err := app.LoadClusterNodeConfig(instanceID)
+			if err != nil {
+				spinSession.Stop()
+				return err
+			}
+			cost, err := getInstanceCost(nodeConfig)
+			if err != nil {
+				if isExpiredCredentialError(err) {
+					spinSession.Stop()
+					printExpiredCredentialsOutput(awsProfile)
+					return err
+				}
+				ux.SpinFailWithError(spinner, "", err)
+				continue
+			}
+			ux.SpinComplete(spinner)
+			instanceCosts = append(instanceCosts, clusterInstanceCost{
+				instanceID: instanceID,
+				role:       getInstanceRole(clusterConfig, nodeConfig),
+				cloud:      nodeConfig.CloudService,
+				region:     nodeConfig.Region,
+				cost:       cost,
+			})
+		}
+		spinSession.Stop()
+		grandTotal += printClusterCosts(clusterName, instanceCosts)
+		reported = true
+	}
+	if !reported {
+		ux.Logger.PrintToUser("No cloud clusters found")
+		return nil
+	}
+	if len(clusterNames) > 1 {
+		ux.Logger.PrintToUser("Estimated monthly total of all clusters: %s", formatMonthlyCost(grandTotal))
+	}
+	ux.Logger.PrintToUser("Estimates use on demand list prices. Check the cloud billing console for the actual spend")
+	return nil
+}
+
+// getClusterInstanceIDs returns the IDs of all cloud instances of a cluster, including
+// monitoring and load test hosts
+func getClusterInstanceIDs(clusterConfig models.ClusterConfig) []string {
+	instanceIDs := append([]string{}, clusterConfig.Nodes...)
+	if clusterConfig.MonitoringInstance != "" && !slices.Contains(instanceIDs, clusterConfig.MonitoringInstance) {
+		instanceIDs = append(instanceIDs, clusterConfig.MonitoringInstance)
+	}
+	loadTestNames := maps.Keys(clusterConfig.LoadTestInstance)
+	sort.Strings(loadTestNames)
+	for _, loadTestName := range loadTestNames {
+		if instanceID := clusterConfig.LoadTestInstance[loadTestName]; !slices.Contains(instanceIDs, instanceID) {
+			instanceIDs = append(instanceIDs, instanceID)
+		}
+	}
+	return instanceIDs
+}
+
+func getInstanceRole(clusterConfig models.ClusterConfig, nodeConfig models.NodeConfig) string {
+	role := "validator"
+	switch {
+	case nodeConfig.IsMonitor || nodeConfig.NodeID == clusterConfig.MonitoringInstance:
+		role = "monitoring"
+	case nodeConfig.IsLoadTest:
+		role = "load test"
+	case slices.Contains(clusterConfig.APINodes, nodeConfig.NodeID):
+		role = "API"
+	}
+	if nodeConfig.IsICMRelayer {
+		role += " + relayer"
+	}
+	return role
+}
+
+// printClusterCosts prints the cost table of a cluster, and returns its total
+func printClusterCosts(clusterName string, instanceCosts []clusterInstanceCost) float64 {
+	header := table.Row{"Instance", "Role", "Cloud", "Region", "Type", "Disk", "Compute", "Storage", "Public IP", "Monthly"}
+	t := ux.DefaultTable(fmt.Sprintf("%s estimated monthly costs (USD)", clusterName), header)
+	total := 0.0
+	unknownPrices := false
+	approximatePrices := false
+	for _, ic := range instanceCosts {
+		compute := formatMonthlyCost(ic.cost.Compute)
+		switch {
+		case !ic.cost.Running:
+			compute = "stopped"
+		case !ic.cost.Known:
+			compute = "unknown"
+			unknownPrices = true
+		}
+		region := ic.region
+		if !ic.cost.Live {
+			region += " (approximate)"
+			approximatePrices = true
+		}
+		t.AppendRow(table.Row{
+			ic.instanceID,
+			ic.role,
+			ic.cloud,
+			region,
+			ic.cost.InstanceType,
+			fmt.Sprintf("%d GB", ic.cost.DiskSize),
+			compute,
+			formatMonthlyCost(ic.cost.Storage),
+			formatMonthlyCost(ic.cost.PublicIP),
+			formatMonthlyCost(ic.cost.Total()),
+		})
+		total += ic.cost.Total()
+	}
+	t.AppendFooter(table.Row{"Total", "", "", "", "", "", "", "", "", formatMonthlyCost(total)})
+	ux.Logger.PrintToUser(t.Render())
+	if unknownPrices {
+		ux.Logger.PrintToUser("Some instance types have no price information: their compute cost is not included")
+	}
+	if approximatePrices {
+		ux.Logger.PrintToUser("Regional prices could not be queried for instances marked as approximate: reference region prices are used")
+	}
+	return total
+}
+
+func formatMonthlyCost(cost float64) string {
+	return fmt.Sprintf("$%.2f", cost)
+}
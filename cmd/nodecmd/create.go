@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/node"
 
 	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
+	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/gcp"
 	"github.com/ava-labs/avalanche-cli/pkg/docker"
 
 	"github.com/ava-labs/avalanche-cli/pkg/metrics"
@@ -42,6 +43,7 @@ import (
 
 const (
 	enableMonitoringFlag = "enable-monitoring"
+	monitoringFlag       = "monitoring"
 )
 
 var (
@@ -50,6 +52,7 @@ var (
 	useAWS                                bool
 	useGCP                                bool
 	cmdLineRegion                         []string
+	cmdLineRegionNodes                    []string
 	authorizeAccess                       bool
 	numValidatorsNodes                    []int
 	nodeType                              string
@@ -61,6 +64,7 @@ var (
 	useAvalanchegoVersionFromSubnet       string
 	cmdLineGCPCredentialsPath             string
 	cmdLineGCPProjectName                 string
+	cmdLineGCPImpersonateServiceAccount   string
 	cmdLineAlternativeKeyPairName         string
 	addMonitoring                         bool
 	useSSHAgent                           bool
@@ -70,9 +74,17 @@ var (
 	iops                                  int
 	volumeType                            string
 	volumeSize                            int
+	skipPreflightPrompt                   bool
 	grafanaPkg                            string
 	wizSubnet                             string
 	publicHTTPPortAccess                  bool
+	monitoringBackend                     string
+	datadogAPIKey                         string
+	datadogSite                           string
+	cloudWatchRegion                      string
+	cloudWatchCredentialsPath             string
+	provisioningMode                      string
+	avalancheGoBinaryPath                 string
 )
 
 func newCreateCmd() *cobra.Command {
@@ -92,7 +104,19 @@ status by running avalanche node status
 
 The created node will be part of group of validators called <clusterName> 
 and users can call node commands with <clusterName> so that the command
-will apply to all nodes in the cluster`,
+will apply to all nodes in the cluster
+
+A geographically distributed cluster can be created in one go with
+--regions, ex: --regions us-east-1=2,eu-west-1=2,ap-south-1=1. Each region
+gets its own security group and image, and the nodes are labeled with
+their region on the cluster inventory and on the monitoring dashboards
+
+By default avalanchego runs as a docker compose service, with its image pinned
+to a digest, and upgrades swap that image. With --provisioning binary the
+avalanchego release bundle and a systemd (or openrc) unit are uploaded directly
+over SSH instead, so the hosts need neither docker nor python. A custom build, ex: a
+statically linked one for musl based images, can be given with --avalanchego-binary.
+Monitoring and telemetry agents are not available on binary provisioned nodes`,
 		Args:              cobrautils.ExactArgs(1),
 		RunE:              createNodes,
 		PersistentPostRun: handlePostRun,
@@ -102,20 +126,24 @@ will apply to all nodes in the cluster`,
 	cmd.Flags().BoolVar(&useAWS, "aws", false, "create node/s in AWS cloud")
 	cmd.Flags().BoolVar(&useGCP, "gcp", false, "create node/s in GCP cloud")
 	cmd.Flags().StringSliceVar(&cmdLineRegion, "region", []string{}, "create node(s) in given region(s). Use comma to separate multiple regions")
+	cmd.Flags().StringSliceVar(&cmdLineRegionNodes, "regions", []string{}, "create node(s) in multiple regions at once, given as region=num-validators pairs (ex: us-east-1=2,eu-west-1=2,ap-south-1=1). Can't be used together with --region and --num-validators")
 	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to create cloud resources")
 	cmd.Flags().IntSliceVar(&numValidatorsNodes, "num-validators", []int{}, "number of nodes to create per region(s). Use comma to separate multiple numbers for each region in the same order as --region flag")
-	cmd.Flags().StringVar(&nodeType, "node-type", "", "cloud instance type. Use 'default' to use recommended default instance type")
+	cmd.Flags().StringVar(&nodeType, "node-type", "", "cloud instance type, including ARM64 ones (ex: c7g.2xlarge, t2a-standard-8). Use 'default' to use recommended default instance type")
 	cmd.Flags().BoolVar(&useLatestAvalanchegoReleaseVersion, "latest-avalanchego-version", false, "install latest avalanchego release version on node/s")
 	cmd.Flags().BoolVar(&useLatestAvalanchegoPreReleaseVersion, "latest-avalanchego-pre-release-version", false, "install latest avalanchego pre-release version on node/s")
 	cmd.Flags().StringVar(&useCustomAvalanchegoVersion, "custom-avalanchego-version", "", "install given avalanchego version on node/s")
 	cmd.Flags().StringVar(&useAvalanchegoVersionFromSubnet, "avalanchego-version-from-subnet", "", "install latest avalanchego version, that is compatible with the given subnet, on node/s")
-	cmd.Flags().StringVar(&cmdLineGCPCredentialsPath, "gcp-credentials", "", "use given GCP credentials")
+	cmd.Flags().StringVar(&cmdLineGCPCredentialsPath, "gcp-credentials", "", "use given GCP credentials (service account key or workload identity federation config)")
 	cmd.Flags().StringVar(&cmdLineGCPProjectName, "gcp-project", "", "use given GCP project")
+	cmd.Flags().StringVar(&cmdLineGCPImpersonateServiceAccount, "gcp-impersonate-service-account", "", "impersonate given GCP service account (defaults to $"+constants.GCPImpersonateEnvVar+")")
 	cmd.Flags().StringVar(&cmdLineAlternativeKeyPairName, "alternative-key-pair-name", "", "key pair name to use if default one generates conflicts")
 	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
 	cmd.Flags().BoolVar(&useSSHAgent, "use-ssh-agent", false, "use ssh agent(ex: Yubikey) for ssh auth")
 	cmd.Flags().StringVar(&sshIdentity, "ssh-agent-identity", "", "use given ssh identity(only for ssh agent). If not set, default will be used")
 	cmd.Flags().BoolVar(&addMonitoring, enableMonitoringFlag, false, "set up Prometheus monitoring for created nodes. This option creates a separate monitoring cloud instance and incures additional cost")
+	cmd.Flags().StringVar(&monitoringBackend, monitoringFlag, "", "set up monitoring for created nodes with the given backend: prometheus (same as --enable-monitoring), datadog or cloudwatch. datadog and cloudwatch install the vendor agent on the nodes instead of creating a monitoring instance")
+	addTelemetryFlags(cmd)
 	cmd.Flags().StringVar(&grafanaPkg, "grafana-pkg", "", "use grafana pkg instead of apt repo(by default), for example https://dl.grafana.com/oss/release/grafana_10.4.1_amd64.deb")
 	cmd.Flags().IntSliceVar(&numAPINodes, "num-apis", []int{}, "number of API nodes(nodes without stake) to create in the new Devnet")
 	cmd.Flags().StringVar(&customGrafanaDashboardPath, "add-grafana-dashboard", "", "path to additional grafana dashboard json file")
@@ -127,10 +155,13 @@ will apply to all nodes in the cluster`,
 	cmd.Flags().BoolVar(&publicHTTPPortAccess, "public-http-port", false, "allow public access to avalanchego HTTP port")
 	cmd.Flags().StringArrayVar(&bootstrapIDs, "bootstrap-ids", []string{}, "nodeIDs of bootstrap nodes")
 	cmd.Flags().StringArrayVar(&bootstrapIPs, "bootstrap-ips", []string{}, "IP:port pairs of bootstrap nodes")
-	cmd.Flags().StringVar(&genesisPath, "genesis", "", "path to genesis file")
+	cmd.Flags().StringVar(&genesisPath, "genesis", "", "path to genesis file. for a new devnet, custom primary network genesis to use instead of the generated one")
 	cmd.Flags().StringVar(&upgradePath, "upgrade", "", "path to upgrade file")
 	cmd.Flags().BoolVar(&partialSync, "partial-sync", true, "primary network partial sync")
-	return cmd
+	cmd.Flags().StringVar(&provisioningMode, "provisioning", constants.DockerProvisioning, "how avalanchego is installed on the nodes: docker (docker compose service) or binary (native service, no docker required)")
+	cmd.Flags().StringVar(&avalancheGoBinaryPath, "avalanchego-binary", "", "upload given local avalanchego binary instead of the release bundle (only with --provisioning binary)")
+	cmd.Flags().BoolVar(&skipPreflightPrompt, "skip-preflight-prompt", false, "do not ask for confirmation after pre-flight checks succeed (never asked without a terminal)")
+	return cobrautils.MarkClusterState(cmd)
 }
 
 // override postrun function from root.go, so that we don't double send metrics for the same command
@@ -153,6 +184,16 @@ func preCreateChecks(clusterName string) error {
 	if !useAWS && awsProfile != constants.AWSDefaultCredential {
 		return fmt.Errorf("could not use AWS profile for non AWS cloud option")
 	}
+	if len(cmdLineRegionNodes) > 0 {
+		if len(cmdLineRegion) > 0 || len(numValidatorsNodes) > 0 {
+			return fmt.Errorf("--regions can't be used together with --region or --num-validators")
+		}
+		var err error
+		cmdLineRegion, numValidatorsNodes, err = parseRegionNodes(cmdLineRegionNodes)
+		if err != nil {
+			return err
+		}
+	}
 	if len(utils.Unique(cmdLineRegion)) != len(numValidatorsNodes) {
 		return fmt.Errorf("regions provided is not consistent with number of nodes provided. Please make sure list of regions is unique")
 	}
@@ -201,9 +242,15 @@ func preCreateChecks(clusterName string) error {
 	if grafanaPkg != "" && (!strings.HasSuffix(grafanaPkg, ".deb") || !utils.IsValidURL(grafanaPkg)) {
 		return fmt.Errorf("grafana package must be URL to a .deb file")
 	}
+	if err := checkMonitoringBackend(clusterName); err != nil {
+		return err
+	}
 	if grafanaPkg != "" && !addMonitoring {
 		return fmt.Errorf("grafana package can only be used with monitoring setup")
 	}
+	if err := checkProvisioningMode(); err != nil {
+		return err
+	}
 	// check external cluster
 	if err := failForExternal(clusterName); err != nil {
 		return err
@@ -296,6 +343,14 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	network = models.NewNetworkFromCluster(network, clusterName)
+	if network.Kind == models.Devnet && genesisPath != "" {
+		// initial stakers are checked once the devnet nodes are created
+		genesisConfig, err := utils.LoadPrimaryNetworkGenesis(genesisPath, nil)
+		if err != nil {
+			return err
+		}
+		network.ID = genesisConfig.NetworkID
+	}
 	globalNetworkFlags.UseDevnet = network.Kind == models.Devnet // set globalNetworkFlags.UseDevnet to true if network is devnet for further use
 	avaGoVersionSetting := node.AvalancheGoVersionSettings{
 		UseAvalanchegoVersionFromSubnet:       useAvalanchegoVersionFromSubnet,
@@ -322,13 +377,16 @@ func createNodes(cmd *cobra.Command, args []string) error {
 	if cloudService != constants.GCPCloudService && cmdLineGCPProjectName != "" {
 		return fmt.Errorf("set to use GCP project but cloud option is not GCP")
 	}
+	if cloudService != constants.GCPCloudService && cmdLineGCPImpersonateServiceAccount != "" {
+		return fmt.Errorf("set to impersonate GCP service account but cloud option is not GCP")
+	}
 	// for devnet add nonstake api nodes for each region with stake
 	cloudConfigMap := models.CloudConfig{}
 	publicIPMap := map[string]string{}
 	apiNodeIPMap := map[string]string{}
 	numNodesMetricsMap := map[string]NumNodes{}
 	gcpProjectName := ""
-	gcpCredentialFilepath := ""
+	gcpAuth := gcpAPI.AuthConfig{}
 	// set ssh-Key
 	if useSSHAgent && sshIdentity == "" {
 		sshIdentity, err = setSSHIdentity()
@@ -342,7 +400,11 @@ func createNodes(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if existingMonitoringInstance == "" && !cmd.Flags().Changed(enableMonitoringFlag) {
+	binaryProvisioning := provisioningMode == constants.BinaryProvisioning
+	if binaryProvisioning && existingMonitoringInstance != "" {
+		return fmt.Errorf("cluster %s has monitoring, which is not supported on nodes with --provisioning %s", clusterName, constants.BinaryProvisioning)
+	}
+	if existingMonitoringInstance == "" && !binaryProvisioning && !cmd.Flags().Changed(enableMonitoringFlag) && monitoringBackend == "" {
 		if addMonitoring, err = promptSetUpMonitoring(); err != nil {
 			return err
 		}
@@ -449,6 +511,13 @@ func createNodes(cmd *cobra.Command, args []string) error {
 			if existingMonitoringInstance == "" {
 				monitoringHostRegion = regions[0]
 			}
+			newMonitoringRegion := ""
+			if addMonitoring && existingMonitoringInstance == "" {
+				newMonitoringRegion = monitoringHostRegion
+			}
+			if err := awsPreflightChecks(ec2SvcMap, ami, numNodesMap, nodeType, newMonitoringRegion); err != nil {
+				return err
+			}
 			cloudConfigMap, err = createAWSInstances(ec2SvcMap, nodeType, numNodesMap, regions, ami, false, publicHTTPPortAccess)
 			if err != nil {
 				return err
@@ -513,7 +582,7 @@ func createNodes(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("cloud access is required")
 			}
 			// Get GCP Credential, zone, Image ID, service account key file path, and GCP project name
-			gcpClient, numNodesMap, imageID, credentialFilepath, projectName, err := getGCPConfig(false)
+			gcpClient, numNodesMap, imageID, auth, projectName, err := getGCPConfig(false)
 			if err != nil {
 				return err
 			}
@@ -521,6 +590,13 @@ func createNodes(cmd *cobra.Command, args []string) error {
 			if existingMonitoringInstance == "" {
 				monitoringHostRegion = maps.Keys(numNodesMap)[0]
 			}
+			newMonitoringZone := ""
+			if addMonitoring && existingMonitoringInstance == "" {
+				newMonitoringZone = monitoringHostRegion
+			}
+			if err := gcpPreflightChecks(gcpClient, auth, numNodesMap, imageID, nodeType, newMonitoringZone); err != nil {
+				return err
+			}
 			cloudConfigMap, err = createGCPInstance(gcpClient, nodeType, numNodesMap, imageID, clusterName, false)
 			if err != nil {
 				return err
@@ -592,7 +668,7 @@ func createNodes(cmd *cobra.Command, args []string) error {
 				}
 			}
 			gcpProjectName = projectName
-			gcpCredentialFilepath = credentialFilepath
+			gcpAuth = auth
 		}
 	}
 
@@ -608,7 +684,7 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if cloudService == constants.GCPCloudService {
-		if err = updateClustersConfigGCPKeyFilepath(gcpProjectName, gcpCredentialFilepath); err != nil {
+		if err = updateClustersConfigGCPAuth(gcpProjectName, gcpAuth); err != nil {
 			return err
 		}
 	}
@@ -618,7 +694,6 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	monitoringInventoryPath := ""
-	var monitoringHosts []*models.Host
 	if addMonitoring {
 		monitoringInventoryPath = app.GetMonitoringInventoryDir(clusterName)
 		if existingMonitoringInstance == "" {
@@ -626,6 +701,12 @@ func createNodes(cmd *cobra.Command, args []string) error {
 				return err
 			}
 		}
+	}
+	if err = node.ApplyClusterSSHConfig(app, clusterName); err != nil {
+		return err
+	}
+	var monitoringHosts []*models.Host
+	if addMonitoring {
 		monitoringHosts, err = ansible.GetInventoryFromAnsibleInventoryFile(monitoringInventoryPath)
 		if err != nil {
 			return err
@@ -648,6 +729,13 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		}
 		return fmt.Errorf("failed to provision node(s) %s", failedHosts.GetNodeList())
 	}
+	telemetryBackend := telemetryAgentBackend()
+	var telemetryCredentials string
+	if telemetryBackend != "" {
+		if telemetryCredentials, err = getTelemetryCredentials(clusterName, telemetryBackend); err != nil {
+			return err
+		}
+	}
 	ux.Logger.PrintToUser("Starting bootstrap process on the newly created Avalanche node(s)...")
 	wg := sync.WaitGroup{}
 	wgResults := models.NodeResults{}
@@ -657,6 +745,10 @@ func createNodes(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	targetRegions, err := getPrometheusTargetRegions(clusterName)
+	if err != nil {
+		return err
+	}
 	startTime := time.Now()
 	if addMonitoring {
 		if len(monitoringHosts) != 1 {
@@ -673,47 +765,11 @@ func createNodes(cmd *cobra.Command, args []string) error {
 					return
 				}
 				spinner := spinSession.SpinToUser(utils.ScriptLog(monitoringHost.NodeID, "Setup Monitoring"))
-				if err = app.SetupMonitoringEnv(); err != nil {
-					nodeResults.AddResult(monitoringHost.NodeID, nil, err)
-					ux.SpinFailWithError(spinner, "", err)
-					return
-				}
-				if err = ssh.RunSSHSetupDockerService(monitoringHost); err != nil {
-					nodeResults.AddResult(monitoringHost.NodeID, nil, err)
-					ux.SpinFailWithError(spinner, "", err)
-					return
-				}
-				ux.Logger.Info("SetupMonitoringEnv RunSSHSetupDockerService completed")
-				if err = ssh.RunSSHSetupMonitoringFolders(monitoringHost); err != nil {
-					nodeResults.AddResult(monitoringHost.NodeID, nil, err)
-					ux.SpinFailWithError(spinner, "", err)
-					return
-				}
-				ux.Logger.Info("RunSSHSetupMonitoringFolders completed")
-				if err := ssh.RunSSHCopyMonitoringDashboards(monitoringHost, app.GetMonitoringDashboardDir()+"/"); err != nil {
-					nodeResults.AddResult(monitoringHost.NodeID, nil, err)
-					ux.SpinFailWithError(spinner, "", err)
-					return
-				}
-				ux.Logger.Info("RunSSHCopyMonitoringDashboards completed")
-				if err := ssh.RunSSHSetupPrometheusConfig(monitoringHost, avalancheGoPorts, machinePorts, ltPorts); err != nil {
-					nodeResults.AddResult(monitoringHost.NodeID, nil, err)
-					ux.SpinFailWithError(spinner, "", err)
-					return
-				}
-				ux.Logger.Info("RunSSHSetupPrometheusConfig completed")
-				if err := ssh.RunSSHSetupLokiConfig(monitoringHost, constants.AvalancheGoLokiPort); err != nil {
-					nodeResults.AddResult(monitoringHost.NodeID, nil, err)
-					ux.SpinFailWithError(spinner, "", err)
-					return
-				}
-				ux.Logger.Info("RunSSHSetupLokiConfig completed")
-				if err := docker.ComposeSSHSetupMonitoring(monitoringHost); err != nil {
+				if err := setupMonitoringHost(monitoringHost, clusterName, avalancheGoPorts, machinePorts, ltPorts, targetRegions); err != nil {
 					nodeResults.AddResult(monitoringHost.NodeID, nil, err)
 					ux.SpinFailWithError(spinner, "", err)
 					return
 				}
-				ux.Logger.Info("ComposeSSHSetupMonitoring completed")
 				ux.SpinComplete(spinner)
 			}(&wgResults, monitoringHost)
 		}
@@ -731,15 +787,23 @@ func createNodes(cmd *cobra.Command, args []string) error {
 				return
 			}
 			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, "Setup Node"))
-			if err := ssh.RunSSHSetupNode(host, app.Conf.GetConfigPath()); err != nil {
-				nodeResults.AddResult(host.NodeID, nil, err)
-				ux.SpinFailWithError(spinner, "", err)
-				return
-			}
-			if err := ssh.RunSSHSetupDockerService(host); err != nil {
-				nodeResults.AddResult(host.NodeID, nil, err)
-				ux.SpinFailWithError(spinner, "", err)
-				return
+			if binaryProvisioning {
+				if err := ssh.RunSSHSetupNodeBinary(host, app.Conf.GetConfigPath()); err != nil {
+					nodeResults.AddResult(host.NodeID, nil, err)
+					ux.SpinFailWithError(spinner, "", err)
+					return
+				}
+			} else {
+				if err := ssh.RunSSHSetupNode(host, app.Conf.GetConfigPath()); err != nil {
+					nodeResults.AddResult(host.NodeID, nil, err)
+					ux.SpinFailWithError(spinner, "", err)
+					return
+				}
+				if err := ssh.RunSSHSetupDockerService(host); err != nil {
+					nodeResults.AddResult(host.NodeID, nil, err)
+					ux.SpinFailWithError(spinner, "", err)
+					return
+				}
 			}
 			ux.SpinComplete(spinner)
 			if addMonitoring {
@@ -760,7 +824,25 @@ func createNodes(cmd *cobra.Command, args []string) error {
 			spinner = spinSession.SpinToUser(utils.ScriptLog(host.NodeID, "Setup AvalancheGo"))
 			// check if host is a API host
 			publicAccessToHTTPPort := slices.Contains(cloudConfigMap.GetAllAPIInstanceIDs(), host.GetCloudID()) || publicHTTPPortAccess
-			if err := docker.ComposeSSHSetupNode(host,
+			if binaryProvisioning {
+				if err := ssh.RunSSHSetupAvalanchegoBinary(host,
+					network,
+					avalancheGoVersion,
+					avalancheGoBinaryPath,
+					docker.AvalancheGoConfigOptions{
+						BootstrapIDs:      bootstrapIDs,
+						BootstrapIPs:      bootstrapIPs,
+						PartialSync:       partialSync,
+						GenesisPath:       genesisPath,
+						UpgradePath:       upgradePath,
+						AllowPublicAccess: publicAccessToHTTPPort,
+					},
+				); err != nil {
+					nodeResults.AddResult(host.NodeID, nil, err)
+					ux.SpinFailWithError(spinner, "", err)
+					return
+				}
+			} else if err := docker.ComposeSSHSetupNode(host,
 				network,
 				avalancheGoVersion,
 				bootstrapIDs,
@@ -776,6 +858,15 @@ func createNodes(cmd *cobra.Command, args []string) error {
 				return
 			}
 			ux.SpinComplete(spinner)
+			if telemetryBackend != "" {
+				spinner = spinSession.SpinToUser(utils.ScriptLog(host.NodeID, "Setup %s Agent", telemetryBackend))
+				if err := setupTelemetryAgent(host, telemetryBackend, telemetryCredentials, clusterName, network); err != nil {
+					nodeResults.AddResult(host.NodeID, nil, err)
+					ux.SpinFailWithError(spinner, "", err)
+					return
+				}
+				ux.SpinComplete(spinner)
+			}
 		}(&wgResults, host)
 	}
 	wg.Wait()
@@ -792,6 +883,14 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if telemetryBackend != "" {
+		if err := setClusterTelemetry(clusterName, telemetryBackend, telemetryCredentials); err != nil {
+			return err
+		}
+		if err := setupTelemetryDashboard(clusterName); err != nil {
+			return err
+		}
+	}
 	if wgResults.HasErrors() {
 		return fmt.Errorf("failed to deploy node(s) %s", wgResults.GetErrorHostMap())
 	} else {
@@ -842,6 +941,7 @@ func CreateClusterNodeConfig(
 				CloudService:  cloudService,
 				UseStaticIP:   useStaticIP,
 				IsMonitor:     false,
+				Arch:          cloudConfig.Arch,
 			}
 			if err := app.CreateNodeCloudConfigFile(cloudConfig.InstanceIDs[i], &nodeConfig); err != nil {
 				return err
@@ -877,6 +977,7 @@ func saveExternalHostConfig(externalHostConfig models.RegionConfig, hostRegion,
 		UseStaticIP:   useStaticIP,
 		IsMonitor:     isMonitoring,
 		IsLoadTest:    isLoadTest,
+		Arch:          externalHostConfig.Arch,
 	}
 	if err := app.CreateNodeCloudConfigFile(externalHostConfig.InstanceIDs[0], &nodeConfig); err != nil {
 		return err
@@ -936,6 +1037,7 @@ func getNodeCloudConfig(node string) (models.RegionConfig, string, error) {
 		SecurityGroupName: config.SecurityGroup,
 		CertFilePath:      config.CertPath,
 		ImageID:           config.AMI,
+		Arch:              config.Arch,
 	}, config.Region, nil
 }
 
@@ -1086,28 +1188,39 @@ func setCloudInstanceType(cloudService string) (string, error) {
 	defaultNodeType := ""
 	nodeTypeOption2 := ""
 	nodeTypeOption3 := ""
+	armNodeType := ""
 	customNodeType := "Choose custom instance type"
 	switch {
 	case cloudService == constants.AWSCloudService:
 		defaultNodeType = constants.AWSDefaultInstanceType
 		nodeTypeOption2 = "t3a.2xlarge" // burst
 		nodeTypeOption3 = "c5n.2xlarge"
+		armNodeType = constants.AWSDefaultARMInstanceType // graviton
 	case cloudService == constants.GCPCloudService:
 		defaultNodeType = constants.GCPDefaultInstanceType
 		nodeTypeOption2 = "c3-highcpu-8"
 		nodeTypeOption3 = "n2-standard-8"
+		armNodeType = constants.GCPDefaultARMInstanceType // tau t2a
 	}
 	if nodeType == "" {
 		defaultStr := "[default] (recommended)"
+		armStr := "[ARM64] (lower cost)"
 		nodeTypeStr, err := app.Prompt.CaptureList(
 			"Instance type to use",
-			[]string{fmt.Sprintf("%s %s", defaultNodeType, defaultStr), nodeTypeOption2, nodeTypeOption3, customNodeType},
+			[]string{
+				fmt.Sprintf("%s %s", defaultNodeType, defaultStr),
+				nodeTypeOption2,
+				nodeTypeOption3,
+				fmt.Sprintf("%s %s", armNodeType, armStr),
+				customNodeType,
+			},
 		)
 		if err != nil {
 			ux.Logger.PrintToUser("Failed to capture node type with error: %s", err.Error())
 			return "", err
 		}
 		nodeTypeStr = strings.ReplaceAll(nodeTypeStr, defaultStr, "") // remove (default) if any
+		nodeTypeStr = strings.ReplaceAll(nodeTypeStr, armStr, "")
 		if nodeTypeStr == customNodeType {
 			nodeTypeStr, err = app.Prompt.CaptureString("What instance type would you like to use? Please refer to https://docs.avax.network/nodes/run/node-manually#hardware-and-os-requirements for minimum hardware requirements")
 			if err != nil {
@@ -1279,6 +1392,55 @@ func getSeparateHostNodeParam(cloudName string) (
 	return userRegion, nil
 }
 
+// parseRegionNodes parses region=num-validators pairs into the regions and the number of
+// validators to create in each of them, in the given order
+func parseRegionNodes(regionNodes []string) ([]string, []int, error) {
+	regions := []string{}
+	numNodes := []int{}
+	for _, regionNode := range regionNodes {
+		region, num, found := strings.Cut(regionNode, "=")
+		region = strings.TrimSpace(region)
+		if !found || region == "" {
+			return nil, nil, fmt.Errorf("invalid region %q: expected region=num-validators", regionNode)
+		}
+		if slices.Contains(regions, region) {
+			return nil, nil, fmt.Errorf("region %s given more than once", region)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(num))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid number of nodes for region %s: %w", region, err)
+		}
+		regions = append(regions, region)
+		numNodes = append(numNodes, n)
+	}
+	return regions, numNodes, nil
+}
+
+// checkProvisioningMode validates the provisioning flags. Binary provisioned nodes don't run
+// docker, so the docker based monitoring and telemetry agents can't be set up on them
+func checkProvisioningMode() error {
+	switch provisioningMode {
+	case "", constants.DockerProvisioning:
+		if avalancheGoBinaryPath != "" {
+			return fmt.Errorf("--avalanchego-binary can only be used with --provisioning %s", constants.BinaryProvisioning)
+		}
+		return nil
+	case constants.BinaryProvisioning:
+		if addMonitoring || monitoringBackend != "" {
+			return fmt.Errorf("monitoring is not supported on nodes with --provisioning %s", constants.BinaryProvisioning)
+		}
+		if avalancheGoBinaryPath != "" {
+			avalancheGoBinaryPath = utils.ExpandHome(avalancheGoBinaryPath)
+			if !utils.FileExists(avalancheGoBinaryPath) {
+				return fmt.Errorf("avalanchego binary %s not found", avalancheGoBinaryPath)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid provisioning mode %q. Valid options are %s and %s", provisioningMode, constants.DockerProvisioning, constants.BinaryProvisioning)
+	}
+}
+
 func getRegionsNodeNum(cloudName string) (
 	map[string]NumNodes,
 	error,
@@ -1442,3 +1604,26 @@ func getPrometheusTargets(clusterName string) ([]string, []string, []string, err
 	}
 	return avalancheGoPorts, machinePorts, ltPorts, nil
 }
+
+// getPrometheusTargetRegions returns the region of each of the cluster hosts, by IP,
+// to label the prometheus targets with
+func getPrometheusTargetRegions(clusterName string) (map[string]string, error) {
+	targetRegions := map[string]string{}
+	inventoryHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range inventoryHosts {
+		region := host.Region
+		if region == "" {
+			// inventories created before region vars were added
+			if nodeConfig, err := app.LoadClusterNodeConfig(host.GetCloudID()); err == nil {
+				region = nodeConfig.Region
+			}
+		}
+		if region != "" {
+			targetRegions[host.IP] = region
+		}
+	}
+	return targetRegions, nil
+}
@@ -506,6 +506,10 @@ func createAWSInstances(
 		if err != nil {
 			return models.CloudConfig{}, err
 		}
+		arch, err := ec2Svc[region].GetInstanceTypeArch(nodeType)
+		if err != nil {
+			return models.CloudConfig{}, err
+		}
 		regionConf[region] = models.RegionConfig{
 			Prefix:            prefix,
 			ImageID:           ami[region],
@@ -513,6 +517,7 @@ func createAWSInstances(
 			SecurityGroupName: prefix + "-" + region + constants.AWSSecurityGroupSuffix,
 			NumNodes:          numNodes[region].All(),
 			InstanceType:      nodeType,
+			Arch:              arch,
 		}
 	}
 	// Create new EC2 instances
@@ -554,6 +559,7 @@ func createAWSInstances(
 			SecurityGroup: regionConf[region].SecurityGroupName,
 			CertFilePath:  certFilePath[region],
 			ImageID:       ami[region],
+			Arch:          regionConf[region].Arch,
 		}
 	}
 	return awsCloudConfig, nil
@@ -25,8 +25,8 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/ids"
 	avago_upgrade "github.com/ava-labs/avalanchego/upgrade"
-	avago_constants "github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -47,9 +47,9 @@ const (
 //go:embed upgrade.json
 var upgradeBytes []byte
 
-func generateCustomCchainGenesis() ([]byte, error) {
+func generateCustomCchainGenesis(networkID uint32) ([]byte, error) {
 	cChainGenesisMap := map[string]interface{}{}
-	cChainGenesisMap["config"] = coreth_params.GetChainConfig(avago_upgrade.GetConfig(avago_constants.LocalID), coreth_params.AvalancheLocalChainID)
+	cChainGenesisMap["config"] = coreth_params.GetChainConfig(avago_upgrade.GetConfig(networkID), coreth_params.AvalancheLocalChainID)
 	cChainGenesisMap["nonce"] = hexa0Str
 	cChainGenesisMap["timestamp"] = hexa0Str
 	cChainGenesisMap["extraData"] = "0x00"
@@ -77,7 +77,7 @@ func generateCustomGenesis(
 	genesisMap := map[string]interface{}{}
 
 	// cchain
-	cChainGenesisBytes, err := generateCustomCchainGenesis()
+	cChainGenesisBytes, err := generateCustomCchainGenesis(networkID)
 	if err != nil {
 		return nil, err
 	}
@@ -181,7 +181,39 @@ func setupDevnet(clusterName string, hosts []*models.Host, apiNodeIPMap map[stri
 		endpointIP = ansibleHosts[ansibleHostIDs[0]].IP
 	}
 	endpoint := node.GetAvalancheGoEndpoint(endpointIP)
-	network := models.NewDevnetNetwork(endpoint, 0)
+
+	// exclude API nodes from genesis file generation as they will have no stake
+	hostsAPI := utils.Filter(hosts, func(h *models.Host) bool {
+		return slices.Contains(maps.Keys(apiNodeIPMap), h.GetCloudID())
+	})
+	hostsWithoutAPI := utils.Filter(hosts, func(h *models.Host) bool {
+		return !slices.Contains(maps.Keys(apiNodeIPMap), h.GetCloudID())
+	})
+	hostsWithoutAPIIDs := utils.Map(hostsWithoutAPI, func(h *models.Host) string { return h.NodeID })
+
+	// a custom genesis sets the network ID and the initial stakers, that must be the validator nodes
+	var customGenesisBytes []byte
+	networkID := uint32(0)
+	if genesisPath != "" {
+		validatorNodeIDs := []ids.NodeID{}
+		for _, host := range hostsWithoutAPI {
+			nodeID, err := getNodeID(app.GetNodeInstanceDirPath(host.GetCloudID()))
+			if err != nil {
+				return err
+			}
+			validatorNodeIDs = append(validatorNodeIDs, nodeID)
+		}
+		genesisConfig, err := utils.LoadPrimaryNetworkGenesis(genesisPath, validatorNodeIDs)
+		if err != nil {
+			return err
+		}
+		customGenesisBytes, err = os.ReadFile(genesisPath)
+		if err != nil {
+			return err
+		}
+		networkID = genesisConfig.NetworkID
+	}
+	network := models.NewDevnetNetwork(endpoint, networkID)
 	network = models.NewNetworkFromCluster(network, clusterName)
 
 	// get random staking key for devnet genesis
@@ -198,19 +230,13 @@ func setupDevnet(clusterName string, hosts []*models.Host, apiNodeIPMap map[stri
 	}
 	walletAddrStr := k.X()[0]
 
-	// exclude API nodes from genesis file generation as they will have no stake
-	hostsAPI := utils.Filter(hosts, func(h *models.Host) bool {
-		return slices.Contains(maps.Keys(apiNodeIPMap), h.GetCloudID())
-	})
-	hostsWithoutAPI := utils.Filter(hosts, func(h *models.Host) bool {
-		return !slices.Contains(maps.Keys(apiNodeIPMap), h.GetCloudID())
-	})
-	hostsWithoutAPIIDs := utils.Map(hostsWithoutAPI, func(h *models.Host) string { return h.NodeID })
-
 	// create genesis file at each node dir
-	genesisBytes, err := generateCustomGenesis(network.ID, walletAddrStr, stakingAddrStr, hostsWithoutAPI)
-	if err != nil {
-		return err
+	genesisBytes := customGenesisBytes
+	if genesisBytes == nil {
+		genesisBytes, err = generateCustomGenesis(network.ID, walletAddrStr, stakingAddrStr, hostsWithoutAPI)
+		if err != nil {
+			return err
+		}
 	}
 	// make sure that custom genesis is saved to the subnet dir
 	if err := os.WriteFile(app.GetGenesisPath(blockchainName), genesisBytes, constants.WriteReadReadPerms); err != nil {
@@ -14,7 +14,6 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"golang.org/x/net/context"
-	"golang.org/x/oauth2/google"
 	"google.golang.org/api/compute/v1"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
@@ -25,13 +24,18 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 )
 
-func getServiceAccountKeyFilepath() (string, error) {
+func getGCPCredentialsPath() (string, error) {
 	if cmdLineGCPCredentialsPath != "" {
-		return cmdLineGCPCredentialsPath, nil
+		return utils.GetRealFilePath(cmdLineGCPCredentialsPath), nil
+	}
+	if credentialsPath := os.Getenv(constants.GCPEnvVar); credentialsPath != "" {
+		return utils.GetRealFilePath(credentialsPath), nil
 	}
 	ux.Logger.PrintToUser("To create a VM instance in GCP, you can use your account credentials")
 	ux.Logger.PrintToUser("Please follow instructions detailed at https://developers.google.com/workspace/guides/create-credentials#service-account to set up a GCP service account")
 	ux.Logger.PrintToUser("Or use https://cloud.google.com/sdk/docs/authorizing#user-account for authorization without a service account")
+	ux.Logger.PrintToUser("If exporting service account keys is not allowed, use a workload identity federation credential configuration")
+	ux.Logger.PrintToUser("as detailed at https://cloud.google.com/iam/docs/workload-identity-federation, or impersonate a service account with --gcp-impersonate-service-account")
 	customAuthKeyPath := "Choose custom path for credentials JSON file"
 	credJSONFilePath, err := app.Prompt.CaptureList(
 		"What is the filepath to the credentials JSON file?",
@@ -49,64 +53,73 @@ func getServiceAccountKeyFilepath() (string, error) {
 	return utils.GetRealFilePath(credJSONFilePath), err
 }
 
-func getGCPCloudCredentials() (*compute.Service, string, string, error) {
+// getGCPAuthConfig gets the GCP authentication settings, giving precedence to flags,
+// then to env vars, and then to the settings stored on the clusters config
+func getGCPAuthConfig(gcpConfig models.GCPConfig) (gcpAPI.AuthConfig, error) {
+	auth := gcpAPI.AuthConfig{
+		CredentialsPath:           gcpConfig.ServiceAccFilePath,
+		ImpersonateServiceAccount: gcpConfig.ImpersonateServiceAccount,
+	}
+	if cmdLineGCPCredentialsPath != "" || auth.CredentialsPath == "" {
+		credentialsPath, err := getGCPCredentialsPath()
+		if err != nil {
+			return gcpAPI.AuthConfig{}, err
+		}
+		auth.CredentialsPath = credentialsPath
+	}
+	switch {
+	case cmdLineGCPImpersonateServiceAccount != "":
+		auth.ImpersonateServiceAccount = cmdLineGCPImpersonateServiceAccount
+	case os.Getenv(constants.GCPImpersonateEnvVar) != "":
+		auth.ImpersonateServiceAccount = os.Getenv(constants.GCPImpersonateEnvVar)
+	}
+	return auth, nil
+}
+
+func getGCPCloudCredentials() (*compute.Service, string, gcpAPI.AuthConfig, error) {
 	var err error
-	var gcpCredentialsPath string
 	var gcpProjectName string
 	clustersConfig, err := app.GetClustersConfig()
 	if err != nil {
-		return nil, "", "", err
-	}
-	if clustersConfig.GCPConfig != (models.GCPConfig{}) {
-		gcpProjectName = clustersConfig.GCPConfig.ProjectName
-		gcpCredentialsPath = clustersConfig.GCPConfig.ServiceAccFilePath
+		return nil, "", gcpAPI.AuthConfig{}, err
 	}
+	gcpProjectName = clustersConfig.GCPConfig.ProjectName
 	if gcpProjectName == "" {
 		if cmdLineGCPProjectName != "" {
 			gcpProjectName = cmdLineGCPProjectName
 		} else {
 			gcpProjectName, err = app.Prompt.CaptureString("What is the name of your Google Cloud project?")
 			if err != nil {
-				return nil, "", "", err
+				return nil, "", gcpAPI.AuthConfig{}, err
 			}
 		}
 	}
-	if gcpCredentialsPath == "" {
-		gcpCredentialsPath, err = getServiceAccountKeyFilepath()
-		if err != nil {
-			return nil, "", "", err
-		}
-	}
-	err = os.Setenv(constants.GCPEnvVar, gcpCredentialsPath)
+	auth, err := getGCPAuthConfig(clustersConfig.GCPConfig)
 	if err != nil {
-		return nil, "", "", err
+		return nil, "", gcpAPI.AuthConfig{}, err
 	}
-	ctx := context.Background()
-	client, err := google.DefaultClient(ctx, compute.ComputeScope)
-	if err != nil {
-		return nil, "", "", err
-	}
-	computeService, err := compute.New(client)
-	return computeService, gcpProjectName, gcpCredentialsPath, err
+	ux.Logger.Info("authenticating to GCP with %s", auth.Describe())
+	computeService, err := gcpAPI.NewComputeService(context.Background(), auth)
+	return computeService, gcpProjectName, auth, err
 }
 
-func getGCPConfig(singleNode bool) (*gcpAPI.GcpCloud, map[string]NumNodes, string, string, string, error) {
+func getGCPConfig(singleNode bool) (*gcpAPI.GcpCloud, map[string]NumNodes, string, gcpAPI.AuthConfig, string, error) {
 	finalRegions := map[string]NumNodes{}
 	switch {
 	case len(numValidatorsNodes) != len(utils.Unique(cmdLineRegion)):
-		return nil, nil, "", "", "", errors.New("number of regions and number of nodes must be equal. Please make sure list of regions is unique")
+		return nil, nil, "", gcpAPI.AuthConfig{}, "", errors.New("number of regions and number of nodes must be equal. Please make sure list of regions is unique")
 	case len(cmdLineRegion) == 0 && len(numValidatorsNodes) == 0:
 		var err error
 		if singleNode {
 			selectedRegion, err := getSeparateHostNodeParam(constants.GCPCloudService)
 			finalRegions = map[string]NumNodes{selectedRegion: {1, 0}}
 			if err != nil {
-				return nil, nil, "", "", "", err
+				return nil, nil, "", gcpAPI.AuthConfig{}, "", err
 			}
 		} else {
 			finalRegions, err = getRegionsNodeNum(constants.GCPCloudService)
 			if err != nil {
-				return nil, nil, "", "", "", err
+				return nil, nil, "", gcpAPI.AuthConfig{}, "", err
 			}
 		}
 	default:
@@ -120,32 +133,32 @@ func getGCPConfig(singleNode bool) (*gcpAPI.GcpCloud, map[string]NumNodes, strin
 			}
 		}
 	}
-	gcpClient, projectName, gcpCredentialFilePath, err := getGCPCloudCredentials()
+	gcpClient, projectName, gcpAuth, err := getGCPCloudCredentials()
 	if err != nil {
-		return nil, nil, "", "", "", err
+		return nil, nil, "", gcpAPI.AuthConfig{}, "", err
 	}
 	gcpCloud, err := gcpAPI.NewGcpCloud(gcpClient, projectName, context.Background())
 	if err != nil {
-		return nil, nil, "", "", "", err
+		return nil, nil, "", gcpAPI.AuthConfig{}, "", err
 	}
 	finalZones := map[string]NumNodes{}
 	// verify regions are valid and place in random zones per region
 	for region, numNodes := range finalRegions {
 		if !slices.Contains(gcpCloud.ListRegions(), region) {
-			return nil, nil, "", "", "", fmt.Errorf("invalid region %s", region)
+			return nil, nil, "", gcpAPI.AuthConfig{}, "", fmt.Errorf("invalid region %s", region)
 		} else {
 			finalZone, err := gcpCloud.GetRandomZone(region)
 			if err != nil {
-				return nil, nil, "", "", "", err
+				return nil, nil, "", gcpAPI.AuthConfig{}, "", err
 			}
 			finalZones[finalZone] = numNodes
 		}
 	}
-	imageID, err := gcpCloud.GetUbuntuImageID()
+	imageID, err := gcpCloud.GetUbuntuImageID(gcpAPI.GetMachineTypeArch(nodeType))
 	if err != nil {
-		return nil, nil, "", "", "", err
+		return nil, nil, "", gcpAPI.AuthConfig{}, "", err
 	}
-	return gcpCloud, finalZones, imageID, gcpCredentialFilePath, projectName, nil
+	return gcpCloud, finalZones, imageID, gcpAuth, projectName, nil
 }
 
 // createGCEInstances creates Google Compute Engine VM instances
@@ -371,12 +384,13 @@ func createGCPInstance(
 			SecurityGroup: fmt.Sprintf("%s-network", prefix),
 			CertFilePath:  certFilePath,
 			ImageID:       imageID,
+			Arch:          gcpAPI.GetMachineTypeArch(instanceType),
 		}
 	}
 	return ccm, nil
 }
 
-func updateClustersConfigGCPKeyFilepath(projectName, serviceAccountKeyFilepath string) error {
+func updateClustersConfigGCPAuth(projectName string, auth gcpAPI.AuthConfig) error {
 	clustersConfig, err := app.GetClustersConfig()
 	if err != nil {
 		return err
@@ -384,8 +398,11 @@ func updateClustersConfigGCPKeyFilepath(projectName, serviceAccountKeyFilepath s
 	if projectName != "" {
 		clustersConfig.GCPConfig.ProjectName = projectName
 	}
-	if serviceAccountKeyFilepath != "" {
-		clustersConfig.GCPConfig.ServiceAccFilePath = serviceAccountKeyFilepath
+	if auth.CredentialsPath != "" {
+		clustersConfig.GCPConfig.ServiceAccFilePath = auth.CredentialsPath
+	}
+	if auth.ImpersonateServiceAccount != "" {
+		clustersConfig.GCPConfig.ImpersonateServiceAccount = auth.ImpersonateServiceAccount
 	}
 	return app.WriteClustersConfigFile(&clustersConfig)
 }
@@ -0,0 +1,300 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
+	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/gcp"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/exp/maps"
+)
+
+type preflightCheck struct {
+	region string
+	name   string
+	result string
+	failed bool
+	// the check could not be completed, but it does not block the creation
+	unknown bool
+}
+
+// preflightCost is the estimated monthly cost of the instances to be created
+type preflightCost struct {
+	total float64
+	// false if some instance type has no price information
+	known bool
+	// false if some prices are approximate, instead of the published ones for the region
+	live bool
+}
+
+func (c *preflightCost) add(cost float64, known bool, live bool) {
+	c.total += cost
+	c.known = c.known && known
+	c.live = c.live && live
+}
+
+// awsPreflightChecks validates, before creating any resource, that the given regions
+// have enough vCPU quota and elastic IPs, the AMI is available, and the key pair can be
+// used. It also prints an estimated monthly cost, and asks the user for confirmation
+func awsPreflightChecks(
+	ec2SvcMap map[string]*awsAPI.AwsCloud,
+	amiMap map[string]string,
+	numNodesMap map[string]NumNodes,
+	instanceType string,
+	monitoringRegion string,
+) error {
+	ux.Logger.PrintToUser("Running pre-flight checks on AWS...")
+	checks := []preflightCheck{}
+	totalNodes := 0
+	cost := preflightCost{known: true, live: true}
+	regions := maps.Keys(numNodesMap)
+	sort.Strings(regions)
+	for _, region := range regions {
+		ec2Svc := ec2SvcMap[region]
+		numNodes := numNodesMap[region].All()
+		if region == monitoringRegion {
+			numNodes++
+		}
+		totalNodes += numNodes
+		prices, known := ec2Svc.GetPrices(instanceType, volumeType)
+		cost.add(awsAPI.EstimateMonthlyCost(prices, volumeSize, numNodes), known, prices.Live)
+		// vCPUs
+		instanceVCPUs, err := ec2Svc.GetInstanceTypeVCPUs(instanceType)
+		if err != nil {
+			return err
+		}
+		usedVCPUs, err := ec2Svc.GetUsedVCPUs(instanceType)
+		if err != nil {
+			return err
+		}
+		requiredVCPUs := instanceVCPUs * numNodes
+		if maxVCPUs, err := ec2Svc.GetVCPUQuota(instanceType); err != nil {
+			checks = append(checks, preflightCheck{
+				region:  region,
+				name:    "vCPUs",
+				result:  fmt.Sprintf("%d required, %d in use, quota could not be read: %s", requiredVCPUs, usedVCPUs, err),
+				unknown: true,
+			})
+		} else {
+			checks = append(checks, preflightCheck{
+				region: region,
+				name:   "vCPUs",
+				result: fmt.Sprintf("%d required, %d of %d in use", requiredVCPUs, usedVCPUs, maxVCPUs),
+				failed: usedVCPUs+requiredVCPUs > maxVCPUs,
+			})
+		}
+		// elastic IPs
+		if useStaticIP {
+			usedEIPs, maxEIPs, err := ec2Svc.GetElasticIPUsage()
+			if err != nil {
+				return err
+			}
+			checks = append(checks, preflightCheck{
+				region: region,
+				name:   "Elastic IPs",
+				result: fmt.Sprintf("%d required, %d of %d in use", numNodes, usedEIPs, maxEIPs),
+				failed: usedEIPs+numNodes > maxEIPs,
+			})
+		}
+		// AMI
+		amiAvailable, err := ec2Svc.CheckImageAvailable(amiMap[region])
+		if err != nil {
+			return err
+		}
+		amiResult := fmt.Sprintf("%s available", amiMap[region])
+		if !amiAvailable {
+			amiResult = fmt.Sprintf("%s not available", amiMap[region])
+		}
+		checks = append(checks, preflightCheck{
+			region: region,
+			name:   "AMI",
+			result: amiResult,
+			failed: !amiAvailable,
+		})
+		// key pair
+		prefix, err := defaultAvalancheCLIPrefix(region)
+		if err != nil {
+			return err
+		}
+		keyPairExists, err := ec2Svc.CheckKeyPairExists(prefix)
+		if err != nil {
+			return err
+		}
+		certInSSHDir, err := app.CheckCertInSSHDir(prefix + "-" + region + constants.CertSuffix)
+		if err != nil {
+			return err
+		}
+		keyPairResult := ""
+		switch {
+		case replaceKeyPair:
+			keyPairResult = fmt.Sprintf("%s will be replaced", prefix)
+		case useSSHAgent && keyPairExists:
+			keyPairResult = fmt.Sprintf("%s exists, using ssh agent", prefix)
+		case useSSHAgent:
+			keyPairResult = fmt.Sprintf("%s will be created from ssh agent identity", prefix)
+		case keyPairExists && certInSSHDir:
+			keyPairResult = fmt.Sprintf("%s exists", prefix)
+		case keyPairExists || certInSSHDir:
+			keyPairResult = fmt.Sprintf("%s is not in sync with your .ssh directory, an alternative name will be asked", prefix)
+		default:
+			keyPairResult = fmt.Sprintf("%s will be created", prefix)
+		}
+		checks = append(checks, preflightCheck{
+			region: region,
+			name:   "Key Pair",
+			result: keyPairResult,
+		})
+	}
+	return confirmPreflightChecks(constants.AWSCloudService, checks, totalNodes, instanceType, cost)
+}
+
+// gcpPreflightChecks validates, before creating any resource, that the given zones
+// have enough CPU and IP address quota, and that the image is available. It also
+// prints an estimated monthly cost, and asks the user for confirmation
+func gcpPreflightChecks(
+	gcpClient *gcpAPI.GcpCloud,
+	auth gcpAPI.AuthConfig,
+	numNodesMap map[string]NumNodes,
+	imageID string,
+	instanceType string,
+	monitoringZone string,
+) error {
+	ux.Logger.PrintToUser("Running pre-flight checks on GCP...")
+	checks := []preflightCheck{}
+	totalNodes := 0
+	cost := preflightCost{known: true, live: true}
+	priceCatalog, err := getGCPPriceCatalog(auth)
+	if err != nil {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Using approximate us-central1 prices: %s"), err)
+	}
+	zones := maps.Keys(numNodesMap)
+	sort.Strings(zones)
+	imageAvailable, err := gcpClient.CheckImageAvailable(imageID)
+	if err != nil {
+		return err
+	}
+	imageResult := fmt.Sprintf("%s available", imageID)
+	if !imageAvailable {
+		imageResult = fmt.Sprintf("%s not available", imageID)
+	}
+	checks = append(checks, preflightCheck{
+		region: "global",
+		name:   "Image",
+		result: imageResult,
+		failed: !imageAvailable,
+	})
+	for _, zone := range zones {
+		region := gcpAPI.ZoneToRegion(zone)
+		numNodes := numNodesMap[zone].All()
+		if zone == monitoringZone {
+			numNodes++
+		}
+		totalNodes += numNodes
+		machineVCPUs, machineMemoryGB, err := gcpClient.GetMachineTypeResources(instanceType, zone)
+		if err != nil {
+			return err
+		}
+		prices, known := gcpAPI.GetPrices(priceCatalog, region, instanceType, machineVCPUs, machineMemoryGB)
+		cost.add(gcpAPI.EstimateMonthlyCost(prices, constants.CloudServerStorageSize, numNodes), known, prices.Live)
+		usedCPUs, maxCPUs, err := gcpClient.GetRegionQuota(region, "CPUS")
+		if err != nil {
+			return err
+		}
+		requiredCPUs := machineVCPUs * numNodes
+		checks = append(checks, preflightCheck{
+			region: zone,
+			name:   "vCPUs",
+			result: fmt.Sprintf("%d required, %.0f of %.0f in use", requiredCPUs, usedCPUs, maxCPUs),
+			failed: usedCPUs+float64(requiredCPUs) > maxCPUs,
+		})
+		if useStaticIP {
+			usedIPs, maxIPs, err := gcpClient.GetRegionQuota(region, "STATIC_ADDRESSES")
+			if err != nil {
+				return err
+			}
+			checks = append(checks, preflightCheck{
+				region: zone,
+				name:   "Static IPs",
+				result: fmt.Sprintf("%d required, %.0f of %.0f in use", numNodes, usedIPs, maxIPs),
+				failed: usedIPs+float64(numNodes) > maxIPs,
+			})
+		}
+		usedInUseIPs, maxInUseIPs, err := gcpClient.GetRegionQuota(region, "IN_USE_ADDRESSES")
+		if err != nil {
+			return err
+		}
+		checks = append(checks, preflightCheck{
+			region: zone,
+			name:   "In use IPs",
+			result: fmt.Sprintf("%d required, %.0f of %.0f in use", numNodes, usedInUseIPs, maxInUseIPs),
+			failed: usedInUseIPs+float64(numNodes) > maxInUseIPs,
+		})
+	}
+	return confirmPreflightChecks(constants.GCPCloudService, checks, totalNodes, instanceType, cost)
+}
+
+// getGCPPriceCatalog loads the Compute Engine prices from the cloud billing catalog
+func getGCPPriceCatalog(auth gcpAPI.AuthConfig) (*gcpAPI.PriceCatalog, error) {
+	billingService, err := gcpAPI.NewBillingService(context.Background(), auth)
+	if err != nil {
+		return nil, err
+	}
+	return gcpAPI.NewPriceCatalog(context.Background(), billingService)
+}
+
+// confirmPreflightChecks prints the pre-flight checks summary, fails if any
+// check failed, and otherwise asks the user for confirmation to proceed, unless
+// prompting is disabled or there is no terminal to answer
+func confirmPreflightChecks(
+	cloudService string,
+	checks []preflightCheck,
+	totalNodes int,
+	instanceType string,
+	cost preflightCost,
+) error {
+	header := table.Row{"Region", "Check", "Result", "Status"}
+	t := ux.DefaultTable(fmt.Sprintf("%s pre-flight checks", cloudService), header)
+	failed := false
+	for _, check := range checks {
+		status := logging.Green.Wrap("OK")
+		switch {
+		case check.failed:
+			status = logging.Red.Wrap("FAILED")
+			failed = true
+		case check.unknown:
+			status = logging.Yellow.Wrap("UNKNOWN")
+		}
+		t.AppendRow(table.Row{check.region, check.name, check.result, status})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	switch {
+	case !cost.known:
+		ux.Logger.PrintToUser("No cost estimation available for instance type %s", instanceType)
+	case cost.live:
+		ux.Logger.PrintToUser("Estimated monthly cost for %d %s instance(s): ~%.2f USD (on-demand list prices of the selected regions)", totalNodes, instanceType, cost.total)
+	default:
+		ux.Logger.PrintToUser("Estimated monthly cost for %d %s instance(s): ~%.2f USD (approximate on-demand prices, regional prices could not be obtained)", totalNodes, instanceType, cost.total)
+	}
+	if failed {
+		return fmt.Errorf("pre-flight checks failed, no cloud resources were created")
+	}
+	if skipPreflightPrompt || !prompts.IsInteractive() {
+		return nil
+	}
+	yes, err := app.Prompt.CaptureYesNo("Do you want to proceed with the creation of cloud resources (use --skip-preflight-prompt to skip prompting)?")
+	if err != nil {
+		return err
+	}
+	if !yes {
+		return fmt.Errorf("node creation aborted by user")
+	}
+	return nil
+}
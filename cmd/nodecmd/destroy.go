@@ -46,7 +46,7 @@ If there is a static IP address attached, it will be released.`,
 	cmd.Flags().BoolVar(&destroyAll, "all", false, "destroy all existing clusters created by Avalanche CLI")
 	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
 
-	return cmd
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func removeNodeFromClustersConfig(clusterName string) error {
@@ -89,6 +89,9 @@ func removeClustersConfigFiles(clusterName string) error {
 	if err := removeClusterInventoryDir(clusterName); err != nil {
 		return err
 	}
+	if err := os.RemoveAll(app.GetClusterSecretsPath(clusterName)); err != nil {
+		return err
+	}
 	return removeNodeFromClustersConfig(clusterName)
 }
 
@@ -0,0 +1,248 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"os/user"
+
+	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
+	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/gcp"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	nodePkg "github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+var (
+	gcCloud    string
+	gcDryRun   bool
+	gcAllUsers bool
+)
+
+func newGCCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "(ALPHA Warning) Delete orphaned cloud resources created by the CLI",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node gc command scans the given cloud regions for resources created by Avalanche CLI
+(instances, static IPs and security groups, including monitoring and load test hosts)
+that are not referenced by any local cluster definition, and offers to delete them.
+
+These resources are usually left behind by failed node creations, and keep being charged.
+Only resources tagged or labelled by the CLI are considered, and by default only the ones
+created by the current OS user. GCP static IPs created by older CLI versions carry no labels,
+and are never deleted by this command.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: gcNodes,
+	}
+	cmd.Flags().StringVar(&gcCloud, "cloud", "", fmt.Sprintf("cloud service to scan (%s or %s)", constants.AWSCloudService, constants.GCPCloudService))
+	cmd.Flags().StringSliceVar(&cmdLineRegion, "region", []string{}, "scan given region(s). Use comma to separate multiple regions. Defaults to all regions")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to release cloud resources")
+	cmd.Flags().BoolVarP(&authorizeAll, "authorize-all", "y", false, "delete orphaned resources without asking for confirmation")
+	cmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "only list orphaned resources, do not delete them")
+	cmd.Flags().BoolVar(&gcAllUsers, "all-users", false, "also consider resources created by other OS users on the same cloud account")
+	return cmd
+}
+
+// cloudResourceDeleter is implemented by the cloud APIs that support gc
+type cloudResourceDeleter interface {
+	ListManagedResources(region string) ([]models.CloudResource, error)
+	DeleteManagedResource(resource models.CloudResource) error
+}
+
+func gcNodes(_ *cobra.Command, _ []string) error {
+	switch gcCloud {
+	case constants.AWSCloudService, constants.GCPCloudService:
+	case "":
+		var err error
+		gcCloud, err = app.Prompt.CaptureList(
+			"Which cloud service do you want to scan?",
+			[]string{constants.AWSCloudService, constants.GCPCloudService},
+		)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported cloud service %s", gcCloud)
+	}
+	if !(authorizeAccess || authorizeAll || nodePkg.AuthorizedAccessFromSettings(app)) && (requestCloudAuth(gcCloud) != nil) {
+		return fmt.Errorf("cloud access is required")
+	}
+	referenced, err := getReferencedCloudResources()
+	if err != nil {
+		return err
+	}
+	username := ""
+	if !gcAllUsers {
+		usr, err := user.Current()
+		if err != nil {
+			return err
+		}
+		username = usr.Username
+	}
+	deleters := map[string]cloudResourceDeleter{}
+	regions := cmdLineRegion
+	switch gcCloud {
+	case constants.AWSCloudService:
+		if len(regions) == 0 {
+			awsCloud, err := awsAPI.NewAwsCloud(awsProfile, "us-east-1")
+			if err != nil {
+				return err
+			}
+			if regions, err = awsCloud.ListRegions(); err != nil {
+				if isExpiredCredentialError(err) {
+					printExpiredCredentialsOutput(awsProfile)
+				}
+				return err
+			}
+		}
+		for _, region := range regions {
+			awsCloud, err := awsAPI.NewAwsCloud(awsProfile, region)
+			if err != nil {
+				return err
+			}
+			deleters[region] = awsCloud
+		}
+	case constants.GCPCloudService:
+		gcpClient, projectName, _, err := getGCPCloudCredentials()
+		if err != nil {
+			return err
+		}
+		gcpCloud, err := gcpAPI.NewGcpCloud(gcpClient, projectName, context.Background())
+		if err != nil {
+			return err
+		}
+		if len(regions) == 0 {
+			regions = gcpCloud.ListRegions()
+		}
+		for _, region := range regions {
+			deleters[region] = gcpCloud
+		}
+	}
+	orphans := []models.CloudResource{}
+	spinSession := ux.NewUserSpinner()
+	for _, region := range regions {
+		spinner := spinSession.SpinToUser("Scanning %s[%s] for resources created by Avalanche CLI...", gcCloud, region)
+		resources, err := deleters[region].ListManagedResources(region)
+		if err != nil {
+			ux.SpinFailWithError(spinner, "", err)
+			spinSession.Stop()
+			return err
+		}
+		ux.SpinComplete(spinner)
+		orphans = append(orphans, utils.Filter(resources, func(r models.CloudResource) bool {
+			return nodePkg.IsOrphanedCloudResource(r, referenced, username)
+		})...)
+	}
+	spinSession.Stop()
+	if len(orphans) == 0 {
+		ux.Logger.GreenCheckmarkToUser("No orphaned resources found")
+		return nil
+	}
+	header := table.Row{"Region", "Type", "ID", "Name", "Public IP", "State"}
+	t := ux.DefaultTable("Orphaned resources", header)
+	for _, r := range orphans {
+		t.AppendRow(table.Row{r.Region, r.Type, r.ID, r.Name, r.PublicIP, r.State})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	if gcDryRun {
+		return nil
+	}
+	if !authorizeAll {
+		yes, err := app.Prompt.CaptureYesNo(fmt.Sprintf("Do you want to delete the %d orphaned resource(s) listed above?", len(orphans)))
+		if err != nil {
+			return err
+		}
+		if !yes {
+			return nil
+		}
+	}
+	return deleteOrphanedCloudResources(deleters, orphans)
+}
+
+// getReferencedCloudResources gathers all instances, IPs and security groups used by local clusters
+func getReferencedCloudResources() (nodePkg.ReferencedCloudResources, error) {
+	referenced := nodePkg.ReferencedCloudResources{}
+	if !app.ClustersConfigExists() {
+		return referenced, nil
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return referenced, err
+	}
+	for _, clusterConfig := range clustersConfig.Clusters {
+		instanceIDs := append([]string{}, clusterConfig.Nodes...)
+		if clusterConfig.MonitoringInstance != "" {
+			instanceIDs = append(instanceIDs, clusterConfig.MonitoringInstance)
+		}
+		for _, loadTestInstance := range clusterConfig.LoadTestInstance {
+			instanceIDs = append(instanceIDs, loadTestInstance)
+		}
+		for _, instanceID := range instanceIDs {
+			referenced.InstanceIDs = append(referenced.InstanceIDs, instanceID)
+			if !utils.FileExists(app.GetNodeConfigPath(instanceID)) {
+				continue
+			}
+			nodeConfig, err := app.LoadClusterNodeConfig(instanceID)
+			if err != nil {
+				return referenced, err
+			}
+			referenced.PublicIPs = append(referenced.PublicIPs, nodeConfig.ElasticIP)
+			referenced.StaticIPNames = append(referenced.StaticIPNames, fmt.Sprintf("%s-%s", constants.GCPStaticIPPrefix, nodeConfig.NodeID))
+			referenced.SecurityGroups = append(referenced.SecurityGroups, nodeConfig.SecurityGroup)
+		}
+	}
+	return referenced, nil
+}
+
+// deleteOrphanedCloudResources deletes instances first, and then static IPs and security
+// groups, as the later can't be removed while still attached to an instance
+func deleteOrphanedCloudResources(deleters map[string]cloudResourceDeleter, orphans []models.CloudResource) error {
+	failed := map[string]error{}
+	for _, resourceType := range []models.CloudResourceType{
+		models.CloudResourceInstance,
+		models.CloudResourceStaticIP,
+		models.CloudResourceSecurityGroup,
+	} {
+		terminated := map[string][]string{}
+		for _, r := range utils.Filter(orphans, func(r models.CloudResource) bool { return r.Type == resourceType }) {
+			region := r.Region
+			if gcCloud == constants.GCPCloudService && r.Type == models.CloudResourceInstance {
+				region = gcpAPI.ZoneToRegion(r.Region)
+			}
+			ux.Logger.PrintToUser("Deleting %s %s in %s...", r.Type, r.ID, r.Region)
+			if err := deleters[region].DeleteManagedResource(r); err != nil {
+				failed[r.ID] = err
+				continue
+			}
+			terminated[region] = append(terminated[region], r.ID)
+			ux.Logger.GreenCheckmarkToUser("%s %s deleted", r.Type, r.ID)
+		}
+		if resourceType == models.CloudResourceInstance && gcCloud == constants.AWSCloudService {
+			// wait for instances to terminate so that IPs and security groups can be released
+			for region, instanceIDs := range terminated {
+				if awsCloud, ok := deleters[region].(*awsAPI.AwsCloud); ok {
+					if err := awsCloud.WaitForEC2Instances(instanceIDs, types.InstanceStateNameTerminated); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	if len(failed) > 0 {
+		for id, err := range failed {
+			ux.Logger.RedXToUser("Failed to delete %s: %s", id, err)
+		}
+		return fmt.Errorf("failed to delete %d orphaned resource(s)", len(failed))
+	}
+	return nil
+}
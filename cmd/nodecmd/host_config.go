@@ -0,0 +1,240 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+var (
+	hostConfigHost        string
+	hostConfigFlags       []string
+	hostConfigRemoveFlags []string
+	hostConfigDBDir       string
+	hostConfigCPUs        float64
+	hostConfigMemory      string
+	hostConfigReset       bool
+	hostConfigApply       bool
+
+	hostConfigMemoryRegex = regexp.MustCompile(`^[0-9]+[KMGT]?$`)
+	hostConfigDBDirRegex  = regexp.MustCompile(`^/[A-Za-z0-9._/-]+$`)
+	// node config keys managed by the CLI, that can't be overridden by host flags
+	hostConfigReservedFlags = []string{config.NetworkNameKey, config.TrackSubnetsKey, config.DBPathKey}
+)
+
+func newHostConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "host-config [clusterName]",
+		Short: "(ALPHA Warning) Configure AvalancheGo on a single node of the cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node host-config command overrides the AvalancheGo configuration of the node with
+cloud ID --host, for clusters whose nodes have heterogeneous hardware. Supported settings:
+extra avalanchego config flags, the path of the database (eg a dedicated disk mount),
+and CPU and memory limits of the avalanchego service.
+
+Settings are stored in the cluster definition and applied each time the cluster is synced
+or upgraded, or right away with --apply, which restarts avalanchego on the node. Flags
+removed, or a database path reset, are dropped from the node config on the next sync.
+
+If no setting is given, the current settings of the cluster nodes are shown.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: hostConfig,
+	}
+	cmd.Flags().StringVar(&hostConfigHost, "host", "", "cloud ID of the node to configure")
+	cmd.Flags().StringArrayVar(&hostConfigFlags, "flag", nil, "avalanchego config flag to set, as key=value (can be repeated)")
+	cmd.Flags().StringArrayVar(&hostConfigRemoveFlags, "remove-flag", nil, "avalanchego config flag to remove (can be repeated)")
+	cmd.Flags().StringVar(&hostConfigDBDir, "db-dir", "", "absolute path of the avalanchego database on the node")
+	cmd.Flags().Float64Var(&hostConfigCPUs, "cpus", 0, "number of CPUs avalanchego can use (0 for no limit)")
+	cmd.Flags().StringVar(&hostConfigMemory, "memory", "", "memory avalanchego can use, eg 16G (empty for no limit)")
+	cmd.Flags().BoolVar(&hostConfigReset, "reset", false, "remove the settings of the node, going back to the cluster ones")
+	cmd.Flags().BoolVar(&hostConfigApply, "apply", false, "apply the settings now, restarting avalanchego on the node")
+	return cobrautils.MarkClusterState(cmd)
+}
+
+func hostConfig(cmd *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if clusterConfig.Local {
+		return notImplementedForLocal("host-config")
+	}
+	settingFlags := []string{"flag", "remove-flag", "db-dir", "cpus", "memory"}
+	changed := false
+	for _, flag := range settingFlags {
+		changed = changed || cmd.Flags().Changed(flag)
+	}
+	if !changed && !hostConfigReset && !hostConfigApply {
+		printHostNodeConfigs(clusterConfig)
+		return nil
+	}
+	if hostConfigHost == "" {
+		return fmt.Errorf("--host is required to change the node settings")
+	}
+	if !clusterConfig.IsAvalancheGoHost(hostConfigHost) {
+		return fmt.Errorf("avalanchego node %s not found in cluster %s", hostConfigHost, clusterName)
+	}
+	hostNodeConfig := clusterConfig.GetHostNodeConfig(hostConfigHost)
+	if hostConfigReset {
+		hostNodeConfig = models.HostNodeConfig{}
+	}
+	flags := map[string]interface{}{}
+	maps.Copy(flags, hostNodeConfig.Flags)
+	for _, flag := range hostConfigFlags {
+		key, value, err := parseHostConfigFlag(flag)
+		if err != nil {
+			return err
+		}
+		flags[key] = value
+	}
+	for _, key := range hostConfigRemoveFlags {
+		delete(flags, key)
+	}
+	hostNodeConfig.Flags = flags
+	if len(flags) == 0 {
+		hostNodeConfig.Flags = nil
+	}
+	if cmd.Flags().Changed("db-dir") {
+		if hostConfigDBDir != "" && !hostConfigDBDirRegex.MatchString(hostConfigDBDir) {
+			return fmt.Errorf("invalid --db-dir %q, expected an absolute path", hostConfigDBDir)
+		}
+		hostNodeConfig.DBDir = hostConfigDBDir
+	}
+	if cmd.Flags().Changed("cpus") {
+		if hostConfigCPUs < 0 {
+			return fmt.Errorf("invalid --cpus %v, expected a positive number", hostConfigCPUs)
+		}
+		hostNodeConfig.CPUs = hostConfigCPUs
+	}
+	if cmd.Flags().Changed("memory") {
+		if hostConfigMemory != "" && !hostConfigMemoryRegex.MatchString(hostConfigMemory) {
+			return fmt.Errorf("invalid --memory %q, expected a number of bytes with an optional K, M, G or T suffix", hostConfigMemory)
+		}
+		hostNodeConfig.Memory = hostConfigMemory
+	}
+	if clusterConfig.HostsNodeConfig == nil {
+		clusterConfig.HostsNodeConfig = map[string]models.HostNodeConfig{}
+	}
+	if hostNodeConfig.IsEmpty() {
+		delete(clusterConfig.HostsNodeConfig, hostConfigHost)
+	} else {
+		clusterConfig.HostsNodeConfig[hostConfigHost] = hostNodeConfig
+	}
+	if err := app.SetClusterConfig(clusterName, clusterConfig); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("AvalancheGo settings updated for node %s", hostConfigHost)
+	ux.Logger.PrintToUser("  %s: %s", hostConfigHost, hostNodeConfigString(hostNodeConfig))
+	if !hostConfigApply {
+		ux.Logger.PrintToUser("Settings will be applied the next time the cluster is synced or upgraded, or use --apply")
+		return nil
+	}
+	return applyHostConfig(clusterName, hostConfigHost, hostNodeConfig)
+}
+
+// applyHostConfig restarts avalanchego on the node with [cloudID], applying [hostNodeConfig]
+func applyHostConfig(clusterName string, cloudID string, hostNodeConfig models.HostNodeConfig) error {
+	host, err := node.GetHostWithCloudID(app, clusterName, cloudID)
+	if err != nil {
+		return err
+	}
+	if host == nil {
+		return fmt.Errorf("node %s not found in the inventory of cluster %s", cloudID, clusterName)
+	}
+	defer node.DisconnectHosts([]*models.Host{host})
+	spinSession := ux.NewUserSpinner()
+	defer spinSession.Stop()
+	spinner := spinSession.SpinToUser("Applying AvalancheGo settings to node %s", cloudID)
+	if err := ssh.RunSSHStopNode(host); err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		return err
+	}
+	if err := ssh.RunSSHApplyHostNodeConfig(host, hostNodeConfig); err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		return err
+	}
+	if err := ssh.RunSSHStartNode(host); err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		return err
+	}
+	ux.SpinComplete(spinner)
+	return nil
+}
+
+// parseHostConfigFlag parses a key=value avalanchego config flag. Values are decoded as
+// JSON when possible, so numbers, booleans and objects keep their type in the node config
+func parseHostConfigFlag(flag string) (string, interface{}, error) {
+	key, value, found := strings.Cut(flag, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return "", nil, fmt.Errorf("invalid --flag %q, expected key=value", flag)
+	}
+	for _, reserved := range hostConfigReservedFlags {
+		if key == reserved {
+			return "", nil, fmt.Errorf("flag %s is managed by the CLI and can't be overridden", key)
+		}
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+		return key, decoded, nil
+	}
+	return key, value, nil
+}
+
+func printHostNodeConfigs(clusterConfig models.ClusterConfig) {
+	if len(clusterConfig.HostsNodeConfig) == 0 {
+		ux.Logger.PrintToUser("All the cluster nodes use the cluster AvalancheGo settings")
+		return
+	}
+	ux.Logger.PrintToUser("Node AvalancheGo settings:")
+	cloudIDs := maps.Keys(clusterConfig.HostsNodeConfig)
+	sort.Strings(cloudIDs)
+	for _, cloudID := range cloudIDs {
+		ux.Logger.PrintToUser("  %s: %s", cloudID, hostNodeConfigString(clusterConfig.HostsNodeConfig[cloudID]))
+	}
+}
+
+func hostNodeConfigString(hostNodeConfig models.HostNodeConfig) string {
+	settings := []string{}
+	flagKeys := maps.Keys(hostNodeConfig.Flags)
+	sort.Strings(flagKeys)
+	for _, key := range flagKeys {
+		value, err := json.Marshal(hostNodeConfig.Flags[key])
+		if err != nil {
+			value = []byte(fmt.Sprint(hostNodeConfig.Flags[key]))
+		}
+		settings = append(settings, fmt.Sprintf("%s=%s", key, value))
+	}
+	if hostNodeConfig.DBDir != "" {
+		settings = append(settings, fmt.Sprintf("db-dir=%s", hostNodeConfig.DBDir))
+	}
+	if hostNodeConfig.CPUs != 0 {
+		settings = append(settings, fmt.Sprintf("cpus=%s", strconv.FormatFloat(hostNodeConfig.CPUs, 'f', -1, 64)))
+	}
+	if hostNodeConfig.Memory != "" {
+		settings = append(settings, fmt.Sprintf("memory=%s", hostNodeConfig.Memory))
+	}
+	if len(settings) == 0 {
+		return "defaults"
+	}
+	return strings.Join(settings, " ")
+}
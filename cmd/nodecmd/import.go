@@ -4,6 +4,7 @@ package nodecmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -21,10 +22,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	importInventoryPath string
+	importClusterName   string
+)
+
 func newImportCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "import [clusterName]",
-		Short: "(ALPHA Warning) Import cluster configuration from a file",
+		Short: "(ALPHA Warning) Import cluster configuration from a file or an existing set of nodes",
 		Long: `(ALPHA Warning) This command is currently in experimental mode.
 
 The node import command imports cluster configuration and its nodes configuration from a text file
@@ -33,17 +39,50 @@ created from the node export command.
 Prior to calling this command, call node whitelist command to have your SSH public key and IP whitelisted by
 the cluster owner. This will enable you to use avalanche-cli commands to manage the imported cluster.
 
+With --inventory, the command instead adopts avalanchego nodes that were not created by the CLI,
+listed on an ansible YAML inventory:
+
+  all:
+    vars:
+      ansible_user: admin
+      ansible_ssh_private_key_file: ~/.ssh/validators.pem
+    hosts:
+      validator-1:
+        ansible_host: 1.2.3.4
+
+Each host is inspected over SSH to find its avalanchego version, node ID, network, tracked subnets and
+the location of its config and staking files. Only the staking certificate is copied locally.
+Hosts that do not follow the CLI node setup can be checked with node status, node ssh, or
+validate the primary network and blockchains, but can't be modified with node sync or node upgrade.
+
 Please note, that this imported cluster will be considered as EXTERNAL by avalanche-cli, so some commands
 affecting cloud nodes like node create or node destroy will be not applicable to it.`,
-		Args: cobrautils.ExactArgs(1),
+		Args: cobrautils.RangeArgs(0, 1),
 		RunE: importFile,
 	}
 	cmd.Flags().StringVar(&clusterFileName, "file", "", "specify the file to export the cluster configuration to")
-	return cmd
+	cmd.Flags().StringVar(&importInventoryPath, "inventory", "", "adopt the avalanchego nodes listed on the given ansible YAML inventory")
+	cmd.Flags().StringVar(&importClusterName, "name", "", "name of the imported cluster")
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func importFile(_ *cobra.Command, args []string) error {
-	clusterName := args[0]
+	clusterName := importClusterName
+	if len(args) > 0 {
+		if clusterName != "" && clusterName != args[0] {
+			return fmt.Errorf("cluster name given both as argument (%s) and with --name (%s)", args[0], clusterName)
+		}
+		clusterName = args[0]
+	}
+	if clusterName == "" {
+		return errors.New("cluster name is required, either as argument or with --name")
+	}
+	if importInventoryPath != "" {
+		if clusterFileName != "" {
+			return errors.New("--file and --inventory can't be used together")
+		}
+		return importFromInventory(clusterName, importInventoryPath)
+	}
 	if clusterExists, err := node.CheckClusterExists(app, clusterName); clusterExists || err != nil {
 		ux.Logger.RedXToUser("cluster %s already exists, please use a different name", clusterName)
 		return nil
@@ -122,6 +161,10 @@ func importFile(_ *cobra.Command, args []string) error {
 	if err := app.WriteClustersConfigFile(&clustersConfig); err != nil {
 		ux.Logger.RedXToUser("error saving clusters config: %v", err)
 	}
+	if err := node.ApplyClusterSSHConfig(app, clusterName); err != nil {
+		ux.Logger.RedXToUser("error applying cluster SSH settings: %v", err)
+		return err
+	}
 	ux.Logger.GreenCheckmarkToUser("cluster [%s] imported successfully", clusterName)
 	return nil
 }
@@ -0,0 +1,214 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	sdkutils "github.com/ava-labs/avalanche-cli/sdk/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"golang.org/x/exp/slices"
+)
+
+// importFromInventory adopts the avalanchego nodes listed on the ansible YAML inventory
+// at [inventoryPath] as the external cluster [clusterName]
+func importFromInventory(clusterName string, inventoryPath string) error {
+	if clusterExists, err := node.CheckClusterExists(app, clusterName); err != nil {
+		return err
+	} else if clusterExists {
+		return fmt.Errorf("cluster %s already exists, please use a different name", clusterName)
+	}
+	hosts, err := ansible.ParseYAMLInventory(utils.ExpandHome(inventoryPath))
+	if err != nil {
+		return err
+	}
+	if len(hosts) == 0 {
+		return fmt.Errorf("no hosts found on inventory %s", inventoryPath)
+	}
+	cloudIDs := []string{}
+	for _, host := range hosts {
+		cloudID := host.NodeID
+		if sdkutils.DirExists(app.GetNodeInstanceDirPath(cloudID)) {
+			return fmt.Errorf("node %s already exists and belongs to an existing cluster, can't import", cloudID)
+		}
+		host.NodeID, err = models.HostCloudIDToAnsibleID(constants.ImportedCloudService, cloudID)
+		if err != nil {
+			return err
+		}
+		host.SSHCommonArgs = constants.AnsibleSSHUseAgentParams
+		cloudIDs = append(cloudIDs, cloudID)
+	}
+	defer node.DisconnectHosts(hosts)
+
+	importedHosts := map[string]*node.ImportedHost{}
+	stakingCerts := map[string][]byte{}
+	spinSession := ux.NewUserSpinner()
+	for i, host := range hosts {
+		spinner := spinSession.SpinToUser(utils.ScriptLog(cloudIDs[i], "Inspecting avalanchego installation"))
+		importedHost, certBytes, err := node.InspectHost(host)
+		if err != nil {
+			ux.SpinFailWithError(spinner, "", err)
+			spinSession.Stop()
+			return err
+		}
+		ux.SpinComplete(spinner)
+		importedHosts[cloudIDs[i]] = importedHost
+		stakingCerts[cloudIDs[i]] = certBytes
+	}
+	spinSession.Stop()
+
+	networkID := importedHosts[cloudIDs[0]].NetworkID
+	for _, cloudID := range cloudIDs {
+		if importedHosts[cloudID].NetworkID != networkID {
+			return fmt.Errorf(
+				"hosts are on different networks: %s has network ID %d, %s has network ID %d",
+				cloudIDs[0],
+				networkID,
+				cloudID,
+				importedHosts[cloudID].NetworkID,
+			)
+		}
+	}
+	network := models.NetworkFromNetworkID(networkID)
+	if network.Kind == models.Undefined || network.Kind == models.Local {
+		network = models.NewDevnetNetwork(fmt.Sprintf("http://%s:%d", hosts[0].IP, constants.AvalancheGoAPIPort), networkID)
+	}
+	subnets, unknownSubnets, err := getImportedSubnets(network, importedHosts)
+	if err != nil {
+		return err
+	}
+
+	clusterConfig := models.ClusterConfig{
+		Nodes:    cloudIDs,
+		Network:  models.NewNetworkFromCluster(network, clusterName),
+		Subnets:  subnets,
+		External: true,
+		HostsSSH: map[string]models.SSHConfig{},
+	}
+	for i, host := range hosts {
+		cloudID := cloudIDs[i]
+		nodeConfig := models.NodeConfig{
+			NodeID:       cloudID,
+			ElasticIP:    host.IP,
+			CertPath:     host.SSHPrivateKeyPath,
+			CloudService: constants.ImportedCloudService,
+			UseStaticIP:  true,
+		}
+		if err := app.CreateNodeCloudConfigFile(cloudID, &nodeConfig); err != nil {
+			return err
+		}
+		certPath := filepath.Join(app.GetNodeInstanceDirPath(cloudID), constants.StakerCertFileName)
+		if err := os.WriteFile(certPath, stakingCerts[cloudID], constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+		if err := node.SaveImportedHost(app, cloudID, importedHosts[cloudID]); err != nil {
+			return err
+		}
+		forwardAgent := host.SSHForwardAgent
+		clusterConfig.HostsSSH[cloudID] = models.SSHConfig{
+			User:         host.SSHUser,
+			Port:         host.SSHPort,
+			ProxyJump:    host.SSHProxyJump,
+			ForwardAgent: &forwardAgent,
+		}
+	}
+	inventoryDir := app.GetAnsibleInventoryDirPath(clusterName)
+	if err := os.MkdirAll(inventoryDir, os.ModePerm); err != nil {
+		return err
+	}
+	if err := ansible.WriteAnsibleInventory(inventoryDir, hosts); err != nil {
+		return err
+	}
+	clustersConfig, err := app.GetClustersConfig()
+	if err != nil {
+		return err
+	}
+	if clustersConfig.Clusters == nil {
+		clustersConfig.Clusters = map[string]models.ClusterConfig{}
+	}
+	clustersConfig.Clusters[clusterName] = clusterConfig
+	if err := app.WriteClustersConfigFile(&clustersConfig); err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Cluster %s (%s) EXTERNAL", logging.LightBlue.Wrap(clusterName), network.Name())
+	unmanaged := []string{}
+	for _, cloudID := range cloudIDs {
+		importedHost := importedHosts[cloudID]
+		ux.Logger.PrintToUser("  %s [%s] %s", logging.Green.Wrap(cloudID), importedHost.NodeID, importedHost.AvalancheGoVersion)
+		ux.Logger.PrintToUser("    Setup: %s", importedHost.ServiceManager)
+		if importedHost.ConfigFile != "" {
+			ux.Logger.PrintToUser("    Config File: %s", importedHost.ConfigFile)
+		}
+		ux.Logger.PrintToUser("    Data Dir: %s", importedHost.DataDir)
+		ux.Logger.PrintToUser("    Staking Files: %s", strings.Join([]string{
+			importedHost.StakingCertFile,
+			importedHost.StakingKeyFile,
+			importedHost.SignerKeyFile,
+		}, ", "))
+		if len(importedHost.TrackedSubnets) > 0 {
+			ux.Logger.PrintToUser("    Tracked Subnets: %s", strings.Join(importedHost.TrackedSubnets, ", "))
+		}
+		if !importedHost.ManagedLayout() {
+			unmanaged = append(unmanaged, cloudID)
+		}
+	}
+	ux.Logger.PrintToUser("")
+	if len(unknownSubnets) > 0 {
+		ux.Logger.PrintToUser("Subnets %s are tracked by the cluster but not known to the CLI. Import them with avalanche blockchain import", strings.Join(unknownSubnets, ", "))
+	}
+	if len(unmanaged) > 0 {
+		ux.Logger.PrintToUser(
+			"Hosts %s do not follow the CLI node setup: node sync and node upgrade are not available for them",
+			strings.Join(unmanaged, ", "),
+		)
+	}
+	ux.Logger.GreenCheckmarkToUser("cluster [%s] imported successfully", clusterName)
+	return nil
+}
+
+// getImportedSubnets returns the names of the blockchains configured on the CLI whose subnets
+// are tracked by the [importedHosts] on [network], and the IDs of the tracked subnets without
+// a blockchain configuration
+func getImportedSubnets(network models.Network, importedHosts map[string]*node.ImportedHost) ([]string, []string, error) {
+	trackedSubnets := []string{}
+	for _, importedHost := range importedHosts {
+		for _, subnetID := range importedHost.TrackedSubnets {
+			if !slices.Contains(trackedSubnets, subnetID) {
+				trackedSubnets = append(trackedSubnets, subnetID)
+			}
+		}
+	}
+	blockchainNames, err := app.GetBlockchainNames()
+	if err != nil {
+		return nil, nil, err
+	}
+	subnets := []string{}
+	knownSubnets := []string{}
+	for _, blockchainName := range blockchainNames {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			return nil, nil, err
+		}
+		subnetID := sc.Networks[network.Name()].SubnetID.String()
+		if slices.Contains(trackedSubnets, subnetID) {
+			subnets = append(subnets, blockchainName)
+			knownSubnets = append(knownSubnets, subnetID)
+		}
+	}
+	unknownSubnets := utils.Filter(trackedSubnets, func(subnetID string) bool {
+		return !slices.Contains(knownSubnets, subnetID)
+	})
+	slices.Sort(unknownSubnets)
+	return subnets, unknownSubnets, nil
+}
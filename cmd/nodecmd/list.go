@@ -26,7 +26,7 @@ The node list command lists all clusters together with their nodes.`,
 		RunE: list,
 	}
 
-	return cmd
+	return cobrautils.MarkReadOnly(cmd)
 }
 
 func list(_ *cobra.Command, _ []string) error {
@@ -75,7 +75,11 @@ func list(_ *cobra.Command, _ []string) error {
 			if rolesStr != "" {
 				rolesStr = " [" + rolesStr + "]"
 			}
-			ux.Logger.PrintToUser("  Node %s (%s) %s%s", cloudID, nodeIDs[i], nodeConfig.ElasticIP, rolesStr)
+			httpsStr := ""
+			if httpsEndpoint, ok := clusterConf.HTTPSEndpoints[cloudID]; ok {
+				httpsStr = " " + httpsEndpoint
+			}
+			ux.Logger.PrintToUser("  Node %s (%s) %s%s%s", cloudID, nodeIDs[i], nodeConfig.ElasticIP, rolesStr, httpsStr)
 		}
 	}
 	return nil
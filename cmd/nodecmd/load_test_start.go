@@ -331,7 +331,11 @@ func startLoadTest(_ *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		if err := ssh.RunSSHSetupPrometheusConfig(monitoringHosts[0], avalancheGoPorts, machinePorts, ltPorts); err != nil {
+		targetRegions, err := getPrometheusTargetRegions(clusterName)
+		if err != nil {
+			return err
+		}
+		if err := ssh.RunSSHSetupPrometheusConfig(monitoringHosts[0], avalancheGoPorts, machinePorts, ltPorts, targetRegions); err != nil {
 			return err
 		}
 		if err := docker.RestartDockerComposeService(monitoringHosts[0], utils.GetRemoteComposeFile(), "prometheus", constants.SSHLongRunningScriptTimeout); err != nil {
@@ -38,7 +38,7 @@ separate cloud server created to host the load test.`,
 		RunE: stopLoadTest,
 	}
 	cmd.Flags().StringSliceVar(&loadTestsToStop, "load-test", []string{}, "stop specified load test node(s). Use comma to separate multiple load test instance names")
-	return cmd
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func getLoadTestInstancesInCluster(clusterName string) ([]string, error) {
@@ -146,7 +146,7 @@ func newLocalStatusCmd() *cobra.Command {
 	cmd.Flags().StringVar(&blockchainName, "subnet", "", "specify the blockchain the node is syncing with")
 	cmd.Flags().StringVar(&blockchainName, "blockchain", "", "specify the blockchain the node is syncing with")
 
-	return cmd
+	return cobrautils.MarkReadOnly(cmd)
 }
 
 func localStartNode(_ *cobra.Command, args []string) error {
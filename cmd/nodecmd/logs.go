@@ -0,0 +1,113 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/monitoring"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+type logsFlags struct {
+	clusterName string
+	chain       string
+	since       time.Duration
+	grep        string
+	pageSize    int
+}
+
+var logsCmdFlags logsFlags
+
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "(ALPHA Warning) Queries the logs of all the nodes of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node logs command queries the logs of all the nodes of a cluster from the cluster
+monitoring host, and prints them merged in timestamp order.
+
+--chain selects the logs of the C, P or X chains, of the main avalanchego log, or of a
+blockchain deployed to the cluster. If not given, all of them are queried.
+The cluster must have been created with monitoring enabled.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: logs,
+	}
+	cmd.Flags().StringVar(&logsCmdFlags.clusterName, "cluster", "", "cluster to query the logs of")
+	cmd.Flags().StringVar(&logsCmdFlags.chain, "chain", "", "C, P, X, main or the name of a blockchain deployed to the cluster")
+	cmd.Flags().DurationVar(&logsCmdFlags.since, "since", time.Hour, "query logs not older than this duration")
+	cmd.Flags().StringVar(&logsCmdFlags.grep, "grep", "", "only show log lines containing this text")
+	cmd.Flags().IntVar(&logsCmdFlags.pageSize, "page-size", monitoring.DefaultLokiPageSize, "number of log lines obtained on each query to the monitoring host")
+	return cmd
+}
+
+func logs(_ *cobra.Command, _ []string) error {
+	if logsCmdFlags.clusterName == "" {
+		return fmt.Errorf("--cluster is required")
+	}
+	if logsCmdFlags.since <= 0 {
+		return fmt.Errorf("--since must be positive")
+	}
+	clusterName := logsCmdFlags.clusterName
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if clusterConfig.Local {
+		return notImplementedForLocal("logs")
+	}
+	if clusterConfig.MonitoringInstance == "" {
+		return fmt.Errorf("cluster %s has no monitoring host. Logs can only be queried from clusters created with monitoring enabled", clusterName)
+	}
+	query := monitoring.LokiQuery{
+		Grep: logsCmdFlags.grep,
+	}
+	switch logsCmdFlags.chain {
+	case "", "C", "c", "P", "p", "X", "x", "main":
+		query.Chain = logsCmdFlags.chain
+	default:
+		_, chainID, err := getDeployedSubnetInfo(clusterName, logsCmdFlags.chain)
+		if err != nil {
+			return err
+		}
+		query.ChainID = chainID
+	}
+	monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetMonitoringInventoryDir(clusterName))
+	if err != nil {
+		return err
+	}
+	if len(monitoringHosts) == 0 {
+		return fmt.Errorf("monitoring host for cluster %s not found", clusterName)
+	}
+	lokiURL := "http://" + monitoringHosts[0].IP + ":" + strconv.Itoa(constants.AvalancheGoLokiPort)
+	end := time.Now()
+	start := end.Add(-logsCmdFlags.since)
+	numLines := 0
+	if err := monitoring.QueryLoki(lokiURL, query, start, end, logsCmdFlags.pageSize, func(line monitoring.LogLine) error {
+		numLines++
+		ux.Logger.PrintToUser("%s %s %s",
+			line.Timestamp.Format(time.RFC3339Nano),
+			logging.LightBlue.Wrap(fmt.Sprintf("[%s %s %s]", line.Host, line.NodeID, line.Job)),
+			line.Line,
+		)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failure querying logs from monitoring host %s: %w", monitoringHosts[0].IP, err)
+	}
+	if numLines == 0 {
+		ux.Logger.PrintToUser("No log lines found for cluster %s since %s", clusterName, start.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,460 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
+	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/gcp"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/docker"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+var monitoringRegion string
+
+// avalanche node monitor
+func newMonitorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "Manage the monitoring of a cluster",
+		Long: `The node monitor command suite provides a collection of tools for managing the
+Prometheus, Loki and Grafana monitoring of a cluster, or its export to Datadog or CloudWatch.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	// node monitor enable
+	cmd.AddCommand(newMonitorEnableCmd())
+	return cmd
+}
+
+func newMonitorEnableCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enable [clusterName]",
+		Short: "(ALPHA Warning) Set up monitoring for an existing cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node monitor enable command sets up monitoring for a cluster that was created without it.
+
+A separate monitoring cloud instance is created on the cloud of the cluster, unless
+--existing-monitoring-instance is given, in which case the given monitoring instance
+is reused. Prometheus, Loki and Grafana are configured on the monitoring host from the current
+cluster inventory, and promtail and node exporter are installed on all the cluster nodes.
+
+With --monitoring datadog or --monitoring cloudwatch, no monitoring instance is created. The
+vendor agent is installed on all the cluster nodes instead, exporting their metrics and logs, and
+the cluster dashboard is created on the backend. Nodes added later to the cluster get the agent
+too. Run it again after adding subnets to the cluster to update the dashboard.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: enableMonitoring,
+	}
+	cmd.Flags().StringVar(&nodeType, "node-type", "", "cloud instance type for the monitoring host")
+	cmd.Flags().StringVar(&monitoringRegion, "region", "", "create the monitoring host in a given region (defaults to a region of the cluster)")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to create cloud resources")
+	cmd.Flags().BoolVar(&useStaticIP, "use-static-ip", true, "attach static Public IP on the monitoring host")
+	cmd.Flags().StringVar(&existingMonitoringInstance, "existing-monitoring-instance", "", "use the given monitoring instance of another cluster instead of creating a new one")
+	cmd.Flags().StringVar(&monitoringBackend, monitoringFlag, constants.MonitoringPrometheus, "monitoring backend: prometheus, datadog or cloudwatch")
+	addTelemetryFlags(cmd)
+	return cobrautils.MarkClusterState(cmd)
+}
+
+func enableMonitoring(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if clusterConfig.Local {
+		return notImplementedForLocal("monitor enable")
+	}
+	if err := checkMonitoringBackend(clusterName); err != nil {
+		return err
+	}
+	if backend := telemetryAgentBackend(); backend != "" {
+		return enableTelemetry(clusterName, clusterConfig, backend)
+	}
+	if clusterConfig.MonitoringInstance != "" {
+		return fmt.Errorf("cluster %s already has monitoring host %s", clusterName, clusterConfig.MonitoringInstance)
+	}
+	clusterNodes, err := node.GetClusterNodes(app, clusterName)
+	if err != nil {
+		return err
+	}
+	if len(clusterNodes) == 0 {
+		return fmt.Errorf("no nodes found in the cluster %s", clusterName)
+	}
+	cloudSecurityGroupList, err := getCloudSecurityGroupList(clusterNodes)
+	if err != nil {
+		return err
+	}
+	if len(cloudSecurityGroupList) == 0 {
+		return fmt.Errorf("no cloud nodes found in the cluster %s", clusterName)
+	}
+	cloudService := cloudSecurityGroupList[0].cloud
+	if len(utils.Filter(cloudSecurityGroupList, func(sg regionSecurityGroup) bool { return sg.cloud != cloudService })) > 0 {
+		return fmt.Errorf("monitoring can't be enabled for clusters spanning multiple clouds")
+	}
+	sgRegions := []string{}
+	for _, sg := range cloudSecurityGroupList {
+		sgRegions = append(sgRegions, sg.region)
+	}
+	if existingMonitoringInstance != "" {
+		monitoringNodeConfig, err := app.LoadClusterNodeConfig(existingMonitoringInstance)
+		if err != nil {
+			return err
+		}
+		if !monitoringNodeConfig.IsMonitor {
+			return fmt.Errorf("instance %s is not a monitoring host", existingMonitoringInstance)
+		}
+		if monitoringNodeConfig.CloudService != cloudService {
+			return fmt.Errorf("monitoring host %s is not on the cluster cloud %s", existingMonitoringInstance, cloudService)
+		}
+		ux.Logger.PrintToUser("Will be using monitoring instance %s for cluster %s...", existingMonitoringInstance, clusterName)
+	} else {
+		ux.Logger.PrintToUser("Creating a separate monitoring instance for cluster %s...", clusterName)
+		if monitoringRegion == "" {
+			monitoringRegion = sgRegions[0]
+		}
+		if !slices.Contains(sgRegions, monitoringRegion) {
+			sgRegions = append(sgRegions, monitoringRegion)
+		}
+		nodeType, err = setCloudInstanceType(cloudService)
+		if err != nil {
+			return err
+		}
+	}
+	// cluster wide settings are overwritten when adding an external host to the cluster
+	publicHTTPPortAccess = bool(clusterConfig.HTTPAccess)
+	var monitoringNodeConfig models.RegionConfig
+	switch cloudService {
+	case constants.AWSCloudService:
+		if !(authorizeAccess || node.AuthorizedAccessFromSettings(app)) && (requestCloudAuth(constants.AWSCloudService) != nil) {
+			return fmt.Errorf("cloud access is required")
+		}
+		monitoringNodeConfig, monitoringRegion, err = setupAWSMonitoringInstance(cloudSecurityGroupList, sgRegions)
+	case constants.GCPCloudService:
+		if !(authorizeAccess || node.AuthorizedAccessFromSettings(app)) && (requestCloudAuth(constants.GCPCloudService) != nil) {
+			return fmt.Errorf("cloud access is required")
+		}
+		monitoringNodeConfig, monitoringRegion, err = setupGCPMonitoringInstance(clusterName)
+	default:
+		return fmt.Errorf("cloud service %s is not supported", cloudService)
+	}
+	if err != nil {
+		return err
+	}
+	if existingMonitoringInstance == "" {
+		if err := saveExternalHostConfig(monitoringNodeConfig, monitoringRegion, cloudService, clusterName, constants.MonitorRole, ""); err != nil {
+			return err
+		}
+	} else if err := addNodeToClustersConfig(models.UndefinedNetwork, existingMonitoringInstance, clusterName, false, true, constants.MonitorRole, ""); err != nil {
+		return err
+	}
+	monitoringInventoryPath := app.GetMonitoringInventoryDir(clusterName)
+	if err := ansible.CreateAnsibleHostInventory(monitoringInventoryPath, monitoringNodeConfig.CertFilePath, cloudService, map[string]string{monitoringNodeConfig.InstanceIDs[0]: monitoringNodeConfig.PublicIPs[0]}, nil); err != nil {
+		return err
+	}
+	monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(monitoringInventoryPath)
+	if err != nil {
+		return err
+	}
+	if len(monitoringHosts) != 1 {
+		return fmt.Errorf("expected only one monitoring host, found %d", len(monitoringHosts))
+	}
+	monitoringHost := monitoringHosts[0]
+	if existingMonitoringInstance == "" {
+		failedHosts := waitForHosts([]*models.Host{monitoringHost})
+		if failedHosts.Len() > 0 {
+			for _, result := range failedHosts.GetResults() {
+				ux.Logger.PrintToUser("Instance %s failed to provision with error %s. Please check instance logs for more information", result.NodeID, result.Err)
+			}
+			return fmt.Errorf("failed to provision node(s) %s", failedHosts.GetNodeList())
+		}
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	avalancheGoPorts, machinePorts, ltPorts, targetRegions, err := getMonitoringHostPrometheusTargets(monitoringNodeConfig.InstanceIDs[0])
+	if err != nil {
+		return err
+	}
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	spinSession := ux.NewUserSpinner()
+	wg.Add(1)
+	go func(nodeResults *models.NodeResults, monitoringHost *models.Host) {
+		defer wg.Done()
+		if err := monitoringHost.Connect(0); err != nil {
+			nodeResults.AddResult(monitoringHost.NodeID, nil, err)
+			return
+		}
+		spinner := spinSession.SpinToUser(utils.ScriptLog(monitoringHost.NodeID, "Setup Monitoring"))
+		var err error
+		if existingMonitoringInstance == "" {
+			err = setupMonitoringHost(monitoringHost, clusterName, avalancheGoPorts, machinePorts, ltPorts, targetRegions)
+		} else {
+			err = updateMonitoringHostTargets(monitoringHost, avalancheGoPorts, machinePorts, ltPorts, targetRegions)
+		}
+		if err != nil {
+			nodeResults.AddResult(monitoringHost.NodeID, nil, err)
+			ux.SpinFailWithError(spinner, "", err)
+			return
+		}
+		ux.SpinComplete(spinner)
+	}(&wgResults, monitoringHost)
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, host *models.Host) {
+			defer wg.Done()
+			if err := host.Connect(0); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+				return
+			}
+			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, "Setup Node Monitoring"))
+			if err := setupNodeMonitoring(host, monitoringHost.IP); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+				ux.SpinFailWithError(spinner, "", err)
+				return
+			}
+			ux.SpinComplete(spinner)
+		}(&wgResults, host)
+	}
+	wg.Wait()
+	spinSession.Stop()
+	if wgResults.HasErrors() {
+		return fmt.Errorf("failed to enable monitoring on node(s) %s", wgResults.GetErrorHostMap())
+	}
+	if err := waitForMonitoringEndpoint(monitoringHost); err != nil {
+		return err
+	}
+	getMonitoringHint(monitoringHost.IP)
+	return nil
+}
+
+// setupAWSMonitoringInstance creates a monitoring instance at [monitoringRegion], or loads the
+// existing one, and grants it access to the cluster security groups
+func setupAWSMonitoringInstance(cloudSecurityGroupList []regionSecurityGroup, sgRegions []string) (models.RegionConfig, string, error) {
+	var (
+		monitoringNodeConfig models.RegionConfig
+		err                  error
+	)
+	region := monitoringRegion
+	if existingMonitoringInstance != "" {
+		monitoringNodeConfig, region, err = getNodeCloudConfig(existingMonitoringInstance)
+		if err != nil {
+			return models.RegionConfig{}, "", err
+		}
+		if !slices.Contains(sgRegions, region) {
+			sgRegions = append(sgRegions, region)
+		}
+	}
+	ec2SvcMap := map[string]*awsAPI.AwsCloud{}
+	for _, sgRegion := range sgRegions {
+		regionEc2SvcMap, err := getAWSMonitoringEC2Svc(awsProfile, sgRegion)
+		if err != nil {
+			return models.RegionConfig{}, "", err
+		}
+		ec2SvcMap[sgRegion] = regionEc2SvcMap[sgRegion]
+	}
+	if existingMonitoringInstance == "" {
+		arch, err := ec2SvcMap[region].GetInstanceTypeArch(nodeType)
+		if err != nil {
+			return models.RegionConfig{}, "", err
+		}
+		ami, err := ec2SvcMap[region].GetUbuntuAMIID(arch, constants.UbuntuVersionLTS)
+		if err != nil {
+			return models.RegionConfig{}, "", err
+		}
+		monitoringCloudConfig, err := createAWSInstances(
+			map[string]*awsAPI.AwsCloud{region: ec2SvcMap[region]},
+			nodeType,
+			map[string]NumNodes{region: {1, 0}},
+			[]string{region},
+			map[string]string{region: ami},
+			true,
+			publicHTTPPortAccess,
+		)
+		if err != nil {
+			return models.RegionConfig{}, "", err
+		}
+		monitoringNodeConfig = monitoringCloudConfig[region]
+	}
+	if len(monitoringNodeConfig.PublicIPs) == 0 {
+		monitoringPublicIPMap, err := ec2SvcMap[region].GetInstancePublicIPs(monitoringNodeConfig.InstanceIDs)
+		if err != nil {
+			return models.RegionConfig{}, "", err
+		}
+		monitoringNodeConfig.PublicIPs = []string{monitoringPublicIPMap[monitoringNodeConfig.InstanceIDs[0]]}
+	}
+	for _, sg := range cloudSecurityGroupList {
+		if err := AddMonitoringSecurityGroupRule(ec2SvcMap, monitoringNodeConfig.PublicIPs[0], sg.securityGroup, sg.region); err != nil {
+			return models.RegionConfig{}, "", err
+		}
+	}
+	return monitoringNodeConfig, region, nil
+}
+
+// setupGCPMonitoringInstance creates a monitoring instance at [monitoringRegion], or loads the
+// existing one, and grants it access to the cluster network
+func setupGCPMonitoringInstance(clusterName string) (models.RegionConfig, string, error) {
+	gcpClient, projectName, _, err := getGCPCloudCredentials()
+	if err != nil {
+		return models.RegionConfig{}, "", err
+	}
+	gcpCloud, err := gcpAPI.NewGcpCloud(gcpClient, projectName, context.Background())
+	if err != nil {
+		return models.RegionConfig{}, "", err
+	}
+	var monitoringNodeConfig models.RegionConfig
+	zone := monitoringRegion
+	if existingMonitoringInstance != "" {
+		monitoringNodeConfig, zone, err = getNodeCloudConfig(existingMonitoringInstance)
+		if err != nil {
+			return models.RegionConfig{}, "", err
+		}
+	} else {
+		// cluster nodes are stored by zone, but a region may also be given
+		if slices.Contains(gcpCloud.ListRegions(), zone) {
+			zone, err = gcpCloud.GetRandomZone(zone)
+			if err != nil {
+				return models.RegionConfig{}, "", err
+			}
+		}
+		imageID, err := gcpCloud.GetUbuntuImageID(gcpAPI.GetMachineTypeArch(nodeType))
+		if err != nil {
+			return models.RegionConfig{}, "", err
+		}
+		monitoringCloudConfig, err := createGCPInstance(gcpCloud, nodeType, map[string]NumNodes{zone: {1, 0}}, imageID, clusterName, true)
+		if err != nil {
+			return models.RegionConfig{}, "", err
+		}
+		monitoringNodeConfig = monitoringCloudConfig[zone]
+	}
+	if len(monitoringNodeConfig.PublicIPs) == 0 {
+		monitoringPublicIPMap, err := gcpCloud.GetInstancePublicIPs(zone, monitoringNodeConfig.InstanceIDs)
+		if err != nil {
+			return models.RegionConfig{}, "", err
+		}
+		monitoringNodeConfig.PublicIPs = []string{monitoringPublicIPMap[monitoringNodeConfig.InstanceIDs[0]]}
+	}
+	if err := grantAccessToPublicIPViaFirewall(gcpCloud, projectName, monitoringNodeConfig.PublicIPs[0], "monitoring"); err != nil {
+		return models.RegionConfig{}, "", err
+	}
+	return monitoringNodeConfig, zone, nil
+}
+
+// getMonitoringHostPrometheusTargets returns the prometheus targets of all clusters
+// monitored by [monitoringInstance]
+func getMonitoringHostPrometheusTargets(monitoringInstance string) ([]string, []string, []string, map[string]string, error) {
+	avalancheGoPorts := []string{}
+	machinePorts := []string{}
+	ltPorts := []string{}
+	targetRegions := map[string]string{}
+	clustersConfig, err := app.GetClustersConfig()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	for clusterName, clusterConfig := range clustersConfig.Clusters {
+		if clusterConfig.MonitoringInstance != monitoringInstance {
+			continue
+		}
+		clusterAvalancheGoPorts, clusterMachinePorts, clusterLtPorts, err := getPrometheusTargets(clusterName)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		clusterTargetRegions, err := getPrometheusTargetRegions(clusterName)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		avalancheGoPorts = append(avalancheGoPorts, clusterAvalancheGoPorts...)
+		machinePorts = append(machinePorts, clusterMachinePorts...)
+		ltPorts = append(ltPorts, clusterLtPorts...)
+		maps.Copy(targetRegions, clusterTargetRegions)
+	}
+	return avalancheGoPorts, machinePorts, ltPorts, targetRegions, nil
+}
+
+// setupMonitoringHost installs and configures Prometheus, Loki and Grafana on a new monitoring host
+func setupMonitoringHost(monitoringHost *models.Host, clusterName string, avalancheGoPorts, machinePorts, ltPorts []string, targetRegions map[string]string) error {
+	if err := app.SetupMonitoringEnv(); err != nil {
+		return err
+	}
+	if err := ssh.RunSSHSetupDockerService(monitoringHost); err != nil {
+		return err
+	}
+	ux.Logger.Info("SetupMonitoringEnv RunSSHSetupDockerService completed")
+	if err := ssh.RunSSHSetupMonitoringFolders(monitoringHost); err != nil {
+		return err
+	}
+	ux.Logger.Info("RunSSHSetupMonitoringFolders completed")
+	if err := ssh.RunSSHCopyMonitoringDashboards(monitoringHost, app.GetMonitoringDashboardDir()+"/"); err != nil {
+		return err
+	}
+	ux.Logger.Info("RunSSHCopyMonitoringDashboards completed")
+	if err := ssh.RunSSHSetupPrometheusConfig(monitoringHost, avalancheGoPorts, machinePorts, ltPorts, targetRegions); err != nil {
+		return err
+	}
+	ux.Logger.Info("RunSSHSetupPrometheusConfig completed")
+	if err := ssh.RunSSHSetupLokiConfig(monitoringHost, constants.AvalancheGoLokiPort); err != nil {
+		return err
+	}
+	ux.Logger.Info("RunSSHSetupLokiConfig completed")
+	rulesInputs, alertmanagerInputs, exporterInputs, err := getAlertingInputs(clusterName)
+	if err != nil {
+		return err
+	}
+	if err := ssh.RunSSHSetupAlertingConfig(monitoringHost, rulesInputs, alertmanagerInputs, exporterInputs); err != nil {
+		return err
+	}
+	ux.Logger.Info("RunSSHSetupAlertingConfig completed")
+	if err := docker.ComposeSSHSetupMonitoring(monitoringHost); err != nil {
+		return err
+	}
+	ux.Logger.Info("ComposeSSHSetupMonitoring completed")
+	return nil
+}
+
+// updateMonitoringHostTargets updates the prometheus targets of an already set up monitoring host
+func updateMonitoringHostTargets(monitoringHost *models.Host, avalancheGoPorts, machinePorts, ltPorts []string, targetRegions map[string]string) error {
+	if err := ssh.RunSSHSetupPrometheusConfig(monitoringHost, avalancheGoPorts, machinePorts, ltPorts, targetRegions); err != nil {
+		return err
+	}
+	return docker.RestartDockerComposeService(monitoringHost, utils.GetRemoteComposeFile(), "prometheus", constants.SSHLongRunningScriptTimeout)
+}
+
+// setupNodeMonitoring points promtail of an already running node to [monitoringHostIP],
+// installing promtail and node exporter if the node was set up without monitoring
+func setupNodeMonitoring(host *models.Host, monitoringHostIP string) error {
+	cloudID := host.GetCloudID()
+	nodeID, err := getNodeID(app.GetNodeInstanceDirPath(cloudID))
+	if err != nil {
+		return err
+	}
+	if err := ssh.RunSSHSetupPromtailConfig(host, monitoringHostIP, constants.AvalancheGoLokiPort, cloudID, nodeID.String(), ""); err != nil {
+		return err
+	}
+	withMonitoring, err := docker.WasNodeSetupWithMonitoring(host)
+	if err != nil {
+		return err
+	}
+	if withMonitoring {
+		return docker.RestartDockerComposeService(host, utils.GetRemoteComposeFile(), "promtail", constants.SSHLongRunningScriptTimeout)
+	}
+	return docker.ComposeSSHSetupNodeMonitoring(host)
+}
@@ -42,6 +42,12 @@ rest of the commands to maintain your node and make your node a Subnet Validator
 	cmd.AddCommand(newUpgradeCmd())
 	// node ssh
 	cmd.AddCommand(newSSHCmd())
+	// node ssh-config
+	cmd.AddCommand(newSSHConfigCmd())
+	// node ssh-proxy
+	cmd.AddCommand(newSSHProxyCmd())
+	// node host-config
+	cmd.AddCommand(newHostConfigCmd())
 	// node scp
 	cmd.AddCommand(newSCPCmd())
 	// node whitelist
@@ -60,5 +66,23 @@ rest of the commands to maintain your node and make your node a Subnet Validator
 	cmd.AddCommand(newImportCmd())
 	// node local
 	cmd.AddCommand(newLocalCmd())
+	// node gc
+	cmd.AddCommand(newGCCmd())
+	// node logs
+	cmd.AddCommand(newLogsCmd())
+	// node alerts
+	cmd.AddCommand(newAlertsCmd())
+	// node monitor
+	cmd.AddCommand(newMonitorCmd())
+	// node costs
+	cmd.AddCommand(newCostsCmd())
+	// node ssl
+	cmd.AddCommand(newSSLCmd())
+	// node rpc-auth
+	cmd.AddCommand(newRPCAuthCmd())
+	// node state
+	cmd.AddCommand(newStateCmd())
+	// node bluegreen
+	cmd.AddCommand(newBlueGreenCmd())
 	return cmd
 }
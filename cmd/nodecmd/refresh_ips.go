@@ -26,7 +26,7 @@ and updates the local node information used by CLI commands.`,
 
 	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
 
-	return cmd
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func refreshIPs(_ *cobra.Command, args []string) error {
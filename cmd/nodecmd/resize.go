@@ -39,7 +39,7 @@ The node resize command can change the amount of CPU, memory and disk space avai
 	cmd.Flags().StringVar(&nodeType, "node-type", "", "Node type to resize (e.g. t3.2xlarge)")
 	cmd.Flags().StringVar(&diskSize, "disk-size", "", "Disk size to resize in Gb (e.g. 1000Gb)")
 	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
-	return cmd
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func preResizeChecks(clusterName string) error {
@@ -0,0 +1,351 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/remoteconfig"
+	"github.com/ava-labs/avalanche-cli/pkg/rpcauth"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+type rpcAuthFlags struct {
+	clusterName    string
+	blockchainName string
+	token          string
+	showTokens     bool
+}
+
+var (
+	rpcAuthCmdFlags rpcAuthFlags
+	// tokens are embedded into the proxy config, so they are restricted to header safe chars
+	rpcAuthTokenRegex = regexp.MustCompile(`^[A-Za-z0-9._~+/=-]{16,}$`)
+)
+
+// avalanche node rpc-auth
+func newRPCAuthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rpc-auth",
+		Short: "Manage auth tokens for the blockchain RPC served by the nodes of a cluster",
+		Long: `The node rpc-auth command suite provides a collection of tools for locking down the
+blockchain RPC that cluster nodes serve over HTTPS (see avalanche node ssl setup), so it can
+only be used by clients that send the blockchain auth token as header:
+
+  Authorization: Bearer <token>
+
+The CLI sends the tokens by itself on all the commands that hit the cluster HTTPS endpoints,
+over HTTP and WebSocket, and configures them on the relayer. Tokens are kept on the cluster
+secrets file, only readable by the user.
+
+Only the HTTPS endpoints are locked down. Use the cloud firewall (see avalanche node whitelist)
+to restrict the access to the plain avalanchego API port.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	// node rpc-auth enable
+	cmd.AddCommand(newRPCAuthEnableCmd())
+	// node rpc-auth rotate
+	cmd.AddCommand(newRPCAuthRotateCmd())
+	// node rpc-auth disable
+	cmd.AddCommand(newRPCAuthDisableCmd())
+	// node rpc-auth list
+	cmd.AddCommand(newRPCAuthListCmd())
+	return cmd
+}
+
+func newRPCAuthEnableCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enable",
+		Short: "(ALPHA Warning) Require an auth token for the RPC of a blockchain",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node rpc-auth enable command makes the HTTPS endpoints of a cluster reject the requests to
+the RPC of a blockchain that do not carry its auth token. A random token is generated, unless
+one is given with --token, eg to share it between clusters.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: rpcAuthEnable,
+	}
+	addRPCAuthFlags(cmd)
+	cmd.Flags().StringVar(&rpcAuthCmdFlags.token, "token", "", "use the given token instead of a random one")
+	return cmd
+}
+
+func newRPCAuthRotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "(ALPHA Warning) Replace the auth token of the RPC of a blockchain",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node rpc-auth rotate command replaces the auth token of a blockchain on the HTTPS endpoints
+of a cluster. The previous token stops working as soon as the nodes are reconfigured.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: rpcAuthRotate,
+	}
+	addRPCAuthFlags(cmd)
+	cmd.Flags().StringVar(&rpcAuthCmdFlags.token, "token", "", "use the given token instead of a random one")
+	return cmd
+}
+
+func newRPCAuthDisableCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "disable",
+		Short: "(ALPHA Warning) Stop requiring an auth token for the RPC of a blockchain",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node rpc-auth disable command makes the HTTPS endpoints of a cluster serve the RPC of a
+blockchain without auth token again.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: rpcAuthDisable,
+	}
+	addRPCAuthFlags(cmd)
+	return cmd
+}
+
+func newRPCAuthListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the blockchains whose RPC requires an auth token",
+		Long: `The node rpc-auth list command lists the blockchains whose RPC requires an auth token on
+the HTTPS endpoints of a cluster. Tokens are only shown with --show-tokens.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: rpcAuthList,
+	}
+	cmd.Flags().StringVar(&rpcAuthCmdFlags.clusterName, "cluster", "", "cluster to list auth tokens for")
+	cmd.Flags().BoolVar(&rpcAuthCmdFlags.showTokens, "show-tokens", false, "show the tokens")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func addRPCAuthFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&rpcAuthCmdFlags.clusterName, "cluster", "", "cluster to manage auth tokens for")
+	cmd.Flags().StringVar(&rpcAuthCmdFlags.blockchainName, "blockchain", "", "blockchain whose RPC is locked down")
+}
+
+func rpcAuthEnable(_ *cobra.Command, _ []string) error {
+	return setRPCAuthToken(false)
+}
+
+func rpcAuthRotate(_ *cobra.Command, _ []string) error {
+	return setRPCAuthToken(true)
+}
+
+// setRPCAuthToken sets a new auth token for the blockchain on the cluster. If [rotate], the
+// blockchain is expected to already have one, otherwise it is expected not to
+func setRPCAuthToken(rotate bool) error {
+	clusterConfig, blockchainID, currentTokens, err := loadRPCAuthSettings()
+	if err != nil {
+		return err
+	}
+	_, hasToken := currentTokens[blockchainID.String()]
+	switch {
+	case rotate && !hasToken:
+		return fmt.Errorf("auth token is not enabled for blockchain %s on cluster %s", rpcAuthCmdFlags.blockchainName, rpcAuthCmdFlags.clusterName)
+	case !rotate && hasToken:
+		return fmt.Errorf("auth token is already enabled for blockchain %s on cluster %s. Use rotate to replace it", rpcAuthCmdFlags.blockchainName, rpcAuthCmdFlags.clusterName)
+	}
+	token := rpcAuthCmdFlags.token
+	if token != "" {
+		if !rpcAuthTokenRegex.MatchString(token) {
+			return fmt.Errorf("invalid token: it must have at least 16 characters, restricted to letters, digits and ._~+/=-")
+		}
+	} else {
+		token, err = rpcauth.NewToken()
+		if err != nil {
+			return err
+		}
+	}
+	tokens := maps.Clone(currentTokens)
+	tokens[blockchainID.String()] = token
+	if err := applyRPCAuthTokens(clusterConfig, tokens); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("RPC of blockchain %s on cluster %s now requires the header:", rpcAuthCmdFlags.blockchainName, rpcAuthCmdFlags.clusterName)
+	ux.Logger.PrintToUser("  Authorization: %s", logging.Green.Wrap(rpcauth.AuthorizationHeader(token)))
+	return nil
+}
+
+func rpcAuthDisable(_ *cobra.Command, _ []string) error {
+	clusterConfig, blockchainID, currentTokens, err := loadRPCAuthSettings()
+	if err != nil {
+		return err
+	}
+	if _, ok := currentTokens[blockchainID.String()]; !ok {
+		ux.Logger.PrintToUser("Auth token is not enabled for blockchain %s on cluster %s", rpcAuthCmdFlags.blockchainName, rpcAuthCmdFlags.clusterName)
+		return nil
+	}
+	tokens := maps.Clone(currentTokens)
+	delete(tokens, blockchainID.String())
+	if err := applyRPCAuthTokens(clusterConfig, tokens); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("RPC of blockchain %s on cluster %s no longer requires an auth token", rpcAuthCmdFlags.blockchainName, rpcAuthCmdFlags.clusterName)
+	return nil
+}
+
+func rpcAuthList(_ *cobra.Command, _ []string) error {
+	clusterName := rpcAuthCmdFlags.clusterName
+	if clusterName == "" {
+		return fmt.Errorf("--cluster is required")
+	}
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	tokens, err := node.GetRPCAuthTokens(app, clusterName, clusterConfig)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		ux.Logger.PrintToUser("No blockchain RPC requires an auth token on cluster %s", clusterName)
+		return nil
+	}
+	// blockchain IDs are shown together with their names, when known
+	blockchainNames := map[string]string{}
+	for _, blockchainName := range clusterConfig.Subnets {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			continue
+		}
+		if networkInfo, ok := sc.Networks[clusterConfig.Network.Name()]; ok {
+			blockchainNames[networkInfo.BlockchainID.String()] = blockchainName
+		}
+	}
+	blockchainIDs := maps.Keys(tokens)
+	sort.Strings(blockchainIDs)
+	ux.Logger.PrintToUser("Blockchain RPC requiring an auth token on cluster %s:", clusterName)
+	for _, blockchainID := range blockchainIDs {
+		token := tokens[blockchainID]
+		if !rpcAuthCmdFlags.showTokens {
+			token = strings.Repeat("*", 8)
+		}
+		name := blockchainNames[blockchainID]
+		if name != "" {
+			name = " (" + name + ")"
+		}
+		ux.Logger.PrintToUser("  %s%s %s", blockchainID, name, token)
+	}
+	return nil
+}
+
+// loadRPCAuthSettings validates the flags of the rpc-auth commands, returning the config of
+// the cluster, the ID of the blockchain to manage the token for, and the current tokens of
+// the cluster
+func loadRPCAuthSettings() (models.ClusterConfig, ids.ID, map[string]string, error) {
+	clusterName := rpcAuthCmdFlags.clusterName
+	if clusterName == "" {
+		return models.ClusterConfig{}, ids.Empty, nil, fmt.Errorf("--cluster is required")
+	}
+	if rpcAuthCmdFlags.blockchainName == "" {
+		return models.ClusterConfig{}, ids.Empty, nil, fmt.Errorf("--blockchain is required")
+	}
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return models.ClusterConfig{}, ids.Empty, nil, err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return models.ClusterConfig{}, ids.Empty, nil, err
+	}
+	if clusterConfig.Local {
+		return models.ClusterConfig{}, ids.Empty, nil, fmt.Errorf("rpc-auth is not supported by local clusters")
+	}
+	if len(clusterConfig.HTTPSEndpoints) == 0 {
+		return models.ClusterConfig{}, ids.Empty, nil, fmt.Errorf("cluster %s has no HTTPS endpoints. Set them up first with avalanche node ssl setup", clusterName)
+	}
+	sc, err := app.LoadSidecar(rpcAuthCmdFlags.blockchainName)
+	if err != nil {
+		return models.ClusterConfig{}, ids.Empty, nil, fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	networkInfo, ok := sc.Networks[clusterConfig.Network.Name()]
+	if !ok || networkInfo.BlockchainID == ids.Empty {
+		return models.ClusterConfig{}, ids.Empty, nil, fmt.Errorf("blockchain %s is not deployed to %s", rpcAuthCmdFlags.blockchainName, clusterConfig.Network.Name())
+	}
+	tokens, err := node.GetRPCAuthTokens(app, clusterName, clusterConfig)
+	if err != nil {
+		return models.ClusterConfig{}, ids.Empty, nil, err
+	}
+	return clusterConfig, networkInfo.BlockchainID, tokens, nil
+}
+
+// applyRPCAuthTokens reconfigures the reverse proxies of the cluster HTTPS endpoints to
+// require [tokens], and records them on the cluster config once all the nodes are updated
+func applyRPCAuthTokens(clusterConfig models.ClusterConfig, tokens map[string]string) error {
+	clusterName := rpcAuthCmdFlags.clusterName
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts(hosts)
+	hosts = utils.Filter(hosts, func(h *models.Host) bool {
+		_, ok := clusterConfig.HTTPSEndpoints[h.GetCloudID()]
+		return ok
+	})
+	proxies := []reverseProxy{}
+	if clusterConfig.HTTPSLoadBalancer != "" {
+		lbHost, err := getLoadBalancerHost(clusterName, clusterConfig)
+		if err != nil {
+			return err
+		}
+		defer node.DisconnectHosts([]*models.Host{lbHost})
+		if len(hosts) > 0 {
+			proxies = append(proxies, reverseProxy{
+				host:        lbHost,
+				domain:      strings.TrimPrefix(clusterConfig.HTTPSEndpoints[hosts[0].GetCloudID()], "https://"),
+				upstreamIPs: utils.Map(hosts, func(h *models.Host) string { return h.IP }),
+			})
+		}
+	} else {
+		for _, host := range hosts {
+			proxies = append(proxies, reverseProxy{
+				host:   host,
+				domain: strings.TrimPrefix(clusterConfig.HTTPSEndpoints[host.GetCloudID()], "https://"),
+			})
+		}
+	}
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	spinSession := ux.NewUserSpinner()
+	for _, proxy := range proxies {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, proxy reverseProxy) {
+			defer wg.Done()
+			host := proxy.host
+			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, "Update RPC auth"))
+			userCert, err := ssh.ReverseProxyHasUserCert(host)
+			if err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+				ux.SpinFailWithError(spinner, "", err)
+				return
+			}
+			inputs := remoteconfig.PrepareReverseProxyInputs(proxy.domain, clusterConfig.HTTPSEmail, userCert, proxy.upstreamIPs, tokens)
+			if err := ssh.RunSSHSetupReverseProxy(host, inputs, "", ""); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+				ux.SpinFailWithError(spinner, "", err)
+				return
+			}
+			ux.SpinComplete(spinner)
+		}(&wgResults, proxy)
+	}
+	wg.Wait()
+	spinSession.Stop()
+	if wgResults.HasErrors() {
+		return fmt.Errorf("failed to update RPC auth for node(s) %s", wgResults.GetErrorHostMap())
+	}
+	return node.SetRPCAuthTokens(app, clusterName, tokens)
+}
@@ -183,6 +183,8 @@ func scpHosts(op ClusterOp, hosts []*models.Host, sourcePath, destPath string, c
 			}
 			scpCmd := ""
 			scpCmd, err = utils.GetSCPCommandString(
+				host.SSHUser,
+				host.GetSSHOptions(),
 				host.SSHPrivateKeyPath,
 				prefixIP,
 				prefixPath,
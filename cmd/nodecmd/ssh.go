@@ -138,7 +138,7 @@ func sshHosts(hosts []*models.Host, cmd string, clusterConf models.ClusterConfig
 					}
 				}
 				defer wg.Done()
-				cmd := utils.Command(utils.GetSSHConnectionString(host.IP, host.SSHPrivateKeyPath), cmd)
+				cmd := utils.Command(host.GetSSHConnectionString(), cmd)
 				outBuf, errBuf := utils.SetupRealtimeCLIOutput(cmd, false, false)
 				if !isParallel {
 					_, _ = utils.SetupRealtimeCLIOutput(cmd, true, true)
@@ -177,7 +177,7 @@ func sshHosts(hosts []*models.Host, cmd string, clusterConf models.ClusterConfig
 			return fmt.Errorf("no nodes found")
 		default:
 			selectedHost := hosts[0]
-			splitCmdLine := strings.Split(utils.GetSSHConnectionString(selectedHost.IP, selectedHost.SSHPrivateKeyPath), " ")
+			splitCmdLine := strings.Split(selectedHost.GetSSHConnectionString(), " ")
 			cmd := exec.Command(splitCmdLine[0], splitCmdLine[1:]...)
 			cmd.Env = os.Environ()
 			cmd.Stdin = os.Stdin
@@ -216,7 +216,7 @@ func printClusterConnectionString(clusterName string, networkName string) error
 		clusterHosts = append(clusterHosts, monitoringHosts...)
 	}
 	for _, host := range clusterHosts {
-		ux.Logger.PrintToUser(utils.GetSSHConnectionString(host.IP, host.SSHPrivateKeyPath))
+		ux.Logger.PrintToUser(host.GetSSHConnectionString())
 	}
 	ux.Logger.PrintToUser("")
 	return nil
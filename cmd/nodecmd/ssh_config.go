@@ -0,0 +1,158 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sshConfigHost         string
+	sshConfigUser         string
+	sshConfigPort         uint
+	sshConfigProxyJump    string
+	sshConfigForwardAgent bool
+	sshConfigReset        bool
+)
+
+func newSSHConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssh-config [clusterName]",
+		Short: "(ALPHA Warning) Configure how CLI connects to the cluster nodes by SSH",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node ssh-config command sets the SSH user, port, bastion host and agent forwarding
+used to reach the nodes of the cluster. Settings apply to all the cluster nodes, unless
+--host is given, in which case they apply only to that node, overriding the cluster ones.
+Use --proxy-jump none to connect directly to a node when the cluster uses a bastion host.
+
+If no setting is given, the current SSH settings of the cluster are shown.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: sshConfig,
+	}
+	cmd.Flags().StringVar(&sshConfigHost, "host", "", "apply the settings only to the node with this cloud ID")
+	cmd.Flags().StringVar(&sshConfigUser, "user", "", "SSH user")
+	cmd.Flags().UintVar(&sshConfigPort, "port", 0, "SSH port")
+	cmd.Flags().StringVar(&sshConfigProxyJump, "proxy-jump", "", "bastion host to connect through, as [user@]host[:port]")
+	cmd.Flags().BoolVar(&sshConfigForwardAgent, "forward-agent", false, "forward the local SSH agent to the nodes")
+	cmd.Flags().BoolVar(&sshConfigReset, "reset", false, "remove the SSH settings, going back to the defaults")
+	return cobrautils.MarkClusterState(cmd)
+}
+
+func sshConfig(cmd *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if clusterConfig.Local {
+		return fmt.Errorf("SSH settings are not supported for local cluster %s", clusterName)
+	}
+	if sshConfigHost != "" && !isClusterHost(clusterConfig, sshConfigHost) {
+		return fmt.Errorf("node %s not found in cluster %s", sshConfigHost, clusterName)
+	}
+	if strings.Contains(sshConfigProxyJump, ",") {
+		return fmt.Errorf("only one bastion host is supported on --proxy-jump")
+	}
+	settingFlags := []string{"user", "port", "proxy-jump", "forward-agent"}
+	changed := false
+	for _, flag := range settingFlags {
+		changed = changed || cmd.Flags().Changed(flag)
+	}
+	if !changed && !sshConfigReset {
+		printSSHConfig(clusterConfig)
+		return nil
+	}
+	sshConf := clusterConfig.SSH
+	if sshConfigHost != "" {
+		sshConf = clusterConfig.HostsSSH[sshConfigHost]
+	}
+	if sshConfigReset {
+		sshConf = models.SSHConfig{}
+	}
+	if cmd.Flags().Changed("user") {
+		sshConf.User = sshConfigUser
+	}
+	if cmd.Flags().Changed("port") {
+		sshConf.Port = sshConfigPort
+	}
+	if cmd.Flags().Changed("proxy-jump") {
+		sshConf.ProxyJump = sshConfigProxyJump
+	}
+	if cmd.Flags().Changed("forward-agent") {
+		forwardAgent := sshConfigForwardAgent
+		sshConf.ForwardAgent = &forwardAgent
+	}
+	if sshConfigHost == "" {
+		clusterConfig.SSH = sshConf
+	} else {
+		if clusterConfig.HostsSSH == nil {
+			clusterConfig.HostsSSH = map[string]models.SSHConfig{}
+		}
+		if sshConf == (models.SSHConfig{}) {
+			delete(clusterConfig.HostsSSH, sshConfigHost)
+		} else {
+			clusterConfig.HostsSSH[sshConfigHost] = sshConf
+		}
+	}
+	if err := app.SetClusterConfig(clusterName, clusterConfig); err != nil {
+		return err
+	}
+	if err := node.ApplyClusterSSHConfig(app, clusterName); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("SSH settings updated for cluster %s", clusterName)
+	printSSHConfig(clusterConfig)
+	return nil
+}
+
+func isClusterHost(clusterConfig models.ClusterConfig, cloudID string) bool {
+	for _, hostCloudID := range clusterConfig.GetCloudIDs() {
+		if hostCloudID == cloudID {
+			return true
+		}
+	}
+	for _, hostCloudID := range clusterConfig.LoadTestInstance {
+		if hostCloudID == cloudID {
+			return true
+		}
+	}
+	return false
+}
+
+func printSSHConfig(clusterConfig models.ClusterConfig) {
+	ux.Logger.PrintToUser("Cluster SSH settings: %s", sshConfigString(clusterConfig.SSH))
+	for cloudID, hostSSHConfig := range clusterConfig.HostsSSH {
+		ux.Logger.PrintToUser("  %s: %s", cloudID, sshConfigString(hostSSHConfig))
+	}
+}
+
+func sshConfigString(sshConf models.SSHConfig) string {
+	settings := []string{}
+	if sshConf.User != "" {
+		settings = append(settings, fmt.Sprintf("user=%s", sshConf.User))
+	}
+	if sshConf.Port != 0 {
+		settings = append(settings, fmt.Sprintf("port=%d", sshConf.Port))
+	}
+	if sshConf.ProxyJump != "" {
+		settings = append(settings, fmt.Sprintf("proxy-jump=%s", sshConf.ProxyJump))
+	}
+	if sshConf.ForwardAgent != nil {
+		settings = append(settings, fmt.Sprintf("forward-agent=%t", *sshConf.ForwardAgent))
+	}
+	if len(settings) == 0 {
+		return "defaults"
+	}
+	return strings.Join(settings, " ")
+}
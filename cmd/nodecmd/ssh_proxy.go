@@ -0,0 +1,54 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const sshProxyDialTimeout = 30 * time.Second
+
+func newSSHProxyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   constants.SSHProxyCmd + " [host] [port]",
+		Short: "Tunnel stdin and stdout to a node through the SSH proxy",
+		Long: `The node ssh-proxy command connects to the given host and port through the SSH proxy
+set in the CLI config (or ALL_PROXY), and pipes stdin and stdout to the connection.
+
+It is run by ssh as ProxyCommand, so the proxy credentials are never given on the
+command line.`,
+		Args:   cobrautils.ExactArgs(2),
+		RunE:   sshProxy,
+		Hidden: true,
+	}
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func sshProxy(_ *cobra.Command, args []string) error {
+	conn, err := utils.DialTCP(net.JoinHostPort(args[0], args[1]), sshProxyDialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(conn, os.Stdin)
+		if tcpConn, ok := conn.(interface{ CloseWrite() error }); ok {
+			_ = tcpConn.CloseWrite()
+		}
+	}()
+	go func() {
+		_, _ = io.Copy(os.Stdout, conn)
+		close(done)
+	}()
+	<-done
+	return nil
+}
@@ -0,0 +1,492 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
+	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/gcp"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/remoteconfig"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/pingcap/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+const httpsEndpointWaitTimeout = 2 * time.Minute
+
+type sslSetupFlags struct {
+	clusterName       string
+	domains           []string
+	email             string
+	certFile          string
+	keyFile           string
+	skipFirewall      bool
+	loadBalancer      bool
+	keepPublicAPIPort bool
+}
+
+// reverseProxy is a reverse proxy installed on [host], serving over HTTPS at [domain] the
+// avalanchego API of the nodes at [upstreamIPs], or of [host] itself if none is given
+type reverseProxy struct {
+	host        *models.Host
+	domain      string
+	upstreamIPs []string
+}
+
+var sslSetupCmdFlags sslSetupFlags
+
+// avalanche node ssl
+func newSSLCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssl",
+		Short: "Manage HTTPS access to the API of the nodes of a cluster",
+		Long: `The node ssl command suite provides a collection of tools for serving the
+avalanchego API of cluster nodes over HTTPS at custom domains.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	// node ssl setup
+	cmd.AddCommand(newSSLSetupCmd())
+	return cmd
+}
+
+func newSSLSetupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "setup",
+		Short: "(ALPHA Warning) Serve the API of cluster nodes over HTTPS at custom domains",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node ssl setup command installs a reverse proxy on the API nodes of a cluster (or on
+all its nodes, if it has no API nodes), serving the avalanchego API over HTTPS at the
+given domains, eg:
+
+  avalanche node ssl setup --cluster mycluster --domain rpc.example.com
+
+One --domain is needed for each node, assigned in the order shown by avalanche node list.
+The DNS records of the domains must point to the public IPs of the nodes.
+
+With --load-balancer, a single --domain is served by a reverse proxy on the cluster
+monitoring host, that balances the requests among the healthy API nodes. The DNS record
+of the domain must point to the public IP of the monitoring host.
+
+Certificates are obtained from Let's Encrypt, unless --cert-file and --key-file are given.
+Ports 80 and 443 are opened on the cluster cloud firewall, as required by Let's Encrypt
+and HTTPS. Public access to the plain HTTP API port 9650 is then closed, unless
+--keep-public-api-port is given.
+
+The HTTPS endpoints are recorded on the cluster, and used as the RPC endpoints of the
+blockchains the cluster tracks.`,
+		Args: cobrautils.ExactArgs(0),
+		RunE: sslSetup,
+	}
+	cmd.Flags().StringVar(&sslSetupCmdFlags.clusterName, "cluster", "", "cluster to set up HTTPS for")
+	cmd.Flags().StringSliceVar(&sslSetupCmdFlags.domains, "domain", nil, "domain to serve the API of a node at. Give one per node")
+	cmd.Flags().StringVar(&sslSetupCmdFlags.email, "email", "", "contact email for the Let's Encrypt account")
+	cmd.Flags().StringVar(&sslSetupCmdFlags.certFile, "cert-file", "", "use the given PEM certificate (chain) instead of Let's Encrypt")
+	cmd.Flags().StringVar(&sslSetupCmdFlags.keyFile, "key-file", "", "PEM private key of --cert-file")
+	cmd.Flags().BoolVar(&sslSetupCmdFlags.skipFirewall, "skip-firewall", false, "do not open ports 80 and 443 on the cluster cloud firewall")
+	cmd.Flags().BoolVar(&sslSetupCmdFlags.loadBalancer, "load-balancer", false, "serve a single domain from the monitoring host, load balancing among the API nodes")
+	cmd.Flags().BoolVar(&sslSetupCmdFlags.keepPublicAPIPort, "keep-public-api-port", false, fmt.Sprintf("do not close public access to the HTTP API port %d", constants.AvalancheGoAPIPort))
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	return cmd
+}
+
+func sslSetup(_ *cobra.Command, _ []string) error {
+	clusterName := sslSetupCmdFlags.clusterName
+	if clusterName == "" {
+		return fmt.Errorf("--cluster is required")
+	}
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if clusterConfig.Local {
+		return notImplementedForLocal("ssl setup")
+	}
+	userCert := sslSetupCmdFlags.certFile != "" || sslSetupCmdFlags.keyFile != ""
+	if userCert {
+		if sslSetupCmdFlags.certFile == "" || sslSetupCmdFlags.keyFile == "" {
+			return errors.New("--cert-file and --key-file must be given together")
+		}
+		for _, path := range []string{sslSetupCmdFlags.certFile, sslSetupCmdFlags.keyFile} {
+			if !utils.FileExists(utils.ExpandHome(path)) {
+				return fmt.Errorf("file %s not found", path)
+			}
+		}
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	defer node.DisconnectHosts(hosts)
+	hosts = getSSLHosts(clusterConfig, hosts)
+	if len(hosts) == 0 {
+		return fmt.Errorf("no nodes found in cluster %s", clusterName)
+	}
+	domains := sslSetupCmdFlags.domains
+	loadBalancer := ""
+	var proxies []reverseProxy
+	if sslSetupCmdFlags.loadBalancer {
+		if len(domains) != 1 {
+			return fmt.Errorf("expected one --domain for the load balancer, but got %d", len(domains))
+		}
+		lbHost, err := getLoadBalancerHost(clusterName, clusterConfig)
+		if err != nil {
+			return err
+		}
+		defer node.DisconnectHosts([]*models.Host{lbHost})
+		loadBalancer = lbHost.GetCloudID()
+		proxies = []reverseProxy{{
+			host:        lbHost,
+			domain:      domains[0],
+			upstreamIPs: utils.Map(hosts, func(h *models.Host) string { return h.IP }),
+		}}
+	} else {
+		if len(domains) != len(hosts) {
+			ux.Logger.PrintToUser("Nodes to serve over HTTPS:")
+			for _, host := range hosts {
+				ux.Logger.PrintToUser("  %s %s", host.GetCloudID(), host.IP)
+			}
+			return fmt.Errorf("expected %d --domain values, one for each node, but got %d", len(hosts), len(domains))
+		}
+		for i, host := range hosts {
+			proxies = append(proxies, reverseProxy{host: host, domain: domains[i]})
+		}
+	}
+	if err := checkSSLDomains(proxies, userCert); err != nil {
+		return err
+	}
+	if !sslSetupCmdFlags.skipFirewall {
+		if clusterConfig.External {
+			ux.Logger.PrintToUser("Cluster %s is external. Make sure ports %d and %d of the nodes are open", clusterName, constants.ACMEChallengePort, constants.HTTPSPort)
+		} else if err := openHTTPSPorts(clusterName); err != nil {
+			return err
+		}
+	}
+
+	authTokens, err := node.GetRPCAuthTokens(app, clusterName, clusterConfig)
+	if err != nil {
+		return err
+	}
+	if err := setupReverseProxies(
+		authTokens,
+		proxies,
+		sslSetupCmdFlags.email,
+		userCert,
+		utils.ExpandHome(sslSetupCmdFlags.certFile),
+		utils.ExpandHome(sslSetupCmdFlags.keyFile),
+	); err != nil {
+		return err
+	}
+
+	httpsEndpoints := map[string]string{}
+	for i, host := range hosts {
+		if loadBalancer != "" {
+			httpsEndpoints[host.GetCloudID()] = node.GetHTTPSEndpoint(domains[0])
+		} else {
+			httpsEndpoints[host.GetCloudID()] = node.GetHTTPSEndpoint(domains[i])
+		}
+	}
+	if err := node.SetClusterHTTPSEndpoints(app, clusterName, hosts, httpsEndpoints, sslSetupCmdFlags.email, loadBalancer); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("HTTPS API endpoints of cluster %s:", clusterName)
+	for _, host := range hosts {
+		ux.Logger.PrintToUser("  %s %s", host.GetCloudID(), logging.Green.Wrap(httpsEndpoints[host.GetCloudID()]))
+	}
+	if sslSetupCmdFlags.keepPublicAPIPort {
+		return nil
+	}
+	ux.Logger.PrintToUser("")
+	return closePublicAPIPort(clusterName, clusterConfig)
+}
+
+// getLoadBalancerHost returns the monitoring host of [clusterName], that runs the reverse
+// proxy load balancing among the cluster API nodes
+func getLoadBalancerHost(clusterName string, clusterConfig models.ClusterConfig) (*models.Host, error) {
+	if clusterConfig.MonitoringInstance == "" {
+		return nil, fmt.Errorf("cluster %s has no monitoring host to run the load balancer. Create the cluster with --enable-monitoring", clusterName)
+	}
+	monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetMonitoringInventoryDir(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	if len(monitoringHosts) != 1 {
+		return nil, fmt.Errorf("expected 1 monitoring host in cluster %s, but found %d", clusterName, len(monitoringHosts))
+	}
+	return monitoringHosts[0], nil
+}
+
+// setupReverseProxies installs [proxies], requiring [authTokens] for the RPC of their
+// blockchains, and waits for their endpoints to be available. The certificates are obtained
+// from Let's Encrypt, unless [userCert]
+func setupReverseProxies(
+	authTokens map[string]string,
+	proxies []reverseProxy,
+	email string,
+	userCert bool,
+	certFile string,
+	keyFile string,
+) error {
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	spinSession := ux.NewUserSpinner()
+	for _, proxy := range proxies {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, proxy reverseProxy) {
+			defer wg.Done()
+			host := proxy.host
+			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, "Setup HTTPS at %s", proxy.domain))
+			inputs := remoteconfig.PrepareReverseProxyInputs(proxy.domain, email, userCert, proxy.upstreamIPs, authTokens)
+			if err := ssh.RunSSHSetupReverseProxy(
+				host,
+				inputs,
+				certFile,
+				keyFile,
+			); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+				ux.SpinFailWithError(spinner, "", err)
+				return
+			}
+			if err := waitForHTTPSEndpoint(node.GetHTTPSEndpoint(proxy.domain)); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+				ux.SpinFailWithError(spinner, "", err)
+				return
+			}
+			ux.SpinComplete(spinner)
+		}(&wgResults, proxy)
+	}
+	wg.Wait()
+	spinSession.Stop()
+	if wgResults.HasErrors() {
+		return fmt.Errorf("failed to set up HTTPS for node(s) %s", wgResults.GetErrorHostMap())
+	}
+	return nil
+}
+
+// getSSLHosts returns the hosts of the cluster whose API is served over HTTPS: the API
+// nodes if any, or all the avalanchego nodes otherwise
+func getSSLHosts(clusterConfig models.ClusterConfig, hosts []*models.Host) []*models.Host {
+	hosts = utils.Filter(hosts, func(h *models.Host) bool {
+		return clusterConfig.IsAvalancheGoHost(h.GetCloudID())
+	})
+	if len(clusterConfig.APINodes) == 0 {
+		return hosts
+	}
+	return utils.Filter(hosts, func(h *models.Host) bool {
+		return clusterConfig.IsAPIHost(h.GetCloudID())
+	})
+}
+
+// checkSSLDomains verifies the domains of [proxies] resolve to the IPs of their hosts. This is
+// required by Let's Encrypt, so a mismatch fails unless the user gives its own certificate
+func checkSSLDomains(proxies []reverseProxy, userCert bool) error {
+	for _, proxy := range proxies {
+		domain, host := proxy.domain, proxy.host
+		if utils.IsValidIP(domain) || strings.Contains(domain, "/") || strings.Contains(domain, ":") {
+			return fmt.Errorf("invalid domain %q", domain)
+		}
+		ips, err := net.LookupHost(domain)
+		if err == nil && slices.Contains(ips, host.IP) {
+			continue
+		}
+		msg := fmt.Sprintf("domain %s does not resolve to the IP %s of node %s", domain, host.IP, host.GetCloudID())
+		if err != nil {
+			msg = fmt.Sprintf("%s: %s", msg, err)
+		}
+		if !userCert {
+			return fmt.Errorf("%s. Update the DNS records of the domain before requesting a Let's Encrypt certificate", msg)
+		}
+		ux.Logger.RedXToUser("%s", msg)
+	}
+	return nil
+}
+
+// openHTTPSPorts allows HTTPS and Let's Encrypt traffic from anywhere on the cloud
+// firewalls of the cluster nodes
+func openHTTPSPorts(clusterName string) error {
+	clusterNodes, err := node.GetClusterNodes(app, clusterName)
+	if err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if clusterConfig.MonitoringInstance != "" {
+		// the monitoring host may run the load balancer
+		clusterNodes = append(clusterNodes, clusterConfig.MonitoringInstance)
+	}
+	cloudSecurityGroupList, err := getCloudSecurityGroupList(clusterNodes)
+	if err != nil {
+		return err
+	}
+	ports := []int32{constants.ACMEChallengePort, constants.HTTPSPort}
+	gcpSGFound := false
+	for _, cloudSecurityGroup := range cloudSecurityGroupList {
+		if cloudSecurityGroup.cloud == constants.GCPCloudService {
+			gcpSGFound = true
+			continue
+		}
+		ec2Svc, err := awsAPI.NewAwsCloud(awsProfile, cloudSecurityGroup.region)
+		if err != nil {
+			return fmt.Errorf("failed to establish connection to %s cloud region %s with err: %w", constants.AWSCloudService, cloudSecurityGroup.region, err)
+		}
+		sgExists, sg, err := ec2Svc.CheckSecurityGroupExists(cloudSecurityGroup.securityGroup)
+		if err != nil || !sgExists {
+			return fmt.Errorf("can't find security group %s in %s cloud region %s with err: %w", cloudSecurityGroup.securityGroup, constants.AWSCloudService, cloudSecurityGroup.region, err)
+		}
+		for _, port := range ports {
+			if awsAPI.CheckIPInSg(&sg, "0.0.0.0/0", port) {
+				continue
+			}
+			ux.Logger.GreenCheckmarkToUser("Opening port %d in %s cloud region %s", port, constants.AWSCloudService, cloudSecurityGroup.region)
+			if err := ec2Svc.AddSecurityGroupRule(*sg.GroupId, "ingress", "tcp", "0.0.0.0/0", port); err != nil {
+				return fmt.Errorf("failed to open port %d in %s cloud region %s with err: %w", port, constants.AWSCloudService, cloudSecurityGroup.region, err)
+			}
+		}
+	}
+	if !gcpSGFound {
+		return nil
+	}
+	prefix, err := defaultAvalancheCLIPrefix("")
+	if err != nil {
+		return err
+	}
+	networkName := fmt.Sprintf("%s-network", prefix)
+	gcpClient, projectName, _, err := getGCPCloudCredentials()
+	if err != nil {
+		return err
+	}
+	gcpCloud, err := gcpAPI.NewGcpCloud(gcpClient, projectName, context.Background())
+	if err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Opening ports %d and %d in %s cloud", constants.ACMEChallengePort, constants.HTTPSPort, constants.GCPCloudService)
+	if _, err := gcpCloud.SetFirewallRule(
+		"0.0.0.0/0",
+		fmt.Sprintf("%s-https", networkName),
+		networkName,
+		[]string{strconv.Itoa(constants.ACMEChallengePort), strconv.Itoa(constants.HTTPSPort)},
+	); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to open HTTPS ports in %s cloud with err: %w", constants.GCPCloudService, err)
+	}
+	return nil
+}
+
+// closePublicAPIPort revokes public access to the plain HTTP API port of the nodes of
+// [clusterName], now served over HTTPS. Only AWS security groups get that access, on node
+// create --public-http-port. Security groups shared with other clusters that still serve
+// the API publicly over plain HTTP are left untouched
+func closePublicAPIPort(clusterName string, clusterConfig models.ClusterConfig) error {
+	if clusterConfig.External {
+		ux.Logger.PrintToUser("Cluster %s is external. Make sure port %d of the nodes is not open to the public", clusterName, constants.AvalancheGoAPIPort)
+		return nil
+	}
+	clusterNodes, err := node.GetClusterNodes(app, clusterName)
+	if err != nil {
+		return err
+	}
+	cloudSecurityGroupList, err := getCloudSecurityGroupList(clusterNodes)
+	if err != nil {
+		return err
+	}
+	sharedSecurityGroups, err := getPublicHTTPSecurityGroups(clusterName)
+	if err != nil {
+		return err
+	}
+	for _, cloudSecurityGroup := range cloudSecurityGroupList {
+		if cloudSecurityGroup.cloud != constants.AWSCloudService {
+			continue
+		}
+		if slices.Contains(sharedSecurityGroups, cloudSecurityGroup) {
+			ux.Logger.PrintToUser(logging.Yellow.Wrap("Warning: security group %s in %s cloud region %s is shared with clusters that serve the API over plain HTTP. Port %d is left open to the public"),
+				cloudSecurityGroup.securityGroup, constants.AWSCloudService, cloudSecurityGroup.region, constants.AvalancheGoAPIPort)
+			continue
+		}
+		ec2Svc, err := awsAPI.NewAwsCloud(awsProfile, cloudSecurityGroup.region)
+		if err != nil {
+			return fmt.Errorf("failed to establish connection to %s cloud region %s with err: %w", constants.AWSCloudService, cloudSecurityGroup.region, err)
+		}
+		sgExists, sg, err := ec2Svc.CheckSecurityGroupExists(cloudSecurityGroup.securityGroup)
+		if err != nil || !sgExists {
+			return fmt.Errorf("can't find security group %s in %s cloud region %s with err: %w", cloudSecurityGroup.securityGroup, constants.AWSCloudService, cloudSecurityGroup.region, err)
+		}
+		if !awsAPI.CheckIPInSg(&sg, "0.0.0.0/0", constants.AvalancheGoAPIPort) {
+			continue
+		}
+		ux.Logger.GreenCheckmarkToUser("Closing public access to port %d in %s cloud region %s", constants.AvalancheGoAPIPort, constants.AWSCloudService, cloudSecurityGroup.region)
+		if err := ec2Svc.DeleteSecurityGroupRule(*sg.GroupId, "ingress", "tcp", "0.0.0.0/0", constants.AvalancheGoAPIPort); err != nil {
+			return fmt.Errorf("failed to close port %d in %s cloud region %s with err: %w", constants.AvalancheGoAPIPort, constants.AWSCloudService, cloudSecurityGroup.region, err)
+		}
+	}
+	clusterConfig, err = app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	clusterConfig.HTTPAccess = false
+	return app.SetClusterConfig(clusterName, clusterConfig)
+}
+
+// getPublicHTTPSecurityGroups returns the security groups of the clusters other than
+// [clusterName] that serve the API publicly over plain HTTP
+func getPublicHTTPSecurityGroups(clusterName string) ([]regionSecurityGroup, error) {
+	clustersConfig, err := app.GetClustersConfig()
+	if err != nil {
+		return nil, err
+	}
+	securityGroups := []regionSecurityGroup{}
+	for otherClusterName, otherClusterConfig := range clustersConfig.Clusters {
+		if otherClusterName == clusterName || !bool(otherClusterConfig.HTTPAccess) || otherClusterConfig.External || otherClusterConfig.Local {
+			continue
+		}
+		otherSecurityGroups, err := getCloudSecurityGroupList(otherClusterConfig.Nodes)
+		if err != nil {
+			return nil, err
+		}
+		securityGroups = append(securityGroups, otherSecurityGroups...)
+	}
+	return securityGroups, nil
+}
+
+// waitForHTTPSEndpoint waits until the avalanchego health API answers at [endpoint], which
+// also covers the time needed by Let's Encrypt to issue the certificate
+func waitForHTTPSEndpoint(endpoint string) error {
+	client := http.Client{Timeout: constants.APIRequestTimeout}
+	deadline := time.Now().Add(httpsEndpointWaitTimeout)
+	var lastErr error
+	for time.Now().Before(deadline) {
+		resp, err := client.Get(endpoint + "/ext/health")
+		if err == nil {
+			_ = resp.Body.Close()
+			// any answer from avalanchego means proxy and certificate are working
+			if resp.StatusCode != http.StatusBadGateway {
+				return nil
+			}
+			err = fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		lastErr = err
+		time.Sleep(5 * time.Second)
+	}
+	return fmt.Errorf("%s is not available after %s: %w", endpoint, httpsEndpointWaitTimeout, lastErr)
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/statebackend"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche node state
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Sync the state of a cluster with the shared state backend",
+		Long: `The node state command suite syncs the local state of clusters with the state backend
+shared by a team (see avalanche config state-backend).
+
+Commands changing a cluster already pull and push its state under the cluster lock. These
+commands are meant to fetch the latest state before read only commands (eg node status),
+to share a cluster created before the state backend was set, and to remove stale locks.`,
+		RunE: cobrautils.CommandSuiteUsage,
+	}
+	// node state pull
+	cmd.AddCommand(newStatePullCmd())
+	// node state push
+	cmd.AddCommand(newStatePushCmd())
+	// node state unlock
+	cmd.AddCommand(newStateUnlockCmd())
+	return cmd
+}
+
+func newStatePullCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pull [clusterName]",
+		Short: "Replace the local state of a cluster with the shared one",
+		Long: `The node state pull command replaces the local cluster definition, node configs,
+inventory and deployment records of the cluster with the ones of the state backend.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: statePull,
+	}
+}
+
+func newStatePushCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "push [clusterName]",
+		Short: "Replace the shared state of a cluster with the local one",
+		Long: `The node state push command uploads the local cluster definition, node configs,
+inventory and deployment records of the cluster to the state backend, under the cluster
+lock, overwriting the shared ones.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: statePush,
+	}
+}
+
+func newStateUnlockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlock [clusterName]",
+		Short: "Remove the lock of a cluster",
+		Long: `The node state unlock command removes the lock of the cluster on the state backend.
+Only use it when the lock is stale, eg the command holding it was killed, as changes
+made concurrently by the lock owner could be overwritten.`,
+		Args: cobrautils.ExactArgs(1),
+		RunE: stateUnlock,
+	}
+}
+
+func statePull(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	ctx, cancel := context.WithTimeout(context.Background(), constants.StateBackendTimeout)
+	defer cancel()
+	backend, err := statebackend.NewFromConfig(ctx, app)
+	if err != nil {
+		return err
+	}
+	if lock, locked, err := statebackend.GetLock(ctx, backend, clusterName); err != nil {
+		return err
+	} else if locked {
+		ux.Logger.PrintToUser("Warning: cluster %s is being changed by %s", clusterName, lock)
+	}
+	pulled, err := statebackend.Pull(ctx, app, backend, clusterName)
+	if err != nil {
+		return err
+	}
+	if !pulled {
+		return fmt.Errorf("cluster %s not found on the state backend", clusterName)
+	}
+	ux.Logger.GreenCheckmarkToUser("Cluster %s state pulled", clusterName)
+	return nil
+}
+
+func statePush(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := node.CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.StateBackendTimeout)
+	defer cancel()
+	backend, err := statebackend.NewFromConfig(ctx, app)
+	if err != nil {
+		return err
+	}
+	lock, err := statebackend.AcquireLock(ctx, backend, clusterName, "avalanche node state push")
+	if err != nil {
+		return err
+	}
+	pushErr := statebackend.Push(ctx, app, backend, clusterName)
+	if err := statebackend.ReleaseLock(ctx, backend, clusterName, lock); err != nil && pushErr == nil {
+		return err
+	}
+	if pushErr != nil {
+		return pushErr
+	}
+	ux.Logger.GreenCheckmarkToUser("Cluster %s state pushed", clusterName)
+	return nil
+}
+
+func stateUnlock(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	ctx, cancel := context.WithTimeout(context.Background(), constants.StateBackendTimeout)
+	defer cancel()
+	backend, err := statebackend.NewFromConfig(ctx, app)
+	if err != nil {
+		return err
+	}
+	lock, locked, err := statebackend.GetLock(ctx, backend, clusterName)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		ux.Logger.PrintToUser("Cluster %s is not locked", clusterName)
+		return nil
+	}
+	if err := statebackend.ReleaseLock(ctx, backend, clusterName, lock); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Removed lock of cluster %s held by %s", clusterName, lock)
+	return nil
+}
@@ -6,27 +6,34 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/node"
 
 	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/events"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
-	"github.com/ava-labs/avalanche-cli/pkg/ssh"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/olekukonko/tablewriter"
 	"github.com/pborman/ansi"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
 )
 
-var blockchainName string
+var (
+	blockchainName      string
+	watchStatus         bool
+	watchStatusInterval time.Duration
+	healthHistoryPeriod string
+	heartbeatInterval   time.Duration
+)
 
 func newStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -37,14 +44,26 @@ func newStatusCmd() *cobra.Command {
 The node status command gets the bootstrap status of all nodes in a cluster with the Primary Network. 
 If no cluster is given, defaults to node list behaviour.
 
-To get the bootstrap status of a node with a Blockchain, use --blockchain flag`,
+To get the bootstrap status of a node with a Blockchain, use --blockchain flag.
+
+Use --watch to periodically report per-chain bootstrap progress (blocks processed
+vs chain tip, and estimated time to completion) until all nodes are synced.
+
+Each status check is recorded on the cluster health history, kept for 30 days.
+Use --heartbeat to keep recording health checks periodically, and --history to show
+when nodes became unreachable, not bootstrapped or unhealthy, or changed version, over
+a given period (eg --history 7d), even if they have since recovered.`,
 		Args: cobrautils.MinimumNArgs(0),
 		RunE: statusNode,
 	}
 	cmd.Flags().StringVar(&blockchainName, "subnet", "", "specify the blockchain the node is syncing with")
 	cmd.Flags().StringVar(&blockchainName, "blockchain", "", "specify the blockchain the node is syncing with")
+	cmd.Flags().BoolVar(&watchStatus, "watch", false, "periodically report chain bootstrap progress until all nodes are synced")
+	cmd.Flags().DurationVar(&watchStatusInterval, "watch-interval", 15*time.Second, "time between progress reports when using --watch")
+	cmd.Flags().StringVar(&healthHistoryPeriod, "history", "", "show the recorded health changes of the cluster nodes over the given period (eg 7d, 12h)")
+	cmd.Flags().DurationVar(&heartbeatInterval, "heartbeat", 0, "keep recording the health of the cluster nodes with the given interval (eg 5m), until interrupted")
 
-	return cmd
+	return cobrautils.MarkReadOnly(cmd)
 }
 
 func statusNode(_ *cobra.Command, args []string) error {
@@ -63,13 +82,20 @@ func statusNode(_ *cobra.Command, args []string) error {
 	if clusterConf.Local {
 		return notImplementedForLocal("status")
 	}
-	var blockchainID ids.ID
+	if healthHistoryPeriod != "" {
+		return printClusterHealthHistory(clusterName)
+	}
+	var (
+		blockchainID ids.ID
+		rpcEndpoints []string
+	)
 	if blockchainName != "" {
 		sc, err := app.LoadSidecar(blockchainName)
 		if err != nil {
 			return err
 		}
 		blockchainID = sc.Networks[clusterConf.Network.Name()].BlockchainID
+		rpcEndpoints = sc.Networks[clusterConf.Network.Name()].RPCEndpoints
 		if blockchainID == ids.Empty {
 			return ErrNoBlockchainID
 		}
@@ -96,105 +122,67 @@ func statusNode(_ *cobra.Command, args []string) error {
 	}
 	defer node.DisconnectHosts(hosts)
 
+	if watchStatus {
+		hosts = utils.Filter(hosts, func(h *models.Host) bool { return slices.Contains(hostIDs, h.GetCloudID()) })
+		return watchBootstrapProgress(clusterConf, clusterName, hosts, blockchainID, rpcEndpoints)
+	}
+	if heartbeatInterval != 0 {
+		hosts = utils.Filter(hosts, func(h *models.Host) bool { return slices.Contains(hostIDs, h.GetCloudID()) })
+		return heartbeatClusterHealth(clusterConf, clusterName, hosts, getNodeIDsMap(hostIDs, nodeIDs), blockchainID)
+	}
+
+	hosts = utils.Filter(hosts, func(h *models.Host) bool { return slices.Contains(hostIDs, h.GetCloudID()) })
 	spinSession := ux.NewUserSpinner()
 	spinner := spinSession.SpinToUser("Checking node(s) status...")
-	notBootstrappedNodes, err := node.GetNotBootstrappedNodes(hosts)
-	if err != nil {
-		ux.SpinFailWithError(spinner, "", err)
-		return err
+	nodesHealth := node.GetClusterHealth(hosts, getNodeIDsMap(hostIDs, nodeIDs), blockchainID)
+	// recorded before failing on unreachable nodes, as the history is most useful then
+	recordClusterHealth(clusterName, nodesHealth)
+	failedChecks := map[string]string{}
+	for _, health := range nodesHealth {
+		if health.Error != "" {
+			failedChecks[health.CloudID] = health.Error
+		}
 	}
-	ux.SpinComplete(spinner)
-
-	spinner = spinSession.SpinToUser("Checking if node(s) are healthy...")
-	unhealthyNodes, err := node.GetUnhealthyNodes(hosts)
-	if err != nil {
+	if len(failedChecks) > 0 {
+		err := fmt.Errorf("failed to check status of node(s) %s", failedChecks)
 		ux.SpinFailWithError(spinner, "", err)
 		return err
 	}
 	ux.SpinComplete(spinner)
-
-	spinner = spinSession.SpinToUser("Getting avalanchego version of node(s)...")
-	wg := sync.WaitGroup{}
-	wgResults := models.NodeResults{}
-	for _, host := range hosts {
-		wg.Add(1)
-		go func(nodeResults *models.NodeResults, host *models.Host) {
-			defer wg.Done()
-			if resp, err := ssh.RunSSHCheckAvalancheGoVersion(host); err != nil {
-				nodeResults.AddResult(host.GetCloudID(), nil, err)
-				return
-			} else {
-				if avalancheGoVersion, _, err := node.ParseAvalancheGoOutput(resp); err != nil {
-					nodeResults.AddResult(host.GetCloudID(), nil, err)
-				} else {
-					nodeResults.AddResult(host.GetCloudID(), avalancheGoVersion, err)
-				}
-			}
-		}(&wgResults, host)
-	}
-	wg.Wait()
-
-	if wgResults.HasErrors() {
-		e := fmt.Errorf("failed to get avalanchego version for node(s) %s", wgResults.GetErrorHostMap())
-		ux.SpinFailWithError(spinner, "", e)
-		return e
-	}
-	ux.SpinComplete(spinner)
 	spinSession.Stop()
-	avagoVersions := map[string]string{}
-	for nodeID, avalanchegoVersion := range wgResults.GetResultMap() {
-		avagoVersions[nodeID] = fmt.Sprintf("%v", avalanchegoVersion)
-	}
 
+	notBootstrappedNodes := []string{}
+	unhealthyNodes := []string{}
 	notSyncedNodes := []string{}
 	subnetSyncedNodes := []string{}
 	subnetValidatingNodes := []string{}
-	if blockchainName != "" {
-		hostsToCheckSyncStatus := []string{}
-		for _, hostID := range hostIDs {
-			if slices.Contains(notBootstrappedNodes, hostID) {
-				notSyncedNodes = append(notSyncedNodes, hostID)
-			} else {
-				hostsToCheckSyncStatus = append(hostsToCheckSyncStatus, hostID)
-			}
+	avagoVersions := map[string]string{}
+	for _, health := range nodesHealth {
+		avagoVersions[health.CloudID] = health.AvalancheGoVersion
+		if !health.Bootstrapped {
+			notBootstrappedNodes = append(notBootstrappedNodes, health.CloudID)
 		}
-		if len(hostsToCheckSyncStatus) != 0 {
-			ux.Logger.PrintToUser("Getting subnet sync status of node(s)")
-			hostsToCheck := utils.Filter(hosts, func(h *models.Host) bool { return slices.Contains(hostsToCheckSyncStatus, h.GetCloudID()) })
-			wg := sync.WaitGroup{}
-			wgResults := models.NodeResults{}
-			for _, host := range hostsToCheck {
-				wg.Add(1)
-				go func(nodeResults *models.NodeResults, host *models.Host) {
-					defer wg.Done()
-					if syncstatus, err := ssh.RunSSHSubnetSyncStatus(host, blockchainID.String()); err != nil {
-						nodeResults.AddResult(host.GetCloudID(), nil, err)
-						return
-					} else {
-						if subnetSyncStatus, err := parseSubnetSyncOutput(syncstatus); err != nil {
-							nodeResults.AddResult(host.GetCloudID(), nil, err)
-							return
-						} else {
-							nodeResults.AddResult(host.GetCloudID(), subnetSyncStatus, err)
-						}
-					}
-				}(&wgResults, host)
-			}
-			wg.Wait()
-			if wgResults.HasErrors() {
-				return fmt.Errorf("failed to check sync status for node(s) %s", wgResults.GetErrorHostMap())
-			}
-			for nodeID, subnetSyncStatus := range wgResults.GetResultMap() {
-				switch subnetSyncStatus {
-				case status.Syncing.String():
-					subnetSyncedNodes = append(subnetSyncedNodes, nodeID)
-				case status.Validating.String():
-					subnetValidatingNodes = append(subnetValidatingNodes, nodeID)
-				default:
-					notSyncedNodes = append(notSyncedNodes, nodeID)
-				}
-			}
+		if !health.Healthy {
+			unhealthyNodes = append(unhealthyNodes, health.CloudID)
+		}
+		if blockchainName == "" {
+			continue
 		}
+		switch health.BlockchainStatus {
+		case status.Syncing.String():
+			subnetSyncedNodes = append(subnetSyncedNodes, health.CloudID)
+		case status.Validating.String():
+			subnetValidatingNodes = append(subnetValidatingNodes, health.CloudID)
+		default:
+			notSyncedNodes = append(notSyncedNodes, health.CloudID)
+		}
+	}
+	if len(unhealthyNodes) > 0 {
+		events.Emit(app, events.NodeUnhealthy, fmt.Sprintf("Cluster %s has unhealthy nodes: %s", clusterName, strings.Join(unhealthyNodes, ", ")), map[string]interface{}{
+			"cluster": clusterName,
+			"network": clusterConf.Network.Name(),
+			"nodes":   unhealthyNodes,
+		})
 	}
 	if clusterConf.MonitoringInstance != "" {
 		hostIDs = append(hostIDs, clusterConf.MonitoringInstance)
@@ -311,6 +299,73 @@ func printOutput(
 	table.Render()
 }
 
+// watchBootstrapProgress periodically prints, for each node, the blocks processed vs chain
+// tip for C-Chain and (if given) the tracked blockchain, together with an ETA. The tip of
+// the blockchain is taken from its [rpcEndpoints] not served by the cluster, if any
+func watchBootstrapProgress(
+	clusterConf models.ClusterConfig,
+	clusterName string,
+	hosts []*models.Host,
+	blockchainID ids.ID,
+	rpcEndpoints []string,
+) error {
+	if watchStatusInterval <= 0 {
+		return fmt.Errorf("invalid --watch-interval %s", watchStatusInterval)
+	}
+	chains := []string{"C"}
+	if blockchainID != ids.Empty {
+		chains = append(chains, blockchainID.String())
+	}
+	trackers := map[string]*node.BootstrapProgressTracker{}
+	for _, chain := range chains {
+		trackers[chain] = node.NewBootstrapProgressTracker()
+	}
+	for {
+		now := time.Now()
+		header := table.Row{"Cloud ID", "IP", "Chain", "Processed", "Tip", "Progress", "ETA"}
+		t := ux.DefaultTable(fmt.Sprintf("Bootstrap progress for cluster %s at %s", clusterName, now.Format(time.TimeOnly)), header)
+		allSynced := true
+		for _, chain := range chains {
+			chainName := chain
+			if chain != "C" {
+				chainName = blockchainName
+			}
+			progressMap, err := node.GetChainSyncProgress(hosts, chain, node.ReferenceRPCForChain(clusterConf.Network, chain, rpcEndpoints, hosts))
+			if err != nil {
+				return err
+			}
+			for _, host := range hosts {
+				progress := progressMap[host.GetCloudID()]
+				eta, etaOk := trackers[chain].Update(host.GetCloudID(), progress, now)
+				processed, tip, percent, etaStr := "N/A", "N/A", logging.Red.Wrap("NOT_AVAILABLE"), ""
+				if progress.Available {
+					processed = ux.ConvertToStringWithThousandSeparator(progress.CurrentBlock)
+					tip = ux.ConvertToStringWithThousandSeparator(progress.HighestBlock)
+					percent = fmt.Sprintf("%.2f%%", progress.Percent())
+				}
+				switch {
+				case progress.Synced():
+					percent = logging.Green.Wrap("SYNCED")
+				case etaOk:
+					etaStr = ux.FormatDuration(eta)
+				case progress.Available:
+					etaStr = "calculating..."
+				}
+				if !progress.Synced() {
+					allSynced = false
+				}
+				t.AppendRow(table.Row{host.GetCloudID(), host.IP, chainName, processed, tip, percent, etaStr})
+			}
+		}
+		ux.Logger.PrintToUser(t.Render())
+		if allSynced {
+			ux.Logger.PrintToUser("All nodes in cluster %s are synced", clusterName)
+			return nil
+		}
+		time.Sleep(watchStatusInterval)
+	}
+}
+
 func removeColors(s string) string {
 	bs, err := ansi.Strip([]byte(s))
 	if err != nil {
@@ -0,0 +1,176 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/events"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// getNodeIDsMap maps the cloud IDs [hostIDs] to their node IDs [nodeIDs]
+func getNodeIDsMap(hostIDs []string, nodeIDs []string) map[string]string {
+	nodeIDsMap := map[string]string{}
+	for i, hostID := range hostIDs {
+		nodeIDsMap[hostID] = nodeIDs[i]
+	}
+	return nodeIDsMap
+}
+
+// recordClusterHealth adds [nodesHealth] to the cluster health history. Recording is
+// best effort, so node status does not fail if the history can not be written, and
+// it is skipped in read-only mode
+func recordClusterHealth(clusterName string, nodesHealth []models.NodeHealth) {
+	if app.ReadOnly {
+		return
+	}
+	snapshot := models.ClusterHealthSnapshot{
+		Time:       time.Now().UTC(),
+		Blockchain: blockchainName,
+		Nodes:      nodesHealth,
+	}
+	if err := app.AddClusterHealthSnapshot(clusterName, snapshot); err != nil {
+		app.Log.Warn(fmt.Sprintf("failed to record health history of cluster %s: %s", clusterName, err))
+	}
+}
+
+// heartbeatClusterHealth checks the health of [hosts] every --heartbeat interval, until
+// interrupted, recording each check on the cluster health history and printing the changes.
+// [nodeIDs] maps cloud IDs to node IDs. NodeUnhealthy events are only emitted when the
+// status of a node changes to a failing one, not on every check. In read-only mode, checks are printed but not recorded
+func heartbeatClusterHealth(
+	clusterConf models.ClusterConfig,
+	clusterName string,
+	hosts []*models.Host,
+	nodeIDs map[string]string,
+	blockchainID ids.ID,
+) error {
+	if heartbeatInterval <= 0 {
+		return fmt.Errorf("invalid --heartbeat interval %s", heartbeatInterval)
+	}
+	if app.ReadOnly {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Read-only mode: health checks are shown, but not recorded on the cluster health history"))
+	}
+	ux.Logger.PrintToUser("Checking health of cluster %s every %s. Press Ctrl+C to stop", clusterName, heartbeatInterval)
+	previous := map[string]models.NodeHealth{}
+	for {
+		snapshot := models.ClusterHealthSnapshot{
+			Time:       time.Now().UTC(),
+			Blockchain: blockchainName,
+			Nodes:      node.GetClusterHealth(hosts, nodeIDs, blockchainID),
+		}
+		if !app.ReadOnly {
+			if err := app.AddClusterHealthSnapshot(clusterName, snapshot); err != nil {
+				return err
+			}
+		}
+		for _, health := range snapshot.Nodes {
+			transition := node.HealthTransition{Time: snapshot.Time, To: health}
+			if prev, ok := previous[health.CloudID]; ok {
+				if !node.HealthChanged(prev, health) {
+					continue
+				}
+				transition.From = &prev
+			}
+			ux.Logger.PrintToUser("%s %s %s", snapshot.Time.Local().Format(time.DateTime), health.CloudID, describeHealthTransition(transition))
+		}
+		if failing := node.GetNewlyFailingNodes(previous, snapshot.Nodes); len(failing) > 0 {
+			events.Emit(app, events.NodeUnhealthy, fmt.Sprintf("Cluster %s has unhealthy nodes: %s", clusterName, strings.Join(failing, ", ")), map[string]interface{}{
+				"cluster": clusterName,
+				"network": clusterConf.Network.Name(),
+				"nodes":   failing,
+			})
+		}
+		for _, health := range snapshot.Nodes {
+			previous[health.CloudID] = health
+		}
+		time.Sleep(heartbeatInterval)
+	}
+}
+
+// printClusterHealthHistory shows the health changes of the nodes of [clusterName] recorded
+// over the --history period, and how often each node was found failing
+func printClusterHealthHistory(clusterName string) error {
+	period, err := prompts.ParseDuration(healthHistoryPeriod)
+	if err != nil {
+		return fmt.Errorf("invalid --history period: %w", err)
+	}
+	history, err := app.LoadClusterHealthHistory(clusterName)
+	if err != nil {
+		return err
+	}
+	since := time.Now().Add(-period)
+	transitions, summaries := node.GetHealthTransitions(history, since)
+	if len(summaries) == 0 {
+		ux.Logger.PrintToUser("No health checks recorded for cluster %s over the last %s", clusterName, healthHistoryPeriod)
+		ux.Logger.PrintToUser("Health is recorded on each run of avalanche node status %s, or continuously with --heartbeat", clusterName)
+		return nil
+	}
+	header := table.Row{"Time", "Cloud ID", "Node ID", "Change"}
+	t := ux.DefaultTable(fmt.Sprintf("Health changes of cluster %s since %s", clusterName, since.Format(time.DateTime)), header)
+	for _, transition := range transitions {
+		t.AppendRow(table.Row{
+			transition.Time.Local().Format(time.DateTime),
+			transition.To.CloudID,
+			transition.To.NodeID,
+			describeHealthTransition(transition),
+		})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	header = table.Row{"Cloud ID", "Node ID", "Checks", "Failed Checks", "Last Failure", "Last Status"}
+	t = ux.DefaultTable("Health summary", header)
+	for _, summary := range summaries {
+		lastFailure := ""
+		if summary.Failures > 0 {
+			lastFailure = summary.LastFailure.Local().Format(time.DateTime)
+		}
+		t.AppendRow(table.Row{
+			summary.CloudID,
+			summary.NodeID,
+			summary.Checks,
+			summary.Failures,
+			lastFailure,
+			colorHealthStatus(summary.Current.Status()),
+		})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	return nil
+}
+
+func describeHealthTransition(transition node.HealthTransition) string {
+	to := transition.To
+	description := colorHealthStatus(to.Status())
+	if transition.From != nil && transition.From.Status() != to.Status() {
+		description = fmt.Sprintf("%s -> %s", transition.From.Status(), description)
+	}
+	details := []string{}
+	if to.AvalancheGoVersion != "" && (transition.From == nil || transition.From.AvalancheGoVersion != to.AvalancheGoVersion) {
+		details = append(details, "avalanchego "+to.AvalancheGoVersion)
+	}
+	if to.BlockchainStatus != "" && (transition.From == nil || transition.From.BlockchainStatus != to.BlockchainStatus) {
+		details = append(details, "blockchain "+to.BlockchainStatus)
+	}
+	if to.Error != "" {
+		details = append(details, to.Error)
+	}
+	if len(details) > 0 {
+		description = fmt.Sprintf("%s (%s)", description, strings.Join(details, ", "))
+	}
+	return description
+}
+
+func colorHealthStatus(status string) string {
+	if status == models.NodeHealthOK {
+		return logging.Green.Wrap(status)
+	}
+	return logging.Red.Wrap(status)
+}
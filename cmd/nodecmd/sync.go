@@ -24,7 +24,7 @@ You can check the blockchain bootstrap status by calling avalanche node status <
 	cmd.Flags().BoolVar(&avoidChecks, "no-checks", false, "do not check for bootstrapped/healthy status or rpc compatibility of nodes against subnet")
 	cmd.Flags().StringSliceVar(&subnetAliases, "subnet-aliases", nil, "subnet alias to be used for RPC calls. defaults to subnet blockchain ID")
 
-	return cmd
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func syncSubnet(_ *cobra.Command, args []string) error {
@@ -0,0 +1,322 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/monitoring"
+	"github.com/ava-labs/avalanche-cli/pkg/remoteconfig"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	monitoringBackends = []string{
+		constants.MonitoringPrometheus,
+		constants.MonitoringDatadog,
+		constants.MonitoringCloudWatch,
+	}
+	datadogAppKey string
+	// characters not allowed on cloudwatch dashboard names
+	cloudWatchDashboardNameRegex = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+)
+
+// addTelemetryFlags adds the settings of the datadog and cloudwatch monitoring backends
+func addTelemetryFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&datadogAPIKey, "datadog-api-key", "", "datadog API key (defaults to $"+constants.DatadogAPIKeyEnvVar+")")
+	cmd.Flags().StringVar(&datadogAppKey, "datadog-app-key", "", "datadog application key, only used to create the cluster dashboard (defaults to $"+constants.DatadogAppKeyEnvVar+")")
+	cmd.Flags().StringVar(&datadogSite, "datadog-site", constants.DatadogDefaultSite, "datadog site to send metrics and logs to")
+	cmd.Flags().StringVar(&cloudWatchRegion, "cloudwatch-region", "", "AWS region to send metrics and logs to (defaults to the first --region for AWS nodes)")
+	cmd.Flags().StringVar(&cloudWatchCredentialsPath, "cloudwatch-credentials", "", "AWS credentials file for the cloudwatch agent of the nodes. Use a dedicated IAM user allowed to put metrics and logs")
+}
+
+// checkMonitoringBackend validates the --monitoring flag and the settings of the chosen
+// backend. Prometheus is the self-hosted stack set up by --enable-monitoring. Nodes added to
+// a cluster that exports to datadog or cloudwatch get the same agent, with the settings and
+// credentials saved for the cluster
+func checkMonitoringBackend(clusterName string) error {
+	telemetry, secrets, err := getClusterTelemetry(clusterName)
+	if err != nil {
+		return err
+	}
+	if monitoringBackend == "" {
+		monitoringBackend = telemetry.Backend
+	}
+	if monitoringBackend == "" {
+		return nil
+	}
+	if !slices.Contains(monitoringBackends, monitoringBackend) {
+		return fmt.Errorf("invalid monitoring backend %q. Valid options are %v", monitoringBackend, monitoringBackends)
+	}
+	if telemetry.Backend != "" && telemetry.Backend != monitoringBackend {
+		return fmt.Errorf("cluster %s exports its metrics and logs to %s", clusterName, telemetry.Backend)
+	}
+	if monitoringBackend == constants.MonitoringPrometheus {
+		addMonitoring = true
+		return nil
+	}
+	if addMonitoring {
+		return fmt.Errorf("--%s can not be used together with --monitoring %s", enableMonitoringFlag, monitoringBackend)
+	}
+	switch monitoringBackend {
+	case constants.MonitoringDatadog:
+		if datadogAPIKey == "" {
+			datadogAPIKey = os.Getenv(constants.DatadogAPIKeyEnvVar)
+		}
+		if datadogAPIKey == "" {
+			datadogAPIKey = secrets.DatadogAPIKey
+		}
+		if datadogAPIKey == "" {
+			return fmt.Errorf("datadog monitoring requires an API key. Use --datadog-api-key or set $%s", constants.DatadogAPIKeyEnvVar)
+		}
+		if datadogAppKey == "" {
+			datadogAppKey = os.Getenv(constants.DatadogAppKeyEnvVar)
+		}
+		if telemetry.DatadogSite != "" && datadogSite == constants.DatadogDefaultSite {
+			datadogSite = telemetry.DatadogSite
+		}
+	case constants.MonitoringCloudWatch:
+		if cloudWatchCredentialsPath == "" && secrets.CloudWatchCredentials == "" {
+			return fmt.Errorf("cloudwatch monitoring requires --cloudwatch-credentials, an AWS credentials file allowed to put metrics and logs to CloudWatch")
+		}
+		if cloudWatchCredentialsPath != "" && !utils.FileExists(utils.ExpandHome(cloudWatchCredentialsPath)) {
+			return fmt.Errorf("file %s not found", cloudWatchCredentialsPath)
+		}
+		if cloudWatchRegion == "" {
+			cloudWatchRegion = telemetry.CloudWatchRegion
+		}
+		if cloudWatchRegion == "" && useAWS && len(cmdLineRegion) > 0 {
+			cloudWatchRegion = cmdLineRegion[0]
+		}
+		if cloudWatchRegion == "" {
+			return fmt.Errorf("cloudwatch monitoring requires --cloudwatch-region")
+		}
+	}
+	return nil
+}
+
+// getClusterTelemetry returns the external monitoring settings and credentials of [clusterName],
+// if it exists
+func getClusterTelemetry(clusterName string) (models.TelemetryConfig, models.ClusterSecrets, error) {
+	if ok, err := app.ClusterExists(clusterName); err != nil || !ok {
+		return models.TelemetryConfig{}, models.ClusterSecrets{}, err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return models.TelemetryConfig{}, models.ClusterSecrets{}, err
+	}
+	if clusterConfig.Telemetry.Backend == "" {
+		return models.TelemetryConfig{}, models.ClusterSecrets{}, nil
+	}
+	secrets, err := app.LoadClusterSecrets(clusterName)
+	if err != nil {
+		return models.TelemetryConfig{}, models.ClusterSecrets{}, err
+	}
+	return clusterConfig.Telemetry, secrets, nil
+}
+
+// telemetryAgentBackend returns the external backend whose agent is installed on the nodes
+// (if any)
+func telemetryAgentBackend() string {
+	if monitoringBackend == constants.MonitoringPrometheus {
+		return ""
+	}
+	return monitoringBackend
+}
+
+// getTelemetryCredentials returns the credentials of the agent of [backend]: the datadog API
+// key, or the contents of the AWS credentials file of the cloudwatch agent
+func getTelemetryCredentials(clusterName string, backend string) (string, error) {
+	switch backend {
+	case constants.MonitoringDatadog:
+		return datadogAPIKey, nil
+	case constants.MonitoringCloudWatch:
+		if cloudWatchCredentialsPath == "" {
+			_, secrets, err := getClusterTelemetry(clusterName)
+			return secrets.CloudWatchCredentials, err
+		}
+		credentials, err := os.ReadFile(utils.ExpandHome(cloudWatchCredentialsPath))
+		return string(credentials), err
+	}
+	return "", fmt.Errorf("unsupported monitoring backend %q", backend)
+}
+
+// setupTelemetryAgent installs on [host] the agent exporting its metrics and logs to [backend]
+func setupTelemetryAgent(
+	host *models.Host,
+	backend string,
+	credentials string,
+	clusterName string,
+	network models.Network,
+) error {
+	cloudID := host.GetCloudID()
+	nodeID, err := getNodeID(app.GetNodeInstanceDirPath(cloudID))
+	if err != nil {
+		return err
+	}
+	inputs := remoteconfig.PrepareTelemetryInputs(clusterName, network.Name(), nodeID.String(), cloudID, cloudWatchRegion)
+	credentialsFile := []byte(credentials)
+	if backend == constants.MonitoringDatadog {
+		credentialsFile = remoteconfig.RenderDatadogAgentConfig(credentials, datadogSite, inputs.Tags())
+	}
+	return ssh.RunSSHSetupTelemetryAgent(host, backend, inputs, credentialsFile)
+}
+
+// setClusterTelemetry records on the cluster config the backend its nodes export metrics and
+// logs to, and saves the agent [credentials] on the cluster secrets
+func setClusterTelemetry(clusterName string, backend string, credentials string) error {
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	secrets, err := app.LoadClusterSecrets(clusterName)
+	if err != nil {
+		return err
+	}
+	clusterConfig.Telemetry.Backend = backend
+	switch backend {
+	case constants.MonitoringDatadog:
+		clusterConfig.Telemetry.DatadogSite = datadogSite
+		secrets.DatadogAPIKey = credentials
+	case constants.MonitoringCloudWatch:
+		clusterConfig.Telemetry.CloudWatchRegion = cloudWatchRegion
+		secrets.CloudWatchCredentials = credentials
+	}
+	if err := app.WriteClusterSecrets(clusterName, secrets); err != nil {
+		return err
+	}
+	return app.SetClusterConfig(clusterName, clusterConfig)
+}
+
+// getTelemetryDashboardChains returns the chains of the cluster subnets, for the dashboard
+func getTelemetryDashboardChains(clusterConfig models.ClusterConfig) ([]remoteconfig.TelemetryDashboardChain, error) {
+	chains := []remoteconfig.TelemetryDashboardChain{}
+	for _, subnetName := range clusterConfig.Subnets {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return nil, err
+		}
+		blockchainID := sc.Networks[clusterConfig.Network.Name()].BlockchainID
+		if blockchainID == ids.Empty {
+			continue
+		}
+		chains = append(chains, remoteconfig.TelemetryDashboardChain{
+			Name:      subnetName,
+			MetricsID: blockchainID.String(),
+		})
+	}
+	return chains, nil
+}
+
+// setupTelemetryDashboard creates or updates the dashboard of [clusterName] on the backend its
+// nodes export to. If the dashboard can't be created, it is saved locally for a manual import
+func setupTelemetryDashboard(clusterName string) error {
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	telemetry := clusterConfig.Telemetry
+	chains, err := getTelemetryDashboardChains(clusterConfig)
+	if err != nil {
+		return err
+	}
+	dashboard, err := remoteconfig.RenderTelemetryDashboard(
+		telemetry.Backend,
+		remoteconfig.PrepareTelemetryDashboardInputs(clusterName, clusterConfig.Network.Name(), chains, telemetry.CloudWatchRegion),
+	)
+	if err != nil {
+		return err
+	}
+	var dashboardID, dashboardURL string
+	switch telemetry.Backend {
+	case constants.MonitoringDatadog:
+		if datadogAppKey == "" && telemetry.Dashboard != "" {
+			// keep the existing dashboard
+			return nil
+		}
+		if datadogAppKey == "" {
+			err = fmt.Errorf("datadog dashboards can only be created with an application key. Use --datadog-app-key or set $%s", constants.DatadogAppKeyEnvVar)
+			break
+		}
+		dashboardID, dashboardURL, err = monitoring.PutDatadogDashboard(telemetry.DatadogSite, datadogAPIKey, datadogAppKey, telemetry.DashboardID, dashboard)
+	case constants.MonitoringCloudWatch:
+		var awsCloud *awsAPI.AwsCloud
+		awsCloud, err = awsAPI.NewAwsCloud(awsProfile, telemetry.CloudWatchRegion)
+		if err != nil {
+			break
+		}
+		dashboardID = "avalanche-" + cloudWatchDashboardNameRegex.ReplaceAllString(clusterName, "-")
+		dashboardURL, err = awsCloud.PutCloudWatchDashboard(dashboardID, dashboard)
+	}
+	if err != nil {
+		dashboardPath := app.GetTelemetryDashboardPath(clusterName, telemetry.Backend)
+		if writeErr := os.WriteFile(dashboardPath, dashboard, constants.WriteReadReadPerms); writeErr != nil {
+			return writeErr
+		}
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Warning: the %s dashboard of the cluster was not created: %s"), telemetry.Backend, err)
+		ux.Logger.PrintToUser("The dashboard was saved at %s, to be imported on %s", dashboardPath, telemetry.Backend)
+		return nil
+	}
+	clusterConfig.Telemetry.DashboardID = dashboardID
+	clusterConfig.Telemetry.Dashboard = dashboardURL
+	if err := app.SetClusterConfig(clusterName, clusterConfig); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("%s dashboard of cluster %s: %s", telemetry.Backend, clusterName, dashboardURL)
+	return nil
+}
+
+// enableTelemetry installs on the cluster nodes the agent exporting their metrics and logs to
+// [backend], and creates the cluster dashboard on it
+func enableTelemetry(clusterName string, clusterConfig models.ClusterConfig, backend string) error {
+	credentials, err := getTelemetryCredentials(clusterName, backend)
+	if err != nil {
+		return err
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	spinSession := ux.NewUserSpinner()
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, host *models.Host) {
+			defer wg.Done()
+			if err := host.Connect(0); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+				return
+			}
+			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, "Setup %s Agent", backend))
+			if err := setupTelemetryAgent(host, backend, credentials, clusterName, clusterConfig.Network); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+				ux.SpinFailWithError(spinner, "", err)
+				return
+			}
+			ux.SpinComplete(spinner)
+		}(&wgResults, host)
+	}
+	wg.Wait()
+	spinSession.Stop()
+	if wgResults.HasErrors() {
+		return fmt.Errorf("failed to set up the %s agent on node(s) %s", backend, wgResults.GetErrorHostMap())
+	}
+	if err := setClusterTelemetry(clusterName, backend, credentials); err != nil {
+		return err
+	}
+	return setupTelemetryDashboard(clusterName)
+}
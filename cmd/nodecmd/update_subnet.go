@@ -30,7 +30,7 @@ You can check the updated subnet bootstrap status by calling avalanche node stat
 		RunE: updateSubnet,
 	}
 
-	return cmd
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func updateSubnet(_ *cobra.Command, args []string) error {
@@ -109,6 +109,9 @@ func doUpdateSubnet(
 			); err != nil {
 				nodeResults.AddResult(host.NodeID, nil, err)
 			}
+			if err := ssh.RunSSHApplyHostNodeConfig(host, clusterConf.GetHostNodeConfig(host.GetCloudID())); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+			}
 			if err := ssh.RunSSHSyncSubnetData(app, host, network, subnetName); err != nil {
 				nodeResults.AddResult(host.NodeID, nil, err)
 			}
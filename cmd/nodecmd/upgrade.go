@@ -43,7 +43,7 @@ You can check the status after upgrade by calling avalanche node status`,
 		RunE: upgrade,
 	}
 
-	return cmd
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func upgrade(_ *cobra.Command, args []string) error {
@@ -63,6 +63,9 @@ func upgrade(_ *cobra.Command, args []string) error {
 		return err
 	}
 	defer node.DisconnectHosts(hosts)
+	if err := node.CheckManagedLayout(app, hosts, "node upgrade"); err != nil {
+		return err
+	}
 	toUpgradeNodesMap, err := getNodesUpgradeInfo(hosts)
 	if err != nil {
 		return err
@@ -71,15 +74,19 @@ func upgrade(_ *cobra.Command, args []string) error {
 	for host, upgradeInfo := range toUpgradeNodesMap {
 		if upgradeInfo.AvalancheGoVersion != "" {
 			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, fmt.Sprintf("Upgrading avalanchego to version %s...", upgradeInfo.AvalancheGoVersion)))
-			if err := upgradeAvalancheGo(host, upgradeInfo.AvalancheGoVersion); err != nil {
+			if err := upgradeAvalancheGo(host, upgradeInfo.AvalancheGoVersion, clusterConfig.GetHostNodeConfig(host.GetCloudID())); err != nil {
 				ux.SpinFailWithError(spinner, "", err)
 				return err
 			}
 			ux.SpinComplete(spinner)
 		}
 		if upgradeInfo.SubnetEVMVersion != "" {
+			goarch, err := getHostGoArch(host)
+			if err != nil {
+				return err
+			}
 			subnetEVMVersionToUpgradeToWoPrefix := strings.TrimPrefix(upgradeInfo.SubnetEVMVersion, "v")
-			subnetEVMArchive := fmt.Sprintf(constants.SubnetEVMArchive, subnetEVMVersionToUpgradeToWoPrefix)
+			subnetEVMArchive := fmt.Sprintf(constants.SubnetEVMArchive, subnetEVMVersionToUpgradeToWoPrefix, goarch)
 			subnetEVMReleaseURL := fmt.Sprintf(constants.SubnetEVMReleaseURL, upgradeInfo.SubnetEVMVersion, subnetEVMArchive)
 			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, fmt.Sprintf("Upgrading SubnetEVM to version %s...", upgradeInfo.SubnetEVMVersion)))
 			if err := getNewSubnetEVMRelease(host, subnetEVMReleaseURL, subnetEVMArchive); err != nil {
@@ -220,13 +227,28 @@ func checkIfKeyIsStandardVMName(vmName string) bool {
 func upgradeAvalancheGo(
 	host *models.Host,
 	avaGoVersionToUpdateTo string,
+	hostNodeConfig models.HostNodeConfig,
 ) error {
-	if err := ssh.RunSSHUpgradeAvalanchego(host, avaGoVersionToUpdateTo); err != nil {
+	if err := ssh.RunSSHUpgradeAvalanchego(host, avaGoVersionToUpdateTo, hostNodeConfig); err != nil {
 		return err
 	}
 	return nil
 }
 
+// getHostGoArch returns the go arch of the binaries to install on [host]: the architecture
+// recorded when the node was created, or the one reported by the host for nodes created
+// before architectures were recorded
+func getHostGoArch(host *models.Host) (string, error) {
+	nodeConfig, err := app.LoadClusterNodeConfig(host.GetCloudID())
+	if err != nil {
+		return "", err
+	}
+	if nodeConfig.Arch != "" {
+		return utils.GoArch(nodeConfig.Arch), nil
+	}
+	return ssh.GetHostArch(host)
+}
+
 func upgradeSubnetEVM(
 	host *models.Host,
 	subnetEVMBinaryPath string,
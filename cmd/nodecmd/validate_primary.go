@@ -68,7 +68,7 @@ Network.`,
 	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "UTC start time when this validator starts validating, in 'YYYY-MM-DD HH:MM:SS' format")
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long validator validates for after start time")
 
-	return cmd
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func GetMinStakingAmount(network models.Network) (uint64, error) {
@@ -3,7 +3,6 @@
 package nodecmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -59,22 +58,7 @@ You can check the subnet sync status by calling avalanche node status <clusterNa
 	cmd.Flags().BoolVar(&avoidSubnetValidationChecks, "no-validation-checks", true, "do not check if subnet is already synced or validated")
 	cmd.Flags().BoolVar(&avoidChecks, "no-checks", false, "do not check for bootstrapped status or healthy status")
 
-	return cmd
-}
-
-func parseSubnetSyncOutput(byteValue []byte) (string, error) {
-	var result map[string]interface{}
-	if err := json.Unmarshal(byteValue, &result); err != nil {
-		return "", err
-	}
-	statusInterface, ok := result["result"].(map[string]interface{})
-	if ok {
-		status, ok := statusInterface["status"].(string)
-		if ok {
-			return status, nil
-		}
-	}
-	return "", errors.New("unable to parse subnet sync status")
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func addNodeAsSubnetValidator(
@@ -124,26 +108,11 @@ func waitForSubnetValidator(
 	subnetID ids.ID,
 	nodeIDStr string,
 ) error {
-	timeout := 5 * time.Minute
-	poolTime := 1 * time.Second
 	nodeID, err := ids.NodeIDFromString(nodeIDStr)
 	if err != nil {
 		return err
 	}
-	startTime := time.Now()
-	for {
-		isValidator, err := subnet.IsSubnetValidator(subnetID, nodeID, network)
-		if err != nil {
-			return err
-		}
-		if isValidator {
-			return nil
-		}
-		if time.Since(startTime) > timeout {
-			return fmt.Errorf("node %s not validating subnet ID %s after %d seconds", nodeID, subnetID, uint32(timeout.Seconds()))
-		}
-		time.Sleep(poolTime)
-	}
+	return subnet.WaitForSubnetValidator(subnetID, nodeID, network, 5*time.Minute)
 }
 
 // getNodeSubnetSyncStatus checks if node is bootstrapped to blockchain blockchainID
@@ -158,7 +127,7 @@ func getNodeSubnetSyncStatus(
 	if resp, err := ssh.RunSSHSubnetSyncStatus(host, blockchainID); err != nil {
 		return "", err
 	} else {
-		if subnetSyncStatus, err := parseSubnetSyncOutput(resp); err != nil {
+		if subnetSyncStatus, err := node.ParseSubnetSyncOutput(resp); err != nil {
 			return "", err
 		} else {
 			return subnetSyncStatus, nil
@@ -49,7 +49,7 @@ func newWhitelistCmd() *cobra.Command {
 	cmd.Flags().StringVar(&userIPAddress, "ip", "", "ip address to whitelist")
 	cmd.Flags().StringVar(&userPubKey, "ssh", "", "ssh public key to whitelist")
 	cmd.Flags().BoolVarP(&discoverIP, "current-ip", "y", false, "whitelist current host ip")
-	return cmd
+	return cobrautils.MarkClusterState(cmd)
 }
 
 // Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
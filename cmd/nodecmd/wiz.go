@@ -98,8 +98,9 @@ The node wiz command creates a devnet and deploys, sync and validate a subnet in
 	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to create cloud resources")
 	cmd.Flags().IntSliceVar(&numValidatorsNodes, "num-validators", []int{}, "number of nodes to create per region(s). Use comma to separate multiple numbers for each region in the same order as --region flag")
 	cmd.Flags().StringVar(&nodeType, "node-type", "", "cloud instance type. Use 'default' to use recommended default instance type")
-	cmd.Flags().StringVar(&cmdLineGCPCredentialsPath, "gcp-credentials", "", "use given GCP credentials")
+	cmd.Flags().StringVar(&cmdLineGCPCredentialsPath, "gcp-credentials", "", "use given GCP credentials (service account key or workload identity federation config)")
 	cmd.Flags().StringVar(&cmdLineGCPProjectName, "gcp-project", "", "use given GCP project")
+	cmd.Flags().StringVar(&cmdLineGCPImpersonateServiceAccount, "gcp-impersonate-service-account", "", "impersonate given GCP service account (defaults to $"+constants.GCPImpersonateEnvVar+")")
 	cmd.Flags().StringVar(&cmdLineAlternativeKeyPairName, "alternative-key-pair-name", "", "key pair name to use if default one generates conflicts")
 	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
 	cmd.Flags().BoolVar(&defaultValidatorParams, "default-validator-params", false, "use default weight/start/duration params for subnet validator")
@@ -155,7 +156,7 @@ The node wiz command creates a devnet and deploys, sync and validate a subnet in
 	cmd.Flags().BoolVar(&replaceKeyPair, "auto-replace-keypair", false, "automatically replaces key pair to access node if previous key pair is not found")
 	cmd.Flags().BoolVar(&publicHTTPPortAccess, "public-http-port", false, "allow public access to avalanchego HTTP port")
 	cmd.Flags().StringSliceVar(&subnetAliases, "subnet-aliases", nil, "additional subnet aliases to be used for RPC calls in addition to subnet blockchain name")
-	return cmd
+	return cobrautils.MarkClusterState(cmd)
 }
 
 func wiz(cmd *cobra.Command, args []string) error {
@@ -752,7 +753,7 @@ func waitForClusterSubnetStatus(
 					nodeResults.AddResult(host.NodeID, nil, err)
 					return
 				} else {
-					if subnetSyncStatus, err := parseSubnetSyncOutput(syncstatus); err != nil {
+					if subnetSyncStatus, err := node.ParseSubnetSyncOutput(syncstatus); err != nil {
 						nodeResults.AddResult(host.NodeID, nil, err)
 						return
 					} else {
@@ -3,10 +3,11 @@
 package primarycmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
@@ -19,8 +20,15 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/spf13/cobra"
 )
 
@@ -34,6 +42,7 @@ var (
 	keyName                      string
 	useLedger                    bool
 	ledgerAddresses              []string
+	custodyAddresses             []string
 	nodeIDStr                    string
 	weight                       uint64
 	delegationFee                uint32
@@ -41,22 +50,28 @@ var (
 	duration                     time.Duration
 	publicKey                    string
 	pop                          string
+	stakingDir                   string
+	stakeOwners                  []string
+	outputTxPath                 string
 	ErrMutuallyExlusiveKeyLedger = errors.New("--key and --ledger,--ledger-addrs are mutually exclusive")
 	ErrStoredKeyOnMainnet        = errors.New("--key is not available for mainnet operations")
 )
 
-type jsonProofOfPossession struct {
-	PublicKey         string `json:"publicKey"`
-	ProofOfPossession string `json:"proofOfPossession"`
-}
-
 // avalanche primary addValidator
 func newAddValidatorCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "addValidator",
 		Short: "Add a validator to Primary Network",
 		Long: `The primary addValidator command adds a node as a validator 
-in the Primary Network`,
+in the Primary Network
+
+The node BLS info can be given with --public-key and --proof-of-possession, or obtained
+from the node staking files with --staking-dir. Either way, the proof of possession is
+verified before issuing the transaction.
+
+If the staked funds are owned by a multisig, --stake-owners lists the owner addresses not
+present in the used key or ledger. The partially signed transaction is then saved, to be
+signed by the remaining owners with transaction sign, and issued with transaction commit.`,
 		RunE: addValidator,
 		Args: cobrautils.ExactArgs(0),
 	}
@@ -68,13 +83,17 @@ in the Primary Network`,
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long this validator will be staking")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().StringSliceVar(&custodyAddresses, "custody-addrs", nil, "stake and pay with the given P-Chain addresses of keys kept by custodial signers, saving the tx for them to sign")
 	cmd.Flags().StringVar(&publicKey, "public-key", "", "set the BLS public key of the validator to add")
 	cmd.Flags().StringVar(&pop, "proof-of-possession", "", "set the BLS proof of possession of the validator to add")
 	cmd.Flags().Uint32Var(&delegationFee, "delegation-fee", 0, "set the delegation fee (20 000 is equivalent to 2%)")
+	cmd.Flags().StringVar(&stakingDir, "staking-dir", "", "get the NodeID and BLS info of the validator from the staker.crt and signer.key files at this dir")
+	cmd.Flags().StringSliceVar(&stakeOwners, "stake-owners", nil, "P-Chain addresses that own the staked funds together with the used key (multisig)")
+	cmd.Flags().StringVar(&outputTxPath, "output-tx-path", "", "file path of the partially signed tx, if the staked funds are owned by a multisig")
 	return cmd
 }
 
-func promptProofOfPossession() (jsonProofOfPossession, error) {
+func promptProofOfPossession() (*signer.ProofOfPossession, error) {
 	if publicKey != "" {
 		err := prompts.ValidateHexa(publicKey)
 		if err != nil {
@@ -99,17 +118,39 @@ func promptProofOfPossession() (jsonProofOfPossession, error) {
 		txt := "What is the public key of the node's BLS?"
 		publicKey, err = app.Prompt.CaptureValidatedString(txt, prompts.ValidateHexa)
 		if err != nil {
-			return jsonProofOfPossession{}, err
+			return nil, err
 		}
 	}
 	if pop == "" {
 		txt := "What is the proof of possession of the node's BLS?"
 		pop, err = app.Prompt.CaptureValidatedString(txt, prompts.ValidateHexa)
 		if err != nil {
-			return jsonProofOfPossession{}, err
+			return nil, err
 		}
 	}
-	return jsonProofOfPossession{PublicKey: publicKey, ProofOfPossession: pop}, nil
+	return utils.ParseBLSPoP(publicKey, pop)
+}
+
+// getStakingDirParams returns the NodeID and BLS proof of possession of the node
+// whose staking files are at [dir]
+func getStakingDirParams(dir string) (ids.NodeID, *signer.ProofOfPossession, error) {
+	certBytes, err := os.ReadFile(filepath.Join(dir, constants.StakerCertFileName))
+	if err != nil {
+		return ids.EmptyNodeID, nil, err
+	}
+	nodeID, err := utils.ToNodeID(certBytes)
+	if err != nil {
+		return ids.EmptyNodeID, nil, fmt.Errorf("invalid staker certificate: %w", err)
+	}
+	blsKeyBytes, err := os.ReadFile(filepath.Join(dir, constants.BLSKeyFileName))
+	if err != nil {
+		return ids.EmptyNodeID, nil, err
+	}
+	blsSk, err := bls.SecretKeyFromBytes(blsKeyBytes)
+	if err != nil {
+		return ids.EmptyNodeID, nil, fmt.Errorf("invalid BLS signer key: %w", err)
+	}
+	return nodeID, signer.NewProofOfPossession(blsSk), nil
 }
 
 func addValidator(_ *cobra.Command, _ []string) error {
@@ -139,17 +180,20 @@ func addValidator(_ *cobra.Command, _ []string) error {
 	if useLedger && keyName != "" {
 		return ErrMutuallyExlusiveKeyLedger
 	}
+	if len(custodyAddresses) > 0 && (useLedger || keyName != "") {
+		return keychain.ErrCustodyWithKeySource
+	}
 
 	switch network.Kind {
 	case models.Fuji:
-		if !useLedger && keyName == "" {
+		if !useLedger && keyName == "" && len(custodyAddresses) == 0 {
 			useLedger, keyName, err = prompts.GetKeyOrLedger(app.Prompt, constants.PayTxsFeesMsg, app.GetKeyDir(), false)
 			if err != nil {
 				return err
 			}
 		}
 	case models.Mainnet:
-		useLedger = true
+		useLedger = len(custodyAddresses) == 0
 		if keyName != "" {
 			return ErrStoredKeyOnMainnet
 		}
@@ -157,7 +201,20 @@ func addValidator(_ *cobra.Command, _ []string) error {
 		return errors.New("unsupported network")
 	}
 
-	if nodeIDStr == "" {
+	if stakingDir != "" && (publicKey != "" || pop != "") {
+		return fmt.Errorf("--staking-dir is mutually exclusive with --public-key and --proof-of-possession")
+	}
+
+	var proofOfPossession *signer.ProofOfPossession
+	if stakingDir != "" {
+		nodeID, proofOfPossession, err = getStakingDirParams(stakingDir)
+		if err != nil {
+			return err
+		}
+		if nodeIDStr != "" && nodeIDStr != nodeID.String() {
+			return fmt.Errorf("given NodeID %s does not match NodeID %s of staking dir %s", nodeIDStr, nodeID, stakingDir)
+		}
+	} else if nodeIDStr == "" {
 		nodeID, err = blockchaincmd.PromptNodeID("add as Primary Network Validator")
 		if err != nil {
 			return err
@@ -169,6 +226,11 @@ func addValidator(_ *cobra.Command, _ []string) error {
 		}
 	}
 
+	stakeOwnersAddrs, err := address.ParseToIDs(stakeOwners)
+	if err != nil {
+		return fmt.Errorf("failure parsing stake owners: %w", err)
+	}
+
 	minValStake, err := nodecmd.GetMinStakingAmount(network)
 	if err != nil {
 		return err
@@ -182,27 +244,55 @@ func addValidator(_ *cobra.Command, _ []string) error {
 	if weight < minValStake {
 		return fmt.Errorf("illegal weight, must be greater than or equal to %d: %d", minValStake, weight)
 	}
+	if maxValStake := network.GenesisParams().MaxValidatorStake; weight > maxValStake {
+		return fmt.Errorf("illegal weight, must be less than or equal to %d: %d", maxValStake, weight)
+	}
 
 	fee := network.GenesisParams().TxFeeConfig.StaticFeeConfig.AddPrimaryNetworkValidatorFee
-	kc, err := keychain.GetKeychain(app, false, useLedger, ledgerAddresses, keyName, network, fee)
+	var kc *keychain.Keychain
+	if len(custodyAddresses) > 0 {
+		kc, err = keychain.GetCustodyKeychain(network, custodyAddresses)
+	} else {
+		kc, err = keychain.GetKeychain(app, false, useLedger, ledgerAddresses, keyName, network, fee)
+	}
 	if err != nil {
 		return err
 	}
 
 	network.HandlePublicNetworkSimulation()
 
-	jsonPop, err := promptProofOfPossession()
-	if err != nil {
-		return err
+	// funds owned by a multisig are not all accounted as the key balance
+	if len(stakeOwnersAddrs) == 0 {
+		balance, err := utils.GetNetworkBalance(kc.Addresses().List(), network.Endpoint)
+		if err != nil {
+			return err
+		}
+		if balance < weight+fee {
+			return fmt.Errorf("insufficient balance: %.9f AVAX available, %.9f AVAX needed to stake and pay fees",
+				float64(balance)/float64(units.Avax),
+				float64(weight+fee)/float64(units.Avax),
+			)
+		}
 	}
-	popBytes, err := json.Marshal(jsonPop)
-	if err != nil {
-		return err
+
+	if proofOfPossession == nil {
+		proofOfPossession, err = promptProofOfPossession()
+		if err != nil {
+			return err
+		}
 	}
 	start, duration, err = nodecmd.GetTimeParametersPrimaryNetwork(network, 0, duration, startTimeStr, false)
 	if err != nil {
 		return err
 	}
+	stakingConfig := network.GenesisParams().StakingConfig
+	if duration < stakingConfig.MinStakeDuration || duration > stakingConfig.MaxStakeDuration {
+		return fmt.Errorf("staking period must be between %s and %s: %s",
+			stakingConfig.MinStakeDuration,
+			stakingConfig.MaxStakeDuration,
+			duration,
+		)
+	}
 	deployer := subnet.NewPublicDeployer(app, kc, network)
 	nodecmd.PrintNodeJoinPrimaryNetworkOutput(nodeID, weight, network, start)
 	recipientAddr := kc.Addresses().List()[0]
@@ -217,8 +307,71 @@ func addValidator(_ *cobra.Command, _ []string) error {
 			return fmt.Errorf("delegation fee has to be larger than %d", defaultFee)
 		}
 	}
-	_, err = deployer.AddPermissionlessValidator(ids.Empty, ids.Empty, nodeID, weight, uint64(start.Unix()), uint64(start.Add(duration).Unix()), recipientAddr, delegationFee, popBytes, nil)
-	return err
+	if reward, err := utils.EstimatePrimaryNetworkReward(network.Endpoint, stakingConfig.RewardConfig, duration, weight); err != nil {
+		ux.Logger.RedXToUser("failure estimating validation reward: %s", err)
+	} else {
+		ux.Logger.PrintToUser("Estimated validation reward: %.9f AVAX (only paid if the validator keeps the required uptime)", float64(reward)/float64(units.Avax))
+	}
+	isFullySigned, txID, tx, err := deployer.AddPrimaryNetworkValidator(
+		nodeID,
+		weight,
+		uint64(start.Unix()),
+		uint64(start.Add(duration).Unix()),
+		recipientAddr,
+		delegationFee,
+		proofOfPossession,
+		stakeOwnersAddrs,
+	)
+	if err != nil {
+		return err
+	}
+	if !isFullySigned {
+		return SaveNotFullySignedTx(tx, outputTxPath, false)
+	}
+	ux.Logger.PrintToUser("Transaction successful, transaction ID: %s", txID)
+	return nil
+}
+
+// SaveNotFullySignedTx saves a Primary Network tx that still needs to be signed by
+// some of the owners of its inputs
+func SaveNotFullySignedTx(tx *txs.Tx, outputTxPath string, forceOverwrite bool) error {
+	missingSignatures, totalSignatures, err := txutils.GetMissingSignatures(tx)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("%d of %d required signatures have been signed. "+
+		"Saving tx to disk to enable remaining signing.", totalSignatures-missingSignatures, totalSignatures)
+	if outputTxPath == "" {
+		ux.Logger.PrintToUser("")
+		if forceOverwrite {
+			outputTxPath, err = app.Prompt.CaptureString("Path to export partially signed tx to")
+		} else {
+			outputTxPath, err = app.Prompt.CaptureNewFilepath("Path to export partially signed tx to")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if forceOverwrite {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Overwriting %s", outputTxPath)
+	}
+	if err := txutils.SaveToDisk(tx, outputTxPath, forceOverwrite); err != nil {
+		return err
+	}
+	if missingSignatures == 0 {
+		blockchaincmd.PrintReadyToSignMsg("", outputTxPath)
+		return nil
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Connect a ledger or choose a stored key of one of the remaining stake owners "+
+		"and run the signing command, or send %q to another owner for signing.", outputTxPath)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Signing command:")
+	ux.Logger.PrintToUser("  avalanche transaction sign --input-tx-filepath %s", outputTxPath)
+	blockchaincmd.PrintCustodySignMsg("", outputTxPath)
+	return nil
 }
 
 func getDelegationFeeOption(app *application.Avalanche, network models.Network) (uint32, error) {
@@ -45,7 +45,7 @@ func newDescribeCmd() *cobra.Command {
 		Args:  cobrautils.ExactArgs(0),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, describeSupportedNetworkOptions)
-	return cmd
+	return cobrautils.MarkReadOnly(cmd)
 }
 
 func describe(_ *cobra.Command, _ []string) error {
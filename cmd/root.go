@@ -5,10 +5,12 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 	"os/user"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"syscall"
 	"time"
@@ -16,16 +18,21 @@ import (
 	"github.com/ava-labs/avalanche-cli/cmd/validatorcmd"
 
 	"github.com/ava-labs/avalanche-cli/cmd/backendcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/backupcmd"
 	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
+	"github.com/ava-labs/avalanche-cli/cmd/cachecmd"
 	"github.com/ava-labs/avalanche-cli/cmd/configcmd"
 	"github.com/ava-labs/avalanche-cli/cmd/contractcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/doctorcmd"
 	"github.com/ava-labs/avalanche-cli/cmd/interchaincmd"
 	"github.com/ava-labs/avalanche-cli/cmd/interchaincmd/messengercmd"
 	"github.com/ava-labs/avalanche-cli/cmd/interchaincmd/tokentransferrercmd"
 	"github.com/ava-labs/avalanche-cli/cmd/keycmd"
+	"github.com/ava-labs/avalanche-cli/cmd/logscmd"
 	"github.com/ava-labs/avalanche-cli/cmd/networkcmd"
 	"github.com/ava-labs/avalanche-cli/cmd/nodecmd"
 	"github.com/ava-labs/avalanche-cli/cmd/primarycmd"
+	"github.com/ava-labs/avalanche-cli/cmd/schedulecmd"
 	"github.com/ava-labs/avalanche-cli/cmd/transactioncmd"
 	"github.com/ava-labs/avalanche-cli/cmd/updatecmd"
 	"github.com/ava-labs/avalanche-cli/internal/migrations"
@@ -33,24 +40,38 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/config"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/logs"
 	"github.com/ava-labs/avalanche-cli/pkg/metrics"
+	"github.com/ava-labs/avalanche-cli/pkg/networkupgrades"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/policy"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/statebackend"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	sdkinterchain "github.com/ava-labs/avalanche-cli/sdk/interchain"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/perms"
 	ansi "github.com/k0kubun/go-ansi"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var (
-	app       *application.Avalanche
-	logLevel  string
-	Version   = ""
-	cfgFile   string
-	skipCheck bool
+	app               *application.Avalanche
+	logLevel          string
+	verbosity         string
+	Version           = ""
+	cfgFile           string
+	skipCheck         bool
+	skipUpgradeChecks bool
+	readOnly          bool
+	approvalPaths     []string
+	// change to a cluster made under the lock of the shared state backend (if any)
+	clusterStateSession *statebackend.Session
 )
 
 func NewRootCmd() *cobra.Command {
@@ -77,8 +98,16 @@ in with avalanche blockchain create myNewBlockchain.`,
 		StringVar(&cfgFile, "config", "", "config file (default is $HOME/.avalanche-cli/config.json)")
 	rootCmd.PersistentFlags().
 		StringVar(&logLevel, "log-level", "ERROR", "log level for the application")
+	rootCmd.PersistentFlags().
+		StringVar(&verbosity, constants.VerbosityFlag, constants.NormalVerbosity, "console output verbosity [quiet, normal, verbose, debug]. --log-level takes precedence on the displayed logs")
 	rootCmd.PersistentFlags().
 		BoolVar(&skipCheck, constants.SkipUpdateFlag, false, "skip check for new versions")
+	rootCmd.PersistentFlags().
+		BoolVar(&skipUpgradeChecks, constants.SkipUpgradeChecksFlag, false, "allow features not supported by the activated upgrades of the target network")
+	rootCmd.PersistentFlags().
+		BoolVar(&readOnly, constants.ReadOnlyFlag, false, "only allow commands that do not modify local state, sign or broadcast (default from config readOnly)")
+	rootCmd.PersistentFlags().
+		StringSliceVar(&approvalPaths, constants.ApprovalFlag, nil, "approval files of the operation, for commands with a confirmation policy requiring approvals")
 
 	// add sub commands
 	rootCmd.AddCommand(blockchaincmd.NewCmd(app))
@@ -125,38 +154,251 @@ in with avalanche blockchain create myNewBlockchain.`,
 	rootCmd.AddCommand(contractcmd.NewCmd(app))
 	// add validator command
 	rootCmd.AddCommand(validatorcmd.NewCmd(app))
+	// add backup command
+	rootCmd.AddCommand(backupcmd.NewCmd(app, Version))
+	// add doctor command
+	rootCmd.AddCommand(doctorcmd.NewCmd(app, Version))
+	// add logs command
+	rootCmd.AddCommand(logscmd.NewCmd(app))
+	// add schedule command
+	rootCmd.AddCommand(schedulecmd.NewCmd(app))
+	// add cache command
+	rootCmd.AddCommand(cachecmd.NewCmd(app))
 
 	cobrautils.ConfigureRootCmd(rootCmd)
 
 	return rootCmd
 }
 
-func createApp(cmd *cobra.Command, _ []string) error {
+func createApp(cmd *cobra.Command, args []string) error {
+	if readOnlyMode(cmd) {
+		return createReadOnlyApp(cmd)
+	}
 	baseDir, err := setupEnv()
 	if err != nil {
 		return err
 	}
-	log, err := setupLogging(baseDir)
+	log, commandLogPath, err := setupLogging(cmd, baseDir)
 	if err != nil {
 		return err
 	}
 	log.Info("-----------")
 	log.Info(fmt.Sprintf("cmd: %s", strings.Join(os.Args[1:], " ")))
+	log.Debug("environment", zap.String("version", Version), zap.String("os", runtime.GOOS), zap.String("arch", runtime.GOARCH))
 	cf := config.New()
 	app.Setup(baseDir, log, cf, prompts.NewPrompter(), application.NewDownloader())
+	app.CommandLogPath = commandLogPath
+	networkupgrades.SkipChecks = skipUpgradeChecks
 
 	initConfig()
+	if err := initProxyConfig(); err != nil {
+		return err
+	}
+	if err := applyFlagDefaults(cmd); err != nil {
+		return err
+	}
+	if err := enforceConfirmationPolicy(cmd, args); err != nil {
+		return err
+	}
+	useManuallySignedMessages()
 
 	if err := migrations.RunMigrations(app); err != nil {
 		return err
 	}
+	useRPCAuthTokens()
 	if err := checkForUpdates(cmd, app); err != nil {
 		return err
 	}
+	if err := beginClusterStateSession(cmd, args); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// beginClusterStateSession locks the cluster changed by [cmd] on the shared state backend,
+// if any, and pulls its state, so changes made from other machines are not overwritten
+func beginClusterStateSession(cmd *cobra.Command, args []string) error {
+	if !cobrautils.ChangesClusterState(cmd) || len(args) == 0 {
+		return nil
+	}
+	session, err := statebackend.Begin(app, args[0], cmd.CommandPath())
+	if err != nil {
+		return err
+	}
+	clusterStateSession = session
+	return nil
+}
+
+// endClusterStateSession pushes the cluster state changed by the command, unless it failed
+// with [cmdErr], and releases the cluster lock
+func endClusterStateSession(cmdErr error) error {
+	if clusterStateSession == nil {
+		return cmdErr
+	}
+	if err := clusterStateSession.End(cmdErr == nil); err != nil {
+		if cmdErr != nil {
+			app.Log.Error("failure ending cluster state session", zap.Error(err))
+			return cmdErr
+		}
+		return err
+	}
+	return cmdErr
+}
+
+// readOnlyMode returns true if read-only mode is requested, either by flag or
+// by config. The flag, if given, takes precedence
+func readOnlyMode(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed(constants.ReadOnlyFlag) {
+		return readOnly
+	}
+	cf := config.New()
+	cf.SetConfig(logging.NoLog{}, getConfigFile())
+	return cf.GetConfigBoolValue(constants.ConfigReadOnlyKey)
+}
+
+// createReadOnlyApp sets up the app without writing anything to the base dir:
+// no dirs are created, logs are only displayed, and neither migrations nor
+// update checks are run. Only commands marked as read-only can be executed
+func createReadOnlyApp(cmd *cobra.Command) error {
+	if !cobrautils.IsReadOnly(cmd) {
+		return fmt.Errorf(
+			"%q may modify local state, sign or broadcast, and is not available in read-only mode (use --%s=false to override)",
+			cmd.CommandPath(),
+			constants.ReadOnlyFlag,
+		)
+	}
+	baseDir, err := getBaseDir()
+	if err != nil {
+		return err
+	}
+	displayLevel, err := getDisplayLevel(cmd)
+	if err != nil {
+		return err
+	}
+	log := logging.NewLogger(
+		"avalanche",
+		logging.NewWrappedCore(displayLevel, os.Stderr, logging.Colors.ConsoleEncoder()),
+	)
+	ux.NewUserLog(log, os.Stdout)
+	ux.Logger.SetQuiet(verbosity == constants.QuietVerbosity)
+	cf := config.New()
+	cf.ReadOnly = true
+	app.Setup(baseDir, log, cf, prompts.NewPrompter(), application.NewDownloader())
+	app.ReadOnly = true
+	networkupgrades.SkipChecks = skipUpgradeChecks
+	initConfig()
+	if err := initProxyConfig(); err != nil {
+		return err
+	}
+	if err := applyFlagDefaults(cmd); err != nil {
+		return err
+	}
+	useManuallySignedMessages()
+	useRPCAuthTokens()
+	return nil
+}
 
+// applyFlagDefaults sets the flags of [cmd] not given on the command line to the
+// defaults configured for it with config set-default
+func applyFlagDefaults(cmd *cobra.Command) error {
+	commandPath := cobrautils.CommandConfigPath(cmd)
+	if commandPath == "" {
+		return nil
+	}
+	defaults := app.Conf.GetConfigStringMapValue(constants.ConfigFlagDefaultsKey + "." + commandPath)
+	applied, err := cobrautils.ApplyFlagDefaults(cmd, defaults)
+	if err != nil {
+		return err
+	}
+	if len(applied) > 0 {
+		// on stderr, so machine readable output of the command is not altered
+		prevWriter := ux.Logger.SetWriter(os.Stderr)
+		defer ux.Logger.SetWriter(prevWriter)
+		ux.Logger.PrintToUser(
+			logging.Yellow.Wrap("Using flag defaults from config (see avalanche config set-default): --%s"),
+			strings.Join(applied, " --"),
+		)
+	}
 	return nil
 }
 
+// enforceConfirmationPolicy makes [cmd] go through the confirmation policy configured
+// for it with config policy, if it applies to the selected network
+func enforceConfirmationPolicy(cmd *cobra.Command, args []string) error {
+	commandPath := cobrautils.CommandConfigPath(cmd)
+	if commandPath == "" {
+		return nil
+	}
+	confirmationPolicy, found, err := policy.GetPolicy(app, commandPath)
+	if err != nil || !found {
+		return err
+	}
+	network := policy.ResolveNetwork(app, cmd)
+	if !confirmationPolicy.AppliesTo(network) {
+		return nil
+	}
+	// approvals must be given for a known network, as they can't be asked for later
+	if confirmationPolicy.Approvals > 0 && network == "" && cmd.Flags().Lookup("mainnet") != nil {
+		return fmt.Errorf("the confirmation policy of %s requires approvals, so the network must be selected by a flag", commandPath)
+	}
+	operation := policy.Operation(cmd, args, network, time.Now())
+	app.Log.Info("enforcing confirmation policy", zap.String("operation", operation))
+	return policy.Enforce(app, confirmationPolicy, operation, approvalPaths)
+}
+
+// useManuallySignedMessages makes available to the flows the warp messages
+// signed with interchain sign-warp
+func useManuallySignedMessages() {
+	if err := interchain.UseManuallySignedMessages(app); err != nil {
+		app.Log.Warn("failed to load manually signed warp messages", zap.Error(err))
+	}
+}
+
+// useRPCAuthTokens makes the flows authenticate to the cluster HTTPS endpoints
+// locked down with node rpc-auth
+func useRPCAuthTokens() {
+	if err := node.UseRPCAuthTokens(app); err != nil {
+		app.Log.Warn("failed to load RPC auth tokens", zap.Error(err))
+	}
+}
+
+// handleAggregationError saves the warp message that failed signature aggregation
+// on [err], so it can be manually signed
+func handleAggregationError(err error) {
+	var aggregationErr *sdkinterchain.AggregationError
+	if !errors.As(err, &aggregationErr) || app.ReadOnly || app.Log == nil {
+		return
+	}
+	requestPath := interchain.GetSigningRequestPath(app, aggregationErr.Request.MessageID)
+	if err := interchain.SaveSigningRequest(requestPath, aggregationErr.Request); err != nil {
+		app.Log.Warn("failed to save warp signing request", zap.Error(err))
+		return
+	}
+	ux.Logger.PrintToUser("Not enough validator signatures could be aggregated for warp message %s", aggregationErr.Request.MessageID)
+	ux.Logger.PrintToUser("It can be signed manually by the validators, see: avalanche interchain sign-warp --help")
+	ux.Logger.PrintToUser("  avalanche interchain sign-warp export %s", aggregationErr.Request.MessageID)
+}
+
+// handleNetworkError points to the proxy settings if [err] is due to a failed
+// outbound connection
+func handleNetworkError(err error) {
+	var (
+		opErr  *net.OpError
+		dnsErr *net.DNSError
+	)
+	if ux.Logger == nil || (!errors.As(err, &opErr) && !errors.As(err, &dnsErr)) {
+		return
+	}
+	proxies := utils.GetProxyConfig()
+	if proxies.HTTPProxy == "" && proxies.HTTPSProxy == "" && proxies.SSHProxy == "" {
+		ux.Logger.PrintToUser("An outbound connection failed. If your network requires a proxy, set it with avalanche config proxy,")
+		ux.Logger.PrintToUser("or with the HTTP_PROXY, HTTPS_PROXY and ALL_PROXY environment variables")
+	} else {
+		ux.Logger.PrintToUser("An outbound connection failed. Check the proxies in use with avalanche config proxy")
+	}
+}
+
 func UpdateCheckDisabled(app *application.Avalanche) bool {
 	// returns true obly if explicitly disabled in the config
 	if app.Conf.ConfigFileExists() {
@@ -244,18 +486,28 @@ func checkForUpdates(cmd *cobra.Command, app *application.Avalanche) error {
 }
 
 func handleTracking(cmd *cobra.Command, _ []string) {
+	if app.ReadOnly {
+		return
+	}
 	metrics.HandleTracking(cmd, cmd.CommandPath(), app, nil)
 }
 
-func setupEnv() (string, error) {
-	// Set base dir
+func getBaseDir() (string, error) {
 	usr, err := user.Current()
 	if err != nil {
 		// no logger here yet
 		fmt.Printf("unable to get system user %s\n", err)
 		return "", err
 	}
-	baseDir := filepath.Join(usr.HomeDir, constants.BaseDirName)
+	return filepath.Join(usr.HomeDir, constants.BaseDirName), nil
+}
+
+func setupEnv() (string, error) {
+	// Set base dir
+	baseDir, err := getBaseDir()
+	if err != nil {
+		return "", err
+	}
 
 	// Create base dir if it doesn't exist
 	if err := os.MkdirAll(baseDir, os.ModePerm); err != nil {
@@ -323,50 +575,133 @@ func setupEnv() (string, error) {
 	return baseDir, nil
 }
 
-func setupLogging(baseDir string) (logging.Logger, error) {
-	var err error
-
-	config := logging.Config{}
-	config.LogLevel = logging.Info
-	config.DisplayLevel, err = logging.ToLevel(logLevel)
+// setupLogging creates the application logger. It displays on the console the entries
+// allowed by the verbosity, writes the info entries to the rotating avalanche log, and
+// always writes all the debug entries as json to the log file of the [cmd] invocation.
+// Returns the path of the invocation log file, if it could be created
+func setupLogging(cmd *cobra.Command, baseDir string) (logging.Logger, string, error) {
+	displayLevel, err := getDisplayLevel(cmd)
 	if err != nil {
-		return nil, fmt.Errorf("invalid log level configured: %s", logLevel)
+		return nil, "", err
 	}
-	config.Directory = filepath.Join(baseDir, constants.LogDir)
-	if err := os.MkdirAll(config.Directory, perms.ReadWriteExecute); err != nil {
-		return nil, fmt.Errorf("failed creating log directory: %w", err)
+	logDir := filepath.Join(baseDir, constants.LogDir)
+	if err := os.MkdirAll(logDir, perms.ReadWriteExecute); err != nil {
+		return nil, "", fmt.Errorf("failed creating log directory: %w", err)
 	}
-
-	// some logging config params
-	config.LogFormat = logging.Colors
-	config.MaxSize = constants.MaxLogFileSize
-	config.MaxFiles = constants.MaxNumOfLogFiles
-	config.MaxAge = constants.RetainOldFiles
-
-	factory := logging.NewFactory(config)
-	log, err := factory.Make("avalanche")
+	consoleCore := logging.NewWrappedCore(displayLevel, os.Stdout, logging.Colors.ConsoleEncoder())
+	fileCore := logging.NewWrappedCore(
+		logging.Info,
+		&lumberjack.Logger{
+			Filename:   filepath.Join(logDir, "avalanche.log"),
+			MaxSize:    constants.MaxLogFileSize,
+			MaxAge:     constants.RetainOldFiles,
+			MaxBackups: constants.MaxNumOfLogFiles,
+		},
+		logging.Colors.FileEncoder(),
+	)
+	fileCores := []logging.WrappedCore{fileCore}
+	commandLogPath := ""
+	commandLogFile, err := logs.NewCommandLogFile(
+		filepath.Join(logDir, constants.CommandLogsDir),
+		cmd.CommandPath(),
+		time.Now(),
+	)
 	if err != nil {
-		factory.Close()
-		return nil, fmt.Errorf("failed setting up logging, exiting: %w", err)
+		// not critical, the command can still run
+		fmt.Fprintf(os.Stderr, "failed creating the command log file: %s\n", err)
+	} else {
+		commandLogPath = commandLogFile.Name()
+		fileCores = append(fileCores, logging.NewWrappedCore(logging.Debug, commandLogFile, logging.JSON.FileEncoder()))
+	}
+	log := logging.NewLogger("", append([]logging.WrappedCore{consoleCore}, fileCores...)...)
+	// create the user facing logger as a global var. It prints on its own to the console,
+	// so it only logs to files
+	ux.NewUserLog(logging.NewLogger("", fileCores...), os.Stdout)
+	ux.Logger.SetQuiet(verbosity == constants.QuietVerbosity)
+	return log, commandLogPath, nil
+}
+
+// getDisplayLevel returns the level of the log entries displayed on the console: the
+// one given by --log-level, or else the one corresponding to the verbosity
+func getDisplayLevel(cmd *cobra.Command) (logging.Level, error) {
+	if cmd.Flags().Changed("log-level") {
+		displayLevel, err := logging.ToLevel(logLevel)
+		if err != nil {
+			return logging.Off, fmt.Errorf("invalid log level configured: %s", logLevel)
+		}
+		return displayLevel, nil
+	}
+	switch verbosity {
+	case constants.QuietVerbosity:
+		return logging.Fatal, nil
+	case constants.NormalVerbosity:
+		return logging.ToLevel(logLevel)
+	case constants.VerboseVerbosity:
+		return logging.Info, nil
+	case constants.DebugVerbosity:
+		return logging.Debug, nil
+	default:
+		return logging.Off, fmt.Errorf(
+			"invalid verbosity %q, expected one of %s",
+			verbosity,
+			strings.Join([]string{constants.QuietVerbosity, constants.NormalVerbosity, constants.VerboseVerbosity, constants.DebugVerbosity}, ", "),
+		)
 	}
-	// create the user facing logger as a global var
-	ux.NewUserLog(log, os.Stdout)
-	return log, nil
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	oldMetricsConfig := utils.UserHomePath(constants.OldMetricsConfigFileName)
-	if cfgFile == "" {
-		cfgFile = utils.UserHomePath(constants.DefaultConfigFileName)
-	}
-	app.Conf.SetConfig(app.Log, cfgFile)
+	app.Conf.SetConfig(app.Log, getConfigFile())
 	// check if metrics setting is available, and if not load metricConfig
 	if !app.Conf.ConfigValueIsSet(constants.ConfigMetricsEnabledKey) {
 		if utils.FileExists(oldMetricsConfig) {
 			app.Conf.MergeConfig(app.Log, oldMetricsConfig)
 		}
 	}
+	initAPIClientConfig()
+}
+
+func getConfigFile() string {
+	if cfgFile == "" {
+		cfgFile = utils.UserHomePath(constants.DefaultConfigFileName)
+	}
+	return cfgFile
+}
+
+// initAPIClientConfig applies the API call settings found in the config file
+func initAPIClientConfig() {
+	apiClientConfig := utils.DefaultAPIClientConfig
+	if app.Conf.ConfigValueIsSet(constants.ConfigAPIMaxAttemptsKey) {
+		apiClientConfig.MaxAttempts = app.Conf.GetConfigIntValue(constants.ConfigAPIMaxAttemptsKey)
+	}
+	if app.Conf.ConfigValueIsSet(constants.ConfigAPIRequestsPerSecondKey) {
+		apiClientConfig.RequestsPerSecond = app.Conf.GetConfigIntValue(constants.ConfigAPIRequestsPerSecondKey)
+	}
+	if app.Conf.ConfigValueIsSet(constants.ConfigAPIFailureThresholdKey) {
+		apiClientConfig.FailureThreshold = app.Conf.GetConfigIntValue(constants.ConfigAPIFailureThresholdKey)
+	}
+	utils.SetAPIClientConfig(apiClientConfig)
+}
+
+// initProxyConfig applies the proxies found in the config file. Environment
+// variables are used for the ones not set
+func initProxyConfig() error {
+	if err := utils.SetProxyConfig(app.Conf.GetProxyConfig()); err != nil {
+		return fmt.Errorf("invalid proxy config: %w", err)
+	}
+	// ssh reaches the nodes through the SSH proxy by running this same binary
+	if executable, err := os.Executable(); err == nil {
+		utils.SetSSHProxyCommand(
+			executable,
+			"node",
+			constants.SSHProxyCmd,
+			"--"+constants.ReadOnlyFlag,
+			"--config",
+			getConfigFile(),
+		)
+	}
+	return nil
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -376,6 +711,12 @@ func Execute() {
 	app = application.New()
 	rootCmd := NewRootCmd()
 	err := rootCmd.Execute()
+	err = endClusterStateSession(err)
+	if err != nil && app.Log != nil {
+		app.Log.Error("command failed", zap.Error(err))
+	}
+	handleAggregationError(err)
+	handleNetworkError(err)
 	cobrautils.HandleErrors(err)
 }
 
@@ -0,0 +1,109 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedulecmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/schedule"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	addName    string
+	addCron    string
+	addCommand string
+)
+
+// avalanche schedule add
+func newAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a command to be run on a cron schedule",
+		Long: `The schedule add command adds a CLI command, given without the binary name, to be run
+on the activations of a standard 5 fields cron expression (minute hour day-of-month month
+day-of-week), evaluated on the local time of the host. For example:
+
+  avalanche schedule add --cron "0 3 * * *" --command "node upgrade --cluster prod --security-only"
+
+Commands are run without a terminal, so they must be given all the inputs they need by flags.`,
+		RunE: addSchedule,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().StringVar(&addName, "name", "", "name of the schedule (default: job-N)")
+	cmd.Flags().StringVar(&addCron, "cron", "", "cron expression of the schedule (eg: \"0 3 * * *\", @daily)")
+	cmd.Flags().StringVar(&addCommand, "command", "", "CLI command to run, without the binary name")
+	return cmd
+}
+
+func addSchedule(cmd *cobra.Command, _ []string) error {
+	if addCron == "" {
+		return fmt.Errorf("--cron is required")
+	}
+	if addCommand == "" {
+		return fmt.Errorf("--command is required")
+	}
+	cron, err := schedule.ParseCron(addCron)
+	if err != nil {
+		return err
+	}
+	args, err := schedule.SplitCommandLine(addCommand)
+	if err != nil {
+		return err
+	}
+	if err := checkCommand(cmd.Root(), args); err != nil {
+		return err
+	}
+	if addName == "" {
+		addName, err = schedule.NextScheduleName(app.GetSchedulesDir())
+		if err != nil {
+			return err
+		}
+	}
+	if err := schedule.AddSchedule(app.GetSchedulesDir(), schedule.Schedule{
+		Name:      addName,
+		Cron:      addCron,
+		Command:   addCommand,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Schedule %s added. Next run at %s", addName, formatNextRun(cron.Next(time.Now())))
+	proc, err := schedule.GetSchedulerProcess(app.GetSchedulesDir())
+	if err != nil {
+		return err
+	}
+	if proc == nil {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("The scheduler is not running. Start it with: avalanche schedule start"))
+	}
+	return nil
+}
+
+// checkCommand verifies that [args] is a runnable CLI command, other than the schedule ones
+func checkCommand(root *cobra.Command, args []string) error {
+	found, _, err := root.Find(args)
+	if err != nil || found == root {
+		return fmt.Errorf("unknown command %q", strings.Join(args, " "))
+	}
+	if !found.Runnable() || found.HasSubCommands() {
+		return fmt.Errorf("%q is not a runnable command", found.CommandPath())
+	}
+	for c := found; c != nil; c = c.Parent() {
+		if c.Parent() == root && c.Name() == "schedule" {
+			return fmt.Errorf("schedule commands can not be scheduled")
+		}
+	}
+	return nil
+}
+
+func formatNextRun(next time.Time) string {
+	if next.IsZero() {
+		return "never"
+	}
+	return next.Format(time.RFC1123)
+}
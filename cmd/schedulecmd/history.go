@@ -0,0 +1,90 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedulecmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/schedule"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit      int
+	historyFailedOnly bool
+)
+
+// avalanche schedule history
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history [scheduleName]",
+		Short: "Show the run history of the schedules",
+		Long: `The schedule history command shows the latest runs of all the schedules, or of the given
+one, the latest first, with their result and the path of the file holding their output.`,
+		RunE: showHistory,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+	cmd.Flags().IntVar(&historyLimit, "limit", 20, "maximum number of runs to show")
+	cmd.Flags().BoolVar(&historyFailedOnly, "failed", false, "only show failed runs")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func showHistory(_ *cobra.Command, args []string) error {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	records, err := schedule.LoadRunHistory(app.GetSchedulesDir(), name)
+	if err != nil {
+		return err
+	}
+	if historyFailedOnly {
+		failed := []schedule.RunRecord{}
+		for _, record := range records {
+			if !record.Succeeded() {
+				failed = append(failed, record)
+			}
+		}
+		records = failed
+	}
+	if len(records) == 0 {
+		ux.Logger.PrintToUser("No runs found")
+		return nil
+	}
+	if historyLimit > 0 && len(records) > historyLimit {
+		records = records[:historyLimit]
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Schedule", "Started At", "Duration", "Result", "Exit Code", "Output"})
+	table.SetRowLine(true)
+	for _, record := range records {
+		table.Append([]string{
+			record.Schedule,
+			record.StartedAt.Format(time.RFC1123),
+			record.Duration.String(),
+			runResult(record),
+			strconv.Itoa(record.ExitCode),
+			record.LogPath,
+		})
+	}
+	table.Render()
+	return nil
+}
+
+func runResult(record schedule.RunRecord) string {
+	if record.Succeeded() {
+		return "succeeded"
+	}
+	return "failed: " + record.Error
+}
+
+// formatRunResult describes the result of [record] together with its start time
+func formatRunResult(record schedule.RunRecord) string {
+	return fmt.Sprintf("%s (%s)", runResult(record), record.StartedAt.Format(time.RFC1123))
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedulecmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/schedule"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// avalanche schedule list
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the schedules",
+		Long: `The schedule list command lists the schedules, with their next run and the result of
+their last run, and shows whether the scheduler is running.`,
+		RunE: listSchedules,
+		Args: cobrautils.ExactArgs(0),
+	}
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func listSchedules(_ *cobra.Command, _ []string) error {
+	if err := printSchedulerStatus(); err != nil {
+		return err
+	}
+	schedules, err := schedule.LoadSchedules(app.GetSchedulesDir())
+	if err != nil {
+		return err
+	}
+	if len(schedules) == 0 {
+		ux.Logger.PrintToUser("No schedules found")
+		return nil
+	}
+	history, err := schedule.LoadRunHistory(app.GetSchedulesDir(), "")
+	if err != nil {
+		return err
+	}
+	lastRuns := map[string]schedule.RunRecord{}
+	for _, record := range history {
+		if _, ok := lastRuns[record.Schedule]; !ok {
+			lastRuns[record.Schedule] = record
+		}
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Cron", "Command", "Next Run", "Last Run"})
+	table.SetRowLine(true)
+	for _, s := range schedules {
+		nextRun := "invalid cron"
+		if cron, err := schedule.ParseCron(s.Cron); err == nil {
+			nextRun = formatNextRun(cron.Next(time.Now()))
+		}
+		lastRun := "-"
+		if record, ok := lastRuns[s.Name]; ok {
+			lastRun = formatRunResult(record)
+		}
+		table.Append([]string{s.Name, s.Cron, s.Command, nextRun, lastRun})
+	}
+	table.Render()
+	return nil
+}
+
+func printSchedulerStatus() error {
+	proc, err := schedule.GetSchedulerProcess(app.GetSchedulesDir())
+	if err != nil {
+		return err
+	}
+	if proc == nil {
+		ux.Logger.PrintToUser("Scheduler: not running")
+	} else {
+		ux.Logger.PrintToUser("Scheduler: running, pid %d", proc.Pid)
+	}
+	return nil
+}
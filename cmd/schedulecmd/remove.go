@@ -0,0 +1,31 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedulecmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/schedule"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche schedule remove
+func newRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove [scheduleName]",
+		Short: "Remove a schedule",
+		Long: `The schedule remove command removes the given schedule, so it is not run anymore.
+Its run history is kept.`,
+		RunE: removeSchedule,
+		Args: cobrautils.ExactArgs(1),
+	}
+	return cmd
+}
+
+func removeSchedule(_ *cobra.Command, args []string) error {
+	if err := schedule.RemoveSchedule(app.GetSchedulesDir(), args[0]); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Schedule %s removed", args[0])
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedulecmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/spf13/cobra"
+)
+
+var app *application.Avalanche
+
+// avalanche schedule
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Run CLI commands on a recurring schedule",
+		Long: `The schedule command suite manages recurring maintenance operations, such as balance
+top-ups, node upgrades or health reports, executed by the CLI itself on a cron schedule.
+
+Schedules are executed by a lightweight scheduler process, started with schedule start, that
+runs each command as a separate CLI invocation and records its result and output on the
+run history.`,
+		RunE: cobrautils.CommandSuiteUsage,
+		Args: cobrautils.ExactArgs(0),
+	}
+	// schedule add
+	cmd.AddCommand(newAddCmd())
+	// schedule list
+	cmd.AddCommand(newListCmd())
+	// schedule remove
+	cmd.AddCommand(newRemoveCmd())
+	// schedule history
+	cmd.AddCommand(newHistoryCmd())
+	// schedule start
+	cmd.AddCommand(newStartCmd())
+	// schedule stop
+	cmd.AddCommand(newStopCmd())
+	return cmd
+}
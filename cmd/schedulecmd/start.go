@@ -0,0 +1,122 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedulecmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/schedule"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// time given to a background scheduler to fail on startup
+const schedulerStartupTime = 2 * time.Second
+
+var (
+	foreground bool
+	// set on the scheduler process started by schedule start
+	detached bool
+)
+
+// avalanche schedule start
+func newStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the scheduler process",
+		Long: `The schedule start command starts the scheduler process in background. The scheduler
+checks the schedules every minute, so schedules added or removed later are considered without
+restarting it, and records the result of each run on the run history.
+
+Use --foreground to run the scheduler in the current process instead, eg: to have it managed
+by the service manager of the host.`,
+		RunE: startScheduler,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().BoolVar(&foreground, "foreground", false, "run the scheduler in the current process")
+	cmd.Flags().BoolVar(&detached, "detached", false, "run as a background scheduler")
+	_ = cmd.Flags().MarkHidden("detached")
+	return cmd
+}
+
+func startScheduler(_ *cobra.Command, _ []string) error {
+	proc, err := schedule.GetSchedulerProcess(app.GetSchedulesDir())
+	if err != nil {
+		return err
+	}
+	if proc != nil {
+		return fmt.Errorf("the scheduler is already running with pid %d", proc.Pid)
+	}
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if foreground || detached {
+		return runScheduler(execPath)
+	}
+	if err := os.MkdirAll(app.GetSchedulesDir(), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	logPath := filepath.Join(app.GetSchedulesDir(), constants.SchedulerLogFileName)
+	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.WriteReadReadPerms)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	cmd := exec.Command(execPath, "schedule", "start", "--detached", "--"+constants.SkipUpdateFlag)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	select {
+	case err := <-exited:
+		return fmt.Errorf("scheduler failed on startup: %v. check its output at %s", err, logPath)
+	case <-time.After(schedulerStartupTime):
+	}
+	ux.Logger.GreenCheckmarkToUser("Scheduler started, pid: %d, output at: %s", cmd.Process.Pid, logPath)
+	return nil
+}
+
+// runScheduler executes the schedules with the CLI binary at [execPath] until the
+// process is interrupted
+func runScheduler(execPath string) error {
+	if detached {
+		// keep running after the terminal that started it is closed
+		signal.Ignore(syscall.SIGHUP)
+	}
+	if err := schedule.SaveSchedulerPID(app.GetSchedulesDir(), os.Getpid()); err != nil {
+		return err
+	}
+	defer func() {
+		_ = schedule.RemoveSchedulerPID(app.GetSchedulesDir())
+	}()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	printf := func(format string, args ...interface{}) {
+		ux.Logger.PrintToUser("[%s] %s", time.Now().Format(time.RFC3339), fmt.Sprintf(format, args...))
+	}
+	printf("scheduler started, pid %d", os.Getpid())
+	runner := schedule.Runner{
+		Dir:       app.GetSchedulesDir(),
+		ExecPath:  execPath,
+		ExtraArgs: []string{"--" + constants.SkipUpdateFlag},
+		Printf:    printf,
+	}
+	err := runner.Run(ctx)
+	printf("scheduler stopped")
+	return err
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedulecmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/schedule"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+const schedulerStopPollInterval = time.Second
+
+var stopTimeout time.Duration
+
+// avalanche schedule stop
+func newStopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the scheduler process",
+		Long: `The schedule stop command stops the scheduler process. The scheduler waits for the
+commands being run to finish before exiting.`,
+		RunE: stopScheduler,
+		Args: cobrautils.ExactArgs(0),
+	}
+	cmd.Flags().DurationVar(&stopTimeout, "timeout", 5*time.Minute, "time to wait for the running commands to finish")
+	return cmd
+}
+
+func stopScheduler(_ *cobra.Command, _ []string) error {
+	proc, err := schedule.GetSchedulerProcess(app.GetSchedulesDir())
+	if err != nil {
+		return err
+	}
+	if proc == nil {
+		ux.Logger.PrintToUser("The scheduler is not running")
+		return nil
+	}
+	if err := proc.Signal(os.Interrupt); err != nil {
+		return fmt.Errorf("failed sending interrupt signal to scheduler process with pid %d: %w", proc.Pid, err)
+	}
+	ux.Logger.PrintToUser("Stopping scheduler. Waiting for the running commands to finish...")
+	deadline := time.Now().Add(stopTimeout)
+	for time.Now().Before(deadline) {
+		proc, err := schedule.GetSchedulerProcess(app.GetSchedulesDir())
+		if err != nil {
+			return err
+		}
+		if proc == nil {
+			ux.Logger.GreenCheckmarkToUser("Scheduler stopped")
+			return nil
+		}
+		time.Sleep(schedulerStopPollInterval)
+	}
+	return fmt.Errorf("scheduler did not stop after %s", stopTimeout)
+}
@@ -23,5 +23,11 @@ func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 	cmd.AddCommand(newTransactionSignCmd())
 	// subnet upgrade generate
 	cmd.AddCommand(newTransactionCommitCmd())
+	// transaction share
+	cmd.AddCommand(newTransactionShareCmd())
+	// transaction export
+	cmd.AddCommand(newTransactionExportCmd())
+	// transaction import-signatures
+	cmd.AddCommand(newTransactionImportSignaturesCmd())
 	return cmd
 }
@@ -7,13 +7,17 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/custody"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
 	"github.com/ava-labs/avalanche-cli/pkg/keychain"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/txutils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/spf13/cobra"
 )
 
@@ -22,9 +26,11 @@ func newTransactionCommitCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "commit [blockchainName]",
 		Short: "commit a transaction",
-		Long:  "The transaction commit command commits a transaction by submitting it to the P-Chain.",
-		RunE:  commitTx,
-		Args:  cobrautils.MaximumNArgs(1),
+		Long: `The transaction commit command commits a transaction by submitting it to the P-Chain,
+or, for EVM transaction files signed with 'avalanche transaction import-signatures', to the
+EVM chain they are for.`,
+		RunE: commitTx,
+		Args: cobrautils.MaximumNArgs(1),
 	}
 
 	cmd.Flags().StringVar(&inputTxPath, inputTxPathFlag, "", "Path to the transaction signed by all signatories")
@@ -39,6 +45,11 @@ func commitTx(_ *cobra.Command, args []string) error {
 			return err
 		}
 	}
+	if isEVMTx, err := custody.IsEVMTxFile(inputTxPath); err != nil {
+		return err
+	} else if isEVMTx {
+		return commitEVMTx()
+	}
 	tx, err := txutils.LoadFromDisk(inputTxPath)
 	if err != nil {
 		return err
@@ -51,6 +62,10 @@ func commitTx(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	if txutils.IsAddPrimaryNetworkValidatorTx(tx) {
+		return commitPrimaryNetworkTx(tx, network)
+	}
+
 	subnetID, err := txutils.GetSubnetID(tx)
 	if err != nil {
 		return err
@@ -112,6 +127,13 @@ func commitTx(_ *cobra.Command, args []string) error {
 		blockchaincmd.PrintRemainingToSignMsg(blockchainName, remainingSubnetAuthKeys, inputTxPath)
 		return fmt.Errorf("tx is not fully signed")
 	}
+	if missingSignatures, totalSignatures, err := txutils.GetMissingSignatures(tx); err != nil {
+		return err
+	} else if missingSignatures != 0 {
+		ux.Logger.PrintToUser("%d of %d required signatures have been signed.", totalSignatures-missingSignatures, totalSignatures)
+		blockchaincmd.PrintCustodySignMsg(blockchainName, inputTxPath)
+		return fmt.Errorf("tx is not fully signed")
+	}
 
 	// get kc with some random address, to pass wallet creation checks
 	kc := secp256k1fx.NewKeychain()
@@ -141,3 +163,55 @@ func commitTx(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// commitPrimaryNetworkTx commits a Primary Network validator tx whose staked funds
+// are owned by a multisig
+func commitPrimaryNetworkTx(tx *txs.Tx, network models.Network) error {
+	missingSignatures, totalSignatures, err := txutils.GetMissingSignatures(tx)
+	if err != nil {
+		return err
+	}
+	if missingSignatures != 0 {
+		ux.Logger.PrintToUser("%d of %d required signatures have been signed.", totalSignatures-missingSignatures, totalSignatures)
+		return fmt.Errorf("tx is not fully signed")
+	}
+	// get kc with some random address, to pass wallet creation checks
+	kc := secp256k1fx.NewKeychain()
+	if _, err := kc.New(); err != nil {
+		return err
+	}
+	deployer := subnet.NewPublicDeployer(app, keychain.NewKeychain(network, kc, nil, nil), network)
+	txID, err := deployer.Commit(tx, true)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Transaction successful, transaction ID: %s", txID)
+	return nil
+}
+
+// commitEVMTx sends a signed EVM tx to the chain it is for
+func commitEVMTx() error {
+	evmTx, err := custody.LoadEVMTx(inputTxPath)
+	if err != nil {
+		return err
+	}
+	if !evmTx.IsSigned() {
+		return fmt.Errorf("tx is not signed")
+	}
+	client, err := evm.GetClient(evmTx.RPCURL)
+	if err != nil {
+		return err
+	}
+	if err := evm.SendTransaction(client, evmTx.Tx); err != nil {
+		return err
+	}
+	receipt, success, err := evm.WaitForTransaction(client, evmTx.Tx)
+	if err != nil {
+		return err
+	}
+	if !success {
+		return fmt.Errorf("tx %s failed: got status %d expected %d", evmTx.Tx.Hash(), receipt.Status, types.ReceiptStatusSuccessful)
+	}
+	ux.Logger.PrintToUser("Transaction successful, transaction ID: %s", evmTx.Tx.Hash())
+	return nil
+}
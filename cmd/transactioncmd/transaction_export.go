@@ -0,0 +1,141 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package transactioncmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/custody"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat           string
+	exportOutput           string
+	fireblocksVaultAccount string
+	fireblocksAssetID      string
+)
+
+// avalanche transaction export
+func newTransactionExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [blockchainName]",
+		Short: "export the signing payloads of a transaction for custodial signers",
+		Long: `The transaction export command exports the exact payloads a not fully signed transaction
+has to be signed with, so it can be signed by custodial signers that do not expose private keys.
+
+It accepts P-Chain transaction files, as created by CLI commands when the control keys are not
+available locally, or when the fee is paid from custodial addresses (--custody-addrs), and EVM
+transaction files, as created by 'avalanche contract encode --output-tx-path'. For P-Chain
+transactions, the remaining signers are the owners of the UTXOs paying the fee, and of the
+subnet or L1 validator the transaction is authorized by.
+
+Supported formats:
+  signdoc         JSON describing the transaction, with a human readable summary for review, the
+                  unsigned transaction bytes, and the digest each remaining signer has to sign
+  fireblocks-raw  body of a Fireblocks RAW signing transaction request, for the given vault
+                  account, signing the digests with MPC_ECDSA_SECP256K1
+  coinbase-prime  body of a Coinbase Prime onchain transaction request, signing the unsigned EVM
+                  transaction without broadcasting it. Only supported for EVM transactions
+
+Custodians that sign whole payloads instead of raw digests can be given the unsignedTx of the
+signdoc. Once signed, the returned signatures are added to the transaction with
+'avalanche transaction import-signatures'.`,
+		RunE: exportTx,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+
+	cmd.Flags().StringVar(&inputTxPath, inputTxPathFlag, "", "Path to the transaction file to export")
+	cmd.Flags().StringVar(&exportFormat, "format", custody.SignDocFormat, fmt.Sprintf("export format [%s]", strings.Join(custody.Formats, ", ")))
+	cmd.Flags().StringVarP(&exportOutput, "output", "o", "", "write the export to this file instead of stdout")
+	cmd.Flags().StringVar(&fireblocksVaultAccount, "fireblocks-vault-account", "0", "Fireblocks vault account ID that signs the transaction")
+	cmd.Flags().StringVar(&fireblocksAssetID, "fireblocks-asset-id", "", "Fireblocks asset ID the signing key belongs to")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func exportTx(_ *cobra.Command, args []string) error {
+	var err error
+	if inputTxPath == "" {
+		inputTxPath, err = app.Prompt.CaptureExistingFilepath("What is the path to the transactions file to export?")
+		if err != nil {
+			return err
+		}
+	}
+	isEVMTx, err := custody.IsEVMTxFile(inputTxPath)
+	if err != nil {
+		return err
+	}
+	var doc custody.SignDoc
+	if isEVMTx {
+		doc, err = getEVMSignDoc()
+	} else {
+		doc, err = getPChainSignDoc(args)
+	}
+	if err != nil {
+		return err
+	}
+	export, err := doc.Export(exportFormat, custody.FireblocksOptions{
+		VaultAccountID: fireblocksVaultAccount,
+		AssetID:        fireblocksAssetID,
+	})
+	if err != nil {
+		return err
+	}
+	bs, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+	if exportOutput == "" {
+		ux.Logger.PrintToUser("%s", bs)
+		return nil
+	}
+	if err := os.WriteFile(exportOutput, bs, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Signing payloads for %d signature(s) exported to %s", len(doc.Payloads), exportOutput)
+	return nil
+}
+
+func getEVMSignDoc() (custody.SignDoc, error) {
+	evmTx, err := custody.LoadEVMTx(inputTxPath)
+	if err != nil {
+		return custody.SignDoc{}, err
+	}
+	if evmTx.IsSigned() {
+		return custody.SignDoc{}, fmt.Errorf("tx is already signed")
+	}
+	return custody.NewEVMSignDoc(evmTx)
+}
+
+func getPChainSignDoc(args []string) (custody.SignDoc, error) {
+	tx, err := txutils.LoadFromDisk(inputTxPath)
+	if err != nil {
+		return custody.SignDoc{}, err
+	}
+	network, err := txutils.GetNetwork(tx)
+	if err != nil {
+		return custody.SignDoc{}, err
+	}
+	remainingSigners, err := custody.GetPChainRemainingSigners(network, tx)
+	if err != nil {
+		return custody.SignDoc{}, err
+	}
+	if len(remainingSigners) == 0 {
+		blockchainName := ""
+		if len(args) > 0 {
+			blockchainName = args[0]
+		}
+		blockchaincmd.PrintReadyToSignMsg(blockchainName, inputTxPath)
+		ux.Logger.PrintToUser("")
+		return custody.SignDoc{}, fmt.Errorf("tx is already fully signed")
+	}
+	return custody.NewPChainSignDoc(tx, network, remainingSigners)
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package transactioncmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
+	"github.com/ava-labs/avalanche-cli/cmd/primarycmd"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/custody"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/spf13/cobra"
+)
+
+var signaturesPath string
+
+// avalanche transaction import-signatures
+func newTransactionImportSignaturesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-signatures [blockchainName]",
+		Short: "add signatures made by custodial signers to a transaction",
+		Long: `The transaction import-signatures command adds to a transaction file the signatures made by
+custodial signers over the payloads exported with 'avalanche transaction export'.
+
+The signatures file can be the response of a Fireblocks RAW signing transaction, containing
+its signedMessages, the Coinbase Prime onchain transaction, containing its signed_transaction,
+or a JSON of the form
+  {"signatures": [{"digest": "0x...", "signature": "0x..."}]}
+with 65 bytes [r || s || v] hex encoded signatures. Signatures of other digests, or made by
+keys that are not expected to sign the transaction, are ignored.`,
+		RunE: importSignatures,
+		Args: cobrautils.MaximumNArgs(1),
+	}
+
+	cmd.Flags().StringVar(&inputTxPath, inputTxPathFlag, "", "Path to the transaction file the signatures are for")
+	cmd.Flags().StringVar(&signaturesPath, "signatures", "", "Path to the file with the signatures returned by the signer")
+	return cmd
+}
+
+func importSignatures(_ *cobra.Command, args []string) error {
+	var err error
+	if inputTxPath == "" {
+		inputTxPath, err = app.Prompt.CaptureExistingFilepath("What is the path to the transactions file the signatures are for?")
+		if err != nil {
+			return err
+		}
+	}
+	if signaturesPath == "" {
+		signaturesPath, err = app.Prompt.CaptureExistingFilepath("What is the path to the signatures file?")
+		if err != nil {
+			return err
+		}
+	}
+	bs, err := os.ReadFile(signaturesPath)
+	if err != nil {
+		return err
+	}
+	signatures, err := custody.ParseSignatures(bs)
+	if err != nil {
+		return err
+	}
+	isEVMTx, err := custody.IsEVMTxFile(inputTxPath)
+	if err != nil {
+		return err
+	}
+	if isEVMTx {
+		return importEVMSignature(signatures)
+	}
+	return importPChainSignatures(args, signatures)
+}
+
+func importEVMSignature(signatures []custody.Signature) error {
+	evmTx, err := custody.LoadEVMTx(inputTxPath)
+	if err != nil {
+		return err
+	}
+	if evmTx.IsSigned() {
+		return fmt.Errorf("tx is already signed")
+	}
+	if err := custody.ApplyEVMSignatures(&evmTx, signatures); err != nil {
+		return err
+	}
+	if err := custody.SaveEVMTx(evmTx, inputTxPath, true); err != nil {
+		return err
+	}
+	blockchaincmd.PrintReadyToSignMsg("", inputTxPath)
+	return nil
+}
+
+func importPChainSignatures(args []string, signatures []custody.Signature) error {
+	tx, err := txutils.LoadFromDisk(inputTxPath)
+	if err != nil {
+		return err
+	}
+	network, err := txutils.GetNetwork(tx)
+	if err != nil {
+		return err
+	}
+	var blockchainName string
+	if len(args) > 0 {
+		blockchainName = args[0]
+	}
+	remainingSigners, err := custody.GetPChainRemainingSigners(network, tx)
+	if err != nil {
+		return err
+	}
+	if len(remainingSigners) == 0 {
+		blockchaincmd.PrintReadyToSignMsg(blockchainName, inputTxPath)
+		ux.Logger.PrintToUser("")
+		return fmt.Errorf("tx is already fully signed")
+	}
+	applied, err := custody.ApplyPChainSignatures(network, tx, signatures)
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		ux.Logger.PrintToUser("None of the signatures is made by a remaining signer")
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Expected one of:")
+		for _, addr := range remainingSigners {
+			ux.Logger.PrintToUser("  %s", addr)
+		}
+		ux.Logger.PrintToUser("")
+		return fmt.Errorf("no signature of a remaining signer found")
+	}
+	for _, addr := range applied {
+		ux.Logger.GreenCheckmarkToUser("Added signature of %s", addr)
+	}
+	if txutils.IsAddPrimaryNetworkValidatorTx(tx) {
+		return primarycmd.SaveNotFullySignedTx(tx, inputTxPath, true)
+	}
+	controlKeys, subnetAuthKeys, err := getSubnetAuthKeys(tx, network, args)
+	if err != nil {
+		return err
+	}
+	_, remainingSubnetAuthKeys, err := txutils.GetRemainingSigners(tx, controlKeys)
+	if err != nil {
+		return err
+	}
+	return blockchaincmd.SaveNotFullySignedTx(
+		"Tx",
+		tx,
+		blockchainName,
+		subnetAuthKeys,
+		remainingSubnetAuthKeys,
+		inputTxPath,
+		true,
+	)
+}
+
+// getSubnetAuthKeys returns the control keys of the subnet [tx] is for, and the subnet auth keys
+// of [tx]
+func getSubnetAuthKeys(tx *txs.Tx, network models.Network, args []string) ([]string, []string, error) {
+	subnetID, err := txutils.GetSubnetID(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	// subnet ID from tx is always preferred
+	if subnetID == ids.Empty && len(args) > 0 {
+		sc, err := app.LoadSidecar(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		subnetID = sc.Networks[network.Name()].SubnetID
+	}
+	if subnetID == ids.Empty {
+		return nil, nil, errNoSubnetID
+	}
+	isPermissioned, controlKeys, _, err := txutils.GetOwners(network, subnetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isPermissioned {
+		return nil, nil, blockchaincmd.ErrNotPermissionedSubnet
+	}
+	subnetAuthKeys, err := txutils.GetAuthSigners(tx, controlKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return controlKeys, subnetAuthKeys, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package transactioncmd
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	copyTx bool
+	qrTx   bool
+)
+
+// avalanche transaction share
+func newTransactionShareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "share",
+		Short: "copy or show as QR code a transaction to be signed",
+		Long: `The transaction share command copies a not fully signed transaction to the clipboard,
+or renders it as a QR code, so it can be signed elsewhere, for example on a mobile wallet.
+
+The payload is the hex encoded transaction, as stored on transaction files. Large transactions
+may not fit into a QR code.`,
+		RunE: shareTx,
+		Args: cobrautils.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVar(&inputTxPath, inputTxPathFlag, "", "Path to the transaction file to share")
+	cmd.Flags().BoolVar(&copyTx, "copy", false, "copy the transaction to the clipboard")
+	cmd.Flags().BoolVar(&qrTx, "qr", false, "render the transaction as a QR code")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func shareTx(_ *cobra.Command, _ []string) error {
+	if !copyTx && !qrTx {
+		return errors.New("at least one of --copy or --qr must be given")
+	}
+	var err error
+	if inputTxPath == "" {
+		inputTxPath, err = app.Prompt.CaptureExistingFilepath("What is the path to the transactions file to share?")
+		if err != nil {
+			return err
+		}
+	}
+	tx, err := txutils.LoadFromDisk(inputTxPath)
+	if err != nil {
+		return err
+	}
+	txStr, err := txutils.Encode(tx)
+	if err != nil {
+		return err
+	}
+	return ux.ShareValue("Transaction "+tx.ID().String(), txStr, copyTx, qrTx)
+}
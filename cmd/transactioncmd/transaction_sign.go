@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
+	"github.com/ava-labs/avalanche-cli/cmd/primarycmd"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
 	"github.com/ava-labs/avalanche-cli/pkg/keychain"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
@@ -15,6 +16,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/txutils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/spf13/cobra"
 )
 
@@ -96,6 +98,10 @@ func signTx(_ *cobra.Command, args []string) error {
 		return errors.New("unsupported network")
 	}
 
+	if txutils.IsAddPrimaryNetworkValidatorTx(tx) {
+		return signPrimaryNetworkTx(tx, network)
+	}
+
 	// we need subnet ID for the wallet signing validation + process
 	subnetID, err := txutils.GetSubnetID(tx)
 	if err != nil {
@@ -187,3 +193,31 @@ func signTx(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// signPrimaryNetworkTx signs a Primary Network validator tx whose staked funds
+// are owned by a multisig
+func signPrimaryNetworkTx(tx *txs.Tx, network models.Network) error {
+	missingSignatures, _, err := txutils.GetMissingSignatures(tx)
+	if err != nil {
+		return err
+	}
+	if missingSignatures == 0 {
+		blockchaincmd.PrintReadyToSignMsg("", inputTxPath)
+		ux.Logger.PrintToUser("")
+		return fmt.Errorf("tx is already fully signed")
+	}
+	kc, err := keychain.GetKeychain(app, false, useLedger, ledgerAddresses, keyName, network, 0)
+	if err != nil {
+		return err
+	}
+	deployer := subnet.NewPublicDeployer(app, kc, network)
+	if err := deployer.SignInputs(tx); err != nil {
+		return err
+	}
+	if remainingSignatures, _, err := txutils.GetMissingSignatures(tx); err != nil {
+		return err
+	} else if remainingSignatures == missingSignatures {
+		return fmt.Errorf("no remaining signer address present in wallet")
+	}
+	return primarycmd.SaveNotFullySignedTx(tx, inputTxPath, true)
+}
@@ -0,0 +1,282 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatorcmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	delegatorKeyFlags           contract.PrivateKeyFlags
+	stakeAmount                 uint64
+	rpcURL                      string
+	aggregatorLogLevel          string
+	aggregatorExtraEndpoints    []string
+	aggregatorAllowPrivatePeers bool
+	aggregationFlags            interchain.AggregationFlags
+	allowUnapprovedKey          bool
+)
+
+var delegateSupportedNetworkOptions = []networkoptions.NetworkOption{
+	networkoptions.Local,
+	networkoptions.Devnet,
+	networkoptions.Fuji,
+	networkoptions.Mainnet,
+}
+
+func NewDelegateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delegate",
+		Short: "Delegate stake to a validator of a PoS L1",
+		Long: `This command delegates stake to a validator of a Proof of Stake L1.
+
+The stake is locked on the L1 PoS validator manager and paid by the delegator key
+(L1 gas token). The validator weight change is then registered on P-Chain, paid by
+the given P-Chain key or ledger.
+
+On L1s that stake an ERC20 token, the delegator key must hold the tokens to delegate.
+The validator manager is approved to transfer them first, unless the current
+allowance already covers the delegation.`,
+		RunE: delegate,
+		Args: cobrautils.ExactArgs(0),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, delegateSupportedNetworkOptions)
+	cmd.Flags().StringVar(&l1, "l1", "", "name of the PoS L1")
+	cmd.Flags().StringVar(&nodeIDStr, "node-id", "", "node ID of the validator to delegate to")
+	cmd.Flags().Uint64Var(&stakeAmount, "stake-amount", 0, "amount of tokens to delegate")
+	addDelegationFlags(cmd, "to stake the delegated tokens (L1 gas token)")
+	return cmd
+}
+
+// addDelegationFlags adds the key, rpc and signature aggregator flags
+// shared by the delegation commands
+func addDelegationFlags(cmd *cobra.Command, delegatorKeyGoal string) {
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use to pay P-Chain fees [fuji/devnet only]")
+	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key to pay P-Chain fees (always true on mainnet, defaults to false on fuji/devnet)")
+	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	delegatorKeyFlags.SetFlagNames("delegator-private-key", "delegator-key", "delegator-genesis-key")
+	delegatorKeyFlags.AddToCmd(cmd, delegatorKeyGoal)
+	cmd.Flags().StringVar(&rpcURL, "rpc", "", "connect to validator manager at the given rpc endpoint")
+	cmd.Flags().StringSliceVar(&aggregatorExtraEndpoints, "aggregator-extra-endpoints", nil, "endpoints for extra nodes that are needed in signature aggregation")
+	cmd.Flags().BoolVar(&aggregatorAllowPrivatePeers, "aggregator-allow-private-peers", true, "allow the signature aggregator to connect to peers with private IP")
+	cmd.Flags().StringVar(&aggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	interchain.AddAggregationFlagsToCmd(cmd, &aggregationFlags)
+	keychain.AddAllowUnapprovedKeyFlag(cmd, &allowUnapprovedKey)
+}
+
+func delegate(_ *cobra.Command, _ []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		delegateSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	sc, chainSpec, cancel, err := promptPoSL1(network)
+	if err != nil || cancel {
+		return err
+	}
+	if nodeIDStr == "" {
+		nodeID, err := blockchaincmd.PromptNodeID("delegate to")
+		if err != nil {
+			return err
+		}
+		nodeIDStr = nodeID.String()
+	}
+	nodeID, err := ids.NodeIDFromString(nodeIDStr)
+	if err != nil {
+		return err
+	}
+	if stakeAmount == 0 {
+		stakeAmount, err = app.Prompt.CaptureUint64Compare(
+			fmt.Sprintf("Enter the amount of %s to delegate", sc.StakedTokenName()),
+			[]prompts.Comparator{
+				{
+					Label: "Positive",
+					Type:  prompts.MoreThan,
+					Value: 0,
+				},
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	fee := network.GenesisParams().TxFeeConfig.StaticFeeConfig.TxFee
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		"to pay for the validator weight update on P-Chain",
+		network,
+		keyName,
+		useEwoq,
+		useLedger,
+		ledgerAddresses,
+		fee,
+	)
+	if err != nil {
+		return err
+	}
+	network.HandlePublicNetworkSimulation()
+	delegatorPrivateKey, err := getDelegatorPrivateKey(network, chainSpec, "stake the delegated tokens")
+	if err != nil {
+		return err
+	}
+	if rpcURL == "" {
+		rpcURL, _, err = contract.GetBlockchainEndpoints(app, network, chainSpec, true, false)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), rpcURL)
+	extraAggregatorPeers, err := blockchaincmd.GetAggregatorExtraPeers(sc.Networks[network.Name()].ClusterName, aggregatorExtraEndpoints)
+	if err != nil {
+		return err
+	}
+
+	aggregationConfig, err := interchain.GetAggregationConfig(app, aggregationFlags)
+	if err != nil {
+		return err
+	}
+	signedMessage, delegationID, delegatorAdded, err := validatormanager.InitDelegatorRegistration(
+		app,
+		network,
+		rpcURL,
+		chainSpec,
+		delegatorPrivateKey,
+		nodeID,
+		big.NewInt(int64(stakeAmount)),
+		extraAggregatorPeers,
+		aggregatorAllowPrivatePeers,
+		aggregatorLogLevel,
+		aggregationConfig,
+		sc.ERC20Staking(),
+	)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("DelegationID: %s", delegationID)
+	ux.Logger.PrintToUser("Delegator weight: %d, new validator weight: %d", delegatorAdded.DelegatorWeight, delegatorAdded.ValidatorWeight)
+
+	deployer := subnet.NewPublicDeployer(app, kc, network)
+	txID, _, err := deployer.SetL1ValidatorWeight(signedMessage)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("SetL1ValidatorWeightTx ID: %s", txID)
+	if err := blockchaincmd.UpdatePChainHeight(
+		"Waiting for P-Chain to update validator information ...",
+	); err != nil {
+		return err
+	}
+
+	if err := validatormanager.FinishDelegatorRegistration(
+		app,
+		network,
+		rpcURL,
+		chainSpec,
+		delegatorPrivateKey,
+		delegationID,
+		delegatorAdded.ValidationID,
+		delegatorAdded.Nonce,
+		delegatorAdded.ValidatorWeight,
+		extraAggregatorPeers,
+		aggregatorAllowPrivatePeers,
+		aggregatorLogLevel,
+		aggregationConfig,
+	); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Delegation %s to validator %s successfully registered", delegationID, nodeID)
+	return nil
+}
+
+// promptPoSL1 returns the sidecar and chain spec of the PoS L1 given by flag or prompted to the user.
+// It also returns true if the user cancels operations during prompting
+func promptPoSL1(network models.Network) (models.Sidecar, contract.ChainSpec, bool, error) {
+	chainSpec := contract.ChainSpec{
+		BlockchainName: l1,
+	}
+	if l1 == "" {
+		chainSpec.SetEnabled(
+			true,  // prompt blockchain name
+			false, // do not prompt for PChain
+			false, // do not prompt for XChain
+			false, // do not prompt for CChain
+			false, // do not prompt blockchain ID
+		)
+		chainSpec.OnlySOV = true
+		if cancel, err := contract.PromptChain(
+			app,
+			network,
+			"Choose the L1",
+			"",
+			&chainSpec,
+		); err != nil {
+			return models.Sidecar{}, chainSpec, false, err
+		} else if cancel {
+			return models.Sidecar{}, chainSpec, true, nil
+		}
+		l1 = chainSpec.BlockchainName
+	}
+	sc, err := app.LoadSidecar(l1)
+	if err != nil {
+		return models.Sidecar{}, chainSpec, false, fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if !sc.Sovereign || !sc.PoS() {
+		return models.Sidecar{}, chainSpec, false, fmt.Errorf("delegation is only applicable to Proof of Stake L1s")
+	}
+	return sc, chainSpec, false, nil
+}
+
+// getDelegatorPrivateKey returns the delegator private key given by flags, or prompts for it
+func getDelegatorPrivateKey(network models.Network, chainSpec contract.ChainSpec, goal string) (string, error) {
+	genesisAddress, genesisPrivateKey, err := contract.GetEVMSubnetPrefundedKey(app, network, chainSpec)
+	if err != nil {
+		return "", err
+	}
+	privateKey, err := delegatorKeyFlags.GetPrivateKey(app, genesisPrivateKey)
+	if err != nil {
+		return "", err
+	}
+	if privateKey == "" {
+		privateKey, err = prompts.PromptPrivateKey(
+			app.Prompt,
+			goal,
+			app.GetKeyDir(),
+			app.GetKey,
+			genesisAddress,
+			genesisPrivateKey,
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := keychain.CheckPrivateKeyOnNetwork(app, network, privateKey, allowUnapprovedKey); err != nil {
+		return "", err
+	}
+	return privateKey, nil
+}
@@ -0,0 +1,183 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatorcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/events"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchEvents   bool
+	eventsFrom    uint64
+	wsURL         string
+	forwardEvents bool
+)
+
+func NewEventsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events [blockchainName]",
+		Short: "Shows the validator set and delegation changes of an L1",
+		Long: `This command shows the events of the validator manager of an L1: validators added,
+removed or with changed weight, and delegations.
+
+By default, the events emitted from --from-block until now are listed. With --watch, the
+command subscribes to the validator manager over the L1 websocket RPC, and prints each event
+as it happens, until interrupted. Lost connections are re-established, without missing events.
+With --forward, watched events are also sent to the hooks set with avalanche config webhook,
+and to the message queue set with avalanche config event-sink.`,
+		RunE: validatorEvents,
+		Args: cobrautils.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, getBalanceSupportedNetworkOptions)
+	cmd.Flags().BoolVar(&watchEvents, "watch", false, "subscribe to new events, and print them as they happen")
+	cmd.Flags().Uint64Var(&eventsFrom, "from-block", 0, "show the events emitted since the given block (on --watch, defaults to only new events)")
+	cmd.Flags().StringVar(&rpcURL, "rpc", "", "connect to validator manager at the given rpc endpoint")
+	cmd.Flags().StringVar(&wsURL, "ws", "", "subscribe to validator manager events at the given websocket endpoint")
+	cmd.Flags().BoolVar(&forwardEvents, "forward", false, "send watched events to the configured webhook, script hook and event sink")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func validatorEvents(_ *cobra.Command, args []string) error {
+	blockchainName := args[0]
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if !sc.Sovereign {
+		return fmt.Errorf("avalanche validator commands are only applicable to sovereign L1s")
+	}
+	if forwardEvents {
+		if !watchEvents {
+			return errors.New("--forward can only be used together with --watch")
+		}
+		if !events.Configured(app) {
+			return errors.New("no webhook URL, script hook nor event sink configured. Set them with avalanche config webhook or avalanche config event-sink")
+		}
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		getBalanceSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	chainSpec := contract.ChainSpec{
+		BlockchainName: blockchainName,
+	}
+	if (!watchEvents && rpcURL == "") || (watchEvents && wsURL == "") {
+		endpoint, wsEndpoint, err := contract.GetBlockchainEndpoints(app, network, chainSpec, !watchEvents, watchEvents)
+		if err != nil {
+			return err
+		}
+		if rpcURL == "" {
+			rpcURL = endpoint
+		}
+		if wsURL == "" {
+			wsURL = wsEndpoint
+		}
+	}
+	managerAddress := getManagerAddress(network, blockchainName)
+	nodeIDs := map[ids.ID]ids.NodeID{}
+	resolveNodeID := func(event *validatormanager.ValidatorManagerEvent) {
+		if nodeID, ok := nodeIDs[event.ValidationID]; ok {
+			event.NodeID = nodeID
+			return
+		}
+		// not available on P-Chain for registrations not yet completed, or ended validations
+		nodeID, err := txutils.GetValidatorNodeIDValidationID(network, event.ValidationID)
+		if err == nil {
+			nodeIDs[event.ValidationID] = nodeID
+			event.NodeID = nodeID
+		}
+	}
+
+	if !watchEvents {
+		client, err := evm.GetClient(rpcURL)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		validatorManagerEvents, err := validatormanager.GetValidatorManagerEvents(client, managerAddress, eventsFrom, nil)
+		if err != nil {
+			return err
+		}
+		if len(validatorManagerEvents) == 0 {
+			ux.Logger.PrintToUser("No validator manager events found for %s on %s", blockchainName, network.Name())
+			return nil
+		}
+		for _, event := range validatorManagerEvents {
+			resolveNodeID(&event)
+			printValidatorManagerEvent(event)
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ux.Logger.PrintToUser("Watching validator manager events of %s on %s. Press Ctrl+C to stop", blockchainName, network.Name())
+	return validatormanager.WatchValidatorManagerEvents(
+		ctx,
+		wsURL,
+		managerAddress,
+		eventsFrom,
+		func(event validatormanager.ValidatorManagerEvent) {
+			resolveNodeID(&event)
+			printValidatorManagerEvent(event)
+			if forwardEvents {
+				events.Emit(
+					app,
+					events.ValidatorSetChanged,
+					fmt.Sprintf("%s on %s: %s", blockchainName, network.Name(), event.Text()),
+					map[string]interface{}{
+						"blockchain":   blockchainName,
+						"network":      network.Name(),
+						"event":        event.Name,
+						"validationID": event.ValidationID.String(),
+						"validator":    event.Validator(),
+						"weight":       event.Weight,
+						"blockNumber":  event.BlockNumber,
+						"txHash":       event.TxHash.Hex(),
+					},
+				)
+			}
+		},
+		func(err error) {
+			ux.Logger.RedXToUser("connection to %s lost: %s. Reconnecting", wsURL, err)
+		},
+	)
+}
+
+func printValidatorManagerEvent(event validatormanager.ValidatorManagerEvent) {
+	ux.Logger.PrintToUser(
+		"%s %s %s",
+		logging.LightBlue.Wrap(fmt.Sprintf("[block %d]", event.BlockNumber)),
+		event.Text(),
+		logging.DarkGray.Wrap("tx "+event.TxHash.Hex()),
+	)
+}
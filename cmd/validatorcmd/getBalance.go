@@ -14,12 +14,10 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
-	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 	"github.com/ava-labs/avalanchego/vms/platformvm/api"
-	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
@@ -199,7 +197,7 @@ func getNodeValidationID(
 		if err != nil {
 			return ids.Empty, false, err
 		}
-		managerAddress := common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+		managerAddress := getManagerAddress(network, chainSpec.BlockchainName)
 		validationID, err = validatormanager.GetRegisteredValidator(rpcURL, managerAddress, nodeID)
 		if err != nil {
 			return ids.Empty, false, err
@@ -13,14 +13,12 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
-	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 	"github.com/ava-labs/avalanchego/vms/platformvm/api"
 	"golang.org/x/exp/maps"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 )
@@ -35,7 +33,7 @@ func NewListCmd() *cobra.Command {
 	}
 
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, getBalanceSupportedNetworkOptions)
-	return cmd
+	return cobrautils.MarkReadOnly(cmd)
 }
 
 func list(_ *cobra.Command, args []string) error {
@@ -89,7 +87,7 @@ func list(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	managerAddress := common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+	managerAddress := getManagerAddress(network, blockchainName)
 
 	t := ux.DefaultTable(
 		fmt.Sprintf("%s Validators", blockchainName),
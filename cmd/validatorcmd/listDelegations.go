@@ -0,0 +1,102 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatorcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var delegatorAddressStr string
+
+func NewListDelegationsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "listDelegations",
+		Aliases: []string{"list-delegations"},
+		Short:   "Lists the delegations made by a delegator on a PoS L1",
+		Long: `This command lists the delegations made by the given delegator address on a
+Proof of Stake L1, together with their status and, for completed ones, the rewards received.`,
+		RunE: listDelegations,
+		Args: cobrautils.ExactArgs(0),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, delegateSupportedNetworkOptions)
+	cmd.Flags().StringVar(&l1, "l1", "", "name of the PoS L1")
+	cmd.Flags().StringVar(&delegatorAddressStr, "delegator", "", "EVM address of the delegator")
+	delegatorKeyFlags.SetFlagNames("delegator-private-key", "delegator-key", "delegator-genesis-key")
+	delegatorKeyFlags.AddToCmd(cmd, "as delegator")
+	cmd.Flags().StringVar(&rpcURL, "rpc", "", "connect to validator manager at the given rpc endpoint")
+	return cobrautils.MarkReadOnly(cmd)
+}
+
+func listDelegations(_ *cobra.Command, _ []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		delegateSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	sc, chainSpec, cancel, err := promptPoSL1(network)
+	if err != nil || cancel {
+		return err
+	}
+	var delegatorAddress common.Address
+	if delegatorAddressStr != "" {
+		if !common.IsHexAddress(delegatorAddressStr) {
+			return fmt.Errorf("invalid delegator address %s", delegatorAddressStr)
+		}
+		delegatorAddress = common.HexToAddress(delegatorAddressStr)
+	} else {
+		delegatorPrivateKey, err := getDelegatorPrivateKey(network, chainSpec, "as delegator")
+		if err != nil {
+			return err
+		}
+		delegatorAddress, err = utils.PrivateKeyToAddress(delegatorPrivateKey)
+		if err != nil {
+			return err
+		}
+	}
+	if rpcURL == "" {
+		rpcURL, _, err = contract.GetBlockchainEndpoints(app, network, chainSpec, true, false)
+		if err != nil {
+			return err
+		}
+	}
+	delegations, err := validatormanager.GetDelegations(rpcURL, delegatorAddress)
+	if err != nil {
+		return err
+	}
+	if len(delegations) == 0 {
+		ux.Logger.PrintToUser("No delegations found for %s on %s", delegatorAddress.Hex(), l1)
+		return nil
+	}
+	header := table.Row{"Delegation ID", "Validation ID", "Weight", "Status", "Rewards"}
+	t := ux.DefaultTable(fmt.Sprintf("Delegations of %s on %s", delegatorAddress.Hex(), l1), header)
+	for _, delegation := range delegations {
+		rewards := ""
+		if delegation.Rewards != nil {
+			rewards = fmt.Sprintf("%s %s", delegation.Rewards, sc.TokenName)
+		}
+		t.AppendRow(table.Row{delegation.DelegationID, delegation.ValidationID, delegation.Weight, delegation.Status, rewards})
+	}
+	ux.Logger.PrintToUser(t.Render())
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatorcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/spf13/cobra"
+)
+
+var (
+	refundAllExited bool
+	refundTo        string
+)
+
+// avalanche validator refund
+func NewRefundCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "refund",
+		Short: "Move the refunded balance of removed validators to a P-Chain address",
+		Long: `This command moves the remaining balance refunded by the P-Chain to removed L1 validators
+into the given P-Chain address.
+
+When an L1 validator is removed, the P-Chain refunds its remaining balance to the validator
+remaining balance owner. The refunds of validators removed with avalanche blockchain removeValidator
+are recorded, and can be moved with --node-id, or all at once with --all-exited. Each refund is
+signed with the stored key owning it, and the tx fee is paid out of the refund.`,
+		RunE: refund,
+		Args: cobrautils.ExactArgs(0),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, getBalanceSupportedNetworkOptions)
+	cmd.Flags().StringVar(&l1, "l1", "", "name of L1")
+	cmd.Flags().StringVar(&nodeIDStr, "node-id", "", "node ID of the removed validator")
+	cmd.Flags().BoolVar(&refundAllExited, "all-exited", false, "move the refunds of all the removed validators not moved yet")
+	cmd.Flags().StringVar(&refundTo, "to", "", "P-Chain address to move the refunds to")
+	return cmd
+}
+
+func refund(_ *cobra.Command, _ []string) error {
+	if l1 == "" {
+		return fmt.Errorf("--l1 is required")
+	}
+	if (nodeIDStr == "") == !refundAllExited {
+		return fmt.Errorf("use one of --node-id or --all-exited")
+	}
+	sc, err := app.LoadSidecar(l1)
+	if err != nil {
+		return err
+	}
+	if !sc.Sovereign {
+		return fmt.Errorf("avalanche validator commands are only applicable to sovereign L1s")
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		networkoptions.GetNetworkFromSidecar(sc, getBalanceSupportedNetworkOptions),
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if refundTo == "" {
+		refundTo, err = app.Prompt.CapturePChainAddress("What P-Chain address do you want to move the refunds to?", network)
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := address.ParseToID(refundTo); err != nil {
+		return fmt.Errorf("invalid P-Chain address %s: %w", refundTo, err)
+	}
+	networkData := sc.Networks[network.Name()]
+	pending := []int{}
+	for i, removed := range networkData.RemovedValidators {
+		if nodeIDStr != "" && removed.NodeID != nodeIDStr {
+			continue
+		}
+		if removed.ClaimTxID == "" && removed.Refund > 0 {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		if nodeIDStr != "" {
+			return fmt.Errorf("no refund to move recorded for validator %s of %s on %s", nodeIDStr, l1, network.Name())
+		}
+		ux.Logger.PrintToUser("No refunds to move from removed validators of %s on %s", l1, network.Name())
+		return nil
+	}
+	claimed, failed := 0, 0
+	for _, i := range pending {
+		removed := &networkData.RemovedValidators[i]
+		ux.Logger.PrintToUser("Validator %s: refund of %.9f AVAX at %s", removed.NodeID, float64(removed.Refund)/float64(units.Avax), removed.RemovalTxID)
+		if err := blockchaincmd.ClaimValidatorRefund(network, removed, refundTo); err != nil {
+			ux.Logger.RedXToUser("failure moving the refund of validator %s: %s", removed.NodeID, err)
+			failed++
+			continue
+		}
+		claimed++
+		sc.Networks[network.Name()] = networkData
+		if err := app.UpdateSidecar(&sc); err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("%d refunds moved to %s, %d failed", claimed, refundTo, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d refunds could not be moved", failed)
+	}
+	return nil
+}
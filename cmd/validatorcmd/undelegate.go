@@ -0,0 +1,242 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatorcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	delegationIDStr string
+	uptimeSec       uint64
+	force           bool
+)
+
+func NewUndelegateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undelegate",
+		Short: "Remove a delegation from a validator of a PoS L1, claiming its rewards",
+		Long: `This command ends a delegation made to a validator of a Proof of Stake L1.
+
+The validator weight change is registered on P-Chain, paid by the given P-Chain key or ledger.
+Once completed, the delegated stake and the delegation rewards are sent to the delegator.`,
+		RunE: undelegate,
+		Args: cobrautils.ExactArgs(0),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, delegateSupportedNetworkOptions)
+	cmd.Flags().StringVar(&l1, "l1", "", "name of the PoS L1")
+	cmd.Flags().StringVar(&delegationIDStr, "delegation-id", "", "ID of the delegation to remove")
+	cmd.Flags().Uint64Var(&uptimeSec, "uptime", 0, "validator's uptime in seconds. If not provided, it will be automatically calculated")
+	cmd.Flags().BoolVar(&force, "force", false, "force delegation removal even if it's not getting rewarded")
+	addDelegationFlags(cmd, "that owns the delegation (L1 gas token)")
+	return cmd
+}
+
+func undelegate(_ *cobra.Command, _ []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		delegateSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	sc, chainSpec, cancel, err := promptPoSL1(network)
+	if err != nil || cancel {
+		return err
+	}
+	fee := network.GenesisParams().TxFeeConfig.StaticFeeConfig.TxFee
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		"to pay for the validator weight update on P-Chain",
+		network,
+		keyName,
+		useEwoq,
+		useLedger,
+		ledgerAddresses,
+		fee,
+	)
+	if err != nil {
+		return err
+	}
+	network.HandlePublicNetworkSimulation()
+	delegatorPrivateKey, err := getDelegatorPrivateKey(network, chainSpec, "remove the delegation")
+	if err != nil {
+		return err
+	}
+	delegatorAddress, err := utils.PrivateKeyToAddress(delegatorPrivateKey)
+	if err != nil {
+		return err
+	}
+	if rpcURL == "" {
+		rpcURL, _, err = contract.GetBlockchainEndpoints(app, network, chainSpec, true, false)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("RPC Endpoint: %s"), rpcURL)
+
+	delegation, err := getDelegationToRemove(delegatorAddress)
+	if err != nil {
+		return err
+	}
+	nodeID, err := txutils.GetValidatorNodeIDValidationID(network, delegation.ValidationID)
+	if err != nil {
+		return err
+	}
+	extraAggregatorPeers, err := blockchaincmd.GetAggregatorExtraPeers(sc.Networks[network.Name()].ClusterName, aggregatorExtraEndpoints)
+	if err != nil {
+		return err
+	}
+	aggregationConfig, err := interchain.GetAggregationConfig(app, aggregationFlags)
+	if err != nil {
+		return err
+	}
+
+	var weightUpdate *validatormanager.ValidatorWeightUpdate
+	if delegation.Status != validatormanager.DelegatorStatusPendingRemoved {
+		signedMessage, update, err := validatormanager.InitEndDelegation(
+			app,
+			network,
+			rpcURL,
+			chainSpec,
+			delegatorPrivateKey,
+			nodeID,
+			delegation.ValidationID,
+			delegation.DelegationID,
+			extraAggregatorPeers,
+			aggregatorAllowPrivatePeers,
+			aggregatorLogLevel,
+			aggregationConfig,
+			uptimeSec,
+			force,
+		)
+		if err != nil && errors.Is(err, validatorManagerSDK.ErrDelegatorIneligibleForRewards) {
+			ux.Logger.PrintToUser("Calculated rewards is zero. Delegation %s is not eligible for rewards", delegation.DelegationID)
+			force, err = app.Prompt.CaptureNoYes("Do you want to continue with delegation removal?")
+			if err != nil {
+				return err
+			}
+			if !force {
+				return fmt.Errorf("delegation %s is not eligible for rewards. Use --force flag to force removal", delegation.DelegationID)
+			}
+			signedMessage, update, err = validatormanager.InitEndDelegation(
+				app,
+				network,
+				rpcURL,
+				chainSpec,
+				delegatorPrivateKey,
+				nodeID,
+				delegation.ValidationID,
+				delegation.DelegationID,
+				extraAggregatorPeers,
+				aggregatorAllowPrivatePeers,
+				aggregatorLogLevel,
+				aggregationConfig,
+				uptimeSec,
+				true, // force
+			)
+			if err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+		weightUpdate = update
+		if signedMessage != nil {
+			deployer := subnet.NewPublicDeployer(app, kc, network)
+			txID, _, err := deployer.SetL1ValidatorWeight(signedMessage)
+			if err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("SetL1ValidatorWeightTx ID: %s", txID)
+			if err := blockchaincmd.UpdatePChainHeight(
+				"Waiting for P-Chain to update validator information ...",
+			); err != nil {
+				return err
+			}
+		}
+	} else {
+		ux.Logger.PrintToUser("the delegation removal process was already initialized. Proceeding to the next step")
+	}
+
+	delegationEnded, err := validatormanager.FinishEndDelegation(
+		app,
+		network,
+		rpcURL,
+		chainSpec,
+		delegatorPrivateKey,
+		delegation.DelegationID,
+		weightUpdate,
+		extraAggregatorPeers,
+		aggregatorAllowPrivatePeers,
+		aggregatorLogLevel,
+		aggregationConfig,
+	)
+	if err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Delegation %s successfully removed", delegation.DelegationID)
+	ux.Logger.PrintToUser("Rewards: %s %s", delegationEnded.Rewards, sc.TokenName)
+	return nil
+}
+
+// getDelegationToRemove returns the delegation given by flag, or prompts the
+// user to choose one among the active delegations of [delegatorAddress]
+func getDelegationToRemove(delegatorAddress common.Address) (validatormanager.Delegation, error) {
+	if delegationIDStr != "" {
+		delegationID, err := ids.FromString(delegationIDStr)
+		if err != nil {
+			return validatormanager.Delegation{}, err
+		}
+		return validatormanager.GetDelegation(rpcURL, delegatorAddress, delegationID)
+	}
+	delegations, err := validatormanager.GetDelegations(rpcURL, delegatorAddress)
+	if err != nil {
+		return validatormanager.Delegation{}, err
+	}
+	delegations = utils.Filter(delegations, func(d validatormanager.Delegation) bool {
+		return d.Status == validatormanager.DelegatorStatusActive || d.Status == validatormanager.DelegatorStatusPendingRemoved
+	})
+	if len(delegations) == 0 {
+		return validatormanager.Delegation{}, fmt.Errorf("no active delegations found for %s", delegatorAddress.Hex())
+	}
+	options := utils.Map(delegations, func(d validatormanager.Delegation) string {
+		return fmt.Sprintf("%s (weight %d, %s)", d.DelegationID, d.Weight, d.Status)
+	})
+	option, err := app.Prompt.CaptureList("Choose the delegation to remove", options)
+	if err != nil {
+		return validatormanager.Delegation{}, err
+	}
+	index, err := utils.GetIndexInSlice(options, option)
+	if err != nil {
+		return validatormanager.Delegation{}, err
+	}
+	return delegations[index], nil
+}
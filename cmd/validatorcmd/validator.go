@@ -5,6 +5,10 @@ package validatorcmd
 import (
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/validatormanager"
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 )
 
@@ -14,9 +18,9 @@ var app *application.Avalanche
 func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "validator",
-		Short: "Manage P-Chain validator balance",
+		Short: "Manage P-Chain validator balance and PoS delegations",
 		Long: `The validator command suite provides a collection of tools for managing validator
-balance on P-Chain.
+balance on P-Chain, and delegations to validators of Proof of Stake L1s.
 
 Validator's balance is used to pay for continuous fee to the P-Chain. When this Balance reaches 0, 
 the validator will be considered inactive and will no longer participate in validating the L1`,
@@ -29,5 +33,27 @@ the validator will be considered inactive and will no longer participate in vali
 	cmd.AddCommand(NewGetBalanceCmd())
 	// validator increaseBalance
 	cmd.AddCommand(NewIncreaseBalanceCmd())
+	// validator delegate
+	cmd.AddCommand(NewDelegateCmd())
+	// validator undelegate
+	cmd.AddCommand(NewUndelegateCmd())
+	// validator listDelegations
+	cmd.AddCommand(NewListDelegationsCmd())
+	// validator watch
+	cmd.AddCommand(NewWatchCmd())
+	// validator events
+	cmd.AddCommand(NewEventsCmd())
+	// validator refund
+	cmd.AddCommand(NewRefundCmd())
 	return cmd
 }
+
+// getManagerAddress returns the address of the validator manager of [blockchainName] on
+// [network], defaulting to the genesis proxy if the blockchain is not known
+func getManagerAddress(network models.Network, blockchainName string) common.Address {
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+	}
+	return validatormanager.GetValidatorManagerAddress(sc.Networks[network.Name()])
+}
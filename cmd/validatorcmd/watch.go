@@ -0,0 +1,250 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatorcmd
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/cmd/blockchaincmd"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/interchain"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	sdkinterchain "github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchWarnBefore time.Duration
+	watchInterval   time.Duration
+	watchOnce       bool
+	watchReissue    bool
+)
+
+type registrationStatus int
+
+const (
+	registrationPending registrationStatus = iota
+	// accepted by P-Chain, but not completed on the validator manager
+	registrationRegistered
+	// expires within the warning period
+	registrationExpiring
+	// expired without being accepted by P-Chain
+	registrationExpired
+)
+
+var watchSupportedNetworkOptions = []networkoptions.NetworkOption{
+	networkoptions.Local,
+	networkoptions.Devnet,
+	networkoptions.Fuji,
+	networkoptions.Mainnet,
+}
+
+func NewWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Watch pending L1 validator registrations for expiry",
+		Long: `This command watches the L1 validator registrations initialized by the CLI
+that were not yet completed, for example because addValidator was interrupted.
+
+A registration message is only accepted by P-Chain before its expiry. The command warns
+about registrations expiring within --warn-before, and reports the ones that already expired.
+With --reissue, registrations about to expire are issued again to P-Chain, paid by the given
+P-Chain key or ledger, and completed on the validator manager using the stored validator
+manager owner key. Expired registrations are invalidated on the validator manager with
+the P-Chain attestation that the validator was never registered, so the validator
+manager does not keep them as pending.
+
+Failures to reach P-Chain or the validator manager are reported, and checked again
+on the next interval.`,
+		RunE: watch,
+		Args: cobrautils.ExactArgs(0),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, watchSupportedNetworkOptions)
+	cmd.Flags().StringVar(&l1, "l1", "", "only watch registrations to the given L1")
+	cmd.Flags().DurationVar(&watchWarnBefore, "warn-before", 6*time.Hour, "warn about registrations expiring within the given duration")
+	cmd.Flags().DurationVar(&watchInterval, "interval", time.Minute, "time between checks")
+	cmd.Flags().BoolVar(&watchOnce, "once", false, "check the registrations once and exit")
+	cmd.Flags().BoolVar(&watchReissue, "reissue", false, "re-issue registrations about to expire to P-Chain and complete them, and invalidate expired ones")
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use to pay P-Chain fees on re-issue [fuji/devnet only]")
+	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key to pay P-Chain fees on re-issue (always true on mainnet, defaults to false on fuji/devnet)")
+	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().StringVar(&rpcURL, "rpc", "", "connect to validator manager at the given rpc endpoint")
+	cmd.Flags().StringSliceVar(&aggregatorExtraEndpoints, "aggregator-extra-endpoints", nil, "endpoints for extra nodes that are needed in signature aggregation")
+	cmd.Flags().BoolVar(&aggregatorAllowPrivatePeers, "aggregator-allow-private-peers", true, "allow the signature aggregator to connect to peers with private IP")
+	cmd.Flags().StringVar(&aggregatorLogLevel, "aggregator-log-level", "Off", "log level to use with signature aggregator")
+	interchain.AddAggregationFlagsToCmd(cmd, &aggregationFlags)
+	return cmd
+}
+
+func watch(_ *cobra.Command, _ []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		"",
+		globalNetworkFlags,
+		true,
+		false,
+		watchSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if network.ClusterName != "" {
+		network = models.ConvertClusterToNetwork(network)
+	}
+	var deployer *subnet.PublicDeployer
+	if watchReissue {
+		fee := network.GenesisParams().TxFeeConfig.StaticFeeConfig.TxFee
+		kc, err := keychain.GetKeychainFromCmdLineFlags(
+			app,
+			"to pay for the re-issued validator registrations on P-Chain",
+			network,
+			keyName,
+			useEwoq,
+			useLedger,
+			ledgerAddresses,
+			fee,
+		)
+		if err != nil {
+			return err
+		}
+		deployer = subnet.NewPublicDeployer(app, kc, network)
+	}
+	aggregationConfig, err := interchain.GetAggregationConfig(app, aggregationFlags)
+	if err != nil {
+		return err
+	}
+	for {
+		pending, err := checkValidatorRegistrations(network, deployer, aggregationConfig)
+		switch {
+		case err != nil && watchOnce:
+			return err
+		case err != nil:
+			ux.Logger.RedXToUser("failure checking validator registrations, retrying in %s: %s", watchInterval, err)
+		case pending == 0:
+			ux.Logger.PrintToUser("No pending validator registrations on %s", network.Name())
+			return nil
+		}
+		if watchOnce {
+			return nil
+		}
+		time.Sleep(watchInterval)
+	}
+}
+
+// checkValidatorRegistrations checks the tracked registrations of [network], warning about
+// the ones close to expiry, and re-issuing them if [deployer] is given, aggregating
+// signatures as set by [aggregationConfig].
+// Returns the number of registrations still pending
+func checkValidatorRegistrations(
+	network models.Network,
+	deployer *subnet.PublicDeployer,
+	aggregationConfig sdkinterchain.AggregationConfig,
+) (int, error) {
+	registrations, err := app.LoadValidatorRegistrations()
+	if err != nil {
+		return 0, err
+	}
+	toCheck := []models.ValidatorRegistration{}
+	for _, registration := range registrations.Registrations {
+		if registration.Network != network.Name() || (l1 != "" && registration.BlockchainName != l1) {
+			continue
+		}
+		toCheck = append(toCheck, registration)
+	}
+	sort.Slice(toCheck, func(i, j int) bool {
+		return toCheck[i].Expiry < toCheck[j].Expiry
+	})
+	pending := 0
+	for _, registration := range toCheck {
+		validationID, err := ids.FromString(registration.ValidationID)
+		if err != nil {
+			return 0, err
+		}
+		registered, err := blockchaincmd.IsRegisteredOnPChain(network, validationID)
+		if err != nil {
+			ux.Logger.RedXToUser("failure checking registration of validator %s to %s on P-Chain: %s", registration.NodeID, registration.BlockchainName, err)
+			pending++
+			continue
+		}
+		remaining := time.Until(registration.ExpiryTime()).Round(time.Second)
+		switch getRegistrationStatus(registered, remaining, watchWarnBefore) {
+		case registrationRegistered:
+			ux.Logger.PrintToUser("Validator %s of %s is registered on P-Chain, but not yet on the validator manager", registration.NodeID, registration.BlockchainName)
+		case registrationExpired:
+			ux.Logger.PrintToUser(
+				logging.Yellow.Wrap("Registration of validator %s to %s expired at %s without being registered on P-Chain"),
+				registration.NodeID,
+				registration.BlockchainName,
+				registration.ExpiryTime().Format(time.RFC1123),
+			)
+			if deployer == nil {
+				ux.Logger.PrintToUser("Use --reissue to invalidate it on the validator manager")
+				pending++
+				continue
+			}
+			if err := blockchaincmd.ExpireValidatorRegistration(
+				network,
+				registration,
+				validationID,
+				rpcURL,
+				aggregatorExtraEndpoints,
+				aggregatorAllowPrivatePeers,
+				aggregatorLogLevel,
+				aggregationConfig,
+			); err != nil {
+				ux.Logger.RedXToUser("failure invalidating expired registration of validator %s to %s: %s", registration.NodeID, registration.BlockchainName, err)
+				pending++
+			}
+			continue
+		case registrationExpiring:
+			ux.Logger.PrintToUser(logging.Yellow.Wrap("Registration of validator %s to %s expires in %s"), registration.NodeID, registration.BlockchainName, remaining)
+		default:
+			ux.Logger.PrintToUser("Registration of validator %s to %s expires in %s", registration.NodeID, registration.BlockchainName, remaining)
+			pending++
+			continue
+		}
+		if deployer == nil {
+			pending++
+			continue
+		}
+		if err := blockchaincmd.ReissueValidatorRegistration(
+			network,
+			deployer,
+			registration,
+			validationID,
+			registered,
+			rpcURL,
+			aggregatorExtraEndpoints,
+			aggregatorAllowPrivatePeers,
+			aggregatorLogLevel,
+			aggregationConfig,
+		); err != nil {
+			ux.Logger.RedXToUser("failure re-issuing registration of validator %s to %s: %s", registration.NodeID, registration.BlockchainName, err)
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// getRegistrationStatus classifies a registration, given if it is [registered] on P-Chain,
+// the [remaining] time to its expiry, and the [warnBefore] period
+func getRegistrationStatus(registered bool, remaining time.Duration, warnBefore time.Duration) registrationStatus {
+	switch {
+	case registered:
+		return registrationRegistered
+	case remaining <= 0:
+		return registrationExpired
+	case remaining <= warnBefore:
+		return registrationExpiring
+	default:
+		return registrationPending
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatorcmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRegistrationStatus(t *testing.T) {
+	require := require.New(t)
+	warnBefore := 6 * time.Hour
+	require.Equal(registrationPending, getRegistrationStatus(false, 7*time.Hour, warnBefore))
+	require.Equal(registrationExpiring, getRegistrationStatus(false, 6*time.Hour, warnBefore))
+	require.Equal(registrationExpiring, getRegistrationStatus(false, time.Second, warnBefore))
+	require.Equal(registrationExpired, getRegistrationStatus(false, 0, warnBefore))
+	require.Equal(registrationExpired, getRegistrationStatus(false, -time.Hour, warnBefore))
+	// registered on P-Chain, only the validator manager needs to be completed, even if expired
+	require.Equal(registrationRegistered, getRegistrationStatus(true, 7*time.Hour, warnBefore))
+	require.Equal(registrationRegistered, getRegistrationStatus(true, -time.Hour, warnBefore))
+}
@@ -29,9 +29,10 @@ func RunMigrations(app *application.Avalanche) error {
 		showMsg: true,
 		migrations: map[int]migrationFunc{
 			// add new migrations here in rising index order
-			// next one is 2
+			// next one is 3
 			0: migrateTopLevelFiles,
 			1: migrateSubnetEVMNames,
+			2: migrateRPCAuthTokens,
 		},
 	}
 	return runner.run(app)
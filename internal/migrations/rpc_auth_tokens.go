@@ -0,0 +1,49 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migrations
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+)
+
+// The RPC auth tokens of the cluster HTTPS endpoints were stored on the clusters
+// config. They are now kept on the cluster secrets file, only readable by the user
+func migrateRPCAuthTokens(app *application.Avalanche, runner *migrationRunner) error {
+	if !app.ClustersConfigExists() {
+		return nil
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	migrated := false
+	for clusterName, clusterConfig := range clustersConfig.Clusters {
+		if len(clusterConfig.RPCAuthTokens) == 0 {
+			continue
+		}
+		runner.printMigrationMessage()
+		secrets, err := app.LoadClusterSecrets(clusterName)
+		if err != nil {
+			return err
+		}
+		if secrets.RPCAuthTokens == nil {
+			secrets.RPCAuthTokens = map[string]string{}
+		}
+		for blockchainID, token := range clusterConfig.RPCAuthTokens {
+			if _, ok := secrets.RPCAuthTokens[blockchainID]; !ok {
+				secrets.RPCAuthTokens[blockchainID] = token
+			}
+		}
+		if err := app.WriteClusterSecrets(clusterName, secrets); err != nil {
+			return err
+		}
+		clusterConfig.RPCAuthTokens = nil
+		clustersConfig.Clusters[clusterName] = clusterConfig
+		migrated = true
+	}
+	if !migrated {
+		return nil
+	}
+	return app.WriteClustersConfigFile(&clustersConfig)
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migrations
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPCAuthTokensMigration(t *testing.T) {
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	require := require.New(t)
+	app := &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+
+	// nothing to migrate without clusters
+	runner := &migrationRunner{showMsg: true}
+	require.NoError(migrateRPCAuthTokens(app, runner))
+	require.False(runner.running)
+
+	require.NoError(app.WriteClustersConfigFile(&models.ClustersConfig{
+		Clusters: map[string]models.ClusterConfig{
+			"locked": {
+				Nodes:         []string{"i-1"},
+				RPCAuthTokens: map[string]string{"chainA": "tokenA", "chainB": "tokenB"},
+			},
+			"open": {
+				Nodes: []string{"i-2"},
+			},
+		},
+	}))
+	// tokens already on the secrets file are kept
+	require.NoError(app.WriteClusterSecrets("locked", models.ClusterSecrets{
+		SMTPPassword:  "password",
+		RPCAuthTokens: map[string]string{"chainB": "rotated"},
+	}))
+
+	require.NoError(migrateRPCAuthTokens(app, runner))
+	require.True(runner.running)
+	clustersConfig, err := app.LoadClustersConfig()
+	require.NoError(err)
+	require.Empty(clustersConfig.Clusters["locked"].RPCAuthTokens)
+	require.Equal([]string{"i-1"}, clustersConfig.Clusters["locked"].Nodes)
+	secrets, err := app.LoadClusterSecrets("locked")
+	require.NoError(err)
+	require.Equal("password", secrets.SMTPPassword)
+	require.Equal(map[string]string{"chainA": "tokenA", "chainB": "rotated"}, secrets.RPCAuthTokens)
+	info, err := os.Stat(app.GetClusterSecretsPath("locked"))
+	require.NoError(err)
+	require.Equal(os.FileMode(constants.WriteReadUserOnlyPerms), info.Mode().Perm())
+	require.NoFileExists(app.GetClusterSecretsPath("open"))
+
+	// applied once
+	runner = &migrationRunner{showMsg: true}
+	require.NoError(migrateRPCAuthTokens(app, runner))
+	require.False(runner.running)
+}
@@ -140,6 +140,34 @@ func (_m *Prompter) CaptureDuration(promptStr string) (time.Duration, error) {
 	return r0, r1
 }
 
+// CaptureDurationInRange provides a mock function with given fields: promptStr, start, minDuration, maxDuration
+func (_m *Prompter) CaptureDurationInRange(promptStr string, start time.Time, minDuration time.Duration, maxDuration time.Duration) (time.Duration, error) {
+	ret := _m.Called(promptStr, start, minDuration, maxDuration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CaptureDurationInRange")
+	}
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Duration, time.Duration) (time.Duration, error)); ok {
+		return rf(promptStr, start, minDuration, maxDuration)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Duration, time.Duration) time.Duration); ok {
+		r0 = rf(promptStr, start, minDuration, maxDuration)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Duration, time.Duration) error); ok {
+		r1 = rf(promptStr, start, minDuration, maxDuration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CaptureEmail provides a mock function with given fields: promptStr
 func (_m *Prompter) CaptureEmail(promptStr string) (string, error) {
 	ret := _m.Called(promptStr)
@@ -622,6 +650,34 @@ func (_m *Prompter) CapturePChainAddress(promptStr string, network models.Networ
 	return r0, r1
 }
 
+// CapturePassword provides a mock function with given fields: promptStr
+func (_m *Prompter) CapturePassword(promptStr string) (string, error) {
+	ret := _m.Called(promptStr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CapturePassword")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(promptStr)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(promptStr)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(promptStr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CapturePositiveBigInt provides a mock function with given fields: promptStr
 func (_m *Prompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
 	ret := _m.Called(promptStr)
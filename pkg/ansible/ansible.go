@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
@@ -29,13 +31,13 @@ func CreateAnsibleHostInventory(inventoryDirPath, certFilePath, cloudService str
 	}
 	defer inventoryFile.Close()
 	if cloudConfigMap != nil {
-		for _, cloudConfig := range cloudConfigMap {
+		for region, cloudConfig := range cloudConfigMap {
 			for _, instanceID := range cloudConfig.InstanceIDs {
 				ansibleInstanceID, err := models.HostCloudIDToAnsibleID(cloudService, instanceID)
 				if err != nil {
 					return err
 				}
-				if err = writeToInventoryFile(inventoryFile, ansibleInstanceID, publicIPMap[instanceID], cloudConfig.CertFilePath); err != nil {
+				if err = writeToInventoryFile(inventoryFile, ansibleInstanceID, publicIPMap[instanceID], cloudConfig.CertFilePath, region); err != nil {
 					return err
 				}
 			}
@@ -46,7 +48,7 @@ func CreateAnsibleHostInventory(inventoryDirPath, certFilePath, cloudService str
 			if err != nil {
 				return err
 			}
-			if err = writeToInventoryFile(inventoryFile, ansibleInstanceID, publicIPMap[instanceID], certFilePath); err != nil {
+			if err = writeToInventoryFile(inventoryFile, ansibleInstanceID, publicIPMap[instanceID], certFilePath, ""); err != nil {
 				return err
 			}
 		}
@@ -54,13 +56,16 @@ func CreateAnsibleHostInventory(inventoryDirPath, certFilePath, cloudService str
 	return nil
 }
 
-func writeToInventoryFile(inventoryFile *os.File, ansibleInstanceID, publicIP, certFilePath string) error {
+func writeToInventoryFile(inventoryFile *os.File, ansibleInstanceID, publicIP, certFilePath, region string) error {
 	inventoryContent := ansibleInstanceID
 	inventoryContent += " ansible_host="
 	inventoryContent += publicIP
 	inventoryContent += " ansible_user=ubuntu"
 	inventoryContent += fmt.Sprintf(" ansible_ssh_private_key_file=%s", certFilePath)
 	inventoryContent += fmt.Sprintf(" ansible_ssh_common_args='%s'", constants.AnsibleSSHUseAgentParams)
+	if region != "" {
+		inventoryContent += fmt.Sprintf(" region=%s", region)
+	}
 	if _, err := inventoryFile.WriteString(inventoryContent + "\n"); err != nil {
 		return err
 	}
@@ -83,7 +88,23 @@ func WriteNodeConfigsToAnsibleInventory(inventoryDirPath string, nc []models.Nod
 		if err != nil {
 			return err
 		}
-		if err := writeToInventoryFile(inventoryFile, nodeID, nodeConfig.ElasticIP, nodeConfig.CertPath); err != nil {
+		if err := writeToInventoryFile(inventoryFile, nodeID, nodeConfig.ElasticIP, nodeConfig.CertPath, nodeConfig.Region); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteAnsibleInventory regenerates the ansible inventory file with the given hosts
+func WriteAnsibleInventory(inventoryDirPath string, hosts []*models.Host) error {
+	inventoryHostsFilePath := filepath.Join(inventoryDirPath, constants.AnsibleHostInventoryFileName)
+	inventoryFile, err := os.Create(inventoryHostsFilePath)
+	if err != nil {
+		return err
+	}
+	defer inventoryFile.Close()
+	for _, host := range hosts {
+		if _, err := inventoryFile.WriteString(host.GetAnsibleInventoryRecord() + "\n"); err != nil {
 			return err
 		}
 	}
@@ -103,6 +124,35 @@ func GetAnsibleHostsFromInventory(inventoryDirPath string) ([]string, error) {
 	return ansibleHostIDs, nil
 }
 
+// managedInventoryVars are the host variables set by the CLI on the inventories. Any other
+// variable of a host is kept as is when the inventory is rewritten
+var managedInventoryVars = []string{
+	"ansible_host",
+	"ansible_user",
+	"ansible_ssh_private_key_file",
+	"ansible_ssh_common_args",
+	"ansible_port",
+	"ssh_proxy_jump",
+	"ssh_forward_agent",
+	"region",
+}
+
+// getUnmanagedInventoryVars returns the variables of [hostVars] not managed by the CLI,
+// skipping the [hostAlias] entry of inventory file records
+func getUnmanagedInventoryVars(hostAlias string, hostVars map[string]string) map[string]string {
+	vars := map[string]string{}
+	for name, value := range hostVars {
+		if name == hostAlias || slices.Contains(managedInventoryVars, name) {
+			continue
+		}
+		vars[name] = value
+	}
+	if len(vars) == 0 {
+		return nil
+	}
+	return vars
+}
+
 func GetInventoryFromAnsibleInventoryFile(inventoryDirPath string) ([]*models.Host, error) {
 	inventory := []*models.Host{}
 	inventoryHostsFile := filepath.Join(inventoryDirPath, constants.AnsibleHostInventoryFileName)
@@ -124,6 +174,17 @@ func GetInventoryFromAnsibleInventoryFile(inventoryDirPath string) ([]*models.Ho
 			SSHUser:           parsedHost["ansible_user"],
 			SSHPrivateKeyPath: parsedHost["ansible_ssh_private_key_file"],
 			SSHCommonArgs:     parsedHost["ansible_ssh_common_args"],
+			SSHProxyJump:      parsedHost["ssh_proxy_jump"],
+			SSHForwardAgent:   parsedHost["ssh_forward_agent"] == "true",
+			Region:            parsedHost["region"],
+			InventoryVars:     getUnmanagedInventoryVars(strings.Split(scanner.Text(), " ")[0], parsedHost),
+		}
+		if sshPort, ok := parsedHost["ansible_port"]; ok {
+			port, err := strconv.ParseUint(sshPort, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ansible_port %q for host %s: %w", sshPort, host.NodeID, err)
+			}
+			host.SSHPort = uint(port)
 		}
 		inventory = append(inventory, host)
 	}
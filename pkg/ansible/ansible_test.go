@@ -0,0 +1,39 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ansible
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnsibleInventoryKeepsCustomVars(t *testing.T) {
+	require := require.New(t)
+	inventoryDir := t.TempDir()
+	hosts := []*models.Host{
+		{
+			NodeID:            "aws_node_i-1",
+			IP:                "10.0.0.1",
+			SSHUser:           "ubuntu",
+			SSHPrivateKeyPath: "/keys/node.pem",
+			SSHCommonArgs:     "-o IdentitiesOnly=yes",
+			SSHPort:           2222,
+			InventoryVars: map[string]string{
+				"ansible_python_interpreter": "/usr/bin/python3",
+				"ansible_become_flags":       "-H -S",
+			},
+		},
+		{
+			NodeID:  "aws_node_i-2",
+			IP:      "10.0.0.2",
+			SSHUser: "ubuntu",
+		},
+	}
+	require.NoError(WriteAnsibleInventory(inventoryDir, hosts))
+	got, err := GetInventoryFromAnsibleInventoryFile(inventoryDir)
+	require.NoError(err)
+	require.Equal(hosts, got)
+}
@@ -0,0 +1,138 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ansible
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlInventoryGroup is a group of an ansible YAML inventory
+type yamlInventoryGroup struct {
+	Vars     map[string]interface{}            `yaml:"vars"`
+	Hosts    map[string]map[string]interface{} `yaml:"hosts"`
+	Children map[string]*yamlInventoryGroup    `yaml:"children"`
+}
+
+// ParseYAMLInventory reads the hosts of the ansible YAML inventory at [inventoryPath], eg:
+//
+//	all:
+//	  vars:
+//	    ansible_user: admin
+//	  hosts:
+//	    validator-1:
+//	      ansible_host: 1.2.3.4
+//	      ansible_ssh_private_key_file: ~/.ssh/validators.pem
+//
+// Host vars take precedence over group vars, and group vars are inherited by the group
+// children. The ansible_host, ansible_user, ansible_port, ansible_ssh_private_key_file,
+// ssh_proxy_jump, ssh_forward_agent and region vars are used. Host NodeID is the inventory name
+func ParseYAMLInventory(inventoryPath string) ([]*models.Host, error) {
+	inventoryBytes, err := os.ReadFile(inventoryPath)
+	if err != nil {
+		return nil, err
+	}
+	groups := map[string]*yamlInventoryGroup{}
+	if err := yaml.Unmarshal(inventoryBytes, &groups); err != nil {
+		return nil, fmt.Errorf("invalid inventory %s: %w", inventoryPath, err)
+	}
+	hostsVars := map[string]map[string]interface{}{}
+	for _, group := range groups {
+		collectYAMLInventoryHosts(group, nil, hostsVars)
+	}
+	names := make([]string, 0, len(hostsVars))
+	for name := range hostsVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hosts := []*models.Host{}
+	for _, name := range names {
+		host, err := yamlInventoryHost(name, hostsVars[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid inventory %s: %w", inventoryPath, err)
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// collectYAMLInventoryHosts adds to [hostsVars] the vars of the hosts of [group] and its
+// children, given the [inherited] vars of the group parents
+func collectYAMLInventoryHosts(
+	group *yamlInventoryGroup,
+	inherited map[string]interface{},
+	hostsVars map[string]map[string]interface{},
+) {
+	if group == nil {
+		return
+	}
+	groupVars := maps.Clone(inherited)
+	if groupVars == nil {
+		groupVars = map[string]interface{}{}
+	}
+	maps.Copy(groupVars, group.Vars)
+	for name, vars := range group.Hosts {
+		hostVars, ok := hostsVars[name]
+		if !ok {
+			hostVars = map[string]interface{}{}
+			hostsVars[name] = hostVars
+		}
+		maps.Copy(hostVars, groupVars)
+		maps.Copy(hostVars, vars)
+	}
+	for _, child := range group.Children {
+		collectYAMLInventoryHosts(child, groupVars, hostsVars)
+	}
+}
+
+func yamlInventoryHost(name string, vars map[string]interface{}) (*models.Host, error) {
+	getVar := func(key string) string {
+		if value, ok := vars[key]; ok && value != nil {
+			return fmt.Sprint(value)
+		}
+		return ""
+	}
+	host := &models.Host{
+		NodeID:            name,
+		IP:                getVar("ansible_host"),
+		SSHUser:           getVar("ansible_user"),
+		SSHPrivateKeyPath: getVar("ansible_ssh_private_key_file"),
+		SSHProxyJump:      getVar("ssh_proxy_jump"),
+		SSHForwardAgent:   getVar("ssh_forward_agent") == "true",
+		Region:            getVar("region"),
+	}
+	if host.IP == "" {
+		host.IP = name
+	}
+	extraVars := map[string]string{}
+	for key, value := range vars {
+		switch value.(type) {
+		case string, bool, int, float64:
+			extraVars[key] = fmt.Sprint(value)
+		}
+	}
+	host.InventoryVars = getUnmanagedInventoryVars("", extraVars)
+	if host.SSHUser == "" {
+		host.SSHUser = constants.AnsibleSSHUser
+	}
+	if host.SSHPrivateKeyPath != "" {
+		host.SSHPrivateKeyPath = utils.ExpandHome(host.SSHPrivateKeyPath)
+	}
+	if sshPort := getVar("ansible_port"); sshPort != "" {
+		port, err := strconv.ParseUint(sshPort, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ansible_port %q for host %s: %w", sshPort, name, err)
+		}
+		host.SSHPort = uint(port)
+	}
+	return host, nil
+}
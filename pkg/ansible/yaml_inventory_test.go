@@ -0,0 +1,85 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ansible
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseYAMLInventory(t *testing.T) {
+	require := require.New(t)
+	inventoryPath := filepath.Join(t.TempDir(), "hosts.yaml")
+	inventory := `
+all:
+  vars:
+    ansible_user: admin
+    ansible_ssh_private_key_file: /keys/legacy.pem
+    ansible_python_interpreter: /usr/bin/python3
+  hosts:
+    rpc-1:
+      ansible_host: 10.0.0.1
+  children:
+    validators:
+      vars:
+        ansible_port: 2222
+        ssh_proxy_jump: bastion.example.com
+      hosts:
+        validator-1:
+          ansible_host: 10.0.0.2
+        validator-2:
+          ansible_host: 10.0.0.3
+          ansible_user: ubuntu
+          ssh_forward_agent: true
+          ansible_become: true
+    legacy:
+      hosts:
+        node.example.com:
+`
+	require.NoError(os.WriteFile(inventoryPath, []byte(inventory), 0o600))
+	hosts, err := ParseYAMLInventory(inventoryPath)
+	require.NoError(err)
+	require.Equal([]*models.Host{
+		{
+			NodeID:            "node.example.com",
+			IP:                "node.example.com",
+			SSHUser:           "admin",
+			SSHPrivateKeyPath: "/keys/legacy.pem",
+			InventoryVars:     map[string]string{"ansible_python_interpreter": "/usr/bin/python3"},
+		},
+		{
+			NodeID:            "rpc-1",
+			IP:                "10.0.0.1",
+			SSHUser:           "admin",
+			SSHPrivateKeyPath: "/keys/legacy.pem",
+			InventoryVars:     map[string]string{"ansible_python_interpreter": "/usr/bin/python3"},
+		},
+		{
+			NodeID:            "validator-1",
+			IP:                "10.0.0.2",
+			SSHUser:           "admin",
+			SSHPrivateKeyPath: "/keys/legacy.pem",
+			SSHPort:           2222,
+			SSHProxyJump:      "bastion.example.com",
+			InventoryVars:     map[string]string{"ansible_python_interpreter": "/usr/bin/python3"},
+		},
+		{
+			NodeID:            "validator-2",
+			IP:                "10.0.0.3",
+			SSHUser:           "ubuntu",
+			SSHPrivateKeyPath: "/keys/legacy.pem",
+			SSHPort:           2222,
+			SSHProxyJump:      "bastion.example.com",
+			SSHForwardAgent:   true,
+			InventoryVars: map[string]string{
+				"ansible_python_interpreter": "/usr/bin/python3",
+				"ansible_become":             "true",
+			},
+		},
+	}, hosts)
+}
@@ -4,9 +4,11 @@ package application
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/ava-labs/apm/apm"
@@ -18,14 +20,22 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	sdkutils "github.com/ava-labs/avalanche-cli/sdk/utils"
+	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/subnet-evm/core"
 
+	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
 )
 
+// ErrReadOnly is returned by the operations that mutate local state when the
+// application runs in read-only mode
+var ErrReadOnly = errors.New("operation not allowed in read-only mode")
+
 type Avalanche struct {
+	// ReadOnly guarantees no local state is written
+	ReadOnly   bool
 	Log        logging.Logger
 	baseDir    string
 	Conf       *config.Config
@@ -33,6 +43,8 @@ type Avalanche struct {
 	Apm        *apm.APM
 	ApmDir     string
 	Downloader Downloader
+	// CommandLogPath is the structured log file of the current command invocation, if any
+	CommandLogPath string
 }
 
 func New() *Avalanche {
@@ -79,6 +91,71 @@ func (app *Avalanche) GetRunDir() string {
 	return filepath.Join(app.baseDir, constants.RunDir)
 }
 
+// GetCommandLogsDir returns the dir holding the structured debug logs of the latest
+// command invocations
+func (app *Avalanche) GetCommandLogsDir() string {
+	return filepath.Join(app.baseDir, constants.LogDir, constants.CommandLogsDir)
+}
+
+// GetNetworkUpgradesDir returns the dir holding the cached upgrade schedules of the networks
+func (app *Avalanche) GetNetworkUpgradesDir() string {
+	return filepath.Join(app.baseDir, constants.NetworkUpgradesDir)
+}
+
+// GetSchedulesDir returns the dir holding the scheduled operations and their run history
+func (app *Avalanche) GetSchedulesDir() string {
+	return filepath.Join(app.baseDir, constants.SchedulesDir)
+}
+
+// GetWarpSigningDir returns the dir holding the warp messages that failed signature
+// aggregation, to be manually signed
+func (app *Avalanche) GetWarpSigningDir() string {
+	return filepath.Join(app.baseDir, constants.WarpSigningDir)
+}
+
+// GetLocalNetworkGenesisPath returns the path of the custom primary network genesis
+// the local network was started with, if any
+func (app *Avalanche) GetLocalNetworkGenesisPath() string {
+	return filepath.Join(app.baseDir, constants.LocalNetworkGenesisFileName)
+}
+
+// GetLocalNetworkID returns the network ID of the local network: the one of its custom
+// genesis if it was started with one, or the default local network ID
+func (app *Avalanche) GetLocalNetworkID() (uint32, error) {
+	genesisPath := app.GetLocalNetworkGenesisPath()
+	if !utils.FileExists(genesisPath) {
+		return constants.LocalNetworkID, nil
+	}
+	genesisConfig, err := genesis.GetConfigFile(genesisPath)
+	if err != nil {
+		return 0, fmt.Errorf("invalid local network genesis %s: %w", genesisPath, err)
+	}
+	return genesisConfig.NetworkID, nil
+}
+
+// GetLocalNetwork returns the local network, with the network ID of the custom genesis
+// it was started with, if any
+func (app *Avalanche) GetLocalNetwork() models.Network {
+	networkID, err := app.GetLocalNetworkID()
+	if err != nil {
+		app.Log.Warn("failed to get local network ID, using the default one", zap.Error(err))
+		networkID = constants.LocalNetworkID
+	}
+	return models.NewLocalNetworkWithID(networkID)
+}
+
+// GetLocalDockerNetworkDir returns the dir holding the compose project and node data of
+// the local network, when it is run with the docker backend
+func (app *Avalanche) GetLocalDockerNetworkDir() string {
+	return filepath.Join(app.GetRunDir(), constants.LocalDockerNetworkDir)
+}
+
+// UseLocalDockerNetwork returns true if the local network is configured to run each node
+// in a docker container, instead of as a process managed by the network runner
+func (app *Avalanche) UseLocalDockerNetwork() bool {
+	return app.Conf.GetConfigStringValue(constants.ConfigLocalNetworkBackendKey) == constants.LocalNetworkDockerBackend
+}
+
 func (app *Avalanche) GetServicesDir(baseDir string) string {
 	if baseDir == "" {
 		baseDir = app.baseDir
@@ -100,6 +177,9 @@ func (app *Avalanche) GetLocalDir(clusterName string) string {
 
 // Remove all plugins from plugin dir
 func (app *Avalanche) ResetPluginsDir() error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
 	pluginDir := app.GetPluginsDir()
 	installedPlugins, err := os.ReadDir(pluginDir)
 	if err != nil {
@@ -117,6 +197,12 @@ func (app *Avalanche) GetAvalanchegoBinDir() string {
 	return filepath.Join(app.baseDir, constants.AvalancheCliBinDir, constants.AvalancheGoInstallDir)
 }
 
+// GetBinaryCacheDir returns the dir of the binary cache, shared by the install dirs of
+// avalanchego, subnet-evm and icm-relayer
+func (app *Avalanche) GetBinaryCacheDir() string {
+	return filepath.Join(app.baseDir, constants.AvalancheCliBinDir, constants.BinaryCacheDir)
+}
+
 func (app *Avalanche) GetICMContractsBinDir() string {
 	return filepath.Join(app.baseDir, constants.AvalancheCliBinDir, constants.ICMContractsInstallDir)
 }
@@ -224,6 +310,12 @@ func (app *Avalanche) GetMonitoringInventoryDir(clusterName string) string {
 	return filepath.Join(app.GetAnsibleInventoryDirPath(clusterName), constants.MonitoringDir)
 }
 
+// GetTelemetryDashboardPath returns the path where the dashboard of [clusterName] on the
+// external monitoring [backend] is saved when it can't be created on the backend
+func (app *Avalanche) GetTelemetryDashboardPath(clusterName string, backend string) string {
+	return filepath.Join(app.GetNodesDir(), clusterName+"-"+backend+"-dashboard.json")
+}
+
 func (app *Avalanche) GetLoadTestInventoryDir(clusterName string) string {
 	return filepath.Join(app.GetAnsibleInventoryDirPath(clusterName), constants.LoadTestDir)
 }
@@ -282,11 +374,37 @@ func (app *Avalanche) GetKeyPath(keyName string) string {
 	return filepath.Join(app.baseDir, constants.KeyDir, keyName+constants.KeySuffix)
 }
 
+func (app *Avalanche) GetHDWalletPath(walletName string) string {
+	return filepath.Join(app.baseDir, constants.KeyDir, walletName+constants.HDWalletSuffix)
+}
+
+func (app *Avalanche) GetKeyTagsPath() string {
+	return filepath.Join(app.baseDir, constants.KeyDir, constants.KeyTagsFileName)
+}
+
 func (app *Avalanche) GetKey(keyName string, network models.Network, createIfMissing bool) (*key.SoftKey, error) {
+	if walletName, evm, index, ok := key.ParseDerivedKeyName(keyName); ok {
+		var (
+			w   *key.HDWallet
+			err error
+		)
+		if createIfMissing && !app.ReadOnly {
+			w, err = key.LoadHDWalletOrCreate(app.GetHDWalletPath(walletName))
+		} else {
+			w, err = key.LoadHDWallet(app.GetHDWalletPath(walletName))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if evm {
+			return w.DeriveEVMAccount(network.ID, index)
+		}
+		return w.DeriveAccount(network.ID, index)
+	}
 	if keyName == "ewoq" {
 		return key.LoadEwoq(network.ID)
 	} else {
-		if createIfMissing {
+		if createIfMissing && !app.ReadOnly {
 			return key.LoadSoftOrCreate(network.ID, app.GetKeyPath(keyName))
 		} else {
 			return key.LoadSoft(network.ID, app.GetKeyPath(keyName))
@@ -330,6 +448,59 @@ func (app *Avalanche) WriteLockUpgradeFile(blockchainName string, bytes []byte)
 	return app.writeFile(upgradeBytesLockFilePath, bytes)
 }
 
+// GetUpgradeSetFilePath returns the path of the named upgrade set [setName] of [blockchainName].
+// The default set maps to the blockchain upgrade file
+func (app *Avalanche) GetUpgradeSetFilePath(blockchainName string, setName string) string {
+	if setName == "" || setName == constants.DefaultUpgradeSetName {
+		return app.GetUpgradeBytesFilePath(blockchainName)
+	}
+	return filepath.Join(app.GetSubnetDir(), blockchainName, constants.UpgradeSetsDir, setName+".json")
+}
+
+// GetNetworkUpgradeSetFilePath returns the path of the upgrade file to be installed on [networkName],
+// that is, the upgrade set selected for that network, or the default upgrade file if none was selected
+func (app *Avalanche) GetNetworkUpgradeSetFilePath(sc models.Sidecar, networkName string) string {
+	return app.GetUpgradeSetFilePath(sc.Name, sc.UpgradeSets[networkName])
+}
+
+func (app *Avalanche) UpgradeSetExists(blockchainName string, setName string) bool {
+	_, err := os.Stat(app.GetUpgradeSetFilePath(blockchainName, setName))
+	return err == nil
+}
+
+func (app *Avalanche) ReadUpgradeSetFile(blockchainName string, setName string) ([]byte, error) {
+	return app.readFile(app.GetUpgradeSetFilePath(blockchainName, setName))
+}
+
+func (app *Avalanche) WriteUpgradeSetFile(blockchainName string, setName string, bytes []byte) error {
+	return app.writeFile(app.GetUpgradeSetFilePath(blockchainName, setName), bytes)
+}
+
+// GetUpgradeSets returns the sorted names of the upgrade sets of [blockchainName],
+// including the default one if the blockchain upgrade file exists
+func (app *Avalanche) GetUpgradeSets(blockchainName string) ([]string, error) {
+	setNames := []string{}
+	if app.NetworkUpgradeExists(blockchainName) {
+		setNames = append(setNames, constants.DefaultUpgradeSetName)
+	}
+	entries, err := os.ReadDir(filepath.Join(app.GetSubnetDir(), blockchainName, constants.UpgradeSetsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return setNames, nil
+		}
+		return nil, err
+	}
+	namedSets := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		namedSets = append(namedSets, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(namedSets)
+	return append(setNames, namedSets...), nil
+}
+
 func (app *Avalanche) WriteGenesisFile(blockchainName string, genesisBytes []byte) error {
 	genesisPath := app.GetGenesisPath(blockchainName)
 
@@ -402,13 +573,25 @@ func (app *Avalanche) BlockchainConfigExists(blockchainName string) bool {
 	return app.SidecarExists(blockchainName)
 }
 
+// HDWalletExists returns true if HD wallet [walletName] exists
+func (app *Avalanche) HDWalletExists(walletName string) bool {
+	_, err := os.Stat(app.GetHDWalletPath(walletName))
+	return err == nil
+}
+
 func (app *Avalanche) KeyExists(keyName string) bool {
 	keyPath := app.GetKeyPath(keyName)
+	if walletName, _, _, ok := key.ParseDerivedKeyName(keyName); ok {
+		keyPath = app.GetHDWalletPath(walletName)
+	}
 	_, err := os.Stat(keyPath)
 	return err == nil
 }
 
 func (app *Avalanche) CopyGenesisFile(inputFilename string, blockchainName string) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
 	genesisBytes, err := os.ReadFile(inputFilename)
 	if err != nil {
 		return err
@@ -422,6 +605,9 @@ func (app *Avalanche) CopyGenesisFile(inputFilename string, blockchainName strin
 }
 
 func (app *Avalanche) CopyVMBinary(inputFilename string, blockchainName string) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
 	vmBytes, err := os.ReadFile(inputFilename)
 	if err != nil {
 		return err
@@ -431,6 +617,9 @@ func (app *Avalanche) CopyVMBinary(inputFilename string, blockchainName string)
 }
 
 func (app *Avalanche) CopyKeyFile(inputFilename string, keyName string) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
 	keyBytes, err := os.ReadFile(inputFilename)
 	if err != nil {
 		return err
@@ -482,6 +671,9 @@ func (app *Avalanche) LoadRawNetworkUpgrades(blockchainName string) ([]byte, err
 }
 
 func (app *Avalanche) CreateSidecar(sc *models.Sidecar) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
 	if sc.TokenName == "" {
 		sc.TokenName = constants.DefaultTokenName
 		sc.TokenSymbol = constants.DefaultTokenSymbol
@@ -525,6 +717,9 @@ func (app *Avalanche) LoadSidecar(blockchainName string) (models.Sidecar, error)
 }
 
 func (app *Avalanche) UpdateSidecar(sc *models.Sidecar) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
 	sc.Version = constants.SidecarVersion
 	scBytes, err := json.MarshalIndent(sc, "", "    ")
 	if err != nil {
@@ -579,6 +774,15 @@ func (app *Avalanche) GetTokenSymbol(blockchainName string) string {
 	return sidecar.TokenSymbol
 }
 
+// GetTokenDecimals returns the decimals of the native token of [blockchainName]
+func (app *Avalanche) GetTokenDecimals(blockchainName string) uint8 {
+	sidecar, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return constants.DefaultTokenDecimals
+	}
+	return sidecar.GetTokenDecimals()
+}
+
 func (app *Avalanche) GetBlockchainNames() ([]string, error) {
 	matches, err := os.ReadDir(app.GetSubnetDir())
 	if err != nil {
@@ -631,15 +835,28 @@ func (app *Avalanche) GetBlockchainNamesOnNetwork(
 	return filtered, nil
 }
 
-func (*Avalanche) readFile(path string) ([]byte, error) {
-	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
-		return nil, err
+func (app *Avalanche) readFile(path string) ([]byte, error) {
+	if !app.ReadOnly {
+		if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+			return nil, err
+		}
 	}
 
 	return os.ReadFile(path)
 }
 
-func (*Avalanche) writeFile(path string, bytes []byte) error {
+// checkWritable fails if local state is not allowed to be modified
+func (app *Avalanche) checkWritable() error {
+	if app.ReadOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+func (app *Avalanche) writeFile(path string, bytes []byte) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
 	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
 		return err
 	}
@@ -648,6 +865,9 @@ func (*Avalanche) writeFile(path string, bytes []byte) error {
 }
 
 func (app *Avalanche) CreateNodeCloudConfigFile(nodeName string, nodeConfig *models.NodeConfig) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
 	nodeConfigPath := app.GetNodeConfigPath(nodeName)
 	if err := os.MkdirAll(filepath.Dir(nodeConfigPath), constants.DefaultPerms755); err != nil {
 		return err
@@ -724,6 +944,9 @@ func (app *Avalanche) GetClustersConfig() (models.ClustersConfig, error) {
 }
 
 func (app *Avalanche) WriteClustersConfigFile(clustersConfig *models.ClustersConfig) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
 	clustersConfigPath := app.GetClustersConfigPath()
 	if err := os.MkdirAll(filepath.Dir(clustersConfigPath), constants.DefaultPerms755); err != nil {
 		return err
@@ -738,6 +961,247 @@ func (app *Avalanche) WriteClustersConfigFile(clustersConfig *models.ClustersCon
 	return os.WriteFile(clustersConfigPath, clustersConfigBytes, constants.WriteReadReadPerms)
 }
 
+func (app *Avalanche) GetDevnetsConfigPath() string {
+	return filepath.Join(app.baseDir, constants.DevnetsConfigFileName)
+}
+
+func (app *Avalanche) LoadDevnetsConfig() (models.DevnetsConfig, error) {
+	devnetsConfig := models.DevnetsConfig{
+		Devnets: map[string]models.DevnetConfig{},
+	}
+	devnetsConfigPath := app.GetDevnetsConfigPath()
+	if !utils.FileExists(devnetsConfigPath) {
+		return devnetsConfig, nil
+	}
+	jsonBytes, err := os.ReadFile(devnetsConfigPath)
+	if err != nil {
+		return models.DevnetsConfig{}, err
+	}
+	if err := json.Unmarshal(jsonBytes, &devnetsConfig); err != nil {
+		return models.DevnetsConfig{}, err
+	}
+	if devnetsConfig.Devnets == nil {
+		devnetsConfig.Devnets = map[string]models.DevnetConfig{}
+	}
+	return devnetsConfig, nil
+}
+
+func (app *Avalanche) WriteDevnetsConfigFile(devnetsConfig *models.DevnetsConfig) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
+	devnetsConfigBytes, err := json.MarshalIndent(devnetsConfig, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(app.GetDevnetsConfigPath(), devnetsConfigBytes, constants.WriteReadReadPerms)
+}
+
+func (app *Avalanche) GetDevnetConfig(devnetName string) (models.DevnetConfig, error) {
+	devnetsConfig, err := app.LoadDevnetsConfig()
+	if err != nil {
+		return models.DevnetConfig{}, err
+	}
+	devnetConfig, ok := devnetsConfig.Devnets[devnetName]
+	if !ok {
+		return models.DevnetConfig{}, fmt.Errorf("devnet %q is not registered. use avalanche network add-devnet to register it", devnetName)
+	}
+	return devnetConfig, nil
+}
+
+func (app *Avalanche) GetValidatorRegistrationsPath() string {
+	return filepath.Join(app.baseDir, constants.ValidatorRegistrationsFile)
+}
+
+func (app *Avalanche) LoadValidatorRegistrations() (models.ValidatorRegistrations, error) {
+	registrations := models.ValidatorRegistrations{
+		Registrations: map[string]models.ValidatorRegistration{},
+	}
+	registrationsPath := app.GetValidatorRegistrationsPath()
+	if !utils.FileExists(registrationsPath) {
+		return registrations, nil
+	}
+	jsonBytes, err := os.ReadFile(registrationsPath)
+	if err != nil {
+		return models.ValidatorRegistrations{}, err
+	}
+	if err := json.Unmarshal(jsonBytes, &registrations); err != nil {
+		return models.ValidatorRegistrations{}, err
+	}
+	if registrations.Registrations == nil {
+		registrations.Registrations = map[string]models.ValidatorRegistration{}
+	}
+	return registrations, nil
+}
+
+func (app *Avalanche) WriteValidatorRegistrationsFile(registrations *models.ValidatorRegistrations) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
+	registrationsBytes, err := json.MarshalIndent(registrations, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(app.GetValidatorRegistrationsPath(), registrationsBytes, constants.WriteReadReadPerms)
+}
+
+// TrackValidatorRegistration records [registration] as pending, so it can be
+// checked for expiry with avalanche validator watch
+func (app *Avalanche) TrackValidatorRegistration(registration models.ValidatorRegistration) error {
+	registrations, err := app.LoadValidatorRegistrations()
+	if err != nil {
+		return err
+	}
+	registrations.Registrations[registration.ValidationID] = registration
+	return app.WriteValidatorRegistrationsFile(&registrations)
+}
+
+// UntrackValidatorRegistration removes the pending registration for [validationID], if any
+func (app *Avalanche) UntrackValidatorRegistration(validationID string) error {
+	registrations, err := app.LoadValidatorRegistrations()
+	if err != nil {
+		return err
+	}
+	if _, ok := registrations.Registrations[validationID]; !ok {
+		return nil
+	}
+	delete(registrations.Registrations, validationID)
+	return app.WriteValidatorRegistrationsFile(&registrations)
+}
+
+func (app *Avalanche) GetClusterHealthHistoryPath(clusterName string) string {
+	return filepath.Join(app.GetNodesDir(), constants.ClusterHealthHistoryDir, clusterName+".json")
+}
+
+func (app *Avalanche) LoadClusterHealthHistory(clusterName string) (models.ClusterHealthHistory, error) {
+	historyPath := app.GetClusterHealthHistoryPath(clusterName)
+	if !utils.FileExists(historyPath) {
+		return models.ClusterHealthHistory{}, nil
+	}
+	jsonBytes, err := os.ReadFile(historyPath)
+	if err != nil {
+		return models.ClusterHealthHistory{}, err
+	}
+	var history models.ClusterHealthHistory
+	if err := json.Unmarshal(jsonBytes, &history); err != nil {
+		return models.ClusterHealthHistory{}, err
+	}
+	return history, nil
+}
+
+func (app *Avalanche) WriteClusterHealthHistoryFile(clusterName string, history *models.ClusterHealthHistory) error {
+	historyBytes, err := json.MarshalIndent(history, "", "    ")
+	if err != nil {
+		return err
+	}
+	return app.writeFile(app.GetClusterHealthHistoryPath(clusterName), historyBytes)
+}
+
+// GetClusterSecretsPath returns the path of the file holding the credentials of [clusterName]
+func (app *Avalanche) GetClusterSecretsPath(clusterName string) string {
+	return filepath.Join(app.GetNodesDir(), constants.ClusterSecretsDir, clusterName+".json")
+}
+
+// LoadClusterSecrets loads the credentials of [clusterName], if any
+func (app *Avalanche) LoadClusterSecrets(clusterName string) (models.ClusterSecrets, error) {
+	secretsPath := app.GetClusterSecretsPath(clusterName)
+	if !utils.FileExists(secretsPath) {
+		return models.ClusterSecrets{}, nil
+	}
+	jsonBytes, err := os.ReadFile(secretsPath)
+	if err != nil {
+		return models.ClusterSecrets{}, err
+	}
+	var secrets models.ClusterSecrets
+	if err := json.Unmarshal(jsonBytes, &secrets); err != nil {
+		return models.ClusterSecrets{}, fmt.Errorf("invalid cluster secrets file %s: %w", secretsPath, err)
+	}
+	return secrets, nil
+}
+
+// WriteClusterSecrets saves the credentials of [clusterName] on a file only readable by the user
+func (app *Avalanche) WriteClusterSecrets(clusterName string, secrets models.ClusterSecrets) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
+	secretsBytes, err := json.MarshalIndent(secrets, "", "    ")
+	if err != nil {
+		return err
+	}
+	secretsPath := app.GetClusterSecretsPath(clusterName)
+	if err := os.MkdirAll(filepath.Dir(secretsPath), constants.UserOnlyDirPerms); err != nil {
+		return err
+	}
+	if err := os.WriteFile(secretsPath, secretsBytes, constants.WriteReadUserOnlyPerms); err != nil {
+		return err
+	}
+	// the file may have been created before with wider permissions
+	return os.Chmod(secretsPath, constants.WriteReadUserOnlyPerms)
+}
+
+// AddClusterHealthSnapshot appends [snapshot] to the health history of [clusterName],
+// removing the snapshots older than the history retention
+func (app *Avalanche) AddClusterHealthSnapshot(clusterName string, snapshot models.ClusterHealthSnapshot) error {
+	history, err := app.LoadClusterHealthHistory(clusterName)
+	if err != nil {
+		return err
+	}
+	cutoff := snapshot.Time.Add(-constants.ClusterHealthHistoryRetention)
+	history.Snapshots = utils.Filter(history.Snapshots, func(s models.ClusterHealthSnapshot) bool {
+		return s.Time.After(cutoff)
+	})
+	history.Snapshots = append(history.Snapshots, snapshot)
+	return app.WriteClusterHealthHistoryFile(clusterName, &history)
+}
+
+func (app *Avalanche) GetScratchChainsDir() string {
+	return filepath.Join(app.baseDir, constants.ScratchChainsDir)
+}
+
+func (app *Avalanche) GetScratchChainDir(chainName string) string {
+	return filepath.Join(app.GetScratchChainsDir(), chainName)
+}
+
+func (app *Avalanche) GetScratchChainGenesisPath(chainName string) string {
+	return filepath.Join(app.GetScratchChainDir(chainName), constants.GenesisFileName)
+}
+
+// GetPresetsDir returns the dir where blockchain preset registries and presets are cached
+func (app *Avalanche) GetPresetsDir() string {
+	return filepath.Join(app.baseDir, constants.PresetsDir)
+}
+
+func (app *Avalanche) ScratchChainExists(chainName string) bool {
+	return utils.FileExists(filepath.Join(app.GetScratchChainDir(chainName), constants.ScratchChainFileName))
+}
+
+func (app *Avalanche) LoadScratchChain(chainName string) (models.ScratchChain, error) {
+	jsonBytes, err := os.ReadFile(filepath.Join(app.GetScratchChainDir(chainName), constants.ScratchChainFileName))
+	if err != nil {
+		return models.ScratchChain{}, err
+	}
+	var chain models.ScratchChain
+	if err := json.Unmarshal(jsonBytes, &chain); err != nil {
+		return models.ScratchChain{}, err
+	}
+	return chain, nil
+}
+
+func (app *Avalanche) WriteScratchChain(chain models.ScratchChain) error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
+	chainDir := app.GetScratchChainDir(chain.Name)
+	if err := os.MkdirAll(chainDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	chainBytes, err := json.MarshalIndent(chain, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(chainDir, constants.ScratchChainFileName), chainBytes, constants.WriteReadReadPerms)
+}
+
 func (*Avalanche) GetSSHCertFilePath(certName string) (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -805,6 +1269,9 @@ func (app *Avalanche) GetMonitoringDashboardDir() string {
 }
 
 func (app *Avalanche) SetupMonitoringEnv() error {
+	if err := app.checkWritable(); err != nil {
+		return err
+	}
 	err := os.RemoveAll(app.GetMonitoringDir())
 	if err != nil {
 		return err
@@ -877,7 +1344,7 @@ func (app *Avalanche) GetNetworkFromSidecarNetworkName(
 ) (models.Network, error) {
 	switch {
 	case networkName == models.Local.String():
-		return models.NewLocalNetwork(), nil
+		return app.GetLocalNetwork(), nil
 	case strings.HasPrefix(networkName, "Cluster"):
 		// network names on sidecar can refer to a cluster in the form "Cluster <clusterName>"
 		// we use clusterName to find out the underlying network for the cluster
@@ -3,12 +3,15 @@
 package application
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/stretchr/testify/require"
@@ -276,6 +279,88 @@ func Test_genesisExists(t *testing.T) {
 	require.NoError(err)
 }
 
+func Test_readOnly(t *testing.T) {
+	require := require.New(t)
+	sc := &models.Sidecar{
+		Name: subnetName1,
+		VM:   models.SubnetEvm,
+	}
+
+	ap := newTestApp(t)
+	err := ap.CreateSidecar(sc)
+	require.NoError(err)
+
+	ap.ReadOnly = true
+	_, err = ap.LoadSidecar(sc.Name)
+	require.NoError(err)
+	require.ErrorIs(ap.UpdateSidecar(sc), ErrReadOnly)
+	require.ErrorIs(ap.CreateSidecar(&models.Sidecar{Name: subnetName2}), ErrReadOnly)
+	require.ErrorIs(ap.WriteGenesisFile(subnetName1, []byte("genesis")), ErrReadOnly)
+	require.ErrorIs(ap.WriteClustersConfigFile(&models.ClustersConfig{}), ErrReadOnly)
+	require.False(ap.GenesisExists(subnetName1))
+	require.False(ap.SidecarExists(subnetName2))
+
+	// reads do not create missing dirs
+	_, err = ap.ReadUpgradeFile(subnetName2)
+	require.Error(err)
+	_, err = os.Stat(filepath.Join(ap.GetSubnetDir(), subnetName2))
+	require.ErrorIs(err, os.ErrNotExist)
+}
+
+func Test_trackValidatorRegistration(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+
+	registrations, err := ap.LoadValidatorRegistrations()
+	require.NoError(err)
+	require.Empty(registrations.Registrations)
+
+	registration := models.ValidatorRegistration{
+		BlockchainName: subnetName1,
+		Network:        "Fuji",
+		NodeID:         ids.GenerateTestNodeID().String(),
+		ValidationID:   ids.GenerateTestID().String(),
+		Expiry:         1700000000,
+		Balance:        1000,
+	}
+	require.NoError(ap.TrackValidatorRegistration(registration))
+	registrations, err = ap.LoadValidatorRegistrations()
+	require.NoError(err)
+	require.Equal(map[string]models.ValidatorRegistration{registration.ValidationID: registration}, registrations.Registrations)
+
+	require.NoError(ap.UntrackValidatorRegistration(ids.GenerateTestID().String()))
+	require.NoError(ap.UntrackValidatorRegistration(registration.ValidationID))
+	registrations, err = ap.LoadValidatorRegistrations()
+	require.NoError(err)
+	require.Empty(registrations.Registrations)
+}
+
+func Test_addClusterHealthSnapshot(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+
+	history, err := ap.LoadClusterHealthHistory("cluster")
+	require.NoError(err)
+	require.Empty(history.Snapshots)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	old := models.ClusterHealthSnapshot{Time: now.Add(-constants.ClusterHealthHistoryRetention - time.Hour)}
+	recent := models.ClusterHealthSnapshot{
+		Time:  now.Add(-time.Hour),
+		Nodes: []models.NodeHealth{{CloudID: "i-1", Reachable: true}},
+	}
+	latest := models.ClusterHealthSnapshot{Time: now}
+	require.NoError(ap.AddClusterHealthSnapshot("cluster", old))
+	require.NoError(ap.AddClusterHealthSnapshot("cluster", recent))
+	require.NoError(ap.AddClusterHealthSnapshot("cluster", latest))
+	history, err = ap.LoadClusterHealthHistory("cluster")
+	require.NoError(err)
+	require.Equal([]models.ClusterHealthSnapshot{recent, latest}, history.Snapshots)
+
+	ap.ReadOnly = true
+	require.ErrorIs(ap.AddClusterHealthSnapshot("cluster", latest), ErrReadOnly)
+}
+
 func newTestApp(t *testing.T) *Avalanche {
 	tempDir := t.TempDir()
 	return &Avalanche{
@@ -283,3 +368,45 @@ func newTestApp(t *testing.T) *Avalanche {
 		Log:     logging.NoLog{},
 	}
 }
+
+func TestGetLocalNetwork(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+
+	require.Equal(models.NewLocalNetwork(), ap.GetLocalNetwork())
+
+	genesisConfig := genesis.LocalConfig
+	genesisConfig.NetworkID = 54321
+	unparsedConfig, err := genesisConfig.Unparse()
+	require.NoError(err)
+	genesisBytes, err := json.Marshal(unparsedConfig)
+	require.NoError(err)
+	require.NoError(os.WriteFile(ap.GetLocalNetworkGenesisPath(), genesisBytes, constants.WriteReadReadPerms))
+	network := ap.GetLocalNetwork()
+	require.Equal(models.Local, network.Kind)
+	require.Equal(uint32(54321), network.ID)
+	require.Equal(constants.LocalAPIEndpoint, network.Endpoint)
+
+	require.NoError(os.WriteFile(ap.GetLocalNetworkGenesisPath(), []byte("{"), constants.WriteReadReadPerms))
+	_, err = ap.GetLocalNetworkID()
+	require.Error(err)
+	require.Equal(models.NewLocalNetwork(), ap.GetLocalNetwork())
+}
+
+func TestClusterSecrets(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+
+	secrets, err := ap.LoadClusterSecrets("cluster1")
+	require.NoError(err)
+	require.Equal(models.ClusterSecrets{}, secrets)
+
+	secrets.SMTPPassword = "password"
+	require.NoError(ap.WriteClusterSecrets("cluster1", secrets))
+	info, err := os.Stat(ap.GetClusterSecretsPath("cluster1"))
+	require.NoError(err)
+	require.Equal(os.FileMode(constants.WriteReadUserOnlyPerms), info.Mode().Perm())
+	loaded, err := ap.LoadClusterSecrets("cluster1")
+	require.NoError(err)
+	require.Equal(secrets, loaded)
+}
@@ -4,6 +4,8 @@
 package application
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -47,16 +49,31 @@ func NewDownloader() Downloader {
 }
 
 func (downloader) Download(url string) ([]byte, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+	return utils.CallAPI(url, func(context.Context) ([]byte, error) {
+		// no timeout is set, as downloads of large binaries may take long
+		resp, err := http.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if err := checkHTTPStatus(resp.StatusCode); err != nil {
+			return nil, err
+		}
+		return io.ReadAll(resp.Body)
+	})
+}
+
+// checkHTTPStatus returns an error for non OK statuses. Statuses that are not
+// going to change on retry are marked as non retryable
+func checkHTTPStatus(statusCode int) error {
+	if statusCode == http.StatusOK {
+		return nil
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected http status code: %d", resp.StatusCode)
+	err := fmt.Errorf("unexpected http status code: %d", statusCode)
+	if statusCode >= 500 || statusCode == http.StatusTooManyRequests {
+		return err
 	}
-
-	return io.ReadAll(resp.Body)
+	return utils.NonRetryable(err)
 }
 
 // GetLatestPreReleaseVersion returns the latest available pre release or release version from github
@@ -148,22 +165,30 @@ func (d downloader) GetAllReleasesForRepo(org, repo, component string, kind Rele
 }
 
 func (downloader) doAPIRequest(url, token string) (io.ReadCloser, error) {
-	request, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request for %s: %w", url, err)
-	}
-	if token != "" {
-		// avoid rate limitation issues at CI
-		request.Header.Set("authorization", fmt.Sprintf("Bearer %s", token))
-	}
-	resp, err := http.DefaultClient.Do(request)
+	body, err := utils.CallAPI(url, func(ctx context.Context) ([]byte, error) {
+		request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, utils.NonRetryable(err)
+		}
+		if token != "" {
+			// avoid rate limitation issues at CI
+			request.Header.Set("authorization", fmt.Sprintf("Bearer %s", token))
+		}
+		resp, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if err := checkHTTPStatus(resp.StatusCode); err != nil {
+			return nil, err
+		}
+		// read the body while the request context is alive
+		return io.ReadAll(resp.Body)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed doing request to %s: %w", url, err)
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed doing request %s: unexpected http status code: %d", url, resp.StatusCode)
-	}
-	return resp.Body, nil
+	return io.NopCloser(bytes.NewReader(body)), nil
 }
 
 func (d downloader) getLatestReleaseVersion(org, repo string) (string, error) {
@@ -0,0 +1,76 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+)
+
+// Backups are encrypted with the age file format (https://age-encryption.org/v1), using
+// a passphrase (scrypt) recipient, so they can also be decrypted with the age tool.
+
+const (
+	// scrypt work factor used to encrypt, as log2(N)
+	ageScryptWorkFactor = 18
+	// max scrypt work factor accepted when decrypting
+	ageMaxScryptWorkFactor = 22
+)
+
+var (
+	ErrIncorrectPassphrase = errors.New("incorrect passphrase")
+	errInvalidAgeFile      = errors.New("invalid or corrupted backup file")
+)
+
+// newEncryptWriter returns a writer that encrypts into [w] with [passphrase]. The
+// encryption is only complete once the writer is closed
+func newEncryptWriter(w io.Writer, passphrase string) (io.WriteCloser, error) {
+	return newEncryptWriterWithWorkFactor(w, passphrase, ageScryptWorkFactor)
+}
+
+func newEncryptWriterWithWorkFactor(w io.Writer, passphrase string, workFactor int) (io.WriteCloser, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase can't be empty")
+	}
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	recipient.SetWorkFactor(workFactor)
+	return age.Encrypt(w, recipient)
+}
+
+// newDecryptReader returns a reader of the age file at [r] decrypted with [passphrase].
+// Corrupted content is reported by the reader as errInvalidAgeFile
+func newDecryptReader(r io.Reader, passphrase string) (io.Reader, error) {
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	identity.SetMaxWorkFactor(ageMaxScryptWorkFactor)
+	plaintextReader, err := age.Decrypt(r, identity)
+	if err != nil {
+		var noMatchErr *age.NoIdentityMatchError
+		if errors.As(err, &noMatchErr) {
+			return nil, ErrIncorrectPassphrase
+		}
+		return nil, fmt.Errorf("%w: %w", errInvalidAgeFile, err)
+	}
+	return ageReader{r: plaintextReader}, nil
+}
+
+// ageReader wraps the decryption errors found while reading as errInvalidAgeFile
+type ageReader struct {
+	r io.Reader
+}
+
+func (r ageReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("%w: %w", errInvalidAgeFile, err)
+	}
+	return n, err
+}
@@ -0,0 +1,492 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backup
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	KeysCategory     = "keys"
+	SidecarsCategory = "sidecars"
+	ClustersCategory = "clusters"
+	NetworksCategory = "networks"
+
+	manifestVersion  = 1
+	manifestFileName = "manifest.json"
+)
+
+// Categories lists the kinds of CLI state a backup can contain
+var Categories = []string{KeysCategory, SidecarsCategory, ClustersCategory, NetworksCategory}
+
+// categoryPaths maps each category to the files and dirs it covers, relative to the CLI base dir
+var categoryPaths = map[string][]string{
+	KeysCategory:     {constants.KeyDir},
+	SidecarsCategory: {constants.SubnetDir},
+	ClustersCategory: {constants.NodesDir},
+	NetworksCategory: {constants.SnapshotsDirName, constants.DevnetsConfigFileName},
+}
+
+// Manifest describes the content of a backup, and is used to check its integrity
+// before restoring it
+type Manifest struct {
+	Version    int            `json:"version"`
+	CLIVersion string         `json:"cliVersion"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	Categories []string       `json:"categories"`
+	Files      []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	// slash separated path relative to the CLI base dir
+	Path     string      `json:"path"`
+	Category string      `json:"category"`
+	Mode     fs.FileMode `json:"mode"`
+	Size     int64       `json:"size"`
+	SHA256   string      `json:"sha256"`
+}
+
+// RestoreResult summarizes a restore
+type RestoreResult struct {
+	Manifest  Manifest
+	Restored  []string
+	Unchanged []string
+}
+
+// archiveFile is the size and checksum of a file read from a backup archive
+type archiveFile struct {
+	size   int64
+	sha256 string
+}
+
+// Create writes into [outputPath] an encrypted backup of the [categories] of the CLI state
+// found at [baseDir]. Files are streamed from disk into the encrypted archive
+func Create(
+	baseDir string,
+	outputPath string,
+	passphrase string,
+	cliVersion string,
+	categories []string,
+) (*Manifest, error) {
+	if err := checkCategories(categories, Categories); err != nil {
+		return nil, err
+	}
+	manifest := Manifest{
+		Version:    manifestVersion,
+		CLIVersion: cliVersion,
+		CreatedAt:  time.Now().UTC(),
+		Categories: categories,
+		Files:      []ManifestFile{},
+	}
+	for _, category := range categories {
+		for _, categoryPath := range categoryPaths[category] {
+			root := filepath.Join(baseDir, categoryPath)
+			if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			err := filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				// only regular files are backed up. dirs are recreated from file paths
+				if !d.Type().IsRegular() {
+					return nil
+				}
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				digest, err := fileDigest(filePath)
+				if err != nil {
+					return err
+				}
+				relPath, err := filepath.Rel(baseDir, filePath)
+				if err != nil {
+					return err
+				}
+				manifest.Files = append(manifest.Files, ManifestFile{
+					Path:     filepath.ToSlash(relPath),
+					Category: category,
+					Mode:     info.Mode().Perm(),
+					Size:     digest.size,
+					SHA256:   digest.sha256,
+				})
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failure reading %s: %w", root, err)
+			}
+		}
+	}
+	// the backup is written to a temp file, so a failure does not leave a partial backup
+	// on [outputPath]
+	out, err := os.CreateTemp(filepath.Dir(outputPath), filepath.Base(outputPath)+".tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+	if err := writeBackup(out, passphrase, baseDir, manifest); err != nil {
+		return nil, err
+	}
+	if err := out.Chmod(constants.WriteReadUserOnlyPerms); err != nil {
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(out.Name(), outputPath); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// ReadManifest decrypts the backup at [inputPath] with [passphrase], checks its
+// integrity, and returns its manifest
+func ReadManifest(inputPath string, passphrase string) (*Manifest, error) {
+	return readBackup(inputPath, passphrase, "", nil)
+}
+
+// Restore restores the [categories] of the backup at [inputPath] into the CLI base dir
+// [baseDir]. All categories in the backup are restored if [categories] is empty.
+// The backup integrity is fully checked before writing anything: files are extracted
+// into a staging dir, and only moved into place after the check. Existing files
+// with a different content are only overwritten if [force] is set
+func Restore(
+	baseDir string,
+	inputPath string,
+	passphrase string,
+	categories []string,
+	force bool,
+) (*RestoreResult, error) {
+	var (
+		result    *RestoreResult
+		toRestore []ManifestFile
+	)
+	selectFiles := func(manifest *Manifest) (map[string]bool, error) {
+		if len(categories) == 0 {
+			categories = manifest.Categories
+		}
+		if err := checkCategories(categories, manifest.Categories); err != nil {
+			return nil, err
+		}
+		result = &RestoreResult{
+			Manifest:  *manifest,
+			Restored:  []string{},
+			Unchanged: []string{},
+		}
+		conflicts := []string{}
+		selected := map[string]bool{}
+		for _, file := range manifest.Files {
+			if !slices.Contains(categories, file.Category) {
+				continue
+			}
+			digest, err := fileDigest(filepath.Join(baseDir, filepath.FromSlash(file.Path)))
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+				toRestore = append(toRestore, file)
+			case err != nil:
+				return nil, err
+			case digest.sha256 == file.SHA256:
+				result.Unchanged = append(result.Unchanged, file.Path)
+				continue
+			default:
+				conflicts = append(conflicts, file.Path)
+				toRestore = append(toRestore, file)
+			}
+			selected[file.Path] = true
+		}
+		if len(conflicts) > 0 && !force {
+			return nil, fmt.Errorf(
+				"%d files to restore already exist with a different content (%s). use --force to overwrite them",
+				len(conflicts),
+				strings.Join(conflicts, ", "),
+			)
+		}
+		return selected, nil
+	}
+	stagingDir, err := os.MkdirTemp(baseDir, ".backup-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(stagingDir)
+	if _, err := readBackup(inputPath, passphrase, stagingDir, selectFiles); err != nil {
+		return nil, err
+	}
+	for _, file := range toRestore {
+		filePath := filepath.Join(baseDir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(filePath), constants.DefaultPerms755); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(filepath.Join(stagingDir, filepath.FromSlash(file.Path)), filePath); err != nil {
+			return nil, err
+		}
+		if err := os.Chmod(filePath, file.Mode.Perm()); err != nil {
+			return nil, err
+		}
+		result.Restored = append(result.Restored, file.Path)
+	}
+	return result, nil
+}
+
+// writeBackup streams into [w] the tar archive of [manifest] and the files it lists
+// from [baseDir], encrypted with [passphrase]
+func writeBackup(w io.Writer, passphrase string, baseDir string, manifest Manifest) error {
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	wc, err := newEncryptWriter(w, passphrase)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(wc)
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    manifestFileName,
+		Mode:    constants.WriteReadUserOnlyPerms,
+		Size:    int64(len(manifestBytes)),
+		ModTime: manifest.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestBytes); err != nil {
+		return err
+	}
+	for _, file := range manifest.Files {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    file.Path,
+			Mode:    int64(file.Mode),
+			Size:    file.Size,
+			ModTime: manifest.CreatedAt,
+		}); err != nil {
+			return err
+		}
+		if err := copyFile(tw, filepath.Join(baseDir, filepath.FromSlash(file.Path)), file); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return wc.Close()
+}
+
+// copyFile writes into [w] the content of [filePath], checking it still matches [file]
+func copyFile(w io.Writer, filePath string, file ManifestFile) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, hash), io.LimitReader(f, file.Size+1))
+	if err != nil && !errors.Is(err, tar.ErrWriteTooLong) {
+		return err
+	}
+	if n != file.Size || hex.EncodeToString(hash.Sum(nil)) != file.SHA256 {
+		return fmt.Errorf("file %s changed while creating the backup", file.Path)
+	}
+	return nil
+}
+
+// readBackup streams the backup at [inputPath], decrypting it with [passphrase], and
+// checks its integrity. [selectFiles] is called with the manifest before any file is
+// read, and returns the files to extract into [stagingDir]
+func readBackup(
+	inputPath string,
+	passphrase string,
+	stagingDir string,
+	selectFiles func(*Manifest) (map[string]bool, error),
+) (*Manifest, error) {
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := newDecryptReader(f, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(r)
+	manifest, err := readManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkManifestPaths(manifest); err != nil {
+		return nil, fmt.Errorf("backup integrity check failed: %w", err)
+	}
+	selected := map[string]bool{}
+	if selectFiles != nil {
+		if selected, err = selectFiles(manifest); err != nil {
+			return nil, err
+		}
+	}
+	files := map[string]archiveFile{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup archive: %w", err)
+		}
+		stagedPath := ""
+		if selected[header.Name] {
+			stagedPath = filepath.Join(stagingDir, filepath.FromSlash(header.Name))
+		}
+		archived, err := extractFile(tr, stagedPath)
+		if err != nil {
+			return nil, err
+		}
+		files[header.Name] = archived
+	}
+	if err := checkIntegrity(manifest, files); err != nil {
+		return nil, fmt.Errorf("backup integrity check failed: %w", err)
+	}
+	return manifest, nil
+}
+
+// extractFile reads the current entry of [tr], writing it into [stagedPath] if given,
+// and returns its size and checksum
+func extractFile(tr *tar.Reader, stagedPath string) (archiveFile, error) {
+	var dst io.Writer = io.Discard
+	if stagedPath != "" {
+		if err := os.MkdirAll(filepath.Dir(stagedPath), constants.DefaultPerms755); err != nil {
+			return archiveFile{}, err
+		}
+		staged, err := os.OpenFile(stagedPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, constants.WriteReadUserOnlyPerms)
+		if err != nil {
+			return archiveFile{}, err
+		}
+		defer staged.Close()
+		dst = staged
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(dst, hash), tr)
+	if err != nil {
+		return archiveFile{}, fmt.Errorf("invalid backup archive: %w", err)
+	}
+	return archiveFile{size: size, sha256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// readManifest reads the manifest, expected to be the first entry of [tr]
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	header, err := tr.Next()
+	if err == io.EOF {
+		return nil, fmt.Errorf("invalid backup archive: manifest not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup archive: %w", err)
+	}
+	if header.Name != manifestFileName {
+		return nil, fmt.Errorf("invalid backup archive: manifest not found")
+	}
+	manifest := &Manifest{}
+	if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	if manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+	return manifest, nil
+}
+
+// checkManifestPaths checks that the files of [manifest] are safe to be restored
+func checkManifestPaths(manifest *Manifest) error {
+	for _, file := range manifest.Files {
+		if !isCategoryPath(file.Category, file.Path) {
+			return fmt.Errorf("file %s is not part of category %s", file.Path, file.Category)
+		}
+	}
+	return nil
+}
+
+// checkIntegrity checks that [files] contains exactly the files of [manifest], and
+// that the files are safe to be restored
+func checkIntegrity(manifest *Manifest, files map[string]archiveFile) error {
+	if err := checkManifestPaths(manifest); err != nil {
+		return err
+	}
+	listed := map[string]bool{}
+	for _, file := range manifest.Files {
+		archived, ok := files[file.Path]
+		if !ok {
+			return fmt.Errorf("file %s is missing", file.Path)
+		}
+		if archived.size != file.Size || archived.sha256 != file.SHA256 {
+			return fmt.Errorf("file %s does not match its checksum", file.Path)
+		}
+		listed[file.Path] = true
+	}
+	unlisted := []string{}
+	for filePath := range files {
+		if !listed[filePath] {
+			unlisted = append(unlisted, filePath)
+		}
+	}
+	if len(unlisted) > 0 {
+		sort.Strings(unlisted)
+		return fmt.Errorf("files not listed in manifest: %s", strings.Join(unlisted, ", "))
+	}
+	return nil
+}
+
+// isCategoryPath checks that [filePath] is a clean relative path covered by [category]
+func isCategoryPath(category string, filePath string) bool {
+	if filePath != path.Clean(filePath) || path.IsAbs(filePath) || strings.HasPrefix(filePath, "../") {
+		return false
+	}
+	for _, categoryPath := range categoryPaths[category] {
+		if filePath == categoryPath || strings.HasPrefix(filePath, categoryPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func checkCategories(categories []string, available []string) error {
+	if len(categories) == 0 {
+		return fmt.Errorf("no category selected")
+	}
+	for _, category := range categories {
+		if !slices.Contains(Categories, category) {
+			return fmt.Errorf("unknown category %q. valid categories are: %s", category, strings.Join(Categories, ", "))
+		}
+		if !slices.Contains(available, category) {
+			return fmt.Errorf("category %q is not contained in the backup", category)
+		}
+	}
+	return nil
+}
+
+// fileDigest returns the size and checksum of the file at [filePath], without loading it
+// into memory
+func fileDigest(filePath string) (archiveFile, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return archiveFile{}, err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return archiveFile{}, err
+	}
+	return archiveFile{size: size, sha256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
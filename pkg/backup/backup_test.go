@@ -0,0 +1,204 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testWorkFactor = 10
+	// size of the age payload chunks
+	testChunkSize = 64 * 1024
+)
+
+func encryptForTest(t *testing.T, passphrase string, plaintext []byte) []byte {
+	out := &bytes.Buffer{}
+	wc, err := newEncryptWriterWithWorkFactor(out, passphrase, testWorkFactor)
+	require.NoError(t, err)
+	_, err = wc.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+	return out.Bytes()
+}
+
+func decryptForTest(ciphertext []byte, passphrase string) ([]byte, error) {
+	r, err := newDecryptReader(bytes.NewReader(ciphertext), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	require := require.New(t)
+	for _, size := range []int{0, 1, testChunkSize - 1, testChunkSize, testChunkSize + 1, 3 * testChunkSize} {
+		plaintext := make([]byte, size)
+		_, err := rand.Read(plaintext)
+		require.NoError(err)
+		ciphertext := encryptForTest(t, "passphrase", plaintext)
+		decrypted, err := decryptForTest(ciphertext, "passphrase")
+		require.NoError(err)
+		require.Equal(plaintext, decrypted)
+	}
+}
+
+func TestDecryptFailures(t *testing.T) {
+	require := require.New(t)
+	ciphertext := encryptForTest(t, "passphrase", []byte("secret"))
+
+	_, err := decryptForTest(ciphertext, "wrong")
+	require.ErrorIs(err, ErrIncorrectPassphrase)
+
+	tampered := bytes.Clone(ciphertext)
+	tampered[len(tampered)-1] ^= 1
+	_, err = decryptForTest(tampered, "passphrase")
+	require.ErrorIs(err, errInvalidAgeFile)
+
+	_, err = decryptForTest(ciphertext[:len(ciphertext)-1], "passphrase")
+	require.ErrorIs(err, errInvalidAgeFile)
+
+	_, err = decryptForTest([]byte("not an age file\n"), "passphrase")
+	require.ErrorIs(err, errInvalidAgeFile)
+}
+
+func TestCreateRestore(t *testing.T) {
+	require := require.New(t)
+	srcDir := t.TempDir()
+	files := map[string]string{
+		"key/mykey.pk":                     "private key",
+		"subnets/chain/sidecar.json":       "sidecar",
+		"nodes/cluster_config.json":        "clusters",
+		"devnets.json":                     "devnets",
+		"logs/cli.log":                     "not backed up",
+		"snapshots/anr-snapshot-x/db/data": "network state",
+	}
+	for filePath, content := range files {
+		fullPath := filepath.Join(srcDir, filePath)
+		require.NoError(os.MkdirAll(filepath.Dir(fullPath), 0o755))
+		require.NoError(os.WriteFile(fullPath, []byte(content), 0o600))
+	}
+	backupPath := filepath.Join(t.TempDir(), "backup.tar.age")
+	manifest, err := Create(srcDir, backupPath, "passphrase", "v1.0.0", Categories)
+	require.NoError(err)
+	require.Len(manifest.Files, 5)
+
+	_, err = Restore(t.TempDir(), backupPath, "wrong", nil, false)
+	require.ErrorIs(err, ErrIncorrectPassphrase)
+
+	// selective restore
+	dstDir := t.TempDir()
+	result, err := Restore(dstDir, backupPath, "passphrase", []string{KeysCategory}, false)
+	require.NoError(err)
+	require.Equal([]string{"key/mykey.pk"}, result.Restored)
+	content, err := os.ReadFile(filepath.Join(dstDir, "key", "mykey.pk"))
+	require.NoError(err)
+	require.Equal("private key", string(content))
+	info, err := os.Stat(filepath.Join(dstDir, "key", "mykey.pk"))
+	require.NoError(err)
+	require.Equal(os.FileMode(0o600), info.Mode().Perm())
+	require.NoFileExists(filepath.Join(dstDir, "devnets.json"))
+
+	// conflicting files need force
+	require.NoError(os.WriteFile(filepath.Join(dstDir, "key", "mykey.pk"), []byte("other key"), 0o600))
+	_, err = Restore(dstDir, backupPath, "passphrase", nil, false)
+	require.ErrorContains(err, "--force")
+	result, err = Restore(dstDir, backupPath, "passphrase", nil, true)
+	require.NoError(err)
+	require.Len(result.Restored, 5)
+	require.NoFileExists(filepath.Join(dstDir, "logs", "cli.log"))
+
+	result, err = Restore(dstDir, backupPath, "passphrase", nil, false)
+	require.NoError(err)
+	require.Empty(result.Restored)
+	require.Len(result.Unchanged, 5)
+
+	_, err = Restore(dstDir, backupPath, "passphrase", []string{"logs"}, false)
+	require.ErrorContains(err, "unknown category")
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	content := []byte("content")
+	hash := sha256.Sum256(content)
+	file := ManifestFile{Path: "key/k.pk", Category: KeysCategory, Size: int64(len(content)), SHA256: hex.EncodeToString(hash[:])}
+	type test struct {
+		name     string
+		files    []ManifestFile
+		contents map[string][]byte
+		errMsg   string
+	}
+	escaping := file
+	escaping.Path = "key/../../k.pk"
+	wrongCategory := file
+	wrongCategory.Category = SidecarsCategory
+	tests := []test{
+		{name: "valid", files: []ManifestFile{file}, contents: map[string][]byte{file.Path: content}},
+		{name: "missing file", files: []ManifestFile{file}, contents: map[string][]byte{}, errMsg: "missing"},
+		{name: "modified file", files: []ManifestFile{file}, contents: map[string][]byte{file.Path: []byte("other")}, errMsg: "checksum"},
+		{name: "unlisted file", files: []ManifestFile{file}, contents: map[string][]byte{file.Path: content, "key/x": nil}, errMsg: "not listed"},
+		{name: "path escaping base dir", files: []ManifestFile{escaping}, contents: map[string][]byte{escaping.Path: content}, errMsg: "not part of category"},
+		{name: "path out of category", files: []ManifestFile{wrongCategory}, contents: map[string][]byte{file.Path: content}, errMsg: "not part of category"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]archiveFile{}
+			for filePath, content := range tt.contents {
+				hash := sha256.Sum256(content)
+				files[filePath] = archiveFile{size: int64(len(content)), sha256: hex.EncodeToString(hash[:])}
+			}
+			err := checkIntegrity(&Manifest{Files: tt.files}, files)
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestRestoreMasksFileModes(t *testing.T) {
+	require := require.New(t)
+	srcDir := t.TempDir()
+	content := []byte("private key")
+	require.NoError(os.MkdirAll(filepath.Join(srcDir, "key"), 0o755))
+	require.NoError(os.WriteFile(filepath.Join(srcDir, "key", "mykey.pk"), content, 0o600))
+	hash := sha256.Sum256(content)
+	manifest := Manifest{
+		Version:    manifestVersion,
+		Categories: []string{KeysCategory},
+		Files: []ManifestFile{{
+			Path:     "key/mykey.pk",
+			Category: KeysCategory,
+			Mode:     fs.ModeSetuid | fs.ModeSticky | 0o640,
+			Size:     int64(len(content)),
+			SHA256:   hex.EncodeToString(hash[:]),
+		}},
+	}
+	backupPath := filepath.Join(t.TempDir(), "backup.tar.age")
+	out, err := os.Create(backupPath)
+	require.NoError(err)
+	require.NoError(writeBackup(out, "passphrase", srcDir, manifest))
+	require.NoError(out.Close())
+
+	dstDir := t.TempDir()
+	result, err := Restore(dstDir, backupPath, "passphrase", nil, false)
+	require.NoError(err)
+	require.Equal([]string{"key/mykey.pk"}, result.Restored)
+	info, err := os.Stat(filepath.Join(dstDir, "key", "mykey.pk"))
+	require.NoError(err)
+	require.Equal(fs.FileMode(0o640), info.Mode())
+	// nothing is left from the staging of the restored files
+	entries, err := os.ReadDir(dstDir)
+	require.NoError(err)
+	require.Len(entries, 1)
+}
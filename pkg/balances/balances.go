@@ -0,0 +1,164 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package balances queries the balances of a set of keys over several networks and
+// chains concurrently, and arranges them into a per network matrix
+package balances
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+const (
+	PChain = "P-Chain"
+	XChain = "X-Chain"
+	CChain = "C-Chain"
+)
+
+// Query is a balance lookup of a key on a chain of a network
+type Query struct {
+	Network string
+	Key     string
+	Chain   string
+	Token   string
+	// Decimals of the balance returned by Fetch
+	Decimals uint8
+	// AVAX is true if the balance is given in AVAX, and so can be converted to USD
+	AVAX  bool
+	Fetch func(ctx context.Context) (*big.Int, error)
+}
+
+// Result is the outcome of a Query
+type Result struct {
+	Query
+	Balance *big.Int
+	Err     error
+}
+
+// FetchAll runs [queries] concurrently, with at most [parallelism] of them at a
+// time, and returns their results in the same order
+func FetchAll(queries []Query, parallelism int) []Result {
+	results := make([]Result, len(queries))
+	sem := make(chan struct{}, max(1, parallelism))
+	wg := sync.WaitGroup{}
+	for i, query := range queries {
+		wg.Add(1)
+		go func(i int, query Query) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			ctx, cancel := utils.GetAPIContext()
+			defer cancel()
+			balance, err := query.Fetch(ctx)
+			results[i] = Result{Query: query, Balance: balance, Err: err}
+		}(i, query)
+	}
+	wg.Wait()
+	return results
+}
+
+// Column is a chain shown on a Matrix
+type Column struct {
+	Chain string
+	Token string
+}
+
+func (c Column) String() string {
+	return fmt.Sprintf("%s (%s)", c.Chain, c.Token)
+}
+
+// Row holds the balances of a key on a Matrix, indexed by column
+type Row struct {
+	Key   string
+	Cells map[Column]Result
+}
+
+// Matrix holds the balances of a set of keys on the chains of a network
+type Matrix struct {
+	Network string
+	Columns []Column
+	Rows    []Row
+}
+
+// BuildMatrices arranges [results] into one matrix per network. Networks, keys and
+// chains keep the order of their first appearance on [results]
+func BuildMatrices(results []Result) []Matrix {
+	matrices := []Matrix{}
+	matrixIndex := map[string]int{}
+	rowIndex := map[string]map[string]int{}
+	for _, result := range results {
+		i, ok := matrixIndex[result.Network]
+		if !ok {
+			i = len(matrices)
+			matrixIndex[result.Network] = i
+			rowIndex[result.Network] = map[string]int{}
+			matrices = append(matrices, Matrix{Network: result.Network})
+		}
+		matrix := &matrices[i]
+		column := Column{Chain: result.Chain, Token: result.Token}
+		if !utils.Belongs(matrix.Columns, column) {
+			matrix.Columns = append(matrix.Columns, column)
+		}
+		j, ok := rowIndex[result.Network][result.Key]
+		if !ok {
+			j = len(matrix.Rows)
+			rowIndex[result.Network][result.Key] = j
+			matrix.Rows = append(matrix.Rows, Row{Key: result.Key, Cells: map[Column]Result{}})
+		}
+		row := &matrix.Rows[j]
+		// keys with several addresses on a chain add up their balances
+		if previous, ok := row.Cells[column]; ok {
+			result = mergeResults(previous, result)
+		}
+		row.Cells[column] = result
+	}
+	return matrices
+}
+
+func mergeResults(a Result, b Result) Result {
+	if a.Err != nil {
+		return a
+	}
+	if b.Err != nil {
+		return b
+	}
+	a.Balance = new(big.Int).Add(a.Balance, b.Balance)
+	return a
+}
+
+// AVAXTotal is the sum of the AVAX balances of the row, and whether any of them
+// failed to be obtained
+func (r Row) AVAXTotal() (*big.Float, bool) {
+	total := new(big.Float)
+	complete := true
+	for _, cell := range r.Cells {
+		if !cell.AVAX {
+			continue
+		}
+		if cell.Err != nil {
+			complete = false
+			continue
+		}
+		total.Add(total, ToFloat(cell.Balance, cell.Decimals))
+	}
+	return total, complete
+}
+
+// ToFloat converts [amount], given with [decimals] decimals, into its units
+func ToFloat(amount *big.Int, decimals uint8) *big.Float {
+	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	return new(big.Float).Quo(new(big.Float).SetInt(amount), divisor)
+}
+
+// FormatBalance shows [amount], given with [decimals] decimals, with up to 4 decimals
+func FormatBalance(amount *big.Int, decimals uint8) string {
+	if amount.Sign() == 0 {
+		return "0"
+	}
+	return ToFloat(amount, decimals).Text('f', 4)
+}
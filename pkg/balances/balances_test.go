@@ -0,0 +1,77 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package balances
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fixedBalance(balance int64, err error) func(context.Context) (*big.Int, error) {
+	return func(context.Context) (*big.Int, error) {
+		if err != nil {
+			return nil, err
+		}
+		return big.NewInt(balance), nil
+	}
+}
+
+func TestBuildMatrices(t *testing.T) {
+	errUnreachable := errors.New("connection refused")
+	queries := []Query{
+		{Network: "Fuji", Key: "a", Chain: PChain, Token: "AVAX", Decimals: 9, AVAX: true, Fetch: fixedBalance(1_500_000_000, nil)},
+		{Network: "Fuji", Key: "a", Chain: PChain, Token: "AVAX", Decimals: 9, AVAX: true, Fetch: fixedBalance(500_000_000, nil)},
+		{Network: "Fuji", Key: "a", Chain: CChain, Token: "AVAX", Decimals: 18, AVAX: true, Fetch: fixedBalance(0, nil)},
+		{Network: "Fuji", Key: "b", Chain: "mychain", Token: "TKN", Decimals: 18, Fetch: fixedBalance(0, errUnreachable)},
+		{Network: "Fuji", Key: "b", Chain: PChain, Token: "AVAX", Decimals: 9, AVAX: true, Fetch: fixedBalance(3_000_000_000, nil)},
+		{Network: "Local Network", Key: "a", Chain: PChain, Token: "AVAX", Decimals: 9, AVAX: true, Fetch: fixedBalance(0, errUnreachable)},
+	}
+	results := FetchAll(queries, 2)
+	require.Len(t, results, len(queries))
+	matrices := BuildMatrices(results)
+	require.Len(t, matrices, 2)
+	fuji := matrices[0]
+	require.Equal(t, "Fuji", fuji.Network)
+	require.Equal(t, []Column{
+		{Chain: PChain, Token: "AVAX"},
+		{Chain: CChain, Token: "AVAX"},
+		{Chain: "mychain", Token: "TKN"},
+	}, fuji.Columns)
+	require.Len(t, fuji.Rows, 2)
+	pChain := fuji.Columns[0]
+	require.Equal(t, big.NewInt(2_000_000_000), fuji.Rows[0].Cells[pChain].Balance)
+	total, complete := fuji.Rows[0].AVAXTotal()
+	require.True(t, complete)
+	totalValue, _ := total.Float64()
+	require.InDelta(t, 2, totalValue, 1e-9)
+	require.ErrorIs(t, fuji.Rows[1].Cells[fuji.Columns[2]].Err, errUnreachable)
+	_, ok := fuji.Rows[1].Cells[fuji.Columns[1]]
+	require.False(t, ok)
+	_, complete = matrices[1].Rows[0].AVAXTotal()
+	require.False(t, complete)
+}
+
+func TestFormatBalance(t *testing.T) {
+	require.Equal(t, "0", FormatBalance(big.NewInt(0), 18))
+	require.Equal(t, "1.5000", FormatBalance(big.NewInt(1_500_000_000), 9))
+	oneAndAThird, _ := new(big.Int).SetString("1333333333333333333", 10)
+	require.Equal(t, "1.3333", FormatBalance(oneAndAThird, 18))
+}
+
+func TestGetAVAXPriceUSD(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"avalanche-2":{"usd":35.2}}`))
+	}))
+	defer server.Close()
+	price, err := GetAVAXPriceUSD(server.URL)
+	require.NoError(t, err)
+	require.InDelta(t, 35.2, price, 1e-9)
+	_, err = parseAVAXPrice([]byte(`{"status":{"error_code":429}}`))
+	require.Error(t, err)
+}
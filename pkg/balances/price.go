@@ -0,0 +1,32 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package balances
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+// GetAVAXPriceUSD obtains the current AVAX price in USD from the CoinGecko simple
+// price API at [url]
+func GetAVAXPriceUSD(url string) (float64, error) {
+	bs, err := utils.Download(url)
+	if err != nil {
+		return 0, err
+	}
+	return parseAVAXPrice(bs)
+}
+
+func parseAVAXPrice(bs []byte) (float64, error) {
+	prices := map[string]map[string]float64{}
+	if err := json.Unmarshal(bs, &prices); err != nil {
+		return 0, fmt.Errorf("invalid price response: %w", err)
+	}
+	price, ok := prices["avalanche-2"]["usd"]
+	if !ok || price <= 0 {
+		return 0, fmt.Errorf("price response does not contain the AVAX USD price: %s", bs)
+	}
+	return price, nil
+}
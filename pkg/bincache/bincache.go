@@ -0,0 +1,427 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package bincache stores the binaries downloaded by the CLI (avalanchego, subnet-evm,
+// icm-relayer) in a content addressed cache shared by all local networks and clusters.
+//
+// Each downloaded archive is extracted once into a dir named after its sha256, and the
+// install dirs the rest of the CLI uses link to it. Networks and clusters reference the
+// binaries they run, so that garbage collection only removes binaries nobody uses. All
+// changes to the cache are done under a file lock, so concurrent CLI runs don't race
+// downloading or extracting the same archive.
+package bincache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+const (
+	objectsDirName = "objects"
+	indexFileName  = "index.json"
+	lockFileName   = "cache.lock"
+
+	// LocalNetworkOwner references the binaries the local network runs
+	LocalNetworkOwner  = "network:local"
+	clusterOwnerPrefix = "cluster:"
+)
+
+// ClusterOwner references the binaries the local cluster [clusterName] runs
+func ClusterOwner(clusterName string) string {
+	return clusterOwnerPrefix + clusterName
+}
+
+// ClusterName returns the cluster of [owner], if it is a cluster owner
+func ClusterName(owner string) (string, bool) {
+	return strings.CutPrefix(owner, clusterOwnerPrefix)
+}
+
+// Entry is an extracted archive stored in the cache
+type Entry struct {
+	Component string    `json:"component"`
+	Version   string    `json:"version"`
+	Hash      string    `json:"hash"`
+	Size      int64     `json:"size"`
+	AddedAt   time.Time `json:"addedAt"`
+	// install paths that link to the entry
+	Links []string `json:"links"`
+	// networks and clusters that use the entry
+	Refs []string `json:"refs"`
+}
+
+type index struct {
+	Entries map[string]*Entry `json:"entries"`
+}
+
+// Cache is a binary cache stored at a dir
+type Cache struct {
+	dir string
+}
+
+// New returns the cache stored at [dir]
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// ObjectDir returns the dir holding the extracted archive of [hash]
+func (c *Cache) ObjectDir(hash string) string {
+	return filepath.Join(c.dir, objectsDirName, hash)
+}
+
+// lock takes the exclusive lock of the cache, waiting for other CLI runs to release it.
+// Returns the function to release it
+func (c *Cache) lock() (func(), error) {
+	if err := os.MkdirAll(c.dir, constants.DefaultPerms755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(c.dir, lockFileName), os.O_CREATE|os.O_RDWR, constants.WriteReadReadPerms)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failure locking binary cache: %w", err)
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
+
+func (c *Cache) loadIndex() (*index, error) {
+	idx := &index{Entries: map[string]*Entry{}}
+	bs, err := os.ReadFile(filepath.Join(c.dir, indexFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bs, idx); err != nil {
+		return nil, fmt.Errorf("invalid binary cache index: %w", err)
+	}
+	if idx.Entries == nil {
+		idx.Entries = map[string]*Entry{}
+	}
+	return idx, nil
+}
+
+func (c *Cache) saveIndex(idx *index) error {
+	bs, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	// written aside and renamed, so that an interrupted run does not leave a corrupted index
+	tmpPath := filepath.Join(c.dir, indexFileName+".tmp")
+	if err := os.WriteFile(tmpPath, bs, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(c.dir, indexFileName))
+}
+
+// findEntry returns the entry of [component] [version] whose object is present, if any
+func (c *Cache) findEntry(idx *index, component string, version string) *Entry {
+	for _, entry := range idx.Entries {
+		if entry.Component == component && entry.Version == version && utils.DirExists(c.ObjectDir(entry.Hash)) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Install makes [installDir] hold the files of [component] [version], by linking into it
+// every top level file of its cache entry. If the entry is not cached yet, its archive is
+// obtained with [fetch] and extracted with [extract] into a new entry
+func (c *Cache) Install(
+	component string,
+	version string,
+	installDir string,
+	fetch func() ([]byte, error),
+	extract func(archive []byte, dir string) error,
+) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	idx, err := c.loadIndex()
+	if err != nil {
+		return err
+	}
+	entry := c.findEntry(idx, component, version)
+	if entry == nil {
+		archive, err := fetch()
+		if err != nil {
+			return err
+		}
+		entry, err = c.addEntry(idx, component, version, archive, extract)
+		if err != nil {
+			return err
+		}
+	}
+	if err := c.link(entry, installDir); err != nil {
+		return err
+	}
+	return c.saveIndex(idx)
+}
+
+func (c *Cache) addEntry(
+	idx *index,
+	component string,
+	version string,
+	archive []byte,
+	extract func(archive []byte, dir string) error,
+) (*Entry, error) {
+	sum := sha256.Sum256(archive)
+	hash := hex.EncodeToString(sum[:])
+	objectDir := c.ObjectDir(hash)
+	if !utils.DirExists(objectDir) {
+		if err := os.MkdirAll(filepath.Dir(objectDir), constants.DefaultPerms755); err != nil {
+			return nil, err
+		}
+		// extracted aside and renamed, so that entries are never seen half extracted
+		tmpDir, err := os.MkdirTemp(filepath.Dir(objectDir), ".extract-")
+		if err != nil {
+			return nil, err
+		}
+		if err := extract(archive, tmpDir); err != nil {
+			_ = os.RemoveAll(tmpDir)
+			return nil, err
+		}
+		if err := os.Chmod(tmpDir, constants.DefaultPerms755); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(tmpDir, objectDir); err != nil {
+			_ = os.RemoveAll(tmpDir)
+			return nil, err
+		}
+	}
+	size, err := dirSize(objectDir)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := idx.Entries[hash]
+	if !ok {
+		entry = &Entry{Hash: hash, AddedAt: time.Now().UTC()}
+		idx.Entries[hash] = entry
+	}
+	entry.Component = component
+	entry.Version = version
+	entry.Size = size
+	return entry, nil
+}
+
+// link symlinks the top level files of [entry] into [installDir]
+func (c *Cache) link(entry *Entry, installDir string) error {
+	objectDir := c.ObjectDir(entry.Hash)
+	files, err := os.ReadDir(objectDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(installDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	for _, file := range files {
+		linkPath := filepath.Join(installDir, file.Name())
+		if info, err := os.Lstat(linkPath); err == nil {
+			if info.Mode()&os.ModeSymlink == 0 {
+				return fmt.Errorf("can't link cached %s %s: %s already exists", entry.Component, entry.Version, linkPath)
+			}
+			if err := os.Remove(linkPath); err != nil {
+				return err
+			}
+		}
+		if err := os.Symlink(filepath.Join(objectDir, file.Name()), linkPath); err != nil {
+			return err
+		}
+		if !utils.Belongs(entry.Links, linkPath) {
+			entry.Links = append(entry.Links, linkPath)
+		}
+	}
+	return nil
+}
+
+// Ref records that [owner] uses the cached binary at [binPath]. Binaries that are not in
+// the cache are ignored
+func (c *Cache) Ref(owner string, binPath string) error {
+	hash, ok := c.resolve(binPath)
+	if !ok {
+		return nil
+	}
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	idx, err := c.loadIndex()
+	if err != nil {
+		return err
+	}
+	entry, ok := idx.Entries[hash]
+	if !ok || utils.Belongs(entry.Refs, owner) {
+		return nil
+	}
+	entry.Refs = append(entry.Refs, owner)
+	return c.saveIndex(idx)
+}
+
+// Unref removes all the references of [owner]
+func (c *Cache) Unref(owner string) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	idx, err := c.loadIndex()
+	if err != nil {
+		return err
+	}
+	for _, entry := range idx.Entries {
+		entry.Refs = utils.RemoveFromSlice(entry.Refs, owner)
+	}
+	return c.saveIndex(idx)
+}
+
+// resolve returns the hash of the entry [binPath] is in, following links
+func (c *Cache) resolve(binPath string) (string, bool) {
+	realPath, err := filepath.EvalSymlinks(binPath)
+	if err != nil {
+		return "", false
+	}
+	objectsDir, err := filepath.EvalSymlinks(filepath.Join(c.dir, objectsDirName))
+	if err != nil {
+		return "", false
+	}
+	relPath, err := filepath.Rel(objectsDir, realPath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return "", false
+	}
+	return strings.Split(relPath, string(filepath.Separator))[0], true
+}
+
+// List returns the entries of the cache, by component and version
+func (c *Cache) List() ([]Entry, error) {
+	unlock, err := c.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	idx, err := c.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	return sortedEntries(idx), nil
+}
+
+// GCResult describes the entries removed, or to be removed, by a garbage collection
+type GCResult struct {
+	Removed []Entry
+	// bytes freed by removing the entries
+	Reclaimed int64
+	// bytes used by the entries kept
+	Kept int64
+}
+
+// GC removes the entries no network or cluster references, and their links. References
+// of owners for which [isStale] returns true are dropped first. With [dryRun], nothing is
+// changed, only reported
+func (c *Cache) GC(isStale func(owner string) bool, dryRun bool) (GCResult, error) {
+	unlock, err := c.lock()
+	if err != nil {
+		return GCResult{}, err
+	}
+	defer unlock()
+	idx, err := c.loadIndex()
+	if err != nil {
+		return GCResult{}, err
+	}
+	result := GCResult{}
+	for _, entry := range sortedEntries(idx) {
+		refs := []string{}
+		for _, owner := range entry.Refs {
+			if !isStale(owner) {
+				refs = append(refs, owner)
+			}
+		}
+		if len(refs) > 0 {
+			idx.Entries[entry.Hash].Refs = refs
+			result.Kept += entry.Size
+			continue
+		}
+		result.Removed = append(result.Removed, entry)
+		result.Reclaimed += entry.Size
+		if dryRun {
+			continue
+		}
+		if err := c.removeEntry(entry); err != nil {
+			return result, err
+		}
+		delete(idx.Entries, entry.Hash)
+	}
+	if dryRun {
+		return result, nil
+	}
+	return result, c.saveIndex(idx)
+}
+
+func (c *Cache) removeEntry(entry Entry) error {
+	objectDir := c.ObjectDir(entry.Hash)
+	for _, linkPath := range entry.Links {
+		// links replaced by other installs are not removed
+		target, err := os.Readlink(linkPath)
+		if err != nil || filepath.Dir(target) != objectDir {
+			continue
+		}
+		if err := os.Remove(linkPath); err != nil {
+			return err
+		}
+		// remove the install dir too, if the links were all it had
+		_ = os.Remove(filepath.Dir(linkPath))
+	}
+	return os.RemoveAll(objectDir)
+}
+
+func sortedEntries(idx *index) []Entry {
+	entries := make([]Entry, 0, len(idx.Entries))
+	for _, entry := range idx.Entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Component != entries[j].Component {
+			return entries[i].Component < entries[j].Component
+		}
+		return entries[i].Version < entries[j].Version
+	})
+	return entries
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
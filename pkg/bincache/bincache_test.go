@@ -0,0 +1,111 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bincache
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// extractFile writes the archive as a single binary file, under a version dir
+func extractFile(archive []byte, dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, "bin"), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "bin", "avalanchego"), archive, 0o755)
+}
+
+func TestInstall(t *testing.T) {
+	require := require.New(t)
+	cache := New(filepath.Join(t.TempDir(), "cache"))
+	installDir := filepath.Join(t.TempDir(), "avalanchego")
+	var fetches atomic.Int32
+	fetch := func() ([]byte, error) {
+		fetches.Add(1)
+		return []byte("v1"), nil
+	}
+
+	// concurrent runs download and extract only once
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(cache.Install("avalanchego", "v1", installDir, fetch, extractFile))
+		}()
+	}
+	wg.Wait()
+	require.Equal(int32(1), fetches.Load())
+	bs, err := os.ReadFile(filepath.Join(installDir, "bin", "avalanchego"))
+	require.NoError(err)
+	require.Equal([]byte("v1"), bs)
+
+	// another install dir of the same version links to the same entry
+	otherInstallDir := filepath.Join(t.TempDir(), "avalanchego")
+	require.NoError(cache.Install("avalanchego", "v1", otherInstallDir, fetch, extractFile))
+	require.Equal(int32(1), fetches.Load())
+	entries, err := cache.List()
+	require.NoError(err)
+	require.Len(entries, 1)
+	require.Equal(int64(2), entries[0].Size)
+	require.Len(entries[0].Links, 2)
+}
+
+func TestGC(t *testing.T) {
+	require := require.New(t)
+	cache := New(filepath.Join(t.TempDir(), "cache"))
+	binDir := t.TempDir()
+	for _, version := range []string{"v1", "v2"} {
+		archive := []byte(version)
+		require.NoError(cache.Install(
+			"avalanchego",
+			version,
+			filepath.Join(binDir, version),
+			func() ([]byte, error) { return archive, nil },
+			extractFile,
+		))
+	}
+	v1Bin := filepath.Join(binDir, "v1", "bin", "avalanchego")
+	v2Bin := filepath.Join(binDir, "v2", "bin", "avalanchego")
+	require.NoError(cache.Ref("network:local", v1Bin))
+	require.NoError(cache.Ref("cluster:old", v2Bin))
+	// binaries out of the cache are ignored
+	require.NoError(cache.Ref("network:local", filepath.Join(binDir, "unknown")))
+
+	neverStale := func(string) bool { return false }
+	result, err := cache.GC(neverStale, false)
+	require.NoError(err)
+	require.Empty(result.Removed)
+	require.Equal(int64(4), result.Kept)
+
+	// refs of stale owners are dropped
+	result, err = cache.GC(func(owner string) bool { return owner == "cluster:old" }, true)
+	require.NoError(err)
+	require.Len(result.Removed, 1)
+	require.Equal("v2", result.Removed[0].Version)
+	require.Equal(int64(2), result.Reclaimed)
+	require.FileExists(v2Bin)
+
+	require.NoError(cache.Unref("cluster:old"))
+	result, err = cache.GC(neverStale, false)
+	require.NoError(err)
+	require.Len(result.Removed, 1)
+	require.NoFileExists(v2Bin)
+	require.NoDirExists(filepath.Join(binDir, "v2"))
+	require.NoDirExists(cache.ObjectDir(result.Removed[0].Hash))
+	require.FileExists(v1Bin)
+
+	require.NoError(cache.Unref("network:local"))
+	result, err = cache.GC(neverStale, false)
+	require.NoError(err)
+	require.Len(result.Removed, 1)
+	entries, err := cache.List()
+	require.NoError(err)
+	require.Empty(entries)
+}
@@ -20,3 +20,21 @@ func NewInstaller() Installer {
 func (installerImpl) GetArch() (string, string) {
 	return runtime.GOARCH, runtime.GOOS
 }
+
+type remoteInstallerImpl struct {
+	goarch string
+	goos   string
+}
+
+// NewRemoteInstaller returns an installer for binaries that are run on a host with the
+// given architecture and OS, instead of the local one
+func NewRemoteInstaller(goarch string, goos string) Installer {
+	return &remoteInstallerImpl{
+		goarch: goarch,
+		goos:   goos,
+	}
+}
+
+func (i remoteInstallerImpl) GetArch() (string, string) {
+	return i.goarch, i.goos
+}
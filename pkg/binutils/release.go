@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/bincache"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -31,22 +32,28 @@ func installBinaryWithVersion(
 		return "", fmt.Errorf("unable to determine binary install URL: %w", err)
 	}
 
-	app.Log.Debug("starting download...", zap.String("download-url", installURL))
-	archive, err := app.Downloader.Download(installURL)
-	if err != nil {
-		return "", fmt.Errorf("unable to download binary: %w", err)
-	}
-
-	app.Log.Debug("download successful. installing archive...")
-	if err := InstallArchive(ext, archive, binDir); err != nil {
-		return "", err
+	fetch := func() ([]byte, error) {
+		app.Log.Debug("starting download...", zap.String("download-url", installURL))
+		archive, err := app.Downloader.Download(installURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to download binary: %w", err)
+		}
+		app.Log.Debug("download successful. installing archive...")
+		return archive, nil
 	}
-
-	if ext == zipExtension {
-		// zip contains a build subdir instead of the toplevel expected from tar.gz
-		if err := os.Rename(filepath.Join(binDir, "build"), filepath.Join(binDir, binPrefix+version)); err != nil {
-			return "", err
+	extract := func(archive []byte, dir string) error {
+		if err := InstallArchive(ext, archive, dir); err != nil {
+			return err
+		}
+		if ext == zipExtension {
+			// zip contains a build subdir instead of the toplevel expected from tar.gz
+			return os.Rename(filepath.Join(dir, "build"), filepath.Join(dir, binPrefix+version))
 		}
+		return nil
+	}
+	cache := bincache.New(app.GetBinaryCacheDir())
+	if err := cache.Install(strings.TrimSuffix(binPrefix, "-"), version, binDir, fetch, extract); err != nil {
+		return "", err
 	}
 	ux.Logger.PrintToUser(binPrefix + version + " installation successful")
 
@@ -0,0 +1,184 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package chainregistry
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Formats an EVM chain can be described with, for wallets and chain registries
+const (
+	// parameter of the EIP-3085 wallet_addEthereumChain method
+	EIP3085Format = "eip3085"
+	// entry of the ethereum-lists/chains registry, that feeds chainlist.org
+	ChainlistFormat = "chainlist"
+)
+
+// Formats lists the supported formats
+var Formats = []string{EIP3085Format, ChainlistFormat}
+
+// explorers are expected to follow the EIP-3091 URL scheme
+const eip3091Standard = "EIP3091"
+
+var nonShortNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// NativeCurrency describes the native token of a chain
+type NativeCurrency struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// Chain is the metadata wallets and chain registries need to use an EVM chain
+type Chain struct {
+	Name           string
+	ChainID        uint64
+	NativeCurrency NativeCurrency
+	RPCURLs        []string
+	// optional fields
+	ExplorerURL string
+	InfoURL     string
+	IconURL     string
+	ShortName   string
+	Faucets     []string
+}
+
+// AddEthereumChainParameter is the parameter of the EIP-3085 wallet_addEthereumChain method
+type AddEthereumChainParameter struct {
+	ChainID           string         `json:"chainId"`
+	ChainName         string         `json:"chainName"`
+	NativeCurrency    NativeCurrency `json:"nativeCurrency"`
+	RPCURLs           []string       `json:"rpcUrls"`
+	BlockExplorerURLs []string       `json:"blockExplorerUrls,omitempty"`
+	IconURLs          []string       `json:"iconUrls,omitempty"`
+}
+
+// ChainlistExplorer is a block explorer of a chainlist entry
+type ChainlistExplorer struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Standard string `json:"standard"`
+}
+
+// ChainlistEntry is a chain entry of the ethereum-lists/chains registry
+type ChainlistEntry struct {
+	Name           string              `json:"name"`
+	Chain          string              `json:"chain"`
+	Icon           string              `json:"icon,omitempty"`
+	RPC            []string            `json:"rpc"`
+	Faucets        []string            `json:"faucets"`
+	NativeCurrency NativeCurrency      `json:"nativeCurrency"`
+	InfoURL        string              `json:"infoURL"`
+	ShortName      string              `json:"shortName"`
+	ChainID        uint64              `json:"chainId"`
+	NetworkID      uint64              `json:"networkId"`
+	Explorers      []ChainlistExplorer `json:"explorers,omitempty"`
+}
+
+// EIP3085 returns the wallet_addEthereumChain parameter of the chain
+func (c Chain) EIP3085() AddEthereumChainParameter {
+	param := AddEthereumChainParameter{
+		ChainID:        fmt.Sprintf("0x%x", c.ChainID),
+		ChainName:      c.Name,
+		NativeCurrency: c.NativeCurrency,
+		RPCURLs:        c.RPCURLs,
+	}
+	if c.ExplorerURL != "" {
+		param.BlockExplorerURLs = []string{c.ExplorerURL}
+	}
+	if c.IconURL != "" {
+		param.IconURLs = []string{c.IconURL}
+	}
+	return param
+}
+
+// Chainlist returns the ethereum-lists/chains entry of the chain. On EVM L1s the
+// network ID is the chain ID
+func (c Chain) Chainlist() ChainlistEntry {
+	entry := ChainlistEntry{
+		Name:           c.Name,
+		Chain:          c.NativeCurrency.Symbol,
+		Icon:           c.IconURL,
+		RPC:            c.RPCURLs,
+		Faucets:        c.Faucets,
+		NativeCurrency: c.NativeCurrency,
+		InfoURL:        c.InfoURL,
+		ShortName:      c.GetShortName(),
+		ChainID:        c.ChainID,
+		NetworkID:      c.ChainID,
+	}
+	if entry.Faucets == nil {
+		entry.Faucets = []string{}
+	}
+	if c.ExplorerURL != "" {
+		entry.Explorers = []ChainlistExplorer{{
+			Name:     c.Name + " Explorer",
+			URL:      c.ExplorerURL,
+			Standard: eip3091Standard,
+		}}
+	}
+	return entry
+}
+
+// GetShortName returns the short name of the chain, derived from its name if not given
+func (c Chain) GetShortName() string {
+	if c.ShortName != "" {
+		return c.ShortName
+	}
+	return strings.Trim(nonShortNameChars.ReplaceAllString(strings.ToLower(c.Name), "-"), "-")
+}
+
+// Export returns the description of the chain in [format]
+func (c Chain) Export(format string) (interface{}, error) {
+	switch format {
+	case EIP3085Format:
+		return c.EIP3085(), nil
+	case ChainlistFormat:
+		return c.Chainlist(), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q, expected one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Validate returns the issues that would make wallets or registries reject the chain
+// described in [format]
+func (c Chain) Validate(format string) []string {
+	issues := []string{}
+	if c.ChainID == 0 {
+		issues = append(issues, "chain ID is not set")
+	}
+	if len(c.RPCURLs) == 0 {
+		issues = append(issues, "no RPC URL is known for the chain. Use --rpc-url to give one")
+	}
+	// EIP-3085 restricts the symbol length, and MetaMask only accepts 18 decimals
+	if symbolLen := len(c.NativeCurrency.Symbol); symbolLen < 2 || symbolLen > 6 {
+		issues = append(issues, fmt.Sprintf("native currency symbol %q must have between 2 and 6 characters", c.NativeCurrency.Symbol))
+	}
+	if c.NativeCurrency.Decimals != 18 {
+		issues = append(issues, fmt.Sprintf("native currency has %d decimals, wallets may only accept 18", c.NativeCurrency.Decimals))
+	}
+	for _, rpcURL := range c.RPCURLs {
+		if !isPublicHTTPS(rpcURL) {
+			issues = append(issues, fmt.Sprintf("RPC URL %s is not a public https URL, as wallets and registries require", rpcURL))
+		}
+	}
+	if c.ExplorerURL != "" && !isPublicHTTPS(c.ExplorerURL) {
+		issues = append(issues, fmt.Sprintf("explorer URL %s is not a public https URL", c.ExplorerURL))
+	}
+	if format == ChainlistFormat && c.InfoURL == "" {
+		issues = append(issues, "chainlist entries require an info URL. Use --info-url to give one")
+	}
+	return issues
+}
+
+func isPublicHTTPS(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "https" {
+		return false
+	}
+	host := u.Hostname()
+	return host != "localhost" && host != "127.0.0.1" && host != "::1"
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package chainregistry
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testChain() Chain {
+	return Chain{
+		Name:    "My L1",
+		ChainID: 12345,
+		NativeCurrency: NativeCurrency{
+			Name:     "My Token",
+			Symbol:   "MYT",
+			Decimals: 18,
+		},
+		RPCURLs:     []string{"https://rpc.myl1.org/ext/bc/abc/rpc"},
+		ExplorerURL: "https://explorer.myl1.org",
+		InfoURL:     "https://myl1.org",
+	}
+}
+
+func TestEIP3085(t *testing.T) {
+	require := require.New(t)
+	out, err := testChain().Export(EIP3085Format)
+	require.NoError(err)
+	bs, err := json.Marshal(out)
+	require.NoError(err)
+	require.JSONEq(`{
+		"chainId": "0x3039",
+		"chainName": "My L1",
+		"nativeCurrency": {"name": "My Token", "symbol": "MYT", "decimals": 18},
+		"rpcUrls": ["https://rpc.myl1.org/ext/bc/abc/rpc"],
+		"blockExplorerUrls": ["https://explorer.myl1.org"]
+	}`, string(bs))
+	require.Empty(testChain().Validate(EIP3085Format))
+}
+
+func TestChainlist(t *testing.T) {
+	require := require.New(t)
+	out, err := testChain().Export(ChainlistFormat)
+	require.NoError(err)
+	bs, err := json.Marshal(out)
+	require.NoError(err)
+	require.JSONEq(`{
+		"name": "My L1",
+		"chain": "MYT",
+		"rpc": ["https://rpc.myl1.org/ext/bc/abc/rpc"],
+		"faucets": [],
+		"nativeCurrency": {"name": "My Token", "symbol": "MYT", "decimals": 18},
+		"infoURL": "https://myl1.org",
+		"shortName": "my-l1",
+		"chainId": 12345,
+		"networkId": 12345,
+		"explorers": [{"name": "My L1 Explorer", "url": "https://explorer.myl1.org", "standard": "EIP3091"}]
+	}`, string(bs))
+	require.Empty(testChain().Validate(ChainlistFormat))
+
+	_, err = testChain().Export("csv")
+	require.ErrorContains(err, "unsupported format")
+}
+
+func TestValidate(t *testing.T) {
+	chain := testChain()
+	chain.RPCURLs = []string{"http://127.0.0.1:9650/ext/bc/abc/rpc"}
+	chain.InfoURL = ""
+	chain.NativeCurrency.Symbol = "M"
+	chain.NativeCurrency.Decimals = 6
+	require.Len(t, chain.Validate(EIP3085Format), 3)
+	require.Len(t, chain.Validate(ChainlistFormat), 4)
+	chain.RPCURLs = nil
+	require.Contains(t, chain.Validate(EIP3085Format), "no RPC URL is known for the chain. Use --rpc-url to give one")
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
 )
 
 var (
@@ -33,7 +35,10 @@ var (
 
 type AwsCloud struct {
 	ec2Client *ec2.Client
+	cfg       aws.Config
 	ctx       context.Context
+	// on demand prices already obtained from the price list API
+	prices map[string]float64
 }
 
 // NewAwsCloud creates an AWS cloud
@@ -62,7 +67,9 @@ func NewAwsCloud(awsProfile, region string) (*AwsCloud, error) {
 	}
 	return &AwsCloud{
 		ec2Client: ec2.NewFromConfig(cfg),
+		cfg:       cfg,
 		ctx:       ctx,
+		prices:    map[string]float64{},
 	}, nil
 }
 
@@ -71,6 +78,12 @@ func (c *AwsCloud) CreateSecurityGroup(groupName, description string) (string, e
 	createSGOutput, err := c.ec2Client.CreateSecurityGroup(c.ctx, &ec2.CreateSecurityGroupInput{
 		GroupName:   aws.String(groupName),
 		Description: aws.String(description),
+		TagSpecifications: []types.TagSpecification{
+			{
+				ResourceType: types.ResourceTypeSecurityGroup,
+				Tags:         managedTags(groupName),
+			},
+		},
 	})
 	if err != nil {
 		return "", err
@@ -228,16 +241,7 @@ func (c *AwsCloud) CreateEC2Instances(prefix string, count int, amiID, instanceT
 		TagSpecifications: []types.TagSpecification{
 			{
 				ResourceType: types.ResourceTypeInstance,
-				Tags: []types.Tag{
-					{
-						Key:   aws.String("Name"),
-						Value: aws.String(prefix),
-					},
-					{
-						Key:   aws.String("Managed-By"),
-						Value: aws.String("avalanche-cli"),
-					},
-				},
+				Tags:         managedTags(prefix),
 			},
 		},
 	})
@@ -404,16 +408,7 @@ func (c *AwsCloud) CreateEIP(prefix string) (string, string, error) {
 		TagSpecifications: []types.TagSpecification{
 			{
 				ResourceType: types.ResourceTypeElasticIp,
-				Tags: []types.Tag{
-					{
-						Key:   aws.String("Name"),
-						Value: aws.String(prefix),
-					},
-					{
-						Key:   aws.String("Managed-By"),
-						Value: aws.String("avalanche-cli"),
-					},
-				},
+				Tags:         managedTags(prefix),
 			},
 		},
 	}); err != nil {
@@ -614,7 +609,14 @@ func (c *AwsCloud) GetInstanceTypeArch(instanceType string) (string, error) {
 	if len(archOutput.InstanceTypes) == 0 {
 		return "", fmt.Errorf("no instance type found for %s", instanceType)
 	}
-	return string(archOutput.InstanceTypes[0].ProcessorInfo.SupportedArchitectures[0]), nil
+	// some instance types also support architectures avalanchego is not released for (ex: i386)
+	supportedArchs := archOutput.InstanceTypes[0].ProcessorInfo.SupportedArchitectures
+	for _, arch := range supportedArchs {
+		if utils.ArchSupported(string(arch)) {
+			return string(arch), nil
+		}
+	}
+	return "", fmt.Errorf("instance type %s architectures %v are not supported, use one of %v", instanceType, supportedArchs, utils.SupportedAvagoArch())
 }
 
 // IsInstanceTypeSupported checks if the given instance type is supported by the AWS cloud.
@@ -765,3 +767,249 @@ func (c *AwsCloud) ChangeInstanceType(instanceID, instanceType string) error {
 	}
 	return nil
 }
+
+// GetInstanceTypeVCPUs returns the default number of vCPUs of the given instance type.
+func (c *AwsCloud) GetInstanceTypeVCPUs(instanceType string) (int, error) {
+	output, err := c.ec2Client.DescribeInstanceTypes(c.ctx, &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []types.InstanceType{types.InstanceType(instanceType)},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(output.InstanceTypes) == 0 || output.InstanceTypes[0].VCpuInfo == nil {
+		return 0, fmt.Errorf("no vCPU information found for instance type %s", instanceType)
+	}
+	return int(aws.ToInt32(output.InstanceTypes[0].VCpuInfo.DefaultVCpus)), nil
+}
+
+// service quota that limits the on demand vCPUs of standard (A, C, D, H, I, M, R, T, Z) instances
+const standardVCPUQuotaCode = "L-1216C47A"
+
+// service quotas that limit the on demand vCPUs of the other instance families
+var vCPUQuotaCodes = map[string]string{
+	"f":   "L-74FC7D96",
+	"g":   "L-DB2E81BA",
+	"vt":  "L-DB2E81BA",
+	"inf": "L-1945791B",
+	"p":   "L-417A185B",
+	"x":   "L-7295265B",
+	"u":   "L-43DA4232",
+	"trn": "L-2C3B7624",
+	"dl":  "L-6E869C2A",
+	"hpc": "L-F7808C92",
+}
+
+// GetVCPUQuotaCode returns the code of the service quota that limits the on demand vCPUs
+// of [instanceType] instances
+func GetVCPUQuotaCode(instanceType string) string {
+	family := strings.Split(instanceType, ".")[0]
+	if i := strings.IndexFunc(family, func(r rune) bool { return r < 'a' || r > 'z' }); i >= 0 {
+		family = family[:i]
+	}
+	if code, ok := vCPUQuotaCodes[family]; ok {
+		return code
+	}
+	return standardVCPUQuotaCode
+}
+
+// GetVCPUQuota returns the max number of on demand vCPUs the region allows for the
+// instance family of [instanceType]
+func (c *AwsCloud) GetVCPUQuota(instanceType string) (int, error) {
+	output, err := servicequotas.NewFromConfig(c.cfg).GetServiceQuota(c.ctx, &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String("ec2"),
+		QuotaCode:   aws.String(GetVCPUQuotaCode(instanceType)),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if output.Quota == nil || output.Quota.Value == nil {
+		return 0, fmt.Errorf("no vCPU quota found for instance type %s", instanceType)
+	}
+	return int(aws.ToFloat64(output.Quota.Value)), nil
+}
+
+// GetUsedVCPUs returns the number of vCPUs used by pending or running instances in the region
+// that count against the same quota as [instanceType] instances.
+func (c *AwsCloud) GetUsedVCPUs(instanceType string) (int, error) {
+	quotaCode := GetVCPUQuotaCode(instanceType)
+	usedVCPUs := 0
+	paginator := ec2.NewDescribeInstancesPaginator(c.ec2Client, &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("instance-state-name"), Values: []string{"pending", "running"}},
+		},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(c.ctx)
+		if err != nil {
+			return 0, err
+		}
+		for _, reservation := range output.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.CpuOptions != nil && GetVCPUQuotaCode(string(instance.InstanceType)) == quotaCode {
+					usedVCPUs += int(aws.ToInt32(instance.CpuOptions.CoreCount) * aws.ToInt32(instance.CpuOptions.ThreadsPerCore))
+				}
+			}
+		}
+	}
+	return usedVCPUs, nil
+}
+
+// GetElasticIPUsage returns the number of elastic IPs allocated in the region, and the max number allowed.
+func (c *AwsCloud) GetElasticIPUsage() (int, int, error) {
+	addresses, err := c.ec2Client.DescribeAddresses(c.ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return 0, 0, err
+	}
+	attributes, err := c.ec2Client.DescribeAccountAttributes(c.ctx, &ec2.DescribeAccountAttributesInput{
+		AttributeNames: []types.AccountAttributeName{types.AccountAttributeName("vpc-max-elastic-ips")},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, attribute := range attributes.AccountAttributes {
+		for _, value := range attribute.AttributeValues {
+			limit, err := strconv.Atoi(aws.ToString(value.AttributeValue))
+			if err != nil {
+				return 0, 0, err
+			}
+			return len(addresses.Addresses), limit, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("unable to get elastic IP limit")
+}
+
+// CheckImageAvailable checks that the given AMI exists and is in available state.
+func (c *AwsCloud) CheckImageAvailable(amiID string) (bool, error) {
+	images, err := c.ec2Client.DescribeImages(c.ctx, &ec2.DescribeImagesInput{
+		ImageIds: []string{amiID},
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "InvalidAMIID") {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(images.Images) > 0 && images.Images[0].State == types.ImageStateAvailable, nil
+}
+
+// managedTags returns the tags set on every resource created by the CLI, so that
+// they can be found later by ListManagedResources
+func managedTags(name string) []types.Tag {
+	tags := []types.Tag{
+		{
+			Key:   aws.String("Name"),
+			Value: aws.String(name),
+		},
+		{
+			Key:   aws.String("Managed-By"),
+			Value: aws.String(constants.AvalancheCLIManagedByTag),
+		},
+	}
+	if owner, err := utils.GetCloudResourceOwner(); err == nil {
+		tags = append(tags, types.Tag{
+			Key:   aws.String(constants.AvalancheCLIOwnerTag),
+			Value: aws.String(owner),
+		})
+	}
+	return tags
+}
+
+// ListManagedResources returns the instances, elastic IPs and security groups in the region
+// that were created by the CLI
+func (c *AwsCloud) ListManagedResources(region string) ([]models.CloudResource, error) {
+	resources := []models.CloudResource{}
+	managedByFilter := types.Filter{Name: aws.String("tag:Managed-By"), Values: []string{constants.AvalancheCLIManagedByTag}}
+	paginator := ec2.NewDescribeInstancesPaginator(c.ec2Client, &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			managedByFilter,
+			{Name: aws.String("instance-state-name"), Values: []string{"pending", "running", "stopping", "stopped"}},
+		},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(c.ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range output.Reservations {
+			for _, instance := range reservation.Instances {
+				resource := models.CloudResource{
+					Type:     models.CloudResourceInstance,
+					ID:       aws.ToString(instance.InstanceId),
+					Name:     getTag(instance.Tags, "Name"),
+					Owner:    getTag(instance.Tags, constants.AvalancheCLIOwnerTag),
+					Region:   region,
+					PublicIP: aws.ToString(instance.PublicIpAddress),
+				}
+				if instance.State != nil {
+					resource.State = string(instance.State.Name)
+				}
+				resources = append(resources, resource)
+			}
+		}
+	}
+	addresses, err := c.ec2Client.DescribeAddresses(c.ctx, &ec2.DescribeAddressesInput{
+		Filters: []types.Filter{managedByFilter},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range addresses.Addresses {
+		state := "unassociated"
+		if address.InstanceId != nil {
+			state = "associated to " + aws.ToString(address.InstanceId)
+		}
+		resources = append(resources, models.CloudResource{
+			Type:     models.CloudResourceStaticIP,
+			ID:       aws.ToString(address.AllocationId),
+			Name:     getTag(address.Tags, "Name"),
+			Owner:    getTag(address.Tags, constants.AvalancheCLIOwnerTag),
+			Region:   region,
+			PublicIP: aws.ToString(address.PublicIp),
+			State:    state,
+		})
+	}
+	sgs, err := c.ec2Client.DescribeSecurityGroups(c.ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []types.Filter{managedByFilter},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, sg := range sgs.SecurityGroups {
+		resources = append(resources, models.CloudResource{
+			Type:   models.CloudResourceSecurityGroup,
+			ID:     aws.ToString(sg.GroupId),
+			Name:   aws.ToString(sg.GroupName),
+			Owner:  getTag(sg.Tags, constants.AvalancheCLIOwnerTag),
+			Region: region,
+		})
+	}
+	return resources, nil
+}
+
+// DeleteManagedResource deletes a resource previously listed by ListManagedResources
+func (c *AwsCloud) DeleteManagedResource(resource models.CloudResource) error {
+	switch resource.Type {
+	case models.CloudResourceInstance:
+		return c.DestroyInstance(resource.ID, "", false)
+	case models.CloudResourceStaticIP:
+		_, err := c.ec2Client.ReleaseAddress(c.ctx, &ec2.ReleaseAddressInput{
+			AllocationId: aws.String(resource.ID),
+		})
+		return err
+	case models.CloudResourceSecurityGroup:
+		_, err := c.ec2Client.DeleteSecurityGroup(c.ctx, &ec2.DeleteSecurityGroupInput{
+			GroupId: aws.String(resource.ID),
+		})
+		return err
+	}
+	return fmt.Errorf("unsupported resource type %s", resource.Type)
+}
+
+func getTag(tags []types.Tag, key string) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == key {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
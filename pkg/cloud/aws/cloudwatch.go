@@ -0,0 +1,57 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aws
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const cloudWatchAPIVersion = "2010-08-01"
+
+// PutCloudWatchDashboard creates or replaces the CloudWatch dashboard [name] of the cloud region
+// with [body], and returns its console URL
+func (c *AwsCloud) PutCloudWatchDashboard(name string, body []byte) (string, error) {
+	params := url.Values{}
+	params.Set("Action", "PutDashboard")
+	params.Set("Version", cloudWatchAPIVersion)
+	params.Set("DashboardName", name)
+	params.Set("DashboardBody", string(body))
+	payload := params.Encode()
+	endpoint := fmt.Sprintf("https://monitoring.%s.amazonaws.com/", c.cfg.Region)
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, endpoint, strings.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	credentials, err := c.cfg.Credentials.Retrieve(c.ctx)
+	if err != nil {
+		return "", err
+	}
+	payloadHash := sha256.Sum256([]byte(payload))
+	if err := v4.NewSigner().SignHTTP(c.ctx, credentials, req, hex.EncodeToString(payloadHash[:]), "monitoring", c.cfg.Region, time.Now()); err != nil {
+		return "", err
+	}
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cloudwatch PutDashboard failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return fmt.Sprintf("https://%s.console.aws.amazon.com/cloudwatch/home?region=%s#dashboards/dashboard/%s", c.cfg.Region, c.cfg.Region, url.PathEscape(name)), nil
+}
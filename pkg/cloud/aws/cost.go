@@ -0,0 +1,281 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	pricingtypes "github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	hoursPerMonth = 730
+	// public IPv4 addresses have the same hourly price in all regions
+	publicIPv4HourPrice = 0.005
+	// the price list API is only served from us-east-1, for all regions
+	pricingAPIRegion = "us-east-1"
+	// approximate us-east-1 on demand prices in USD, used if the price list can't be queried
+	gp3GBMonthPrice = 0.08
+	io1GBMonthPrice = 0.125
+)
+
+// approximate us-east-1 on demand hourly prices in USD for the instance types
+// usually selected for avalanche nodes, used if the price list can't be queried
+var instanceHourPrice = map[string]float64{
+	"t3.large":     0.0832,
+	"t3.xlarge":    0.1664,
+	"t3.2xlarge":   0.3328,
+	"t3a.xlarge":   0.1504,
+	"t3a.2xlarge":  0.3008,
+	"c5.xlarge":    0.17,
+	"c5.2xlarge":   0.34,
+	"c5.4xlarge":   0.68,
+	"c5n.2xlarge":  0.432,
+	"c6i.2xlarge":  0.34,
+	"c6a.2xlarge":  0.306,
+	"c7g.2xlarge":  0.29,
+	"m5.2xlarge":   0.384,
+	"m6i.2xlarge":  0.384,
+	"r5.2xlarge":   0.504,
+	"c5.9xlarge":   1.53,
+	"m5.4xlarge":   0.768,
+	"c6g.2xlarge":  0.272,
+	"m6g.2xlarge":  0.308,
+	"t4g.2xlarge":  0.2688,
+	"c7i.2xlarge":  0.357,
+	"m7i.2xlarge":  0.4032,
+	"c6in.2xlarge": 0.4536,
+}
+
+// Prices are the on demand prices in USD used to estimate the cost of an instance
+type Prices struct {
+	InstanceHour  float64
+	VolumeGBMonth float64
+	PublicIPHour  float64
+	// true if the prices come from the price list of the region, false if they are
+	// approximate us-east-1 prices
+	Live bool
+}
+
+// GetPrices returns the on demand prices at the region of the client of [instanceType] instances
+// with [volumeType] volumes, as given by the AWS price list API. If the price list can't be
+// queried, approximate us-east-1 prices are returned instead. Returns false if there is no
+// price information for the instance type
+func (c *AwsCloud) GetPrices(instanceType string, volumeType string) (Prices, bool) {
+	instanceHour, instanceLive, known := c.getInstanceHourPrice(instanceType)
+	volumeGBMonth, volumeLive := c.getVolumeGBMonthPrice(volumeType)
+	return Prices{
+		InstanceHour:  instanceHour,
+		VolumeGBMonth: volumeGBMonth,
+		PublicIPHour:  publicIPv4HourPrice,
+		Live:          instanceLive && volumeLive,
+	}, known
+}
+
+// getInstanceHourPrice returns the on demand hourly price of [instanceType] at the region of
+// the client, and true if it comes from the price list. If the price list can't be queried,
+// the approximate us-east-1 price is returned instead. The last value is false if there is
+// no price information for the instance type
+func (c *AwsCloud) getInstanceHourPrice(instanceType string) (float64, bool, bool) {
+	instanceHour, err := c.getOnDemandPrice(map[string]string{
+		"regionCode":      c.cfg.Region,
+		"instanceType":    instanceType,
+		"operatingSystem": "Linux",
+		"tenancy":         "Shared",
+		"preInstalledSw":  "NA",
+		"capacitystatus":  "Used",
+		"licenseModel":    "No License required",
+	})
+	if err == nil {
+		return instanceHour, true, true
+	}
+	instanceHour, ok := instanceHourPrice[instanceType]
+	return instanceHour, false, ok
+}
+
+// getVolumeGBMonthPrice returns the monthly price per GB of [volumeType] volumes at the region
+// of the client, and true if it comes from the price list. If the price list can't be queried,
+// the approximate us-east-1 price is returned instead
+func (c *AwsCloud) getVolumeGBMonthPrice(volumeType string) (float64, bool) {
+	volumeGBMonth, err := c.getOnDemandPrice(map[string]string{
+		"regionCode":    c.cfg.Region,
+		"productFamily": "Storage",
+		"volumeApiName": volumeType,
+	})
+	if err == nil {
+		return volumeGBMonth, true
+	}
+	return approximateVolumeGBMonthPrice(volumeType), false
+}
+
+// getOnDemandPrice returns the on demand price in USD of the EC2 product matching all [attributes]
+func (c *AwsCloud) getOnDemandPrice(attributes map[string]string) (float64, error) {
+	names := maps.Keys(attributes)
+	sort.Strings(names)
+	filters := []pricingtypes.Filter{}
+	cacheKey := ""
+	for _, name := range names {
+		cacheKey += name + "=" + attributes[name] + ";"
+		filters = append(filters, pricingtypes.Filter{
+			Type:  pricingtypes.FilterTypeTermMatch,
+			Field: aws.String(name),
+			Value: aws.String(attributes[name]),
+		})
+	}
+	if price, ok := c.prices[cacheKey]; ok {
+		return price, nil
+	}
+	client := pricing.NewFromConfig(c.cfg, func(o *pricing.Options) {
+		o.Region = pricingAPIRegion
+	})
+	output, err := client.GetProducts(c.ctx, &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonEC2"),
+		Filters:     filters,
+		MaxResults:  aws.Int32(10),
+	})
+	if err != nil {
+		return 0, err
+	}
+	price, err := parseOnDemandPrice(output.PriceList)
+	if err != nil {
+		return 0, err
+	}
+	c.prices[cacheKey] = price
+	return price, nil
+}
+
+// parseOnDemandPrice returns the on demand price in USD of the products of a price list
+// document, failing if they don't agree on it
+func parseOnDemandPrice(priceList []string) (float64, error) {
+	if len(priceList) == 0 {
+		return 0, fmt.Errorf("no product found on the price list")
+	}
+	price := -1.0
+	for _, item := range priceList {
+		var product struct {
+			Terms struct {
+				OnDemand map[string]struct {
+					PriceDimensions map[string]struct {
+						PricePerUnit map[string]string `json:"pricePerUnit"`
+					} `json:"priceDimensions"`
+				} `json:"OnDemand"`
+			} `json:"terms"`
+		}
+		if err := json.Unmarshal([]byte(item), &product); err != nil {
+			return 0, fmt.Errorf("invalid price list: %w", err)
+		}
+		for _, term := range product.Terms.OnDemand {
+			for _, dimension := range term.PriceDimensions {
+				usd, ok := dimension.PricePerUnit["USD"]
+				if !ok {
+					continue
+				}
+				itemPrice, err := strconv.ParseFloat(usd, 64)
+				if err != nil {
+					return 0, fmt.Errorf("invalid price %q on price list: %w", usd, err)
+				}
+				if price >= 0 && itemPrice != price {
+					return 0, fmt.Errorf("price list has more than one on demand price")
+				}
+				price = itemPrice
+			}
+		}
+	}
+	if price < 0 {
+		return 0, fmt.Errorf("no on demand USD price found on the price list")
+	}
+	return price, nil
+}
+
+// EstimateMonthlyCost returns the approximate monthly cost in USD of [numNodes] instances at
+// [prices], each one with a volume of [volumeSize] GB and a public IP.
+func EstimateMonthlyCost(prices Prices, volumeSize int, numNodes int) float64 {
+	nodeCost := (prices.InstanceHour+prices.PublicIPHour)*hoursPerMonth + prices.VolumeGBMonth*float64(volumeSize)
+	return nodeCost * float64(numNodes)
+}
+
+func approximateVolumeGBMonthPrice(volumeType string) float64 {
+	switch volumeType {
+	case "io1", "io2":
+		return io1GBMonthPrice
+	}
+	return gp3GBMonthPrice
+}
+
+// GetInstanceMonthlyCost returns the approximate monthly cost of the current setup of
+// instance [instanceID], at the prices of the region of the client: its instance type while
+// running, all its attached volumes, and its public IP. Stopped instances are only charged
+// for volumes and IPs
+func (c *AwsCloud) GetInstanceMonthlyCost(instanceID string) (models.CloudInstanceCost, error) {
+	instancesOutput, err := c.ec2Client.DescribeInstances(c.ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return models.CloudInstanceCost{}, err
+	}
+	if len(instancesOutput.Reservations) == 0 || len(instancesOutput.Reservations[0].Instances) == 0 {
+		return models.CloudInstanceCost{}, fmt.Errorf("instance with ID %s not found", instanceID)
+	}
+	instance := instancesOutput.Reservations[0].Instances[0]
+	volumesOutput, err := c.ec2Client.DescribeVolumes(c.ctx, &ec2.DescribeVolumesInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("attachment.instance-id"),
+				Values: []string{instanceID},
+			},
+		},
+	})
+	if err != nil {
+		return models.CloudInstanceCost{}, err
+	}
+	instanceHour, live, known := c.getInstanceHourPrice(string(instance.InstanceType))
+	volumeGBMonth := map[types.VolumeType]float64{}
+	for _, volume := range volumesOutput.Volumes {
+		if _, ok := volumeGBMonth[volume.VolumeType]; !ok {
+			price, volumeLive := c.getVolumeGBMonthPrice(string(volume.VolumeType))
+			volumeGBMonth[volume.VolumeType] = price
+			live = live && volumeLive
+		}
+	}
+	cost := instanceMonthlyCost(instance, volumesOutput.Volumes, instanceHour, volumeGBMonth)
+	cost.Known = known
+	cost.Live = live
+	return cost, nil
+}
+
+// instanceMonthlyCost returns the monthly cost of [instance] with [volumes] attached, for an
+// instance hourly price of [instanceHour] and the monthly GB prices of [volumeGBMonth] by
+// volume type
+func instanceMonthlyCost(
+	instance types.Instance,
+	volumes []types.Volume,
+	instanceHour float64,
+	volumeGBMonth map[types.VolumeType]float64,
+) models.CloudInstanceCost {
+	cost := models.CloudInstanceCost{
+		InstanceType: string(instance.InstanceType),
+		Running:      instance.State != nil && instance.State.Name == types.InstanceStateNameRunning,
+	}
+	if cost.Running {
+		cost.Compute = instanceHour * hoursPerMonth
+	}
+	if instance.PublicIpAddress != nil {
+		cost.PublicIP = publicIPv4HourPrice * hoursPerMonth
+	}
+	for _, volume := range volumes {
+		volumeSize := int(aws.ToInt32(volume.Size))
+		cost.DiskSize += volumeSize
+		cost.Storage += volumeGBMonth[volume.VolumeType] * float64(volumeSize)
+	}
+	return cost
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVCPUQuotaCode(t *testing.T) {
+	require := require.New(t)
+	require.Equal(standardVCPUQuotaCode, GetVCPUQuotaCode("c5.2xlarge"))
+	require.Equal(standardVCPUQuotaCode, GetVCPUQuotaCode("m7i.2xlarge"))
+	require.Equal(standardVCPUQuotaCode, GetVCPUQuotaCode("t4g.2xlarge"))
+	require.Equal("L-DB2E81BA", GetVCPUQuotaCode("g5.xlarge"))
+	require.Equal("L-1945791B", GetVCPUQuotaCode("inf2.xlarge"))
+	require.Equal("L-7295265B", GetVCPUQuotaCode("x2gd.large"))
+	require.Equal("L-F7808C92", GetVCPUQuotaCode("hpc6a.48xlarge"))
+}
+
+func TestParseOnDemandPrice(t *testing.T) {
+	require := require.New(t)
+	product := `{"product":{"sku":"A"},"terms":{"OnDemand":{"A.1":{"priceDimensions":{"A.1.2":{"unit":"Hrs","pricePerUnit":{"USD":"0.3400000000"}}}}}}}`
+	price, err := parseOnDemandPrice([]string{product})
+	require.NoError(err)
+	require.InDelta(0.34, price, 1e-9)
+	// products that agree on the price
+	price, err = parseOnDemandPrice([]string{product, product})
+	require.NoError(err)
+	require.InDelta(0.34, price, 1e-9)
+	other := `{"terms":{"OnDemand":{"B.1":{"priceDimensions":{"B.1.2":{"pricePerUnit":{"USD":"0.5"}}}}}}}`
+	_, err = parseOnDemandPrice([]string{product, other})
+	require.Error(err)
+	_, err = parseOnDemandPrice(nil)
+	require.Error(err)
+	_, err = parseOnDemandPrice([]string{`{"terms":{"OnDemand":{}}}`})
+	require.Error(err)
+	_, err = parseOnDemandPrice([]string{`not json`})
+	require.Error(err)
+}
+
+func TestEstimateMonthlyCost(t *testing.T) {
+	prices := Prices{InstanceHour: 0.1, VolumeGBMonth: 0.08, PublicIPHour: 0.005}
+	// (0.1 + 0.005) * 730 + 0.08 * 1000 per node
+	require.InDelta(t, 2*(76.65+80), EstimateMonthlyCost(prices, 1000, 2), 1e-6)
+}
+
+func TestInstanceMonthlyCost(t *testing.T) {
+	require := require.New(t)
+	instance := types.Instance{
+		InstanceType:    types.InstanceTypeC52xlarge,
+		State:           &types.InstanceState{Name: types.InstanceStateNameRunning},
+		PublicIpAddress: aws.String("1.2.3.4"),
+	}
+	volumes := []types.Volume{
+		{VolumeType: types.VolumeTypeGp3, Size: aws.Int32(1000)},
+		{VolumeType: types.VolumeTypeIo2, Size: aws.Int32(100)},
+	}
+	volumeGBMonth := map[types.VolumeType]float64{
+		types.VolumeTypeGp3: 0.088,
+		types.VolumeTypeIo2: 0.138,
+	}
+	cost := instanceMonthlyCost(instance, volumes, 0.384, volumeGBMonth)
+	require.Equal("c5.2xlarge", cost.InstanceType)
+	require.True(cost.Running)
+	require.Equal(1100, cost.DiskSize)
+	require.InDelta(0.384*730, cost.Compute, 1e-6)
+	require.InDelta(0.088*1000+0.138*100, cost.Storage, 1e-6)
+	require.InDelta(0.005*730, cost.PublicIP, 1e-6)
+
+	// stopped instances without public IP only pay for volumes
+	instance.State = &types.InstanceState{Name: types.InstanceStateNameStopped}
+	instance.PublicIpAddress = nil
+	cost = instanceMonthlyCost(instance, volumes, 0.384, volumeGBMonth)
+	require.False(cost.Running)
+	require.Zero(cost.Compute)
+	require.Zero(cost.PublicIP)
+	require.InDelta(0.088*1000+0.138*100, cost.Total(), 1e-6)
+}
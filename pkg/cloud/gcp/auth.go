@@ -0,0 +1,129 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"google.golang.org/api/cloudbilling/v1"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+const (
+	// credential file types, as given by the "type" field of the JSON file
+	ServiceAccountKeyCredentials   = "service_account"
+	WorkloadIdentityCredentials    = "external_account"
+	UserCredentials                = "authorized_user"
+	ImpersonatedAccountCredentials = "impersonated_service_account"
+	ExternalUserCredentials        = "external_account_authorized_user"
+)
+
+var supportedCredentialsTypes = []string{
+	ServiceAccountKeyCredentials,
+	WorkloadIdentityCredentials,
+	UserCredentials,
+	ImpersonatedAccountCredentials,
+	ExternalUserCredentials,
+}
+
+// AuthConfig selects how the CLI authenticates to GCP
+type AuthConfig struct {
+	// path to a service account key, a workload identity federation credential
+	// configuration, or user credentials. Application default credentials are
+	// used if empty
+	CredentialsPath string
+	// service account to impersonate using the credentials above, if set
+	ImpersonateServiceAccount string
+}
+
+// GetCredentialsType returns the type of the credentials JSON file at [path]
+func GetCredentialsType(path string) (string, error) {
+	credentialsBytes, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failure reading GCP credentials %s: %w", path, err)
+	}
+	credentials := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(credentialsBytes, &credentials); err != nil {
+		return "", fmt.Errorf("invalid GCP credentials %s: %w", path, err)
+	}
+	if !utils.Belongs(supportedCredentialsTypes, credentials.Type) {
+		return "", fmt.Errorf("unsupported GCP credentials type %q on %s", credentials.Type, path)
+	}
+	return credentials.Type, nil
+}
+
+// Describe returns a user friendly description of the authentication method
+func (a AuthConfig) Describe() string {
+	desc := "application default credentials"
+	if a.CredentialsPath != "" {
+		desc = fmt.Sprintf("credentials %s", a.CredentialsPath)
+		if credentialsType, err := GetCredentialsType(a.CredentialsPath); err == nil {
+			switch credentialsType {
+			case ServiceAccountKeyCredentials:
+				desc = fmt.Sprintf("service account key %s", a.CredentialsPath)
+			case WorkloadIdentityCredentials, ExternalUserCredentials:
+				desc = fmt.Sprintf("workload identity federation config %s", a.CredentialsPath)
+			}
+		}
+	}
+	if a.ImpersonateServiceAccount != "" {
+		desc = fmt.Sprintf("%s impersonating %s", desc, a.ImpersonateServiceAccount)
+	}
+	return desc
+}
+
+// NewComputeService creates a compute engine client authenticated as given by [auth].
+// Credentials files of any supported type are accepted, so workload identity federation
+// configs can be used in place of exported service account keys
+func NewComputeService(ctx context.Context, auth AuthConfig) (*compute.Service, error) {
+	options, err := getClientOptions(ctx, auth, compute.ComputeScope)
+	if err != nil {
+		return nil, err
+	}
+	return compute.NewService(ctx, options...)
+}
+
+// NewBillingService creates a cloud billing catalog client authenticated as given by [auth]
+func NewBillingService(ctx context.Context, auth AuthConfig) (*cloudbilling.APIService, error) {
+	options, err := getClientOptions(ctx, auth, cloudbilling.CloudBillingReadonlyScope)
+	if err != nil {
+		return nil, err
+	}
+	return cloudbilling.NewService(ctx, options...)
+}
+
+// getClientOptions returns the options to authenticate a client for [scope] as given by [auth]
+func getClientOptions(ctx context.Context, auth AuthConfig, scope string) ([]option.ClientOption, error) {
+	baseOptions := []option.ClientOption{}
+	if auth.CredentialsPath != "" {
+		if _, err := GetCredentialsType(auth.CredentialsPath); err != nil {
+			return nil, err
+		}
+		baseOptions = append(baseOptions, option.WithCredentialsFile(auth.CredentialsPath))
+	}
+	if auth.ImpersonateServiceAccount == "" {
+		return append(baseOptions, option.WithScopes(scope)), nil
+	}
+	tokenSource, err := impersonate.CredentialsTokenSource(
+		ctx,
+		impersonate.CredentialsConfig{
+			TargetPrincipal: auth.ImpersonateServiceAccount,
+			Scopes:          []string{scope},
+		},
+		baseOptions...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failure impersonating GCP service account %s: %w", auth.ImpersonateServiceAccount, err)
+	}
+	return []option.ClientOption{option.WithTokenSource(tokenSource)}, nil
+}
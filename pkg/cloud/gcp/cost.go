@@ -0,0 +1,205 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"golang.org/x/exp/slices"
+	"google.golang.org/api/cloudbilling/v1"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	hoursPerMonth = 730
+	// external IPv4 addresses have the same hourly price in all regions
+	externalIPv4HourPrice = 0.005
+	// billing catalog ID of the Compute Engine service
+	computeEngineServiceName = "services/6F81-5844-456A"
+	// approximate us-central1 on demand prices in USD, used if the billing catalog can't be queried
+	standardDiskGBMonthPrice = 0.04
+)
+
+// approximate us-central1 on demand hourly prices in USD for the machine types
+// usually selected for avalanche nodes, used if the billing catalog can't be queried
+var machineHourPrice = map[string]float64{
+	"e2-standard-4":  0.134,
+	"e2-standard-8":  0.268,
+	"e2-standard-16": 0.536,
+	"n2-standard-4":  0.1942,
+	"n2-standard-8":  0.3885,
+	"n2-standard-16": 0.7769,
+	"c3-highcpu-8":   0.3424,
+	"c3-standard-8":  0.4176,
+	"n2d-standard-8": 0.338,
+	"c2-standard-8":  0.4176,
+}
+
+// prefixes of the billing catalog descriptions of the vCPU and memory SKUs of each machine family
+var machineFamilySkuPrefixes = map[string]string{
+	"e2":  "E2 Instance",
+	"n1":  "N1 Predefined Instance",
+	"n2":  "N2 Instance",
+	"n2d": "N2D AMD Instance",
+	"n4":  "N4 Instance",
+	"c2":  "Compute optimized",
+	"c2d": "C2D AMD Instance",
+	"c3":  "C3 Instance",
+	"c3d": "C3D Instance",
+	"c4":  "C4 Instance",
+	"t2a": "T2A Arm Instance",
+	"t2d": "T2D AMD Instance",
+}
+
+// Prices are the on demand prices in USD used to estimate the cost of an instance
+type Prices struct {
+	MachineHour    float64
+	DiskGBMonth    float64
+	ExternalIPHour float64
+	// true if the prices come from the billing catalog for the region, false if they are
+	// approximate us-central1 prices
+	Live bool
+}
+
+// PriceCatalog holds the Compute Engine SKUs of the cloud billing catalog
+type PriceCatalog struct {
+	skus []*cloudbilling.Sku
+}
+
+// NewPriceCatalog loads the Compute Engine SKUs from the cloud billing catalog
+func NewPriceCatalog(ctx context.Context, billingService *cloudbilling.APIService) (*PriceCatalog, error) {
+	catalog := &PriceCatalog{}
+	if err := billingService.Services.Skus.List(computeEngineServiceName).CurrencyCode("USD").Pages(
+		ctx,
+		func(response *cloudbilling.ListSkusResponse) error {
+			catalog.skus = append(catalog.skus, response.Skus...)
+			return nil
+		},
+	); err != nil {
+		return nil, fmt.Errorf("failure querying GCP billing catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// GetPrices returns the on demand prices at [region] of [machineType] instances, that have
+// [vCPUs] and [memoryGB], with standard persistent disks, as given by [catalog]. If [catalog]
+// is nil or has no prices for them, approximate us-central1 prices are returned instead.
+// Returns false if there is no price information for the machine type
+func GetPrices(catalog *PriceCatalog, region string, machineType string, vCPUs int, memoryGB float64) (Prices, bool) {
+	if catalog != nil {
+		if prices, err := catalog.getPrices(region, machineType, vCPUs, memoryGB); err == nil {
+			return prices, true
+		}
+	}
+	machineHour, ok := machineHourPrice[machineType]
+	return Prices{
+		MachineHour:    machineHour,
+		DiskGBMonth:    standardDiskGBMonthPrice,
+		ExternalIPHour: externalIPv4HourPrice,
+	}, ok
+}
+
+func (catalog *PriceCatalog) getPrices(region string, machineType string, vCPUs int, memoryGB float64) (Prices, error) {
+	family := strings.Split(machineType, "-")[0]
+	skuPrefix, ok := machineFamilySkuPrefixes[family]
+	if !ok {
+		return Prices{}, fmt.Errorf("machine family %s is not supported", family)
+	}
+	vCPUHour, err := catalog.findPrice(region, skuPrefix+" Core running in")
+	if err != nil {
+		return Prices{}, err
+	}
+	memoryGBHour, err := catalog.findPrice(region, skuPrefix+" Ram running in")
+	if err != nil {
+		return Prices{}, err
+	}
+	diskGBMonth, err := catalog.findPrice(region, "Storage PD Capacity")
+	if err != nil {
+		return Prices{}, err
+	}
+	return Prices{
+		MachineHour:    vCPUHour*float64(vCPUs) + memoryGBHour*memoryGB,
+		DiskGBMonth:    diskGBMonth,
+		ExternalIPHour: externalIPv4HourPrice,
+		Live:           true,
+	}, nil
+}
+
+// findPrice returns the unit price of the on demand SKU for [region] whose description
+// starts with [descriptionPrefix]
+func (catalog *PriceCatalog) findPrice(region string, descriptionPrefix string) (float64, error) {
+	for _, sku := range catalog.skus {
+		if sku.Category == nil || sku.Category.UsageType != "OnDemand" {
+			continue
+		}
+		if !strings.HasPrefix(sku.Description, descriptionPrefix) || !slices.Contains(sku.ServiceRegions, region) {
+			continue
+		}
+		if len(sku.PricingInfo) == 0 || sku.PricingInfo[0].PricingExpression == nil {
+			continue
+		}
+		rates := sku.PricingInfo[0].PricingExpression.TieredRates
+		if len(rates) == 0 || rates[len(rates)-1].UnitPrice == nil {
+			continue
+		}
+		unitPrice := rates[len(rates)-1].UnitPrice
+		return float64(unitPrice.Units) + float64(unitPrice.Nanos)/1e9, nil
+	}
+	return 0, fmt.Errorf("no on demand price found for %q at %s", descriptionPrefix, region)
+}
+
+// EstimateMonthlyCost returns the approximate monthly cost in USD of [numNodes] instances at
+// [prices], each one with a disk of [diskSize] GB and an external IP.
+func EstimateMonthlyCost(prices Prices, diskSize int, numNodes int) float64 {
+	nodeCost := (prices.MachineHour+prices.ExternalIPHour)*hoursPerMonth + prices.DiskGBMonth*float64(diskSize)
+	return nodeCost * float64(numNodes)
+}
+
+// GetInstanceMonthlyCost returns the approximate monthly cost of the current setup of
+// instance [instanceID] at [zone], at the prices of its region given by [catalog]: its machine
+// type while running, all its disks, and its external IP. Stopped instances are only charged
+// for disks and IPs. If [catalog] is nil or has no prices for the region, approximate
+// us-central1 prices are used
+func (c *GcpCloud) GetInstanceMonthlyCost(catalog *PriceCatalog, zone string, instanceID string) (models.CloudInstanceCost, error) {
+	instance, err := c.gcpClient.Instances.Get(c.projectID, zone, instanceID).Do()
+	if err != nil {
+		return models.CloudInstanceCost{}, err
+	}
+	machineType := getNameFromURL(instance.MachineType)
+	vCPUs, memoryGB, err := c.GetMachineTypeResources(machineType, zone)
+	if err != nil {
+		return models.CloudInstanceCost{}, err
+	}
+	prices, known := GetPrices(catalog, ZoneToRegion(zone), machineType, vCPUs, memoryGB)
+	cost := instanceMonthlyCost(instance, prices)
+	cost.Known = known
+	return cost, nil
+}
+
+// instanceMonthlyCost returns the monthly cost of [instance] at [prices]
+func instanceMonthlyCost(instance *compute.Instance, prices Prices) models.CloudInstanceCost {
+	cost := models.CloudInstanceCost{
+		InstanceType: getNameFromURL(instance.MachineType),
+		Running:      instance.Status == "RUNNING",
+		Live:         prices.Live,
+	}
+	if cost.Running {
+		cost.Compute = prices.MachineHour * hoursPerMonth
+	}
+	for _, disk := range instance.Disks {
+		cost.DiskSize += int(disk.DiskSizeGb)
+		cost.Storage += prices.DiskGBMonth * float64(disk.DiskSizeGb)
+	}
+	for _, networkInterface := range instance.NetworkInterfaces {
+		for _, accessConfig := range networkInterface.AccessConfigs {
+			if accessConfig.NatIP != "" {
+				cost.PublicIP += prices.ExternalIPHour * hoursPerMonth
+			}
+		}
+	}
+	return cost
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/cloudbilling/v1"
+	"google.golang.org/api/compute/v1"
+)
+
+func newTestSku(description string, usageType string, region string, units int64, nanos int64) *cloudbilling.Sku {
+	return &cloudbilling.Sku{
+		Description:    description,
+		Category:       &cloudbilling.Category{UsageType: usageType},
+		ServiceRegions: []string{region},
+		PricingInfo: []*cloudbilling.PricingInfo{
+			{
+				PricingExpression: &cloudbilling.PricingExpression{
+					TieredRates: []*cloudbilling.TierRate{
+						{UnitPrice: &cloudbilling.Money{Units: units, Nanos: nanos}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetPrices(t *testing.T) {
+	require := require.New(t)
+	catalog := &PriceCatalog{
+		skus: []*cloudbilling.Sku{
+			newTestSku("E2 Instance Core running in Americas", "Preemptible", "us-east1", 0, 6_000_000),
+			newTestSku("E2 Instance Core running in Americas", "OnDemand", "us-east1", 0, 21_811_590),
+			newTestSku("E2 Instance Ram running in Americas", "OnDemand", "us-east1", 0, 2_923_530),
+			newTestSku("E2 Instance Core running in Frankfurt", "OnDemand", "europe-west3", 0, 28_000_000),
+			newTestSku("Storage PD Capacity", "OnDemand", "us-east1", 0, 40_000_000),
+		},
+	}
+	prices, ok := GetPrices(catalog, "us-east1", "e2-standard-8", 8, 32)
+	require.True(ok)
+	require.True(prices.Live)
+	require.InDelta(8*0.02181159+32*0.00292353, prices.MachineHour, 1e-9)
+	require.InDelta(0.04, prices.DiskGBMonth, 1e-9)
+	// no memory price at the region: approximate prices
+	prices, ok = GetPrices(catalog, "europe-west3", "e2-standard-8", 8, 32)
+	require.True(ok)
+	require.False(prices.Live)
+	require.Equal(machineHourPrice["e2-standard-8"], prices.MachineHour)
+	// unknown machine type
+	_, ok = GetPrices(nil, "us-east1", "a3-highgpu-8g", 208, 1872)
+	require.False(ok)
+	// (0.1 + 0.005) * 730 + 0.04 * 1000 per node
+	require.InDelta(3*(76.65+40), EstimateMonthlyCost(Prices{MachineHour: 0.1, DiskGBMonth: 0.04, ExternalIPHour: 0.005}, 1000, 3), 1e-6)
+}
+
+func TestInstanceMonthlyCost(t *testing.T) {
+	require := require.New(t)
+	instance := &compute.Instance{
+		MachineType: "https://www.googleapis.com/compute/v1/projects/p/zones/europe-west3-a/machineTypes/e2-standard-8",
+		Status:      "RUNNING",
+		Disks:       []*compute.AttachedDisk{{DiskSizeGb: 1000}, {DiskSizeGb: 10}},
+		NetworkInterfaces: []*compute.NetworkInterface{
+			{AccessConfigs: []*compute.AccessConfig{{NatIP: "1.2.3.4"}}},
+		},
+	}
+	prices := Prices{MachineHour: 0.3, DiskGBMonth: 0.044, ExternalIPHour: 0.005, Live: true}
+	cost := instanceMonthlyCost(instance, prices)
+	require.Equal("e2-standard-8", cost.InstanceType)
+	require.True(cost.Running)
+	require.True(cost.Live)
+	require.Equal(1010, cost.DiskSize)
+	require.InDelta(0.3*730, cost.Compute, 1e-6)
+	require.InDelta(0.044*1010, cost.Storage, 1e-6)
+	require.InDelta(0.005*730, cost.PublicIP, 1e-6)
+
+	// terminated instances without external IP only pay for disks
+	instance.Status = "TERMINATED"
+	instance.NetworkInterfaces = nil
+	cost = instanceMonthlyCost(instance, prices)
+	require.Zero(cost.Compute)
+	require.Zero(cost.PublicIP)
+	require.InDelta(0.044*1010, cost.Total(), 1e-6)
+}
@@ -194,8 +194,9 @@ func (c *GcpCloud) SetPublicIP(zone, nodeName string, numNodes int) ([]string, e
 			Name:        staticIPName,
 			AddressType: "EXTERNAL",
 			NetworkTier: "PREMIUM",
+			Labels:      managedLabels(staticIPName),
 		}
-		region := zoneToRegion(zone)
+		region := ZoneToRegion(zone)
 		insertOp, err := c.gcpClient.Addresses.Insert(c.projectID, region, address).Do()
 		if err != nil {
 			return nil, fmt.Errorf("error creating static IP 1 %s: %w", staticIPName, err)
@@ -280,10 +281,7 @@ func (c *GcpCloud) SetupInstances(
 				Scheduling: &compute.Scheduling{
 					AutomaticRestart: &automaticRestart,
 				},
-				Labels: map[string]string{
-					"name":       cliDefaultName,
-					"managed-by": "avalanche-cli",
-				},
+				Labels: managedLabels(cliDefaultName),
 			}
 			if staticIP != nil {
 				instance.NetworkInterfaces[0].AccessConfigs[0].NatIP = staticIP[currentIndex]
@@ -321,8 +319,20 @@ func (c *GcpCloud) SetupInstances(
 	return instances, nil
 }
 
-func (c *GcpCloud) GetUbuntuImageID() (string, error) {
-	imageListCall := c.gcpClient.Images.List(constants.GCPDefaultImageProvider).Filter(constants.GCPImageFilter)
+// GetMachineTypeArch returns the architecture of the given machine type: arm64 for the
+// Arm based families (Tau T2A, Axion C4A), x86_64 otherwise
+func GetMachineTypeArch(machineType string) string {
+	for _, family := range []string{"t2a", "c4a"} {
+		if strings.HasPrefix(machineType, family+"-") {
+			return "arm64"
+		}
+	}
+	return "x86_64"
+}
+
+// GetUbuntuImageID returns the latest non deprecated avalanche cli ubuntu image for [arch]
+func (c *GcpCloud) GetUbuntuImageID(arch string) (string, error) {
+	imageListCall := c.gcpClient.Images.List(constants.GCPDefaultImageProvider).Filter(fmt.Sprintf(constants.GCPImageFilter, arch))
 	imageList, err := imageListCall.Do()
 	if err != nil {
 		return "", err
@@ -334,6 +344,9 @@ func (c *GcpCloud) GetUbuntuImageID() (string, error) {
 			break
 		}
 	}
+	if imageID == "" {
+		return "", fmt.Errorf("no ubuntu image found for architecture %s", arch)
+	}
 	return imageID, nil
 }
 
@@ -501,8 +514,8 @@ func (c *GcpCloud) GetRandomZone(region string) (string, error) {
 	return zones[rand.Intn(len(zones))], nil
 }
 
-// zoneToRegion returns region from zone
-func zoneToRegion(zone string) string {
+// ZoneToRegion returns region from zone
+func ZoneToRegion(zone string) string {
 	splitZone := strings.Split(zone, "-")
 	if len(splitZone) < 2 {
 		return ""
@@ -611,3 +624,123 @@ func (c *GcpCloud) IsInstanceTypeSupported(machineType string, zone string) (boo
 	})
 	return slices.Contains(supportedMachineTypes, machineType), nil
 }
+
+// GetMachineTypeResources returns the number of vCPUs and the memory in GB of the given
+// machine type in the given zone
+func (c *GcpCloud) GetMachineTypeResources(machineType string, zone string) (int, float64, error) {
+	mt, err := c.gcpClient.MachineTypes.Get(c.projectID, zone, machineType).Do()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(mt.GuestCpus), float64(mt.MemoryMb) / 1024, nil
+}
+
+// GetRegionQuota returns the usage and limit of the given quota metric (ex: CPUS, IN_USE_ADDRESSES) in the given region
+func (c *GcpCloud) GetRegionQuota(region string, metric string) (float64, float64, error) {
+	regionInfo, err := c.gcpClient.Regions.Get(c.projectID, region).Do()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, quota := range regionInfo.Quotas {
+		if quota.Metric == metric {
+			return quota.Usage, quota.Limit, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("quota %s not found for region %s", metric, region)
+}
+
+// CheckImageAvailable checks that the given image exists and is ready to be used
+func (c *GcpCloud) CheckImageAvailable(imageID string) (bool, error) {
+	image, err := c.gcpClient.Images.Get(constants.GCPDefaultImageProvider, imageID).Do()
+	if err != nil {
+		if strings.Contains(err.Error(), "notFound") {
+			return false, nil
+		}
+		return false, err
+	}
+	return image.Status == "READY", nil
+}
+
+// managedLabels returns the labels set on every resource created by the CLI, so that
+// they can be found later by ListManagedResources
+func managedLabels(name string) map[string]string {
+	labels := map[string]string{
+		"name":       name,
+		"managed-by": constants.AvalancheCLIManagedByTag,
+	}
+	if owner, err := utils.GetCloudResourceOwner(); err == nil {
+		labels[constants.AvalancheCLIOwnerLabel] = owner
+	}
+	return labels
+}
+
+// ListManagedResources returns the instances and static IPs in the region
+// that were labelled as created by the CLI
+func (c *GcpCloud) ListManagedResources(region string) ([]models.CloudResource, error) {
+	resources := []models.CloudResource{}
+	zones, err := c.ListZonesInRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	for _, zone := range zones {
+		instanceList, err := c.gcpClient.Instances.List(c.projectID, zone).
+			Filter(fmt.Sprintf("labels.managed-by=%s", constants.AvalancheCLIManagedByTag)).Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instanceList.Items {
+			publicIP := ""
+			if len(instance.NetworkInterfaces) > 0 && len(instance.NetworkInterfaces[0].AccessConfigs) > 0 {
+				publicIP = instance.NetworkInterfaces[0].AccessConfigs[0].NatIP
+			}
+			resources = append(resources, models.CloudResource{
+				Type:     models.CloudResourceInstance,
+				ID:       instance.Name,
+				Name:     instance.Labels["name"],
+				Owner:    instance.Labels[constants.AvalancheCLIOwnerLabel],
+				Region:   zone,
+				PublicIP: publicIP,
+				State:    instance.Status,
+			})
+		}
+	}
+	// static IPs created by older versions carry no labels, and can't be told apart
+	// from the ones created by other tools, so they are not listed
+	addressList, err := c.gcpClient.Addresses.List(c.projectID, region).
+		Filter(fmt.Sprintf("labels.managed-by=%s", constants.AvalancheCLIManagedByTag)).Do()
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range addressList.Items {
+		resources = append(resources, models.CloudResource{
+			Type:     models.CloudResourceStaticIP,
+			ID:       address.Name,
+			Name:     address.Name,
+			Owner:    address.Labels[constants.AvalancheCLIOwnerLabel],
+			Region:   region,
+			PublicIP: address.Address,
+			State:    strings.ToLower(address.Status),
+		})
+	}
+	return resources, nil
+}
+
+// DeleteManagedResource deletes a resource previously listed by ListManagedResources
+func (c *GcpCloud) DeleteManagedResource(resource models.CloudResource) error {
+	var (
+		operation *compute.Operation
+		err       error
+	)
+	switch resource.Type {
+	case models.CloudResourceInstance:
+		operation, err = c.gcpClient.Instances.Delete(c.projectID, resource.Region, resource.ID).Do()
+	case models.CloudResourceStaticIP:
+		operation, err = c.gcpClient.Addresses.Delete(c.projectID, resource.Region, resource.ID).Do()
+	default:
+		return fmt.Errorf("unsupported resource type %s", resource.Type)
+	}
+	if err != nil {
+		return err
+	}
+	return c.waitForOperation(operation)
+}
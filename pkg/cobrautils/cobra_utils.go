@@ -12,6 +12,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	// readOnlyAnnotation marks commands that are safe to run in read-only mode
+	readOnlyAnnotation = "readOnly"
+	// clusterStateAnnotation marks commands that change the state of the cluster given as
+	// first argument
+	clusterStateAnnotation = "clusterState"
+)
+
 type UsageError struct {
 	cmd *cobra.Command
 	err error
@@ -101,3 +109,38 @@ func ConfigureRootCmd(cmd *cobra.Command) {
 		return NewUsageError(cmd, err)
 	})
 }
+
+// MarkReadOnly flags [cmd] as safe to run in read-only mode: it does not
+// modify local state, sign or broadcast
+func MarkReadOnly(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[readOnlyAnnotation] = "true"
+	return cmd
+}
+
+// IsReadOnly returns true if [cmd] is allowed to run in read-only mode. Command
+// suites only print their usage, so they are always allowed
+func IsReadOnly(cmd *cobra.Command) bool {
+	if cmd.HasSubCommands() || cmd.Name() == "help" {
+		return true
+	}
+	return cmd.Annotations[readOnlyAnnotation] == "true"
+}
+
+// MarkClusterState flags [cmd] as changing the state of the cluster given as its first
+// argument, so the command runs under the cluster lock of the shared state backend, if any
+func MarkClusterState(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[clusterStateAnnotation] = "true"
+	return cmd
+}
+
+// ChangesClusterState returns true if [cmd] changes the state of the cluster given as its
+// first argument
+func ChangesClusterState(cmd *cobra.Command) bool {
+	return cmd.Annotations[clusterStateAnnotation] == "true"
+}
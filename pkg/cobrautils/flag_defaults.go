@@ -0,0 +1,183 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package cobrautils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// NetworkDefault is the flag default name that selects one of the network flags
+// of a command, eg network=fuji sets --fuji
+const NetworkDefault = "network"
+
+var (
+	// networkSelectionFlags are the boolean flags a network default can set
+	networkSelectionFlags = []string{"local", "devnet", "fuji", "testnet", "mainnet"}
+	// networkFlags are all the flags that select a network. If any of them is given
+	// on the command line, the network default is not applied
+	networkFlags = append([]string{"cluster", "endpoint", "network"}, networkSelectionFlags...)
+)
+
+// CommandConfigPath returns the path that identifies [cmd] on the config file:
+// its subcommand names, without the root command, joined by dots
+func CommandConfigPath(cmd *cobra.Command) string {
+	names := []string{}
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		names = append([]string{c.Name()}, names...)
+	}
+	return strings.Join(names, ".")
+}
+
+// FindCommandByConfigPath returns the subcommand of [root] identified by [path],
+// as given by CommandConfigPath
+func FindCommandByConfigPath(root *cobra.Command, path string) (*cobra.Command, error) {
+	cmd := root
+	for _, name := range strings.Split(path, ".") {
+		var found *cobra.Command
+		for _, sub := range cmd.Commands() {
+			if sub.Name() == name || sub.HasAlias(name) {
+				found = sub
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("command %q not found", strings.ReplaceAll(path, ".", " "))
+		}
+		cmd = found
+	}
+	return cmd, nil
+}
+
+// flagDefaultValue converts a config value into a flag value. Lists are comma separated
+func flagDefaultValue(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		items := make([]string, 0, len(list))
+		for _, item := range list {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// ValidateFlagDefault checks [value] can be set as default of the flag [flagName] of [cmd]
+func ValidateFlagDefault(cmd *cobra.Command, flagName string, value string) error {
+	if flagName == NetworkDefault {
+		for _, name := range networkSelectionFlags {
+			if strings.EqualFold(value, name) && cmd.Flags().Lookup(name) != nil {
+				return nil
+			}
+		}
+		if cmd.Flags().Lookup(NetworkDefault) != nil {
+			// named devnet
+			return nil
+		}
+		return fmt.Errorf("%q does not support network %q", cmd.CommandPath(), value)
+	}
+	flag := cmd.Flags().Lookup(flagName)
+	if flag == nil {
+		return fmt.Errorf("%q has no flag --%s", cmd.CommandPath(), flagName)
+	}
+	var err error
+	switch flag.Value.Type() {
+	case "bool":
+		_, err = strconv.ParseBool(value)
+	case "int", "int8", "int16", "int32", "int64":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		_, err = strconv.ParseUint(value, 10, 64)
+	case "float32", "float64":
+		_, err = strconv.ParseFloat(value, 64)
+	case "duration":
+		_, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q for flag --%s of type %s", value, flagName, flag.Value.Type())
+	}
+	return nil
+}
+
+// ApplyFlagDefaults sets the flags of [cmd] that were not given on the command line to
+// the values found on [defaults], keyed by flag name. The network default selects the
+// network flag named as its value, or a named devnet otherwise, unless any network
+// flag was given. Defaults for flags the command does not have are ignored.
+// Returns the flags that were set, as name=value
+func ApplyFlagDefaults(cmd *cobra.Command, defaults map[string]interface{}) ([]string, error) {
+	flags := cmd.Flags()
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	applied := []string{}
+	for _, name := range names {
+		if _, isSubcommand := defaults[name].(map[string]interface{}); isSubcommand {
+			// defaults of a subcommand
+			continue
+		}
+		value := flagDefaultValue(defaults[name])
+		if value == "" {
+			// unset default
+			continue
+		}
+		flagName := name
+		flagValue := value
+		if name == NetworkDefault {
+			if anyFlagChanged(cmd, networkFlags) {
+				continue
+			}
+			for _, selectionFlag := range networkSelectionFlags {
+				if strings.EqualFold(value, selectionFlag) {
+					flagName, flagValue = selectionFlag, "true"
+				}
+			}
+		}
+		flag := flags.Lookup(flagName)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := flags.Set(flagName, flagValue); err != nil {
+			return nil, fmt.Errorf(
+				"invalid default for %s.%s on the config file: %w",
+				CommandConfigPath(cmd),
+				name,
+				err,
+			)
+		}
+		applied = append(applied, fmt.Sprintf("%s=%s", flagName, flagValue))
+	}
+	return applied, nil
+}
+
+func anyFlagChanged(cmd *cobra.Command, names []string) bool {
+	for _, name := range names {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectedNetwork returns the network selection flag given to [cmd] on the command
+// line or by a network default, with testnet reported as fuji. Returns an empty
+// string if the network is not selected by a flag, eg because it is going to be
+// prompted, or is given by a cluster or endpoint
+func SelectedNetwork(cmd *cobra.Command) string {
+	for _, name := range networkSelectionFlags {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || !flag.Changed || flag.Value.String() != "true" {
+			continue
+		}
+		if name == "testnet" {
+			return "fuji"
+		}
+		return name
+	}
+	return ""
+}
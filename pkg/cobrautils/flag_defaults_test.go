@@ -0,0 +1,118 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package cobrautils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+type testFlags struct {
+	fuji     bool
+	mainnet  bool
+	devnet   string
+	token    string
+	timeout  time.Duration
+	keys     []string
+	executed bool
+}
+
+func newTestCmds(flags *testFlags) *cobra.Command {
+	root := &cobra.Command{Use: "avalanche"}
+	blockchain := &cobra.Command{Use: "blockchain", Aliases: []string{"subnet"}}
+	deploy := &cobra.Command{
+		Use: "deploy",
+		RunE: func(*cobra.Command, []string) error {
+			flags.executed = true
+			return nil
+		},
+	}
+	deploy.Flags().BoolVar(&flags.fuji, "fuji", false, "")
+	deploy.Flags().BoolVar(&flags.mainnet, "mainnet", false, "")
+	deploy.Flags().StringVar(&flags.devnet, "network", "", "")
+	deploy.Flags().StringVar(&flags.token, "evm-token", "", "")
+	deploy.Flags().DurationVar(&flags.timeout, "timeout", time.Minute, "")
+	deploy.Flags().StringSliceVar(&flags.keys, "keys", nil, "")
+	blockchain.AddCommand(deploy)
+	root.AddCommand(blockchain)
+	return root
+}
+
+func TestCommandConfigPath(t *testing.T) {
+	require := require.New(t)
+	root := newTestCmds(&testFlags{})
+	deploy, err := FindCommandByConfigPath(root, "subnet.deploy")
+	require.NoError(err)
+	require.Equal("blockchain.deploy", CommandConfigPath(deploy))
+	_, err = FindCommandByConfigPath(root, "blockchain.missing")
+	require.ErrorContains(err, `command "blockchain missing" not found`)
+}
+
+func TestApplyFlagDefaults(t *testing.T) {
+	require := require.New(t)
+	defaults := map[string]interface{}{
+		"network":   "fuji",
+		"evm-token": "GAS",
+		"timeout":   "5m",
+		"keys":      []interface{}{"a", "b"},
+		"unknown":   "x",
+		"unset":     "",
+	}
+
+	flags := &testFlags{}
+	root := newTestCmds(flags)
+	deploy, err := FindCommandByConfigPath(root, "blockchain.deploy")
+	require.NoError(err)
+	require.NoError(deploy.ParseFlags([]string{"--evm-token", "OTHER"}))
+	applied, err := ApplyFlagDefaults(deploy, defaults)
+	require.NoError(err)
+	require.Equal([]string{"keys=a,b", "fuji=true", "timeout=5m"}, applied)
+	require.True(flags.fuji)
+	require.Equal("OTHER", flags.token)
+	require.Equal(5*time.Minute, flags.timeout)
+	require.Equal([]string{"a", "b"}, flags.keys)
+
+	// network default is not applied if a network flag is given
+	flags = &testFlags{}
+	root = newTestCmds(flags)
+	deploy, err = FindCommandByConfigPath(root, "blockchain.deploy")
+	require.NoError(err)
+	require.NoError(deploy.ParseFlags([]string{"--mainnet"}))
+	_, err = ApplyFlagDefaults(deploy, defaults)
+	require.NoError(err)
+	require.False(flags.fuji)
+	require.True(flags.mainnet)
+
+	// named devnet
+	applied, err = ApplyFlagDefaults(newTestCmds(flags).Commands()[0].Commands()[0], map[string]interface{}{"network": "mydevnet"})
+	require.NoError(err)
+	require.Equal([]string{"network=mydevnet"}, applied)
+
+	_, err = ApplyFlagDefaults(newTestCmds(flags).Commands()[0].Commands()[0], map[string]interface{}{"timeout": "soon"})
+	require.ErrorContains(err, "invalid default for blockchain.deploy.timeout")
+}
+
+func TestValidateFlagDefault(t *testing.T) {
+	require := require.New(t)
+	root := newTestCmds(&testFlags{})
+	deploy, err := FindCommandByConfigPath(root, "blockchain.deploy")
+	require.NoError(err)
+	require.NoError(ValidateFlagDefault(deploy, "network", "fuji"))
+	require.NoError(ValidateFlagDefault(deploy, "network", "mydevnet"))
+	require.NoError(ValidateFlagDefault(deploy, "timeout", "1h"))
+	require.ErrorContains(ValidateFlagDefault(deploy, "timeout", "soon"), "invalid value")
+	require.ErrorContains(ValidateFlagDefault(deploy, "missing", "x"), "has no flag --missing")
+}
+
+func TestSelectedNetwork(t *testing.T) {
+	require := require.New(t)
+	root := newTestCmds(&testFlags{})
+	deploy, err := FindCommandByConfigPath(root, "blockchain.deploy")
+	require.NoError(err)
+	require.Equal("", SelectedNetwork(deploy))
+	require.NoError(deploy.Flags().Set("mainnet", "true"))
+	require.Equal("mainnet", SelectedNetwork(deploy))
+}
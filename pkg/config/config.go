@@ -5,6 +5,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"path/filepath"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
@@ -15,7 +16,10 @@ import (
 	"go.uber.org/zap"
 )
 
-type Config struct{}
+type Config struct {
+	// ReadOnly prevents the config file from being written
+	ReadOnly bool
+}
 
 func New() *Config {
 	return &Config{}
@@ -52,7 +56,10 @@ func (c *Config) ConfigFileExists() bool {
 }
 
 // SetConfigValue sets the value of a configuration key.
-func (*Config) SetConfigValue(key string, value interface{}) error {
+func (c *Config) SetConfigValue(key string, value interface{}) error {
+	if c.ReadOnly {
+		return errors.New("config changes not allowed in read-only mode")
+	}
 	viper.Set(key, value)
 	err := viper.WriteConfig()
 	return err
@@ -70,6 +77,18 @@ func (*Config) GetConfigStringValue(key string) string {
 	return viper.GetString(key)
 }
 
+func (*Config) GetConfigIntValue(key string) int {
+	return viper.GetInt(key)
+}
+
+func (*Config) GetConfigStringSliceValue(key string) []string {
+	return viper.GetStringSlice(key)
+}
+
+func (*Config) GetConfigStringMapValue(key string) map[string]interface{} {
+	return viper.GetStringMap(key)
+}
+
 func (*Config) LoadNodeConfig() (string, error) {
 	globalConfigs := viper.GetStringMap(constants.ConfigNodeConfigKey)
 	if len(globalConfigs) == 0 {
@@ -81,3 +100,13 @@ func (*Config) LoadNodeConfig() (string, error) {
 	}
 	return string(configStr), nil
 }
+
+// GetProxyConfig returns the proxies set on the config file
+func (c *Config) GetProxyConfig() utils.ProxyConfig {
+	return utils.ProxyConfig{
+		HTTPProxy:  c.GetConfigStringValue(constants.ConfigHTTPProxyKey),
+		HTTPSProxy: c.GetConfigStringValue(constants.ConfigHTTPSProxyKey),
+		NoProxy:    c.GetConfigStringValue(constants.ConfigNoProxyKey),
+		SSHProxy:   c.GetConfigStringValue(constants.ConfigSSHProxyKey),
+	}
+}
@@ -14,17 +14,20 @@ const (
 	DefaultPerms755        = 0o755
 	WriteReadReadPerms     = 0o644
 	WriteReadUserOnlyPerms = 0o600
+	UserOnlyDirPerms       = 0o700
 
 	UbuntuVersionLTS = "20.04"
 
-	BaseDirName = ".avalanche-cli"
-	LogDir      = "logs"
+	BaseDirName    = ".avalanche-cli"
+	LogDir         = "logs"
+	WarpSigningDir = "warp-signing"
 
 	ServerRunFile                   = "gRPCserver.run"
 	ServerRunFileLocalNetworkPrefix = ""
 	ServerRunFileLocalClusterPrefix = "localcluster_"
 
 	AvalancheCliBinDir = "bin"
+	BinaryCacheDir     = "cache"
 	RunDir             = "runs"
 	ServicesDir        = "services"
 
@@ -32,31 +35,101 @@ const (
 	SidecarFileName              = "sidecar.json"
 	GenesisFileName              = "genesis.json"
 	UpgradeFileName              = "upgrade.json"
+	UpgradeSetsDir               = "upgrades"
+	DefaultUpgradeSetName        = "default"
 	AliasesFileName              = "aliases.json"
+	LocalNetworkGenesisFileName  = "local_network_genesis.json"
 	SidecarSuffix                = SuffixSeparator + SidecarFileName
 	GenesisSuffix                = SuffixSeparator + GenesisFileName
 	NodeFileName                 = "node.json"
 	NodePrometheusConfigFileName = "prometheus.yml"
 	NodeCloudConfigFileName      = "node_cloud_config.json"
+	ImportedHostFileName         = "imported_host.json"
 	AnsibleDir                   = "ansible"
 	AnsibleHostInventoryFileName = "hosts"
 	ClustersConfigFileName       = "cluster_config.json"
 	ClustersConfigVersion        = "1"
+	DevnetsConfigFileName        = "devnets.json"
+	FaucetRequestsFileName       = "faucet_requests.json"
+	ValidatorRegistrationsFile   = "validator_registrations.json"
+	ClusterHealthHistoryDir      = "health"
+	ClusterSecretsDir            = "secrets"
+	ScratchChainsDir             = "scratch-chains"
+	ScratchChainFileName         = "scratch.json"
+	PresetsDir                   = "presets"
+	PresetIndexFileName          = "index.json"
 	StakerCertFileName           = "staker.crt"
 	StakerKeyFileName            = "staker.key"
 	BLSKeyFileName               = "signer.key"
 	SidecarVersion               = "1.4.0"
 
+	// files used by the supervisor of a persistent local network, under the run dir
+	LocalNetworkSupervisorLogFileName    = "local-network-supervisor.log"
+	LocalNetworkSupervisorDetachFileName = "local-network-supervisor.detach"
+
 	MaxLogFileSize   = 4
 	MaxNumOfLogFiles = 5
 	RetainOldFiles   = 0 // retain all old log files
 
+	// structured debug logs of each command invocation, under the log dir
+	CommandLogsDir           = "commands"
+	MaxNumOfCommandLogFiles  = 50
+	CommandLogFileNameSuffix = ".json"
+	CommandLogFileTimeFormat = "20060102-150405.000"
+
+	// cached network upgrade schedules, used to gate features by upgrade activation
+	NetworkUpgradesDir      = "network-upgrades"
+	NetworkUpgradesCacheTTL = 24 * time.Hour
+
+	// scheduled operations, their run history and the scheduler process files, under the schedules dir
+	SchedulesDir             = "schedules"
+	SchedulesFileName        = "schedules.json"
+	ScheduleHistoryFileName  = "history.jsonl"
+	ScheduleRunLogsDir       = "runs"
+	SchedulerRunFileName     = "scheduler.run"
+	SchedulerLogFileName     = "scheduler.log"
+	MaxNumOfScheduleRuns     = 500
+	ScheduleRunLogTimeFormat = "20060102-150405"
+
+	// local DNS proxy, serving the local network endpoints at their local DNS names
+	LocalDNSProxyPort           = 9080
+	LocalDNSProxyRunFileName    = "local-dns-proxy.run"
+	LocalDNSProxyLogFileName    = "local-dns-proxy.log"
+	LocalDNSProxyStartupTimeout = 2 * time.Second
+
+	// timeout of the operations on the shared cluster state backend
+	StateBackendTimeout = 30 * time.Second
+	// cluster locks expire if not renewed for StateBackendLockTTL (eg: the CLI was killed),
+	// and are renewed every StateBackendLockRenewal while held
+	StateBackendLockTTL     = 15 * time.Minute
+	StateBackendLockRenewal = 5 * time.Minute
+
+	// console output verbosity levels
+	VerbosityFlag    = "verbosity"
+	QuietVerbosity   = "quiet"
+	NormalVerbosity  = "normal"
+	VerboseVerbosity = "verbose"
+	DebugVerbosity   = "debug"
+
 	CloudOperationTimeout = 2 * time.Minute
 
+	// health snapshots of a cluster older than this are removed from its history
+	ClusterHealthHistoryRetention = 30 * 24 * time.Hour
+
 	ANRRequestTimeout      = 3 * time.Minute
 	APIRequestTimeout      = 10 * time.Second
 	APIRequestLargeTimeout = 10 * time.Second
 	FastGRPCDialTimeout    = 100 * time.Millisecond
+	HookTimeout            = 10 * time.Second
+	FaucetRequestTimeout   = 30 * time.Second
+	FaucetFundsWaitTimeout = 2 * time.Minute
+	FaucetPollInterval     = 5 * time.Second
+	FaucetRequestCooldown  = 24 * time.Hour
+
+	// how often the supervisor of a persistent local network checks the nodes, and
+	// how many consecutive failed checks make it restart a node
+	LocalNetworkSupervisorCheckInterval = 10 * time.Second
+	LocalNetworkSupervisorNodeFailures  = 3
 
 	FujiBootstrapTimeout    = 15 * time.Minute
 	MainnetBootstrapTimeout = 24 * time.Hour
@@ -82,6 +155,7 @@ const (
 
 	FujiAPIEndpoint    = "https://api.avax-test.network"
 	MainnetAPIEndpoint = "https://api.avax.network"
+	FujiFaucetURL      = "https://faucet.avax.network"
 
 	// this depends on bootstrap snapshot
 	LocalAPIEndpoint                   = "http://127.0.0.1:9650"
@@ -90,6 +164,13 @@ const (
 	LocalNetworkAvalancheGoMaxLogSize  = 1
 	LocalNetworkAvalancheGoMaxLogFiles = 2
 
+	// local network backends
+	LocalNetworkProcessBackend = "process"
+	LocalNetworkDockerBackend  = "docker"
+	LocalDockerNetworkDir      = "docker-local-network"
+	LocalDockerNetworkProject  = "avalanche-cli-local-network"
+	LocalDockerNetworkSubnet   = "172.30.0.0/24"
+
 	DevnetAPIEndpoint = ""
 	DevnetNetworkID   = 1338
 
@@ -97,6 +178,9 @@ const (
 
 	DefaultTokenSymbol = "TEST"
 
+	// decimals of EVM native tokens, assumed by wallets, explorers and ICTT contracts
+	DefaultTokenDecimals = 18
+
 	// it's unlikely anyone would want to name a snapshot `default`
 	// but let's add some more entropy
 	SnapshotsDirName = "snapshots"
@@ -110,6 +194,8 @@ const (
 	ErrReleasingGCPStaticIP    = "failed to release gcp static ip"
 	KeyDir                     = "key"
 	KeySuffix                  = ".pk"
+	HDWalletSuffix             = ".mnemonic"
+	KeyTagsFileName            = "key-tags.json"
 	YAMLSuffix                 = ".yml"
 	CustomGrafanaDashboardJSON = "custom.json"
 	Enable                     = "enable"
@@ -124,6 +210,10 @@ const (
 	BootstrapValidatorBalanceAVAX     = 0.1
 	// Default weight when we prompt users for bootstrap validators
 	BootstrapValidatorWeight = 100
+	// Default max number of bootstrap validators set on the ConvertSubnetToL1Tx. The tx size
+	// and the gas of the validator manager initialization grow with the validator set, so the
+	// remaining ones are registered on the validator manager after it is initialized
+	DefaultBootstrapValidatorsBatchSize = 50
 	// Default weight when we prompt users for non bootstrap validators
 	NonBootstrapValidatorWeight       = BootstrapValidatorWeight / 5
 	DefaultStakeWeight                = 20
@@ -131,6 +221,9 @@ const (
 	DefaultFujiStakeDuration          = "48h"
 	DefaultMainnetStakeDuration       = "336h"
 	DefaultValidationIDExpiryDuration = 24 * time.Hour
+	// Min number of validators an L1 is expected to keep when removing validators,
+	// unless set differently in the config file
+	DefaultL1MinValidators = 1
 	// The absolute minimum is 25 seconds, but set to 1 minute to allow for
 	// time to go through the command
 	DevnetStakingStartLeadTime                   = 30 * time.Second
@@ -140,10 +233,14 @@ const (
 	PrimaryNetworkValidatingStartLeadTime        = 1 * time.Minute
 	AWSCloudServerRunningState                   = "running"
 	AvalancheCLISuffix                           = "-avalanche-cli"
+	AvalancheCLIManagedByTag                     = "avalanche-cli"
+	AvalancheCLIOwnerTag                         = "Owner"
+	AvalancheCLIOwnerLabel                       = "owner"
 	AWSDefaultCredential                         = "default"
 	GCPDefaultImageProvider                      = "avalabs-experimental"
-	GCPImageFilter                               = "family=avalanchecli-ubuntu-2204 AND architecture=x86_64"
+	GCPImageFilter                               = "family=avalanchecli-ubuntu-2204 AND architecture=%s"
 	GCPEnvVar                                    = "GOOGLE_APPLICATION_CREDENTIALS"
+	GCPImpersonateEnvVar                         = "CLOUDSDK_AUTH_IMPERSONATE_SERVICE_ACCOUNT"
 	GCPDefaultAuthKeyPath                        = "~/.config/gcloud/application_default_credentials.json"
 	CertSuffix                                   = "-kp.pem"
 	AWSSecurityGroupSuffix                       = "-sg"
@@ -151,6 +248,8 @@ const (
 	AvalancheGoAPIPort                           = 9650
 	AvalancheGoP2PPort                           = 9651
 	AvalancheGoGrafanaPort                       = 3000
+	HTTPSPort                                    = 443
+	ACMEChallengePort                            = 80 // used by Let's Encrypt to validate domain ownership
 	AvalancheGoLokiPort                          = 23101
 	CloudServerStorageSize                       = 1000
 	MonitoringCloudServerStorageSize             = 50
@@ -166,17 +265,50 @@ const (
 	ConfigUpdatesDisabledKey      = "UpdatesDisabled"
 	ConfigAuthorizeCloudAccessKey = "AuthorizeCloudAccess"
 	ConfigSnapshotsAutoSaveKey    = "SnapshotsAutoSaveEnabled"
+	ConfigAPIMaxAttemptsKey       = "APIMaxAttempts"
+	ConfigAPIRequestsPerSecondKey = "APIRequestsPerSecond"
+	ConfigAPIFailureThresholdKey  = "APIFailureThreshold"
+	ConfigAggregatorQuorumKey     = "AggregatorQuorumPercentage"
+	ConfigAggregatorTimeoutKey    = "AggregatorValidatorTimeout"
+	ConfigAggregatorParallelKey   = "AggregatorParallelRequests"
+	ConfigAggregatorAttemptsKey   = "AggregatorMaxAttempts"
+	ConfigAggregatorStrategyKey   = "AggregatorRetryStrategy"
+	ConfigAggregatorRetryDelayKey = "AggregatorRetryDelay"
+	ConfigL1MinValidatorsKey      = "L1MinValidators"
+	ConfigLocalNetworkBackendKey  = "LocalNetworkBackend"
+	ConfigLocalNetworkNodeCPUsKey = "LocalNetworkNodeCPUs"
+	ConfigLocalNetworkNodeMemKey  = "LocalNetworkNodeMemory"
+	ConfigReadOnlyKey             = "ReadOnly"
+	ConfigWebhookURLKey           = "WebhookURL"
+	ConfigHookScriptKey           = "HookScript"
+	ConfigEventSinkURLKey         = "EventSinkURL"
+	ConfigStateBackendKey         = "StateBackend"
+	ConfigTrustedSigningKeysKey   = "TrustedSigningKeys"
+	ConfigRequireSignaturesKey    = "RequireSignedArtifacts"
+	ConfigFlagDefaultsKey         = "FlagDefaults"
+	ConfigConfirmationPoliciesKey = "ConfirmationPolicies"
+	ConfigPresetRegistryKey       = "PresetRegistry"
+	ConfigHTTPProxyKey            = "HTTPProxy"
+	ConfigHTTPSProxyKey           = "HTTPSProxy"
+	ConfigNoProxyKey              = "NoProxy"
+	ConfigSSHProxyKey             = "SSHProxy"
+	ConfigLocalDNSHostsFileKey    = "LocalDNSHostsFile"
+	ConfigLocalDNSProxyPortKey    = "LocalDNSProxyPort"
 	OldConfigFileName             = ".avalanche-cli.json"
 	OldMetricsConfigFileName      = ".avalanche-cli/config"
 	DefaultConfigFileName         = ".avalanche-cli/config.json"
 	DefaultNodeType               = "default"
 	AWSCloudService               = "Amazon Web Services"
 	GCPCloudService               = "Google Cloud Platform"
+	ImportedCloudService          = "Imported"
 	AWSDefaultInstanceType        = "c5.2xlarge"
 	GCPDefaultInstanceType        = "e2-standard-8"
+	AWSDefaultARMInstanceType     = "c7g.2xlarge"
+	GCPDefaultARMInstanceType     = "t2a-standard-8"
 	AnsibleSSHUser                = "ubuntu"
 	AWSNodeAnsiblePrefix          = "aws_node"
 	GCPNodeAnsiblePrefix          = "gcp_node"
+	ImportedNodeAnsiblePrefix     = "imported_node"
 	CustomVMDir                   = "vms"
 	ClusterYAMLFileName           = "clusterInfo.yaml"
 	GCPStaticIPPrefix             = "static-ip"
@@ -188,12 +320,16 @@ const (
 	ICMServicesRepoName           = "icm-services"
 	ICMRelayerKind                = "icm-relayer"
 	SubnetEVMReleaseURL           = "https://github.com/ava-labs/subnet-evm/releases/download/%s/%s"
-	SubnetEVMArchive              = "subnet-evm_%s_linux_amd64.tar.gz"
+	SubnetEVMArchive              = "subnet-evm_%s_linux_%s.tar.gz"
 	CloudNodeConfigBasePath       = "/home/ubuntu/.avalanchego/"
 	CloudNodeSubnetEvmBinaryPath  = "/home/ubuntu/.avalanchego/plugins/%s"
 	CloudNodeStakingPath          = "/home/ubuntu/.avalanchego/staking/"
 	CloudNodeConfigPath           = "/home/ubuntu/.avalanchego/configs/"
 	CloudNodePluginsPath          = "/home/ubuntu/.avalanchego/plugins/"
+	CloudNodeDBPath               = "/home/ubuntu/.avalanchego/db/"
+	CloudNodeChainDataPath        = "/home/ubuntu/.avalanchego/chainData/"
+	CloudNodeBackupsPath          = "/home/ubuntu/.avalanchego/backups/"
+	CloudNodeAvalancheGoBinDir    = "/home/ubuntu/avalanche-node/"
 	DockerNodeConfigPath          = "/.avalanchego/configs/"
 	CloudNodePrometheusConfigPath = "/etc/prometheus/prometheus.yml"
 	CloudNodeCLIConfigBasePath    = "/home/ubuntu/.avalanche-cli/"
@@ -239,6 +375,21 @@ const (
 
 	// #nosec G101
 	GithubAPITokenEnvVarName = "AVALANCHE_CLI_GITHUB_TOKEN"
+	// #nosec G101
+	BackupPassphraseEnvVarName = "AVALANCHE_CLI_BACKUP_PASSPHRASE"
+	// #nosec G101
+	AlertSMTPPasswordEnvVarName = "AVALANCHE_CLI_ALERT_SMTP_PASSWORD"
+	// #nosec G101
+	AlertSlackWebhookURLEnvVarName = "AVALANCHE_CLI_ALERT_SLACK_WEBHOOK_URL"
+	// credentials of the message queue event sink
+	EventSinkUserEnvVarName = "AVALANCHE_CLI_EVENT_SINK_USER"
+	// #nosec G101
+	EventSinkPasswordEnvVarName = "AVALANCHE_CLI_EVENT_SINK_PASSWORD"
+	// passphrase the node keys are encrypted with on the shared cluster state
+	// #nosec G101
+	StateBackendPassphraseEnvVarName = "AVALANCHE_CLI_STATE_PASSPHRASE"
+	// prefix of the env vars that answer interactive prompts
+	PromptEnvVarPrefix = "AVALANCHE_PROMPT_"
 
 	ReposDir                    = "repos"
 	SubnetDir                   = "subnets"
@@ -281,13 +432,21 @@ const (
 
 	AvalancheGoDockerImage = "avaplatform/avalanchego"
 	AvalancheGoGitRepo     = "https://github.com/ava-labs/avalanchego"
+	// image with tc and iptables, used to inject network faults into local network containers
+	NetworkToolsDockerImage = "nicolaka/netshoot"
 
 	UpgradeBytesLockExtension = ".lock"
 	NotAvailableLabel         = "Not available"
 	BackendCmd                = "avalanche-cli-backend"
+	// hidden node command ssh runs as ProxyCommand to reach nodes through the SSH proxy
+	SSHProxyCmd = "ssh-proxy"
+
+	// extension of the copy kept of an upgrade file before rewriting it
+	UpgradeBytesBackupExtension = ".bak"
 
 	AvalancheGoCompatibilityURL  = "https://raw.githubusercontent.com/ava-labs/avalanchego/master/version/compatibility.json"
 	SubnetEVMRPCCompatibilityURL = "https://raw.githubusercontent.com/ava-labs/subnet-evm/master/compatibility.json"
+	AVAXPriceURL                 = "https://api.coingecko.com/api/v3/simple/price?ids=avalanche-2&vs_currencies=usd"
 
 	YesLabel = "Yes"
 	NoLabel  = "No"
@@ -301,6 +460,10 @@ const (
 	DefaultNumberOfLocalMachineNodes = 1
 	MetricsNetwork                   = "network"
 	SkipUpdateFlag                   = "skip-update-check"
+	AllowUnapprovedKeyFlag           = "allow-unapproved-key"
+	SkipUpgradeChecksFlag            = "skip-upgrade-checks"
+	ReadOnlyFlag                     = "read-only"
+	ApprovalFlag                     = "approval"
 	LastFileName                     = ".last_actions.json"
 	APIRole                          = "API"
 	ValidatorRole                    = "Validator"
@@ -308,6 +471,19 @@ const (
 	ICMRelayerRole                   = "Relayer"
 	LoadTestRole                     = "LoadTest"
 
+	// node monitoring backends
+	MonitoringPrometheus       = "prometheus"
+	MonitoringDatadog          = "datadog"
+	MonitoringCloudWatch       = "cloudwatch"
+	DatadogAPIKeyEnvVar        = "DD_API_KEY"
+	DatadogAppKeyEnvVar        = "DD_APP_KEY"
+	DatadogDefaultSite         = "datadoghq.com"
+	CloudWatchDefaultNamespace = "Avalanche"
+
+	// node provisioning modes
+	DockerProvisioning = "docker"
+	BinaryProvisioning = "binary"
+
 	PayTxsFeesMsg = "pay transaction fees"
 
 	CodespaceNameEnvVar = "CODESPACE_NAME"
@@ -7,7 +7,6 @@ import (
 	"math/big"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
-	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
@@ -22,9 +21,8 @@ func GetDefaultBlockchainAirdropKeyInfo(
 	blockchainName string,
 ) (string, string, string, error) {
 	keyName := utils.GetDefaultBlockchainAirdropKeyName(blockchainName)
-	keyPath := app.GetKeyPath(keyName)
-	if utils.FileExists(keyPath) {
-		k, err := key.LoadSoft(models.NewLocalNetwork().ID, keyPath)
+	if app.KeyExists(keyName) {
+		k, err := app.GetKey(keyName, app.GetLocalNetwork(), false)
 		if err != nil {
 			return "", "", "", err
 		}
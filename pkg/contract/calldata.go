@@ -0,0 +1,496 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package contract
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const selectorLen = 4
+
+// builtin solidity errors, that can be returned by any contract
+var builtinErrorSignatures = []string{
+	"Error(string)",
+	"Panic(uint256)",
+}
+
+// Signature is a parsed method, error or event signature, eg "transfer(address,uint256)"
+// or "Transfer(address indexed,address indexed,uint256)". Tuple types are given as
+// "(type1,type2)", and arrays either as "type[]" or "[type]"
+type Signature struct {
+	Name   string
+	Inputs abi.Arguments
+	// canonical signature, as used to compute selectors and topics
+	Canonical string
+}
+
+// Decoded is the result of decoding calldata, revert data, or an event log
+type Decoded struct {
+	Signature *Signature
+	Values    []interface{}
+}
+
+func removeSurroundingParenthesis(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 0 {
+		if string(s[0]) != "(" || string(s[len(s)-1]) != ")" {
+			return "", fmt.Errorf("expected esp %q to be surrounded by parenthesis", s)
+		}
+		s = s[1 : len(s)-1]
+	}
+	return s, nil
+}
+
+func removeSurroundingBrackets(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 0 {
+		if string(s[0]) != "[" || string(s[len(s)-1]) != "]" {
+			return "", fmt.Errorf("expected esp %q to be surrounded by parenthesis", s)
+		}
+		s = s[1 : len(s)-1]
+	}
+	return s, nil
+}
+
+func ParseSignature(signature string) (*Signature, error) {
+	signature = strings.TrimSpace(signature)
+	// outputs are not part of the signature
+	if index := strings.Index(signature, "->"); index != -1 {
+		signature = signature[:index]
+	}
+	index := strings.Index(signature, "(")
+	if index <= 0 {
+		return nil, fmt.Errorf("invalid signature %q: expected name(types)", signature)
+	}
+	name := strings.TrimSpace(signature[:index])
+	params, err := removeSurroundingParenthesis(signature[index:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %q: %w", signature, err)
+	}
+	sig := &Signature{
+		Name:   name,
+		Inputs: abi.Arguments{},
+	}
+	types := []string{}
+	for i, param := range splitTopLevel(params, ',') {
+		words := splitTopLevel(param, ' ')
+		if len(words) == 0 {
+			return nil, fmt.Errorf("invalid signature %q: empty type for parameter %d", signature, i)
+		}
+		abiType, err := parseABIType(words[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature %q: %w", signature, err)
+		}
+		arg := abi.Argument{
+			Name: fmt.Sprintf("arg%d", i),
+			Type: abiType,
+		}
+		for _, word := range words[1:] {
+			if word == "indexed" {
+				arg.Indexed = true
+			} else {
+				arg.Name = word
+			}
+		}
+		sig.Inputs = append(sig.Inputs, arg)
+		types = append(types, abiType.String())
+	}
+	sig.Canonical = fmt.Sprintf("%s(%s)", name, strings.Join(types, ","))
+	return sig, nil
+}
+
+// Selector returns the 4 bytes identifying the signature as a method or error
+func (s *Signature) Selector() []byte {
+	return crypto.Keccak256([]byte(s.Canonical))[:selectorLen]
+}
+
+// Topic returns the first topic of the logs of the signature as an event
+func (s *Signature) Topic() common.Hash {
+	return crypto.Keccak256Hash([]byte(s.Canonical))
+}
+
+// EncodeCalldata returns the calldata to call the method [signature] with [args],
+// given in their text form. Numbers can be decimal or 0x prefixed hex, bytes are hex,
+// and tuples and arrays are given as "(v1,v2)" and "[v1,v2]"
+func EncodeCalldata(signature string, args []string) ([]byte, error) {
+	sig, err := ParseSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != len(sig.Inputs) {
+		return nil, fmt.Errorf("method %s expects %d arguments, got %d", sig.Canonical, len(sig.Inputs), len(args))
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		value, err := parseABIValue(sig.Inputs[i].Type, arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d for %s: %w", i, sig.Canonical, err)
+		}
+		values[i] = value.Interface()
+	}
+	packed, err := sig.Inputs.Pack(values...)
+	if err != nil {
+		return nil, err
+	}
+	return append(sig.Selector(), packed...), nil
+}
+
+// DecodeCalldata decodes [calldata] using the method signature among [signatures]
+// whose selector matches it
+func DecodeCalldata(signatures []string, calldata []byte) (*Decoded, error) {
+	return decodeWithSelector("method", signatures, calldata)
+}
+
+// DecodeRevert decodes the revert data [data] using the error signature among
+// [signatures] whose selector matches it, or the builtin solidity errors
+func DecodeRevert(signatures []string, data []byte) (*Decoded, error) {
+	return decodeWithSelector("error", append(builtinErrorSignatures, signatures...), data)
+}
+
+// DecodeLog decodes an event log using the event signature among [signatures]
+// whose topic matches the first of [topics]. Indexed parameters of dynamic types
+// are only available as the hash of their value
+func DecodeLog(signatures []string, topics []common.Hash, data []byte) (*Decoded, error) {
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("log has no topics: anonymous events are not supported")
+	}
+	for _, signature := range signatures {
+		sig, err := ParseSignature(signature)
+		if err != nil {
+			return nil, err
+		}
+		if sig.Topic() != topics[0] {
+			continue
+		}
+		nonIndexed, err := sig.Inputs.NonIndexed().Unpack(data)
+		if err != nil {
+			return nil, fmt.Errorf("failure decoding %s data: %w", sig.Canonical, err)
+		}
+		values := make([]interface{}, 0, len(sig.Inputs))
+		topicIndex := 1
+		for _, input := range sig.Inputs {
+			if !input.Indexed {
+				values = append(values, nonIndexed[0])
+				nonIndexed = nonIndexed[1:]
+				continue
+			}
+			if topicIndex >= len(topics) {
+				return nil, fmt.Errorf("log has %d topics, expected more for %s", len(topics), sig.Canonical)
+			}
+			topic := topics[topicIndex]
+			topicIndex++
+			if isDynamicType(input.Type) {
+				values = append(values, topic)
+				continue
+			}
+			value, err := abi.Arguments{{Type: input.Type}}.Unpack(topic.Bytes())
+			if err != nil {
+				return nil, fmt.Errorf("failure decoding %s topic %d: %w", sig.Canonical, topicIndex-1, err)
+			}
+			values = append(values, value[0])
+		}
+		return &Decoded{Signature: sig, Values: values}, nil
+	}
+	return nil, fmt.Errorf("no event signature matches topic %s", topics[0])
+}
+
+func decodeWithSelector(kind string, signatures []string, data []byte) (*Decoded, error) {
+	if len(data) < selectorLen {
+		return nil, fmt.Errorf("data is too short to contain a %s selector", kind)
+	}
+	for _, signature := range signatures {
+		sig, err := ParseSignature(signature)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(sig.Selector(), data[:selectorLen]) {
+			continue
+		}
+		values, err := sig.Inputs.Unpack(data[selectorLen:])
+		if err != nil {
+			return nil, fmt.Errorf("failure decoding %s arguments: %w", sig.Canonical, err)
+		}
+		return &Decoded{Signature: sig, Values: values}, nil
+	}
+	return nil, fmt.Errorf("no %s signature matches selector %s", kind, hexutil.Encode(data[:selectorLen]))
+}
+
+// FormatABIValue returns a readable text form of a decoded ABI value, compatible
+// with the one accepted by EncodeCalldata
+func FormatABIValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return hexutil.Encode(v)
+	case string:
+		return strconv.Quote(v)
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		return formatABIList(rv)
+	case reflect.Slice:
+		return formatABIList(rv)
+	case reflect.Struct:
+		fields := make([]string, rv.NumField())
+		for i := range fields {
+			fields[i] = FormatABIValue(rv.Field(i).Interface())
+		}
+		return "(" + strings.Join(fields, ",") + ")"
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+func formatABIList(rv reflect.Value) string {
+	elems := make([]string, rv.Len())
+	for i := range elems {
+		elems[i] = FormatABIValue(rv.Index(i).Interface())
+	}
+	return "[" + strings.Join(elems, ",") + "]"
+}
+
+func isDynamicType(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseABIType parses a type given in signature notation
+func parseABIType(s string) (abi.Type, error) {
+	typeStr, components, err := parseABITypeMarshaling(s)
+	if err != nil {
+		return abi.Type{}, err
+	}
+	return abi.NewType(typeStr, "", components)
+}
+
+// parseABITypeMarshaling converts a type given in signature notation into the json ABI
+// notation, where tuples are described by components
+func parseABITypeMarshaling(s string) (string, []abi.ArgumentMarshaling, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "["):
+		inner, err := removeSurroundingBrackets(s)
+		if err != nil {
+			return "", nil, err
+		}
+		typeStr, components, err := parseABITypeMarshaling(inner)
+		if err != nil {
+			return "", nil, err
+		}
+		return typeStr + "[]", components, nil
+	case strings.HasPrefix(s, "("):
+		end := matchingCloseIndex(s)
+		if end == -1 {
+			return "", nil, fmt.Errorf("unbalanced parenthesis in type %q", s)
+		}
+		suffix := s[end+1:]
+		if strings.Trim(suffix, "[]0123456789") != "" {
+			return "", nil, fmt.Errorf("invalid type %q", s)
+		}
+		components := []abi.ArgumentMarshaling{}
+		for i, field := range splitTopLevel(s[1:end], ',') {
+			typeStr, fieldComponents, err := parseABITypeMarshaling(field)
+			if err != nil {
+				return "", nil, err
+			}
+			components = append(components, abi.ArgumentMarshaling{
+				Name:       fmt.Sprintf("field%d", i),
+				Type:       typeStr,
+				Components: fieldComponents,
+			})
+		}
+		return "tuple" + suffix, components, nil
+	default:
+		return s, nil, nil
+	}
+}
+
+// parseABIValue parses [s] into a value of the go type used by the ABI packer for [t]
+func parseABIValue(t abi.Type, s string) (reflect.Value, error) {
+	s = strings.TrimSpace(s)
+	switch t.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("invalid address %q", s)
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bool %q", s)
+		}
+		return reflect.ValueOf(b), nil
+	case abi.StringTy:
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			s = unquoted
+		}
+		return reflect.ValueOf(s), nil
+	case abi.BytesTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid hex bytes %q: %w", s, err)
+		}
+		return reflect.ValueOf(b), nil
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid hex bytes %q: %w", s, err)
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes for %s, got %d", t.Size, t, len(b))
+		}
+		v := reflect.New(t.GetType()).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v, nil
+	case abi.IntTy, abi.UintTy:
+		n, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid number %q", s)
+		}
+		if t.T == abi.UintTy && n.Sign() < 0 {
+			return reflect.Value{}, fmt.Errorf("negative value %s for %s", n, t)
+		}
+		bitLen := n.BitLen()
+		if t.T == abi.IntTy && n.Sign() < 0 {
+			// two's complement range is one larger on the negative side
+			bitLen = new(big.Int).Add(n, big.NewInt(1)).BitLen()
+		}
+		if (t.T == abi.IntTy && bitLen >= t.Size) || (t.T == abi.UintTy && bitLen > t.Size) {
+			return reflect.Value{}, fmt.Errorf("value %s out of range for %s", n, t)
+		}
+		goType := t.GetType()
+		if goType == reflect.TypeOf(&big.Int{}) {
+			return reflect.ValueOf(n), nil
+		}
+		v := reflect.New(goType).Elem()
+		if t.T == abi.IntTy {
+			v.SetInt(n.Int64())
+		} else {
+			v.SetUint(n.Uint64())
+		}
+		return v, nil
+	case abi.SliceTy, abi.ArrayTy:
+		inner, err := removeSurroundingBrackets(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		elems := splitTopLevel(inner, ',')
+		var v reflect.Value
+		if t.T == abi.SliceTy {
+			v = reflect.MakeSlice(t.GetType(), len(elems), len(elems))
+		} else {
+			if len(elems) != t.Size {
+				return reflect.Value{}, fmt.Errorf("expected %d elements for %s, got %d", t.Size, t, len(elems))
+			}
+			v = reflect.New(t.GetType()).Elem()
+		}
+		for i, elem := range elems {
+			elemValue, err := parseABIValue(*t.Elem, elem)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v.Index(i).Set(elemValue)
+		}
+		return v, nil
+	case abi.TupleTy:
+		inner, err := removeSurroundingParenthesis(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		fields := splitTopLevel(inner, ',')
+		if len(fields) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("expected %d fields for %s, got %d", len(t.TupleElems), t, len(fields))
+		}
+		v := reflect.New(t.GetType()).Elem()
+		for i, field := range fields {
+			fieldValue, err := parseABIValue(*t.TupleElems[i], field)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v.Field(i).Set(fieldValue)
+		}
+		return v, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// splitTopLevel splits [s] by [sep], without splitting inside parenthesis, brackets,
+// or double quoted strings. Empty parts are skipped
+func splitTopLevel(s string, sep rune) []string {
+	parts := []string{}
+	part := strings.Builder{}
+	depth := 0
+	quoted := false
+	escaped := false
+	addPart := func() {
+		if p := strings.TrimSpace(part.String()); p != "" {
+			parts = append(parts, p)
+		}
+		part.Reset()
+	}
+	for _, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == sep && depth == 0:
+			addPart()
+			continue
+		}
+		part.WriteRune(c)
+	}
+	addPart()
+	return parts
+}
+
+// matchingCloseIndex returns the index of the parenthesis closing the one at the
+// start of [s], or -1 if not found
+func matchingCloseIndex(s string) int {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
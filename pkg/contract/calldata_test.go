@@ -0,0 +1,92 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeCalldata(t *testing.T) {
+	require := require.New(t)
+	calldata, err := EncodeCalldata("transferOwnership(address)", []string{"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"})
+	require.NoError(err)
+	require.Equal("0xf2fde38b0000000000000000000000008db97c7cece249c2b98bdc0226cc4c2a57bf52fc", hexutil.Encode(calldata))
+
+	calldata, err = EncodeCalldata("transfer(address,uint256)", []string{"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", "0x10"})
+	require.NoError(err)
+	require.Equal("0xa9059cbb0000000000000000000000008db97c7cece249c2b98bdc0226cc4c2a57bf52fc0000000000000000000000000000000000000000000000000000000000000010", hexutil.Encode(calldata))
+
+	_, err = EncodeCalldata("transferOwnership(address)", nil)
+	require.ErrorContains(err, "expects 1 arguments, got 0")
+	_, err = EncodeCalldata("transferOwnership(address)", []string{"0x1234"})
+	require.ErrorContains(err, "invalid address")
+	_, err = EncodeCalldata("setFee(uint8)", []string{"256"})
+	require.ErrorContains(err, "out of range")
+	_, err = EncodeCalldata("setFee(int8)", []string{"-129"})
+	require.ErrorContains(err, "out of range")
+	_, err = EncodeCalldata("setFee(int8)", []string{"-128"})
+	require.NoError(err)
+}
+
+func TestEncodeDecodeCalldata(t *testing.T) {
+	require := require.New(t)
+	signature := "initializeValidatorRegistration((bytes,bytes,uint64,(uint32,[address]),(uint32,address[])),uint16,bytes32,string,bool[2])"
+	args := []string{
+		"(0x0102,0x03,100,(1,[0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC]),(0,[]))",
+		"20",
+		"0x" + common.Bytes2Hex(common.HexToHash("0xab").Bytes()),
+		`"hello, world"`,
+		"[true,false]",
+	}
+	calldata, err := EncodeCalldata(signature, args)
+	require.NoError(err)
+	decoded, err := DecodeCalldata([]string{"transferOwnership(address)", signature}, calldata)
+	require.NoError(err)
+	require.Equal("initializeValidatorRegistration((bytes,bytes,uint64,(uint32,address[]),(uint32,address[])),uint16,bytes32,string,bool[2])", decoded.Signature.Canonical)
+	require.Len(decoded.Values, len(args))
+	for i, value := range decoded.Values {
+		require.Equal(args[i], FormatABIValue(value))
+	}
+
+	_, err = DecodeCalldata([]string{"transferOwnership(address)"}, calldata)
+	require.ErrorContains(err, "no method signature matches selector")
+}
+
+func TestDecodeRevert(t *testing.T) {
+	require := require.New(t)
+	// Error("not owner")
+	revertData, err := EncodeCalldata("Error(string)", []string{`"not owner"`})
+	require.NoError(err)
+	decoded, err := DecodeRevert(nil, revertData)
+	require.NoError(err)
+	require.Equal("Error(string)", decoded.Signature.Canonical)
+	require.Equal([]interface{}{"not owner"}, decoded.Values)
+
+	revertData, err = EncodeCalldata("UnauthorizedOwner(address)", []string{"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"})
+	require.NoError(err)
+	decoded, err = DecodeRevert([]string{"UnauthorizedOwner(address)"}, revertData)
+	require.NoError(err)
+	require.Equal([]interface{}{common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")}, decoded.Values)
+}
+
+func TestDecodeLog(t *testing.T) {
+	require := require.New(t)
+	signature := "DelegatorRegistered(bytes32 indexed,bytes32 indexed,uint256)"
+	sig, err := ParseSignature(signature)
+	require.NoError(err)
+	data, err := sig.Inputs.NonIndexed().Pack(big.NewInt(1000))
+	require.NoError(err)
+	topics := []common.Hash{sig.Topic(), common.HexToHash("0x01"), common.HexToHash("0x02")}
+	decoded, err := DecodeLog([]string{"OwnershipTransferred(address indexed,address indexed)", signature}, topics, data)
+	require.NoError(err)
+	require.Equal("DelegatorRegistered(bytes32,bytes32,uint256)", decoded.Signature.Canonical)
+	require.Equal([]interface{}{[32]byte(topics[1]), [32]byte(topics[2]), big.NewInt(1000)}, decoded.Values)
+
+	_, err = DecodeLog([]string{signature}, topics[:2], data)
+	require.ErrorContains(err, "expected more")
+}
@@ -5,16 +5,13 @@ package contract
 import (
 	_ "embed"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
-	"reflect"
-	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/evm"
-	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	contractSDK "github.com/ava-labs/avalanche-cli/sdk/contract"
 	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
 	"github.com/ava-labs/subnet-evm/core/types"
@@ -23,190 +20,9 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
-var ErrFailedReceiptStatus = fmt.Errorf("failed receipt status")
-
-func removeSurroundingParenthesis(s string) (string, error) {
-	s = strings.TrimSpace(s)
-	if len(s) > 0 {
-		if string(s[0]) != "(" || string(s[len(s)-1]) != ")" {
-			return "", fmt.Errorf("expected esp %q to be surrounded by parenthesis", s)
-		}
-		s = s[1 : len(s)-1]
-	}
-	return s, nil
-}
-
-func removeSurroundingBrackets(s string) (string, error) {
-	s = strings.TrimSpace(s)
-	if len(s) > 0 {
-		if string(s[0]) != "[" || string(s[len(s)-1]) != "]" {
-			return "", fmt.Errorf("expected esp %q to be surrounded by parenthesis", s)
-		}
-		s = s[1 : len(s)-1]
-	}
-	return s, nil
-}
-
-func getWords(s string) []string {
-	words := []string{}
-	word := ""
-	parenthesisCount := 0
-	insideBrackets := false
-	for _, rune := range s {
-		c := string(rune)
-		if parenthesisCount > 0 {
-			word += c
-			if c == "(" {
-				parenthesisCount++
-			}
-			if c == ")" {
-				parenthesisCount--
-				if parenthesisCount == 0 {
-					words = append(words, word)
-					word = ""
-				}
-			}
-			continue
-		}
-		if insideBrackets {
-			word += c
-			if c == "]" {
-				words = append(words, word)
-				word = ""
-				insideBrackets = false
-			}
-			continue
-		}
-		if c == " " || c == "," || c == "(" || c == "[" {
-			if word != "" {
-				words = append(words, word)
-				word = ""
-			}
-		}
-		if c == " " || c == "," {
-			continue
-		}
-		if c == "(" {
-			parenthesisCount++
-		}
-		if c == "[" {
-			insideBrackets = true
-		}
-		word += c
-	}
-	if word != "" {
-		words = append(words, word)
-	}
-	return words
-}
-
-func getMap(
-	types []string,
-	params interface{},
-) ([]map[string]interface{}, error) {
-	r := []map[string]interface{}{}
-	for i, t := range types {
-		var (
-			param      interface{}
-			name       string
-			structName string
-		)
-		rt := reflect.ValueOf(params)
-		if rt.Kind() == reflect.Ptr {
-			rt = rt.Elem()
-		}
-		if rt.Kind() == reflect.Slice {
-			if rt.Len() != len(types) {
-				if rt.Len() == 1 {
-					return getMap(types, rt.Index(0).Interface())
-				} else {
-					return nil, fmt.Errorf(
-						"inconsistency in slice len between method esp %q and given params %#v: expected %d got %d",
-						types,
-						params,
-						len(types),
-						rt.Len(),
-					)
-				}
-			}
-			param = rt.Index(i).Interface()
-		} else if rt.Kind() == reflect.Struct {
-			if rt.NumField() < len(types) {
-				return nil, fmt.Errorf(
-					"inconsistency in struct len between method esp %q and given params %#v: expected %d got %d",
-					types,
-					params,
-					len(types),
-					rt.NumField(),
-				)
-			}
-			name = rt.Type().Field(i).Name
-			structName = rt.Type().Field(i).Type.Name()
-			param = rt.Field(i).Interface()
-		}
-		m := map[string]interface{}{}
-		switch {
-		case string(t[0]) == "(":
-			// struct type
-			var err error
-			t, err = removeSurroundingParenthesis(t)
-			if err != nil {
-				return nil, err
-			}
-			m["components"], err = getMap(getWords(t), param)
-			if err != nil {
-				return nil, err
-			}
-			if structName != "" {
-				m["internalType"] = "struct " + structName
-			} else {
-				m["internalType"] = "tuple"
-			}
-			m["type"] = "tuple"
-			m["name"] = name
-		case string(t[0]) == "[":
-			var err error
-			t, err = removeSurroundingBrackets(t)
-			if err != nil {
-				return nil, err
-			}
-			if string(t[0]) == "(" {
-				t, err = removeSurroundingParenthesis(t)
-				if err != nil {
-					return nil, err
-				}
-				rt := reflect.ValueOf(param)
-				if rt.Kind() != reflect.Slice {
-					return nil, fmt.Errorf("expected param for field %d of esp %q to be an slice", i, types)
-				}
-				param = reflect.Zero(rt.Type().Elem()).Interface()
-				structName = rt.Type().Elem().Name()
-				m["components"], err = getMap(getWords(t), param)
-				if err != nil {
-					return nil, err
-				}
-				if structName != "" {
-					m["internalType"] = "struct " + structName + "[]"
-				} else {
-					m["internalType"] = "tuple[]"
-				}
-				m["type"] = "tuple[]"
-				m["name"] = name
-			} else {
-				m["internalType"] = fmt.Sprintf("%s[]", t)
-				m["type"] = fmt.Sprintf("%s[]", t)
-				m["name"] = name
-			}
-		default:
-			m["internalType"] = t
-			m["type"] = t
-			m["name"] = name
-		}
-		r = append(r, m)
-	}
-	return r, nil
-}
+var ErrFailedReceiptStatus = contractSDK.ErrFailedReceiptStatus
 
+// ParseSpec returns the method name and the json ABI of the solidity method signature [esp]
 func ParseSpec(
 	esp string,
 	indexedFields []int,
@@ -216,77 +32,7 @@ func ParseSpec(
 	view bool,
 	params ...interface{},
 ) (string, string, error) {
-	index := strings.Index(esp, "(")
-	if index == -1 {
-		return esp, "", nil
-	}
-	name := esp[:index]
-	types := esp[index:]
-	inputs := ""
-	outputs := ""
-	index = strings.Index(types, "->")
-	if index == -1 {
-		inputs = types
-	} else {
-		inputs = types[:index]
-		outputs = types[index+2:]
-	}
-	var err error
-	inputs, err = removeSurroundingParenthesis(inputs)
-	if err != nil {
-		return "", "", err
-	}
-	outputs, err = removeSurroundingParenthesis(outputs)
-	if err != nil {
-		return "", "", err
-	}
-	inputTypes := getWords(inputs)
-	outputTypes := getWords(outputs)
-	inputsMaps, err := getMap(inputTypes, params)
-	if err != nil {
-		return "", "", err
-	}
-	outputsMaps, err := getMap(outputTypes, nil)
-	if err != nil {
-		return "", "", err
-	}
-	if event {
-		for i := range inputsMaps {
-			if utils.Belongs(indexedFields, i) {
-				inputsMaps[i]["indexed"] = true
-			}
-		}
-	}
-	abiMap := []map[string]interface{}{
-		{
-			"inputs": inputsMaps,
-		},
-	}
-	switch {
-	case paid:
-		abiMap[0]["stateMutability"] = "payable"
-	case view:
-		abiMap[0]["stateMutability"] = "view"
-	default:
-		abiMap[0]["stateMutability"] = "nonpayable"
-	}
-	switch {
-	case constructor:
-		abiMap[0]["type"] = "constructor"
-	case event:
-		abiMap[0]["type"] = "event"
-		abiMap[0]["name"] = name
-		delete(abiMap[0], "stateMutability")
-	default:
-		abiMap[0]["type"] = "function"
-		abiMap[0]["outputs"] = outputsMaps
-		abiMap[0]["name"] = name
-	}
-	abiBytes, err := json.MarshalIndent(abiMap, "", "  ")
-	if err != nil {
-		return "", "", err
-	}
-	return name, string(abiBytes), nil
+	return contractSDK.ParseSpec(esp, indexedFields, constructor, event, paid, view, params...)
 }
 
 // get method name and types from [methodsSpec], then call it
@@ -489,6 +235,74 @@ func DebugTraceCall(
 	methodSpec string,
 	params ...interface{},
 ) (map[string]interface{}, error) {
+	toTrace, err := getCallToTrace(privateKey, contractAddress, payment, methodSpec, params...)
+	if err != nil {
+		return nil, err
+	}
+	client, err := evm.GetRPCClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return evm.DebugTraceCall(
+		client,
+		toTrace,
+	)
+}
+
+// get method name and types from [methodsSpec], then execute the call
+// to it at the smart contract [contractAddress] with the given [params]
+// on top of the latest state of the chain, without issuing a tx.
+// returns the call trace, including the emitted logs
+func SimulateTxToMethod(
+	rpcURL string,
+	privateKey string,
+	contractAddress common.Address,
+	payment *big.Int,
+	description string,
+	errorSignatureToError map[string]error,
+	methodSpec string,
+	params ...interface{},
+) (evm.CallTrace, error) {
+	toTrace, err := getCallToTrace(privateKey, contractAddress, payment, methodSpec, params...)
+	if err != nil {
+		return evm.CallTrace{}, err
+	}
+	client, err := evm.GetRPCClient(rpcURL)
+	if err != nil {
+		return evm.CallTrace{}, err
+	}
+	defer client.Close()
+	trace, err := evm.SimulateCall(client, toTrace)
+	if err != nil {
+		return trace, fmt.Errorf("%w. Simulation needs the debug API to be enabled on the node", err)
+	}
+	if trace.Error == "" {
+		return trace, nil
+	}
+	errorFromSignature, err := evm.GetErrorFromTrace(
+		map[string]interface{}{"output": trace.Output.String()},
+		errorSignatureToError,
+	)
+	if err != nil && !errors.Is(err, evm.ErrUnknownErrorSelector) {
+		ux.Logger.RedXToUser("failure traying to match error selector on trace: %s", err)
+	}
+	if errorFromSignature != nil {
+		return trace, errorFromSignature
+	}
+	if trace.RevertReason != "" {
+		return trace, fmt.Errorf("%s failed: %s: %s", description, trace.Error, trace.RevertReason)
+	}
+	return trace, fmt.Errorf("%s failed: %s", description, trace.Error)
+}
+
+func getCallToTrace(
+	privateKey string,
+	contractAddress common.Address,
+	payment *big.Int,
+	methodSpec string,
+	params ...interface{},
+) (map[string]string, error) {
 	methodName, methodABI, err := ParseSpec(methodSpec, nil, false, false, false, false, params...)
 	if err != nil {
 		return nil, err
@@ -504,11 +318,6 @@ func DebugTraceCall(
 	if err != nil {
 		return nil, err
 	}
-	client, err := evm.GetRPCClient(rpcURL)
-	if err != nil {
-		return nil, err
-	}
-	defer client.Close()
 	pk, err := crypto.HexToECDSA(privateKey)
 	if err != nil {
 		return nil, err
@@ -523,10 +332,7 @@ func DebugTraceCall(
 		hexBytes, _ := hexutil.Big(*payment).MarshalText()
 		data["value"] = string(hexBytes)
 	}
-	return evm.DebugTraceCall(
-		client,
-		data,
-	)
+	return data, nil
 }
 
 func CallToMethod(
@@ -7,7 +7,6 @@ import (
 
 	cmdflags "github.com/ava-labs/avalanche-cli/cmd/flags"
 	"github.com/ava-labs/avalanche-cli/pkg/application"
-	"github.com/ava-labs/avalanche-cli/pkg/models"
 
 	"github.com/spf13/cobra"
 )
@@ -92,7 +91,7 @@ func (pkf *PrivateKeyFlags) GetPrivateKey(
 	}
 	privateKey := pkf.PrivateKey
 	if pkf.KeyName != "" {
-		k, err := app.GetKey(pkf.KeyName, models.NewLocalNetwork(), false)
+		k, err := app.GetKey(pkf.KeyName, app.GetLocalNetwork(), false)
 		if err != nil {
 			return "", err
 		}
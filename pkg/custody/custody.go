@@ -0,0 +1,316 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package custody exports the exact payloads P-Chain and EVM transactions need to be signed
+// with, so they can be signed by custodial signers that do not expose private keys, and
+// applies the signatures those signers return.
+package custody
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// Kinds of transactions
+const (
+	PChainKind = "P-Chain"
+	EVMKind    = "EVM"
+)
+
+// Formats signing payloads can be exported in
+const (
+	// SignDoc JSON, describing the tx and the digests each signer has to sign
+	SignDocFormat = "signdoc"
+	// request body of a Fireblocks RAW signing transaction
+	FireblocksRawFormat = "fireblocks-raw"
+	// request body of a Coinbase Prime onchain transaction, signed but not broadcasted
+	CoinbasePrimeFormat = "coinbase-prime"
+)
+
+// Formats lists the supported export formats
+var Formats = []string{SignDocFormat, FireblocksRawFormat, CoinbasePrimeFormat}
+
+// signature length in [r || s || v] format
+const signatureLen = 65
+
+var secp256k1HalfN = new(big.Int).Rsh(ethcrypto.S256().Params().N, 1)
+
+// SigningPayload is a digest that [Signer] has to sign with its secp256k1 key
+type SigningPayload struct {
+	Signer string `json:"signer"`
+	Digest string `json:"digest"`
+}
+
+// SignDoc describes a transaction waiting for signatures, with a human readable summary
+// for the signers to review, and the exact digests they have to sign
+type SignDoc struct {
+	Kind    string   `json:"kind"`
+	Network string   `json:"network,omitempty"`
+	ChainID string   `json:"chainId,omitempty"`
+	Summary []string `json:"summary"`
+	// bytes the digests are computed from, for signers that hash the payload themselves
+	UnsignedTx string           `json:"unsignedTx"`
+	Payloads   []SigningPayload `json:"payloads"`
+}
+
+// FireblocksRawRequest is the body of a Fireblocks transaction request that raw signs the
+// digests of a SignDoc
+type FireblocksRawRequest struct {
+	Operation       string                    `json:"operation"`
+	AssetID         string                    `json:"assetId,omitempty"`
+	Source          FireblocksSource          `json:"source"`
+	Note            string                    `json:"note"`
+	ExtraParameters FireblocksExtraParameters `json:"extraParameters"`
+}
+
+// FireblocksSource is the vault account that signs a Fireblocks request
+type FireblocksSource struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// FireblocksExtraParameters holds the messages of a Fireblocks RAW request
+type FireblocksExtraParameters struct {
+	RawMessageData FireblocksRawMessageData `json:"rawMessageData"`
+}
+
+// FireblocksRawMessageData is the list of digests a Fireblocks RAW request signs
+type FireblocksRawMessageData struct {
+	Messages  []FireblocksMessage `json:"messages"`
+	Algorithm string              `json:"algorithm"`
+}
+
+// FireblocksMessage is a digest to be signed, hex encoded without prefix
+type FireblocksMessage struct {
+	Content string `json:"content"`
+}
+
+// FireblocksOptions selects the Fireblocks vault account and asset that sign a request
+type FireblocksOptions struct {
+	VaultAccountID string
+	AssetID        string
+}
+
+// FireblocksRaw returns the Fireblocks RAW signing request for the digests of [doc]
+func (doc SignDoc) FireblocksRaw(opts FireblocksOptions) FireblocksRawRequest {
+	messages := make([]FireblocksMessage, 0, len(doc.Payloads))
+	for _, payload := range doc.Payloads {
+		messages = append(messages, FireblocksMessage{Content: strings.TrimPrefix(payload.Digest, "0x")})
+	}
+	return FireblocksRawRequest{
+		Operation: "RAW",
+		AssetID:   opts.AssetID,
+		Source: FireblocksSource{
+			Type: "VAULT_ACCOUNT",
+			ID:   opts.VaultAccountID,
+		},
+		Note: strings.Join(doc.Summary, ". "),
+		ExtraParameters: FireblocksExtraParameters{
+			RawMessageData: FireblocksRawMessageData{
+				Messages:  messages,
+				Algorithm: "MPC_ECDSA_SECP256K1",
+			},
+		},
+	}
+}
+
+// CoinbasePrimeRequest is the body of a Coinbase Prime onchain transaction request that
+// signs the unsigned EVM tx of a SignDoc, leaving it to the CLI to broadcast it
+type CoinbasePrimeRequest struct {
+	RawUnsignedTxn string                 `json:"raw_unsigned_txn"`
+	RPC            CoinbasePrimeRPC       `json:"rpc"`
+	EVMParams      CoinbasePrimeEVMParams `json:"evm_params"`
+}
+
+// CoinbasePrimeRPC sets how Coinbase Prime broadcasts a request
+type CoinbasePrimeRPC struct {
+	SkipBroadcast bool `json:"skip_broadcast"`
+}
+
+// CoinbasePrimeEVMParams sets how Coinbase Prime handles an EVM request
+type CoinbasePrimeEVMParams struct {
+	// keep the fees of the tx, as they are part of what is reviewed
+	DisableDynamicGas bool   `json:"disable_dynamic_gas"`
+	ChainID           string `json:"chain_id"`
+}
+
+// CoinbasePrime returns the Coinbase Prime onchain transaction request for the EVM tx
+// of [doc]. Coinbase Prime only signs whole EVM transactions, so P-Chain docs are not supported
+func (doc SignDoc) CoinbasePrime() (CoinbasePrimeRequest, error) {
+	if doc.Kind != EVMKind {
+		return CoinbasePrimeRequest{}, fmt.Errorf("format %s only supports %s transactions", CoinbasePrimeFormat, EVMKind)
+	}
+	return CoinbasePrimeRequest{
+		RawUnsignedTxn: strings.TrimPrefix(doc.UnsignedTx, "0x"),
+		RPC: CoinbasePrimeRPC{
+			SkipBroadcast: true,
+		},
+		EVMParams: CoinbasePrimeEVMParams{
+			DisableDynamicGas: true,
+			ChainID:           doc.ChainID,
+		},
+	}, nil
+}
+
+// Export returns [doc] in [format]
+func (doc SignDoc) Export(format string, fireblocksOpts FireblocksOptions) (interface{}, error) {
+	switch format {
+	case SignDocFormat:
+		return doc, nil
+	case FireblocksRawFormat:
+		return doc.FireblocksRaw(fireblocksOpts), nil
+	case CoinbasePrimeFormat:
+		return doc.CoinbasePrime()
+	default:
+		return nil, fmt.Errorf("unsupported format %q, expected one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Signature is a secp256k1 recoverable signature of Digest, in [r || s || v] format,
+// with v being the recovery id 0 or 1, and s in the lower half of the curve order
+type Signature struct {
+	Digest    []byte
+	Signature []byte
+}
+
+// signatures file of the signdoc format
+type signDocSignatures struct {
+	Signatures []struct {
+		Digest    string `json:"digest"`
+		Signature string `json:"signature"`
+	} `json:"signatures"`
+}
+
+// response of a Fireblocks RAW signing transaction
+type fireblocksSignatures struct {
+	SignedMessages []struct {
+		Content   string `json:"content"`
+		Signature struct {
+			FullSig string `json:"fullSig"`
+			R       string `json:"r"`
+			S       string `json:"s"`
+			V       int    `json:"v"`
+		} `json:"signature"`
+	} `json:"signedMessages"`
+}
+
+// onchain details of a Coinbase Prime transaction, either the whole transaction or just
+// its details
+type coinbasePrimeSignatures struct {
+	Transaction struct {
+		OnchainDetails coinbasePrimeOnchainDetails `json:"onchain_details"`
+	} `json:"transaction"`
+	OnchainDetails coinbasePrimeOnchainDetails `json:"onchain_details"`
+}
+
+type coinbasePrimeOnchainDetails struct {
+	SignedTransaction string `json:"signed_transaction"`
+}
+
+// ParseSignatures parses the signatures returned by a custodial signer, either as
+// {"signatures": [{"digest": ..., "signature": ...}]} with 65 bytes [r || s || v] signatures,
+// as the signedMessages of a Fireblocks RAW transaction, or as the signed EVM tx of a
+// Coinbase Prime onchain transaction
+func ParseSignatures(bs []byte) ([]Signature, error) {
+	signatures := []Signature{}
+	coinbasePrime := coinbasePrimeSignatures{}
+	if err := json.Unmarshal(bs, &coinbasePrime); err != nil {
+		return nil, fmt.Errorf("invalid signatures file: %w", err)
+	}
+	for _, signedTx := range []string{
+		coinbasePrime.Transaction.OnchainDetails.SignedTransaction,
+		coinbasePrime.OnchainDetails.SignedTransaction,
+	} {
+		if signedTx == "" {
+			continue
+		}
+		signature, err := evmTxSignature(signedTx)
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, signature)
+	}
+	fireblocks := fireblocksSignatures{}
+	if err := json.Unmarshal(bs, &fireblocks); err != nil {
+		return nil, fmt.Errorf("invalid signatures file: %w", err)
+	}
+	for _, message := range fireblocks.SignedMessages {
+		digest, err := decodeHex(message.Content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signed message content %q: %w", message.Content, err)
+		}
+		rs := message.Signature.FullSig
+		if rs == "" {
+			rs = message.Signature.R + message.Signature.S
+		}
+		sigBytes, err := decodeHex(rs)
+		if err != nil || len(sigBytes) != signatureLen-1 {
+			return nil, fmt.Errorf("invalid signature of message %s: expected 64 bytes r || s", message.Content)
+		}
+		sig, err := normalizeSignature(append(sigBytes, byte(message.Signature.V)))
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, Signature{Digest: digest, Signature: sig})
+	}
+	signDoc := signDocSignatures{}
+	if err := json.Unmarshal(bs, &signDoc); err != nil {
+		return nil, fmt.Errorf("invalid signatures file: %w", err)
+	}
+	for _, entry := range signDoc.Signatures {
+		digest, err := decodeHex(entry.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid digest %q: %w", entry.Digest, err)
+		}
+		sigBytes, err := decodeHex(entry.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature of digest %s: %w", entry.Digest, err)
+		}
+		sig, err := normalizeSignature(sigBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature of digest %s: %w", entry.Digest, err)
+		}
+		signatures = append(signatures, Signature{Digest: digest, Signature: sig})
+	}
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("no signatures found")
+	}
+	return signatures, nil
+}
+
+// normalizeSignature converts a 65 bytes [r || s || v] signature to the form expected by
+// P-Chain and EVM chains: v as recovery id 0 or 1, and s in the lower half of the curve order
+func normalizeSignature(sig []byte) ([]byte, error) {
+	if len(sig) != signatureLen {
+		return nil, fmt.Errorf("expected %d bytes signature, got %d", signatureLen, len(sig))
+	}
+	sig = append([]byte{}, sig...)
+	v := sig[signatureLen-1]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, fmt.Errorf("invalid signature recovery id %d", sig[signatureLen-1])
+	}
+	s := new(big.Int).SetBytes(sig[32:64])
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s.Sub(ethcrypto.S256().Params().N, s)
+		s.FillBytes(sig[32:64])
+		v ^= 1
+	}
+	sig[signatureLen-1] = v
+	return sig, nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "0x") {
+		s = "0x" + s
+	}
+	return hexutil.Decode(s)
+}
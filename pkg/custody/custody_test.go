@@ -0,0 +1,296 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package custody
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func signaturesFile(t *testing.T, digest []byte, sig []byte) []byte {
+	bs, err := json.Marshal(map[string]interface{}{
+		"signatures": []map[string]string{
+			{
+				"digest":    hexutil.Encode(digest),
+				"signature": hexutil.Encode(sig),
+			},
+		},
+	})
+	require.NoError(t, err)
+	return bs
+}
+
+func TestParseSignatures(t *testing.T) {
+	require := require.New(t)
+	key, err := secp256k1.NewPrivateKey()
+	require.NoError(err)
+	digest := ethcrypto.Keccak256([]byte("digest"))
+	sig, err := key.SignHash(digest)
+	require.NoError(err)
+
+	signatures, err := ParseSignatures(signaturesFile(t, digest, sig))
+	require.NoError(err)
+	require.Equal([]Signature{{Digest: digest, Signature: sig}}, signatures)
+
+	// high s and ethereum style v are normalized
+	highS := append([]byte{}, sig...)
+	s := new(big.Int).SetBytes(sig[32:64])
+	new(big.Int).Sub(ethcrypto.S256().Params().N, s).FillBytes(highS[32:64])
+	highS[64] = (sig[64] ^ 1) + 27
+	signatures, err = ParseSignatures(signaturesFile(t, digest, highS))
+	require.NoError(err)
+	require.Equal(sig, signatures[0].Signature)
+
+	// fireblocks response
+	fireblocks, err := json.Marshal(map[string]interface{}{
+		"signedMessages": []map[string]interface{}{
+			{
+				"content": hex.EncodeToString(digest),
+				"signature": map[string]interface{}{
+					"r": hex.EncodeToString(sig[:32]),
+					"s": hex.EncodeToString(sig[32:64]),
+					"v": sig[64],
+				},
+			},
+		},
+	})
+	require.NoError(err)
+	signatures, err = ParseSignatures(fireblocks)
+	require.NoError(err)
+	require.Equal([]Signature{{Digest: digest, Signature: sig}}, signatures)
+
+	_, err = ParseSignatures([]byte(`{}`))
+	require.ErrorContains(err, "no signatures found")
+	_, err = ParseSignatures(signaturesFile(t, digest, sig[:64]))
+	require.ErrorContains(err, "expected 65 bytes signature")
+}
+
+// testPChainBackend holds the UTXOs and owners a P-Chain tx is signed with
+type testPChainBackend struct {
+	utxos  map[ids.ID]*avax.UTXO
+	owners map[ids.ID]fx.Owner
+}
+
+func (b *testPChainBackend) GetUTXO(_ context.Context, chainID, utxoID ids.ID) (*avax.UTXO, error) {
+	utxo, ok := b.utxos[utxoID]
+	if chainID != avagoconstants.PlatformChainID || !ok {
+		return nil, database.ErrNotFound
+	}
+	return utxo, nil
+}
+
+func (b *testPChainBackend) GetOwner(_ context.Context, ownerID ids.ID) (fx.Owner, error) {
+	owner, ok := b.owners[ownerID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return owner, nil
+}
+
+func TestPChainSignatures(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	keys := []*secp256k1.PrivateKey{}
+	for i := 0; i < 3; i++ {
+		key, err := secp256k1.NewPrivateKey()
+		require.NoError(err)
+		keys = append(keys, key)
+	}
+	feeKey, authKeys := keys[0], keys[1:]
+	subnetID := ids.GenerateTestID()
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: ids.GenerateTestID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 1000,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{feeKey.Address()},
+			},
+		},
+	}
+	backend := &testPChainBackend{
+		utxos: map[ids.ID]*avax.UTXO{utxo.InputID(): utxo},
+		owners: map[ids.ID]fx.Owner{
+			subnetID: &secp256k1fx.OutputOwners{
+				Threshold: 2,
+				Addrs:     []ids.ShortID{authKeys[0].Address(), authKeys[1].Address()},
+			},
+		},
+	}
+	// the fee is paid from a custodial address, so the tx is created with no signature
+	tx := &txs.Tx{
+		Unsigned: &txs.RemoveSubnetValidatorTx{
+			BaseTx: txs.BaseTx{
+				BaseTx: avax.BaseTx{
+					NetworkID:    5,
+					BlockchainID: avagoconstants.PlatformChainID,
+					Ins: []*avax.TransferableInput{
+						{
+							UTXOID: utxo.UTXOID,
+							Asset:  utxo.Asset,
+							In: &secp256k1fx.TransferInput{
+								Amt:   1000,
+								Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+							},
+						},
+					},
+				},
+			},
+			NodeID:     ids.GenerateTestNodeID(),
+			Subnet:     subnetID,
+			SubnetAuth: &secp256k1fx.Input{SigIndices: []uint32{0, 1}},
+		},
+	}
+	remaining, err := getPChainRemainingSigners(ctx, backend, tx)
+	require.NoError(err)
+	require.Equal([]ids.ShortID{feeKey.Address(), authKeys[0].Address(), authKeys[1].Address()}, remaining)
+
+	_, hash, err := PChainSigningHash(tx)
+	require.NoError(err)
+	// the fee payer and the second subnet owner sign, the signature of an unrelated digest is ignored
+	feeSig, err := feeKey.SignHash(hash)
+	require.NoError(err)
+	authSig, err := authKeys[1].SignHash(hash)
+	require.NoError(err)
+	otherDigest := ethcrypto.Keccak256([]byte("other"))
+	otherSig, err := authKeys[0].SignHash(otherDigest)
+	require.NoError(err)
+	applied, err := applyPChainSignatures(ctx, backend, tx, []Signature{
+		{Digest: otherDigest, Signature: otherSig},
+		{Digest: hash, Signature: feeSig},
+		{Digest: hash, Signature: authSig},
+	})
+	require.NoError(err)
+	require.Equal([]ids.ShortID{feeKey.Address(), authKeys[1].Address()}, applied)
+	require.Len(tx.Creds, 2)
+	require.Equal(feeSig, tx.Creds[0].(*secp256k1fx.Credential).Sigs[0][:])
+	authCred := tx.Creds[1].(*secp256k1fx.Credential)
+	require.Equal([secp256k1.SignatureLen]byte{}, authCred.Sigs[0])
+	require.Equal(authSig, authCred.Sigs[1][:])
+	require.NotEqual(ids.Empty, tx.ID())
+
+	remaining, err = getPChainRemainingSigners(ctx, backend, tx)
+	require.NoError(err)
+	require.Equal([]ids.ShortID{authKeys[0].Address()}, remaining)
+}
+
+func TestEVMSignatures(t *testing.T) {
+	require := require.New(t)
+	key, err := ethcrypto.GenerateKey()
+	require.NoError(err)
+	from := ethcrypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	chainID := big.NewInt(43113)
+	evmTx := EVMTx{
+		RPCURL: "http://127.0.0.1:9650/ext/bc/C/rpc",
+		From:   from,
+		Tx: types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     3,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(25_000_000_000),
+			Gas:       21_000,
+			To:        &to,
+			Value:     big.NewInt(1000),
+		}),
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	doc, err := NewEVMSignDoc(evmTx)
+	require.NoError(err)
+	require.Equal(hexutil.Encode(signer.Hash(evmTx.Tx).Bytes()), doc.Payloads[0].Digest)
+	payload, digest, err := EVMSigningPayload(evmTx.Tx)
+	require.NoError(err)
+	require.Equal(ethcrypto.Keccak256(payload), digest)
+
+	txPath := filepath.Join(t.TempDir(), "tx.json")
+	require.NoError(SaveEVMTx(evmTx, txPath, false))
+	isEVM, err := IsEVMTxFile(txPath)
+	require.NoError(err)
+	require.True(isEVM)
+	evmTx, err = LoadEVMTx(txPath)
+	require.NoError(err)
+	require.False(evmTx.IsSigned())
+
+	otherKey, err := ethcrypto.GenerateKey()
+	require.NoError(err)
+	otherSig, err := ethcrypto.Sign(digest, otherKey)
+	require.NoError(err)
+	require.ErrorContains(ApplyEVMSignatures(&evmTx, []Signature{{Digest: digest, Signature: otherSig}}), "no signature")
+
+	sig, err := ethcrypto.Sign(digest, key)
+	require.NoError(err)
+	require.NoError(ApplyEVMSignatures(&evmTx, []Signature{{Digest: digest, Signature: sig}}))
+	require.True(evmTx.IsSigned())
+	sender, err := types.Sender(signer, evmTx.Tx)
+	require.NoError(err)
+	require.Equal(from, sender)
+}
+
+func TestCoinbasePrime(t *testing.T) {
+	require := require.New(t)
+	key, err := ethcrypto.GenerateKey()
+	require.NoError(err)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	chainID := big.NewInt(43114)
+	evmTx := EVMTx{
+		From: ethcrypto.PubkeyToAddress(key.PublicKey),
+		Tx: types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(25_000_000_000),
+			Gas:       21_000,
+			To:        &to,
+			Value:     big.NewInt(1000),
+		}),
+	}
+	doc, err := NewEVMSignDoc(evmTx)
+	require.NoError(err)
+	export, err := doc.Export(CoinbasePrimeFormat, FireblocksOptions{})
+	require.NoError(err)
+	request := export.(CoinbasePrimeRequest)
+	require.Equal(strings.TrimPrefix(doc.UnsignedTx, "0x"), request.RawUnsignedTxn)
+	require.True(request.RPC.SkipBroadcast)
+	require.True(request.EVMParams.DisableDynamicGas)
+	require.Equal("43114", request.EVMParams.ChainID)
+	_, err = SignDoc{Kind: PChainKind}.Export(CoinbasePrimeFormat, FireblocksOptions{})
+	require.ErrorContains(err, "only supports EVM")
+
+	// the signature is taken from the signed tx of the response
+	signedTx, err := types.SignTx(evmTx.Tx, types.LatestSignerForChainID(chainID), key)
+	require.NoError(err)
+	signedBytes, err := signedTx.MarshalBinary()
+	require.NoError(err)
+	response, err := json.Marshal(map[string]interface{}{
+		"transaction": map[string]interface{}{
+			"onchain_details": map[string]interface{}{
+				"signed_transaction": hexutil.Encode(signedBytes),
+			},
+		},
+	})
+	require.NoError(err)
+	signatures, err := ParseSignatures(response)
+	require.NoError(err)
+	require.Len(signatures, 1)
+	require.NoError(ApplyEVMSignatures(&evmTx, signatures))
+	require.Equal(signedTx.Hash(), evmTx.Tx.Hash())
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package custody
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// EVMTx is an EVM transaction saved to a tx file, to be signed outside of the CLI and
+// then committed
+type EVMTx struct {
+	RPCURL string
+	From   common.Address
+	Tx     *types.Transaction
+}
+
+// IsSigned returns true if the transaction has a signature
+func (evmTx EVMTx) IsSigned() bool {
+	v, r, s := evmTx.Tx.RawSignatureValues()
+	return v.Sign() != 0 || r.Sign() != 0 || s.Sign() != 0
+}
+
+// SaveEVMTx writes [evmTx] to [txPath]
+func SaveEVMTx(evmTx EVMTx, txPath string, forceOverwrite bool) error {
+	if _, err := os.Stat(txPath); err == nil && !forceOverwrite {
+		return fmt.Errorf("couldn't create file to write tx to: file exists")
+	}
+	bs, err := json.MarshalIndent(evmTx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(txPath, bs, constants.WriteReadReadPerms)
+}
+
+// IsEVMTxFile returns true if [txPath] holds an EVM tx saved with SaveEVMTx, instead of
+// a P-Chain tx
+func IsEVMTxFile(txPath string) (bool, error) {
+	bs, err := os.ReadFile(txPath)
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(strings.TrimSpace(string(bs)), "{"), nil
+}
+
+// LoadEVMTx reads the EVM tx saved at [txPath]
+func LoadEVMTx(txPath string) (EVMTx, error) {
+	bs, err := os.ReadFile(txPath)
+	if err != nil {
+		return EVMTx{}, err
+	}
+	evmTx := EVMTx{}
+	if err := json.Unmarshal(bs, &evmTx); err != nil {
+		return EVMTx{}, fmt.Errorf("invalid EVM tx file %s: %w", txPath, err)
+	}
+	if evmTx.Tx == nil {
+		return EVMTx{}, fmt.Errorf("invalid EVM tx file %s: no tx", txPath)
+	}
+	return evmTx, nil
+}
+
+// EVMSigningPayload returns the bytes an EIP-1559 transaction signature is computed from,
+// 0x02 || rlp([chainId, nonce, maxPriorityFeePerGas, maxFeePerGas, gas, to, value, data, accessList]),
+// and the digest signed, as computed by the signer of the tx chain
+func EVMSigningPayload(tx *types.Transaction) ([]byte, []byte, error) {
+	if tx.Type() != types.DynamicFeeTxType {
+		return nil, nil, fmt.Errorf("unsupported EVM tx type %d, expected a dynamic fee tx", tx.Type())
+	}
+	encoded, err := rlp.EncodeToBytes([]interface{}{
+		tx.ChainId(),
+		tx.Nonce(),
+		tx.GasTipCap(),
+		tx.GasFeeCap(),
+		tx.Gas(),
+		tx.To(),
+		tx.Value(),
+		tx.Data(),
+		tx.AccessList(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	payload := append([]byte{types.DynamicFeeTxType}, encoded...)
+	digest := types.LatestSignerForChainID(tx.ChainId()).Hash(tx)
+	return payload, digest.Bytes(), nil
+}
+
+// evmTxSignature returns the signature of the hex encoded signed EVM tx [signedTx], and the
+// digest it is made over
+func evmTxSignature(signedTx string) (Signature, error) {
+	txBytes, err := decodeHex(signedTx)
+	if err != nil {
+		return Signature{}, fmt.Errorf("invalid signed transaction %q: %w", signedTx, err)
+	}
+	tx := &types.Transaction{}
+	if err := tx.UnmarshalBinary(txBytes); err != nil {
+		return Signature{}, fmt.Errorf("invalid signed transaction %q: %w", signedTx, err)
+	}
+	v, r, s := tx.RawSignatureValues()
+	recoveryID := new(big.Int).Set(v)
+	if tx.Type() == types.LegacyTxType && tx.Protected() {
+		// EIP-155 v = chainId * 2 + 35 + recovery id
+		recoveryID.Sub(recoveryID, new(big.Int).Add(new(big.Int).Lsh(tx.ChainId(), 1), big.NewInt(35)))
+	}
+	if !recoveryID.IsUint64() || recoveryID.Uint64() > 28 || r.BitLen() > 256 || s.BitLen() > 256 {
+		return Signature{}, fmt.Errorf("invalid signature of signed transaction %s", tx.Hash().Hex())
+	}
+	sig := make([]byte, signatureLen)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[signatureLen-1] = byte(recoveryID.Uint64())
+	sig, err = normalizeSignature(sig)
+	if err != nil {
+		return Signature{}, fmt.Errorf("invalid signature of signed transaction %s: %w", tx.Hash().Hex(), err)
+	}
+	digest := types.LatestSignerForChainID(tx.ChainId()).Hash(tx)
+	return Signature{Digest: digest.Bytes(), Signature: sig}, nil
+}
+
+// NewEVMSignDoc describes the signature [evmTx] is waiting for
+func NewEVMSignDoc(evmTx EVMTx) (SignDoc, error) {
+	payload, digest, err := EVMSigningPayload(evmTx.Tx)
+	if err != nil {
+		return SignDoc{}, err
+	}
+	return SignDoc{
+		Kind:       EVMKind,
+		ChainID:    evmTx.Tx.ChainId().String(),
+		Summary:    SummarizeEVMTx(evmTx),
+		UnsignedTx: hexutil.Encode(payload),
+		Payloads: []SigningPayload{
+			{
+				Signer: evmTx.From.Hex(),
+				Digest: hexutil.Encode(digest),
+			},
+		},
+	}, nil
+}
+
+// SummarizeEVMTx describes what [evmTx] does, for signers to review it
+func SummarizeEVMTx(evmTx EVMTx) []string {
+	tx := evmTx.Tx
+	summary := []string{}
+	switch {
+	case tx.To() == nil:
+		summary = append(summary, fmt.Sprintf("Deploy a contract from %s", evmTx.From.Hex()))
+	case len(tx.Data()) >= 4:
+		summary = append(summary, fmt.Sprintf("Call method %s of %s from %s", hexutil.Encode(tx.Data()[:4]), tx.To().Hex(), evmTx.From.Hex()))
+	default:
+		summary = append(summary, fmt.Sprintf("Transfer from %s to %s", evmTx.From.Hex(), tx.To().Hex()))
+	}
+	if tx.Value().Sign() > 0 {
+		summary = append(summary, fmt.Sprintf("Value %s wei", tx.Value()))
+	}
+	maxFee := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+	return append(summary,
+		fmt.Sprintf("Chain ID %s, nonce %d", tx.ChainId(), tx.Nonce()),
+		fmt.Sprintf("Gas limit %d, max fee %s wei", tx.Gas(), maxFee),
+	)
+}
+
+// ApplyEVMSignatures signs [evmTx] with the one of [signatures] that is made by its sender
+// over its signing digest. Fails if there is none
+func ApplyEVMSignatures(evmTx *EVMTx, signatures []Signature) error {
+	_, digest, err := EVMSigningPayload(evmTx.Tx)
+	if err != nil {
+		return err
+	}
+	signer := types.LatestSignerForChainID(evmTx.Tx.ChainId())
+	for _, signature := range signatures {
+		if !bytes.Equal(signature.Digest, digest) {
+			continue
+		}
+		publicKey, err := ethcrypto.SigToPub(digest, signature.Signature)
+		if err != nil {
+			return fmt.Errorf("invalid signature of %s: %w", hexutil.Encode(digest), err)
+		}
+		if ethcrypto.PubkeyToAddress(*publicKey) != evmTx.From {
+			continue
+		}
+		signedTx, err := evmTx.Tx.WithSignature(signer, signature.Signature)
+		if err != nil {
+			return err
+		}
+		evmTx.Tx = signedTx
+		return nil
+	}
+	return fmt.Errorf("no signature of %s by %s found", hexutil.Encode(digest), evmTx.From.Hex())
+}
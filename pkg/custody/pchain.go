@@ -0,0 +1,376 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package custody
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/keychain"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/chain/p/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PChainSigningHash returns the digest P-Chain credentials of [tx] sign: the sha256
+// of the unsigned tx bytes
+func PChainSigningHash(tx *txs.Tx) ([]byte, []byte, error) {
+	unsignedBytes, err := txs.Codec.Marshal(txs.CodecVersion, &tx.Unsigned)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't marshal unsigned tx: %w", err)
+	}
+	return unsignedBytes, hashing.ComputeHash256(unsignedBytes), nil
+}
+
+// NewPChainSignDoc describes the signatures [tx] on [network] is waiting for from
+// [remainingSigners]
+func NewPChainSignDoc(tx *txs.Tx, network models.Network, remainingSigners []string) (SignDoc, error) {
+	unsignedBytes, hash, err := PChainSigningHash(tx)
+	if err != nil {
+		return SignDoc{}, err
+	}
+	doc := SignDoc{
+		Kind:       PChainKind,
+		Network:    network.Name(),
+		Summary:    SummarizePChainTx(tx, network),
+		UnsignedTx: hexutil.Encode(unsignedBytes),
+	}
+	for _, signer := range remainingSigners {
+		doc.Payloads = append(doc.Payloads, SigningPayload{
+			Signer: signer,
+			Digest: hexutil.Encode(hash),
+		})
+	}
+	return doc, nil
+}
+
+// SummarizePChainTx describes what [tx] does, for signers to review it
+func SummarizePChainTx(tx *txs.Tx, network models.Network) []string {
+	summary := []string{}
+	switch unsignedTx := tx.Unsigned.(type) {
+	case *txs.ConvertSubnetToL1Tx:
+		summary = append(summary,
+			fmt.Sprintf("Convert subnet %s into an L1", unsignedTx.Subnet),
+			fmt.Sprintf("Validator manager %s on chain %s", common.BytesToAddress(unsignedTx.Address).Hex(), unsignedTx.ChainID),
+		)
+		for _, validator := range unsignedTx.Validators {
+			nodeID, err := ids.ToNodeID(validator.NodeID)
+			if err != nil {
+				continue
+			}
+			summary = append(summary, fmt.Sprintf("Bootstrap validator %s with weight %d and balance %d nAVAX", nodeID, validator.Weight, validator.Balance))
+		}
+	case *txs.CreateChainTx:
+		summary = append(summary, fmt.Sprintf("Create blockchain %s with VM %s on subnet %s", unsignedTx.ChainName, unsignedTx.VMID, unsignedTx.SubnetID))
+	case *txs.AddSubnetValidatorTx:
+		summary = append(summary, fmt.Sprintf(
+			"Add validator %s with weight %d to subnet %s, from %s to %s",
+			unsignedTx.NodeID(),
+			unsignedTx.Weight(),
+			unsignedTx.SubnetValidator.Subnet,
+			unsignedTx.StartTime().UTC(),
+			unsignedTx.EndTime().UTC(),
+		))
+	case *txs.RemoveSubnetValidatorTx:
+		summary = append(summary, fmt.Sprintf("Remove validator %s from subnet %s", unsignedTx.NodeID, unsignedTx.Subnet))
+	case *txs.TransferSubnetOwnershipTx:
+		line := fmt.Sprintf("Transfer ownership of subnet %s", unsignedTx.Subnet)
+		if owner, ok := unsignedTx.Owner.(*secp256k1fx.OutputOwners); ok {
+			addrs := make([]string, 0, len(owner.Addrs))
+			for _, addr := range owner.Addrs {
+				addrStr, err := address.Format("P", key.GetHRP(network.ID), addr[:])
+				if err != nil {
+					addrStr = addr.String()
+				}
+				addrs = append(addrs, addrStr)
+			}
+			line += fmt.Sprintf(" to %d of %v", owner.Threshold, addrs)
+		}
+		summary = append(summary, line)
+	default:
+		summary = append(summary, fmt.Sprintf("%T", unsignedTx))
+	}
+	return summary
+}
+
+// pchainBackend provides the wallet signer with the UTXOs consumed by a tx, fetched from the
+// txs that produced them, and the owners authorizing it. So the signers of a tx can be found
+// without a wallet holding the UTXOs of its keys
+type pchainBackend struct {
+	client platformvm.Client
+	utxos  map[ids.ID]*avax.UTXO
+}
+
+// newPChainBackend fetches from [client] the P-Chain UTXOs consumed by [tx]
+func newPChainBackend(ctx context.Context, client platformvm.Client, tx *txs.Tx) (*pchainBackend, error) {
+	backend := &pchainBackend{
+		client: client,
+		utxos:  map[ids.ID]*avax.UTXO{},
+	}
+	baseTx, ok := tx.Unsigned.(interface{ InputUTXOs() []*avax.UTXOID })
+	if !ok {
+		return nil, fmt.Errorf("unsupported tx type %T", tx.Unsigned)
+	}
+	sourceTxs := map[ids.ID][]*avax.UTXO{}
+	for _, utxoID := range baseTx.InputUTXOs() {
+		utxos, ok := sourceTxs[utxoID.TxID]
+		if !ok {
+			var err error
+			utxos, err = getProducedUTXOs(ctx, client, utxoID.TxID)
+			if err != nil {
+				return nil, fmt.Errorf("failure fetching UTXO %s: %w", utxoID, err)
+			}
+			sourceTxs[utxoID.TxID] = utxos
+		}
+		for _, utxo := range utxos {
+			if utxo.InputID() == utxoID.InputID() {
+				backend.utxos[utxo.InputID()] = utxo
+			}
+		}
+		if _, ok := backend.utxos[utxoID.InputID()]; !ok {
+			return nil, fmt.Errorf("UTXO %s not found", utxoID)
+		}
+	}
+	return backend, nil
+}
+
+// getProducedUTXOs returns the UTXOs produced by P-Chain tx [txID], including its rewards
+func getProducedUTXOs(ctx context.Context, client platformvm.Client, txID ids.ID) ([]*avax.UTXO, error) {
+	txBytes, err := client.GetTx(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txs.Parse(txs.Codec, txBytes)
+	if err != nil {
+		return nil, err
+	}
+	utxos := tx.UTXOs()
+	rewardUTXOsBytes, err := client.GetRewardUTXOs(ctx, &api.GetTxArgs{TxID: txID})
+	if err != nil {
+		return nil, err
+	}
+	for _, utxoBytes := range rewardUTXOsBytes {
+		utxo := &avax.UTXO{}
+		if _, err := txs.Codec.Unmarshal(utxoBytes, utxo); err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
+func (b *pchainBackend) GetUTXO(_ context.Context, chainID, utxoID ids.ID) (*avax.UTXO, error) {
+	// imported UTXOs are not supported
+	utxo, ok := b.utxos[utxoID]
+	if chainID != avagoconstants.PlatformChainID || !ok {
+		return nil, database.ErrNotFound
+	}
+	return utxo, nil
+}
+
+// GetOwner returns the owner of subnet or L1 validator [ownerID]
+func (b *pchainBackend) GetOwner(ctx context.Context, ownerID ids.ID) (fx.Owner, error) {
+	owners, err := platformvm.GetSubnetOwners(b.client, ctx, ownerID)
+	if err != nil {
+		owners, err = platformvm.GetDeactivationOwners(b.client, ctx, ownerID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failure fetching owner %s: %w", ownerID, err)
+	}
+	return owners[ownerID], nil
+}
+
+// recordingKeychain provides the wallet signer with signers that make no signature, and
+// records the addresses they are asked for. Signatures already on the tx are not asked for
+type recordingKeychain struct {
+	addrs []ids.ShortID
+}
+
+type recordingSigner struct {
+	kc   *recordingKeychain
+	addr ids.ShortID
+}
+
+func (kc *recordingKeychain) Get(addr ids.ShortID) (keychain.Signer, bool) {
+	return &recordingSigner{kc: kc, addr: addr}, true
+}
+
+func (kc *recordingKeychain) Addresses() set.Set[ids.ShortID] {
+	return set.Of(kc.addrs...)
+}
+
+func (s *recordingSigner) SignHash([]byte) ([]byte, error) {
+	if !slices.Contains(s.kc.addrs, s.addr) {
+		s.kc.addrs = append(s.kc.addrs, s.addr)
+	}
+	return make([]byte, secp256k1.SignatureLen), nil
+}
+
+func (s *recordingSigner) Sign(b []byte) ([]byte, error) {
+	return s.SignHash(hashing.ComputeHash256(b))
+}
+
+func (s *recordingSigner) Address() ids.ShortID {
+	return s.addr
+}
+
+// signaturesKeychain provides the wallet signer with signers that return the signatures of
+// a tx hash made by custodial signers
+type signaturesKeychain struct {
+	hash    []byte
+	signers map[ids.ShortID]*signatureSigner
+	// addresses whose signature was placed on the tx
+	applied []ids.ShortID
+}
+
+type signatureSigner struct {
+	kc        *signaturesKeychain
+	addr      ids.ShortID
+	signature []byte
+}
+
+func newSignaturesKeychain(hash []byte, signatures []Signature) (*signaturesKeychain, error) {
+	kc := &signaturesKeychain{
+		hash:    hash,
+		signers: map[ids.ShortID]*signatureSigner{},
+	}
+	for _, signature := range signatures {
+		if !bytes.Equal(signature.Digest, hash) {
+			continue
+		}
+		publicKey, err := secp256k1.RecoverPublicKeyFromHash(hash, signature.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature of %s: %w", hexutil.Encode(hash), err)
+		}
+		kc.signers[publicKey.Address()] = &signatureSigner{
+			kc:        kc,
+			addr:      publicKey.Address(),
+			signature: signature.Signature,
+		}
+	}
+	return kc, nil
+}
+
+func (kc *signaturesKeychain) Get(addr ids.ShortID) (keychain.Signer, bool) {
+	signer, ok := kc.signers[addr]
+	return signer, ok
+}
+
+func (kc *signaturesKeychain) Addresses() set.Set[ids.ShortID] {
+	addrs := set.NewSet[ids.ShortID](len(kc.signers))
+	for addr := range kc.signers {
+		addrs.Add(addr)
+	}
+	return addrs
+}
+
+func (s *signatureSigner) SignHash(hash []byte) ([]byte, error) {
+	if !bytes.Equal(hash, s.kc.hash) {
+		return nil, fmt.Errorf("no signature of %s by %s", hexutil.Encode(hash), s.addr)
+	}
+	if !slices.Contains(s.kc.applied, s.addr) {
+		s.kc.applied = append(s.kc.applied, s.addr)
+	}
+	return s.signature, nil
+}
+
+func (s *signatureSigner) Sign(b []byte) ([]byte, error) {
+	return s.SignHash(hashing.ComputeHash256(b))
+}
+
+func (s *signatureSigner) Address() ids.ShortID {
+	return s.addr
+}
+
+// GetPChainRemainingSigners returns the addresses that still have to sign [tx] on [network]:
+// the owners of the inputs it consumes, and of the subnet or L1 validator it is authorized by
+func GetPChainRemainingSigners(network models.Network, tx *txs.Tx) ([]string, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	backend, err := newPChainBackend(ctx, platformvm.NewClient(network.Endpoint), tx)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := getPChainRemainingSigners(ctx, backend, tx)
+	if err != nil {
+		return nil, err
+	}
+	return formatPChainAddresses(network, addrs)
+}
+
+func getPChainRemainingSigners(ctx context.Context, backend signer.Backend, tx *txs.Tx) ([]ids.ShortID, error) {
+	kc := &recordingKeychain{}
+	// the signer only fills the missing signatures, so a copy of the tx is not needed
+	if err := signer.New(kc, backend).Sign(ctx, tx); err != nil {
+		return nil, err
+	}
+	return kc.addrs, nil
+}
+
+// ApplyPChainSignatures fills the credentials of [tx] on [network] with the [signatures] made
+// by its remaining signers. Returns the signers whose signature was filled. Signatures of other
+// digests or signers are ignored
+func ApplyPChainSignatures(network models.Network, tx *txs.Tx, signatures []Signature) ([]string, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	backend, err := newPChainBackend(ctx, platformvm.NewClient(network.Endpoint), tx)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := applyPChainSignatures(ctx, backend, tx, signatures)
+	if err != nil {
+		return nil, err
+	}
+	return formatPChainAddresses(network, addrs)
+}
+
+func applyPChainSignatures(ctx context.Context, backend signer.Backend, tx *txs.Tx, signatures []Signature) ([]ids.ShortID, error) {
+	_, hash, err := PChainSigningHash(tx)
+	if err != nil {
+		return nil, err
+	}
+	kc, err := newSignaturesKeychain(hash, signatures)
+	if err != nil {
+		return nil, err
+	}
+	if err := signer.New(kc, backend).Sign(ctx, tx); err != nil {
+		return nil, err
+	}
+	if len(kc.applied) > 0 {
+		// the tx id depends on the credentials
+		if err := tx.Initialize(txs.Codec); err != nil {
+			return nil, err
+		}
+	}
+	return kc.applied, nil
+}
+
+func formatPChainAddresses(network models.Network, addrs []ids.ShortID) ([]string, error) {
+	addrsStr := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		addrStr, err := address.Format("P", key.GetHRP(network.ID), addr[:])
+		if err != nil {
+			return nil, err
+		}
+		addrsStr = append(addrsStr, addrStr)
+	}
+	return addrsStr, nil
+}
@@ -0,0 +1,344 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package dapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"golang.org/x/exp/maps"
+)
+
+// Framework is a smart contract development framework a dapp project is built with
+type Framework string
+
+const (
+	Foundry Framework = "foundry"
+	Hardhat Framework = "hardhat"
+)
+
+const (
+	FoundryConfigFile = "foundry.toml"
+	// network config written for hardhat projects, to be imported from hardhat.config
+	HardhatNetworksFile = "avalanche.networks.json"
+	EnvFile             = ".env"
+	gitIgnoreFile       = ".gitignore"
+)
+
+var (
+	hardhatConfigFiles = []string{"hardhat.config.ts", "hardhat.config.js", "hardhat.config.cjs", "hardhat.config.mjs"}
+	nonAliasChars      = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Explorer is a block explorer supporting the etherscan contract verification API
+type Explorer struct {
+	APIURL     string
+	BrowserURL string
+}
+
+// NetworkConfig is the configuration a dapp project needs to deploy to a blockchain on a network
+type NetworkConfig struct {
+	// name of the network on the dapp project
+	Alias   string
+	RPCURL  string
+	ChainID uint64
+	// explorer to verify contracts on, if any
+	Explorer *Explorer
+	// deployer private key, written to the project .env file
+	PrivateKey string
+	// foundry keystore account of the deployer
+	KeystoreAccount string
+}
+
+// PrivateKeyEnvVar is the .env variable holding the deployer private key of the network
+func (c NetworkConfig) PrivateKeyEnvVar() string {
+	return strings.ToUpper(c.Alias) + "_PRIVATE_KEY"
+}
+
+// AccountEnvVar is the .env variable holding the deployer keystore account of the network
+func (c NetworkConfig) AccountEnvVar() string {
+	return strings.ToUpper(c.Alias) + "_ACCOUNT"
+}
+
+// NetworkAlias names the network [networkName] of [blockchainName] on the dapp project,
+// using only characters valid on foundry and hardhat network names and env variables
+func NetworkAlias(blockchainName string, networkName string) string {
+	return strings.Trim(nonAliasChars.ReplaceAllString(strings.ToLower(blockchainName+"_"+networkName), "_"), "_")
+}
+
+// DetectFramework returns the framework of the dapp project at [dir], based on its config files
+func DetectFramework(dir string) (Framework, error) {
+	if utils.FileExists(filepath.Join(dir, FoundryConfigFile)) {
+		return Foundry, nil
+	}
+	if HardhatConfigPath(dir) != "" {
+		return Hardhat, nil
+	}
+	return "", fmt.Errorf("no %s or hardhat.config file found at %s", FoundryConfigFile, dir)
+}
+
+// HardhatConfigPath returns the path of the hardhat config of the project at [dir], or
+// empty if there is none
+func HardhatConfigPath(dir string) string {
+	for _, configFile := range hardhatConfigFiles {
+		configPath := filepath.Join(dir, configFile)
+		if utils.FileExists(configPath) {
+			return configPath
+		}
+	}
+	return ""
+}
+
+// Write updates the config files of the [framework] project at [dir] with [configs],
+// leaving the networks of other blockchains as they are
+func Write(dir string, framework Framework, configs []NetworkConfig) error {
+	switch framework {
+	case Foundry:
+		if err := writeFoundryConfig(dir, configs); err != nil {
+			return err
+		}
+	case Hardhat:
+		if err := writeHardhatNetworks(dir, configs); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported framework %q", framework)
+	}
+	return writeEnv(dir, configs)
+}
+
+func writeFoundryConfig(dir string, configs []NetworkConfig) error {
+	configPath := filepath.Join(dir, FoundryConfigFile)
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	config := string(content)
+	for _, c := range configs {
+		config = UpsertTOMLEntry(config, "rpc_endpoints", c.Alias, fmt.Sprintf("%q", c.RPCURL))
+		if c.Explorer != nil {
+			// routescan style APIs take any key
+			config = UpsertTOMLEntry(
+				config,
+				"etherscan",
+				c.Alias,
+				fmt.Sprintf(`{ key = "verifyContract", url = %q, chain = %d }`, c.Explorer.APIURL, c.ChainID),
+			)
+		}
+	}
+	return os.WriteFile(configPath, []byte(config), constants.WriteReadReadPerms)
+}
+
+// UpsertTOMLEntry sets [key] = [value] on [table] of the TOML [content], replacing the
+// line of an existing [key], and adding the table at the end if missing. Other lines are
+// kept as they are
+func UpsertTOMLEntry(content string, table string, key string, value string) string {
+	entry := key + " = " + value
+	lines := strings.Split(content, "\n")
+	header := "[" + table + "]"
+	tableStart := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == header {
+			tableStart = i
+			break
+		}
+	}
+	if tableStart == -1 {
+		content = strings.TrimRight(content, "\n")
+		if content != "" {
+			content += "\n\n"
+		}
+		return content + header + "\n" + entry + "\n"
+	}
+	insertAt := tableStart + 1
+	for i := tableStart + 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "[") {
+			break
+		}
+		if entryKey, _, found := strings.Cut(line, "="); found && strings.TrimSpace(entryKey) == key {
+			lines[i] = entry
+			return strings.Join(lines, "\n")
+		}
+		if line != "" {
+			insertAt = i + 1
+		}
+	}
+	lines = append(lines[:insertAt], append([]string{entry}, lines[insertAt:]...)...)
+	return strings.Join(lines, "\n")
+}
+
+// HardhatNetworks is the content of the hardhat networks file
+type HardhatNetworks struct {
+	Networks     map[string]HardhatNetwork `json:"networks"`
+	CustomChains []HardhatCustomChain      `json:"customChains"`
+}
+
+// HardhatNetwork is a network entry of hardhat.config networks, plus the .env variable
+// holding its deployer key
+type HardhatNetwork struct {
+	URL           string `json:"url"`
+	ChainID       uint64 `json:"chainId"`
+	PrivateKeyEnv string `json:"privateKeyEnv,omitempty"`
+}
+
+// HardhatCustomChain is an entry of hardhat.config etherscan.customChains
+type HardhatCustomChain struct {
+	Network string `json:"network"`
+	ChainID uint64 `json:"chainId"`
+	URLs    struct {
+		APIURL     string `json:"apiURL"`
+		BrowserURL string `json:"browserURL"`
+	} `json:"urls"`
+}
+
+func writeHardhatNetworks(dir string, configs []NetworkConfig) error {
+	networksPath := filepath.Join(dir, HardhatNetworksFile)
+	networks := HardhatNetworks{}
+	if utils.FileExists(networksPath) {
+		content, err := os.ReadFile(networksPath)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(content, &networks); err != nil {
+			return fmt.Errorf("failure parsing %s: %w", networksPath, err)
+		}
+	}
+	if networks.Networks == nil {
+		networks.Networks = map[string]HardhatNetwork{}
+	}
+	for _, c := range configs {
+		network := HardhatNetwork{
+			URL:     c.RPCURL,
+			ChainID: c.ChainID,
+		}
+		if c.PrivateKey != "" {
+			network.PrivateKeyEnv = c.PrivateKeyEnvVar()
+		}
+		networks.Networks[c.Alias] = network
+		networks.CustomChains = utils.Filter(networks.CustomChains, func(customChain HardhatCustomChain) bool {
+			return customChain.Network != c.Alias
+		})
+		if c.Explorer != nil {
+			customChain := HardhatCustomChain{
+				Network: c.Alias,
+				ChainID: c.ChainID,
+			}
+			customChain.URLs.APIURL = c.Explorer.APIURL
+			customChain.URLs.BrowserURL = c.Explorer.BrowserURL
+			networks.CustomChains = append(networks.CustomChains, customChain)
+		}
+	}
+	if networks.CustomChains == nil {
+		// the config snippet maps over it
+		networks.CustomChains = []HardhatCustomChain{}
+	}
+	sort.Slice(networks.CustomChains, func(i, j int) bool {
+		return networks.CustomChains[i].Network < networks.CustomChains[j].Network
+	})
+	content, err := json.MarshalIndent(networks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(networksPath, append(content, '\n'), constants.WriteReadReadPerms)
+}
+
+// HardhatConfigSnippet is the code that wires the hardhat networks file into hardhat.config
+const HardhatConfigSnippet = `require("dotenv").config();
+const avalanche = require("./` + HardhatNetworksFile + `");
+
+module.exports = {
+  // ...
+  networks: Object.fromEntries(
+    Object.entries(avalanche.networks).map(([name, { url, chainId, privateKeyEnv }]) => [
+      name,
+      { url, chainId, accounts: privateKeyEnv && process.env[privateKeyEnv] ? [process.env[privateKeyEnv]] : [] },
+    ])
+  ),
+  etherscan: {
+    apiKey: Object.fromEntries(avalanche.customChains.map(({ network }) => [network, "verifyContract"])),
+    customChains: avalanche.customChains,
+  },
+};`
+
+// writeEnv sets the deployer variables of [configs] on the project .env file, and makes
+// sure git ignores it
+func writeEnv(dir string, configs []NetworkConfig) error {
+	vars := map[string]string{}
+	for _, c := range configs {
+		if c.PrivateKey != "" {
+			vars[c.PrivateKeyEnvVar()] = c.PrivateKey
+		}
+		if c.KeystoreAccount != "" {
+			vars[c.AccountEnvVar()] = c.KeystoreAccount
+		}
+	}
+	if len(vars) == 0 {
+		return nil
+	}
+	envPath := filepath.Join(dir, EnvFile)
+	content := ""
+	if utils.FileExists(envPath) {
+		bs, err := os.ReadFile(envPath)
+		if err != nil {
+			return err
+		}
+		content = string(bs)
+	}
+	names := maps.Keys(vars)
+	sort.Strings(names)
+	for _, name := range names {
+		content = UpsertEnvVar(content, name, vars[name])
+	}
+	if err := os.WriteFile(envPath, []byte(content), constants.WriteReadUserOnlyPerms); err != nil {
+		return err
+	}
+	return ignoreOnGit(dir, EnvFile)
+}
+
+// UpsertEnvVar sets [name]=[value] on the dotenv [content], replacing an existing
+// definition of [name]
+func UpsertEnvVar(content string, name string, value string) string {
+	entry := name + "=" + value
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	for i, line := range lines {
+		varName, _, found := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "export "), "=")
+		if found && strings.TrimSpace(varName) == name {
+			lines[i] = entry
+			return strings.Join(lines, "\n") + "\n"
+		}
+	}
+	if content == "" {
+		return entry + "\n"
+	}
+	return strings.Join(append(lines, entry), "\n") + "\n"
+}
+
+// ignoreOnGit adds [file] to the .gitignore of the project at [dir], if it has one
+func ignoreOnGit(dir string, file string) error {
+	gitIgnorePath := filepath.Join(dir, gitIgnoreFile)
+	if !utils.FileExists(gitIgnorePath) {
+		return nil
+	}
+	content, err := os.ReadFile(gitIgnorePath)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimSpace(line); line == file || line == "/"+file {
+			return nil
+		}
+	}
+	gitIgnore := strings.TrimRight(string(content), "\n")
+	if gitIgnore != "" {
+		gitIgnore += "\n"
+	}
+	return os.WriteFile(gitIgnorePath, []byte(gitIgnore+file+"\n"), constants.WriteReadReadPerms)
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package dapp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkAlias(t *testing.T) {
+	require := require.New(t)
+	require.Equal("mychain_local", NetworkAlias("myChain", "local"))
+	require.Equal("my_chain_cluster_1", NetworkAlias("my-chain", "Cluster 1"))
+	config := NetworkConfig{Alias: "mychain_fuji"}
+	require.Equal("MYCHAIN_FUJI_PRIVATE_KEY", config.PrivateKeyEnvVar())
+	require.Equal("MYCHAIN_FUJI_ACCOUNT", config.AccountEnvVar())
+}
+
+func TestUpsertTOMLEntry(t *testing.T) {
+	require := require.New(t)
+	config := "[profile.default]\nsrc = \"src\"\n"
+	config = UpsertTOMLEntry(config, "rpc_endpoints", "a", `"http://a"`)
+	require.Equal("[profile.default]\nsrc = \"src\"\n\n[rpc_endpoints]\na = \"http://a\"\n", config)
+	config = UpsertTOMLEntry(config, "rpc_endpoints", "b", `"http://b"`)
+	config = UpsertTOMLEntry(config, "rpc_endpoints", "a", `"http://a2"`)
+	require.Equal("[profile.default]\nsrc = \"src\"\n\n[rpc_endpoints]\na = \"http://a2\"\nb = \"http://b\"\n", config)
+	// entries are added to the existing table, before the next one
+	config = "[rpc_endpoints]\n# mine\nmainnet = \"x\"\n\n[fmt]\nline_length = 100\n"
+	config = UpsertTOMLEntry(config, "rpc_endpoints", "a", `"http://a"`)
+	require.Equal("[rpc_endpoints]\n# mine\nmainnet = \"x\"\na = \"http://a\"\n\n[fmt]\nline_length = 100\n", config)
+}
+
+func TestUpsertEnvVar(t *testing.T) {
+	require := require.New(t)
+	env := UpsertEnvVar("", "A", "1")
+	require.Equal("A=1\n", env)
+	env = UpsertEnvVar("OTHER=x\nexport A=0", "A", "1")
+	require.Equal("OTHER=x\nA=1\n", env)
+	env = UpsertEnvVar(env, "B", "2")
+	require.Equal("OTHER=x\nA=1\nB=2\n", env)
+}
+
+func TestWriteFoundry(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, FoundryConfigFile), []byte("[profile.default]\n"), 0o600))
+	require.NoError(os.WriteFile(filepath.Join(dir, gitIgnoreFile), []byte("out/\n"), 0o600))
+	framework, err := DetectFramework(dir)
+	require.NoError(err)
+	require.Equal(Foundry, framework)
+	configs := []NetworkConfig{
+		{Alias: "chain_local", RPCURL: "http://127.0.0.1:9650/ext/bc/x/rpc", ChainID: 1, PrivateKey: "0x01"},
+		{Alias: "chain_fuji", RPCURL: "https://fuji/rpc", ChainID: 1, Explorer: &Explorer{APIURL: "https://verify"}, KeystoreAccount: "deployer"},
+	}
+	require.NoError(Write(dir, Foundry, configs))
+	config, err := os.ReadFile(filepath.Join(dir, FoundryConfigFile))
+	require.NoError(err)
+	require.Equal(`[profile.default]
+
+[rpc_endpoints]
+chain_local = "http://127.0.0.1:9650/ext/bc/x/rpc"
+chain_fuji = "https://fuji/rpc"
+
+[etherscan]
+chain_fuji = { key = "verifyContract", url = "https://verify", chain = 1 }
+`, string(config))
+	env, err := os.ReadFile(filepath.Join(dir, EnvFile))
+	require.NoError(err)
+	require.Equal("CHAIN_FUJI_ACCOUNT=deployer\nCHAIN_LOCAL_PRIVATE_KEY=0x01\n", string(env))
+	gitIgnore, err := os.ReadFile(filepath.Join(dir, gitIgnoreFile))
+	require.NoError(err)
+	require.Equal("out/\n.env\n", string(gitIgnore))
+
+	// a new deploy updates the entries in place
+	configs[0].RPCURL = "http://127.0.0.1:9650/ext/bc/y/rpc"
+	require.NoError(Write(dir, Foundry, configs[:1]))
+	config, err = os.ReadFile(filepath.Join(dir, FoundryConfigFile))
+	require.NoError(err)
+	require.Contains(string(config), "chain_local = \"http://127.0.0.1:9650/ext/bc/y/rpc\"\nchain_fuji")
+	gitIgnore, err = os.ReadFile(filepath.Join(dir, gitIgnoreFile))
+	require.NoError(err)
+	require.Equal("out/\n.env\n", string(gitIgnore))
+}
+
+func TestWriteHardhat(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "hardhat.config.ts"), []byte("export default {}\n"), 0o600))
+	framework, err := DetectFramework(dir)
+	require.NoError(err)
+	require.Equal(Hardhat, framework)
+	configs := []NetworkConfig{
+		{Alias: "chain_local", RPCURL: "http://local/rpc", ChainID: 2, PrivateKey: "0x01"},
+		{Alias: "chain_fuji", RPCURL: "https://fuji/rpc", ChainID: 2, Explorer: &Explorer{APIURL: "https://verify", BrowserURL: "https://explorer"}},
+	}
+	require.NoError(Write(dir, Hardhat, configs))
+	require.NoError(Write(dir, Hardhat, []NetworkConfig{{Alias: "other_local", RPCURL: "http://other/rpc", ChainID: 3}}))
+	content, err := os.ReadFile(filepath.Join(dir, HardhatNetworksFile))
+	require.NoError(err)
+	networks := HardhatNetworks{}
+	require.NoError(json.Unmarshal(content, &networks))
+	require.Equal(map[string]HardhatNetwork{
+		"chain_local": {URL: "http://local/rpc", ChainID: 2, PrivateKeyEnv: "CHAIN_LOCAL_PRIVATE_KEY"},
+		"chain_fuji":  {URL: "https://fuji/rpc", ChainID: 2},
+		"other_local": {URL: "http://other/rpc", ChainID: 3},
+	}, networks.Networks)
+	require.Len(networks.CustomChains, 1)
+	require.Equal("chain_fuji", networks.CustomChains[0].Network)
+	require.Equal("https://verify", networks.CustomChains[0].URLs.APIURL)
+	// no .gitignore on the project, the .env is written anyway
+	require.FileExists(filepath.Join(dir, EnvFile))
+	require.NoFileExists(filepath.Join(dir, gitIgnoreFile))
+}
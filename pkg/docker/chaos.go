@@ -0,0 +1,173 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package docker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"golang.org/x/exp/slices"
+)
+
+// GetLocalNetworkContainerName returns the name of the container of [nodeName] on the local network
+func GetLocalNetworkContainerName(nodeName string) string {
+	return fmt.Sprintf("%s-%s", constants.LocalDockerNetworkProject, nodeName)
+}
+
+// PauseLocalNetworkNodes freezes the processes of [nodeNames] on the local network at [rootDir].
+// Their state, and the connections to them, are kept, but they stop answering
+func PauseLocalNetworkNodes(rootDir string, nodeNames []string) error {
+	_, err := localNetworkCompose(rootDir, append([]string{"pause"}, nodeNames...)...)
+	return err
+}
+
+// ResumeLocalNetworkNodes resumes the paused [nodeNames] on the local network at [rootDir]
+func ResumeLocalNetworkNodes(rootDir string, nodeNames []string) error {
+	_, err := localNetworkCompose(rootDir, append([]string{"unpause"}, nodeNames...)...)
+	return err
+}
+
+// GetPausedLocalNetworkNodes returns the paused nodes of the local network at [rootDir]
+func GetPausedLocalNetworkNodes(rootDir string) ([]string, error) {
+	output, err := localNetworkCompose(rootDir, "ps", "--services", "--status", "paused")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// GetPartitionDrops returns, for each node of [nodes], the IPs of the nodes it has to stop
+// talking to so that [groups] of node names are split from each other. Nodes not included
+// in any group form an additional group
+func GetPartitionDrops(nodes []LocalNetworkNode, groups [][]string) (map[string][]string, error) {
+	nodeGroup := map[string]int{}
+	for i, group := range groups {
+		for _, nodeName := range group {
+			if !slices.ContainsFunc(nodes, func(node LocalNetworkNode) bool { return node.Name == nodeName }) {
+				return nil, fmt.Errorf("node %s not found on the local network", nodeName)
+			}
+			if _, ok := nodeGroup[nodeName]; ok {
+				return nil, fmt.Errorf("node %s is included in more than one group", nodeName)
+			}
+			nodeGroup[nodeName] = i + 1
+		}
+	}
+	drops := map[string][]string{}
+	for _, node := range nodes {
+		for _, peer := range nodes {
+			if nodeGroup[node.Name] != nodeGroup[peer.Name] {
+				drops[node.Name] = append(drops[node.Name], peer.IP)
+			}
+		}
+	}
+	if len(drops) == 0 {
+		return nil, fmt.Errorf("partition does not split the network: at least two groups are needed")
+	}
+	return drops, nil
+}
+
+// PartitionLocalNetwork splits the nodes of the local network at [rootDir] into [groups] that
+// can't reach each other, as described by GetPartitionDrops. Previous partitions are replaced
+func PartitionLocalNetwork(rootDir string, groups [][]string) error {
+	nodes, err := GetLocalNetworkNodes(rootDir)
+	if err != nil {
+		return err
+	}
+	drops, err := GetPartitionDrops(nodes, groups)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		script := []string{"iptables -F INPUT", "iptables -F OUTPUT"}
+		for _, ip := range drops[node.Name] {
+			script = append(script,
+				fmt.Sprintf("iptables -A INPUT -s %s -j DROP", ip),
+				fmt.Sprintf("iptables -A OUTPUT -d %s -j DROP", ip),
+			)
+		}
+		if err := runOnLocalNetworkNode(node.Name, script); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveLocalNetworkPartition lets all the nodes of the local network at [rootDir] reach each other again
+func RemoveLocalNetworkPartition(rootDir string) error {
+	nodes, err := GetLocalNetworkNodes(rootDir)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err := runOnLocalNetworkNode(node.Name, []string{"iptables -F INPUT", "iptables -F OUTPUT"}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetLocalNetworkNodeLatency delays by [delay] +- [jitter] every message [nodeName] sends to
+// its peers on the local network. API traffic is not affected. A zero [delay] removes it
+func SetLocalNetworkNodeLatency(nodeName string, delay time.Duration, jitter time.Duration) error {
+	script := []string{"tc qdisc del dev eth0 root 2>/dev/null || true"}
+	if delay > 0 {
+		// peer traffic goes to the third band of a prio qdisc, that adds the delay
+		script = append(script,
+			"tc qdisc add dev eth0 root handle 1: prio",
+			fmt.Sprintf("tc qdisc add dev eth0 parent 1:3 handle 30: netem delay %dms %dms", delay.Milliseconds(), jitter.Milliseconds()),
+			fmt.Sprintf("tc filter add dev eth0 parent 1: protocol ip u32 match ip dport %d 0xffff flowid 1:3", localNetworkStakingPort),
+			fmt.Sprintf("tc filter add dev eth0 parent 1: protocol ip u32 match ip sport %d 0xffff flowid 1:3", localNetworkStakingPort),
+		)
+	}
+	return runOnLocalNetworkNode(nodeName, script)
+}
+
+// HealLocalNetwork removes the partitions and latencies added to the nodes of the local
+// network at [rootDir], and resumes its paused nodes
+func HealLocalNetwork(rootDir string) error {
+	pausedNodes, err := GetPausedLocalNetworkNodes(rootDir)
+	if err != nil {
+		return err
+	}
+	if len(pausedNodes) > 0 {
+		if err := ResumeLocalNetworkNodes(rootDir, pausedNodes); err != nil {
+			return err
+		}
+	}
+	if err := RemoveLocalNetworkPartition(rootDir); err != nil {
+		return err
+	}
+	nodes, err := GetLocalNetworkNodes(rootDir)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err := SetLocalNetworkNodeLatency(node.Name, 0, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runOnLocalNetworkNode executes [script] on the network namespace of the container of
+// [nodeName], from a network tools container with admin rights on it
+func runOnLocalNetworkNode(nodeName string, script []string) error {
+	args := []string{
+		"run", "--rm",
+		"--network", "container:" + GetLocalNetworkContainerName(nodeName),
+		"--cap-add", "NET_ADMIN",
+		constants.NetworkToolsDockerImage,
+		"sh", "-ec", strings.Join(script, "\n"),
+	}
+	cmd := exec.Command("docker", args...)
+	cmd.Env = os.Environ()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failure updating network of node %s: %w: %s", nodeName, err, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPartitionDrops(t *testing.T) {
+	require := require.New(t)
+	nodes := []LocalNetworkNode{
+		{Name: "node1", IP: "10.0.0.1"},
+		{Name: "node2", IP: "10.0.0.2"},
+		{Name: "node3", IP: "10.0.0.3"},
+		{Name: "node4", IP: "10.0.0.4"},
+	}
+	// nodes not in a group form their own group
+	drops, err := GetPartitionDrops(nodes, [][]string{{"node1"}})
+	require.NoError(err)
+	require.Equal(map[string][]string{
+		"node1": {"10.0.0.2", "10.0.0.3", "10.0.0.4"},
+		"node2": {"10.0.0.1"},
+		"node3": {"10.0.0.1"},
+		"node4": {"10.0.0.1"},
+	}, drops)
+
+	drops, err = GetPartitionDrops(nodes, [][]string{{"node1", "node2"}, {"node3", "node4"}})
+	require.NoError(err)
+	require.Equal(map[string][]string{
+		"node1": {"10.0.0.3", "10.0.0.4"},
+		"node2": {"10.0.0.3", "10.0.0.4"},
+		"node3": {"10.0.0.1", "10.0.0.2"},
+		"node4": {"10.0.0.1", "10.0.0.2"},
+	}, drops)
+
+	_, err = GetPartitionDrops(nodes, [][]string{{"node1", "node2", "node3", "node4"}})
+	require.ErrorContains(err, "at least two groups")
+	_, err = GetPartitionDrops(nodes, [][]string{{"node1"}, {"node1", "node2"}})
+	require.ErrorContains(err, "more than one group")
+	_, err = GetPartitionDrops(nodes, [][]string{{"node5"}})
+	require.ErrorContains(err, "not found")
+}
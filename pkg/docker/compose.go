@@ -23,7 +23,11 @@ type DockerComposeInputs struct {
 	WithMonitoring     bool
 	WithAvalanchego    bool
 	AvalanchegoVersion string
+	AvalanchegoImage   string // overrides the image derived from AvalanchegoVersion, eg to pin a digest
 	ICMRelayerVersion  string
+	AvalanchegoDBDir   string // host path of the avalanchego database, mounted at the same container path
+	AvalanchegoCPUs    string // number of CPUs avalanchego can use, 0 for no limit
+	AvalanchegoMemory  string // memory avalanchego can use, 0 for no limit
 	E2E                bool
 	E2EIP              string
 	E2ESuffix          string
@@ -32,7 +36,7 @@ type DockerComposeInputs struct {
 //go:embed templates/*.docker-compose.yml
 var composeTemplate embed.FS
 
-func renderComposeFile(composePath string, composeDesc string, templateVars DockerComposeInputs) ([]byte, error) {
+func renderComposeFile(composePath string, composeDesc string, templateVars interface{}) ([]byte, error) {
 	compose, err := composeTemplate.ReadFile(composePath)
 	if err != nil {
 		return nil, err
@@ -209,8 +213,27 @@ func ComposeOverSSH(
 	composePath string,
 	composeVars DockerComposeInputs,
 ) error {
-	remoteComposeFile := utils.GetRemoteComposeFile()
 	startTime := time.Now()
+	if err := pushComposeOverSSH(composeDesc, host, timeout, composePath, composeVars); err != nil {
+		return err
+	}
+	ux.Logger.Info("StartDockerCompose [%s]%s", host.NodeID, composeDesc)
+	err := StartDockerCompose(host, timeout)
+	executionTime := time.Since(startTime)
+	ux.Logger.Info("ComposeOverSSH[%s]%s took %s with err: %v", host.NodeID, composeDesc, executionTime, err)
+	return err
+}
+
+// pushComposeOverSSH renders a docker-compose file and merges it into the one of a remote host
+// over SSH, without starting the services
+func pushComposeOverSSH(
+	composeDesc string,
+	host *models.Host,
+	timeout time.Duration,
+	composePath string,
+	composeVars DockerComposeInputs,
+) error {
+	remoteComposeFile := utils.GetRemoteComposeFile()
 	tmpFile, err := os.CreateTemp("", "avalanchecli-docker-compose-*.yml")
 	if err != nil {
 		return err
@@ -233,12 +256,6 @@ func ComposeOverSSH(
 		ux.Logger.Error("ComposeOverSSH[%s]%s failed to validate: %v", host.NodeID, composeDesc, err)
 		return err
 	}
-	ux.Logger.Info("StartDockerCompose [%s]%s", host.NodeID, composeDesc)
-	if err := StartDockerCompose(host, timeout); err != nil {
-		return err
-	}
-	executionTime := time.Since(startTime)
-	ux.Logger.Info("ComposeOverSSH[%s]%s took %s with err: %v", host.NodeID, composeDesc, executionTime, err)
 	return nil
 }
 
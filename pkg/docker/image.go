@@ -4,6 +4,7 @@
 package docker
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -115,3 +116,55 @@ func PrepareDockerImageWithRepo(host *models.Host, image string, gitRepo string,
 	ux.Logger.Info("Docker image %s is READY on %s", image, host.NodeID)
 	return nil
 }
+
+// GetDockerImageDigest returns the repo digest (sha256:...) of a docker image on a remote host,
+// or an empty string if the image has no repo digest, as is the case for locally built images.
+func GetDockerImageDigest(host *models.Host, image string) (string, error) {
+	output, err := host.Command(
+		fmt.Sprintf("docker image inspect --format '{{json .RepoDigests}}' %s", image),
+		nil,
+		constants.SSHScriptTimeout,
+	)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, string(output))
+	}
+	return parseDockerImageDigestOutput(output)
+}
+
+// parseDockerImageDigestOutput parses the repo digests list given by docker image inspect,
+// returning the digest part of the first entry
+func parseDockerImageDigestOutput(output []byte) (string, error) {
+	repoDigests := []string{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(output))), &repoDigests); err != nil {
+		return "", fmt.Errorf("unable to parse docker image digests: %w", err)
+	}
+	if len(repoDigests) == 0 {
+		return "", nil
+	}
+	_, digest, found := strings.Cut(repoDigests[0], "@")
+	if !found {
+		return "", fmt.Errorf("unexpected docker image digest %q", repoDigests[0])
+	}
+	return digest, nil
+}
+
+// PrepareAvalanchegoImage makes the AvalancheGo image for [avalancheGoVersion] available on
+// the remote host, and returns a reference to it pinned to its digest when possible (eg
+// avaplatform/avalanchego:v1.11.11@sha256:...), so the node keeps running the exact
+// same image even if the tag is later moved
+func PrepareAvalanchegoImage(host *models.Host, avalancheGoVersion string) (string, error) {
+	avagoDockerImage := fmt.Sprintf("%s:%s", constants.AvalancheGoDockerImage, avalancheGoVersion)
+	ux.Logger.Info("Preparing AvalancheGo Docker image %s on %s[%s]", avagoDockerImage, host.NodeID, host.IP)
+	if err := PrepareDockerImageWithRepo(host, avagoDockerImage, constants.AvalancheGoGitRepo, avalancheGoVersion); err != nil {
+		return "", err
+	}
+	digest, err := GetDockerImageDigest(host, avagoDockerImage)
+	if err != nil {
+		return "", err
+	}
+	if digest == "" {
+		ux.Logger.Info("Docker image %s has no repo digest on %s, using it unpinned", avagoDockerImage, host.NodeID)
+		return avagoDockerImage, nil
+	}
+	return avagoDockerImage + "@" + digest, nil
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDockerImageDigestOutput(t *testing.T) {
+	const digest = "sha256:0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9"
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+		err      string
+	}{
+		{
+			name:     "single repo digest",
+			output:   `["avaplatform/avalanchego@` + digest + `"]` + "\n",
+			expected: digest,
+		},
+		{
+			name:     "first of several repo digests",
+			output:   `["avaplatform/avalanchego@` + digest + `","mirror.io/avalanchego@sha256:ffff"]`,
+			expected: digest,
+		},
+		{
+			name:     "locally built image",
+			output:   "[]\n",
+			expected: "",
+		},
+		{
+			name:   "digest without repository",
+			output: `["` + digest + `"]`,
+			err:    "unexpected docker image digest",
+		},
+		{
+			name:   "docker error output",
+			output: "Error: No such image: avaplatform/avalanchego:v1.11.11",
+			err:    "unable to parse docker image digests",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			digest, err := parseDockerImageDigestOutput([]byte(tt.output))
+			if tt.err != "" {
+				require.ErrorContains(err, tt.err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tt.expected, digest)
+		})
+	}
+}
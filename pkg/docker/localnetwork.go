@@ -0,0 +1,402 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/local"
+	anrutils "github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const (
+	localNetworkComposeFileName = "docker-compose.yml"
+	localNetworkContainerDir    = "/data"
+	localNetworkHTTPPort        = 9650
+	localNetworkStakingPort     = 9651
+	// first host octet of the node containers, inside the project subnet
+	localNetworkFirstNodeIP = 10
+)
+
+// LocalNetworkNode is an avalanchego container of a local network run with the docker backend
+type LocalNetworkNode struct {
+	Name     string
+	IP       string
+	HTTPPort uint32
+	DataDir  string
+}
+
+// LocalNetworkComposeInputs are the template vars of the local network compose project
+type LocalNetworkComposeInputs struct {
+	ProjectName      string
+	AvalanchegoImage string
+	Subnet           string
+	// containers run as the host user, so that node state can be managed from the host
+	User string
+	// per node resource limits, in docker compose format (eg "1.5", "2g"). empty means no limit
+	CPUs   string
+	Memory string
+	Nodes  []LocalNetworkNode
+}
+
+// LocalNetworkParams defines a new local network to be run with the docker backend
+type LocalNetworkParams struct {
+	NumNodes uint32
+	// network ID and optional custom primary network genesis of the network
+	NetworkID          uint32
+	GenesisPath        string
+	AvalanchegoVersion string
+	CPUs               string
+	Memory             string
+	// global avalanchego config of the nodes, in JSON format
+	NodeConfig string
+	Upgrade    []byte
+}
+
+// LocalNetworkChain is a blockchain to be tracked by the nodes of a local network run
+// with the docker backend
+type LocalNetworkChain struct {
+	SubnetID     ids.ID
+	BlockchainID ids.ID
+	VMID         ids.ID
+	// VM binary, installed on the plugin dir of every node. It runs inside the containers
+	VMBinaryPath string
+	// optional chain and subnet configs of all nodes
+	ChainConfig  []byte
+	SubnetConfig []byte
+	// optional chain configs that replace [ChainConfig] on specific nodes, by node name
+	PerNodeChainConfigs map[string][]byte
+}
+
+// CheckLocalNetworkRequirements checks that docker and its compose plugin, used to run the
+// local network with the docker backend, are available
+func CheckLocalNetworkRequirements() error {
+	cmd := exec.Command("docker", "compose", "version")
+	cmd.Env = os.Environ()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("docker with the compose plugin is required by the docker local network backend: %w: %s", err, string(output))
+	}
+	return nil
+}
+
+// GetLocalNetworkComposeFile returns the path of the compose project of the local network
+// stored at [rootDir]
+func GetLocalNetworkComposeFile(rootDir string) string {
+	return filepath.Join(rootDir, localNetworkComposeFileName)
+}
+
+// LocalNetworkExists checks if a local network was already created at [rootDir]
+func LocalNetworkExists(rootDir string) bool {
+	return utils.FileExists(GetLocalNetworkComposeFile(rootDir))
+}
+
+// CreateLocalNetwork generates the staking keys, genesis, node configs and compose project
+// of a new local network at [rootDir], using the same defaults as the network runner
+func CreateLocalNetwork(rootDir string, params LocalNetworkParams) error {
+	networkConfig, err := local.NewDefaultConfigNNodes("", params.NumNodes, params.NetworkID, params.GenesisPath, "", nil)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(rootDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	subnetPrefix := strings.Join(strings.Split(constants.LocalDockerNetworkSubnet, ".")[:3], ".")
+	inputs := LocalNetworkComposeInputs{
+		ProjectName:      constants.LocalDockerNetworkProject,
+		AvalanchegoImage: getLocalNetworkImage(params.AvalanchegoVersion),
+		Subnet:           constants.LocalDockerNetworkSubnet,
+		User:             fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()),
+		CPUs:             params.CPUs,
+		Memory:           params.Memory,
+	}
+	bootstrapIPs := []string{}
+	bootstrapIDs := []string{}
+	for i, nodeConfig := range networkConfig.NodeConfigs {
+		node := LocalNetworkNode{
+			Name:     fmt.Sprintf("node%d", i+1),
+			IP:       fmt.Sprintf("%s.%d", subnetPrefix, localNetworkFirstNodeIP+i),
+			HTTPPort: uint32(localNetworkHTTPPort + 2*i),
+		}
+		node.DataDir = filepath.Join(rootDir, node.Name)
+		flags := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(params.NodeConfig), &flags); err != nil {
+			return fmt.Errorf("invalid node config: %w", err)
+		}
+		for k, v := range networkConfig.Flags {
+			flags[k] = v
+		}
+		for k, v := range map[string]interface{}{
+			config.DataDirKey:              localNetworkContainerDir,
+			config.NetworkNameKey:          networkConfig.NetworkID,
+			config.HTTPHostKey:             "0.0.0.0",
+			config.HTTPPortKey:             localNetworkHTTPPort,
+			config.StakingPortKey:          localNetworkStakingPort,
+			config.PublicIPKey:             node.IP,
+			config.StakingTLSKeyPathKey:    filepath.Join(localNetworkContainerDir, "staking", "staker.key"),
+			config.StakingCertPathKey:      filepath.Join(localNetworkContainerDir, "staking", "staker.crt"),
+			config.StakingSignerKeyPathKey: filepath.Join(localNetworkContainerDir, "staking", "signer.key"),
+			config.ChainConfigDirKey:       filepath.Join(localNetworkContainerDir, "configs", "chains"),
+			config.BootstrapIPsKey:         strings.Join(bootstrapIPs, ","),
+			config.BootstrapIDsKey:         strings.Join(bootstrapIDs, ","),
+		} {
+			flags[k] = v
+		}
+		files := map[string][]byte{
+			filepath.Join("staking", "staker.key"): []byte(nodeConfig.StakingKey),
+			filepath.Join("staking", "staker.crt"): []byte(nodeConfig.StakingCert),
+		}
+		files[filepath.Join("staking", "signer.key")], err = base64.StdEncoding.DecodeString(nodeConfig.StakingSigningKey)
+		if err != nil {
+			return fmt.Errorf("invalid signing key of %s: %w", node.Name, err)
+		}
+		if networkConfig.Genesis != "" {
+			files["genesis.json"] = []byte(networkConfig.Genesis)
+			flags[config.GenesisFileKey] = filepath.Join(localNetworkContainerDir, "genesis.json")
+		}
+		if len(params.Upgrade) != 0 {
+			files["upgrade.json"] = params.Upgrade
+			flags[config.UpgradeFileKey] = filepath.Join(localNetworkContainerDir, "upgrade.json")
+		}
+		for chainAlias, chainConfig := range networkConfig.ChainConfigFiles {
+			files[filepath.Join("configs", "chains", chainAlias, "config.json")] = []byte(chainConfig)
+		}
+		files[filepath.Join("configs", "node.json")], err = json.MarshalIndent(flags, "", "  ")
+		if err != nil {
+			return err
+		}
+		for path, content := range files {
+			path = filepath.Join(node.DataDir, path)
+			if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, content, constants.WriteReadUserOnlyPerms); err != nil {
+				return err
+			}
+		}
+		nodeID, err := anrutils.ToNodeID([]byte(nodeConfig.StakingKey), []byte(nodeConfig.StakingCert))
+		if err != nil {
+			return err
+		}
+		// every node bootstraps from the ones defined before it
+		bootstrapIPs = append(bootstrapIPs, fmt.Sprintf("%s:%d", node.IP, localNetworkStakingPort))
+		bootstrapIDs = append(bootstrapIDs, nodeID.String())
+		inputs.Nodes = append(inputs.Nodes, node)
+	}
+	composeBytes, err := renderComposeFile(
+		"templates/localnetwork.docker-compose.yml",
+		"local network compose",
+		inputs,
+	)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(GetLocalNetworkComposeFile(rootDir), composeBytes, constants.WriteReadUserOnlyPerms)
+}
+
+// getLocalNetworkImage returns the avalanchego image for [version]. The image registry
+// has no pre release tag, so latest is used for both latest tags
+func getLocalNetworkImage(version string) string {
+	if version == "" || version == constants.LatestPreReleaseVersionTag {
+		version = constants.LatestReleaseVersionTag
+	}
+	return fmt.Sprintf("%s:%s", constants.AvalancheGoDockerImage, version)
+}
+
+// GetLocalNetworkNodes returns the nodes of the local network at [rootDir], sorted by name index
+func GetLocalNetworkNodes(rootDir string) ([]LocalNetworkNode, error) {
+	nodeDirs, err := filepath.Glob(filepath.Join(rootDir, "node*"))
+	if err != nil {
+		return nil, err
+	}
+	nodeIndexes := []int{}
+	for _, nodeDir := range nodeDirs {
+		nodeIndex := 0
+		if _, err := fmt.Sscanf(filepath.Base(nodeDir), "node%d", &nodeIndex); err == nil {
+			nodeIndexes = append(nodeIndexes, nodeIndex)
+		}
+	}
+	sort.Ints(nodeIndexes)
+	subnetPrefix := strings.Join(strings.Split(constants.LocalDockerNetworkSubnet, ".")[:3], ".")
+	nodes := []LocalNetworkNode{}
+	for _, nodeIndex := range nodeIndexes {
+		name := fmt.Sprintf("node%d", nodeIndex)
+		nodes = append(nodes, LocalNetworkNode{
+			Name:     name,
+			IP:       fmt.Sprintf("%s.%d", subnetPrefix, localNetworkFirstNodeIP+nodeIndex-1),
+			HTTPPort: uint32(localNetworkHTTPPort + 2*(nodeIndex-1)),
+			DataDir:  filepath.Join(rootDir, name),
+		})
+	}
+	return nodes, nil
+}
+
+// GetLocalNetworkEndpoints returns the API endpoints of the nodes of the local network at [rootDir]
+func GetLocalNetworkEndpoints(rootDir string) ([]string, error) {
+	nodes, err := GetLocalNetworkNodes(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	endpoints := []string{}
+	for _, node := range nodes {
+		endpoints = append(endpoints, fmt.Sprintf("http://127.0.0.1:%d", node.HTTPPort))
+	}
+	return endpoints, nil
+}
+
+// StartLocalNetwork starts the containers of the local network at [rootDir], and waits
+// until all nodes are healthy
+func StartLocalNetwork(rootDir string, timeout time.Duration) error {
+	if _, err := localNetworkCompose(rootDir, "up", "--detach", "--remove-orphans"); err != nil {
+		return err
+	}
+	return waitLocalNetworkHealthy(rootDir, timeout)
+}
+
+func waitLocalNetworkHealthy(rootDir string, timeout time.Duration) error {
+	endpoints, err := GetLocalNetworkEndpoints(rootDir)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, endpoint := range endpoints {
+		healthy, err := health.AwaitHealthy(ctx, health.NewClient(endpoint), time.Second, nil)
+		if err != nil {
+			return fmt.Errorf("node %s did not become healthy: %w", endpoint, err)
+		}
+		if !healthy {
+			return fmt.Errorf("node %s is not healthy", endpoint)
+		}
+	}
+	return nil
+}
+
+// TrackLocalNetworkChain makes all nodes of the local network at [rootDir] track [chain]:
+// installs its VM and configs on the node dirs, adds its subnet to the tracked subnets of
+// the node configs, and restarts the containers, waiting until all nodes are healthy
+func TrackLocalNetworkChain(rootDir string, chain LocalNetworkChain, timeout time.Duration) error {
+	nodes, err := GetLocalNetworkNodes(rootDir)
+	if err != nil {
+		return err
+	}
+	vmBinary, err := os.ReadFile(chain.VMBinaryPath)
+	if err != nil {
+		return fmt.Errorf("failure reading VM binary: %w", err)
+	}
+	for _, node := range nodes {
+		files := map[string][]byte{
+			filepath.Join("plugins", chain.VMID.String()): vmBinary,
+		}
+		chainConfig := chain.ChainConfig
+		if perNodeChainConfig, ok := chain.PerNodeChainConfigs[node.Name]; ok {
+			chainConfig = perNodeChainConfig
+		}
+		if len(chainConfig) != 0 {
+			files[filepath.Join("configs", "chains", chain.BlockchainID.String(), "config.json")] = chainConfig
+		}
+		if len(chain.SubnetConfig) != 0 {
+			files[filepath.Join("configs", "subnets", chain.SubnetID.String()+".json")] = chain.SubnetConfig
+		}
+		for path, content := range files {
+			path = filepath.Join(node.DataDir, path)
+			if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, content, constants.WriteReadUserOnlyPerms); err != nil {
+				return err
+			}
+		}
+		// the plugin is executed by the container user, which is the host user
+		if err := os.Chmod(filepath.Join(node.DataDir, "plugins", chain.VMID.String()), constants.DefaultPerms755); err != nil {
+			return err
+		}
+		if err := addNodeTrackedSubnet(node, chain.SubnetID); err != nil {
+			return err
+		}
+	}
+	if _, err := localNetworkCompose(rootDir, "restart"); err != nil {
+		return err
+	}
+	return waitLocalNetworkHealthy(rootDir, timeout)
+}
+
+// addNodeTrackedSubnet adds [subnetID] to the tracked subnets of the config of [node],
+// pointing the plugin and subnet config dirs to the node data dir
+func addNodeTrackedSubnet(node LocalNetworkNode, subnetID ids.ID) error {
+	nodeConfigPath := filepath.Join(node.DataDir, "configs", "node.json")
+	nodeConfigBytes, err := os.ReadFile(nodeConfigPath)
+	if err != nil {
+		return err
+	}
+	flags := map[string]interface{}{}
+	if err := json.Unmarshal(nodeConfigBytes, &flags); err != nil {
+		return fmt.Errorf("invalid config of %s: %w", node.Name, err)
+	}
+	trackedSubnets := []string{}
+	if current, ok := flags[config.TrackSubnetsKey].(string); ok && current != "" {
+		trackedSubnets = strings.Split(current, ",")
+	}
+	if !utils.Belongs(trackedSubnets, subnetID.String()) {
+		trackedSubnets = append(trackedSubnets, subnetID.String())
+	}
+	flags[config.TrackSubnetsKey] = strings.Join(trackedSubnets, ",")
+	flags[config.PluginDirKey] = filepath.Join(localNetworkContainerDir, "plugins")
+	flags[config.SubnetConfigDirKey] = filepath.Join(localNetworkContainerDir, "configs", "subnets")
+	nodeConfigBytes, err = json.MarshalIndent(flags, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(nodeConfigPath, nodeConfigBytes, constants.WriteReadUserOnlyPerms)
+}
+
+// StopLocalNetwork stops and removes the containers of the local network at [rootDir].
+// Node state is kept on [rootDir], so that a later start resumes the network
+func StopLocalNetwork(rootDir string) error {
+	_, err := localNetworkCompose(rootDir, "down")
+	return err
+}
+
+// RemoveLocalNetwork removes the containers, project network and all state of the local
+// network at [rootDir]
+func RemoveLocalNetwork(rootDir string) error {
+	if LocalNetworkExists(rootDir) {
+		if _, err := localNetworkCompose(rootDir, "down", "--volumes", "--remove-orphans"); err != nil {
+			ux.Logger.RedXToUser("failure removing local network containers: %s", err)
+		}
+	}
+	return os.RemoveAll(rootDir)
+}
+
+// GetLocalNetworkStatus returns the compose status of the containers of the local network
+// at [rootDir]
+func GetLocalNetworkStatus(rootDir string) (string, error) {
+	output, err := localNetworkCompose(rootDir, "ps", "--all")
+	return string(output), err
+}
+
+func localNetworkCompose(rootDir string, args ...string) ([]byte, error) {
+	composeArgs := append([]string{"compose", "-f", GetLocalNetworkComposeFile(rootDir)}, args...)
+	cmd := exec.Command("docker", composeArgs...)
+	cmd.Env = os.Environ()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("docker %s failed: %w: %s", strings.Join(composeArgs, " "), err, string(output))
+	}
+	return output, nil
+}
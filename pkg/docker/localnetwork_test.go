@@ -0,0 +1,45 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package docker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddNodeTrackedSubnet(t *testing.T) {
+	require := require.New(t)
+	node := LocalNetworkNode{Name: "node1", DataDir: t.TempDir()}
+	nodeConfigPath := filepath.Join(node.DataDir, "configs", "node.json")
+	require.NoError(os.MkdirAll(filepath.Dir(nodeConfigPath), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(nodeConfigPath, []byte(`{"http-port": 9650}`), constants.WriteReadUserOnlyPerms))
+
+	readConfig := func() map[string]interface{} {
+		flags := map[string]interface{}{}
+		bs, err := os.ReadFile(nodeConfigPath)
+		require.NoError(err)
+		require.NoError(json.Unmarshal(bs, &flags))
+		return flags
+	}
+
+	subnet1, subnet2 := ids.GenerateTestID(), ids.GenerateTestID()
+	require.NoError(addNodeTrackedSubnet(node, subnet1))
+	flags := readConfig()
+	require.Equal(subnet1.String(), flags[config.TrackSubnetsKey])
+	require.Equal("/data/plugins", flags[config.PluginDirKey])
+	require.Equal("/data/configs/subnets", flags[config.SubnetConfigDirKey])
+	require.Equal(float64(9650), flags["http-port"])
+
+	require.NoError(addNodeTrackedSubnet(node, subnet2))
+	// tracking a subnet again does not repeat it
+	require.NoError(addNodeTrackedSubnet(node, subnet1))
+	require.Equal(subnet1.String()+","+subnet2.String(), readConfig()[config.TrackSubnetsKey])
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
@@ -38,21 +39,12 @@ func ComposeSSHSetupNode(
 	publicAccessToHTTPPort bool,
 ) error {
 	startTime := time.Now()
-	folderStructure := remoteconfig.RemoteFoldersToCreateAvalanchego()
-	for _, dir := range folderStructure {
-		if err := host.MkdirAll(dir, constants.SSHFileOpsTimeout); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dir, err)
-		}
-	}
-	ux.Logger.Info("avalancheCLI folder structure created on remote host %s after %s", folderStructure, time.Since(startTime))
-
-	avagoDockerImage := fmt.Sprintf("%s:%s", constants.AvalancheGoDockerImage, avalancheGoVersion)
-	ux.Logger.Info("Preparing AvalancheGo Docker image %s on %s[%s]", avagoDockerImage, host.NodeID, host.IP)
-	if err := PrepareDockerImageWithRepo(host, avagoDockerImage, constants.AvalancheGoGitRepo, avalancheGoVersion); err != nil {
+	avagoDockerImage, err := PrepareAvalanchegoImage(host, avalancheGoVersion)
+	if err != nil {
 		return err
 	}
 	ux.Logger.Info("AvalancheGo Docker image %s ready on %s[%s] after %s", avagoDockerImage, host.NodeID, host.IP, time.Since(startTime))
-	nodeConfFile, cChainConfFile, err := prepareAvalanchegoConfig(
+	if err := SetupAvalanchegoConfigs(
 		host,
 		network,
 		AvalancheGoConfigOptions{
@@ -63,7 +55,40 @@ func ComposeSSHSetupNode(
 			UpgradePath:       avalanchegoUpgradeFilePath,
 			AllowPublicAccess: publicAccessToHTTPPort,
 		},
-	)
+	); err != nil {
+		return err
+	}
+	return ComposeOverSSH("Compose Node",
+		host,
+		constants.SSHScriptTimeout,
+		"templates/avalanchego.docker-compose.yml",
+		DockerComposeInputs{
+			AvalanchegoVersion: avalancheGoVersion,
+			AvalanchegoImage:   avagoDockerImage,
+			WithMonitoring:     withMonitoring,
+			WithAvalanchego:    true,
+			E2E:                utils.IsE2E(),
+			E2EIP:              utils.E2EConvertIP(host.IP),
+			E2ESuffix:          utils.E2ESuffix(host.IP),
+		})
+}
+
+// SetupAvalanchegoConfigs creates the avalanchego folder structure on a remote host, and
+// uploads the node config, the C-Chain config and the given genesis and upgrade files.
+func SetupAvalanchegoConfigs(
+	host *models.Host,
+	network models.Network,
+	avalancheGoConfig AvalancheGoConfigOptions,
+) error {
+	startTime := time.Now()
+	folderStructure := remoteconfig.RemoteFoldersToCreateAvalanchego()
+	for _, dir := range folderStructure {
+		if err := host.MkdirAll(dir, constants.SSHFileOpsTimeout); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+	ux.Logger.Info("avalancheCLI folder structure created on remote host %s after %s", folderStructure, time.Since(startTime))
+	nodeConfFile, cChainConfFile, err := prepareAvalanchegoConfig(host, network, avalancheGoConfig)
 	if err != nil {
 		return err
 	}
@@ -82,39 +107,63 @@ func ComposeSSHSetupNode(
 	if err := host.Upload(cChainConfFile, remoteconfig.GetRemoteAvalancheCChainConfig(), constants.SSHFileOpsTimeout); err != nil {
 		return err
 	}
-	if avalanchegoGenesisFilePath != "" {
-		if err := host.Upload(avalanchegoGenesisFilePath, remoteconfig.GetRemoteAvalancheGenesis(), constants.SSHFileOpsTimeout); err != nil {
+	if avalancheGoConfig.GenesisPath != "" {
+		if err := host.Upload(avalancheGoConfig.GenesisPath, remoteconfig.GetRemoteAvalancheGenesis(), constants.SSHFileOpsTimeout); err != nil {
 			return err
 		}
 	}
-	if avalanchegoUpgradeFilePath != "" {
-		if err := host.Upload(avalanchegoUpgradeFilePath, remoteconfig.GetRemoteAvalancheUpgrade(), constants.SSHFileOpsTimeout); err != nil {
+	if avalancheGoConfig.UpgradePath != "" {
+		if err := host.Upload(avalancheGoConfig.UpgradePath, remoteconfig.GetRemoteAvalancheUpgrade(), constants.SSHFileOpsTimeout); err != nil {
 			return err
 		}
 	}
 	ux.Logger.Info("AvalancheGo configs uploaded to %s[%s] after %s", host.NodeID, host.IP, time.Since(startTime))
+	return nil
+}
+
+func ComposeSSHSetupLoadTest(host *models.Host) error {
 	return ComposeOverSSH("Compose Node",
 		host,
 		constants.SSHScriptTimeout,
 		"templates/avalanchego.docker-compose.yml",
 		DockerComposeInputs{
-			AvalanchegoVersion: avalancheGoVersion,
-			WithMonitoring:     withMonitoring,
-			WithAvalanchego:    true,
-			E2E:                utils.IsE2E(),
-			E2EIP:              utils.E2EConvertIP(host.IP),
-			E2ESuffix:          utils.E2ESuffix(host.IP),
+			WithMonitoring:  true,
+			WithAvalanchego: false,
 		})
 }
 
-func ComposeSSHSetupLoadTest(host *models.Host) error {
-	return ComposeOverSSH("Compose Node",
+// ComposeSSHSetupNodeMonitoring adds promtail and node exporter to an AvalancheGo node
+// that was set up without monitoring.
+func ComposeSSHSetupNodeMonitoring(host *models.Host) error {
+	return ComposeOverSSH("Compose Node Monitoring",
 		host,
 		constants.SSHScriptTimeout,
 		"templates/avalanchego.docker-compose.yml",
 		DockerComposeInputs{
 			WithMonitoring:  true,
 			WithAvalanchego: false,
+			E2E:             utils.IsE2E(),
+			E2EIP:           utils.E2EConvertIP(host.IP),
+			E2ESuffix:       utils.E2ESuffix(host.IP),
+		})
+}
+
+// ComposeSSHSetupNodeOverrides merges the database dir and resource limits of [hostNodeConfig]
+// into the avalanchego service of a remote host. Limits not set are removed. The service is not
+// restarted: the changes are applied the next time avalanchego starts
+func ComposeSSHSetupNodeOverrides(host *models.Host, hostNodeConfig models.HostNodeConfig) error {
+	memory := hostNodeConfig.Memory
+	if memory == "" {
+		memory = "0"
+	}
+	return pushComposeOverSSH("Compose Node Overrides",
+		host,
+		constants.SSHScriptTimeout,
+		"templates/avalanchego-overrides.docker-compose.yml",
+		DockerComposeInputs{
+			AvalanchegoDBDir:  hostNodeConfig.DBDir,
+			AvalanchegoCPUs:   strconv.FormatFloat(hostNodeConfig.CPUs, 'f', -1, 64),
+			AvalanchegoMemory: memory,
 		})
 }
 
@@ -177,3 +226,22 @@ func ComposeSSHSetupICMRelayer(host *models.Host, relayerVersion string) error {
 			ICMRelayerVersion: relayerVersion,
 		})
 }
+
+// ComposeSSHSetupReverseProxy adds the caddy reverse proxy to the host compose file
+func ComposeSSHSetupReverseProxy(host *models.Host) error {
+	return ComposeOverSSH("Setup Reverse Proxy",
+		host,
+		constants.SSHScriptTimeout,
+		"templates/caddy.docker-compose.yml",
+		DockerComposeInputs{})
+}
+
+// ComposeSSHSetupTelemetryAgent adds the agent exporting node metrics and logs to the
+// external monitoring [backend] to the host compose file
+func ComposeSSHSetupTelemetryAgent(host *models.Host, backend string) error {
+	return ComposeOverSSH("Setup Telemetry Agent",
+		host,
+		constants.SSHScriptTimeout,
+		fmt.Sprintf("templates/%s.docker-compose.yml", backend),
+		DockerComposeInputs{})
+}
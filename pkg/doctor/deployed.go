@@ -0,0 +1,188 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package doctor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/node"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"golang.org/x/exp/maps"
+)
+
+// CheckBlockchain inspects the deployments of [blockchainName]: that the local network
+// is running for local deployments, and that the RPC endpoints of EVM blockchains answer
+func CheckBlockchain(app *application.Avalanche, blockchainName string) []Finding {
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return []Finding{{
+			Severity:    Critical,
+			Area:        BlockchainArea,
+			Problem:     fmt.Sprintf("blockchain %s can't be loaded: %s", blockchainName, err),
+			Remediation: "avalanche blockchain list",
+		}}
+	}
+	if len(sc.Networks) == 0 {
+		return []Finding{{
+			Severity:    Info,
+			Area:        BlockchainArea,
+			Problem:     fmt.Sprintf("blockchain %s is not deployed", blockchainName),
+			Remediation: fmt.Sprintf("avalanche blockchain deploy %s", blockchainName),
+		}}
+	}
+	findings := []Finding{}
+	networkNames := maps.Keys(sc.Networks)
+	sort.Strings(networkNames)
+	for _, networkName := range networkNames {
+		networkData := sc.Networks[networkName]
+		if networkName == models.Local.String() {
+			if !isLocalNetworkRunning() {
+				findings = append(findings, Finding{
+					Severity:    Warning,
+					Area:        BlockchainArea,
+					Problem:     fmt.Sprintf("blockchain %s is deployed on %s, which is not running", blockchainName, networkName),
+					Remediation: "avalanche network start",
+				})
+				continue
+			}
+			if deployed, err := localnet.Deployed(blockchainName); err == nil && !deployed {
+				findings = append(findings, Finding{
+					Severity:    Critical,
+					Area:        BlockchainArea,
+					Problem:     fmt.Sprintf("blockchain %s is recorded as deployed on %s, but the running local network doesn't have it", blockchainName, networkName),
+					Remediation: fmt.Sprintf("avalanche network stop, then avalanche network start to restore the snapshot, or deploy it again with avalanche blockchain deploy %s --local", blockchainName),
+				})
+				continue
+			}
+		}
+		if sc.VM != models.SubnetEvm {
+			continue
+		}
+		for _, rpcEndpoint := range networkData.RPCEndpoints {
+			if err := checkRPCEndpoint(rpcEndpoint); err != nil {
+				remediation := "check the nodes validating the blockchain are running and track it"
+				switch {
+				case networkName == models.Local.String():
+					remediation = "avalanche network status"
+				case networkData.ClusterName != "":
+					remediation = fmt.Sprintf("avalanche node status %s --blockchain %s", networkData.ClusterName, blockchainName)
+				}
+				findings = append(findings, Finding{
+					Severity:    Critical,
+					Area:        BlockchainArea,
+					Problem:     fmt.Sprintf("RPC endpoint %s of blockchain %s on %s doesn't answer: %s", rpcEndpoint, blockchainName, networkName, err),
+					Remediation: remediation,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// checkRPCEndpoint checks that the EVM RPC endpoint at [rpcURL] answers its chain id
+func checkRPCEndpoint(rpcURL string) error {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	client, err := evm.DialContext(ctx, rpcURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	_, err = client.ChainID(ctx)
+	return err
+}
+
+// CheckCluster inspects the nodes of [clusterName]: that their SSH keys are in place, that
+// they are reachable over SSH, and that avalanchego is healthy on them
+func CheckCluster(app *application.Avalanche, clusterName string) []Finding {
+	if exists, err := node.CheckClusterExists(app, clusterName); err != nil || !exists {
+		return []Finding{{
+			Severity:    Critical,
+			Area:        ClusterArea,
+			Problem:     fmt.Sprintf("cluster %s not found", clusterName),
+			Remediation: "avalanche node list",
+		}}
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return []Finding{{
+			Severity:    Critical,
+			Area:        ClusterArea,
+			Problem:     fmt.Sprintf("cluster %s config can't be loaded: %s", clusterName, err),
+			Remediation: "restore it with avalanche backup restore",
+		}}
+	}
+	if clusterConfig.Local {
+		return nil
+	}
+	findings := []Finding{}
+	for _, cloudID := range clusterConfig.GetCloudIDs() {
+		nodeConfig, err := app.LoadClusterNodeConfig(cloudID)
+		if err != nil {
+			findings = append(findings, Finding{
+				Severity:    Critical,
+				Area:        ClusterArea,
+				Problem:     fmt.Sprintf("config of node %s can't be loaded: %s", cloudID, err),
+				Remediation: "restore it with avalanche backup restore",
+			})
+			continue
+		}
+		if nodeConfig.CertPath != "" && !utils.FileExists(utils.ExpandHome(nodeConfig.CertPath)) {
+			findings = append(findings, Finding{
+				Severity:    Critical,
+				Area:        ClusterArea,
+				Problem:     fmt.Sprintf("SSH key %s of node %s is missing", nodeConfig.CertPath, cloudID),
+				Remediation: fmt.Sprintf("restore the key pair %s at %s", nodeConfig.KeyPair, nodeConfig.CertPath),
+			})
+		}
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return append(findings, Finding{
+			Severity:    Critical,
+			Area:        ClusterArea,
+			Problem:     fmt.Sprintf("inventory of cluster %s can't be loaded: %s", clusterName, err),
+			Remediation: "restore it with avalanche backup restore",
+		})
+	}
+	defer node.DisconnectHosts(hosts)
+	for _, host := range hosts {
+		cloudID := host.GetCloudID()
+		if err := host.Connect(0); err != nil {
+			findings = append(findings, Finding{
+				Severity:    Critical,
+				Area:        ClusterArea,
+				Problem:     fmt.Sprintf("node %s (%s) is not reachable over SSH: %s", cloudID, host.IP, err),
+				Remediation: fmt.Sprintf("check the instance is running and its security group allows your IP, then avalanche node ssh %s", cloudID),
+			})
+			continue
+		}
+		if !clusterConfig.IsAvalancheGoHost(cloudID) {
+			continue
+		}
+		unhealthyNodes, err := node.GetUnhealthyNodes([]*models.Host{host})
+		switch {
+		case err != nil:
+			findings = append(findings, Finding{
+				Severity:    Warning,
+				Area:        ClusterArea,
+				Problem:     fmt.Sprintf("could not get the health of node %s: %s", cloudID, err),
+				Remediation: fmt.Sprintf("avalanche node status %s", clusterName),
+			})
+		case len(unhealthyNodes) > 0:
+			findings = append(findings, Finding{
+				Severity:    Warning,
+				Area:        ClusterArea,
+				Problem:     fmt.Sprintf("avalanchego is not healthy on node %s", cloudID),
+				Remediation: fmt.Sprintf("avalanche node status %s, and check its logs with avalanche node ssh %s", clusterName, cloudID),
+			})
+		}
+	}
+	return findings
+}
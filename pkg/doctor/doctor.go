@@ -0,0 +1,71 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package doctor
+
+import (
+	"sort"
+)
+
+// Severity ranks how much a finding can break the CLI operations
+type Severity int
+
+const (
+	// Critical findings make CLI operations fail
+	Critical Severity = iota
+	// Warning findings are likely to cause problems
+	Warning
+	// Info findings are worth knowing but need no action
+	Info
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Critical:
+		return "Critical"
+	case Warning:
+		return "Warning"
+	default:
+		return "Info"
+	}
+}
+
+// areas inspected by the checks
+const (
+	PortsArea      = "Ports"
+	DiskArea       = "Disk"
+	VersionsArea   = "Versions"
+	ProcessesArea  = "Processes"
+	StateArea      = "State Files"
+	KeysArea       = "Keys"
+	BlockchainArea = "Blockchain"
+	ClusterArea    = "Cluster"
+)
+
+// Finding is a problem found on the environment, with the command or action that
+// remediates it
+type Finding struct {
+	Severity    Severity
+	Area        string
+	Problem     string
+	Remediation string
+}
+
+// RankFindings sorts [findings] from the most to the least severe, keeping the
+// order of the checks among findings of the same severity
+func RankFindings(findings []Finding) []Finding {
+	ranked := append([]Finding{}, findings...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Severity < ranked[j].Severity
+	})
+	return ranked
+}
+
+// HasCritical returns true if any of [findings] is critical
+func HasCritical(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == Critical {
+			return true
+		}
+	}
+	return false
+}
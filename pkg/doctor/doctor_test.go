@@ -0,0 +1,63 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package doctor
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankFindings(t *testing.T) {
+	require := require.New(t)
+	findings := []Finding{
+		{Severity: Info, Problem: "a"},
+		{Severity: Critical, Problem: "b"},
+		{Severity: Warning, Problem: "c"},
+		{Severity: Critical, Problem: "d"},
+	}
+	ranked := RankFindings(findings)
+	problems := []string{}
+	for _, finding := range ranked {
+		problems = append(problems, finding.Problem)
+	}
+	require.Equal([]string{"b", "d", "c", "a"}, problems)
+	// the input is kept as is
+	require.Equal("a", findings[0].Problem)
+	require.True(HasCritical(findings))
+	require.False(HasCritical(findings[2:3]))
+}
+
+func TestDiskSpaceFindings(t *testing.T) {
+	require := require.New(t)
+	require.Empty(diskSpaceFindings("/home", 100*units.GiB))
+	findings := diskSpaceFindings("/home", 10*units.GiB)
+	require.Len(findings, 1)
+	require.Equal(Warning, findings[0].Severity)
+	require.Contains(findings[0].Problem, "10.0 GiB")
+	findings = diskSpaceFindings("/home", units.GiB)
+	require.Len(findings, 1)
+	require.Equal(Critical, findings[0].Severity)
+}
+
+func TestCLIVersionFindings(t *testing.T) {
+	require := require.New(t)
+	require.Empty(cliVersionFindings("1.8.2", "v1.8.2"))
+	require.Empty(cliVersionFindings("v1.8.3", "v1.8.2"))
+	require.Empty(cliVersionFindings("", "v1.8.2"))
+	findings := cliVersionFindings("1.8.1", "v1.8.2")
+	require.Len(findings, 1)
+	require.Equal(Info, findings[0].Severity)
+	require.Equal("avalanche update", findings[0].Remediation)
+}
+
+func TestRPCVersionFindings(t *testing.T) {
+	require := require.New(t)
+	require.Empty(rpcVersionFindings("chain", 38, "v1.12.0", 38))
+	require.Empty(rpcVersionFindings("chain", 0, "v1.12.0", 38))
+	findings := rpcVersionFindings("chain", 37, "v1.12.0", 38)
+	require.Len(findings, 1)
+	require.Equal(Critical, findings[0].Severity)
+	require.Contains(findings[0].Problem, "RPC version 37")
+}
@@ -0,0 +1,338 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/localnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/process"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// free disk space under which the local network nodes are likely to stop
+	criticalFreeDiskSpace = 5 * units.GiB
+	// free disk space under which the local network nodes may run out of space soon
+	lowFreeDiskSpace = 20 * units.GiB
+)
+
+// CheckLocalEnvironment inspects the local environment the CLI runs on: the local network
+// ports, the disk space, the CLI and avalanchego versions, stale processes, and the
+// blockchain, cluster and key files. [cliVersion] is the version of the running CLI
+func CheckLocalEnvironment(app *application.Avalanche, cliVersion string) []Finding {
+	localNetworkRunning := isLocalNetworkRunning()
+	findings := []Finding{}
+	findings = append(findings, checkPorts(app, localNetworkRunning)...)
+	findings = append(findings, checkDiskSpace(app)...)
+	findings = append(findings, checkVersions(app, cliVersion, localNetworkRunning)...)
+	findings = append(findings, checkProcesses(app)...)
+	findings = append(findings, checkStateFiles(app)...)
+	findings = append(findings, checkKeys(app)...)
+	return findings
+}
+
+func isLocalNetworkRunning() bool {
+	_, err := localnet.GetClusterInfo()
+	return err == nil
+}
+
+// checkPorts looks for processes holding the ports the local network needs. A running
+// local network holds its own ports, so they are only checked while it is stopped
+func checkPorts(app *application.Avalanche, localNetworkRunning bool) []Finding {
+	if localNetworkRunning {
+		return nil
+	}
+	conflicts, err := localnet.CheckLocalNetworkPorts(app.GetSnapshotPath(constants.DefaultSnapshotName), constants.LocalNetworkNumNodes)
+	if err != nil {
+		return []Finding{{
+			Severity:    Warning,
+			Area:        PortsArea,
+			Problem:     fmt.Sprintf("could not check the local network ports: %s", err),
+			Remediation: "avalanche network clean",
+		}}
+	}
+	findings := []Finding{}
+	for _, conflict := range conflicts {
+		findings = append(findings, Finding{
+			Severity:    Critical,
+			Area:        PortsArea,
+			Problem:     utils.PortConflictsReport([]utils.PortConflict{conflict}),
+			Remediation: "stop the process using the port, or run avalanche network start --auto-ports",
+		})
+	}
+	return findings
+}
+
+func checkDiskSpace(app *application.Avalanche) []Finding {
+	usage, err := disk.Usage(app.GetBaseDir())
+	if err != nil {
+		return []Finding{{
+			Severity: Info,
+			Area:     DiskArea,
+			Problem:  fmt.Sprintf("could not check the free disk space of %s: %s", app.GetBaseDir(), err),
+		}}
+	}
+	return diskSpaceFindings(app.GetBaseDir(), usage.Free)
+}
+
+// diskSpaceFindings reports [free] bytes of disk space left on [path] if it is low
+func diskSpaceFindings(path string, free uint64) []Finding {
+	var severity Severity
+	switch {
+	case free < criticalFreeDiskSpace:
+		severity = Critical
+	case free < lowFreeDiskSpace:
+		severity = Warning
+	default:
+		return nil
+	}
+	return []Finding{{
+		Severity:    severity,
+		Area:        DiskArea,
+		Problem:     fmt.Sprintf("only %.1f GiB of disk space left on %s", float64(free)/float64(units.GiB), path),
+		Remediation: "free disk space, or remove the local network state and downloaded binaries with avalanche network clean --hard",
+	}}
+}
+
+func checkVersions(app *application.Avalanche, cliVersion string, localNetworkRunning bool) []Finding {
+	findings := []Finding{}
+	latestCLIVersion, err := app.Downloader.GetLatestReleaseVersion(constants.AvaLabsOrg, constants.CliRepoName, "")
+	if err != nil {
+		findings = append(findings, Finding{
+			Severity: Info,
+			Area:     VersionsArea,
+			Problem:  fmt.Sprintf("could not get the latest CLI release: %s", err),
+		})
+	} else {
+		findings = append(findings, cliVersionFindings(cliVersion, latestCLIVersion)...)
+	}
+	if !localNetworkRunning {
+		return findings
+	}
+	_, avagoVersion, rpcVersion, err := localnet.GetVersion()
+	if err != nil {
+		return append(findings, Finding{
+			Severity:    Warning,
+			Area:        VersionsArea,
+			Problem:     fmt.Sprintf("could not get the avalanchego version of the local network: %s", err),
+			Remediation: "avalanche network status",
+		})
+	}
+	blockchainNames, err := app.GetBlockchainNamesOnNetwork(app.GetLocalNetwork(), false)
+	if err != nil {
+		return findings
+	}
+	for _, blockchainName := range blockchainNames {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			// reported by the state files check
+			continue
+		}
+		findings = append(findings, rpcVersionFindings(blockchainName, sc.RPCVersion, avagoVersion, rpcVersion)...)
+	}
+	return findings
+}
+
+// cliVersionFindings reports a newer [latestVersion] of the CLI than [currentVersion]
+func cliVersionFindings(currentVersion string, latestVersion string) []Finding {
+	if currentVersion == "" {
+		return nil
+	}
+	if !strings.HasPrefix(currentVersion, "v") {
+		currentVersion = "v" + currentVersion
+	}
+	if !semver.IsValid(currentVersion) || !semver.IsValid(latestVersion) || semver.Compare(latestVersion, currentVersion) <= 0 {
+		return nil
+	}
+	return []Finding{{
+		Severity:    Info,
+		Area:        VersionsArea,
+		Problem:     fmt.Sprintf("CLI %s is available, running %s", latestVersion, currentVersion),
+		Remediation: "avalanche update",
+	}}
+}
+
+// rpcVersionFindings reports a blockchain whose VM needs a different avalanchego RPC
+// version than the [avagoRPCVersion] of the local network
+func rpcVersionFindings(blockchainName string, vmRPCVersion int, avagoVersion string, avagoRPCVersion int) []Finding {
+	if vmRPCVersion == 0 || vmRPCVersion == avagoRPCVersion {
+		return nil
+	}
+	return []Finding{{
+		Severity: Critical,
+		Area:     VersionsArea,
+		Problem: fmt.Sprintf(
+			"blockchain %s needs avalanchego RPC version %d, but the local network runs avalanchego %s with RPC version %d",
+			blockchainName,
+			vmRPCVersion,
+			avagoVersion,
+			avagoRPCVersion,
+		),
+		Remediation: "avalanche network stop, then avalanche network start --avalanchego-version <compatible version>",
+	}}
+}
+
+// checkProcesses looks for run files of backends that are not running anymore, and for
+// backends left running without a run file
+func checkProcesses(app *application.Avalanche) []Finding {
+	findings := []Finding{}
+	anyServerRunning := false
+	for _, prefix := range []string{constants.ServerRunFileLocalNetworkPrefix, constants.ServerRunFileLocalClusterPrefix} {
+		runFile := app.GetRunFile(prefix)
+		if _, err := os.Stat(runFile); err != nil {
+			continue
+		}
+		running, err := binutils.NewProcessChecker().IsServerProcessRunning(app, prefix)
+		if err != nil {
+			findings = append(findings, Finding{
+				Severity:    Warning,
+				Area:        ProcessesArea,
+				Problem:     fmt.Sprintf("backend run file %s is corrupted: %s", runFile, err),
+				Remediation: "avalanche network clean",
+			})
+			continue
+		}
+		if !running {
+			findings = append(findings, Finding{
+				Severity:    Warning,
+				Area:        ProcessesArea,
+				Problem:     fmt.Sprintf("backend run file %s refers to a process that is not running", runFile),
+				Remediation: "avalanche network clean",
+			})
+			continue
+		}
+		anyServerRunning = true
+	}
+	if anyServerRunning {
+		return findings
+	}
+	backendPIDs := getBackendPIDs()
+	if len(backendPIDs) > 0 {
+		findings = append(findings, Finding{
+			Severity:    Warning,
+			Area:        ProcessesArea,
+			Problem:     fmt.Sprintf("backend processes %v are running without a run file, and may hold local network ports", backendPIDs),
+			Remediation: "avalanche network clean --hard",
+		})
+	}
+	return findings
+}
+
+// getBackendPIDs returns the PIDs of the CLI backend processes running on the machine
+func getBackendPIDs() []int32 {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil
+	}
+	regex := regexp.MustCompile(".* " + constants.BackendCmd + ".*")
+	pids := []int32{}
+	for _, p := range procs {
+		cmdLine, err := p.Cmdline()
+		if err != nil {
+			// the process may have just died
+			continue
+		}
+		if regex.MatchString(cmdLine) {
+			pids = append(pids, p.Pid)
+		}
+	}
+	return pids
+}
+
+// checkStateFiles looks for blockchain and cluster files that can't be loaded
+func checkStateFiles(app *application.Avalanche) []Finding {
+	findings := []Finding{}
+	blockchainNames, err := app.GetBlockchainNames()
+	if err != nil {
+		return append(findings, Finding{
+			Severity:    Critical,
+			Area:        StateArea,
+			Problem:     fmt.Sprintf("could not list the blockchains at %s: %s", app.GetSubnetDir(), err),
+			Remediation: "avalanche backup restore",
+		})
+	}
+	for _, blockchainName := range blockchainNames {
+		remediation := fmt.Sprintf("restore it with avalanche backup restore, or remove it with avalanche blockchain delete %s", blockchainName)
+		if _, err := app.LoadSidecar(blockchainName); err != nil {
+			findings = append(findings, Finding{
+				Severity:    Critical,
+				Area:        StateArea,
+				Problem:     fmt.Sprintf("sidecar of blockchain %s is corrupted: %s", blockchainName, err),
+				Remediation: remediation,
+			})
+			continue
+		}
+		genesisBytes, err := app.LoadRawGenesis(blockchainName)
+		switch {
+		case err != nil:
+			findings = append(findings, Finding{
+				Severity:    Critical,
+				Area:        StateArea,
+				Problem:     fmt.Sprintf("genesis of blockchain %s can't be read: %s", blockchainName, err),
+				Remediation: remediation,
+			})
+		case len(genesisBytes) > 0 && genesisBytes[0] == '{' && !json.Valid(genesisBytes):
+			findings = append(findings, Finding{
+				Severity:    Critical,
+				Area:        StateArea,
+				Problem:     fmt.Sprintf("genesis of blockchain %s is not valid JSON", blockchainName),
+				Remediation: remediation,
+			})
+		}
+	}
+	if utils.FileExists(app.GetClustersConfigPath()) {
+		if _, err := app.LoadClustersConfig(); err != nil {
+			findings = append(findings, Finding{
+				Severity:    Critical,
+				Area:        StateArea,
+				Problem:     fmt.Sprintf("clusters config %s is corrupted: %s", app.GetClustersConfigPath(), err),
+				Remediation: "restore it with avalanche backup restore",
+			})
+		}
+	}
+	return findings
+}
+
+// checkKeys looks for stored keys that can't be loaded
+func checkKeys(app *application.Avalanche) []Finding {
+	entries, err := os.ReadDir(app.GetKeyDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return []Finding{{
+			Severity: Warning,
+			Area:     KeysArea,
+			Problem:  fmt.Sprintf("could not list the keys at %s: %s", app.GetKeyDir(), err),
+		}}
+	}
+	findings := []Finding{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != constants.KeySuffix {
+			continue
+		}
+		keyName := strings.TrimSuffix(entry.Name(), constants.KeySuffix)
+		if _, err := key.LoadSoft(app.GetLocalNetwork().ID, filepath.Join(app.GetKeyDir(), entry.Name())); err != nil {
+			findings = append(findings, Finding{
+				Severity:    Warning,
+				Area:        KeysArea,
+				Problem:     fmt.Sprintf("key %s can't be loaded: %s", keyName, err),
+				Remediation: fmt.Sprintf("avalanche key delete %s, then create it again with avalanche key create %s --file <private key file>", keyName, keyName),
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package doctor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvalancheGoVersionFindings(t *testing.T) {
+	require := require.New(t)
+	require.Empty(avalancheGoVersionFindings("avalanchego/1.12.0", "v1.12.0"))
+	require.Empty(avalancheGoVersionFindings("avalanchego/1.12.1", "v1.12.0"))
+	require.Empty(avalancheGoVersionFindings("unknown", "v1.12.0"))
+	findings := avalancheGoVersionFindings("avalanchego/1.12.0", "v1.12.1")
+	require.Len(findings, 1)
+	require.Equal(Info, findings[0].Severity)
+	findings = avalancheGoVersionFindings("avalanchego/1.11.13", "v1.12.1")
+	require.Len(findings, 1)
+	require.Equal(Warning, findings[0].Severity)
+	require.Contains(findings[0].Problem, "v1.11.13")
+}
+
+func TestTrackedChains(t *testing.T) {
+	require := require.New(t)
+	chainID := ids.GenerateTestID().String()
+	checkErr := "not bootstrapped"
+	checks := map[string]health.Result{
+		"C":         {},
+		"P":         {},
+		"X":         {},
+		"network":   {},
+		"bootstrap": {Error: &checkErr},
+		chainID:     {Error: &checkErr},
+	}
+	require.Equal([]string{"P", "X", "C", chainID}, trackedChains(checks))
+	require.Equal(map[string]string{"bootstrap": checkErr, chainID: checkErr}, failingChecks(checks))
+}
+
+func TestCertificateExpiryFindings(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	require.Empty(certificateExpiryFindings("api.example.com", now.Add(90*24*time.Hour), now))
+	findings := certificateExpiryFindings("api.example.com", now.Add(7*24*time.Hour), now)
+	require.Len(findings, 1)
+	require.Equal(Warning, findings[0].Severity)
+}
+
+func TestProbeTransport(t *testing.T) {
+	require := require.New(t)
+	handler := func(status int) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+		}
+	}
+
+	server := httptest.NewServer(handler(http.StatusOK))
+	defer server.Close()
+	endpointURL, err := url.Parse(server.URL)
+	require.NoError(err)
+	report := ProbeReport{}
+	findings, ok := probeTransport(&report, endpointURL)
+	require.True(ok)
+	require.Empty(findings)
+	require.Empty(report.TLS)
+
+	unauthorizedServer := httptest.NewServer(handler(http.StatusUnauthorized))
+	defer unauthorizedServer.Close()
+	endpointURL, err = url.Parse(unauthorizedServer.URL)
+	require.NoError(err)
+	findings, ok = probeTransport(&ProbeReport{}, endpointURL)
+	require.False(ok)
+	require.Len(findings, 1)
+	require.Equal(Critical, findings[0].Severity)
+	require.Contains(findings[0].Problem, "HTTP 401")
+
+	// the test server certificate is not signed by a trusted CA
+	tlsServer := httptest.NewTLSServer(handler(http.StatusOK))
+	defer tlsServer.Close()
+	endpointURL, err = url.Parse(tlsServer.URL)
+	require.NoError(err)
+	findings, ok = probeTransport(&ProbeReport{}, endpointURL)
+	require.False(ok)
+	require.Len(findings, 1)
+	require.Contains(findings[0].Problem, "TLS certificate")
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"go.uber.org/zap"
+)
+
+type Type string
+
+const (
+	DeployStarted   Type = "deploy.started"
+	DeploySucceeded Type = "deploy.succeeded"
+	DeployFailed    Type = "deploy.failed"
+	ValidatorAdded  Type = "validator.added"
+	// validator removed from an L1 by blockchain removeValidator
+	ValidatorRemoved Type = "validator.removed"
+	UpgradeApplied   Type = "upgrade.applied"
+	NodeUnhealthy    Type = "node.unhealthy"
+	// validator set or delegation change seen by validator events --watch
+	ValidatorSetChanged Type = "validator.changed"
+	Test                Type = "test"
+)
+
+// Event is the JSON document delivered to the configured hooks. Text is a
+// human readable summary, so the webhook can directly be a Slack incoming webhook
+type Event struct {
+	Type      Type                   `json:"type"`
+	Timestamp time.Time              `json:"timestamp"`
+	Text      string                 `json:"text"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Configured returns true if a webhook URL, a script hook or a message queue sink is configured
+func Configured(app *application.Avalanche) bool {
+	return app.Conf.GetConfigStringValue(constants.ConfigWebhookURLKey) != "" ||
+		app.Conf.GetConfigStringValue(constants.ConfigHookScriptKey) != "" ||
+		app.Conf.GetConfigStringValue(constants.ConfigEventSinkURLKey) != ""
+}
+
+// Emit delivers an event of type [eventType] to the configured hooks.
+// Delivery failures are logged but never fail the command emitting the event.
+// Nothing is emitted in read-only mode
+func Emit(app *application.Avalanche, eventType Type, text string, data map[string]interface{}) {
+	if app.ReadOnly || !Configured(app) {
+		return
+	}
+	if err := Deliver(app, Event{
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Text:      text,
+		Data:      data,
+	}); err != nil {
+		app.Log.Warn("failed to deliver event", zap.String("type", string(eventType)), zap.Error(err))
+	}
+}
+
+// Deliver sends [event] to the configured webhook URL, pipes it to the
+// configured script hook, and publishes it to the configured message queue sink.
+// Each destination is delivered to even if a previous one failed, and the
+// failures of all of them are returned
+func Deliver(app *application.Avalanche, event Event) error {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.HookTimeout)
+	defer cancel()
+	errs := []error{}
+	if url := app.Conf.GetConfigStringValue(constants.ConfigWebhookURLKey); url != "" {
+		if err := postEvent(ctx, url, eventBytes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if script := app.Conf.GetConfigStringValue(constants.ConfigHookScriptKey); script != "" {
+		if err := runScript(ctx, script, event.Type, eventBytes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if sinkURL := app.Conf.GetConfigStringValue(constants.ConfigEventSinkURLKey); sinkURL != "" {
+		if err := publishEvent(ctx, sinkURL, event.Type, eventBytes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func postEvent(ctx context.Context, url string, eventBytes []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(eventBytes))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failure posting event to webhook: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered with status %s", response.Status)
+	}
+	return nil
+}
+
+// runScript executes [script] with the event JSON on its standard input, and
+// the event type on the AVALANCHE_CLI_EVENT environment variable
+func runScript(ctx context.Context, script string, eventType Type, eventBytes []byte) error {
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Stdin = bytes.NewReader(eventBytes)
+	cmd.Env = append(os.Environ(), "AVALANCHE_CLI_EVENT="+string(eventType))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("hook script %s failed: %w: %s", script, err, output)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestApp(t *testing.T, webhookURL string, hookScript string) *application.Avalanche {
+	viper.Set(constants.ConfigWebhookURLKey, webhookURL)
+	viper.Set(constants.ConfigHookScriptKey, hookScript)
+	t.Cleanup(func() {
+		viper.Set(constants.ConfigWebhookURLKey, "")
+		viper.Set(constants.ConfigHookScriptKey, "")
+	})
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil, nil)
+	return app
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	require := require.New(t)
+
+	received := make(chan Event, 1)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(http.MethodPost, r.Method)
+		require.Equal("application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(err)
+		var event Event
+		require.NoError(json.Unmarshal(body, &event))
+		received <- event
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	app := newTestApp(t, server.URL, "")
+	require.True(Configured(app))
+	event := Event{
+		Type:      DeploySucceeded,
+		Timestamp: time.Now().UTC(),
+		Text:      "deployed",
+		Data:      map[string]interface{}{"blockchain": "chain1"},
+	}
+	require.NoError(Deliver(app, event))
+	got := <-received
+	require.Equal(DeploySucceeded, got.Type)
+	require.Equal("deployed", got.Text)
+	require.Equal("chain1", got.Data["blockchain"])
+
+	status = http.StatusInternalServerError
+	require.ErrorContains(Deliver(app, event), "500")
+	<-received
+}
+
+func TestDeliverScript(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "output")
+	scriptPath := filepath.Join(dir, "hook.sh")
+	script := "#!/bin/sh\necho $AVALANCHE_CLI_EVENT > " + outputPath + "\ncat >> " + outputPath + "\n"
+	require.NoError(os.WriteFile(scriptPath, []byte(script), constants.DefaultPerms755))
+
+	app := newTestApp(t, "", scriptPath)
+	require.NoError(Deliver(app, Event{Type: NodeUnhealthy, Text: "unhealthy"}))
+	output, err := os.ReadFile(outputPath)
+	require.NoError(err)
+	require.Contains(string(output), string(NodeUnhealthy)+"\n")
+	require.Contains(string(output), `"text":"unhealthy"`)
+}
+
+func TestEmitNotConfigured(t *testing.T) {
+	require := require.New(t)
+
+	app := newTestApp(t, "", "")
+	require.False(Configured(app))
+	// nothing to deliver to, must not panic nor block
+	Emit(app, Test, "test", nil)
+}
+
+func TestDeliverIndependently(t *testing.T) {
+	require := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "output")
+	scriptPath := filepath.Join(dir, "hook.sh")
+	script := "#!/bin/sh\ncat > " + outputPath + "\n"
+	require.NoError(os.WriteFile(scriptPath, []byte(script), constants.DefaultPerms755))
+
+	// a failing webhook must not prevent the script hook from running
+	app := newTestApp(t, server.URL, scriptPath)
+	require.ErrorContains(Deliver(app, Event{Type: DeployFailed, Text: "failed"}), "500")
+	output, err := os.ReadFile(outputPath)
+	require.NoError(err)
+	require.Contains(string(output), `"text":"failed"`)
+}
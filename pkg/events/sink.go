@@ -0,0 +1,272 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/nats-io/nats.go"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// message queue sink schemes. NATS and AMQP are reached with their native clients, and
+// Kafka through a Kafka REST Proxy
+const (
+	natsScheme       = "nats"
+	kafkaHTTPScheme  = "kafka+http"
+	kafkaHTTPSScheme = "kafka+https"
+	amqpScheme       = "amqp"
+	amqpsScheme      = "amqps"
+
+	natsDefaultPort     = "4222"
+	amqpDefaultVhost    = "/"
+	amqpVhostQueryKey   = "vhost"
+	amqpRoutingQueryKey = "routing-key"
+	kafkaJSONMediaType  = "application/vnd.kafka.json.v2+json"
+)
+
+// SupportedSinkURLs documents the accepted formats of the message queue sink URL
+var SupportedSinkURLs = []string{
+	"nats://host[:port]/subject",
+	"kafka+http[s]://rest-proxy-host[:port]/topic",
+	"amqp[s]://rabbitmq-host[:port]/exchange[?vhost=/&routing-key=key]",
+}
+
+// sink is a parsed message queue sink URL, with the credentials taken from the environment
+type sink struct {
+	scheme string
+	// nats or amqp server URL, or base URL of the kafka rest proxy
+	address  string
+	user     string
+	password string
+	// nats subject prefix, kafka topic, or amqp exchange
+	destination string
+	vhost       string
+	routingKey  string
+}
+
+// ValidateSinkURL checks that [sinkURL] is a supported message queue sink URL
+func ValidateSinkURL(sinkURL string) error {
+	_, err := parseSinkURL(sinkURL)
+	return err
+}
+
+// parseSinkURL parses [sinkURL]. Credentials are not accepted on the URL, as it is kept on
+// the config file: they are taken from the event sink user and password env vars instead
+func parseSinkURL(sinkURL string) (sink, error) {
+	u, err := url.Parse(sinkURL)
+	if err != nil {
+		return sink{}, fmt.Errorf("invalid event sink URL %q: %w", sinkURL, err)
+	}
+	if u.User != nil {
+		return sink{}, fmt.Errorf(
+			"event sink URL %s must not include credentials. Set them on the %s and %s env vars",
+			u.Redacted(),
+			constants.EventSinkUserEnvVarName,
+			constants.EventSinkPasswordEnvVarName,
+		)
+	}
+	destination := strings.Trim(u.Path, "/")
+	if u.Host == "" || destination == "" || strings.Contains(destination, "/") {
+		return sink{}, fmt.Errorf("invalid event sink URL %q, expected one of %v", sinkURL, SupportedSinkURLs)
+	}
+	s := sink{
+		scheme:      u.Scheme,
+		user:        os.Getenv(constants.EventSinkUserEnvVarName),
+		password:    os.Getenv(constants.EventSinkPasswordEnvVarName),
+		destination: destination,
+	}
+	switch u.Scheme {
+	case natsScheme:
+		host := u.Host
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), natsDefaultPort)
+		}
+		s.address = natsScheme + "://" + host
+	case kafkaHTTPScheme, kafkaHTTPSScheme:
+		s.address = strings.TrimPrefix(u.Scheme, "kafka+") + "://" + u.Host
+	case amqpScheme, amqpsScheme:
+		s.address = u.Scheme + "://" + u.Host + "/"
+		s.vhost = u.Query().Get(amqpVhostQueryKey)
+		if s.vhost == "" {
+			s.vhost = amqpDefaultVhost
+		}
+		s.routingKey = u.Query().Get(amqpRoutingQueryKey)
+	default:
+		return sink{}, fmt.Errorf("unsupported event sink scheme %q, expected one of %v", u.Scheme, SupportedSinkURLs)
+	}
+	return s, nil
+}
+
+// publishEvent publishes [eventBytes] to the message queue sink at [sinkURL]. The event
+// type is used as NATS subject suffix, Kafka record key and default AMQP routing key, so
+// consumers can filter events without parsing them
+func publishEvent(ctx context.Context, sinkURL string, eventType Type, eventBytes []byte) error {
+	s, err := parseSinkURL(sinkURL)
+	if err != nil {
+		return err
+	}
+	switch s.scheme {
+	case natsScheme:
+		err = s.publishNATS(ctx, eventType, eventBytes)
+	case kafkaHTTPScheme, kafkaHTTPSScheme:
+		err = s.publishKafka(ctx, eventType, eventBytes)
+	default:
+		err = s.publishAMQP(ctx, eventType, eventBytes)
+	}
+	if err != nil {
+		return fmt.Errorf("failure publishing event to %s sink: %w", s.scheme, err)
+	}
+	return nil
+}
+
+// publishNATS publishes the event on subject <destination>.<event type>, and waits for
+// the server to process it. A user without password is used as auth token
+func (s sink) publishNATS(ctx context.Context, eventType Type, eventBytes []byte) error {
+	options := []nats.Option{
+		nats.Name("avalanche-cli"),
+		nats.NoReconnect(),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		options = append(options, nats.Timeout(time.Until(deadline)))
+	}
+	switch {
+	case s.user != "" && s.password != "":
+		options = append(options, nats.UserInfo(s.user, s.password))
+	case s.user != "":
+		options = append(options, nats.Token(s.user))
+	}
+	conn, err := nats.Connect(s.address, options...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.Publish(s.destination+"."+string(eventType), eventBytes); err != nil {
+		return err
+	}
+	return conn.FlushWithContext(ctx)
+}
+
+// publishKafka produces the event as a JSON record of the topic, through the Kafka REST Proxy v2 API
+func (s sink) publishKafka(ctx context.Context, eventType Type, eventBytes []byte) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"records": []map[string]interface{}{
+			{
+				"key":   string(eventType),
+				"value": json.RawMessage(eventBytes),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	responseBytes, err := s.post(ctx, s.address+"/topics/"+url.PathEscape(s.destination), kafkaJSONMediaType, body)
+	if err != nil {
+		return err
+	}
+	var response struct {
+		Offsets []struct {
+			Error string `json:"error"`
+		} `json:"offsets"`
+	}
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return fmt.Errorf("unexpected kafka rest proxy response %q: %w", responseBytes, err)
+	}
+	for _, offset := range response.Offsets {
+		if offset.Error != "" {
+			return fmt.Errorf("kafka rest proxy error: %s", offset.Error)
+		}
+	}
+	return nil
+}
+
+// publishAMQP publishes the event to the exchange as a persistent message, waiting for
+// the broker to confirm it. An event not routed to any queue is considered as not delivered
+func (s sink) publishAMQP(ctx context.Context, eventType Type, eventBytes []byte) error {
+	routingKey := s.routingKey
+	if routingKey == "" {
+		routingKey = string(eventType)
+	}
+	config := amqp.Config{
+		Vhost:      s.vhost,
+		Properties: amqp.Table{"connection_name": "avalanche-cli"},
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		config.Dial = amqp.DefaultDial(time.Until(deadline))
+	}
+	if s.user != "" {
+		config.SASL = []amqp.Authentication{&amqp.PlainAuth{Username: s.user, Password: s.password}}
+	}
+	conn, err := amqp.DialConfig(s.address, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+	if err := ch.Confirm(false); err != nil {
+		return err
+	}
+	// unroutable mandatory messages are returned before being confirmed
+	returns := ch.NotifyReturn(make(chan amqp.Return, 1))
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, s.destination, routingKey, true, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Type:         string(eventType),
+		Timestamp:    time.Now(),
+		Body:         eventBytes,
+	})
+	if err != nil {
+		return err
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return fmt.Errorf("event was rejected by exchange %s", s.destination)
+	}
+	select {
+	case <-returns:
+		return fmt.Errorf("event was not routed to any queue of exchange %s with routing key %s", s.destination, routingKey)
+	default:
+	}
+	return nil
+}
+
+func (s sink) post(ctx context.Context, endpoint string, contentType string, body []byte) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", contentType)
+	if s.user != "" {
+		request.SetBasicAuth(s.user, s.password)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	var responseBytes bytes.Buffer
+	if _, err := responseBytes.ReadFrom(response.Body); err != nil {
+		return nil, err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("answered with status %s: %s", response.Status, strings.TrimSpace(responseBytes.String()))
+	}
+	return responseBytes.Bytes(), nil
+}
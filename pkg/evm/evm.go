@@ -5,38 +5,62 @@ package evm
 import (
 	"context"
 	"crypto/ecdsa"
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ava-labs/avalanche-cli/pkg/rpcauth"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	evmSDK "github.com/ava-labs/avalanche-cli/sdk/evm"
 	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/commontype"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/ethclient"
 	"github.com/ava-labs/subnet-evm/interfaces"
-	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
 	"github.com/ava-labs/subnet-evm/predicate"
 	"github.com/ava-labs/subnet-evm/rpc"
 	subnetEvmUtils "github.com/ava-labs/subnet-evm/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
-	BaseFeeFactor               = 2
-	MaxPriorityFeePerGas        = 2500000000 // 2.5 gwei
+	BaseFeeFactor               = evmSDK.BaseFeeFactor
+	MaxPriorityFeePerGas        = evmSDK.MaxPriorityFeePerGas
 	NativeTransferGas    uint64 = 21_000
-	repeatsOnFailure            = 3
-	sleepBetweenRepeats         = 1 * time.Second
+	// number of headers requested on each JSON-RPC batch by GetLastHeaders
+	headerBatchSize = 50
+	// max number of header batches requested at the same time by GetLastHeaders
+	maxConcurrentHeaderBatches = 4
 )
 
-var ErrUnknownErrorSelector = fmt.Errorf("unknown error selector")
+var (
+	ErrUnknownErrorSelector = evmSDK.ErrUnknownErrorSelector
+	// rpc urls of the clients created by this package, used to identify
+	// their endpoint on API calls
+	clientEndpoints sync.Map
+)
+
+// the sdk operations called by the CLI also send the registered RPC auth tokens
+func init() {
+	evmSDK.RPCHeaders = rpcauth.Tokens.Headers
+}
+
+// clientEndpoint returns the rpc url [client] was created for
+func clientEndpoint(client any) string {
+	if endpoint, ok := clientEndpoints.Load(client); ok {
+		return endpoint.(string)
+	}
+	return fmt.Sprintf("%p", client)
+}
 
 func ContractAlreadyDeployed(
 	client ethclient.Client,
@@ -54,25 +78,17 @@ func GetContractBytecode(
 	contractAddressStr string,
 ) ([]byte, error) {
 	contractAddress := common.HexToAddress(contractAddressStr)
-	var (
-		code []byte
-		err  error
-	)
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		code, err = client.CodeAt(ctx, contractAddress, nil)
-		if err == nil {
-			break
-		}
+	code, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) ([]byte, error) {
+		return client.CodeAt(ctx, contractAddress, nil)
+	})
+	if err != nil {
 		err = fmt.Errorf(
-			"failure obtaining code for %s on %#v: %w",
+			"failure obtaining code for %s on %s: %w",
 			contractAddressStr,
-			client,
+			clientEndpoint(client),
 			err,
 		)
 		ux.Logger.RedXToUser("%s", err)
-		time.Sleep(sleepBetweenRepeats)
 	}
 	return code, err
 }
@@ -93,20 +109,12 @@ func GetAddressBalance(
 	addressStr string,
 ) (*big.Int, error) {
 	address := common.HexToAddress(addressStr)
-	var (
-		balance *big.Int
-		err     error
-	)
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		balance, err = client.BalanceAt(ctx, address, nil)
-		if err == nil {
-			break
-		}
-		err = fmt.Errorf("failure obtaining balance for %s on %#v: %w", addressStr, client, err)
+	balance, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (*big.Int, error) {
+		return client.BalanceAt(ctx, address, nil)
+	})
+	if err != nil {
+		err = fmt.Errorf("failure obtaining balance for %s on %s: %w", addressStr, clientEndpoint(client), err)
 		ux.Logger.RedXToUser("%s", err)
-		time.Sleep(sleepBetweenRepeats)
 	}
 	return balance, err
 }
@@ -138,20 +146,12 @@ func NonceAt(
 	addressStr string,
 ) (uint64, error) {
 	address := common.HexToAddress(addressStr)
-	var (
-		nonce uint64
-		err   error
-	)
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		nonce, err = client.NonceAt(ctx, address, nil)
-		if err == nil {
-			break
-		}
-		err = fmt.Errorf("failure obtaining nonce for %s on %#v: %w", addressStr, client, err)
+	nonce, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (uint64, error) {
+		return client.NonceAt(ctx, address, nil)
+	})
+	if err != nil {
+		err = fmt.Errorf("failure obtaining nonce for %s on %s: %w", addressStr, clientEndpoint(client), err)
 		ux.Logger.RedXToUser("%s", err)
-		time.Sleep(sleepBetweenRepeats)
 	}
 	return nonce, err
 }
@@ -159,20 +159,10 @@ func NonceAt(
 func SuggestGasTipCap(
 	client ethclient.Client,
 ) (*big.Int, error) {
-	var (
-		gasTipCap *big.Int
-		err       error
-	)
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		gasTipCap, err = client.SuggestGasTipCap(ctx)
-		if err == nil {
-			break
-		}
-		err = fmt.Errorf("failure obtaining gas tip cap on %#v: %w", client, err)
+	gasTipCap, err := utils.CallAPI(clientEndpoint(client), client.SuggestGasTipCap)
+	if err != nil {
+		err = fmt.Errorf("failure obtaining gas tip cap on %s: %w", clientEndpoint(client), err)
 		ux.Logger.RedXToUser("%s", err)
-		time.Sleep(sleepBetweenRepeats)
 	}
 	return gasTipCap, err
 }
@@ -180,20 +170,10 @@ func SuggestGasTipCap(
 func EstimateBaseFee(
 	client ethclient.Client,
 ) (*big.Int, error) {
-	var (
-		baseFee *big.Int
-		err     error
-	)
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		baseFee, err = client.EstimateBaseFee(ctx)
-		if err == nil {
-			break
-		}
-		err = fmt.Errorf("failure estimating base fee on %#v: %w", client, err)
+	baseFee, err := utils.CallAPI(clientEndpoint(client), client.EstimateBaseFee)
+	if err != nil {
+		err = fmt.Errorf("failure estimating base fee on %s: %w", clientEndpoint(client), err)
 		ux.Logger.RedXToUser("%s", err)
-		time.Sleep(sleepBetweenRepeats)
 	}
 	return baseFee, err
 }
@@ -202,19 +182,11 @@ func EstimateGasLimit(
 	client ethclient.Client,
 	msg interfaces.CallMsg,
 ) (uint64, error) {
-	var (
-		gasLimit uint64
-		err      error
-	)
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		gasLimit, err = client.EstimateGas(ctx, msg)
-		if err == nil {
-			break
-		}
-		err = fmt.Errorf("failure estimating gas limit on %#v: %w", client, err)
-		time.Sleep(sleepBetweenRepeats)
+	gasLimit, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (uint64, error) {
+		return client.EstimateGas(ctx, msg)
+	})
+	if err != nil {
+		err = fmt.Errorf("failure estimating gas limit on %s: %w", clientEndpoint(client), err)
 	}
 	return gasLimit, err
 }
@@ -324,6 +296,47 @@ func GetSignedTxToMethodWithWarpMessage(
 	return types.SignTx(tx, txSigner, privateKey)
 }
 
+// GetUnsignedTx returns a dynamic fee tx from [from] to [to] with [callData] and [value],
+// to be signed outside of the CLI. Fails if the gas limit can not be estimated, as the tx
+// would revert
+func GetUnsignedTx(
+	client ethclient.Client,
+	from common.Address,
+	to common.Address,
+	callData []byte,
+	value *big.Int,
+) (*types.Transaction, error) {
+	gasFeeCap, gasTipCap, nonce, err := CalculateTxParams(client, from.Hex())
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := GetChainID(client)
+	if err != nil {
+		return nil, err
+	}
+	gasLimit, err := EstimateGasLimit(client, interfaces.CallMsg{
+		From:      from,
+		To:        &to,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Value:     value,
+		Data:      callData,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		To:        &to,
+		Gas:       gasLimit,
+		GasFeeCap: gasFeeCap,
+		GasTipCap: gasTipCap,
+		Value:     value,
+		Data:      callData,
+	}), nil
+}
+
 func IssueTx(
 	client ethclient.Client,
 	txStr string,
@@ -347,17 +360,12 @@ func SendTransaction(
 	client ethclient.Client,
 	tx *types.Transaction,
 ) error {
-	var err error
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		err = client.SendTransaction(ctx, tx)
-		if err == nil {
-			break
-		}
-		err = fmt.Errorf("failure sending transaction %#v to %#v: %w", tx, client, err)
+	_, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (any, error) {
+		return nil, client.SendTransaction(ctx, tx)
+	})
+	if err != nil {
+		err = fmt.Errorf("failure sending transaction %#v to %s: %w", tx, clientEndpoint(client), err)
 		ux.Logger.RedXToUser("%s", err)
-		time.Sleep(sleepBetweenRepeats)
 	}
 	return err
 }
@@ -373,7 +381,7 @@ func FindOutScheme(rpcURL string) (ethclient.Client, string, error) {
 	scheme := "ws://"
 	ctx, cancel := utils.GetAPILargeContext()
 	defer cancel()
-	client, err := ethclient.DialContext(ctx, scheme+rpcURL)
+	client, err := DialContext(ctx, scheme+rpcURL)
 	if err == nil {
 		return client, scheme, nil
 	} else if !strings.Contains(err.Error(), "websocket: bad handshake") {
@@ -381,7 +389,7 @@ func FindOutScheme(rpcURL string) (ethclient.Client, string, error) {
 	}
 	// wss give specific errors for http/http
 	scheme = "wss://"
-	client, err = ethclient.DialContext(ctx, scheme+rpcURL)
+	client, err = DialContext(ctx, scheme+rpcURL)
 	if err == nil {
 		return client, scheme, nil
 	} else if !strings.Contains(err.Error(), "websocket: bad handshake") && // may be https
@@ -390,7 +398,7 @@ func FindOutScheme(rpcURL string) (ethclient.Client, string, error) {
 	}
 	// https/http discrimination based on sending a specific query
 	scheme = "https://"
-	client, err = ethclient.DialContext(ctx, scheme+rpcURL)
+	client, err = DialContext(ctx, scheme+rpcURL)
 	if err == nil {
 		_, err = client.ChainID(ctx)
 		switch {
@@ -398,7 +406,7 @@ func FindOutScheme(rpcURL string) (ethclient.Client, string, error) {
 			return client, scheme, nil
 		case strings.Contains(err.Error(), "server gave HTTP response to HTTPS client"):
 			scheme = "http://"
-			client, err = ethclient.DialContext(ctx, scheme+rpcURL)
+			client, err = DialContext(ctx, scheme+rpcURL)
 			if err == nil {
 				return client, scheme, nil
 			}
@@ -419,31 +427,41 @@ func HasScheme(rpcURL string) (bool, error) {
 	return true, nil
 }
 
+// DialContext connects to the EVM RPC at [rawURL], either HTTP or WebSocket, sending
+// the RPC auth token registered for it (if any)
+func DialContext(ctx context.Context, rawURL string) (ethclient.Client, error) {
+	client, err := DialRPCContext(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// DialRPCContext connects a raw RPC client to [rawURL], either HTTP or WebSocket, sending
+// the RPC auth token registered for it (if any)
+func DialRPCContext(ctx context.Context, rawURL string) (*rpc.Client, error) {
+	return evmSDK.DialRPCContext(ctx, rawURL)
+}
+
 func GetClient(rpcURL string) (ethclient.Client, error) {
-	var (
-		client ethclient.Client
-		err    error
-	)
 	hasScheme, err := HasScheme(rpcURL)
 	if err != nil {
 		return nil, err
 	}
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
+	client, err := utils.CallAPI(rpcURL, func(ctx context.Context) (ethclient.Client, error) {
 		if hasScheme {
-			client, err = ethclient.DialContext(ctx, rpcURL)
-		} else {
-			client, _, err = FindOutScheme(rpcURL)
-		}
-		if err == nil {
-			break
+			return DialContext(ctx, rpcURL)
 		}
+		client, _, err := FindOutScheme(rpcURL)
+		return client, err
+	})
+	if err != nil {
 		err = fmt.Errorf("failure connecting to %s: %w", rpcURL, err)
 		ux.Logger.RedXToUser("%s", err)
-		time.Sleep(sleepBetweenRepeats)
+		return nil, err
 	}
-	return client, err
+	clientEndpoints.Store(client, rpcURL)
+	return client, nil
 }
 
 func WaitForChainID(client ethclient.Client) {
@@ -471,20 +489,10 @@ func WaitForChainID(client ethclient.Client) {
 }
 
 func GetChainID(client ethclient.Client) (*big.Int, error) {
-	var (
-		chainID *big.Int
-		err     error
-	)
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		chainID, err = client.ChainID(ctx)
-		if err == nil {
-			break
-		}
-		err = fmt.Errorf("failure getting chain id from client %#v: %w", client, err)
+	chainID, err := utils.CallAPI(clientEndpoint(client), client.ChainID)
+	if err != nil {
+		err = fmt.Errorf("failure getting chain id from %s: %w", clientEndpoint(client), err)
 		ux.Logger.RedXToUser("%s", err)
-		time.Sleep(sleepBetweenRepeats)
 	}
 	return chainID, err
 }
@@ -508,24 +516,15 @@ func WaitForTransaction(
 	client ethclient.Client,
 	tx *types.Transaction,
 ) (*types.Receipt, bool, error) {
-	var (
-		err     error
-		receipt *types.Receipt
-		success bool
-	)
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		receipt, err = bind.WaitMined(ctx, client, tx)
-		if err == nil {
-			success = receipt.Status == types.ReceiptStatusSuccessful
-			break
-		}
-		err = fmt.Errorf("failure waiting for tx %#v on client %#v: %w", tx, client, err)
+	receipt, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (*types.Receipt, error) {
+		return bind.WaitMined(ctx, client, tx)
+	})
+	if err != nil {
+		err = fmt.Errorf("failure waiting for tx %#v on %s: %w", tx, clientEndpoint(client), err)
 		ux.Logger.RedXToUser("%s", err)
-		time.Sleep(sleepBetweenRepeats)
+		return nil, false, err
 	}
-	return receipt, success, err
+	return receipt, receipt.Status == types.ReceiptStatusSuccessful, nil
 }
 
 // Returns the first log in 'logs' that is successfully parsed by 'parser'
@@ -545,60 +544,46 @@ func GetEventFromLogs[T any](logs []*types.Log, parser func(log types.Log) (T, e
 }
 
 func GetRPCClient(rpcURL string) (*rpc.Client, error) {
-	var (
-		client *rpc.Client
-		err    error
-	)
 	hasScheme, err := HasScheme(rpcURL)
 	if err != nil {
 		return nil, err
 	}
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		if !hasScheme {
-			_, scheme, findErr := FindOutScheme(rpcURL)
-			if findErr == nil {
-				client, err = rpc.DialContext(ctx, scheme+rpcURL)
-			} else {
-				err = findErr
-			}
-		} else {
-			client, err = rpc.DialContext(ctx, rpcURL)
+	client, err := utils.CallAPI(rpcURL, func(ctx context.Context) (*rpc.Client, error) {
+		if hasScheme {
+			return DialRPCContext(ctx, rpcURL)
 		}
-		if err == nil {
-			break
+		_, scheme, err := FindOutScheme(rpcURL)
+		if err != nil {
+			return nil, err
 		}
+		return DialRPCContext(ctx, scheme+rpcURL)
+	})
+	if err != nil {
 		err = fmt.Errorf("failure connecting to rpc client on %s: %w", rpcURL, err)
 		ux.Logger.RedXToUser("%s", err)
-		time.Sleep(sleepBetweenRepeats)
+		return nil, err
 	}
-	return client, err
+	clientEndpoints.Store(client, rpcURL)
+	return client, nil
 }
 
 func DebugTraceTransaction(
 	client *rpc.Client,
 	txID string,
 ) (map[string]interface{}, error) {
-	var (
-		err   error
-		trace map[string]interface{}
-	)
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		err = client.CallContext(
+	trace, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (map[string]interface{}, error) {
+		var trace map[string]interface{}
+		err := client.CallContext(
 			ctx,
 			&trace,
 			"debug_traceTransaction",
 			txID,
 			map[string]string{"tracer": "callTracer"},
 		)
-		if err == nil {
-			break
-		}
-		err = fmt.Errorf("failure tracing tx %s for client %#v: %w", txID, client, err)
-		time.Sleep(sleepBetweenRepeats)
+		return trace, err
+	})
+	if err != nil {
+		err = fmt.Errorf("failure tracing tx %s on %s: %w", txID, clientEndpoint(client), err)
 	}
 	return trace, err
 }
@@ -607,14 +592,9 @@ func DebugTraceCall(
 	client *rpc.Client,
 	toTrace map[string]string,
 ) (map[string]interface{}, error) {
-	var (
-		err   error
-		trace map[string]interface{}
-	)
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		err = client.CallContext(
+	trace, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (map[string]interface{}, error) {
+		var trace map[string]interface{}
+		err := client.CallContext(
 			ctx,
 			&trace,
 			"debug_traceCall",
@@ -627,15 +607,159 @@ func DebugTraceCall(
 				},
 			},
 		)
-		if err == nil {
-			break
+		return trace, err
+	})
+	if err != nil {
+		err = fmt.Errorf("failure tracing call on %s: %w", clientEndpoint(client), err)
+	}
+	return trace, err
+}
+
+// CallTraceLog is a log emitted during a traced call
+type CallTraceLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+	// index of the subcall the log was emitted before
+	Position hexutil.Uint `json:"position"`
+}
+
+// CallTrace is a call frame as given by the call tracer, including the logs
+// emitted by the call. Logs of failed frames are not included by the tracer
+type CallTrace struct {
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Output       hexutil.Bytes   `json:"output"`
+	Error        string          `json:"error"`
+	RevertReason string          `json:"revertReason"`
+	Calls        []CallTrace     `json:"calls"`
+	Logs         []CallTraceLog  `json:"logs"`
+}
+
+// AllLogs returns the logs emitted by the call and its subcalls, in emission order
+func (t CallTrace) AllLogs() []*types.Log {
+	logs := []*types.Log{}
+	logIndex := 0
+	for i, call := range t.Calls {
+		for ; logIndex < len(t.Logs) && int(t.Logs[logIndex].Position) <= i; logIndex++ {
+			logs = append(logs, t.Logs[logIndex].toLog())
 		}
-		err = fmt.Errorf("failure tracing call for client %#v: %w", client, err)
-		time.Sleep(sleepBetweenRepeats)
+		logs = append(logs, call.AllLogs()...)
+	}
+	for ; logIndex < len(t.Logs); logIndex++ {
+		logs = append(logs, t.Logs[logIndex].toLog())
+	}
+	return logs
+}
+
+func (l CallTraceLog) toLog() *types.Log {
+	return &types.Log{
+		Address: l.Address,
+		Topics:  l.Topics,
+		Data:    l.Data,
+	}
+}
+
+// SimulateCall executes [toTrace] on top of the latest state of the chain served
+// by [client], without issuing any tx, and returns its trace including the emitted
+// logs. As the call is executed by the chain's own VM, precompiles (eg warp) behave
+// as they would for a real tx. Needs the debug API to be enabled on the node
+func SimulateCall(
+	client *rpc.Client,
+	toTrace map[string]string,
+) (CallTrace, error) {
+	trace, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (CallTrace, error) {
+		var trace CallTrace
+		err := client.CallContext(
+			ctx,
+			&trace,
+			"debug_traceCall",
+			toTrace,
+			"latest",
+			map[string]interface{}{
+				"tracer": "callTracer",
+				"tracerConfig": map[string]interface{}{
+					"withLog": true,
+				},
+			},
+		)
+		return trace, err
+	})
+	if err != nil {
+		err = fmt.Errorf("failure simulating call on %s: %w", clientEndpoint(client), err)
 	}
 	return trace, err
 }
 
+// GetFeeConfig returns the fee config in use at the last block of the chain
+func GetFeeConfig(client ethclient.Client) (commontype.FeeConfig, error) {
+	type feeConfigResult struct {
+		FeeConfig commontype.FeeConfig `json:"feeConfig"`
+	}
+	result, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (feeConfigResult, error) {
+		var result feeConfigResult
+		err := client.Client().CallContext(ctx, &result, "eth_feeConfig")
+		return result, err
+	})
+	if err != nil {
+		err = fmt.Errorf("failure obtaining fee config on %s: %w", clientEndpoint(client), err)
+		ux.Logger.RedXToUser("%s", err)
+	}
+	return result.FeeConfig, err
+}
+
+// GetLastHeaders returns the headers of the last [numBlocks] blocks of the chain,
+// from older to newer. Headers are requested in concurrent JSON-RPC batches
+func GetLastHeaders(client ethclient.Client, numBlocks uint64) ([]*types.Header, error) {
+	lastBlock, err := utils.CallAPI(clientEndpoint(client), client.BlockNumber)
+	if err != nil {
+		err = fmt.Errorf("failure obtaining block number on %s: %w", clientEndpoint(client), err)
+		ux.Logger.RedXToUser("%s", err)
+		return nil, err
+	}
+	firstBlock := uint64(0)
+	if lastBlock+1 > numBlocks {
+		firstBlock = lastBlock + 1 - numBlocks
+	}
+	headers := make([]*types.Header, lastBlock+1-firstBlock)
+	eg := &errgroup.Group{}
+	eg.SetLimit(maxConcurrentHeaderBatches)
+	for start := 0; start < len(headers); start += headerBatchSize {
+		end := min(start+headerBatchSize, len(headers))
+		eg.Go(func() error {
+			batch := make([]rpc.BatchElem, end-start)
+			for i := range batch {
+				headers[start+i] = &types.Header{}
+				batch[i] = rpc.BatchElem{
+					Method: "eth_getBlockByNumber",
+					Args:   []any{hexutil.EncodeUint64(firstBlock + uint64(start+i)), false},
+					Result: headers[start+i],
+				}
+			}
+			if _, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (any, error) {
+				return nil, client.Client().BatchCallContext(ctx, batch)
+			}); err != nil {
+				return fmt.Errorf("failure obtaining headers of blocks %d-%d on %s: %w", firstBlock+uint64(start), firstBlock+uint64(end-1), clientEndpoint(client), err)
+			}
+			for i, elem := range batch {
+				if elem.Error != nil {
+					return fmt.Errorf("failure obtaining header of block %d on %s: %w", firstBlock+uint64(start+i), clientEndpoint(client), elem.Error)
+				}
+				if headers[start+i].Number == nil {
+					return fmt.Errorf("block %d not found on %s", firstBlock+uint64(start+i), clientEndpoint(client))
+				}
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		ux.Logger.RedXToUser("%s", err)
+		return nil, err
+	}
+	return headers, nil
+}
+
 func GetTrace(rpcURL string, txID string) (map[string]interface{}, error) {
 	client, err := GetRPCClient(rpcURL)
 	if err != nil {
@@ -668,74 +792,20 @@ func IssueTxsToActivateProposerVMFork(
 	chainID *big.Int,
 	privKey *ecdsa.PrivateKey,
 ) error {
-	var errorList []error
-	var err error
-	for i := 0; i < repeatsOnFailure; i++ {
-		ctx, cancel := utils.GetAPILargeContext()
-		defer cancel()
-		err = issueTxsToActivateProposerVMFork(client, ctx, chainID, privKey)
-		if err == nil {
-			break
-		}
+	_, err := utils.CallAPI(clientEndpoint(client), func(ctx context.Context) (any, error) {
+		return nil, evmSDK.IssueTxsToActivateProposerVMFork(ctx, client, chainID, privKey)
+	})
+	if err != nil {
 		err = fmt.Errorf(
-			"failure issuing txs to activate proposer VM fork for client %#v: %w",
-			client,
+			"failure issuing txs to activate proposer VM fork on %s: %w",
+			clientEndpoint(client),
 			err,
 		)
-		errorList = append(errorList, err)
-		time.Sleep(sleepBetweenRepeats)
-	}
-	// this means that on the last try there is error
-	// print out all previous errors
-	if err != nil {
-		for _, indivError := range errorList {
-			ux.Logger.RedXToUser("%s", indivError)
-		}
+		ux.Logger.RedXToUser("%s", err)
 	}
 	return err
 }
 
-// issueTxsToActivateProposerVMFork issues transactions at the current
-// timestamp, which should be after the ProposerVM activation time (aka
-// ApricotPhase4). This should generate a PostForkBlock because its parent block
-// (genesis) has a timestamp (0) that is greater than or equal to the fork
-// activation time of 0. Therefore, subsequent blocks should be built with
-// BuildBlockWithContext.
-func issueTxsToActivateProposerVMFork(
-	client ethclient.Client,
-	ctx context.Context,
-	chainID *big.Int,
-	fundedKey *ecdsa.PrivateKey,
-) error {
-	const numTriggerTxs = 2 // Number of txs needed to activate the proposer VM fork
-	addr := crypto.PubkeyToAddress(fundedKey.PublicKey)
-	gasPrice := big.NewInt(params.MinGasPrice)
-	txSigner := types.LatestSignerForChainID(chainID)
-	for i := 0; i < numTriggerTxs; i++ {
-		prevBlockNumber, err := client.BlockNumber(ctx)
-		if err != nil {
-			return err
-		}
-		nonce, err := client.NonceAt(ctx, addr, nil)
-		if err != nil {
-			return err
-		}
-		tx := types.NewTransaction(
-			nonce, addr, common.Big1, params.TxGas, gasPrice, nil)
-		triggerTx, err := types.SignTx(tx, txSigner, fundedKey)
-		if err != nil {
-			return err
-		}
-		if err := client.SendTransaction(ctx, triggerTx); err != nil {
-			return err
-		}
-		if err := WaitForNewBlock(client, ctx, prevBlockNumber, 0, 0); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 func WaitForNewBlock(
 	client ethclient.Client,
 	ctx context.Context,
@@ -743,24 +813,7 @@ func WaitForNewBlock(
 	totalDuration time.Duration,
 	stepDuration time.Duration,
 ) error {
-	if stepDuration == 0 {
-		stepDuration = 1 * time.Second
-	}
-	if totalDuration == 0 {
-		totalDuration = 10 * time.Second
-	}
-	steps := totalDuration / stepDuration
-	for seconds := 0; seconds < int(steps); seconds++ {
-		blockNumber, err := client.BlockNumber(ctx)
-		if err != nil {
-			return err
-		}
-		if blockNumber > prevBlockNumber {
-			return nil
-		}
-		time.Sleep(stepDuration)
-	}
-	return fmt.Errorf("new block not produced in %f seconds", totalDuration.Seconds())
+	return evmSDK.WaitForNewBlock(ctx, client, prevBlockNumber, totalDuration, stepDuration)
 }
 
 func ExtractWarpMessageFromReceipt(
@@ -783,47 +836,18 @@ func ExtractWarpMessageFromReceipt(
 }
 
 func GetFunctionSelector(functionSignature string) string {
-	return "0x" + hex.EncodeToString(crypto.Keccak256([]byte(functionSignature))[:4])
+	return evmSDK.GetFunctionSelector(functionSignature)
 }
 
 func GetErrorFromTrace(
 	trace map[string]interface{},
 	functionSignatureToError map[string]error,
 ) (error, error) {
-	traceOutputI, ok := trace["output"]
-	if !ok {
-		return nil, fmt.Errorf("trace does not contain output field")
-	}
-	traceOutput, ok := traceOutputI.(string)
-	if !ok {
-		return nil, fmt.Errorf("expected type string for trace output, got %T", traceOutputI)
-	}
-	traceOutputBytes, err := hex.DecodeString(strings.TrimPrefix(traceOutput, "0x"))
-	if err != nil {
-		return nil, fmt.Errorf("failure decoding trace output: %w", err)
-	}
-	if len(traceOutputBytes) < 4 {
-		return nil, fmt.Errorf("less than 4 bytes in trace output")
-	}
-	traceErrorSelector := "0x" + hex.EncodeToString(traceOutputBytes[:4])
-	for errorSignature, err := range functionSignatureToError {
-		errorSelector := GetFunctionSelector(errorSignature)
-		if traceErrorSelector == errorSelector {
-			return err, nil
-		}
-	}
-	return nil, fmt.Errorf("%w: %s", ErrUnknownErrorSelector, traceErrorSelector)
+	return evmSDK.GetErrorFromTrace(trace, functionSignatureToError)
 }
 
 func TransactionError(tx *types.Transaction, err error, msg string, args ...interface{}) error {
-	msgSuffix := ": %w"
-	if tx != nil {
-		msgSuffix += fmt.Sprintf(" (txHash=%s)", tx.Hash().String())
-	} else {
-		msgSuffix += " (tx failed to be submitted)"
-	}
-	args = append(args, err)
-	return fmt.Errorf(msg+msgSuffix, args...)
+	return evmSDK.TransactionError(tx, err, msg, args...)
 }
 
 func WaitForRPC(ctx context.Context, rpcURL string) error {
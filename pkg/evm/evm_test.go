@@ -0,0 +1,123 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package evm
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+}
+
+// newHeadersServer serves eth_blockNumber and eth_getBlockByNumber for a chain
+// of [numBlocks] blocks, counting the http requests made for headers on [requests]
+func newHeadersServer(t *testing.T, numBlocks uint64, requests *atomic.Int64) *httptest.Server {
+	answer := func(req rpcRequest) rpcResponse {
+		resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+		switch req.Method {
+		case "eth_blockNumber":
+			resp.Result = hexutil.Uint64(numBlocks - 1)
+		case "eth_getBlockByNumber":
+			var number hexutil.Uint64
+			require.NoError(t, json.Unmarshal(req.Params[0], &number))
+			resp.Result = &types.Header{
+				Number:     new(big.Int).SetUint64(uint64(number)),
+				Difficulty: big.NewInt(1),
+				Time:       uint64(number) * 2,
+				GasUsed:    uint64(number) * 1000,
+				BaseFee:    big.NewInt(25),
+			}
+		default:
+			require.Failf(t, "unexpected method", "%s", req.Method)
+		}
+		return resp
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			requests.Add(1)
+			var reqs []rpcRequest
+			require.NoError(t, json.Unmarshal(body, &reqs))
+			resps := []rpcResponse{}
+			for _, req := range reqs {
+				resps = append(resps, answer(req))
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(resps))
+			return
+		}
+		var req rpcRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		require.NoError(t, json.NewEncoder(w).Encode(answer(req)))
+	}))
+}
+
+func TestGetLastHeaders(t *testing.T) {
+	require := require.New(t)
+	var requests atomic.Int64
+	server := newHeadersServer(t, 100, &requests)
+	defer server.Close()
+	client, err := GetClient(server.URL)
+	require.NoError(err)
+	defer client.Close()
+
+	headers, err := GetLastHeaders(client, 40)
+	require.NoError(err)
+	require.Len(headers, 40)
+	for i, header := range headers {
+		require.Equal(uint64(60+i), header.Number.Uint64())
+		require.Equal(uint64(60+i)*1000, header.GasUsed)
+	}
+	require.Equal(int64(1), requests.Load())
+
+	// asking for more blocks than the chain has returns all of them
+	headers, err = GetLastHeaders(client, 1000)
+	require.NoError(err)
+	require.Len(headers, 100)
+	require.Equal(uint64(0), headers[0].Number.Uint64())
+	require.Equal(uint64(99), headers[99].Number.Uint64())
+	require.Equal(int64(3), requests.Load())
+}
+
+func TestCallTraceAllLogs(t *testing.T) {
+	require := require.New(t)
+	traceJSON := `{
+		"gasUsed": "0x5208",
+		"logs": [
+			{"address": "0x0000000000000000000000000000000000000001", "topics": [], "data": "0x01", "position": "0x0"},
+			{"address": "0x0000000000000000000000000000000000000001", "topics": [], "data": "0x03", "position": "0x1"}
+		],
+		"calls": [
+			{"logs": [{"address": "0x0000000000000000000000000000000000000002", "topics": [], "data": "0x02", "position": "0x0"}]}
+		]
+	}`
+	var trace CallTrace
+	require.NoError(json.Unmarshal([]byte(traceJSON), &trace))
+	require.Equal(hexutil.Uint64(21000), trace.GasUsed)
+	logs := trace.AllLogs()
+	require.Len(logs, 3)
+	for i, log := range logs {
+		require.Equal([]byte{byte(i + 1)}, log.Data)
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/txs/mempool"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// max number of accounts returned by a debug_accountRange call
+	accountRangeMaxResults = 256
+	// the genesis is included on the P-Chain CreateChainTx, whose other fields (inputs,
+	// outputs, credentials, names) are given this room below the max tx size
+	createChainTxOverhead = 2 * units.KiB
+	// MaxGenesisSize is the max size of a genesis that can be included on a CreateChainTx
+	MaxGenesisSize = mempool.MaxTxSize - createChainTxOverhead
+)
+
+// StateAccount is an account of an exported EVM state
+type StateAccount struct {
+	Balance *big.Int                    `json:"balance"`
+	Nonce   uint64                      `json:"nonce"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// StateExport is the full EVM state of a chain at a given height
+type StateExport struct {
+	ChainID   uint64                          `json:"chainId"`
+	Height    uint64                          `json:"height"`
+	BlockHash common.Hash                     `json:"blockHash"`
+	StateRoot common.Hash                     `json:"stateRoot"`
+	Accounts  map[common.Address]StateAccount `json:"accounts"`
+}
+
+// dumpAccount is the account format returned by debug_accountRange
+type dumpAccount struct {
+	Balance string                 `json:"balance"`
+	Nonce   uint64                 `json:"nonce"`
+	Root    common.Hash            `json:"root"`
+	Code    hexutil.Bytes          `json:"code"`
+	Storage map[common.Hash]string `json:"storage"`
+}
+
+type dump struct {
+	Accounts map[string]dumpAccount `json:"accounts"`
+	Next     []byte                 `json:"next"`
+}
+
+// ExportState gets all the accounts, contract code and storage of the chain at [rpcURL]
+// at block [height], or at the last accepted block if [height] is 0. The chain must
+// have the debug-handler API enabled, and must record the preimages of the state
+// keys, as the state trie is otherwise only indexed by their hashes. The dump gives
+// the storage slots without preimage the same zero key, so the storage of each
+// account is checked against its storage root to detect them
+func ExportState(rpcURL string, height uint64) (*StateExport, error) {
+	client, err := GetClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	chainID, err := GetChainID(client)
+	if err != nil {
+		return nil, err
+	}
+	var blockNumber *big.Int
+	if height != 0 {
+		blockNumber = new(big.Int).SetUint64(height)
+	}
+	header, err := utils.CallAPI(rpcURL, func(ctx context.Context) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, blockNumber)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failure obtaining header of block %d on %s: %w", height, rpcURL, err)
+	}
+	rpcClient, err := GetRPCClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer rpcClient.Close()
+	state := &StateExport{
+		ChainID:   chainID.Uint64(),
+		Height:    header.Number.Uint64(),
+		BlockHash: header.Hash(),
+		StateRoot: header.Root,
+		Accounts:  map[common.Address]StateAccount{},
+	}
+	missingPreimages := 0
+	missingSlotPreimages := []string{}
+	start := hexutil.Bytes{}
+	for {
+		page, err := utils.CallAPI(rpcURL, func(ctx context.Context) (dump, error) {
+			var page dump
+			err := rpcClient.CallContext(
+				ctx,
+				&page,
+				"debug_accountRange",
+				hexutil.EncodeUint64(state.Height),
+				start,
+				accountRangeMaxResults,
+				false,
+				false,
+				true,
+			)
+			return page, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failure dumping state at block %d on %s: %w", state.Height, rpcURL, err)
+		}
+		for key, account := range page.Accounts {
+			if !common.IsHexAddress(key) {
+				missingPreimages++
+				continue
+			}
+			balance, ok := new(big.Int).SetString(account.Balance, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid balance %q for account %s", account.Balance, key)
+			}
+			storage := map[common.Hash]common.Hash{}
+			for slot, value := range account.Storage {
+				storage[slot] = common.HexToHash(value)
+			}
+			storageRoot, err := StorageRoot(storage)
+			if err != nil {
+				return nil, err
+			}
+			if storageRoot != account.Root {
+				missingSlotPreimages = append(missingSlotPreimages, key)
+			}
+			state.Accounts[common.HexToAddress(key)] = StateAccount{
+				Balance: balance,
+				Nonce:   account.Nonce,
+				Code:    account.Code,
+				Storage: storage,
+			}
+		}
+		if len(page.Next) == 0 {
+			break
+		}
+		start = page.Next
+	}
+	if missingPreimages > 0 {
+		return nil, fmt.Errorf(
+			"%d accounts of %s have no address preimage. set \"preimages-enabled\": true on the chain config and resync the chain before exporting its state",
+			missingPreimages,
+			rpcURL,
+		)
+	}
+	if len(missingSlotPreimages) > 0 {
+		sort.Strings(missingSlotPreimages)
+		return nil, fmt.Errorf(
+			"storage of %s on %s has slots with no key preimage. set \"preimages-enabled\": true on the chain config and resync the chain before exporting its state",
+			strings.Join(missingSlotPreimages, ", "),
+			rpcURL,
+		)
+	}
+	return state, nil
+}
+
+// StorageRoot computes the root of the storage trie holding [storage]
+func StorageRoot(storage map[common.Hash]common.Hash) (common.Hash, error) {
+	// the trie is indexed by the hash of the slots, and must be built in key order
+	keys := make([][]byte, 0, len(storage))
+	values := map[string][]byte{}
+	for slot, value := range storage {
+		if value == (common.Hash{}) {
+			continue
+		}
+		key := crypto.Keccak256(slot.Bytes())
+		encodedValue, err := rlp.EncodeToBytes(common.TrimLeftZeroes(value.Bytes()))
+		if err != nil {
+			return common.Hash{}, err
+		}
+		keys = append(keys, key)
+		values[string(key)] = encodedValue
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return string(keys[i]) < string(keys[j])
+	})
+	storageTrie := trie.NewStackTrie(nil)
+	for _, key := range keys {
+		if err := storageTrie.Update(key, values[string(key)]); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	return storageTrie.Hash(), nil
+}
+
+// SeedGenesisAlloc sets the accounts of [state] as allocations of the EVM [genesisBytes].
+// Precompile addresses are skipped, as they are set up from the genesis config. Contracts
+// already predeployed on the genesis (eg validator managers) are also kept, as they belong
+// to the new deployment. Returns the updated genesis and the kept addresses. It fails if
+// the updated genesis does not fit on the P-Chain tx that creates the chain
+func SeedGenesisAlloc(genesisBytes []byte, state *StateExport) ([]byte, []common.Address, error) {
+	var genesisMap map[string]interface{}
+	if err := json.Unmarshal(genesisBytes, &genesisMap); err != nil {
+		return nil, nil, err
+	}
+	alloc := map[string]interface{}{}
+	if allocI, ok := genesisMap["alloc"]; ok && allocI != nil {
+		alloc, ok = allocI.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("expected genesis alloc field to be a map[string]interface, found %T", allocI)
+		}
+	}
+	// genesis alloc keys may come with or without prefix, in any case
+	allocKeys := map[common.Address]string{}
+	for key := range alloc {
+		allocKeys[common.HexToAddress(key)] = key
+	}
+	kept := []common.Address{}
+	for address, account := range state.Accounts {
+		if modules.ReservedAddress(address) {
+			continue
+		}
+		if key, ok := allocKeys[address]; ok {
+			if hasGenesisCode(alloc[key]) {
+				kept = append(kept, address)
+				continue
+			}
+			delete(alloc, key)
+		}
+		entry := map[string]interface{}{
+			"balance": "0x" + account.Balance.Text(16),
+		}
+		if account.Nonce != 0 {
+			entry["nonce"] = "0x" + strconv.FormatUint(account.Nonce, 16)
+		}
+		if len(account.Code) > 0 {
+			entry["code"] = account.Code.String()
+		}
+		if len(account.Storage) > 0 {
+			storage := map[string]string{}
+			for slot, value := range account.Storage {
+				storage[slot.Hex()] = value.Hex()
+			}
+			entry["storage"] = storage
+		}
+		alloc[strings.ToLower(strings.TrimPrefix(address.Hex(), "0x"))] = entry
+	}
+	genesisMap["alloc"] = alloc
+	bs, err := json.MarshalIndent(genesisMap, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(bs) > MaxGenesisSize {
+		return nil, nil, fmt.Errorf(
+			"genesis seeded with %d exported accounts is %s, over the max of %s that fits on the P-Chain tx creating the chain",
+			len(state.Accounts),
+			utils.FormatSize(int64(len(bs))),
+			utils.FormatSize(MaxGenesisSize),
+		)
+	}
+	return bs, kept, nil
+}
+
+func hasGenesisCode(entry interface{}) bool {
+	entryMap, ok := entry.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	code, _ := entryMap["code"].(string)
+	return len(common.FromHex(code)) > 0
+}
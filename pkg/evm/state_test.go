@@ -0,0 +1,114 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package evm
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedGenesisAlloc(t *testing.T) {
+	require := require.New(t)
+	funded := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	manager := common.HexToAddress("0x0C0DEBA5E0000000000000000000000000000000")
+	contract := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	precompile := common.HexToAddress("0x0200000000000000000000000000000000000005")
+	genesis := []byte(`{
+  "config": {"chainId": 1},
+  "alloc": {
+    "8db97c7cece249c2b98bdc0226cc4c2a57bf52fc": {"balance": "0x1"},
+    "0x0C0DEBA5E0000000000000000000000000000000": {"balance": "0x0", "code": "0x6001"}
+  }
+}`)
+	state := &StateExport{
+		Accounts: map[common.Address]StateAccount{
+			funded:     {Balance: big.NewInt(255), Nonce: 3},
+			manager:    {Balance: big.NewInt(0), Code: []byte{0x60, 0x02}},
+			precompile: {Balance: big.NewInt(0), Nonce: 1, Code: []byte{0x01}},
+			contract: {
+				Balance: big.NewInt(16),
+				Nonce:   1,
+				Code:    []byte{0x60, 0x03},
+				Storage: map[common.Hash]common.Hash{common.HexToHash("0x01"): common.HexToHash("0x2a")},
+			},
+		},
+	}
+	seeded, kept, err := SeedGenesisAlloc(genesis, state)
+	require.NoError(err)
+	require.Equal([]common.Address{manager}, kept)
+
+	genesisMap := map[string]interface{}{}
+	require.NoError(json.Unmarshal(seeded, &genesisMap))
+	require.Equal(map[string]interface{}{"chainId": float64(1)}, genesisMap["config"])
+	require.Equal(map[string]interface{}{
+		"8db97c7cece249c2b98bdc0226cc4c2a57bf52fc": map[string]interface{}{
+			"balance": "0xff",
+			"nonce":   "0x3",
+		},
+		"0x0C0DEBA5E0000000000000000000000000000000": map[string]interface{}{
+			"balance": "0x0",
+			"code":    "0x6001",
+		},
+		"1111111111111111111111111111111111111111": map[string]interface{}{
+			"balance": "0x10",
+			"nonce":   "0x1",
+			"code":    "0x6003",
+			"storage": map[string]interface{}{
+				"0x0000000000000000000000000000000000000000000000000000000000000001": "0x000000000000000000000000000000000000000000000000000000000000002a",
+			},
+		},
+	}, genesisMap["alloc"])
+}
+
+func TestSeedGenesisAllocMaxSize(t *testing.T) {
+	require := require.New(t)
+	genesis := []byte(`{"config": {"chainId": 1}, "alloc": {}}`)
+	state := &StateExport{
+		Accounts: map[common.Address]StateAccount{
+			common.HexToAddress("0x1111111111111111111111111111111111111111"): {
+				Balance: big.NewInt(0),
+				Code:    make([]byte, MaxGenesisSize/2),
+			},
+		},
+	}
+	_, _, err := SeedGenesisAlloc(genesis, state)
+	require.ErrorContains(err, "over the max")
+}
+
+func TestStorageRoot(t *testing.T) {
+	require := require.New(t)
+	root, err := StorageRoot(nil)
+	require.NoError(err)
+	require.Equal(types.EmptyRootHash, root)
+
+	storage := map[common.Hash]common.Hash{
+		common.HexToHash("0x00"): common.HexToHash("0x2a"),
+		common.HexToHash("0x01"): common.HexToHash("0x0100"),
+		common.HexToHash("0x02"): common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+	}
+	expectedTrie := trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	for slot, value := range storage {
+		encodedValue, err := rlp.EncodeToBytes(common.TrimLeftZeroes(value.Bytes()))
+		require.NoError(err)
+		require.NoError(expectedTrie.Update(crypto.Keccak256(slot.Bytes()), encodedValue))
+	}
+	root, err = StorageRoot(storage)
+	require.NoError(err)
+	require.Equal(expectedTrie.Hash(), root)
+
+	// slots without preimage are all dumped at the zero key, so the root no longer matches
+	collapsed := map[common.Hash]common.Hash{common.Hash{}: common.HexToHash("0x2a")}
+	root, err = StorageRoot(collapsed)
+	require.NoError(err)
+	require.NotEqual(expectedTrie.Hash(), root)
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package faucet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+const (
+	sendTokenPath = "/api/sendToken"
+	// max size of the faucet answers
+	maxResponseSize = 1 << 20
+)
+
+// Chain identifies the chain the faucet sends the funds to
+type Chain string
+
+const (
+	CChain Chain = "C"
+	PChain Chain = "P"
+)
+
+// Request describes the funds asked to the faucet
+type Request struct {
+	Chain   Chain
+	Address string
+	// optional ERC20 token to receive instead of the native token, by its faucet id or address
+	ERC20 string
+	// optional faucet coupon to redeem
+	CouponID string
+	// captcha answer obtained from the faucet web page, required by faucets protected with a captcha
+	CaptchaToken string
+}
+
+type sendTokenRequest struct {
+	Address  string `json:"address"`
+	Chain    string `json:"chain"`
+	ERC20    string `json:"erc20,omitempty"`
+	CouponID string `json:"couponId,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+type sendTokenResponse struct {
+	Message string `json:"message"`
+	TxHash  string `json:"txHash"`
+}
+
+// RateLimitedError is returned when the faucet rejects a request because too many
+// were made. RetryAfter is zero if the faucet does not say when to retry
+type RateLimitedError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	msg := "faucet rate limit reached"
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(". retry in %s", e.RetryAfter)
+	}
+	return msg
+}
+
+// RequestFunds asks the faucet at [faucetURL] to send testnet tokens as described by
+// [req]. Returns the hash of the faucet transaction
+func RequestFunds(
+	ctx context.Context,
+	faucetURL string,
+	req Request,
+) (string, error) {
+	requestBytes, err := json.Marshal(sendTokenRequest{
+		Address:  req.Address,
+		Chain:    string(req.Chain),
+		ERC20:    req.ERC20,
+		CouponID: req.CouponID,
+		Token:    req.CaptchaToken,
+	})
+	if err != nil {
+		return "", err
+	}
+	url := strings.TrimSuffix(faucetURL, "/") + sendTokenPath
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(requestBytes))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failure requesting funds to faucet %s: %w", faucetURL, err)
+	}
+	defer response.Body.Close()
+	responseBytes, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return "", err
+	}
+	var resp sendTokenResponse
+	if err := json.Unmarshal(responseBytes, &resp); err != nil {
+		resp = sendTokenResponse{}
+		if response.StatusCode >= 200 && response.StatusCode < 300 {
+			return "", fmt.Errorf("unexpected faucet response %q: %w", responseBytes, err)
+		}
+	}
+	if response.StatusCode == http.StatusTooManyRequests {
+		return "", &RateLimitedError{
+			Message:    resp.Message,
+			RetryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+		}
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 || resp.TxHash == "" {
+		if resp.Message == "" {
+			return "", fmt.Errorf("faucet answered with status %s", response.Status)
+		}
+		return "", fmt.Errorf("faucet request rejected: %s", resp.Message)
+	}
+	return resp.TxHash, nil
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds
+func parseRetryAfter(retryAfter string) time.Duration {
+	seconds, err := strconv.ParseUint(retryAfter, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// requestsLog maps the faucet requests, as faucet URL, chain, token and address, to the
+// time of the last successful one
+type requestsLog map[string]time.Time
+
+func requestKey(faucetURL string, req Request) string {
+	return strings.Join([]string{strings.TrimSuffix(faucetURL, "/"), string(req.Chain), req.ERC20, req.Address}, "|")
+}
+
+func loadRequestsLog(logPath string) (requestsLog, error) {
+	log := requestsLog{}
+	if !utils.FileExists(logPath) {
+		return log, nil
+	}
+	logBytes, err := os.ReadFile(logPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(logBytes, &log); err != nil {
+		return nil, fmt.Errorf("invalid faucet requests file %s: %w", logPath, err)
+	}
+	return log, nil
+}
+
+// CheckCooldown fails if a request like [req] was successfully made to [faucetURL] less
+// than [cooldown] before [now], according to the requests log at [logPath]. This keeps
+// scripts from hammering the faucet for an address that was already funded
+func CheckCooldown(logPath string, faucetURL string, req Request, cooldown time.Duration, now time.Time) error {
+	log, err := loadRequestsLog(logPath)
+	if err != nil {
+		return err
+	}
+	last, ok := log[requestKey(faucetURL, req)]
+	if !ok {
+		return nil
+	}
+	if wait := last.Add(cooldown).Sub(now); wait > 0 {
+		return &RateLimitedError{
+			Message:    fmt.Sprintf("%s was already funded at %s", req.Address, last.Format(time.RFC3339)),
+			RetryAfter: wait.Round(time.Second),
+		}
+	}
+	return nil
+}
+
+// RecordRequest saves on the requests log at [logPath] that [req] was successfully made
+// to [faucetURL] at [now], forgetting the requests older than [cooldown]
+func RecordRequest(logPath string, faucetURL string, req Request, cooldown time.Duration, now time.Time) error {
+	log, err := loadRequestsLog(logPath)
+	if err != nil {
+		return err
+	}
+	for key, last := range log {
+		if now.Sub(last) > cooldown {
+			delete(log, key)
+		}
+	}
+	log[requestKey(faucetURL, req)] = now
+	logBytes, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(logPath, logBytes, constants.WriteReadReadPerms)
+}
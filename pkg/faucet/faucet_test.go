@@ -0,0 +1,95 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestFunds(t *testing.T) {
+	require := require.New(t)
+	var received sendTokenRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(http.MethodPost, r.Method)
+		require.Equal(sendTokenPath, r.URL.Path)
+		require.NoError(json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"message":"Transaction successful","txHash":"0xabc"}`))
+	}))
+	defer server.Close()
+
+	txHash, err := RequestFunds(context.Background(), server.URL+"/", Request{
+		Chain:        CChain,
+		Address:      "0x1234",
+		ERC20:        "0x5678",
+		CouponID:     "coupon",
+		CaptchaToken: "captcha",
+	})
+	require.NoError(err)
+	require.Equal("0xabc", txHash)
+	require.Equal(sendTokenRequest{Address: "0x1234", Chain: "C", ERC20: "0x5678", CouponID: "coupon", Token: "captcha"}, received)
+}
+
+func TestRequestFundsRejected(t *testing.T) {
+	require := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"Too many requests"}`))
+	}))
+	defer server.Close()
+
+	_, err := RequestFunds(context.Background(), server.URL, Request{Chain: PChain, Address: "P-fuji1abc"})
+	var rateLimitedErr *RateLimitedError
+	require.ErrorAs(err, &rateLimitedErr)
+	require.Equal("Too many requests", rateLimitedErr.Message)
+	require.Equal(time.Minute, rateLimitedErr.RetryAfter)
+
+	badServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer badServer.Close()
+
+	_, err = RequestFunds(context.Background(), badServer.URL, Request{Chain: CChain, Address: "0x1234"})
+	require.ErrorContains(err, "502")
+
+	captchaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"Captcha verification failed"}`))
+	}))
+	defer captchaServer.Close()
+
+	_, err = RequestFunds(context.Background(), captchaServer.URL, Request{Chain: CChain, Address: "0x1234"})
+	require.ErrorContains(err, "Captcha verification failed")
+}
+
+func TestCooldown(t *testing.T) {
+	require := require.New(t)
+	logPath := filepath.Join(t.TempDir(), "faucet_requests.json")
+	faucetURL := "https://faucet.example.com"
+	req := Request{Chain: CChain, Address: "0x1234"}
+	cooldown := 24 * time.Hour
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(CheckCooldown(logPath, faucetURL, req, cooldown, now))
+	require.NoError(RecordRequest(logPath, faucetURL, req, cooldown, now))
+
+	err := CheckCooldown(logPath, faucetURL+"/", req, cooldown, now.Add(time.Hour))
+	var rateLimitedErr *RateLimitedError
+	require.ErrorAs(err, &rateLimitedErr)
+	require.Equal(23*time.Hour, rateLimitedErr.RetryAfter)
+
+	// other addresses, chains and tokens are not affected
+	require.NoError(CheckCooldown(logPath, faucetURL, Request{Chain: CChain, Address: "0x5678"}, cooldown, now))
+	require.NoError(CheckCooldown(logPath, faucetURL, Request{Chain: PChain, Address: "0x1234"}, cooldown, now))
+	require.NoError(CheckCooldown(logPath, faucetURL, Request{Chain: CChain, Address: "0x1234", ERC20: "0x9"}, cooldown, now))
+
+	require.NoError(CheckCooldown(logPath, faucetURL, req, cooldown, now.Add(cooldown)))
+}
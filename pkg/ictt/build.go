@@ -12,7 +12,6 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
-	"github.com/ava-labs/avalanche-cli/pkg/vm"
 )
 
 func RepoDir(
@@ -56,7 +55,7 @@ func DownloadRepo(
 	app *application.Avalanche,
 	version string,
 ) error {
-	if err := vm.CheckGitIsInstalled(); err != nil {
+	if err := utils.CheckGitIsInstalled(); err != nil {
 		return err
 	}
 	repoDir, err := RepoDir(app)
@@ -3,15 +3,27 @@
 package ictt
 
 import (
+	"context"
 	_ "embed"
 
+	"github.com/ava-labs/avalanche-cli/pkg/rpcauth"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/liyue201/erc20-go/erc20"
 )
 
+// dial connects to the EVM RPC at [endpoint], sending the RPC auth token registered for it (if any)
+func dial(endpoint string) (*ethclient.Client, error) {
+	client, err := rpc.DialOptions(context.Background(), endpoint, rpc.WithHeaders(rpcauth.Tokens.Headers(endpoint)))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
 func GetTokenParams(endpoint string, tokenAddress common.Address) (string, string, uint8, error) {
-	client, err := ethclient.Dial(endpoint)
+	client, err := dial(endpoint)
 	if err != nil {
 		return "", "", 0, err
 	}
@@ -35,7 +47,7 @@ func GetTokenParams(endpoint string, tokenAddress common.Address) (string, strin
 }
 
 func GetTokenDecimals(endpoint string, tokenAddress common.Address) (uint8, error) {
-	client, err := ethclient.Dial(endpoint)
+	client, err := dial(endpoint)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,263 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ictt
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	remoteRegisteredEventSpec = "RemoteRegistered(bytes32,address,uint256,uint8)"
+	tokensSentEventSpec       = "TokensSent(bytes32,address,(bytes32,address,address,address,uint256,uint256,uint256,address),uint256)"
+	// TokensSent data is the static SendTokensInput tuple (8 words) followed by the amount
+	tokensSentInputWords = 8
+)
+
+// RemoteRegistration is a remote endpoint registered on a token home
+type RemoteRegistration struct {
+	BlockchainID ids.ID
+	Address      common.Address
+	Decimals     uint8
+}
+
+// TransferVolume is the amount sent by an endpoint to a given destination
+type TransferVolume struct {
+	Transfers int
+	Amount    *big.Int
+}
+
+// GetDeploymentBlock returns the block at which the contract at [address] was deployed,
+// by searching for the first block with code at it. Returns 0 if the node does not keep
+// the state needed for the search
+func GetDeploymentBlock(
+	rpcURL string,
+	address common.Address,
+) (uint64, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	latest, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	block, err := findFirstBlock(latest, func(blockNumber uint64) (bool, error) {
+		code, err := client.CodeAt(ctx, address, new(big.Int).SetUint64(blockNumber))
+		return len(code) > 0, err
+	})
+	if err != nil {
+		// historical state pruned
+		return 0, nil
+	}
+	return block, nil
+}
+
+// findFirstBlock returns the first block up to [latest] for which [deployed] is true,
+// assuming it stays true afterwards
+func findFirstBlock(latest uint64, deployed func(uint64) (bool, error)) (uint64, error) {
+	ok, err := deployed(latest)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("no contract found at block %d", latest)
+	}
+	low, high := uint64(0), latest
+	for low < high {
+		mid := low + (high-low)/2
+		ok, err := deployed(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, nil
+}
+
+// TokenHomeGetRegisteredRemotes returns the remotes that registered on the token home
+// at [homeAddress], scanning the logs from [fromBlock]
+func TokenHomeGetRegisteredRemotes(
+	rpcURL string,
+	homeAddress common.Address,
+	fromBlock uint64,
+) ([]RemoteRegistration, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	logs, err := client.FilterLogs(ctx, interfaces.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		Addresses: []common.Address{homeAddress},
+		Topics:    [][]common.Hash{{eventTopic(remoteRegisteredEventSpec)}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ParseRemoteRegistrations(logs)
+}
+
+// ParseRemoteRegistrations decodes the RemoteRegistered events on [logs]
+func ParseRemoteRegistrations(logs []types.Log) ([]RemoteRegistration, error) {
+	remotes := []RemoteRegistration{}
+	for _, log := range logs {
+		if len(log.Topics) < 3 || len(log.Data) < 2*common.HashLength {
+			return nil, fmt.Errorf("malformed RemoteRegistered event on tx %s", log.TxHash)
+		}
+		remotes = append(remotes, RemoteRegistration{
+			BlockchainID: ids.ID(log.Topics[1]),
+			Address:      common.BytesToAddress(log.Topics[2].Bytes()),
+			Decimals:     uint8(new(big.Int).SetBytes(log.Data[common.HashLength : 2*common.HashLength]).Uint64()),
+		})
+	}
+	return remotes, nil
+}
+
+// TokenHomeGetTransferredBalance returns the amount of home tokens locked on [homeAddress]
+// for the remote [remoteAddress] on [remoteBlockchainID]
+func TokenHomeGetTransferredBalance(
+	rpcURL string,
+	homeAddress common.Address,
+	remoteBlockchainID [32]byte,
+	remoteAddress common.Address,
+) (*big.Int, error) {
+	out, err := contract.CallToMethod(
+		rpcURL,
+		homeAddress,
+		"transferredBalances(bytes32, address)->(uint256)",
+		remoteBlockchainID,
+		remoteAddress,
+	)
+	if err != nil {
+		return nil, err
+	}
+	balance, b := out[0].(*big.Int)
+	if !b {
+		return nil, fmt.Errorf("error at transferredBalances call, expected *big.Int, got %T", out[0])
+	}
+	return balance, nil
+}
+
+// ERC20TokenRemoteGetTotalSupply returns the amount of tokens minted by the erc20 remote
+// at [remoteAddress]
+func ERC20TokenRemoteGetTotalSupply(
+	rpcURL string,
+	remoteAddress common.Address,
+) (*big.Int, error) {
+	out, err := contract.CallToMethod(
+		rpcURL,
+		remoteAddress,
+		"totalSupply()->(uint256)",
+	)
+	if err != nil {
+		return nil, err
+	}
+	supply, b := out[0].(*big.Int)
+	if !b {
+		return nil, fmt.Errorf("error at totalSupply call, expected *big.Int, got %T", out[0])
+	}
+	return supply, nil
+}
+
+// GetPaused returns the paused state of the endpoint at [address]. [supported] is false
+// if the endpoint is not pausable
+func GetPaused(
+	rpcURL string,
+	address common.Address,
+) (paused bool, supported bool) {
+	out, err := contract.CallToMethod(
+		rpcURL,
+		address,
+		"paused()->(bool)",
+	)
+	if err != nil {
+		return false, false
+	}
+	paused, b := out[0].(bool)
+	if !b {
+		return false, false
+	}
+	return paused, true
+}
+
+// GetSentVolume returns the transfers made by the endpoint at [address] to the endpoint
+// [destinationAddress] on [destinationBlockchainID], over the last [blocks] blocks
+func GetSentVolume(
+	rpcURL string,
+	address common.Address,
+	destinationBlockchainID ids.ID,
+	destinationAddress common.Address,
+	blocks uint64,
+) (TransferVolume, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return TransferVolume{}, err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	blockNumber, err := client.BlockNumber(ctx)
+	if err != nil {
+		return TransferVolume{}, err
+	}
+	fromBlock := uint64(0)
+	if blockNumber > blocks {
+		fromBlock = blockNumber - blocks
+	}
+	logs, err := client.FilterLogs(ctx, interfaces.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		Addresses: []common.Address{address},
+		Topics:    [][]common.Hash{{eventTopic(tokensSentEventSpec)}},
+	})
+	if err != nil {
+		return TransferVolume{}, err
+	}
+	return SumSentVolume(logs, destinationBlockchainID, destinationAddress)
+}
+
+// SumSentVolume adds up the TokensSent events on [logs] directed to [destinationAddress]
+// on [destinationBlockchainID]
+func SumSentVolume(
+	logs []types.Log,
+	destinationBlockchainID ids.ID,
+	destinationAddress common.Address,
+) (TransferVolume, error) {
+	volume := TransferVolume{Amount: big.NewInt(0)}
+	for _, log := range logs {
+		if len(log.Data) < (tokensSentInputWords+1)*common.HashLength {
+			return TransferVolume{}, fmt.Errorf("malformed TokensSent event on tx %s", log.TxHash)
+		}
+		word := func(i int) []byte {
+			return log.Data[i*common.HashLength : (i+1)*common.HashLength]
+		}
+		if ids.ID(word(0)) != destinationBlockchainID || common.BytesToAddress(word(1)) != destinationAddress {
+			continue
+		}
+		volume.Transfers++
+		volume.Amount.Add(volume.Amount, new(big.Int).SetBytes(word(tokensSentInputWords)))
+	}
+	return volume, nil
+}
+
+func eventTopic(eventSpec string) common.Hash {
+	return crypto.Keccak256Hash([]byte(eventSpec))
+}
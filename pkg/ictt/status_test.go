@@ -0,0 +1,95 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ictt
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func word(b []byte) []byte {
+	return common.LeftPadBytes(b, common.HashLength)
+}
+
+func tokensSentLog(destinationBlockchainID ids.ID, destinationAddress common.Address, amount int64) types.Log {
+	data := []byte{}
+	data = append(data, destinationBlockchainID[:]...)
+	data = append(data, word(destinationAddress.Bytes())...)
+	for i := 2; i < tokensSentInputWords; i++ {
+		data = append(data, word(nil)...)
+	}
+	data = append(data, word(big.NewInt(amount).Bytes())...)
+	return types.Log{Topics: []common.Hash{eventTopic(tokensSentEventSpec)}, Data: data}
+}
+
+func TestSumSentVolume(t *testing.T) {
+	require := require.New(t)
+	blockchainID := ids.GenerateTestID()
+	otherBlockchainID := ids.GenerateTestID()
+	address := common.HexToAddress("0x1")
+	otherAddress := common.HexToAddress("0x2")
+	logs := []types.Log{
+		tokensSentLog(blockchainID, address, 10),
+		tokensSentLog(blockchainID, otherAddress, 100),
+		tokensSentLog(otherBlockchainID, address, 1000),
+		tokensSentLog(blockchainID, address, 5),
+	}
+	volume, err := SumSentVolume(logs, blockchainID, address)
+	require.NoError(err)
+	require.Equal(2, volume.Transfers)
+	require.Equal(big.NewInt(15), volume.Amount)
+
+	_, err = SumSentVolume([]types.Log{{Data: []byte{1}}}, blockchainID, address)
+	require.ErrorContains(err, "malformed TokensSent event")
+}
+
+func TestParseRemoteRegistrations(t *testing.T) {
+	require := require.New(t)
+	blockchainID := ids.GenerateTestID()
+	address := common.HexToAddress("0x1234")
+	logs := []types.Log{{
+		Topics: []common.Hash{
+			eventTopic(remoteRegisteredEventSpec),
+			common.Hash(blockchainID),
+			common.BytesToHash(address.Bytes()),
+		},
+		Data: append(word(big.NewInt(1000).Bytes()), word([]byte{6})...),
+	}}
+	remotes, err := ParseRemoteRegistrations(logs)
+	require.NoError(err)
+	require.Equal([]RemoteRegistration{{BlockchainID: blockchainID, Address: address, Decimals: 6}}, remotes)
+
+	_, err = ParseRemoteRegistrations([]types.Log{{Topics: logs[0].Topics[:1]}})
+	require.ErrorContains(err, "malformed RemoteRegistered event")
+}
+
+func TestFindFirstBlock(t *testing.T) {
+	require := require.New(t)
+	deployedAt := func(deploymentBlock uint64) func(uint64) (bool, error) {
+		return func(blockNumber uint64) (bool, error) {
+			return blockNumber >= deploymentBlock, nil
+		}
+	}
+	for _, deploymentBlock := range []uint64{0, 1, 57, 99, 100} {
+		block, err := findFirstBlock(100, deployedAt(deploymentBlock))
+		require.NoError(err)
+		require.Equal(deploymentBlock, block)
+	}
+	_, err := findFirstBlock(100, deployedAt(101))
+	require.ErrorContains(err, "no contract found")
+
+	errPruned := errors.New("missing trie node")
+	_, err = findFirstBlock(100, func(blockNumber uint64) (bool, error) {
+		if blockNumber < 90 {
+			return false, errPruned
+		}
+		return true, nil
+	})
+	require.ErrorIs(err, errPruned)
+}
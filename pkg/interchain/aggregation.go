@@ -0,0 +1,154 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	sdkinterchain "github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// AggregationFlags holds the signature aggregation settings given on the command line.
+// Zero values mean the setting was not given
+type AggregationFlags struct {
+	QuorumPercentage uint64
+	ValidatorTimeout time.Duration
+	ParallelRequests int
+	MaxAttempts      int
+	RetryStrategy    string
+	RetryDelay       time.Duration
+	Verbose          bool
+}
+
+// AddAggregationFlagsToCmd adds the signature aggregation settings flags to [cmd]
+func AddAggregationFlagsToCmd(cmd *cobra.Command, flags *AggregationFlags) {
+	cmd.Flags().Uint64Var(&flags.QuorumPercentage, "aggregator-quorum-percentage", 0, fmt.Sprintf("percentage of the validators weight that must sign warp messages (default %d)", sdkinterchain.DefaultQuorumPercentage))
+	cmd.Flags().DurationVar(&flags.ValidatorTimeout, "aggregator-validator-timeout", 0, "max time to wait for the signature of each validator (default is the p2p request timeout)")
+	cmd.Flags().IntVar(&flags.ParallelRequests, "aggregator-parallel-requests", 0, "max number of validators to request signatures from at the same time (default all of them)")
+	cmd.Flags().IntVar(&flags.MaxAttempts, "aggregator-max-attempts", 0, fmt.Sprintf("number of signature aggregation rounds before failing (default %d)", sdkinterchain.DefaultAggregationConfig.MaxAttempts))
+	cmd.Flags().StringVar(&flags.RetryStrategy, "aggregator-retry-strategy", "", fmt.Sprintf("delay growth between signature aggregation rounds [fixed, exponential] (default %s)", sdkinterchain.DefaultAggregationConfig.RetryStrategy))
+	cmd.Flags().DurationVar(&flags.RetryDelay, "aggregator-retry-delay", 0, fmt.Sprintf("delay before the first signature aggregation retry (default %s)", sdkinterchain.DefaultAggregationConfig.RetryDelay))
+	cmd.Flags().BoolVar(&flags.Verbose, "aggregator-verbose", false, "show the signature request outcome of each validator after signature aggregation")
+}
+
+// GetAggregationConfig returns the signature aggregation settings given by [flags],
+// falling back to the ones found in the config file, and then to the defaults
+func GetAggregationConfig(app *application.Avalanche, flags AggregationFlags) (sdkinterchain.AggregationConfig, error) {
+	config := sdkinterchain.DefaultAggregationConfig
+	if app.Conf.ConfigValueIsSet(constants.ConfigAggregatorQuorumKey) {
+		config.QuorumPercentage = uint64(app.Conf.GetConfigIntValue(constants.ConfigAggregatorQuorumKey))
+	}
+	if app.Conf.ConfigValueIsSet(constants.ConfigAggregatorTimeoutKey) {
+		timeout, err := time.ParseDuration(app.Conf.GetConfigStringValue(constants.ConfigAggregatorTimeoutKey))
+		if err != nil {
+			return config, fmt.Errorf("invalid %s config value: %w", constants.ConfigAggregatorTimeoutKey, err)
+		}
+		config.ValidatorTimeout = timeout
+	}
+	if app.Conf.ConfigValueIsSet(constants.ConfigAggregatorParallelKey) {
+		config.MaxParallelRequests = app.Conf.GetConfigIntValue(constants.ConfigAggregatorParallelKey)
+	}
+	if app.Conf.ConfigValueIsSet(constants.ConfigAggregatorAttemptsKey) {
+		config.MaxAttempts = app.Conf.GetConfigIntValue(constants.ConfigAggregatorAttemptsKey)
+	}
+	if app.Conf.ConfigValueIsSet(constants.ConfigAggregatorStrategyKey) {
+		config.RetryStrategy = sdkinterchain.RetryStrategy(app.Conf.GetConfigStringValue(constants.ConfigAggregatorStrategyKey))
+	}
+	if app.Conf.ConfigValueIsSet(constants.ConfigAggregatorRetryDelayKey) {
+		retryDelay, err := time.ParseDuration(app.Conf.GetConfigStringValue(constants.ConfigAggregatorRetryDelayKey))
+		if err != nil {
+			return config, fmt.Errorf("invalid %s config value: %w", constants.ConfigAggregatorRetryDelayKey, err)
+		}
+		config.RetryDelay = retryDelay
+	}
+	if flags.QuorumPercentage != 0 {
+		config.QuorumPercentage = flags.QuorumPercentage
+	}
+	if flags.ValidatorTimeout != 0 {
+		config.ValidatorTimeout = flags.ValidatorTimeout
+	}
+	if flags.ParallelRequests != 0 {
+		config.MaxParallelRequests = flags.ParallelRequests
+	}
+	if flags.MaxAttempts != 0 {
+		config.MaxAttempts = flags.MaxAttempts
+	}
+	if flags.RetryStrategy != "" {
+		config.RetryStrategy = sdkinterchain.RetryStrategy(flags.RetryStrategy)
+	}
+	if flags.RetryDelay != 0 {
+		config.RetryDelay = flags.RetryDelay
+	}
+	if flags.Verbose {
+		config.ResultsHandler = PrintAggregationResults
+	}
+	return config, config.Validate()
+}
+
+// PrintAggregationResults shows the outcome of the signature request made to each
+// validator on a signature aggregation
+func PrintAggregationResults(results []sdkinterchain.ValidatorSignatureResult) {
+	var totalWeight, signedWeight uint64
+	for _, result := range results {
+		totalWeight += result.Weight
+		if result.Status == sdkinterchain.ValidatorSignatureStatusSigned {
+			signedWeight += result.Weight
+		}
+	}
+	ux.Logger.PrintToUser("Signature aggregation results:")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"NodeID", "Weight", "Status", "Requests", "Latency"})
+	for _, result := range results {
+		latency := ""
+		if result.Requests > 0 {
+			latency = result.Latency.Round(time.Millisecond).String()
+		}
+		table.Append([]string{
+			result.NodeID.String(),
+			fmt.Sprintf("%d", result.Weight),
+			result.Status,
+			fmt.Sprintf("%d", result.Requests),
+			latency,
+		})
+	}
+	table.Render()
+	if totalWeight > 0 {
+		ux.Logger.PrintToUser("Signed weight: %d/%d (%d%%)", signedWeight, totalWeight, signedWeight*100/totalWeight)
+	}
+}
+
+// URIsToPeers returns the node ID and public IP of the nodes at [uris], to be
+// given as extra peers to the signature aggregator
+func URIsToPeers(uris []string) ([]info.Peer, error) {
+	peers := []info.Peer{}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	for _, uri := range uris {
+		client := info.NewClient(uri)
+		nodeID, _, err := client.GetNodeID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ip, err := client.GetNodeIP(ctx)
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, info.Peer{
+			Info: peer.Info{
+				ID:       nodeID,
+				PublicIP: ip,
+			},
+		})
+	}
+	return peers, nil
+}
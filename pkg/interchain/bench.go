@@ -0,0 +1,185 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// BenchMessageResult contains the measurements for a single benchmark message
+type BenchMessageResult struct {
+	Index     int
+	MessageID ids.ID
+	// SentAt is the time the send tx was issued at the source blockchain
+	SentAt time.Time
+	// SourceLatency is the time from issuing the send tx until it was accepted at source
+	SourceLatency time.Duration
+	// DeliveryLatency is the time from source acceptance until the message was observed
+	// as received at destination
+	DeliveryLatency time.Duration
+	Delivered       bool
+	Error           string `json:",omitempty"`
+}
+
+// EndToEndLatency is the time from issuing the send tx until the message was observed
+// as received at destination
+func (r BenchMessageResult) EndToEndLatency() time.Duration {
+	return r.SourceLatency + r.DeliveryLatency
+}
+
+type LatencyStats struct {
+	Min time.Duration
+	Avg time.Duration
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+type BenchSummary struct {
+	Messages        int
+	Delivered       int
+	SendFailures    int
+	DeliveryTimeout int
+	Duration        time.Duration
+	// SendRate is the achieved rate of sent messages per second
+	SendRate        float64
+	SourceLatency   LatencyStats
+	DeliveryLatency LatencyStats
+	EndToEndLatency LatencyStats
+}
+
+type BenchReport struct {
+	Summary BenchSummary
+	Results []BenchMessageResult
+}
+
+// Percentile returns the [p] (0-100) percentile of [sorted] using the nearest rank method
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+func GetLatencyStats(latencies []time.Duration) LatencyStats {
+	if len(latencies) == 0 {
+		return LatencyStats{}
+	}
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+	return LatencyStats{
+		Min: sorted[0],
+		Avg: sum / time.Duration(len(sorted)),
+		P50: Percentile(sorted, 50),
+		P90: Percentile(sorted, 90),
+		P99: Percentile(sorted, 99),
+		Max: sorted[len(sorted)-1],
+	}
+}
+
+// SummarizeBench computes latency percentiles and failure counts for a benchmark
+// that took [duration] to complete. Latencies are only computed over delivered messages
+func SummarizeBench(results []BenchMessageResult, duration time.Duration) BenchSummary {
+	summary := BenchSummary{
+		Messages: len(results),
+		Duration: duration,
+	}
+	sourceLatencies := []time.Duration{}
+	deliveryLatencies := []time.Duration{}
+	endToEndLatencies := []time.Duration{}
+	var firstSent, lastSent time.Time
+	sent := 0
+	for _, r := range results {
+		switch {
+		case r.Delivered:
+			summary.Delivered++
+			sourceLatencies = append(sourceLatencies, r.SourceLatency)
+			deliveryLatencies = append(deliveryLatencies, r.DeliveryLatency)
+			endToEndLatencies = append(endToEndLatencies, r.EndToEndLatency())
+		case r.MessageID == ids.Empty:
+			summary.SendFailures++
+		default:
+			summary.DeliveryTimeout++
+		}
+		if r.MessageID != ids.Empty {
+			sent++
+			if firstSent.IsZero() || r.SentAt.Before(firstSent) {
+				firstSent = r.SentAt
+			}
+			if r.SentAt.After(lastSent) {
+				lastSent = r.SentAt
+			}
+		}
+	}
+	if sent > 1 && lastSent.After(firstSent) {
+		summary.SendRate = float64(sent-1) / lastSent.Sub(firstSent).Seconds()
+	}
+	summary.SourceLatency = GetLatencyStats(sourceLatencies)
+	summary.DeliveryLatency = GetLatencyStats(deliveryLatencies)
+	summary.EndToEndLatency = GetLatencyStats(endToEndLatencies)
+	return summary
+}
+
+func WriteBenchJSON(w io.Writer, report BenchReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// WriteBenchCSV writes one row per message, with latencies in milliseconds
+func WriteBenchCSV(w io.Writer, results []BenchMessageResult) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write([]string{
+		"index",
+		"message_id",
+		"sent_at",
+		"delivered",
+		"source_latency_ms",
+		"delivery_latency_ms",
+		"end_to_end_latency_ms",
+		"error",
+	}); err != nil {
+		return err
+	}
+	for _, r := range results {
+		messageID := ""
+		if r.MessageID != ids.Empty {
+			messageID = r.MessageID.String()
+		}
+		if err := csvWriter.Write([]string{
+			strconv.Itoa(r.Index),
+			messageID,
+			r.SentAt.UTC().Format(time.RFC3339Nano),
+			strconv.FormatBool(r.Delivered),
+			fmt.Sprint(r.SourceLatency.Milliseconds()),
+			fmt.Sprint(r.DeliveryLatency.Milliseconds()),
+			fmt.Sprint(r.EndToEndLatency().Milliseconds()),
+			r.Error,
+		}); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	require := require.New(t)
+	sorted := []time.Duration{}
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(50*time.Millisecond, Percentile(sorted, 50))
+	require.Equal(90*time.Millisecond, Percentile(sorted, 90))
+	require.Equal(99*time.Millisecond, Percentile(sorted, 99))
+	require.Equal(100*time.Millisecond, Percentile(sorted, 100))
+	require.Equal(1*time.Millisecond, Percentile(sorted, 0))
+	require.Equal(time.Duration(0), Percentile(nil, 50))
+}
+
+func TestSummarizeBench(t *testing.T) {
+	require := require.New(t)
+	start := time.Now()
+	results := []BenchMessageResult{}
+	for i := 0; i < 10; i++ {
+		results = append(results, BenchMessageResult{
+			Index:           i,
+			MessageID:       ids.GenerateTestID(),
+			SentAt:          start.Add(time.Duration(i) * 100 * time.Millisecond),
+			SourceLatency:   time.Second,
+			DeliveryLatency: time.Duration(i+1) * time.Second,
+			Delivered:       true,
+		})
+	}
+	// send failure
+	results[8].MessageID = ids.Empty
+	results[8].Delivered = false
+	// delivery timeout
+	results[9].Delivered = false
+
+	summary := SummarizeBench(results, 20*time.Second)
+	require.Equal(10, summary.Messages)
+	require.Equal(8, summary.Delivered)
+	require.Equal(1, summary.SendFailures)
+	require.Equal(1, summary.DeliveryTimeout)
+	// 9 sent messages within 900ms
+	require.InDelta(8/0.9, summary.SendRate, 0.001)
+	require.Equal(time.Second, summary.DeliveryLatency.Min)
+	require.Equal(8*time.Second, summary.DeliveryLatency.Max)
+	require.Equal(4*time.Second, summary.DeliveryLatency.P50)
+	require.Equal(4500*time.Millisecond, summary.DeliveryLatency.Avg)
+	require.Equal(9*time.Second, summary.EndToEndLatency.Max)
+	require.Equal(time.Second, summary.SourceLatency.P99)
+}
+
+func TestWriteBenchCSV(t *testing.T) {
+	require := require.New(t)
+	results := []BenchMessageResult{
+		{
+			Index:           0,
+			MessageID:       ids.GenerateTestID(),
+			SourceLatency:   1500 * time.Millisecond,
+			DeliveryLatency: 2 * time.Second,
+			Delivered:       true,
+		},
+		{
+			Index: 1,
+			Error: "failure sending",
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(WriteBenchCSV(&buf, results))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(lines, 3)
+	require.True(strings.HasSuffix(lines[1], ",true,1500,2000,3500,"))
+	require.True(strings.HasPrefix(lines[2], "1,,"))
+	require.True(strings.HasSuffix(lines[2], ",false,0,0,0,failure sending"))
+}
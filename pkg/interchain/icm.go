@@ -82,6 +82,12 @@ func (t *ICMDeployer) CheckAssets() error {
 	return nil
 }
 
+// MessengerAddress returns the address the messenger of the assets is deployed at, on
+// any blockchain
+func (t *ICMDeployer) MessengerAddress() string {
+	return t.messengerContractAddress
+}
+
 func (t *ICMDeployer) SetAssetsFromPaths(
 	messengerContractAddressPath string,
 	messengerDeployerAddressPath string,
@@ -363,7 +369,7 @@ func getPrivateKey(
 			return "", err
 		}
 	} else {
-		k, err = key.LoadSoft(network.ID, app.GetKeyPath(keyName))
+		k, err = app.GetKey(keyName, network, false)
 		if err != nil {
 			return "", err
 		}
@@ -429,7 +435,7 @@ func getICMKeyInfo(
 	app *application.Avalanche,
 	keyName string,
 ) (string, string, *big.Int, error) {
-	k, err := key.LoadSoftOrCreate(models.NewLocalNetwork().ID, app.GetKeyPath(keyName))
+	k, err := app.GetKey(keyName, app.GetLocalNetwork(), true)
 	if err != nil {
 		return "", "", nil, err
 	}
@@ -446,12 +452,21 @@ type ICMInfo struct {
 
 func GetICMInfo(
 	app *application.Avalanche,
+) (*ICMInfo, error) {
+	return GetICMInfoForKey(app, constants.ICMKeyName)
+}
+
+// GetICMInfoForKey is like GetICMInfo, but funds the ICM operations from
+// the key [keyName], created if it does not exist
+func GetICMInfoForKey(
+	app *application.Avalanche,
+	keyName string,
 ) (*ICMInfo, error) {
 	var err error
 	ti := ICMInfo{}
 	ti.FundedAddress, _, ti.FundedBalance, err = getICMKeyInfo(
 		app,
-		constants.ICMKeyName,
+		keyName,
 	)
 	if err != nil {
 		return nil, err
@@ -471,3 +486,16 @@ func GetICMInfo(
 	}
 	return &ti, nil
 }
+
+// GetMessengerAddress returns the address of the ICM messenger release [version], which
+// is the same on all blockchains
+func GetMessengerAddress(
+	app *application.Avalanche,
+	version string,
+) (string, error) {
+	deployer := ICMDeployer{}
+	if err := deployer.DownloadAssets(app.GetICMContractsBinDir(), version); err != nil {
+		return "", err
+	}
+	return deployer.MessengerAddress(), nil
+}
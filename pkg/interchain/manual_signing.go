@@ -0,0 +1,83 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	sdkinterchain "github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// GetSigningRequestPath returns the path where the manual signing request of
+// [messageID] is stored
+func GetSigningRequestPath(app *application.Avalanche, messageID ids.ID) string {
+	return filepath.Join(app.GetWarpSigningDir(), messageID.String()+".json")
+}
+
+// SaveSigningRequest stores [request] at [path]
+func SaveSigningRequest(path string, request sdkinterchain.ManualSigningRequest) error {
+	requestBytes, err := json.MarshalIndent(request, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, requestBytes, constants.WriteReadReadPerms)
+}
+
+// LoadSigningRequest reads the manual signing request stored at [path]
+func LoadSigningRequest(path string) (sdkinterchain.ManualSigningRequest, error) {
+	requestBytes, err := os.ReadFile(path)
+	if err != nil {
+		return sdkinterchain.ManualSigningRequest{}, err
+	}
+	var request sdkinterchain.ManualSigningRequest
+	if err := json.Unmarshal(requestBytes, &request); err != nil {
+		return sdkinterchain.ManualSigningRequest{}, fmt.Errorf("invalid warp signing request %s: %w", path, err)
+	}
+	return request, nil
+}
+
+// LoadSigningRequests reads all the manual signing requests stored by the app
+func LoadSigningRequests(app *application.Avalanche) ([]sdkinterchain.ManualSigningRequest, error) {
+	paths, err := filepath.Glob(filepath.Join(app.GetWarpSigningDir(), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	requests := []sdkinterchain.ManualSigningRequest{}
+	for _, path := range paths {
+		request, err := LoadSigningRequest(path)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// UseManuallySignedMessages makes the manually signed messages stored by the app
+// to be used by the flows that need them, instead of aggregating signatures again
+func UseManuallySignedMessages(app *application.Avalanche) error {
+	requests, err := LoadSigningRequests(app)
+	if err != nil {
+		return err
+	}
+	for _, request := range requests {
+		if request.SignedMessage == "" {
+			continue
+		}
+		signedMessage, err := request.GetSignedMessage()
+		if err != nil {
+			return err
+		}
+		sdkinterchain.UsePresignedMessage(signedMessage)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RegistryAddProtocolVersionMethod registers on the ICM registry the messenger given by
+// the off-chain warp message at the given index of the tx predicates
+const RegistryAddProtocolVersionMethod = "addProtocolVersion(uint32)"
+
+// GetRegistryLatestVersion returns the latest protocol version registered on the ICM
+// registry at [registryAddress]
+func GetRegistryLatestVersion(rpcURL string, registryAddress common.Address) (uint64, error) {
+	out, err := contract.CallToMethod(
+		rpcURL,
+		registryAddress,
+		"latestVersion()->(uint256)",
+	)
+	if err != nil {
+		return 0, err
+	}
+	version, b := out[0].(*big.Int)
+	if !b {
+		return 0, fmt.Errorf("error at latestVersion call, expected *big.Int, got %T", out[0])
+	}
+	return version.Uint64(), nil
+}
+
+// GetRegistryVersions returns the messenger addresses registered on the ICM registry at
+// [registryAddress], by protocol version
+func GetRegistryVersions(rpcURL string, registryAddress common.Address) (map[uint64]common.Address, error) {
+	latestVersion, err := GetRegistryLatestVersion(rpcURL, registryAddress)
+	if err != nil {
+		return nil, err
+	}
+	versions := map[uint64]common.Address{}
+	for version := uint64(1); version <= latestVersion; version++ {
+		out, err := contract.CallToMethod(
+			rpcURL,
+			registryAddress,
+			"getAddressFromVersion(uint256)->(address)",
+			new(big.Int).SetUint64(version),
+		)
+		if err != nil {
+			return nil, err
+		}
+		messengerAddress, b := out[0].(common.Address)
+		if !b {
+			return nil, fmt.Errorf("error at getAddressFromVersion call, expected common.Address, got %T", out[0])
+		}
+		versions[version] = messengerAddress
+	}
+	return versions, nil
+}
+
+// NewRegistryEntryMessage builds the off-chain warp message that registers [messengerAddress]
+// as protocol [version] on the ICM registry at [registryAddress] of [blockchainID]. The
+// registry only accepts it signed by the blockchain validators, coming from the zero
+// address, so validators must list it on the warp-off-chain-messages field of their
+// chain config
+func NewRegistryEntryMessage(
+	networkID uint32,
+	blockchainID ids.ID,
+	registryAddress common.Address,
+	version uint64,
+	messengerAddress common.Address,
+) (*warp.UnsignedMessage, error) {
+	// abi encoding of ((uint256 version, address protocolAddress), address destinationAddress).
+	// the entry tuple only has static fields, so it is encoded in place
+	payload := make([]byte, 0, 3*common.HashLength)
+	payload = append(payload, common.BigToHash(new(big.Int).SetUint64(version)).Bytes()...)
+	payload = append(payload, common.BytesToHash(messengerAddress.Bytes()).Bytes()...)
+	payload = append(payload, common.BytesToHash(registryAddress.Bytes()).Bytes()...)
+	return NewAddressedCallMessage(networkID, blockchainID, common.Address{}.Bytes(), payload)
+}
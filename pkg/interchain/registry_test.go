@@ -0,0 +1,48 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpPayload "github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistryEntryMessage(t *testing.T) {
+	require := require.New(t)
+	blockchainID := ids.GenerateTestID()
+	registryAddress := common.HexToAddress("0x17aB05351fC94a1a67Bf3f56DdbB941aE6c63E25")
+	messengerAddress := common.HexToAddress("0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf")
+	msg, err := NewRegistryEntryMessage(5, blockchainID, registryAddress, 2, messengerAddress)
+	require.NoError(err)
+	parsed, err := warp.ParseUnsignedMessage(msg.Bytes())
+	require.NoError(err)
+	require.Equal(blockchainID, parsed.SourceChainID)
+	addressedCall, err := warpPayload.ParseAddressedCall(parsed.Payload)
+	require.NoError(err)
+	require.Equal(common.Address{}, common.BytesToAddress(addressedCall.SourceAddress))
+
+	// decoded as the registry does: abi.decode(payload, (ProtocolRegistryEntry, address))
+	entryType, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "version", Type: "uint256"},
+		{Name: "protocolAddress", Type: "address"},
+	})
+	require.NoError(err)
+	addressType, err := abi.NewType("address", "", nil)
+	require.NoError(err)
+	expected, err := abi.Arguments{{Type: entryType}, {Type: addressType}}.Pack(
+		struct {
+			Version         *big.Int
+			ProtocolAddress common.Address
+		}{big.NewInt(2), messengerAddress},
+		registryAddress,
+	)
+	require.NoError(err)
+	require.Equal(expected, addressedCall.Payload)
+}
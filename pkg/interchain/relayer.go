@@ -17,11 +17,13 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/bincache"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/evm"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/rpcauth"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	apiConfig "github.com/ava-labs/icm-services/config"
@@ -35,6 +37,10 @@ const (
 	localRelayerCheckTimeout      = 3 * time.Second
 	defaultDBWriteIntervalSeconds = 10
 	defaultSignatureCacheSize     = 1024 * 1024
+	// port used by the relayer API when none is set on its config
+	defaultRelayerAPIPort = 8080
+	// times the relayer is restarted on new ports if its ports are taken while it starts
+	relayerPortRetries = 3
 )
 
 var relayerRequiredBalance = big.NewInt(0).Mul(big.NewInt(1e18), big.NewInt(500)) // 500 AVAX
@@ -94,6 +100,10 @@ type relayerRunFile struct {
 	Pid int `json:"pid"`
 }
 
+// DeployRelayer starts the relayer at [binPath], or the one of [version] if not given,
+// with the config at [configPath]. Relayer ports that are in use are moved to
+// available ones, avoiding [reservedPorts], the ports of other local services
+// mapped to the service using them
 func DeployRelayer(
 	version string,
 	binPath string,
@@ -102,6 +112,7 @@ func DeployRelayer(
 	logFilePath string,
 	runFilePath string,
 	storageDir string,
+	reservedPorts map[uint16]string,
 ) (string, error) {
 	if err := RelayerCleanup(runFilePath, logFilePath, storageDir); err != nil {
 		return "", err
@@ -113,11 +124,35 @@ func DeployRelayer(
 			return "", err
 		}
 	}
-	pid, err := executeRelayer(binPath, configPath, logFilePath)
+	for attempt := 1; ; attempt++ {
+		conflicts, err := ResolveRelayerPortConflicts(configPath, reservedPorts)
+		if err != nil {
+			return "", err
+		}
+		for _, conflict := range conflicts {
+			ux.Logger.PrintToUser("%s. Using port %d instead", utils.PortConflictsReport([]utils.PortConflict{conflict.PortConflict}), conflict.NewPort)
+		}
+		pid, err := executeRelayer(binPath, configPath, logFilePath)
+		if err != nil {
+			// the ports can be taken by another process after being checked
+			if attempt < relayerPortRetries && relayerPortInUse(logFilePath) {
+				ux.Logger.PrintToUser("Relayer ports were taken while it was starting. Retrying")
+				continue
+			}
+			return "", err
+		}
+		return binPath, saveRelayerRunFile(runFilePath, pid)
+	}
+}
+
+// relayerPortInUse returns true if the relayer logs at [logFilePath] show it failed
+// to bind a port already in use
+func relayerPortInUse(logFilePath string) bool {
+	bs, err := os.ReadFile(logFilePath)
 	if err != nil {
-		return "", err
+		return false
 	}
-	return binPath, saveRelayerRunFile(runFilePath, pid)
+	return utils.IsPortInUseError(errors.New(string(bs)))
 }
 
 func RelayerIsUp(runFilePath string) (bool, int, *os.Process, error) {
@@ -266,11 +301,15 @@ func InstallRelayer(binDir, version string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	bs, err := utils.Download(url)
-	if err != nil {
-		return "", err
-	}
-	if err := binutils.InstallArchive("tar.gz", bs, versionBinDir); err != nil {
+	// the binary cache lives next to the install dirs of all binaries
+	cache := bincache.New(filepath.Join(filepath.Dir(binDir), constants.BinaryCacheDir))
+	if err := cache.Install(
+		constants.ICMRelayerKind,
+		version,
+		versionBinDir,
+		func() ([]byte, error) { return utils.Download(url) },
+		func(archive []byte, dir string) error { return binutils.InstallArchive("tar.gz", archive, dir) },
+	); err != nil {
 		return "", err
 	}
 	return binPath, nil
@@ -369,6 +408,72 @@ func loadRelayerConfig(relayerConfigPath string) (*config.Config, error) {
 	return &awmRelayerConfig, nil
 }
 
+// GetRelayerConfigKeyDestinations returns the IDs of the destination blockchains of the
+// relayer config at [relayerConfigPath] that pay for message delivery with [privateKeyHex]
+func GetRelayerConfigKeyDestinations(relayerConfigPath string, privateKeyHex string) ([]string, error) {
+	relayerConfig, err := loadRelayerConfig(relayerConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	privateKeyHex = strings.ToLower(strings.TrimPrefix(privateKeyHex, "0x"))
+	blockchainIDs := []string{}
+	for _, destination := range relayerConfig.DestinationBlockchains {
+		if strings.ToLower(strings.TrimPrefix(destination.AccountPrivateKey, "0x")) == privateKeyHex {
+			blockchainIDs = append(blockchainIDs, destination.BlockchainID)
+		}
+	}
+	return blockchainIDs, nil
+}
+
+// RelayerPortConflict is a relayer port conflict, solved by moving the port to [NewPort]
+type RelayerPortConflict struct {
+	utils.PortConflict
+	NewPort uint16
+}
+
+// ResolveRelayerPortConflicts checks the API and metrics ports set on the relayer config at
+// [relayerConfigPath]. The ones already in use are moved to available ports other than
+// [reservedPorts], and saved on the config so the relayer keeps using them afterwards
+func ResolveRelayerPortConflicts(relayerConfigPath string, reservedPorts map[uint16]string) ([]RelayerPortConflict, error) {
+	relayerConfig, err := loadRelayerConfig(relayerConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	apiPort := relayerConfig.APIPort
+	if apiPort == 0 {
+		apiPort = defaultRelayerAPIPort
+	}
+	portSetters := map[uint16]func(uint16){
+		apiPort:                   func(port uint16) { relayerConfig.APIPort = port },
+		relayerConfig.MetricsPort: func(port uint16) { relayerConfig.MetricsPort = port },
+	}
+	conflicts := utils.FindPortConflicts(map[uint16]string{
+		apiPort:                   "relayer API",
+		relayerConfig.MetricsPort: "relayer metrics",
+	})
+	if len(conflicts) == 0 {
+		return nil, nil
+	}
+	reserved := map[uint16]bool{apiPort: true, relayerConfig.MetricsPort: true}
+	for port := range reservedPorts {
+		reserved[port] = true
+	}
+	resolved := []RelayerPortConflict{}
+	for _, conflict := range conflicts {
+		newPort, err := utils.GetAvailablePort(conflict.Port+1, reserved)
+		if err != nil {
+			return nil, err
+		}
+		reserved[newPort] = true
+		portSetters[conflict.Port](newPort)
+		resolved = append(resolved, RelayerPortConflict{
+			PortConflict: conflict,
+			NewPort:      newPort,
+		})
+	}
+	return resolved, saveRelayerConfig(relayerConfig, relayerConfigPath)
+}
+
 func saveRelayerConfig(relayerConfig *config.Config, relayerConfigPath string) error {
 	if err := os.MkdirAll(filepath.Dir(relayerConfigPath), constants.DefaultPerms755); err != nil {
 		return err
@@ -535,10 +640,12 @@ func addSourceToRelayerConfig(
 		BlockchainID: blockchainID,
 		VM:           config.EVM.String(),
 		RPCEndpoint: apiConfig.APIConfig{
-			BaseURL: rpcEndpoint,
+			BaseURL:     rpcEndpoint,
+			HTTPHeaders: getRPCAuthHeaders(rpcEndpoint),
 		},
 		WSEndpoint: apiConfig.APIConfig{
-			BaseURL: wsEndpoint,
+			BaseURL:     wsEndpoint,
+			HTTPHeaders: getRPCAuthHeaders(wsEndpoint),
 		},
 		MessageContracts: map[string]config.MessageProtocolConfig{
 			icmMessengerAddress: {
@@ -560,6 +667,20 @@ func addSourceToRelayerConfig(
 	}
 }
 
+// getRPCAuthHeaders returns the headers the relayer needs to authenticate to the
+// blockchain RPC at [endpoint], if it is locked down with node rpc-auth
+func getRPCAuthHeaders(endpoint string) map[string]string {
+	headers := rpcauth.Tokens.Headers(endpoint)
+	if len(headers) == 0 {
+		return nil
+	}
+	httpHeaders := map[string]string{}
+	for key := range headers {
+		httpHeaders[key] = headers.Get(key)
+	}
+	return httpHeaders
+}
+
 func addDestinationToRelayerConfig(
 	relayerConfig *config.Config,
 	rpcEndpoint string,
@@ -572,7 +693,8 @@ func addDestinationToRelayerConfig(
 		BlockchainID: blockchainID,
 		VM:           config.EVM.String(),
 		RPCEndpoint: apiConfig.APIConfig{
-			BaseURL: rpcEndpoint,
+			BaseURL:     rpcEndpoint,
+			HTTPHeaders: getRPCAuthHeaders(rpcEndpoint),
 		},
 		AccountPrivateKey: relayerFundedAddressKey,
 	}
@@ -0,0 +1,421 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/icm-services/relayer/config"
+	icmutils "github.com/ava-labs/icm-services/utils"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// gas used by the relayer to deliver a message, used to estimate the minimum balance
+// its destination keys need
+const relayerDeliveryGasLimit = 2_000_000
+
+// RelayerConfigCheck is the result of a single check done over a relayer config
+type RelayerConfigCheck struct {
+	// config element the check applies to
+	Target string
+	Check  string
+	// extra info for successful or skipped checks
+	Info string
+	Err  error
+}
+
+// relayerChainState keeps what has been learnt about a configured chain, to be used
+// when simulating delivery paths
+type relayerChainState struct {
+	blockchainID ids.ID
+	client       ethclient.Client
+	rpcURL       string
+	healthy      bool
+	funded       bool
+}
+
+// ValidateRelayerConfig checks the relayer config at [relayerConfigPath] against the
+// live chains it references, without sending any transaction: endpoint reachability,
+// blockchain and subnet IDs, funding of destination keys, and that every source to
+// destination message path can be delivered.
+// An error is returned only if the config can't be loaded
+func ValidateRelayerConfig(app *application.Avalanche, relayerConfigPath string) ([]RelayerConfigCheck, error) {
+	relayerConfig, err := loadRelayerConfig(relayerConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failure loading relayer config %s: %w", relayerConfigPath, err)
+	}
+	checks := []RelayerConfigCheck{}
+	addCheck := func(target string, check string, details string, err error) {
+		checks = append(checks, RelayerConfigCheck{Target: target, Check: check, Info: details, Err: err})
+	}
+	// Validate dereferences the API configs, so missing ones are reported below instead
+	if relayerConfig.PChainAPI != nil && relayerConfig.InfoAPI != nil {
+		addCheck("config", "schema", "", relayerConfig.Validate())
+	}
+
+	pChainEndpoint := ""
+	if relayerConfig.PChainAPI == nil || relayerConfig.PChainAPI.BaseURL == "" {
+		addCheck("p-chain-api", "reachable", "", fmt.Errorf("p-chain-api base-url is not set"))
+	} else {
+		ctx, cancel := utils.GetAPIContext()
+		_, err := platformvm.NewClient(relayerConfig.PChainAPI.BaseURL).GetHeight(ctx)
+		cancel()
+		if err != nil {
+			addCheck("p-chain-api", "reachable", "", fmt.Errorf("%s is not reachable: %w", relayerConfig.PChainAPI.BaseURL, err))
+		} else {
+			pChainEndpoint = relayerConfig.PChainAPI.BaseURL
+			addCheck("p-chain-api", "reachable", relayerConfig.PChainAPI.BaseURL, nil)
+		}
+	}
+	if relayerConfig.InfoAPI == nil || relayerConfig.InfoAPI.BaseURL == "" {
+		addCheck("info-api", "reachable", "", fmt.Errorf("info-api base-url is not set"))
+	} else {
+		ctx, cancel := utils.GetAPIContext()
+		networkID, err := info.NewClient(relayerConfig.InfoAPI.BaseURL).GetNetworkID(ctx)
+		cancel()
+		if err != nil {
+			addCheck("info-api", "reachable", "", fmt.Errorf("%s is not reachable: %w", relayerConfig.InfoAPI.BaseURL, err))
+		} else {
+			addCheck("info-api", "reachable", fmt.Sprintf("network ID %d", networkID), nil)
+		}
+	}
+
+	// blockchain IDs can be given either in hex or cb58, so states are indexed by parsed ID
+	destinations := map[ids.ID]*relayerChainState{}
+	states := []*relayerChainState{}
+	defer func() {
+		for _, state := range states {
+			if state.client != nil {
+				state.client.Close()
+			}
+		}
+	}()
+	for _, destination := range relayerConfig.DestinationBlockchains {
+		target := "destination " + destination.BlockchainID
+		state := checkRelayerChain(
+			target,
+			destination.RPCEndpoint.BaseURL,
+			destination.BlockchainID,
+			destination.SubnetID,
+			pChainEndpoint,
+			addCheck,
+		)
+		states = append(states, state)
+		if state.blockchainID != ids.Empty {
+			destinations[state.blockchainID] = state
+		}
+		switch {
+		case destination.AccountPrivateKey != "":
+			if !state.healthy {
+				addCheck(target, "relayer key funded", "", fmt.Errorf("not checked, as the chain is not reachable"))
+				continue
+			}
+			details, err := checkRelayerKeyFunding(state.client, destination.AccountPrivateKey, getNativeTokenDecimals(app, state.blockchainID))
+			state.funded = err == nil
+			addCheck(target, "relayer key funded", details, err)
+		case destination.KMSKeyID != "":
+			// balance of KMS keys can't be obtained without KMS access
+			state.funded = true
+			addCheck(target, "relayer key funded", fmt.Sprintf("KMS key %s, funding not checked", destination.KMSKeyID), nil)
+		default:
+			addCheck(target, "relayer key funded", "", fmt.Errorf("no account-private-key or kms-key-id is set"))
+		}
+	}
+
+	for _, source := range relayerConfig.SourceBlockchains {
+		target := "source " + source.BlockchainID
+		state := checkRelayerChain(
+			target,
+			source.RPCEndpoint.BaseURL,
+			source.BlockchainID,
+			source.SubnetID,
+			pChainEndpoint,
+			addCheck,
+		)
+		states = append(states, state)
+		if source.WSEndpoint.BaseURL == "" {
+			addCheck(target, "ws reachable", "", fmt.Errorf("ws-endpoint base-url is not set"))
+		} else if err := checkRelayerEndpoint(source.WSEndpoint.BaseURL); err != nil {
+			addCheck(target, "ws reachable", "", fmt.Errorf("%s is not reachable: %w", source.WSEndpoint.BaseURL, err))
+		} else {
+			addCheck(target, "ws reachable", source.WSEndpoint.BaseURL, nil)
+		}
+		messengerAddresses := []string{}
+		for address, messageContract := range source.MessageContracts {
+			if messageContract.MessageFormat != config.TELEPORTER.String() {
+				continue
+			}
+			messengerAddresses = append(messengerAddresses, address)
+			if !state.healthy {
+				continue
+			}
+			if err := checkContractDeployed(state.client, address); err != nil {
+				addCheck(target, "messenger deployed", "", err)
+			} else {
+				addCheck(target, "messenger deployed", address, nil)
+			}
+		}
+		if len(messengerAddresses) == 0 {
+			addCheck(target, "messenger configured", "", fmt.Errorf("no %s message contract is set", config.TELEPORTER))
+			continue
+		}
+		registryAddress := getRelayerRegistryAddress(source)
+		destinationBlockchainIDs, err := getRelayerSupportedDestinations(relayerConfig, source)
+		if err != nil {
+			addCheck(target, "supported destinations", "", err)
+			continue
+		}
+		for _, destinationBlockchainID := range destinationBlockchainIDs {
+			path := fmt.Sprintf("path %s -> %s", source.BlockchainID, destinationBlockchainID)
+			for _, messengerAddress := range messengerAddresses {
+				details, err := simulateRelayerDelivery(state, destinations[destinationBlockchainID], messengerAddress, registryAddress)
+				addCheck(path, "delivery", details, err)
+			}
+		}
+	}
+	return checks, nil
+}
+
+// checkRelayerChain checks that [rpcURL] is reachable and that it serves [blockchainID],
+// and that [blockchainID] belongs to [subnetID]
+func checkRelayerChain(
+	target string,
+	rpcURL string,
+	blockchainIDStr string,
+	subnetIDStr string,
+	pChainEndpoint string,
+	addCheck func(string, string, string, error),
+) *relayerChainState {
+	state := &relayerChainState{rpcURL: rpcURL}
+	blockchainID, err := icmutils.HexOrCB58ToID(blockchainIDStr)
+	if err != nil {
+		addCheck(target, "blockchain ID", "", fmt.Errorf("invalid blockchain-id %q: %w", blockchainIDStr, err))
+		return state
+	}
+	state.blockchainID = blockchainID
+	if rpcURL == "" {
+		addCheck(target, "rpc reachable", "", fmt.Errorf("rpc-endpoint base-url is not set"))
+		return state
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	client, err := evm.DialContext(ctx, rpcURL)
+	if err == nil {
+		_, err = client.ChainID(ctx)
+	}
+	if err != nil {
+		if client != nil {
+			client.Close()
+		}
+		addCheck(target, "rpc reachable", "", fmt.Errorf("%s is not reachable: %w", rpcURL, err))
+		return state
+	}
+	chainID, err := GetWarpBlockchainID(rpcURL)
+	switch {
+	case err != nil:
+		client.Close()
+		addCheck(target, "rpc reachable", "", fmt.Errorf("%s does not answer warp queries: %w", rpcURL, err))
+		return state
+	case chainID != blockchainID:
+		client.Close()
+		addCheck(target, "rpc reachable", rpcURL, nil)
+		addCheck(target, "blockchain ID", "", fmt.Errorf("%s serves blockchain %s, not %s", rpcURL, chainID, blockchainID))
+		return state
+	}
+	addCheck(target, "rpc reachable", rpcURL, nil)
+	addCheck(target, "blockchain ID", blockchainID.String(), nil)
+	state.client = client
+	state.healthy = true
+	subnetID, err := icmutils.HexOrCB58ToID(subnetIDStr)
+	switch {
+	case err != nil:
+		addCheck(target, "subnet ID", "", fmt.Errorf("invalid subnet-id %q: %w", subnetIDStr, err))
+	case subnetID == ids.Empty:
+		// primary network chains are not created by a P-Chain tx
+		addCheck(target, "subnet ID", "primary network", nil)
+	case pChainEndpoint == "":
+		addCheck(target, "subnet ID", "", fmt.Errorf("not checked, as the p-chain-api is not reachable"))
+	default:
+		tx, err := utils.GetBlockchainTx(pChainEndpoint, blockchainID)
+		switch {
+		case err != nil:
+			addCheck(target, "subnet ID", "", fmt.Errorf("blockchain %s not found on the P-Chain: %w", blockchainID, err))
+		case tx.SubnetID != subnetID:
+			addCheck(target, "subnet ID", "", fmt.Errorf("blockchain %s belongs to subnet %s, not %s", blockchainID, tx.SubnetID, subnetID))
+		default:
+			addCheck(target, "subnet ID", subnetID.String(), nil)
+		}
+	}
+	return state
+}
+
+// simulateRelayerDelivery checks all the conditions needed for the relayer to deliver a
+// message sent by [messengerAddress] on [source] into [destination], without sending it
+func simulateRelayerDelivery(
+	source *relayerChainState,
+	destination *relayerChainState,
+	messengerAddress string,
+	registryAddress string,
+) (string, error) {
+	switch {
+	case !source.healthy:
+		return "", fmt.Errorf("source chain is not reachable")
+	case destination == nil:
+		return "", fmt.Errorf("destination is a supported destination of the source but is not set under destination-blockchains")
+	case !destination.healthy:
+		return "", fmt.Errorf("destination chain is not reachable")
+	}
+	if err := checkContractDeployed(destination.client, messengerAddress); err != nil {
+		return "", fmt.Errorf("messenger %s is not deployed on destination, so messages can't be received: %w", messengerAddress, err)
+	}
+	if registryAddress != "" {
+		if err := checkContractDeployed(destination.client, registryAddress); err != nil {
+			return "", fmt.Errorf("registry %s is not deployed on destination: %w", registryAddress, err)
+		}
+	}
+	if !destination.funded {
+		return "", fmt.Errorf("destination relayer key can't pay for message delivery")
+	}
+	return fmt.Sprintf("messenger %s", messengerAddress), nil
+}
+
+// getNativeTokenDecimals returns the decimals of the native token of the blockchain with
+// [blockchainID], if known locally, or the default ones
+func getNativeTokenDecimals(app *application.Avalanche, blockchainID ids.ID) uint8 {
+	blockchainNames, err := app.GetBlockchainNames()
+	if err != nil {
+		return constants.DefaultTokenDecimals
+	}
+	for _, blockchainName := range blockchainNames {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			continue
+		}
+		for _, networkData := range sc.Networks {
+			if networkData.BlockchainID == blockchainID {
+				return sc.GetTokenDecimals()
+			}
+		}
+	}
+	return constants.DefaultTokenDecimals
+}
+
+// checkRelayerKeyFunding checks the key can pay for at least one message delivery
+// at current gas prices. Amounts are shown with the native token [decimals]
+func checkRelayerKeyFunding(client ethclient.Client, privateKey string, decimals uint8) (string, error) {
+	address, err := utils.PrivateKeyToAddress(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid account-private-key: %w", err)
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	balance, err := client.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return "", fmt.Errorf("failure obtaining balance for %s: %w", address.Hex(), err)
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failure obtaining gas price: %w", err)
+	}
+	required := new(big.Int).Mul(gasPrice, big.NewInt(relayerDeliveryGasLimit))
+	if balance.Cmp(required) < 0 {
+		return "", fmt.Errorf(
+			"%s has a balance of %s, less than the %s needed to deliver a message. fund it before starting the relayer",
+			address.Hex(),
+			utils.FormatAmount(balance, decimals),
+			utils.FormatAmount(required, decimals),
+		)
+	}
+	return fmt.Sprintf("%s balance %s", address.Hex(), utils.FormatAmount(balance, decimals)), nil
+}
+
+func checkRelayerEndpoint(url string) error {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	client, err := evm.DialContext(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	_, err = client.ChainID(ctx)
+	return err
+}
+
+func checkContractDeployed(client ethclient.Client, address string) error {
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("invalid contract address %q", address)
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	code, err := client.CodeAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("no contract found at %s", address)
+	}
+	return nil
+}
+
+// GetWarpBlockchainID returns the blockchain ID reported by the warp precompile of [rpcURL]
+func GetWarpBlockchainID(rpcURL string) (ids.ID, error) {
+	out, err := contract.CallToMethod(
+		rpcURL,
+		warp.ContractAddress,
+		"getBlockchainID()->(bytes32)",
+	)
+	if err != nil {
+		return ids.Empty, err
+	}
+	blockchainID, b := out[0].([32]byte)
+	if !b {
+		return ids.Empty, fmt.Errorf("error at getBlockchainID call, expected ids.ID, got %T", out[0])
+	}
+	return blockchainID, nil
+}
+
+// getRelayerSupportedDestinations returns the blockchain IDs messages from [source] are
+// relayed to. The relayer relays to all destinations if none is specified.
+// Invalid destinations are already reported when checking the destination chains
+func getRelayerSupportedDestinations(relayerConfig *config.Config, source *config.SourceBlockchain) ([]ids.ID, error) {
+	blockchainIDs := []ids.ID{}
+	if len(source.SupportedDestinations) == 0 {
+		for _, destination := range relayerConfig.DestinationBlockchains {
+			if blockchainID, err := icmutils.HexOrCB58ToID(destination.BlockchainID); err == nil {
+				blockchainIDs = append(blockchainIDs, blockchainID)
+			}
+		}
+		return blockchainIDs, nil
+	}
+	for _, supportedDestination := range source.SupportedDestinations {
+		blockchainID, err := icmutils.HexOrCB58ToID(supportedDestination.BlockchainID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid supported destination %q: %w", supportedDestination.BlockchainID, err)
+		}
+		blockchainIDs = append(blockchainIDs, blockchainID)
+	}
+	return blockchainIDs, nil
+}
+
+func getRelayerRegistryAddress(source *config.SourceBlockchain) string {
+	for _, messageContract := range source.MessageContracts {
+		if messageContract.MessageFormat != config.OFF_CHAIN_REGISTRY.String() {
+			continue
+		}
+		if registryAddress, ok := messageContract.Settings["teleporter-registry-address"].(string); ok {
+			return registryAddress
+		}
+	}
+	return ""
+}
@@ -0,0 +1,214 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	avaconfig "github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	basecfg "github.com/ava-labs/icm-services/config"
+	"github.com/ava-labs/icm-services/relayer/config"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testMessengerAddress = "0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf"
+
+// newTestEVMServer serves the JSON-RPC methods used by the relayer config validation, for a
+// chain with blockchain ID [blockchainID], the messenger deployed, and [balance] on every address
+func newTestEVMServer(t *testing.T, blockchainID ids.ID, balance int64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var result interface{}
+		switch request.Method {
+		case "eth_chainId":
+			result = "0x1"
+		case "eth_gasPrice":
+			result = "0x1"
+		case "eth_getBalance":
+			result = hexutil.EncodeBig(common.Big1.SetInt64(balance))
+		case "eth_getCode":
+			var address common.Address
+			_ = json.Unmarshal(request.Params[0], &address)
+			result = "0x"
+			if address == common.HexToAddress(testMessengerAddress) {
+				result = "0x6080"
+			}
+		case "eth_call":
+			var call struct {
+				To common.Address `json:"to"`
+			}
+			_ = json.Unmarshal(request.Params[0], &call)
+			result = "0x"
+			if call.To == warp.ContractAddress {
+				result = hexutil.Encode(blockchainID[:])
+			}
+		default:
+			t.Errorf("unexpected method %s", request.Method)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeTestRelayerConfig(t *testing.T, relayerConfig config.Config) string {
+	bs, err := json.Marshal(relayerConfig)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "relayer-config.json")
+	require.NoError(t, os.WriteFile(path, bs, 0o600))
+	return path
+}
+
+func getTestCheck(checks []RelayerConfigCheck, target string, check string) *RelayerConfigCheck {
+	for i := range checks {
+		if checks[i].Target == target && checks[i].Check == check {
+			return &checks[i]
+		}
+	}
+	return nil
+}
+
+func TestValidateRelayerConfig(t *testing.T) {
+	require := require.New(t)
+	sourceID := ids.GenerateTestID()
+	destinationID := ids.GenerateTestID()
+	unfundedID := ids.GenerateTestID()
+	source := newTestEVMServer(t, sourceID, 0)
+	destination := newTestEVMServer(t, destinationID, 1_000_000_000_000)
+	unfunded := newTestEVMServer(t, unfundedID, 0)
+	key := "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+	relayerConfig := config.Config{
+		SourceBlockchains: []*config.SourceBlockchain{
+			{
+				SubnetID:     ids.Empty.String(),
+				BlockchainID: sourceID.String(),
+				RPCEndpoint:  basecfg.APIConfig{BaseURL: source.URL},
+				WSEndpoint:   basecfg.APIConfig{BaseURL: source.URL},
+				MessageContracts: map[string]config.MessageProtocolConfig{
+					testMessengerAddress: {MessageFormat: config.TELEPORTER.String()},
+				},
+				// destinations given in hex, while destination blockchains use cb58
+				SupportedDestinations: []*config.SupportedDestination{
+					{BlockchainID: "0x" + common.Bytes2Hex(destinationID[:])},
+					{BlockchainID: "0x" + common.Bytes2Hex(unfundedID[:])},
+				},
+			},
+		},
+		DestinationBlockchains: []*config.DestinationBlockchain{
+			{
+				SubnetID:          ids.Empty.String(),
+				BlockchainID:      destinationID.String(),
+				RPCEndpoint:       basecfg.APIConfig{BaseURL: destination.URL},
+				AccountPrivateKey: key,
+			},
+			{
+				SubnetID:          ids.Empty.String(),
+				BlockchainID:      unfundedID.String(),
+				RPCEndpoint:       basecfg.APIConfig{BaseURL: unfunded.URL},
+				AccountPrivateKey: key,
+			},
+		},
+	}
+	// the destination native token has 6 decimals
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, avaconfig.New(), nil, nil)
+	require.NoError(app.CreateSidecar(&models.Sidecar{
+		Name:          "destination",
+		TokenDecimals: 6,
+		Networks: map[string]models.NetworkData{
+			models.Local.String(): {BlockchainID: destinationID},
+		},
+	}))
+	checks, err := ValidateRelayerConfig(app, writeTestRelayerConfig(t, relayerConfig))
+	require.NoError(err)
+
+	sourceTarget := "source " + sourceID.String()
+	require.NoError(getTestCheck(checks, sourceTarget, "rpc reachable").Err)
+	require.NoError(getTestCheck(checks, sourceTarget, "blockchain ID").Err)
+	require.NoError(getTestCheck(checks, sourceTarget, "messenger deployed").Err)
+	funded := getTestCheck(checks, "destination "+destinationID.String(), "relayer key funded")
+	require.NoError(funded.Err)
+	require.Contains(funded.Info, "balance 1000000")
+	require.Error(getTestCheck(checks, "destination "+unfundedID.String(), "relayer key funded").Err)
+
+	delivery := getTestCheck(checks, "path "+sourceID.String()+" -> "+destinationID.String(), "delivery")
+	require.NotNil(delivery)
+	require.NoError(delivery.Err)
+	delivery = getTestCheck(checks, "path "+sourceID.String()+" -> "+unfundedID.String(), "delivery")
+	require.NotNil(delivery)
+	require.ErrorContains(delivery.Err, "can't pay for message delivery")
+
+	// the P-Chain and info APIs are not set
+	require.Error(getTestCheck(checks, "p-chain-api", "reachable").Err)
+	require.Error(getTestCheck(checks, "info-api", "reachable").Err)
+}
+
+func TestValidateRelayerConfigWrongBlockchain(t *testing.T) {
+	require := require.New(t)
+	blockchainID := ids.GenerateTestID()
+	server := newTestEVMServer(t, ids.GenerateTestID(), 0)
+	relayerConfig := config.Config{
+		DestinationBlockchains: []*config.DestinationBlockchain{
+			{
+				SubnetID:     ids.Empty.String(),
+				BlockchainID: blockchainID.String(),
+				RPCEndpoint:  basecfg.APIConfig{BaseURL: server.URL},
+			},
+		},
+	}
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, avaconfig.New(), nil, nil)
+	checks, err := ValidateRelayerConfig(app, writeTestRelayerConfig(t, relayerConfig))
+	require.NoError(err)
+	check := getTestCheck(checks, "destination "+blockchainID.String(), "blockchain ID")
+	require.NotNil(check)
+	require.ErrorContains(check.Err, "serves blockchain")
+}
+
+func TestGetRelayerSupportedDestinations(t *testing.T) {
+	require := require.New(t)
+	blockchainID := ids.GenerateTestID()
+	relayerConfig := &config.Config{
+		DestinationBlockchains: []*config.DestinationBlockchain{
+			{BlockchainID: blockchainID.String()},
+			{BlockchainID: "invalid"},
+		},
+	}
+	// all destinations if none is specified
+	destinations, err := getRelayerSupportedDestinations(relayerConfig, &config.SourceBlockchain{})
+	require.NoError(err)
+	require.Equal([]ids.ID{blockchainID}, destinations)
+
+	destinations, err = getRelayerSupportedDestinations(relayerConfig, &config.SourceBlockchain{
+		SupportedDestinations: []*config.SupportedDestination{
+			{BlockchainID: "0x" + common.Bytes2Hex(blockchainID[:])},
+		},
+	})
+	require.NoError(err)
+	require.Equal([]ids.ID{blockchainID}, destinations)
+
+	_, err = getRelayerSupportedDestinations(relayerConfig, &config.SourceBlockchain{
+		SupportedDestinations: []*config.SupportedDestination{{BlockchainID: "invalid"}},
+	})
+	require.Error(err)
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/core/vm/runtime"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// GetMessengerCodeHash returns the hash of the runtime code created by the ICM messenger
+// deployment tx [messengerDeployerTx]. The messenger has no constructor, so its code is
+// the same on every blockchain, whatever the way it was deployed
+func GetMessengerCodeHash(messengerDeployerTx string) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(common.FromHex(strings.TrimSpace(messengerDeployerTx))); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid ICM messenger deployment tx: %w", err)
+	}
+	if tx.To() != nil {
+		return common.Hash{}, fmt.Errorf("ICM messenger deployment tx does not create a contract")
+	}
+	code, _, _, err := runtime.Create(tx.Data(), &runtime.Config{ChainConfig: params.TestChainConfig})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failure executing ICM messenger deployment tx: %w", err)
+	}
+	return crypto.Keccak256Hash(code), nil
+}
+
+// GetKnownMessengerCodeHashes returns the messenger runtime code hashes of the ICM
+// releases whose assets are available at [icmInstallDir], by release version
+func GetKnownMessengerCodeHashes(icmInstallDir string) (map[string]common.Hash, error) {
+	codeHashes := map[string]common.Hash{}
+	entries, err := os.ReadDir(icmInstallDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return codeHashes, nil
+		}
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		version := entry.Name()
+		_, _, messengerDeployerTxURL, _ := getICMURLs(version)
+		messengerDeployerTxPath := filepath.Join(icmInstallDir, version, filepath.Base(messengerDeployerTxURL))
+		if !utils.FileExists(messengerDeployerTxPath) {
+			continue
+		}
+		messengerDeployerTx, err := os.ReadFile(messengerDeployerTxPath)
+		if err != nil {
+			return nil, err
+		}
+		codeHash, err := GetMessengerCodeHash(string(messengerDeployerTx))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", messengerDeployerTxPath, err)
+		}
+		codeHashes[version] = codeHash
+	}
+	return codeHashes, nil
+}
+
+// GetDeployedMessengerVersion returns the ICM release of the messenger deployed at
+// [messengerAddress], by matching its code against [knownCodeHashes]
+func GetDeployedMessengerVersion(
+	rpcURL string,
+	messengerAddress common.Address,
+	knownCodeHashes map[string]common.Hash,
+) (string, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	code, err := evm.GetContractBytecode(client, messengerAddress.Hex())
+	if err != nil {
+		return "", err
+	}
+	if len(code) == 0 {
+		return "", fmt.Errorf("no contract is deployed at %s", messengerAddress)
+	}
+	return matchMessengerVersion(crypto.Keccak256Hash(code), knownCodeHashes)
+}
+
+func matchMessengerVersion(codeHash common.Hash, knownCodeHashes map[string]common.Hash) (string, error) {
+	versions := make([]string, 0, len(knownCodeHashes))
+	for version, knownCodeHash := range knownCodeHashes {
+		if knownCodeHash == codeHash {
+			return version, nil
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no ICM release is available to verify the messenger code against")
+	}
+	sort.Strings(versions)
+	return "", fmt.Errorf("messenger code does not match any of the known ICM releases (%s)", strings.Join(versions, ", "))
+}
+
+// VerifyRegistry checks that the contract at [registryAddress] is an ICM registry of
+// [blockchainID], returning the messenger addresses registered on it by protocol version
+func VerifyRegistry(
+	rpcURL string,
+	registryAddress common.Address,
+	blockchainID ids.ID,
+) (map[uint64]common.Address, error) {
+	out, err := contract.CallToMethod(
+		rpcURL,
+		registryAddress,
+		"blockchainID()->(bytes32)",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an ICM registry: %w", registryAddress, err)
+	}
+	registryBlockchainID, b := out[0].([32]byte)
+	if !b {
+		return nil, fmt.Errorf("error at blockchainID call, expected [32]byte, got %T", out[0])
+	}
+	if ids.ID(registryBlockchainID) != blockchainID {
+		return nil, fmt.Errorf("ICM registry %s belongs to blockchain %s, not to %s", registryAddress, ids.ID(registryBlockchainID), blockchainID)
+	}
+	return GetRegistryVersions(rpcURL, registryAddress)
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// runtime code returning 1, and init code copying it into memory and returning it
+var (
+	testRuntimeCode = common.FromHex("600160005260206000f3")
+	testInitCode    = append(common.FromHex("600a600c600039600a6000f3"), testRuntimeCode...)
+)
+
+func newTestDeployerTx(t *testing.T, to *common.Address, data []byte) string {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    0,
+		GasPrice: big.NewInt(100_000_000_000),
+		Gas:      1_000_000,
+		To:       to,
+		Value:    big.NewInt(0),
+		Data:     data,
+	})
+	signedTx, err := types.SignTx(tx, types.HomesteadSigner{}, key)
+	require.NoError(t, err)
+	txBytes, err := signedTx.MarshalBinary()
+	require.NoError(t, err)
+	return common.Bytes2Hex(txBytes)
+}
+
+func TestGetMessengerCodeHash(t *testing.T) {
+	require := require.New(t)
+	codeHash, err := GetMessengerCodeHash(newTestDeployerTx(t, nil, testInitCode) + "\n")
+	require.NoError(err)
+	require.Equal(crypto.Keccak256Hash(testRuntimeCode), codeHash)
+
+	to := common.HexToAddress("0x253b2784c75e510dD0fF1da844684a1aC0aa5fcf")
+	_, err = GetMessengerCodeHash(newTestDeployerTx(t, &to, testInitCode))
+	require.ErrorContains(err, "does not create a contract")
+
+	_, err = GetMessengerCodeHash("0x1234")
+	require.ErrorContains(err, "invalid ICM messenger deployment tx")
+}
+
+func TestGetKnownMessengerCodeHashes(t *testing.T) {
+	require := require.New(t)
+	icmInstallDir := t.TempDir()
+	codeHashes, err := GetKnownMessengerCodeHashes(filepath.Join(icmInstallDir, "missing"))
+	require.NoError(err)
+	require.Empty(codeHashes)
+
+	version := "v1.0.0"
+	_, _, messengerDeployerTxURL, _ := getICMURLs(version)
+	require.NoError(os.MkdirAll(filepath.Join(icmInstallDir, version), 0o750))
+	require.NoError(os.MkdirAll(filepath.Join(icmInstallDir, "v0.9.0"), 0o750))
+	require.NoError(os.WriteFile(
+		filepath.Join(icmInstallDir, version, filepath.Base(messengerDeployerTxURL)),
+		[]byte(newTestDeployerTx(t, nil, testInitCode)),
+		0o600,
+	))
+	codeHashes, err = GetKnownMessengerCodeHashes(icmInstallDir)
+	require.NoError(err)
+	require.Equal(map[string]common.Hash{version: crypto.Keccak256Hash(testRuntimeCode)}, codeHashes)
+
+	matched, err := matchMessengerVersion(crypto.Keccak256Hash(testRuntimeCode), codeHashes)
+	require.NoError(err)
+	require.Equal(version, matched)
+	_, err = matchMessengerVersion(common.Hash{}, codeHashes)
+	require.ErrorContains(err, "does not match any of the known ICM releases (v1.0.0)")
+	_, err = matchMessengerVersion(common.Hash{}, nil)
+	require.ErrorContains(err, "no ICM release is available")
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpPayload "github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/subnet-evm/core/types"
+	subnetEvmWarp "github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultWarpReceiverMethod is the method called at the destination contract when
+// delivering a raw warp message. Its only parameter is the index of the warp
+// message in the tx predicates
+const DefaultWarpReceiverMethod = "receiveWarpMessage(uint32)"
+
+// NewAddressedCallMessage builds the unsigned warp message for an addressed call
+// with [payload] coming from [sourceAddress] at [sourceBlockchainID]. This is the
+// same structure a blockchain emits through the warp precompile, and the one
+// validators accept as an off-chain message when listed on their chain config
+func NewAddressedCallMessage(
+	networkID uint32,
+	sourceBlockchainID ids.ID,
+	sourceAddress []byte,
+	payload []byte,
+) (*warp.UnsignedMessage, error) {
+	addressedCall, err := warpPayload.NewAddressedCall(sourceAddress, payload)
+	if err != nil {
+		return nil, err
+	}
+	return warp.NewUnsignedMessage(networkID, sourceBlockchainID, addressedCall.Bytes())
+}
+
+// SendWarpMessage asks the warp precompile at [rpcURL] to emit an addressed call
+// with [payload], coming from the address of [privateKey]
+func SendWarpMessage(
+	rpcURL string,
+	privateKey string,
+	payload []byte,
+) (*types.Transaction, *types.Receipt, error) {
+	return contract.TxToMethod(
+		rpcURL,
+		privateKey,
+		subnetEvmWarp.Module.Address,
+		nil,
+		"send warp message",
+		nil,
+		"sendWarpMessage(bytes)->(bytes32)",
+		payload,
+	)
+}
+
+// GetWarpMessageFromReceipt returns the unsigned warp message emitted by the warp
+// precompile on the tx of [receipt]
+func GetWarpMessageFromReceipt(receipt *types.Receipt) (*warp.UnsignedMessage, error) {
+	for _, txLog := range receipt.Logs {
+		if txLog.Address != subnetEvmWarp.Module.Address {
+			continue
+		}
+		msg, err := subnetEvmWarp.UnpackSendWarpEventDataToMessage(txLog.Data)
+		if err == nil {
+			return msg, nil
+		}
+	}
+	return nil, fmt.Errorf("no warp message found on receipt for tx %s", receipt.TxHash)
+}
+
+// DeliverWarpMessage submits [signedMessage] to [contractAddress] at [rpcURL], calling
+// [methodSpec] with the index of the message in the tx predicates
+func DeliverWarpMessage(
+	rpcURL string,
+	privateKey string,
+	contractAddress common.Address,
+	signedMessage *warp.Message,
+	methodSpec string,
+) (*types.Transaction, *types.Receipt, error) {
+	if methodSpec == "" {
+		methodSpec = DefaultWarpReceiverMethod
+	}
+	return contract.TxToMethodWithWarpMessage(
+		rpcURL,
+		privateKey,
+		contractAddress,
+		signedMessage,
+		nil,
+		"deliver warp message",
+		nil,
+		methodSpec,
+		uint32(0),
+	)
+}
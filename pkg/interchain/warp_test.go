@@ -0,0 +1,50 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpPayload "github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/subnet-evm/core/types"
+	subnetEvmWarp "github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAddressedCallMessage(t *testing.T) {
+	require := require.New(t)
+	blockchainID := ids.GenerateTestID()
+	sourceAddress := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	msg, err := NewAddressedCallMessage(5, blockchainID, sourceAddress.Bytes(), []byte("hello"))
+	require.NoError(err)
+	parsed, err := warp.ParseUnsignedMessage(msg.Bytes())
+	require.NoError(err)
+	require.Equal(uint32(5), parsed.NetworkID)
+	require.Equal(blockchainID, parsed.SourceChainID)
+	addressedCall, err := warpPayload.ParseAddressedCall(parsed.Payload)
+	require.NoError(err)
+	require.Equal(sourceAddress.Bytes(), addressedCall.SourceAddress)
+	require.Equal([]byte("hello"), addressedCall.Payload)
+}
+
+func TestGetWarpMessageFromReceipt(t *testing.T) {
+	require := require.New(t)
+	msg, err := NewAddressedCallMessage(5, ids.GenerateTestID(), nil, []byte("hello"))
+	require.NoError(err)
+	topics, data, err := subnetEvmWarp.PackSendWarpMessageEvent(common.Address{}, common.Hash(msg.ID()), msg.Bytes())
+	require.NoError(err)
+	receipt := &types.Receipt{
+		Logs: []*types.Log{
+			{Address: common.HexToAddress("0x01"), Data: []byte{1, 2, 3}},
+			{Address: subnetEvmWarp.Module.Address, Topics: topics, Data: data},
+		},
+	}
+	got, err := GetWarpMessageFromReceipt(receipt)
+	require.NoError(err)
+	require.Equal(msg.ID(), got.ID())
+	_, err = GetWarpMessageFromReceipt(&types.Receipt{})
+	require.Error(err)
+}
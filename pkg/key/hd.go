@@ -0,0 +1,175 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/tyler-smith/go-bip32"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	// AvalancheCoinType is the BIP-44 coin type of X/P-Chain accounts
+	AvalancheCoinType = 9000
+	// EVMCoinType is the BIP-44 coin type of C-Chain and EVM accounts
+	EVMCoinType = 60
+
+	mnemonicEntropyBits = 256
+	bip44Purpose        = 44
+
+	// derived key names are <wallet>/<index> for avalanche accounts
+	// and <wallet>/evm/<index> for EVM accounts
+	derivedKeySeparator = "/"
+	derivedKeyEVM       = "evm"
+)
+
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// HDWallet derives deterministic accounts from a single BIP-39 mnemonic
+type HDWallet struct {
+	mnemonic  string
+	masterKey *bip32.Key
+}
+
+// NewHDWallet generates a new 24 words mnemonic and creates the corresponding HDWallet.
+func NewHDWallet() (*HDWallet, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return nil, err
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return nil, err
+	}
+	return NewHDWalletFromMnemonic(mnemonic)
+}
+
+// NewHDWalletFromMnemonic creates the HDWallet of [mnemonic], without passphrase.
+func NewHDWalletFromMnemonic(mnemonic string) (*HDWallet, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMnemonic, err)
+	}
+	masterKey, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &HDWallet{
+		mnemonic:  mnemonic,
+		masterKey: masterKey,
+	}, nil
+}
+
+// LoadHDWallet loads the mnemonic from disk and creates the corresponding HDWallet.
+func LoadHDWallet(walletPath string) (*HDWallet, error) {
+	mnemonicBytes, err := os.ReadFile(walletPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewHDWalletFromMnemonic(string(mnemonicBytes))
+}
+
+func LoadHDWalletOrCreate(walletPath string) (*HDWallet, error) {
+	if utils.FileExists(walletPath) {
+		return LoadHDWallet(walletPath)
+	}
+	w, err := NewHDWallet()
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Save(walletPath); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Returns the mnemonic of the wallet.
+func (w *HDWallet) Mnemonic() string {
+	return w.mnemonic
+}
+
+// Saves the mnemonic to disk.
+func (w *HDWallet) Save(p string) error {
+	return os.WriteFile(p, []byte(w.mnemonic), constants.WriteReadUserOnlyPerms)
+}
+
+// Derive returns the key at BIP-32 [path] from the wallet master key.
+func (w *HDWallet) Derive(networkID uint32, path []uint32) (*SoftKey, error) {
+	k := w.masterKey
+	for _, childIdx := range path {
+		var err error
+		k, err = k.NewChildKey(childIdx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	privKey, err := secp256k1.ToPrivateKey(k.Key)
+	if err != nil {
+		return nil, err
+	}
+	return NewSoft(networkID, WithPrivateKey(privKey))
+}
+
+// DeriveAccount returns the X/P-Chain account [index] of the wallet, at m/44'/9000'/0'/0/[index],
+// as derived by the Avalanche wallets.
+func (w *HDWallet) DeriveAccount(networkID uint32, index uint32) (*SoftKey, error) {
+	return w.Derive(networkID, AccountPath(AvalancheCoinType, index))
+}
+
+// DeriveEVMAccount returns the EVM account [index] of the wallet, at m/44'/60'/0'/0/[index],
+// as derived by the Ethereum wallets.
+func (w *HDWallet) DeriveEVMAccount(networkID uint32, index uint32) (*SoftKey, error) {
+	return w.Derive(networkID, AccountPath(EVMCoinType, index))
+}
+
+// AccountPath returns the BIP-44 path m/44'/[coinType]'/0'/0/[index]
+func AccountPath(coinType uint32, index uint32) []uint32 {
+	return []uint32{
+		bip32.FirstHardenedChild + bip44Purpose,
+		bip32.FirstHardenedChild + coinType,
+		bip32.FirstHardenedChild,
+		0,
+		index,
+	}
+}
+
+// DerivedKeyName returns the name under which account [index] of HD wallet [walletName]
+// is referred to
+func DerivedKeyName(walletName string, evm bool, index uint32) string {
+	elems := []string{walletName}
+	if evm {
+		elems = append(elems, derivedKeyEVM)
+	}
+	elems = append(elems, strconv.FormatUint(uint64(index), 10))
+	return strings.Join(elems, derivedKeySeparator)
+}
+
+// ParseDerivedKeyName parses key names of the form <wallet>/<index> and <wallet>/evm/<index>,
+// returning the wallet name, whether the EVM path is used, and the account index. It returns
+// false if [keyName] does not refer to a HD wallet account
+func ParseDerivedKeyName(keyName string) (string, bool, uint32, bool) {
+	elems := strings.Split(keyName, derivedKeySeparator)
+	evm := false
+	switch {
+	case len(elems) == 2:
+	case len(elems) == 3 && elems[1] == derivedKeyEVM:
+		evm = true
+	default:
+		return "", false, 0, false
+	}
+	index, err := strconv.ParseUint(elems[len(elems)-1], 10, 31)
+	if err != nil || elems[0] == "" {
+		return "", false, 0, false
+	}
+	return elems[0], evm, uint32(index), true
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// hardhat/anvil default mnemonic
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestHDWalletDerive(t *testing.T) {
+	t.Parallel()
+
+	w, err := NewHDWalletFromMnemonic(testMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+	} {
+		k, err := w.DeriveEVMAccount(fallbackNetworkID, uint32(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.C() != expected {
+			t.Fatalf("unexpected EVM account %d %s, expected %s", i, k.C(), expected)
+		}
+	}
+	k, err := w.DeriveEVMAccount(fallbackNetworkID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsWellKnownTestKey(k.PrivKeyHex()) {
+		t.Fatalf("unexpected EVM account 0 private key %s", k.PrivKeyHex())
+	}
+
+	k0, err := w.DeriveAccount(fallbackNetworkID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k1, err := w.DeriveAccount(fallbackNetworkID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(k0.PrivKeyRaw(), k1.PrivKeyRaw()) || bytes.Equal(k0.PrivKeyRaw(), k.PrivKeyRaw()) {
+		t.Fatal("expected different keys for different derivation paths")
+	}
+
+	walletPath := filepath.Join(t.TempDir(), "wallet.mnemonic")
+	if err := w.Save(walletPath); err != nil {
+		t.Fatal(err)
+	}
+	w2, err := LoadHDWallet(walletPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := w2.DeriveAccount(fallbackNetworkID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(k1.PrivKeyRaw(), k2.PrivKeyRaw()) {
+		t.Fatalf("loaded wallet derived %v, expected %v", k2.PrivKeyRaw(), k1.PrivKeyRaw())
+	}
+
+	if _, err := NewHDWalletFromMnemonic("test test test"); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrInvalidMnemonic)
+	}
+}
+
+func TestNewHDWallet(t *testing.T) {
+	t.Parallel()
+
+	walletPath := filepath.Join(t.TempDir(), "wallet.mnemonic")
+	w, err := LoadHDWalletOrCreate(walletPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w2, err := LoadHDWalletOrCreate(walletPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Mnemonic() != w2.Mnemonic() {
+		t.Fatal("expected the stored wallet to be loaded")
+	}
+}
+
+func TestParseDerivedKeyName(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		keyName string
+		wallet  string
+		evm     bool
+		index   uint32
+		ok      bool
+	}{
+		{keyName: "suite/7", wallet: "suite", index: 7, ok: true},
+		{keyName: "suite/evm/250", wallet: "suite", evm: true, index: 250, ok: true},
+		{keyName: "suite"},
+		{keyName: "/7"},
+		{keyName: "suite/x"},
+		{keyName: "suite/-1"},
+		{keyName: "suite/2147483648"},
+		{keyName: "suite/eth/1"},
+	}
+	for _, tv := range tt {
+		wallet, evm, index, ok := ParseDerivedKeyName(tv.keyName)
+		if wallet != tv.wallet || evm != tv.evm || index != tv.index || ok != tv.ok {
+			t.Fatalf("%s: unexpected (%q, %v, %d, %v)", tv.keyName, wallet, evm, index, ok)
+		}
+		if ok && DerivedKeyName(wallet, evm, index) != tv.keyName {
+			t.Fatalf("%s: unexpected derived key name %s", tv.keyName, DerivedKeyName(wallet, evm, index))
+		}
+	}
+}
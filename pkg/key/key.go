@@ -80,17 +80,10 @@ func WithFeeDeduct(fee uint64) OpOption {
 	}
 }
 
+// GetHRP returns the address HRP of [networkID], that is the fallback HRP for
+// custom networks such as a local network started with a custom genesis
 func GetHRP(networkID uint32) string {
-	switch networkID {
-	case constants.LocalID:
-		return constants.LocalHRP
-	case constants.FujiID:
-		return constants.FujiHRP
-	case constants.MainnetID:
-		return constants.MainnetHRP
-	default:
-		return constants.FallbackHRP
-	}
+	return constants.GetHRP(networkID)
 }
 
 type innerSortTransferableInputsWithSigners struct {
@@ -0,0 +1,88 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"golang.org/x/exp/slices"
+)
+
+// Tag classifies a stored key by the networks it may be used on
+type Tag string
+
+const (
+	// TagTestOnly keys can not be used for mainnet operations. This is the
+	// default for untagged keys
+	TagTestOnly Tag = "test-only"
+	// TagMainnetApproved keys can be used for mainnet operations
+	TagMainnetApproved Tag = "mainnet-approved"
+)
+
+var ErrInvalidKeyTag = fmt.Errorf("invalid key tag (expected %s or %s)", TagTestOnly, TagMainnetApproved)
+
+// well known test keys, that must never be used on mainnet
+var wellKnownTestKeys = []string{
+	// ewoq
+	string(ewoqKeyBytes),
+	// hardhat/anvil default accounts
+	"ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+	"59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+	"5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a",
+}
+
+func ParseTag(s string) (Tag, error) {
+	tag := Tag(s)
+	if tag != TagTestOnly && tag != TagMainnetApproved {
+		return "", ErrInvalidKeyTag
+	}
+	return tag, nil
+}
+
+// IsWellKnownTestKey returns true if [privKeyHex] is a publicly known test key,
+// such as ewoq
+func IsWellKnownTestKey(privKeyHex string) bool {
+	privKeyHex = strings.ToLower(strings.TrimPrefix(privKeyHex, "0x"))
+	return slices.Contains(wellKnownTestKeys, privKeyHex)
+}
+
+// Tags maps stored key names to their tags
+type Tags map[string]Tag
+
+// LoadTags reads the key tags file at [path]. A missing file means no key is tagged
+func LoadTags(path string) (Tags, error) {
+	tags := Tags{}
+	tagsBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tags, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tagsBytes, &tags); err != nil {
+		return nil, fmt.Errorf("invalid key tags file %s: %w", path, err)
+	}
+	return tags, nil
+}
+
+func (t Tags) Save(path string) error {
+	tagsBytes, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, tagsBytes, constants.WriteReadUserOnlyPerms)
+}
+
+// Get returns the tag of [keyName], defaulting to TagTestOnly
+func (t Tags) Get(keyName string) Tag {
+	if tag, ok := t[keyName]; ok {
+		return tag
+	}
+	return TagTestOnly
+}
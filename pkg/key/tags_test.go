@@ -0,0 +1,71 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+
+	tagsPath := filepath.Join(t.TempDir(), "key-tags.json")
+	tags, err := LoadTags(tagsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag := tags.Get("mykey"); tag != TagTestOnly {
+		t.Fatalf("unexpected tag %q for untagged key, expected %q", tag, TagTestOnly)
+	}
+
+	tag, err := ParseTag("mainnet-approved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags["mykey"] = tag
+	if err := tags.Save(tagsPath); err != nil {
+		t.Fatal(err)
+	}
+	tags, err = LoadTags(tagsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag := tags.Get("mykey"); tag != TagMainnetApproved {
+		t.Fatalf("unexpected tag %q, expected %q", tag, TagMainnetApproved)
+	}
+	if tag := tags.Get("otherkey"); tag != TagTestOnly {
+		t.Fatalf("unexpected tag %q for untagged key, expected %q", tag, TagTestOnly)
+	}
+
+	if _, err := ParseTag("approved"); err != ErrInvalidKeyTag {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrInvalidKeyTag)
+	}
+}
+
+func TestIsWellKnownTestKey(t *testing.T) {
+	t.Parallel()
+
+	ewoq, err := LoadEwoq(fallbackNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, privKeyHex := range []string{
+		ewoq.PrivKeyHex(),
+		"0x" + ewoq.PrivKeyHex(),
+		"0xAC0974BEC39A17E36BA4A6B4D238FF944BACB478CBED5EFCAE784D7BF4F2FF80",
+	} {
+		if !IsWellKnownTestKey(privKeyHex) {
+			t.Fatalf("expected %s to be a well known test key", privKeyHex)
+		}
+	}
+
+	k, err := NewSoft(fallbackNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsWellKnownTestKey(k.PrivKeyHex()) {
+		t.Fatal("unexpected well known test key for a new key")
+	}
+}
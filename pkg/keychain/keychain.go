@@ -5,9 +5,11 @@ package keychain
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/ava-labs/avalanche-cli/cmd/flags"
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
@@ -21,6 +23,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/spf13/cobra"
 )
 
 const (
@@ -31,8 +34,10 @@ const (
 var (
 	ErrMutuallyExlusiveKeySource = errors.New("key source flags --key, --ewoq, --ledger/--ledger-addrs are mutually exclusive")
 	ErrStoredKeyOrEwoqOnMainnet  = errors.New("key sources --key, --ewoq are not available for mainnet operations")
+	ErrTestKeyOnMainnet          = errors.New("well known test keys are not available for mainnet operations")
 	ErrNonEwoqKeyOnDevnet        = errors.New("key source --ewoq is the only one available for devnet operations")
 	ErrEwoqKeyOnFuji             = errors.New("key source --ewoq is not available for fuji operations")
+	ErrCustodyWithKeySource      = errors.New("--custody-addrs is mutually exclusive with key sources --key, --ewoq, --ledger/--ledger-addrs")
 )
 
 type Keychain struct {
@@ -232,6 +237,100 @@ func GetKeychain(
 	return NewKeychain(network, kc, nil, nil), nil
 }
 
+// custodyKeychain holds the P-Chain addresses of keys kept by custodial signers. It has no
+// signers, so the inputs it pays with are left unsigned
+type custodyKeychain struct {
+	addrs set.Set[ids.ShortID]
+}
+
+func (custodyKeychain) Get(ids.ShortID) (keychain.Signer, bool) {
+	return nil, false
+}
+
+func (kc custodyKeychain) Addresses() set.Set[ids.ShortID] {
+	return kc.addrs
+}
+
+// GetCustodyKeychain returns a keychain for the P-Chain [addresses] of keys kept by custodial
+// signers, that do not expose them. Txs paid with it are not signed, and have to be exported
+// for the custodial signers with avalanche transaction export
+func GetCustodyKeychain(network models.Network, addresses []string) (*Keychain, error) {
+	addrs, err := address.ParseToIDs(addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failure parsing custody addresses: %w", err)
+	}
+	ux.Logger.PrintToUser("Paying with custodial addresses %s. The tx will be saved for them to sign", strings.Join(addresses, ", "))
+	return NewKeychain(network, custodyKeychain{addrs: set.Of(addrs...)}, nil, nil), nil
+}
+
+// CheckStoredKeyOnMainnet returns an error if [keyName] can not be used for mainnet
+// operations: well known test keys are always rejected, and other keys need to be
+// tagged as mainnet-approved unless [allowUnapproved] is set.
+// This applies to EVM keys only, P-Chain mainnet operations always require a ledger
+func CheckStoredKeyOnMainnet(app *application.Avalanche, keyName string, allowUnapproved bool) error {
+	if keyName == "ewoq" {
+		return ErrTestKeyOnMainnet
+	}
+	k, err := app.GetKey(keyName, app.GetLocalNetwork(), false)
+	if err != nil {
+		return err
+	}
+	if key.IsWellKnownTestKey(k.PrivKeyHex()) {
+		return fmt.Errorf("key %s: %w", keyName, ErrTestKeyOnMainnet)
+	}
+	tags, err := key.LoadTags(app.GetKeyTagsPath())
+	if err != nil {
+		return err
+	}
+	if tags.Get(keyName) == key.TagMainnetApproved {
+		return nil
+	}
+	if !allowUnapproved {
+		return fmt.Errorf(
+			"key %s is not approved for mainnet operations. Tag it with 'avalanche key tag %s %s' or use --%s",
+			keyName,
+			keyName,
+			key.TagMainnetApproved,
+			constants.AllowUnapprovedKeyFlag,
+		)
+	}
+	ux.Logger.PrintToUser(logging.Yellow.Wrap("Using key %s, not approved for mainnet, on a mainnet operation"), keyName)
+	return nil
+}
+
+// CheckPrivateKeyOnNetwork returns an error if [privateKey] can not be used for
+// operations on [network]. On mainnet, well known test keys are always rejected,
+// and private keys of stored keys are checked as in CheckStoredKeyOnMainnet
+func CheckPrivateKeyOnNetwork(app *application.Avalanche, network models.Network, privateKey string, allowUnapproved bool) error {
+	if network.Kind != models.Mainnet {
+		return nil
+	}
+	if key.IsWellKnownTestKey(privateKey) {
+		return ErrTestKeyOnMainnet
+	}
+	keyNames, err := utils.GetKeyNames(app.GetKeyDir(), false)
+	if err != nil {
+		return err
+	}
+	privateKey = strings.ToLower(strings.TrimPrefix(privateKey, "0x"))
+	for _, keyName := range keyNames {
+		k, err := app.GetKey(keyName, app.GetLocalNetwork(), false)
+		if err != nil {
+			continue
+		}
+		if k.PrivKeyHex() == privateKey {
+			return CheckStoredKeyOnMainnet(app, keyName, allowUnapproved)
+		}
+	}
+	return nil
+}
+
+// AddAllowUnapprovedKeyFlag adds to [cmd] the flag that lets it use on mainnet
+// stored keys not tagged as mainnet-approved
+func AddAllowUnapprovedKeyFlag(cmd *cobra.Command, allowUnapproved *bool) {
+	cmd.Flags().BoolVar(allowUnapproved, constants.AllowUnapprovedKeyFlag, false, "allow using on mainnet a stored key not tagged as mainnet-approved")
+}
+
 func getLedgerIndices(ledgerDevice keychain.Ledger, addressesStr []string) ([]uint32, error) {
 	addresses, err := address.ParseToIDs(addressesStr)
 	if err != nil {
@@ -0,0 +1,52 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+const (
+	ErrorClassNonce             = "nonce"
+	ErrorClassFee               = "fee too low"
+	ErrorClassInsufficientFunds = "insufficient funds"
+	ErrorClassTxPoolFull        = "txpool full"
+	ErrorClassTimeout           = "timeout"
+	ErrorClassRPC               = "rpc unavailable"
+	ErrorClassReverted          = "reverted"
+	ErrorClassReceiptTimeout    = "receipt timeout"
+	ErrorClassOther             = "other"
+)
+
+// errorClassPatterns maps substrings of the errors returned by the node to their class,
+// in matching order
+var errorClassPatterns = []struct {
+	class    string
+	patterns []string
+}{
+	{ErrorClassNonce, []string{"nonce too low", "nonce too high", "already known", "replacement transaction"}},
+	{ErrorClassFee, []string{"underpriced", "fee cap less than block base fee", "less than block base fee", "max fee per gas", "tip higher than"}},
+	{ErrorClassInsufficientFunds, []string{"insufficient funds"}},
+	{ErrorClassTxPoolFull, []string{"txpool is full", "account limit exceeded"}},
+	{ErrorClassTimeout, []string{"timeout", "timed out", "deadline exceeded"}},
+	{ErrorClassRPC, []string{"connection refused", "connection reset", "eof", "too many requests", "429", "503", "no such host"}},
+}
+
+// ClassifyError maps [err], as returned when sending a load tx, into one of the
+// ErrorClass categories, so the load report can show why txs were rejected
+func ClassifyError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassTimeout
+	}
+	msg := strings.ToLower(err.Error())
+	for _, entry := range errorClassPatterns {
+		for _, pattern := range entry.patterns {
+			if strings.Contains(msg, pattern) {
+				return entry.class
+			}
+		}
+	}
+	return ErrorClassOther
+}
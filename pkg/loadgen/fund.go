@@ -0,0 +1,164 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package loadgen
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// feeMargin multiplies the estimated fees funded to each key, so the load can
+// keep going if the base fee rises because of it
+const feeMargin = 2
+
+// fundingBatchSize is the max number of funding txs sent before waiting for them
+const fundingBatchSize = 16
+
+// RequiredBalance is the native balance a key needs to issue [txs] txs of [txType],
+// given [gasFeeCap]
+func RequiredBalance(txType TxType, txs uint64, gasFeeCap *big.Int) *big.Int {
+	perTx := new(big.Int).Mul(gasFeeCap, new(big.Int).SetUint64(GasLimit(txType)*feeMargin))
+	if txType == TransferTx {
+		// transferred value
+		perTx.Add(perTx, big.NewInt(1))
+	}
+	return perTx.Mul(perTx, new(big.Int).SetUint64(txs))
+}
+
+type fundingTx struct {
+	to    common.Address
+	value *big.Int
+	data  []byte
+	gas   uint64
+}
+
+// FundKeys tops up the native balance of [keys] from [funder], so each one can issue
+// [txsPerKey] txs of [txType]. For ERC20 loads, it also tops up the [token] balance
+// of the keys. Keys that already have enough funds, from previous loads, are skipped
+func FundKeys(
+	rpcURL string,
+	client ethclient.Client,
+	funder *ecdsa.PrivateKey,
+	keys []*ecdsa.PrivateKey,
+	txType TxType,
+	token common.Address,
+	txsPerKey uint64,
+) error {
+	gasFeeCap, gasTipCap, nonce, err := evm.CalculateTxParams(client, crypto.PubkeyToAddress(funder.PublicKey).Hex())
+	if err != nil {
+		return err
+	}
+	required := RequiredBalance(txType, txsPerKey, gasFeeCap)
+	txs := []fundingTx{}
+	total := big.NewInt(0)
+	for _, key := range keys {
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		balance, err := evm.GetAddressBalance(client, address.Hex())
+		if err != nil {
+			return err
+		}
+		if balance.Cmp(required) >= 0 {
+			continue
+		}
+		deficit := new(big.Int).Sub(required, balance)
+		total.Add(total, deficit)
+		txs = append(txs, fundingTx{to: address, value: deficit, gas: evm.NativeTransferGas})
+	}
+	if txType == ERC20Tx {
+		requiredTokens := new(big.Int).SetUint64(txsPerKey)
+		for _, key := range keys {
+			address := crypto.PubkeyToAddress(key.PublicKey)
+			balance, err := getTokenBalance(rpcURL, token, address)
+			if err != nil {
+				return err
+			}
+			if balance.Cmp(requiredTokens) >= 0 {
+				continue
+			}
+			deficit := new(big.Int).Sub(requiredTokens, balance)
+			callData, err := contract.EncodeCalldata("transfer(address,uint256)", []string{address.Hex(), deficit.String()})
+			if err != nil {
+				return err
+			}
+			txs = append(txs, fundingTx{to: token, data: callData, gas: erc20TransferGas})
+		}
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+	fundingFees := new(big.Int).Mul(gasFeeCap, new(big.Int).SetUint64(uint64(len(txs))*erc20TransferGas))
+	funderBalance, err := evm.GetAddressBalance(client, crypto.PubkeyToAddress(funder.PublicKey).Hex())
+	if err != nil {
+		return err
+	}
+	if needed := new(big.Int).Add(total, fundingFees); funderBalance.Cmp(needed) < 0 {
+		return fmt.Errorf(
+			"funding key %s has %s but needs %s to fund %d load keys. Use a lower --tps or --duration, or a funding key with more balance",
+			crypto.PubkeyToAddress(funder.PublicKey).Hex(),
+			funderBalance,
+			needed,
+			len(keys),
+		)
+	}
+	ux.Logger.PrintToUser("Funding %d load keys...", len(keys))
+	chainID, err := evm.GetChainID(client)
+	if err != nil {
+		return err
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	// send the funding txs in batches with consecutive nonces, waiting for each batch,
+	// to stay within the per account limits of the mempool
+	for start := 0; start < len(txs); start += fundingBatchSize {
+		batch := txs[start:min(start+fundingBatchSize, len(txs))]
+		signedTxs := make([]*types.Transaction, 0, len(batch))
+		for i, fundingTx := range batch {
+			to := fundingTx.to
+			tx, err := types.SignNewTx(funder, signer, &types.DynamicFeeTx{
+				ChainID:   chainID,
+				Nonce:     nonce + uint64(start+i),
+				To:        &to,
+				Gas:       fundingTx.gas,
+				GasFeeCap: gasFeeCap,
+				GasTipCap: gasTipCap,
+				Value:     fundingTx.value,
+				Data:      fundingTx.data,
+			})
+			if err != nil {
+				return err
+			}
+			if err := evm.SendTransaction(client, tx); err != nil {
+				return err
+			}
+			signedTxs = append(signedTxs, tx)
+		}
+		for _, tx := range signedTxs {
+			if receipt, success, err := evm.WaitForTransaction(client, tx); err != nil {
+				return err
+			} else if !success {
+				return fmt.Errorf("funding tx %s failed with status %d", tx.Hash(), receipt.Status)
+			}
+		}
+	}
+	return nil
+}
+
+func getTokenBalance(rpcURL string, token common.Address, address common.Address) (*big.Int, error) {
+	out, err := contract.CallToMethod(rpcURL, token, "balanceOf(address)->(uint256)", address)
+	if err != nil {
+		return nil, err
+	}
+	balance, ok := out[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("error at balanceOf call, expected *big.Int, got %T", out[0])
+	}
+	return balance, nil
+}
@@ -0,0 +1,282 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package loadgen generates sustained transaction load on EVM chains, from a pool
+// of keys derived from a funding key, and measures its throughput and latency
+package loadgen
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type TxType string
+
+const (
+	TransferTx TxType = "transfer"
+	ERC20Tx    TxType = "erc20"
+	BlobTx     TxType = "blob"
+
+	// gas limit used for ERC20 transfers, above the cost of a standard token transfer
+	erc20TransferGas uint64 = 100_000
+	// time between fee refreshes during the load
+	feeRefreshInterval = 5 * time.Second
+	// time between block height queries, when the endpoint does not support subscriptions
+	blockPollInterval = 250 * time.Millisecond
+	// time between progress reports
+	progressInterval = 10 * time.Second
+)
+
+var (
+	ErrBlobTxsNotSupported = errors.New("blob transactions are not accepted by Subnet-EVM chains, as their blob pool is disabled")
+	ErrInvalidTxType       = errors.New("invalid tx type")
+)
+
+// ParseTxType validates [s] as one of the supported tx types
+func ParseTxType(s string) (TxType, error) {
+	switch TxType(strings.ToLower(s)) {
+	case TransferTx:
+		return TransferTx, nil
+	case ERC20Tx:
+		return ERC20Tx, nil
+	case BlobTx:
+		return "", ErrBlobTxsNotSupported
+	default:
+		return "", fmt.Errorf("%w %q: expected %s or %s", ErrInvalidTxType, s, TransferTx, ERC20Tx)
+	}
+}
+
+// GasLimit is the gas limit of each load tx of type [txType]
+func GasLimit(txType TxType) uint64 {
+	if txType == ERC20Tx {
+		return erc20TransferGas
+	}
+	return evm.NativeTransferGas
+}
+
+// DeriveKeys deterministically derives [n] keys from [seed], so the same pool (and
+// its remaining funds) is reused by all the loads generated with the same funding key
+func DeriveKeys(seed *ecdsa.PrivateKey, n int) ([]*ecdsa.PrivateKey, error) {
+	keys := make([]*ecdsa.PrivateKey, 0, n)
+	seedBytes := crypto.FromECDSA(seed)
+	for i := 0; i < n; i++ {
+		index := binary.BigEndian.AppendUint64(nil, uint64(i))
+		key, err := crypto.ToECDSA(crypto.Keccak256(seedBytes, []byte("avalanche-cli-load"), index))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Config holds the settings of a load run
+type Config struct {
+	TPS            float64
+	Duration       time.Duration
+	TxType         TxType
+	ReceiptTimeout time.Duration
+	// Token is the ERC20 contract used by ERC20Tx loads
+	Token common.Address
+}
+
+// ExpectedTxsPerKey is the number of load txs each one of [numKeys] keys issues
+func (c Config) ExpectedTxsPerKey(numKeys int) uint64 {
+	total := c.TPS * c.Duration.Seconds()
+	return uint64(total/float64(numKeys)) + 1
+}
+
+// loadKey is a key of the pool, with its next nonce. Sends with a key are serialized
+// so its nonces are used in order
+type loadKey struct {
+	lock    sync.Mutex
+	key     *ecdsa.PrivateKey
+	address common.Address
+	nonce   uint64
+}
+
+type generator struct {
+	client  ethclient.Client
+	chainID *big.Int
+	config  Config
+	keys    []*loadKey
+	gasTip  *big.Int
+	gasCap  atomic.Pointer[big.Int]
+	stats   *Stats
+	pending pendingTxs
+}
+
+// Run issues load txs on [client] at the configured rate, during the configured
+// duration, round robin among [keys], and waits for their receipts. Each tx sends
+// the minimum amount (of native token or ERC20) to the next key of the pool.
+// Receipts are fetched per accepted block, instead of per tx
+func Run(ctx context.Context, client ethclient.Client, keys []*ecdsa.PrivateKey, config Config) (*Stats, error) {
+	if config.TPS <= 0 {
+		return nil, fmt.Errorf("invalid tps %f", config.TPS)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no keys to generate load from")
+	}
+	chainID, err := evm.GetChainID(client)
+	if err != nil {
+		return nil, err
+	}
+	gasTip, err := evm.SuggestGasTipCap(client)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{
+		client:  client,
+		chainID: chainID,
+		config:  config,
+		gasTip:  gasTip,
+		stats:   NewStats(),
+		pending: pendingTxs{txs: map[common.Hash]time.Time{}},
+	}
+	if err := g.refreshGasFeeCap(); err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		nonce, err := pendingNonce(ctx, client, address)
+		if err != nil {
+			return nil, err
+		}
+		g.keys = append(g.keys, &loadKey{key: key, address: address, nonce: nonce})
+	}
+	startHeight, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	defer stopWatch()
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		g.watchBlocks(watchCtx, startHeight)
+	}()
+	loadCtx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+	interval := time.Duration(float64(time.Second) / config.TPS)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	feeTicker := time.NewTicker(feeRefreshInterval)
+	defer feeTicker.Stop()
+	progressTicker := time.NewTicker(progressInterval)
+	defer progressTicker.Stop()
+	sends := sync.WaitGroup{}
+	g.stats.Start = time.Now()
+	for i := 0; ; i++ {
+		select {
+		case <-loadCtx.Done():
+			g.stats.IssueEnd = time.Now()
+			sends.Wait()
+			g.waitPending(ctx)
+			stopWatch()
+			<-watchDone
+			g.stats.End = time.Now()
+			return g.stats, nil
+		case <-feeTicker.C:
+			if err := g.refreshGasFeeCap(); err != nil {
+				g.stats.AddError(err)
+			}
+		case <-progressTicker.C:
+			ux.Logger.PrintToUser("%s", g.stats.Progress(time.Now()))
+		case <-ticker.C:
+			from := g.keys[i%len(g.keys)]
+			to := g.keys[(i+1)%len(g.keys)].address
+			sends.Add(1)
+			go func() {
+				defer sends.Done()
+				g.send(ctx, from, to)
+			}()
+		}
+	}
+}
+
+func (g *generator) refreshGasFeeCap() error {
+	baseFee, err := evm.EstimateBaseFee(g.client)
+	if err != nil {
+		return err
+	}
+	gasCap := new(big.Int).Mul(baseFee, big.NewInt(evm.BaseFeeFactor))
+	gasCap.Add(gasCap, big.NewInt(evm.MaxPriorityFeePerGas))
+	g.gasCap.Store(gasCap)
+	return nil
+}
+
+func (g *generator) buildTx(from *loadKey, to common.Address) (*types.Transaction, error) {
+	txData := &types.DynamicFeeTx{
+		ChainID:   g.chainID,
+		Nonce:     from.nonce,
+		Gas:       GasLimit(g.config.TxType),
+		GasFeeCap: g.gasCap.Load(),
+		GasTipCap: g.gasTip,
+	}
+	switch g.config.TxType {
+	case ERC20Tx:
+		callData, err := contract.EncodeCalldata("transfer(address,uint256)", []string{to.Hex(), "1"})
+		if err != nil {
+			return nil, err
+		}
+		txData.To = &g.config.Token
+		txData.Data = callData
+	default:
+		txData.To = &to
+		txData.Value = big.NewInt(1)
+	}
+	return types.SignNewTx(from.key, types.LatestSignerForChainID(g.chainID), txData)
+}
+
+func (g *generator) send(ctx context.Context, from *loadKey, to common.Address) {
+	from.lock.Lock()
+	defer from.lock.Unlock()
+	tx, err := g.buildTx(from, to)
+	if err != nil {
+		g.stats.AddError(err)
+		return
+	}
+	// tracked before sending, as the tx can be accepted before the send returns
+	g.pending.add(tx.Hash(), time.Now())
+	sendCtx, cancel := context.WithTimeout(ctx, g.config.ReceiptTimeout)
+	defer cancel()
+	if err := g.client.SendTransaction(sendCtx, tx); err != nil {
+		g.pending.remove(tx.Hash())
+		g.stats.AddError(err)
+		if ClassifyError(err) == ErrorClassNonce {
+			// resync with the chain view of the key
+			if nonce, err := pendingNonce(ctx, g.client, from.address); err == nil {
+				from.nonce = nonce
+			}
+		}
+		return
+	}
+	from.nonce++
+	g.stats.AddSent()
+}
+
+// pendingNonce returns the next nonce of [address], including the txs on the mempool
+func pendingNonce(ctx context.Context, client ethclient.Client, address common.Address) (uint64, error) {
+	var nonce hexutil.Uint64
+	if err := client.Client().CallContext(ctx, &nonce, "eth_getTransactionCount", address, "pending"); err != nil {
+		return 0, fmt.Errorf("failure obtaining pending nonce for %s: %w", address.Hex(), err)
+	}
+	return uint64(nonce), nil
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTxType(t *testing.T) {
+	txType, err := ParseTxType("transfer")
+	require.NoError(t, err)
+	require.Equal(t, TransferTx, txType)
+	txType, err = ParseTxType("ERC20")
+	require.NoError(t, err)
+	require.Equal(t, ERC20Tx, txType)
+	_, err = ParseTxType("blob")
+	require.ErrorIs(t, err, ErrBlobTxsNotSupported)
+	_, err = ParseTxType("swap")
+	require.ErrorIs(t, err, ErrInvalidTxType)
+}
+
+func TestDeriveKeys(t *testing.T) {
+	seed, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keys, err := DeriveKeys(seed, 5)
+	require.NoError(t, err)
+	require.Len(t, keys, 5)
+	again, err := DeriveKeys(seed, 3)
+	require.NoError(t, err)
+	seen := map[string]bool{}
+	for i, key := range keys {
+		address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+		require.False(t, seen[address])
+		require.NotEqual(t, crypto.PubkeyToAddress(seed.PublicKey).Hex(), address)
+		seen[address] = true
+		if i < len(again) {
+			require.Equal(t, crypto.FromECDSA(key), crypto.FromECDSA(again[i]))
+		}
+	}
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKeys, err := DeriveKeys(other, 1)
+	require.NoError(t, err)
+	require.NotEqual(t, crypto.FromECDSA(keys[0]), crypto.FromECDSA(otherKeys[0]))
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err   error
+		class string
+	}{
+		{errors.New("nonce too low: address 0x1, tx: 3 state: 5"), ErrorClassNonce},
+		{errors.New("transaction underpriced"), ErrorClassFee},
+		{errors.New("max fee per gas less than block base fee"), ErrorClassFee},
+		{errors.New("insufficient funds for gas * price + value"), ErrorClassInsufficientFunds},
+		{errors.New("txpool is full"), ErrorClassTxPoolFull},
+		{fmt.Errorf("send: %w", context.DeadlineExceeded), ErrorClassTimeout},
+		{errors.New("dial tcp 127.0.0.1:9650: connect: connection refused"), ErrorClassRPC},
+		{errors.New("429 Too Many Requests"), ErrorClassRPC},
+		{errors.New("something unexpected"), ErrorClassOther},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.class, ClassifyError(test.err), test.err.Error())
+	}
+}
+
+func TestStats(t *testing.T) {
+	stats := NewStats()
+	stats.Start = time.Now()
+	for i := 1; i <= 100; i++ {
+		stats.AddSent()
+		stats.AddConfirmed(time.Duration(i) * time.Millisecond)
+	}
+	stats.AddError(errors.New("transaction underpriced"))
+	stats.AddError(errors.New("replacement transaction underpriced"))
+	stats.AddErrorClass(ErrorClassReverted)
+	stats.IssueEnd = stats.Start.Add(10 * time.Second)
+	stats.LastConfirmation = stats.Start.Add(20 * time.Second)
+	require.Equal(t, 50*time.Millisecond, stats.Percentile(50))
+	require.Equal(t, 90*time.Millisecond, stats.Percentile(90))
+	require.Equal(t, 99*time.Millisecond, stats.Percentile(99))
+	require.Equal(t, 100*time.Millisecond, stats.Percentile(100))
+	require.InDelta(t, 10, stats.IssuedTPS(), 0.001)
+	require.InDelta(t, 5, stats.ConfirmedTPS(), 0.001)
+	require.Equal(t, 3, stats.Failed())
+	require.Equal(t, 1, stats.Errors[ErrorClassFee])
+	require.Equal(t, 1, stats.Errors[ErrorClassNonce])
+	require.Equal(t, "transaction underpriced", stats.SampleErrors[ErrorClassFee])
+	require.Zero(t, NewStats().Percentile(50))
+}
+
+func TestRequiredBalance(t *testing.T) {
+	gasFeeCap := big.NewInt(10)
+	require.Equal(t, big.NewInt((21_000*2*10+1)*5), RequiredBalance(TransferTx, 5, gasFeeCap))
+	require.Equal(t, big.NewInt(100_000*2*10*5), RequiredBalance(ERC20Tx, 5, gasFeeCap))
+	config := Config{TPS: 10, Duration: time.Minute}
+	require.Equal(t, uint64(201), config.ExpectedTxsPerKey(3))
+}
+
+func TestProcessReceipts(t *testing.T) {
+	g := &generator{
+		config:  Config{ReceiptTimeout: time.Minute},
+		stats:   NewStats(),
+		pending: pendingTxs{txs: map[common.Hash]time.Time{}},
+	}
+	now := time.Now()
+	confirmed, reverted, expired := common.Hash{1}, common.Hash{2}, common.Hash{3}
+	g.pending.add(confirmed, now.Add(-2*time.Second))
+	g.pending.add(reverted, now.Add(-time.Second))
+	g.pending.add(expired, now.Add(-2*time.Minute))
+	g.processReceipts([]*types.Receipt{
+		{TxHash: confirmed, Status: types.ReceiptStatusSuccessful},
+		{TxHash: reverted, Status: types.ReceiptStatusFailed},
+		// not a load tx
+		{TxHash: common.Hash{4}, Status: types.ReceiptStatusSuccessful},
+	}, now)
+	require.Equal(t, 1, g.stats.Confirmed)
+	require.Equal(t, []time.Duration{2 * time.Second}, g.stats.Latencies)
+	require.Equal(t, 1, g.stats.Errors[ErrorClassReverted])
+	require.Equal(t, 1, g.pending.len())
+	g.expirePending()
+	require.Equal(t, 1, g.stats.Errors[ErrorClassReceiptTimeout])
+	require.Zero(t, g.pending.len())
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package loadgen
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// pendingTxs holds the send time of the load txs waiting for their receipts
+type pendingTxs struct {
+	lock sync.Mutex
+	txs  map[common.Hash]time.Time
+}
+
+func (p *pendingTxs) add(txHash common.Hash, sentAt time.Time) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.txs[txHash] = sentAt
+}
+
+func (p *pendingTxs) remove(txHash common.Hash) (time.Time, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	sentAt, ok := p.txs[txHash]
+	delete(p.txs, txHash)
+	return sentAt, ok
+}
+
+func (p *pendingTxs) has(txHash common.Hash) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	_, ok := p.txs[txHash]
+	return ok
+}
+
+func (p *pendingTxs) len() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.txs)
+}
+
+// expire removes the txs sent before [deadline], or all of them if [deadline] is zero,
+// returning how many were removed
+func (p *pendingTxs) expire(deadline time.Time) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	expired := 0
+	for txHash, sentAt := range p.txs {
+		if deadline.IsZero() || sentAt.Before(deadline) {
+			delete(p.txs, txHash)
+			expired++
+		}
+	}
+	return expired
+}
+
+// watchBlocks fetches the receipts of the blocks accepted after [height], until [ctx]
+// is done, and records the outcome of the load txs found in them. New blocks are
+// notified by a head subscription, or polled if the endpoint does not support it
+func (g *generator) watchBlocks(ctx context.Context, height uint64) {
+	heads := make(chan *types.Header)
+	var headsErr <-chan error
+	if sub, err := g.client.SubscribeNewHead(ctx, heads); err == nil {
+		defer sub.Unsubscribe()
+		headsErr = sub.Err()
+	}
+	ticker := time.NewTicker(blockPollInterval)
+	defer ticker.Stop()
+	for {
+		var head uint64
+		select {
+		case <-ctx.Done():
+			return
+		case <-headsErr:
+			// fall back to polling
+			headsErr = nil
+			continue
+		case header := <-heads:
+			head = header.Number.Uint64()
+		case <-ticker.C:
+			g.expirePending()
+			if headsErr != nil {
+				continue
+			}
+			var err error
+			head, err = g.client.BlockNumber(ctx)
+			if err != nil {
+				continue
+			}
+		}
+		for ; height < head; height++ {
+			if err := g.processBlock(ctx, height+1); err != nil {
+				// retried on the next head
+				break
+			}
+		}
+	}
+}
+
+// processBlock records the outcome of the load txs included in block [height]
+func (g *generator) processBlock(ctx context.Context, height uint64) error {
+	seenAt := time.Now()
+	receipts, err := g.client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(height)))
+	if err != nil {
+		// endpoints without eth_getBlockReceipts: fetch the load txs receipts of the block
+		block, err := g.client.BlockByNumber(ctx, new(big.Int).SetUint64(height))
+		if err != nil {
+			return err
+		}
+		receipts = nil
+		for _, tx := range block.Transactions() {
+			if !g.pending.has(tx.Hash()) {
+				continue
+			}
+			receipt, err := g.client.TransactionReceipt(ctx, tx.Hash())
+			if err != nil {
+				return err
+			}
+			receipts = append(receipts, receipt)
+		}
+	}
+	g.processReceipts(receipts, seenAt)
+	return nil
+}
+
+// processReceipts records the outcome of the load txs among [receipts], seen at [seenAt]
+func (g *generator) processReceipts(receipts []*types.Receipt, seenAt time.Time) {
+	for _, receipt := range receipts {
+		sentAt, ok := g.pending.remove(receipt.TxHash)
+		if !ok {
+			continue
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			g.stats.AddErrorClass(ErrorClassReverted)
+			continue
+		}
+		g.stats.AddConfirmed(seenAt.Sub(sentAt))
+	}
+}
+
+// expirePending records as timed out the pending txs sent more than the receipt
+// timeout ago
+func (g *generator) expirePending() {
+	for i := g.pending.expire(time.Now().Add(-g.config.ReceiptTimeout)); i > 0; i-- {
+		g.stats.AddErrorClass(ErrorClassReceiptTimeout)
+	}
+}
+
+// waitPending waits until all the pending txs get their receipts or time out. If [ctx]
+// is done first, the remaining ones are recorded as timed out
+func (g *generator) waitPending(ctx context.Context) {
+	ticker := time.NewTicker(blockPollInterval)
+	defer ticker.Stop()
+	for g.pending.len() > 0 {
+		select {
+		case <-ctx.Done():
+			for i := g.pending.expire(time.Time{}); i > 0; i-- {
+				g.stats.AddErrorClass(ErrorClassReceiptTimeout)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
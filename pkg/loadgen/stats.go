@@ -0,0 +1,162 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package loadgen
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// Stats collects the outcome of the txs of a load run
+type Stats struct {
+	lock  sync.Mutex
+	Start time.Time
+	// IssueEnd is the time the last load tx was issued
+	IssueEnd time.Time
+	// End is the time the last receipt was received, or timed out
+	End       time.Time
+	Sent      int
+	Confirmed int
+	// LastConfirmation is the time the last successful receipt was received
+	LastConfirmation time.Time
+	Latencies        []time.Duration
+	Errors           map[string]int
+	// SampleErrors holds the first error message found for each error class
+	SampleErrors map[string]string
+}
+
+func NewStats() *Stats {
+	return &Stats{
+		Errors:       map[string]int{},
+		SampleErrors: map[string]string{},
+	}
+}
+
+func (s *Stats) AddSent() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Sent++
+}
+
+func (s *Stats) AddConfirmed(latency time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Confirmed++
+	s.LastConfirmation = time.Now()
+	s.Latencies = append(s.Latencies, latency)
+}
+
+func (s *Stats) AddError(err error) {
+	class := ClassifyError(err)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Errors[class]++
+	if _, ok := s.SampleErrors[class]; !ok {
+		s.SampleErrors[class] = err.Error()
+	}
+}
+
+func (s *Stats) AddErrorClass(class string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Errors[class]++
+}
+
+// Failed is the total number of txs that were rejected, reverted, or not confirmed
+func (s *Stats) Failed() int {
+	failed := 0
+	for _, count := range s.Errors {
+		failed += count
+	}
+	return failed
+}
+
+// IssuedTPS is the rate at which txs were accepted by the node during the load
+func (s *Stats) IssuedTPS() float64 {
+	elapsed := s.IssueEnd.Sub(s.Start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Sent) / elapsed
+}
+
+// ConfirmedTPS is the rate at which txs were confirmed, from the load start
+// to the last confirmation
+func (s *Stats) ConfirmedTPS() float64 {
+	elapsed := s.LastConfirmation.Sub(s.Start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Confirmed) / elapsed
+}
+
+// Percentile returns the confirmation latency below which [p] percent of the confirmed
+// txs are found, using the nearest rank method
+func (s *Stats) Percentile(p float64) time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, s.Latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
+
+// Progress summarizes the load state at [now]
+func (s *Stats) Progress(now time.Time) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return fmt.Sprintf(
+		"[%s] sent %d, confirmed %d, failed %d",
+		now.Sub(s.Start).Round(time.Second),
+		s.Sent,
+		s.Confirmed,
+		s.Failed(),
+	)
+}
+
+// Render returns the load report, for a load that targeted [targetTPS]
+func (s *Stats) Render(targetTPS float64) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t := ux.DefaultTable("Load Results", nil)
+	t.AppendRows([]table.Row{
+		{"Duration", s.IssueEnd.Sub(s.Start).Round(time.Second)},
+		{"Target TPS", fmt.Sprintf("%.2f", targetTPS)},
+		{"Issued TPS", fmt.Sprintf("%.2f", s.IssuedTPS())},
+		{"Confirmed TPS", fmt.Sprintf("%.2f", s.ConfirmedTPS())},
+		{"Txs Sent", s.Sent},
+		{"Txs Confirmed", s.Confirmed},
+		{"Txs Failed", s.Failed()},
+	})
+	report := t.Render()
+	if len(s.Latencies) > 0 {
+		t = ux.DefaultTable("Confirmation Latency", table.Row{"p50", "p90", "p99", "Max"})
+		t.AppendRow(table.Row{
+			s.Percentile(50).Round(time.Millisecond),
+			s.Percentile(90).Round(time.Millisecond),
+			s.Percentile(99).Round(time.Millisecond),
+			s.Percentile(100).Round(time.Millisecond),
+		})
+		report += "\n" + t.Render()
+	}
+	if len(s.Errors) > 0 {
+		classes := make([]string, 0, len(s.Errors))
+		for class := range s.Errors {
+			classes = append(classes, class)
+		}
+		sort.Slice(classes, func(i, j int) bool { return s.Errors[classes[i]] > s.Errors[classes[j]] })
+		t = ux.DefaultTable("Errors", table.Row{"Class", "Count", "Sample"})
+		for _, class := range classes {
+			t.AppendRow(table.Row{class, s.Errors[class], s.SampleErrors[class]})
+		}
+		report += "\n" + t.Render()
+	}
+	return report
+}
@@ -0,0 +1,293 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// LocalDNSDomain is the domain of the names given to the local network endpoints
+	LocalDNSDomain    = "local.avax"
+	localDNSIP        = "127.0.0.1"
+	hostsSectionBegin = "# BEGIN avalanche-cli local network names"
+	hostsSectionEnd   = "# END avalanche-cli local network names"
+	// max length of a DNS label
+	maxDNSLabelLen = 63
+	// hex chars of the name hash added to the labels of names that are not valid labels
+	dnsLabelHashLen = 6
+)
+
+var (
+	invalidDNSLabelChars = regexp.MustCompile(`[^a-z0-9-]+`)
+	// ErrLocalDNSNeedsAdmin is returned when new local DNS names can't be registered because
+	// the hosts file is not writable by the current user
+	ErrLocalDNSNeedsAdmin = errors.New("registering the new local DNS names needs admin rights. Run avalanche network dns --enable to register them")
+)
+
+// DefaultHostsFilePath returns the path of the system hosts file
+func DefaultHostsFilePath() string {
+	if runtime.GOOS == "windows" {
+		return `C:\Windows\System32\drivers\etc\hosts`
+	}
+	return "/etc/hosts"
+}
+
+// GetLocalDNSName returns the local DNS name of [blockchainName] (eg mychain.local.avax),
+// or the one of the local network primary nodes if [blockchainName] is empty.
+// Names that are not valid DNS labels as they are (eg my_chain) get a suffix with a hash
+// of the name, so they don't collide with the ones they are converted to (eg my-chain)
+func GetLocalDNSName(blockchainName string) string {
+	label := strings.Trim(invalidDNSLabelChars.ReplaceAllString(strings.ToLower(blockchainName), "-"), "-")
+	if label != blockchainName || len(label) > maxDNSLabelLen {
+		hash := sha256.Sum256([]byte(blockchainName))
+		suffix := hex.EncodeToString(hash[:])[:dnsLabelHashLen]
+		if len(label) > maxDNSLabelLen-dnsLabelHashLen-1 {
+			label = strings.TrimRight(label[:maxDNSLabelLen-dnsLabelHashLen-1], "-")
+		}
+		if label == "" {
+			label = suffix
+		} else {
+			label += "-" + suffix
+		}
+	}
+	if label == "" {
+		return LocalDNSDomain
+	}
+	return label + "." + LocalDNSDomain
+}
+
+// GetLocalDNSHostsFile returns the hosts file the local DNS names are registered on, or
+// an empty string if local DNS names are not enabled
+func GetLocalDNSHostsFile(app *application.Avalanche) string {
+	if app.Conf == nil {
+		return ""
+	}
+	return app.Conf.GetConfigStringValue(constants.ConfigLocalDNSHostsFileKey)
+}
+
+// GetLocalDNSProxyPort returns the port the local DNS proxy listens on
+func GetLocalDNSProxyPort(app *application.Avalanche) uint16 {
+	if app.Conf != nil && app.Conf.ConfigValueIsSet(constants.ConfigLocalDNSProxyPortKey) {
+		return uint16(app.Conf.GetConfigIntValue(constants.ConfigLocalDNSProxyPortKey))
+	}
+	return constants.LocalDNSProxyPort
+}
+
+// GetLocalDNSURL returns the URL that reaches [endpoint] through the local DNS proxy, at
+// the local DNS name of [blockchainName], or an empty string if local DNS names are not
+// enabled. The RPC endpoint of a blockchain is served at /rpc, so the URL does not change
+// when the blockchain is redeployed or the local network ports change
+func GetLocalDNSURL(app *application.Avalanche, endpoint string, blockchainName string) string {
+	if GetLocalDNSHostsFile(app) == "" {
+		return ""
+	}
+	parsedURL, err := url.Parse(endpoint)
+	if err != nil || parsedURL.Host == "" {
+		return ""
+	}
+	parsedURL.Host = GetLocalDNSName(blockchainName) + ":" + strconv.Itoa(int(GetLocalDNSProxyPort(app)))
+	if blockchainName != "" {
+		parsedURL.Path = localDNSProxyRPCPath
+		if strings.HasPrefix(parsedURL.Scheme, "ws") {
+			parsedURL.Path = localDNSProxyWSPath
+		}
+	}
+	return parsedURL.String()
+}
+
+// GetLocalDNSNames returns the local DNS names of the local network, mapped to the
+// blockchain they refer to (empty for the primary nodes)
+func GetLocalDNSNames(app *application.Avalanche) (map[string]string, error) {
+	names := map[string]string{
+		GetLocalDNSName(""): "",
+	}
+	blockchainNames, err := app.GetBlockchainNamesOnNetwork(app.GetLocalNetwork(), false)
+	if err != nil {
+		return nil, err
+	}
+	for _, blockchainName := range blockchainNames {
+		names[GetLocalDNSName(blockchainName)] = blockchainName
+	}
+	return names, nil
+}
+
+// SyncLocalDNSNames registers on the configured hosts file the local DNS names of the
+// blockchains currently deployed on the local network. The hosts file is only written
+// if the names changed. Does nothing if local DNS names are not enabled
+func SyncLocalDNSNames(app *application.Avalanche) error {
+	hostsFilePath := GetLocalDNSHostsFile(app)
+	if hostsFilePath == "" {
+		return nil
+	}
+	names, err := GetLocalDNSNames(app)
+	if err != nil {
+		return err
+	}
+	registeredNames, err := ReadHostsFileNames(hostsFilePath)
+	if err == nil && sameNames(registeredNames, maps.Keys(names)) {
+		return nil
+	}
+	return WriteHostsFileNames(hostsFilePath, maps.Keys(names))
+}
+
+// RefreshLocalDNS registers the local DNS names of the blockchains deployed on the local
+// network, and starts the local DNS proxy if it is not running. Does nothing if local DNS
+// names are not enabled. As deploying a blockchain doesn't need admin rights, a hosts file
+// that can't be written is reported with [ErrLocalDNSNeedsAdmin]
+func RefreshLocalDNS(app *application.Avalanche) error {
+	if GetLocalDNSHostsFile(app) == "" {
+		return nil
+	}
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := StartLocalDNSProxy(app, execPath); err != nil {
+		return err
+	}
+	if err := SyncLocalDNSNames(app); errors.Is(err, os.ErrPermission) {
+		return ErrLocalDNSNeedsAdmin
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// sameNames returns true if [a] and [b] hold the same names, in any order
+func sameNames(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteHostsFileNames replaces the section of the hosts file at [hostsFilePath] managed by
+// the CLI with entries resolving [names] to localhost. The rest of the file is kept as is.
+// An empty [names] removes the section. The file is replaced atomically, so name resolution
+// never sees a partially written hosts file
+func WriteHostsFileNames(hostsFilePath string, names []string) error {
+	content := ""
+	mode := os.FileMode(constants.WriteReadReadPerms)
+	if fileInfo, err := os.Stat(hostsFilePath); err == nil {
+		mode = fileInfo.Mode().Perm()
+		contentBytes, err := os.ReadFile(hostsFilePath)
+		if err != nil {
+			return err
+		}
+		content = string(contentBytes)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	lines, _ := splitHostsSection(content)
+	if len(names) > 0 {
+		sortedNames := append([]string{}, names...)
+		sort.Strings(sortedNames)
+		lines = append(lines, hostsSectionBegin)
+		for _, name := range sortedNames {
+			lines = append(lines, fmt.Sprintf("%s\t%s", localDNSIP, name))
+		}
+		lines = append(lines, hostsSectionEnd)
+	}
+	newContent := strings.Join(lines, "\n")
+	if newContent != "" {
+		newContent += "\n"
+	}
+	if err := writeFileAtomically(hostsFilePath, []byte(newContent), mode); err != nil {
+		return fmt.Errorf("could not update hosts file %s: %w", hostsFilePath, err)
+	}
+	return nil
+}
+
+// writeFileAtomically writes [content] to a temp file on the dir of [filePath], and
+// renames it to [filePath]
+func writeFileAtomically(filePath string, content []byte, mode os.FileMode) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
+
+// ReadHostsFileNames returns the names registered on the section of the hosts file at
+// [hostsFilePath] managed by the CLI
+func ReadHostsFileNames(hostsFilePath string) ([]string, error) {
+	contentBytes, err := os.ReadFile(hostsFilePath)
+	if err != nil {
+		return nil, err
+	}
+	_, sectionLines := splitHostsSection(string(contentBytes))
+	names := []string{}
+	for _, line := range sectionLines {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			names = append(names, fields[1:]...)
+		}
+	}
+	return names, nil
+}
+
+// splitHostsSection splits the lines of the hosts file [content] into the ones outside
+// and the ones inside the section managed by the CLI
+func splitHostsSection(content string) ([]string, []string) {
+	outside := []string{}
+	inside := []string{}
+	inSection := false
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		switch {
+		case strings.TrimSpace(line) == hostsSectionBegin:
+			inSection = true
+		case strings.TrimSpace(line) == hostsSectionEnd:
+			inSection = false
+		case inSection:
+			inside = append(inside, line)
+		default:
+			outside = append(outside, line)
+		}
+	}
+	// drop the empty line of an empty file
+	if len(outside) == 1 && outside[0] == "" {
+		outside = nil
+	}
+	return outside, inside
+}
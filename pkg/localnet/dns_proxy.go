@@ -0,0 +1,247 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+// The local DNS proxy listens on a fixed localhost port, and forwards each request to the
+// current endpoint of the local network blockchain named on its Host header. So the local
+// DNS URLs keep working when the blockchains are redeployed or the node ports change.
+
+const (
+	// paths the proxy serves the RPC and websocket endpoints of a blockchain at
+	localDNSProxyRPCPath = "/rpc"
+	localDNSProxyWSPath  = "/ws"
+)
+
+type localDNSProxyRunFile struct {
+	Pid int `json:"pid"`
+}
+
+// errUnknownLocalDNSName is returned for requests to names not registered by the CLI
+var errUnknownLocalDNSName = errors.New("not a local DNS name of the local network")
+
+// NewLocalDNSProxy returns the handler of the local DNS proxy. The blockchain is looked up
+// on each request, so blockchains deployed after the proxy is started are also served
+func NewLocalDNSProxy(app *application.Avalanche) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target, err := GetLocalDNSTarget(app, r.Host, r.URL.Path)
+		if errors.Is(err, errUnknownLocalDNSName) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		proxy := &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.Out.URL.Scheme = target.Scheme
+				pr.Out.URL.Host = target.Host
+				pr.Out.URL.Path = target.Path
+				pr.Out.URL.RawPath = ""
+				pr.Out.Host = ""
+			},
+		}
+		proxy.ServeHTTP(w, r)
+	})
+}
+
+// GetLocalDNSTarget returns the URL the local DNS proxy forwards a request for [path] on
+// [host] to. For blockchains, /rpc and /ws go to the blockchain RPC and websocket endpoints,
+// and any other path to the node serving them. For the primary nodes, the path is kept
+func GetLocalDNSTarget(app *application.Avalanche, host string, path string) (*url.URL, error) {
+	name := host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		name = hostname
+	}
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	names, err := GetLocalDNSNames(app)
+	if err != nil {
+		return nil, err
+	}
+	blockchainName, ok := names[name]
+	if !ok {
+		return nil, fmt.Errorf("%s is %w", name, errUnknownLocalDNSName)
+	}
+	if blockchainName == "" {
+		endpoint, err := GetEndpoint()
+		if err != nil {
+			return nil, fmt.Errorf("local network is not running: %w", err)
+		}
+		target, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		target.Path = path
+		return target, nil
+	}
+	sc, err := app.LoadSidecar(blockchainName)
+	if err != nil {
+		return nil, err
+	}
+	networkData := sc.Networks[app.GetLocalNetwork().Name()]
+	if len(networkData.RPCEndpoints) == 0 {
+		return nil, fmt.Errorf("blockchain %s is not deployed on the local network", blockchainName)
+	}
+	target, err := url.Parse(networkData.RPCEndpoints[0])
+	if err != nil {
+		return nil, err
+	}
+	switch path {
+	case localDNSProxyRPCPath, localDNSProxyRPCPath + "/":
+	case localDNSProxyWSPath, localDNSProxyWSPath + "/":
+		if len(networkData.WSEndpoints) == 0 {
+			return nil, fmt.Errorf("blockchain %s has no websocket endpoint on the local network", blockchainName)
+		}
+		wsTarget, err := url.Parse(networkData.WSEndpoints[0])
+		if err != nil {
+			return nil, err
+		}
+		// the websocket upgrade is done by the reverse proxy over http
+		target.Path = wsTarget.Path
+	default:
+		target.Path = path
+	}
+	return target, nil
+}
+
+// ServeLocalDNSProxy serves the local DNS proxy until [ctx] is done
+func ServeLocalDNSProxy(ctx context.Context, app *application.Avalanche) error {
+	port := GetLocalDNSProxyPort(app)
+	listener, err := net.Listen("tcp", net.JoinHostPort(localDNSIP, strconv.Itoa(int(port))))
+	if err != nil {
+		if utils.IsPortInUseError(err) {
+			return fmt.Errorf("%w\n%s", err, utils.PortConflictsReport([]utils.PortConflict{utils.NewPortConflict("local DNS proxy", port)}))
+		}
+		return err
+	}
+	server := &http.Server{
+		Handler:           NewLocalDNSProxy(app),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// StartLocalDNSProxy starts the local DNS proxy in background, running the CLI binary at
+// [execPath], unless it is already running. Its output is appended to a log file on the
+// run dir
+func StartLocalDNSProxy(app *application.Avalanche, execPath string) error {
+	proc, err := GetLocalDNSProxyProcess(app)
+	if err != nil {
+		return err
+	}
+	if proc != nil {
+		return nil
+	}
+	if err := os.MkdirAll(app.GetRunDir(), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	logPath := filepath.Join(app.GetRunDir(), constants.LocalDNSProxyLogFileName)
+	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.WriteReadReadPerms)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	cmd := exec.Command(execPath, "network", "dns", "--serve-proxy", "--"+constants.SkipUpdateFlag)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	select {
+	case err := <-exited:
+		return fmt.Errorf("local DNS proxy failed on startup: %v. check its output at %s", err, logPath)
+	case <-time.After(constants.LocalDNSProxyStartupTimeout):
+	}
+	return nil
+}
+
+// StopLocalDNSProxy stops the local DNS proxy, if running
+func StopLocalDNSProxy(app *application.Avalanche) error {
+	proc, err := GetLocalDNSProxyProcess(app)
+	if err != nil || proc == nil {
+		return err
+	}
+	if err := proc.Signal(syscall.SIGTERM); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	return RemoveLocalDNSProxyPID(app)
+}
+
+// SaveLocalDNSProxyPID records [pid] as the local DNS proxy process
+func SaveLocalDNSProxyPID(app *application.Avalanche, pid int) error {
+	bs, err := json.Marshal(&localDNSProxyRunFile{Pid: pid})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(app.GetRunDir(), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(app.GetRunDir(), constants.LocalDNSProxyRunFileName), bs, constants.WriteReadReadPerms)
+}
+
+// RemoveLocalDNSProxyPID removes the local DNS proxy process record
+func RemoveLocalDNSProxyPID(app *application.Avalanche) error {
+	err := os.Remove(filepath.Join(app.GetRunDir(), constants.LocalDNSProxyRunFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// GetLocalDNSProxyProcess returns the local DNS proxy process, or nil if it is not running
+func GetLocalDNSProxyProcess(app *application.Avalanche) (*os.Process, error) {
+	bs, err := os.ReadFile(filepath.Join(app.GetRunDir(), constants.LocalDNSProxyRunFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rf := localDNSProxyRunFile{}
+	if err := json.Unmarshal(bs, &rf); err != nil {
+		return nil, err
+	}
+	proc, err := os.FindProcess(rf.Pid)
+	if err != nil {
+		return nil, nil
+	}
+	// FindProcess always succeeds on unix. signal 0 fails if the process does not exist
+	if err := proc.Signal(syscall.Signal(0)); err != nil {
+		// stale record left by a proxy not stopped gracefully (eg: on reboot)
+		return nil, RemoveLocalDNSProxyPID(app)
+	}
+	return proc, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLocalDNSName(t *testing.T) {
+	require := require.New(t)
+	require.Equal("mychain.local.avax", GetLocalDNSName("mychain"))
+	require.Equal("my-chain.local.avax", GetLocalDNSName("my-chain"))
+	require.Equal("local.avax", GetLocalDNSName(""))
+	// names that are not valid labels get a hash suffix, so they don't collide
+	require.Regexp(`^my-chain-[0-9a-f]{6}\.local\.avax$`, GetLocalDNSName("my_chain"))
+	require.Regexp(`^mychain-[0-9a-f]{6}\.local\.avax$`, GetLocalDNSName("myChain"))
+	require.NotEqual(GetLocalDNSName("my_chain"), GetLocalDNSName("my.chain"))
+	longName := GetLocalDNSName(strings.Repeat("a", 70))
+	require.Len(strings.TrimSuffix(longName, "."+LocalDNSDomain), maxDNSLabelLen)
+}
+
+func TestWriteHostsFileNames(t *testing.T) {
+	require := require.New(t)
+	hostsFilePath := filepath.Join(t.TempDir(), "hosts")
+	original := "127.0.0.1\tlocalhost\n::1\tlocalhost\n"
+	require.NoError(os.WriteFile(hostsFilePath, []byte(original), 0o644))
+
+	require.NoError(WriteHostsFileNames(hostsFilePath, []string{"mychain.local.avax", "local.avax"}))
+	content, err := os.ReadFile(hostsFilePath)
+	require.NoError(err)
+	require.Equal(
+		original+hostsSectionBegin+"\n127.0.0.1\tlocal.avax\n127.0.0.1\tmychain.local.avax\n"+hostsSectionEnd+"\n",
+		string(content),
+	)
+	names, err := ReadHostsFileNames(hostsFilePath)
+	require.NoError(err)
+	require.Equal([]string{"local.avax", "mychain.local.avax"}, names)
+
+	// the section is replaced, not appended
+	require.NoError(WriteHostsFileNames(hostsFilePath, []string{"local.avax"}))
+	names, err = ReadHostsFileNames(hostsFilePath)
+	require.NoError(err)
+	require.Equal([]string{"local.avax"}, names)
+
+	require.NoError(WriteHostsFileNames(hostsFilePath, nil))
+	content, err = os.ReadFile(hostsFilePath)
+	require.NoError(err)
+	require.Equal(original, string(content))
+
+	// the file is replaced keeping its mode, and no temp files are left behind
+	fileInfo, err := os.Stat(hostsFilePath)
+	require.NoError(err)
+	require.Equal(os.FileMode(0o644), fileInfo.Mode().Perm())
+	entries, err := os.ReadDir(filepath.Dir(hostsFilePath))
+	require.NoError(err)
+	require.Len(entries, 1)
+}
+
+func TestLocalDNSProxy(t *testing.T) {
+	require := require.New(t)
+	paths := make(chan string, 1)
+	node := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+	}))
+	defer node.Close()
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil, nil)
+	blockchainID := ids.GenerateTestID()
+	require.NoError(app.CreateSidecar(&models.Sidecar{
+		Name: "my_chain",
+		Networks: map[string]models.NetworkData{
+			app.GetLocalNetwork().Name(): {
+				BlockchainID: blockchainID,
+				RPCEndpoints: []string{models.GetRPCEndpoint(node.URL, blockchainID.String())},
+				WSEndpoints:  []string{models.GetWSEndpoint(node.URL, blockchainID.String())},
+			},
+		},
+	}))
+	proxy := httptest.NewServer(NewLocalDNSProxy(app))
+	defer proxy.Close()
+
+	get := func(host string, path string) int {
+		req, err := http.NewRequest(http.MethodGet, proxy.URL+path, nil)
+		require.NoError(err)
+		req.Host = host
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	name := GetLocalDNSName("my_chain")
+	require.Equal(http.StatusOK, get(name+":9080", "/rpc"))
+	require.Equal("/ext/bc/"+blockchainID.String()+"/rpc", <-paths)
+	require.Equal(http.StatusOK, get(name, "/ws"))
+	require.Equal("/ext/bc/"+blockchainID.String()+"/ws", <-paths)
+	require.Equal(http.StatusOK, get(strings.ToUpper(name), "/ext/health"))
+	require.Equal("/ext/health", <-paths)
+	require.Equal(http.StatusNotFound, get("other.local.avax", "/rpc"))
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnet
+
+import (
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-network-runner/local"
+	anrutils "github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// GetNodeIDs returns the node IDs of a new local network of [numNodes] nodes. Only
+// those nodes can be initial stakers of a custom primary network genesis
+func GetNodeIDs(numNodes uint32) ([]ids.NodeID, error) {
+	networkConfig, err := local.NewDefaultConfigNNodes("", numNodes, constants.LocalNetworkID, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	nodeIDs := []ids.NodeID{}
+	for _, nodeConfig := range networkConfig.NodeConfigs {
+		nodeID, err := anrutils.ToNodeID([]byte(nodeConfig.StakingKey), []byte(nodeConfig.StakingCert))
+		if err != nil {
+			return nil, err
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return nodeIDs, nil
+}
+
+// LoadCustomGenesis loads the custom primary network genesis at [genesisPath],
+// checking it can be used to start a local network of [numNodes] nodes
+func LoadCustomGenesis(genesisPath string, numNodes uint32) (*genesis.Config, error) {
+	nodeIDs, err := GetNodeIDs(numNodes)
+	if err != nil {
+		return nil, err
+	}
+	return utils.LoadPrimaryNetworkGenesis(genesisPath, nodeIDs)
+}
+
+// SaveCustomGenesis records the custom primary network genesis at [genesisPath]
+// as the one the local network was started with
+func SaveCustomGenesis(app *application.Avalanche, genesisPath string) error {
+	genesisBytes, err := os.ReadFile(genesisPath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(app.GetLocalNetworkGenesisPath(), genesisBytes, constants.WriteReadReadPerms)
+}
+
+// RemoveCustomGenesis records that the local network uses the default genesis
+func RemoveCustomGenesis(app *application.Avalanche) error {
+	if err := os.Remove(app.GetLocalNetworkGenesisPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// FundsDefaultKey checks if the custom primary network genesis [genesisConfig] allocates
+// funds to the ewoq key, that local flows use by default to pay fees
+func FundsDefaultKey(genesisConfig *genesis.Config) bool {
+	ewoqAddr := genesis.EWOQKey.Address()
+	for _, allocation := range genesisConfig.Allocations {
+		if allocation.AVAXAddr == ewoqAddr {
+			return true
+		}
+	}
+	return false
+}
@@ -10,7 +10,6 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
-	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-network-runner/rpcpb"
@@ -72,12 +71,15 @@ func PrintSubnetEndpoints(
 	blockchainIDURL := fmt.Sprintf("%s/ext/bc/%s/rpc", (*nodeInfo).GetUri(), chainInfo.ChainId)
 	sc, err := app.LoadSidecar(chainInfo.ChainName)
 	if err == nil {
-		rpcEndpoints := sc.Networks[models.NewLocalNetwork().Name()].RPCEndpoints
+		rpcEndpoints := sc.Networks[app.GetLocalNetwork().Name()].RPCEndpoints
 		if len(rpcEndpoints) > 0 {
 			blockchainIDURL = rpcEndpoints[0]
 		}
 	}
 	t.AppendRow(table.Row{"Localhost", blockchainIDURL})
+	if localDNSURL := GetLocalDNSURL(app, blockchainIDURL, chainInfo.ChainName); localDNSURL != "" {
+		t.AppendRow(table.Row{"Local DNS", localDNSURL})
+	}
 	if utils.InsideCodespace() {
 		var err error
 		blockchainIDURL, err = utils.GetCodespaceURL(blockchainIDURL)
@@ -0,0 +1,124 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	sdkutils "github.com/ava-labs/avalanche-cli/sdk/utils"
+	"github.com/ava-labs/avalanchego/config"
+)
+
+// GetLocalNetworkPorts returns the API and staking ports the local network nodes are
+// going to use, mapped to a description of the node port. If the snapshot at
+// [snapshotPath] exists the ports are taken from it, otherwise they are the
+// ones a new network of [numNodes] nodes gets by default
+func GetLocalNetworkPorts(snapshotPath string, numNodes uint32) (map[uint16]string, error) {
+	ports := map[uint16]string{}
+	if !sdkutils.DirExists(snapshotPath) {
+		port := uint16(constants.AvalancheGoAPIPort)
+		for i := uint32(1); i <= numNodes; i++ {
+			ports[port] = fmt.Sprintf("node%d API", i)
+			ports[port+1] = fmt.Sprintf("node%d staking", i)
+			port += 2
+		}
+		return ports, nil
+	}
+	networkConfigPath := filepath.Join(snapshotPath, snapshotNetworkConfigFileName)
+	bs, err := os.ReadFile(networkConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read local network config file %s: %w", networkConfigPath, err)
+	}
+	networkConfig := struct {
+		NodeConfigs []struct {
+			Name       string                 `json:"name"`
+			Flags      map[string]interface{} `json:"flags"`
+			ConfigFile string                 `json:"configFile"`
+		} `json:"nodeConfigs"`
+	}{}
+	if err := json.Unmarshal(bs, &networkConfig); err != nil {
+		return nil, err
+	}
+	for i, nodeConfig := range networkConfig.NodeConfigs {
+		nodeName := nodeConfig.Name
+		if nodeName == "" {
+			nodeName = fmt.Sprintf("node%d", i+1)
+		}
+		configFileFlags := map[string]interface{}{}
+		if nodeConfig.ConfigFile != "" {
+			if err := json.Unmarshal([]byte(nodeConfig.ConfigFile), &configFileFlags); err != nil {
+				return nil, fmt.Errorf("invalid config file of %s: %w", nodeName, err)
+			}
+		}
+		for key, desc := range map[string]string{
+			config.HTTPPortKey:    "API",
+			config.StakingPortKey: "staking",
+		} {
+			port, ok := getPortFlag(nodeConfig.Flags, key)
+			if !ok {
+				port, ok = getPortFlag(configFileFlags, key)
+			}
+			// a zero port is dynamically assigned, so it can't conflict
+			if ok && port != 0 {
+				ports[port] = fmt.Sprintf("%s %s", nodeName, desc)
+			}
+		}
+	}
+	return ports, nil
+}
+
+// getPortFlag gets the port at [key] on [flags], that can be encoded either as a
+// number or as a string
+func getPortFlag(flags map[string]interface{}, key string) (uint16, bool) {
+	switch value := flags[key].(type) {
+	case float64:
+		return uint16(value), true
+	case string:
+		port, err := strconv.ParseUint(value, 10, 16)
+		return uint16(port), err == nil
+	}
+	return 0, false
+}
+
+// CheckLocalNetworkPorts returns the conflicts found for the ports the local network
+// nodes started from [snapshotPath] are going to use
+func CheckLocalNetworkPorts(snapshotPath string, numNodes uint32) ([]utils.PortConflict, error) {
+	ports, err := GetLocalNetworkPorts(snapshotPath, numNodes)
+	if err != nil {
+		return nil, err
+	}
+	return utils.FindPortConflicts(ports), nil
+}
+
+// GetRunningNetworkPorts returns the API and staking ports used by the nodes of the
+// running local network, mapped to a description of the node port. It is empty if
+// the local network is not running
+func GetRunningNetworkPorts() map[uint16]string {
+	ports := map[uint16]string{}
+	clusterInfo, err := GetClusterInfo()
+	if err != nil {
+		return ports
+	}
+	for nodeName, nodeInfo := range clusterInfo.NodeInfos {
+		if uri, err := url.Parse(nodeInfo.Uri); err == nil {
+			if port, err := strconv.ParseUint(uri.Port(), 10, 16); err == nil {
+				ports[uint16(port)] = fmt.Sprintf("%s API", nodeName)
+			}
+		}
+		nodeFlags := map[string]interface{}{}
+		if err := json.Unmarshal(nodeInfo.Config, &nodeFlags); err != nil {
+			continue
+		}
+		if port, ok := getPortFlag(nodeFlags, config.StakingPortKey); ok && port != 0 {
+			ports[port] = fmt.Sprintf("%s staking", nodeName)
+		}
+	}
+	return ports
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLocalNetworkPorts(t *testing.T) {
+	require := require.New(t)
+
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot")
+	ports, err := GetLocalNetworkPorts(snapshotPath, 2)
+	require.NoError(err)
+	require.Equal(map[uint16]string{
+		9650: "node1 API",
+		9651: "node1 staking",
+		9652: "node2 API",
+		9653: "node2 staking",
+	}, ports)
+
+	require.NoError(os.MkdirAll(snapshotPath, 0o755))
+	networkConfig := `{
+  "nodeConfigs": [
+    {"name": "node1", "flags": {"http-port": 9660, "staking-port": 9661}},
+    {"name": "node2", "flags": {"http-port": "9662"}, "configFile": "{\"staking-port\": 9663}"},
+    {"name": "node3", "flags": {"http-port": 0, "staking-port": 0}}
+  ]
+}`
+	require.NoError(os.WriteFile(filepath.Join(snapshotPath, snapshotNetworkConfigFileName), []byte(networkConfig), 0o600))
+	ports, err = GetLocalNetworkPorts(snapshotPath, 2)
+	require.NoError(err)
+	require.Equal(map[uint16]string{
+		9660: "node1 API",
+		9661: "node1 staking",
+		9662: "node2 API",
+		9663: "node2 staking",
+	}, ports)
+}
@@ -0,0 +1,293 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// A persistent local network is kept running by a per-user service of the host
+// service manager, that executes the CLI network supervisor. It is started on
+// boot (or login), and restarted if it fails.
+
+const (
+	SystemdSupervisor = "systemd"
+	LaunchdSupervisor = "launchd"
+
+	supervisorUnitName     = "avalanche-cli-local-network"
+	supervisorLaunchdLabel = "com.avalabs.avalanche-cli.local-network"
+	// seconds to wait before restarting a failed supervisor
+	supervisorRestartSec = 10
+	// seconds given to the supervisor to save the network on stop
+	supervisorStopTimeoutSec = 180
+)
+
+var ErrSupervisorNotSupported = fmt.Errorf("persistent local network is not supported on %s", runtime.GOOS)
+
+// SupervisorStatus describes the service supervising a persistent local network
+type SupervisorStatus struct {
+	// systemd or launchd
+	Kind      string
+	UnitPath  string
+	Installed bool
+	Running   bool
+	// for systemd, whether the user services are started on boot, without the user
+	// having to login
+	StartsOnBoot bool
+}
+
+// InstallSupervisor installs and starts a per-user service that keeps running
+// [execPath] with [args], appending its output to [logPath]
+func InstallSupervisor(execPath string, args []string, logPath string) error {
+	kind, unitPath, err := supervisorUnit()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(unitPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	switch kind {
+	case SystemdSupervisor:
+		if err := os.WriteFile(unitPath, []byte(renderSystemdUnit(execPath, args, logPath)), constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+		if err := runSupervisorCmd("systemctl", "--user", "daemon-reload"); err != nil {
+			return err
+		}
+		return runSupervisorCmd("systemctl", "--user", "enable", "--now", supervisorUnitName)
+	default:
+		plist, err := renderLaunchdPlist(execPath, args, logPath)
+		if err != nil {
+			return err
+		}
+		// reinstalling an already loaded agent needs it to be unloaded first
+		_ = runSupervisorCmd("launchctl", "bootout", launchdServiceTarget())
+		if err := os.WriteFile(unitPath, []byte(plist), constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+		return runSupervisorCmd("launchctl", "bootstrap", launchdDomainTarget(), unitPath)
+	}
+}
+
+// UninstallSupervisor stops the supervisor service, and removes it so it is not
+// started again on boot
+func UninstallSupervisor() error {
+	kind, unitPath, err := supervisorUnit()
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case SystemdSupervisor:
+		if err := runSupervisorCmd("systemctl", "--user", "disable", "--now", supervisorUnitName); err != nil {
+			return err
+		}
+		if err := os.Remove(unitPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return runSupervisorCmd("systemctl", "--user", "daemon-reload")
+	default:
+		if err := runSupervisorCmd("launchctl", "bootout", launchdServiceTarget()); err != nil {
+			return err
+		}
+		if err := os.Remove(unitPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+}
+
+// GetSupervisorStatus returns the state of the supervisor service. On hosts without
+// a supported service manager, the supervisor is reported as not installed
+func GetSupervisorStatus() (SupervisorStatus, error) {
+	kind, unitPath, err := supervisorUnit()
+	if errors.Is(err, ErrSupervisorNotSupported) {
+		return SupervisorStatus{}, nil
+	}
+	if err != nil {
+		return SupervisorStatus{}, err
+	}
+	status := SupervisorStatus{
+		Kind:     kind,
+		UnitPath: unitPath,
+	}
+	if _, err := os.Stat(unitPath); errors.Is(err, os.ErrNotExist) {
+		return status, nil
+	} else if err != nil {
+		return status, err
+	}
+	status.Installed = true
+	switch kind {
+	case SystemdSupervisor:
+		// is-active exits with error for non active units, so only the output is considered
+		out, _ := exec.Command("systemctl", "--user", "is-active", supervisorUnitName).Output()
+		status.Running = strings.TrimSpace(string(out)) == "active"
+		status.StartsOnBoot = systemdUserLingers()
+	default:
+		out, _ := exec.Command("launchctl", "print", launchdServiceTarget()).Output()
+		status.Running = strings.Contains(string(out), "state = running")
+		// launch agents are started on login
+		status.StartsOnBoot = false
+	}
+	return status, nil
+}
+
+// supervisorUnit returns the kind of service manager of the host, and the path of
+// the supervisor unit file for it
+func supervisorUnit() (string, string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+	switch runtime.GOOS {
+	case "linux":
+		if _, err := exec.LookPath("systemctl"); err != nil {
+			return "", "", fmt.Errorf("%w: systemctl not found", ErrSupervisorNotSupported)
+		}
+		return SystemdSupervisor, filepath.Join(home, ".config", "systemd", "user", supervisorUnitName+".service"), nil
+	case "darwin":
+		return LaunchdSupervisor, filepath.Join(home, "Library", "LaunchAgents", supervisorLaunchdLabel+".plist"), nil
+	default:
+		return "", "", ErrSupervisorNotSupported
+	}
+}
+
+func runSupervisorCmd(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// systemdUserLingers checks if the user services are started on boot
+func systemdUserLingers() bool {
+	u, err := user.Current()
+	if err != nil {
+		return false
+	}
+	out, err := exec.Command("loginctl", "show-user", u.Username, "--property=Linger").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "Linger=yes"
+}
+
+func launchdDomainTarget() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+func launchdServiceTarget() string {
+	return launchdDomainTarget() + "/" + supervisorLaunchdLabel
+}
+
+// renderSystemdUnit returns a systemd user unit that restarts the supervisor if it
+// fails. Only the supervisor process is signaled on stop, so it can shutdown the
+// network gracefully
+func renderSystemdUnit(execPath string, args []string, logPath string) string {
+	cmdLine := []string{systemdQuote(execPath)}
+	for _, arg := range args {
+		cmdLine = append(cmdLine, systemdQuote(arg))
+	}
+	return fmt.Sprintf(`[Unit]
+Description=Avalanche CLI local network
+After=network-online.target
+
+[Service]
+Type=simple
+ExecStart=%s
+Restart=on-failure
+RestartSec=%d
+KillMode=process
+TimeoutStopSec=%d
+StandardOutput=append:%s
+StandardError=append:%s
+
+[Install]
+WantedBy=default.target
+`,
+		strings.Join(cmdLine, " "),
+		supervisorRestartSec,
+		supervisorStopTimeoutSec,
+		logPath,
+		logPath,
+	)
+}
+
+// systemdQuote quotes [s] as a single systemd command line argument
+func systemdQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	// avoid specifier and environment variable expansion
+	s = strings.ReplaceAll(s, "%", "%%")
+	s = strings.ReplaceAll(s, "$", "$$")
+	return `"` + s + `"`
+}
+
+// renderLaunchdPlist returns a launchd agent definition that restarts the supervisor
+// if it fails. Processes started by the supervisor are not killed on stop, so it
+// can shutdown the network gracefully
+func renderLaunchdPlist(execPath string, args []string, logPath string) (string, error) {
+	escape := func(s string) (string, error) {
+		buf := &bytes.Buffer{}
+		if err := xml.EscapeText(buf, []byte(s)); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	programArgs := ""
+	for _, arg := range append([]string{execPath}, args...) {
+		escaped, err := escape(arg)
+		if err != nil {
+			return "", err
+		}
+		programArgs += fmt.Sprintf("\t\t<string>%s</string>\n", escaped)
+	}
+	escapedLogPath, err := escape(logPath)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>ThrottleInterval</key>
+	<integer>%d</integer>
+	<key>ExitTimeOut</key>
+	<integer>%d</integer>
+	<key>AbandonProcessGroup</key>
+	<true/>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`,
+		supervisorLaunchdLabel,
+		programArgs,
+		supervisorRestartSec,
+		supervisorStopTimeoutSec,
+		escapedLogPath,
+		escapedLogPath,
+	), nil
+}
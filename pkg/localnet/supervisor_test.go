@@ -0,0 +1,39 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnet
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSystemdUnit(t *testing.T) {
+	require := require.New(t)
+	unit := renderSystemdUnit("/home/user/bin/avalanche", []string{"network", "supervise", "--avalanchego-path", `/opt/my "avago"/100%$HOME`}, "/tmp/supervisor.log")
+	require.Contains(unit, `ExecStart="/home/user/bin/avalanche" "network" "supervise" "--avalanchego-path" "/opt/my \"avago\"/100%%$$HOME"`+"\n")
+	require.Contains(unit, "Restart=on-failure\n")
+	require.Contains(unit, "KillMode=process\n")
+	require.Contains(unit, "StandardOutput=append:/tmp/supervisor.log\n")
+	require.Contains(unit, "WantedBy=default.target\n")
+}
+
+func TestRenderLaunchdPlist(t *testing.T) {
+	require := require.New(t)
+	plist, err := renderLaunchdPlist("/usr/local/bin/avalanche", []string{"network", "supervise", "--snapshot-name", "a<b&c"}, "/tmp/supervisor.log")
+	require.NoError(err)
+	// must be valid xml
+	decoder := xml.NewDecoder(strings.NewReader(plist))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			require.Equal("EOF", err.Error())
+			break
+		}
+	}
+	require.Contains(plist, "<string>"+supervisorLaunchdLabel+"</string>")
+	require.Contains(plist, "\t\t<string>/usr/local/bin/avalanche</string>\n\t\t<string>network</string>\n\t\t<string>supervise</string>\n\t\t<string>--snapshot-name</string>\n\t\t<string>a&lt;b&amp;c</string>\n")
+	require.Contains(plist, "<key>AbandonProcessGroup</key>\n\t<true/>")
+}
@@ -0,0 +1,202 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"golang.org/x/mod/semver"
+)
+
+const snapshotNetworkConfigFileName = "network.json"
+
+// FlagRename describes an avalanchego config flag renamed to [NewKey] starting from
+// [Version]. Renames can't be told apart from removals by looking at the flags the new
+// binary accepts, so they are listed here to keep the flag values
+type FlagRename struct {
+	Key     string
+	NewKey  string
+	Version string
+}
+
+var flagRenames = []FlagRename{
+	{Key: "snow-virtuous-commit-threshold", NewKey: "snow-commit-threshold", Version: "v1.11.0"},
+	{Key: "snow-rogue-commit-threshold", NewKey: "snow-commit-threshold", Version: "v1.11.0"},
+}
+
+var (
+	// flag names on the avalanchego --help output
+	avalancheGoHelpFlagRegex = regexp.MustCompile(`(?m)^\s+--([a-z0-9][a-z0-9.-]*)`)
+	// version on the avalanchego --version output, ex: avalanchego/1.12.1 [database=v1.4.5, ...]
+	avalancheGoVersionRegex = regexp.MustCompile(`avalanchego/(\d+\.\d+\.\d+)`)
+)
+
+// NodeFlagsTarget is the avalanchego binary config flags are migrated to
+type NodeFlagsTarget struct {
+	Version string          // semantic version of the binary, or empty if unknown
+	Flags   map[string]bool // flags accepted by the binary. If nil, no flag is removed
+}
+
+// GetNodeFlagsTarget obtains the version and the accepted config flags of the avalanchego
+// binary at [binaryPath], by running it with --version and --help
+func GetNodeFlagsTarget(binaryPath string) (NodeFlagsTarget, error) {
+	out, err := exec.Command(binaryPath, "--version").CombinedOutput()
+	if err != nil {
+		return NodeFlagsTarget{}, fmt.Errorf("could not get the version of avalanchego %s: %w: %s", binaryPath, err, out)
+	}
+	target := NodeFlagsTarget{Flags: map[string]bool{}}
+	if matches := avalancheGoVersionRegex.FindStringSubmatch(string(out)); matches != nil {
+		target.Version = "v" + matches[1]
+	}
+	// --help exits with error on older versions
+	out, _ = exec.Command(binaryPath, "--help").CombinedOutput()
+	for _, matches := range avalancheGoHelpFlagRegex.FindAllStringSubmatch(string(out), -1) {
+		target.Flags[matches[1]] = true
+	}
+	if len(target.Flags) == 0 {
+		return NodeFlagsTarget{}, fmt.Errorf("could not get the config flags of avalanchego %s: %s", binaryPath, out)
+	}
+	return target, nil
+}
+
+// MigrateNodeFlags updates [flags] in place to be accepted by the avalanchego [target],
+// returning a description of each change. Renamed flags are moved to their new key,
+// and flags not accepted by the target are dropped. If the target version is unknown,
+// all known renames are applied
+func MigrateNodeFlags(flags map[string]interface{}, target NodeFlagsTarget) []string {
+	changes := []string{}
+	for _, rename := range flagRenames {
+		value, ok := flags[rename.Key]
+		if !ok {
+			continue
+		}
+		if semver.IsValid(target.Version) && semver.Compare(target.Version, rename.Version) < 0 {
+			continue
+		}
+		delete(flags, rename.Key)
+		// an explicit value of the new flag takes precedence
+		if _, ok := flags[rename.NewKey]; !ok {
+			flags[rename.NewKey] = value
+		}
+		changes = append(changes, fmt.Sprintf("renamed %s to %s (since %s)", rename.Key, rename.NewKey, rename.Version))
+	}
+	if target.Flags == nil {
+		return changes
+	}
+	removed := []string{}
+	for key := range flags {
+		if !target.Flags[key] {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		delete(flags, key)
+		if target.Version != "" {
+			changes = append(changes, fmt.Sprintf("removed %s (not supported by avalanchego %s)", key, target.Version))
+		} else {
+			changes = append(changes, fmt.Sprintf("removed %s (not supported by the new avalanchego)", key))
+		}
+	}
+	return changes
+}
+
+// migrateNodeConfig is MigrateNodeFlags for a JSON encoded avalanchego config
+func migrateNodeConfig(config string, target NodeFlagsTarget) (string, []string, error) {
+	if config == "" {
+		return config, nil, nil
+	}
+	flags := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(config), &flags); err != nil {
+		return "", nil, err
+	}
+	changes := MigrateNodeFlags(flags, target)
+	if len(changes) == 0 {
+		return config, nil, nil
+	}
+	bs, err := json.Marshal(flags)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(bs), changes, nil
+}
+
+// MigrateSnapshotNodeFlags updates the network and node flags saved on the local network
+// snapshot at [snapshotPath] to be accepted by the avalanchego [target]. The changes are
+// returned mapped by node name, or by "network" for the flags common to all nodes
+func MigrateSnapshotNodeFlags(snapshotPath string, target NodeFlagsTarget) (map[string][]string, error) {
+	networkConfigPath := filepath.Join(snapshotPath, snapshotNetworkConfigFileName)
+	bs, err := os.ReadFile(networkConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read local network config file %s: %w", networkConfigPath, err)
+	}
+	// generic decoding keeps the fields the migration does not touch as they are
+	networkConfig := map[string]interface{}{}
+	if err := json.Unmarshal(bs, &networkConfig); err != nil {
+		return nil, err
+	}
+	allChanges := map[string][]string{}
+	if flags, ok := networkConfig["flags"].(map[string]interface{}); ok {
+		if changes := MigrateNodeFlags(flags, target); len(changes) > 0 {
+			allChanges["network"] = changes
+		}
+	}
+	nodeConfigs, _ := networkConfig["nodeConfigs"].([]interface{})
+	for i, nodeConfigValue := range nodeConfigs {
+		nodeConfig, ok := nodeConfigValue.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		nodeName, _ := nodeConfig["name"].(string)
+		if nodeName == "" {
+			nodeName = fmt.Sprintf("node%d", i+1)
+		}
+		changes := []string{}
+		if flags, ok := nodeConfig["flags"].(map[string]interface{}); ok {
+			changes = append(changes, MigrateNodeFlags(flags, target)...)
+		}
+		if configFile, ok := nodeConfig["configFile"].(string); ok {
+			newConfigFile, configFileChanges, err := migrateNodeConfig(configFile, target)
+			if err != nil {
+				return nil, fmt.Errorf("invalid config file of %s: %w", nodeName, err)
+			}
+			nodeConfig["configFile"] = newConfigFile
+			changes = append(changes, configFileChanges...)
+		}
+		if len(changes) > 0 {
+			sort.Strings(changes)
+			allChanges[nodeName] = changes
+		}
+	}
+	if len(allChanges) == 0 {
+		return allChanges, nil
+	}
+	bs, err = json.MarshalIndent(networkConfig, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return allChanges, os.WriteFile(networkConfigPath, bs, constants.WriteReadReadPerms)
+}
+
+// GetSnapshotNumNodes returns the number of nodes of the local network snapshot at [snapshotPath]
+func GetSnapshotNumNodes(snapshotPath string) (uint32, error) {
+	networkConfigPath := filepath.Join(snapshotPath, snapshotNetworkConfigFileName)
+	bs, err := os.ReadFile(networkConfigPath)
+	if err != nil {
+		return 0, fmt.Errorf("could not read local network config file %s: %w", networkConfigPath, err)
+	}
+	networkConfig := struct {
+		NodeConfigs []json.RawMessage `json:"nodeConfigs"`
+	}{}
+	if err := json.Unmarshal(bs, &networkConfig); err != nil {
+		return 0, err
+	}
+	return uint32(len(networkConfig.NodeConfigs)), nil
+}
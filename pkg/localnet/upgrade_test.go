@@ -0,0 +1,134 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package localnet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateNodeFlags(t *testing.T) {
+	require := require.New(t)
+
+	acceptedFlags := map[string]bool{"snow-commit-threshold": true, "log-level": true}
+	flags := map[string]interface{}{
+		"snow-virtuous-commit-threshold": 20,
+		"api-auth-required":              true,
+		"log-level":                      "info",
+	}
+	// renames of later versions are not applied
+	changes := MigrateNodeFlags(flags, NodeFlagsTarget{Version: "v1.10.17"})
+	require.Empty(changes)
+
+	changes = MigrateNodeFlags(flags, NodeFlagsTarget{Version: "v1.11.3"})
+	require.Len(changes, 1)
+	require.Equal(map[string]interface{}{
+		"snow-commit-threshold": 20,
+		"api-auth-required":     true,
+		"log-level":             "info",
+	}, flags)
+
+	// flags not accepted by the binary are dropped
+	changes = MigrateNodeFlags(flags, NodeFlagsTarget{Version: "v1.12.0", Flags: acceptedFlags})
+	require.Equal([]string{"removed api-auth-required (not supported by avalanchego v1.12.0)"}, changes)
+	require.Equal(map[string]interface{}{
+		"snow-commit-threshold": 20,
+		"log-level":             "info",
+	}, flags)
+
+	// explicit new flag is kept, and unknown versions get all the renames
+	flags = map[string]interface{}{
+		"snow-rogue-commit-threshold": 30,
+		"snow-commit-threshold":       25,
+	}
+	require.Len(MigrateNodeFlags(flags, NodeFlagsTarget{}), 1)
+	require.Equal(map[string]interface{}{"snow-commit-threshold": 25}, flags)
+}
+
+func TestGetNodeFlagsTarget(t *testing.T) {
+	require := require.New(t)
+	binaryPath := filepath.Join(t.TempDir(), "avalanchego")
+	script := `#!/bin/sh
+if [ "$1" = "--version" ]; then
+  echo "avalanchego/1.12.1 [database=v1.4.5, rpcchainvm=39, go=1.22.8]"
+  exit 0
+fi
+echo "Usage of avalanchego:"
+echo "      --api-admin-enabled                        If true, this node exposes the Admin API"
+echo "      --http-port uint                           Port of the HTTP server (default 9650)"
+echo "      --snow-commit-threshold int                Beta value to use for consensus (default 20)"
+exit 2
+`
+	require.NoError(os.WriteFile(binaryPath, []byte(script), 0o700))
+	target, err := GetNodeFlagsTarget(binaryPath)
+	require.NoError(err)
+	require.Equal("v1.12.1", target.Version)
+	require.Equal(map[string]bool{
+		"api-admin-enabled":     true,
+		"http-port":             true,
+		"snow-commit-threshold": true,
+	}, target.Flags)
+
+	_, err = GetNodeFlagsTarget(filepath.Join(t.TempDir(), "missing"))
+	require.Error(err)
+}
+
+func TestMigrateSnapshotNodeFlags(t *testing.T) {
+	require := require.New(t)
+	snapshotPath := t.TempDir()
+	networkConfigPath := filepath.Join(snapshotPath, snapshotNetworkConfigFileName)
+	networkConfig := map[string]interface{}{
+		"genesis": "{}",
+		"flags": map[string]interface{}{
+			"api-ipcs-enabled": true,
+		},
+		"nodeConfigs": []interface{}{
+			map[string]interface{}{
+				"name":       "node1",
+				"configFile": `{"api-auth-required":false,"http-port":9650}`,
+				"flags":      map[string]interface{}{"ipcs-path": "/tmp"},
+			},
+			map[string]interface{}{
+				"name":       "node2",
+				"configFile": `{"http-port":9652}`,
+			},
+		},
+	}
+	bs, err := json.Marshal(networkConfig)
+	require.NoError(err)
+	require.NoError(os.WriteFile(networkConfigPath, bs, 0o600))
+
+	target := NodeFlagsTarget{
+		Version: "v1.12.1",
+		Flags:   map[string]bool{"http-port": true},
+	}
+	numNodes, err := GetSnapshotNumNodes(snapshotPath)
+	require.NoError(err)
+	require.Equal(uint32(2), numNodes)
+	changes, err := MigrateSnapshotNodeFlags(snapshotPath, target)
+	require.NoError(err)
+	require.Len(changes, 2)
+	require.Len(changes["network"], 1)
+	require.Len(changes["node1"], 2)
+
+	bs, err = os.ReadFile(networkConfigPath)
+	require.NoError(err)
+	migrated := map[string]interface{}{}
+	require.NoError(json.Unmarshal(bs, &migrated))
+	require.Equal("{}", migrated["genesis"])
+	require.Empty(migrated["flags"])
+	node1 := migrated["nodeConfigs"].([]interface{})[0].(map[string]interface{})
+	require.Equal(`{"http-port":9650}`, node1["configFile"])
+	require.Empty(node1["flags"])
+	node2 := migrated["nodeConfigs"].([]interface{})[1].(map[string]interface{})
+	require.Equal(`{"http-port":9652}`, node2["configFile"])
+
+	// nothing left to migrate
+	changes, err = MigrateSnapshotNodeFlags(snapshotPath, target)
+	require.NoError(err)
+	require.Empty(changes)
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"golang.org/x/exp/maps"
+)
+
+// keys of the command log entries, as encoded by the avalanchego json log format
+const (
+	timestampKey  = "timestamp"
+	levelKey      = "level"
+	loggerKey     = "logger"
+	callerKey     = "caller"
+	messageKey    = "msg"
+	stacktraceKey = "stacktrace"
+)
+
+// Entry is a structured log entry of a command invocation
+type Entry struct {
+	Timestamp string
+	Level     string
+	Caller    string
+	Message   string
+	// additional structured fields of the entry
+	Fields map[string]interface{}
+}
+
+// String formats the entry in a single human readable line
+func (e Entry) String() string {
+	if e.Timestamp == "" && e.Level == "" {
+		return e.Message
+	}
+	line := fmt.Sprintf("%s %s %s", e.Timestamp, strings.ToUpper(e.Level), strings.TrimSpace(e.Message))
+	keys := maps.Keys(e.Fields)
+	sort.Strings(keys)
+	for _, key := range keys {
+		line += fmt.Sprintf(" %s=%v", key, e.Fields[key])
+	}
+	return line
+}
+
+// CommandLogFileName returns the name of the log file of an invocation of [commandPath]
+// started at [startTime]. Names sort by start time
+func CommandLogFileName(commandPath string, startTime time.Time) string {
+	command := strings.Join(strings.Fields(commandPath), "-")
+	return startTime.UTC().Format(constants.CommandLogFileTimeFormat) + "_" + command + constants.CommandLogFileNameSuffix
+}
+
+// NewCommandLogFile creates at [dir] the log file of an invocation of [commandPath] started
+// at [startTime], removing the oldest command log files so only the latest
+// constants.MaxNumOfCommandLogFiles are kept
+func NewCommandLogFile(dir string, commandPath string, startTime time.Time) (*os.File, error) {
+	if err := os.MkdirAll(dir, constants.DefaultPerms755); err != nil {
+		return nil, err
+	}
+	logFiles, err := GetCommandLogFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(logFiles) >= constants.MaxNumOfCommandLogFiles {
+		for _, logFile := range logFiles[constants.MaxNumOfCommandLogFiles-1:] {
+			if err := os.Remove(logFile); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return os.OpenFile(
+		filepath.Join(dir, CommandLogFileName(commandPath, startTime)),
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		constants.WriteReadUserOnlyPerms,
+	)
+}
+
+// GetCommandLogFiles returns the paths of the command log files at [dir], from the
+// newest to the oldest
+func GetCommandLogFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	logFiles := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), constants.CommandLogFileNameSuffix) {
+			continue
+		}
+		logFiles = append(logFiles, filepath.Join(dir, entry.Name()))
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(logFiles)))
+	return logFiles, nil
+}
+
+// ReadEntries parses the structured log entries of a command log file. Lines that are
+// not structured entries are kept as messages
+func ReadEntries(r io.Reader) ([]Entry, error) {
+	entries := []Entry{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			entries = append(entries, Entry{Message: line})
+			continue
+		}
+		entry := Entry{
+			Timestamp: popString(fields, timestampKey),
+			Level:     popString(fields, levelKey),
+			Caller:    popString(fields, callerKey),
+			Message:   popString(fields, messageKey),
+		}
+		delete(fields, loggerKey)
+		delete(fields, stacktraceKey)
+		if len(fields) > 0 {
+			entry.Fields = fields
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// popString removes [key] from [fields], returning its value as a string
+func popString(fields map[string]interface{}, key string) string {
+	value, ok := fields[key]
+	if !ok {
+		return ""
+	}
+	delete(fields, key)
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
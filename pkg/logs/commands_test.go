@@ -0,0 +1,72 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandLogFileName(t *testing.T) {
+	require := require.New(t)
+	startTime := time.Date(2024, 11, 5, 10, 30, 15, 120*int(time.Millisecond), time.UTC)
+	require.Equal(
+		"20241105-103015.120_avalanche-blockchain-deploy.json",
+		CommandLogFileName("avalanche blockchain deploy", startTime),
+	)
+}
+
+func TestNewCommandLogFile(t *testing.T) {
+	require := require.New(t)
+	dir := filepath.Join(t.TempDir(), "commands")
+	startTime := time.Date(2024, 11, 5, 10, 30, 15, 0, time.UTC)
+	for i := 0; i < constants.MaxNumOfCommandLogFiles+5; i++ {
+		f, err := NewCommandLogFile(dir, "avalanche network start", startTime.Add(time.Duration(i)*time.Second))
+		require.NoError(err)
+		require.NoError(f.Close())
+	}
+	logFiles, err := GetCommandLogFiles(dir)
+	require.NoError(err)
+	require.Len(logFiles, constants.MaxNumOfCommandLogFiles)
+	// newest first, oldest removed
+	require.Equal(filepath.Join(dir, CommandLogFileName("avalanche network start", startTime.Add(time.Duration(constants.MaxNumOfCommandLogFiles+4)*time.Second))), logFiles[0])
+	require.Equal(filepath.Join(dir, CommandLogFileName("avalanche network start", startTime.Add(5*time.Second))), logFiles[len(logFiles)-1])
+
+	// no command logs yet
+	logFiles, err = GetCommandLogFiles(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(err)
+	require.Empty(logFiles)
+	require.NoError(os.WriteFile(filepath.Join(dir, "notes.txt"), nil, constants.WriteReadUserOnlyPerms))
+	logFiles, err = GetCommandLogFiles(dir)
+	require.NoError(err)
+	require.Len(logFiles, constants.MaxNumOfCommandLogFiles)
+}
+
+func TestReadEntries(t *testing.T) {
+	require := require.New(t)
+	input := strings.Join([]string{
+		`{"level":"info","timestamp":"2024-11-05T10:30:15.120Z","logger":"avalanche","caller":"cmd/root.go:205","msg":"cmd: network start"}`,
+		``,
+		`{"level":"warn","timestamp":"2024-11-05T10:30:16.000Z","caller":"cmd/root.go:333","msg":"failed to get local network ID","error":"not running","attempt":2}`,
+		`panic: unexpected`,
+	}, "\n")
+	entries, err := ReadEntries(strings.NewReader(input))
+	require.NoError(err)
+	require.Len(entries, 3)
+	require.Equal("info", entries[0].Level)
+	require.Equal("cmd: network start", entries[0].Message)
+	require.Equal("cmd/root.go:205", entries[0].Caller)
+	require.Nil(entries[0].Fields)
+	require.Equal(map[string]interface{}{"error": "not running", "attempt": float64(2)}, entries[1].Fields)
+	require.Equal(
+		"2024-11-05T10:30:16.000Z WARN failed to get local network ID attempt=2 error=not running",
+		entries[1].String(),
+	)
+	require.Equal("panic: unexpected", entries[2].Message)
+}
@@ -17,6 +17,7 @@ type RegionConfig struct {
 	SecurityGroupName string
 	NumNodes          int
 	InstanceType      string
+	Arch              string // architecture of the instances (x86_64 or arm64)
 }
 
 type CloudConfig map[string]RegionConfig
@@ -59,3 +60,43 @@ func (ccm *CloudConfig) GetAPIInstanceIDsForRegion(region string) []string {
 	}
 	return []string{}
 }
+
+type CloudResourceType string
+
+const (
+	CloudResourceInstance      CloudResourceType = "instance"
+	CloudResourceStaticIP      CloudResourceType = "static ip"
+	CloudResourceSecurityGroup CloudResourceType = "security group"
+)
+
+// CloudResource is a resource created by the CLI on a cloud service
+type CloudResource struct {
+	Type     CloudResourceType
+	ID       string // instance ID, IP allocation ID, security group ID, etc
+	Name     string
+	Owner    string // normalized OS user that created the resource, empty on resources created by older versions
+	Region   string // region or zone of the resource
+	PublicIP string
+	State    string
+}
+
+// CloudInstanceCost is the approximate monthly cost in USD of a cloud instance, including
+// its disks and public IP
+type CloudInstanceCost struct {
+	InstanceType string
+	DiskSize     int // GB
+	Running      bool
+	Compute      float64
+	Storage      float64
+	PublicIP     float64
+	// false if there is no price information for the instance type, in which case
+	// Compute is zero
+	Known bool
+	// true if the prices are the ones of the instance region, false if they are
+	// approximate prices of a reference region
+	Live bool
+}
+
+func (c CloudInstanceCost) Total() float64 {
+	return c.Compute + c.Storage + c.PublicIP
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import "time"
+
+const (
+	NodeHealthOK              = "OK"
+	NodeHealthUnreachable     = "UNREACHABLE"
+	NodeHealthNotBootstrapped = "NOT_BOOTSTRAPPED"
+	NodeHealthUnhealthy       = "UNHEALTHY"
+)
+
+// NodeHealth is the health of a cluster node at a given time
+type NodeHealth struct {
+	CloudID            string
+	NodeID             string
+	Reachable          bool
+	Bootstrapped       bool
+	Healthy            bool
+	AvalancheGoVersion string `json:",omitempty"`
+	// BlockchainStatus is the P-Chain status of the tracked blockchain on the node, if any
+	BlockchainStatus string `json:",omitempty"`
+	Error            string `json:",omitempty"`
+}
+
+// Status summarizes the node health as one of the NodeHealth* values
+func (h NodeHealth) Status() string {
+	switch {
+	case !h.Reachable:
+		return NodeHealthUnreachable
+	case !h.Bootstrapped:
+		return NodeHealthNotBootstrapped
+	case !h.Healthy:
+		return NodeHealthUnhealthy
+	default:
+		return NodeHealthOK
+	}
+}
+
+// ClusterHealthSnapshot is the health of all the nodes of a cluster at [Time]
+type ClusterHealthSnapshot struct {
+	Time       time.Time
+	Blockchain string `json:",omitempty"`
+	Nodes      []NodeHealth
+}
+
+// ClusterHealthHistory holds the health snapshots of a cluster, oldest first
+type ClusterHealthHistory struct {
+	Snapshots []ClusterHealthSnapshot
+}
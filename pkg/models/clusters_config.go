@@ -12,6 +12,8 @@ import (
 type GCPConfig struct {
 	ProjectName        string // name of GCP Project
 	ServiceAccFilePath string // location of GCP service account key file path
+	// GCP service account to impersonate with the credentials above
+	ImpersonateServiceAccount string
 }
 
 type ExtraNetworkData struct {
@@ -19,6 +21,77 @@ type ExtraNetworkData struct {
 	CChainTeleporterRegistryAddress  string
 }
 
+// SSHConfig holds the settings used to reach hosts by SSH. Empty fields keep the defaults
+type SSHConfig struct {
+	User         string
+	Port         uint
+	ProxyJump    string // bastion host, as [user@]host[:port]. "none" disables it
+	ForwardAgent *bool  // nil keeps the cluster setting on a host, and means disabled on the cluster
+}
+
+// HostNodeConfig holds the AvalancheGo settings of a host overriding the ones shared by all the
+// cluster nodes, for clusters with heterogeneous hardware. Empty fields keep the defaults
+type HostNodeConfig struct {
+	Flags  map[string]interface{} // extra avalanchego config flags, added to the host node config
+	DBDir  string                 // host path of the avalanchego database, eg a dedicated disk mount
+	CPUs   float64                // number of CPUs avalanchego can use
+	Memory string                 // memory avalanchego can use, as a number of bytes with an optional K, M, G or T suffix
+}
+
+// HasResourceLimits returns true if CPU or memory limits are set
+func (hnc HostNodeConfig) HasResourceLimits() bool {
+	return hnc.CPUs != 0 || hnc.Memory != ""
+}
+
+// IsEmpty returns true if no setting is overridden
+func (hnc HostNodeConfig) IsEmpty() bool {
+	return len(hnc.Flags) == 0 && hnc.DBDir == "" && !hnc.HasResourceLimits()
+}
+
+// AlertingConfig holds the alerting settings of the cluster monitoring. Zero thresholds keep the defaults
+type AlertingConfig struct {
+	NodeDownMinutes     uint    // minutes a node must be unreachable before alerting
+	DiskUsagePercent    uint    // disk usage that triggers a disk filling alert
+	ChainStalledMinutes uint    // minutes with blocks processing but none accepted before alerting
+	MinValidatorBalance float64 // L1 validator balance (in AVAX) below which to alert
+	Email               AlertEmailReceiver
+	Slack               AlertSlackReceiver
+}
+
+// AlertEmailReceiver holds the email alerting settings. The SMTP password is a ClusterSecrets field
+type AlertEmailReceiver struct {
+	To           string
+	From         string
+	SMTPHost     string // host:port
+	SMTPUsername string
+}
+
+// AlertSlackReceiver holds the Slack alerting settings. The webhook URL is a ClusterSecrets field
+type AlertSlackReceiver struct {
+	Channel string
+}
+
+// TelemetryConfig holds the settings of the external monitoring backend the cluster nodes export
+// metrics and logs to. The backend credentials are ClusterSecrets fields
+type TelemetryConfig struct {
+	Backend          string // datadog or cloudwatch
+	DatadogSite      string
+	CloudWatchRegion string
+	Dashboard        string // URL of the cluster dashboard on the backend (if created)
+	DashboardID      string // ID (datadog) or name (cloudwatch) of the cluster dashboard
+}
+
+// ClusterSecrets holds the credentials used by a cluster. They are kept apart from the
+// clusters config, in a file only readable by the user
+type ClusterSecrets struct {
+	SMTPPassword    string            `json:",omitempty"`
+	SlackWebhookURL string            `json:",omitempty"`
+	RPCAuthTokens   map[string]string `json:",omitempty"` // maps blockchain ID to the token required by the HTTPS endpoints to serve its RPC
+	DatadogAPIKey   string            `json:",omitempty"`
+	// contents of the AWS credentials file of the cloudwatch agent
+	CloudWatchCredentials string `json:",omitempty"`
+}
+
 type ClusterConfig struct {
 	Nodes              []string
 	APINodes           []string
@@ -30,6 +103,16 @@ type ClusterConfig struct {
 	External           bool
 	Local              bool
 	HTTPAccess         constants.HTTPAccess
+	SSH                SSHConfig                 // SSH settings of all the cluster hosts
+	HostsSSH           map[string]SSHConfig      // maps host cloud ID to SSH settings overriding the cluster ones
+	HostsNodeConfig    map[string]HostNodeConfig // maps host cloud ID to AvalancheGo settings overriding the cluster ones
+	Alerting           AlertingConfig            // alerting settings of the monitoring host (if any)
+	Telemetry          TelemetryConfig           // external monitoring backend the nodes export metrics and logs to (if any)
+	HTTPSEndpoints     map[string]string         // maps host cloud ID to the HTTPS endpoint of its API (if any)
+	HTTPSEmail         string                    // contact email of the Let's Encrypt account of the HTTPS endpoints (if any)
+	HTTPSLoadBalancer  string                    // cloud ID of the host serving the HTTPS endpoint of all the API hosts, load balancing among them (if any)
+	RPCAuthTokens      map[string]string         `json:",omitempty"` // Deprecated: moved to ClusterSecrets. Only read to migrate older configs
+	BlueGreenCluster   string                    // cluster the API traffic was shifted to by node bluegreen, while this one is kept for rollback (if any)
 }
 
 type ClustersConfig struct {
@@ -72,6 +155,34 @@ func (cc *ClusterConfig) GetCloudIDs() []string {
 	return r
 }
 
+// GetHostSSHConfig returns the SSH settings of [hostCloudID], where the host
+// settings override the cluster ones
+func (cc *ClusterConfig) GetHostSSHConfig(hostCloudID string) SSHConfig {
+	sshConfig := cc.SSH
+	hostSSHConfig, ok := cc.HostsSSH[hostCloudID]
+	if !ok {
+		return sshConfig
+	}
+	if hostSSHConfig.User != "" {
+		sshConfig.User = hostSSHConfig.User
+	}
+	if hostSSHConfig.Port != 0 {
+		sshConfig.Port = hostSSHConfig.Port
+	}
+	if hostSSHConfig.ProxyJump != "" {
+		sshConfig.ProxyJump = hostSSHConfig.ProxyJump
+	}
+	if hostSSHConfig.ForwardAgent != nil {
+		sshConfig.ForwardAgent = hostSSHConfig.ForwardAgent
+	}
+	return sshConfig
+}
+
+// GetHostNodeConfig returns the AvalancheGo settings overridden for [hostCloudID]
+func (cc *ClusterConfig) GetHostNodeConfig(hostCloudID string) HostNodeConfig {
+	return cc.HostsNodeConfig[hostCloudID]
+}
+
 func (cc *ClusterConfig) GetHostRoles(nodeConf NodeConfig) []string {
 	roles := []string{}
 	if cc.IsAvalancheGoHost(nodeConf.NodeID) {
@@ -0,0 +1,87 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DeployManifestBlockchain is a blockchain to be deployed as part of a manifest
+type DeployManifestBlockchain struct {
+	Name string `json:"name"`
+	// blockchains that must be deployed before this one
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// DeployManifest lists several blockchain configurations to be deployed together
+type DeployManifest struct {
+	Blockchains []DeployManifestBlockchain `json:"blockchains"`
+}
+
+func LoadDeployManifest(path string) (DeployManifest, error) {
+	manifest := DeployManifest{}
+	manifestBytes, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return manifest, fmt.Errorf("invalid deploy manifest %s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+// DeployOrder returns the manifest blockchain names sorted so that every blockchain
+// comes after the ones it depends on. Blockchains not related by dependencies keep
+// the manifest order
+func (m DeployManifest) DeployOrder() ([]string, error) {
+	dependencies := map[string][]string{}
+	for _, blockchain := range m.Blockchains {
+		if blockchain.Name == "" {
+			return nil, fmt.Errorf("deploy manifest contains a blockchain without name")
+		}
+		if _, ok := dependencies[blockchain.Name]; ok {
+			return nil, fmt.Errorf("blockchain %s is listed more than once in the deploy manifest", blockchain.Name)
+		}
+		dependencies[blockchain.Name] = blockchain.DependsOn
+	}
+	for _, blockchain := range m.Blockchains {
+		for _, dependency := range blockchain.DependsOn {
+			if _, ok := dependencies[dependency]; !ok {
+				return nil, fmt.Errorf("blockchain %s depends on %s, which is not listed in the deploy manifest", blockchain.Name, dependency)
+			}
+		}
+	}
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	order := []string{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle in deploy manifest: %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+		for _, dependency := range dependencies[name] {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+	for _, blockchain := range m.Blockchains {
+		if err := visit(blockchain.Name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployManifestDeployOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		blockchains []DeployManifestBlockchain
+		expected    []string
+		err         string
+	}{
+		{
+			name: "no dependencies keeps manifest order",
+			blockchains: []DeployManifestBlockchain{
+				{Name: "c"},
+				{Name: "a"},
+				{Name: "b"},
+			},
+			expected: []string{"c", "a", "b"},
+		},
+		{
+			name: "dependencies come first",
+			blockchains: []DeployManifestBlockchain{
+				{Name: "game", DependsOn: []string{"dex", "bridge"}},
+				{Name: "dex", DependsOn: []string{"bridge"}},
+				{Name: "bridge"},
+				{Name: "other"},
+			},
+			expected: []string{"bridge", "dex", "game", "other"},
+		},
+		{
+			name: "unknown dependency",
+			blockchains: []DeployManifestBlockchain{
+				{Name: "a", DependsOn: []string{"b"}},
+			},
+			err: "blockchain a depends on b, which is not listed",
+		},
+		{
+			name: "duplicated blockchain",
+			blockchains: []DeployManifestBlockchain{
+				{Name: "a"},
+				{Name: "a"},
+			},
+			err: "blockchain a is listed more than once",
+		},
+		{
+			name: "cycle",
+			blockchains: []DeployManifestBlockchain{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"c"}},
+				{Name: "c", DependsOn: []string{"a"}},
+			},
+			err: "dependency cycle in deploy manifest: a -> b -> c -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := DeployManifest{Blockchains: tt.blockchains}.DeployOrder()
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, order)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+// DevnetKey describes a funded test key of a devnet. Only public
+// information is kept, so devnet configs can be freely shared
+type DevnetKey struct {
+	Name        string
+	PChainAddr  string `json:",omitempty"`
+	CChainAddr  string `json:",omitempty"`
+	Description string `json:",omitempty"`
+}
+
+// DevnetConfig holds the connection descriptor of a named remote devnet
+type DevnetConfig struct {
+	Name      string
+	Endpoint  string
+	NetworkID uint32
+	Keys      []DevnetKey `json:",omitempty"`
+}
+
+// Network returns the network described by the devnet config
+func (dc DevnetConfig) Network() Network {
+	return NewDevnetNetwork(dc.Endpoint, dc.NetworkID)
+}
+
+type DevnetsConfig struct {
+	Devnets map[string]DevnetConfig // maps devnet name to devnet config
+}
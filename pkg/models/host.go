@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +21,8 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/melbahja/goph"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/exp/maps"
 )
 
 const (
@@ -34,12 +37,20 @@ type Host struct {
 	SSHUser           string
 	SSHPrivateKeyPath string
 	SSHCommonArgs     string
+	SSHPort           uint   // 0 means the default SSH port
+	SSHProxyJump      string // bastion host to connect through, as [user@]host[:port]
+	SSHForwardAgent   bool
+	Region            string // cloud region of the host, if known
 	Connection        *goph.Client
+	// ansible variables of the host not managed by the CLI, kept when the inventory is rewritten
+	InventoryVars map[string]string
+	// connection to the bastion host, if any
+	proxyConnection *ssh.Client
 }
 
 func NewHostConnection(h *Host, port uint) (*goph.Client, error) {
 	if port == 0 {
-		port = constants.SSHTCPPort
+		port = h.GetSSHPort()
 	}
 	var (
 		auth goph.Auth
@@ -54,7 +65,7 @@ func NewHostConnection(h *Host, port uint) (*goph.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	cl, err := goph.NewConn(&goph.Config{
+	config := &goph.Config{
 		User:    h.SSHUser,
 		Addr:    h.IP,
 		Port:    port,
@@ -62,11 +73,159 @@ func NewHostConnection(h *Host, port uint) (*goph.Client, error) {
 		Timeout: sshConnectionTimeout,
 		// #nosec G106
 		Callback: ssh.InsecureIgnoreHostKey(), // we don't verify host key ( similar to ansible)
-	})
+	}
+	var cl *goph.Client
+	if h.SSHProxyJump == "" {
+		sshClient, err := dialSSH(net.JoinHostPort(config.Addr, fmt.Sprint(config.Port)), sshClientConfig(config, config.User))
+		if err != nil {
+			return nil, err
+		}
+		cl = &goph.Client{
+			Client: sshClient,
+			Config: config,
+		}
+	} else {
+		cl, err = h.newProxiedConnection(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if h.SSHForwardAgent {
+		if !utils.IsSSHAgentAvailable() {
+			_ = cl.Close()
+			return nil, fmt.Errorf("SSH agent forwarding is enabled for host %s, but SSH agent is not available", h.IP)
+		}
+		if err := agent.ForwardToRemote(cl.Client, os.Getenv("SSH_AUTH_SOCK")); err != nil {
+			_ = cl.Close()
+			return nil, err
+		}
+	}
+	return cl, nil
+}
+
+// newProxiedConnection connects to the host through its bastion host, using
+// the same authentication for both
+func (h *Host) newProxiedConnection(config *goph.Config) (*goph.Client, error) {
+	proxyUser, proxyAddr := parseProxyJump(h.SSHProxyJump, h.SSHUser)
+	proxyClient, err := dialSSH(proxyAddr, sshClientConfig(config, proxyUser))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to bastion host %s: %w", proxyAddr, err)
+	}
+	addr := net.JoinHostPort(config.Addr, fmt.Sprint(config.Port))
+	conn, err := proxyClient.Dial("tcp", addr)
+	if err != nil {
+		_ = proxyClient.Close()
+		return nil, fmt.Errorf("failed to reach %s from bastion host %s: %w", addr, proxyAddr, err)
+	}
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, addr, sshClientConfig(config, config.User))
 	if err != nil {
+		_ = proxyClient.Close()
 		return nil, err
 	}
-	return cl, nil
+	h.proxyConnection = proxyClient
+	return &goph.Client{
+		Client: ssh.NewClient(clientConn, chans, reqs),
+		Config: config,
+	}, nil
+}
+
+// sshClientConfig returns the ssh client config to connect as [user] with the
+// settings of [config]
+func sshClientConfig(config *goph.Config, user string) *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            config.Auth,
+		Timeout:         config.Timeout,
+		HostKeyCallback: config.Callback,
+	}
+}
+
+// dialSSH opens an SSH connection to [addr], through the configured SSH proxy if any
+func dialSSH(addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	conn, err := utils.DialTCP(addr, config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(clientConn, chans, reqs), nil
+}
+
+// parseProxyJump returns the user and address of bastion host [proxyJump], given as
+// [user@]host[:port]. [defaultUser] is used if no user is given
+func parseProxyJump(proxyJump string, defaultUser string) (string, string) {
+	user := defaultUser
+	if i := strings.LastIndex(proxyJump, "@"); i >= 0 {
+		user = proxyJump[:i]
+		proxyJump = proxyJump[i+1:]
+	}
+	if _, _, err := net.SplitHostPort(proxyJump); err != nil {
+		proxyJump = net.JoinHostPort(proxyJump, fmt.Sprint(constants.SSHTCPPort))
+	}
+	return user, proxyJump
+}
+
+// GetSSHPort returns the port the host SSH server listens on
+func (h *Host) GetSSHPort() uint {
+	if h.SSHPort == 0 {
+		return constants.SSHTCPPort
+	}
+	return h.SSHPort
+}
+
+// SetSSHConfig sets the SSH settings of the host. Empty fields set the defaults
+func (h *Host) SetSSHConfig(sshConfig SSHConfig) {
+	h.SSHUser = sshConfig.User
+	if h.SSHUser == "" {
+		h.SSHUser = constants.AnsibleSSHUser
+	}
+	h.SSHPort = sshConfig.Port
+	h.SSHProxyJump = sshConfig.ProxyJump
+	if h.SSHProxyJump == "none" {
+		h.SSHProxyJump = ""
+	}
+	h.SSHForwardAgent = sshConfig.ForwardAgent != nil && *sshConfig.ForwardAgent
+}
+
+// GetSSHOptions returns the ssh/scp command line options for the host SSH settings
+func (h *Host) GetSSHOptions() string {
+	options := []string{}
+	if h.SSHPort != 0 {
+		options = append(options, fmt.Sprintf("-o Port=%d", h.SSHPort))
+	}
+	if h.SSHProxyJump != "" {
+		options = append(options, fmt.Sprintf("-o ProxyJump=%s", h.SSHProxyJump))
+	} else if proxyCommand := utils.GetSSHProxyCommand(net.JoinHostPort(h.IP, fmt.Sprint(h.GetSSHPort()))); proxyCommand != "" {
+		options = append(options, proxyCommand)
+	}
+	if h.SSHForwardAgent {
+		options = append(options, "-o ForwardAgent=yes")
+	}
+	return strings.Join(options, " ")
+}
+
+// GetSSHConnectionString returns the ssh command to open a shell on the host
+func (h *Host) GetSSHConnectionString() string {
+	sshCmd := fmt.Sprintf("ssh %s", constants.AnsibleSSHShellParams)
+	if options := h.GetSSHOptions(); options != "" {
+		sshCmd += " " + options
+	}
+	sshCmd += fmt.Sprintf(" %s@%s", h.SSHUser, h.IP)
+	if h.SSHPrivateKeyPath != "" {
+		sshCmd += fmt.Sprintf(" -i %s", h.SSHPrivateKeyPath)
+	}
+	return sshCmd
+}
+
+// requestAgentForwarding forwards the local SSH agent to [session], if enabled for the host
+func (h *Host) requestAgentForwarding(session *ssh.Session) error {
+	if !h.SSHForwardAgent {
+		return nil
+	}
+	return agent.RequestAgentForwarding(session)
 }
 
 // GetCloudID returns the node ID of the host.
@@ -78,7 +237,7 @@ func (h *Host) GetCloudID() string {
 // Connect starts a new SSH connection with the provided private key.
 func (h *Host) Connect(port uint) error {
 	if port == 0 {
-		port = constants.SSHTCPPort
+		port = h.GetSSHPort()
 	}
 	if h.Connection != nil {
 		return nil
@@ -102,6 +261,12 @@ func (h *Host) Disconnect() error {
 		return nil
 	}
 	err := h.Connection.Close()
+	if h.proxyConnection != nil {
+		if proxyErr := h.proxyConnection.Close(); err == nil {
+			err = proxyErr
+		}
+		h.proxyConnection = nil
+	}
 	return err
 }
 
@@ -240,6 +405,9 @@ func (h *Host) Command(script string, env []string, timeout time.Duration) ([]by
 	if err != nil {
 		return nil, err
 	}
+	if err := h.requestAgentForwarding(cmd.Session); err != nil {
+		return nil, err
+	}
 	if env != nil {
 		cmd.Env = env
 	}
@@ -405,13 +573,35 @@ func (h *Host) Remove(path string, recursive bool) error {
 }
 
 func (h *Host) GetAnsibleInventoryRecord() string {
-	return strings.Join([]string{
+	record := []string{
 		h.NodeID,
 		fmt.Sprintf("ansible_host=%s", h.IP),
 		fmt.Sprintf("ansible_user=%s", h.SSHUser),
 		fmt.Sprintf("ansible_ssh_private_key_file=%s", h.SSHPrivateKeyPath),
 		fmt.Sprintf("ansible_ssh_common_args='%s'", h.SSHCommonArgs),
-	}, " ")
+	}
+	if h.SSHPort != 0 {
+		record = append(record, fmt.Sprintf("ansible_port=%d", h.SSHPort))
+	}
+	if h.SSHProxyJump != "" {
+		record = append(record, fmt.Sprintf("ssh_proxy_jump=%s", h.SSHProxyJump))
+	}
+	if h.SSHForwardAgent {
+		record = append(record, "ssh_forward_agent=true")
+	}
+	if h.Region != "" {
+		record = append(record, fmt.Sprintf("region=%s", h.Region))
+	}
+	varNames := maps.Keys(h.InventoryVars)
+	sort.Strings(varNames)
+	for _, name := range varNames {
+		value := h.InventoryVars[name]
+		if strings.ContainsAny(value, " \t") {
+			value = "'" + value + "'"
+		}
+		record = append(record, fmt.Sprintf("%s=%s", name, value))
+	}
+	return strings.Join(record, " ")
 }
 
 func HostCloudIDToAnsibleID(cloudService string, hostCloudID string) (string, error) {
@@ -422,6 +612,8 @@ func HostCloudIDToAnsibleID(cloudService string, hostCloudID string) (string, er
 		return fmt.Sprintf("%s_%s", constants.AWSNodeAnsiblePrefix, hostCloudID), nil
 	case constants.E2EDocker:
 		return fmt.Sprintf("%s_%s", constants.E2EDocker, hostCloudID), nil
+	case constants.ImportedCloudService:
+		return fmt.Sprintf("%s_%s", constants.ImportedNodeAnsiblePrefix, hostCloudID), nil
 	}
 	return "", fmt.Errorf("unknown cloud service %s", cloudService)
 }
@@ -439,6 +631,9 @@ func HostAnsibleIDToCloudID(hostAnsibleID string) (string, string, error) {
 	case strings.HasPrefix(hostAnsibleID, constants.E2EDocker):
 		cloudService = constants.E2EDocker
 		cloudIDPrefix = strings.TrimPrefix(hostAnsibleID, constants.E2EDocker+"_")
+	case strings.HasPrefix(hostAnsibleID, constants.ImportedNodeAnsiblePrefix):
+		cloudService = constants.ImportedCloudService
+		cloudIDPrefix = strings.TrimPrefix(hostAnsibleID, constants.ImportedNodeAnsiblePrefix+"_")
 	default:
 		return "", "", fmt.Errorf("unknown cloud service prefix in %s", hostAnsibleID)
 	}
@@ -456,7 +651,8 @@ func (h *Host) WaitForPort(port uint, timeout time.Duration) error {
 		if time.Now().After(deadline) {
 			return fmt.Errorf("timeout: SSH port %d on host %s is not available after %vs", port, h.IP, timeout.Seconds())
 		}
-		if _, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", h.IP, port), time.Second); err == nil {
+		if conn, err := utils.DialTCP(net.JoinHostPort(h.IP, fmt.Sprint(port)), time.Second); err == nil {
+			_ = conn.Close()
 			return nil
 		}
 		time.Sleep(constants.SSHSleepBetweenChecks)
@@ -469,8 +665,11 @@ func (h *Host) WaitForSSHShell(timeout time.Duration) error {
 		return fmt.Errorf("host IP is empty")
 	}
 	start := time.Now()
-	if err := h.WaitForPort(constants.SSHTCPPort, timeout); err != nil {
-		return err
+	// hosts behind a bastion are not directly reachable
+	if h.SSHProxyJump == "" {
+		if err := h.WaitForPort(h.GetSSHPort(), timeout); err != nil {
+			return err
+		}
 	}
 
 	deadline := start.Add(timeout)
@@ -508,6 +707,9 @@ func (h *Host) StreamSSHCommand(command string, env []string, timeout time.Durat
 		return err
 	}
 	defer session.Close()
+	if err := h.requestAgentForwarding(session); err != nil {
+		return err
+	}
 
 	stdout, err := session.StdoutPipe()
 	if err != nil {
@@ -314,3 +314,54 @@ func TestExpandHome(t *testing.T) {
 		t.Errorf("Expected: %s, Got: %s", expected5, result5)
 	}
 }
+
+func TestHostSSHConfig(t *testing.T) {
+	require := require.New(t)
+	enabled, disabled := true, false
+	clusterConfig := ClusterConfig{
+		SSH: SSHConfig{
+			User:      "admin",
+			ProxyJump: "jump@bastion.example.com:2222",
+		},
+		HostsSSH: map[string]SSHConfig{
+			"i-direct": {Port: 2200, ProxyJump: "none", ForwardAgent: &enabled},
+		},
+	}
+
+	host := &Host{IP: "10.0.0.1"}
+	host.SetSSHConfig(clusterConfig.GetHostSSHConfig("i-other"))
+	require.Equal("admin", host.SSHUser)
+	require.Equal(uint(constants.SSHTCPPort), host.GetSSHPort())
+	require.Equal("jump@bastion.example.com:2222", host.SSHProxyJump)
+	require.False(host.SSHForwardAgent)
+	require.Equal("-o ProxyJump=jump@bastion.example.com:2222", host.GetSSHOptions())
+
+	host.SetSSHConfig(clusterConfig.GetHostSSHConfig("i-direct"))
+	require.Equal("admin", host.SSHUser)
+	require.Equal(uint(2200), host.GetSSHPort())
+	require.Empty(host.SSHProxyJump)
+	require.True(host.SSHForwardAgent)
+	require.Equal("-o Port=2200 -o ForwardAgent=yes", host.GetSSHOptions())
+
+	// a host can turn off agent forwarding enabled on the cluster
+	clusterConfig.SSH.ForwardAgent = &enabled
+	clusterConfig.HostsSSH["i-no-agent"] = SSHConfig{ForwardAgent: &disabled}
+	host.SetSSHConfig(clusterConfig.GetHostSSHConfig("i-other"))
+	require.True(host.SSHForwardAgent)
+	host.SetSSHConfig(clusterConfig.GetHostSSHConfig("i-no-agent"))
+	require.False(host.SSHForwardAgent)
+
+	host.SetSSHConfig(SSHConfig{})
+	require.Equal(constants.AnsibleSSHUser, host.SSHUser)
+	require.Empty(host.GetSSHOptions())
+}
+
+func TestParseProxyJump(t *testing.T) {
+	require := require.New(t)
+	user, addr := parseProxyJump("bastion.example.com", "ubuntu")
+	require.Equal("ubuntu", user)
+	require.Equal("bastion.example.com:22", addr)
+	user, addr = parseProxyJump("jump@10.0.0.1:2222", "ubuntu")
+	require.Equal("jump", user)
+	require.Equal("10.0.0.1:2222", addr)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/api/info"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	networkSDK "github.com/ava-labs/avalanche-cli/sdk/network"
 	"github.com/ava-labs/avalanchego/genesis"
 	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
 )
@@ -64,8 +65,30 @@ func (n Network) IsUndefined() bool {
 	return n.Kind == Undefined
 }
 
+// SDKNetwork returns the sdk representation of [n], to be given to the sdk packages
+func (n Network) SDKNetwork() networkSDK.Network {
+	kind := networkSDK.Undefined
+	switch n.Kind {
+	case Mainnet:
+		kind = networkSDK.Mainnet
+	case Fuji:
+		kind = networkSDK.Fuji
+	case Local:
+		kind = networkSDK.Local
+	case Devnet:
+		kind = networkSDK.Devnet
+	}
+	return networkSDK.NewNetwork(kind, n.ID, n.Endpoint)
+}
+
 func NewLocalNetwork() Network {
-	return NewNetwork(Local, constants.LocalNetworkID, constants.LocalAPIEndpoint, "")
+	return NewLocalNetworkWithID(constants.LocalNetworkID)
+}
+
+// NewLocalNetworkWithID returns the local network for a network started with a
+// custom genesis of network ID [id]
+func NewLocalNetworkWithID(id uint32) Network {
+	return NewNetwork(Local, id, constants.LocalAPIEndpoint, "")
 }
 
 func NewDevnetNetwork(endpoint string, id uint32) Network {
@@ -84,8 +107,8 @@ func ConvertClusterToNetwork(clusterNetwork Network) Network {
 		return clusterNetwork
 	}
 	switch {
-	case clusterNetwork.ID == constants.LocalNetworkID:
-		return NewLocalNetwork()
+	case clusterNetwork.Kind == Local || clusterNetwork.ID == constants.LocalNetworkID:
+		return NewLocalNetworkWithID(clusterNetwork.ID)
 	case clusterNetwork.ID == avagoconstants.FujiID:
 		return NewFujiNetwork()
 	case clusterNetwork.ID == avagoconstants.MainnetID:
@@ -169,6 +192,9 @@ func (n Network) BlockchainWSEndpoint(blockchainID string) string {
 	case Mainnet:
 		scheme = wssScheme
 	}
+	if strings.HasPrefix(n.Endpoint, "https://") {
+		scheme = wssScheme
+	}
 	return fmt.Sprintf("%s://%s/ext/bc/%s/ws", scheme, trimmedURI, blockchainID)
 }
 
@@ -230,8 +256,8 @@ func (n *Network) BootstrappingContext() (context.Context, context.CancelFunc) {
 func GetNetworkFromCluster(clusterConfig ClusterConfig) Network {
 	network := clusterConfig.Network
 	switch {
-	case network.ID == constants.LocalNetworkID:
-		return NewLocalNetwork()
+	case network.Kind == Local || network.ID == constants.LocalNetworkID:
+		return NewLocalNetworkWithID(network.ID)
 	case network.ID == avagoconstants.FujiID:
 		return NewFujiNetwork()
 	case network.ID == avagoconstants.MainnetID:
@@ -15,4 +15,5 @@ type NodeConfig struct {
 	IsMonitor     bool   // node has a monitoring dashboard
 	IsICMRelayer  bool   // node has an ICM relayer service
 	IsLoadTest    bool   // node is used to host load test
+	Arch          string // architecture of the cloud server (x86_64 or arm64), used to pick the binaries to install
 }
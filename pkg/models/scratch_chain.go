@@ -0,0 +1,32 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// ScratchChain describes a single node Subnet-EVM chain started with
+// avalanche network scratch-evm, that has no blockchain definition
+type ScratchChain struct {
+	Name               string
+	VMVersion          string
+	AvalancheGoVersion string
+	ChainID            uint64
+	TokenSymbol        string
+	HTTPPort           uint32
+	SubnetID           ids.ID
+	BlockchainID       ids.ID
+}
+
+// Endpoint returns the API endpoint of the scratch chain node
+func (c ScratchChain) Endpoint() string {
+	return fmt.Sprintf("http://127.0.0.1:%d", c.HTTPPort)
+}
+
+// RPCURL returns the EVM RPC endpoint of the scratch chain
+func (c ScratchChain) RPCURL() string {
+	return fmt.Sprintf("%s/ext/bc/%s/rpc", c.Endpoint(), c.BlockchainID)
+}
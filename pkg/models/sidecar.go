@@ -3,8 +3,10 @@
 package models
 
 import (
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-network-runner/utils"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
 )
 
 type NetworkData struct {
@@ -17,6 +19,96 @@ type NetworkData struct {
 	WSEndpoints                []string
 	BootstrapValidators        []SubnetValidator
 	ClusterName                string
+	// address of the validator manager deployed when converting a legacy subnet into
+	// an L1. Empty when the validator manager is the genesis predeployed proxy
+	ValidatorManagerAddress string
+	// ICM messenger releases deployed on the blockchain, by release version. The
+	// default one used by the CLI is TeleporterMessengerAddress
+	ICMMessengers map[string]ICMMessenger
+	// L1 validators removed by the CLI, with the refund of their remaining balance
+	RemovedValidators []RemovedValidator
+	// bootstrap validators left out of the ConvertSubnetToL1Tx, that are registered on the
+	// validator manager afterwards, in batches that fit its churn limit
+	PendingBootstrapValidators []SubnetValidator
+	// ERC20 token staked on the validator manager, for ERC20 token PoS L1s
+	StakingTokenAddress string
+}
+
+// RemovedValidator is an L1 validator removed by the CLI. The P-Chain refunds its remaining
+// balance to the remaining balance owner, as a UTXO of the tx that removed it
+type RemovedValidator struct {
+	NodeID       string
+	ValidationID string
+	// P-Chain tx that set the validator weight to 0, and its output holding the refund
+	RemovalTxID       string
+	RefundOutputIndex uint32
+	// P-Chain addresses the refund was sent to
+	RefundOwners []string
+	Refund       uint64
+	// P-Chain tx that moved the refund to the claim address, if claimed
+	ClaimTxID    string
+	ClaimAddress string
+}
+
+// RefundUTXOID is the P-Chain UTXO the refund was created at
+func (r RemovedValidator) RefundUTXOID() avax.UTXOID {
+	txID, _ := ids.FromString(r.RemovalTxID)
+	return avax.UTXOID{TxID: txID, OutputIndex: r.RefundOutputIndex}
+}
+
+// ICMMessenger is an ICM messenger release deployed on a blockchain
+type ICMMessenger struct {
+	Address string
+	// protocol version of the messenger on the blockchain ICM registry. 0 if not registered
+	RegistryVersion uint64
+}
+
+// SetICMMessenger records that the ICM messenger release [version] is deployed at [address]
+func (nd *NetworkData) SetICMMessenger(version string, address string, registryVersion uint64) {
+	if nd.ICMMessengers == nil {
+		nd.ICMMessengers = map[string]ICMMessenger{}
+	}
+	nd.ICMMessengers[version] = ICMMessenger{
+		Address:         address,
+		RegistryVersion: registryVersion,
+	}
+}
+
+// VMVersionConstraint is the VM version range required by the features a blockchain
+// genesis uses
+type VMVersionConstraint struct {
+	MinVersion string
+	// genesis features requiring a minimum version
+	Features []string
+}
+
+// ImportSignature records the trusted signature a blockchain definition was imported with
+type ImportSignature struct {
+	// id of the key that signed the imported definition
+	KeyID string
+	// sha256 of the imported genesis, to detect later modifications
+	GenesisHash string
+}
+
+// PresetInfo records the registry preset a blockchain was created from
+type PresetInfo struct {
+	Name     string
+	Version  string
+	Registry string
+	// sha256 of the preset file, as verified against the registry index
+	SHA256 string
+}
+
+// DappProject is a foundry or hardhat project configured to deploy to the blockchain
+type DappProject struct {
+	Dir       string
+	Framework string
+	// CLI key written as deployer key of the project, if any
+	KeyName string
+	// foundry keystore account of the deployer, if any
+	KeystoreAccount string
+	// etherscan compatible API to verify contracts with, overriding the default explorer
+	VerifierURL string
 }
 
 type Sidecar struct {
@@ -28,6 +120,7 @@ type Sidecar struct {
 	ExternalToken       bool
 	TokenName           string
 	TokenSymbol         string
+	TokenDecimals       uint8 // 0 means the default of 18
 	ChainID             string
 	Version             string
 	Networks            map[string]NetworkData
@@ -47,8 +140,23 @@ type Sidecar struct {
 	ValidatorManagement   ValidatorManagementType
 	ValidatorManagerOwner string
 	ProxyContractOwner    string
+	// asset staked on the PoS validator manager. Native token when empty
+	StakingToken StakingTokenType
 	// Subnet defaults to Sovereign post ACP-77
 	Sovereign bool
+	// network name -> named upgrade set to be installed on that network
+	// (the default upgrade file is used for networks not present)
+	UpgradeSets map[string]string
+	// VM versions supporting the features used by the genesis
+	VMVersionConstraint VMVersionConstraint
+	// declared maximum total supply of the native token, in token units. 0 means no cap
+	MaxSupply uint64
+	// signature verified when importing the blockchain definition, if any
+	ImportSignature ImportSignature
+	// registry preset used on creation, if any
+	Preset PresetInfo
+	// dapp projects wired with blockchain init-dapp, updated after each deploy
+	DappProjects []DappProject
 }
 
 func (sc Sidecar) GetVMID() (string, error) {
@@ -71,6 +179,14 @@ func (sc Sidecar) NetworkDataIsEmpty(network string) bool {
 	return !networkExists
 }
 
+// GetTokenDecimals returns the decimals used to display and enter native token amounts
+func (sc Sidecar) GetTokenDecimals() uint8 {
+	if sc.TokenDecimals == 0 {
+		return constants.DefaultTokenDecimals
+	}
+	return sc.TokenDecimals
+}
+
 func (sc Sidecar) PoA() bool {
 	return sc.ValidatorManagement == ProofOfAuthority
 }
@@ -78,3 +194,17 @@ func (sc Sidecar) PoA() bool {
 func (sc Sidecar) PoS() bool {
 	return sc.ValidatorManagement == ProofOfStake
 }
+
+// ERC20Staking returns true if validators and delegators stake an ERC20 token,
+// instead of the native token, on the PoS validator manager
+func (sc Sidecar) ERC20Staking() bool {
+	return sc.PoS() && sc.StakingToken == ERC20TokenStaking
+}
+
+// StakedTokenName returns how to refer to the asset staked on the PoS validator manager
+func (sc Sidecar) StakedTokenName() string {
+	if sc.ERC20Staking() {
+		return "ERC20 staking token"
+	}
+	return sc.TokenName
+}
@@ -38,3 +38,23 @@ func TestGetVMID_derived(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal(expectedVMID.String(), vmid)
 }
+
+func TestGetTokenDecimals(t *testing.T) {
+	assert := require.New(t)
+	// sidecars created before decimals were configurable use 18
+	assert.Equal(uint8(18), Sidecar{}.GetTokenDecimals())
+	assert.Equal(uint8(6), Sidecar{TokenDecimals: 6}.GetTokenDecimals())
+}
+
+func TestERC20Staking(t *testing.T) {
+	require := require.New(t)
+	sc := Sidecar{TokenName: "TEST", ValidatorManagement: ProofOfStake}
+	require.False(sc.ERC20Staking())
+	require.Equal("TEST", sc.StakedTokenName())
+	sc.StakingToken = StakingTokenTypeFromString(ERC20TokenStaking)
+	require.True(sc.ERC20Staking())
+	require.Equal("ERC20 staking token", sc.StakedTokenName())
+	sc.ValidatorManagement = ProofOfAuthority
+	require.False(sc.ERC20Staking())
+	require.Equal(StakingTokenType(NativeTokenStaking), StakingTokenTypeFromString(""))
+}
@@ -20,3 +20,19 @@ func ValidatorManagementTypeFromString(s string) ValidatorManagementType {
 		return UndefinedValidatorManagement
 	}
 }
+
+type StakingTokenType string
+
+const (
+	NativeTokenStaking = "Native Token"
+	ERC20TokenStaking  = "ERC20 Token"
+)
+
+func StakingTokenTypeFromString(s string) StakingTokenType {
+	switch s {
+	case ERC20TokenStaking:
+		return ERC20TokenStaking
+	default:
+		return NativeTokenStaking
+	}
+}
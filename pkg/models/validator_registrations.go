@@ -0,0 +1,30 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import "time"
+
+// ValidatorRegistration describes an L1 validator registration initialized on
+// the validator manager, that is not yet completed. The registration message
+// is only accepted by P-Chain before its expiry
+type ValidatorRegistration struct {
+	BlockchainName       string
+	Network              string
+	NodeID               string
+	ValidationID         string
+	Expiry               uint64
+	BLSPublicKey         string
+	BLSProofOfPossession string
+	// Balance is the initial P-Chain balance of the validator, in nAVAX
+	Balance uint64
+	RPCURL  string `json:",omitempty"`
+}
+
+// ExpiryTime returns the time after which P-Chain rejects the registration
+func (r ValidatorRegistration) ExpiryTime() time.Time {
+	return time.Unix(int64(r.Expiry), 0)
+}
+
+type ValidatorRegistrations struct {
+	Registrations map[string]ValidatorRegistration // maps validation id to registration
+}
@@ -0,0 +1,199 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+// job labels set by promtail on the avalanchego logs (see configs/promtail.yml)
+var avalancheGoLogJobs = map[string]string{
+	"c":    "c-chain",
+	"p":    "p-chain",
+	"x":    "x-chain",
+	"main": "main",
+}
+
+const (
+	subnetLogJob   = "subnet"
+	lokiQueryRange = "/loki/api/v1/query_range"
+	// max number of lines Loki returns on a single query by default
+	DefaultLokiPageSize = 1000
+)
+
+// LogLine is a log line of a cluster node, as stored in Loki
+type LogLine struct {
+	Timestamp time.Time
+	// cloud instance ID of the node
+	Host   string
+	NodeID string
+	Job    string
+	Line   string
+}
+
+// LokiQuery selects the cluster log lines to be obtained from Loki
+type LokiQuery struct {
+	// C, P, X, main or empty for all of them. Ignored if ChainID is given
+	Chain string
+	// blockchain ID of a deployed blockchain
+	ChainID string
+	// only lines containing Grep are selected
+	Grep string
+}
+
+// LogQL returns the Loki query expression for [q]
+func (q LokiQuery) LogQL() (string, error) {
+	var selector string
+	switch {
+	case q.ChainID != "":
+		selector = fmt.Sprintf(`{job=%q, filename=%q}`, subnetLogJob, "/logs/"+q.ChainID+".log")
+	case q.Chain != "":
+		job, ok := avalancheGoLogJobs[strings.ToLower(q.Chain)]
+		if !ok {
+			return "", fmt.Errorf("unknown chain %q", q.Chain)
+		}
+		selector = fmt.Sprintf(`{job=%q}`, job)
+	default:
+		jobs := []string{}
+		for _, job := range avalancheGoLogJobs {
+			jobs = append(jobs, job)
+		}
+		sort.Strings(jobs)
+		jobs = append(jobs, subnetLogJob)
+		selector = fmt.Sprintf(`{job=~%q}`, strings.Join(jobs, "|"))
+	}
+	if q.Grep != "" {
+		selector += " |= " + strconv.Quote(q.Grep)
+	}
+	return selector, nil
+}
+
+type lokiQueryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// QueryLoki obtains from the Loki server at [lokiURL] the lines selected by [query]
+// within [start, end), calling [f] with them in timestamp order, merged from all
+// the cluster hosts. Lines are requested in pages of [pageSize]
+func QueryLoki(
+	lokiURL string,
+	query LokiQuery,
+	start time.Time,
+	end time.Time,
+	pageSize int,
+	f func(LogLine) error,
+) error {
+	logQL, err := query.LogQL()
+	if err != nil {
+		return err
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultLokiPageSize
+	}
+	// lines on the page boundary timestamp, already given to [f]
+	seen := map[LogLine]struct{}{}
+	for start.Before(end) {
+		// the next page starts at the last timestamp, as it may contain more lines,
+		// so the limit is extended to skip over the ones already given
+		limit := pageSize + len(seen)
+		lines, err := queryLokiRange(lokiURL, logQL, start, end, limit)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			if _, ok := seen[line]; ok {
+				continue
+			}
+			if err := f(line); err != nil {
+				return err
+			}
+			if line.Timestamp.After(start) {
+				start = line.Timestamp
+				seen = map[LogLine]struct{}{}
+			}
+			seen[line] = struct{}{}
+		}
+		if len(lines) < limit {
+			return nil
+		}
+	}
+	return nil
+}
+
+func queryLokiRange(lokiURL string, logQL string, start time.Time, end time.Time, limit int) ([]LogLine, error) {
+	params := url.Values{}
+	params.Set("query", logQL)
+	params.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(end.UnixNano(), 10))
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("direction", "forward")
+	endpoint := strings.TrimSuffix(lokiURL, "/") + lokiQueryRange
+	resp, err := utils.CallAPI(endpoint, func(ctx context.Context) (*lokiQueryResponse, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		httpResp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer httpResp.Body.Close()
+		body, err := io.ReadAll(httpResp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if httpResp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("loki query failed with status %d: %s", httpResp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		resp := &lokiQueryResponse{}
+		if err := json.Unmarshal(body, resp); err != nil {
+			return nil, fmt.Errorf("invalid loki response: %w", err)
+		}
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("loki query failed with status %q", resp.Status)
+	}
+	lines := []LogLine{}
+	for _, stream := range resp.Data.Result {
+		for _, value := range stream.Values {
+			ns, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid loki timestamp %q: %w", value[0], err)
+			}
+			lines = append(lines, LogLine{
+				Timestamp: time.Unix(0, ns).UTC(),
+				Host:      stream.Stream["host"],
+				NodeID:    stream.Stream["nodeID"],
+				Job:       stream.Stream["job"],
+				Line:      value[1],
+			})
+		}
+	}
+	// Loki returns the lines grouped by stream, that is, by host and file
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Timestamp.Before(lines[j].Timestamp)
+	})
+	return lines, nil
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package monitoring
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLokiQueryLogQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    LokiQuery
+		expected string
+		err      string
+	}{
+		{
+			name:     "all chains",
+			query:    LokiQuery{},
+			expected: `{job=~"c-chain|main|p-chain|x-chain|subnet"}`,
+		},
+		{
+			name:     "primary network chain",
+			query:    LokiQuery{Chain: "P", Grep: "ERROR"},
+			expected: `{job="p-chain"} |= "ERROR"`,
+		},
+		{
+			name:     "blockchain",
+			query:    LokiQuery{Chain: "C", ChainID: "abc", Grep: `"quoted"`},
+			expected: `{job="subnet", filename="/logs/abc.log"} |= "\"quoted\""`,
+		},
+		{
+			name:  "unknown chain",
+			query: LokiQuery{Chain: "D"},
+			err:   `unknown chain "D"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logQL, err := tt.query.LogQL()
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, logQL)
+		})
+	}
+}
+
+// lokiServer serves [lines] as Loki would, grouped by host, in forward direction
+func lokiServer(t *testing.T, lines []LogLine) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, lokiQueryRange, r.URL.Path)
+		start, err := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		require.NoError(t, err)
+		end, err := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+		require.NoError(t, err)
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		require.NoError(t, err)
+		selected := []LogLine{}
+		for _, line := range lines {
+			if ns := line.Timestamp.UnixNano(); ns >= start && ns < end {
+				selected = append(selected, line)
+			}
+		}
+		sort.SliceStable(selected, func(i, j int) bool {
+			return selected[i].Timestamp.Before(selected[j].Timestamp)
+		})
+		if len(selected) > limit {
+			selected = selected[:limit]
+		}
+		resp := lokiQueryResponse{Status: "success"}
+		resp.Data.ResultType = "streams"
+		streams := map[string]int{}
+		for _, line := range selected {
+			i, ok := streams[line.Host]
+			if !ok {
+				i = len(resp.Data.Result)
+				streams[line.Host] = i
+				resp.Data.Result = append(resp.Data.Result, struct {
+					Stream map[string]string `json:"stream"`
+					Values [][2]string       `json:"values"`
+				}{
+					Stream: map[string]string{"host": line.Host, "nodeID": line.NodeID, "job": line.Job},
+				})
+			}
+			resp.Data.Result[i].Values = append(resp.Data.Result[i].Values, [2]string{strconv.FormatInt(line.Timestamp.UnixNano(), 10), line.Line})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+}
+
+func TestQueryLoki(t *testing.T) {
+	base := time.Unix(1_700_000_000, 0).UTC()
+	line := func(offset time.Duration, host string, text string) LogLine {
+		return LogLine{Timestamp: base.Add(offset), Host: host, NodeID: "NodeID-" + host, Job: "main", Line: text}
+	}
+	lines := []LogLine{
+		line(1*time.Second, "i-1", "a"),
+		line(4*time.Second, "i-1", "d"),
+		line(4*time.Second, "i-1", "e"),
+		line(2*time.Second, "i-2", "b"),
+		line(4*time.Second, "i-2", "f"),
+		line(5*time.Second, "i-2", "g"),
+		line(3*time.Second, "i-3", "c"),
+		line(10*time.Second, "i-3", "out of range"),
+	}
+	server := lokiServer(t, lines)
+	defer server.Close()
+	for _, pageSize := range []int{1, 2, 3, 100} {
+		t.Run(strconv.Itoa(pageSize), func(t *testing.T) {
+			result := []string{}
+			err := QueryLoki(server.URL, LokiQuery{}, base, base.Add(10*time.Second), pageSize, func(line LogLine) error {
+				result = append(result, line.Line)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Len(t, result, 7)
+			require.Equal(t, []string{"a", "b", "c"}, result[:3])
+			require.ElementsMatch(t, []string{"d", "e", "f"}, result[3:6])
+			require.Equal(t, "g", result[6])
+		})
+	}
+}
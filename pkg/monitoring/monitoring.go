@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -25,6 +27,13 @@ type configInputs struct {
 	Host             string
 	NodeID           string
 	ChainID          string
+	TargetRegions    []targetRegion
+}
+
+// targetRegion labels with [Region] the prometheus targets whose address matches [AddressRegex]
+type targetRegion struct {
+	AddressRegex string
+	Region       string
 }
 
 //go:embed dashboards/*
@@ -77,11 +86,21 @@ func GenerateConfig(configPath string, configDesc string, templateVars configInp
 	return config.String(), nil
 }
 
-func WritePrometheusConfig(filePath string, avalancheGoPorts []string, machinePorts []string, loadTestPorts []string) error {
+// WritePrometheusConfig writes the prometheus config scraping the given targets. Node targets
+// are labeled with the region given for their IP on [targetRegions], so that dashboards can
+// filter multi-region clusters by region
+func WritePrometheusConfig(
+	filePath string,
+	avalancheGoPorts []string,
+	machinePorts []string,
+	loadTestPorts []string,
+	targetRegions map[string]string,
+) error {
 	config, err := GenerateConfig("configs/prometheus.yml", "Prometheus Config", configInputs{
 		AvalancheGoPorts: strings.Join(utils.AddSingleQuotes(avalancheGoPorts), ","),
 		MachinePorts:     strings.Join(utils.AddSingleQuotes(machinePorts), ","),
 		LoadTestPorts:    strings.Join(utils.AddSingleQuotes(loadTestPorts), ","),
+		TargetRegions:    getTargetRegions(targetRegions),
 	})
 	if err != nil {
 		return err
@@ -89,6 +108,24 @@ func WritePrometheusConfig(filePath string, avalancheGoPorts []string, machinePo
 	return os.WriteFile(filePath, []byte(config), constants.WriteReadReadPerms)
 }
 
+func getTargetRegions(targetRegions map[string]string) []targetRegion {
+	ips := make([]string, 0, len(targetRegions))
+	for ip, region := range targetRegions {
+		if region != "" {
+			ips = append(ips, ip)
+		}
+	}
+	sort.Strings(ips)
+	regions := make([]targetRegion, 0, len(ips))
+	for _, ip := range ips {
+		regions = append(regions, targetRegion{
+			AddressRegex: regexp.QuoteMeta(ip) + ":.*",
+			Region:       targetRegions[ip],
+		})
+	}
+	return regions
+}
+
 func WriteLokiConfig(filePath string, port string) error {
 	config, err := GenerateConfig("configs/loki.yml", "Loki Config", configInputs{
 		Port: port,
@@ -0,0 +1,59 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package monitoring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWritePrometheusConfigRegions(t *testing.T) {
+	require := require.New(t)
+	configPath := filepath.Join(t.TempDir(), "prometheus.yml")
+	require.NoError(WritePrometheusConfig(
+		configPath,
+		[]string{"'10.0.0.1:9650'", "'10.0.0.2:9650'"},
+		[]string{"'10.0.0.1:9100'", "'10.0.0.2:9100'"},
+		nil,
+		map[string]string{"10.0.0.1": "us-east-1", "10.0.0.2": "eu-west-1"},
+	))
+	configBytes, err := os.ReadFile(configPath)
+	require.NoError(err)
+	config := struct {
+		ScrapeConfigs []struct {
+			JobName        string `yaml:"job_name"`
+			RelabelConfigs []struct {
+				SourceLabels []string `yaml:"source_labels"`
+				Regex        string   `yaml:"regex"`
+				TargetLabel  string   `yaml:"target_label"`
+				Replacement  string   `yaml:"replacement"`
+			} `yaml:"relabel_configs"`
+		} `yaml:"scrape_configs"`
+	}{}
+	require.NoError(yaml.Unmarshal(configBytes, &config))
+	labeledJobs := 0
+	for _, scrapeConfig := range config.ScrapeConfigs {
+		if scrapeConfig.JobName != "avalanchego" && scrapeConfig.JobName != "avalanchego-machine" {
+			require.Empty(scrapeConfig.RelabelConfigs)
+			continue
+		}
+		labeledJobs++
+		require.Len(scrapeConfig.RelabelConfigs, 2)
+		require.Equal([]string{"__address__"}, scrapeConfig.RelabelConfigs[0].SourceLabels)
+		require.Equal(`10\.0\.0\.1:.*`, scrapeConfig.RelabelConfigs[0].Regex)
+		require.Equal("region", scrapeConfig.RelabelConfigs[0].TargetLabel)
+		require.Equal("us-east-1", scrapeConfig.RelabelConfigs[0].Replacement)
+		require.Equal(`10\.0\.0\.2:.*`, scrapeConfig.RelabelConfigs[1].Regex)
+		require.Equal("eu-west-1", scrapeConfig.RelabelConfigs[1].Replacement)
+	}
+	require.Equal(2, labeledJobs)
+
+	require.NoError(WritePrometheusConfig(configPath, []string{"'10.0.0.1:9650'"}, []string{"'10.0.0.1:9100'"}, nil, nil))
+	configBytes, err = os.ReadFile(configPath)
+	require.NoError(err)
+	require.NotContains(string(configBytes), "target_label: region")
+}
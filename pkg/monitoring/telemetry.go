@@ -0,0 +1,89 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+const datadogDashboardPath = "/api/v1/dashboard"
+
+type datadogDashboardResponse struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Errors []string `json:"errors"`
+}
+
+// getDatadogAPIURL returns the API URL of the datadog [site]
+func getDatadogAPIURL(site string) string {
+	return "https://api." + site
+}
+
+// getDatadogAppURL returns the web app URL of the datadog [site]. Regional sites
+// (eg us3.datadoghq.com) are served at the site itself
+func getDatadogAppURL(site string) string {
+	if strings.Count(site, ".") == 1 {
+		return "https://app." + site
+	}
+	return "https://" + site
+}
+
+// PutDatadogDashboard creates [dashboard] on the datadog [site], or updates the one with
+// [dashboardID] if given. Returns the dashboard ID and URL
+func PutDatadogDashboard(site string, apiKey string, appKey string, dashboardID string, dashboard []byte) (string, string, error) {
+	return putDatadogDashboard(getDatadogAPIURL(site), getDatadogAppURL(site), apiKey, appKey, dashboardID, dashboard)
+}
+
+func putDatadogDashboard(
+	apiURL string,
+	appURL string,
+	apiKey string,
+	appKey string,
+	dashboardID string,
+	dashboard []byte,
+) (string, string, error) {
+	method := http.MethodPost
+	endpoint := apiURL + datadogDashboardPath
+	if dashboardID != "" {
+		method = http.MethodPut
+		endpoint += "/" + dashboardID
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.APIRequestLargeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(dashboard))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", apiKey)
+	req.Header.Set("DD-APPLICATION-KEY", appKey)
+	httpResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer httpResp.Body.Close()
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return "", "", err
+	}
+	resp := datadogDashboardResponse{}
+	if err := json.Unmarshal(body, &resp); err != nil && httpResp.StatusCode == http.StatusOK {
+		return "", "", fmt.Errorf("invalid datadog response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		if len(resp.Errors) > 0 {
+			return "", "", fmt.Errorf("datadog dashboard request failed with status %d: %s", httpResp.StatusCode, strings.Join(resp.Errors, ", "))
+		}
+		return "", "", fmt.Errorf("datadog dashboard request failed with status %d: %s", httpResp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.ID, appURL + resp.URL, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package monitoring
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutDatadogDashboard(t *testing.T) {
+	require := require.New(t)
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Header.Get("DD-API-KEY") != "api" || r.Header.Get("DD-APPLICATION-KEY") != "app" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": ["Forbidden"]}`))
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(err)
+		require.JSONEq(`{"title": "dashboard"}`, string(body))
+		_ = json.NewEncoder(w).Encode(datadogDashboardResponse{ID: "abc-def-ghi", URL: "/dashboard/abc-def-ghi/dashboard"})
+	}))
+	defer server.Close()
+
+	dashboard := []byte(`{"title": "dashboard"}`)
+	id, url, err := putDatadogDashboard(server.URL, "https://app.datadoghq.com", "api", "app", "", dashboard)
+	require.NoError(err)
+	require.Equal("abc-def-ghi", id)
+	require.Equal("https://app.datadoghq.com/dashboard/abc-def-ghi/dashboard", url)
+	// updates the existing dashboard
+	_, _, err = putDatadogDashboard(server.URL, "https://app.datadoghq.com", "api", "app", id, dashboard)
+	require.NoError(err)
+	require.Equal([]string{"POST /api/v1/dashboard", "PUT /api/v1/dashboard/abc-def-ghi"}, requests)
+
+	_, _, err = putDatadogDashboard(server.URL, "https://app.datadoghq.com", "api", "wrong", "", dashboard)
+	require.ErrorContains(err, "status 403: Forbidden")
+
+	require.Equal("https://app.datadoghq.eu", getDatadogAppURL("datadoghq.eu"))
+	require.Equal("https://us5.datadoghq.com", getDatadogAppURL("us5.datadoghq.com"))
+}
@@ -73,6 +73,7 @@ type NetworkFlags struct {
 	UseMainnet  bool
 	Endpoint    string
 	ClusterName string
+	DevnetName  string
 }
 
 func AddNetworkFlagsToCmd(cmd *cobra.Command, networkFlags *NetworkFlags, addEndpoint bool, supportedNetworkOptions []NetworkOption) {
@@ -83,6 +84,7 @@ func AddNetworkFlagsToCmd(cmd *cobra.Command, networkFlags *NetworkFlags, addEnd
 			cmd.Flags().BoolVarP(&networkFlags.UseLocal, "local", "l", false, "operate on a local network")
 		case Devnet:
 			cmd.Flags().BoolVar(&networkFlags.UseDevnet, "devnet", false, "operate on a devnet network")
+			cmd.Flags().StringVar(&networkFlags.DevnetName, "network", "", "operate on the given named devnet (see network add-devnet)")
 			addEndpoint = true
 			addCluster = true
 		case Fuji:
@@ -222,6 +224,20 @@ func GetNetworkFromCmdLineFlags(
 		Cluster: "--cluster",
 	}
 	supportedNetworksFlags := strings.Join(utils.Map(supportedNetworkOptions, func(n NetworkOption) string { return networkFlagsMap[n] }), ", ")
+	// named devnet
+	devnetNetworkID := uint32(0)
+	if networkFlags.DevnetName != "" {
+		devnetConfig, err := app.GetDevnetConfig(networkFlags.DevnetName)
+		if err != nil {
+			return models.UndefinedNetwork, err
+		}
+		if networkFlags.Endpoint != "" && networkFlags.Endpoint != devnetConfig.Endpoint {
+			return models.UndefinedNetwork, fmt.Errorf("--endpoint %s does not match devnet %s endpoint %s", networkFlags.Endpoint, networkFlags.DevnetName, devnetConfig.Endpoint)
+		}
+		networkFlags.UseDevnet = true
+		networkFlags.Endpoint = devnetConfig.Endpoint
+		devnetNetworkID = devnetConfig.NetworkID
+	}
 	// received option
 	networkOption := Undefined
 	switch {
@@ -353,10 +369,10 @@ func GetNetworkFromCmdLineFlags(
 	network := models.UndefinedNetwork
 	switch networkOption {
 	case Local:
-		network = models.NewLocalNetwork()
+		network = app.GetLocalNetwork()
 	case Devnet:
-		networkID := uint32(0)
-		if networkFlags.Endpoint != "" {
+		networkID := devnetNetworkID
+		if networkID == 0 && networkFlags.Endpoint != "" {
 			infoClient := info.NewClient(networkFlags.Endpoint)
 			ctx, cancel := utils.GetAPIContext()
 			defer cancel()
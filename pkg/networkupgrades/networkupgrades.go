@@ -0,0 +1,221 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package networkupgrades gates CLI features on the activation state of the network
+// upgrades of the target network, so flows the network doesn't support yet fail early
+// with a clear error, instead of failing midway on a rejected transaction
+package networkupgrades
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/upgrade"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	Durango = "Durango"
+	Etna    = "Etna"
+)
+
+// SkipChecks disables the gating of features by upgrade activation
+var SkipChecks bool
+
+var ErrNotActivated = errors.New("network upgrade not activated")
+
+// Feature is a CLI functionality that depends on the activation of a network upgrade
+type Feature struct {
+	Name    string
+	Upgrade string
+	// Deprecated features are superseded by the ones of the upgrade: they are still
+	// allowed once it activates, with a warning
+	Deprecated bool
+	// Hint is shown to the user together with the error or the deprecation warning
+	Hint string
+}
+
+var (
+	SovereignL1 = Feature{
+		Name:    "sovereign L1s",
+		Upgrade: Etna,
+		Hint:    "Use --sovereign=false to deploy a non sovereign subnet instead",
+	}
+	SubnetToL1Conversion = Feature{
+		Name:    "subnet to L1 conversion",
+		Upgrade: Etna,
+	}
+	NonSovereignSubnets = Feature{
+		Name:       "non sovereign subnets",
+		Upgrade:    Etna,
+		Deprecated: true,
+		Hint:       "Consider deploying a sovereign L1, or converting the subnet into one with avalanche blockchain convert",
+	}
+)
+
+// Schedule is the cached upgrade schedule of a network
+type Schedule struct {
+	Endpoint  string         `json:"endpoint"`
+	FetchedAt time.Time      `json:"fetchedAt"`
+	Config    upgrade.Config `json:"config"`
+}
+
+// ActivationTime returns the activation time of [upgradeName] on [config]
+func ActivationTime(config upgrade.Config, upgradeName string) (time.Time, error) {
+	switch upgradeName {
+	case Durango:
+		return config.DurangoTime, nil
+	case Etna:
+		return config.EtnaTime, nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown network upgrade %q", upgradeName)
+	}
+}
+
+// Check validates [feature] against the upgrade schedule [config] at [now]. It returns an
+// error wrapping ErrNotActivated if the feature requires an upgrade not yet activated, and
+// a warning if the feature is deprecated by an activated upgrade
+func Check(config upgrade.Config, feature Feature, networkName string, now time.Time) (string, error) {
+	activationTime, err := ActivationTime(config, feature.Upgrade)
+	if err != nil {
+		return "", err
+	}
+	activated := !now.Before(activationTime)
+	switch {
+	case !feature.Deprecated && !activated:
+		msg := fmt.Sprintf(
+			"%s require the %s upgrade, which activates on %s at %s",
+			feature.Name,
+			feature.Upgrade,
+			networkName,
+			activationTime.UTC().Format(time.RFC3339),
+		)
+		if feature.Hint != "" {
+			msg += ". " + feature.Hint
+		}
+		return "", fmt.Errorf("%w: %s", ErrNotActivated, msg)
+	case feature.Deprecated && activated:
+		warning := fmt.Sprintf("%s are deprecated on %s since the %s upgrade", feature.Name, networkName, feature.Upgrade)
+		if feature.Hint != "" {
+			warning += ". " + feature.Hint
+		}
+		return warning, nil
+	}
+	return "", nil
+}
+
+// CheckFeature gates [feature] on [network]: it fails if the feature requires an upgrade the
+// network has not activated, unless SkipChecks is set, and warns if it is deprecated. The
+// feature is allowed if the network upgrade schedule can't be determined
+func CheckFeature(app *application.Avalanche, network models.Network, feature Feature) error {
+	config, err := GetSchedule(app, network)
+	if err != nil {
+		app.Log.Warn(
+			"unable to get network upgrade schedule, skipping upgrade checks",
+			zap.String("network", network.Name()),
+			zap.Error(err),
+		)
+		return nil
+	}
+	warning, err := Check(config, feature, network.Name(), time.Now())
+	switch {
+	case err != nil && SkipChecks:
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Skipping upgrade checks: %s"), err)
+		return nil
+	case err != nil:
+		return fmt.Errorf("%w. Use --%s to override", err, constants.SkipUpgradeChecksFlag)
+	case warning != "":
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("Warning: %s"), warning)
+	}
+	return nil
+}
+
+// GetSchedule returns the upgrade schedule of [network]. Schedules are queried from the
+// network info API, and cached for constants.NetworkUpgradesCacheTTL. If the API is not
+// reachable, an expired cached schedule, or the well known schedule of Mainnet and Fuji,
+// is used instead
+func GetSchedule(app *application.Avalanche, network models.Network) (upgrade.Config, error) {
+	cachePath := scheduleCachePath(app, network)
+	cached, cacheErr := loadSchedule(cachePath)
+	if cacheErr == nil && cached.Endpoint == network.Endpoint && time.Since(cached.FetchedAt) < constants.NetworkUpgradesCacheTTL {
+		return cached.Config, nil
+	}
+	config, err := fetchSchedule(network.Endpoint)
+	if err != nil {
+		switch {
+		case cacheErr == nil && cached.Endpoint == network.Endpoint:
+			app.Log.Debug("using expired network upgrade schedule", zap.String("network", network.Name()), zap.Error(err))
+			return cached.Config, nil
+		case network.ID == avagoconstants.MainnetID || network.ID == avagoconstants.FujiID:
+			return upgrade.GetConfig(network.ID), nil
+		}
+		return upgrade.Config{}, err
+	}
+	if !app.ReadOnly {
+		if err := saveSchedule(cachePath, Schedule{
+			Endpoint:  network.Endpoint,
+			FetchedAt: time.Now().UTC(),
+			Config:    config,
+		}); err != nil {
+			app.Log.Warn("failed to cache network upgrade schedule", zap.Error(err))
+		}
+	}
+	return config, nil
+}
+
+func fetchSchedule(endpoint string) (upgrade.Config, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.APIRequestTimeout)
+	defer cancel()
+	config, err := info.NewClient(endpoint).Upgrades(ctx)
+	if err != nil {
+		return upgrade.Config{}, fmt.Errorf("failure querying upgrades of %s: %w", endpoint, err)
+	}
+	return *config, nil
+}
+
+// scheduleCachePath returns the cache file of the schedule of [network]. The endpoint is
+// part of the key, as devnets and local networks sharing an ID may have different schedules
+func scheduleCachePath(app *application.Avalanche, network models.Network) string {
+	endpointHash := sha256.Sum256([]byte(network.Endpoint))
+	return filepath.Join(
+		app.GetNetworkUpgradesDir(),
+		fmt.Sprintf("%d-%s.json", network.ID, hex.EncodeToString(endpointHash[:4])),
+	)
+}
+
+func loadSchedule(path string) (Schedule, error) {
+	scheduleBytes, err := os.ReadFile(path)
+	if err != nil {
+		return Schedule{}, err
+	}
+	var schedule Schedule
+	if err := json.Unmarshal(scheduleBytes, &schedule); err != nil {
+		return Schedule{}, err
+	}
+	return schedule, nil
+}
+
+func saveSchedule(path string, schedule Schedule) error {
+	scheduleBytes, err := json.MarshalIndent(schedule, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, scheduleBytes, constants.WriteReadReadPerms)
+}
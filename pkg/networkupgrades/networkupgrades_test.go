@@ -0,0 +1,104 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkupgrades
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/upgrade"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	require := require.New(t)
+	etnaTime := time.Date(2024, time.December, 16, 17, 0, 0, 0, time.UTC)
+	config := upgrade.Config{EtnaTime: etnaTime}
+
+	// before activation
+	before := etnaTime.Add(-time.Hour)
+	_, err := Check(config, SovereignL1, "Devnet", before)
+	require.True(errors.Is(err, ErrNotActivated))
+	require.ErrorContains(err, "2024-12-16T17:00:00Z")
+	require.ErrorContains(err, SovereignL1.Hint)
+	warning, err := Check(config, NonSovereignSubnets, "Devnet", before)
+	require.NoError(err)
+	require.Empty(warning)
+
+	// after activation
+	warning, err = Check(config, SovereignL1, "Devnet", etnaTime)
+	require.NoError(err)
+	require.Empty(warning)
+	warning, err = Check(config, NonSovereignSubnets, "Devnet", etnaTime)
+	require.NoError(err)
+	require.Contains(warning, "deprecated")
+
+	_, err = Check(config, Feature{Name: "future", Upgrade: "Unknown"}, "Devnet", etnaTime)
+	require.ErrorContains(err, "unknown network upgrade")
+}
+
+func TestGetSchedule(t *testing.T) {
+	require := require.New(t)
+	etnaTime := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		require.Equal("/ext/info", r.URL.Path)
+		var request struct {
+			ID     interface{} `json:"id"`
+			Method string      `json:"method"`
+		}
+		require.NoError(json.NewDecoder(r.Body).Decode(&request))
+		require.Equal("info.upgrades", request.Method)
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      request.ID,
+			"result":  upgrade.Config{EtnaTime: etnaTime},
+		}))
+	}))
+
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil, nil)
+	network := models.NewDevnetNetwork(server.URL, 0)
+
+	config, err := GetSchedule(app, network)
+	require.NoError(err)
+	require.True(config.EtnaTime.Equal(etnaTime))
+	require.Equal(int32(1), requests.Load())
+
+	// cached
+	config, err = GetSchedule(app, network)
+	require.NoError(err)
+	require.True(config.EtnaTime.Equal(etnaTime))
+	require.Equal(int32(1), requests.Load())
+
+	// expired cache is still used when the network is not reachable
+	cachePath := scheduleCachePath(app, network)
+	schedule, err := loadSchedule(cachePath)
+	require.NoError(err)
+	schedule.FetchedAt = time.Now().Add(-48 * time.Hour)
+	require.NoError(saveSchedule(cachePath, schedule))
+	server.Close()
+	config, err = GetSchedule(app, network)
+	require.NoError(err)
+	require.True(config.EtnaTime.Equal(etnaTime))
+
+	// unknown schedule of an unreachable network
+	_, err = GetSchedule(app, models.NewDevnetNetwork(server.URL+"0", 0))
+	require.Error(err)
+	// well known schedule of an unreachable mainnet endpoint
+	config, err = GetSchedule(app, models.NewNetwork(models.Mainnet, avagoconstants.MainnetID, server.URL, ""))
+	require.NoError(err)
+	require.Equal(upgrade.Mainnet.EtnaTime, config.EtnaTime)
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"slices"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"golang.org/x/exp/maps"
+)
+
+// ShiftBlockchainEndpoints replaces the API endpoints of [fromCluster] by the ones of
+// [toCluster] on the blockchains tracked by [fromCluster], so their RPC traffic is directed
+// to [toCluster]. Blockchains not tracked by [toCluster] just lose the [fromCluster] endpoints
+func ShiftBlockchainEndpoints(app *application.Avalanche, fromCluster, toCluster string) error {
+	fromConfig, err := app.GetClusterConfig(fromCluster)
+	if err != nil {
+		return err
+	}
+	toConfig, err := app.GetClusterConfig(toCluster)
+	if err != nil {
+		return err
+	}
+	fromEndpoints, err := getClusterAPIEndpoints(app, fromCluster)
+	if err != nil {
+		return err
+	}
+	toEndpoints, err := getClusterAPIEndpoints(app, toCluster)
+	if err != nil {
+		return err
+	}
+	for _, blockchainName := range fromConfig.Subnets {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			return err
+		}
+		networkInfo, ok := sc.Networks[fromConfig.Network.Name()]
+		if !ok || networkInfo.BlockchainID == ids.Empty {
+			continue
+		}
+		blockchainID := networkInfo.BlockchainID.String()
+		targetEndpoints := toEndpoints
+		if !slices.Contains(toConfig.Subnets, blockchainName) {
+			targetEndpoints = nil
+		}
+		networkInfo.RPCEndpoints = replaceEndpoints(networkInfo.RPCEndpoints, fromEndpoints, targetEndpoints, func(endpoint string) string {
+			return models.GetRPCEndpoint(endpoint, blockchainID)
+		})
+		networkInfo.WSEndpoints = replaceEndpoints(networkInfo.WSEndpoints, fromEndpoints, targetEndpoints, func(endpoint string) string {
+			return models.GetWSEndpoint(endpoint, blockchainID)
+		})
+		sc.Networks[fromConfig.Network.Name()] = networkInfo
+		if err := app.UpdateSidecar(&sc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getClusterAPIEndpoints returns the endpoints the API of [clusterName] is served at: the
+// HTTPS endpoints of its nodes, and the plain HTTP endpoints of its public nodes
+func getClusterAPIEndpoints(app *application.Avalanche, clusterName string) ([]string, error) {
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	publicEndpoints, err := getPublicEndpoints(app, clusterName, hosts)
+	if err != nil {
+		return nil, err
+	}
+	httpsEndpoints := maps.Values(clusterConfig.HTTPSEndpoints)
+	slices.Sort(httpsEndpoints)
+	return utils.Unique(append(publicEndpoints, httpsEndpoints...)), nil
+}
+
+// replaceEndpoints removes from [current] the blockchain endpoints built by [format] out of
+// [from], and adds the ones built out of [to], keeping the order of the remaining ones
+func replaceEndpoints(current []string, from []string, to []string, format func(string) string) []string {
+	removed := utils.Map(from, format)
+	replaced := utils.Filter(current, func(endpoint string) bool {
+		return !slices.Contains(removed, endpoint)
+	})
+	for _, endpoint := range utils.Map(to, format) {
+		if !slices.Contains(replaced, endpoint) {
+			replaced = append(replaced, endpoint)
+		}
+	}
+	return replaced
+}
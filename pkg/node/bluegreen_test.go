@@ -0,0 +1,41 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceEndpoints(t *testing.T) {
+	require := require.New(t)
+	blockchainID := "2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM"
+	format := func(endpoint string) string {
+		return models.GetRPCEndpoint(endpoint, blockchainID)
+	}
+	current := []string{
+		format("https://rpc.example.com"),
+		format("http://10.0.0.1:9650"),
+		format("https://other.example.com"),
+	}
+	// the shared HTTPS endpoint is kept, the plain blue one replaced by the green one
+	replaced := replaceEndpoints(
+		current,
+		[]string{"https://rpc.example.com", "http://10.0.0.1:9650"},
+		[]string{"https://rpc.example.com", "http://10.0.0.2:9650"},
+		format,
+	)
+	require.Equal([]string{
+		format("https://other.example.com"),
+		format("https://rpc.example.com"),
+		format("http://10.0.0.2:9650"),
+	}, replaced)
+	// no target cluster just removes the endpoints
+	replaced = replaceEndpoints(current, []string{"http://10.0.0.1:9650"}, nil, format)
+	require.Equal([]string{
+		format("https://rpc.example.com"),
+		format("https://other.example.com"),
+	}, replaced)
+}
@@ -0,0 +1,251 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+// ChainSyncProgress is the bootstrap progress of a node for a given EVM chain
+type ChainSyncProgress struct {
+	// Available is false if the chain RPC is not yet answering (eg: still in early bootstrap)
+	Available bool
+	// Bootstrapped is true if the node reports the chain as bootstrapped (info.isBootstrapped)
+	Bootstrapped bool
+	CurrentBlock uint64
+	HighestBlock uint64
+}
+
+// Synced returns true if the node has bootstrapped the chain and processed all blocks up
+// to the known tip. Without a reference tip, the known tip is the one of the cluster nodes,
+// so a lagging cluster is only reported as synced once bootstrapped
+func (p ChainSyncProgress) Synced() bool {
+	return p.Available && p.Bootstrapped && p.HighestBlock > 0 && p.CurrentBlock >= p.HighestBlock
+}
+
+// Percent returns the percentage of blocks processed vs known tip
+func (p ChainSyncProgress) Percent() float64 {
+	if !p.Available || p.HighestBlock == 0 {
+		return 0
+	}
+	if p.CurrentBlock >= p.HighestBlock {
+		return 100
+	}
+	return float64(p.CurrentBlock) * 100 / float64(p.HighestBlock)
+}
+
+// GetChainSyncProgress gathers, for each host, the number of blocks processed
+// for [chain], if it is bootstrapped, and the chain tip. The tip is taken as the
+// highest value among the node eth_syncing report, the other hosts, and
+// [referenceRPC] if given
+func GetChainSyncProgress(hosts []*models.Host, chain string, referenceRPC string) (map[string]ChainSyncProgress, error) {
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, host *models.Host) {
+			defer wg.Done()
+			progress, err := getHostChainSyncProgress(host, chain)
+			nodeResults.AddResult(host.GetCloudID(), progress, err)
+		}(&wgResults, host)
+	}
+	wg.Wait()
+	if wgResults.HasErrors() {
+		return nil, fmt.Errorf("failed to get sync progress of chain %s for node(s) %s", chain, wgResults.GetErrorHostMap())
+	}
+	referenceTip := uint64(0)
+	if referenceRPC != "" {
+		if tip, err := getReferenceTip(referenceRPC); err == nil {
+			referenceTip = tip
+		}
+	}
+	progressMap := map[string]ChainSyncProgress{}
+	for cloudID, result := range wgResults.GetResultMap() {
+		progressMap[cloudID] = result.(ChainSyncProgress)
+	}
+	setChainTip(progressMap, referenceTip)
+	return progressMap, nil
+}
+
+// setChainTip sets the tip of the available entries of [progressMap] to the highest
+// among [referenceTip] and the blocks known by the hosts
+func setChainTip(progressMap map[string]ChainSyncProgress, referenceTip uint64) {
+	tip := referenceTip
+	for _, progress := range progressMap {
+		tip = max(tip, progress.HighestBlock, progress.CurrentBlock)
+	}
+	for cloudID, progress := range progressMap {
+		if progress.Available {
+			progress.HighestBlock = tip
+			progressMap[cloudID] = progress
+		}
+	}
+}
+
+func getHostChainSyncProgress(host *models.Host, chain string) (ChainSyncProgress, error) {
+	resp, err := ssh.RunSSHCheckChainBootstrapped(host, chain)
+	if err != nil {
+		return ChainSyncProgress{}, err
+	}
+	// a chain not yet created on the node is not found, so a parse failure means
+	// it is not bootstrapped
+	bootstrapped, _ := parseBootstrappedOutput(resp)
+	progress, err := getHostChainBlocks(host, chain)
+	progress.Bootstrapped = bootstrapped
+	return progress, err
+}
+
+func getHostChainBlocks(host *models.Host, chain string) (ChainSyncProgress, error) {
+	resp, err := ssh.RunSSHChainSyncProgress(host, chain)
+	if err != nil {
+		return ChainSyncProgress{}, err
+	}
+	progress, syncing, err := parseEthSyncingOutput(resp)
+	if err != nil || syncing {
+		// a chain still bootstrapping does not answer RPC requests yet,
+		// so a parse failure just means progress is not available
+		return progress, nil
+	}
+	resp, err = ssh.RunSSHChainBlockNumber(host, chain)
+	if err != nil {
+		return ChainSyncProgress{}, err
+	}
+	blockNumber, err := parseHexResultOutput(resp)
+	if err != nil {
+		return ChainSyncProgress{}, nil
+	}
+	return ChainSyncProgress{
+		Available:    true,
+		CurrentBlock: blockNumber,
+		HighestBlock: blockNumber,
+	}, nil
+}
+
+func getReferenceTip(rpcURL string) (uint64, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	return client.BlockNumber(ctx)
+}
+
+// parseEthSyncingOutput parses an eth_syncing response. It returns true if the node
+// reported an ongoing sync, together with its progress
+func parseEthSyncingOutput(byteValue []byte) (ChainSyncProgress, bool, error) {
+	var result map[string]interface{}
+	if err := json.Unmarshal(byteValue, &result); err != nil {
+		return ChainSyncProgress{}, false, err
+	}
+	switch syncing := result["result"].(type) {
+	case bool:
+		if syncing {
+			return ChainSyncProgress{}, false, fmt.Errorf("unexpected eth_syncing output: %v", result)
+		}
+		return ChainSyncProgress{}, false, nil
+	case map[string]interface{}:
+		current, err := parseHexField(syncing, "currentBlock")
+		if err != nil {
+			return ChainSyncProgress{}, false, err
+		}
+		highest, err := parseHexField(syncing, "highestBlock")
+		if err != nil {
+			return ChainSyncProgress{}, false, err
+		}
+		return ChainSyncProgress{
+			Available:    true,
+			CurrentBlock: current,
+			HighestBlock: highest,
+		}, true, nil
+	}
+	return ChainSyncProgress{}, false, fmt.Errorf("unable to parse eth_syncing output: %v", result)
+}
+
+func parseHexResultOutput(byteValue []byte) (uint64, error) {
+	var result map[string]interface{}
+	if err := json.Unmarshal(byteValue, &result); err != nil {
+		return 0, err
+	}
+	return parseHexField(result, "result")
+}
+
+func parseHexField(m map[string]interface{}, field string) (uint64, error) {
+	hexValue, ok := m[field].(string)
+	if !ok {
+		return 0, fmt.Errorf("field %s not found in %v", field, m)
+	}
+	return strconv.ParseUint(strings.TrimPrefix(hexValue, "0x"), 16, 64)
+}
+
+// BootstrapProgressTracker keeps previous progress samples so as to
+// estimate the remaining time for each node to reach the chain tip
+type BootstrapProgressTracker struct {
+	samples map[string]progressSample
+}
+
+type progressSample struct {
+	block uint64
+	time  time.Time
+}
+
+func NewBootstrapProgressTracker() *BootstrapProgressTracker {
+	return &BootstrapProgressTracker{
+		samples: map[string]progressSample{},
+	}
+}
+
+// Update registers a new progress sample for [key] and returns the estimated
+// time to reach the tip. The boolean is false if there is not enough data yet
+func (t *BootstrapProgressTracker) Update(key string, progress ChainSyncProgress, now time.Time) (time.Duration, bool) {
+	if !progress.Available {
+		return 0, false
+	}
+	if progress.Synced() {
+		t.samples[key] = progressSample{block: progress.CurrentBlock, time: now}
+		return 0, true
+	}
+	prev, ok := t.samples[key]
+	t.samples[key] = progressSample{block: progress.CurrentBlock, time: now}
+	if !ok || progress.CurrentBlock <= prev.block || !now.After(prev.time) {
+		return 0, false
+	}
+	rate := float64(progress.CurrentBlock-prev.block) / now.Sub(prev.time).Seconds()
+	remaining := float64(progress.HighestBlock - progress.CurrentBlock)
+	return time.Duration(remaining / rate * float64(time.Second)), true
+}
+
+// ReferenceRPCForChain returns an RPC endpoint not served by [hosts] that can be used as tip
+// reference for [chain]: the public C-Chain endpoint, or one of the [rpcEndpoints] of the
+// blockchain, as recorded on its sidecar. Returns empty string if there is no such endpoint
+func ReferenceRPCForChain(network models.Network, chain string, rpcEndpoints []string, hosts []*models.Host) string {
+	if chain == "C" {
+		if network.StandardPublicEndpoint() {
+			return network.CChainEndpoint()
+		}
+		return ""
+	}
+	for _, endpoint := range rpcEndpoints {
+		endpointURL, err := url.Parse(endpoint)
+		if err != nil {
+			continue
+		}
+		if !slices.ContainsFunc(hosts, func(host *models.Host) bool { return host.IP == endpointURL.Hostname() }) {
+			return endpoint
+		}
+	}
+	return ""
+}
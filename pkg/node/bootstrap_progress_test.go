@@ -0,0 +1,83 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEthSyncingOutput(t *testing.T) {
+	require := require.New(t)
+
+	progress, syncing, err := parseEthSyncingOutput([]byte(`{"jsonrpc":"2.0","id":1,"result":false}`))
+	require.NoError(err)
+	require.False(syncing)
+	require.False(progress.Available)
+
+	progress, syncing, err = parseEthSyncingOutput([]byte(`{"jsonrpc":"2.0","id":1,"result":{"currentBlock":"0x10","highestBlock":"0x40","startingBlock":"0x0"}}`))
+	require.NoError(err)
+	require.True(syncing)
+	require.Equal(ChainSyncProgress{Available: true, CurrentBlock: 16, HighestBlock: 64}, progress)
+	require.InDelta(25.0, progress.Percent(), 0.001)
+	require.False(progress.Synced())
+
+	_, _, err = parseEthSyncingOutput([]byte(`404 page not found`))
+	require.Error(err)
+}
+
+func TestBootstrapProgressTracker(t *testing.T) {
+	require := require.New(t)
+	tracker := NewBootstrapProgressTracker()
+	start := time.Now()
+
+	_, ok := tracker.Update("node", ChainSyncProgress{}, start)
+	require.False(ok)
+
+	_, ok = tracker.Update("node", ChainSyncProgress{Available: true, CurrentBlock: 100, HighestBlock: 1100}, start)
+	require.False(ok)
+
+	// 100 blocks in 10 seconds, 900 remaining
+	eta, ok := tracker.Update("node", ChainSyncProgress{Available: true, CurrentBlock: 200, HighestBlock: 1100}, start.Add(10*time.Second))
+	require.True(ok)
+	require.Equal(90*time.Second, eta)
+
+	eta, ok = tracker.Update("node", ChainSyncProgress{Available: true, Bootstrapped: true, CurrentBlock: 1100, HighestBlock: 1100}, start.Add(20*time.Second))
+	require.True(ok)
+	require.Zero(eta)
+}
+
+func TestSetChainTipSingleNode(t *testing.T) {
+	require := require.New(t)
+	// a single node still bootstrapping, with no reference RPC, knows no tip other than its own
+	progressMap := map[string]ChainSyncProgress{
+		"node": {Available: true, CurrentBlock: 100, HighestBlock: 100},
+	}
+	setChainTip(progressMap, 0)
+	require.Equal(uint64(100), progressMap["node"].HighestBlock)
+	require.False(progressMap["node"].Synced())
+
+	progressMap["node"] = ChainSyncProgress{Available: true, Bootstrapped: true, CurrentBlock: 100, HighestBlock: 100}
+	setChainTip(progressMap, 0)
+	require.True(progressMap["node"].Synced())
+
+	// a reference tip ahead of the node
+	setChainTip(progressMap, 150)
+	require.Equal(uint64(150), progressMap["node"].HighestBlock)
+	require.False(progressMap["node"].Synced())
+}
+
+func TestReferenceRPCForChain(t *testing.T) {
+	require := require.New(t)
+	hosts := []*models.Host{{IP: "10.0.0.1"}}
+	require.Equal(models.NewFujiNetwork().CChainEndpoint(), ReferenceRPCForChain(models.NewFujiNetwork(), "C", nil, hosts))
+	require.Empty(ReferenceRPCForChain(models.NewLocalNetwork(), "C", nil, hosts))
+	require.Empty(ReferenceRPCForChain(models.NewFujiNetwork(), "chain", []string{"http://10.0.0.1:9650/ext/bc/chain/rpc"}, hosts))
+	require.Equal(
+		"https://rpc.example.com/ext/bc/chain/rpc",
+		ReferenceRPCForChain(models.NewFujiNetwork(), "chain", []string{"http://10.0.0.1:9650/ext/bc/chain/rpc", "https://rpc.example.com/ext/bc/chain/rpc"}, hosts),
+	)
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"golang.org/x/exp/slices"
+)
+
+// ReferencedCloudResources holds the cloud resources referenced by local cluster definitions
+type ReferencedCloudResources struct {
+	InstanceIDs    []string
+	PublicIPs      []string
+	StaticIPNames  []string
+	SecurityGroups []string
+}
+
+// IsOrphanedCloudResource tells if [r], as listed by a cloud ListManagedResources, is not
+// referenced by any local cluster and was created by OS user [username], or by any user if
+// [username] is empty
+func IsOrphanedCloudResource(r models.CloudResource, referenced ReferencedCloudResources, username string) bool {
+	if username != "" && !isCloudResourceOwnedBy(r, username) {
+		return false
+	}
+	switch r.Type {
+	case models.CloudResourceInstance:
+		return !slices.Contains(referenced.InstanceIDs, r.ID)
+	case models.CloudResourceStaticIP:
+		return !slices.Contains(referenced.PublicIPs, r.PublicIP) && !slices.Contains(referenced.StaticIPNames, r.Name)
+	case models.CloudResourceSecurityGroup:
+		return !slices.Contains(referenced.SecurityGroups, r.Name) && !slices.Contains(referenced.SecurityGroups, r.ID)
+	}
+	return false
+}
+
+// isCloudResourceOwnedBy checks the owner label of [r]. Resources created by older versions
+// have no owner label, and are attributed by the username prefix of their name
+func isCloudResourceOwnedBy(r models.CloudResource, username string) bool {
+	if r.Owner != "" {
+		return r.Owner == utils.CloudResourceOwner(username)
+	}
+	return strings.HasPrefix(r.Name, username+"-")
+}
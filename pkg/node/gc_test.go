@@ -0,0 +1,106 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsOrphanedCloudResource(t *testing.T) {
+	referenced := ReferencedCloudResources{
+		InstanceIDs:    []string{"i-used"},
+		PublicIPs:      []string{"1.2.3.4"},
+		StaticIPNames:  []string{"static-ip-used"},
+		SecurityGroups: []string{"sg-used"},
+	}
+	tests := []struct {
+		name     string
+		resource models.CloudResource
+		username string
+		orphaned bool
+	}{
+		{
+			name:     "unreferenced instance of the user",
+			resource: models.CloudResource{Type: models.CloudResourceInstance, ID: "i-1", Name: "alice-us-east-1-avalanche-cli", Owner: "alice"},
+			username: "alice",
+			orphaned: true,
+		},
+		{
+			name:     "referenced instance",
+			resource: models.CloudResource{Type: models.CloudResourceInstance, ID: "i-used", Owner: "alice"},
+			username: "alice",
+		},
+		{
+			name:     "instance of another user",
+			resource: models.CloudResource{Type: models.CloudResourceInstance, ID: "i-1", Owner: "bob"},
+			username: "alice",
+		},
+		{
+			name:     "instance of another user with all users",
+			resource: models.CloudResource{Type: models.CloudResourceInstance, ID: "i-1", Owner: "bob"},
+			orphaned: true,
+		},
+		{
+			name:     "owner label takes precedence over the name",
+			resource: models.CloudResource{Type: models.CloudResourceInstance, ID: "i-1", Name: "alice-us-east-1-avalanche-cli", Owner: "bob"},
+			username: "alice",
+		},
+		{
+			name:     "owner label is normalized",
+			resource: models.CloudResource{Type: models.CloudResourceInstance, ID: "i-1", Owner: "alice_smith"},
+			username: "Alice.Smith",
+			orphaned: true,
+		},
+		{
+			name:     "legacy instance attributed by name",
+			resource: models.CloudResource{Type: models.CloudResourceInstance, ID: "i-1", Name: "alice-us-east-1-avalanche-cli"},
+			username: "alice",
+			orphaned: true,
+		},
+		{
+			name:     "reserved static ip of another user",
+			resource: models.CloudResource{Type: models.CloudResourceStaticIP, ID: "static-ip-node-0", Name: "static-ip-node-0", Owner: "bob", State: "reserved"},
+			username: "alice",
+		},
+		{
+			name:     "unlabelled static ip",
+			resource: models.CloudResource{Type: models.CloudResourceStaticIP, ID: "static-ip-node-0", Name: "static-ip-node-0", State: "reserved"},
+			username: "alice",
+		},
+		{
+			name:     "reserved static ip of the user",
+			resource: models.CloudResource{Type: models.CloudResourceStaticIP, ID: "static-ip-node-0", Name: "static-ip-node-0", Owner: "alice", State: "reserved"},
+			username: "alice",
+			orphaned: true,
+		},
+		{
+			name:     "static ip referenced by address",
+			resource: models.CloudResource{Type: models.CloudResourceStaticIP, ID: "eipalloc-1", PublicIP: "1.2.3.4", Owner: "alice"},
+			username: "alice",
+		},
+		{
+			name:     "static ip referenced by name",
+			resource: models.CloudResource{Type: models.CloudResourceStaticIP, ID: "static-ip-used", Name: "static-ip-used", Owner: "alice"},
+			username: "alice",
+		},
+		{
+			name:     "referenced security group",
+			resource: models.CloudResource{Type: models.CloudResourceSecurityGroup, ID: "sg-used", Name: "alice-us-east-1-avalanche-cli-us-east-1-aws-sg", Owner: "alice"},
+			username: "alice",
+		},
+		{
+			name:     "unreferenced security group",
+			resource: models.CloudResource{Type: models.CloudResourceSecurityGroup, ID: "sg-1", Name: "alice-us-east-1-avalanche-cli-us-east-1-aws-sg", Owner: "alice"},
+			username: "alice",
+			orphaned: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.orphaned, IsOrphanedCloudResource(tt.resource, referenced, tt.username))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// GetClusterHealth checks the bootstrap and health status, the avalanchego version and, if
+// [blockchainID] is given, the blockchain status of each one of [hosts]. [nodeIDs] maps cloud
+// IDs to node IDs. Unlike the other cluster checks, a host that can not be reached or a check
+// that fails does not make the whole operation fail, but is recorded on the host health
+func GetClusterHealth(hosts []*models.Host, nodeIDs map[string]string, blockchainID ids.ID) []models.NodeHealth {
+	wg := sync.WaitGroup{}
+	health := make([]models.NodeHealth, len(hosts))
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host *models.Host) {
+			defer wg.Done()
+			health[i] = getNodeHealth(host, nodeIDs[host.GetCloudID()], blockchainID)
+		}(i, host)
+	}
+	wg.Wait()
+	return health
+}
+
+func getNodeHealth(host *models.Host, nodeID string, blockchainID ids.ID) models.NodeHealth {
+	health := models.NodeHealth{
+		CloudID: host.GetCloudID(),
+		NodeID:  nodeID,
+	}
+	resp, err := ssh.RunSSHCheckBootstrapped(host)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	health.Reachable = true
+	if health.Bootstrapped, err = parseBootstrappedOutput(resp); err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	if resp, err = ssh.RunSSHCheckHealthy(host); err == nil {
+		health.Healthy, err = parseHealthyOutput(resp)
+	}
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	if resp, err = ssh.RunSSHCheckAvalancheGoVersion(host); err == nil {
+		health.AvalancheGoVersion, _, err = ParseAvalancheGoOutput(resp)
+	}
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	// blockchains are only synced once the Primary Network is bootstrapped
+	if blockchainID != ids.Empty && health.Bootstrapped {
+		if resp, err = ssh.RunSSHSubnetSyncStatus(host, blockchainID.String()); err == nil {
+			health.BlockchainStatus, err = ParseSubnetSyncOutput(resp)
+		}
+		if err != nil {
+			health.Error = err.Error()
+		}
+	}
+	return health
+}
+
+// HealthTransition is a change on the health of a node between two consecutive snapshots.
+// The first snapshot where a node is found also counts as a transition, with an empty From
+type HealthTransition struct {
+	Time time.Time
+	From *models.NodeHealth
+	To   models.NodeHealth
+}
+
+// NodeHealthSummary aggregates the health of a node over a set of snapshots
+type NodeHealthSummary struct {
+	CloudID string
+	NodeID  string
+	Checks  int
+	// Failures is the number of checks where the node status was not OK
+	Failures    int
+	LastFailure time.Time
+	Current     models.NodeHealth
+}
+
+// GetHealthTransitions returns, in chronological order, the changes of status, avalanchego
+// version, or blockchain status of the nodes on the snapshots of [history] taken since [since],
+// together with a per node summary sorted by first appearance
+func GetHealthTransitions(history models.ClusterHealthHistory, since time.Time) ([]HealthTransition, []NodeHealthSummary) {
+	transitions := []HealthTransition{}
+	summaries := []NodeHealthSummary{}
+	summaryIndex := map[string]int{}
+	for _, snapshot := range history.Snapshots {
+		if snapshot.Time.Before(since) {
+			continue
+		}
+		for _, health := range snapshot.Nodes {
+			i, seen := summaryIndex[health.CloudID]
+			if !seen {
+				i = len(summaries)
+				summaryIndex[health.CloudID] = i
+				summaries = append(summaries, NodeHealthSummary{CloudID: health.CloudID})
+				transitions = append(transitions, HealthTransition{Time: snapshot.Time, To: health})
+			} else if HealthChanged(summaries[i].Current, health) {
+				previous := summaries[i].Current
+				transitions = append(transitions, HealthTransition{Time: snapshot.Time, From: &previous, To: health})
+			}
+			summary := &summaries[i]
+			if health.NodeID != "" {
+				summary.NodeID = health.NodeID
+			}
+			summary.Checks++
+			if health.Status() != models.NodeHealthOK {
+				summary.Failures++
+				summary.LastFailure = snapshot.Time
+			}
+			summary.Current = health
+		}
+	}
+	return transitions, summaries
+}
+
+// HealthChanged returns true if the status, avalanchego version or blockchain status of a
+// node differ between [previous] and [current]
+func HealthChanged(previous models.NodeHealth, current models.NodeHealth) bool {
+	return previous.Status() != current.Status() ||
+		(current.AvalancheGoVersion != "" && previous.AvalancheGoVersion != current.AvalancheGoVersion) ||
+		previous.BlockchainStatus != current.BlockchainStatus
+}
+
+// GetNewlyFailingNodes returns the cloud IDs of the nodes of [current] whose status is a
+// failing one, and differs from the one on [previous], or were not checked before
+func GetNewlyFailingNodes(previous map[string]models.NodeHealth, current []models.NodeHealth) []string {
+	failing := []string{}
+	for _, health := range current {
+		if health.Status() == models.NodeHealthOK {
+			continue
+		}
+		if prev, ok := previous[health.CloudID]; ok && prev.Status() == health.Status() {
+			continue
+		}
+		failing = append(failing, health.CloudID)
+	}
+	return failing
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHealthTransitions(t *testing.T) {
+	require := require.New(t)
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	ok := models.NodeHealth{CloudID: "i-1", NodeID: "NodeID-1", Reachable: true, Bootstrapped: true, Healthy: true, AvalancheGoVersion: "v1.11.0"}
+	unhealthy := ok
+	unhealthy.Healthy = false
+	unreachable := models.NodeHealth{CloudID: "i-1", NodeID: "NodeID-1", Error: "connection refused"}
+	upgraded := ok
+	upgraded.AvalancheGoVersion = "v1.11.1"
+	other := models.NodeHealth{CloudID: "i-2", NodeID: "NodeID-2", Reachable: true, Bootstrapped: true, Healthy: true}
+	snapshot := func(hours int, nodes ...models.NodeHealth) models.ClusterHealthSnapshot {
+		return models.ClusterHealthSnapshot{Time: start.Add(time.Duration(hours) * time.Hour), Nodes: nodes}
+	}
+	history := models.ClusterHealthHistory{
+		Snapshots: []models.ClusterHealthSnapshot{
+			snapshot(0, unhealthy),
+			snapshot(1, ok, other),
+			snapshot(2, unhealthy, other),
+			snapshot(3, unreachable, other),
+			snapshot(4, upgraded, other),
+			snapshot(5, upgraded, other),
+		},
+	}
+
+	transitions, summaries := GetHealthTransitions(history, start.Add(time.Hour))
+	require.Equal([]HealthTransition{
+		{Time: start.Add(time.Hour), To: ok},
+		{Time: start.Add(time.Hour), To: other},
+		{Time: start.Add(2 * time.Hour), From: &ok, To: unhealthy},
+		{Time: start.Add(3 * time.Hour), From: &unhealthy, To: unreachable},
+		{Time: start.Add(4 * time.Hour), From: &unreachable, To: upgraded},
+	}, transitions)
+	require.Equal([]NodeHealthSummary{
+		{CloudID: "i-1", NodeID: "NodeID-1", Checks: 5, Failures: 2, LastFailure: start.Add(3 * time.Hour), Current: upgraded},
+		{CloudID: "i-2", NodeID: "NodeID-2", Checks: 5, Current: other},
+	}, summaries)
+
+	transitions, summaries = GetHealthTransitions(history, start.Add(6*time.Hour))
+	require.Empty(transitions)
+	require.Empty(summaries)
+}
+
+func TestGetNewlyFailingNodes(t *testing.T) {
+	require := require.New(t)
+	ok := models.NodeHealth{CloudID: "i-1", Reachable: true, Bootstrapped: true, Healthy: true}
+	unhealthy := ok
+	unhealthy.Healthy = false
+	unreachable := models.NodeHealth{CloudID: "i-1", Error: "connection refused"}
+	otherUnhealthy := models.NodeHealth{CloudID: "i-2", Reachable: true, Bootstrapped: true}
+
+	// first check
+	require.Equal([]string{"i-1", "i-2"}, GetNewlyFailingNodes(map[string]models.NodeHealth{}, []models.NodeHealth{unhealthy, otherUnhealthy}))
+	previous := map[string]models.NodeHealth{"i-1": unhealthy, "i-2": otherUnhealthy}
+	// still failing the same way
+	require.Empty(GetNewlyFailingNodes(previous, []models.NodeHealth{unhealthy, otherUnhealthy}))
+	// recovered
+	require.Empty(GetNewlyFailingNodes(previous, []models.NodeHealth{ok, otherUnhealthy}))
+	// failing in a different way
+	require.Equal([]string{"i-1"}, GetNewlyFailingNodes(previous, []models.NodeHealth{unreachable, otherUnhealthy}))
+	// failing again after recovering
+	require.Equal([]string{"i-1"}, GetNewlyFailingNodes(map[string]models.NodeHealth{"i-1": ok}, []models.NodeHealth{unhealthy}))
+}
@@ -154,21 +154,31 @@ func getRPCIncompatibleNodes(app *application.Avalanche, hosts []*models.Host, s
 }
 
 func ParseAvalancheGoOutput(byteValue []byte) (string, uint32, error) {
+	nodeVersionReply, err := ParseNodeVersionOutput(byteValue)
+	if err != nil {
+		return "", 0, err
+	}
+	return nodeVersionReply.VMVersions["platform"], uint32(nodeVersionReply.RPCProtocolVersion), nil
+}
+
+// ParseNodeVersionOutput parses the full reply of an info.getNodeVersion call, including
+// the versions of all the VMs installed on the node
+func ParseNodeVersionOutput(byteValue []byte) (info.GetNodeVersionReply, error) {
 	reply := map[string]interface{}{}
 	if err := json.Unmarshal(byteValue, &reply); err != nil {
-		return "", 0, err
+		return info.GetNodeVersionReply{}, err
 	}
 	resultMap := reply["result"]
 	resultJSON, err := json.Marshal(resultMap)
 	if err != nil {
-		return "", 0, err
+		return info.GetNodeVersionReply{}, err
 	}
 
 	nodeVersionReply := info.GetNodeVersionReply{}
 	if err := json.Unmarshal(resultJSON, &nodeVersionReply); err != nil {
-		return "", 0, err
+		return info.GetNodeVersionReply{}, err
 	}
-	return nodeVersionReply.VMVersions["platform"], uint32(nodeVersionReply.RPCProtocolVersion), nil
+	return nodeVersionReply, nil
 }
 
 func DisconnectHosts(hosts []*models.Host) {
@@ -194,6 +204,9 @@ func getPublicEndpoints(
 		return utils.Belongs(publicNodes, tracker.GetCloudID())
 	})
 	endpoints := utils.Map(publicTrackers, func(tracker *models.Host) string {
+		if httpsEndpoint, ok := clusterConfig.HTTPSEndpoints[tracker.GetCloudID()]; ok {
+			return httpsEndpoint
+		}
 		return GetAvalancheGoEndpoint(tracker.IP)
 	})
 	return endpoints, nil
@@ -0,0 +1,158 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/rpcauth"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+// GetHTTPSEndpoint returns the endpoint of the avalanchego API served over HTTPS at [domain]
+func GetHTTPSEndpoint(domain string) string {
+	return fmt.Sprintf("https://%s", domain)
+}
+
+// SetClusterHTTPSEndpoints records [httpsEndpoints], that maps host cloud IDs to HTTPS
+// endpoints, on the config of [clusterName]. The plain HTTP endpoints of those hosts, and
+// the HTTPS ones they no longer serve, are replaced by the new HTTPS ones on the blockchains
+// tracked by the cluster. [email], if given,
+// is recorded as the Let's Encrypt contact, to be reused when the proxies are reconfigured.
+// [loadBalancer] is the cloud ID of the host serving the endpoints, if they are load balanced
+func SetClusterHTTPSEndpoints(
+	app *application.Avalanche,
+	clusterName string,
+	hosts []*models.Host,
+	httpsEndpoints map[string]string,
+	email string,
+	loadBalancer string,
+) error {
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if clusterConfig.HTTPSEndpoints == nil {
+		clusterConfig.HTTPSEndpoints = map[string]string{}
+	}
+	previousEndpoints := set.Set[string]{}
+	for cloudID, endpoint := range httpsEndpoints {
+		if previousEndpoint, ok := clusterConfig.HTTPSEndpoints[cloudID]; ok {
+			previousEndpoints.Add(previousEndpoint)
+		}
+		clusterConfig.HTTPSEndpoints[cloudID] = endpoint
+	}
+	for _, endpoint := range clusterConfig.HTTPSEndpoints {
+		previousEndpoints.Remove(endpoint)
+	}
+	if email != "" {
+		clusterConfig.HTTPSEmail = email
+	}
+	clusterConfig.HTTPSLoadBalancer = loadBalancer
+	if err := app.SetClusterConfig(clusterName, clusterConfig); err != nil {
+		return err
+	}
+	for _, blockchainName := range clusterConfig.Subnets {
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			return err
+		}
+		networkInfo, ok := sc.Networks[clusterConfig.Network.Name()]
+		if !ok || networkInfo.BlockchainID == ids.Empty {
+			continue
+		}
+		blockchainID := networkInfo.BlockchainID.String()
+		rpcEndpoints := set.Of(networkInfo.RPCEndpoints...)
+		wsEndpoints := set.Of(networkInfo.WSEndpoints...)
+		for previousEndpoint := range previousEndpoints {
+			rpcEndpoints.Remove(models.GetRPCEndpoint(previousEndpoint, blockchainID))
+			wsEndpoints.Remove(models.GetWSEndpoint(previousEndpoint, blockchainID))
+		}
+		for _, host := range hosts {
+			httpsEndpoint, ok := httpsEndpoints[host.GetCloudID()]
+			if !ok {
+				continue
+			}
+			httpEndpoint := GetAvalancheGoEndpoint(host.IP)
+			rpcEndpoints.Remove(models.GetRPCEndpoint(httpEndpoint, blockchainID))
+			wsEndpoints.Remove(models.GetWSEndpoint(httpEndpoint, blockchainID))
+			rpcEndpoints.Add(models.GetRPCEndpoint(httpsEndpoint, blockchainID))
+			wsEndpoints.Add(models.GetWSEndpoint(httpsEndpoint, blockchainID))
+		}
+		networkInfo.RPCEndpoints = rpcEndpoints.List()
+		networkInfo.WSEndpoints = wsEndpoints.List()
+		sc.Networks[clusterConfig.Network.Name()] = networkInfo
+		if err := app.UpdateSidecar(&sc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRPCAuthTokens returns the RPC auth tokens of [clusterName], that map blockchain IDs to
+// the tokens required by the cluster HTTPS endpoints. Tokens not yet moved from the clusters
+// config to the cluster secrets are also included
+func GetRPCAuthTokens(app *application.Avalanche, clusterName string, clusterConfig models.ClusterConfig) (map[string]string, error) {
+	secrets, err := app.LoadClusterSecrets(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	tokens := maps.Clone(clusterConfig.RPCAuthTokens)
+	if tokens == nil {
+		tokens = map[string]string{}
+	}
+	maps.Copy(tokens, secrets.RPCAuthTokens)
+	return tokens, nil
+}
+
+// SetRPCAuthTokens saves [tokens] as the RPC auth tokens of [clusterName], on the cluster
+// secrets file
+func SetRPCAuthTokens(app *application.Avalanche, clusterName string, tokens map[string]string) error {
+	secrets, err := app.LoadClusterSecrets(clusterName)
+	if err != nil {
+		return err
+	}
+	secrets.RPCAuthTokens = tokens
+	if err := app.WriteClusterSecrets(clusterName, secrets); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if len(clusterConfig.RPCAuthTokens) == 0 {
+		return nil
+	}
+	clusterConfig.RPCAuthTokens = nil
+	return app.SetClusterConfig(clusterName, clusterConfig)
+}
+
+// UseRPCAuthTokens registers the RPC auth tokens of all the clusters, so the RPC clients
+// dialed by the CLI send them to the locked down HTTPS endpoints
+func UseRPCAuthTokens(app *application.Avalanche) error {
+	clustersConfig, err := app.GetClustersConfig()
+	if err != nil {
+		return err
+	}
+	for clusterName, clusterConfig := range clustersConfig.Clusters {
+		if len(clusterConfig.HTTPSEndpoints) == 0 {
+			continue
+		}
+		tokens, err := GetRPCAuthTokens(app, clusterName, clusterConfig)
+		if err != nil {
+			return err
+		}
+		for _, endpoint := range clusterConfig.HTTPSEndpoints {
+			for blockchainID, token := range tokens {
+				if err := rpcauth.Tokens.Register(endpoint, blockchainID, token); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetClusterHTTPSEndpoints(t *testing.T) {
+	require := require.New(t)
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil, nil)
+
+	network := models.NewFujiNetwork()
+	blockchainID := ids.GenerateTestID()
+	hosts := []*models.Host{
+		{NodeID: "aws_node_i-1", IP: "10.0.0.1"},
+		{NodeID: "aws_node_i-2", IP: "10.0.0.2"},
+	}
+	httpEndpoints := []string{GetAvalancheGoEndpoint(hosts[0].IP), GetAvalancheGoEndpoint(hosts[1].IP)}
+	require.NoError(app.CreateSidecar(&models.Sidecar{
+		Name: "chain",
+		Networks: map[string]models.NetworkData{
+			network.Name(): {
+				BlockchainID: blockchainID,
+				RPCEndpoints: []string{
+					models.GetRPCEndpoint(httpEndpoints[0], blockchainID.String()),
+					models.GetRPCEndpoint(httpEndpoints[1], blockchainID.String()),
+				},
+				WSEndpoints: []string{
+					models.GetWSEndpoint(httpEndpoints[0], blockchainID.String()),
+					models.GetWSEndpoint(httpEndpoints[1], blockchainID.String()),
+				},
+			},
+		},
+	}))
+	require.NoError(app.SetClusterConfig("cluster", models.ClusterConfig{
+		Nodes:   []string{"i-1", "i-2"},
+		Network: network,
+		Subnets: []string{"chain"},
+	}))
+
+	// only the endpoint of the first host is served over HTTPS
+	httpsEndpoint := GetHTTPSEndpoint("rpc1.example.com")
+	require.NoError(SetClusterHTTPSEndpoints(app, "cluster", hosts, map[string]string{"i-1": httpsEndpoint}, "ops@example.com", ""))
+	clusterConfig, err := app.GetClusterConfig("cluster")
+	require.NoError(err)
+	require.Equal(map[string]string{"i-1": httpsEndpoint}, clusterConfig.HTTPSEndpoints)
+	require.Equal("ops@example.com", clusterConfig.HTTPSEmail)
+	require.Empty(clusterConfig.HTTPSLoadBalancer)
+	sc, err := app.LoadSidecar("chain")
+	require.NoError(err)
+	require.ElementsMatch([]string{
+		models.GetRPCEndpoint(httpsEndpoint, blockchainID.String()),
+		models.GetRPCEndpoint(httpEndpoints[1], blockchainID.String()),
+	}, sc.Networks[network.Name()].RPCEndpoints)
+	require.ElementsMatch([]string{
+		models.GetWSEndpoint(httpsEndpoint, blockchainID.String()),
+		models.GetWSEndpoint(httpEndpoints[1], blockchainID.String()),
+	}, sc.Networks[network.Name()].WSEndpoints)
+
+	// a load balancer serves both hosts at a single endpoint. The email is kept
+	lbEndpoint := GetHTTPSEndpoint("rpc.example.com")
+	require.NoError(SetClusterHTTPSEndpoints(app, "cluster", hosts, map[string]string{"i-1": lbEndpoint, "i-2": lbEndpoint}, "", "i-3"))
+	clusterConfig, err = app.GetClusterConfig("cluster")
+	require.NoError(err)
+	require.Equal(map[string]string{"i-1": lbEndpoint, "i-2": lbEndpoint}, clusterConfig.HTTPSEndpoints)
+	require.Equal("ops@example.com", clusterConfig.HTTPSEmail)
+	require.Equal("i-3", clusterConfig.HTTPSLoadBalancer)
+	sc, err = app.LoadSidecar("chain")
+	require.NoError(err)
+	require.Equal([]string{models.GetRPCEndpoint(lbEndpoint, blockchainID.String())}, sc.Networks[network.Name()].RPCEndpoints)
+	require.Equal([]string{models.GetWSEndpoint(lbEndpoint, blockchainID.String())}, sc.Networks[network.Name()].WSEndpoints)
+}
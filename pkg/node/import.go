@@ -0,0 +1,289 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/remoteconfig"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/api/info"
+)
+
+const (
+	// DockerComposeServiceManager is the avalanchego setup of the CLI, that the node
+	// management commands expect
+	DockerComposeServiceManager = "docker-compose"
+	SystemdServiceManager       = "systemd"
+	// ProcessServiceManager is an avalanchego process not managed by a known service manager
+	ProcessServiceManager = "process"
+)
+
+// ImportedHost describes an avalanchego installation not created by the CLI, as found
+// by InspectHost. Paths are the ones on the host
+type ImportedHost struct {
+	AvalancheGoVersion string
+	NodeID             string
+	NetworkID          uint32
+	TrackedSubnets     []string
+	ConfigFile         string // avalanchego config file (if any)
+	DataDir            string
+	StakingCertFile    string
+	StakingKeyFile     string
+	SignerKeyFile      string
+	ServiceManager     string
+}
+
+// ManagedLayout returns true if the installation follows the CLI setup, so that the
+// commands that modify the node setup (eg node sync, node upgrade) can be used on it
+func (ih ImportedHost) ManagedLayout() bool {
+	return ih.ServiceManager == DockerComposeServiceManager
+}
+
+// InspectHost finds the version, node ID, network, tracked subnets and file locations of the
+// avalanchego installation running on [host], from its API, process arguments and config file.
+// Also returns the staking certificate of the node, that is checked against the node ID
+func InspectHost(host *models.Host) (*ImportedHost, []byte, error) {
+	resp, err := ssh.RunSSHCheckAvalancheGoVersion(host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failure querying avalanchego API on %s: %w", host.IP, err)
+	}
+	versionReply, err := ParseNodeVersionOutput(resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err = ssh.RunSSHGetNodeID(host)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodeIDReply := info.GetNodeIDReply{}
+	if err := parseAPIReply(resp, &nodeIDReply); err != nil {
+		return nil, nil, err
+	}
+	resp, err = ssh.PostOverSSH(host, "", "{\"jsonrpc\":\"2.0\", \"id\":1,\"method\" :\"info.getNetworkID\"}")
+	if err != nil {
+		return nil, nil, err
+	}
+	networkIDReply := info.GetNetworkIDReply{}
+	if err := parseAPIReply(resp, &networkIDReply); err != nil {
+		return nil, nil, err
+	}
+	serviceManager := ProcessServiceManager
+	if composeFileExists, _ := host.FileExists(utils.GetRemoteComposeFile()); composeFileExists {
+		serviceManager = DockerComposeServiceManager
+	} else if output, err := host.Command("systemctl is-active avalanchego", nil, constants.SSHFileOpsTimeout); err == nil && strings.TrimSpace(string(output)) == "active" {
+		serviceManager = SystemdServiceManager
+	}
+	// process arguments are only meaningful outside of the CLI containers
+	args := map[string]string{}
+	if serviceManager != DockerComposeServiceManager {
+		output, err := host.Command("ps -o args= -C avalanchego || true", nil, constants.SSHFileOpsTimeout)
+		if err != nil {
+			return nil, nil, err
+		}
+		cmdLine, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+		args = ParseAvalancheGoArgs(cmdLine)
+	}
+	home := host.ExpandHome("")
+	configFile := ResolveImportedHost(home, args, serviceManager).ConfigFile
+	if exists, _ := host.FileExists(configFile); exists {
+		configBytes, err := host.ReadFileBytes(configFile, constants.SSHFileOpsTimeout)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failure reading avalanchego config file %s on %s: %w", configFile, host.IP, err)
+		}
+		config := map[string]interface{}{}
+		if err := json.Unmarshal(configBytes, &config); err != nil {
+			return nil, nil, fmt.Errorf("invalid avalanchego config file %s on %s: %w", configFile, host.IP, err)
+		}
+		// command line arguments take precedence over the config file
+		for key, value := range config {
+			if _, ok := args[key]; !ok {
+				args[key] = fmt.Sprint(value)
+			}
+		}
+	} else {
+		if _, ok := args["config-file"]; ok {
+			return nil, nil, fmt.Errorf("avalanchego config file %s not found on %s", configFile, host.IP)
+		}
+		configFile = ""
+	}
+	importedHost := ResolveImportedHost(home, args, serviceManager)
+	importedHost.ConfigFile = configFile
+	importedHost.AvalancheGoVersion = versionReply.Version
+	importedHost.NodeID = nodeIDReply.NodeID.String()
+	importedHost.NetworkID = uint32(networkIDReply.NetworkID)
+	certBytes, err := host.ReadFileBytes(importedHost.StakingCertFile, constants.SSHFileOpsTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failure reading staking certificate %s on %s: %w", importedHost.StakingCertFile, host.IP, err)
+	}
+	certNodeID, err := utils.ToNodeID(certBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid staking certificate %s on %s: %w", importedHost.StakingCertFile, host.IP, err)
+	}
+	if certNodeID.String() != importedHost.NodeID {
+		return nil, nil, fmt.Errorf(
+			"staking certificate %s on %s belongs to node %s, but the running node is %s",
+			importedHost.StakingCertFile,
+			host.IP,
+			certNodeID,
+			importedHost.NodeID,
+		)
+	}
+	return importedHost, certBytes, nil
+}
+
+// ParseAvalancheGoArgs returns the flags given on the avalanchego command line [cmdLine],
+// as --flag=value or --flag value
+func ParseAvalancheGoArgs(cmdLine string) map[string]string {
+	args := map[string]string{}
+	fields := strings.Fields(cmdLine)
+	for i := 0; i < len(fields); i++ {
+		if !strings.HasPrefix(fields[i], "-") {
+			continue
+		}
+		flag := strings.TrimLeft(fields[i], "-")
+		if name, value, found := strings.Cut(flag, "="); found {
+			args[name] = value
+			continue
+		}
+		if i+1 < len(fields) && !strings.HasPrefix(fields[i+1], "-") {
+			args[flag] = fields[i+1]
+			i++
+			continue
+		}
+		args[flag] = "true"
+	}
+	return args
+}
+
+// ResolveImportedHost sets the file locations and tracked subnets of an avalanchego installation
+// from its [args] (command line flags and config file keys), using the avalanchego defaults
+// for the user [home] dir. For the CLI setup, the CLI locations are used instead, as the
+// paths seen by avalanchego are the ones inside its container
+func ResolveImportedHost(home string, args map[string]string, serviceManager string) *ImportedHost {
+	expand := func(path string) string {
+		path = strings.ReplaceAll(path, "$HOME", home)
+		if strings.HasPrefix(path, "~") {
+			path = filepath.Join(home, path[1:])
+		}
+		return path
+	}
+	importedHost := &ImportedHost{
+		ServiceManager: serviceManager,
+	}
+	if serviceManager == DockerComposeServiceManager {
+		importedHost.ConfigFile = remoteconfig.GetRemoteAvalancheNodeConfig()
+		importedHost.DataDir = constants.CloudNodeConfigBasePath
+		importedHost.StakingCertFile = filepath.Join(constants.CloudNodeStakingPath, constants.StakerCertFileName)
+		importedHost.StakingKeyFile = filepath.Join(constants.CloudNodeStakingPath, constants.StakerKeyFileName)
+		importedHost.SignerKeyFile = filepath.Join(constants.CloudNodeStakingPath, constants.BLSKeyFileName)
+	} else {
+		importedHost.DataDir = filepath.Join(home, ".avalanchego")
+		if dataDir, ok := args["data-dir"]; ok {
+			importedHost.DataDir = expand(dataDir)
+		}
+		importedHost.ConfigFile = filepath.Join(importedHost.DataDir, "configs", constants.NodeFileName)
+		if configFile, ok := args["config-file"]; ok {
+			importedHost.ConfigFile = expand(configFile)
+		}
+		stakingDir := filepath.Join(importedHost.DataDir, "staking")
+		importedHost.StakingCertFile = filepath.Join(stakingDir, constants.StakerCertFileName)
+		if certFile, ok := args["staking-tls-cert-file"]; ok {
+			importedHost.StakingCertFile = expand(certFile)
+		}
+		importedHost.StakingKeyFile = filepath.Join(stakingDir, constants.StakerKeyFileName)
+		if keyFile, ok := args["staking-tls-key-file"]; ok {
+			importedHost.StakingKeyFile = expand(keyFile)
+		}
+		importedHost.SignerKeyFile = filepath.Join(stakingDir, constants.BLSKeyFileName)
+		if signerKeyFile, ok := args["staking-signer-key-file"]; ok {
+			importedHost.SignerKeyFile = expand(signerKeyFile)
+		}
+	}
+	if trackSubnets := strings.Trim(args["track-subnets"], "[]"); trackSubnets != "" {
+		for _, subnetID := range strings.FieldsFunc(trackSubnets, func(r rune) bool { return r == ',' || r == ' ' }) {
+			importedHost.TrackedSubnets = append(importedHost.TrackedSubnets, subnetID)
+		}
+	}
+	return importedHost
+}
+
+// parseAPIReply decodes the result of the avalanchego API response [resp] into [reply]
+func parseAPIReply(resp []byte, reply interface{}) error {
+	response := struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return errors.New(response.Error.Message)
+	}
+	return json.Unmarshal(response.Result, reply)
+}
+
+// SaveImportedHost saves the inspection of the host with [cloudID], done on node import
+func SaveImportedHost(app *application.Avalanche, cloudID string, importedHost *ImportedHost) error {
+	bs, err := json.MarshalIndent(importedHost, "", "    ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(app.GetNodeInstanceDirPath(cloudID), constants.ImportedHostFileName)
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, bs, constants.WriteReadReadPerms)
+}
+
+// LoadImportedHost loads the inspection of the host with [cloudID]. Returns false if the
+// host was not imported with node import
+func LoadImportedHost(app *application.Avalanche, cloudID string) (*ImportedHost, bool, error) {
+	path := filepath.Join(app.GetNodeInstanceDirPath(cloudID), constants.ImportedHostFileName)
+	if !utils.FileExists(path) {
+		return nil, false, nil
+	}
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	importedHost := &ImportedHost{}
+	if err := json.Unmarshal(bs, importedHost); err != nil {
+		return nil, false, fmt.Errorf("invalid imported host file %s: %w", path, err)
+	}
+	return importedHost, true, nil
+}
+
+// CheckManagedLayout fails if any of [hosts] was imported with node import and does not
+// follow the CLI setup, as [operation] would not find the node files or services
+func CheckManagedLayout(app *application.Avalanche, hosts []*models.Host, operation string) error {
+	unmanaged := []string{}
+	for _, host := range hosts {
+		importedHost, found, err := LoadImportedHost(app, host.GetCloudID())
+		if err != nil {
+			return err
+		}
+		if found && !importedHost.ManagedLayout() {
+			unmanaged = append(unmanaged, fmt.Sprintf("%s (%s)", host.GetCloudID(), importedHost.ServiceManager))
+		}
+	}
+	if len(unmanaged) > 0 {
+		return fmt.Errorf(
+			"%s requires the CLI node setup, but imported host(s) %s run avalanchego with their own setup",
+			operation,
+			strings.Join(unmanaged, ", "),
+		)
+	}
+	return nil
+}
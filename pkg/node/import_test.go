@@ -0,0 +1,54 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAvalancheGoArgs(t *testing.T) {
+	require := require.New(t)
+	args := ParseAvalancheGoArgs("/opt/avalanchego/avalanchego --config-file=/etc/avalanchego/node.json --data-dir /data/avalanchego --public-ip-resolution-service opendns --index-enabled")
+	require.Equal(map[string]string{
+		"config-file":                  "/etc/avalanchego/node.json",
+		"data-dir":                     "/data/avalanchego",
+		"public-ip-resolution-service": "opendns",
+		"index-enabled":                "true",
+	}, args)
+	require.Empty(ParseAvalancheGoArgs(""))
+}
+
+func TestResolveImportedHost(t *testing.T) {
+	require := require.New(t)
+	importedHost := ResolveImportedHost("/home/admin", map[string]string{}, SystemdServiceManager)
+	require.Equal(&ImportedHost{
+		ConfigFile:      "/home/admin/.avalanchego/configs/node.json",
+		DataDir:         "/home/admin/.avalanchego",
+		StakingCertFile: "/home/admin/.avalanchego/staking/staker.crt",
+		StakingKeyFile:  "/home/admin/.avalanchego/staking/staker.key",
+		SignerKeyFile:   "/home/admin/.avalanchego/staking/signer.key",
+		ServiceManager:  SystemdServiceManager,
+	}, importedHost)
+
+	importedHost = ResolveImportedHost("/home/admin", map[string]string{
+		"data-dir":              "$HOME/avalanche",
+		"staking-tls-cert-file": "~/certs/node.crt",
+		"track-subnets":         "2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM,29uVeLPJB1eQJkzRemU8g8wZDw5uJRqpab5U2mX9euieVwiEbL",
+	}, ProcessServiceManager)
+	require.Equal("/home/admin/avalanche", importedHost.DataDir)
+	require.Equal("/home/admin/avalanche/configs/node.json", importedHost.ConfigFile)
+	require.Equal("/home/admin/certs/node.crt", importedHost.StakingCertFile)
+	require.Equal("/home/admin/avalanche/staking/staker.key", importedHost.StakingKeyFile)
+	require.Equal([]string{
+		"2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM",
+		"29uVeLPJB1eQJkzRemU8g8wZDw5uJRqpab5U2mX9euieVwiEbL",
+	}, importedHost.TrackedSubnets)
+
+	importedHost = ResolveImportedHost("/home/ubuntu", map[string]string{"track-subnets": ""}, DockerComposeServiceManager)
+	require.True(importedHost.ManagedLayout())
+	require.Equal("/home/ubuntu/.avalanchego/configs/node.json", importedHost.ConfigFile)
+	require.Equal("/home/ubuntu/.avalanchego/staking/staker.crt", importedHost.StakingCertFile)
+	require.Empty(importedHost.TrackedSubnets)
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/bincache"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/evm"
@@ -248,6 +249,9 @@ func StartLocalNode(
 		avalanchegoBinaryPath = filepath.Join(avagoDir, "avalanchego")
 		ux.Logger.PrintToUser("Using AvalancheGo version: %s", avalancheGoVersion)
 	}
+	if err := bincache.New(app.GetBinaryCacheDir()).Ref(bincache.ClusterOwner(clusterName), avalanchegoBinaryPath); err != nil {
+		return err
+	}
 	serverLogPath := filepath.Join(rootDir, "server.log")
 	sd := subnet.NewLocalDeployer(app, avalancheGoVersion, avalanchegoBinaryPath, "", true)
 	if err := sd.StartServer(
@@ -706,6 +710,9 @@ func DestroyLocalNode(app *application.Avalanche, clusterName string) error {
 	if err := app.WriteClustersConfigFile(&clustersConfig); err != nil {
 		return err
 	}
+	if err := bincache.New(app.GetBinaryCacheDir()).Unref(bincache.ClusterOwner(clusterName)); err != nil {
+		return err
+	}
 
 	ux.Logger.GreenCheckmarkToUser("Local node %s cleaned up.", clusterName)
 	return nil
@@ -3,8 +3,11 @@
 package node
 
 import (
+	"path/filepath"
+
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 )
@@ -45,3 +48,33 @@ func GetICMRelayerHost(app *application.Avalanche, clusterName string) (*models.
 	}
 	return GetHostWithCloudID(app, clusterName, relayerCloudID)
 }
+
+// ApplyClusterSSHConfig updates the cluster inventories so that every host uses
+// the SSH settings stored in the cluster config
+func ApplyClusterSSHConfig(app *application.Avalanche, clusterName string) error {
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	inventoryDirs := []string{
+		app.GetAnsibleInventoryDirPath(clusterName),
+		app.GetMonitoringInventoryDir(clusterName),
+		app.GetLoadTestInventoryDir(clusterName),
+	}
+	for _, inventoryDir := range inventoryDirs {
+		if !utils.FileExists(filepath.Join(inventoryDir, constants.AnsibleHostInventoryFileName)) {
+			continue
+		}
+		hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(inventoryDir)
+		if err != nil {
+			return err
+		}
+		for _, host := range hosts {
+			host.SetSSHConfig(clusterConfig.GetHostSSHConfig(host.GetCloudID()))
+		}
+		if err := ansible.WriteAnsibleInventory(inventoryDir, hosts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
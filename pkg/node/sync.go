@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
@@ -34,6 +35,9 @@ func SyncSubnet(app *application.Avalanche, clusterName, blockchainName string,
 		return err
 	}
 	defer DisconnectHosts(hosts)
+	if err := CheckManagedLayout(app, hosts, "node sync"); err != nil {
+		return err
+	}
 	if !avoidChecks {
 		if err := CheckHostsAreBootstrapped(hosts); err != nil {
 			return err
@@ -129,6 +133,9 @@ func trackSubnet(
 			); err != nil {
 				nodeResults.AddResult(host.NodeID, nil, err)
 			}
+			if err := ssh.RunSSHApplyHostNodeConfig(host, clusterConfig.GetHostNodeConfig(host.GetCloudID())); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+			}
 			if err := ssh.RunSSHSyncSubnetData(app, host, network, blockchainName); err != nil {
 				nodeResults.AddResult(host.NodeID, nil, err)
 			}
@@ -168,6 +175,67 @@ func trackSubnet(
 	return app.UpdateSidecar(&sc)
 }
 
+// UntrackSubnet makes the nodes of the given cluster to stop tracking [blockchainName],
+// restarting them with a node config that only includes the remaining blockchains
+func UntrackSubnet(app *application.Avalanche, clusterName, blockchainName string) error {
+	if err := CheckCluster(app, clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if clusterConfig.Local {
+		return fmt.Errorf("stop tracking a blockchain is not supported on local cluster %s", clusterName)
+	}
+	if !slices.Contains(clusterConfig.Subnets, blockchainName) {
+		return fmt.Errorf("cluster %s is not tracking blockchain %s", clusterName, blockchainName)
+	}
+	remainingSubnets := utils.Filter(clusterConfig.Subnets, func(s string) bool {
+		return s != blockchainName
+	})
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	defer DisconnectHosts(hosts)
+	network := clusterConfig.Network
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, host *models.Host) {
+			defer wg.Done()
+			// do not touch the config of a node that could not be stopped
+			if err := ssh.RunSSHStopNode(host); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, fmt.Errorf("failure stopping node: %w", err))
+				return
+			}
+			if err := ssh.RunSSHRenderAvalancheNodeConfig(
+				app,
+				host,
+				network,
+				remainingSubnets,
+				clusterConfig.IsAPIHost(host.GetCloudID()),
+			); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+			} else if err := ssh.RunSSHApplyHostNodeConfig(host, clusterConfig.GetHostNodeConfig(host.GetCloudID())); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+			}
+			// start the node again even on config failures, to not leave it down
+			if err := ssh.RunSSHStartNode(host); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+			}
+		}(&wgResults, host)
+	}
+	wg.Wait()
+	if wgResults.HasErrors() {
+		return fmt.Errorf("failed to stop tracking blockchain for node(s) %s", wgResults.GetErrorHostMap())
+	}
+	clusterConfig.Subnets = remainingSubnets
+	return app.SetClusterConfig(clusterName, clusterConfig)
+}
+
 func CheckHostsAreBootstrapped(hosts []*models.Host) error {
 	notBootstrappedNodes, err := GetNotBootstrappedNodes(hosts)
 	if err != nil {
@@ -233,3 +301,19 @@ func parseBootstrappedOutput(byteValue []byte) (bool, error) {
 	}
 	return false, errors.New("unable to parse node bootstrap status")
 }
+
+// ParseSubnetSyncOutput parses the platform.getBlockchainStatus response of a node
+func ParseSubnetSyncOutput(byteValue []byte) (string, error) {
+	var result map[string]interface{}
+	if err := json.Unmarshal(byteValue, &result); err != nil {
+		return "", err
+	}
+	statusInterface, ok := result["result"].(map[string]interface{})
+	if ok {
+		status, ok := statusInterface["status"].(string)
+		if ok {
+			return status, nil
+		}
+	}
+	return "", errors.New("unable to parse subnet sync status")
+}
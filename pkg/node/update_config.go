@@ -0,0 +1,106 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"golang.org/x/exp/slices"
+)
+
+// BlockchainConfigUpdate is the outcome of updating the blockchain configs of a node
+type BlockchainConfigUpdate struct {
+	Changed   bool
+	Restarted bool
+}
+
+// UpdateBlockchainConfigs uploads the current node, subnet and chain configs of [blockchainName]
+// to all avalanchego hosts of [clusterName]. Per node chain configs are matched against the
+// host cloud ID or IP. AvalancheGo only reads these files at startup, so the avalanchego
+// service is restarted on the hosts whose files changed, unless [skipRestart] is set.
+// Results are returned by host cloud ID
+func UpdateBlockchainConfigs(
+	app *application.Avalanche,
+	clusterName string,
+	blockchainName string,
+	skipRestart bool,
+) (*models.NodeResults, error) {
+	if err := CheckCluster(app, clusterName); err != nil {
+		return nil, err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(clusterConfig.Subnets, blockchainName) {
+		return nil, fmt.Errorf("cluster %s is not tracking blockchain %s", clusterName, blockchainName)
+	}
+	perNodeChainConfigs, err := loadPerNodeChainConfigs(app, blockchainName)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	defer DisconnectHosts(hosts)
+	hosts = utils.Filter(hosts, func(h *models.Host) bool { return clusterConfig.IsAvalancheGoHost(h.GetCloudID()) })
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, host *models.Host) {
+			defer wg.Done()
+			perNodeChainConfig := perNodeChainConfigs[host.GetCloudID()]
+			if perNodeChainConfig == nil {
+				perNodeChainConfig = perNodeChainConfigs[host.IP]
+			}
+			changed, err := ssh.RunSSHUpdateBlockchainConfigs(app, host, clusterConfig.Network, blockchainName, perNodeChainConfig)
+			if err != nil {
+				nodeResults.AddResult(host.GetCloudID(), BlockchainConfigUpdate{}, err)
+				return
+			}
+			if !changed || skipRestart {
+				nodeResults.AddResult(host.GetCloudID(), BlockchainConfigUpdate{Changed: changed}, nil)
+				return
+			}
+			if err := ssh.RunSSHRestartNode(host); err != nil {
+				nodeResults.AddResult(host.GetCloudID(), BlockchainConfigUpdate{Changed: true}, err)
+				return
+			}
+			nodeResults.AddResult(host.GetCloudID(), BlockchainConfigUpdate{Changed: true, Restarted: true}, nil)
+		}(&wgResults, host)
+	}
+	wg.Wait()
+	return &wgResults, nil
+}
+
+// loadPerNodeChainConfigs returns the per node chain configs of [blockchainName], if any
+func loadPerNodeChainConfigs(app *application.Avalanche, blockchainName string) (map[string][]byte, error) {
+	perNodeChainConfigs := map[string][]byte{}
+	perNodeChainConfigPath := filepath.Join(app.GetSubnetDir(), blockchainName, constants.PerNodeChainConfigFileName)
+	if !utils.FileExists(perNodeChainConfigPath) {
+		return perNodeChainConfigs, nil
+	}
+	perNodeChainConfig, err := utils.ReadJSON(perNodeChainConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	for nodeName, cfg := range perNodeChainConfig {
+		cfgBytes, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		perNodeChainConfigs[nodeName] = cfgBytes
+	}
+	return perNodeChainConfigs, nil
+}
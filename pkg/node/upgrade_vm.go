@@ -0,0 +1,89 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package node
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"golang.org/x/exp/slices"
+)
+
+// UpgradeClusterVM installs the VM binary described by [sc] on all avalanchego hosts
+// of [clusterName], performing the steps required by [migration] on each of them:
+// the node is stopped, its chain data is backed up (and moved away if a resync is needed),
+// the new binary is installed, and the node is started again.
+// Results are returned by host cloud ID, with the backup dir as result, if any
+func UpgradeClusterVM(
+	app *application.Avalanche,
+	clusterName string,
+	sc models.Sidecar,
+	migration vm.VMMigration,
+) (*models.NodeResults, error) {
+	if err := CheckCluster(app, clusterName); err != nil {
+		return nil, err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(clusterConfig.Subnets, sc.Name) {
+		return nil, fmt.Errorf("cluster %s is not tracking blockchain %s", clusterName, sc.Name)
+	}
+	blockchainID := sc.Networks[clusterConfig.Network.Name()].BlockchainID
+	if migration.Kind != vm.MigrationNone && blockchainID == ids.Empty {
+		return nil, fmt.Errorf("blockchain %s is not deployed on cluster %s network", sc.Name, clusterName)
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	defer DisconnectHosts(hosts)
+	hosts = utils.Filter(hosts, func(h *models.Host) bool { return clusterConfig.IsAvalancheGoHost(h.GetCloudID()) })
+	backupName := fmt.Sprintf("%s-%s", sc.Name, time.Now().UTC().Format("20060102150405"))
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, host *models.Host) {
+			defer wg.Done()
+			if err := ssh.RunSSHStopNode(host); err != nil {
+				nodeResults.AddResult(host.GetCloudID(), "", fmt.Errorf("failed to stop node: %w", err))
+				return
+			}
+			backupDir := ""
+			if migration.Kind != vm.MigrationNone {
+				dir, err := ssh.RunSSHBackupChainData(
+					host,
+					blockchainID.String(),
+					backupName,
+					migration.Kind == vm.MigrationResync,
+				)
+				if err != nil {
+					nodeResults.AddResult(host.GetCloudID(), "", err)
+					return
+				}
+				backupDir = dir
+			}
+			if err := ssh.RunSSHCreatePlugin(host, sc); err != nil {
+				nodeResults.AddResult(host.GetCloudID(), backupDir, fmt.Errorf("failed to install VM: %w", err))
+				return
+			}
+			if err := ssh.RunSSHStartNode(host); err != nil {
+				nodeResults.AddResult(host.GetCloudID(), backupDir, fmt.Errorf("failed to start node: %w", err))
+				return
+			}
+			nodeResults.AddResult(host.GetCloudID(), backupDir, nil)
+		}(&wgResults, host)
+	}
+	wg.Wait()
+	return &wgResults, nil
+}
@@ -0,0 +1,329 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/cobrautils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/signing"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	ErrPhraseMismatch       = errors.New("confirmation phrase does not match, aborted")
+	ErrNotEnoughApprovals   = errors.New("not enough approvals")
+	policyFields            = []string{"networks", "phrase", "approvals", "wait"}
+	secretFlagNameFragments = []string{"private-key", "password", "secret", "token"}
+)
+
+// ConfirmationPolicy sets the guardrails a command goes through before running,
+// on top of its own prompts
+type ConfirmationPolicy struct {
+	// Networks restricts the policy to the given networks. The policy also applies
+	// when the network is not selected by a flag
+	Networks []string `json:"networks,omitempty"`
+	// Phrase must be typed to confirm the operation
+	Phrase string `json:"phrase,omitempty"`
+	// Approvals is the number of different trusted signing keys that must sign the operation
+	Approvals int `json:"approvals,omitempty"`
+	// Wait is the waiting period before running the operation, that can be aborted meanwhile
+	Wait string `json:"wait,omitempty"`
+}
+
+// Validate checks the policy settings
+func (p ConfirmationPolicy) Validate() error {
+	if p.Approvals < 0 {
+		return fmt.Errorf("invalid number of approvals %d", p.Approvals)
+	}
+	if _, err := p.WaitDuration(); err != nil {
+		return err
+	}
+	if p.Phrase == "" && p.Approvals == 0 && p.Wait == "" {
+		return errors.New("a policy needs a phrase, approvals, or a waiting period")
+	}
+	return nil
+}
+
+// WaitDuration returns the waiting period of the policy
+func (p ConfirmationPolicy) WaitDuration() (time.Duration, error) {
+	if p.Wait == "" {
+		return 0, nil
+	}
+	wait, err := time.ParseDuration(p.Wait)
+	if err != nil || wait < 0 {
+		return 0, fmt.Errorf("invalid waiting period %q", p.Wait)
+	}
+	return wait, nil
+}
+
+// AppliesTo indicates if the policy must be enforced for an operation on [network].
+// An empty [network] means it is not known yet, and the policy applies
+func (p ConfirmationPolicy) AppliesTo(network string) bool {
+	if len(p.Networks) == 0 || network == "" {
+		return true
+	}
+	for _, policyNetwork := range p.Networks {
+		if strings.EqualFold(policyNetwork, network) {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the policy
+func (p ConfirmationPolicy) String() string {
+	parts := []string{}
+	if len(p.Networks) > 0 {
+		parts = append(parts, "networks: "+strings.Join(p.Networks, ","))
+	}
+	if p.Phrase != "" {
+		parts = append(parts, fmt.Sprintf("phrase: %q", p.Phrase))
+	}
+	if p.Approvals > 0 {
+		parts = append(parts, fmt.Sprintf("approvals: %d", p.Approvals))
+	}
+	if p.Wait != "" {
+		parts = append(parts, "wait: "+p.Wait)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// policyFromMap decodes the policy fields of [m], a command entry of the config.
+// Returns false if [m] has no policy fields, eg because it only has policies
+// for subcommands
+func policyFromMap(m map[string]interface{}) (ConfirmationPolicy, bool, error) {
+	fields := map[string]interface{}{}
+	for _, field := range policyFields {
+		if value, ok := m[field]; ok {
+			fields[field] = value
+		}
+	}
+	if len(fields) == 0 {
+		return ConfirmationPolicy{}, false, nil
+	}
+	bs, err := json.Marshal(fields)
+	if err != nil {
+		return ConfirmationPolicy{}, false, err
+	}
+	var policy ConfirmationPolicy
+	if err := json.Unmarshal(bs, &policy); err != nil {
+		return ConfirmationPolicy{}, false, err
+	}
+	return policy, true, nil
+}
+
+// GetPolicy returns the policy configured for the command at [commandPath], as given
+// by cobrautils.CommandConfigPath
+func GetPolicy(app *application.Avalanche, commandPath string) (ConfirmationPolicy, bool, error) {
+	m := app.Conf.GetConfigStringMapValue(constants.ConfigConfirmationPoliciesKey + "." + commandPath)
+	policy, found, err := policyFromMap(m)
+	if err != nil {
+		return ConfirmationPolicy{}, false, fmt.Errorf("invalid confirmation policy for %s on the config file: %w", commandPath, err)
+	}
+	return policy, found, nil
+}
+
+// SetPolicy sets [policy] for the command at [commandPath]. An empty policy removes it
+func SetPolicy(app *application.Avalanche, commandPath string, policy ConfirmationPolicy) error {
+	key := constants.ConfigConfirmationPoliciesKey + "." + commandPath
+	m := map[string]interface{}{}
+	// keep the policies of the subcommands
+	for name, value := range app.Conf.GetConfigStringMapValue(key) {
+		if _, isSubcommand := value.(map[string]interface{}); isSubcommand {
+			m[name] = value
+		}
+	}
+	if len(policy.Networks) > 0 {
+		m["networks"] = policy.Networks
+	}
+	if policy.Phrase != "" {
+		m["phrase"] = policy.Phrase
+	}
+	if policy.Approvals > 0 {
+		m["approvals"] = policy.Approvals
+	}
+	if policy.Wait != "" {
+		m["wait"] = policy.Wait
+	}
+	if len(m) == 0 {
+		return app.Conf.SetConfigValue(key, "")
+	}
+	return app.Conf.SetConfigValue(key, m)
+}
+
+// GetPolicies returns all the configured policies, by command path
+func GetPolicies(app *application.Avalanche) (map[string]ConfirmationPolicy, error) {
+	policies := map[string]ConfirmationPolicy{}
+	if err := collectPolicies(app.Conf.GetConfigStringMapValue(constants.ConfigConfirmationPoliciesKey), "", policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func collectPolicies(m map[string]interface{}, commandPath string, policies map[string]ConfirmationPolicy) error {
+	if commandPath != "" {
+		policy, found, err := policyFromMap(m)
+		if err != nil {
+			return fmt.Errorf("invalid confirmation policy for %s on the config file: %w", commandPath, err)
+		}
+		if found {
+			policies[commandPath] = policy
+		}
+	}
+	for name, value := range m {
+		if sub, ok := value.(map[string]interface{}); ok {
+			subPath := name
+			if commandPath != "" {
+				subPath = commandPath + "." + name
+			}
+			if err := collectPolicies(sub, subPath, policies); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ResolveNetwork returns the network [cmd] is going to operate on, as named on the
+// policies, if it is selected by a flag or given by the cluster flag. Returns an
+// empty string if it is not known yet
+func ResolveNetwork(app *application.Avalanche, cmd *cobra.Command) string {
+	if network := cobrautils.SelectedNetwork(cmd); network != "" {
+		return network
+	}
+	flag := cmd.Flags().Lookup("cluster")
+	if flag == nil || flag.Value.String() == "" {
+		return ""
+	}
+	clusterConfig, err := app.GetClusterConfig(flag.Value.String())
+	if err != nil {
+		return ""
+	}
+	switch clusterConfig.Network.Kind {
+	case models.Local:
+		return "local"
+	case models.Devnet:
+		return "devnet"
+	case models.Fuji:
+		return "fuji"
+	case models.Mainnet:
+		return "mainnet"
+	}
+	return ""
+}
+
+// Operation describes the execution of [cmd] with [args] on [network] on the day of
+// [now], to be confirmed and approved. Values of flags that may hold secrets are
+// replaced by their hash, and the approval flags are left out. [network] is omitted
+// if empty
+func Operation(cmd *cobra.Command, args []string, network string, now time.Time) string {
+	parts := append([]string{cmd.CommandPath()}, args...)
+	flags := []string{}
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if !flag.Changed || flag.Name == constants.ApprovalFlag {
+			return
+		}
+		value := flag.Value.String()
+		for _, fragment := range secretFlagNameFragments {
+			if strings.Contains(flag.Name, fragment) {
+				hash := sha256.Sum256([]byte(value))
+				value = "sha256:" + hex.EncodeToString(hash[:])[:16]
+				break
+			}
+		}
+		flags = append(flags, fmt.Sprintf("--%s=%s", flag.Name, value))
+	})
+	sort.Strings(flags)
+	parts = append(parts, flags...)
+	if network != "" {
+		return fmt.Sprintf("%s (network %s, on %s UTC)", strings.Join(parts, " "), network, now.UTC().Format(time.DateOnly))
+	}
+	return fmt.Sprintf("%s (on %s UTC)", strings.Join(parts, " "), now.UTC().Format(time.DateOnly))
+}
+
+// Approve signs [operation] with [sk], returning the contents of the approval file
+func Approve(sk *signing.SecretKey, operation string) []byte {
+	return sk.Sign([]byte(operation), "approval of "+operation)
+}
+
+// CheckApprovals verifies that the approval files at [approvalPaths] contain signatures
+// of [operation] made by at least [required] different trusted signing keys
+func CheckApprovals(app *application.Avalanche, operation string, approvalPaths []string, required int) error {
+	keys, err := signing.TrustedKeys(app)
+	if err != nil {
+		return err
+	}
+	approvers := map[signing.KeyID]bool{}
+	for _, path := range approvalPaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sig, err := signing.ParseSignature(data)
+		if err != nil {
+			return fmt.Errorf("invalid approval %s: %w", path, err)
+		}
+		pk, err := signing.VerifyWithKeys(keys, []byte(operation), sig)
+		if err != nil {
+			return fmt.Errorf("invalid approval %s: %w", path, err)
+		}
+		approvers[pk.ID] = true
+	}
+	if len(approvers) < required {
+		return fmt.Errorf("%w: %d of %d trusted keys approved the operation", ErrNotEnoughApprovals, len(approvers), required)
+	}
+	return nil
+}
+
+// Enforce makes [operation] go through [policy]: approvals are checked first, then the
+// confirmation phrase is asked for, and lastly the waiting period is observed
+func Enforce(
+	app *application.Avalanche,
+	policy ConfirmationPolicy,
+	operation string,
+	approvalPaths []string,
+) error {
+	ux.Logger.PrintToUser("A confirmation policy applies to: %s", operation)
+	if policy.Approvals > 0 {
+		if err := CheckApprovals(app, operation, approvalPaths, policy.Approvals); err != nil {
+			ux.Logger.PrintToUser("")
+			ux.Logger.PrintToUser("%d approvals of trusted signing keys are needed. Each approver must run:", policy.Approvals)
+			ux.Logger.PrintToUser("  avalanche config approve %q --sign-key <secret key> --output <approval file>", operation)
+			ux.Logger.PrintToUser("and the operation must be run again with --%s <approval file> for each approval", constants.ApprovalFlag)
+			ux.Logger.PrintToUser("")
+			return err
+		}
+	}
+	if policy.Phrase != "" {
+		phrase, err := app.Prompt.CaptureString(fmt.Sprintf("Type %q to confirm", policy.Phrase))
+		if err != nil {
+			return err
+		}
+		if phrase != policy.Phrase {
+			return ErrPhraseMismatch
+		}
+	}
+	wait, err := policy.WaitDuration()
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		ux.Logger.PrintToUser("Waiting %s before running the operation. Press Ctrl+C to abort", wait)
+		time.Sleep(wait)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/signing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestApp(t *testing.T) *application.Avalanche {
+	t.Cleanup(func() {
+		viper.Set(constants.ConfigConfirmationPoliciesKey, nil)
+		viper.Set(constants.ConfigTrustedSigningKeysKey, nil)
+	})
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil, nil)
+	return app
+}
+
+func TestAppliesTo(t *testing.T) {
+	require := require.New(t)
+	require.True(ConfirmationPolicy{}.AppliesTo("fuji"))
+	mainnetPolicy := ConfirmationPolicy{Networks: []string{"mainnet"}}
+	require.True(mainnetPolicy.AppliesTo("mainnet"))
+	require.True(mainnetPolicy.AppliesTo(""))
+	require.False(mainnetPolicy.AppliesTo("fuji"))
+}
+
+func TestValidate(t *testing.T) {
+	require := require.New(t)
+	require.NoError(ConfirmationPolicy{Phrase: "yes"}.Validate())
+	require.NoError(ConfirmationPolicy{Wait: "30s"}.Validate())
+	require.Error(ConfirmationPolicy{}.Validate())
+	require.Error(ConfirmationPolicy{Wait: "soon"}.Validate())
+	require.Error(ConfirmationPolicy{Approvals: -1, Phrase: "yes"}.Validate())
+}
+
+func TestGetPolicies(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+	viper.Set(constants.ConfigConfirmationPoliciesKey, map[string]interface{}{
+		"network": map[string]interface{}{
+			"clean": map[string]interface{}{"phrase": "clean"},
+		},
+		"blockchain": map[string]interface{}{
+			"wait": "10s",
+			"deploy": map[string]interface{}{
+				"networks":  []interface{}{"mainnet"},
+				"approvals": 2,
+			},
+		},
+	})
+	policies, err := GetPolicies(app)
+	require.NoError(err)
+	require.Equal(map[string]ConfirmationPolicy{
+		"network.clean":     {Phrase: "clean"},
+		"blockchain":        {Wait: "10s"},
+		"blockchain.deploy": {Networks: []string{"mainnet"}, Approvals: 2},
+	}, policies)
+	policy, found, err := GetPolicy(app, "blockchain.deploy")
+	require.NoError(err)
+	require.True(found)
+	require.Equal(2, policy.Approvals)
+	_, found, err = GetPolicy(app, "network.start")
+	require.NoError(err)
+	require.False(found)
+}
+
+func TestOperation(t *testing.T) {
+	require := require.New(t)
+	root := &cobra.Command{Use: "avalanche"}
+	var approvals []string
+	root.PersistentFlags().StringSliceVar(&approvals, constants.ApprovalFlag, nil, "")
+	cmd := &cobra.Command{Use: "deploy", Run: func(*cobra.Command, []string) {}}
+	var mainnet bool
+	var privateKey, output string
+	cmd.Flags().BoolVar(&mainnet, "mainnet", false, "")
+	cmd.Flags().StringVar(&privateKey, "ewoq-private-key", "", "")
+	cmd.Flags().StringVar(&output, "output", "", "")
+	root.AddCommand(cmd)
+	root.SetArgs([]string{"deploy", "myChain", "--mainnet", "--ewoq-private-key", "secret", "--approval", "a.minisig"})
+	require.NoError(root.Execute())
+
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.FixedZone("", -3*60*60))
+	require.Equal(
+		"avalanche deploy myChain --ewoq-private-key=sha256:2bb80d537b1da3e3 --mainnet=true (on 2024-05-02 UTC)",
+		Operation(cmd, []string{"myChain"}, "", now),
+	)
+	require.Equal(
+		"avalanche deploy myChain --ewoq-private-key=sha256:2bb80d537b1da3e3 --mainnet=true (network mainnet, on 2024-05-02 UTC)",
+		Operation(cmd, []string{"myChain"}, "mainnet", now),
+	)
+}
+
+func TestResolveNetwork(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+	require.NoError(app.SetClusterConfig("cluster", models.ClusterConfig{Network: models.NewFujiNetwork()}))
+	resolve := func(args ...string) string {
+		cmd := &cobra.Command{Use: "deploy", Run: func(*cobra.Command, []string) {}}
+		var mainnet bool
+		var cluster string
+		cmd.Flags().BoolVar(&mainnet, "mainnet", false, "")
+		cmd.Flags().StringVar(&cluster, "cluster", "", "")
+		cmd.SetArgs(args)
+		require.NoError(cmd.Execute())
+		return ResolveNetwork(app, cmd)
+	}
+	require.Equal("mainnet", resolve("--mainnet"))
+	require.Equal("fuji", resolve("--cluster", "cluster"))
+	require.Equal("", resolve("--cluster", "unknown"))
+	require.Equal("", resolve())
+}
+
+func TestCheckApprovals(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+	sk1, err := signing.GenerateKey()
+	require.NoError(err)
+	sk2, err := signing.GenerateKey()
+	require.NoError(err)
+	untrusted, err := signing.GenerateKey()
+	require.NoError(err)
+	viper.Set(constants.ConfigTrustedSigningKeysKey, []string{sk1.Public().String(), sk2.Public().String()})
+
+	operation := "avalanche network clean (on 2024-05-02 UTC)"
+	dir := t.TempDir()
+	writeApproval := func(name string, sk *signing.SecretKey, operation string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(os.WriteFile(path, Approve(sk, operation), constants.WriteReadReadPerms))
+		return path
+	}
+	approval1 := writeApproval("1.minisig", sk1, operation)
+	approval2 := writeApproval("2.minisig", sk2, operation)
+
+	require.NoError(CheckApprovals(app, operation, []string{approval1, approval2}, 2))
+	require.ErrorIs(CheckApprovals(app, operation, []string{approval1, approval1}, 2), ErrNotEnoughApprovals)
+	require.Error(CheckApprovals(app, operation, []string{approval1, writeApproval("u.minisig", untrusted, operation)}, 2))
+	require.Error(CheckApprovals(app, operation, []string{approval1, writeApproval("o.minisig", sk2, "avalanche network stop (on 2024-05-02 UTC)")}, 2))
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package precompiles
+
+import (
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/subnet-evm/commontype"
+)
+
+func SetFeeConfig(
+	rpcURL string,
+	privateKey string,
+	feeConfig commontype.FeeConfig,
+) error {
+	_, _, err := contract.TxToMethod(
+		rpcURL,
+		privateKey,
+		FeeManagerPrecompile,
+		nil,
+		"set fee config",
+		nil,
+		"setFeeConfig(uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)",
+		feeConfig.GasLimit,
+		new(big.Int).SetUint64(feeConfig.TargetBlockRate),
+		feeConfig.MinBaseFee,
+		feeConfig.TargetGas,
+		feeConfig.BaseFeeChangeDenominator,
+		feeConfig.MinBlockGasCost,
+		feeConfig.MaxBlockGasCost,
+		feeConfig.BlockGasCostStep,
+	)
+	return err
+}
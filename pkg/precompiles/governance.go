@@ -0,0 +1,261 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package precompiles
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ActionKind is a precompile admin operation
+type ActionKind string
+
+const (
+	SetAdminAction     ActionKind = "setAdmin"
+	SetManagerAction   ActionKind = "setManager"
+	SetEnabledAction   ActionKind = "setEnabled"
+	SetNoneAction      ActionKind = "setNone"
+	MintAction         ActionKind = "mintNativeCoin"
+	SetFeeConfigAction ActionKind = "setFeeConfig"
+)
+
+// names of the allow list precompiles, as used on the command line
+var allowListPrecompiles = map[string]common.Address{
+	"native-minter":      NativeMinterPrecompile,
+	"fee-manager":        FeeManagerPrecompile,
+	"tx-allowlist":       TxAllowListPrecompile,
+	"deployer-allowlist": ContractDeployerAllowListPrecompile,
+	"reward-manager":     RewardManagerPrecompile,
+}
+
+// AllowListPrecompileNames returns the names of the precompiles whose allow list can be governed
+func AllowListPrecompileNames() []string {
+	names := make([]string, 0, len(allowListPrecompiles))
+	for name := range allowListPrecompiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetAllowListPrecompile returns the address of the allow list precompile [name]
+func GetAllowListPrecompile(name string) (common.Address, error) {
+	address, ok := allowListPrecompiles[name]
+	if !ok {
+		return common.Address{}, fmt.Errorf("unknown precompile %q. Expected one of %s", name, strings.Join(AllowListPrecompileNames(), ", "))
+	}
+	return address, nil
+}
+
+// GetPrecompileName returns the command line name of the precompile at [address]
+func GetPrecompileName(address common.Address) string {
+	for name, precompileAddress := range allowListPrecompiles {
+		if precompileAddress == address {
+			return name
+		}
+	}
+	return address.Hex()
+}
+
+// Action is a precompile admin transaction to be executed as part of a governance batch
+type Action struct {
+	Kind       ActionKind
+	Precompile common.Address
+	// target of role changes and mints
+	Address common.Address
+	// amount to mint, in wei
+	Amount *big.Int
+	// fee config to set
+	FeeConfig *commontype.FeeConfig
+}
+
+// NewRoleAction returns the action giving [role] (admin, manager, enabled, none)
+// on the allow list of [precompile] to [address]
+func NewRoleAction(precompile common.Address, role string, address common.Address) (Action, error) {
+	kinds := map[string]ActionKind{
+		"admin":   SetAdminAction,
+		"manager": SetManagerAction,
+		"enabled": SetEnabledAction,
+		"none":    SetNoneAction,
+	}
+	kind, ok := kinds[role]
+	if !ok {
+		return Action{}, fmt.Errorf("unknown allow list role %q. Expected one of admin, manager, enabled, none", role)
+	}
+	return Action{Kind: kind, Precompile: precompile, Address: address}, nil
+}
+
+// NewMintAction returns the action minting [amount] wei of native tokens to [address]
+func NewMintAction(address common.Address, amount *big.Int) Action {
+	return Action{Kind: MintAction, Precompile: NativeMinterPrecompile, Address: address, Amount: amount}
+}
+
+// NewFeeConfigAction returns the action setting [feeConfig] as the chain fee config
+func NewFeeConfigAction(feeConfig commontype.FeeConfig) Action {
+	return Action{Kind: SetFeeConfigAction, Precompile: FeeManagerPrecompile, FeeConfig: &feeConfig}
+}
+
+// MethodSpec returns the precompile method called by the action, with its params
+func (a Action) MethodSpec() (string, []interface{}) {
+	switch a.Kind {
+	case MintAction:
+		return "mintNativeCoin(address,uint256)", []interface{}{a.Address, a.Amount}
+	case SetFeeConfigAction:
+		return "setFeeConfig(uint256,uint256,uint256,uint256,uint256,uint256,uint256,uint256)", []interface{}{
+			a.FeeConfig.GasLimit,
+			new(big.Int).SetUint64(a.FeeConfig.TargetBlockRate),
+			a.FeeConfig.MinBaseFee,
+			a.FeeConfig.TargetGas,
+			a.FeeConfig.BaseFeeChangeDenominator,
+			a.FeeConfig.MinBlockGasCost,
+			a.FeeConfig.MaxBlockGasCost,
+			a.FeeConfig.BlockGasCostStep,
+		}
+	default:
+		return string(a.Kind) + "(address)", []interface{}{a.Address}
+	}
+}
+
+// Calldata returns the data of the action transaction
+func (a Action) Calldata() ([]byte, error) {
+	methodSpec, params := a.MethodSpec()
+	signature, err := contract.ParseSignature(methodSpec)
+	if err != nil {
+		return nil, err
+	}
+	packed, err := signature.Inputs.Pack(params...)
+	if err != nil {
+		return nil, err
+	}
+	return append(signature.Selector(), packed...), nil
+}
+
+// Description returns a human readable description of the action
+func (a Action) Description() string {
+	switch a.Kind {
+	case MintAction:
+		return fmt.Sprintf("mint %s wei to %s", a.Amount, a.Address.Hex())
+	case SetFeeConfigAction:
+		return "set fee config"
+	default:
+		return fmt.Sprintf("%s %s on %s", a.Kind, a.Address.Hex(), GetPrecompileName(a.Precompile))
+	}
+}
+
+// NewRole returns the allow list role the action target ends up with, for role changes
+func (a Action) NewRole() (allowlist.Role, bool) {
+	switch a.Kind {
+	case SetAdminAction:
+		return allowlist.AdminRole, true
+	case SetManagerAction:
+		return allowlist.ManagerRole, true
+	case SetEnabledAction:
+		return allowlist.EnabledRole, true
+	case SetNoneAction:
+		return allowlist.NoRole, true
+	}
+	return allowlist.NoRole, false
+}
+
+// CheckSignerRole checks that a signer with [signerRole] on the action precompile allow
+// list can execute the action, given the [targetRole] the action target currently has.
+// Admins can change any role, managers can only change enabled and no role addresses,
+// and any allowed address can use the precompile
+func (a Action) CheckSignerRole(signerRole allowlist.Role, targetRole allowlist.Role) error {
+	newRole, isRoleChange := a.NewRole()
+	if !isRoleChange {
+		if !signerRole.IsEnabled() {
+			return fmt.Errorf("signer is not allowed to use %s", GetPrecompileName(a.Precompile))
+		}
+		return nil
+	}
+	if !signerRole.CanModify(targetRole, newRole) {
+		return fmt.Errorf(
+			"signer with %s on %s can't change %s from %s to %s",
+			signerRole,
+			GetPrecompileName(a.Precompile),
+			a.Address.Hex(),
+			targetRole,
+			newRole,
+		)
+	}
+	return nil
+}
+
+// ReadRole returns the role of [address] on the allow list of [precompile]
+func ReadRole(rpcURL string, precompile common.Address, address common.Address) (allowlist.Role, error) {
+	role, err := ReadAllowList(rpcURL, precompile, address)
+	if err != nil {
+		return allowlist.NoRole, err
+	}
+	return allowlist.FromBig(role)
+}
+
+// SafeTxBundle is a Safe Transaction Builder batch file, to be imported on a
+// Safe{Wallet} to propose the batch to the Safe owners
+type SafeTxBundle struct {
+	Version      string          `json:"version"`
+	ChainID      string          `json:"chainId"`
+	CreatedAt    int64           `json:"createdAt"`
+	Meta         SafeTxMeta      `json:"meta"`
+	Transactions []SafeTxElement `json:"transactions"`
+}
+
+type SafeTxMeta struct {
+	Name                   string `json:"name"`
+	Description            string `json:"description"`
+	TxBuilderVersion       string `json:"txBuilderVersion"`
+	CreatedFromSafeAddress string `json:"createdFromSafeAddress"`
+}
+
+type SafeTxElement struct {
+	To    string `json:"to"`
+	Value string `json:"value"`
+	Data  string `json:"data"`
+}
+
+// NewSafeTxBundle returns the Safe Transaction Builder batch executing [actions] on chain
+// [chainID] from [safeAddress]
+func NewSafeTxBundle(
+	chainID *big.Int,
+	safeAddress common.Address,
+	name string,
+	actions []Action,
+	createdAt time.Time,
+) (SafeTxBundle, error) {
+	descriptions := make([]string, 0, len(actions))
+	transactions := make([]SafeTxElement, 0, len(actions))
+	for _, action := range actions {
+		calldata, err := action.Calldata()
+		if err != nil {
+			return SafeTxBundle{}, err
+		}
+		descriptions = append(descriptions, action.Description())
+		transactions = append(transactions, SafeTxElement{
+			To:    action.Precompile.Hex(),
+			Value: "0",
+			Data:  hexutil.Encode(calldata),
+		})
+	}
+	return SafeTxBundle{
+		Version:   "1.0",
+		ChainID:   chainID.String(),
+		CreatedAt: createdAt.UnixMilli(),
+		Meta: SafeTxMeta{
+			Name:                   name,
+			Description:            strings.Join(descriptions, "; "),
+			TxBuilderVersion:       "1.16.5",
+			CreatedFromSafeAddress: safeAddress.Hex(),
+		},
+		Transactions: transactions,
+	}, nil
+}
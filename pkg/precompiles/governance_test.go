@@ -0,0 +1,71 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package precompiles
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionCalldata(t *testing.T) {
+	require := require.New(t)
+	address := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+
+	action, err := NewRoleAction(NativeMinterPrecompile, "enabled", address)
+	require.NoError(err)
+	calldata, err := action.Calldata()
+	require.NoError(err)
+	// setEnabled(address)
+	require.Equal("0x0aaf7043"+"0000000000000000000000008db97c7cece249c2b98bdc0226cc4c2a57bf52fc", hexutil.Encode(calldata))
+
+	calldata, err = NewMintAction(address, big.NewInt(1)).Calldata()
+	require.NoError(err)
+	// mintNativeCoin(address,uint256)
+	require.Equal("0x4f5aaaba", hexutil.Encode(calldata[:4]))
+	require.Len(calldata, 4+2*32)
+
+	_, err = NewRoleAction(NativeMinterPrecompile, "owner", address)
+	require.Error(err)
+}
+
+func TestActionCheckSignerRole(t *testing.T) {
+	require := require.New(t)
+	address := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	enable, err := NewRoleAction(TxAllowListPrecompile, "enabled", address)
+	require.NoError(err)
+	setAdmin, err := NewRoleAction(TxAllowListPrecompile, "admin", address)
+	require.NoError(err)
+	mint := NewMintAction(address, big.NewInt(1))
+
+	require.NoError(enable.CheckSignerRole(allowlist.AdminRole, allowlist.ManagerRole))
+	require.NoError(enable.CheckSignerRole(allowlist.ManagerRole, allowlist.NoRole))
+	require.Error(enable.CheckSignerRole(allowlist.ManagerRole, allowlist.AdminRole))
+	require.Error(enable.CheckSignerRole(allowlist.EnabledRole, allowlist.NoRole))
+	require.Error(setAdmin.CheckSignerRole(allowlist.ManagerRole, allowlist.NoRole))
+	require.NoError(mint.CheckSignerRole(allowlist.EnabledRole, allowlist.NoRole))
+	require.Error(mint.CheckSignerRole(allowlist.NoRole, allowlist.NoRole))
+}
+
+func TestNewSafeTxBundle(t *testing.T) {
+	require := require.New(t)
+	safe := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	action, err := NewRoleAction(FeeManagerPrecompile, "manager", common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	require.NoError(err)
+	bundle, err := NewSafeTxBundle(big.NewInt(8888), safe, "test", []Action{action}, time.UnixMilli(1700000000000))
+	require.NoError(err)
+	require.Equal("8888", bundle.ChainID)
+	require.Equal(int64(1700000000000), bundle.CreatedAt)
+	require.Equal(safe.Hex(), bundle.Meta.CreatedFromSafeAddress)
+	require.Len(bundle.Transactions, 1)
+	require.Equal(FeeManagerPrecompile.Hex(), bundle.Transactions[0].To)
+	require.Equal("0", bundle.Transactions[0].Value)
+	calldata, err := action.Calldata()
+	require.NoError(err)
+	require.Equal(hexutil.Encode(calldata), bundle.Transactions[0].Data)
+}
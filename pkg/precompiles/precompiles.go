@@ -8,4 +8,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-var NativeMinterPrecompile = common.HexToAddress("0x0200000000000000000000000000000000000001")
+var (
+	ContractDeployerAllowListPrecompile = common.HexToAddress("0x0200000000000000000000000000000000000000")
+	NativeMinterPrecompile              = common.HexToAddress("0x0200000000000000000000000000000000000001")
+	TxAllowListPrecompile               = common.HexToAddress("0x0200000000000000000000000000000000000002")
+	FeeManagerPrecompile                = common.HexToAddress("0x0200000000000000000000000000000000000003")
+	RewardManagerPrecompile             = common.HexToAddress("0x0200000000000000000000000000000000000004")
+)
@@ -0,0 +1,171 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package preflight aggregates the costs of a flow made of several transactions, per
+// paying account, so balances can be checked and the costs confirmed by the user before
+// issuing any of them
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// Account is an address paying costs on a given chain
+type Account struct {
+	Chain    string
+	Address  string
+	Symbol   string
+	Decimals uint8
+	Balance  *big.Int
+}
+
+// Cost is an amount to be paid by an account
+type Cost struct {
+	AccountID   string
+	Description string
+	Amount      *big.Int
+	// Estimated is set for fees that are only known at issuance time
+	Estimated bool
+}
+
+// Report holds the accounts and costs of a flow
+type Report struct {
+	title      string
+	accountIDs []string
+	accounts   map[string]Account
+	costs      []Cost
+	problems   []string
+}
+
+func NewReport(title string) *Report {
+	return &Report{
+		title:    title,
+		accounts: map[string]Account{},
+	}
+}
+
+// AddAccount registers [account] under [id]. Registering again an id updates it
+func (r *Report) AddAccount(id string, account Account) {
+	if _, ok := r.accounts[id]; !ok {
+		r.accountIDs = append(r.accountIDs, id)
+	}
+	r.accounts[id] = account
+}
+
+// AddCost adds [amount] to be paid by the account registered as [accountID]
+func (r *Report) AddCost(accountID string, description string, amount *big.Int, estimated bool) {
+	r.costs = append(r.costs, Cost{
+		AccountID:   accountID,
+		Description: description,
+		Amount:      amount,
+		Estimated:   estimated,
+	})
+}
+
+// AddProblem records an issue that prevents the flow to succeed, other than a balance shortfall
+func (r *Report) AddProblem(format string, args ...interface{}) {
+	r.problems = append(r.problems, fmt.Sprintf(format, args...))
+}
+
+// Required returns the total amount to be paid by [accountID]
+func (r *Report) Required(accountID string) *big.Int {
+	required := big.NewInt(0)
+	for _, cost := range r.costs {
+		if cost.AccountID == accountID && cost.Amount != nil {
+			required.Add(required, cost.Amount)
+		}
+	}
+	return required
+}
+
+// Err returns the balance shortfalls and problems of the report, or nil if the flow
+// can be paid for
+func (r *Report) Err() error {
+	errs := []error{}
+	for _, id := range r.accountIDs {
+		account := r.accounts[id]
+		required := r.Required(id)
+		if account.Balance == nil || account.Balance.Cmp(required) >= 0 {
+			continue
+		}
+		errs = append(errs, fmt.Errorf(
+			"%w on %s for %s: %s %s required, %s %s available",
+			ErrInsufficientBalance,
+			account.Chain,
+			account.Address,
+			utils.FormatAmount(required, account.Decimals),
+			account.Symbol,
+			utils.FormatAmount(account.Balance, account.Decimals),
+			account.Symbol,
+		))
+	}
+	for _, problem := range r.problems {
+		errs = append(errs, errors.New(problem))
+	}
+	return errors.Join(errs...)
+}
+
+// Render returns the costs table, followed by the balances table, and the problems found
+func (r *Report) Render() string {
+	costs := ux.DefaultTable(r.title, table.Row{"Chain", "Paid By", "Item", "Amount"})
+	costs.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 1, AutoMerge: true},
+		{Number: 2, AutoMerge: true},
+	})
+	hasEstimates := false
+	for _, id := range r.accountIDs {
+		account := r.accounts[id]
+		for _, cost := range r.costs {
+			if cost.AccountID != id {
+				continue
+			}
+			amount := "-"
+			if cost.Amount != nil {
+				amount = fmt.Sprintf("%s %s", utils.FormatAmount(cost.Amount, account.Decimals), account.Symbol)
+				if cost.Estimated {
+					amount = "~" + amount
+					hasEstimates = true
+				}
+			}
+			costs.AppendRow(table.Row{account.Chain, account.Address, cost.Description, amount})
+		}
+	}
+	balances := ux.DefaultTable("Balances", table.Row{"Chain", "Address", "Required", "Available", "Status"})
+	for _, id := range r.accountIDs {
+		account := r.accounts[id]
+		required := r.Required(id)
+		available := "unknown"
+		status := "-"
+		if account.Balance != nil {
+			available = fmt.Sprintf("%s %s", utils.FormatAmount(account.Balance, account.Decimals), account.Symbol)
+			status = "OK"
+			if account.Balance.Cmp(required) < 0 {
+				status = "INSUFFICIENT"
+			}
+		}
+		balances.AppendRow(table.Row{
+			account.Chain,
+			account.Address,
+			fmt.Sprintf("%s %s", utils.FormatAmount(required, account.Decimals), account.Symbol),
+			available,
+			status,
+		})
+	}
+	out := costs.Render() + "\n"
+	if hasEstimates {
+		out += "~ estimated fee, the final one is computed when the transaction is issued\n"
+	}
+	out += balances.Render() + "\n"
+	for _, problem := range r.problems {
+		out += "Problem: " + problem + "\n"
+	}
+	return out
+}
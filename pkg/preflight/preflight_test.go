@@ -0,0 +1,50 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package preflight
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	require := require.New(t)
+	r := NewReport("Deploy Costs")
+	r.AddAccount("p", Account{
+		Chain:    "P-Chain",
+		Address:  "P-fuji1abc",
+		Symbol:   "AVAX",
+		Decimals: 9,
+		Balance:  big.NewInt(3_000_000_000),
+	})
+	r.AddAccount("l1", Account{
+		Chain:    "L1 chain",
+		Address:  "0xabc",
+		Symbol:   "TEST",
+		Decimals: 18,
+	})
+	r.AddCost("p", "CreateSubnetTx fee", big.NewInt(1_000_000_000), true)
+	r.AddCost("p", "bootstrap validator balance", big.NewInt(1_000_000_000), false)
+	r.AddCost("l1", "validator manager initialization", nil, false)
+	require.Equal(big.NewInt(2_000_000_000), r.Required("p"))
+	require.Equal(big.NewInt(0), r.Required("l1"))
+	require.NoError(r.Err())
+
+	out := r.Render()
+	require.Contains(out, "~1.000000000 AVAX")
+	require.Contains(out, "estimated fee")
+	require.Contains(out, "OK")
+	require.Contains(out, "unknown")
+
+	r.AddCost("p", "ConvertSubnetToL1Tx fee", big.NewInt(1_000_000_001), true)
+	err := r.Err()
+	require.True(errors.Is(err, ErrInsufficientBalance))
+	require.ErrorContains(err, "3.000000001 AVAX required, 3.000000000 AVAX available")
+	require.Contains(r.Render(), "INSUFFICIENT")
+
+	r.AddProblem("no key found for %s", "0xabc")
+	require.ErrorContains(r.Err(), "no key found for 0xabc")
+}
@@ -0,0 +1,526 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package preset fetches blockchain presets (recommended genesis, fee config, precompile
+// settings and ICM defaults) published by ecosystems on a preset registry.
+//
+// A registry is an index file, served over HTTPS, stored on the root of a git repository,
+// or on a local directory:
+//
+//	{
+//	  "presets": {
+//	    "gaming-l1": {
+//	      "description": "low fee, high throughput L1 for games",
+//	      "latest": "1.1.0",
+//	      "versions": {
+//	        "1.0.0": {"url": "gaming-l1/1.0.0.json", "sha256": "..."},
+//	        "1.1.0": {"url": "gaming-l1/1.1.0.json", "sha256": "..."}
+//	      }
+//	    }
+//	  }
+//	}
+//
+// Preset urls can be absolute, or relative to the index location. The sha256 of each preset
+// file is mandatory and is verified before the preset is used. Registries and preset urls
+// must not use plain http.
+package preset
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	sdkutils "github.com/ava-labs/avalanche-cli/sdk/utils"
+	"github.com/ethereum/go-ethereum/common/math"
+	"golang.org/x/mod/semver"
+)
+
+var (
+	ErrNoRegistry = errors.New(
+		"no preset registry configured. Use --preset-registry, or set one with avalanche config preset-registry",
+	)
+	// preset names and versions are used as dir names on the presets cache
+	pathElementRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+	// precompile config fields that grant roles, by role name
+	precompileRoleFields = map[string]string{
+		"adminAddresses":   "admin",
+		"managerAddresses": "manager",
+		"enabledAddresses": "enabled",
+	}
+)
+
+// Index lists the presets available on a registry
+type Index struct {
+	Presets map[string]IndexEntry `json:"presets"`
+}
+
+type IndexEntry struct {
+	Description string                  `json:"description,omitempty"`
+	Latest      string                  `json:"latest,omitempty"`
+	Versions    map[string]VersionEntry `json:"versions"`
+}
+
+// Allocation is an account funded, or a contract predeployed, by a preset genesis
+type Allocation struct {
+	Address string
+	Balance *big.Int
+	HasCode bool
+}
+
+// PrecompileRole lists the addresses a preset genesis grants [Role] on [Precompile]
+type PrecompileRole struct {
+	Precompile string
+	Role       string
+	Addresses  []string
+}
+
+type VersionEntry struct {
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// Preset is a blockchain configuration recommended by a registry. Fee config and precompile
+// settings are part of the Subnet-EVM [Genesis] config
+type Preset struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+	// Subnet-EVM version the preset was tested with
+	VMVersion   string `json:"vmVersion,omitempty"`
+	TokenSymbol string `json:"tokenSymbol,omitempty"`
+	// ICM default, asked to the user if not set
+	ICM         *bool           `json:"icm,omitempty"`
+	Genesis     json.RawMessage `json:"genesis"`
+	ChainConfig json.RawMessage `json:"chainConfig,omitempty"`
+	// sha256 of the preset file, as verified against the registry index
+	SHA256 string `json:"-"`
+}
+
+// ParseRef splits a preset reference name[@version]
+func ParseRef(ref string) (string, string, error) {
+	name, version, _ := strings.Cut(ref, "@")
+	if name == "" {
+		return "", "", fmt.Errorf("invalid preset %q, expected name[@version]", ref)
+	}
+	return name, version, nil
+}
+
+// GetRegistry returns [registry] if given, or the preset registry set on the CLI config
+func GetRegistry(app *application.Avalanche, registry string) (string, error) {
+	if registry == "" {
+		registry = app.Conf.GetConfigStringValue(constants.ConfigPresetRegistryKey)
+	}
+	if registry == "" {
+		return "", ErrNoRegistry
+	}
+	return registry, nil
+}
+
+// checkNotPlainHTTP fails if [location] is to be fetched over plain http
+func checkNotPlainHTTP(location string) error {
+	if strings.HasPrefix(strings.TrimPrefix(location, "git+"), "http://") {
+		return fmt.Errorf("%s uses plain http, which can be tampered with. use https instead", location)
+	}
+	return nil
+}
+
+// IsGitRegistry returns true if [registry] is a git repository, optionally followed by
+// #ref to pin a branch, tag or commit
+func IsGitRegistry(registry string) bool {
+	repo, _, _ := strings.Cut(registry, "#")
+	return strings.HasPrefix(repo, "git@") || strings.HasPrefix(repo, "git+") || strings.HasSuffix(repo, ".git")
+}
+
+// registry reads files relative to the index of a preset registry
+type registry struct {
+	app       *application.Avalanche
+	indexURL  *url.URL
+	indexPath string
+}
+
+func openRegistry(app *application.Avalanche, location string) (*registry, error) {
+	if err := checkNotPlainHTTP(location); err != nil {
+		return nil, fmt.Errorf("invalid preset registry: %w", err)
+	}
+	switch {
+	case IsGitRegistry(location):
+		repoDir, err := syncGitRegistry(app, location)
+		if err != nil {
+			return nil, err
+		}
+		return &registry{app: app, indexPath: filepath.Join(repoDir, constants.PresetIndexFileName)}, nil
+	case strings.HasPrefix(location, "https://"):
+		indexURL, err := url.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid preset registry %q: %w", location, err)
+		}
+		if !strings.HasSuffix(indexURL.Path, ".json") {
+			indexURL = indexURL.JoinPath(constants.PresetIndexFileName)
+		}
+		return &registry{app: app, indexURL: indexURL}, nil
+	default:
+		indexPath := utils.ExpandHome(location)
+		if !strings.HasSuffix(indexPath, ".json") {
+			indexPath = filepath.Join(indexPath, constants.PresetIndexFileName)
+		}
+		return &registry{app: app, indexPath: indexPath}, nil
+	}
+}
+
+// read returns the contents of [location], absolute or relative to the registry index
+func (r *registry) read(location string) ([]byte, error) {
+	if err := checkNotPlainHTTP(location); err != nil {
+		return nil, err
+	}
+	if r.indexURL != nil {
+		ref, err := url.Parse(location)
+		if err != nil {
+			return nil, err
+		}
+		fileURL := r.indexURL.ResolveReference(ref)
+		if fileURL.Scheme != "https" {
+			return nil, fmt.Errorf("%s is not served over https", fileURL)
+		}
+		bs, err := r.app.Downloader.Download(fileURL.String())
+		if err != nil {
+			return nil, fmt.Errorf("failure downloading %s: %w", fileURL, err)
+		}
+		return bs, nil
+	}
+	if strings.HasPrefix(location, "https://") {
+		return r.app.Downloader.Download(location)
+	}
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(filepath.Dir(r.indexPath), location)
+	}
+	return os.ReadFile(location)
+}
+
+func (r *registry) index() (Index, error) {
+	location := r.indexPath
+	if r.indexURL != nil {
+		location = r.indexURL.String()
+	}
+	bs, err := r.read(location)
+	if err != nil {
+		return Index{}, fmt.Errorf("failure reading preset registry index: %w", err)
+	}
+	index := Index{}
+	if err := json.Unmarshal(bs, &index); err != nil {
+		return Index{}, fmt.Errorf("invalid preset registry index %s: %w", location, err)
+	}
+	return index, nil
+}
+
+// syncGitRegistry clones or updates the git registry [location] into the presets cache,
+// checking out the ref pinned on it if any. Returns the repository dir
+func syncGitRegistry(app *application.Avalanche, location string) (string, error) {
+	if err := utils.CheckGitIsInstalled(); err != nil {
+		return "", err
+	}
+	repo, ref, _ := strings.Cut(location, "#")
+	repo = strings.TrimPrefix(repo, "git+")
+	hash := sha256.Sum256([]byte(repo))
+	repoDir := filepath.Join(app.GetPresetsDir(), "registries", hex.EncodeToString(hash[:8]))
+	run := func(dir string, args ...string) error {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s on preset registry %s failed: %w: %s", args[0], repo, err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+	if !sdkutils.DirExists(filepath.Join(repoDir, ".git")) {
+		if err := os.MkdirAll(filepath.Dir(repoDir), constants.DefaultPerms755); err != nil {
+			return "", err
+		}
+		if err := run("", "clone", "--quiet", repo, repoDir); err != nil {
+			return "", err
+		}
+	} else if err := run(repoDir, "fetch", "--quiet", "--tags", "origin"); err != nil {
+		return "", err
+	}
+	if ref == "" {
+		// follow the remote default branch
+		if err := run(repoDir, "reset", "--quiet", "--hard", "origin/HEAD"); err != nil {
+			return "", err
+		}
+		return repoDir, nil
+	}
+	// branches are taken from the fetched remote state, tags and commits as they are
+	if err := run(repoDir, "checkout", "--quiet", "--detach", "origin/"+ref); err == nil {
+		return repoDir, nil
+	}
+	if err := run(repoDir, "checkout", "--quiet", "--detach", ref); err != nil {
+		return "", err
+	}
+	return repoDir, nil
+}
+
+// List returns the index of the preset registry at [location]
+func List(app *application.Avalanche, location string) (Index, error) {
+	r, err := openRegistry(app, location)
+	if err != nil {
+		return Index{}, err
+	}
+	return r.index()
+}
+
+// Fetch gets the preset [ref] (name[@version]) from the registry at [location], verifying
+// its checksum against the registry index, and against [pinnedSHA256] if given, so that
+// a compromised registry can't change a reviewed preset. Without version, the latest one
+// is used
+func Fetch(app *application.Avalanche, location string, ref string, pinnedSHA256 string) (*Preset, error) {
+	name, version, err := ParseRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	r, err := openRegistry(app, location)
+	if err != nil {
+		return nil, err
+	}
+	index, err := r.index()
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := index.Presets[name]
+	if !ok {
+		return nil, fmt.Errorf("preset %s not found on registry %s", name, location)
+	}
+	if version == "" {
+		version, err = entry.latestVersion()
+		if err != nil {
+			return nil, fmt.Errorf("preset %s: %w", name, err)
+		}
+	}
+	versionEntry, ok := entry.Versions[version]
+	if !ok {
+		return nil, fmt.Errorf("version %s of preset %s not found on registry %s. Available versions: %s",
+			version, name, location, strings.Join(entry.SortedVersions(), ", "))
+	}
+	if versionEntry.SHA256 == "" {
+		return nil, fmt.Errorf("registry %s does not publish a checksum for preset %s@%s", location, name, version)
+	}
+	if pinnedSHA256 != "" && !strings.EqualFold(strings.TrimSpace(pinnedSHA256), strings.TrimSpace(versionEntry.SHA256)) {
+		return nil, fmt.Errorf("preset %s@%s: registry checksum %s does not match the pinned sha256 %s", name, version, versionEntry.SHA256, pinnedSHA256)
+	}
+	presetBytes, err := r.read(versionEntry.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading preset %s@%s: %w", name, version, err)
+	}
+	if err := VerifyChecksum(presetBytes, versionEntry.SHA256); err != nil {
+		return nil, fmt.Errorf("preset %s@%s: %w", name, version, err)
+	}
+	if pinnedSHA256 != "" {
+		if err := VerifyChecksum(presetBytes, pinnedSHA256); err != nil {
+			return nil, fmt.Errorf("preset %s@%s: %w", name, version, err)
+		}
+	}
+	preset := &Preset{}
+	if err := json.Unmarshal(presetBytes, preset); err != nil {
+		return nil, fmt.Errorf("invalid preset %s@%s: %w", name, version, err)
+	}
+	if preset.Name != name || preset.Version != version {
+		return nil, fmt.Errorf("registry entry %s@%s points to preset %s@%s", name, version, preset.Name, preset.Version)
+	}
+	if err := preset.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid preset %s@%s: %w", name, version, err)
+	}
+	preset.SHA256 = versionEntry.SHA256
+	return preset, nil
+}
+
+// VerifyChecksum fails if the sha256 of [bs] is not [expected] (hex encoded)
+func VerifyChecksum(bs []byte, expected string) error {
+	sum := sha256.Sum256(bs)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return fmt.Errorf("checksum mismatch: expected sha256 %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// SortedVersions returns the versions of the preset, oldest first
+func (e IndexEntry) SortedVersions() []string {
+	versions := make([]string, 0, len(e.Versions))
+	for version := range e.Versions {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		vi, vj := "v"+strings.TrimPrefix(versions[i], "v"), "v"+strings.TrimPrefix(versions[j], "v")
+		if semver.IsValid(vi) && semver.IsValid(vj) {
+			return semver.Compare(vi, vj) < 0
+		}
+		return versions[i] < versions[j]
+	})
+	return versions
+}
+
+func (e IndexEntry) latestVersion() (string, error) {
+	if e.Latest != "" {
+		return e.Latest, nil
+	}
+	versions := e.SortedVersions()
+	if len(versions) == 0 {
+		return "", errors.New("no versions published")
+	}
+	return versions[len(versions)-1], nil
+}
+
+// validatePathElement fails if [value] can't be safely used as a dir name
+func validatePathElement(field string, value string) error {
+	if !pathElementRegex.MatchString(value) || strings.Contains(value, "..") {
+		return fmt.Errorf("invalid preset %s %q: only letters, digits, '.', '_', '+' and '-' are allowed", field, value)
+	}
+	return nil
+}
+
+// Validate checks that the preset has a safe name and version, and holds a Subnet-EVM
+// genesis and a valid chain config
+func (p *Preset) Validate() error {
+	if err := validatePathElement("name", p.Name); err != nil {
+		return err
+	}
+	if err := validatePathElement("version", p.Version); err != nil {
+		return err
+	}
+	if len(p.Genesis) == 0 {
+		return errors.New("missing genesis")
+	}
+	if !utils.ByteSliceIsSubnetEvmGenesis(p.Genesis) {
+		return errors.New("genesis is not a Subnet-EVM genesis")
+	}
+	if p.VMVersion != "" && !semver.IsValid(p.VMVersion) {
+		return fmt.Errorf("invalid vmVersion %q, should be semantic version (ex: v1.1.1)", p.VMVersion)
+	}
+	if len(p.ChainConfig) > 0 && !json.Valid(p.ChainConfig) {
+		return errors.New("invalid chainConfig")
+	}
+	return nil
+}
+
+// Allocations returns the accounts funded and the contracts predeployed by the preset
+// genesis, sorted by address
+func (p *Preset) Allocations() ([]Allocation, error) {
+	genesis := struct {
+		Alloc map[string]struct {
+			Balance *math.HexOrDecimal256 `json:"balance"`
+			Code    string                `json:"code"`
+		} `json:"alloc"`
+	}{}
+	if err := json.Unmarshal(p.Genesis, &genesis); err != nil {
+		return nil, fmt.Errorf("invalid preset genesis alloc: %w", err)
+	}
+	allocations := []Allocation{}
+	for address, account := range genesis.Alloc {
+		balance := big.NewInt(0)
+		if account.Balance != nil {
+			balance = (*big.Int)(account.Balance)
+		}
+		allocations = append(allocations, Allocation{
+			Address: "0x" + strings.ToLower(utils.TrimHexa(address)),
+			Balance: balance,
+			HasCode: utils.TrimHexa(account.Code) != "",
+		})
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].Address < allocations[j].Address
+	})
+	return allocations, nil
+}
+
+// PrecompileRoles returns the roles the preset genesis grants on its precompiles (admin,
+// manager and enabled addresses), sorted by precompile and role
+func (p *Preset) PrecompileRoles() ([]PrecompileRole, error) {
+	genesis := struct {
+		Config map[string]json.RawMessage `json:"config"`
+	}{}
+	if err := json.Unmarshal(p.Genesis, &genesis); err != nil {
+		return nil, fmt.Errorf("invalid preset genesis config: %w", err)
+	}
+	roles := []PrecompileRole{}
+	for key, value := range genesis.Config {
+		precompileConfig := map[string]json.RawMessage{}
+		// fields that are not objects are not precompile configs
+		if err := json.Unmarshal(value, &precompileConfig); err != nil {
+			continue
+		}
+		for field, role := range precompileRoleFields {
+			addresses := []string{}
+			if roleAddresses, ok := precompileConfig[field]; !ok || json.Unmarshal(roleAddresses, &addresses) != nil || len(addresses) == 0 {
+				continue
+			}
+			roles = append(roles, PrecompileRole{
+				Precompile: strings.TrimSuffix(key, "Config"),
+				Role:       role,
+				Addresses:  addresses,
+			})
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].Precompile != roles[j].Precompile {
+			return roles[i].Precompile < roles[j].Precompile
+		}
+		return roles[i].Role < roles[j].Role
+	})
+	return roles, nil
+}
+
+// GenesisWithChainID returns the preset genesis, with its EVM chain ID replaced by [chainID]
+// if not zero
+func (p *Preset) GenesisWithChainID(chainID uint64) ([]byte, error) {
+	if chainID == 0 {
+		return p.Genesis, nil
+	}
+	// keep big numbers as they are
+	decoder := json.NewDecoder(bytes.NewReader(p.Genesis))
+	decoder.UseNumber()
+	genesisMap := map[string]interface{}{}
+	if err := decoder.Decode(&genesisMap); err != nil {
+		return nil, err
+	}
+	config, ok := genesisMap["config"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("preset genesis has no config")
+	}
+	config["chainId"] = chainID
+	return json.MarshalIndent(genesisMap, "", "  ")
+}
+
+// WriteGenesis saves the genesis of the preset, with EVM chain ID [chainID] if not zero, into
+// the presets cache. Returns its path
+func WriteGenesis(app *application.Avalanche, p *Preset, chainID uint64) (string, error) {
+	// name and version come from the registry, and must not escape the presets cache
+	if err := validatePathElement("name", p.Name); err != nil {
+		return "", err
+	}
+	if err := validatePathElement("version", p.Version); err != nil {
+		return "", err
+	}
+	genesisBytes, err := p.GenesisWithChainID(chainID)
+	if err != nil {
+		return "", err
+	}
+	presetDir := filepath.Join(app.GetPresetsDir(), p.Name, p.Version)
+	if err := os.MkdirAll(presetDir, constants.DefaultPerms755); err != nil {
+		return "", err
+	}
+	genesisPath := filepath.Join(presetDir, constants.GenesisFileName)
+	if err := os.WriteFile(genesisPath, genesisBytes, constants.WriteReadReadPerms); err != nil {
+		return "", err
+	}
+	return genesisPath, nil
+}
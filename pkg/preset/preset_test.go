@@ -0,0 +1,226 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package preset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+const testGenesis = `{"config":{"chainId":8888,"feeConfig":{"gasLimit":20000000,"minBaseFee":1000000000}},"alloc":{},"gasLimit":"0x1312d00","difficulty":"0x0","timestamp":"0x0"}`
+
+func newTestApp(t *testing.T) *application.Avalanche {
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil, application.NewDownloader())
+	return app
+}
+
+// writeRegistry writes a registry with the given preset versions into [dir]
+func writeRegistry(t *testing.T, dir string, latest string, versions ...string) {
+	require := require.New(t)
+	icm := true
+	entry := IndexEntry{Description: "games", Latest: latest, Versions: map[string]VersionEntry{}}
+	for _, version := range versions {
+		presetBytes, err := json.Marshal(Preset{
+			Name:        "gaming-l1",
+			Version:     version,
+			VMVersion:   "v0.7.0",
+			TokenSymbol: "GAME",
+			ICM:         &icm,
+			Genesis:     json.RawMessage(testGenesis),
+		})
+		require.NoError(err)
+		fileName := "gaming-l1-" + version + ".json"
+		require.NoError(os.WriteFile(filepath.Join(dir, fileName), presetBytes, 0o600))
+		sum := sha256.Sum256(presetBytes)
+		entry.Versions[version] = VersionEntry{URL: fileName, SHA256: hex.EncodeToString(sum[:])}
+	}
+	indexBytes, err := json.Marshal(Index{Presets: map[string]IndexEntry{"gaming-l1": entry}})
+	require.NoError(err)
+	require.NoError(os.WriteFile(filepath.Join(dir, "index.json"), indexBytes, 0o600))
+}
+
+func TestParseRef(t *testing.T) {
+	require := require.New(t)
+	name, version, err := ParseRef("gaming-l1@1.0.0")
+	require.NoError(err)
+	require.Equal("gaming-l1", name)
+	require.Equal("1.0.0", version)
+	name, version, err = ParseRef("gaming-l1")
+	require.NoError(err)
+	require.Equal("gaming-l1", name)
+	require.Empty(version)
+	_, _, err = ParseRef("@1.0.0")
+	require.Error(err)
+}
+
+func TestFetchLocalRegistry(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+	registryDir := t.TempDir()
+	writeRegistry(t, registryDir, "", "1.0.0", "1.10.0", "1.9.0")
+
+	preset, err := Fetch(app, registryDir, "gaming-l1", "")
+	require.NoError(err)
+	require.Equal("1.10.0", preset.Version)
+	require.Equal("GAME", preset.TokenSymbol)
+	require.True(*preset.ICM)
+
+	preset, err = Fetch(app, filepath.Join(registryDir, "index.json"), "gaming-l1@1.0.0", "")
+	require.NoError(err)
+	require.Equal("1.0.0", preset.Version)
+
+	_, err = Fetch(app, registryDir, "gaming-l1@2.0.0", "")
+	require.ErrorContains(err, "Available versions: 1.0.0, 1.9.0, 1.10.0")
+	_, err = Fetch(app, registryDir, "defi-l1", "")
+	require.ErrorContains(err, "preset defi-l1 not found")
+
+	// tampered preset file
+	require.NoError(os.WriteFile(filepath.Join(registryDir, "gaming-l1-1.9.0.json"), []byte(`{"name":"gaming-l1"}`), 0o600))
+	_, err = Fetch(app, registryDir, "gaming-l1@1.9.0", "")
+	require.ErrorContains(err, "checksum mismatch")
+}
+
+func TestFetchHTTPSRegistry(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+	registryDir := t.TempDir()
+	writeRegistry(t, registryDir, "1.0.0", "1.0.0", "1.1.0")
+	server := httptest.NewTLSServer(http.StripPrefix("/registry", http.FileServer(http.Dir(registryDir))))
+	defer server.Close()
+	// trust the test server certificate
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = server.Client().Transport
+	defer func() {
+		http.DefaultTransport = defaultTransport
+	}()
+
+	preset, err := Fetch(app, server.URL+"/registry", "gaming-l1", "")
+	require.NoError(err)
+	require.Equal("1.0.0", preset.Version)
+	require.NotEmpty(preset.SHA256)
+
+	index, err := List(app, server.URL+"/registry/index.json")
+	require.NoError(err)
+	require.Equal([]string{"1.0.0", "1.1.0"}, index.Presets["gaming-l1"].SortedVersions())
+
+	// plain http registries are rejected
+	_, err = Fetch(app, strings.Replace(server.URL, "https://", "http://", 1)+"/registry", "gaming-l1", "")
+	require.ErrorContains(err, "plain http")
+	_, err = List(app, "git+http://example.com/presets")
+	require.ErrorContains(err, "plain http")
+}
+
+func TestFetchPinnedSHA256(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+	registryDir := t.TempDir()
+	writeRegistry(t, registryDir, "", "1.0.0")
+
+	preset, err := Fetch(app, registryDir, "gaming-l1@1.0.0", "")
+	require.NoError(err)
+	pinned := preset.SHA256
+	preset, err = Fetch(app, registryDir, "gaming-l1@1.0.0", strings.ToUpper(pinned))
+	require.NoError(err)
+	require.Equal(pinned, preset.SHA256)
+
+	// the registry publishes a new preset file and checksum for the same version
+	writeRegistry(t, registryDir, "", "1.0.0", "1.1.0")
+	index, err := List(app, registryDir)
+	require.NoError(err)
+	entry := index.Presets["gaming-l1"]
+	entry.Versions["1.0.0"] = entry.Versions["1.1.0"]
+	indexBytes, err := json.Marshal(index)
+	require.NoError(err)
+	require.NoError(os.WriteFile(filepath.Join(registryDir, "index.json"), indexBytes, 0o600))
+	_, err = Fetch(app, registryDir, "gaming-l1@1.0.0", pinned)
+	require.ErrorContains(err, "does not match the pinned sha256")
+}
+
+func TestPresetContents(t *testing.T) {
+	require := require.New(t)
+	preset := &Preset{
+		Name:    "gaming-l1",
+		Version: "1.0.0",
+		Genesis: json.RawMessage(`{
+  "config": {
+    "chainId": 8888,
+    "feeConfig": {"gasLimit": 20000000},
+    "contractNativeMinterConfig": {"blockTimestamp": 0, "adminAddresses": ["0x1111111111111111111111111111111111111111"]},
+    "txAllowListConfig": {"blockTimestamp": 0, "adminAddresses": ["0x2222222222222222222222222222222222222222"], "enabledAddresses": ["0x3333333333333333333333333333333333333333", "0x4444444444444444444444444444444444444444"]},
+    "warpConfig": {"blockTimestamp": 0}
+  },
+  "alloc": {
+    "8DB97C7CECE249C2B98BDC0226CC4C2A57BF52FC": {"balance": "0xde0b6b3a7640000"},
+    "0x0c0deba5e0000000000000000000000000000000": {"balance": "0", "code": "0x6001"}
+  }
+}`),
+	}
+	allocations, err := preset.Allocations()
+	require.NoError(err)
+	require.Len(allocations, 2)
+	require.Equal("0x0c0deba5e0000000000000000000000000000000", allocations[0].Address)
+	require.True(allocations[0].HasCode)
+	require.Equal("0x8db97c7cece249c2b98bdc0226cc4c2a57bf52fc", allocations[1].Address)
+	require.Equal("1000000000000000000", allocations[1].Balance.String())
+	require.False(allocations[1].HasCode)
+
+	roles, err := preset.PrecompileRoles()
+	require.NoError(err)
+	require.Equal([]PrecompileRole{
+		{Precompile: "contractNativeMinter", Role: "admin", Addresses: []string{"0x1111111111111111111111111111111111111111"}},
+		{Precompile: "txAllowList", Role: "admin", Addresses: []string{"0x2222222222222222222222222222222222222222"}},
+		{Precompile: "txAllowList", Role: "enabled", Addresses: []string{"0x3333333333333333333333333333333333333333", "0x4444444444444444444444444444444444444444"}},
+	}, roles)
+}
+
+func TestWriteGenesis(t *testing.T) {
+	require := require.New(t)
+	app := newTestApp(t)
+	preset := &Preset{Name: "gaming-l1", Version: "1.0.0", Genesis: json.RawMessage(testGenesis)}
+	require.NoError(preset.Validate())
+
+	genesisPath, err := WriteGenesis(app, preset, 0)
+	require.NoError(err)
+	genesisBytes, err := os.ReadFile(genesisPath)
+	require.NoError(err)
+	require.JSONEq(testGenesis, string(genesisBytes))
+
+	for _, version := range []string{"../../../../tmp/x", "1.0.0/..", "..", "1.0.0/evil"} {
+		_, err = WriteGenesis(app, &Preset{Name: "gaming-l1", Version: version, Genesis: json.RawMessage(testGenesis)}, 0)
+		require.ErrorContains(err, "invalid preset version")
+	}
+
+	genesisPath, err = WriteGenesis(app, preset, 4242)
+	require.NoError(err)
+	genesisBytes, err = os.ReadFile(genesisPath)
+	require.NoError(err)
+	genesis := map[string]interface{}{}
+	require.NoError(json.Unmarshal(genesisBytes, &genesis))
+	genesisConfig := genesis["config"].(map[string]interface{})
+	require.Equal(float64(4242), genesisConfig["chainId"])
+	require.Equal(float64(20000000), genesisConfig["feeConfig"].(map[string]interface{})["gasLimit"])
+}
+
+func TestIsGitRegistry(t *testing.T) {
+	require := require.New(t)
+	require.True(IsGitRegistry("https://github.com/ava-labs/presets.git"))
+	require.True(IsGitRegistry("https://github.com/ava-labs/presets.git#v1.2.0"))
+	require.True(IsGitRegistry("git@github.com:ava-labs/presets"))
+	require.True(IsGitRegistry("git+https://example.com/presets"))
+	require.False(IsGitRegistry("https://presets.example.com/index.json"))
+	require.False(IsGitRegistry("/opt/presets"))
+}
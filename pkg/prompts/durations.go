@@ -0,0 +1,122 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/genesis"
+)
+
+const (
+	day = 24 * time.Hour
+	// minimum staking duration for PoS L1 validators on Mainnet
+	mainnetL1MinStakeDuration = day
+)
+
+var daysRegex = regexp.MustCompile(`(\d+(?:\.\d+)?)d`)
+
+// ParseDuration parses a duration in the format accepted by time.ParseDuration,
+// additionally supporting days (eg "90d", "1d12h") and spaces between
+// components (eg "3h 30m")
+func ParseDuration(input string) (time.Duration, error) {
+	s := strings.Join(strings.Fields(input), "")
+	if s == "" {
+		return 0, errors.New("empty duration")
+	}
+	if strings.HasPrefix(s, "-") {
+		return 0, fmt.Errorf("invalid duration %q: duration can't be negative", input)
+	}
+	var d time.Duration
+	for _, match := range daysRegex.FindAllStringSubmatch(s, -1) {
+		days, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", input, err)
+		}
+		d += time.Duration(days * float64(day))
+	}
+	if remainder := daysRegex.ReplaceAllString(s, ""); remainder != "" {
+		rd, err := time.ParseDuration(remainder)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: valid time units are \"d\", \"h\", \"m\", \"s\", \"ms\", \"us\" (or \"µs\"), \"ns\"", input)
+		}
+		d += rd
+	}
+	return d, nil
+}
+
+// ParseDate parses a date either in 'YYYY-MM-DD HH:MM:SS' UTC format or in RFC3339 format
+func ParseDate(input string) (time.Time, error) {
+	s := strings.TrimSpace(input)
+	if t, err := time.Parse(constants.TimeParseLayout, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: expected 'YYYY-MM-DD HH:MM:SS' UTC or RFC3339 format", input)
+	}
+	return t.UTC(), nil
+}
+
+// ParseDurationOrDeadline parses either a duration, or a deadline date from which
+// the duration starting at [start] is computed
+func ParseDurationOrDeadline(input string, start time.Time) (time.Duration, error) {
+	if d, err := ParseDuration(input); err == nil {
+		return d, nil
+	}
+	deadline, err := ParseDate(input)
+	if err != nil {
+		return 0, fmt.Errorf("invalid input %q: expected a duration (eg 90d, 3h30m) or a 'YYYY-MM-DD HH:MM:SS' UTC or RFC3339 date", input)
+	}
+	if !deadline.After(start) {
+		return 0, fmt.Errorf("the provided date is not after %s UTC", start.UTC().Format(constants.TimeParseLayout))
+	}
+	return deadline.Sub(start), nil
+}
+
+// ParseDateOrDuration parses either a date, or a duration counted from [now]
+func ParseDateOrDuration(input string, now time.Time) (time.Time, error) {
+	if t, err := ParseDate(input); err == nil {
+		return t, nil
+	}
+	d, err := ParseDuration(input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid input %q: expected a 'YYYY-MM-DD HH:MM:SS' UTC or RFC3339 date, or a duration from now (eg 2d, 3h30m)", input)
+	}
+	return now.Add(d).UTC(), nil
+}
+
+// validateDurationRange checks that [d] is in between [minDuration] and [maxDuration].
+// A zero [maxDuration] means there is no upper bound
+func validateDurationRange(d time.Duration, minDuration time.Duration, maxDuration time.Duration) error {
+	if maxDuration != 0 && d > maxDuration {
+		return fmt.Errorf("exceeds maximum duration of %s", ux.FormatDuration(maxDuration))
+	}
+	if d < minDuration {
+		return fmt.Errorf("below the minimum duration of %s", ux.FormatDuration(minDuration))
+	}
+	return nil
+}
+
+// StakingDurationRange returns the min and max staking durations for validators on
+// a network of the given kind. A zero max duration means there is no upper bound
+func StakingDurationRange(networkKind models.NetworkKind, isPos bool) (time.Duration, time.Duration) {
+	switch {
+	case networkKind == models.Fuji:
+		return genesis.FujiParams.MinStakeDuration, genesis.FujiParams.MaxStakeDuration
+	case networkKind == models.Mainnet && isPos:
+		return mainnetL1MinStakeDuration, genesis.MainnetParams.MaxStakeDuration
+	case networkKind == models.Mainnet:
+		return genesis.MainnetParams.MinStakeDuration, genesis.MainnetParams.MaxStakeDuration
+	default:
+		return 0, 0
+	}
+}
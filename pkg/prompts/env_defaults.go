@@ -0,0 +1,95 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/manifoldco/promptui"
+)
+
+var (
+	// terminal color sequences prompts can be decorated with
+	ansiSequenceRegex  = regexp.MustCompile("\x1b\\[[0-9;]*m")
+	parenthesizedRegex = regexp.MustCompile(`\([^)]*\)`)
+	nonAlphanumRegex   = regexp.MustCompile(`[^A-Z0-9]+`)
+)
+
+// PromptEnvVarName returns the environment variable that provides the answer to the
+// prompt [promptStr]. Every prompt can be answered from the environment, with a
+// variable named after its text, as documented in docs/prompt-env-vars.md:
+// parenthesized text is dropped, ASCII letters and digits are upper cased, every other
+// sequence of characters becomes a single underscore, and the result is prefixed
+// with AVALANCHE_PROMPT_. Eg "Token Symbol" is answered by AVALANCHE_PROMPT_TOKEN_SYMBOL
+func PromptEnvVarName(promptStr string) string {
+	name := ansiSequenceRegex.ReplaceAllString(promptStr, "")
+	name = parenthesizedRegex.ReplaceAllString(name, "")
+	name = nonAlphanumRegex.ReplaceAllString(strings.ToUpper(name), "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return ""
+	}
+	return constants.PromptEnvVarPrefix + name
+}
+
+// getPromptEnvValue returns the answer given by environment for the prompt with [label], if any
+func getPromptEnvValue(label interface{}) (string, string, bool) {
+	name := PromptEnvVarName(fmt.Sprint(label))
+	if name == "" {
+		return "", "", false
+	}
+	value, ok := os.LookupEnv(name)
+	return name, value, ok
+}
+
+// runPrompt answers [prompt] with the value of its environment variable, if set,
+// or else asks the user
+func runPrompt(prompt promptui.Prompt) (string, error) {
+	name, value, ok := getPromptEnvValue(prompt.Label)
+	if !ok {
+		return prompt.Run()
+	}
+	if prompt.Validate != nil {
+		if err := prompt.Validate(value); err != nil {
+			return "", fmt.Errorf("invalid value on %s: %w", name, err)
+		}
+	}
+	shownValue := value
+	if prompt.Mask != 0 {
+		shownValue = strings.Repeat(string(prompt.Mask), len(value))
+	}
+	ux.Logger.PrintToUser("%s: %s (from %s)", prompt.Label, shownValue, name)
+	return value, nil
+}
+
+// runSelect answers [prompt] with the option matching the value of its environment
+// variable, if set, or else asks the user
+func runSelect(prompt promptui.Select) (int, string, error) {
+	name, value, ok := getPromptEnvValue(prompt.Label)
+	if !ok {
+		return prompt.Run()
+	}
+	items := reflect.ValueOf(prompt.Items)
+	if items.Kind() == reflect.Slice {
+		for i := 0; i < items.Len(); i++ {
+			option := fmt.Sprint(items.Index(i).Interface())
+			if strings.EqualFold(option, strings.TrimSpace(value)) {
+				ux.Logger.PrintToUser("%s: %s (from %s)", prompt.Label, option, name)
+				return i, option, nil
+			}
+		}
+	}
+	return 0, "", fmt.Errorf("invalid value on %s: %q is not one of the options %v", name, value, prompt.Items)
+}
+
+// promptAnsweredByEnv returns the environment variable that answers [promptStr], if set
+func promptAnsweredByEnv(promptStr string) (string, bool) {
+	name, _, ok := getPromptEnvValue(promptStr)
+	return name, ok
+}
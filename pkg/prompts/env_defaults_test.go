@@ -0,0 +1,65 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package prompts
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/manifoldco/promptui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptEnvVarName(t *testing.T) {
+	require := require.New(t)
+	require.Equal("AVALANCHE_PROMPT_TOKEN_SYMBOL", PromptEnvVarName("Token Symbol"))
+	require.Equal("AVALANCHE_PROMPT_TOKEN_SYMBOL", PromptEnvVarName("Token symbol"))
+	require.Equal("AVALANCHE_PROMPT_ENTER_THE_ADDRESS_OF_THE_HOME", PromptEnvVarName(" Enter the address of the Home "))
+	require.Equal("AVALANCHE_PROMPT_WHAT_S_THE_CHAIN_ID", PromptEnvVarName("What's the chain id?"))
+	require.Equal(
+		"AVALANCHE_PROMPT_WHICH_VERSION_OF_AVALANCHEGO_WOULD_YOU_LIKE_TO_INSTALL",
+		PromptEnvVarName("Which version of AvalancheGo would you like to install? (Use format v1.10.13)"),
+	)
+	require.Equal("AVALANCHE_PROMPT_BRANCH", PromptEnvVarName("Branch"))
+	require.Equal("AVALANCHE_PROMPT_CHOOSE_A_KEY", PromptEnvVarName(logging.Green.Wrap("Choose a key")))
+	require.Empty(PromptEnvVarName(" ? "))
+}
+
+func TestRunPromptFromEnv(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	t.Setenv("AVALANCHE_PROMPT_TOKEN_SYMBOL", "TEST")
+	value, err := runPrompt(promptui.Prompt{Label: "Token Symbol", Validate: validateNonEmpty})
+	require.NoError(err)
+	require.Equal("TEST", value)
+
+	t.Setenv("AVALANCHE_PROMPT_TOKEN_SYMBOL", "")
+	_, err = runPrompt(promptui.Prompt{
+		Label:    "Token Symbol",
+		Validate: func(string) error { return errors.New("invalid") },
+	})
+	require.ErrorContains(err, "AVALANCHE_PROMPT_TOKEN_SYMBOL")
+
+	// any prompt can be answered, not only the ones listed on the docs
+	t.Setenv("AVALANCHE_PROMPT_WHAT_IS_THE_SUBNET_EVM_CHAIN_ID", "12345")
+	value, err = runPrompt(promptui.Prompt{Label: "What is the Subnet-EVM chain ID?", Validate: validateNonEmpty})
+	require.NoError(err)
+	require.Equal("12345", value)
+}
+
+func TestRunSelectFromEnv(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	t.Setenv("AVALANCHE_PROMPT_DO_YOU_WANT_TO_ENABLE_DYNAMIC_FEES", "no")
+	index, option, err := runSelect(promptui.Select{Label: "Do you want to enable dynamic fees?", Items: []string{Yes, No}})
+	require.NoError(err)
+	require.Equal(1, index)
+	require.Equal(No, option)
+
+	t.Setenv("AVALANCHE_PROMPT_DO_YOU_WANT_TO_ENABLE_DYNAMIC_FEES", "maybe")
+	_, _, err = runSelect(promptui.Select{Label: "Do you want to enable dynamic fees?", Items: []any{Yes, No}})
+	require.Error(err)
+}
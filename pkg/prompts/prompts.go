@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/big"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
 	"golang.org/x/mod/semver"
+	"golang.org/x/term"
 )
 
 type AddressFormat int64
@@ -55,6 +57,11 @@ const (
 
 var errNoKeys = errors.New("no keys")
 
+// IsInteractive returns true if prompts can be answered by the user from a terminal
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
 type Comparator struct {
 	Label string // Label that identifies reference value
 	Type  string // Less Than Eq or More than Eq
@@ -94,6 +101,7 @@ type Prompter interface {
 	CaptureList(promptStr string, options []string) (string, error)
 	CaptureListWithSize(promptStr string, options []string, size int) (string, error)
 	CaptureString(promptStr string) (string, error)
+	CapturePassword(promptStr string) (string, error)
 	CaptureValidatedString(promptStr string, validator func(string) error) (string, error)
 	CaptureURL(promptStr string, validateConnection bool) (string, error)
 	CaptureRepoBranch(promptStr string, repo string) (string, error)
@@ -104,6 +112,7 @@ type Prompter interface {
 	CaptureIndex(promptStr string, options []any) (int, error)
 	CaptureVersion(promptStr string) (string, error)
 	CaptureDuration(promptStr string) (time.Duration, error)
+	CaptureDurationInRange(promptStr string, start time.Time, minDuration time.Duration, maxDuration time.Duration) (time.Duration, error)
 	CaptureFujiDuration(promptStr string) (time.Duration, error)
 	CaptureMainnetDuration(promptStr string) (time.Duration, error)
 	CaptureMainnetL1StakingDuration(promptStr string) (time.Duration, error)
@@ -213,54 +222,70 @@ func (*realPrompter) CaptureDuration(promptStr string) (time.Duration, error) {
 		Validate: validateDuration,
 	}
 
-	durationStr, err := prompt.Run()
+	durationStr, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
 
-	return time.ParseDuration(durationStr)
+	return ParseDuration(durationStr)
 }
 
-func (*realPrompter) CaptureFujiDuration(promptStr string) (time.Duration, error) {
+// CaptureDurationInRange requires from the user a duration in between [minDuration]
+// and [maxDuration] (unbounded if zero), either given as a duration (eg 90d, 3h30m)
+// or as a deadline date from [start]
+func (*realPrompter) CaptureDurationInRange(
+	promptStr string,
+	start time.Time,
+	minDuration time.Duration,
+	maxDuration time.Duration,
+) (time.Duration, error) {
 	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateFujiStakingDuration,
+		Label: promptStr,
+		Validate: func(s string) error {
+			d, err := ParseDurationOrDeadline(s, start)
+			if err != nil {
+				return err
+			}
+			return validateDurationRange(d, minDuration, maxDuration)
+		},
 	}
 
-	durationStr, err := prompt.Run()
+	durationStr, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
 
-	return time.ParseDuration(durationStr)
+	return ParseDurationOrDeadline(durationStr, start)
 }
 
-func (*realPrompter) CaptureMainnetDuration(promptStr string) (time.Duration, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateMainnetStakingDuration,
-	}
-
-	durationStr, err := prompt.Run()
-	if err != nil {
-		return 0, err
-	}
-
-	return time.ParseDuration(durationStr)
+func (prompter *realPrompter) CaptureFujiDuration(promptStr string) (time.Duration, error) {
+	minDuration, maxDuration := StakingDurationRange(models.Fuji, false)
+	return prompter.CaptureDurationInRange(
+		promptStr,
+		time.Now(),
+		minDuration,
+		maxDuration,
+	)
 }
 
-func (*realPrompter) CaptureMainnetL1StakingDuration(promptStr string) (time.Duration, error) {
-	prompt := promptui.Prompt{
-		Label:    promptStr,
-		Validate: validateMainnetL1StakingDuration,
-	}
-
-	durationStr, err := prompt.Run()
-	if err != nil {
-		return 0, err
-	}
+func (prompter *realPrompter) CaptureMainnetDuration(promptStr string) (time.Duration, error) {
+	minDuration, maxDuration := StakingDurationRange(models.Mainnet, false)
+	return prompter.CaptureDurationInRange(
+		promptStr,
+		time.Now(),
+		minDuration,
+		maxDuration,
+	)
+}
 
-	return time.ParseDuration(durationStr)
+func (prompter *realPrompter) CaptureMainnetL1StakingDuration(promptStr string) (time.Duration, error) {
+	minDuration, maxDuration := StakingDurationRange(models.Mainnet, true)
+	return prompter.CaptureDurationInRange(
+		promptStr,
+		time.Now(),
+		minDuration,
+		maxDuration,
+	)
 }
 
 func (*realPrompter) CaptureDate(promptStr string) (time.Time, error) {
@@ -269,12 +294,12 @@ func (*realPrompter) CaptureDate(promptStr string) (time.Time, error) {
 		Validate: validateTime,
 	}
 
-	timeStr, err := prompt.Run()
+	timeStr, err := runPrompt(prompt)
 	if err != nil {
 		return time.Time{}, err
 	}
 
-	return time.Parse(constants.TimeParseLayout, timeStr)
+	return ParseDateOrDuration(timeStr, time.Now())
 }
 
 func (*realPrompter) CaptureID(promptStr string) (ids.ID, error) {
@@ -283,7 +308,7 @@ func (*realPrompter) CaptureID(promptStr string) (ids.ID, error) {
 		Validate: validateID,
 	}
 
-	idStr, err := prompt.Run()
+	idStr, err := runPrompt(prompt)
 	if err != nil {
 		return ids.Empty, err
 	}
@@ -296,7 +321,7 @@ func (*realPrompter) CaptureNodeID(promptStr string) (ids.NodeID, error) {
 		Validate: ValidateNodeID,
 	}
 
-	nodeIDStr, err := prompt.Run()
+	nodeIDStr, err := runPrompt(prompt)
 	if err != nil {
 		return ids.EmptyNodeID, err
 	}
@@ -313,7 +338,7 @@ func (*realPrompter) CaptureValidatorBalance(
 		Label:    promptStr,
 		Validate: validateValidatorBalanceFunc(availableBalance, minBalance),
 	}
-	amountStr, err := prompt.Run()
+	amountStr, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -332,7 +357,7 @@ func (*realPrompter) CaptureWeight(promptStr string) (uint64, error) {
 		Validate: validateWeight,
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -351,7 +376,7 @@ func (*realPrompter) CaptureInt(promptStr string, validator func(int) error) (in
 			return validator(val)
 		},
 	}
-	input, err := prompt.Run()
+	input, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -373,7 +398,7 @@ func (*realPrompter) CaptureUint8(promptStr string) (uint8, error) {
 			return nil
 		},
 	}
-	input, err := prompt.Run()
+	input, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -395,7 +420,7 @@ func (*realPrompter) CaptureUint16(promptStr string) (uint16, error) {
 			return nil
 		},
 	}
-	input, err := prompt.Run()
+	input, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -417,7 +442,7 @@ func (*realPrompter) CaptureUint32(promptStr string) (uint32, error) {
 			return nil
 		},
 	}
-	input, err := prompt.Run()
+	input, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -434,7 +459,7 @@ func (*realPrompter) CaptureUint64(promptStr string) (uint64, error) {
 		Validate: validateBiggerThanZero,
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -453,7 +478,7 @@ func (*realPrompter) CaptureFloat(promptStr string, validator func(float64) erro
 		},
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -480,7 +505,7 @@ func (*realPrompter) CapturePositiveInt(promptStr string, comparators []Comparat
 		},
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -504,7 +529,7 @@ func (*realPrompter) CaptureUint64Compare(promptStr string, comparators []Compar
 		},
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := runPrompt(prompt)
 	if err != nil {
 		return 0, err
 	}
@@ -518,7 +543,7 @@ func (*realPrompter) CapturePositiveBigInt(promptStr string) (*big.Int, error) {
 		Validate: validatePositiveBigInt,
 	}
 
-	amountStr, err := prompt.Run()
+	amountStr, err := runPrompt(prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -537,7 +562,7 @@ func (*realPrompter) CapturePChainAddress(promptStr string, network models.Netwo
 		Validate: getPChainValidationFunc(network),
 	}
 
-	return prompt.Run()
+	return runPrompt(prompt)
 }
 
 func (*realPrompter) CaptureXChainAddress(promptStr string, network models.Network) (string, error) {
@@ -546,7 +571,7 @@ func (*realPrompter) CaptureXChainAddress(promptStr string, network models.Netwo
 		Validate: getXChainValidationFunc(network),
 	}
 
-	return prompt.Run()
+	return runPrompt(prompt)
 }
 
 func (*realPrompter) CaptureAddress(promptStr string) (common.Address, error) {
@@ -555,7 +580,7 @@ func (*realPrompter) CaptureAddress(promptStr string) (common.Address, error) {
 		Validate: ValidateAddress,
 	}
 
-	addressStr, err := prompt.Run()
+	addressStr, err := runPrompt(prompt)
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -567,6 +592,14 @@ func (*realPrompter) CaptureAddress(promptStr string) (common.Address, error) {
 func (*realPrompter) CaptureAddresses(promptStr string) ([]common.Address, error) {
 	addressesStr := ""
 	validated := false
+	if name, value, ok := getPromptEnvValue(promptStr); ok {
+		if err := validateAddresses(value); err != nil {
+			return nil, fmt.Errorf("invalid value on %s: %w", name, err)
+		}
+		ux.Logger.PrintToUser("%s: %s (from %s)", promptStr, value, name)
+		addressesStr = value
+		validated = true
+	}
 	for !validated {
 		var err error
 		addressesStr, err = utils.ReadLongString(promptui.IconGood + " " + promptStr + " ")
@@ -594,7 +627,7 @@ func (*realPrompter) CaptureExistingFilepath(promptStr string) (string, error) {
 		Validate: validateExistingFilepath,
 	}
 
-	pathStr, err := prompt.Run()
+	pathStr, err := runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -609,7 +642,7 @@ func (*realPrompter) CaptureNewFilepath(promptStr string) (string, error) {
 		Validate: validateNewFilepath,
 	}
 
-	pathStr, err := prompt.Run()
+	pathStr, err := runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -623,7 +656,7 @@ func yesNoBase(promptStr string, orderedOptions []string) (bool, error) {
 		Items: orderedOptions,
 	}
 
-	_, decision, err := prompt.Run()
+	_, decision, err := runSelect(prompt)
 	if err != nil {
 		return false, err
 	}
@@ -643,7 +676,7 @@ func (*realPrompter) CaptureList(promptStr string, options []string) (string, er
 		Label: promptStr,
 		Items: options,
 	}
-	_, listDecision, err := prompt.Run()
+	_, listDecision, err := runSelect(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -656,7 +689,7 @@ func (*realPrompter) CaptureListWithSize(promptStr string, options []string, siz
 		Items: options,
 		Size:  size,
 	}
-	_, listDecision, err := prompt.Run()
+	_, listDecision, err := runSelect(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -669,7 +702,7 @@ func (*realPrompter) CaptureEmail(promptStr string) (string, error) {
 		Validate: validateEmail,
 	}
 
-	str, err := prompt.Run()
+	str, err := runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -682,7 +715,7 @@ func (*realPrompter) CaptureStringAllowEmpty(promptStr string) (string, error) {
 		Label: promptStr,
 	}
 
-	str, err := prompt.Run()
+	str, err := runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -696,16 +729,20 @@ func (*realPrompter) CaptureURL(promptStr string, validateConnection bool) (stri
 			Label:    promptStr,
 			Validate: validateURLFormat,
 		}
-		str, err := prompt.Run()
+		str, err := runPrompt(prompt)
 		if err != nil {
 			return "", err
 		}
 		if !validateConnection {
 			return str, nil
 		}
-		if err := ValidateURL(str); err == nil {
+		err = ValidateURL(str)
+		if err == nil {
 			return str, nil
 		}
+		if name, ok := promptAnsweredByEnv(promptStr); ok {
+			return "", fmt.Errorf("invalid URL on %s: %w", name, err)
+		}
 		ux.Logger.PrintToUser("Invalid URL: %s", err)
 	}
 }
@@ -717,13 +754,16 @@ func (*realPrompter) CaptureRepoBranch(promptStr string, repo string) (string, e
 			Label:    promptStr,
 			Validate: validateNonEmpty,
 		}
-		str, err := prompt.Run()
+		str, err := runPrompt(prompt)
 		if err != nil {
 			return "", err
 		}
 		if err = ValidateRepoBranch(repo, str); err == nil {
 			return str, nil
 		}
+		if name, ok := promptAnsweredByEnv(promptStr); ok {
+			return "", fmt.Errorf("invalid repo branch on %s: %w", name, err)
+		}
 		ux.Logger.PrintToUser("Invalid Repo Branch: %s", err)
 	}
 }
@@ -735,13 +775,16 @@ func (*realPrompter) CaptureRepoFile(promptStr string, repo string, branch strin
 			Label:    promptStr,
 			Validate: validateNonEmpty,
 		}
-		str, err := prompt.Run()
+		str, err := runPrompt(prompt)
 		if err != nil {
 			return "", err
 		}
 		if err = ValidateRepoFile(repo, branch, str); err == nil {
 			return str, nil
 		}
+		if name, ok := promptAnsweredByEnv(promptStr); ok {
+			return "", fmt.Errorf("invalid repo file on %s: %w", name, err)
+		}
 		ux.Logger.PrintToUser("Invalid Repo File: %s", err)
 	}
 }
@@ -752,7 +795,22 @@ func (*realPrompter) CaptureString(promptStr string) (string, error) {
 		Validate: validateNonEmpty,
 	}
 
-	str, err := prompt.Run()
+	str, err := runPrompt(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	return str, nil
+}
+
+func (*realPrompter) CapturePassword(promptStr string) (string, error) {
+	prompt := promptui.Prompt{
+		Label:    promptStr,
+		Validate: validateNonEmpty,
+		Mask:     '*',
+	}
+
+	str, err := runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -766,7 +824,7 @@ func (*realPrompter) CaptureValidatedString(promptStr string, validator func(str
 		Validate: validator,
 	}
 
-	str, err := prompt.Run()
+	str, err := runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -780,7 +838,7 @@ func (*realPrompter) CaptureGitURL(promptStr string) (*url.URL, error) {
 		Validate: validateURLFormat,
 	}
 
-	str, err := prompt.Run()
+	str, err := runPrompt(prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -804,7 +862,7 @@ func (*realPrompter) CaptureVersion(promptStr string) (string, error) {
 		},
 	}
 
-	str, err := prompt.Run()
+	str, err := runPrompt(prompt)
 	if err != nil {
 		return "", err
 	}
@@ -818,21 +876,22 @@ func (*realPrompter) CaptureIndex(promptStr string, options []any) (int, error)
 		Items: options,
 	}
 
-	listIndex, _, err := prompt.Run()
+	listIndex, _, err := runSelect(prompt)
 	if err != nil {
 		return 0, err
 	}
 	return listIndex, nil
 }
 
-// CaptureFutureDate requires from the user a date input which is in the future.
+// CaptureFutureDate requires from the user a date input which is in the future,
+// either given as a date or as a duration from now (eg 2d, 3h30m).
 // If `minDate` is not empty, the minimum time in the future from the provided date is required
 // Otherwise, time from time.Now() is chosen.
 func (*realPrompter) CaptureFutureDate(promptStr string, minDate time.Time) (time.Time, error) {
 	prompt := promptui.Prompt{
 		Label: promptStr,
 		Validate: func(s string) error {
-			t, err := time.Parse(constants.TimeParseLayout, s)
+			t, err := ParseDateOrDuration(s, time.Now())
 			if err != nil {
 				return err
 			}
@@ -840,18 +899,18 @@ func (*realPrompter) CaptureFutureDate(promptStr string, minDate time.Time) (tim
 				minDate = time.Now()
 			}
 			if t.Before(minDate.UTC()) {
-				return fmt.Errorf("the provided date is before %s UTC", minDate.Format(constants.TimeParseLayout))
+				return fmt.Errorf("the provided date is before %s UTC", minDate.UTC().Format(constants.TimeParseLayout))
 			}
 			return nil
 		},
 	}
 
-	timestampStr, err := prompt.Run()
+	timestampStr, err := runPrompt(prompt)
 	if err != nil {
 		return time.Time{}, err
 	}
 
-	return time.Parse(constants.TimeParseLayout, timestampStr)
+	return ParseDateOrDuration(timestampStr, time.Now())
 }
 
 // returns true [resp. false] if user chooses stored key [resp. ledger] option
@@ -4,6 +4,7 @@ package prompts
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -36,3 +37,73 @@ func TestContains(t *testing.T) {
 	require.True(contains(addrList, addr2))
 	require.False(contains(addrList, addr3))
 }
+
+func TestParseDuration(t *testing.T) {
+	require := require.New(t)
+
+	for input, expected := range map[string]time.Duration{
+		"90d":      90 * 24 * time.Hour,
+		"1d12h":    36 * time.Hour,
+		"1.5d":     36 * time.Hour,
+		"3h30m":    3*time.Hour + 30*time.Minute,
+		" 3h 30m ": 3*time.Hour + 30*time.Minute,
+		"8760h":    8760 * time.Hour,
+	} {
+		d, err := ParseDuration(input)
+		require.NoError(err, input)
+		require.Equal(expected, d, input)
+	}
+	for _, input := range []string{"", "90", "1d30", "-1d", "3x", "2024-01-01 00:00:00"} {
+		_, err := ParseDuration(input)
+		require.Error(err, input)
+	}
+}
+
+func TestParseDurationOrDeadline(t *testing.T) {
+	require := require.New(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d, err := ParseDurationOrDeadline("14d", start)
+	require.NoError(err)
+	require.Equal(14*24*time.Hour, d)
+
+	d, err = ParseDurationOrDeadline("2024-01-15 12:00:00", start)
+	require.NoError(err)
+	require.Equal(14*24*time.Hour+12*time.Hour, d)
+
+	d, err = ParseDurationOrDeadline("2024-01-02T02:00:00+02:00", start)
+	require.NoError(err)
+	require.Equal(24*time.Hour, d)
+
+	_, err = ParseDurationOrDeadline("2023-12-31 00:00:00", start)
+	require.ErrorContains(err, "is not after")
+}
+
+func TestParseDateOrDuration(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	date, err := ParseDateOrDuration("2d", now)
+	require.NoError(err)
+	require.Equal(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), date)
+
+	date, err = ParseDateOrDuration("2024-02-01 10:30:00", now)
+	require.NoError(err)
+	require.Equal(time.Date(2024, 2, 1, 10, 30, 0, 0, time.UTC), date)
+
+	date, err = ParseDateOrDuration("2024-02-01T10:30:00-03:00", now)
+	require.NoError(err)
+	require.Equal(time.Date(2024, 2, 1, 13, 30, 0, 0, time.UTC), date)
+
+	_, err = ParseDateOrDuration("tomorrow", now)
+	require.Error(err)
+}
+
+func TestValidateDurationRange(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(validateDurationRange(48*time.Hour, 24*time.Hour, 0))
+	require.NoError(validateDurationRange(24*time.Hour, 24*time.Hour, 48*time.Hour))
+	require.ErrorContains(validateDurationRange(time.Hour, 24*time.Hour, 48*time.Hour), "below the minimum duration")
+	require.ErrorContains(validateDurationRange(72*time.Hour, 24*time.Hour, 48*time.Hour), "exceeds maximum duration")
+}
@@ -15,12 +15,9 @@ import (
 	"strings"
 	"time"
 
-	"github.com/ava-labs/avalanchego/genesis"
-
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
-	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
 	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
@@ -44,56 +41,13 @@ func validatePositiveBigInt(input string) error {
 	return nil
 }
 
-func validateMainnetStakingDuration(input string) error {
-	d, err := time.ParseDuration(input)
-	if err != nil {
-		return err
-	}
-	if d > genesis.MainnetParams.MaxStakeDuration {
-		return fmt.Errorf("exceeds maximum staking duration of %s", ux.FormatDuration(genesis.MainnetParams.MaxStakeDuration))
-	}
-	if d < genesis.MainnetParams.MinStakeDuration {
-		return fmt.Errorf("below the minimum staking duration of %s", ux.FormatDuration(genesis.MainnetParams.MinStakeDuration))
-	}
-	return nil
-}
-
-func validateMainnetL1StakingDuration(input string) error {
-	const minL1StakingDuration = 24 * time.Hour
-	d, err := time.ParseDuration(input)
-	if err != nil {
-		return err
-	}
-	if d > genesis.MainnetParams.MaxStakeDuration {
-		return fmt.Errorf("exceeds maximum staking duration of %s", ux.FormatDuration(genesis.MainnetParams.MaxStakeDuration))
-	}
-	if d < minL1StakingDuration {
-		return fmt.Errorf("below the minimum staking duration of %s", ux.FormatDuration(minL1StakingDuration))
-	}
-	return nil
-}
-
-func validateFujiStakingDuration(input string) error {
-	d, err := time.ParseDuration(input)
-	if err != nil {
-		return err
-	}
-	if d > genesis.FujiParams.MaxStakeDuration {
-		return fmt.Errorf("exceeds maximum staking duration of %s", ux.FormatDuration(genesis.FujiParams.MaxStakeDuration))
-	}
-	if d < genesis.FujiParams.MinStakeDuration {
-		return fmt.Errorf("below the minimum staking duration of %s", ux.FormatDuration(genesis.FujiParams.MinStakeDuration))
-	}
-	return nil
-}
-
 func validateDuration(input string) error {
-	_, err := time.ParseDuration(input)
+	_, err := ParseDuration(input)
 	return err
 }
 
 func validateTime(input string) error {
-	t, err := time.Parse(constants.TimeParseLayout, input)
+	t, err := ParseDateOrDuration(input, time.Now())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,208 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package recovery diagnoses the common causes of a halted L1, and describes how to
+// remediate each one of them
+package recovery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+type Severity string
+
+const (
+	Critical Severity = "critical"
+	Warning  Severity = "warning"
+)
+
+// Action is a remediation that can be automatically applied
+type Action string
+
+const (
+	NoAction                  Action = ""
+	IncreaseBalanceAction     Action = "increase validator balance"
+	ReissueRegistrationAction Action = "re-issue validator registration"
+	ExpireRegistrationAction  Action = "invalidate expired validator registration"
+	CorrectUpgradeFileAction  Action = "correct upgrade file"
+)
+
+const (
+	ValidatorWeightCheck       = "validator weight"
+	ValidatorBalanceCheck      = "validator balance"
+	ValidatorRegistrationCheck = "validator registration"
+	UpgradeFileCheck           = "upgrade file"
+	RPCCheck                   = "rpc"
+)
+
+// Finding is a problem found while diagnosing an L1
+type Finding struct {
+	Check       string
+	Severity    Severity
+	Description string
+	Remediation string
+	Action      Action
+	// validator the finding refers to, if any
+	NodeID       ids.NodeID
+	ValidationID ids.ID
+}
+
+// ValidatorStatus is the state of an L1 validator, as seen by the L1 and P-Chain
+type ValidatorStatus struct {
+	NodeID       ids.NodeID
+	ValidationID ids.ID
+	Weight       uint64
+	// Balance is the P-Chain balance of the validator, in nAVAX
+	Balance   uint64
+	Active    bool
+	Connected bool
+}
+
+// Thresholds are the limits the validator set of an L1 is checked against
+type Thresholds struct {
+	// percentage of the L1 weight that must be connected for the L1 to make progress
+	QuorumPercentage float64
+	// min P-Chain balance, in nAVAX, of an active validator
+	MinBalance uint64
+}
+
+// DiagnoseValidators checks that enough weight of [validators] is active and connected
+// for the L1 to make progress, and that the active validators have enough P-Chain
+// balance to keep paying the continuous fee
+func DiagnoseValidators(validators []ValidatorStatus, thresholds Thresholds) []Finding {
+	findings := []Finding{}
+	var (
+		activeWeight    uint64
+		connectedWeight uint64
+		offline         []string
+	)
+	for _, validator := range validators {
+		switch {
+		case !validator.Active:
+			findings = append(findings, Finding{
+				Check:        ValidatorBalanceCheck,
+				Severity:     Critical,
+				Description:  fmt.Sprintf("validator %s is inactive, as its P-Chain balance ran out. Its weight of %d does not count for consensus", validator.NodeID, validator.Weight),
+				Remediation:  "increase the validator P-Chain balance",
+				Action:       IncreaseBalanceAction,
+				NodeID:       validator.NodeID,
+				ValidationID: validator.ValidationID,
+			})
+			continue
+		case validator.Balance < thresholds.MinBalance:
+			findings = append(findings, Finding{
+				Check:    ValidatorBalanceCheck,
+				Severity: Warning,
+				Description: fmt.Sprintf(
+					"validator %s has a P-Chain balance of %.5f AVAX, below %.5f AVAX, and will become inactive soon",
+					validator.NodeID,
+					float64(validator.Balance)/float64(units.Avax),
+					float64(thresholds.MinBalance)/float64(units.Avax),
+				),
+				Remediation:  "increase the validator P-Chain balance",
+				Action:       IncreaseBalanceAction,
+				NodeID:       validator.NodeID,
+				ValidationID: validator.ValidationID,
+			})
+		}
+		activeWeight += validator.Weight
+		if validator.Connected {
+			connectedWeight += validator.Weight
+		} else {
+			offline = append(offline, validator.NodeID.String())
+		}
+	}
+	if activeWeight == 0 {
+		findings = append(findings, Finding{
+			Check:       ValidatorWeightCheck,
+			Severity:    Critical,
+			Description: "the L1 has no active validators",
+			Remediation: "increase the P-Chain balance of the inactive validators, or add new ones with avalanche blockchain addValidator",
+		})
+		return findings
+	}
+	connectedPercentage := float64(connectedWeight) * 100 / float64(activeWeight)
+	switch {
+	case connectedPercentage < thresholds.QuorumPercentage:
+		findings = append(findings, Finding{
+			Check:    ValidatorWeightCheck,
+			Severity: Critical,
+			Description: fmt.Sprintf(
+				"only %.2f%% of the L1 active weight is online, below the %.0f%% needed for the L1 to make progress. Offline validators: %s",
+				connectedPercentage,
+				thresholds.QuorumPercentage,
+				strings.Join(offline, ", "),
+			),
+			Remediation: "bring the offline validators back online (for CLI clusters, check them with avalanche node status)",
+		})
+	case len(offline) > 0:
+		findings = append(findings, Finding{
+			Check:       ValidatorWeightCheck,
+			Severity:    Warning,
+			Description: fmt.Sprintf("%.2f%% of the L1 active weight is offline. Offline validators: %s", 100-connectedPercentage, strings.Join(offline, ", ")),
+			Remediation: "bring the offline validators back online before more weight goes offline",
+		})
+	}
+	return findings
+}
+
+// DiagnoseRegistrations checks the validator registrations initialized by the CLI that
+// were not completed. [registered] indicates, by validation ID, the registrations already
+// accepted by P-Chain
+func DiagnoseRegistrations(
+	registrations []models.ValidatorRegistration,
+	registered map[string]bool,
+	now time.Time,
+) ([]Finding, error) {
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].Expiry < registrations[j].Expiry
+	})
+	findings := []Finding{}
+	for _, registration := range registrations {
+		validationID, err := ids.FromString(registration.ValidationID)
+		if err != nil {
+			return nil, err
+		}
+		nodeID, err := ids.NodeIDFromString(registration.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		finding := Finding{
+			Check:        ValidatorRegistrationCheck,
+			Severity:     Warning,
+			NodeID:       nodeID,
+			ValidationID: validationID,
+		}
+		switch {
+		case registered[registration.ValidationID]:
+			finding.Description = fmt.Sprintf("validator %s is registered on P-Chain, but not on the validator manager", nodeID)
+			finding.Remediation = "complete the registration on the validator manager"
+			finding.Action = ReissueRegistrationAction
+		case !now.Before(registration.ExpiryTime()):
+			finding.Description = fmt.Sprintf(
+				"registration of validator %s expired at %s without being registered on P-Chain",
+				nodeID,
+				registration.ExpiryTime().Format(time.RFC1123),
+			)
+			finding.Remediation = "invalidate the registration on the validator manager, as avalanche validator watch --reissue does, before adding the validator again"
+			finding.Action = ExpireRegistrationAction
+		default:
+			finding.Description = fmt.Sprintf(
+				"registration of validator %s was not issued to P-Chain, and expires in %s",
+				nodeID,
+				registration.ExpiryTime().Sub(now).Round(time.Second),
+			)
+			finding.Remediation = "issue the registration to P-Chain and complete it on the validator manager"
+			finding.Action = ReissueRegistrationAction
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package recovery
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/stretchr/testify/require"
+)
+
+var testThresholds = Thresholds{
+	QuorumPercentage: 67,
+	MinBalance:       units.Avax / 10,
+}
+
+func TestDiagnoseValidators(t *testing.T) {
+	nodeIDs := []ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()}
+	healthy := []ValidatorStatus{
+		{NodeID: nodeIDs[0], Weight: 100, Balance: units.Avax, Active: true, Connected: true},
+		{NodeID: nodeIDs[1], Weight: 100, Balance: units.Avax, Active: true, Connected: true},
+		{NodeID: nodeIDs[2], Weight: 100, Balance: units.Avax, Active: true, Connected: true},
+	}
+	require.Empty(t, DiagnoseValidators(healthy, testThresholds))
+
+	oneOffline := append([]ValidatorStatus{}, healthy...)
+	oneOffline[2].Connected = false
+	findings := DiagnoseValidators(oneOffline, testThresholds)
+	require.Len(t, findings, 1)
+	require.Equal(t, Critical, findings[0].Severity)
+	require.Equal(t, ValidatorWeightCheck, findings[0].Check)
+	require.Contains(t, findings[0].Description, nodeIDs[2].String())
+
+	oneOffline[0].Weight = 300
+	findings = DiagnoseValidators(oneOffline, testThresholds)
+	require.Len(t, findings, 1)
+	require.Equal(t, Warning, findings[0].Severity)
+
+	expired := append([]ValidatorStatus{}, healthy...)
+	expired[1].Active = false
+	expired[1].Balance = 0
+	expired[2].Balance = units.Avax / 100
+	findings = DiagnoseValidators(expired, testThresholds)
+	require.Len(t, findings, 2)
+	require.Equal(t, Critical, findings[0].Severity)
+	require.Equal(t, IncreaseBalanceAction, findings[0].Action)
+	require.Equal(t, nodeIDs[1], findings[0].NodeID)
+	require.Equal(t, Warning, findings[1].Severity)
+	require.Equal(t, IncreaseBalanceAction, findings[1].Action)
+
+	for i := range expired {
+		expired[i].Active = false
+	}
+	findings = DiagnoseValidators(expired, testThresholds)
+	require.Len(t, findings, 4)
+	require.Equal(t, "the L1 has no active validators", findings[3].Description)
+}
+
+func TestDiagnoseRegistrations(t *testing.T) {
+	now := time.Now()
+	newRegistration := func(expiry time.Time) models.ValidatorRegistration {
+		return models.ValidatorRegistration{
+			NodeID:       ids.GenerateTestNodeID().String(),
+			ValidationID: ids.GenerateTestID().String(),
+			Expiry:       uint64(expiry.Unix()),
+		}
+	}
+	expired := newRegistration(now.Add(-time.Hour))
+	pending := newRegistration(now.Add(time.Hour))
+	registered := newRegistration(now.Add(-2 * time.Hour))
+	findings, err := DiagnoseRegistrations(
+		[]models.ValidatorRegistration{pending, expired, registered},
+		map[string]bool{registered.ValidationID: true},
+		now,
+	)
+	require.NoError(t, err)
+	require.Len(t, findings, 3)
+	require.Equal(t, ReissueRegistrationAction, findings[0].Action)
+	require.Equal(t, registered.ValidationID, findings[0].ValidationID.String())
+	require.Equal(t, ExpireRegistrationAction, findings[1].Action)
+	require.Contains(t, findings[1].Description, "expired")
+	require.Contains(t, findings[1].Remediation, "invalidate the registration")
+	require.Equal(t, ReissueRegistrationAction, findings[2].Action)
+	require.Equal(t, pending.NodeID, findings[2].NodeID.String())
+}
+
+const testAdmin = "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+
+func txAllowListUpgrade(timestamp int64) string {
+	return fmt.Sprintf(`{"txAllowListConfig":{"blockTimestamp":%d,"adminAddresses":["%s"]}}`, timestamp, testAdmin)
+}
+
+func txAllowListDisable(timestamp int64) string {
+	return fmt.Sprintf(`{"txAllowListConfig":{"blockTimestamp":%d,"disable":true}}`, timestamp)
+}
+
+func upgradeFile(upgrades ...string) []byte {
+	list := ""
+	for i, upgrade := range upgrades {
+		if i > 0 {
+			list += ","
+		}
+		list += upgrade
+	}
+	return []byte(fmt.Sprintf(`{"precompileUpgrades":[%s]}`, list))
+}
+
+func TestDiagnoseUpgradeFile(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-24 * time.Hour).Unix()
+	future := now.Add(24 * time.Hour).Unix()
+	lock := upgradeFile(txAllowListUpgrade(past))
+
+	require.Empty(t, DiagnoseUpgradeFile(upgradeFile(txAllowListUpgrade(future)), nil, now))
+	require.Empty(t, DiagnoseUpgradeFile(upgradeFile(txAllowListUpgrade(past), txAllowListDisable(future)), lock, now))
+
+	findings := DiagnoseUpgradeFile([]byte(`{"precompileUpgrades":[`), lock, now)
+	require.Len(t, findings, 1)
+	require.Equal(t, CorrectUpgradeFileAction, findings[0].Action)
+	findings = DiagnoseUpgradeFile([]byte(`{"precompileUpgrades":[`), nil, now)
+	require.Len(t, findings, 1)
+	require.Equal(t, NoAction, findings[0].Action)
+
+	// new upgrade scheduled in the past
+	findings = DiagnoseUpgradeFile(upgradeFile(txAllowListUpgrade(past), txAllowListDisable(past+10)), lock, now)
+	require.Len(t, findings, 1)
+	require.Contains(t, findings[0].Description, "in the past")
+
+	// applied upgrade changed
+	findings = DiagnoseUpgradeFile(upgradeFile(txAllowListUpgrade(past+1), txAllowListDisable(future)), lock, now)
+	require.Len(t, findings, 2)
+	require.Contains(t, findings[0].Description, "already applied")
+
+	// unsorted upgrades
+	findings = DiagnoseUpgradeFile(upgradeFile(txAllowListUpgrade(future+100), txAllowListDisable(future)), nil, now)
+	require.Len(t, findings, 1)
+	require.Contains(t, findings[0].Description, "before the previous upgrade")
+}
+
+func TestCorrectUpgradeFile(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-24 * time.Hour).Unix()
+	lock := upgradeFile(txAllowListUpgrade(past))
+	activation := now.Add(10 * time.Minute)
+
+	// new upgrades in the past are moved to the activation time, keeping their spacing
+	corrected, err := CorrectUpgradeFile(
+		upgradeFile(txAllowListUpgrade(past), txAllowListDisable(past+3600), txAllowListUpgrade(past+7200)),
+		lock,
+		activation,
+	)
+	require.NoError(t, err)
+	require.Empty(t, DiagnoseUpgradeFile(corrected, lock, now))
+	_, entries, err := parseUpgradeFile(corrected)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, uint64(past), entries[0].timestamp)
+	require.Equal(t, uint64(activation.Unix()), entries[1].timestamp)
+	require.Equal(t, uint64(activation.Unix()+3600), entries[2].timestamp)
+
+	// unparseable files are restored from the lock file
+	corrected, err = CorrectUpgradeFile([]byte("{"), lock, activation)
+	require.NoError(t, err)
+	require.Empty(t, DiagnoseUpgradeFile(corrected, lock, now))
+	_, err = CorrectUpgradeFile([]byte("{"), nil, activation)
+	require.ErrorIs(t, err, errNoLockFile)
+}
@@ -0,0 +1,245 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package recovery
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/params"
+)
+
+const (
+	precompileUpgradesKey = "precompileUpgrades"
+	blockTimestampKey     = "blockTimestamp"
+)
+
+var errNoLockFile = errors.New("the upgrade file can not be parsed, and there is no lock file to restore it from")
+
+// upgradeEntry is a precompile upgrade of an upgrade file, kept in its raw form so it
+// can be written back without changes other than its timestamp
+type upgradeEntry struct {
+	name      string
+	config    map[string]interface{}
+	parsed    *params.PrecompileUpgrade
+	parseErr  error
+	timestamp uint64
+}
+
+func (e upgradeEntry) raw() map[string]interface{} {
+	return map[string]interface{}{e.name: e.config}
+}
+
+func (e upgradeEntry) String() string {
+	if e.timestamp == 0 {
+		return e.name
+	}
+	return fmt.Sprintf("%s at %s", e.name, time.Unix(int64(e.timestamp), 0).UTC().Format(time.RFC3339))
+}
+
+func (e upgradeEntry) appliedIn(lockEntries []upgradeEntry) bool {
+	if e.parsed == nil {
+		return false
+	}
+	for _, lockEntry := range lockEntries {
+		if lockEntry.parsed != nil && reflect.DeepEqual(*e.parsed, *lockEntry.parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUpgradeFile decodes [upgradeBytes] preserving its numbers as given, together
+// with its precompile upgrades
+func parseUpgradeFile(upgradeBytes []byte) (map[string]interface{}, []upgradeEntry, error) {
+	top := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(upgradeBytes))
+	decoder.UseNumber()
+	if err := decoder.Decode(&top); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	rawUpgrades, ok := top[precompileUpgradesKey]
+	if !ok {
+		return top, nil, nil
+	}
+	rawList, ok := rawUpgrades.([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not a list", precompileUpgradesKey)
+	}
+	entries := []upgradeEntry{}
+	for i, rawUpgrade := range rawList {
+		upgrade, ok := rawUpgrade.(map[string]interface{})
+		if !ok || len(upgrade) != 1 {
+			return nil, nil, fmt.Errorf("precompile upgrade %d must be an object with a single precompile key", i)
+		}
+		entry := upgradeEntry{}
+		for name, config := range upgrade {
+			entry.name = name
+			entry.config, ok = config.(map[string]interface{})
+			if !ok {
+				return nil, nil, fmt.Errorf("precompile upgrade %d config is not an object", i)
+			}
+		}
+		if ts, ok := entry.config[blockTimestampKey].(json.Number); ok {
+			entry.timestamp, _ = strconv.ParseUint(ts.String(), 10, 64)
+		}
+		entryBytes, err := json.Marshal(entry.raw())
+		if err != nil {
+			return nil, nil, err
+		}
+		parsed := params.PrecompileUpgrade{}
+		if err := json.Unmarshal(entryBytes, &parsed); err != nil {
+			entry.parseErr = err
+		} else {
+			entry.parsed = &parsed
+		}
+		entries = append(entries, entry)
+	}
+	return top, entries, nil
+}
+
+// DiagnoseUpgradeFile checks the upgrade file [upgradeBytes] against the upgrades that
+// were already applied, given on the lock file [lockBytes]. Nodes fail to start, or reject
+// the upgrade, if it can not be parsed, if it changes applied upgrades, if it schedules new
+// upgrades in the past, or if its upgrades are not sorted by timestamp
+func DiagnoseUpgradeFile(upgradeBytes []byte, lockBytes []byte, now time.Time) []Finding {
+	correctable := Finding{
+		Check:       UpgradeFileCheck,
+		Severity:    Critical,
+		Remediation: "generate a corrected upgrade file, keeping the applied upgrades and moving the new ones to the future",
+		Action:      CorrectUpgradeFileAction,
+	}
+	_, entries, err := parseUpgradeFile(upgradeBytes)
+	if err != nil {
+		finding := correctable
+		finding.Description = fmt.Sprintf("the upgrade file can not be parsed: %s", err)
+		finding.Remediation = "restore the applied upgrades from the lock file"
+		if len(lockBytes) == 0 {
+			finding.Remediation = "fix the upgrade file, or remove it"
+			finding.Action = NoAction
+		}
+		return []Finding{finding}
+	}
+	var lockEntries []upgradeEntry
+	if len(lockBytes) > 0 {
+		if _, lockEntries, err = parseUpgradeFile(lockBytes); err != nil {
+			return []Finding{{
+				Check:       UpgradeFileCheck,
+				Severity:    Warning,
+				Description: fmt.Sprintf("the upgrade lock file can not be parsed: %s", err),
+				Remediation: "the applied upgrades can not be checked. Remove the lock file if it is not needed",
+			}}
+		}
+	}
+	findings := []Finding{}
+	for _, lockEntry := range lockEntries {
+		found := false
+		for _, entry := range entries {
+			if entry.parsed != nil && lockEntry.parsed != nil && reflect.DeepEqual(*entry.parsed, *lockEntry.parsed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			finding := correctable
+			finding.Description = fmt.Sprintf("upgrade %s was already applied, but it is missing or was changed on the upgrade file", lockEntry)
+			findings = append(findings, finding)
+		}
+	}
+	var lastTimestamp uint64
+	for _, entry := range entries {
+		finding := correctable
+		switch {
+		case entry.parseErr != nil:
+			finding.Description = fmt.Sprintf("upgrade %s is invalid: %s", entry, entry.parseErr)
+			finding.Remediation = "fix or remove the invalid upgrade. Corrected upgrade files do not include it"
+		case entry.timestamp == 0:
+			finding.Description = fmt.Sprintf("upgrade %s has no activation timestamp", entry)
+		case entry.timestamp < lastTimestamp:
+			finding.Description = fmt.Sprintf("upgrade %s is scheduled before the previous upgrade of the file", entry)
+		case !entry.appliedIn(lockEntries) && !now.Before(time.Unix(int64(entry.timestamp), 0)):
+			finding.Description = fmt.Sprintf("upgrade %s is scheduled in the past, but was never applied", entry)
+		default:
+			lastTimestamp = entry.timestamp
+			continue
+		}
+		lastTimestamp = max(lastTimestamp, entry.timestamp)
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// CorrectUpgradeFile generates a corrected version of the upgrade file [upgradeBytes]. It
+// keeps the applied upgrades of the lock file [lockBytes] as they are, and schedules the
+// new upgrades from [activation] on, in their original order and keeping the original
+// time between them. Invalid upgrades are dropped. If [upgradeBytes] can not be parsed,
+// the applied upgrades are restored from [lockBytes]
+func CorrectUpgradeFile(upgradeBytes []byte, lockBytes []byte, activation time.Time) ([]byte, error) {
+	var (
+		lockTop     map[string]interface{}
+		lockEntries []upgradeEntry
+		err         error
+	)
+	if len(lockBytes) > 0 {
+		lockTop, lockEntries, err = parseUpgradeFile(lockBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lock file: %w", err)
+		}
+	}
+	top, entries, err := parseUpgradeFile(upgradeBytes)
+	if err != nil {
+		if lockTop == nil {
+			return nil, errNoLockFile
+		}
+		return json.MarshalIndent(lockTop, "", "  ")
+	}
+	rawUpgrades := []interface{}{}
+	start := uint64(activation.Unix())
+	for _, lockEntry := range lockEntries {
+		rawUpgrades = append(rawUpgrades, lockEntry.raw())
+		start = max(start, lockEntry.timestamp+1)
+	}
+	newEntries := []upgradeEntry{}
+	for _, entry := range entries {
+		if entry.parseErr == nil && !entry.appliedIn(lockEntries) {
+			newEntries = append(newEntries, entry)
+		}
+	}
+	sort.SliceStable(newEntries, func(i, j int) bool {
+		return newEntries[i].timestamp < newEntries[j].timestamp
+	})
+	var (
+		previousOriginal uint64
+		previous         uint64
+	)
+	for i, entry := range newEntries {
+		timestamp := start
+		if i > 0 {
+			timestamp = previous
+			if entry.timestamp > previousOriginal && previousOriginal != 0 {
+				timestamp += entry.timestamp - previousOriginal
+			}
+		}
+		previousOriginal = entry.timestamp
+		previous = timestamp
+		config := map[string]interface{}{}
+		for k, v := range entry.config {
+			config[k] = v
+		}
+		config[blockTimestampKey] = json.Number(strconv.FormatUint(timestamp, 10))
+		entry.config = config
+		rawUpgrades = append(rawUpgrades, entry.raw())
+	}
+	if len(rawUpgrades) == 0 {
+		delete(top, precompileUpgradesKey)
+	} else {
+		top[precompileUpgradesKey] = rawUpgrades
+	}
+	return json.MarshalIndent(top, "", "  ")
+}
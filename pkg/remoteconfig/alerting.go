@@ -0,0 +1,120 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remoteconfig
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+const (
+	DefaultAlertNodeDownMinutes     = 5
+	DefaultAlertDiskUsagePercent    = 85
+	DefaultAlertChainStalledMinutes = 10
+	// 1 AVAX
+	DefaultAlertMinValidatorBalanceNAVAX = 1_000_000_000
+	// seconds between validator balance queries
+	validatorBalanceExportInterval = 300
+)
+
+// AlertingRulesInputs holds the thresholds of the prometheus alerting rules
+type AlertingRulesInputs struct {
+	NodeDownMinutes          uint
+	DiskUsagePercent         uint
+	ChainStalledMinutes      uint
+	MinValidatorBalanceNAVAX uint64
+}
+
+// AlertmanagerInputs holds the alertmanager receivers settings. Receivers with
+// empty settings are not configured
+type AlertmanagerInputs struct {
+	EmailTo         string
+	EmailFrom       string
+	SMTPHost        string
+	SMTPUsername    string
+	SMTPPassword    string
+	SlackWebhookURL string
+	SlackChannel    string
+}
+
+// ValidatorBalanceExporterInputs holds the settings to export the balances of the
+// validators of [SubnetIDs], queried from the P-Chain API at [Endpoint]
+type ValidatorBalanceExporterInputs struct {
+	Endpoint        string
+	SubnetIDs       []string
+	IntervalSeconds uint
+}
+
+func PrepareAlertingRulesInputs() AlertingRulesInputs {
+	return AlertingRulesInputs{
+		NodeDownMinutes:          DefaultAlertNodeDownMinutes,
+		DiskUsagePercent:         DefaultAlertDiskUsagePercent,
+		ChainStalledMinutes:      DefaultAlertChainStalledMinutes,
+		MinValidatorBalanceNAVAX: DefaultAlertMinValidatorBalanceNAVAX,
+	}
+}
+
+func PrepareValidatorBalanceExporterInputs(endpoint string, subnetIDs []string) ValidatorBalanceExporterInputs {
+	return ValidatorBalanceExporterInputs{
+		Endpoint:        endpoint,
+		SubnetIDs:       subnetIDs,
+		IntervalSeconds: validatorBalanceExportInterval,
+	}
+}
+
+func renderAlertingTemplate(templateName string, inputs interface{}) ([]byte, error) {
+	templateBytes, err := templates.ReadFile(templateName)
+	if err != nil {
+		return nil, err
+	}
+	helperFuncs := template.FuncMap{
+		"join": strings.Join,
+	}
+	tmpl, err := template.New("alerting").Funcs(helperFuncs).Parse(string(templateBytes))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, inputs); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func RenderPrometheusAlertingRules(inputs AlertingRulesInputs) ([]byte, error) {
+	return renderAlertingTemplate("templates/prometheus-alerts.yml", inputs)
+}
+
+func RenderAlertmanagerConfig(inputs AlertmanagerInputs) ([]byte, error) {
+	return renderAlertingTemplate("templates/alertmanager.yml", inputs)
+}
+
+func RenderValidatorBalanceExporterScript(inputs ValidatorBalanceExporterInputs) ([]byte, error) {
+	return renderAlertingTemplate("templates/validator-balance-exporter.sh", inputs)
+}
+
+func GetRemotePrometheusAlertingRules() string {
+	return utils.GetRemoteComposeServicePath("prometheus", "rules", "alerts.yml")
+}
+
+func GetRemoteAlertmanagerConfig() string {
+	return utils.GetRemoteComposeServicePath("alertmanager", "alertmanager.yml")
+}
+
+func GetRemoteValidatorBalanceExporterScript() string {
+	return utils.GetRemoteComposeServicePath("validator-balance-exporter", "validator-balance-exporter.sh")
+}
+
+func AlertingFoldersToCreate() []string {
+	return []string{
+		utils.GetRemoteComposeServicePath("prometheus", "rules"),
+		utils.GetRemoteComposeServicePath("alertmanager"),
+		utils.GetRemoteComposeServicePath("alertmanager", "data"),
+		utils.GetRemoteComposeServicePath("validator-balance-exporter"),
+		utils.GetRemoteComposeServicePath("node-exporter", "textfile"),
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remoteconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPrometheusAlertingRules(t *testing.T) {
+	require := require.New(t)
+	inputs := PrepareAlertingRulesInputs()
+	inputs.DiskUsagePercent = 70
+	rules, err := RenderPrometheusAlertingRules(inputs)
+	require.NoError(err)
+	require.Contains(string(rules), "for: 5m")
+	require.Contains(string(rules), "> 70")
+	require.Contains(string(rules), "[10m]")
+	require.Contains(string(rules), "< 1000000000")
+	// prometheus templating is kept for the annotations
+	require.Contains(string(rules), "{{ $labels.instance }}")
+}
+
+func TestRenderAlertmanagerConfig(t *testing.T) {
+	require := require.New(t)
+	config, err := RenderAlertmanagerConfig(AlertmanagerInputs{})
+	require.NoError(err)
+	require.NotContains(string(config), "email_configs")
+	require.NotContains(string(config), "slack_configs")
+
+	config, err = RenderAlertmanagerConfig(AlertmanagerInputs{
+		EmailTo:         "ops@example.com",
+		EmailFrom:       "alerts@example.com",
+		SMTPHost:        "smtp.example.com:587",
+		SlackWebhookURL: "https://hooks.slack.com/services/T/B/X",
+	})
+	require.NoError(err)
+	require.Contains(string(config), `smtp_smarthost: "smtp.example.com:587"`)
+	require.NotContains(string(config), "smtp_auth_username")
+	require.Contains(string(config), `- to: "ops@example.com"`)
+	require.Contains(string(config), `api_url: "https://hooks.slack.com/services/T/B/X"`)
+	require.Contains(string(config), "{{ .CommonAnnotations.summary }}")
+}
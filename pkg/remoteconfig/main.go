@@ -19,6 +19,7 @@ func RemoteFoldersToCreateMonitoring() []string {
 		LokiFoldersToCreate(),
 		PrometheusFoldersToCreate(),
 		PromtailFoldersToCreate(),
+		AlertingFoldersToCreate(),
 	)
 }
 
@@ -0,0 +1,97 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remoteconfig
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+const (
+	// paths of the user provided certificate inside the reverse proxy container
+	reverseProxyCertFile = "/certs/cert.pem"
+	reverseProxyKeyFile  = "/certs/key.pem"
+)
+
+// ReverseProxyInputs holds the settings of the reverse proxy that serves the avalanchego
+// API of a node over HTTPS at [Domain]. If no certificate is given, it is obtained from
+// Let's Encrypt, registering [Email] (if any) as the ACME account contact.
+// With several [Upstreams], requests are load balanced among the healthy ones.
+// Requests to the blockchains in [AuthTokens] are rejected unless they carry
+// the blockchain token as bearer authorization
+type ReverseProxyInputs struct {
+	Domain     string
+	Email      string
+	CertFile   string
+	KeyFile    string
+	Upstreams  []string
+	AuthTokens map[string]string // maps blockchain ID to auth token
+}
+
+// PrepareReverseProxyInputs returns the settings of a reverse proxy serving at [domain] the
+// avalanchego API of the nodes at [upstreamIPs], or of the proxy host itself if none is given
+func PrepareReverseProxyInputs(
+	domain string,
+	email string,
+	userCert bool,
+	upstreamIPs []string,
+	authTokens map[string]string,
+) ReverseProxyInputs {
+	if len(upstreamIPs) == 0 {
+		upstreamIPs = []string{"127.0.0.1"}
+	}
+	inputs := ReverseProxyInputs{
+		Domain:     domain,
+		Email:      email,
+		AuthTokens: authTokens,
+	}
+	for _, ip := range upstreamIPs {
+		inputs.Upstreams = append(inputs.Upstreams, fmt.Sprintf("%s:%d", ip, constants.AvalancheGoAPIPort))
+	}
+	if userCert {
+		inputs.CertFile = reverseProxyCertFile
+		inputs.KeyFile = reverseProxyKeyFile
+	}
+	return inputs
+}
+
+func RenderCaddyfile(inputs ReverseProxyInputs) ([]byte, error) {
+	templateBytes, err := templates.ReadFile("templates/Caddyfile")
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New("caddy").Parse(string(templateBytes))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, inputs); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func GetRemoteCaddyfile() string {
+	return utils.GetRemoteComposeServicePath("caddy", "Caddyfile")
+}
+
+func GetRemoteReverseProxyCertFile() string {
+	return utils.GetRemoteComposeServicePath("caddy", "certs", "cert.pem")
+}
+
+func GetRemoteReverseProxyKeyFile() string {
+	return utils.GetRemoteComposeServicePath("caddy", "certs", "key.pem")
+}
+
+func ReverseProxyFoldersToCreate() []string {
+	return []string{
+		utils.GetRemoteComposeServicePath("caddy", "certs"),
+		utils.GetRemoteComposeServicePath("caddy", "data"),
+		utils.GetRemoteComposeServicePath("caddy", "config"),
+	}
+}
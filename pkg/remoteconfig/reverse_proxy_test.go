@@ -0,0 +1,43 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remoteconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCaddyfile(t *testing.T) {
+	require := require.New(t)
+	config, err := RenderCaddyfile(PrepareReverseProxyInputs("rpc.example.com", "ops@example.com", false, nil, nil))
+	require.NoError(err)
+	require.Contains(string(config), "email ops@example.com")
+	require.Contains(string(config), "rpc.example.com {")
+	require.Contains(string(config), "reverse_proxy 127.0.0.1:9650 {")
+	require.NotContains(string(config), "lb_policy")
+	require.NotContains(string(config), "tls ")
+
+	require.NotContains(string(config), "Authorization")
+
+	config, err = RenderCaddyfile(PrepareReverseProxyInputs("rpc.example.com", "", true, nil, nil))
+	require.NoError(err)
+	require.NotContains(string(config), "email")
+	require.Contains(string(config), "tls /certs/cert.pem /certs/key.pem")
+
+	config, err = RenderCaddyfile(PrepareReverseProxyInputs("rpc.example.com", "", false, nil, map[string]string{
+		"2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM": "t0k3n",
+	}))
+	require.NoError(err)
+	require.Contains(string(config), "path /ext/bc/2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM /ext/bc/2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM/*")
+	require.Contains(string(config), `not header Authorization "Bearer t0k3n"`)
+	require.Contains(string(config), "respond @unauthorized_2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM \"Unauthorized\" 401")
+
+	// load balancer
+	config, err = RenderCaddyfile(PrepareReverseProxyInputs("rpc.example.com", "", false, []string{"10.0.0.1", "10.0.0.2"}, nil))
+	require.NoError(err)
+	require.Contains(string(config), "reverse_proxy 10.0.0.1:9650 10.0.0.2:9650 {")
+	require.Contains(string(config), "lb_policy round_robin")
+	require.Contains(string(config), "health_uri /ext/health")
+}
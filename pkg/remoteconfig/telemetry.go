@@ -0,0 +1,259 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remoteconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+// dimensions (cloudwatch) and labels (prometheus) identifying the cluster and the node of the metrics
+const (
+	cloudWatchClusterDimension = "cluster"
+	cloudWatchNodeDimension    = "node_id"
+)
+
+// TelemetryInputs holds the settings of the vendor agent that exports the metrics and
+// logs of a node to an external monitoring backend
+type TelemetryInputs struct {
+	ClusterName     string
+	Network         string
+	NodeID          string
+	CloudID         string
+	AvalancheGoPort int
+	// cloudwatch only
+	Region    string
+	Namespace string
+}
+
+type CloudWatchDimension struct {
+	Name  string
+	Value string
+}
+
+func PrepareTelemetryInputs(clusterName string, network string, nodeID string, cloudID string, region string) TelemetryInputs {
+	return TelemetryInputs{
+		ClusterName:     clusterName,
+		Network:         network,
+		NodeID:          nodeID,
+		CloudID:         cloudID,
+		AvalancheGoPort: constants.AvalancheGoAPIPort,
+		Region:          region,
+		Namespace:       constants.CloudWatchDefaultNamespace,
+	}
+}
+
+// Tags returns the datadog tags identifying the node
+func (inputs TelemetryInputs) Tags() []string {
+	return []string{
+		"cluster:" + inputs.ClusterName,
+		"network:" + inputs.Network,
+		"node_id:" + inputs.NodeID,
+		"cloud_id:" + inputs.CloudID,
+	}
+}
+
+// Dimensions returns the cloudwatch dimensions appended to the node system metrics
+func (inputs TelemetryInputs) Dimensions() []CloudWatchDimension {
+	return []CloudWatchDimension{
+		{Name: cloudWatchClusterDimension, Value: inputs.ClusterName},
+		{Name: cloudWatchNodeDimension, Value: inputs.NodeID},
+	}
+}
+
+func (TelemetryInputs) ClusterDimension() string {
+	return cloudWatchClusterDimension
+}
+
+func (TelemetryInputs) NodeDimension() string {
+	return cloudWatchNodeDimension
+}
+
+func (inputs TelemetryInputs) LogGroup() string {
+	return getCloudWatchLogGroup(inputs.ClusterName)
+}
+
+func (inputs TelemetryInputs) LogStream() string {
+	return inputs.NodeID
+}
+
+func getCloudWatchLogGroup(clusterName string) string {
+	return fmt.Sprintf("/avalanche/%s", clusterName)
+}
+
+// TelemetryDashboardChain is a chain shown on the cluster dashboard. Its avalanchego metrics
+// are prefixed with [MetricsID]: the alias of the primary network chains, or the blockchain ID
+type TelemetryDashboardChain struct {
+	Name      string
+	MetricsID string
+}
+
+// TelemetryDashboardInputs holds the settings of the dashboard of a cluster on the external
+// monitoring backend its nodes export metrics and logs to
+type TelemetryDashboardInputs struct {
+	ClusterName string
+	Network     string
+	Chains      []TelemetryDashboardChain
+	// cloudwatch only
+	Region    string
+	Namespace string
+}
+
+func PrepareTelemetryDashboardInputs(clusterName string, network string, chains []TelemetryDashboardChain, region string) TelemetryDashboardInputs {
+	return TelemetryDashboardInputs{
+		ClusterName: clusterName,
+		Network:     network,
+		Chains: append([]TelemetryDashboardChain{
+			{Name: "P-Chain", MetricsID: "P"},
+			{Name: "C-Chain", MetricsID: "C"},
+		}, chains...),
+		Region:    region,
+		Namespace: constants.CloudWatchDefaultNamespace,
+	}
+}
+
+// Title returns the name of the dashboard
+func (inputs TelemetryDashboardInputs) Title() string {
+	return fmt.Sprintf("Avalanche %s (%s)", inputs.ClusterName, inputs.Network)
+}
+
+// DatadogQuery returns the query of [metric] on the cluster nodes, by node
+func (inputs TelemetryDashboardInputs) DatadogQuery(aggregator string, metric string) string {
+	return fmt.Sprintf("%s:%s{cluster:%s} by {node_id}", aggregator, metric, inputs.ClusterName)
+}
+
+// DatadogLogsQuery returns the query of the cluster nodes logs
+func (inputs TelemetryDashboardInputs) DatadogLogsQuery() string {
+	return "cluster:" + inputs.ClusterName
+}
+
+// CloudWatchSearch returns the expression of [metric] on the cluster nodes, one time series
+// per node
+func (inputs TelemetryDashboardInputs) CloudWatchSearch(metric string, stat string) string {
+	return fmt.Sprintf(
+		`SEARCH('Namespace="%s" MetricName="%s" %s="%s"', '%s', 60)`,
+		inputs.Namespace,
+		metric,
+		cloudWatchClusterDimension,
+		inputs.ClusterName,
+		stat,
+	)
+}
+
+// CloudWatchLogsQuery returns the logs insights query of the cluster nodes logs
+func (inputs TelemetryDashboardInputs) CloudWatchLogsQuery() string {
+	return fmt.Sprintf(
+		"SOURCE '%s' | fields @timestamp, @logStream, @message | sort @timestamp desc | limit 100",
+		getCloudWatchLogGroup(inputs.ClusterName),
+	)
+}
+
+// RenderTelemetryDashboard returns the dashboard of the cluster on [backend], as expected by
+// the backend dashboards API
+func RenderTelemetryDashboard(backend string, inputs TelemetryDashboardInputs) ([]byte, error) {
+	switch backend {
+	case constants.MonitoringDatadog:
+		return renderTelemetryTemplate("templates/datadog-dashboard.json", inputs)
+	case constants.MonitoringCloudWatch:
+		return renderTelemetryTemplate("templates/cloudwatch-dashboard.json", inputs)
+	}
+	return nil, fmt.Errorf("unsupported monitoring backend %q", backend)
+}
+
+// jsonString quotes [s] as a JSON string, for the JSON templates
+func jsonString(s string) (string, error) {
+	bs, err := json.Marshal(s)
+	return string(bs), err
+}
+
+func renderTelemetryTemplate(templateFile string, inputs interface{}) ([]byte, error) {
+	templateBytes, err := templates.ReadFile(templateFile)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(templateFile).Funcs(template.FuncMap{"json": jsonString}).Parse(string(templateBytes))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, inputs); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderTelemetryConfigs returns the agent config files of [backend], mapped by their
+// path on the node
+func RenderTelemetryConfigs(backend string, inputs TelemetryInputs) (map[string][]byte, error) {
+	var templateFiles map[string]string // maps remote path to template
+	switch backend {
+	case constants.MonitoringDatadog:
+		templateFiles = map[string]string{
+			utils.GetRemoteComposeServicePath("datadog", "conf.d", "openmetrics.d", "conf.yaml"): "templates/datadog-openmetrics.yaml",
+			utils.GetRemoteComposeServicePath("datadog", "conf.d", "avalanchego.d", "conf.yaml"): "templates/datadog-avalanchego-logs.yaml",
+		}
+	case constants.MonitoringCloudWatch:
+		templateFiles = map[string]string{
+			GetRemoteCloudWatchAgentConfigFile():                                         "templates/cloudwatch-agent.json",
+			utils.GetRemoteComposeServicePath("cloudwatch", "config", "prometheus.yaml"): "templates/cloudwatch-prometheus.yaml",
+		}
+	default:
+		return nil, fmt.Errorf("unsupported monitoring backend %q", backend)
+	}
+	configs := map[string][]byte{}
+	for remotePath, templateFile := range templateFiles {
+		config, err := renderTelemetryTemplate(templateFile, inputs)
+		if err != nil {
+			return nil, err
+		}
+		configs[remotePath] = config
+	}
+	return configs, nil
+}
+
+func GetRemoteCloudWatchAgentConfigFile() string {
+	return utils.GetRemoteComposeServicePath("cloudwatch", "config", "cwagentconfig.json")
+}
+
+// GetRemoteTelemetryCredentialsFile returns the path on the node of the file holding the
+// credentials of the agent of [backend]
+func GetRemoteTelemetryCredentialsFile(backend string) string {
+	if backend == constants.MonitoringCloudWatch {
+		return utils.GetRemoteComposeServicePath("cloudwatch", "credentials")
+	}
+	return utils.GetRemoteComposeServicePath("datadog", "datadog.yaml")
+}
+
+// RenderDatadogAgentConfig returns the main config of the datadog agent, that holds the
+// credentials to export to the datadog [site]. [tags] are set on all the host metrics and logs
+func RenderDatadogAgentConfig(apiKey string, site string, tags []string) []byte {
+	config := fmt.Sprintf("api_key: %q\nsite: %q\nlogs_enabled: true\n", apiKey, site)
+	if len(tags) > 0 {
+		config += "tags:\n"
+		for _, tag := range tags {
+			config += fmt.Sprintf("  - %q\n", tag)
+		}
+	}
+	return []byte(config)
+}
+
+func TelemetryFoldersToCreate(backend string) []string {
+	switch backend {
+	case constants.MonitoringDatadog:
+		return []string{
+			utils.GetRemoteComposeServicePath("datadog", "conf.d", "openmetrics.d"),
+			utils.GetRemoteComposeServicePath("datadog", "conf.d", "avalanchego.d"),
+		}
+	case constants.MonitoringCloudWatch:
+		return []string{
+			utils.GetRemoteComposeServicePath("cloudwatch", "config"),
+		}
+	}
+	return nil
+}
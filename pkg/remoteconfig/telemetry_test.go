@@ -0,0 +1,116 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package remoteconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderTelemetryConfigs(t *testing.T) {
+	require := require.New(t)
+	inputs := PrepareTelemetryInputs("mycluster", "Fuji", "NodeID-abc", "i-123", "us-east-1")
+
+	configs, err := RenderTelemetryConfigs(constants.MonitoringDatadog, inputs)
+	require.NoError(err)
+	require.Len(configs, 2)
+	for _, config := range configs {
+		var parsed map[string]interface{}
+		require.NoError(yaml.Unmarshal(config, &parsed))
+		require.Contains(string(config), `"cluster:mycluster"`)
+		require.Contains(string(config), `"node_id:NodeID-abc"`)
+	}
+
+	configs, err = RenderTelemetryConfigs(constants.MonitoringCloudWatch, inputs)
+	require.NoError(err)
+	require.Len(configs, 2)
+	agentConfig := configs[GetRemoteCloudWatchAgentConfigFile()]
+	var parsed map[string]interface{}
+	require.NoError(json.Unmarshal(agentConfig, &parsed))
+	require.Contains(string(agentConfig), `"region": "us-east-1"`)
+	require.Contains(string(agentConfig), `"log_group_name": "/avalanche/mycluster"`)
+	require.Contains(string(agentConfig), `"cluster": "mycluster"`)
+	require.Contains(string(agentConfig), `"node_id": "NodeID-abc"`)
+
+	_, err = RenderTelemetryConfigs("unknown", inputs)
+	require.Error(err)
+}
+
+func TestRenderDatadogAgentConfig(t *testing.T) {
+	require := require.New(t)
+	var parsed map[string]interface{}
+	require.NoError(yaml.Unmarshal(RenderDatadogAgentConfig("abc123", "datadoghq.eu", []string{"cluster:mycluster"}), &parsed))
+	require.Equal("abc123", parsed["api_key"])
+	require.Equal("datadoghq.eu", parsed["site"])
+	require.Equal(true, parsed["logs_enabled"])
+	require.Equal([]interface{}{"cluster:mycluster"}, parsed["tags"])
+}
+
+func TestRenderTelemetryDashboard(t *testing.T) {
+	require := require.New(t)
+	chains := []TelemetryDashboardChain{{Name: "my\"chain", MetricsID: "2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM"}}
+	inputs := PrepareTelemetryDashboardInputs("mycluster", "Fuji", chains, "us-east-1")
+
+	type widget struct {
+		Definition struct {
+			Type     string `json:"type"`
+			Title    string `json:"title"`
+			Query    string `json:"query"`
+			Requests []struct {
+				Q string `json:"q"`
+			} `json:"requests"`
+		} `json:"definition"`
+	}
+	dashboard, err := RenderTelemetryDashboard(constants.MonitoringDatadog, inputs)
+	require.NoError(err)
+	datadog := struct {
+		Title   string   `json:"title"`
+		Widgets []widget `json:"widgets"`
+	}{}
+	require.NoError(json.Unmarshal(dashboard, &datadog))
+	require.Equal("Avalanche mycluster (Fuji)", datadog.Title)
+	titles := map[string]string{}
+	for _, w := range datadog.Widgets {
+		if len(w.Definition.Requests) > 0 {
+			titles[w.Definition.Title] = w.Definition.Requests[0].Q
+		} else {
+			titles[w.Definition.Title] = w.Definition.Query
+		}
+	}
+	require.Equal("avg:avalanche.avalanche_C_blks_processing{cluster:mycluster} by {node_id}", titles["C-Chain blocks processing"])
+	require.Equal("avg:avalanche.avalanche_2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM_blks_processing{cluster:mycluster} by {node_id}", titles[`my"chain blocks processing`])
+	require.Equal("cluster:mycluster", titles["Logs"])
+
+	dashboard, err = RenderTelemetryDashboard(constants.MonitoringCloudWatch, inputs)
+	require.NoError(err)
+	cloudwatch := struct {
+		Widgets []struct {
+			Type       string `json:"type"`
+			Properties struct {
+				Title   string                `json:"title"`
+				Region  string                `json:"region"`
+				Query   string                `json:"query"`
+				Metrics [][]map[string]string `json:"metrics"`
+			} `json:"properties"`
+		} `json:"widgets"`
+	}{}
+	require.NoError(json.Unmarshal(dashboard, &cloudwatch))
+	require.Len(cloudwatch.Widgets, len(datadog.Widgets))
+	for _, w := range cloudwatch.Widgets {
+		require.Equal("us-east-1", w.Properties.Region)
+		if w.Type == "log" {
+			require.Equal("SOURCE '/avalanche/mycluster' | fields @timestamp, @logStream, @message | sort @timestamp desc | limit 100", w.Properties.Query)
+		}
+		if w.Properties.Title == "Peers" {
+			require.Equal(`SEARCH('Namespace="Avalanche" MetricName="avalanche_network_peers" cluster="mycluster"', 'Average', 60)`, w.Properties.Metrics[0][0]["expression"])
+		}
+	}
+
+	_, err = RenderTelemetryDashboard("unknown", inputs)
+	require.Error(err)
+}
@@ -0,0 +1,27 @@
+#!/bin/sh
+# +++++++++++++++++++++++++++++++++++++++ #
+# DO NOT EDIT THIS FILE                   #
+# THIS FILE IS GENERATED BY AVALANCHE-CLI #
+# ALL CHANGES WILL BE OVERWRITTEN         #
+# +++++++++++++++++++++++++++++++++++++++ #
+#
+# Periodically exports the balance of the cluster L1 validators into the
+# node-exporter textfile collector, so prometheus can alert on it
+
+apk add --no-cache curl jq > /dev/null
+
+METRICS_FILE=/textfile/l1_validator_balance.prom
+while true; do
+  echo "# HELP avalanche_l1_validator_balance_navax Balance of the L1 validator, in nAVAX" > "$METRICS_FILE.tmp"
+  echo "# TYPE avalanche_l1_validator_balance_navax gauge" >> "$METRICS_FILE.tmp"
+  for subnet_id in {{ join .SubnetIDs " " }}; do
+    curl -s -X POST -H 'content-type:application/json' \
+      --data "{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"platform.getCurrentValidators\",\"params\":{\"subnetID\":\"$subnet_id\"}}" \
+      {{ .Endpoint }}/ext/bc/P |
+      jq -r --arg subnet_id "$subnet_id" '.result.validators[]? | select(.balance != null) |
+        "avalanche_l1_validator_balance_navax{subnet_id=\"\($subnet_id)\",node_id=\"\(.nodeID)\",validation_id=\"\(.validationID)\"} \(.balance)"' \
+      >> "$METRICS_FILE.tmp"
+  done
+  mv "$METRICS_FILE.tmp" "$METRICS_FILE"
+  sleep {{ .IntervalSeconds }}
+done
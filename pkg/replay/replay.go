@@ -0,0 +1,256 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const goldenSuffix = ".golden.json"
+
+var (
+	receiptPollInterval = 200 * time.Millisecond
+	receiptTimeout      = 30 * time.Second
+)
+
+// Transaction is a recorded transaction of a transaction set
+type Transaction struct {
+	Description string `json:"description,omitempty"`
+	// signed transaction, hex encoded as returned by eth_getRawTransactionByHash
+	RawTx string `json:"rawTx"`
+}
+
+// TransactionSet is a recorded list of transactions, to be replayed in order
+type TransactionSet struct {
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Log is an event emitted by a replayed transaction
+type Log struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// Result is the outcome of replaying a transaction: its receipt and the state
+// root of the block including it
+type Result struct {
+	TxHash          common.Hash     `json:"txHash"`
+	Status          uint64          `json:"status"`
+	GasUsed         uint64          `json:"gasUsed"`
+	ContractAddress *common.Address `json:"contractAddress,omitempty"`
+	Logs            []Log           `json:"logs"`
+	StateRoot       common.Hash     `json:"stateRoot"`
+	// set if the transaction was not accepted by the chain
+	Error string `json:"error,omitempty"`
+}
+
+// Golden holds the expected results of replaying a transaction set
+type Golden struct {
+	Results []Result `json:"results"`
+}
+
+// Mismatch is a difference between the golden and the actual results
+type Mismatch struct {
+	// index of the transaction on the set, -1 if the difference is on the whole set
+	Index    int
+	Field    string
+	Expected string
+	Actual   string
+}
+
+// Backend is the part of an EVM client needed to replay transactions
+type Backend interface {
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// GoldenPath returns the default golden outputs path for the transaction set at [transactionsPath]
+func GoldenPath(transactionsPath string) string {
+	return strings.TrimSuffix(transactionsPath, filepath.Ext(transactionsPath)) + goldenSuffix
+}
+
+// LoadTransactionSet loads the transaction set at [path], checking that all its
+// transactions can be decoded
+func LoadTransactionSet(path string) (TransactionSet, error) {
+	var set TransactionSet
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return set, err
+	}
+	if err := json.Unmarshal(content, &set); err != nil {
+		return set, fmt.Errorf("invalid transaction set %s: %w", path, err)
+	}
+	if len(set.Transactions) == 0 {
+		return set, fmt.Errorf("transaction set %s has no transactions", path)
+	}
+	for i, tx := range set.Transactions {
+		if _, err := tx.Decode(); err != nil {
+			return set, fmt.Errorf("transaction %d of %s: %w", i+1, path, err)
+		}
+	}
+	return set, nil
+}
+
+// Decode returns the signed transaction
+func (t Transaction) Decode() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	rawTx, err := hexutil.Decode(t.RawTx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rawTx: %w", err)
+	}
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return nil, fmt.Errorf("invalid rawTx: %w", err)
+	}
+	return tx, nil
+}
+
+// LoadGolden loads the golden outputs at [path]
+func LoadGolden(path string) (Golden, error) {
+	var golden Golden
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return golden, err
+	}
+	if err := json.Unmarshal(content, &golden); err != nil {
+		return golden, fmt.Errorf("invalid golden outputs %s: %w", path, err)
+	}
+	return golden, nil
+}
+
+// WriteGolden writes [golden] to [path]
+func WriteGolden(path string, golden Golden) error {
+	content, err := json.MarshalIndent(golden, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), constants.WriteReadReadPerms)
+}
+
+// Replay issues the transactions of [set] on [backend] one at a time, waiting for each
+// one to be accepted before issuing the next, so every transaction gets its own block.
+// Transactions not accepted by the chain are reported on their result, and the
+// replay continues
+func Replay(backend Backend, set TransactionSet) ([]Result, error) {
+	results := make([]Result, 0, len(set.Transactions))
+	for _, t := range set.Transactions {
+		tx, err := t.Decode()
+		if err != nil {
+			return nil, err
+		}
+		result, err := replayTransaction(backend, tx)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func replayTransaction(backend Backend, tx *types.Transaction) (Result, error) {
+	result := Result{TxHash: tx.Hash(), Logs: []Log{}}
+	ctx, cancel := context.WithTimeout(context.Background(), receiptTimeout)
+	defer cancel()
+	if err := backend.SendTransaction(ctx, tx); err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	var receipt *types.Receipt
+	for {
+		var err error
+		receipt, err = backend.TransactionReceipt(ctx, tx.Hash())
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, interfaces.NotFound) {
+			return result, fmt.Errorf("failure obtaining receipt of %s: %w", tx.Hash(), err)
+		}
+		select {
+		case <-ctx.Done():
+			result.Error = fmt.Sprintf("not accepted after %s", receiptTimeout)
+			return result, nil
+		case <-time.After(receiptPollInterval):
+		}
+	}
+	header, err := backend.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return result, fmt.Errorf("failure obtaining block %s: %w", receipt.BlockNumber, err)
+	}
+	result.Status = receipt.Status
+	result.GasUsed = receipt.GasUsed
+	if receipt.ContractAddress != (common.Address{}) {
+		contractAddress := receipt.ContractAddress
+		result.ContractAddress = &contractAddress
+	}
+	for _, log := range receipt.Logs {
+		result.Logs = append(result.Logs, Log{Address: log.Address, Topics: log.Topics, Data: log.Data})
+	}
+	result.StateRoot = header.Root
+	return result, nil
+}
+
+// Compare returns the differences of [actual] against the [expected] results. State
+// roots are only compared if [checkStateRoots] is set
+func Compare(expected []Result, actual []Result, checkStateRoots bool) []Mismatch {
+	mismatches := []Mismatch{}
+	if len(expected) != len(actual) {
+		mismatches = append(mismatches, Mismatch{
+			Index:    -1,
+			Field:    "transactions",
+			Expected: fmt.Sprint(len(expected)),
+			Actual:   fmt.Sprint(len(actual)),
+		})
+	}
+	for i := 0; i < len(expected) && i < len(actual); i++ {
+		add := func(field string, expected string, actual string) {
+			if expected != actual {
+				mismatches = append(mismatches, Mismatch{Index: i, Field: field, Expected: expected, Actual: actual})
+			}
+		}
+		e, a := expected[i], actual[i]
+		add("txHash", e.TxHash.Hex(), a.TxHash.Hex())
+		add("error", e.Error, a.Error)
+		add("status", fmt.Sprint(e.Status), fmt.Sprint(a.Status))
+		add("gasUsed", fmt.Sprint(e.GasUsed), fmt.Sprint(a.GasUsed))
+		add("contractAddress", formatAddress(e.ContractAddress), formatAddress(a.ContractAddress))
+		add("logs", formatLogs(e.Logs), formatLogs(a.Logs))
+		if checkStateRoots {
+			add("stateRoot", e.StateRoot.Hex(), a.StateRoot.Hex())
+		}
+	}
+	return mismatches
+}
+
+func formatAddress(address *common.Address) string {
+	if address == nil {
+		return ""
+	}
+	return address.Hex()
+}
+
+func formatLogs(logs []Log) string {
+	formatted := make([]string, 0, len(logs))
+	for _, log := range logs {
+		topics := make([]string, 0, len(log.Topics))
+		for _, topic := range log.Topics {
+			topics = append(topics, topic.Hex())
+		}
+		formatted = append(formatted, fmt.Sprintf("%s [%s] %s", log.Address.Hex(), strings.Join(topics, ","), log.Data))
+	}
+	return strings.Join(formatted, "\n")
+}
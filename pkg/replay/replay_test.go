@@ -0,0 +1,170 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package replay
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend accepts transactions on their own block, making receipts available
+// after a few polls
+type fakeBackend struct {
+	receipts map[common.Hash]*types.Receipt
+	polls    map[common.Hash]int
+	rejected map[common.Hash]bool
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		receipts: map[common.Hash]*types.Receipt{},
+		polls:    map[common.Hash]int{},
+		rejected: map[common.Hash]bool{},
+	}
+}
+
+func (b *fakeBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	if b.rejected[tx.Hash()] {
+		return errors.New("nonce too low")
+	}
+	receipt := &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		GasUsed:     tx.Gas(),
+		BlockNumber: big.NewInt(int64(len(b.receipts) + 1)),
+		Logs:        []*types.Log{},
+	}
+	if tx.To() == nil {
+		receipt.ContractAddress = common.HexToAddress("0x00000000000000000000000000000000000C0DE0")
+		receipt.Logs = append(receipt.Logs, &types.Log{Address: receipt.ContractAddress, Topics: []common.Hash{{1}}, Data: []byte{2}})
+	}
+	b.receipts[tx.Hash()] = receipt
+	return nil
+}
+
+func (b *fakeBackend) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	b.polls[txHash]++
+	if b.polls[txHash] < 2 {
+		return nil, interfaces.NotFound
+	}
+	return b.receipts[txHash], nil
+}
+
+func (*fakeBackend) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: number, Root: common.BigToHash(number)}, nil
+}
+
+func signedRawTx(t *testing.T, nonce uint64, to *common.Address) string {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       to,
+		Gas:      21_000 + nonce,
+		GasPrice: big.NewInt(25_000_000_000),
+	})
+	require.NoError(t, err)
+	rawTx, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	return hexutil.Encode(rawTx)
+}
+
+func TestLoadTransactionSet(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "txs.json")
+	require.Equal(filepath.Join(dir, "txs.golden.json"), GoldenPath(path))
+
+	require.NoError(os.WriteFile(path, []byte(`{"transactions":[]}`), 0o600))
+	_, err := LoadTransactionSet(path)
+	require.ErrorContains(err, "has no transactions")
+
+	require.NoError(os.WriteFile(path, []byte(`{"transactions":[{"rawTx":"0x1234"}]}`), 0o600))
+	_, err = LoadTransactionSet(path)
+	require.ErrorContains(err, "transaction 1 of")
+
+	to := common.HexToAddress("0x00000000000000000000000000000000000A11CE")
+	require.NoError(os.WriteFile(path, []byte(`{"transactions":[{"description":"transfer","rawTx":"`+signedRawTx(t, 0, &to)+`"}]}`), 0o600))
+	set, err := LoadTransactionSet(path)
+	require.NoError(err)
+	require.Len(set.Transactions, 1)
+	require.Equal("transfer", set.Transactions[0].Description)
+}
+
+func TestReplay(t *testing.T) {
+	require := require.New(t)
+	receiptPollInterval = time.Millisecond
+	to := common.HexToAddress("0x00000000000000000000000000000000000A11CE")
+	set := TransactionSet{Transactions: []Transaction{
+		{Description: "deploy", RawTx: signedRawTx(t, 0, nil)},
+		{Description: "transfer", RawTx: signedRawTx(t, 1, &to)},
+		{Description: "rejected", RawTx: signedRawTx(t, 2, &to)},
+	}}
+	backend := newFakeBackend()
+	rejectedTx, err := set.Transactions[2].Decode()
+	require.NoError(err)
+	backend.rejected[rejectedTx.Hash()] = true
+
+	results, err := Replay(backend, set)
+	require.NoError(err)
+	require.Len(results, 3)
+	require.Equal(uint64(21_000), results[0].GasUsed)
+	require.NotNil(results[0].ContractAddress)
+	require.Len(results[0].Logs, 1)
+	require.Equal(common.BigToHash(big.NewInt(1)), results[0].StateRoot)
+	require.Nil(results[1].ContractAddress)
+	require.Equal(common.BigToHash(big.NewInt(2)), results[1].StateRoot)
+	require.Equal("nonce too low", results[2].Error)
+
+	// golden outputs round trip, and match the replay
+	goldenPath := filepath.Join(t.TempDir(), "txs.golden.json")
+	require.NoError(WriteGolden(goldenPath, Golden{Results: results}))
+	golden, err := LoadGolden(goldenPath)
+	require.NoError(err)
+	require.Empty(Compare(golden.Results, results, true))
+}
+
+func TestCompare(t *testing.T) {
+	require := require.New(t)
+	contract := common.HexToAddress("0x00000000000000000000000000000000000C0DE0")
+	expected := []Result{
+		{TxHash: common.Hash{1}, Status: 1, GasUsed: 100, ContractAddress: &contract, StateRoot: common.Hash{2}},
+		{TxHash: common.Hash{3}, Status: 1, GasUsed: 200, Logs: []Log{{Address: contract}}, StateRoot: common.Hash{4}},
+	}
+	actual := []Result{
+		{TxHash: common.Hash{1}, Status: 1, GasUsed: 100, ContractAddress: &contract, StateRoot: common.Hash{5}},
+		{TxHash: common.Hash{3}, Status: 0, GasUsed: 250, StateRoot: common.Hash{4}},
+	}
+	mismatches := Compare(expected, actual, true)
+	require.Equal([]string{"stateRoot", "status", "gasUsed", "logs"}, fields(mismatches))
+	require.Equal(0, mismatches[0].Index)
+	require.Equal("200", mismatches[2].Expected)
+	require.Equal("250", mismatches[2].Actual)
+
+	// state roots can be left out, as they depend on block timestamps when contracts use them
+	require.Equal([]string{"status", "gasUsed", "logs"}, fields(Compare(expected, actual, false)))
+
+	mismatches = Compare(expected, actual[:1], false)
+	require.Len(mismatches, 1)
+	require.Equal(-1, mismatches[0].Index)
+	require.Equal("2", mismatches[0].Expected)
+}
+
+func fields(mismatches []Mismatch) []string {
+	fields := []string{}
+	for _, mismatch := range mismatches {
+		fields = append(fields, mismatch.Field)
+	}
+	return fields
+}
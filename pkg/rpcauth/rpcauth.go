@@ -0,0 +1,136 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package rpcauth handles the auth tokens that lock down the blockchain RPC
+// served by the HTTPS endpoints of cluster nodes
+package rpcauth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	tokenSize         = 32
+	blockchainsPrefix = "/ext/bc/"
+)
+
+// NewToken returns a random hex encoded auth token
+func NewToken() (string, error) {
+	bytes := make([]byte, tokenSize)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// AuthorizationHeader returns the value of the Authorization header that
+// carries [token]
+func AuthorizationHeader(token string) string {
+	return "Bearer " + token
+}
+
+// Registry holds the auth tokens of the blockchain RPC served at each endpoint
+type Registry struct {
+	lock   sync.RWMutex
+	tokens map[string]map[string]string // maps endpoint host to blockchain ID to token
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		tokens: map[string]map[string]string{},
+	}
+}
+
+// Register makes the requests to the RPC of [blockchainID] served at
+// [endpoint] carry [token]
+func (r *Registry) Register(endpoint string, blockchainID string, token string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid endpoint %q", endpoint)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.tokens[u.Host]; !ok {
+		r.tokens[u.Host] = map[string]string{}
+	}
+	r.tokens[u.Host][blockchainID] = token
+	return nil
+}
+
+// Token returns the token registered for the blockchain RPC at [rawURL], either
+// HTTP or WebSocket, if any
+func (r *Registry) Token(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+	return r.lookup(u)
+}
+
+// Headers returns the headers that authenticate the requests to [rawURL], to be
+// given to the RPC client dialing it. It is empty if no token is registered
+func (r *Registry) Headers(rawURL string) http.Header {
+	headers := http.Header{}
+	if token, ok := r.Token(rawURL); ok {
+		headers.Set("Authorization", AuthorizationHeader(token))
+	}
+	return headers
+}
+
+func (r *Registry) lookup(u *url.URL) (string, bool) {
+	blockchainID := blockchainFromPath(u.Path)
+	if blockchainID == "" {
+		return "", false
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	token, ok := r.tokens[u.Host][blockchainID]
+	return token, ok
+}
+
+// Transport is an http.RoundTripper that adds the tokens of [registry] to the
+// requests sent to the RPC of the matching blockchains and endpoints
+type Transport struct {
+	base     http.RoundTripper
+	registry *Registry
+}
+
+func NewTransport(base http.RoundTripper, registry *Registry) *Transport {
+	return &Transport{
+		base:     base,
+		registry: registry,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if token, ok := t.registry.lookup(req.URL); ok && req.Header.Get("Authorization") == "" {
+		// round trippers must not modify the given request
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", AuthorizationHeader(token))
+	}
+	return t.base.RoundTrip(req)
+}
+
+// blockchainFromPath returns the blockchain ID (or alias) of an avalanchego
+// blockchain API path, eg /ext/bc/<blockchainID>/rpc
+func blockchainFromPath(path string) string {
+	if !strings.HasPrefix(path, blockchainsPrefix) {
+		return ""
+	}
+	blockchainID, _, _ := strings.Cut(strings.TrimPrefix(path, blockchainsPrefix), "/")
+	return blockchainID
+}
+
+// Tokens is the registry of the tokens of the cluster HTTPS endpoints, loaded
+// when the CLI starts. The RPC clients dialed by the CLI take their auth headers
+// from it
+var Tokens = NewRegistry()
@@ -0,0 +1,76 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	require := require.New(t)
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	registry := NewRegistry()
+	require.NoError(registry.Register(server.URL, "chainA", "tokenA"))
+	require.Error(registry.Register("chainB", "chainB", "tokenB"))
+	client := http.Client{Transport: NewTransport(http.DefaultTransport, registry)}
+
+	for _, path := range []string{
+		"/ext/bc/chainA/rpc",
+		"/ext/bc/chainA",
+		"/ext/bc/chainB/rpc",
+		"/ext/health",
+	} {
+		resp, err := client.Get(server.URL + path)
+		require.NoError(err)
+		require.NoError(resp.Body.Close())
+	}
+	require.Equal([]string{"Bearer tokenA", "Bearer tokenA", "", ""}, received)
+
+	// explicit authorization is kept
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/ext/bc/chainA/rpc", nil)
+	require.NoError(err)
+	req.Header.Set("Authorization", "Bearer other")
+	resp, err := client.Do(req)
+	require.NoError(err)
+	require.NoError(resp.Body.Close())
+	require.Equal("Bearer other", received[len(received)-1])
+}
+
+func TestRegistryHeaders(t *testing.T) {
+	require := require.New(t)
+	registry := NewRegistry()
+	require.NoError(registry.Register("https://rpc.example.com", "chainA", "tokenA"))
+	for _, rawURL := range []string{
+		"https://rpc.example.com/ext/bc/chainA/rpc",
+		"wss://rpc.example.com/ext/bc/chainA/ws",
+	} {
+		require.Equal("Bearer tokenA", registry.Headers(rawURL).Get("Authorization"), rawURL)
+	}
+	for _, rawURL := range []string{
+		"https://rpc.example.com/ext/bc/chainB/rpc",
+		"https://other.example.com/ext/bc/chainA/rpc",
+		"https://rpc.example.com/ext/info",
+	} {
+		require.Empty(registry.Headers(rawURL), rawURL)
+	}
+}
+
+func TestNewToken(t *testing.T) {
+	require := require.New(t)
+	token1, err := NewToken()
+	require.NoError(err)
+	token2, err := NewToken()
+	require.NoError(err)
+	require.Len(token1, 2*tokenSize)
+	require.NotEqual(token1, token2)
+}
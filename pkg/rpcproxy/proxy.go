@@ -0,0 +1,300 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package rpcproxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+const (
+	// MaxRequestSize bounds the size of a proxied request body
+	MaxRequestSize = 8 * 1024 * 1024
+	// MaxResponseSize bounds the size of a proxied response body
+	MaxResponseSize = 32 * 1024 * 1024
+	// maxCaptureLineSize bounds the size of a captured exchange that can be read back
+	maxCaptureLineSize = 64 * 1024 * 1024
+)
+
+// writeMethods are the JSON-RPC methods that change the chain state, or sign on behalf
+// of the node accounts. Replaying them could broadcast transactions again
+var writeMethods = []string{
+	"eth_sendRawTransaction",
+	"eth_sendTransaction",
+	"eth_sign",
+	"eth_signTransaction",
+	"eth_signTypedData",
+	"eth_signTypedData_v4",
+	"personal_sendTransaction",
+	"personal_sign",
+}
+
+// Exchange is a JSON-RPC request forwarded by the proxy, together with the response
+// it got. Captures are stored as one exchange per line
+type Exchange struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Methods   []string        `json:"methods"`
+	Request   json.RawMessage `json:"request"`
+	Response  json.RawMessage `json:"response,omitempty"`
+	Status    int             `json:"status"`
+	LatencyMs int64           `json:"latencyMs"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// IsWrite returns true if the exchange calls a method that sends or signs transactions
+func (e Exchange) IsWrite() bool {
+	for _, method := range e.Methods {
+		if utils.Belongs(writeMethods, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Failed returns true if the exchange got an http error or a JSON-RPC error
+func (e Exchange) Failed() bool {
+	return e.Error != "" || e.Status != http.StatusOK || hasRPCError(e.Response)
+}
+
+// Filter selects the exchanges to log and to replay
+type Filter struct {
+	// Methods to include. All are included if empty
+	Methods []string
+	// Methods to exclude
+	ExcludeMethods []string
+	// only include failed exchanges
+	ErrorsOnly bool
+}
+
+// Match returns true if [exchange] passes the filter. A batch passes if any of its
+// methods does
+func (f Filter) Match(exchange Exchange) bool {
+	if f.ErrorsOnly && !exchange.Failed() {
+		return false
+	}
+	for _, method := range exchange.Methods {
+		if utils.Belongs(f.ExcludeMethods, method) {
+			continue
+		}
+		if len(f.Methods) == 0 || utils.Belongs(f.Methods, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Proxy is an http handler that forwards JSON-RPC requests to [Target], and hands
+// the exchanges that pass [Filter] to [OnExchange]
+type Proxy struct {
+	Target     string
+	Filter     Filter
+	Client     *http.Client
+	OnExchange func(Exchange)
+	lock       sync.Mutex
+}
+
+func NewProxy(target string, filter Filter, onExchange func(Exchange)) *Proxy {
+	return &Proxy{
+		Target:     target,
+		Filter:     filter,
+		Client:     &http.Client{Timeout: time.Minute},
+		OnExchange: onExchange,
+	}
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only JSON-RPC POST requests are proxied", http.StatusMethodNotAllowed)
+		return
+	}
+	request, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	exchange := Forward(p.Client, p.Target, request)
+	if exchange.Error != "" {
+		http.Error(w, exchange.Error, http.StatusBadGateway)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(exchange.Status)
+		_, _ = w.Write(exchange.Response)
+	}
+	if p.OnExchange != nil && p.Filter.Match(exchange) {
+		// keep captures and output lines whole under concurrent requests
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		p.OnExchange(exchange)
+	}
+}
+
+// Forward posts the JSON-RPC [request] to [target], and returns the resulting exchange.
+// Transport failures are recorded on the exchange Error
+func Forward(client *http.Client, target string, request []byte) Exchange {
+	exchange := Exchange{
+		Timestamp: time.Now().UTC(),
+		Methods:   GetMethods(request),
+		Request:   json.RawMessage(request),
+	}
+	start := time.Now()
+	resp, err := client.Post(target, "application/json", bytes.NewReader(request))
+	exchange.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		exchange.Error = err.Error()
+		return exchange
+	}
+	defer resp.Body.Close()
+	response, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseSize+1))
+	if err != nil {
+		exchange.Error = err.Error()
+		return exchange
+	}
+	if len(response) > MaxResponseSize {
+		exchange.Error = fmt.Sprintf("response exceeds %d bytes", MaxResponseSize)
+		return exchange
+	}
+	exchange.Status = resp.StatusCode
+	exchange.Response = json.RawMessage(response)
+	if !json.Valid(response) {
+		// keep the capture line valid JSON
+		quoted, _ := json.Marshal(string(response))
+		exchange.Response = json.RawMessage(quoted)
+	}
+	return exchange
+}
+
+// GetMethods returns the methods called by the JSON-RPC [request], that can be a
+// single call or a batch
+func GetMethods(request []byte) []string {
+	type call struct {
+		Method string `json:"method"`
+	}
+	trimmed := bytes.TrimSpace(request)
+	calls := []call{}
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &calls); err != nil {
+			return []string{"invalid"}
+		}
+	} else {
+		c := call{}
+		if err := json.Unmarshal(trimmed, &c); err != nil {
+			return []string{"invalid"}
+		}
+		calls = append(calls, c)
+	}
+	return utils.Map(calls, func(c call) string { return c.Method })
+}
+
+func hasRPCError(response []byte) bool {
+	type result struct {
+		Error json.RawMessage `json:"error"`
+	}
+	results := []result{}
+	trimmed := bytes.TrimSpace(response)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &results); err != nil {
+			return true
+		}
+	} else {
+		r := result{}
+		if err := json.Unmarshal(trimmed, &r); err != nil {
+			return true
+		}
+		results = append(results, r)
+	}
+	for _, r := range results {
+		if len(r.Error) > 0 && string(r.Error) != "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteExchange appends [exchange] as a capture line to [w]
+func WriteExchange(w io.Writer, exchange Exchange) error {
+	exchangeBytes, err := json.Marshal(exchange)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(exchangeBytes, '\n'))
+	return err
+}
+
+// ReadCapture reads the exchanges captured at [path]
+func ReadCapture(path string) ([]Exchange, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	exchanges := []Exchange{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxCaptureLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		exchange := Exchange{}
+		if err := json.Unmarshal(scanner.Bytes(), &exchange); err != nil {
+			return nil, fmt.Errorf("invalid capture line %d on %s: %w", line, path, err)
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	return exchanges, scanner.Err()
+}
+
+// ReplayResult is a captured exchange replayed against a target
+type ReplayResult struct {
+	Captured Exchange
+	Replayed Exchange
+}
+
+// SameResponse returns true if the replayed response is equivalent JSON to the captured one
+func (r ReplayResult) SameResponse() bool {
+	return r.Captured.Status == r.Replayed.Status && equalJSON(r.Captured.Response, r.Replayed.Response)
+}
+
+// Replay sends the requests of the [exchanges] that pass [filter] to [target], in order.
+// Requests that send or sign transactions are skipped, and counted on the second
+// return value, unless [includeWrites] is set
+func Replay(client *http.Client, target string, exchanges []Exchange, filter Filter, includeWrites bool) ([]ReplayResult, int) {
+	results := []ReplayResult{}
+	skipped := 0
+	for _, exchange := range exchanges {
+		if !filter.Match(exchange) {
+			continue
+		}
+		if exchange.IsWrite() && !includeWrites {
+			skipped++
+			continue
+		}
+		results = append(results, ReplayResult{
+			Captured: exchange,
+			Replayed: Forward(client, target, exchange.Request),
+		})
+	}
+	return results, skipped
+}
+
+func equalJSON(a, b []byte) bool {
+	var aValue, bValue interface{}
+	if err := json.Unmarshal(a, &aValue); err != nil {
+		return bytes.Equal(a, b)
+	}
+	if err := json.Unmarshal(b, &bValue); err != nil {
+		return false
+	}
+	aBytes, _ := json.Marshal(aValue)
+	bBytes, _ := json.Marshal(bValue)
+	return bytes.Equal(aBytes, bBytes)
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package rpcproxy
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTarget(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "eth_fail") {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"not found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetMethods(t *testing.T) {
+	require := require.New(t)
+	require.Equal([]string{"eth_chainId"}, GetMethods([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`)))
+	require.Equal(
+		[]string{"eth_chainId", "eth_blockNumber"},
+		GetMethods([]byte(` [{"method":"eth_chainId"},{"method":"eth_blockNumber"}]`)),
+	)
+	require.Equal([]string{"invalid"}, GetMethods([]byte(`not json`)))
+}
+
+func TestFilterMatch(t *testing.T) {
+	require := require.New(t)
+	ok := Exchange{Methods: []string{"eth_call"}, Status: http.StatusOK, Response: []byte(`{"result":"0x"}`)}
+	failed := Exchange{Methods: []string{"eth_call"}, Status: http.StatusOK, Response: []byte(`{"error":{"code":3}}`)}
+	require.True(Filter{}.Match(ok))
+	require.True(Filter{Methods: []string{"eth_call"}}.Match(ok))
+	require.False(Filter{Methods: []string{"eth_chainId"}}.Match(ok))
+	require.False(Filter{ExcludeMethods: []string{"eth_call"}}.Match(ok))
+	require.False(Filter{ErrorsOnly: true}.Match(ok))
+	require.True(Filter{ErrorsOnly: true}.Match(failed))
+}
+
+func TestProxyCaptureAndReplay(t *testing.T) {
+	require := require.New(t)
+	target := newTestTarget(t)
+	capture := &bytes.Buffer{}
+	proxy := NewProxy(target.URL, Filter{ExcludeMethods: []string{"eth_chainId"}}, func(exchange Exchange) {
+		require.NoError(WriteExchange(capture, exchange))
+	})
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	for _, request := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`,
+		`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`,
+		`{"jsonrpc":"2.0","id":1,"method":"eth_fail"}`,
+	} {
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader(request))
+		require.NoError(err)
+		require.NoError(resp.Body.Close())
+		require.Equal(http.StatusOK, resp.StatusCode)
+	}
+
+	capturePath := filepath.Join(t.TempDir(), "capture.jsonl")
+	require.NoError(os.WriteFile(capturePath, capture.Bytes(), 0o600))
+	exchanges, err := ReadCapture(capturePath)
+	require.NoError(err)
+	require.Len(exchanges, 2)
+	require.Equal([]string{"eth_blockNumber"}, exchanges[0].Methods)
+	require.False(exchanges[0].Failed())
+	require.True(exchanges[1].Failed())
+
+	results, skipped := Replay(http.DefaultClient, target.URL, exchanges, Filter{ErrorsOnly: true}, false)
+	require.Zero(skipped)
+	require.Len(results, 1)
+	require.True(results[0].SameResponse())
+}
+
+func TestReplaySkipsWrites(t *testing.T) {
+	require := require.New(t)
+	requests := 0
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	defer target.Close()
+	exchanges := []Exchange{
+		{Methods: []string{"eth_blockNumber"}, Request: []byte(`{"method":"eth_blockNumber"}`)},
+		{Methods: []string{"eth_sendRawTransaction"}, Request: []byte(`{"method":"eth_sendRawTransaction"}`)},
+		{Methods: []string{"eth_chainId", "eth_sendTransaction"}, Request: []byte(`[]`)},
+	}
+	results, skipped := Replay(http.DefaultClient, target.URL, exchanges, Filter{}, false)
+	require.Len(results, 1)
+	require.Equal(2, skipped)
+	require.Equal(1, requests)
+
+	results, skipped = Replay(http.DefaultClient, target.URL, exchanges, Filter{}, true)
+	require.Len(results, 3)
+	require.Zero(skipped)
+}
+
+func TestProxyBodyLimits(t *testing.T) {
+	require := require.New(t)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte(" "), MaxResponseSize+1))
+	}))
+	defer target.Close()
+	exchange := Forward(http.DefaultClient, target.URL, []byte(`{"method":"eth_chainId"}`))
+	require.Contains(exchange.Error, "response exceeds")
+
+	server := httptest.NewServer(NewProxy(target.URL, Filter{}, nil))
+	defer server.Close()
+	resp, err := http.Post(server.URL, "application/json", bytes.NewReader(bytes.Repeat([]byte(" "), MaxRequestSize+1)))
+	require.NoError(err)
+	require.NoError(resp.Body.Close())
+	require.Equal(http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
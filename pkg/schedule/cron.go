@@ -0,0 +1,50 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// schedules are activated at most once a minute, so @every intervals are not supported
+const cronEveryPrefix = "@every"
+
+// CronSchedule is a parsed standard 5 fields cron expression
+// (minute hour day-of-month month day-of-week), evaluated on local time
+type CronSchedule struct {
+	schedule cron.Schedule
+}
+
+// ParseCron parses [expr], either a 5 fields cron expression, where each field is a
+// comma separated list of values, ranges (a-b) and steps (*/n, a-b/n), or one of the
+// @yearly, @monthly, @weekly, @daily and @hourly macros
+func ParseCron(expr string) (CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		expr = strings.ToLower(expr)
+	}
+	if strings.HasPrefix(expr, cronEveryPrefix) {
+		return CronSchedule{}, fmt.Errorf("invalid cron expression %q: %s is not supported", expr, cronEveryPrefix)
+	}
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return CronSchedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	return CronSchedule{schedule: schedule}, nil
+}
+
+// Matches returns true if the minute of [t] is an activation of the schedule
+func (c CronSchedule) Matches(t time.Time) bool {
+	t = t.Truncate(time.Minute)
+	return c.schedule.Next(t.Add(-time.Second)).Equal(t)
+}
+
+// Next returns the first activation of the schedule after [t], or the zero time if
+// there is none in the next years (eg: for february 30th)
+func (c CronSchedule) Next(t time.Time) time.Time {
+	return c.schedule.Next(t)
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// Runner executes the schedules saved at a dir on their activations, by running the
+// CLI binary at ExecPath with their commands, and records the results on the dir history
+type Runner struct {
+	Dir      string
+	ExecPath string
+	// extra arguments given to all the commands
+	ExtraArgs []string
+	// reports the runner activity
+	Printf func(format string, args ...interface{})
+
+	mu      sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+// Run executes the due schedules until [ctx] is done, and then waits for the commands
+// being executed to finish. Schedules are reloaded every minute, so added or removed
+// ones are considered without restarting the runner. A schedule is not executed
+// again while its previous execution is still running
+func (r *Runner) Run(ctx context.Context) error {
+	r.running = map[string]bool{}
+	defer r.wg.Wait()
+	for {
+		now := time.Now()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(next.Sub(now)):
+		}
+		schedules, err := LoadSchedules(r.Dir)
+		if err != nil {
+			r.Printf("failure loading schedules: %s", err)
+			continue
+		}
+		for _, s := range schedules {
+			cron, err := ParseCron(s.Cron)
+			if err != nil {
+				r.Printf("schedule %s: %s", s.Name, err)
+				continue
+			}
+			if !cron.Matches(next) {
+				continue
+			}
+			r.mu.Lock()
+			if r.running[s.Name] {
+				r.mu.Unlock()
+				r.Printf("schedule %s: previous run still in progress, skipping", s.Name)
+				continue
+			}
+			r.running[s.Name] = true
+			r.mu.Unlock()
+			r.wg.Add(1)
+			go func(s Schedule) {
+				defer r.wg.Done()
+				r.execute(s)
+				r.mu.Lock()
+				delete(r.running, s.Name)
+				r.mu.Unlock()
+			}(s)
+		}
+	}
+}
+
+func (r *Runner) execute(s Schedule) {
+	r.Printf("schedule %s: running %s", s.Name, s.Command)
+	record := RunSchedule(r.Dir, r.ExecPath, r.ExtraArgs, s)
+	if record.Succeeded() {
+		r.Printf("schedule %s: succeeded in %s", s.Name, record.Duration)
+	} else {
+		r.Printf("schedule %s: failed in %s: %s. output at %s", s.Name, record.Duration, record.Error, record.LogPath)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := appendRunRecord(r.Dir, record); err != nil {
+		r.Printf("schedule %s: failure recording run: %s", s.Name, err)
+	}
+}
+
+// RunSchedule executes the command of [s] with the CLI binary at [execPath], writing
+// its output to a log file under [dir]. The command is executed without a terminal,
+// so it fails instead of prompting for missing inputs
+func RunSchedule(dir string, execPath string, extraArgs []string, s Schedule) (record RunRecord) {
+	startedAt := time.Now()
+	record = RunRecord{
+		Schedule:  s.Name,
+		Command:   s.Command,
+		StartedAt: startedAt,
+		ExitCode:  -1,
+	}
+	defer func() {
+		record.Duration = time.Since(startedAt).Truncate(time.Millisecond)
+	}()
+	args, err := s.Args()
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+	logsDir := filepath.Join(dir, constants.ScheduleRunLogsDir)
+	if err := os.MkdirAll(logsDir, constants.DefaultPerms755); err != nil {
+		record.Error = err.Error()
+		return record
+	}
+	record.LogPath = filepath.Join(logsDir, fmt.Sprintf("%s_%s.log", s.Name, startedAt.Format(constants.ScheduleRunLogTimeFormat)))
+	logFile, err := os.Create(record.LogPath)
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+	defer logFile.Close()
+	cmd := exec.Command(execPath, append(args, extraArgs...)...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	err = cmd.Run()
+	if cmd.ProcessState != nil {
+		record.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	return record
+}
+
+type schedulerRunFile struct {
+	Pid int `json:"pid"`
+}
+
+// SaveSchedulerPID records at [dir] the pid of the scheduler process
+func SaveSchedulerPID(dir string, pid int) error {
+	bs, err := json.Marshal(&schedulerRunFile{Pid: pid})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, constants.SchedulerRunFileName), bs, constants.WriteReadReadPerms)
+}
+
+// RemoveSchedulerPID removes the scheduler process record at [dir]
+func RemoveSchedulerPID(dir string) error {
+	err := os.Remove(filepath.Join(dir, constants.SchedulerRunFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// GetSchedulerProcess returns the scheduler process recorded at [dir], or nil if
+// there is none running
+func GetSchedulerProcess(dir string) (*os.Process, error) {
+	bs, err := os.ReadFile(filepath.Join(dir, constants.SchedulerRunFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rf := schedulerRunFile{}
+	if err := json.Unmarshal(bs, &rf); err != nil {
+		return nil, err
+	}
+	proc, err := os.FindProcess(rf.Pid)
+	if err != nil {
+		return nil, nil
+	}
+	// FindProcess always succeeds on unix. signal 0 fails if the process does not exist
+	if err := proc.Signal(syscall.Signal(0)); err != nil {
+		// stale record left by a scheduler not stopped gracefully (eg: on reboot)
+		return nil, RemoveSchedulerPID(dir)
+	}
+	return proc, nil
+}
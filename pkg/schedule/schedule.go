@@ -0,0 +1,242 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedule
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+// Schedule is a CLI command executed on the activations of a cron expression
+type Schedule struct {
+	Name string
+	Cron string
+	// CLI command line, without the binary name
+	Command   string
+	CreatedAt time.Time
+}
+
+// Args returns the CLI arguments of the schedule command
+func (s Schedule) Args() ([]string, error) {
+	return SplitCommandLine(s.Command)
+}
+
+// RunRecord is the result of an execution of a schedule
+type RunRecord struct {
+	Schedule  string
+	Command   string
+	StartedAt time.Time
+	Duration  time.Duration
+	ExitCode  int
+	// set if the command could not be executed, or exited with error
+	Error string `json:",omitempty"`
+	// path of the file holding the command output
+	LogPath string
+}
+
+// Succeeded returns true if the command was executed and exited without error
+func (r RunRecord) Succeeded() bool {
+	return r.Error == ""
+}
+
+// LoadSchedules returns the schedules saved at [dir], sorted by name
+func LoadSchedules(dir string) ([]Schedule, error) {
+	schedules := []Schedule{}
+	bs, err := os.ReadFile(filepath.Join(dir, constants.SchedulesFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return schedules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bs, &schedules); err != nil {
+		return nil, fmt.Errorf("invalid schedules file at %s: %w", dir, err)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Name < schedules[j].Name })
+	return schedules, nil
+}
+
+// saveSchedules writes [schedules] at [dir], replacing the file so a running scheduler
+// never reads a partial write
+func saveSchedules(dir string, schedules []Schedule) error {
+	if err := os.MkdirAll(dir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	bs, err := json.MarshalIndent(schedules, "", "  ")
+	if err != nil {
+		return err
+	}
+	schedulesPath := filepath.Join(dir, constants.SchedulesFileName)
+	tmpPath := schedulesPath + ".tmp"
+	if err := os.WriteFile(tmpPath, bs, constants.WriteReadUserOnlyPerms); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, schedulesPath)
+}
+
+// AddSchedule validates [s] and saves it at [dir]. Names are unique
+func AddSchedule(dir string, s Schedule) error {
+	if s.Name == "" {
+		return fmt.Errorf("schedule name is required")
+	}
+	if strings.ContainsAny(s.Name, " /\\") {
+		return fmt.Errorf("invalid schedule name %q: spaces and slashes are not allowed", s.Name)
+	}
+	if _, err := ParseCron(s.Cron); err != nil {
+		return err
+	}
+	if args, err := s.Args(); err != nil {
+		return err
+	} else if len(args) == 0 {
+		return fmt.Errorf("schedule command is required")
+	}
+	schedules, err := LoadSchedules(dir)
+	if err != nil {
+		return err
+	}
+	for _, existing := range schedules {
+		if existing.Name == s.Name {
+			return fmt.Errorf("schedule %s already exists", s.Name)
+		}
+	}
+	return saveSchedules(dir, append(schedules, s))
+}
+
+// RemoveSchedule removes the schedule [name] saved at [dir]. Its run history is kept
+func RemoveSchedule(dir string, name string) error {
+	schedules, err := LoadSchedules(dir)
+	if err != nil {
+		return err
+	}
+	remaining := utils.Filter(schedules, func(s Schedule) bool { return s.Name != name })
+	if len(remaining) == len(schedules) {
+		return fmt.Errorf("schedule %s not found", name)
+	}
+	return saveSchedules(dir, remaining)
+}
+
+// NextScheduleName returns the first name of the form job-N not used by the schedules at [dir]
+func NextScheduleName(dir string) (string, error) {
+	schedules, err := LoadSchedules(dir)
+	if err != nil {
+		return "", err
+	}
+	names := utils.Map(schedules, func(s Schedule) string { return s.Name })
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("job-%d", i)
+		if !utils.Belongs(names, name) {
+			return name, nil
+		}
+	}
+}
+
+// LoadRunHistory returns the run records saved at [dir], the latest first. If [name]
+// is given, only the ones of that schedule are returned
+func LoadRunHistory(dir string, name string) ([]RunRecord, error) {
+	records := []RunRecord{}
+	f, err := os.Open(filepath.Join(dir, constants.ScheduleHistoryFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		record := RunRecord{}
+		// a line partially written by an interrupted scheduler is skipped
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			continue
+		}
+		if name == "" || record.Schedule == name {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].StartedAt.After(records[j].StartedAt) })
+	return records, nil
+}
+
+// appendRunRecord adds [record] to the run history at [dir], removing the oldest
+// records, and their output, so only the latest constants.MaxNumOfScheduleRuns are kept
+func appendRunRecord(dir string, record RunRecord) error {
+	records, err := LoadRunHistory(dir, "")
+	if err != nil {
+		return err
+	}
+	records = append([]RunRecord{record}, records...)
+	if len(records) > constants.MaxNumOfScheduleRuns {
+		for _, old := range records[constants.MaxNumOfScheduleRuns:] {
+			_ = os.Remove(old.LogPath)
+		}
+		records = records[:constants.MaxNumOfScheduleRuns]
+	}
+	lines := make([]string, 0, len(records))
+	// the file is kept in execution order
+	for i := len(records) - 1; i >= 0; i-- {
+		bs, err := json.Marshal(records[i])
+		if err != nil {
+			return err
+		}
+		lines = append(lines, string(bs))
+	}
+	historyPath := filepath.Join(dir, constants.ScheduleHistoryFileName)
+	tmpPath := historyPath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(strings.Join(lines, "\n")+"\n"), constants.WriteReadUserOnlyPerms); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, historyPath)
+}
+
+// SplitCommandLine splits [cmdLine] into arguments separated by spaces. Single and
+// double quotes group arguments with spaces, and are removed
+func SplitCommandLine(cmdLine string) ([]string, error) {
+	args := []string{}
+	var current strings.Builder
+	inArg := false
+	quote := rune(0)
+	for _, r := range cmdLine {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command %q", cmdLine)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package schedule
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, expr := range []string{
+		"* * * * *",
+		"0 3 * * *",
+		"*/15 0-6,22 1,15 jan-jun mon-fri",
+		"5/10 * * * 6",
+		"@daily",
+		"@HOURLY",
+	} {
+		_, err := ParseCron(expr)
+		require.NoError(t, err, expr)
+	}
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"@every 1h",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"@often",
+	} {
+		_, err := ParseCron(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	base := time.Date(2024, time.March, 15, 10, 30, 20, 0, time.UTC) // friday
+	testCases := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 15, 10, 31, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, time.March, 16, 3, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2024, time.March, 15, 10, 40, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * sun", time.Date(2024, time.March, 17, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// both day fields restricted: any of them
+		{"0 0 20 * mon", time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 feb *", time.Time{}},
+	}
+	for _, tc := range testCases {
+		cron, err := ParseCron(tc.expr)
+		require.NoError(t, err, tc.expr)
+		next := cron.Next(base)
+		require.Equal(t, tc.expected, next, tc.expr)
+		if !next.IsZero() {
+			require.True(t, cron.Matches(next), tc.expr)
+		}
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	args, err := SplitCommandLine(`node upgrade  --cluster prod --security-only`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"node", "upgrade", "--cluster", "prod", "--security-only"}, args)
+	args, err = SplitCommandLine(`key transfer --memo "monthly top up" --key 'ops key' --x ""`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"key", "transfer", "--memo", "monthly top up", "--key", "ops key", "--x", ""}, args)
+	_, err = SplitCommandLine(`node ssh "prod`)
+	require.Error(t, err)
+}
+
+func TestSchedules(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	name, err := NextScheduleName(dir)
+	require.NoError(err)
+	require.Equal("job-1", name)
+	require.NoError(AddSchedule(dir, Schedule{Name: name, Cron: "0 3 * * *", Command: "node upgrade --cluster prod"}))
+	require.ErrorContains(AddSchedule(dir, Schedule{Name: name, Cron: "0 3 * * *", Command: "node status"}), "already exists")
+	require.Error(AddSchedule(dir, Schedule{Name: "bad-cron", Cron: "0 3 * *", Command: "node status"}))
+	require.Error(AddSchedule(dir, Schedule{Name: "no-command", Cron: "0 3 * * *", Command: " "}))
+	require.Error(AddSchedule(dir, Schedule{Name: "bad name", Cron: "0 3 * * *", Command: "node status"}))
+	require.NoError(AddSchedule(dir, Schedule{Name: "health", Cron: "@hourly", Command: "node status"}))
+	name, err = NextScheduleName(dir)
+	require.NoError(err)
+	require.Equal("job-2", name)
+
+	schedules, err := LoadSchedules(dir)
+	require.NoError(err)
+	require.Len(schedules, 2)
+	require.Equal("health", schedules[0].Name)
+
+	require.NoError(RemoveSchedule(dir, "health"))
+	require.ErrorContains(RemoveSchedule(dir, "health"), "not found")
+	schedules, err = LoadSchedules(dir)
+	require.NoError(err)
+	require.Len(schedules, 1)
+}
+
+func TestRunHistory(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	start := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < constants.MaxNumOfScheduleRuns+2; i++ {
+		name := "a"
+		if i%2 == 1 {
+			name = "b"
+		}
+		require.NoError(appendRunRecord(dir, RunRecord{Schedule: name, StartedAt: start.Add(time.Duration(i) * time.Minute)}))
+	}
+	records, err := LoadRunHistory(dir, "")
+	require.NoError(err)
+	require.Len(records, constants.MaxNumOfScheduleRuns)
+	require.Equal(start.Add(time.Duration(constants.MaxNumOfScheduleRuns+1)*time.Minute), records[0].StartedAt)
+	records, err = LoadRunHistory(dir, "a")
+	require.NoError(err)
+	require.Len(records, constants.MaxNumOfScheduleRuns/2)
+}
+
+func TestRunSchedule(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	record := RunSchedule(dir, "/bin/sh", nil, Schedule{Name: "ok", Command: `-c "echo done"`})
+	require.True(record.Succeeded(), record.Error)
+	require.Equal(0, record.ExitCode)
+	output, err := os.ReadFile(record.LogPath)
+	require.NoError(err)
+	require.Equal("done\n", string(output))
+
+	record = RunSchedule(dir, "/bin/sh", nil, Schedule{Name: "fail", Command: `-c "exit 3"`})
+	require.False(record.Succeeded())
+	require.Equal(3, record.ExitCode)
+}
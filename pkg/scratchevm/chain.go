@@ -0,0 +1,113 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package scratchevm
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// Network returns the single node local network serving [chain]
+func Network(chain models.ScratchChain) models.Network {
+	return models.NewNetwork(models.Local, avagoconstants.LocalID, chain.Endpoint(), "")
+}
+
+// Launch starts the node of [chain], creating the chain on it if it was not created yet, and
+// waits until the chain is ready. The node is stopped if the chain can't be made ready
+func Launch(app *application.Avalanche, chain *models.ScratchChain, avalancheGoBinPath string) (*Node, error) {
+	node, err := StartNode(app, *chain, avalancheGoBinPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := node.WaitForChain(chain.Endpoint(), "P"); err != nil {
+		_ = node.Stop()
+		return nil, fmt.Errorf("%w. See %s", err, LogPath(app, *chain))
+	}
+	if chain.BlockchainID == ids.Empty {
+		node, err = CreateChain(app, chain, node, avalancheGoBinPath)
+		if err != nil {
+			_ = node.Stop()
+			return nil, fmt.Errorf("%w. See %s", err, LogPath(app, *chain))
+		}
+	}
+	if err := node.WaitForChain(chain.Endpoint(), chain.BlockchainID.String()); err != nil {
+		_ = node.Stop()
+		return nil, fmt.Errorf("%w. See %s", err, LogPath(app, *chain))
+	}
+	return node, nil
+}
+
+// CreateChain creates the scratch chain subnet and blockchain on the node P-Chain,
+// paid by ewoq. The node is restarted to track the subnet before creating the blockchain,
+// returning the new node process
+func CreateChain(
+	app *application.Avalanche,
+	chain *models.ScratchChain,
+	node *Node,
+	avalancheGoBinPath string,
+) (*Node, error) {
+	network := Network(*chain)
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		"",
+		network,
+		"",
+		true,
+		false,
+		nil,
+		0,
+	)
+	if err != nil {
+		return node, err
+	}
+	controlKeys, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return node, err
+	}
+	deployer := subnet.NewPublicDeployer(app, kc, network)
+	if chain.SubnetID == ids.Empty {
+		chain.SubnetID, err = deployer.DeploySubnet(controlKeys, 1)
+		if err != nil {
+			return node, err
+		}
+		if err := app.WriteScratchChain(*chain); err != nil {
+			return node, err
+		}
+	}
+	if err := WriteSubnetConfig(app, *chain); err != nil {
+		return node, err
+	}
+	if err := node.Stop(); err != nil {
+		return node, err
+	}
+	node, err = StartNode(app, *chain, avalancheGoBinPath)
+	if err != nil {
+		return node, err
+	}
+	if err := node.WaitForChain(chain.Endpoint(), "P"); err != nil {
+		return node, err
+	}
+	genesisBytes, err := os.ReadFile(app.GetScratchChainGenesisPath(chain.Name))
+	if err != nil {
+		return node, err
+	}
+	_, blockchainID, _, _, err := deployer.DeployBlockchain(
+		controlKeys,
+		controlKeys,
+		chain.SubnetID,
+		chain.Name,
+		genesisBytes,
+	)
+	if err != nil {
+		return node, err
+	}
+	chain.BlockchainID = blockchainID
+	return node, app.WriteScratchChain(*chain)
+}
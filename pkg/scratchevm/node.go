@@ -0,0 +1,173 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package scratchevm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	anrutils "github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/subnets"
+)
+
+const (
+	nodeDir       = "node"
+	pluginsDir    = "plugins"
+	subnetsDir    = "subnets"
+	nodeLogFile   = "node.log"
+	pollInterval  = time.Second
+	stopTimeout   = 30 * time.Second
+	chainsTimeout = 2 * time.Minute
+)
+
+var ErrNodeExited = errors.New("scratch chain node exited unexpectedly")
+
+// Node is a running single node avalanchego process serving a scratch chain
+type Node struct {
+	cmd  *exec.Cmd
+	done chan error
+}
+
+// nodeArgs returns the avalanchego flags of a single node local network, where the
+// node validates every subnet by itself, and builds blocks as soon as there are txs
+func nodeArgs(app *application.Avalanche, chain models.ScratchChain) []string {
+	chainDir := app.GetScratchChainDir(chain.Name)
+	args := []string{
+		fmt.Sprintf("--%s=local", config.NetworkNameKey),
+		fmt.Sprintf("--%s=false", config.SybilProtectionEnabledKey),
+		fmt.Sprintf("--%s=1", config.SnowSampleSizeKey),
+		fmt.Sprintf("--%s=1", config.SnowQuorumSizeKey),
+		fmt.Sprintf("--%s=true", config.StakingEphemeralCertEnabledKey),
+		fmt.Sprintf("--%s=true", config.StakingEphemeralSignerEnabledKey),
+		fmt.Sprintf("--%s=%d", config.HTTPPortKey, chain.HTTPPort),
+		fmt.Sprintf("--%s=%d", config.StakingPortKey, chain.HTTPPort+1),
+		fmt.Sprintf("--%s=%s", config.DataDirKey, filepath.Join(chainDir, nodeDir)),
+		fmt.Sprintf("--%s=%s", config.PluginDirKey, filepath.Join(chainDir, pluginsDir)),
+		fmt.Sprintf("--%s=%s", config.SubnetConfigDirKey, filepath.Join(chainDir, subnetsDir)),
+	}
+	if chain.SubnetID != ids.Empty {
+		args = append(args, fmt.Sprintf("--%s=%s", config.TrackSubnetsKey, chain.SubnetID))
+	}
+	return args
+}
+
+// InstallVM copies the Subnet-EVM binary at [vmBinPath] into the plugin dir of [chain]
+func InstallVM(app *application.Avalanche, chain models.ScratchChain, vmBinPath string) error {
+	vmID, err := anrutils.VMID(chain.Name)
+	if err != nil {
+		return fmt.Errorf("failed to create VM ID from %s: %w", chain.Name, err)
+	}
+	pluginDir := filepath.Join(app.GetScratchChainDir(chain.Name), pluginsDir)
+	if err := os.MkdirAll(pluginDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	pluginPath := filepath.Join(pluginDir, vmID.String())
+	if err := utils.FileCopy(vmBinPath, pluginPath); err != nil {
+		return err
+	}
+	return os.Chmod(pluginPath, constants.DefaultPerms755)
+}
+
+// WriteSubnetConfig removes the proposer min block delay of the scratch chain subnet,
+// so blocks are built as soon as transactions arrive
+func WriteSubnetConfig(app *application.Avalanche, chain models.ScratchChain) error {
+	configDir := filepath.Join(app.GetScratchChainDir(chain.Name), subnetsDir)
+	if err := os.MkdirAll(configDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	configBytes, err := json.MarshalIndent(subnets.Config{ProposerMinBlockDelay: 0}, "", "  ")
+	if err != nil {
+		return err
+	}
+	configPath := filepath.Join(configDir, chain.SubnetID.String()+".json")
+	return os.WriteFile(configPath, configBytes, constants.WriteReadReadPerms)
+}
+
+// StartNode starts the node of [chain], using the avalanchego binary at [avalancheGoBinPath],
+// with its output going to a log file on the chain dir
+func StartNode(app *application.Avalanche, chain models.ScratchChain, avalancheGoBinPath string) (*Node, error) {
+	chainDir := app.GetScratchChainDir(chain.Name)
+	if err := os.MkdirAll(chainDir, constants.DefaultPerms755); err != nil {
+		return nil, err
+	}
+	logFile, err := os.OpenFile(filepath.Join(chainDir, nodeLogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, constants.WriteReadReadPerms)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(avalancheGoBinPath, nodeArgs(app, chain)...) //nolint:gosec
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		_ = logFile.Close()
+		return nil, err
+	}
+	node := &Node{
+		cmd:  cmd,
+		done: make(chan error, 1),
+	}
+	go func() {
+		node.done <- cmd.Wait()
+		_ = logFile.Close()
+	}()
+	return node, nil
+}
+
+// Done receives the process result when the node exits
+func (n *Node) Done() <-chan error {
+	return n.done
+}
+
+// LogPath returns the path of the node output of [chain]
+func LogPath(app *application.Avalanche, chain models.ScratchChain) string {
+	return filepath.Join(app.GetScratchChainDir(chain.Name), nodeLogFile)
+}
+
+// Stop gracefully stops the node, killing it if it does not exit on time
+func (n *Node) Stop() error {
+	if err := n.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		if errors.Is(err, os.ErrProcessDone) {
+			return nil
+		}
+		return err
+	}
+	select {
+	case <-n.done:
+		return nil
+	case <-time.After(stopTimeout):
+		return n.cmd.Process.Kill()
+	}
+}
+
+// WaitForChain waits until the node at [endpoint] has bootstrapped [chain]
+func (n *Node) WaitForChain(endpoint string, chain string) error {
+	client := info.NewClient(endpoint)
+	deadline := time.Now().Add(chainsTimeout)
+	for time.Now().Before(deadline) {
+		ctx, cancel := utils.GetAPIContext()
+		bootstrapped, err := client.IsBootstrapped(ctx, chain)
+		cancel()
+		if err == nil && bootstrapped {
+			return nil
+		}
+		select {
+		case err := <-n.done:
+			n.done <- err
+			return fmt.Errorf("%w: %v", ErrNodeExited, err)
+		case <-time.After(pollInterval):
+		}
+	}
+	return fmt.Errorf("timeout waiting for chain %s to bootstrap at %s", chain, endpoint)
+}
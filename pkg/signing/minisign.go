@@ -0,0 +1,199 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package signing signs and verifies chain definition artifacts (exported blockchains,
+// upgrade files) with minisign keys and signatures, so keys generated with the minisign
+// tool can be used, and signatures can be checked with it.
+package signing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"aead.dev/minisign"
+)
+
+const (
+	// file extension of signature files, as used by minisign
+	SignatureExtension = ".minisig"
+
+	signatureUntrustedComment = "signature from avalanche-cli secret key"
+	// offset and size of the key derivation algorithm on an encoded minisign secret key
+	kdfAlgOffset = 2
+	kdfAlgLen    = 2
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrUntrustedKey     = errors.New("signature made by an untrusted key")
+)
+
+// KeyID identifies a signing key pair. Signatures carry the id of the key that made them
+type KeyID uint64
+
+// String returns the key id as shown by minisign
+func (id KeyID) String() string {
+	return fmt.Sprintf("%016X", uint64(id))
+}
+
+type PublicKey struct {
+	ID  KeyID
+	key minisign.PublicKey
+}
+
+type SecretKey struct {
+	ID  KeyID
+	key minisign.PrivateKey
+}
+
+// Signature is a parsed minisign signature file
+type Signature struct {
+	KeyID            KeyID
+	TrustedComment   string
+	UntrustedComment string
+	signature        minisign.Signature
+}
+
+// GenerateKey generates a new random signing key pair
+func GenerateKey() (*SecretKey, error) {
+	_, privateKey, err := minisign.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretKey{ID: KeyID(privateKey.ID()), key: privateKey}, nil
+}
+
+// Public returns the public key of [sk]
+func (sk *SecretKey) Public() *PublicKey {
+	publicKey := sk.key.Public().(minisign.PublicKey)
+	return &PublicKey{ID: sk.ID, key: publicKey}
+}
+
+// String returns the base64 encoding of the public key, as given on the second line
+// of minisign public key files
+func (pk *PublicKey) String() string {
+	return pk.key.String()
+}
+
+// Encode returns the contents of a minisign public key file for [pk]
+func (pk *PublicKey) Encode() []byte {
+	text, _ := pk.key.MarshalText()
+	return append(text, '\n')
+}
+
+// ParsePublicKey parses either the contents of a minisign public key file, or
+// the base64 encoded public key alone
+func ParsePublicKey(s string) (*PublicKey, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("empty public key")
+	}
+	var publicKey minisign.PublicKey
+	if err := publicKey.UnmarshalText([]byte(s)); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return &PublicKey{ID: KeyID(publicKey.ID()), key: publicKey}, nil
+}
+
+// ParseSecretKey parses the contents of a minisign secret key file, protected with
+// [password]. Unencrypted secret keys are not supported
+func ParseSecretKey(data []byte, password string) (*SecretKey, error) {
+	if err := checkEncryptedSecretKey(data); err != nil {
+		return nil, err
+	}
+	if password == "" {
+		return nil, errors.New("the secret key is password protected")
+	}
+	privateKey, err := minisign.DecryptKey(password, bytes.TrimSpace(data))
+	if err != nil {
+		return nil, fmt.Errorf("wrong password for the secret key, or unsupported secret key: %w", err)
+	}
+	return &SecretKey{ID: KeyID(privateKey.ID()), key: privateKey}, nil
+}
+
+// checkEncryptedSecretKey gives a clear error for the unencrypted keys minisign
+// generates with -W, that can not be used
+func checkEncryptedSecretKey(data []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	encoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[len(lines)-1]))
+	if err != nil || len(encoded) < kdfAlgOffset+kdfAlgLen {
+		return errors.New("invalid secret key encoding")
+	}
+	if bytes.Equal(encoded[kdfAlgOffset:kdfAlgOffset+kdfAlgLen], []byte{0, 0}) {
+		return errors.New("unencrypted secret keys are not supported. Protect it with a password using minisign -C")
+	}
+	return nil
+}
+
+// Encode returns the contents of a minisign secret key file for [sk], protected
+// with [password]
+func (sk *SecretKey) Encode(password string) ([]byte, error) {
+	if password == "" {
+		return nil, errors.New("a password is required to protect the secret key")
+	}
+	encoded, err := minisign.EncryptKey(password, sk.key)
+	if err != nil {
+		return nil, err
+	}
+	return append(encoded, '\n'), nil
+}
+
+// Sign signs [message], returning the contents of a minisign signature file.
+// [trustedComment] is also signed, and shown on verification. The message is
+// pre-hashed, as the minisign tool does by default
+func (sk *SecretKey) Sign(message []byte, trustedComment string) []byte {
+	reader := minisign.NewReader(bytes.NewReader(message))
+	_, _ = io.Copy(io.Discard, reader)
+	return append(reader.SignWithComments(sk.key, trustedComment, signatureUntrustedComment), '\n')
+}
+
+// ParseSignature parses the contents of a minisign signature file
+func ParseSignature(data []byte) (*Signature, error) {
+	var signature minisign.Signature
+	if err := signature.UnmarshalText(bytes.TrimSpace(data)); err != nil {
+		return nil, fmt.Errorf("invalid signature file: %w", err)
+	}
+	return &Signature{
+		KeyID:            KeyID(signature.KeyID),
+		TrustedComment:   signature.TrustedComment,
+		UntrustedComment: signature.UntrustedComment,
+		signature:        signature,
+	}, nil
+}
+
+// Verify checks that [sig] is a valid signature of [message] made by [pk]
+func (pk *PublicKey) Verify(message []byte, sig *Signature) error {
+	if sig.KeyID != pk.ID {
+		return fmt.Errorf("%w: signature key id %s differs from public key id %s", ErrInvalidSignature, sig.KeyID, pk.ID)
+	}
+	signature := sig.signature
+	// the trusted comment shown to the user is the one verified
+	signature.TrustedComment = sig.TrustedComment
+	text, err := signature.MarshalText()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if !minisign.Verify(pk.key, message, text) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyWithKeys checks that [sig] is a valid signature of [message] made by
+// any of [keys], returning the signing key
+func VerifyWithKeys(keys []*PublicKey, message []byte, sig *Signature) (*PublicKey, error) {
+	for _, pk := range keys {
+		if pk.ID == sig.KeyID {
+			if err := pk.Verify(message, sig); err != nil {
+				return nil, err
+			}
+			return pk, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %s", ErrUntrustedKey, sig.KeyID)
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package signing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"aead.dev/minisign"
+	"github.com/stretchr/testify/require"
+)
+
+// public key, message and signature generated with the minisign tool
+const (
+	minisignToolPublicKey = `untrusted comment: minisign public key C373193807678450
+RWRQhGcHOBlzw4CoKyugkk4ioDfoxlXxC9LBx+VNhJ3w9w+cAxgvPsuo
+`
+	minisignToolMessage   = "Hello World!\n"
+	minisignToolSignature = `untrusted comment: signature from minisign secret key
+RWRQhGcHOBlzwxrJCyuC+rJfHSfyRKRxkuwa3JJ0bWEs7RHjL1OUmqnTr+V1B9JzFuJIH/ybR2Eus9oEZKt9RbitpF/L4D3+5wg=
+trusted comment: timestamp:1614549543	file:message.txt
+P/722+ynQ+tIy0qadFHwLx5MsyNz/jDKJkDWQj4dDD2OKnVte8m/M14mwPE/1NMwzShPMSBhMXqZGdbe+UZjDg==
+`
+)
+
+// secret key without key derivation, the format minisign -G -W writes
+const unencryptedSecretKey = `untrusted comment: minisign secret key 0
+RWQAAEIyAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=
+`
+
+func TestVerifyMinisignToolSignature(t *testing.T) {
+	require := require.New(t)
+	pk, err := ParsePublicKey(minisignToolPublicKey)
+	require.NoError(err)
+	require.Equal("C373193807678450", pk.ID.String())
+	sig, err := ParseSignature([]byte(minisignToolSignature))
+	require.NoError(err)
+	require.Equal(pk.ID, sig.KeyID)
+	require.Equal("timestamp:1614549543\tfile:message.txt", sig.TrustedComment)
+	require.NoError(pk.Verify([]byte(minisignToolMessage), sig))
+	require.ErrorIs(pk.Verify([]byte("Hello World?\n"), sig), ErrInvalidSignature)
+
+	// the base64 encoded key alone is also accepted
+	pk, err = ParsePublicKey(strings.Split(minisignToolPublicKey, "\n")[1])
+	require.NoError(err)
+	require.NoError(pk.Verify([]byte(minisignToolMessage), sig))
+}
+
+func TestSignVerify(t *testing.T) {
+	require := require.New(t)
+	sk, err := GenerateKey()
+	require.NoError(err)
+	pk, err := ParsePublicKey(string(sk.Public().Encode()))
+	require.NoError(err)
+	require.Equal(sk.ID, pk.ID)
+
+	message := []byte(`{"sidecar":{"Name":"chain"}}`)
+	sigBytes := sk.Sign(message, "timestamp:1\tfile:chain.json")
+	sig, err := ParseSignature(sigBytes)
+	require.NoError(err)
+	require.Equal("timestamp:1\tfile:chain.json", sig.TrustedComment)
+	require.NoError(pk.Verify(message, sig))
+
+	// signatures are pre-hashed, as made by the minisign tool, and verify with
+	// an independent implementation
+	var minisignSig minisign.Signature
+	require.NoError(minisignSig.UnmarshalText(sigBytes))
+	require.Equal(minisign.HashEdDSA, minisignSig.Algorithm)
+	var minisignPK minisign.PublicKey
+	require.NoError(minisignPK.UnmarshalText(sk.Public().Encode()))
+	reader := minisign.NewReader(strings.NewReader(string(message)))
+	_, err = reader.Read(make([]byte, len(message)))
+	require.NoError(err)
+	require.True(reader.Verify(minisignPK, sigBytes))
+
+	// modified message
+	err = pk.Verify([]byte(`{"sidecar":{"Name":"other"}}`), sig)
+	require.True(errors.Is(err, ErrInvalidSignature))
+
+	// modified trusted comment
+	sig.TrustedComment = "timestamp:2\tfile:chain.json"
+	err = pk.Verify(message, sig)
+	require.True(errors.Is(err, ErrInvalidSignature))
+}
+
+func TestVerifyWithKeys(t *testing.T) {
+	require := require.New(t)
+	sk, err := GenerateKey()
+	require.NoError(err)
+	otherSK, err := GenerateKey()
+	require.NoError(err)
+	message := []byte("upgrade")
+	sig, err := ParseSignature(sk.Sign(message, ""))
+	require.NoError(err)
+
+	signer, err := VerifyWithKeys([]*PublicKey{otherSK.Public(), sk.Public()}, message, sig)
+	require.NoError(err)
+	require.Equal(sk.ID, signer.ID)
+
+	_, err = VerifyWithKeys([]*PublicKey{otherSK.Public()}, message, sig)
+	require.True(errors.Is(err, ErrUntrustedKey))
+}
+
+func TestSecretKeyErrors(t *testing.T) {
+	require := require.New(t)
+	sk, err := GenerateKey()
+	require.NoError(err)
+	_, err = sk.Encode("")
+	require.ErrorContains(err, "password is required")
+	_, err = ParseSecretKey([]byte(unencryptedSecretKey), "")
+	require.ErrorContains(err, "unencrypted secret keys are not supported")
+	_, err = ParseSecretKey([]byte("untrusted comment: x\nnot base64"), "secret")
+	require.ErrorContains(err, "invalid secret key encoding")
+}
+
+func TestParseErrors(t *testing.T) {
+	require := require.New(t)
+	_, err := ParseSignature([]byte("untrusted comment: x\nAAAA\n"))
+	require.ErrorContains(err, "invalid signature file")
+	_, err = ParsePublicKey("RWQ=")
+	require.ErrorContains(err, "invalid public key")
+	_, err = ParsePublicKey(" ")
+	require.ErrorContains(err, "empty public key")
+}
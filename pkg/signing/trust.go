@@ -0,0 +1,175 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package signing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+// TrustedKeys returns the public keys trusted to sign artifacts, as set on the CLI config
+func TrustedKeys(app *application.Avalanche) ([]*PublicKey, error) {
+	keys := []*PublicKey{}
+	for _, encoded := range app.Conf.GetConfigStringSliceValue(constants.ConfigTrustedSigningKeysKey) {
+		pk, err := ParsePublicKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted signing key %q on CLI config: %w", encoded, err)
+		}
+		keys = append(keys, pk)
+	}
+	return keys, nil
+}
+
+// AddTrustedKey adds [pk] to the trusted keys on the CLI config
+func AddTrustedKey(app *application.Avalanche, pk *PublicKey) error {
+	keys, err := TrustedKeys(app)
+	if err != nil {
+		return err
+	}
+	encodedKeys := []string{}
+	for _, trusted := range keys {
+		if trusted.ID == pk.ID {
+			return fmt.Errorf("a key with id %s is already trusted", pk.ID)
+		}
+		encodedKeys = append(encodedKeys, trusted.String())
+	}
+	return app.Conf.SetConfigValue(constants.ConfigTrustedSigningKeysKey, append(encodedKeys, pk.String()))
+}
+
+// RemoveTrustedKey removes the key with id [keyID] from the trusted keys on the CLI config
+func RemoveTrustedKey(app *application.Avalanche, keyID string) error {
+	keys, err := TrustedKeys(app)
+	if err != nil {
+		return err
+	}
+	encodedKeys := []string{}
+	found := false
+	for _, trusted := range keys {
+		if trusted.ID.String() == keyID {
+			found = true
+			continue
+		}
+		encodedKeys = append(encodedKeys, trusted.String())
+	}
+	if !found {
+		return fmt.Errorf("no trusted key with id %s", keyID)
+	}
+	return app.Conf.SetConfigValue(constants.ConfigTrustedSigningKeysKey, encodedKeys)
+}
+
+// SignaturesRequired indicates if unsigned artifacts are rejected on import and deploy
+func SignaturesRequired(app *application.Avalanche) bool {
+	return app.Conf.GetConfigBoolValue(constants.ConfigRequireSignaturesKey)
+}
+
+// LoadSecretKey loads the password protected minisign secret key at [path], prompting
+// for its password
+func LoadSecretKey(app *application.Avalanche, path string) (*SecretKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading secret key %s: %w", path, err)
+	}
+	if err := checkEncryptedSecretKey(data); err != nil {
+		return nil, fmt.Errorf("invalid secret key %s: %w", path, err)
+	}
+	password, err := app.Prompt.CapturePassword(fmt.Sprintf("Password for secret key %s", path))
+	if err != nil {
+		return nil, err
+	}
+	return ParseSecretKey(data, password)
+}
+
+// SignArtifact signs the artifact [content], written at [artifactPath], with the secret
+// key at [secretKeyPath], writing the signature next to it
+func SignArtifact(app *application.Avalanche, content []byte, artifactPath string, secretKeyPath string) error {
+	sk, err := LoadSecretKey(app, secretKeyPath)
+	if err != nil {
+		return err
+	}
+	trustedComment := fmt.Sprintf("timestamp:%d\tfile:%s", time.Now().Unix(), filepath.Base(artifactPath))
+	signaturePath := artifactPath + SignatureExtension
+	if err := os.WriteFile(signaturePath, sk.Sign(content, trustedComment), constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Signature written to %s (key id %s)", signaturePath, sk.ID)
+	return nil
+}
+
+// VerifyArtifact checks the signature at [signaturePath] of the artifact [content] against the
+// trusted keys. If there is no signature, it fails only if signatures are required.
+// Returns the signing key, or nil if the artifact is not signed
+func VerifyArtifact(app *application.Avalanche, content []byte, signaturePath string) (*PublicKey, error) {
+	if !utils.FileExists(signaturePath) {
+		if SignaturesRequired(app) {
+			return nil, fmt.Errorf("signature %s not found, and signatures are required by the CLI config", signaturePath)
+		}
+		return nil, nil
+	}
+	sigBytes, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := ParseSignature(sigBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %s: %w", signaturePath, err)
+	}
+	keys, err := TrustedKeys(app)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("artifact is signed by key %s, but there are no trusted keys. Add one with avalanche config signing --trust", sig.KeyID)
+	}
+	pk, err := VerifyWithKeys(keys, content, sig)
+	if err != nil {
+		return nil, fmt.Errorf("signature %s verification failed: %w", signaturePath, err)
+	}
+	ux.Logger.GreenCheckmarkToUser("Signature verified: signed by trusted key %s (%s)", pk.ID, sig.TrustedComment)
+	return pk, nil
+}
+
+// GenesisHash returns the hash recorded on import for later checks of [genesis]
+func GenesisHash(genesis []byte) string {
+	hash := sha256.Sum256(genesis)
+	return hex.EncodeToString(hash[:])
+}
+
+// CheckDeployable verifies that the blockchain definition given by [sc] and [genesis] was
+// imported with a signature that is still trusted, and that the genesis was not
+// modified since, if signatures are required by the CLI config
+func CheckDeployable(app *application.Avalanche, sc models.Sidecar, genesis []byte) error {
+	if !SignaturesRequired(app) {
+		return nil
+	}
+	if sc.ImportSignature.KeyID == "" {
+		return errors.New("signatures are required by the CLI config, but the blockchain was not imported from a signed export")
+	}
+	keys, err := TrustedKeys(app)
+	if err != nil {
+		return err
+	}
+	trusted := false
+	for _, pk := range keys {
+		if pk.ID.String() == sc.ImportSignature.KeyID {
+			trusted = true
+		}
+	}
+	if !trusted {
+		return fmt.Errorf("blockchain was imported signed by key %s, which is no longer trusted", sc.ImportSignature.KeyID)
+	}
+	if GenesisHash(genesis) != sc.ImportSignature.GenesisHash {
+		return errors.New("blockchain genesis was modified after being imported from a signed export")
+	}
+	return nil
+}
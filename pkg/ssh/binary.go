@@ -0,0 +1,201 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ssh
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/docker"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+// systemd drop-in of the avalanchego service holding the host resource limits
+const binaryNodeLimitsDropIn = "/etc/systemd/system/avalanchego.service.d/limits.conf"
+
+var (
+	// avalanchego release bundles already downloaded, by URL, so they are fetched only once
+	// when provisioning a whole cluster
+	avalanchegoBundlesLock sync.Mutex
+	avalanchegoBundles     = map[string][]byte{}
+)
+
+// RunSSHSetupNodeBinary prepares a host to run avalanchego as a native systemd or openrc
+// service, without docker, ansible or python, and uploads the CLI config to it
+func RunSSHSetupNodeBinary(host *models.Host, configPath string) error {
+	if err := RunOverSSH(
+		"Setup Node Binary",
+		host,
+		constants.SSHLongRunningScriptTimeout,
+		"shell/setupNodeBinary.sh",
+		scriptInputs{},
+	); err != nil {
+		return err
+	}
+	ux.Logger.Info("Uploading config %s to server %s: %s", configPath, host.NodeID, filepath.Join(constants.CloudNodeCLIConfigBasePath, filepath.Base(configPath)))
+	return host.Upload(
+		configPath,
+		filepath.Join(constants.CloudNodeCLIConfigBasePath, filepath.Base(configPath)),
+		constants.SSHFileOpsTimeout,
+	)
+}
+
+// RunSSHSetupAvalanchegoBinary uploads the avalanchego configs and binary to a host prepared
+// with RunSSHSetupNodeBinary, and starts the avalanchego service. If [avalancheGoBinaryPath]
+// is given, that local binary (ex: a statically linked build for musl based images) is
+// uploaded instead of the [avalancheGoVersion] release bundle
+func RunSSHSetupAvalanchegoBinary(
+	host *models.Host,
+	network models.Network,
+	avalancheGoVersion string,
+	avalancheGoBinaryPath string,
+	avalancheGoConfig docker.AvalancheGoConfigOptions,
+) error {
+	if err := docker.SetupAvalanchegoConfigs(host, network, avalancheGoConfig); err != nil {
+		return err
+	}
+	if err := uploadAvalanchegoBinary(host, avalancheGoVersion, avalancheGoBinaryPath); err != nil {
+		return err
+	}
+	return runBinaryNodeService(host, "start")
+}
+
+// IsBinaryNode checks if avalanchego was provisioned on the host as a native service,
+// instead of a docker compose service
+func IsBinaryNode(host *models.Host) bool {
+	binaryExists, _ := host.FileExists(filepath.Join(constants.CloudNodeAvalancheGoBinDir, constants.AvalancheGoInstallDir))
+	return binaryExists
+}
+
+// uploadAvalanchegoBinary installs avalanchego on the host, either from the local binary at
+// [avalancheGoBinaryPath] or from the [avalancheGoVersion] release bundle for the host arch
+func uploadAvalanchegoBinary(host *models.Host, avalancheGoVersion string, avalancheGoBinaryPath string) error {
+	remoteBinaryPath := filepath.Join(constants.CloudNodeAvalancheGoBinDir, constants.AvalancheGoInstallDir)
+	if avalancheGoBinaryPath != "" {
+		if err := host.Upload(avalancheGoBinaryPath, remoteBinaryPath, constants.SSHLongRunningScriptTimeout); err != nil {
+			return err
+		}
+		if output, err := host.Command("chmod +x "+remoteBinaryPath, nil, constants.SSHScriptTimeout); err != nil {
+			return fmt.Errorf("%w: %s", err, string(output))
+		}
+		return nil
+	}
+	goarch, err := GetHostArch(host)
+	if err != nil {
+		return err
+	}
+	bundleURL, _, err := binutils.NewAvagoDownloader().GetDownloadURL(avalancheGoVersion, binutils.NewRemoteInstaller(goarch, "linux"))
+	if err != nil {
+		return err
+	}
+	bundle, err := getAvalanchegoBundle(bundleURL)
+	if err != nil {
+		return err
+	}
+	remoteBundlePath, err := host.CreateTempFile()
+	if err != nil {
+		return err
+	}
+	if err := host.UploadBytes(bundle, remoteBundlePath, constants.SSHLongRunningScriptTimeout); err != nil {
+		return err
+	}
+	ux.Logger.Info("AvalancheGo %s bundle uploaded to %s[%s]", avalancheGoVersion, host.NodeID, host.IP)
+	// the release bundle holds the binary under a versioned top level dir
+	if output, err := host.Command(
+		fmt.Sprintf("tar -xzf %s -C %s --strip-components=1 && rm -f %s", remoteBundlePath, constants.CloudNodeAvalancheGoBinDir, remoteBundlePath),
+		nil,
+		constants.SSHLongRunningScriptTimeout,
+	); err != nil {
+		return fmt.Errorf("%w: %s", err, string(output))
+	}
+	return nil
+}
+
+// getAvalanchegoBundle downloads the avalanchego release bundle at [bundleURL], or returns it
+// from the bundles already downloaded
+func getAvalanchegoBundle(bundleURL string) ([]byte, error) {
+	avalanchegoBundlesLock.Lock()
+	defer avalanchegoBundlesLock.Unlock()
+	if bundle, ok := avalanchegoBundles[bundleURL]; ok {
+		return bundle, nil
+	}
+	ux.Logger.Info("Downloading avalanchego bundle %s", bundleURL)
+	bundle, err := application.NewDownloader().Download(bundleURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download avalanchego bundle %s: %w", bundleURL, err)
+	}
+	avalanchegoBundles[bundleURL] = bundle
+	return bundle, nil
+}
+
+// GetHostArch returns the go arch of the release binaries to install on [host]
+func GetHostArch(host *models.Host) (string, error) {
+	output, err := host.Command("uname -m", nil, constants.SSHScriptTimeout)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, string(output))
+	}
+	return parseRemoteArch(string(output))
+}
+
+// parseRemoteArch maps the output of uname -m to the go arch of the release bundles
+func parseRemoteArch(unameOutput string) (string, error) {
+	switch arch := strings.TrimSpace(unameOutput); arch {
+	case "x86_64", "amd64":
+		return "amd64", nil
+	case "aarch64", "arm64":
+		return "arm64", nil
+	default:
+		return "", fmt.Errorf("unsupported host architecture %q", arch)
+	}
+}
+
+// binaryNodeServiceCommand returns the command that runs [action] (start, stop, restart) on
+// the avalanchego service of a binary node, either managed by systemd or by openrc
+func binaryNodeServiceCommand(action string) string {
+	return fmt.Sprintf(
+		"if [ -d /run/systemd/system ]; then sudo systemctl %s avalanchego; else sudo rc-service avalanchego %s; fi",
+		action,
+		action,
+	)
+}
+
+// binaryNodeLimitsCommand returns the command that sets the CPU and memory limits of
+// [hostNodeConfig] on the avalanchego service of a binary node, through a systemd drop-in.
+// The drop-in is removed if no limit is set. Limits are not supported on openrc hosts
+func binaryNodeLimitsCommand(hostNodeConfig models.HostNodeConfig) string {
+	if !hostNodeConfig.HasResourceLimits() {
+		return fmt.Sprintf(
+			"if [ -f %s ]; then sudo rm -f %s && sudo systemctl daemon-reload; fi",
+			binaryNodeLimitsDropIn,
+			binaryNodeLimitsDropIn,
+		)
+	}
+	limits := []string{"[Service]"}
+	if hostNodeConfig.CPUs != 0 {
+		limits = append(limits, fmt.Sprintf("CPUQuota=%s%%", strconv.FormatFloat(hostNodeConfig.CPUs*100, 'f', -1, 64)))
+	}
+	if hostNodeConfig.Memory != "" {
+		limits = append(limits, "MemoryMax="+hostNodeConfig.Memory)
+	}
+	return fmt.Sprintf(
+		"if [ -d /run/systemd/system ]; then sudo mkdir -p %s && printf '%%s\\n' '%s' | sudo tee %s > /dev/null && sudo systemctl daemon-reload; "+
+			"else echo 'resource limits require systemd' >&2; exit 1; fi",
+		filepath.Dir(binaryNodeLimitsDropIn),
+		strings.Join(limits, "' '"),
+		binaryNodeLimitsDropIn,
+	)
+}
+
+func runBinaryNodeService(host *models.Host, action string) error {
+	if output, err := host.Command(binaryNodeServiceCommand(action), nil, constants.SSHLongRunningScriptTimeout); err != nil {
+		return fmt.Errorf("%w: %s", err, string(output))
+	}
+	return nil
+}
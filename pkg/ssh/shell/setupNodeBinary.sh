@@ -0,0 +1,51 @@
+#!/bin/sh
+# provisions avalanchego as a native service. Only a POSIX shell, tar and sudo are
+# required on the host, so it also runs on minimal images (Flatcar, Alpine)
+set -e
+NODE_USER=$(id -un)
+sudo mkdir -p /home/ubuntu/.avalanchego /home/ubuntu/.avalanche-cli /home/ubuntu/avalanche-node
+sudo chown -R "$NODE_USER" /home/ubuntu/.avalanchego /home/ubuntu/.avalanche-cli /home/ubuntu/avalanche-node
+# node configs refer to the data dir by its path inside the docker container
+sudo ln -sfn /home/ubuntu/.avalanchego /.avalanchego
+if [ -d /run/systemd/system ]; then
+sudo tee /etc/systemd/system/avalanchego.service > /dev/null <<EOF
+[Unit]
+Description=AvalancheGo node
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+User=$NODE_USER
+ExecStart=/home/ubuntu/avalanche-node/avalanchego --data-dir=/home/ubuntu/.avalanchego --config-file=/home/ubuntu/.avalanchego/configs/node.json
+Restart=always
+RestartSec=5
+LimitNOFILE=32768
+
+[Install]
+WantedBy=multi-user.target
+EOF
+sudo systemctl daemon-reload
+sudo systemctl enable avalanchego
+elif [ -x /sbin/openrc-run ]; then
+sudo tee /etc/init.d/avalanchego > /dev/null <<EOF
+#!/sbin/openrc-run
+name="avalanchego"
+description="AvalancheGo node"
+supervisor="supervise-daemon"
+command="/home/ubuntu/avalanche-node/avalanchego"
+command_args="--data-dir=/home/ubuntu/.avalanchego --config-file=/home/ubuntu/.avalanchego/configs/node.json"
+command_user="$NODE_USER"
+rc_ulimit="-n 32768"
+respawn_delay=5
+
+depend() {
+	need net
+}
+EOF
+sudo chmod +x /etc/init.d/avalanchego
+sudo rc-update add avalanchego default
+else
+echo "no supported service manager found on host, systemd or openrc is required" >&2
+exit 1
+fi
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"text/template"
@@ -151,6 +152,9 @@ func RunSSHSetupDockerService(host *models.Host) error {
 
 // RunSSHRestartNode runs script to restart avalanchego
 func RunSSHRestartNode(host *models.Host) error {
+	if IsBinaryNode(host) {
+		return runBinaryNodeService(host, "restart")
+	}
 	remoteComposeFile := utils.GetRemoteComposeFile()
 	avagoService := "avalanchego"
 	if utils.IsE2E() {
@@ -177,18 +181,107 @@ func RunSSHStopICMRelayerService(host *models.Host) error {
 	return docker.StopDockerComposeService(host, utils.GetRemoteComposeFile(), "icm-relayer", constants.SSHLongRunningScriptTimeout)
 }
 
-// RunSSHUpgradeAvalanchego runs script to upgrade avalanchego
-func RunSSHUpgradeAvalanchego(host *models.Host, avalancheGoVersion string) error {
+// RunSSHSetupReverseProxy configures and (re)starts the caddy reverse proxy that serves the
+// avalanchego API of the host over HTTPS. If [certFile] and [keyFile] are given, they are
+// uploaded to be used as the proxy certificate
+func RunSSHSetupReverseProxy(host *models.Host, inputs remoteconfig.ReverseProxyInputs, certFile string, keyFile string) error {
+	for _, folder := range remoteconfig.ReverseProxyFoldersToCreate() {
+		if err := host.MkdirAll(folder, constants.SSHDirOpsTimeout); err != nil {
+			return err
+		}
+	}
+	if certFile != "" {
+		for localFile, remoteFile := range map[string]string{
+			certFile: remoteconfig.GetRemoteReverseProxyCertFile(),
+			keyFile:  remoteconfig.GetRemoteReverseProxyKeyFile(),
+		} {
+			if err := host.Upload(localFile, remoteFile, constants.SSHFileOpsTimeout); err != nil {
+				return err
+			}
+		}
+	}
+	caddyfile, err := remoteconfig.RenderCaddyfile(inputs)
+	if err != nil {
+		return err
+	}
+	if err := host.UploadBytes(caddyfile, remoteconfig.GetRemoteCaddyfile(), constants.SSHFileOpsTimeout); err != nil {
+		return err
+	}
+	if err := docker.ComposeSSHSetupReverseProxy(host); err != nil {
+		return err
+	}
+	// picks up config changes if the proxy was already running
+	return docker.RestartDockerComposeService(host, utils.GetRemoteComposeFile(), "caddy", constants.SSHLongRunningScriptTimeout)
+}
+
+// ReverseProxyHasUserCert returns true if the reverse proxy of the host was set up with a
+// user provided certificate, instead of a Let's Encrypt one
+func ReverseProxyHasUserCert(host *models.Host) (bool, error) {
+	return host.FileExists(remoteconfig.GetRemoteReverseProxyCertFile())
+}
+
+// RunSSHSetupTelemetryAgent configures and starts the agent that exports the metrics and logs
+// of the host to the external monitoring [backend]. [credentials] are uploaded readable by the
+// owner only, as they hold the backend API key or cloud credentials
+func RunSSHSetupTelemetryAgent(host *models.Host, backend string, inputs remoteconfig.TelemetryInputs, credentials []byte) error {
+	for _, folder := range remoteconfig.TelemetryFoldersToCreate(backend) {
+		if err := host.MkdirAll(folder, constants.SSHDirOpsTimeout); err != nil {
+			return err
+		}
+	}
+	configs, err := remoteconfig.RenderTelemetryConfigs(backend, inputs)
+	if err != nil {
+		return err
+	}
+	for remoteFile, config := range configs {
+		if err := host.UploadBytes(config, remoteFile, constants.SSHFileOpsTimeout); err != nil {
+			return err
+		}
+	}
+	credentialsFile := remoteconfig.GetRemoteTelemetryCredentialsFile(backend)
+	if err := host.UploadBytes(credentials, credentialsFile, constants.SSHFileOpsTimeout); err != nil {
+		return err
+	}
+	if output, err := host.Command(fmt.Sprintf("chmod 600 %s", credentialsFile), nil, constants.SSHFileOpsTimeout); err != nil {
+		return fmt.Errorf("%w: %s", err, string(output))
+	}
+	return docker.ComposeSSHSetupTelemetryAgent(host, backend)
+}
+
+// RunSSHUpgradeAvalanchego runs script to upgrade avalanchego, applying the AvalancheGo
+// settings overridden for the host
+func RunSSHUpgradeAvalanchego(host *models.Host, avalancheGoVersion string, hostNodeConfig models.HostNodeConfig) error {
+	if IsBinaryNode(host) {
+		// the running binary can't be overwritten
+		if err := runBinaryNodeService(host, "stop"); err != nil {
+			return err
+		}
+		if err := uploadAvalanchegoBinary(host, avalancheGoVersion, ""); err != nil {
+			return err
+		}
+		if err := RunSSHApplyHostNodeConfig(host, hostNodeConfig); err != nil {
+			return err
+		}
+		return runBinaryNodeService(host, "start")
+	}
+	if err := RunSSHApplyHostNodeConfig(host, hostNodeConfig); err != nil {
+		return err
+	}
 	withMonitoring, err := docker.WasNodeSetupWithMonitoring(host)
 	if err != nil {
 		return err
 	}
+	avagoDockerImage, err := docker.PrepareAvalanchegoImage(host, avalancheGoVersion)
+	if err != nil {
+		return err
+	}
 	if err := docker.ComposeOverSSH("Compose Node",
 		host,
 		constants.SSHScriptTimeout,
 		"templates/avalanchego.docker-compose.yml",
 		docker.DockerComposeInputs{
 			AvalanchegoVersion: avalancheGoVersion,
+			AvalanchegoImage:   avagoDockerImage,
 			WithMonitoring:     withMonitoring,
 			WithAvalanchego:    true,
 			E2E:                utils.IsE2E(),
@@ -211,6 +304,9 @@ func RunSSHStartNode(host *models.Host) error {
 			scriptInputs{},
 		)
 	}
+	if IsBinaryNode(host) {
+		return runBinaryNodeService(host, "start")
+	}
 	return docker.StartDockerComposeService(host, utils.GetRemoteComposeFile(), "avalanchego", constants.SSHLongRunningScriptTimeout)
 }
 
@@ -225,6 +321,9 @@ func RunSSHStopNode(host *models.Host) error {
 			scriptInputs{},
 		)
 	}
+	if IsBinaryNode(host) {
+		return runBinaryNodeService(host, "stop")
+	}
 	return docker.StopDockerComposeService(host, utils.GetRemoteComposeFile(), "avalanchego", constants.SSHLongRunningScriptTimeout)
 }
 
@@ -326,7 +425,7 @@ func RunSSHCopyYAMLFile(host *models.Host, yamlFilePath string) error {
 	return nil
 }
 
-func RunSSHSetupPrometheusConfig(host *models.Host, avalancheGoPorts, machinePorts, loadTestPorts []string) error {
+func RunSSHSetupPrometheusConfig(host *models.Host, avalancheGoPorts, machinePorts, loadTestPorts []string, targetRegions map[string]string) error {
 	for _, folder := range remoteconfig.PrometheusFoldersToCreate() {
 		if err := host.MkdirAll(folder, constants.SSHDirOpsTimeout); err != nil {
 			return err
@@ -338,7 +437,7 @@ func RunSSHSetupPrometheusConfig(host *models.Host, avalancheGoPorts, machinePor
 		return err
 	}
 	defer os.Remove(promConfig.Name())
-	if err := monitoring.WritePrometheusConfig(promConfig.Name(), avalancheGoPorts, machinePorts, loadTestPorts); err != nil {
+	if err := monitoring.WritePrometheusConfig(promConfig.Name(), avalancheGoPorts, machinePorts, loadTestPorts, targetRegions); err != nil {
 		return err
 	}
 
@@ -349,6 +448,43 @@ func RunSSHSetupPrometheusConfig(host *models.Host, avalancheGoPorts, machinePor
 	)
 }
 
+// RunSSHSetupAlertingConfig uploads the prometheus alerting rules, the alertmanager config
+// and the validator balance exporter script to the monitoring host
+func RunSSHSetupAlertingConfig(
+	host *models.Host,
+	rulesInputs remoteconfig.AlertingRulesInputs,
+	alertmanagerInputs remoteconfig.AlertmanagerInputs,
+	exporterInputs remoteconfig.ValidatorBalanceExporterInputs,
+) error {
+	for _, folder := range remoteconfig.AlertingFoldersToCreate() {
+		if err := host.MkdirAll(folder, constants.SSHDirOpsTimeout); err != nil {
+			return err
+		}
+	}
+	rules, err := remoteconfig.RenderPrometheusAlertingRules(rulesInputs)
+	if err != nil {
+		return err
+	}
+	alertmanagerConfig, err := remoteconfig.RenderAlertmanagerConfig(alertmanagerInputs)
+	if err != nil {
+		return err
+	}
+	exporterScript, err := remoteconfig.RenderValidatorBalanceExporterScript(exporterInputs)
+	if err != nil {
+		return err
+	}
+	for remoteFile, content := range map[string][]byte{
+		remoteconfig.GetRemotePrometheusAlertingRules():        rules,
+		remoteconfig.GetRemoteAlertmanagerConfig():             alertmanagerConfig,
+		remoteconfig.GetRemoteValidatorBalanceExporterScript(): exporterScript,
+	} {
+		if err := host.UploadBytes(content, remoteFile, constants.SSHFileOpsTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func RunSSHSetupLokiConfig(host *models.Host, port int) error {
 	for _, folder := range remoteconfig.LokiFoldersToCreate() {
 		if err := host.MkdirAll(folder, constants.SSHDirOpsTimeout); err != nil {
@@ -454,7 +590,12 @@ func RunSSHSetupDevNet(host *models.Host, nodeInstanceDirPath string) error {
 	); err != nil {
 		return err
 	}
-	if err := docker.StopDockerCompose(host, constants.SSHLongRunningScriptTimeout); err != nil {
+	binaryNode := IsBinaryNode(host)
+	if binaryNode {
+		if err := runBinaryNodeService(host, "stop"); err != nil {
+			return err
+		}
+	} else if err := docker.StopDockerCompose(host, constants.SSHLongRunningScriptTimeout); err != nil {
 		return err
 	}
 	if err := host.Remove("/home/ubuntu/.avalanchego/db", true); err != nil {
@@ -469,6 +610,9 @@ func RunSSHSetupDevNet(host *models.Host, nodeInstanceDirPath string) error {
 	if err := host.MkdirAll("/home/ubuntu/.avalanchego/logs", constants.SSHDirOpsTimeout); err != nil {
 		return err
 	}
+	if binaryNode {
+		return runBinaryNodeService(host, "start")
+	}
 	return docker.StartDockerCompose(host, constants.SSHLongRunningScriptTimeout)
 }
 
@@ -604,6 +748,56 @@ func RunSSHRenderAvalancheNodeConfig(
 	return host.UploadBytes(nodeConf, remoteconfig.GetRemoteAvalancheNodeConfig(), constants.SSHFileOpsTimeout)
 }
 
+// RunSSHApplyHostNodeConfig applies the AvalancheGo settings overridden for [host]: extra flags
+// and database dir are merged into its node config, the database dir is created, and resource
+// limits are set on the avalanchego service, removing the ones no longer set. Changes take
+// effect the next time avalanchego starts
+func RunSSHApplyHostNodeConfig(host *models.Host, hostNodeConfig models.HostNodeConfig) error {
+	if utils.IsE2E() && utils.E2EDocker() {
+		return nil
+	}
+	if len(hostNodeConfig.Flags) > 0 || hostNodeConfig.DBDir != "" {
+		avagoConf, err := getAvalancheGoConfigData(host)
+		if err != nil {
+			return err
+		}
+		applyHostNodeConfig(avagoConf, hostNodeConfig)
+		nodeConf, err := json.MarshalIndent(avagoConf, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := host.UploadBytes(nodeConf, remoteconfig.GetRemoteAvalancheNodeConfig(), constants.SSHFileOpsTimeout); err != nil {
+			return err
+		}
+	}
+	if hostNodeConfig.DBDir != "" {
+		if output, err := host.Command(
+			fmt.Sprintf("sudo mkdir -p %s && sudo chown $(id -u):$(id -g) %s", hostNodeConfig.DBDir, hostNodeConfig.DBDir),
+			nil,
+			constants.SSHFileOpsTimeout,
+		); err != nil {
+			return fmt.Errorf("%w: %s", err, string(output))
+		}
+	}
+	if IsBinaryNode(host) {
+		if output, err := host.Command(binaryNodeLimitsCommand(hostNodeConfig), nil, constants.SSHScriptTimeout); err != nil {
+			return fmt.Errorf("%w: %s", err, string(output))
+		}
+		return nil
+	}
+	return docker.ComposeSSHSetupNodeOverrides(host, hostNodeConfig)
+}
+
+// applyHostNodeConfig merges the extra flags and database dir of [hostNodeConfig] into the
+// avalanchego config [avagoConf]. The database dir is set at the same path for binary and
+// docker nodes, as docker nodes mount it at the host path
+func applyHostNodeConfig(avagoConf map[string]interface{}, hostNodeConfig models.HostNodeConfig) {
+	maps.Copy(avagoConf, hostNodeConfig.Flags)
+	if hostNodeConfig.DBDir != "" {
+		avagoConf[config.DBPathKey] = hostNodeConfig.DBDir
+	}
+}
+
 // RunSSHCreatePlugin runs script to create plugin
 func RunSSHCreatePlugin(host *models.Host, sc models.Sidecar) error {
 	vmID, err := sc.GetVMID()
@@ -671,31 +865,40 @@ func RunSSHCreatePlugin(host *models.Host, sc models.Sidecar) error {
 
 // RunSSHMergeSubnetNodeConfig merges subnet node config to the node config on the remote host
 func mergeSubnetNodeConfig(host *models.Host, subnetNodeConfigPath string) error {
+	mergedNodeConfigBytes, err := getMergedSubnetNodeConfig(host, subnetNodeConfigPath)
+	if err != nil {
+		return err
+	}
+	return host.UploadBytes(mergedNodeConfigBytes, remoteconfig.GetRemoteAvalancheNodeConfig(), constants.SSHFileOpsTimeout)
+}
+
+// getMergedSubnetNodeConfig returns the remote node config with the subnet node config merged into it
+func getMergedSubnetNodeConfig(host *models.Host, subnetNodeConfigPath string) ([]byte, error) {
 	if subnetNodeConfigPath == "" {
-		return fmt.Errorf("node config path is empty")
+		return nil, fmt.Errorf("node config path is empty")
 	}
 	remoteNodeConfigBytes, err := host.ReadFileBytes(remoteconfig.GetRemoteAvalancheNodeConfig(), constants.SSHFileOpsTimeout)
 	if err != nil {
-		return fmt.Errorf("error reading remote node config: %w", err)
+		return nil, fmt.Errorf("error reading remote node config: %w", err)
 	}
 	var remoteNodeConfig map[string]interface{}
 	if err := json.Unmarshal(remoteNodeConfigBytes, &remoteNodeConfig); err != nil {
-		return fmt.Errorf("error unmarshalling remote node config: %w", err)
+		return nil, fmt.Errorf("error unmarshalling remote node config: %w", err)
 	}
 	subnetNodeConfigBytes, err := os.ReadFile(subnetNodeConfigPath)
 	if err != nil {
-		return fmt.Errorf("error reading node config: %w", err)
+		return nil, fmt.Errorf("error reading node config: %w", err)
 	}
 	var subnetNodeConfig map[string]interface{}
 	if err := json.Unmarshal(subnetNodeConfigBytes, &subnetNodeConfig); err != nil {
-		return fmt.Errorf("error unmarshalling node config: %w", err)
+		return nil, fmt.Errorf("error unmarshalling node config: %w", err)
 	}
 	maps.Copy(remoteNodeConfig, subnetNodeConfig) // merge remote config into local subnet config. subnetNodeConfig takes precedence
 	mergedNodeConfigBytes, err := json.MarshalIndent(remoteNodeConfig, "", " ")
 	if err != nil {
-		return fmt.Errorf("error creating merged node config: %w", err)
+		return nil, fmt.Errorf("error creating merged node config: %w", err)
 	}
-	return host.UploadBytes(mergedNodeConfigBytes, remoteconfig.GetRemoteAvalancheNodeConfig(), constants.SSHFileOpsTimeout)
+	return mergedNodeConfigBytes, nil
 }
 
 // RunSSHSyncSubnetData syncs subnet data required
@@ -758,8 +961,8 @@ func RunSSHSyncSubnetData(app *application.Avalanche, host *models.Host, network
 	// end chain config
 
 	// network upgrade
-	if app.NetworkUpgradeExists(subnetName) {
-		networkUpgrades, err := app.LoadRawNetworkUpgrades(subnetName)
+	if networkUpgradesFile := app.GetNetworkUpgradeSetFilePath(sc, network.Name()); utils.FileExists(networkUpgradesFile) {
+		networkUpgrades, err := os.ReadFile(networkUpgradesFile)
 		if err != nil {
 			return fmt.Errorf("error loading network upgrades: %w", err)
 		}
@@ -776,6 +979,108 @@ func RunSSHSyncSubnetData(app *application.Avalanche, host *models.Host, network
 	return nil
 }
 
+// RunSSHUpdateBlockchainConfigs uploads the node config, subnet config and chain config of
+// [subnetName] to the host. If [perNodeChainConfig] is not nil, it is used as the chain config
+// of the host. Only files whose content differs from the remote ones are uploaded.
+// Returns true if any file on the host was changed
+func RunSSHUpdateBlockchainConfigs(
+	app *application.Avalanche,
+	host *models.Host,
+	network models.Network,
+	subnetName string,
+	perNodeChainConfig []byte,
+) (bool, error) {
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return false, err
+	}
+	subnetID := sc.Networks[network.Name()].SubnetID
+	if subnetID == ids.Empty {
+		return false, errors.New("subnet id is empty")
+	}
+	blockchainID := sc.Networks[network.Name()].BlockchainID
+	if blockchainID == ids.Empty {
+		return false, errors.New("blockchain id is empty")
+	}
+	changed := false
+	// subnet node config
+	subnetNodeConfigPath := app.GetAvagoNodeConfigPath(subnetName)
+	if utils.FileExists(subnetNodeConfigPath) {
+		mergedNodeConfig, err := getMergedSubnetNodeConfig(host, subnetNodeConfigPath)
+		if err != nil {
+			return false, err
+		}
+		uploaded, err := uploadIfChanged(host, mergedNodeConfig, remoteconfig.GetRemoteAvalancheNodeConfig())
+		if err != nil {
+			return false, err
+		}
+		changed = changed || uploaded
+	}
+	// subnet config
+	if app.AvagoSubnetConfigExists(subnetName) {
+		subnetConfig, err := app.LoadRawAvagoSubnetConfig(subnetName)
+		if err != nil {
+			return false, fmt.Errorf("error loading blockchain config: %w", err)
+		}
+		subnetConfigPath := filepath.Join(constants.CloudNodeConfigPath, "subnets", subnetID.String()+".json")
+		uploaded, err := uploadIfChanged(host, subnetConfig, subnetConfigPath)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || uploaded
+	}
+	// chain config
+	chainConfig := perNodeChainConfig
+	if chainConfig == nil && app.ChainConfigExists(subnetName) {
+		chainConfig, err = app.LoadRawChainConfig(subnetName)
+		if err != nil {
+			return false, fmt.Errorf("error loading chain config: %w", err)
+		}
+	}
+	if chainConfig != nil {
+		chainConfigPath := filepath.Join(constants.CloudNodeConfigPath, "chains", blockchainID.String(), "config.json")
+		uploaded, err := uploadIfChanged(host, chainConfig, chainConfigPath)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || uploaded
+	}
+	return changed, nil
+}
+
+// uploadIfChanged uploads [data] to [remoteFile] only if the remote file does not exist
+// or has a different content. JSON contents are compared semantically
+func uploadIfChanged(host *models.Host, data []byte, remoteFile string) (bool, error) {
+	exists, err := host.FileExists(remoteFile)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		remoteData, err := host.ReadFileBytes(remoteFile, constants.SSHFileOpsTimeout)
+		if err != nil {
+			return false, fmt.Errorf("error reading %s: %w", remoteFile, err)
+		}
+		if sameFileContent(remoteData, data) {
+			return false, nil
+		}
+	}
+	if err := host.MkdirAll(filepath.Dir(remoteFile), constants.SSHDirOpsTimeout); err != nil {
+		return false, err
+	}
+	if err := host.UploadBytes(data, remoteFile, constants.SSHFileOpsTimeout); err != nil {
+		return false, fmt.Errorf("error uploading %s: %w", remoteFile, err)
+	}
+	return true, nil
+}
+
+func sameFileContent(a []byte, b []byte) bool {
+	var aJSON, bJSON interface{}
+	if json.Unmarshal(a, &aJSON) == nil && json.Unmarshal(b, &bJSON) == nil {
+		return reflect.DeepEqual(aJSON, bJSON)
+	}
+	return bytes.Equal(bytes.TrimSpace(a), bytes.TrimSpace(b))
+}
+
 func RunSSHBuildLoadTestCode(host *models.Host, loadTestRepo, loadTestPath, loadTestGitCommit, repoDirName, loadTestBranch string, checkoutCommit bool) error {
 	return StreamOverSSH(
 		"Build Load Test",
@@ -828,6 +1133,13 @@ func RunSSHCheckBootstrapped(host *models.Host) ([]byte, error) {
 	return PostOverSSH(host, "", requestBody)
 }
 
+// RunSSHCheckChainBootstrapped checks if node is bootstrapped to [chain]
+func RunSSHCheckChainBootstrapped(host *models.Host, chain string) ([]byte, error) {
+	// Craft and send the HTTP POST request
+	requestBody := fmt.Sprintf("{\"jsonrpc\":\"2.0\", \"id\":1,\"method\" :\"info.isBootstrapped\", \"params\": {\"chain\":\"%s\"}}", chain)
+	return PostOverSSH(host, "", requestBody)
+}
+
 // RunSSHCheckHealthy checks if node is healthy
 func RunSSHCheckHealthy(host *models.Host) ([]byte, error) {
 	// Craft and send the HTTP POST request
@@ -849,6 +1161,20 @@ func RunSSHSubnetSyncStatus(host *models.Host, blockchainID string) ([]byte, err
 	return PostOverSSH(host, "/ext/bc/P", requestBody)
 }
 
+// RunSSHChainSyncProgress gets the eth_syncing status of an EVM chain on the node
+func RunSSHChainSyncProgress(host *models.Host, chain string) ([]byte, error) {
+	// Craft and send the HTTP POST request
+	requestBody := "{\"jsonrpc\":\"2.0\", \"id\":1,\"method\" :\"eth_syncing\", \"params\": []}"
+	return PostOverSSH(host, fmt.Sprintf("/ext/bc/%s/rpc", chain), requestBody)
+}
+
+// RunSSHChainBlockNumber gets the last accepted block number of an EVM chain on the node
+func RunSSHChainBlockNumber(host *models.Host, chain string) ([]byte, error) {
+	// Craft and send the HTTP POST request
+	requestBody := "{\"jsonrpc\":\"2.0\", \"id\":1,\"method\" :\"eth_blockNumber\", \"params\": []}"
+	return PostOverSSH(host, fmt.Sprintf("/ext/bc/%s/rpc", chain), requestBody)
+}
+
 // StreamOverSSH runs provided script path over ssh.
 // This script can be template as it will be rendered using scriptInputs vars
 func StreamOverSSH(
@@ -968,3 +1294,33 @@ func getAvalancheGoAliasData(host *models.Host) (map[string][]string, error) {
 	}
 	return aliases, nil
 }
+
+// RunSSHBackupChainData backs up the avalanchego database and the chain data dir of [blockchainID]
+// into a [backupName] dir under the node backups path, and returns the backup dir.
+// The node must be stopped. If [reset] is set, data is moved instead of copied, so the node
+// resyncs on next start. Note that all chains share the avalanchego database, so a reset
+// also resyncs the primary network
+func RunSSHBackupChainData(host *models.Host, blockchainID string, backupName string, reset bool) (string, error) {
+	backupDir := filepath.Join(constants.CloudNodeBackupsPath, backupName)
+	if err := host.MkdirAll(backupDir, constants.SSHFileOpsTimeout); err != nil {
+		return "", err
+	}
+	op := "cp -a"
+	if reset {
+		op = "mv"
+	}
+	for _, dataPath := range []string{
+		constants.CloudNodeDBPath,
+		filepath.Join(constants.CloudNodeChainDataPath, blockchainID),
+	} {
+		dataPath = strings.TrimSuffix(dataPath, "/")
+		if _, err := host.Command(
+			fmt.Sprintf("if [ -e %s ]; then %s %s %s/; fi", dataPath, op, dataPath, backupDir),
+			nil,
+			constants.SSHLongRunningScriptTimeout,
+		); err != nil {
+			return "", fmt.Errorf("failed to backup %s: %w", dataPath, err)
+		}
+	}
+	return backupDir, nil
+}
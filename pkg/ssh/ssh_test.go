@@ -5,9 +5,12 @@ package ssh
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
 )
 
 func TestReplaceCustomVarDashboardValues(t *testing.T) {
@@ -97,3 +100,64 @@ func TestReplaceCustomVarDashboardValues(t *testing.T) {
 		t.Errorf("Expected content after replacement:\n%s\nGot:\n%s", expectedContent, string(modifiedContent))
 	}
 }
+
+func TestSameFileContent(t *testing.T) {
+	if !sameFileContent([]byte(`{"a": 1, "b": "x"}`), []byte("{\n  \"b\": \"x\",\n  \"a\": 1\n}\n")) {
+		t.Errorf("expected equivalent JSON contents to be equal")
+	}
+	if sameFileContent([]byte(`{"a": 1}`), []byte(`{"a": 2}`)) {
+		t.Errorf("expected different JSON contents to differ")
+	}
+	if !sameFileContent([]byte("key=value\n"), []byte("key=value")) {
+		t.Errorf("expected non JSON contents to be compared ignoring surrounding spaces")
+	}
+}
+
+func TestParseRemoteArch(t *testing.T) {
+	for unameOutput, expected := range map[string]string{
+		"x86_64\n":  "amd64",
+		"aarch64\n": "arm64",
+		"arm64":     "arm64",
+	} {
+		goarch, err := parseRemoteArch(unameOutput)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", unameOutput, err)
+		}
+		if goarch != expected {
+			t.Errorf("expected %s for %q, got %s", expected, unameOutput, goarch)
+		}
+	}
+	if _, err := parseRemoteArch("riscv64\n"); err == nil {
+		t.Errorf("expected unsupported architecture to fail")
+	}
+}
+
+func TestApplyHostNodeConfig(t *testing.T) {
+	avagoConf := map[string]interface{}{
+		"db-dir":    "/.avalanchego/db/",
+		"log-level": "info",
+	}
+	applyHostNodeConfig(avagoConf, models.HostNodeConfig{
+		Flags: map[string]interface{}{"log-level": "debug", "throttler-inbound-cpu-max-recheck-delay": float64(5)},
+		DBDir: "/mnt/nvme/avalanchego",
+	})
+	expected := map[string]interface{}{
+		"db-dir":    "/mnt/nvme/avalanchego",
+		"log-level": "debug",
+		"throttler-inbound-cpu-max-recheck-delay": float64(5),
+	}
+	if !reflect.DeepEqual(avagoConf, expected) {
+		t.Errorf("expected %v, got %v", expected, avagoConf)
+	}
+}
+
+func TestBinaryNodeLimitsCommand(t *testing.T) {
+	cmd := binaryNodeLimitsCommand(models.HostNodeConfig{CPUs: 1.5, Memory: "16G"})
+	if !strings.Contains(cmd, "'[Service]' 'CPUQuota=150%' 'MemoryMax=16G'") {
+		t.Errorf("unexpected limits command %q", cmd)
+	}
+	cmd = binaryNodeLimitsCommand(models.HostNodeConfig{DBDir: "/mnt/db"})
+	if !strings.Contains(cmd, "sudo rm -f "+binaryNodeLimitsDropIn) {
+		t.Errorf("expected limits drop-in to be removed, got %q", cmd)
+	}
+}
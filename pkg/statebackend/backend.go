@@ -0,0 +1,119 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package statebackend syncs the local bookkeeping of clusters (cluster definitions, node
+// configs, inventories and deployment records) with a bucket shared by a team, so several
+// people can manage the same cluster from different machines. Changes to a cluster are made
+// under a lock object stored in the same bucket
+package statebackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	s3Scheme  = "s3"
+	gcsScheme = "gs"
+
+	s3RegionQueryKey   = "region"
+	s3EndpointQueryKey = "endpoint"
+	s3ProfileQueryKey  = "profile"
+)
+
+// SupportedURLs documents the accepted formats of the state backend URL
+var SupportedURLs = []string{
+	"s3://bucket[/prefix][?region=us-east-1&profile=default&endpoint=https://s3-compatible-host]",
+	"gs://bucket[/prefix]",
+}
+
+var (
+	ErrNotFound = errors.New("object not found")
+	ErrExists   = errors.New("object already exists")
+	ErrChanged  = errors.New("object changed")
+)
+
+// Backend is a bucket holding the shared state objects
+type Backend interface {
+	// Get returns the content of [key], or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// GetVersion returns the content of [key] together with its version, or ErrNotFound. The
+	// version is opaque, and only meant to be given to DeleteIfVersion
+	GetVersion(ctx context.Context, key string) ([]byte, string, error)
+	// Put sets the content of [key]
+	Put(ctx context.Context, key string, data []byte) error
+	// PutIfAbsent sets the content of [key] only if it does not exist, failing with ErrExists
+	// otherwise. The check is atomic, so it can be used to acquire locks
+	PutIfAbsent(ctx context.Context, key string, data []byte) error
+	// Delete removes [key]. Removing a missing key is not an error
+	Delete(ctx context.Context, key string) error
+	// DeleteIfVersion removes [key] only if it is still at [version], failing with ErrChanged
+	// otherwise. The check is atomic, so it can be used to release locks. Removing a missing
+	// key is not an error
+	DeleteIfVersion(ctx context.Context, key string, version string) error
+}
+
+// backendURL is a parsed state backend URL
+type backendURL struct {
+	scheme string
+	bucket string
+	prefix string
+	query  url.Values
+}
+
+// ValidateURL checks that [backendURL] is a supported state backend URL
+func ValidateURL(backendURL string) error {
+	_, err := parseURL(backendURL)
+	return err
+}
+
+func parseURL(rawURL string) (backendURL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return backendURL{}, fmt.Errorf("invalid state backend URL %q: %w", rawURL, err)
+	}
+	if u.Scheme != s3Scheme && u.Scheme != gcsScheme {
+		return backendURL{}, fmt.Errorf("unsupported state backend scheme %q, expected one of %v", u.Scheme, SupportedURLs)
+	}
+	if u.Host == "" {
+		return backendURL{}, fmt.Errorf("invalid state backend URL %q: missing bucket, expected one of %v", rawURL, SupportedURLs)
+	}
+	if u.Scheme == gcsScheme && len(u.Query()) > 0 {
+		return backendURL{}, fmt.Errorf("invalid state backend URL %q: gs URLs don't accept parameters", rawURL)
+	}
+	if endpoint := u.Query().Get(s3EndpointQueryKey); endpoint != "" {
+		if endpointURL, err := url.Parse(endpoint); err != nil || endpointURL.Host == "" {
+			return backendURL{}, fmt.Errorf("invalid state backend endpoint %q", endpoint)
+		}
+	}
+	return backendURL{
+		scheme: u.Scheme,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+		query:  u.Query(),
+	}, nil
+}
+
+// key returns the object key of [elems] under the URL prefix
+func (u backendURL) key(elems ...string) string {
+	return path.Join(append([]string{u.prefix}, elems...)...)
+}
+
+// New returns the backend of [backendURL], using the default credentials of the cloud
+// provider (AWS shared config and env variables, or Google application default credentials)
+func New(ctx context.Context, rawURL string) (Backend, error) {
+	u, err := parseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.scheme {
+	case s3Scheme:
+		return newS3Backend(ctx, u)
+	default:
+		return newGCSBackend(ctx, u)
+	}
+}
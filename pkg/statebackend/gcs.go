@@ -0,0 +1,113 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statebackend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+)
+
+// gcsBackend reaches the bucket through the Cloud Storage JSON API. Lock objects rely on
+// the ifGenerationMatch precondition: 0 only succeeds if the object does not exist, and the
+// object generation, used as its version, only if it was not changed
+type gcsBackend struct {
+	url     backendURL
+	service *storage.Service
+}
+
+func newGCSBackend(ctx context.Context, u backendURL, options ...option.ClientOption) (*gcsBackend, error) {
+	service, err := storage.NewService(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating Cloud Storage client for the state backend: %w", err)
+	}
+	return &gcsBackend{
+		url:     u,
+		service: service,
+	}, nil
+}
+
+// gcsGenerationHeader holds the generation of the object on a download
+const gcsGenerationHeader = "X-Goog-Generation"
+
+func (b *gcsBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	data, _, err := b.GetVersion(ctx, key)
+	return data, err
+}
+
+func (b *gcsBackend) GetVersion(ctx context.Context, key string) ([]byte, string, error) {
+	response, err := b.service.Objects.Get(b.url.bucket, b.url.key(key)).Context(ctx).Download()
+	if err != nil {
+		return nil, "", gcsError(err)
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, response.Header.Get(gcsGenerationHeader), nil
+}
+
+func (b *gcsBackend) Put(ctx context.Context, key string, data []byte) error {
+	_, err := b.insert(ctx, key, data).Do()
+	return gcsError(err)
+}
+
+func (b *gcsBackend) PutIfAbsent(ctx context.Context, key string, data []byte) error {
+	_, err := b.insert(ctx, key, data).IfGenerationMatch(0).Do()
+	return gcsError(err)
+}
+
+func (b *gcsBackend) Delete(ctx context.Context, key string) error {
+	return b.delete(b.service.Objects.Delete(b.url.bucket, b.url.key(key)).Context(ctx))
+}
+
+func (b *gcsBackend) DeleteIfVersion(ctx context.Context, key string, version string) error {
+	generation, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid generation %q of object %s: %w", version, key, err)
+	}
+	err = b.delete(b.service.Objects.Delete(b.url.bucket, b.url.key(key)).IfGenerationMatch(generation).Context(ctx))
+	if errors.Is(err, ErrExists) {
+		return ErrChanged
+	}
+	return err
+}
+
+func (*gcsBackend) delete(call *storage.ObjectsDeleteCall) error {
+	err := gcsError(call.Do())
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (b *gcsBackend) insert(ctx context.Context, key string, data []byte) *storage.ObjectsInsertCall {
+	object := &storage.Object{
+		Name:        b.url.key(key),
+		ContentType: "application/json",
+	}
+	return b.service.Objects.Insert(b.url.bucket, object).Media(bytes.NewReader(data)).Context(ctx)
+}
+
+// gcsError maps the Cloud Storage API errors to the backend ones
+func gcsError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case http.StatusNotFound:
+			return ErrNotFound
+		case http.StatusPreconditionFailed:
+			return ErrExists
+		}
+	}
+	return err
+}
@@ -0,0 +1,299 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statebackend
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"go.uber.org/zap"
+)
+
+const lockFileName = "lock.json"
+
+// Lock is held by whoever is changing the state of a cluster. It expires at ExpiresAt unless
+// renewed by its owner, so a lock left by a killed command does not block the cluster forever
+type Lock struct {
+	ID         string
+	Owner      string // user@hostname
+	Command    string
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+
+	// version of the lock object it was read from, if any
+	version string
+}
+
+func (l Lock) String() string {
+	return fmt.Sprintf("%s since %s (%s)", l.Owner, l.AcquiredAt.Local().Format(time.RFC1123), l.Command)
+}
+
+// Expired returns true if the lock was not renewed in time. Locks written by older versions
+// of the CLI, that have no expiration, expire after the lock TTL
+func (l Lock) Expired() bool {
+	expiresAt := l.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = l.AcquiredAt.Add(constants.StateBackendLockTTL)
+	}
+	return time.Now().After(expiresAt)
+}
+
+func lockKey(clusterName string) string {
+	return clustersKey + "/" + clusterName + "/" + lockFileName
+}
+
+// owner identifies the user of this machine in locks and states
+func owner() string {
+	userName := "unknown"
+	if u, err := user.Current(); err == nil {
+		userName = u.Username
+	}
+	hostName, err := os.Hostname()
+	if err != nil {
+		hostName = "unknown"
+	}
+	return userName + "@" + hostName
+}
+
+// AcquireLock locks [clusterName] on [backend] for [command], for the lock TTL. It fails if the
+// cluster is already locked, including by a previous command of this same machine, unless
+// that lock expired, in which case it is taken over
+func AcquireLock(ctx context.Context, backend Backend, clusterName string, command string) (Lock, error) {
+	lockID := make([]byte, 16)
+	if _, err := rand.Read(lockID); err != nil {
+		return Lock{}, err
+	}
+	now := time.Now().UTC()
+	lock := Lock{
+		ID:         hex.EncodeToString(lockID),
+		Owner:      owner(),
+		Command:    command,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(constants.StateBackendLockTTL),
+	}
+	lockBytes, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return Lock{}, err
+	}
+	err = backend.PutIfAbsent(ctx, lockKey(clusterName), lockBytes)
+	if errors.Is(err, ErrExists) {
+		currentLock, found, getErr := GetLock(ctx, backend, clusterName)
+		if getErr != nil {
+			return Lock{}, fmt.Errorf("cluster %s is locked by another user", clusterName)
+		}
+		if found && !currentLock.Expired() {
+			return Lock{}, fmt.Errorf(
+				"cluster %s is locked by %s. If the lock is stale, remove it with avalanche node state unlock %s",
+				clusterName,
+				currentLock,
+				clusterName,
+			)
+		}
+		if found {
+			ux.Logger.PrintToUser("Taking over expired lock of cluster %s held by %s", clusterName, currentLock)
+			// only the expired lock is removed, not one that replaced it meanwhile
+			err := backend.DeleteIfVersion(ctx, lockKey(clusterName), currentLock.version)
+			if errors.Is(err, ErrChanged) {
+				return Lock{}, fmt.Errorf("cluster %s was locked concurrently by another user", clusterName)
+			}
+			if err != nil {
+				return Lock{}, fmt.Errorf("failure removing expired lock of cluster %s: %w", clusterName, err)
+			}
+		}
+		err = backend.PutIfAbsent(ctx, lockKey(clusterName), lockBytes)
+		if errors.Is(err, ErrExists) {
+			return Lock{}, fmt.Errorf("cluster %s was locked concurrently by another user", clusterName)
+		}
+	}
+	if err != nil {
+		return Lock{}, fmt.Errorf("failure locking cluster %s: %w", clusterName, err)
+	}
+	return lock, nil
+}
+
+// RenewLock extends the expiration of [lock] of [clusterName] by the lock TTL. It fails if the
+// lock is no longer held, eg it expired and was taken over by another user
+func RenewLock(ctx context.Context, backend Backend, clusterName string, lock Lock) (Lock, error) {
+	currentLock, found, err := GetLock(ctx, backend, clusterName)
+	if err != nil {
+		return Lock{}, err
+	}
+	if !found || currentLock.ID != lock.ID {
+		return Lock{}, fmt.Errorf("lock of cluster %s was lost", clusterName)
+	}
+	lock.ExpiresAt = time.Now().UTC().Add(constants.StateBackendLockTTL)
+	lockBytes, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return Lock{}, err
+	}
+	if err := backend.Put(ctx, lockKey(clusterName), lockBytes); err != nil {
+		return Lock{}, fmt.Errorf("failure renewing lock of cluster %s: %w", clusterName, err)
+	}
+	return lock, nil
+}
+
+// GetLock returns the lock of [clusterName], if any
+func GetLock(ctx context.Context, backend Backend, clusterName string) (Lock, bool, error) {
+	lockBytes, version, err := backend.GetVersion(ctx, lockKey(clusterName))
+	if errors.Is(err, ErrNotFound) {
+		return Lock{}, false, nil
+	}
+	if err != nil {
+		return Lock{}, false, err
+	}
+	var lock Lock
+	if err := json.Unmarshal(lockBytes, &lock); err != nil {
+		return Lock{}, false, fmt.Errorf("invalid lock of cluster %s: %w", clusterName, err)
+	}
+	lock.version = version
+	return lock, true, nil
+}
+
+// ReleaseLock removes [lock] of [clusterName] from [backend]. It fails if the lock is no
+// longer held, eg it expired and was taken over by another user, leaving the new lock in place
+func ReleaseLock(ctx context.Context, backend Backend, clusterName string, lock Lock) error {
+	currentLock, found, err := GetLock(ctx, backend, clusterName)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	if currentLock.ID != lock.ID {
+		return fmt.Errorf("lock of cluster %s was lost", clusterName)
+	}
+	err = backend.DeleteIfVersion(ctx, lockKey(clusterName), currentLock.version)
+	if errors.Is(err, ErrChanged) {
+		return fmt.Errorf("lock of cluster %s was lost", clusterName)
+	}
+	if err != nil {
+		return fmt.Errorf("failure unlocking cluster %s: %w", clusterName, err)
+	}
+	return nil
+}
+
+// Configured returns true if a state backend is set on the CLI config
+func Configured(app *application.Avalanche) bool {
+	return app.Conf.GetConfigStringValue(constants.ConfigStateBackendKey) != ""
+}
+
+// NewFromConfig returns the state backend set on the CLI config
+func NewFromConfig(ctx context.Context, app *application.Avalanche) (Backend, error) {
+	backendURL := app.Conf.GetConfigStringValue(constants.ConfigStateBackendKey)
+	if backendURL == "" {
+		return nil, errors.New("no state backend configured, set one with avalanche config state-backend")
+	}
+	return New(ctx, backendURL)
+}
+
+// Session is a change to the local state of a cluster, made under the cluster lock, that is
+// pushed to the state backend when it ends. The lock is renewed while the session lasts
+type Session struct {
+	app         *application.Avalanche
+	backend     Backend
+	clusterName string
+	lock        Lock
+	stop        chan struct{}
+	stopped     chan struct{}
+}
+
+// Begin locks [clusterName] on the state backend set on the CLI config, and replaces its local
+// state with the shared one. It returns nil if no state backend is configured
+func Begin(app *application.Avalanche, clusterName string, command string) (*Session, error) {
+	if !Configured(app) {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.StateBackendTimeout)
+	defer cancel()
+	backend, err := NewFromConfig(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := AcquireLock(ctx, backend, clusterName, command)
+	if err != nil {
+		return nil, err
+	}
+	pulled, err := Pull(ctx, app, backend, clusterName)
+	if err != nil {
+		if releaseErr := ReleaseLock(ctx, backend, clusterName, lock); releaseErr != nil {
+			app.Log.Error("failure releasing cluster lock", zap.String("cluster", clusterName), zap.Error(releaseErr))
+		}
+		return nil, err
+	}
+	if pulled {
+		ux.Logger.Info("Cluster %s state pulled from the state backend", clusterName)
+	}
+	s := &Session{
+		app:         app,
+		backend:     backend,
+		clusterName: clusterName,
+		lock:        lock,
+		stop:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+	go s.renewLock(constants.StateBackendLockRenewal)
+	return s, nil
+}
+
+// renewLock renews the session lock every [interval], until the session ends
+func (s *Session) renewLock(interval time.Duration) {
+	defer close(s.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), constants.StateBackendTimeout)
+			lock, err := RenewLock(ctx, s.backend, s.clusterName, s.lock)
+			cancel()
+			if err == nil {
+				s.lock = lock
+			}
+			if err != nil {
+				s.app.Log.Error("failure renewing cluster lock", zap.String("cluster", s.clusterName), zap.Error(err))
+			}
+		}
+	}
+}
+
+// End pushes the local state of the cluster to the state backend, if [push] is set, and
+// releases the cluster lock. The lock is released even if the push fails. Nothing is pushed
+// if the lock was lost, as the state could have been changed meanwhile by its new owner
+func (s *Session) End(push bool) error {
+	close(s.stop)
+	<-s.stopped
+	ctx, cancel := context.WithTimeout(context.Background(), constants.StateBackendTimeout)
+	defer cancel()
+	currentLock, found, err := GetLock(ctx, s.backend, s.clusterName)
+	if err != nil {
+		return err
+	}
+	if !found || currentLock.ID != s.lock.ID {
+		return fmt.Errorf(
+			"lock of cluster %s expired and was taken over, so its local state was not pushed. Check the shared state and push it with avalanche node state push %s",
+			s.clusterName,
+			s.clusterName,
+		)
+	}
+	var pushErr error
+	if push {
+		pushErr = Push(ctx, s.app, s.backend, s.clusterName)
+	}
+	releaseErr := ReleaseLock(ctx, s.backend, s.clusterName, s.lock)
+	if pushErr != nil {
+		return pushErr
+	}
+	return releaseErr
+}
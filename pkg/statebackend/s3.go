@@ -0,0 +1,143 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statebackend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const s3DefaultRegion = "us-east-1"
+
+// s3Backend reaches the bucket through the S3 API. Lock objects rely on S3 conditional
+// writes (If-None-Match) and deletes (If-Match on the ETag), also supported by most S3
+// compatible stores
+type s3Backend struct {
+	url    backendURL
+	client *s3.Client
+}
+
+func newS3Backend(ctx context.Context, u backendURL) (*s3Backend, error) {
+	options := []func(*config.LoadOptions) error{}
+	if region := u.query.Get(s3RegionQueryKey); region != "" {
+		options = append(options, config.WithRegion(region))
+	}
+	if profile := u.query.Get(s3ProfileQueryKey); profile != "" {
+		options = append(options, config.WithSharedConfigProfile(profile))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failure loading AWS credentials for the state backend: %w", err)
+	}
+	return newS3BackendWithConfig(u, cfg), nil
+}
+
+func newS3BackendWithConfig(u backendURL, cfg aws.Config) *s3Backend {
+	if cfg.Region == "" {
+		cfg.Region = s3DefaultRegion
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		// S3 compatible stores are reached with path style URLs, AWS with virtual hosted ones
+		if endpoint := u.query.Get(s3EndpointQueryKey); endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return &s3Backend{
+		url:    u,
+		client: client,
+	}
+}
+
+func (b *s3Backend) Get(ctx context.Context, key string) ([]byte, error) {
+	data, _, err := b.GetVersion(ctx, key)
+	return data, err
+}
+
+func (b *s3Backend) GetVersion(ctx context.Context, key string) ([]byte, string, error) {
+	output, err := b.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.url.bucket),
+		Key:    aws.String(b.url.key(key)),
+	})
+	if err != nil {
+		return nil, "", s3Error(err)
+	}
+	defer output.Body.Close()
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, aws.ToString(output.ETag), nil
+}
+
+func (b *s3Backend) Put(ctx context.Context, key string, data []byte) error {
+	_, err := b.client.PutObject(ctx, b.putInput(key, data))
+	return s3Error(err)
+}
+
+func (b *s3Backend) PutIfAbsent(ctx context.Context, key string, data []byte) error {
+	input := b.putInput(key, data)
+	input.IfNoneMatch = aws.String("*")
+	_, err := b.client.PutObject(ctx, input)
+	return s3Error(err)
+}
+
+func (b *s3Backend) Delete(ctx context.Context, key string) error {
+	return b.delete(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.url.bucket),
+		Key:    aws.String(b.url.key(key)),
+	})
+}
+
+func (b *s3Backend) DeleteIfVersion(ctx context.Context, key string, version string) error {
+	err := b.delete(ctx, &s3.DeleteObjectInput{
+		Bucket:  aws.String(b.url.bucket),
+		Key:     aws.String(b.url.key(key)),
+		IfMatch: aws.String(version),
+	})
+	if errors.Is(err, ErrExists) {
+		return ErrChanged
+	}
+	return err
+}
+
+func (b *s3Backend) delete(ctx context.Context, input *s3.DeleteObjectInput) error {
+	_, err := b.client.DeleteObject(ctx, input)
+	err = s3Error(err)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (b *s3Backend) putInput(key string, data []byte) *s3.PutObjectInput {
+	return &s3.PutObjectInput{
+		Bucket:      aws.String(b.url.bucket),
+		Key:         aws.String(b.url.key(key)),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	}
+}
+
+// s3Error maps the S3 API errors to the backend ones
+func s3Error(err error) error {
+	var responseErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &responseErr) {
+		switch responseErr.HTTPStatusCode() {
+		case http.StatusNotFound:
+			return ErrNotFound
+		// 409 is returned when a concurrent conditional write to the same key is in progress
+		case http.StatusPreconditionFailed, http.StatusConflict:
+			return ErrExists
+		}
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statebackend
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The node keys are encrypted client side with AES-256-GCM, with a key derived from the state
+// passphrase with scrypt. The path of each key is authenticated, so keys can't be swapped
+// between nodes on the backend
+
+const (
+	secretsSaltSize = 16
+	scryptN         = 1 << 15
+	scryptR         = 8
+	scryptP         = 1
+	secretsKeySize  = 32
+)
+
+var errInvalidPassphrase = errors.New("invalid state passphrase, or the node keys of the state were tampered with")
+
+// statePassphrase returns the passphrase the node keys are encrypted with, if set
+func statePassphrase() string {
+	return os.Getenv(constants.StateBackendPassphraseEnvVarName)
+}
+
+func newSecretsCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, secretsKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealNodeKeys moves the node keys of [state] from its files to its secret files, encrypted
+// with the state passphrase. Without a passphrase, the keys are left out of the state
+func sealNodeKeys(state *ClusterState) error {
+	nodeKeys := map[string][]byte{}
+	for relPath, fileBytes := range state.Files {
+		if isNodeKeyPath(relPath) {
+			nodeKeys[relPath] = fileBytes
+			delete(state.Files, relPath)
+		}
+	}
+	passphrase := statePassphrase()
+	if passphrase == "" || len(nodeKeys) == 0 {
+		return nil
+	}
+	state.SecretsSalt = make([]byte, secretsSaltSize)
+	if _, err := rand.Read(state.SecretsSalt); err != nil {
+		return err
+	}
+	aead, err := newSecretsCipher(passphrase, state.SecretsSalt)
+	if err != nil {
+		return err
+	}
+	state.SecretFiles = map[string][]byte{}
+	for relPath, fileBytes := range nodeKeys {
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return err
+		}
+		state.SecretFiles[relPath] = aead.Seal(nonce, nonce, fileBytes, []byte(relPath))
+	}
+	return nil
+}
+
+// openNodeKeys returns the node keys of [state], decrypted with the state passphrase. Without
+// a passphrase, no key is returned, so the local ones are kept
+func openNodeKeys(state ClusterState) (map[string][]byte, error) {
+	if len(state.SecretFiles) == 0 {
+		return nil, nil
+	}
+	passphrase := statePassphrase()
+	if passphrase == "" {
+		ux.Logger.Info("Node keys of the cluster state not restored, as $%s is not set", constants.StateBackendPassphraseEnvVarName)
+		return nil, nil
+	}
+	aead, err := newSecretsCipher(passphrase, state.SecretsSalt)
+	if err != nil {
+		return nil, err
+	}
+	nodeKeys := map[string][]byte{}
+	for relPath, sealedBytes := range state.SecretFiles {
+		if len(sealedBytes) < aead.NonceSize() {
+			return nil, errInvalidPassphrase
+		}
+		nonce, ciphertext := sealedBytes[:aead.NonceSize()], sealedBytes[aead.NonceSize():]
+		fileBytes, err := aead.Open(nil, nonce, ciphertext, []byte(relPath))
+		if err != nil {
+			return nil, fmt.Errorf("failure decrypting %s: %w", relPath, errInvalidPassphrase)
+		}
+		nodeKeys[relPath] = fileBytes
+	}
+	return nodeKeys, nil
+}
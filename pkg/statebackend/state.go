@@ -0,0 +1,324 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statebackend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	sdkutils "github.com/ava-labs/avalanche-cli/sdk/utils"
+)
+
+const (
+	stateVersion  = "1"
+	stateFileName = "state.json"
+	clustersKey   = "clusters"
+	// prefix of the home dir on the machine independent paths of the state
+	homePrefix = "~/"
+)
+
+// nodeKeyFileNames are the staking and BLS keys of the nodes. They are only shared encrypted
+// with the state passphrase, and kept on the machine that created the nodes otherwise
+var nodeKeyFileNames = []string{constants.StakerKeyFileName, constants.BLSKeyFileName}
+
+// sshKeyPathRegex matches the SSH key paths of the hosts of the ansible inventory
+var sshKeyPathRegex = regexp.MustCompile(`(ansible_ssh_private_key_file=)(\S+)`)
+
+// ClusterState is the shared state of a cluster
+type ClusterState struct {
+	Version       string
+	UpdatedAt     time.Time
+	UpdatedBy     string
+	ClusterConfig models.ClusterConfig
+	// node configs, certs and inventories of the cluster, by slash separated path relative to
+	// the CLI base dir. SSH key paths under the home dir are relative to it
+	Files map[string][]byte
+	// node staking and BLS keys, encrypted with the state passphrase, by path
+	SecretFiles map[string][]byte
+	// salt of the state passphrase key
+	SecretsSalt []byte
+	// sidecars of the blockchains tracked by the cluster, holding their deployment records
+	Sidecars map[string]models.Sidecar
+	// genesis of the blockchains tracked by the cluster, by blockchain name
+	Genesis map[string][]byte
+}
+
+func stateKey(clusterName string) string {
+	return clustersKey + "/" + clusterName + "/" + stateFileName
+}
+
+// Snapshot returns the local state of [clusterName]. It returns false if the cluster does
+// not exist locally. The node keys are only included if a state passphrase is set, encrypted
+// with it
+func Snapshot(app *application.Avalanche, clusterName string) (ClusterState, bool, error) {
+	clustersConfig, err := app.GetClustersConfig()
+	if err != nil {
+		return ClusterState{}, false, err
+	}
+	clusterConfig, ok := clustersConfig.Clusters[clusterName]
+	if !ok {
+		return ClusterState{}, false, nil
+	}
+	state := ClusterState{
+		Version:       stateVersion,
+		ClusterConfig: clusterConfig,
+		Files:         map[string][]byte{},
+		Sidecars:      map[string]models.Sidecar{},
+		Genesis:       map[string][]byte{},
+	}
+	for _, dir := range clusterDirs(app, clusterName, clusterConfig) {
+		if err := addDirFiles(app.GetBaseDir(), dir, state.Files); err != nil {
+			return ClusterState{}, false, err
+		}
+	}
+	if err := sealNodeKeys(&state); err != nil {
+		return ClusterState{}, false, err
+	}
+	for relPath, fileBytes := range state.Files {
+		fileBytes, err := mapSSHKeyPaths(relPath, fileBytes, portablePath)
+		if err != nil {
+			return ClusterState{}, false, err
+		}
+		state.Files[relPath] = fileBytes
+	}
+	for _, blockchainName := range clusterConfig.Subnets {
+		if !app.SidecarExists(blockchainName) {
+			continue
+		}
+		sc, err := app.LoadSidecar(blockchainName)
+		if err != nil {
+			return ClusterState{}, false, err
+		}
+		state.Sidecars[blockchainName] = sc
+		if genesisPath := app.GetGenesisPath(blockchainName); utils.FileExists(genesisPath) {
+			genesisBytes, err := os.ReadFile(genesisPath)
+			if err != nil {
+				return ClusterState{}, false, err
+			}
+			state.Genesis[blockchainName] = genesisBytes
+		}
+	}
+	return state, true, nil
+}
+
+// Restore replaces the local state of [clusterName] with [state]. The inventory of the
+// cluster is rewritten, while the deployment records of other networks, and the genesis of
+// blockchains already known locally, are kept. Node keys are restored if the state passphrase
+// is set, and kept as they are locally otherwise
+func Restore(app *application.Avalanche, clusterName string, state ClusterState) error {
+	if state.Version != stateVersion {
+		return fmt.Errorf("unsupported cluster state version %q", state.Version)
+	}
+	for relPath := range state.Files {
+		if !isNodesPath(relPath) || isNodeKeyPath(relPath) {
+			return fmt.Errorf("invalid cluster state file path %q", relPath)
+		}
+	}
+	for relPath := range state.SecretFiles {
+		if !isNodesPath(relPath) || !isNodeKeyPath(relPath) {
+			return fmt.Errorf("invalid cluster state file path %q", relPath)
+		}
+	}
+	nodeKeys, err := openNodeKeys(state)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{}
+	for relPath, fileBytes := range state.Files {
+		fileBytes, err := mapSSHKeyPaths(relPath, fileBytes, utils.ExpandHome)
+		if err != nil {
+			return err
+		}
+		files[relPath] = fileBytes
+	}
+	maps.Copy(files, nodeKeys)
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	clustersConfig.Clusters[clusterName] = state.ClusterConfig
+	if err := app.WriteClustersConfigFile(&clustersConfig); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(app.GetAnsibleInventoryDirPath(clusterName)); err != nil {
+		return err
+	}
+	for relPath, fileBytes := range files {
+		filePath := filepath.Join(app.GetBaseDir(), filepath.FromSlash(relPath))
+		if err := os.MkdirAll(filepath.Dir(filePath), constants.DefaultPerms755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filePath, fileBytes, constants.WriteReadUserOnlyPerms); err != nil {
+			return err
+		}
+	}
+	networkName := models.NewNetworkFromCluster(state.ClusterConfig.Network, clusterName).Name()
+	for blockchainName, remoteSidecar := range state.Sidecars {
+		sc := remoteSidecar
+		if app.SidecarExists(blockchainName) {
+			networkData, ok := remoteSidecar.Networks[networkName]
+			if !ok {
+				continue
+			}
+			sc, err = app.LoadSidecar(blockchainName)
+			if err != nil {
+				return err
+			}
+			if sc.Networks == nil {
+				sc.Networks = map[string]models.NetworkData{}
+			}
+			sc.Networks[networkName] = networkData
+		} else if err := os.MkdirAll(filepath.Dir(app.GetSidecarPath(blockchainName)), constants.DefaultPerms755); err != nil {
+			return err
+		}
+		if err := app.UpdateSidecar(&sc); err != nil {
+			return err
+		}
+	}
+	for blockchainName, genesisBytes := range state.Genesis {
+		if genesisPath := app.GetGenesisPath(blockchainName); !utils.FileExists(genesisPath) {
+			if err := os.WriteFile(genesisPath, genesisBytes, constants.WriteReadReadPerms); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Pull replaces the local state of [clusterName] with the one of [backend]. It returns false
+// if the backend has no state for the cluster, leaving the local state untouched
+func Pull(ctx context.Context, app *application.Avalanche, backend Backend, clusterName string) (bool, error) {
+	stateBytes, err := backend.Get(ctx, stateKey(clusterName))
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failure reading state of cluster %s: %w", clusterName, err)
+	}
+	var state ClusterState
+	if err := json.Unmarshal(stateBytes, &state); err != nil {
+		return false, fmt.Errorf("invalid state of cluster %s: %w", clusterName, err)
+	}
+	return true, Restore(app, clusterName, state)
+}
+
+// Push uploads the local state of [clusterName] to [backend], or removes it from the backend
+// if the cluster no longer exists locally
+func Push(ctx context.Context, app *application.Avalanche, backend Backend, clusterName string) error {
+	state, found, err := Snapshot(app, clusterName)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return backend.Delete(ctx, stateKey(clusterName))
+	}
+	state.UpdatedAt = time.Now().UTC()
+	state.UpdatedBy = owner()
+	stateBytes, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := backend.Put(ctx, stateKey(clusterName), stateBytes); err != nil {
+		return fmt.Errorf("failure writing state of cluster %s: %w", clusterName, err)
+	}
+	return nil
+}
+
+// clusterDirs returns the local dirs holding the node configs, keys and inventory of the cluster
+func clusterDirs(app *application.Avalanche, clusterName string, clusterConfig models.ClusterConfig) []string {
+	cloudIDs := append([]string{}, clusterConfig.GetCloudIDs()...)
+	for _, cloudID := range clusterConfig.LoadTestInstance {
+		cloudIDs = append(cloudIDs, cloudID)
+	}
+	dirs := []string{app.GetAnsibleInventoryDirPath(clusterName)}
+	for _, cloudID := range cloudIDs {
+		dirs = append(dirs, app.GetNodeInstanceDirPath(cloudID), app.GetNodeInstanceAvaGoConfigDirPath(cloudID))
+	}
+	return dirs
+}
+
+// addDirFiles adds the files under [dir], if it exists, to [files], keyed by their slash
+// separated path relative to [baseDir]
+func addDirFiles(baseDir string, dir string, files map[string][]byte) error {
+	if !sdkutils.DirExists(dir) {
+		return nil
+	}
+	return filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		relPath, err := filepath.Rel(baseDir, filePath)
+		if err != nil {
+			return err
+		}
+		fileBytes, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relPath)] = fileBytes
+		return nil
+	})
+}
+
+// isNodesPath checks that [relPath] is a local path under the nodes dir, so a state
+// can't write anywhere else
+func isNodesPath(relPath string) bool {
+	return filepath.IsLocal(filepath.FromSlash(relPath)) && strings.HasPrefix(path.Clean(relPath), constants.NodesDir+"/")
+}
+
+// isNodeKeyPath checks if [relPath] is a staking or BLS key of a node
+func isNodeKeyPath(relPath string) bool {
+	return slices.Contains(nodeKeyFileNames, path.Base(relPath))
+}
+
+// mapSSHKeyPaths applies [f] to the SSH key paths of [fileBytes], if it is the ansible
+// inventory or the config of a node
+func mapSSHKeyPaths(relPath string, fileBytes []byte, f func(string) string) ([]byte, error) {
+	switch path.Base(relPath) {
+	case constants.AnsibleHostInventoryFileName:
+		return sshKeyPathRegex.ReplaceAllFunc(fileBytes, func(match []byte) []byte {
+			groups := sshKeyPathRegex.FindSubmatch(match)
+			return append(groups[1], []byte(f(string(groups[2])))...)
+		}), nil
+	case constants.NodeCloudConfigFileName:
+		var nodeConfig models.NodeConfig
+		if err := json.Unmarshal(fileBytes, &nodeConfig); err != nil {
+			return nil, fmt.Errorf("invalid node config %s: %w", relPath, err)
+		}
+		if nodeConfig.CertPath == "" {
+			return fileBytes, nil
+		}
+		nodeConfig.CertPath = f(nodeConfig.CertPath)
+		return json.MarshalIndent(nodeConfig, "", "    ")
+	}
+	return fileBytes, nil
+}
+
+// portablePath returns [localPath] relative to the home dir, if it is under it, so it is
+// resolved on each machine to its own home dir
+func portablePath(localPath string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return localPath
+	}
+	relPath, err := filepath.Rel(home, localPath)
+	if err != nil || !filepath.IsLocal(relPath) {
+		return localPath
+	}
+	return homePrefix + filepath.ToSlash(relPath)
+}
@@ -0,0 +1,404 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package statebackend
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+)
+
+// memBackend is an in memory backend. Object versions count the writes to the backend
+type memBackend struct {
+	lock     sync.Mutex
+	objects  map[string][]byte
+	versions map[string]int
+	writes   int
+}
+
+func newMemBackend() *memBackend {
+	return &memBackend{objects: map[string][]byte{}, versions: map[string]int{}}
+}
+
+func (b *memBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	data, _, err := b.GetVersion(ctx, key)
+	return data, err
+}
+
+func (b *memBackend) GetVersion(_ context.Context, key string) ([]byte, string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	data, ok := b.objects[key]
+	if !ok {
+		return nil, "", ErrNotFound
+	}
+	return data, strconv.Itoa(b.versions[key]), nil
+}
+
+func (b *memBackend) Put(_ context.Context, key string, data []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.put(key, data)
+	return nil
+}
+
+func (b *memBackend) put(key string, data []byte) {
+	b.writes++
+	b.objects[key] = data
+	b.versions[key] = b.writes
+}
+
+func (b *memBackend) PutIfAbsent(_ context.Context, key string, data []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.objects[key]; ok {
+		return ErrExists
+	}
+	b.put(key, data)
+	return nil
+}
+
+func (b *memBackend) Delete(_ context.Context, key string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.objects, key)
+	return nil
+}
+
+func (b *memBackend) DeleteIfVersion(_ context.Context, key string, version string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.objects[key]; !ok {
+		return nil
+	}
+	if strconv.Itoa(b.versions[key]) != version {
+		return ErrChanged
+	}
+	delete(b.objects, key)
+	return nil
+}
+
+// etag is the ETag returned by the fake S3 server for [data]
+func etag(data []byte) string {
+	sum := md5.Sum(data) //nolint:gosec
+	return strconv.Quote(hex.EncodeToString(sum[:]))
+}
+
+func newTestApp(t *testing.T) *application.Avalanche {
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), nil, nil)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	return app
+}
+
+func TestParseURL(t *testing.T) {
+	require := require.New(t)
+
+	u, err := parseURL("s3://team-bucket/avalanche/state?region=eu-west-1")
+	require.NoError(err)
+	require.Equal("team-bucket", u.bucket)
+	require.Equal("avalanche/state/clusters/c1/lock.json", u.key(lockKey("c1")))
+	require.Equal("eu-west-1", u.query.Get(s3RegionQueryKey))
+
+	u, err = parseURL("gs://team-bucket")
+	require.NoError(err)
+	require.Equal("clusters/c1/state.json", u.key(stateKey("c1")))
+
+	for _, invalidURL := range []string{
+		"azure://container/prefix",
+		"s3:///prefix",
+		"gs://bucket?region=us-east-1",
+		"s3://bucket?endpoint=localhost",
+	} {
+		require.Error(ValidateURL(invalidURL), invalidURL)
+	}
+}
+
+func TestS3Backend(t *testing.T) {
+	require := require.New(t)
+
+	objects := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.True(strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		require.True(strings.HasPrefix(r.URL.Path, "/team-bucket/prefix/"))
+		key := strings.TrimPrefix(r.URL.Path, "/team-bucket/")
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", etag(data))
+			_, _ = w.Write(data)
+		case http.MethodPut:
+			if _, ok := objects[key]; ok && r.Header.Get("If-None-Match") == "*" {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			data, err := io.ReadAll(r.Body)
+			require.NoError(err)
+			objects[key] = data
+		case http.MethodDelete:
+			if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != etag(objects[key]) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	u, err := parseURL("s3://team-bucket/prefix?endpoint=" + server.URL)
+	require.NoError(err)
+	backend := newS3BackendWithConfig(u, aws.Config{Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")})
+	ctx := context.Background()
+
+	_, err = backend.Get(ctx, "clusters/c1/state.json")
+	require.ErrorIs(err, ErrNotFound)
+	require.NoError(backend.Put(ctx, "clusters/c1/state.json", []byte(`{}`)))
+	data, err := backend.Get(ctx, "clusters/c1/state.json")
+	require.NoError(err)
+	require.Equal(`{}`, string(data))
+
+	lock, err := AcquireLock(ctx, backend, "c1", "avalanche node sync c1 chain")
+	require.NoError(err)
+	_, err = AcquireLock(ctx, backend, "c1", "avalanche node upgrade c1")
+	require.ErrorContains(err, "avalanche node sync c1 chain")
+	lock, err = RenewLock(ctx, backend, "c1", lock)
+	require.NoError(err)
+	require.ErrorIs(backend.DeleteIfVersion(ctx, lockKey("c1"), etag([]byte(`{}`))), ErrChanged)
+	require.NoError(ReleaseLock(ctx, backend, "c1", lock))
+	_, err = AcquireLock(ctx, backend, "c1", "avalanche node upgrade c1")
+	require.NoError(err)
+	require.NoError(backend.Delete(ctx, "clusters/c1/state.json"))
+	_, err = backend.Get(ctx, "clusters/c1/state.json")
+	require.ErrorIs(err, ErrNotFound)
+}
+
+func TestLockExpiration(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	backend := newMemBackend()
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+
+	lock, err := AcquireLock(ctx, backend, "c1", "avalanche node sync c1 chain")
+	require.NoError(err)
+	require.False(lock.Expired())
+	lock, err = RenewLock(ctx, backend, "c1", lock)
+	require.NoError(err)
+
+	// a lock not renewed in time is taken over
+	expiredLock := lock
+	expiredLock.ExpiresAt = time.Now().Add(-time.Minute)
+	lockBytes, err := json.Marshal(expiredLock)
+	require.NoError(err)
+	require.NoError(backend.Put(ctx, lockKey("c1"), lockBytes))
+	newLock, err := AcquireLock(ctx, backend, "c1", "avalanche node upgrade c1")
+	require.NoError(err)
+	require.NotEqual(lock.ID, newLock.ID)
+	_, err = RenewLock(ctx, backend, "c1", lock)
+	require.ErrorContains(err, "lock of cluster c1 was lost")
+
+	// locks without expiration expire after the TTL
+	require.False(Lock{AcquiredAt: time.Now()}.Expired())
+	require.True(Lock{AcquiredAt: time.Now().Add(-constants.StateBackendLockTTL - time.Minute)}.Expired())
+}
+
+func TestReleaseTakenOverLock(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	backend := newMemBackend()
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+
+	lock, err := AcquireLock(ctx, backend, "c1", "avalanche node sync c1 chain")
+	require.NoError(err)
+	expiredLock := lock
+	expiredLock.ExpiresAt = time.Now().Add(-time.Minute)
+	lockBytes, err := json.Marshal(expiredLock)
+	require.NoError(err)
+	require.NoError(backend.Put(ctx, lockKey("c1"), lockBytes))
+	newLock, err := AcquireLock(ctx, backend, "c1", "avalanche node upgrade c1")
+	require.NoError(err)
+
+	// the previous owner can not release the lock that replaced its own
+	require.ErrorContains(ReleaseLock(ctx, backend, "c1", lock), "lock of cluster c1 was lost")
+	currentLock, found, err := GetLock(ctx, backend, "c1")
+	require.NoError(err)
+	require.True(found)
+	require.Equal(newLock.ID, currentLock.ID)
+
+	// a lock changed after being read is not removed
+	staleLock, found, err := GetLock(ctx, backend, "c1")
+	require.NoError(err)
+	require.True(found)
+	_, err = RenewLock(ctx, backend, "c1", newLock)
+	require.NoError(err)
+	require.ErrorIs(backend.DeleteIfVersion(ctx, lockKey("c1"), staleLock.version), ErrChanged)
+	require.NoError(ReleaseLock(ctx, backend, "c1", newLock))
+	_, found, err = GetLock(ctx, backend, "c1")
+	require.NoError(err)
+	require.False(found)
+}
+
+func TestGCSError(t *testing.T) {
+	require := require.New(t)
+	require.ErrorIs(gcsError(&googleapi.Error{Code: http.StatusNotFound}), ErrNotFound)
+	require.ErrorIs(gcsError(&googleapi.Error{Code: http.StatusPreconditionFailed}), ErrExists)
+	require.NoError(gcsError(nil))
+}
+
+func TestPushPull(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	backend := newMemBackend()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(constants.StateBackendPassphraseEnvVarName, "")
+
+	// cluster created on one machine
+	app := newTestApp(t)
+	clusterConfig := models.ClusterConfig{
+		Nodes:   []string{"i-1"},
+		Network: models.NewDevnetNetwork("http://10.0.0.1:9650", 1337),
+		Subnets: []string{"chain"},
+	}
+	clusterConfig.Network.ClusterName = "c1"
+	require.NoError(app.SetClusterConfig("c1", clusterConfig))
+	sshKeyPath := filepath.Join(home, ".ssh", "c1.pem")
+	require.NoError(app.CreateNodeCloudConfigFile("i-1", &models.NodeConfig{NodeID: "i-1", Region: "us-east-1", CertPath: sshKeyPath}))
+	nodeDir := app.GetNodeInstanceDirPath("i-1")
+	require.NoError(os.WriteFile(filepath.Join(nodeDir, constants.StakerCertFileName), []byte("cert"), constants.WriteReadUserOnlyPerms))
+	require.NoError(os.WriteFile(filepath.Join(nodeDir, constants.StakerKeyFileName), []byte("staker key"), constants.WriteReadUserOnlyPerms))
+	require.NoError(os.WriteFile(filepath.Join(nodeDir, constants.BLSKeyFileName), []byte("signer key"), constants.WriteReadUserOnlyPerms))
+	require.NoError(os.MkdirAll(app.GetAnsibleInventoryDirPath("c1"), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(app.GetClusterYAMLFilePath("c1"), []byte("inventory"), constants.WriteReadReadPerms))
+	inventoryPath := filepath.Join(app.GetAnsibleInventoryDirPath("c1"), constants.AnsibleHostInventoryFileName)
+	require.NoError(os.WriteFile(inventoryPath, []byte("aws_node_i-1 ansible_host=10.0.0.1 ansible_ssh_private_key_file="+sshKeyPath+" region=us-east-1\n"), constants.WriteReadReadPerms))
+	networkName := models.NewNetworkFromCluster(clusterConfig.Network, "c1").Name()
+	sc := models.Sidecar{Name: "chain", Networks: map[string]models.NetworkData{networkName: {RPCEndpoints: []string{"http://10.0.0.1:9650/ext/bc/x/rpc"}}}}
+	require.NoError(app.CreateSidecar(&sc))
+	require.NoError(Push(ctx, app, backend, "c1"))
+
+	// node keys are not shared without a passphrase, and SSH key paths are relative to the home dir
+	stateBytes, err := backend.Get(ctx, stateKey("c1"))
+	require.NoError(err)
+	require.NotContains(string(stateBytes), "c2lnbmVyIGtleQ") // base64 of "signer key"
+	require.NotContains(string(stateBytes), home)
+	var state ClusterState
+	require.NoError(json.Unmarshal(stateBytes, &state))
+	require.Empty(state.SecretFiles)
+	require.NotContains(state.Files, "nodes/i-1/"+constants.StakerKeyFileName)
+	require.Contains(string(state.Files["nodes/inventories/c1/"+constants.AnsibleHostInventoryFileName]), "ansible_ssh_private_key_file=~/.ssh/c1.pem ")
+
+	// managed from another machine, that already knows the blockchain on fuji
+	otherApp := newTestApp(t)
+	otherHome := t.TempDir()
+	t.Setenv("HOME", otherHome)
+	otherSidecar := models.Sidecar{Name: "chain", Networks: map[string]models.NetworkData{models.Fuji.String(): {}}}
+	require.NoError(otherApp.CreateSidecar(&otherSidecar))
+	pulled, err := Pull(ctx, otherApp, backend, "c1")
+	require.NoError(err)
+	require.True(pulled)
+	pulledConfig, err := otherApp.GetClusterConfig("c1")
+	require.NoError(err)
+	require.Equal([]string{"i-1"}, pulledConfig.Nodes)
+	nodeConfig, err := otherApp.LoadClusterNodeConfig("i-1")
+	require.NoError(err)
+	require.Equal("us-east-1", nodeConfig.Region)
+	require.Equal(filepath.Join(otherHome, ".ssh", "c1.pem"), nodeConfig.CertPath)
+	require.NoFileExists(filepath.Join(otherApp.GetNodeInstanceDirPath("i-1"), constants.StakerKeyFileName))
+	cert, err := os.ReadFile(filepath.Join(otherApp.GetNodeInstanceDirPath("i-1"), constants.StakerCertFileName))
+	require.NoError(err)
+	require.Equal("cert", string(cert))
+	inventory, err := os.ReadFile(otherApp.GetClusterYAMLFilePath("c1"))
+	require.NoError(err)
+	require.Equal("inventory", string(inventory))
+	inventory, err = os.ReadFile(filepath.Join(otherApp.GetAnsibleInventoryDirPath("c1"), constants.AnsibleHostInventoryFileName))
+	require.NoError(err)
+	require.Contains(string(inventory), "ansible_ssh_private_key_file="+filepath.Join(otherHome, ".ssh", "c1.pem")+" ")
+	pulledSidecar, err := otherApp.LoadSidecar("chain")
+	require.NoError(err)
+	require.Contains(pulledSidecar.Networks, networkName)
+	require.Contains(pulledSidecar.Networks, models.Fuji.String())
+
+	// destroyed on the other machine
+	clustersConfig, err := otherApp.LoadClustersConfig()
+	require.NoError(err)
+	delete(clustersConfig.Clusters, "c1")
+	require.NoError(otherApp.WriteClustersConfigFile(&clustersConfig))
+	require.NoError(Push(ctx, otherApp, backend, "c1"))
+	pulled, err = Pull(ctx, app, backend, "c1")
+	require.NoError(err)
+	require.False(pulled)
+
+	// states can't write outside of the nodes dir
+	require.ErrorContains(Restore(app, "c1", ClusterState{
+		Version: stateVersion,
+		Files:   map[string][]byte{"nodes/../key/ewoq.pk": []byte("key")},
+	}), "invalid cluster state file path")
+	require.ErrorContains(Restore(app, "c1", ClusterState{
+		Version: stateVersion,
+		Files:   map[string][]byte{"nodes/i-1/" + constants.StakerKeyFileName: []byte("key")},
+	}), "invalid cluster state file path")
+}
+
+func TestNodeKeysEncryption(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	backend := newMemBackend()
+	t.Setenv(constants.StateBackendPassphraseEnvVarName, "team passphrase")
+
+	app := newTestApp(t)
+	clusterConfig := models.ClusterConfig{
+		Nodes:   []string{"i-1"},
+		Network: models.NewFujiNetwork(),
+	}
+	require.NoError(app.SetClusterConfig("c1", clusterConfig))
+	require.NoError(app.CreateNodeCloudConfigFile("i-1", &models.NodeConfig{NodeID: "i-1"}))
+	signerKeyPath := app.GetNodeBLSSecretKeyPath("i-1")
+	require.NoError(os.WriteFile(signerKeyPath, []byte("signer key"), constants.WriteReadUserOnlyPerms))
+	require.NoError(Push(ctx, app, backend, "c1"))
+	stateBytes, err := backend.Get(ctx, stateKey("c1"))
+	require.NoError(err)
+	require.NotContains(string(stateBytes), "c2lnbmVyIGtleQ")
+
+	// the keys are only restored with the right passphrase
+	otherApp := newTestApp(t)
+	t.Setenv(constants.StateBackendPassphraseEnvVarName, "wrong passphrase")
+	_, err = Pull(ctx, otherApp, backend, "c1")
+	require.ErrorIs(err, errInvalidPassphrase)
+	t.Setenv(constants.StateBackendPassphraseEnvVarName, "")
+	_, err = Pull(ctx, otherApp, backend, "c1")
+	require.NoError(err)
+	require.NoFileExists(otherApp.GetNodeBLSSecretKeyPath("i-1"))
+	t.Setenv(constants.StateBackendPassphraseEnvVarName, "team passphrase")
+	_, err = Pull(ctx, otherApp, backend, "c1")
+	require.NoError(err)
+	signerKey, err := os.ReadFile(otherApp.GetNodeBLSSecretKeyPath("i-1"))
+	require.NoError(err)
+	require.Equal("signer key", string(signerKey))
+}
@@ -12,7 +12,6 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
-	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 )
@@ -22,9 +21,8 @@ var errIllegalNameCharacter = errors.New(
 
 func GetDefaultSubnetAirdropKeyInfo(app *application.Avalanche, subnetName string) (string, string, string, error) {
 	keyName := utils.GetDefaultBlockchainAirdropKeyName(subnetName)
-	keyPath := app.GetKeyPath(keyName)
-	if utils.FileExists(keyPath) {
-		k, err := key.LoadSoft(models.NewLocalNetwork().ID, keyPath)
+	if app.KeyExists(keyName) {
+		k, err := app.GetKey(keyName, app.GetLocalNetwork(), false)
 		if err != nil {
 			return "", "", "", err
 		}
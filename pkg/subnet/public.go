@@ -103,7 +103,11 @@ func (d *PublicDeployer) AddValidatorNonSOV(
 	if err != nil {
 		return false, nil, nil, err
 	}
-	isFullySigned := len(remainingSubnetAuthKeys) == 0
+	missingSignatures, _, err := txutils.GetMissingSignatures(tx)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	isFullySigned := missingSignatures == 0
 
 	if isFullySigned {
 		id, err := d.Commit(tx, waitForTxAcceptance)
@@ -244,7 +248,11 @@ func (d *PublicDeployer) TransferSubnetOwnership(
 	if err != nil {
 		return false, nil, nil, err
 	}
-	isFullySigned := len(remainingSubnetAuthKeys) == 0
+	missingSignatures, _, err := txutils.GetMissingSignatures(tx)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	isFullySigned := missingSignatures == 0
 
 	if isFullySigned {
 		id, err := d.Commit(tx, true)
@@ -340,7 +348,11 @@ func (d *PublicDeployer) RemoveValidator(
 	if err != nil {
 		return false, nil, nil, err
 	}
-	isFullySigned := len(remainingSubnetAuthKeys) == 0
+	missingSignatures, _, err := txutils.GetMissingSignatures(tx)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	isFullySigned := missingSignatures == 0
 
 	if isFullySigned {
 		id, err := d.Commit(tx, true)
@@ -383,6 +395,56 @@ func (d *PublicDeployer) AddPermissionlessValidator(
 	return txID, nil
 }
 
+// adds [nodeID] as a Primary Network validator
+//   - the staked funds can be owned by multisig addresses. [stakeOwners] are the owner
+//     addresses not present in the wallet
+//   - if partially signed, returns the tx so that it can later on be signed by the rest of the owners
+//   - if fully signed, issues it
+func (d *PublicDeployer) AddPrimaryNetworkValidator(
+	nodeID ids.NodeID,
+	stakeAmount uint64,
+	startTime uint64,
+	endTime uint64,
+	recipientAddr ids.ShortID,
+	delegationFee uint32,
+	proofOfPossession *signer.ProofOfPossession,
+	stakeOwners []ids.ShortID,
+) (bool, ids.ID, *txs.Tx, error) {
+	wallet, err := d.loadCacheWallet()
+	if err != nil {
+		return false, ids.Empty, nil, err
+	}
+	tx, err := d.createAddPermissionlessValidatorTx(
+		recipientAddr,
+		stakeAmount,
+		avagoconstants.PrimaryNetworkID,
+		nodeID,
+		wallet.P().Builder().Context().AVAXAssetID,
+		startTime,
+		endTime,
+		wallet,
+		delegationFee,
+		proofOfPossession,
+		stakeOwners,
+	)
+	if err != nil {
+		return false, ids.Empty, nil, err
+	}
+	missingSignatures, _, err := txutils.GetMissingSignatures(tx)
+	if err != nil {
+		return false, ids.Empty, nil, err
+	}
+	isFullySigned := missingSignatures == 0
+	id := ids.Empty
+	if isFullySigned {
+		id, err = d.Commit(tx, true)
+		if err != nil {
+			return false, ids.Empty, nil, err
+		}
+	}
+	return isFullySigned, id, tx, nil
+}
+
 // - creates a subnet for [chain] using the given [controlKeys] and [threshold] as subnet authentication parameters
 func (d *PublicDeployer) DeploySubnet(
 	controlKeys []string,
@@ -442,7 +504,11 @@ func (d *PublicDeployer) DeployBlockchain(
 	if err != nil {
 		return false, ids.Empty, nil, nil, err
 	}
-	isFullySigned := len(remainingSubnetAuthKeys) == 0
+	missingSignatures, _, err := txutils.GetMissingSignatures(tx)
+	if err != nil {
+		return false, ids.Empty, nil, nil, err
+	}
+	isFullySigned := missingSignatures == 0
 
 	id := ids.Empty
 	if isFullySigned {
@@ -486,7 +552,11 @@ func (d *PublicDeployer) ConvertL1(
 	if err != nil {
 		return false, ids.Empty, nil, nil, err
 	}
-	isFullySigned := len(remainingSubnetAuthKeys) == 0
+	missingSignatures, _, err := txutils.GetMissingSignatures(tx)
+	if err != nil {
+		return false, ids.Empty, nil, nil, err
+	}
+	isFullySigned := missingSignatures == 0
 
 	id := ids.Empty
 	if isFullySigned {
@@ -536,6 +606,48 @@ func (d *PublicDeployer) PChainTransfer(
 	return id, &tx, nil
 }
 
+// PChainTransferFeeIncluded transfers [amount] to [destination] paying the tx fee out of it,
+// so the wallet needs no other funds. Returns the amount received by [destination]
+func (d *PublicDeployer) PChainTransferFeeIncluded(
+	destination ids.ShortID,
+	amount uint64,
+) (ids.ID, uint64, error) {
+	wallet, err := d.loadCacheWallet()
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	// the fee does not depend on the amount transferred
+	probeTx, err := wallet.P().Builder().NewBaseTx([]*avax.TransferableOutput{{
+		Asset: avax.Asset{ID: wallet.P().Builder().Context().AVAXAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt:          1,
+			OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{destination}},
+		},
+	}})
+	if err != nil {
+		return ids.Empty, 0, fmt.Errorf("error building tx: %w", err)
+	}
+	pContext := wallet.P().Builder().Context()
+	var pFeeCalculator avagofee.Calculator
+	if pContext.GasPrice != 0 {
+		pFeeCalculator = avagofee.NewDynamicCalculator(pContext.ComplexityWeights, pContext.GasPrice)
+	} else {
+		pFeeCalculator = avagofee.NewStaticCalculator(pContext.StaticFeeConfig)
+	}
+	txFee, err := pFeeCalculator.CalculateFee(probeTx)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	if amount <= txFee {
+		return ids.Empty, 0, fmt.Errorf("amount %.9f AVAX does not cover the tx fee of %.9f AVAX", float64(amount)/float64(units.Avax), float64(txFee)/float64(units.Avax))
+	}
+	id, _, err := d.PChainTransfer(destination, amount-txFee)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	return id, amount - txFee, nil
+}
+
 func (d *PublicDeployer) Commit(
 	tx *txs.Tx,
 	waitForTxAcceptance bool,
@@ -604,6 +716,19 @@ func (d *PublicDeployer) Sign(
 	return nil
 }
 
+// SignInputs signs the inputs of [tx] owned by the wallet keys, keeping the
+// signatures already present
+func (d *PublicDeployer) SignInputs(tx *txs.Tx) error {
+	wallet, err := d.loadWallet()
+	if err != nil {
+		return err
+	}
+	if d.kc.UsesLedger {
+		showLedgerSignatureMsg(d.kc.UsesLedger, d.kc.HasOnlyOneKey(), "tx hash")
+	}
+	return d.signTx(tx, wallet)
+}
+
 func (d *PublicDeployer) loadWallet(subnetIDs ...ids.ID) (*primary.Wallet, error) {
 	ctx := context.Background()
 	// filter out ids.Empty txs
@@ -808,13 +933,6 @@ func (d *PublicDeployer) issueAddPermissionlessValidatorTX(
 	popBytes []byte,
 	blsProof *signer.ProofOfPossession,
 ) (ids.ID, error) {
-	options := d.getMultisigTxOptions([]ids.ShortID{})
-	owner := &secp256k1fx.OutputOwners{
-		Threshold: 1,
-		Addrs: []ids.ShortID{
-			recipientAddr,
-		},
-	}
 	var proofOfPossession signer.Signer
 	if subnetID == ids.Empty {
 		if popBytes != nil {
@@ -830,7 +948,64 @@ func (d *PublicDeployer) issueAddPermissionlessValidatorTX(
 	} else {
 		proofOfPossession = &signer.Empty{}
 	}
+	tx, err := d.createAddPermissionlessValidatorTx(
+		recipientAddr,
+		stakeAmount,
+		subnetID,
+		nodeID,
+		assetID,
+		startTime,
+		endTime,
+		wallet,
+		delegationFee,
+		proofOfPossession,
+		nil,
+	)
+	if err != nil {
+		return ids.Empty, err
+	}
 
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	err = wallet.P().IssueTx(
+		tx,
+		common.WithContext(ctx),
+	)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), err)
+		} else {
+			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), err)
+		}
+		return ids.Empty, err
+	}
+
+	return tx.ID(), nil
+}
+
+// createAddPermissionlessValidatorTx builds and signs an addPermissionlessValidatorTx.
+// [stakeOwners] are addresses not in the wallet that can be used to select the staked
+// funds, whose signatures are left empty
+func (d *PublicDeployer) createAddPermissionlessValidatorTx(
+	recipientAddr ids.ShortID,
+	stakeAmount uint64,
+	subnetID ids.ID,
+	nodeID ids.NodeID,
+	assetID ids.ID,
+	startTime uint64,
+	endTime uint64,
+	wallet *primary.Wallet,
+	delegationFee uint32,
+	proofOfPossession signer.Signer,
+	stakeOwners []ids.ShortID,
+) (*txs.Tx, error) {
+	options := d.getMultisigTxOptions(stakeOwners)
+	owner := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			recipientAddr,
+		},
+	}
 	if d.kc.UsesLedger {
 		showLedgerSignatureMsg(d.kc.UsesLedger, d.kc.HasOnlyOneKey(), "Add Permissionless Validator hash")
 	}
@@ -852,29 +1027,13 @@ func (d *PublicDeployer) issueAddPermissionlessValidatorTX(
 		options...,
 	)
 	if err != nil {
-		return ids.Empty, fmt.Errorf("error building tx: %w", err)
+		return nil, fmt.Errorf("error building tx: %w", err)
 	}
 	tx := txs.Tx{Unsigned: unsignedTx}
 	if err := wallet.P().Signer().Sign(context.Background(), &tx); err != nil {
-		return ids.Empty, fmt.Errorf("error signing tx: %w", err)
-	}
-
-	ctx, cancel := utils.GetAPIContext()
-	defer cancel()
-	err = wallet.P().IssueTx(
-		&tx,
-		common.WithContext(ctx),
-	)
-	if err != nil {
-		if ctx.Err() != nil {
-			err = fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", tx.ID(), err)
-		} else {
-			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), err)
-		}
-		return ids.Empty, err
+		return nil, fmt.Errorf("error signing tx: %w", err)
 	}
-
-	return tx.ID(), nil
+	return &tx, nil
 }
 
 func (*PublicDeployer) signTx(
@@ -999,6 +1158,29 @@ func IsSubnetValidator(subnetID ids.ID, nodeID ids.NodeID, network models.Networ
 	return !(len(vals) == 0), nil
 }
 
+// WaitForSubnetValidator waits up to [timeout] for [nodeID] to be a current validator of
+// [subnetID]. Use ids.Empty for the Primary Network
+func WaitForSubnetValidator(subnetID ids.ID, nodeID ids.NodeID, network models.Network, timeout time.Duration) error {
+	poolTime := 1 * time.Second
+	startTime := time.Now()
+	for {
+		isValidator, err := IsSubnetValidator(subnetID, nodeID, network)
+		if err != nil {
+			return err
+		}
+		if isValidator {
+			return nil
+		}
+		if time.Since(startTime) > timeout {
+			if subnetID == ids.Empty {
+				return fmt.Errorf("node %s not validating the Primary Network after %d seconds", nodeID, uint32(timeout.Seconds()))
+			}
+			return fmt.Errorf("node %s not validating subnet ID %s after %d seconds", nodeID, subnetID, uint32(timeout.Seconds()))
+		}
+		time.Sleep(poolTime)
+	}
+}
+
 func GetPublicSubnetValidators(subnetID ids.ID, network models.Network) ([]platformvm.ClientPermissionlessValidator, error) {
 	pClient := platformvm.NewClient(network.Endpoint)
 	ctx, cancel := utils.GetAPIContext()
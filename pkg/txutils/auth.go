@@ -58,13 +58,12 @@ func GetAuthSigners(tx *txs.Tx, controlKeys []string) ([]string, error) {
 
 // get subnet auth addresses that did not yet signed a given tx
 //   - get the string slice of auth signers for the tx (GetAuthSigners)
-//   - verifies that all creds in tx.Creds, except the last one, are fully signed
-//     (a cred is fully signed if all the signatures in cred.Sigs are non-empty)
 //   - computes remaining signers by iterating the last cred in tx.Creds, associated to subnet auth signing
 //   - for each sig in cred.Sig: if sig is empty, then add the associated auth signer address (obtained from
 //     authSigners by using the index) to the remaining signers list
 //
-// if the tx is fully signed, returns empty slice
+// if the tx is fully signed by the subnet auth keys, returns empty slice. The creds of the
+// inputs, that may be left unsigned for custodial signers, are checked with GetMissingSignatures
 // expect tx.Unsigned type to be in [txs.AddSubnetValidatorTx, txs.CreateChainTx]
 //
 // controlKeys must be in the same order as in the subnet creation tx (as obtained by GetOwners)
@@ -78,18 +77,6 @@ func GetRemainingSigners(tx *txs.Tx, controlKeys []string) ([]string, []string,
 	if len(tx.Creds) < 2 {
 		return nil, nil, fmt.Errorf("expected tx.Creds of len 2, got %d", len(tx.Creds))
 	}
-	// signatures for output owners should be filled (all creds except last one)
-	for credIndex := range tx.Creds[:len(tx.Creds)-1] {
-		cred, ok := tx.Creds[credIndex].(*secp256k1fx.Credential)
-		if !ok {
-			return nil, nil, fmt.Errorf("expected cred to be of type *secp256k1fx.Credential, got %T", tx.Creds[credIndex])
-		}
-		for i, sig := range cred.Sigs {
-			if sig == emptySig {
-				return nil, nil, fmt.Errorf("expected funding sig %d of cred %d to be filled", i, credIndex)
-			}
-		}
-	}
 	// signatures for subnet auth (last cred)
 	cred, ok := tx.Creds[len(tx.Creds)-1].(*secp256k1fx.Credential)
 	if !ok {
@@ -109,3 +96,24 @@ func GetRemainingSigners(tx *txs.Tx, controlKeys []string) ([]string, []string,
 	}
 	return authSigners, remainingSigners, nil
 }
+
+// GetMissingSignatures returns the number of signatures still to be filled on
+// [tx] credentials, and the total number of signatures the credentials require
+func GetMissingSignatures(tx *txs.Tx) (int, int, error) {
+	emptySig := [secp256k1.SignatureLen]byte{}
+	missing := 0
+	total := 0
+	for credIndex, txCred := range tx.Creds {
+		cred, ok := txCred.(*secp256k1fx.Credential)
+		if !ok {
+			return 0, 0, fmt.Errorf("expected cred %d to be of type *secp256k1fx.Credential, got %T", credIndex, txCred)
+		}
+		for _, sig := range cred.Sigs {
+			total++
+			if sig == emptySig {
+				missing++
+			}
+		}
+	}
+	return missing, total, nil
+}
@@ -4,16 +4,25 @@ package txutils
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/gorilla/rpc/v2/json2"
 )
 
+// ErrL1ValidatorNotFound is returned when P-Chain answers that it does not know an L1 validator
+var ErrL1ValidatorNotFound = errors.New("L1 validator not found on P-Chain")
+
 // get network model associated to tx
 func GetNetwork(tx *txs.Tx) (models.Network, error) {
 	unsignedTx := tx.Unsigned
@@ -85,6 +94,12 @@ func IsCreateChainTx(tx *txs.Tx) bool {
 	return ok
 }
 
+// IsAddPrimaryNetworkValidatorTx checks if [tx] adds a Primary Network validator
+func IsAddPrimaryNetworkValidatorTx(tx *txs.Tx) bool {
+	unsignedTx, ok := tx.Unsigned.(*txs.AddPermissionlessValidatorTx)
+	return ok && unsignedTx.Subnet == avagoconstants.PrimaryNetworkID
+}
+
 func IsTransferSubnetOwnershipTx(tx *txs.Tx) bool {
 	_, ok := tx.Unsigned.(*txs.TransferSubnetOwnershipTx)
 	return ok
@@ -92,8 +107,9 @@ func IsTransferSubnetOwnershipTx(tx *txs.Tx) bool {
 
 func GetOwners(network models.Network, subnetID ids.ID) (bool, []string, uint32, error) {
 	pClient := platformvm.NewClient(network.Endpoint)
-	ctx := context.Background()
-	subnetResponse, err := pClient.GetSubnet(ctx, subnetID)
+	subnetResponse, err := utils.CallAPI(network.Endpoint, func(ctx context.Context) (platformvm.GetSubnetClientResponse, error) {
+		return pClient.GetSubnet(ctx, subnetID)
+	})
 	if err != nil {
 		return false, nil, 0, fmt.Errorf("subnet tx %s query error: %w", subnetID, err)
 	}
@@ -113,11 +129,42 @@ func GetOwners(network models.Network, subnetID ids.ID) (bool, []string, uint32,
 }
 
 func GetValidatorPChainBalanceValidationID(network models.Network, validationID ids.ID) (uint64, error) {
-	pClient := platformvm.NewClient(network.Endpoint)
-	ctx := context.Background()
-	validatorResponse, _, err := pClient.GetL1Validator(ctx, validationID)
+	validatorResponse, err := GetL1Validator(network, validationID)
 	if err != nil {
 		return 0, err
 	}
 	return validatorResponse.Balance, nil
 }
+
+func GetValidatorNodeIDValidationID(network models.Network, validationID ids.ID) (ids.NodeID, error) {
+	validatorResponse, err := GetL1Validator(network, validationID)
+	if err != nil {
+		return ids.EmptyNodeID, err
+	}
+	return validatorResponse.NodeID, nil
+}
+
+// GetL1Validator returns the P-Chain state of the L1 validator [validationID]. Fails with
+// ErrL1ValidatorNotFound if P-Chain does not know it
+func GetL1Validator(network models.Network, validationID ids.ID) (platformvm.L1Validator, error) {
+	pClient := platformvm.NewClient(network.Endpoint)
+	return utils.CallAPI(network.Endpoint, func(ctx context.Context) (platformvm.L1Validator, error) {
+		validator, _, err := pClient.GetL1Validator(ctx, validationID)
+		if isNotFoundResponse(err) {
+			return validator, utils.NonRetryable(fmt.Errorf("%w: %s", ErrL1ValidatorNotFound, validationID))
+		}
+		return validator, err
+	})
+}
+
+// isNotFoundResponse indicates if [err] is a JSON-RPC error answered by the node because
+// the requested item is not on its database. Transport errors are never a not found
+// answer, even if their text says so (eg a DNS lookup failure)
+func isNotFoundResponse(err error) bool {
+	var rpcErr *json2.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	// the node only sends the text of its database error
+	return strings.HasSuffix(rpcErr.Message, database.ErrNotFound.Error())
+}
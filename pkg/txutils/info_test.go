@@ -0,0 +1,25 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package txutils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNotFoundResponse(t *testing.T) {
+	require := require.New(t)
+	notFound := &json2.Error{Code: json2.E_SERVER, Message: "fetching L1 validator: " + database.ErrNotFound.Error()}
+	require.True(isNotFoundResponse(notFound))
+	// as wrapped by the avalanchego rpc client
+	require.True(isNotFoundResponse(fmt.Errorf("failed to issue request: %w", notFound)))
+	require.False(isNotFoundResponse(&json2.Error{Code: json2.E_SERVER, Message: "database closed"}))
+	// transport errors mentioning not found are not a P-Chain answer
+	require.False(isNotFoundResponse(errors.New("dial tcp: lookup api.avax.network: host not found")))
+	require.False(isNotFoundResponse(nil))
+}
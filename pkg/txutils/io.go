@@ -10,18 +10,27 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 )
 
-// saves a given [tx] to [txPath]
-func SaveToDisk(tx *txs.Tx, txPath string, forceOverwrite bool) error {
+// encodes a given [tx] as hex with checksum, the format used on tx files
+func Encode(tx *txs.Tx) (string, error) {
 	// Serialize the signed tx
 	txBytes, err := txs.Codec.Marshal(txs.CodecVersion, tx)
 	if err != nil {
-		return fmt.Errorf("couldn't marshal signed tx: %w", err)
+		return "", fmt.Errorf("couldn't marshal signed tx: %w", err)
 	}
 
 	// Get the encoded (in hex + checksum) signed tx
 	txStr, err := formatting.Encode(formatting.Hex, txBytes)
 	if err != nil {
-		return fmt.Errorf("couldn't encode signed tx: %w", err)
+		return "", fmt.Errorf("couldn't encode signed tx: %w", err)
+	}
+	return txStr, nil
+}
+
+// saves a given [tx] to [txPath]
+func SaveToDisk(tx *txs.Tx, txPath string, forceOverwrite bool) error {
+	txStr, err := Encode(tx)
+	if err != nil {
+		return err
 	}
 	// save
 	if _, err := os.Stat(txPath); err == nil && !forceOverwrite {
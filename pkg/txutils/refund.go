@@ -0,0 +1,65 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package txutils
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+const utxosPageSize = 1024
+
+// GetTxUnspentAmount returns the AVAX of the unspent P-Chain UTXOs created by [txID] for
+// [owners], and false if there are none
+func GetTxUnspentAmount(network models.Network, txID ids.ID, owners []ids.ShortID) (uint64, bool, error) {
+	return getUnspentAmount(network, owners, func(utxoID avax.UTXOID) bool {
+		return utxoID.TxID == txID
+	})
+}
+
+// GetRefundUnspentAmount returns the AVAX of the refund [refundUTXOID] if it is still
+// unspent by [owners]. When an L1 validator is removed, the P-Chain refunds its remaining
+// balance as an extra output of the tx setting its weight to 0, after the tx own outputs
+func GetRefundUnspentAmount(network models.Network, refundUTXOID avax.UTXOID, owners []ids.ShortID) (uint64, bool, error) {
+	return getUnspentAmount(network, owners, func(utxoID avax.UTXOID) bool {
+		return utxoID.TxID == refundUTXOID.TxID && utxoID.OutputIndex == refundUTXOID.OutputIndex
+	})
+}
+
+func getUnspentAmount(network models.Network, owners []ids.ShortID, match func(avax.UTXOID) bool) (uint64, bool, error) {
+	pClient := platformvm.NewClient(network.Endpoint)
+	startAddress, startUTXOID := ids.ShortEmpty, ids.Empty
+	amount, found := uint64(0), false
+	for {
+		ctx, cancel := utils.GetAPIContext()
+		utxosBytes, endAddress, endUTXOID, err := pClient.GetUTXOs(ctx, owners, utxosPageSize, startAddress, startUTXOID)
+		cancel()
+		if err != nil {
+			return 0, false, err
+		}
+		for _, utxoBytes := range utxosBytes {
+			utxo := &avax.UTXO{}
+			if _, err := txs.Codec.Unmarshal(utxoBytes, utxo); err != nil {
+				return 0, false, fmt.Errorf("failure parsing P-Chain UTXO: %w", err)
+			}
+			if !match(utxo.UTXOID) {
+				continue
+			}
+			if out, ok := utxo.Out.(*secp256k1fx.TransferOutput); ok {
+				amount += out.Amt
+				found = true
+			}
+		}
+		if len(utxosBytes) < utxosPageSize {
+			return amount, found, nil
+		}
+		startAddress, startUTXOID = endAddress, endUTXOID
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// ErrCircuitOpen is returned for calls to an endpoint that failed repeatedly, until
+// the endpoint is given a new chance
+var ErrCircuitOpen = errors.New("endpoint temporarily disabled after repeated failures")
+
+// APIClientConfig configures how outbound API calls are made
+type APIClientConfig struct {
+	// max number of attempts done for a call
+	MaxAttempts int
+	// wait before the first retry. it is doubled on each further retry, up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// timeout for each attempt
+	Timeout time.Duration
+	// max requests per second sent to a single endpoint. 0 disables rate limiting
+	RequestsPerSecond int
+	// consecutive failed attempts after which the endpoint circuit is opened.
+	// 0 disables circuit breaking
+	FailureThreshold int
+	// time the circuit stays open before letting a trial call through
+	OpenCircuitTime time.Duration
+}
+
+var DefaultAPIClientConfig = APIClientConfig{
+	MaxAttempts:       3,
+	InitialBackoff:    1 * time.Second,
+	MaxBackoff:        8 * time.Second,
+	Timeout:           constants.APIRequestLargeTimeout,
+	RequestsPerSecond: 10,
+	FailureThreshold:  10,
+	OpenCircuitTime:   30 * time.Second,
+}
+
+// APIClient performs outbound API calls, retrying failed ones with exponential backoff,
+// rate limiting the calls made to each endpoint, and failing fast on endpoints that
+// keep on failing
+type APIClient struct {
+	config    APIClientConfig
+	lock      sync.Mutex
+	endpoints map[string]*endpointState
+	now       func() time.Time
+	sleep     func(time.Duration)
+}
+
+type endpointState struct {
+	// rate limiting token bucket
+	tokens     float64
+	lastRefill time.Time
+	// circuit breaking
+	consecutiveFailures int
+	openUntil           time.Time
+}
+
+type nonRetryableError struct {
+	err error
+}
+
+func (e nonRetryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e nonRetryableError) Unwrap() error {
+	return e.err
+}
+
+// NonRetryable marks [err] as not to be retried by the API client. It is meant for
+// errors answered by a healthy endpoint, that are not going to change on retry,
+// and that do not count as endpoint failures
+func NonRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return nonRetryableError{err: err}
+}
+
+func NewAPIClient(config APIClientConfig) *APIClient {
+	return &APIClient{
+		config:    config,
+		endpoints: map[string]*endpointState{},
+		now:       time.Now,
+		sleep:     time.Sleep,
+	}
+}
+
+var defaultAPIClient = NewAPIClient(DefaultAPIClientConfig)
+
+// SetAPIClientConfig changes the config used for the outbound API calls of the CLI
+func SetAPIClientConfig(config APIClientConfig) {
+	defaultAPIClient = NewAPIClient(config)
+}
+
+// CallAPI executes [f] as a call to [endpoint] using the CLI API client
+func CallAPI[T any](endpoint string, f func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := defaultAPIClient.Call(endpoint, func(ctx context.Context) error {
+		var err error
+		result, err = f(ctx)
+		return err
+	})
+	return result, err
+}
+
+// Call executes [f] as a call to [endpoint], providing it with a context
+// bounded by the configured timeout
+func (c *APIClient) Call(endpoint string, f func(ctx context.Context) error) error {
+	key := endpointKey(endpoint)
+	maxAttempts := c.config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	backoff := c.config.InitialBackoff
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			c.sleep(backoff)
+			backoff = min(2*backoff, c.config.MaxBackoff)
+		}
+		if wait, circuitErr := c.acquire(key); circuitErr != nil {
+			if err != nil {
+				return fmt.Errorf("%w: %w", circuitErr, err)
+			}
+			return circuitErr
+		} else if wait > 0 {
+			c.sleep(wait)
+		}
+		err = c.attempt(f)
+		var (
+			nonRetryableErr nonRetryableError
+			// json-rpc errors returned by the endpoint, eg reverts
+			rpcErr interface{ ErrorCode() int }
+		)
+		switch {
+		case err == nil:
+			c.report(key, true)
+			return nil
+		case errors.As(err, &nonRetryableErr):
+			c.report(key, true)
+			return nonRetryableErr.err
+		case errors.As(err, &rpcErr):
+			c.report(key, true)
+			return err
+		}
+		c.report(key, false)
+	}
+	return err
+}
+
+func (c *APIClient) attempt(f func(ctx context.Context) error) error {
+	ctx := context.Background()
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+	return f(ctx)
+}
+
+// acquire checks the endpoint circuit, and takes a token from the endpoint bucket,
+// returning how long to wait for it to be available
+func (c *APIClient) acquire(key string) (time.Duration, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	state, ok := c.endpoints[key]
+	if !ok {
+		state = &endpointState{
+			tokens:     float64(c.config.RequestsPerSecond),
+			lastRefill: now,
+		}
+		c.endpoints[key] = state
+	}
+	if now.Before(state.openUntil) {
+		return 0, fmt.Errorf("%w (%s, retry after %s)", ErrCircuitOpen, key, state.openUntil.Sub(now).Round(time.Second))
+	}
+	if c.config.RequestsPerSecond <= 0 {
+		return 0, nil
+	}
+	rate := float64(c.config.RequestsPerSecond)
+	state.tokens = min(rate, state.tokens+now.Sub(state.lastRefill).Seconds()*rate)
+	state.lastRefill = now
+	// tokens can go negative, so concurrent callers queue one after the other
+	state.tokens--
+	if state.tokens >= 0 {
+		return 0, nil
+	}
+	return time.Duration(-state.tokens / rate * float64(time.Second)), nil
+}
+
+// report updates the endpoint circuit with the outcome of an attempt
+func (c *APIClient) report(key string, success bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	state := c.endpoints[key]
+	if success {
+		state.consecutiveFailures = 0
+		return
+	}
+	state.consecutiveFailures++
+	// once the circuit opened, a single failed trial call opens it again
+	if c.config.FailureThreshold > 0 && state.consecutiveFailures >= c.config.FailureThreshold {
+		state.openUntil = c.now().Add(c.config.OpenCircuitTime)
+	}
+}
+
+// endpointKey identifies the endpoint of [endpoint] by scheme and host, so that different
+// APIs served by the same node share rate limit and circuit
+func endpointKey(endpoint string) string {
+	parsedURL, err := url.Parse(endpoint)
+	if err != nil || parsedURL.Host == "" {
+		return endpoint
+	}
+	return parsedURL.Scheme + "://" + parsedURL.Host
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAPIClient returns a client with a fake clock, that advances on sleep
+func newTestAPIClient(config APIClientConfig) (*APIClient, *time.Duration) {
+	c := NewAPIClient(config)
+	now := time.Unix(0, 0)
+	slept := time.Duration(0)
+	c.now = func() time.Time { return now }
+	c.sleep = func(d time.Duration) {
+		now = now.Add(d)
+		slept += d
+	}
+	return c, &slept
+}
+
+func TestAPIClientRetries(t *testing.T) {
+	require := require.New(t)
+	errFailure := errors.New("failure")
+	c, slept := newTestAPIClient(APIClientConfig{
+		MaxAttempts:    4,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+	})
+	calls := 0
+	err := c.Call("https://api.avax-test.network/ext/P", func(context.Context) error {
+		calls++
+		return errFailure
+	})
+	require.ErrorIs(err, errFailure)
+	require.Equal(4, calls)
+	// 1s, 2s, and then capped at 3s
+	require.Equal(6*time.Second, *slept)
+
+	calls = 0
+	err = c.Call("https://api.avax-test.network/ext/P", func(context.Context) error {
+		calls++
+		if calls < 2 {
+			return errFailure
+		}
+		return nil
+	})
+	require.NoError(err)
+	require.Equal(2, calls)
+
+	calls = 0
+	err = c.Call("https://api.avax-test.network/ext/P", func(context.Context) error {
+		calls++
+		return NonRetryable(errFailure)
+	})
+	require.ErrorIs(err, errFailure)
+	require.Equal(1, calls)
+
+	calls = 0
+	err = c.Call("https://api.avax-test.network/ext/bc/C/rpc", func(context.Context) error {
+		calls++
+		return testRPCError{}
+	})
+	require.ErrorIs(err, testRPCError{})
+	require.Equal(1, calls)
+}
+
+type testRPCError struct{}
+
+func (testRPCError) Error() string { return "execution reverted" }
+
+func (testRPCError) ErrorCode() int { return 3 }
+
+func TestAPIClientRateLimit(t *testing.T) {
+	require := require.New(t)
+	c, slept := newTestAPIClient(APIClientConfig{
+		MaxAttempts:       1,
+		RequestsPerSecond: 2,
+	})
+	for i := 0; i < 6; i++ {
+		require.NoError(c.Call("https://api.avax-test.network/ext/P", func(context.Context) error { return nil }))
+	}
+	// burst of 2, then 2 per second
+	require.Equal(2*time.Second, *slept)
+	// other endpoints are not limited by it
+	require.NoError(c.Call("http://127.0.0.1:9650/ext/P", func(context.Context) error { return nil }))
+	require.Equal(2*time.Second, *slept)
+}
+
+func TestAPIClientCircuitBreaker(t *testing.T) {
+	require := require.New(t)
+	errFailure := errors.New("failure")
+	c, _ := newTestAPIClient(APIClientConfig{
+		MaxAttempts:      2,
+		FailureThreshold: 3,
+		OpenCircuitTime:  time.Minute,
+	})
+	calls := 0
+	failing := func(context.Context) error {
+		calls++
+		return errFailure
+	}
+	require.ErrorIs(c.Call("https://api.avax-test.network/ext/P", failing), errFailure)
+	// the third attempt opens the circuit, so the fourth one is not done
+	err := c.Call("https://api.avax-test.network/ext/bc/C/rpc", failing)
+	require.ErrorIs(err, ErrCircuitOpen)
+	require.ErrorIs(err, errFailure)
+	require.Equal(3, calls)
+	require.ErrorIs(c.Call("https://api.avax-test.network/ext/info", failing), ErrCircuitOpen)
+	require.Equal(3, calls)
+	// other endpoints are not affected
+	require.NoError(c.Call("http://127.0.0.1:9650/ext/P", func(context.Context) error { return nil }))
+
+	// after the open time, a trial call is let through
+	c.sleep(time.Minute)
+	require.NoError(c.Call("https://api.avax-test.network/ext/P", func(context.Context) error {
+		calls++
+		return nil
+	}))
+	require.Equal(4, calls)
+}
+
+func TestCallAPI(t *testing.T) {
+	require := require.New(t)
+	n, err := CallAPI("http://127.0.0.1:9650", func(context.Context) (uint32, error) {
+		return 5, nil
+	})
+	require.NoError(err)
+	require.Equal(uint32(5), n)
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -379,6 +380,15 @@ func GetGitCommit(gitRepoURL string) string {
 	return ""
 }
 
+// CheckGitIsInstalled returns an error with install instructions if git is not available
+func CheckGitIsInstalled() error {
+	if err := exec.Command("git", "--version").Run(); err != nil {
+		return fmt.Errorf("git tool is not available, and is a necessary dependency for this operation. " +
+			"Please follow install instructions at https://git-scm.com/book/en/v2/Getting-Started-Installing-Git and try again")
+	}
+	return nil
+}
+
 // ReadLongString reads a long string from the user input.
 func ReadLongString(msg string, args ...interface{}) (string, error) {
 	fmt.Printf(msg, args...)
@@ -392,6 +402,28 @@ func ReadLongString(msg string, args ...interface{}) (string, error) {
 	return longString, nil
 }
 
+// ReadLines reads lines from the user input until an empty one, so many values can be
+// pasted at once
+func ReadLines(msg string, args ...interface{}) ([]string, error) {
+	fmt.Printf(msg, args...)
+	reader := bufio.NewReader(os.Stdin)
+	lines := []string{}
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if strings.TrimSpace(line) == "" {
+			return lines, nil
+		}
+		lines = append(lines, line)
+		if err != nil {
+			return lines, nil
+		}
+	}
+}
+
 func SupportedAvagoArch() []string {
 	return []string{string(types.ArchitectureTypeArm64), string(types.ArchitectureTypeX8664)}
 }
@@ -400,6 +432,15 @@ func ArchSupported(arch string) bool {
 	return slices.Contains(SupportedAvagoArch(), arch)
 }
 
+// GoArch returns the go arch used on the release binaries names for the cloud
+// instance architecture [arch] (ex: x86_64 -> amd64)
+func GoArch(arch string) string {
+	if arch == string(types.ArchitectureTypeX8664) {
+		return "amd64"
+	}
+	return arch
+}
+
 // Get the host, port and path from a URL.
 func GetURIHostPortAndPath(uri string) (string, uint32, string, error) {
 	u, err := url.Parse(uri)
@@ -438,16 +479,16 @@ func InsideCodespace() bool {
 
 func GetChainID(endpoint string, chainName string) (ids.ID, error) {
 	client := info.NewClient(endpoint)
-	ctx, cancel := GetAPIContext()
-	defer cancel()
-	return client.GetBlockchainID(ctx, chainName)
+	return CallAPI(endpoint, func(ctx context.Context) (ids.ID, error) {
+		return client.GetBlockchainID(ctx, chainName)
+	})
 }
 
 func GetChainIDs(endpoint string, chainName string) (string, string, error) {
 	pClient := platformvm.NewClient(endpoint)
-	ctx, cancel := GetAPIContext()
-	defer cancel()
-	blockChains, err := pClient.GetBlockchains(ctx)
+	blockChains, err := CallAPI(endpoint, func(ctx context.Context) ([]platformvm.APIBlockchain, error) {
+		return pClient.GetBlockchains(ctx)
+	})
 	if err != nil {
 		return "", "", err
 	}
@@ -459,9 +500,9 @@ func GetChainIDs(endpoint string, chainName string) (string, string, error) {
 
 func GetBlockchainTx(endpoint string, blockchainID ids.ID) (*txs.CreateChainTx, error) {
 	pClient := platformvm.NewClient(endpoint)
-	ctx, cancel := GetAPIContext()
-	defer cancel()
-	txBytes, err := pClient.GetTx(ctx, blockchainID)
+	txBytes, err := CallAPI(endpoint, func(ctx context.Context) ([]byte, error) {
+		return pClient.GetTx(ctx, blockchainID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -572,3 +613,25 @@ func IsValidSemanticVersion(version string, component string) bool {
 	}
 	return true
 }
+
+var invalidCloudLabelChars = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// CloudResourceOwner normalizes [username] so it is a valid value both for AWS tags
+// and GCP labels (lowercase letters, digits, '_' and '-', at most 63 characters)
+func CloudResourceOwner(username string) string {
+	owner := invalidCloudLabelChars.ReplaceAllString(strings.ToLower(username), "_")
+	if len(owner) > 63 {
+		owner = owner[:63]
+	}
+	return owner
+}
+
+// GetCloudResourceOwner returns the owner set on the cloud resources created by the
+// current OS user
+func GetCloudResourceOwner() (string, error) {
+	usr, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return CloudResourceOwner(usr.Username), nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -381,3 +382,19 @@ func TestSetJSONKey(t *testing.T) {
 		}
 	}
 }
+
+func TestGoArch(t *testing.T) {
+	require := require.New(t)
+	require.Equal("amd64", GoArch("x86_64"))
+	require.Equal("arm64", GoArch("arm64"))
+	require.True(ArchSupported("arm64"))
+	require.False(ArchSupported("i386"))
+}
+
+func TestCloudResourceOwner(t *testing.T) {
+	require := require.New(t)
+	require.Equal("ubuntu", CloudResourceOwner("ubuntu"))
+	require.Equal("john_doe", CloudResourceOwner("John.Doe"))
+	require.Equal("corp_jdoe", CloudResourceOwner(`CORP\jdoe`))
+	require.Len(CloudResourceOwner(strings.Repeat("a", 100)), 63)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	sdkutils "github.com/ava-labs/avalanche-cli/sdk/utils"
+	"github.com/ava-labs/avalanchego/utils/units"
 	"golang.org/x/mod/modfile"
 )
 
@@ -32,6 +33,12 @@ func FileExists(filename string) bool {
 	return !info.IsDir()
 }
 
+// DirExists checks if a directory exists.
+func DirExists(dirName string) bool {
+	info, err := os.Stat(dirName)
+	return err == nil && info.IsDir()
+}
+
 // IsExecutable checks if a file is executable.
 func IsExecutable(filename string) bool {
 	if !FileExists(filename) {
@@ -112,6 +119,20 @@ func SizeInKB(path string) (int64, error) {
 	return size, err
 }
 
+// FormatSize returns [bytes] in the largest binary unit it reaches
+func FormatSize(bytes int64) string {
+	switch {
+	case bytes >= int64(units.GiB):
+		return fmt.Sprintf("%.1f GiB", float64(bytes)/float64(units.GiB))
+	case bytes >= int64(units.MiB):
+		return fmt.Sprintf("%.1f MiB", float64(bytes)/float64(units.MiB))
+	case bytes >= int64(units.KiB):
+		return fmt.Sprintf("%.1f KiB", float64(bytes)/float64(units.KiB))
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
+
 // RemoteComposeFile returns the path to the remote docker-compose file
 func GetRemoteComposeFile() string {
 	return filepath.Join(constants.CloudNodeCLIConfigBasePath, "services", "docker-compose.yml")
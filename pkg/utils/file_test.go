@@ -98,3 +98,17 @@ func TestReadGoVersion(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatSize(t *testing.T) {
+	cases := map[int64]string{
+		512:                    "512 B",
+		1536:                   "1.5 KiB",
+		2 * 1024 * 1024:        "2.0 MiB",
+		3 * 1024 * 1024 * 1024: "3.0 GiB",
+	}
+	for bytes, expected := range cases {
+		if formatted := FormatSize(bytes); formatted != expected {
+			t.Errorf("FormatSize(%d): expected %s, got %s", bytes, expected, formatted)
+		}
+	}
+}
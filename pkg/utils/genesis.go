@@ -0,0 +1,54 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"golang.org/x/exp/slices"
+)
+
+// LoadPrimaryNetworkGenesis loads the custom primary network genesis at [genesisPath],
+// checking it is a valid genesis for a network whose nodes are [nodeIDs]:
+// the network ID is not one of a standard network, the allocations, stake durations and
+// C-Chain genesis are consistent, and all initial stakers are nodes of the network.
+// If [nodeIDs] is nil, the initial stakers are not checked
+func LoadPrimaryNetworkGenesis(genesisPath string, nodeIDs []ids.NodeID) (*genesis.Config, error) {
+	genesisConfig, err := genesis.GetConfigFile(genesisPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis file %s: %w", genesisPath, err)
+	}
+	// same validation avalanchego does at startup
+	if _, _, err := genesis.FromFile(genesisConfig.NetworkID, genesisPath, &genesis.LocalParams.StakingConfig); err != nil {
+		return nil, err
+	}
+	if err := validateCChainGenesis(genesisConfig.CChainGenesis); err != nil {
+		return nil, fmt.Errorf("invalid C-Chain genesis in %s: %w", genesisPath, err)
+	}
+	for _, staker := range genesisConfig.InitialStakers {
+		if nodeIDs != nil && !slices.Contains(nodeIDs, staker.NodeID) {
+			return nil, fmt.Errorf("initial staker %s of genesis %s is not a node of the network. expected one of %v", staker.NodeID, genesisPath, nodeIDs)
+		}
+	}
+	return genesisConfig, nil
+}
+
+func validateCChainGenesis(cChainGenesis string) error {
+	var genesisFields struct {
+		Config *struct {
+			ChainID *big.Int `json:"chainId"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal([]byte(cChainGenesis), &genesisFields); err != nil {
+		return err
+	}
+	if genesisFields.Config == nil || genesisFields.Config.ChainID == nil {
+		return errors.New("chain config with chainId is required")
+	}
+	return nil
+}
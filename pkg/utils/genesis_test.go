@@ -0,0 +1,64 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestGenesis writes the local network genesis, with network ID [networkID]
+func writeTestGenesis(t *testing.T, networkID uint32, update func(*genesis.UnparsedConfig)) string {
+	config := *genesis.GetConfig(constants.LocalID)
+	config.NetworkID = networkID
+	unparsedConfig, err := config.Unparse()
+	require.NoError(t, err)
+	if update != nil {
+		update(&unparsedConfig)
+	}
+	genesisBytes, err := json.Marshal(unparsedConfig)
+	require.NoError(t, err)
+	genesisPath := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(genesisPath, genesisBytes, 0o600))
+	return genesisPath
+}
+
+func TestLoadPrimaryNetworkGenesis(t *testing.T) {
+	require := require.New(t)
+
+	stakers := []ids.NodeID{}
+	for _, staker := range genesis.GetConfig(constants.LocalID).InitialStakers {
+		stakers = append(stakers, staker.NodeID)
+	}
+
+	genesisConfig, err := LoadPrimaryNetworkGenesis(writeTestGenesis(t, 1500, nil), stakers)
+	require.NoError(err)
+	require.Equal(uint32(1500), genesisConfig.NetworkID)
+
+	// initial stakers must be nodes of the network
+	_, err = LoadPrimaryNetworkGenesis(writeTestGenesis(t, 1500, nil), stakers[1:])
+	require.ErrorContains(err, "is not a node of the network")
+	_, err = LoadPrimaryNetworkGenesis(writeTestGenesis(t, 1500, nil), nil)
+	require.NoError(err)
+
+	// standard network IDs can't be overridden
+	_, err = LoadPrimaryNetworkGenesis(writeTestGenesis(t, constants.MainnetID, nil), stakers)
+	require.Error(err)
+
+	_, err = LoadPrimaryNetworkGenesis(writeTestGenesis(t, 1500, func(c *genesis.UnparsedConfig) {
+		c.CChainGenesis = `{"alloc": {}}`
+	}), stakers)
+	require.ErrorContains(err, "chainId")
+
+	_, err = LoadPrimaryNetworkGenesis(writeTestGenesis(t, 1500, func(c *genesis.UnparsedConfig) {
+		c.InitialStakers = nil
+	}), stakers)
+	require.Error(err)
+}
@@ -3,6 +3,7 @@
 package utils
 
 import (
+	"context"
 	"os"
 	"strings"
 
@@ -45,11 +46,26 @@ func GetKeyNames(keyDir string, addEwoq bool) ([]string, error) {
 	return names, nil
 }
 
+// GetHDWalletNames returns the names of the HD wallets stored at [keyDir]
+func GetHDWalletNames(keyDir string) ([]string, error) {
+	matches, err := os.ReadDir(keyDir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, m := range matches {
+		if strings.HasSuffix(m.Name(), constants.HDWalletSuffix) {
+			names = append(names, strings.TrimSuffix(m.Name(), constants.HDWalletSuffix))
+		}
+	}
+	return names, nil
+}
+
 func GetNetworkBalance(addressList []ids.ShortID, networkEndpoint string) (uint64, error) {
-	ctx, cancel := GetAPIContext()
-	defer cancel()
 	pClient := platformvm.NewClient(networkEndpoint)
-	bal, err := pClient.GetBalance(ctx, addressList)
+	bal, err := CallAPI(networkEndpoint, func(ctx context.Context) (*platformvm.GetBalanceResponse, error) {
+		return pClient.GetBalance(ctx, addressList)
+	})
 	if err != nil {
 		return 0, err
 	}
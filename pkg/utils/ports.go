@@ -0,0 +1,129 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"syscall"
+
+	psnet "github.com/shirou/gopsutil/net"
+	"github.com/shirou/gopsutil/process"
+)
+
+// PortConflict is a port needed by [Service] that is already in use
+type PortConflict struct {
+	Service   string
+	Port      uint16
+	PIDs      []int32
+	Processes []string
+}
+
+// IsPortAvailable returns true if [port] can be bound on all local interfaces.
+// The result is only valid at the time of the check: another process can take the
+// port before it is used, so the failure to bind it later must still be handled,
+// see IsPortInUseError
+func IsPortAvailable(port uint16) bool {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	_ = ln.Close()
+	return true
+}
+
+// IsPortInUseError returns true if [err] is a failure to bind a port that is
+// already in use, either returned locally or reported by a remote service
+func IsPortInUseError(err error) bool {
+	return err != nil && (errors.Is(err, syscall.EADDRINUSE) || strings.Contains(err.Error(), "address already in use"))
+}
+
+// GetAvailablePort returns the first available port starting from [start], skipping
+// the ports in [reserved]
+func GetAvailablePort(start uint16, reserved map[uint16]bool) (uint16, error) {
+	for port := uint32(start); port <= math.MaxUint16; port++ {
+		if !reserved[uint16(port)] && IsPortAvailable(uint16(port)) {
+			return uint16(port), nil
+		}
+	}
+	return 0, fmt.Errorf("no available port found from %d", start)
+}
+
+// GetPortListeners returns the PIDs and names of the processes listening on
+// [port]. It returns empty lists if they can't be obtained, eg because of
+// missing permissions
+func GetPortListeners(port uint16) ([]int32, []string) {
+	conns, err := psnet.Connections("tcp")
+	if err != nil {
+		return nil, nil
+	}
+	pids := []int32{}
+	names := []string{}
+	for _, conn := range conns {
+		if conn.Status != "LISTEN" || conn.Laddr.Port != uint32(port) || conn.Pid == 0 {
+			continue
+		}
+		if Belongs(pids, conn.Pid) {
+			continue
+		}
+		pids = append(pids, conn.Pid)
+		name := ""
+		if p, err := process.NewProcess(conn.Pid); err == nil {
+			name, _ = p.Name()
+		}
+		names = append(names, name)
+	}
+	return pids, names
+}
+
+// FindPortConflicts checks the ports of [portServices], a map from port to the
+// service that needs it, and returns the ones already in use sorted by port
+func FindPortConflicts(portServices map[uint16]string) []PortConflict {
+	conflicts := []PortConflict{}
+	for port, service := range portServices {
+		if IsPortAvailable(port) {
+			continue
+		}
+		conflicts = append(conflicts, NewPortConflict(service, port))
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Port < conflicts[j].Port
+	})
+	return conflicts
+}
+
+// NewPortConflict returns the conflict of [service] with the processes listening on [port]
+func NewPortConflict(service string, port uint16) PortConflict {
+	pids, names := GetPortListeners(port)
+	return PortConflict{
+		Service:   service,
+		Port:      port,
+		PIDs:      pids,
+		Processes: names,
+	}
+}
+
+// PortConflictsReport describes [conflicts], one per line
+func PortConflictsReport(conflicts []PortConflict) string {
+	lines := []string{}
+	for _, conflict := range conflicts {
+		owners := "unknown process"
+		if len(conflict.PIDs) > 0 {
+			procs := []string{}
+			for i, pid := range conflict.PIDs {
+				if conflict.Processes[i] != "" {
+					procs = append(procs, fmt.Sprintf("%s (pid %d)", conflict.Processes[i], pid))
+				} else {
+					procs = append(procs, fmt.Sprintf("pid %d", pid))
+				}
+			}
+			owners = strings.Join(procs, ", ")
+		}
+		lines = append(lines, fmt.Sprintf("port %d needed by %s is in use by %s", conflict.Port, conflict.Service, owners))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortConflicts(t *testing.T) {
+	require := require.New(t)
+	ln, err := net.Listen("tcp", ":0")
+	require.NoError(err)
+	defer ln.Close()
+	usedPort := uint16(ln.Addr().(*net.TCPAddr).Port)
+
+	require.False(IsPortAvailable(usedPort))
+	port, err := GetAvailablePort(usedPort, nil)
+	require.NoError(err)
+	require.NotEqual(usedPort, port)
+	port2, err := GetAvailablePort(usedPort, map[uint16]bool{port: true})
+	require.NoError(err)
+	require.NotEqual(port, port2)
+
+	conflicts := FindPortConflicts(map[uint16]string{usedPort: "node1 API", port: "node1 staking"})
+	require.Len(conflicts, 1)
+	require.Equal(usedPort, conflicts[0].Port)
+	require.Equal("node1 API", conflicts[0].Service)
+	if len(conflicts[0].PIDs) > 0 {
+		require.Contains(conflicts[0].PIDs, int32(os.Getpid()))
+	}
+	require.Contains(PortConflictsReport(conflicts), "needed by node1 API is in use by")
+
+	_, err = net.Listen("tcp", ln.Addr().String())
+	require.True(IsPortInUseError(err))
+	require.True(IsPortInUseError(fmt.Errorf("failed to start network: %s", err)))
+	require.False(IsPortInUseError(errors.New("node1 failed to bootstrap")))
+	require.False(IsPortInUseError(nil))
+	conflict := NewPortConflict("relayer API", usedPort)
+	require.Equal(usedPort, conflict.Port)
+	require.Equal("relayer API", conflict.Service)
+}
@@ -0,0 +1,264 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// ProxyConfig configures the proxies used for outbound connections. Empty fields
+// fall back to the standard environment variables (HTTP_PROXY, HTTPS_PROXY,
+// NO_PROXY, and ALL_PROXY for SSH)
+type ProxyConfig struct {
+	// proxy for http requests
+	HTTPProxy string
+	// proxy for https requests
+	HTTPSProxy string
+	// comma separated hosts, domains, IPs or CIDRs not to be proxied
+	NoProxy string
+	// proxy for SSH connections, as socks5://[user:pass@]host:port or http://[user:pass@]host:port
+	SSHProxy string
+}
+
+var proxyConfig ProxyConfig
+
+// SetProxyConfig changes the proxies used for the outbound connections of the CLI,
+// including the ones of the spawned processes
+func SetProxyConfig(config ProxyConfig) error {
+	for _, proxyURL := range []string{config.HTTPProxy, config.HTTPSProxy, config.SSHProxy} {
+		if err := ValidateProxyURL(proxyURL); err != nil {
+			return err
+		}
+	}
+	envVars := map[string]string{
+		"HTTP_PROXY":  config.HTTPProxy,
+		"HTTPS_PROXY": config.HTTPSProxy,
+		"NO_PROXY":    config.NoProxy,
+	}
+	for name, value := range envVars {
+		if value == "" {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+		if err := os.Setenv(strings.ToLower(name), value); err != nil {
+			return err
+		}
+	}
+	proxyConfig = config
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		// the env based proxy func is cached on first use, so it may not see the above
+		proxyFunc := GetProxyConfig().httpProxyConfig().ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+	return nil
+}
+
+// GetProxyConfig returns the proxies in use, either set with SetProxyConfig or
+// taken from the environment
+func GetProxyConfig() ProxyConfig {
+	envConfig := httpproxy.FromEnvironment()
+	config := proxyConfig
+	if config.HTTPProxy == "" {
+		config.HTTPProxy = envConfig.HTTPProxy
+	}
+	if config.HTTPSProxy == "" {
+		config.HTTPSProxy = envConfig.HTTPSProxy
+	}
+	if config.NoProxy == "" {
+		config.NoProxy = envConfig.NoProxy
+	}
+	if config.SSHProxy == "" {
+		config.SSHProxy = getEnvAny("ALL_PROXY", "all_proxy")
+	}
+	return config
+}
+
+func (config ProxyConfig) httpProxyConfig() *httpproxy.Config {
+	return &httpproxy.Config{
+		HTTPProxy:  config.HTTPProxy,
+		HTTPSProxy: config.HTTPSProxy,
+		NoProxy:    config.NoProxy,
+	}
+}
+
+// GetSSHProxy returns the proxy to be used for SSH connections to [addr] (host:port),
+// or nil if it is to be reached directly
+func GetSSHProxy(addr string) (*url.URL, error) {
+	config := GetProxyConfig()
+	if config.SSHProxy == "" {
+		return nil, nil
+	}
+	// NO_PROXY matching is done as for https requests
+	proxyConfig := httpproxy.Config{
+		HTTPSProxy: config.SSHProxy,
+		NoProxy:    config.NoProxy,
+	}
+	return proxyConfig.ProxyFunc()(&url.URL{Scheme: "https", Host: addr})
+}
+
+// DialTCP connects to [addr] (host:port), through the SSH proxy if one is configured
+func DialTCP(addr string, timeout time.Duration) (net.Conn, error) {
+	proxyURL, err := GetSSHProxy(addr)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	if proxyURL == nil {
+		return dialer.Dial("tcp", addr)
+	}
+	var conn net.Conn
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		proxyDialer, err := proxy.FromURL(proxyURL, dialer)
+		if err != nil {
+			return nil, err
+		}
+		conn, err = proxyDialer.Dial("tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s through proxy %s: %w", addr, proxyURL.Redacted(), err)
+		}
+	default:
+		conn, err = dialHTTPConnect(proxyURL, addr, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s through proxy %s: %w", addr, proxyURL.Redacted(), err)
+		}
+	}
+	return conn, nil
+}
+
+// dialHTTPConnect opens a tunnel to [addr] through the http proxy [proxyURL]
+func dialHTTPConnect(proxyURL *url.URL, addr string, timeout time.Duration) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+	conn, err := net.DialTimeout("tcp", proxyAddr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname(), MinVersion: tls.VersionTLS12})
+	}
+	if timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(timeout))
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodConnect, "", nil)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	req.URL = &url.URL{Opaque: addr}
+	req.Host = addr
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("proxy answered %s", resp.Status)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	if reader.Buffered() > 0 {
+		// the server may have already sent data after the proxy response
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a connection whose first bytes were already read into [reader]
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// sshProxyCommand is the command ssh runs to connect through the SSH proxy, see [SetSSHProxyCommand]
+var sshProxyCommand []string
+
+// SetSSHProxyCommand sets the command ssh runs to connect through the SSH proxy. The command is
+// given the target host and port as its last two arguments, and must pipe stdin and stdout
+// to a connection made with [DialTCP], so proxy credentials never reach the command line
+func SetSSHProxyCommand(command ...string) {
+	sshProxyCommand = command
+}
+
+// GetSSHProxyCommand returns the ssh ProxyCommand option that connects to [addr] (host:port)
+// through the SSH proxy, or an empty string if none is used
+func GetSSHProxyCommand(addr string) string {
+	proxyURL, err := GetSSHProxy(addr)
+	if err != nil || proxyURL == nil || len(sshProxyCommand) == 0 {
+		return ""
+	}
+	args := make([]string, 0, len(sshProxyCommand))
+	for _, arg := range sshProxyCommand {
+		args = append(args, strconv.Quote(arg))
+	}
+	return fmt.Sprintf("-o ProxyCommand='%s %%h %%p'", strings.Join(args, " "))
+}
+
+// ValidateProxyURL checks that [proxyURL] is an http, https or socks5 proxy URL.
+// An empty URL means no proxy
+func ValidateProxyURL(proxyURL string) error {
+	if proxyURL == "" {
+		return nil
+	}
+	parsedURL, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy %q: %w", proxyURL, err)
+	}
+	switch parsedURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("invalid proxy %q: expected an http://, https:// or socks5:// URL", proxyURL)
+	}
+	if parsedURL.Host == "" {
+		return fmt.Errorf("invalid proxy %q: missing host", proxyURL)
+	}
+	return nil
+}
+
+func getEnvAny(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
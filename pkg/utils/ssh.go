@@ -15,24 +15,17 @@ import (
 	"golang.org/x/crypto/ssh/agent"
 )
 
-// GetSSHConnectionString returns the SSH connection string for the given public IP and certificate file path.
-func GetSSHConnectionString(publicIP, certFilePath string) string {
-	if certFilePath != "" {
-		certFilePath = fmt.Sprintf("-i %s", certFilePath)
-	}
-	return fmt.Sprintf("ssh %s %s@%s %s", constants.AnsibleSSHShellParams, constants.AnsibleSSHUser, publicIP, certFilePath)
-}
-
 // GetSCPTargetPath returns the target path for the given source path and target directory.
-func GetSCPTargetPath(ip, path string) string {
+func GetSCPTargetPath(user, ip, path string) string {
 	if ip == "" {
 		return path
 	}
-	return fmt.Sprintf("%s@%s:%s", constants.AnsibleSSHUser, ip, path)
+	return fmt.Sprintf("%s@%s:%s", user, ip, path)
 }
 
 // GetSCPCommandString returns the SCP command string for the given source and destination paths.
-func GetSCPCommandString(certFilePath string, sourceIP, sourcePath string, destIP, destPath string, recursive, withCompression bool) (string, error) {
+// [sshUser] and [sshOptions] are used to connect to the remote hosts
+func GetSCPCommandString(sshUser, sshOptions, certFilePath string, sourceIP, sourcePath string, destIP, destPath string, recursive, withCompression bool) (string, error) {
 	scpParams := constants.AnsibleSSHShellParams + " -B -o LogLevel=Error"
 	if sourceIP == "" && destIP == "" {
 		return "", fmt.Errorf("source or destination should be remote")
@@ -47,6 +40,9 @@ func GetSCPCommandString(certFilePath string, sourceIP, sourcePath string, destI
 	if withCompression {
 		scpParams += " -C"
 	}
+	if sshOptions != "" {
+		scpParams += " " + sshOptions
+	}
 	if certFilePath != "" {
 		scpParams += fmt.Sprintf(" -i %s", certFilePath)
 	}
@@ -54,10 +50,10 @@ func GetSCPCommandString(certFilePath string, sourceIP, sourcePath string, destI
 		scpParams += " -3"
 	}
 	if sourceIP != "" {
-		sourcePath = GetSCPTargetPath(sourceIP, sourcePath)
+		sourcePath = GetSCPTargetPath(sshUser, sourceIP, sourcePath)
 	}
 	if destIP != "" {
-		destPath = GetSCPTargetPath(destIP, destPath)
+		destPath = GetSCPTargetPath(sshUser, destIP, destPath)
 	}
 
 	return fmt.Sprintf("scp %s %s %s", scpParams, sourcePath, destPath), nil
@@ -3,6 +3,8 @@
 package utils
 
 import (
+	"context"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
@@ -11,10 +13,13 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/rpcauth"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/rpc"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
 
 	"github.com/ava-labs/subnet-evm/plugin/evm"
@@ -79,12 +84,51 @@ func GetNodeParams(nodeDir string) (
 	return nodeID, blsPub, blsPoP, nil
 }
 
+// ParseBLSPoP parses the given BLS [publicKey] and [proofOfPossession] of a node, and
+// verifies that the proof of possession was signed by the public key owner
+func ParseBLSPoP(publicKey string, proofOfPossession string) (*signer.ProofOfPossession, error) {
+	popBytes, err := json.Marshal(map[string]string{
+		"publicKey":         publicKey,
+		"proofOfPossession": proofOfPossession,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pop := &signer.ProofOfPossession{}
+	if err := pop.UnmarshalJSON(popBytes); err != nil {
+		return nil, fmt.Errorf("invalid BLS info: %w", err)
+	}
+	if err := pop.Verify(); err != nil {
+		return nil, fmt.Errorf("invalid BLS proof of possession: %w", err)
+	}
+	return pop, nil
+}
+
+// EstimatePrimaryNetworkReward returns the reward a primary network validator staking
+// [stake] for [duration] would get, given the current AVAX supply. The reward is only
+// given if the validator keeps enough uptime
+func EstimatePrimaryNetworkReward(
+	networkEndpoint string,
+	rewardConfig reward.Config,
+	duration time.Duration,
+	stake uint64,
+) (uint64, error) {
+	pClient := platformvm.NewClient(networkEndpoint)
+	currentSupply, err := CallAPI(networkEndpoint, func(ctx context.Context) (uint64, error) {
+		supply, _, err := pClient.GetCurrentSupply(ctx, ids.Empty)
+		return supply, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return reward.NewCalculator(rewardConfig).Calculate(duration, stake, currentSupply), nil
+}
+
 func GetRemainingValidationTime(networkEndpoint string, nodeID ids.NodeID, subnetID ids.ID, startTime time.Time) (time.Duration, error) {
-	ctx, cancel := GetAPIContext()
-	defer cancel()
 	platformCli := platformvm.NewClient(networkEndpoint)
-	vs, err := platformCli.GetCurrentValidators(ctx, subnetID, nil)
-	cancel()
+	vs, err := CallAPI(networkEndpoint, func(ctx context.Context) ([]platformvm.ClientPermissionlessValidator, error) {
+		return platformCli.GetCurrentValidators(ctx, subnetID, nil)
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -98,14 +142,14 @@ func GetRemainingValidationTime(networkEndpoint string, nodeID ids.NodeID, subne
 
 // GetL1ValidatorUptimeSeconds returns the uptime of the L1 validator
 func GetL1ValidatorUptimeSeconds(rpcURL string, nodeID ids.NodeID) (uint64, error) {
-	ctx, cancel := GetAPIContext()
-	defer cancel()
 	networkEndpoint, blockchainID, err := SplitAvalanchegoRPCURI(rpcURL)
 	if err != nil {
 		return 0, err
 	}
 	evmCli := evm.NewClient(networkEndpoint, blockchainID)
-	validators, err := evmCli.GetCurrentValidators(ctx, []ids.NodeID{nodeID})
+	validators, err := CallAPI(networkEndpoint, func(ctx context.Context) ([]evm.CurrentValidator, error) {
+		return evmCli.GetCurrentValidators(ctx, []ids.NodeID{nodeID})
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -115,3 +159,19 @@ func GetL1ValidatorUptimeSeconds(rpcURL string, nodeID ids.NodeID) (uint64, erro
 
 	return 0, errors.New("nodeID not found in validator set: " + nodeID.String())
 }
+
+// GetL1Validators returns the current validators of the L1, as seen by the node serving [rpcURL]
+func GetL1Validators(rpcURL string) ([]evm.CurrentValidator, error) {
+	networkEndpoint, blockchainID, err := SplitAvalanchegoRPCURI(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	evmCli := evm.NewClient(networkEndpoint, blockchainID)
+	options := []rpc.Option{}
+	if token, ok := rpcauth.Tokens.Token(rpcURL); ok {
+		options = append(options, rpc.WithHeader("Authorization", rpcauth.AuthorizationHeader(token)))
+	}
+	return CallAPI(networkEndpoint, func(ctx context.Context) ([]evm.CurrentValidator, error) {
+		return evmCli.GetCurrentValidators(ctx, nil, options...)
+	})
+}
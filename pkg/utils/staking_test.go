@@ -0,0 +1,42 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBLSInfo(t *testing.T) (string, string) {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	popBytes, err := signer.NewProofOfPossession(sk).MarshalJSON()
+	require.NoError(t, err)
+	info := map[string]string{}
+	require.NoError(t, json.Unmarshal(popBytes, &info))
+	return info["publicKey"], info["proofOfPossession"]
+}
+
+func TestParseBLSPoP(t *testing.T) {
+	publicKey, pop := newTestBLSInfo(t)
+	otherPublicKey, otherPop := newTestBLSInfo(t)
+
+	parsed, err := ParseBLSPoP(publicKey, pop)
+	require.NoError(t, err)
+	parsedJSON, err := parsed.MarshalJSON()
+	require.NoError(t, err)
+	require.Contains(t, string(parsedJSON), publicKey)
+
+	_, err = ParseBLSPoP(publicKey, otherPop)
+	require.ErrorContains(t, err, "invalid BLS proof of possession")
+
+	_, err = ParseBLSPoP(otherPublicKey, pop)
+	require.ErrorContains(t, err, "invalid BLS proof of possession")
+
+	_, err = ParseBLSPoP("0x1234", pop)
+	require.ErrorContains(t, err, "invalid BLS info")
+}
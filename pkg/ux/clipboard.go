@@ -0,0 +1,133 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+var errNoClipboardTool = errors.New("no clipboard tool found")
+
+// clipboardCommands returns the native clipboard tools that may be available
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	}
+	cmds := [][]string{}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-copy"})
+	}
+	if os.Getenv("DISPLAY") != "" {
+		cmds = append(cmds, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+	}
+	return cmds
+}
+
+// copyWithNativeTool pipes [text] into the first available native clipboard tool
+func copyWithNativeTool(text string) error {
+	for _, args := range clipboardCommands() {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...) //nolint:gosec
+		cmd.Stdin = strings.NewReader(text)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s failed: %w: %s", args[0], err, out)
+		}
+		return nil
+	}
+	return errNoClipboardTool
+}
+
+// CopyToClipboard copies [text] to the system clipboard. When no native clipboard tool
+// is available, as on ssh sessions, the OSC 52 escape sequence is sent instead, so
+// terminals supporting it set their local clipboard.
+// Returns true if a native tool was used
+func CopyToClipboard(text string) (bool, error) {
+	err := copyWithNativeTool(text)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, errNoClipboardTool) {
+		return false, err
+	}
+	Logger.PrintRawToUser(fmt.Sprintf("\033]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text))))
+	return false, nil
+}
+
+// PrintQRCode renders [text] as a QR code on the terminal, preferring medium error
+// correction, and falling back to low for long contents
+func PrintQRCode(text string) error {
+	q, err := qrcode.New(text, qrcode.Medium)
+	if err != nil {
+		q, err = qrcode.New(text, qrcode.Low)
+	}
+	if err != nil {
+		return err
+	}
+	Logger.PrintRawToUser(terminalQRCode(q.Bitmap()))
+	return nil
+}
+
+// terminalQRCode renders [bitmap], including its quiet zone, using half block characters
+// so each text line holds two rows of modules. Colors are explicitly set, as scanners
+// need dark modules over a light background
+func terminalQRCode(bitmap [][]bool) string {
+	const (
+		upperHalfBlock = "▀"
+		reset          = "\033[0m"
+	)
+	dark := func(x, y int) bool {
+		return y < len(bitmap) && bitmap[y][x]
+	}
+	var sb strings.Builder
+	for y := 0; y < len(bitmap); y += 2 {
+		for x := range bitmap[y] {
+			// foreground draws the upper module, background the lower one
+			fg, bg := 97, 107
+			if dark(x, y) {
+				fg = 30
+			}
+			if dark(x, y+1) {
+				bg = 40
+			}
+			sb.WriteString(fmt.Sprintf("\033[%d;%dm%s", fg, bg, upperHalfBlock))
+		}
+		sb.WriteString(reset + "\n")
+	}
+	return sb.String()
+}
+
+// ShareValue copies [value] to the clipboard if [copyValue] is set, and renders it as a
+// QR code if [qr] is set, describing it to the user as [what]
+func ShareValue(what string, value string, copyValue bool, qr bool) error {
+	if qr {
+		Logger.PrintToUser("%s:", what)
+		if err := PrintQRCode(value); err != nil {
+			return fmt.Errorf("failure rendering %s as QR code: %w", what, err)
+		}
+	}
+	if copyValue {
+		native, err := CopyToClipboard(value)
+		if err != nil {
+			return fmt.Errorf("failure copying %s to clipboard: %w", what, err)
+		}
+		if native {
+			Logger.GreenCheckmarkToUser("%s copied to clipboard", what)
+		} else {
+			Logger.PrintToUser("%s sent to the terminal clipboard (OSC 52). If it was not copied, install xclip, xsel or wl-copy", what)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalQRCode(t *testing.T) {
+	require := require.New(t)
+	// odd number of rows, the last line only draws its upper module
+	bitmap := [][]bool{
+		{true, false},
+		{false, true},
+		{true, true},
+	}
+	require.Equal(
+		"\033[30;107m▀\033[97;40m▀\033[0m\n"+
+			"\033[30;107m▀\033[30;107m▀\033[0m\n",
+		terminalQRCode(bitmap),
+	)
+}
+
+func TestPrintQRCode(t *testing.T) {
+	require := require.New(t)
+	var out strings.Builder
+	prevLogger := Logger
+	Logger = &UserLog{Writer: &out}
+	defer func() { Logger = prevLogger }()
+
+	require.NoError(PrintQRCode("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"))
+	// version 3 at medium correction is 29 modules, plus 4 modules of quiet zone each side
+	require.Len(strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"), (29+8+1)/2)
+
+	// contents only fitting with low error correction
+	require.NoError(PrintQRCode(strings.Repeat("a", 2900)))
+	require.Error(PrintQRCode(strings.Repeat("a", 3000)))
+}
@@ -5,14 +5,17 @@ package ux
 import (
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
+	"golang.org/x/term"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/fatih/color"
+	"go.uber.org/zap"
 )
 
 var Logger *UserLog
@@ -20,6 +23,8 @@ var Logger *UserLog
 type UserLog struct {
 	log    logging.Logger
 	Writer io.Writer
+	// quiet only sends the messages to the log file
+	quiet bool
 }
 
 func NewUserLog(log logging.Logger, userwriter io.Writer) {
@@ -31,15 +36,54 @@ func NewUserLog(log logging.Logger, userwriter io.Writer) {
 	}
 }
 
+// SetQuiet stops printing messages on the screen, they are still sent to the log file
+func (ul *UserLog) SetQuiet(quiet bool) {
+	ul.quiet = quiet
+}
+
+// SetWriter changes where the messages for the user are printed, eg to stderr when
+// stdout holds machine readable output, and returns the previous writer
+func (ul *UserLog) SetWriter(w io.Writer) io.Writer {
+	prev := ul.Writer
+	ul.Writer = w
+	return prev
+}
+
 // PrintToUser prints msg directly on the screen, but also to log file
 func (ul *UserLog) PrintToUser(msg string, args ...interface{}) {
-	fmt.Print("\r\033[K") // Clear the line from the cursor position to the end
+	switch {
+	case ul == nil:
+		clearLine(os.Stdout)
+	case !ul.quiet:
+		clearLine(ul.Writer)
+	}
 	ul.print(fmt.Sprintf(msg, args...) + "\n")
 }
 
+// clearLine clears the line from the cursor position to the end, if [w] is a terminal.
+// Redirected output is kept free of control sequences
+func clearLine(w io.Writer) {
+	if f, ok := w.(interface{ Fd() uintptr }); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprint(w, "\r\033[K")
+	}
+}
+
+// PrintRawToUser writes [text] as is on the screen, for terminal graphics and control
+// sequences that are meaningless on the log file
+func (ul *UserLog) PrintRawToUser(text string) {
+	switch {
+	case ul == nil:
+		fmt.Print(text)
+	case !ul.quiet:
+		fmt.Fprint(ul.Writer, text)
+	}
+}
+
 func (ul *UserLog) print(msg string) {
 	if ul != nil {
-		fmt.Fprint(ul.Writer, msg)
+		if !ul.quiet {
+			fmt.Fprint(ul.Writer, msg)
+		}
 		ul.log.Info(msg)
 	} else {
 		fmt.Print(msg)
@@ -69,6 +113,23 @@ func (ul *UserLog) RedXToUser(msg string, args ...interface{}) {
 	ul.PrintToUser(red(xmark)+" "+msg, args...)
 }
 
+// userWarnLogger is a logger that also prints its warnings to the user
+type userWarnLogger struct {
+	logging.Logger
+	ul *UserLog
+}
+
+func (l userWarnLogger) Warn(msg string, fields ...zap.Field) {
+	l.ul.PrintToUser("Warning: %s", msg)
+	l.Logger.Warn(msg, fields...)
+}
+
+// WithUserWarnings returns a logger that writes to [log], and also prints the warnings
+// to the user. To be given to the sdk operations called by the CLI
+func (ul *UserLog) WithUserWarnings(log logging.Logger) logging.Logger {
+	return userWarnLogger{Logger: log, ul: ul}
+}
+
 func (ul *UserLog) PrintLineSeparator() {
 	ul.PrintToUser("==============================================")
 }
@@ -0,0 +1,39 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetWriter(t *testing.T) {
+	require := require.New(t)
+	var stdout, stderr bytes.Buffer
+	ul := &UserLog{log: logging.NoLog{}, Writer: &stdout}
+	ul.PrintToUser("first")
+	prev := ul.SetWriter(&stderr)
+	require.Equal(&stdout, prev)
+	ul.PrintToUser("second")
+	ul.SetWriter(prev)
+	ul.PrintToUser("third")
+	require.Equal("first\nthird\n", stdout.String())
+	require.Equal("second\n", stderr.String())
+
+	ul.SetQuiet(true)
+	ul.PrintToUser("quiet")
+	require.Equal("first\nthird\n", stdout.String())
+}
+
+func TestWithUserWarnings(t *testing.T) {
+	require := require.New(t)
+	var stdout bytes.Buffer
+	ul := &UserLog{log: logging.NoLog{}, Writer: &stdout}
+	log := ul.WithUserWarnings(logging.NoLog{})
+	log.Info("not for the user")
+	log.Warn("the PoA contract is already initialized")
+	require.Equal("Warning: the PoA contract is already initialized\n", stdout.String())
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// ChurnBatch is a group of validator registrations that fits in the churn allowed by a
+// validator manager on a churn period
+type ChurnBatch struct {
+	// indexes of the registrations on the planned weights
+	Indexes []int
+	Weight  uint64
+	// unix time since which the registrations of the batch fit in the churn limit
+	NotBefore uint64
+}
+
+// PlanChurnBatches splits the registration of validators with [weights] into batches that
+// do not exceed the churn limit of a validator manager with [state], at unix time [now].
+// It follows the manager churn tracker: a churn period starts on the first weight change
+// after the previous one ended, and allows changes of up to MaximumChurnPercentage of the
+// weight the L1 had at its start. Lighter validators are registered first, so the L1
+// weight grows for the heavier ones. Fails if a validator never fits in a churn period
+func PlanChurnBatches(state ManagerState, now uint64, weights []uint64) ([]ChurnBatch, error) {
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return weights[order[i]] < weights[order[j]] })
+	var (
+		batches       []ChurnBatch
+		periodStart   = state.ChurnPeriodStartedAt
+		initialWeight = state.ChurnPeriodInitialWeight
+		churnAmount   = state.ChurnPeriodChurnAmount
+		totalWeight   = state.ChurnPeriodTotalWeight
+		currentTime   = now
+	)
+	periodEnded := func() bool {
+		return periodStart == 0 || currentTime >= periodStart+state.ChurnPeriodSeconds
+	}
+	for _, index := range order {
+		weight := weights[index]
+		if weight == 0 {
+			return nil, fmt.Errorf("validator weight must be positive")
+		}
+		if periodEnded() {
+			periodStart, initialWeight, churnAmount = currentTime, totalWeight, 0
+		}
+		if !withinChurnLimit(state.MaximumChurnPercentage, initialWeight, churnAmount+weight) && churnAmount > 0 {
+			// the churn of the current period is consumed, the registration waits for the next one
+			currentTime = periodStart + state.ChurnPeriodSeconds
+			periodStart, initialWeight, churnAmount = currentTime, totalWeight, 0
+		}
+		if !withinChurnLimit(state.MaximumChurnPercentage, initialWeight, churnAmount+weight) {
+			return nil, fmt.Errorf(
+				"validator weight %d exceeds the maximum churn of %d%% of the L1 weight %d",
+				weight,
+				state.MaximumChurnPercentage,
+				initialWeight,
+			)
+		}
+		churnAmount += weight
+		totalWeight += weight
+		if len(batches) == 0 || batches[len(batches)-1].NotBefore != currentTime {
+			batches = append(batches, ChurnBatch{NotBefore: currentTime})
+		}
+		batch := &batches[len(batches)-1]
+		batch.Indexes = append(batch.Indexes, index)
+		batch.Weight += weight
+	}
+	return batches, nil
+}
+
+// withinChurnLimit replicates the manager check, that reverts if
+// maximumChurnPercentage * initialWeight < churnAmount * 100
+func withinChurnLimit(maximumChurnPercentage uint8, initialWeight uint64, churnAmount uint64) bool {
+	limit := new(big.Int).Mul(big.NewInt(int64(maximumChurnPercentage)), new(big.Int).SetUint64(initialWeight))
+	churn := new(big.Int).Mul(new(big.Int).SetUint64(churnAmount), big.NewInt(100))
+	return limit.Cmp(churn) >= 0
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanChurnBatchesNoChurnPeriod(t *testing.T) {
+	require := require.New(t)
+	// every registration is checked against the L1 weight it is made on
+	state := ManagerState{
+		MaximumChurnPercentage: 20,
+		ChurnPeriodTotalWeight: 500,
+	}
+	batches, err := PlanChurnBatches(state, 1000, []uint64{100, 100, 100, 100, 100, 100})
+	require.NoError(err)
+	require.Equal([]ChurnBatch{{Indexes: []int{0, 1, 2, 3, 4, 5}, Weight: 600, NotBefore: 1000}}, batches)
+	// lighter validators first let the heavier ones fit
+	batches, err = PlanChurnBatches(state, 1000, []uint64{120, 100})
+	require.NoError(err)
+	require.Equal([]int{1, 0}, batches[0].Indexes)
+	_, err = PlanChurnBatches(state, 1000, []uint64{200})
+	require.ErrorContains(err, "exceeds the maximum churn")
+	_, err = PlanChurnBatches(state, 1000, []uint64{0})
+	require.Error(err)
+}
+
+func TestPlanChurnBatchesChurnPeriod(t *testing.T) {
+	require := require.New(t)
+	state := ManagerState{
+		ChurnPeriodSeconds:     3600,
+		MaximumChurnPercentage: 20,
+		ChurnPeriodTotalWeight: 500,
+	}
+	batches, err := PlanChurnBatches(state, 1000, []uint64{50, 50, 50, 100})
+	require.NoError(err)
+	require.Equal([]ChurnBatch{
+		{Indexes: []int{0, 1}, Weight: 100, NotBefore: 1000},
+		{Indexes: []int{2}, Weight: 50, NotBefore: 4600},
+		{Indexes: []int{3}, Weight: 100, NotBefore: 8200},
+	}, batches)
+
+	// churn of the current period already consumed
+	state.ChurnPeriodStartedAt = 900
+	state.ChurnPeriodInitialWeight = 500
+	state.ChurnPeriodChurnAmount = 100
+	batches, err = PlanChurnBatches(state, 1000, []uint64{50})
+	require.NoError(err)
+	require.Equal([]ChurnBatch{{Indexes: []int{0}, Weight: 50, NotBefore: 4500}}, batches)
+
+	// a validator heavier than the churn of a full period never fits
+	_, err = PlanChurnBatches(state, 1000, []uint64{50, 150})
+	require.ErrorContains(err, "exceeds the maximum churn")
+}
@@ -0,0 +1,722 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/sdk/interchain"
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	warp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	warpMessage "github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
+	warpPayload "github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const delegatorAddedEventDelegatorTopicSlot = 3
+
+// delegator status, as reconstructed from the validator manager events
+const (
+	DelegatorStatusPendingAdded   = "PendingAdded"
+	DelegatorStatusActive         = "Active"
+	DelegatorStatusPendingRemoved = "PendingRemoved"
+	DelegatorStatusCompleted      = "Completed"
+)
+
+var (
+	delegatorAddedEventSpec              = eventSpec(validatorManagerSDK.DelegatorAddedEventSignature)
+	delegatorRegisteredEventSpec         = eventSpec(validatorManagerSDK.DelegatorRegisteredEventSignature)
+	delegatorRemovalInitializedEventSpec = eventSpec(validatorManagerSDK.DelegatorRemovalInitializedEventSignature)
+	delegationEndedEventSpec             = eventSpec(validatorManagerSDK.DelegationEndedEventSignature)
+	validatorWeightUpdateEventSpec       = eventSpec(validatorManagerSDK.ValidatorWeightUpdateEventSignature)
+)
+
+// events
+
+type DelegatorAdded struct {
+	DelegationID       [32]byte
+	ValidationID       [32]byte
+	DelegatorAddress   common.Address
+	Nonce              uint64
+	ValidatorWeight    uint64
+	DelegatorWeight    uint64
+	SetWeightMessageID [32]byte
+}
+
+type DelegatorRegistered struct {
+	DelegationID [32]byte
+	ValidationID [32]byte
+	StartTime    *big.Int
+}
+
+type DelegatorRemovalInitialized struct {
+	DelegationID [32]byte
+	ValidationID [32]byte
+}
+
+type DelegationEnded struct {
+	DelegationID [32]byte
+	ValidationID [32]byte
+	Rewards      *big.Int
+	Fees         *big.Int
+}
+
+type ValidatorWeightUpdate struct {
+	ValidationID       [32]byte
+	Nonce              uint64
+	Weight             uint64
+	SetWeightMessageID [32]byte
+}
+
+func ParseDelegatorAdded(log types.Log) (*DelegatorAdded, error) {
+	event := new(DelegatorAdded)
+	if err := contract.UnpackLog(delegatorAddedEventSpec, []int{0, 1, 2}, log, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func ParseDelegatorRegistered(log types.Log) (*DelegatorRegistered, error) {
+	event := new(DelegatorRegistered)
+	if err := contract.UnpackLog(delegatorRegisteredEventSpec, []int{0, 1}, log, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func ParseDelegatorRemovalInitialized(log types.Log) (*DelegatorRemovalInitialized, error) {
+	event := new(DelegatorRemovalInitialized)
+	if err := contract.UnpackLog(delegatorRemovalInitializedEventSpec, []int{0, 1}, log, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func ParseDelegationEnded(log types.Log) (*DelegationEnded, error) {
+	event := new(DelegationEnded)
+	if err := contract.UnpackLog(delegationEndedEventSpec, []int{0, 1}, log, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func ParseValidatorWeightUpdate(log types.Log) (*ValidatorWeightUpdate, error) {
+	event := new(ValidatorWeightUpdate)
+	if err := contract.UnpackLog(validatorWeightUpdateEventSpec, []int{0, 1}, log, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// contract calls
+
+// step 1 of flow for adding a new delegator. [stakeAmount] is sent
+// with the tx and locked by the native token staking manager
+func InitializeDelegatorRegistration(
+	rpcURL string,
+	managerAddress common.Address,
+	delegatorPrivateKey string,
+	validationID ids.ID,
+	stakeAmount *big.Int,
+) (*types.Transaction, *types.Receipt, error) {
+	return contract.TxToMethod(
+		rpcURL,
+		delegatorPrivateKey,
+		managerAddress,
+		stakeAmount,
+		"initialize delegator registration",
+		validatorManagerSDK.ErrorSignatureToError,
+		"initializeDelegatorRegistration(bytes32)",
+		validationID,
+	)
+}
+
+// step 1 of flow for adding a new delegator on an ERC20 token staking manager.
+// [stakeAmount] is transferred from the delegator, that must have approved it to the manager
+func InitializeDelegatorRegistrationERC20(
+	rpcURL string,
+	managerAddress common.Address,
+	delegatorPrivateKey string,
+	validationID ids.ID,
+	stakeAmount *big.Int,
+) (*types.Transaction, *types.Receipt, error) {
+	return contract.TxToMethod(
+		rpcURL,
+		delegatorPrivateKey,
+		managerAddress,
+		nil,
+		"initialize delegator registration with ERC20 stake",
+		validatorManagerSDK.ErrorSignatureToError,
+		"initializeDelegatorRegistration(bytes32,uint256)",
+		validationID,
+		stakeAmount,
+	)
+}
+
+// last step of flow for adding a new delegator
+func CompleteDelegatorRegistration(
+	rpcURL string,
+	managerAddress common.Address,
+	privateKey string,
+	delegationID ids.ID,
+	l1ValidatorWeightSignedMessage *warp.Message,
+) (*types.Transaction, *types.Receipt, error) {
+	return contract.TxToMethodWithWarpMessage(
+		rpcURL,
+		privateKey,
+		managerAddress,
+		l1ValidatorWeightSignedMessage,
+		big.NewInt(0),
+		"complete delegator registration",
+		validatorManagerSDK.ErrorSignatureToError,
+		"completeDelegatorRegistration(bytes32,uint32)",
+		delegationID,
+		uint32(0),
+	)
+}
+
+// step 1 of flow for removing a delegator. If [force] is set, no uptime
+// proof is provided, and the delegator may not be rewarded
+func InitializeEndDelegation(
+	rpcURL string,
+	managerAddress common.Address,
+	delegatorPrivateKey string,
+	delegationID ids.ID,
+	uptimeProofSignedMessage *warp.Message,
+	force bool,
+) (*types.Transaction, *types.Receipt, error) {
+	if force {
+		return contract.TxToMethod(
+			rpcURL,
+			delegatorPrivateKey,
+			managerAddress,
+			big.NewInt(0),
+			"force delegator removal",
+			validatorManagerSDK.ErrorSignatureToError,
+			"forceInitializeEndDelegation(bytes32,bool,uint32)",
+			delegationID,
+			false, // no uptime proof if force
+			uint32(0),
+		)
+	}
+	return contract.TxToMethodWithWarpMessage(
+		rpcURL,
+		delegatorPrivateKey,
+		managerAddress,
+		uptimeProofSignedMessage,
+		big.NewInt(0),
+		"delegator removal with uptime proof",
+		validatorManagerSDK.ErrorSignatureToError,
+		"initializeEndDelegation(bytes32,bool,uint32)",
+		delegationID,
+		true, // submit uptime proof
+		uint32(0),
+	)
+}
+
+// last step of flow for removing a delegator. Stake and rewards
+// are sent to the delegator
+func CompleteEndDelegation(
+	rpcURL string,
+	managerAddress common.Address,
+	privateKey string,
+	delegationID ids.ID,
+	l1ValidatorWeightSignedMessage *warp.Message,
+) (*types.Transaction, *types.Receipt, error) {
+	if l1ValidatorWeightSignedMessage == nil {
+		// validator already ended, no P-Chain acknowledgement is needed
+		return contract.TxToMethod(
+			rpcURL,
+			privateKey,
+			managerAddress,
+			big.NewInt(0),
+			"complete delegator removal",
+			validatorManagerSDK.ErrorSignatureToError,
+			"completeEndDelegation(bytes32,uint32)",
+			delegationID,
+			uint32(0),
+		)
+	}
+	return contract.TxToMethodWithWarpMessage(
+		rpcURL,
+		privateKey,
+		managerAddress,
+		l1ValidatorWeightSignedMessage,
+		big.NewInt(0),
+		"complete delegator removal",
+		validatorManagerSDK.ErrorSignatureToError,
+		"completeEndDelegation(bytes32,uint32)",
+		delegationID,
+		uint32(0),
+	)
+}
+
+// GetPChainL1ValidatorWeightMessage returns the P-Chain acknowledgement of a
+// validator weight change, signed by the L1 validators
+func GetPChainL1ValidatorWeightMessage(
+	network models.Network,
+	aggregatorLogLevel logging.Level,
+	aggregationConfig interchain.AggregationConfig,
+	aggregatorAllowPrivateIPs bool,
+	aggregatorExtraPeerEndpoints []info.Peer,
+	subnetID ids.ID,
+	validationID ids.ID,
+	nonce uint64,
+	weight uint64,
+) (*warp.Message, error) {
+	addressedCallPayload, err := warpMessage.NewL1ValidatorWeight(
+		validationID,
+		nonce,
+		weight,
+	)
+	if err != nil {
+		return nil, err
+	}
+	addressedCall, err := warpPayload.NewAddressedCall(
+		nil,
+		addressedCallPayload.Bytes(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	unsignedMessage, err := warp.NewUnsignedMessage(
+		network.ID,
+		avagoconstants.PlatformChainID,
+		addressedCall.Bytes(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return interchain.SignMessage(
+		network.SDKNetwork(),
+		aggregatorLogLevel,
+		subnetID,
+		aggregationConfig,
+		aggregatorAllowPrivateIPs,
+		aggregatorExtraPeerEndpoints,
+		unsignedMessage,
+		nil,
+	)
+}
+
+// flows
+
+// InitDelegatorRegistration locks [stakeAmount] on the validator manager as a delegation to [nodeID],
+// and returns the L1 validator weight message to be submitted to P-Chain, together with
+// the delegation ID and the new validator weight information
+func InitDelegatorRegistration(
+	app *application.Avalanche,
+	network models.Network,
+	rpcURL string,
+	chainSpec contract.ChainSpec,
+	delegatorPrivateKey string,
+	nodeID ids.NodeID,
+	stakeAmount *big.Int,
+	aggregatorExtraPeerEndpoints []info.Peer,
+	aggregatorAllowPrivatePeers bool,
+	aggregatorLogLevelStr string,
+	aggregationConfig interchain.AggregationConfig,
+	erc20Staking bool,
+) (*warp.Message, ids.ID, *DelegatorAdded, error) {
+	subnetID, err := contract.GetSubnetID(app, network, chainSpec)
+	if err != nil {
+		return nil, ids.Empty, nil, err
+	}
+	blockchainID, err := contract.GetBlockchainID(app, network, chainSpec)
+	if err != nil {
+		return nil, ids.Empty, nil, err
+	}
+	managerAddress := common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+	validationID, err := GetRegisteredValidator(rpcURL, managerAddress, nodeID)
+	if err != nil {
+		return nil, ids.Empty, nil, err
+	}
+	if validationID == ids.Empty {
+		return nil, ids.Empty, nil, fmt.Errorf("node %s is not a validator of the L1", nodeID)
+	}
+	ux.Logger.PrintLineSeparator()
+	ux.Logger.PrintToUser("Initializing a delegator registration with PoS validator manager")
+	ux.Logger.PrintToUser("Using rpcURL: %s", rpcURL)
+	ux.Logger.PrintToUser("NodeID: %s ValidationID: %s staking %s", nodeID, validationID, stakeAmount)
+	ux.Logger.PrintLineSeparator()
+	var (
+		tx      *types.Transaction
+		receipt *types.Receipt
+	)
+	if erc20Staking {
+		if err := ApproveStake(rpcURL, managerAddress, delegatorPrivateKey, stakeAmount); err != nil {
+			return nil, ids.Empty, nil, err
+		}
+		tx, receipt, err = InitializeDelegatorRegistrationERC20(
+			rpcURL,
+			managerAddress,
+			delegatorPrivateKey,
+			validationID,
+			stakeAmount,
+		)
+	} else {
+		tx, receipt, err = InitializeDelegatorRegistration(
+			rpcURL,
+			managerAddress,
+			delegatorPrivateKey,
+			validationID,
+			stakeAmount,
+		)
+	}
+	if err != nil {
+		return nil, ids.Empty, nil, evm.TransactionError(tx, err, "failure initializing delegator registration")
+	}
+	event, err := evm.GetEventFromLogs(receipt.Logs, ParseDelegatorAdded)
+	if err != nil {
+		return nil, ids.Empty, nil, err
+	}
+	aggregatorLogLevel, err := logging.ToLevel(aggregatorLogLevelStr)
+	if err != nil {
+		aggregatorLogLevel = defaultAggregatorLogLevel
+	}
+	signedMsg, err := GetSubnetValidatorWeightMessage(
+		network,
+		aggregatorLogLevel,
+		aggregationConfig,
+		aggregatorAllowPrivatePeers,
+		aggregatorExtraPeerEndpoints,
+		subnetID,
+		blockchainID,
+		managerAddress,
+		validationID,
+		event.Nonce,
+		event.ValidatorWeight,
+	)
+	return signedMsg, event.DelegationID, event, err
+}
+
+// FinishDelegatorRegistration submits to the validator manager the P-Chain
+// acknowledgement of the validator weight change for the delegation
+func FinishDelegatorRegistration(
+	app *application.Avalanche,
+	network models.Network,
+	rpcURL string,
+	chainSpec contract.ChainSpec,
+	privateKey string,
+	delegationID ids.ID,
+	validationID ids.ID,
+	nonce uint64,
+	weight uint64,
+	aggregatorExtraPeerEndpoints []info.Peer,
+	aggregatorAllowPrivatePeers bool,
+	aggregatorLogLevelStr string,
+	aggregationConfig interchain.AggregationConfig,
+) error {
+	subnetID, err := contract.GetSubnetID(app, network, chainSpec)
+	if err != nil {
+		return err
+	}
+	aggregatorLogLevel, err := logging.ToLevel(aggregatorLogLevelStr)
+	if err != nil {
+		aggregatorLogLevel = defaultAggregatorLogLevel
+	}
+	managerAddress := common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+	signedMessage, err := GetPChainL1ValidatorWeightMessage(
+		network,
+		aggregatorLogLevel,
+		aggregationConfig,
+		aggregatorAllowPrivatePeers,
+		aggregatorExtraPeerEndpoints,
+		subnetID,
+		validationID,
+		nonce,
+		weight,
+	)
+	if err != nil {
+		return err
+	}
+	if err := evm.SetupProposerVM(rpcURL, privateKey); err != nil {
+		return err
+	}
+	tx, _, err := CompleteDelegatorRegistration(
+		rpcURL,
+		managerAddress,
+		privateKey,
+		delegationID,
+		signedMessage,
+	)
+	if err != nil {
+		return evm.TransactionError(tx, err, "failure completing delegator registration")
+	}
+	return nil
+}
+
+// InitEndDelegation starts the removal of [delegationID] from validator [nodeID]. It returns the
+// L1 validator weight message to be submitted to P-Chain, together with the weight update
+// information. Both are nil if the validator already ended, and no P-Chain update is needed
+func InitEndDelegation(
+	app *application.Avalanche,
+	network models.Network,
+	rpcURL string,
+	chainSpec contract.ChainSpec,
+	delegatorPrivateKey string,
+	nodeID ids.NodeID,
+	validationID ids.ID,
+	delegationID ids.ID,
+	aggregatorExtraPeerEndpoints []info.Peer,
+	aggregatorAllowPrivatePeers bool,
+	aggregatorLogLevelStr string,
+	aggregationConfig interchain.AggregationConfig,
+	uptimeSec uint64,
+	force bool,
+) (*warp.Message, *ValidatorWeightUpdate, error) {
+	subnetID, err := contract.GetSubnetID(app, network, chainSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	blockchainID, err := contract.GetBlockchainID(app, network, chainSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	managerAddress := common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+	aggregatorLogLevel, err := logging.ToLevel(aggregatorLogLevelStr)
+	if err != nil {
+		aggregatorLogLevel = defaultAggregatorLogLevel
+	}
+	signedUptimeProof := &warp.Message{}
+	if !force {
+		if uptimeSec == 0 {
+			uptimeSec, err = utils.GetL1ValidatorUptimeSeconds(rpcURL, nodeID)
+			if err != nil {
+				return nil, nil, evm.TransactionError(nil, err, "failure getting uptime data for nodeID: %s via %s ", nodeID, rpcURL)
+			}
+		}
+		ux.Logger.PrintToUser("Using validator uptime: %ds", uptimeSec)
+		signedUptimeProof, err = GetUptimeProofMessage(
+			network,
+			aggregatorLogLevel,
+			aggregationConfig,
+			aggregatorExtraPeerEndpoints,
+			subnetID,
+			blockchainID,
+			validationID,
+			uptimeSec,
+		)
+		if err != nil {
+			return nil, nil, evm.TransactionError(nil, err, "failure getting uptime proof")
+		}
+	}
+	tx, receipt, err := InitializeEndDelegation(
+		rpcURL,
+		managerAddress,
+		delegatorPrivateKey,
+		delegationID,
+		signedUptimeProof,
+		force,
+	)
+	if err != nil {
+		return nil, nil, evm.TransactionError(tx, err, "failure initializing delegator removal")
+	}
+	event, err := evm.GetEventFromLogs(receipt.Logs, ParseValidatorWeightUpdate)
+	if err != nil {
+		// validator is no longer active, so the delegation ends without weight change
+		return nil, nil, nil
+	}
+	signedMsg, err := GetSubnetValidatorWeightMessage(
+		network,
+		aggregatorLogLevel,
+		aggregationConfig,
+		aggregatorAllowPrivatePeers,
+		aggregatorExtraPeerEndpoints,
+		subnetID,
+		blockchainID,
+		managerAddress,
+		event.ValidationID,
+		event.Nonce,
+		event.Weight,
+	)
+	return signedMsg, event, err
+}
+
+// FinishEndDelegation completes the removal of [delegationID], returning the stake
+// and the rewards to the delegator. [weightUpdate] is nil if no P-Chain
+// acknowledgement is needed
+func FinishEndDelegation(
+	app *application.Avalanche,
+	network models.Network,
+	rpcURL string,
+	chainSpec contract.ChainSpec,
+	privateKey string,
+	delegationID ids.ID,
+	weightUpdate *ValidatorWeightUpdate,
+	aggregatorExtraPeerEndpoints []info.Peer,
+	aggregatorAllowPrivatePeers bool,
+	aggregatorLogLevelStr string,
+	aggregationConfig interchain.AggregationConfig,
+) (*DelegationEnded, error) {
+	managerAddress := common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+	var signedMessage *warp.Message
+	if weightUpdate != nil {
+		subnetID, err := contract.GetSubnetID(app, network, chainSpec)
+		if err != nil {
+			return nil, err
+		}
+		aggregatorLogLevel, err := logging.ToLevel(aggregatorLogLevelStr)
+		if err != nil {
+			aggregatorLogLevel = defaultAggregatorLogLevel
+		}
+		signedMessage, err = GetPChainL1ValidatorWeightMessage(
+			network,
+			aggregatorLogLevel,
+			aggregationConfig,
+			aggregatorAllowPrivatePeers,
+			aggregatorExtraPeerEndpoints,
+			subnetID,
+			weightUpdate.ValidationID,
+			weightUpdate.Nonce,
+			weightUpdate.Weight,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := evm.SetupProposerVM(rpcURL, privateKey); err != nil {
+		return nil, err
+	}
+	tx, receipt, err := CompleteEndDelegation(
+		rpcURL,
+		managerAddress,
+		privateKey,
+		delegationID,
+		signedMessage,
+	)
+	if err != nil {
+		return nil, evm.TransactionError(tx, err, "failure completing delegator removal")
+	}
+	return evm.GetEventFromLogs(receipt.Logs, ParseDelegationEnded)
+}
+
+// Delegation is the state of a delegation, as reconstructed from the validator manager events
+type Delegation struct {
+	DelegationID ids.ID
+	ValidationID ids.ID
+	Delegator    common.Address
+	Weight       uint64
+	Status       string
+	Rewards      *big.Int
+}
+
+// GetDelegations returns all delegations made by [delegator] on the validator manager at [rpcURL]
+func GetDelegations(
+	rpcURL string,
+	delegator common.Address,
+) ([]Delegation, error) {
+	managerAddress := common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	topics := make([][]common.Hash, delegatorAddedEventDelegatorTopicSlot+1)
+	topics[0] = []common.Hash{eventTopic(delegatorAddedEventSpec)}
+	topics[delegatorAddedEventDelegatorTopicSlot] = []common.Hash{common.BytesToHash(delegator.Bytes())}
+	addedLogs, err := client.FilterLogs(ctx, interfaces.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []common.Address{managerAddress},
+		Topics:    topics,
+	})
+	if err != nil {
+		return nil, err
+	}
+	delegations := []Delegation{}
+	for _, addedLog := range addedLogs {
+		added, err := ParseDelegatorAdded(addedLog)
+		if err != nil {
+			return nil, err
+		}
+		delegation := Delegation{
+			DelegationID: added.DelegationID,
+			ValidationID: added.ValidationID,
+			Delegator:    added.DelegatorAddress,
+			Weight:       added.DelegatorWeight,
+			Status:       DelegatorStatusPendingAdded,
+		}
+		delegationLogs, err := client.FilterLogs(ctx, interfaces.FilterQuery{
+			FromBlock: big.NewInt(int64(addedLog.BlockNumber)),
+			Addresses: []common.Address{managerAddress},
+			Topics: [][]common.Hash{
+				{
+					eventTopic(delegatorRegisteredEventSpec),
+					eventTopic(delegatorRemovalInitializedEventSpec),
+					eventTopic(delegationEndedEventSpec),
+				},
+				{common.Hash(added.DelegationID)},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, delegationLog := range delegationLogs {
+			switch delegationLog.Topics[0] {
+			case eventTopic(delegatorRegisteredEventSpec):
+				if delegation.Status == DelegatorStatusPendingAdded {
+					delegation.Status = DelegatorStatusActive
+				}
+			case eventTopic(delegatorRemovalInitializedEventSpec):
+				if delegation.Status != DelegatorStatusCompleted {
+					delegation.Status = DelegatorStatusPendingRemoved
+				}
+			case eventTopic(delegationEndedEventSpec):
+				ended, err := ParseDelegationEnded(delegationLog)
+				if err != nil {
+					return nil, err
+				}
+				delegation.Status = DelegatorStatusCompleted
+				delegation.Rewards = ended.Rewards
+			}
+		}
+		delegations = append(delegations, delegation)
+	}
+	return delegations, nil
+}
+
+// GetDelegation returns the delegation [delegationID] made by [delegator]
+func GetDelegation(
+	rpcURL string,
+	delegator common.Address,
+	delegationID ids.ID,
+) (Delegation, error) {
+	delegations, err := GetDelegations(rpcURL, delegator)
+	if err != nil {
+		return Delegation{}, err
+	}
+	for _, delegation := range delegations {
+		if delegation.DelegationID == delegationID {
+			return delegation, nil
+		}
+	}
+	return Delegation{}, errors.New("delegation not found for the given delegator")
+}
+
+func eventTopic(eventSpec string) common.Hash {
+	return crypto.Keccak256Hash([]byte(eventSpec))
+}
+
+// eventSpec removes the indexed markers of an SDK event signature, to get the spec
+// used to compute its topic and to unpack its logs
+func eventSpec(signature string) string {
+	return strings.ReplaceAll(signature, " indexed", "")
+}
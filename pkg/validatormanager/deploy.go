@@ -0,0 +1,75 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// creationCodeHeaderSize is the size of the code that copies the runtime bytecode
+// into memory and returns it, prepended by creationBytecode
+const creationCodeHeaderSize = 13
+
+// creationBytecode wraps [runtimeBytecode] into contract creation code that deploys it
+// as is, without running any constructor. This keeps the initializers of upgradeable
+// contracts enabled, the same as when the runtime bytecode is set on the genesis
+func creationBytecode(runtimeBytecode []byte) ([]byte, error) {
+	if len(runtimeBytecode) > 0xffff {
+		return nil, fmt.Errorf("runtime bytecode of %d bytes is too large to be deployed", len(runtimeBytecode))
+	}
+	size := []byte{byte(len(runtimeBytecode) >> 8), byte(len(runtimeBytecode))}
+	header := []byte{
+		0x61, size[0], size[1], // PUSH2 size
+		0x80,                               // DUP1
+		0x61, 0x00, creationCodeHeaderSize, // PUSH2 offset
+		0x60, 0x00, // PUSH1 0
+		0x39,       // CODECOPY
+		0x60, 0x00, // PUSH1 0
+		0xf3, // RETURN
+	}
+	return append(header, runtimeBytecode...), nil
+}
+
+// DeployPoAValidatorManager deploys a PoA validator manager on the blockchain at [rpcURL],
+// for blockchains that don't have one predeployed on their genesis, returning its address.
+// The manager still needs to be initialized
+func DeployPoAValidatorManager(rpcURL string, privateKey string) (common.Address, error) {
+	runtimeBytecode := common.FromHex(strings.TrimSpace(string(deployedPoAValidatorManagerBytecode)))
+	bytecode, err := creationBytecode(runtimeBytecode)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return contract.DeployContract(
+		rpcURL,
+		privateKey,
+		[]byte(common.Bytes2Hex(bytecode)),
+		"()",
+	)
+}
+
+// ValidatorManagerPredeployed returns true if the blockchain at [rpcURL] has the validator
+// manager proxy on its genesis
+func ValidatorManagerPredeployed(rpcURL string) (bool, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+	return evm.ContractAlreadyDeployed(client, validatorManagerSDK.ProxyContractAddress)
+}
+
+// GetValidatorManagerAddress returns the address of the validator manager of the blockchain
+// deployment [networkData]: the genesis proxy, unless a manager was deployed on conversion
+func GetValidatorManagerAddress(networkData models.NetworkData) common.Address {
+	if networkData.ValidatorManagerAddress != "" {
+		return common.HexToAddress(networkData.ValidatorManagerAddress)
+	}
+	return common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+}
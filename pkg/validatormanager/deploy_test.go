@@ -0,0 +1,29 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreationBytecode(t *testing.T) {
+	require := require.New(t)
+	runtimeBytecode := common.FromHex(strings.TrimSpace(string(deployedPoAValidatorManagerBytecode)))
+	require.NotEmpty(runtimeBytecode)
+	bytecode, err := creationBytecode(runtimeBytecode)
+	require.NoError(err)
+	require.Len(bytecode, creationCodeHeaderSize+len(runtimeBytecode))
+	// copies [size] bytes from the end of the header, and returns them
+	size := int(bytecode[1])<<8 | int(bytecode[2])
+	require.Equal(len(runtimeBytecode), size)
+	require.Equal(byte(0x61), bytecode[4])
+	require.Equal(creationCodeHeaderSize, int(bytecode[5])<<8|int(bytecode[6]))
+	require.Equal(byte(0xf3), bytecode[creationCodeHeaderSize-1])
+	require.Equal(runtimeBytecode, bytecode[creationCodeHeaderSize:])
+	_, err = creationBytecode(make([]byte, 0x10000))
+	require.Error(err)
+}
@@ -0,0 +1,322 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	initialValidatorCreatedEventSpec    = eventSpec(validatorManagerSDK.InitialValidatorCreatedEventSignature)
+	validationPeriodRegisteredEventSpec = eventSpec(validatorManagerSDK.ValidationPeriodRegisteredEventSignature)
+	validationPeriodEndedEventSpec      = eventSpec(validatorManagerSDK.ValidationPeriodEndedEventSignature)
+)
+
+// validator manager event names
+const (
+	InitialValidatorCreatedEvent     = "InitialValidatorCreated"
+	ValidationPeriodCreatedEvent     = "ValidationPeriodCreated"
+	ValidationPeriodRegisteredEvent  = "ValidationPeriodRegistered"
+	ValidatorRemovalInitializedEvent = "ValidatorRemovalInitialized"
+	ValidationPeriodEndedEvent       = "ValidationPeriodEnded"
+	ValidatorWeightUpdateEvent       = "ValidatorWeightUpdate"
+	DelegatorAddedEvent              = "DelegatorAdded"
+	DelegatorRegisteredEvent         = "DelegatorRegistered"
+	DelegatorRemovalInitializedEvent = "DelegatorRemovalInitialized"
+	DelegationEndedEvent             = "DelegationEnded"
+)
+
+var validatorManagerEventNames = map[common.Hash]string{
+	eventTopic(initialValidatorCreatedEventSpec):     InitialValidatorCreatedEvent,
+	eventTopic(validationPeriodCreatedEventSpec):     ValidationPeriodCreatedEvent,
+	eventTopic(validationPeriodRegisteredEventSpec):  ValidationPeriodRegisteredEvent,
+	eventTopic(validatorRemovalInitializedEventSpec): ValidatorRemovalInitializedEvent,
+	eventTopic(validationPeriodEndedEventSpec):       ValidationPeriodEndedEvent,
+	eventTopic(validatorWeightUpdateEventSpec):       ValidatorWeightUpdateEvent,
+	eventTopic(delegatorAddedEventSpec):              DelegatorAddedEvent,
+	eventTopic(delegatorRegisteredEventSpec):         DelegatorRegisteredEvent,
+	eventTopic(delegatorRemovalInitializedEventSpec): DelegatorRemovalInitializedEvent,
+	eventTopic(delegationEndedEventSpec):             DelegationEndedEvent,
+}
+
+// time to wait before reconnecting a lost event subscription
+const eventSubscriptionReconnectDelay = 5 * time.Second
+
+// ValidatorManagerEvent is a change in the validator set or the delegations of an L1,
+// as emitted by its validator manager
+type ValidatorManagerEvent struct {
+	Name        string
+	BlockNumber uint64
+	LogIndex    uint
+	TxHash      common.Hash
+	// validator the event refers to. NodeID is not part of the event, and is only set
+	// if resolved by the caller
+	ValidationID ids.ID
+	NodeID       ids.NodeID
+	// new validator weight, or delegator weight for DelegatorAdded
+	Weight uint64
+	// nonce of the weight change message sent to the P-Chain, for ValidatorWeightUpdate only
+	Nonce uint64
+	// delegation events only
+	DelegationID ids.ID
+	Delegator    common.Address
+	Rewards      *big.Int
+}
+
+// Validator returns the node ID of the event validator if known, or its validation ID
+func (e ValidatorManagerEvent) Validator() string {
+	if e.NodeID != ids.EmptyNodeID {
+		return e.NodeID.String()
+	}
+	return e.ValidationID.String()
+}
+
+// Text is a human readable description of the event
+func (e ValidatorManagerEvent) Text() string {
+	switch e.Name {
+	case InitialValidatorCreatedEvent:
+		return fmt.Sprintf("initial validator %s created with weight %d", e.Validator(), e.Weight)
+	case ValidationPeriodCreatedEvent:
+		return fmt.Sprintf("validator %s registration initiated with weight %d", e.Validator(), e.Weight)
+	case ValidationPeriodRegisteredEvent:
+		return fmt.Sprintf("validator %s added with weight %d", e.Validator(), e.Weight)
+	case ValidatorRemovalInitializedEvent:
+		return fmt.Sprintf("validator %s removal initiated", e.Validator())
+	case ValidationPeriodEndedEvent:
+		return fmt.Sprintf("validator %s removed", e.Validator())
+	case ValidatorWeightUpdateEvent:
+		return fmt.Sprintf("validator %s weight changed to %d", e.Validator(), e.Weight)
+	case DelegatorAddedEvent:
+		return fmt.Sprintf("delegation %s of weight %d to validator %s initiated by %s", e.DelegationID, e.Weight, e.Validator(), e.Delegator.Hex())
+	case DelegatorRegisteredEvent:
+		return fmt.Sprintf("delegation %s to validator %s active", e.DelegationID, e.Validator())
+	case DelegatorRemovalInitializedEvent:
+		return fmt.Sprintf("delegation %s to validator %s removal initiated", e.DelegationID, e.Validator())
+	case DelegationEndedEvent:
+		return fmt.Sprintf("delegation %s to validator %s ended with rewards %s", e.DelegationID, e.Validator(), e.Rewards)
+	}
+	return e.Name
+}
+
+// ParseValidatorManagerEvent decodes [log] as a validator manager event. Returns false if
+// the log is not a known validator manager event
+func ParseValidatorManagerEvent(log types.Log) (ValidatorManagerEvent, bool, error) {
+	if len(log.Topics) == 0 {
+		return ValidatorManagerEvent{}, false, nil
+	}
+	name, ok := validatorManagerEventNames[log.Topics[0]]
+	if !ok {
+		return ValidatorManagerEvent{}, false, nil
+	}
+	event := ValidatorManagerEvent{
+		Name:        name,
+		BlockNumber: log.BlockNumber,
+		LogIndex:    log.Index,
+		TxHash:      log.TxHash,
+	}
+	switch name {
+	case DelegatorAddedEvent:
+		added, err := ParseDelegatorAdded(log)
+		if err != nil {
+			return event, false, err
+		}
+		event.DelegationID = added.DelegationID
+		event.ValidationID = added.ValidationID
+		event.Delegator = added.DelegatorAddress
+		event.Weight = added.DelegatorWeight
+	case DelegatorRegisteredEvent:
+		registered, err := ParseDelegatorRegistered(log)
+		if err != nil {
+			return event, false, err
+		}
+		event.DelegationID = registered.DelegationID
+		event.ValidationID = registered.ValidationID
+	case DelegatorRemovalInitializedEvent:
+		removal, err := ParseDelegatorRemovalInitialized(log)
+		if err != nil {
+			return event, false, err
+		}
+		event.DelegationID = removal.DelegationID
+		event.ValidationID = removal.ValidationID
+	case DelegationEndedEvent:
+		ended, err := ParseDelegationEnded(log)
+		if err != nil {
+			return event, false, err
+		}
+		event.DelegationID = ended.DelegationID
+		event.ValidationID = ended.ValidationID
+		event.Rewards = ended.Rewards
+	default:
+		// validation ID is the first indexed field, and weight the first data field
+		if len(log.Topics) < 2 {
+			return event, false, fmt.Errorf("malformed %s event on tx %s", name, log.TxHash.Hex())
+		}
+		event.ValidationID = ids.ID(log.Topics[1])
+		if name != ValidationPeriodEndedEvent && name != ValidatorRemovalInitializedEvent && len(log.Data) >= common.HashLength {
+			event.Weight = new(big.Int).SetBytes(log.Data[:common.HashLength]).Uint64()
+		}
+		if name == ValidatorWeightUpdateEvent && len(log.Topics) >= 3 {
+			event.Nonce = new(big.Int).SetBytes(log.Topics[2][:]).Uint64()
+		}
+	}
+	return event, true, nil
+}
+
+func validatorManagerEventsQuery(managerAddress common.Address) interfaces.FilterQuery {
+	topics := make([]common.Hash, 0, len(validatorManagerEventNames))
+	for topic := range validatorManagerEventNames {
+		topics = append(topics, topic)
+	}
+	return interfaces.FilterQuery{
+		Addresses: []common.Address{managerAddress},
+		Topics:    [][]common.Hash{topics},
+	}
+}
+
+// GetValidatorManagerEvents returns the events emitted by the validator manager at [managerAddress]
+// from block [fromBlock] to block [toBlock] (latest if nil)
+func GetValidatorManagerEvents(
+	client ethclient.Client,
+	managerAddress common.Address,
+	fromBlock uint64,
+	toBlock *big.Int,
+) ([]ValidatorManagerEvent, error) {
+	query := validatorManagerEventsQuery(managerAddress)
+	query.FromBlock = new(big.Int).SetUint64(fromBlock)
+	query.ToBlock = toBlock
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	logs, err := client.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	events := []ValidatorManagerEvent{}
+	for _, log := range logs {
+		event, ok, err := ParseValidatorManagerEvent(log)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// eventCursor is the position of the last event handled by a watcher, used to
+// not repeat events when catching up after a reconnection
+type eventCursor struct {
+	blockNumber uint64
+	logIndex    uint
+	set         bool
+}
+
+func (c *eventCursor) advance(event ValidatorManagerEvent) bool {
+	if c.set && (event.BlockNumber < c.blockNumber || (event.BlockNumber == c.blockNumber && event.LogIndex <= c.logIndex)) {
+		return false
+	}
+	c.blockNumber, c.logIndex, c.set = event.BlockNumber, event.LogIndex, true
+	return true
+}
+
+// WatchValidatorManagerEvents subscribes to the events of the validator manager at
+// [managerAddress] over the websocket endpoint [wsURL], calling [handler] for each of them,
+// in order, until [ctx] is done. Events emitted from block [fromBlock] are delivered first.
+// Lost subscriptions are reported to [onDisconnect] and reconnected, catching up on the
+// events emitted meanwhile
+func WatchValidatorManagerEvents(
+	ctx context.Context,
+	wsURL string,
+	managerAddress common.Address,
+	fromBlock uint64,
+	handler func(ValidatorManagerEvent),
+	onDisconnect func(error),
+) error {
+	cursor := &eventCursor{}
+	for {
+		err := watchValidatorManagerEvents(ctx, wsURL, managerAddress, fromBlock, cursor, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if onDisconnect != nil {
+			onDisconnect(err)
+		}
+		if cursor.set {
+			fromBlock = cursor.blockNumber
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(eventSubscriptionReconnectDelay):
+		}
+	}
+}
+
+func watchValidatorManagerEvents(
+	ctx context.Context,
+	wsURL string,
+	managerAddress common.Address,
+	fromBlock uint64,
+	cursor *eventCursor,
+	handler func(ValidatorManagerEvent),
+) error {
+	client, err := evm.GetClient(wsURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	logs := make(chan types.Log)
+	// subscribe before catching up, so no event is missed in between
+	sub, err := client.SubscribeFilterLogs(ctx, validatorManagerEventsQuery(managerAddress), logs)
+	if err != nil {
+		return fmt.Errorf("failure subscribing to validator manager events on %s: %w", wsURL, err)
+	}
+	defer sub.Unsubscribe()
+	if fromBlock > 0 || cursor.set {
+		events, err := GetValidatorManagerEvents(client, managerAddress, fromBlock, nil)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if cursor.advance(event) {
+				handler(event)
+			}
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			if err == nil {
+				err = errors.New("subscription closed by the server")
+			}
+			return err
+		case log := <-logs:
+			if log.Removed {
+				// reorged out
+				continue
+			}
+			event, ok, err := ParseValidatorManagerEvent(log)
+			if err != nil {
+				return err
+			}
+			if ok && cursor.advance(event) {
+				handler(event)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValidatorManagerEvent(t *testing.T) {
+	require := require.New(t)
+	validationID := ids.GenerateTestID()
+	delegationID := ids.GenerateTestID()
+	word := func(n int64) []byte {
+		return common.LeftPadBytes(big.NewInt(n).Bytes(), common.HashLength)
+	}
+
+	event, ok, err := ParseValidatorManagerEvent(types.Log{
+		Topics:      []common.Hash{eventTopic(validatorWeightUpdateEventSpec), common.Hash(validationID), common.BytesToHash(word(3))},
+		Data:        append(word(40), make([]byte, common.HashLength)...),
+		BlockNumber: 10,
+		Index:       2,
+	})
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(ValidatorManagerEvent{
+		Name:         ValidatorWeightUpdateEvent,
+		BlockNumber:  10,
+		LogIndex:     2,
+		ValidationID: validationID,
+		Weight:       40,
+		Nonce:        3,
+	}, event)
+	require.Equal("validator "+validationID.String()+" weight changed to 40", event.Text())
+	nodeID := ids.GenerateTestNodeID()
+	event.NodeID = nodeID
+	require.Equal("validator "+nodeID.String()+" weight changed to 40", event.Text())
+
+	event, ok, err = ParseValidatorManagerEvent(types.Log{
+		Topics: []common.Hash{eventTopic(validationPeriodEndedEventSpec), common.Hash(validationID), common.BytesToHash(word(3))},
+	})
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(ValidationPeriodEndedEvent, event.Name)
+	require.Equal(validationID, event.ValidationID)
+	require.Zero(event.Weight)
+
+	event, ok, err = ParseValidatorManagerEvent(types.Log{
+		Topics: []common.Hash{eventTopic(delegationEndedEventSpec), common.Hash(delegationID), common.Hash(validationID)},
+		Data:   append(word(1000), word(10)...),
+	})
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(DelegationEndedEvent, event.Name)
+	require.Equal(delegationID, event.DelegationID)
+	require.Equal(validationID, event.ValidationID)
+	require.Equal(big.NewInt(1000), event.Rewards)
+
+	_, ok, err = ParseValidatorManagerEvent(types.Log{Topics: []common.Hash{common.HexToHash("0x01")}})
+	require.NoError(err)
+	require.False(ok)
+
+	_, _, err = ParseValidatorManagerEvent(types.Log{Topics: []common.Hash{eventTopic(validationPeriodRegisteredEventSpec)}})
+	require.Error(err)
+}
+
+func TestEventCursor(t *testing.T) {
+	require := require.New(t)
+	cursor := &eventCursor{}
+	require.True(cursor.advance(ValidatorManagerEvent{BlockNumber: 5, LogIndex: 1}))
+	require.False(cursor.advance(ValidatorManagerEvent{BlockNumber: 5, LogIndex: 1}))
+	require.False(cursor.advance(ValidatorManagerEvent{BlockNumber: 4, LogIndex: 7}))
+	require.True(cursor.advance(ValidatorManagerEvent{BlockNumber: 5, LogIndex: 2}))
+	require.True(cursor.advance(ValidatorManagerEvent{BlockNumber: 6, LogIndex: 0}))
+}
+
+func TestEventSignatures(t *testing.T) {
+	require := require.New(t)
+	topics := map[common.Hash]bool{}
+	for _, signature := range validatorManagerSDK.EventSignatures {
+		sig, err := contract.ParseSignature(signature)
+		require.NoError(err)
+		require.Equal(sig.Topic(), eventTopic(eventSpec(signature)))
+		topics[sig.Topic()] = true
+	}
+	// every event the CLI follows can be decoded with the SDK signatures
+	for topic, name := range validatorManagerEventNames {
+		require.True(topics[topic], "%s is not in the SDK event signatures", name)
+	}
+}
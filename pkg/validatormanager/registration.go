@@ -92,6 +92,119 @@ func InitializeValidatorRegistrationPoSNative(
 	)
 }
 
+// step 1 of flow for adding a new validator on an ERC20 token staking manager.
+// [stakeAmount] is transferred from the owner of [managerOwnerPrivateKey], that must
+// have approved it to the manager
+func InitializeValidatorRegistrationPoSERC20(
+	rpcURL string,
+	managerAddress common.Address,
+	managerOwnerPrivateKey string,
+	nodeID ids.NodeID,
+	blsPublicKey []byte,
+	expiry uint64,
+	balanceOwners warpMessage.PChainOwner,
+	disableOwners warpMessage.PChainOwner,
+	delegationFeeBips uint16,
+	minStakeDuration time.Duration,
+	stakeAmount *big.Int,
+) (*types.Transaction, *types.Receipt, error) {
+	type PChainOwner struct {
+		Threshold uint32
+		Addresses []common.Address
+	}
+
+	type ValidatorRegistrationInput struct {
+		NodeID                []byte
+		BlsPublicKey          []byte
+		RegistrationExpiry    uint64
+		RemainingBalanceOwner PChainOwner
+		DisableOwner          PChainOwner
+	}
+
+	balanceOwnersAux := PChainOwner{
+		Threshold: balanceOwners.Threshold,
+		Addresses: utils.Map(balanceOwners.Addresses, func(addr ids.ShortID) common.Address {
+			return common.BytesToAddress(addr[:])
+		}),
+	}
+	disableOwnersAux := PChainOwner{
+		Threshold: disableOwners.Threshold,
+		Addresses: utils.Map(disableOwners.Addresses, func(addr ids.ShortID) common.Address {
+			return common.BytesToAddress(addr[:])
+		}),
+	}
+	validatorRegistrationInput := ValidatorRegistrationInput{
+		NodeID:                nodeID[:],
+		BlsPublicKey:          blsPublicKey,
+		RegistrationExpiry:    expiry,
+		RemainingBalanceOwner: balanceOwnersAux,
+		DisableOwner:          disableOwnersAux,
+	}
+
+	return contract.TxToMethod(
+		rpcURL,
+		managerOwnerPrivateKey,
+		managerAddress,
+		nil,
+		"initialize validator registration with ERC20 stake",
+		validatorManagerSDK.ErrorSignatureToError,
+		"initializeValidatorRegistration((bytes,bytes,uint64,(uint32,[address]),(uint32,[address])),uint16,uint64,uint256)",
+		validatorRegistrationInput,
+		delegationFeeBips,
+		uint64(minStakeDuration.Seconds()),
+		stakeAmount,
+	)
+}
+
+// initializeValidatorRegistrationPoS initializes the registration on the native or ERC20 token
+// staking manager. ERC20 stakes are approved to the manager first
+func initializeValidatorRegistrationPoS(
+	rpcURL string,
+	managerAddress common.Address,
+	managerOwnerPrivateKey string,
+	nodeID ids.NodeID,
+	blsPublicKey []byte,
+	expiry uint64,
+	balanceOwners warpMessage.PChainOwner,
+	disableOwners warpMessage.PChainOwner,
+	delegationFeeBips uint16,
+	minStakeDuration time.Duration,
+	stakeAmount *big.Int,
+	erc20Staking bool,
+) (*types.Transaction, *types.Receipt, error) {
+	if !erc20Staking {
+		return InitializeValidatorRegistrationPoSNative(
+			rpcURL,
+			managerAddress,
+			managerOwnerPrivateKey,
+			nodeID,
+			blsPublicKey,
+			expiry,
+			balanceOwners,
+			disableOwners,
+			delegationFeeBips,
+			minStakeDuration,
+			stakeAmount,
+		)
+	}
+	if err := ApproveStake(rpcURL, managerAddress, managerOwnerPrivateKey, stakeAmount); err != nil {
+		return nil, nil, err
+	}
+	return InitializeValidatorRegistrationPoSERC20(
+		rpcURL,
+		managerAddress,
+		managerOwnerPrivateKey,
+		nodeID,
+		blsPublicKey,
+		expiry,
+		balanceOwners,
+		disableOwners,
+		delegationFeeBips,
+		minStakeDuration,
+		stakeAmount,
+	)
+}
+
 // step 1 of flow for adding a new validator
 func InitializeValidatorRegistrationPoA(
 	rpcURL string,
@@ -151,7 +264,7 @@ func GetSubnetValidatorRegistrationMessage(
 	rpcURL string,
 	network models.Network,
 	aggregatorLogLevel logging.Level,
-	aggregatorQuorumPercentage uint64,
+	aggregationConfig interchain.AggregationConfig,
 	aggregatorAllowPrivateIPs bool,
 	aggregatorExtraPeerEndpoints []info.Peer,
 	subnetID ids.ID,
@@ -220,18 +333,16 @@ func GetSubnetValidatorRegistrationMessage(
 			return nil, ids.Empty, err
 		}
 	}
-	signatureAggregator, err := interchain.NewSignatureAggregator(
-		network,
+	signedMessage, err := interchain.SignMessage(
+		network.SDKNetwork(),
 		aggregatorLogLevel,
 		subnetID,
-		aggregatorQuorumPercentage,
+		aggregationConfig,
 		aggregatorAllowPrivateIPs,
 		aggregatorExtraPeerEndpoints,
+		registerSubnetValidatorUnsignedMessage,
+		nil,
 	)
-	if err != nil {
-		return nil, ids.Empty, err
-	}
-	signedMessage, err := signatureAggregator.Sign(registerSubnetValidatorUnsignedMessage, nil)
 	return signedMessage, validationID, err
 }
 
@@ -281,7 +392,7 @@ func GetPChainSubnetValidatorRegistrationWarpMessage(
 	network models.Network,
 	rpcURL string,
 	aggregatorLogLevel logging.Level,
-	aggregatorQuorumPercentage uint64,
+	aggregationConfig interchain.AggregationConfig,
 	aggregatorAllowPrivateIPs bool,
 	aggregatorExtraPeerEndpoints []info.Peer,
 	subnetID ids.ID,
@@ -307,17 +418,6 @@ func GetPChainSubnetValidatorRegistrationWarpMessage(
 	if err != nil {
 		return nil, err
 	}
-	signatureAggregator, err := interchain.NewSignatureAggregator(
-		network,
-		aggregatorLogLevel,
-		subnetID,
-		aggregatorQuorumPercentage,
-		aggregatorAllowPrivateIPs,
-		aggregatorExtraPeerEndpoints,
-	)
-	if err != nil {
-		return nil, err
-	}
 	var justificationBytes []byte
 	if !registered {
 		justificationBytes, err = GetRegistrationJustification(rpcURL, validationID, subnetID)
@@ -325,7 +425,16 @@ func GetPChainSubnetValidatorRegistrationWarpMessage(
 			return nil, err
 		}
 	}
-	return signatureAggregator.Sign(subnetConversionUnsignedMessage, justificationBytes)
+	return interchain.SignMessage(
+		network.SDKNetwork(),
+		aggregatorLogLevel,
+		subnetID,
+		aggregationConfig,
+		aggregatorAllowPrivateIPs,
+		aggregatorExtraPeerEndpoints,
+		subnetConversionUnsignedMessage,
+		justificationBytes,
+	)
 }
 
 // last step of flow for adding a new validator
@@ -363,10 +472,12 @@ func InitValidatorRegistration(
 	aggregatorExtraPeerEndpoints []info.Peer,
 	aggregatorAllowPrivatePeers bool,
 	aggregatorLogLevelStr string,
+	aggregationConfig interchain.AggregationConfig,
 	initWithPos bool,
 	delegationFee uint16,
 	stakeDuration time.Duration,
 	stakeAmount *big.Int,
+	erc20Staking bool,
 ) (*warp.Message, ids.ID, error) {
 	subnetID, err := contract.GetSubnetID(
 		app,
@@ -392,7 +503,7 @@ func InitValidatorRegistration(
 		ux.Logger.PrintToUser("Using rpcURL: %s", rpcURL)
 		ux.Logger.PrintToUser("NodeID: %s staking %s for %ds", nodeID.String(), stakeAmount, uint64(stakeDuration.Seconds()))
 		ux.Logger.PrintLineSeparator()
-		tx, _, err := InitializeValidatorRegistrationPoSNative(
+		tx, _, err := initializeValidatorRegistrationPoS(
 			rpcURL,
 			managerAddress,
 			ownerPrivateKey,
@@ -404,6 +515,7 @@ func InitValidatorRegistration(
 			delegationFee,
 			stakeDuration,
 			stakeAmount,
+			erc20Staking,
 		)
 		if err != nil {
 			if !errors.Is(err, validatorManagerSDK.ErrNodeAlreadyRegistered) {
@@ -455,7 +567,7 @@ func InitValidatorRegistration(
 		rpcURL,
 		network,
 		aggregatorLogLevel,
-		0,
+		aggregationConfig,
 		aggregatorAllowPrivatePeers,
 		aggregatorExtraPeerEndpoints,
 		subnetID,
@@ -481,6 +593,7 @@ func FinishValidatorRegistration(
 	aggregatorExtraPeerEndpoints []info.Peer,
 	aggregatorAllowPrivatePeers bool,
 	aggregatorLogLevelStr string,
+	aggregationConfig interchain.AggregationConfig,
 ) error {
 	subnetID, err := contract.GetSubnetID(
 		app,
@@ -499,7 +612,7 @@ func FinishValidatorRegistration(
 		network,
 		rpcURL,
 		aggregatorLogLevel,
-		0,
+		aggregationConfig,
 		aggregatorAllowPrivatePeers,
 		aggregatorExtraPeerEndpoints,
 		subnetID,
@@ -618,3 +731,69 @@ func GetRegistrationJustification(
 	}
 	return proto.Marshal(&justification)
 }
+
+// GetRegistrationExpiry returns the expiry of the L1 validator registration [message]
+func GetRegistrationExpiry(message *warp.Message) (uint64, error) {
+	addressedCall, err := warpPayload.ParseAddressedCall(message.Payload)
+	if err != nil {
+		return 0, err
+	}
+	reg, err := warpMessage.ParseRegisterL1Validator(addressedCall.Payload)
+	if err != nil {
+		return 0, err
+	}
+	return reg.Expiry, nil
+}
+
+// ReissueValidatorRegistration gets a new signature for the registration message of a validator
+// registration already initialized on the validator manager, so it can be issued again to P-Chain
+func ReissueValidatorRegistration(
+	app *application.Avalanche,
+	network models.Network,
+	rpcURL string,
+	chainSpec contract.ChainSpec,
+	nodeID ids.NodeID,
+	aggregatorExtraPeerEndpoints []info.Peer,
+	aggregatorAllowPrivatePeers bool,
+	aggregatorLogLevelStr string,
+	aggregationConfig interchain.AggregationConfig,
+) (*warp.Message, ids.ID, error) {
+	subnetID, err := contract.GetSubnetID(
+		app,
+		network,
+		chainSpec,
+	)
+	if err != nil {
+		return nil, ids.Empty, err
+	}
+	blockchainID, err := contract.GetBlockchainID(
+		app,
+		network,
+		chainSpec,
+	)
+	if err != nil {
+		return nil, ids.Empty, err
+	}
+	aggregatorLogLevel, err := logging.ToLevel(aggregatorLogLevelStr)
+	if err != nil {
+		aggregatorLogLevel = defaultAggregatorLogLevel
+	}
+	return GetSubnetValidatorRegistrationMessage(
+		rpcURL,
+		network,
+		aggregatorLogLevel,
+		aggregationConfig,
+		aggregatorAllowPrivatePeers,
+		aggregatorExtraPeerEndpoints,
+		subnetID,
+		blockchainID,
+		common.HexToAddress(validatorManagerSDK.ProxyContractAddress),
+		nodeID,
+		[48]byte{},
+		0,
+		warpMessage.PChainOwner{},
+		warpMessage.PChainOwner{},
+		0,
+		true,
+	)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/contract"
 	"github.com/ava-labs/avalanche-cli/pkg/evm"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/sdk/interchain"
@@ -81,7 +82,7 @@ func InitializeValidatorRemoval(
 func GetUptimeProofMessage(
 	network models.Network,
 	aggregatorLogLevel logging.Level,
-	aggregatorQuorumPercentage uint64,
+	aggregationConfig interchain.AggregationConfig,
 	aggregatorExtraPeerEndpoints []info.Peer,
 	subnetID ids.ID,
 	blockchainID ids.ID,
@@ -104,24 +105,22 @@ func GetUptimeProofMessage(
 	if err != nil {
 		return nil, err
 	}
-	signatureAggregator, err := interchain.NewSignatureAggregator(
-		network,
+	return interchain.SignMessage(
+		network.SDKNetwork(),
 		aggregatorLogLevel,
 		subnetID,
-		aggregatorQuorumPercentage,
+		aggregationConfig,
 		true, // allow private peers
 		aggregatorExtraPeerEndpoints,
+		uptimeProofUnsignedMessage,
+		nil,
 	)
-	if err != nil {
-		return nil, err
-	}
-	return signatureAggregator.Sign(uptimeProofUnsignedMessage, nil)
 }
 
 func GetSubnetValidatorWeightMessage(
 	network models.Network,
 	aggregatorLogLevel logging.Level,
-	aggregatorQuorumPercentage uint64,
+	aggregationConfig interchain.AggregationConfig,
 	aggregatorAllowPrivateIPs bool,
 	aggregatorExtraPeerEndpoints []info.Peer,
 	subnetID ids.ID,
@@ -154,18 +153,16 @@ func GetSubnetValidatorWeightMessage(
 	if err != nil {
 		return nil, err
 	}
-	signatureAggregator, err := interchain.NewSignatureAggregator(
-		network,
+	return interchain.SignMessage(
+		network.SDKNetwork(),
 		aggregatorLogLevel,
 		subnetID,
-		aggregatorQuorumPercentage,
+		aggregationConfig,
 		aggregatorAllowPrivateIPs,
 		aggregatorExtraPeerEndpoints,
+		unsignedMessage,
+		nil,
 	)
-	if err != nil {
-		return nil, err
-	}
-	return signatureAggregator.Sign(unsignedMessage, nil)
 }
 
 func InitValidatorRemoval(
@@ -178,6 +175,7 @@ func InitValidatorRemoval(
 	aggregatorExtraPeerEndpoints []info.Peer,
 	aggregatorAllowPrivatePeers bool,
 	aggregatorLogLevelStr string,
+	aggregationConfig interchain.AggregationConfig,
 	initWithPos bool,
 	uptimeSec uint64,
 	force bool,
@@ -224,7 +222,7 @@ func InitValidatorRemoval(
 		signedUptimeProof, err = GetUptimeProofMessage(
 			network,
 			aggregatorLogLevel,
-			0,
+			aggregationConfig,
 			aggregatorExtraPeerEndpoints,
 			subnetID,
 			blockchainID,
@@ -251,11 +249,11 @@ func InitValidatorRemoval(
 		ux.Logger.PrintToUser("the validator removal process was already initialized. Proceeding to the next step")
 	}
 
-	nonce := uint64(1)
+	nonce := getRemovalNonce(network, rpcURL, managerAddress, validationID)
 	signedMsg, err := GetSubnetValidatorWeightMessage(
 		network,
 		aggregatorLogLevel,
-		0,
+		aggregationConfig,
 		aggregatorAllowPrivatePeers,
 		aggregatorExtraPeerEndpoints,
 		subnetID,
@@ -268,6 +266,75 @@ func InitValidatorRemoval(
 	return signedMsg, validationID, err
 }
 
+// RemovalNonce returns the nonce of the weight message removing [validationID]: the one
+// following the last weight change sent for it by the validator manager on [events]. Also
+// returns the weight changes not applied yet on the P-Chain, given the validator [minNonce]
+// there. The removal message supersedes them
+func RemovalNonce(
+	events []ValidatorManagerEvent,
+	validationID ids.ID,
+	minNonce uint64,
+) (uint64, []ValidatorManagerEvent) {
+	nonce := uint64(0)
+	pending := []ValidatorManagerEvent{}
+	for _, event := range events {
+		if event.Name != ValidatorWeightUpdateEvent || event.ValidationID != validationID {
+			continue
+		}
+		if event.Nonce > nonce {
+			nonce = event.Nonce
+		}
+		if event.Nonce >= minNonce {
+			pending = append(pending, event)
+		}
+	}
+	return nonce + 1, pending
+}
+
+// getRemovalNonce obtains the removal nonce of [validationID] from the validator manager
+// events, reporting the weight changes pending on the P-Chain. Delegations change the
+// validator weight, so the nonce is only 1 for validators that never had one
+func getRemovalNonce(
+	network models.Network,
+	rpcURL string,
+	managerAddress common.Address,
+	validationID ids.ID,
+) uint64 {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("could not get the weight changes of validator %s, assuming none: %s"), validationID, err)
+		return 1
+	}
+	defer client.Close()
+	events, err := GetValidatorManagerEvents(client, managerAddress, 0, nil)
+	if err != nil {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("could not get the weight changes of validator %s, assuming none: %s"), validationID, err)
+		return 1
+	}
+	l1Validator, err := txutils.GetL1Validator(network, validationID)
+	if errors.Is(err, txutils.ErrL1ValidatorNotFound) {
+		// already removed from the P-Chain, nothing is pending there
+		nonce, _ := RemovalNonce(events, validationID, 0)
+		return nonce
+	}
+	if err != nil {
+		ux.Logger.PrintToUser(logging.Yellow.Wrap("could not get the P-Chain state of validator %s, assuming no pending weight changes: %s"), validationID, err)
+		nonce, _ := RemovalNonce(events, validationID, 0)
+		return nonce
+	}
+	nonce, pending := RemovalNonce(events, validationID, l1Validator.MinNonce)
+	for _, event := range pending {
+		ux.Logger.PrintToUser(
+			logging.Yellow.Wrap("Weight change of validator %s to %d (nonce %d, tx %s) was not applied on P-Chain. The removal supersedes it"),
+			validationID,
+			event.Weight,
+			event.Nonce,
+			event.TxHash.Hex(),
+		)
+	}
+	return nonce
+}
+
 func CompleteValidatorRemoval(
 	rpcURL string,
 	managerAddress common.Address,
@@ -297,6 +364,7 @@ func FinishValidatorRemoval(
 	aggregatorExtraPeerEndpoints []info.Peer,
 	aggregatorAllowPrivatePeers bool,
 	aggregatorLogLevelStr string,
+	aggregationConfig interchain.AggregationConfig,
 ) error {
 	managerAddress := common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
 	subnetID, err := contract.GetSubnetID(
@@ -315,7 +383,7 @@ func FinishValidatorRemoval(
 		network,
 		rpcURL,
 		aggregatorLogLevel,
-		0,
+		aggregationConfig,
 		aggregatorAllowPrivatePeers,
 		aggregatorExtraPeerEndpoints,
 		subnetID,
@@ -0,0 +1,39 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemovalNonce(t *testing.T) {
+	require := require.New(t)
+	validationID := ids.GenerateTestID()
+	otherValidationID := ids.GenerateTestID()
+
+	// no weight changes
+	nonce, pending := RemovalNonce(nil, validationID, 0)
+	require.Equal(uint64(1), nonce)
+	require.Empty(pending)
+
+	events := []ValidatorManagerEvent{
+		{Name: ValidationPeriodRegisteredEvent, ValidationID: validationID, Weight: 20},
+		{Name: ValidatorWeightUpdateEvent, ValidationID: validationID, Weight: 30, Nonce: 1},
+		{Name: ValidatorWeightUpdateEvent, ValidationID: otherValidationID, Weight: 50, Nonce: 7},
+		{Name: ValidatorWeightUpdateEvent, ValidationID: validationID, Weight: 40, Nonce: 2},
+		{Name: DelegatorAddedEvent, ValidationID: validationID, Weight: 10},
+	}
+	// both weight changes applied on P-Chain
+	nonce, pending = RemovalNonce(events, validationID, 3)
+	require.Equal(uint64(3), nonce)
+	require.Empty(pending)
+
+	// the last weight change is pending
+	nonce, pending = RemovalNonce(events, validationID, 2)
+	require.Equal(uint64(3), nonce)
+	require.Len(pending, 1)
+	require.Equal(uint64(40), pending[0].Weight)
+}
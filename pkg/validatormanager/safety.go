@@ -0,0 +1,120 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/plugin/evm"
+)
+
+// RemovalSafetyThresholds are the limits the validator set of an L1 is checked against
+// before removing one of its validators
+type RemovalSafetyThresholds struct {
+	// min number of active validators the L1 must keep
+	MinValidators int
+	// max percentage of the L1 weight a single validator can hold. Above it, the
+	// validator can halt the L1 by going offline
+	MaxWeightPercentage float64
+	// percentage of the L1 weight that must be connected for the L1 to make progress
+	QuorumPercentage float64
+}
+
+var DefaultRemovalSafetyThresholds = RemovalSafetyThresholds{
+	MinValidators:       constants.DefaultL1MinValidators,
+	MaxWeightPercentage: 33,
+	QuorumPercentage:    67,
+}
+
+// RemovalSafetyReport describes the L1 validator set resulting from a validator removal
+type RemovalSafetyReport struct {
+	RemainingValidators int
+	RemainingWeight     uint64
+	// validator holding the biggest weight after the removal
+	TopValidator              ids.NodeID
+	TopWeightPercentage       float64
+	ConnectedWeightPercentage float64
+	// reasons why the removal is not safe
+	Warnings []string
+}
+
+func (r *RemovalSafetyReport) IsSafe() bool {
+	return len(r.Warnings) == 0
+}
+
+// AnalyzeValidatorRemoval computes the weight distribution of the L1 active validators
+// [validators] after removing [nodeID], and checks it against [thresholds].
+// Connectivity is the one seen by the node that answered [validators]
+func AnalyzeValidatorRemoval(
+	validators []evm.CurrentValidator,
+	nodeID ids.NodeID,
+	thresholds RemovalSafetyThresholds,
+) (*RemovalSafetyReport, error) {
+	found := false
+	report := &RemovalSafetyReport{
+		Warnings: []string{},
+	}
+	var (
+		topWeight       uint64
+		connectedWeight uint64
+	)
+	for _, validator := range validators {
+		if validator.NodeID == nodeID {
+			found = true
+			continue
+		}
+		// inactive validators do not participate in consensus
+		if !validator.IsActive {
+			continue
+		}
+		report.RemainingValidators++
+		report.RemainingWeight += validator.Weight
+		if validator.IsConnected {
+			connectedWeight += validator.Weight
+		}
+		if validator.Weight > topWeight {
+			topWeight = validator.Weight
+			report.TopValidator = validator.NodeID
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("node %s is not a validator of the L1", nodeID)
+	}
+	if report.RemainingValidators < thresholds.MinValidators {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"the L1 would be left with %d active validators, below the minimum of %d",
+			report.RemainingValidators,
+			thresholds.MinValidators,
+		))
+	}
+	if report.RemainingWeight == 0 {
+		if report.IsSafe() {
+			report.Warnings = append(report.Warnings, "the L1 would be left without active validators")
+		}
+		return report, nil
+	}
+	report.TopWeightPercentage = percentage(topWeight, report.RemainingWeight)
+	report.ConnectedWeightPercentage = percentage(connectedWeight, report.RemainingWeight)
+	if report.TopWeightPercentage > thresholds.MaxWeightPercentage {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"validator %s would hold %.2f%% of the L1 weight, above %.0f%%, and could halt the L1 by itself",
+			report.TopValidator,
+			report.TopWeightPercentage,
+			thresholds.MaxWeightPercentage,
+		))
+	}
+	if report.ConnectedWeightPercentage < thresholds.QuorumPercentage {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"only %.2f%% of the remaining L1 weight is connected, below the %.0f%% quorum needed for the L1 to make progress",
+			report.ConnectedWeightPercentage,
+			thresholds.QuorumPercentage,
+		))
+	}
+	return report, nil
+}
+
+func percentage(part uint64, total uint64) float64 {
+	return float64(part) * 100 / float64(total)
+}
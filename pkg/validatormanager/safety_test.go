@@ -0,0 +1,89 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/plugin/evm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeValidatorRemoval(t *testing.T) {
+	nodeIDs := []ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()}
+	validator := func(i int, weight uint64, isActive bool, isConnected bool) evm.CurrentValidator {
+		return evm.CurrentValidator{NodeID: nodeIDs[i], Weight: weight, IsActive: isActive, IsConnected: isConnected}
+	}
+	type test struct {
+		name       string
+		validators []evm.CurrentValidator
+		thresholds RemovalSafetyThresholds
+		warnings   []string
+		errMsg     string
+	}
+	tests := []test{
+		{
+			name:       "safe removal",
+			validators: []evm.CurrentValidator{validator(0, 100, true, true), validator(1, 100, true, true), validator(2, 100, true, true), validator(3, 100, true, true), validator(4, 100, true, true)},
+			thresholds: DefaultRemovalSafetyThresholds,
+		},
+		{
+			name:       "not a validator",
+			validators: []evm.CurrentValidator{validator(1, 100, true, true)},
+			thresholds: DefaultRemovalSafetyThresholds,
+			errMsg:     "is not a validator of the L1",
+		},
+		{
+			name:       "last validator",
+			validators: []evm.CurrentValidator{validator(0, 100, true, true)},
+			thresholds: DefaultRemovalSafetyThresholds,
+			warnings:   []string{"below the minimum of 1"},
+		},
+		{
+			name:       "last active validator without min",
+			validators: []evm.CurrentValidator{validator(0, 100, true, true), validator(1, 100, false, false)},
+			thresholds: RemovalSafetyThresholds{QuorumPercentage: 67, MaxWeightPercentage: 33},
+			warnings:   []string{"without active validators"},
+		},
+		{
+			name:       "below configured min",
+			validators: []evm.CurrentValidator{validator(0, 100, true, true), validator(1, 100, true, true), validator(2, 100, true, true), validator(3, 100, true, true), validator(4, 100, true, true)},
+			thresholds: RemovalSafetyThresholds{MinValidators: 5, QuorumPercentage: 67, MaxWeightPercentage: 33},
+			warnings:   []string{"4 active validators, below the minimum of 5"},
+		},
+		{
+			name:       "single validator above max weight",
+			validators: []evm.CurrentValidator{validator(0, 100, true, true), validator(1, 100, true, true), validator(2, 100, true, true), validator(3, 100, true, true), validator(4, 300, true, true)},
+			thresholds: DefaultRemovalSafetyThresholds,
+			warnings:   []string{"would hold 50.00% of the L1 weight"},
+		},
+		{
+			name:       "connected weight below quorum",
+			validators: []evm.CurrentValidator{validator(0, 100, true, true), validator(1, 100, true, true), validator(2, 100, true, true), validator(3, 100, true, false), validator(4, 100, true, false)},
+			thresholds: DefaultRemovalSafetyThresholds,
+			warnings:   []string{"only 50.00% of the remaining L1 weight is connected"},
+		},
+		{
+			name:       "inactive validators do not count",
+			validators: []evm.CurrentValidator{validator(0, 100, true, true), validator(1, 100, true, true), validator(2, 100, true, true), validator(3, 1000, false, false)},
+			thresholds: DefaultRemovalSafetyThresholds,
+			warnings:   []string{"would hold 50.00% of the L1 weight"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := AnalyzeValidatorRemoval(tt.validators, nodeIDs[0], tt.thresholds)
+			if tt.errMsg != "" {
+				require.ErrorContains(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, report.Warnings, len(tt.warnings))
+			for i, warning := range tt.warnings {
+				require.Contains(t, report.Warnings[i], warning)
+			}
+			require.Equal(t, len(tt.warnings) == 0, report.IsSafe())
+		})
+	}
+}
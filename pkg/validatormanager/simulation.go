@@ -0,0 +1,376 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ava-labs/avalanchego/ids"
+	warpMessage "github.com/ava-labs/avalanchego/vms/platformvm/warp/message"
+	"github.com/ava-labs/subnet-evm/core/types"
+	subnetEvmPlugin "github.com/ava-labs/subnet-evm/plugin/evm"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	validationPeriodCreatedEventSpec     = eventSpec(validatorManagerSDK.ValidationPeriodCreatedEventSignature)
+	validatorRemovalInitializedEventSpec = eventSpec(validatorManagerSDK.ValidatorRemovalInitializedEventSignature)
+)
+
+// validator status, as it would be after the simulated operation
+const (
+	SimulatedValidatorStatusActive         = "Active"
+	SimulatedValidatorStatusInactive       = "Inactive"
+	SimulatedValidatorStatusPendingAdded   = "PendingAdded"
+	SimulatedValidatorStatusPendingRemoved = "PendingRemoved"
+)
+
+// SimulationEvent is a validator manager event emitted on the simulation
+type SimulationEvent struct {
+	Name         string
+	ValidationID ids.ID
+	Weight       uint64
+}
+
+// SimulatedValidator is an L1 validator as it would be after the simulated operation
+type SimulatedValidator struct {
+	NodeID       ids.NodeID
+	ValidationID ids.ID
+	Weight       uint64
+	Status       string
+}
+
+// SimulationReport describes the outcome of a validator manager operation executed
+// on top of the current L1 state, without issuing any tx
+type SimulationReport struct {
+	GasUsed    uint64
+	Events     []SimulationEvent
+	Validators []SimulatedValidator
+	// steps of the operation that can not be simulated
+	SkippedSteps []string
+}
+
+// SimulateValidatorRegistration executes the initialization of the registration of [nodeID]
+// on top of the state of the L1 served at [rpcURL], and reports the resulting validator set.
+// The call is executed by the L1 node itself, so the warp message the validator manager
+// sends is produced as it would be for a real tx
+func SimulateValidatorRegistration(
+	rpcURL string,
+	ownerPrivateKey string,
+	nodeID ids.NodeID,
+	blsPublicKey []byte,
+	expiry uint64,
+	balanceOwners warpMessage.PChainOwner,
+	disableOwners warpMessage.PChainOwner,
+	weight uint64,
+	initWithPos bool,
+	delegationFee uint16,
+	stakeDuration time.Duration,
+	stakeAmount *big.Int,
+	erc20Staking bool,
+) (*SimulationReport, error) {
+	validators, err := utils.GetL1Validators(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failure getting L1 validators: %w", err)
+	}
+	ux.Logger.PrintToUser("Simulating validator registration on %s", rpcURL)
+	managerAddress := common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+	validatorRegistrationInput := getValidatorRegistrationInput(
+		nodeID,
+		blsPublicKey,
+		expiry,
+		balanceOwners,
+		disableOwners,
+	)
+	var trace evm.CallTrace
+	switch {
+	case initWithPos && erc20Staking:
+		if err := checkStakeAllowance(rpcURL, managerAddress, ownerPrivateKey, stakeAmount); err != nil {
+			return nil, err
+		}
+		trace, err = contract.SimulateTxToMethod(
+			rpcURL,
+			ownerPrivateKey,
+			managerAddress,
+			nil,
+			"initialize validator registration with ERC20 stake",
+			validatorManagerSDK.ErrorSignatureToError,
+			"initializeValidatorRegistration((bytes,bytes,uint64,(uint32,[address]),(uint32,[address])),uint16,uint64,uint256)",
+			validatorRegistrationInput,
+			delegationFee,
+			uint64(stakeDuration.Seconds()),
+			stakeAmount,
+		)
+	case initWithPos:
+		trace, err = contract.SimulateTxToMethod(
+			rpcURL,
+			ownerPrivateKey,
+			managerAddress,
+			stakeAmount,
+			"initialize validator registration with stake",
+			validatorManagerSDK.ErrorSignatureToError,
+			"initializeValidatorRegistration((bytes,bytes,uint64,(uint32,[address]),(uint32,[address])),uint16,uint64)",
+			validatorRegistrationInput,
+			delegationFee,
+			uint64(stakeDuration.Seconds()),
+		)
+	default:
+		trace, err = contract.SimulateTxToMethod(
+			rpcURL,
+			ownerPrivateKey,
+			managerAddress,
+			big.NewInt(0),
+			"initialize validator registration",
+			validatorManagerSDK.ErrorSignatureToError,
+			"initializeValidatorRegistration((bytes,bytes,uint64,(uint32,[address]),(uint32,[address])),uint64)",
+			validatorRegistrationInput,
+			weight,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("simulated validator registration failed: %w", err)
+	}
+	report := newSimulationReport(trace, validators, nodeID)
+	report.SkippedSteps = []string{
+		"RegisterL1ValidatorTx on the P-Chain",
+		"registration completion on the validator manager (needs the P-Chain signed acknowledgement)",
+	}
+	return report, nil
+}
+
+// SimulateValidatorRemoval executes the initialization of the removal of [nodeID]
+// on top of the state of the L1 served at [rpcURL], and reports the resulting validator set.
+// PoS removals can only be simulated if [force] is set, as uptime proofs are warp
+// predicates that are only verified when the tx is included in a block
+func SimulateValidatorRemoval(
+	rpcURL string,
+	ownerPrivateKey string,
+	nodeID ids.NodeID,
+	initWithPos bool,
+	force bool,
+) (*SimulationReport, error) {
+	if initWithPos && !force {
+		return nil, fmt.Errorf("PoS validator removal with uptime proof can not be simulated, as uptime proofs are only verified when included in a block. Use --force to simulate a removal without uptime proof")
+	}
+	validators, err := utils.GetL1Validators(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failure getting L1 validators: %w", err)
+	}
+	ux.Logger.PrintToUser("Simulating validator removal on %s", rpcURL)
+	managerAddress := common.HexToAddress(validatorManagerSDK.ProxyContractAddress)
+	validationID, err := GetRegisteredValidator(rpcURL, managerAddress, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	var trace evm.CallTrace
+	if initWithPos {
+		trace, err = contract.SimulateTxToMethod(
+			rpcURL,
+			ownerPrivateKey,
+			managerAddress,
+			big.NewInt(0),
+			"force POS validator removal",
+			validatorManagerSDK.ErrorSignatureToError,
+			"forceInitializeEndValidation(bytes32,bool,uint32)",
+			validationID,
+			false, // no uptime proof if force
+			uint32(0),
+		)
+	} else {
+		trace, err = contract.SimulateTxToMethod(
+			rpcURL,
+			ownerPrivateKey,
+			managerAddress,
+			big.NewInt(0),
+			"POA validator removal initialization",
+			validatorManagerSDK.ErrorSignatureToError,
+			"initializeEndValidation(bytes32)",
+			validationID,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("simulated validator removal failed: %w", err)
+	}
+	report := newSimulationReport(trace, validators, nodeID)
+	report.SkippedSteps = []string{
+		"SetL1ValidatorWeightTx on the P-Chain",
+		"removal completion on the validator manager (needs the P-Chain signed acknowledgement)",
+	}
+	return report, nil
+}
+
+func getValidatorRegistrationInput(
+	nodeID ids.NodeID,
+	blsPublicKey []byte,
+	expiry uint64,
+	balanceOwners warpMessage.PChainOwner,
+	disableOwners warpMessage.PChainOwner,
+) interface{} {
+	type PChainOwner struct {
+		Threshold uint32
+		Addresses []common.Address
+	}
+	type ValidatorRegistrationInput struct {
+		NodeID                []byte
+		BlsPublicKey          []byte
+		RegistrationExpiry    uint64
+		RemainingBalanceOwner PChainOwner
+		DisableOwner          PChainOwner
+	}
+	toAddress := func(addr ids.ShortID) common.Address {
+		return common.BytesToAddress(addr[:])
+	}
+	return ValidatorRegistrationInput{
+		NodeID:             nodeID[:],
+		BlsPublicKey:       blsPublicKey,
+		RegistrationExpiry: expiry,
+		RemainingBalanceOwner: PChainOwner{
+			Threshold: balanceOwners.Threshold,
+			Addresses: utils.Map(balanceOwners.Addresses, toAddress),
+		},
+		DisableOwner: PChainOwner{
+			Threshold: disableOwners.Threshold,
+			Addresses: utils.Map(disableOwners.Addresses, toAddress),
+		},
+	}
+}
+
+// checkStakeAllowance fails if the owner of [privateKey] has not yet approved [stakeAmount]
+// of the staking token to the manager, as the approval would be a separate tx that
+// the simulation can not build upon
+func checkStakeAllowance(
+	rpcURL string,
+	managerAddress common.Address,
+	privateKey string,
+	stakeAmount *big.Int,
+) error {
+	tokenAddress, err := GetStakingToken(rpcURL, managerAddress)
+	if err != nil {
+		return err
+	}
+	owner, err := utils.PrivateKeyToAddress(privateKey)
+	if err != nil {
+		return err
+	}
+	allowance, err := getTokenAmount(rpcURL, tokenAddress, "allowance(address,address)->(uint256)", owner, managerAddress)
+	if err != nil {
+		return err
+	}
+	if allowance.Cmp(stakeAmount) < 0 {
+		return fmt.Errorf(
+			"%s has approved %s of token %s to the validator manager, that is less than the stake %s. Approve the stake before simulating",
+			owner.Hex(),
+			allowance,
+			tokenAddress.Hex(),
+			stakeAmount,
+		)
+	}
+	return nil
+}
+
+func newSimulationReport(
+	trace evm.CallTrace,
+	validators []subnetEvmPlugin.CurrentValidator,
+	nodeID ids.NodeID,
+) *SimulationReport {
+	events := ParseSimulationEvents(trace.AllLogs())
+	return &SimulationReport{
+		GasUsed:    uint64(trace.GasUsed),
+		Events:     events,
+		Validators: ApplySimulationEvents(validators, nodeID, events),
+	}
+}
+
+// ParseSimulationEvents extracts the validator set changes from the validator
+// manager events on [logs]. Events not affecting the validator set are only named
+func ParseSimulationEvents(logs []*types.Log) []SimulationEvent {
+	eventNames := map[common.Hash]string{
+		eventTopic(validationPeriodCreatedEventSpec):     "ValidationPeriodCreated",
+		eventTopic(validatorRemovalInitializedEventSpec): "ValidatorRemovalInitialized",
+		eventTopic(validatorWeightUpdateEventSpec):       "ValidatorWeightUpdate",
+		eventTopic(delegatorAddedEventSpec):              "DelegatorAdded",
+	}
+	events := []SimulationEvent{}
+	for _, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		name, ok := eventNames[log.Topics[0]]
+		if !ok {
+			events = append(events, SimulationEvent{Name: log.Topics[0].Hex()})
+			continue
+		}
+		event := SimulationEvent{Name: name}
+		switch name {
+		case "DelegatorAdded":
+			// validation ID is the second indexed field
+			if len(log.Topics) > 2 {
+				event.ValidationID = ids.ID(log.Topics[2])
+			}
+		default:
+			// validation ID is the first indexed field, and weight the first data field
+			if len(log.Topics) > 1 {
+				event.ValidationID = ids.ID(log.Topics[1])
+			}
+			if len(log.Data) >= common.HashLength {
+				event.Weight = new(big.Int).SetBytes(log.Data[:common.HashLength]).Uint64()
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// ApplySimulationEvents returns the validator set resulting from applying [events],
+// emitted by an operation on [nodeID], to the current L1 [validators]
+func ApplySimulationEvents(
+	validators []subnetEvmPlugin.CurrentValidator,
+	nodeID ids.NodeID,
+	events []SimulationEvent,
+) []SimulatedValidator {
+	result := []SimulatedValidator{}
+	for _, validator := range validators {
+		status := SimulatedValidatorStatusActive
+		if !validator.IsActive {
+			status = SimulatedValidatorStatusInactive
+		}
+		result = append(result, SimulatedValidator{
+			NodeID:       validator.NodeID,
+			ValidationID: validator.ValidationID,
+			Weight:       validator.Weight,
+			Status:       status,
+		})
+	}
+	for _, event := range events {
+		switch event.Name {
+		case "ValidationPeriodCreated":
+			result = append(result, SimulatedValidator{
+				NodeID:       nodeID,
+				ValidationID: event.ValidationID,
+				Weight:       event.Weight,
+				Status:       SimulatedValidatorStatusPendingAdded,
+			})
+		case "ValidatorRemovalInitialized":
+			for i := range result {
+				if result[i].ValidationID == event.ValidationID {
+					result[i].Weight = 0
+					result[i].Status = SimulatedValidatorStatusPendingRemoved
+				}
+			}
+		case "ValidatorWeightUpdate":
+			for i := range result {
+				if result[i].ValidationID == event.ValidationID {
+					result[i].Weight = event.Weight
+				}
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/plugin/evm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSimulationEvents(t *testing.T) {
+	require := require.New(t)
+	validationID := ids.GenerateTestID()
+	weightWord := common.LeftPadBytes(big.NewInt(20).Bytes(), common.HashLength)
+	unknownTopic := common.HexToHash("0x01")
+	logs := []*types.Log{
+		{
+			Topics: []common.Hash{eventTopic(validationPeriodCreatedEventSpec), common.Hash(validationID), {}, {}},
+			Data:   append(weightWord, make([]byte, common.HashLength)...),
+		},
+		{
+			Topics: []common.Hash{unknownTopic},
+		},
+	}
+	require.Equal([]SimulationEvent{
+		{Name: "ValidationPeriodCreated", ValidationID: validationID, Weight: 20},
+		{Name: unknownTopic.Hex()},
+	}, ParseSimulationEvents(logs))
+}
+
+func TestApplySimulationEvents(t *testing.T) {
+	require := require.New(t)
+	nodeIDs := []ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()}
+	validationIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()}
+	validators := []evm.CurrentValidator{
+		{NodeID: nodeIDs[0], ValidationID: validationIDs[0], Weight: 100, IsActive: true},
+		{NodeID: nodeIDs[1], ValidationID: validationIDs[1], Weight: 100, IsActive: false},
+	}
+
+	added := ApplySimulationEvents(validators, nodeIDs[2], []SimulationEvent{
+		{Name: "ValidationPeriodCreated", ValidationID: validationIDs[2], Weight: 20},
+	})
+	require.Equal([]SimulatedValidator{
+		{NodeID: nodeIDs[0], ValidationID: validationIDs[0], Weight: 100, Status: SimulatedValidatorStatusActive},
+		{NodeID: nodeIDs[1], ValidationID: validationIDs[1], Weight: 100, Status: SimulatedValidatorStatusInactive},
+		{NodeID: nodeIDs[2], ValidationID: validationIDs[2], Weight: 20, Status: SimulatedValidatorStatusPendingAdded},
+	}, added)
+
+	removed := ApplySimulationEvents(validators, nodeIDs[0], []SimulationEvent{
+		{Name: "ValidatorRemovalInitialized", ValidationID: validationIDs[0], Weight: 100},
+	})
+	require.Equal(SimulatedValidator{
+		NodeID:       nodeIDs[0],
+		ValidationID: validationIDs[0],
+		Weight:       0,
+		Status:       SimulatedValidatorStatusPendingRemoved,
+	}, removed[0])
+	require.Len(removed, 2)
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ICM contracts ExampleERC20, from ava-labs/icm-contracts commit a4bd5c92b056: a mintable
+// token that funds its deployer with 1e28 units, and lets anyone mint up to 1e19 units per
+// call. Meant for local and devnet networks only
+//
+//go:embed example_erc20_bytecode.txt
+var exampleERC20Bytecode []byte
+
+// DeployExampleStakingToken deploys a mintable ERC20 token on the blockchain at [rpcURL],
+// to be staked on an ERC20 token staking manager, returning its address. The whole initial
+// supply goes to the deployer
+func DeployExampleStakingToken(rpcURL string, privateKey string) (common.Address, error) {
+	return contract.DeployContract(
+		rpcURL,
+		privateKey,
+		[]byte(strings.TrimSpace(string(exampleERC20Bytecode))),
+		"()",
+	)
+}
+
+// GetStakingToken returns the token staked on the ERC20 token staking manager [managerAddress]
+func GetStakingToken(
+	rpcURL string,
+	managerAddress common.Address,
+) (common.Address, error) {
+	out, err := contract.CallToMethod(
+		rpcURL,
+		managerAddress,
+		"erc20()->(address)",
+	)
+	if err != nil {
+		return common.Address{}, err
+	}
+	tokenAddress, b := out[0].(common.Address)
+	if !b {
+		return common.Address{}, fmt.Errorf("error at erc20 call, expected address, got %T", out[0])
+	}
+	return tokenAddress, nil
+}
+
+// CheckStakingTokenContract verifies that [tokenAddress] holds a contract on the blockchain at
+// [rpcURL] that answers as an ERC20 token
+func CheckStakingTokenContract(rpcURL string, tokenAddress common.Address) error {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if deployed, err := evm.ContractAlreadyDeployed(client, tokenAddress.Hex()); err != nil {
+		return err
+	} else if !deployed {
+		return fmt.Errorf("no contract found at staking token address %s", tokenAddress.Hex())
+	}
+	if _, err := getTokenAmount(rpcURL, tokenAddress, "balanceOf(address)->(uint256)", tokenAddress); err != nil {
+		return fmt.Errorf("contract at %s is not an ERC20 token: %w", tokenAddress.Hex(), err)
+	}
+	return nil
+}
+
+// ApproveStake makes sure the ERC20 token staking manager [managerAddress] can transfer
+// [stakeAmount] of its staking token from the owner of [privateKey], approving it if the
+// current allowance is not enough. Fails if the owner does not have [stakeAmount] tokens
+func ApproveStake(
+	rpcURL string,
+	managerAddress common.Address,
+	privateKey string,
+	stakeAmount *big.Int,
+) error {
+	tokenAddress, err := GetStakingToken(rpcURL, managerAddress)
+	if err != nil {
+		return err
+	}
+	owner, err := utils.PrivateKeyToAddress(privateKey)
+	if err != nil {
+		return err
+	}
+	balance, err := getTokenAmount(rpcURL, tokenAddress, "balanceOf(address)->(uint256)", owner)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(stakeAmount) < 0 {
+		return fmt.Errorf(
+			"%s has a staking token balance of %s, that is not enough to stake %s (token %s)",
+			owner.Hex(),
+			balance,
+			stakeAmount,
+			tokenAddress.Hex(),
+		)
+	}
+	allowance, err := getTokenAmount(rpcURL, tokenAddress, "allowance(address,address)->(uint256)", owner, managerAddress)
+	if err != nil {
+		return err
+	}
+	if allowance.Cmp(stakeAmount) >= 0 {
+		ux.Logger.PrintToUser("Validator manager allowance of %s already covers the stake", allowance)
+		return nil
+	}
+	ux.Logger.PrintToUser("Approving the validator manager to transfer %s of token %s from %s", stakeAmount, tokenAddress.Hex(), owner.Hex())
+	tx, _, err := contract.TxToMethod(
+		rpcURL,
+		privateKey,
+		tokenAddress,
+		nil,
+		"staking token approve",
+		nil,
+		"approve(address,uint256)->(bool)",
+		managerAddress,
+		stakeAmount,
+	)
+	if err != nil {
+		return evm.TransactionError(tx, err, "failure approving the stake")
+	}
+	return nil
+}
+
+func getTokenAmount(
+	rpcURL string,
+	tokenAddress common.Address,
+	methodSpec string,
+	params ...interface{},
+) (*big.Int, error) {
+	out, err := contract.CallToMethod(rpcURL, tokenAddress, methodSpec, params...)
+	if err != nil {
+		return nil, err
+	}
+	amount, b := out[0].(*big.Int)
+	if !b {
+		return nil, fmt.Errorf("error at %s call, expected *big.Int, got %T", methodSpec, out[0])
+	}
+	return amount, nil
+}
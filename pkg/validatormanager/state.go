@@ -0,0 +1,149 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// validatorManagerStorageLocation is the ERC-7201 namespace of the validator manager storage.
+// Its first slots hold the L1 ID, the churn settings and the churn tracker of the current
+// churn period
+var validatorManagerStorageLocation = common.HexToHash("0xe92546d698950ddd38910d2e15ed1d923cd0a7b3dde9e2a6a3f380565559cb00")
+
+const validatorManagerSettingsSlots = 4
+
+// ManagerState is the churn configuration of a validator manager, and the churn
+// consumed on its current churn period
+type ManagerState struct {
+	L1ID                   ids.ID
+	ChurnPeriodSeconds     uint64
+	MaximumChurnPercentage uint8
+	// current churn period
+	ChurnPeriodStartedAt     uint64
+	ChurnPeriodInitialWeight uint64
+	ChurnPeriodTotalWeight   uint64
+	ChurnPeriodChurnAmount   uint64
+}
+
+// GetManagerState reads the churn configuration and tracker of the validator manager at
+// [managerAddress] from its storage
+func GetManagerState(rpcURL string, managerAddress common.Address) (ManagerState, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return ManagerState{}, err
+	}
+	defer client.Close()
+	slots := [validatorManagerSettingsSlots]common.Hash{}
+	base := validatorManagerStorageLocation.Big()
+	for i := range slots {
+		slot := common.BigToHash(new(big.Int).Add(base, big.NewInt(int64(i))))
+		value, err := utils.CallAPI(rpcURL, func(ctx context.Context) ([]byte, error) {
+			return client.StorageAt(ctx, managerAddress, slot, nil)
+		})
+		if err != nil {
+			return ManagerState{}, err
+		}
+		slots[i] = common.BytesToHash(value)
+	}
+	return parseManagerState(slots), nil
+}
+
+// parseManagerState decodes the first storage slots of the validator manager namespace.
+// Packed fields are stored from the lowest order bytes of the slot
+func parseManagerState(slots [validatorManagerSettingsSlots]common.Hash) ManagerState {
+	packedUint64 := func(slot common.Hash, offset int) uint64 {
+		return new(big.Int).SetBytes(slot[common.HashLength-offset-8 : common.HashLength-offset]).Uint64()
+	}
+	return ManagerState{
+		L1ID:                     ids.ID(slots[0]),
+		ChurnPeriodSeconds:       packedUint64(slots[1], 0),
+		MaximumChurnPercentage:   slots[1][common.HashLength-9],
+		ChurnPeriodStartedAt:     slots[2].Big().Uint64(),
+		ChurnPeriodInitialWeight: packedUint64(slots[3], 0),
+		ChurnPeriodTotalWeight:   packedUint64(slots[3], 8),
+		ChurnPeriodChurnAmount:   packedUint64(slots[3], 16),
+	}
+}
+
+// GetManagerOwner returns the owner of the validator manager at [managerAddress]
+func GetManagerOwner(rpcURL string, managerAddress common.Address) (common.Address, error) {
+	out, err := contract.CallToMethod(
+		rpcURL,
+		managerAddress,
+		"owner()->(address)",
+	)
+	if err != nil {
+		return common.Address{}, err
+	}
+	owner, b := out[0].(common.Address)
+	if !b {
+		return common.Address{}, fmt.Errorf("error at owner call, expected common.Address, got %T", out[0])
+	}
+	return owner, nil
+}
+
+// PendingValidatorChange is a validator set change initiated on the validator manager,
+// that is waiting for the P-Chain acknowledgement to be completed
+type PendingValidatorChange struct {
+	ValidationID ids.ID
+	// addition or removal
+	Change string
+	// weight of a validator being added
+	Weight      uint64
+	BlockNumber uint64
+	TxHash      common.Hash
+}
+
+// pending validator change kinds
+const (
+	PendingAddition = "addition"
+	PendingRemoval  = "removal"
+)
+
+// GetPendingValidatorChanges returns the validator registrations and removals initiated on
+// [events] that were not completed, ordered by the block they were initiated on
+func GetPendingValidatorChanges(events []ValidatorManagerEvent) []PendingValidatorChange {
+	pending := map[ids.ID]PendingValidatorChange{}
+	for _, event := range events {
+		switch event.Name {
+		case ValidationPeriodCreatedEvent:
+			pending[event.ValidationID] = PendingValidatorChange{
+				ValidationID: event.ValidationID,
+				Change:       PendingAddition,
+				Weight:       event.Weight,
+				BlockNumber:  event.BlockNumber,
+				TxHash:       event.TxHash,
+			}
+		case ValidatorRemovalInitializedEvent:
+			pending[event.ValidationID] = PendingValidatorChange{
+				ValidationID: event.ValidationID,
+				Change:       PendingRemoval,
+				BlockNumber:  event.BlockNumber,
+				TxHash:       event.TxHash,
+			}
+		case ValidationPeriodRegisteredEvent, ValidationPeriodEndedEvent:
+			delete(pending, event.ValidationID)
+		}
+	}
+	changes := make([]PendingValidatorChange, 0, len(pending))
+	for _, change := range pending {
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].BlockNumber != changes[j].BlockNumber {
+			return changes[i].BlockNumber < changes[j].BlockNumber
+		}
+		return changes[i].ValidationID.String() < changes[j].ValidationID.String()
+	})
+	return changes
+}
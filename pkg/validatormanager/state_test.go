@@ -0,0 +1,59 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManagerState(t *testing.T) {
+	require := require.New(t)
+	l1ID := ids.GenerateTestID()
+	// churn period of 1h and 20% max churn, packed from the lowest order bytes
+	settings := common.HexToHash("0x14" + "0000000000000e10")
+	// initial weight 100, total weight 120, churn amount 20
+	tracker := common.HexToHash("0x" + "0000000000000014" + "0000000000000078" + "0000000000000064")
+	state := parseManagerState([validatorManagerSettingsSlots]common.Hash{
+		common.Hash(l1ID),
+		settings,
+		common.BigToHash(big.NewInt(1 << 30)),
+		tracker,
+	})
+	require.Equal(l1ID, state.L1ID)
+	require.Equal(uint64(3600), state.ChurnPeriodSeconds)
+	require.Equal(uint8(20), state.MaximumChurnPercentage)
+	require.Equal(uint64(1<<30), state.ChurnPeriodStartedAt)
+	require.Equal(uint64(100), state.ChurnPeriodInitialWeight)
+	require.Equal(uint64(120), state.ChurnPeriodTotalWeight)
+	require.Equal(uint64(20), state.ChurnPeriodChurnAmount)
+}
+
+func TestGetPendingValidatorChanges(t *testing.T) {
+	require := require.New(t)
+	added := ids.GenerateTestID()
+	pendingAdd := ids.GenerateTestID()
+	removed := ids.GenerateTestID()
+	pendingRemoval := ids.GenerateTestID()
+	events := []ValidatorManagerEvent{
+		{Name: InitialValidatorCreatedEvent, ValidationID: removed, Weight: 100, BlockNumber: 1},
+		{Name: InitialValidatorCreatedEvent, ValidationID: pendingRemoval, Weight: 100, BlockNumber: 1},
+		{Name: ValidationPeriodCreatedEvent, ValidationID: added, Weight: 20, BlockNumber: 2},
+		{Name: ValidationPeriodRegisteredEvent, ValidationID: added, Weight: 20, BlockNumber: 3},
+		{Name: ValidatorRemovalInitializedEvent, ValidationID: removed, BlockNumber: 4},
+		{Name: ValidationPeriodEndedEvent, ValidationID: removed, BlockNumber: 5},
+		{Name: ValidatorRemovalInitializedEvent, ValidationID: pendingRemoval, BlockNumber: 6},
+		{Name: ValidationPeriodCreatedEvent, ValidationID: pendingAdd, Weight: 30, BlockNumber: 7},
+	}
+	changes := GetPendingValidatorChanges(events)
+	require.Len(changes, 2)
+	require.Equal(pendingRemoval, changes[0].ValidationID)
+	require.Equal(PendingRemoval, changes[0].Change)
+	require.Equal(pendingAdd, changes[1].ValidationID)
+	require.Equal(PendingAddition, changes[1].Change)
+	require.Equal(uint64(30), changes[1].Weight)
+}
@@ -48,6 +48,39 @@ func AddPoSValidatorManagerContractToAllocations(
 	}
 }
 
+// The ERC20 token staking manager comes from a newer ICM contracts build than the PoA and
+// native token PoS managers: ava-labs/icm-contracts commit a4bd5c92b056 (the version on go.mod),
+// runtime bytecode of ERC20TokenStakingManager. Its initialize settings add uptimeBlockchainID,
+// and ValidatorMessages is an external library, linked at
+// validatorManagerSDK.ValidatorMessagesContractAddress
+//
+//go:embed deployed_erc20_pos_validator_manager_bytecode.txt
+var deployedERC20PoSValidatorManagerBytecode []byte
+
+// ValidatorMessages library runtime bytecode, from the same ICM contracts commit
+//
+//go:embed deployed_validator_messages_bytecode.txt
+var deployedValidatorMessagesBytecode []byte
+
+// AddERC20PoSValidatorManagerContractToAllocations adds an ERC20 token staking manager,
+// together with the validator messages library it is linked to
+func AddERC20PoSValidatorManagerContractToAllocations(
+	allocs core.GenesisAlloc,
+) {
+	deployedERC20PoSValidatorManagerBytes := common.FromHex(strings.TrimSpace(string(deployedERC20PoSValidatorManagerBytecode)))
+	allocs[common.HexToAddress(validatorManagerSDK.ValidatorContractAddress)] = core.GenesisAccount{
+		Balance: big.NewInt(0),
+		Code:    deployedERC20PoSValidatorManagerBytes,
+		Nonce:   1,
+	}
+	deployedValidatorMessagesBytes := common.FromHex(strings.TrimSpace(string(deployedValidatorMessagesBytecode)))
+	allocs[common.HexToAddress(validatorManagerSDK.ValidatorMessagesContractAddress)] = core.GenesisAccount{
+		Balance: big.NewInt(0),
+		Code:    deployedValidatorMessagesBytes,
+		Nonce:   1,
+	}
+}
+
 //go:embed deployed_transparent_proxy_bytecode.txt
 var deployedTransparentProxyBytecode []byte
 
@@ -118,7 +151,7 @@ func SetupPoA(
 	if err != nil {
 		aggregatorLogLevel = defaultAggregatorLogLevel
 	}
-	return subnet.InitializeProofOfAuthority(network, privateKey, aggregatorExtraPeerEndpoints, aggregatorAllowPrivatePeers, aggregatorLogLevel)
+	return subnet.InitializeProofOfAuthority(network.SDKNetwork(), privateKey, aggregatorExtraPeerEndpoints, aggregatorAllowPrivatePeers, aggregatorLogLevel)
 }
 
 // setups PoA manager after a successful execution of
@@ -139,7 +172,7 @@ func SetupPoS(
 	if err != nil {
 		aggregatorLogLevel = defaultAggregatorLogLevel
 	}
-	return subnet.InitializeProofOfStake(network,
+	return subnet.InitializeProofOfStake(network.SDKNetwork(),
 		privateKey,
 		aggregatorExtraPeerEndpoints,
 		aggregatorAllowPrivatePeers,
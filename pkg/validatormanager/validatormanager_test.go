@@ -0,0 +1,29 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package validatormanager
+
+import (
+	"bytes"
+	"testing"
+
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddERC20PoSValidatorManagerContractToAllocations(t *testing.T) {
+	require := require.New(t)
+	allocs := core.GenesisAlloc{}
+	AddERC20PoSValidatorManagerContractToAllocations(allocs)
+	require.Len(allocs, 2)
+	manager, ok := allocs[common.HexToAddress(validatorManagerSDK.ValidatorContractAddress)]
+	require.True(ok)
+	require.NotEmpty(manager.Code)
+	library, ok := allocs[common.HexToAddress(validatorManagerSDK.ValidatorMessagesContractAddress)]
+	require.True(ok)
+	require.NotEmpty(library.Code)
+	// the manager calls the library at its genesis address
+	libraryAddress := common.HexToAddress(validatorManagerSDK.ValidatorMessagesContractAddress)
+	require.True(bytes.Contains(manager.Code, libraryAddress.Bytes()))
+}
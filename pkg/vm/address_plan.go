@@ -0,0 +1,158 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	icmgenesis "github.com/ava-labs/avalanche-cli/pkg/interchain/genesis"
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PlannedAddress is an address placed on a Subnet-EVM genesis, or derived by the CLI
+// for the blockchain, together with the reasons it is there
+type PlannedAddress struct {
+	Address common.Address
+	Roles   []string
+	// initial token allocation, nil if not funded
+	Balance *big.Int
+	// has contract code on genesis
+	Contract bool
+	// false for addresses derived by the CLI that are not part of genesis, eg
+	// contracts deployed after the blockchain is created
+	InGenesis bool
+}
+
+// well known addresses the CLI places on genesis
+var genesisContractRoles = map[common.Address]string{
+	common.HexToAddress(validatorManagerSDK.ValidatorContractAddress):  "Validator Manager implementation",
+	common.HexToAddress(validatorManagerSDK.ProxyContractAddress):      "Validator Manager (transparent proxy)",
+	common.HexToAddress(validatorManagerSDK.ProxyAdminContractAddress): "Validator Manager proxy admin",
+	common.HexToAddress(validatorManagerSDK.RewardCalculatorAddress):   "PoS reward calculator",
+	common.HexToAddress(icmgenesis.MessengerContractAddress):           "ICM messenger",
+	common.HexToAddress(icmgenesis.RegistryContractAddress):            "ICM registry",
+	common.HexToAddress(icmgenesis.MessengerDeployerAddress):           "ICM messenger deployer",
+	common.HexToAddress(VestingContractAddress):                        "Vesting contract (locked tokens)",
+	PrefundedEwoqAddress: "ewoq test key",
+}
+
+// allow list roles, as named on precompile configs
+var allowListRoles = []struct {
+	field string
+	name  string
+}{
+	{"adminAddresses", "admin"},
+	{"managerAddresses", "manager"},
+	{"enabledAddresses", "enabled"},
+}
+
+// GetAddressPlan lists every address of the Subnet-EVM genesis [genesisBytes] (allocations
+// and precompile allow lists), with its roles and funding. Well known CLI addresses are
+// labeled, as are the ones given on [roles], that are included even if not on genesis.
+// Entries are sorted with genesis contracts first, then by address
+func GetAddressPlan(genesisBytes []byte, roles map[common.Address][]string) ([]PlannedAddress, error) {
+	var genesis core.Genesis
+	if err := json.Unmarshal(genesisBytes, &genesis); err != nil {
+		return nil, fmt.Errorf("invalid Subnet-EVM genesis: %w", err)
+	}
+	plan := map[common.Address]*PlannedAddress{}
+	get := func(address common.Address) *PlannedAddress {
+		entry, ok := plan[address]
+		if !ok {
+			entry = &PlannedAddress{Address: address}
+			plan[address] = entry
+		}
+		return entry
+	}
+	for address, account := range genesis.Alloc {
+		entry := get(address)
+		entry.InGenesis = true
+		entry.Contract = len(account.Code) > 0
+		if account.Balance != nil && account.Balance.Sign() > 0 {
+			entry.Balance = account.Balance
+		}
+	}
+	allowLists, err := getGenesisAllowLists(genesisBytes)
+	if err != nil {
+		return nil, err
+	}
+	for _, precompile := range sortedKeys(allowLists) {
+		for address, role := range allowLists[precompile] {
+			entry := get(address)
+			entry.InGenesis = true
+			entry.Roles = append(entry.Roles, fmt.Sprintf("%s %s", strings.TrimSuffix(precompile, "Config"), role))
+		}
+	}
+	for address, entry := range plan {
+		if role, ok := genesisContractRoles[address]; ok {
+			entry.Roles = append([]string{role}, entry.Roles...)
+		}
+	}
+	for address, addressRoles := range roles {
+		entry := get(address)
+		entry.Roles = append(append([]string{}, addressRoles...), entry.Roles...)
+	}
+	entries := make([]PlannedAddress, 0, len(plan))
+	for _, entry := range plan {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Contract != entries[j].Contract {
+			return entries[i].Contract
+		}
+		return bytes.Compare(entries[i].Address.Bytes(), entries[j].Address.Bytes()) < 0
+	})
+	return entries, nil
+}
+
+// getGenesisAllowLists returns, for each precompile config of the genesis with an allow
+// list, the role of each of its addresses
+func getGenesisAllowLists(genesisBytes []byte) (map[string]map[common.Address]string, error) {
+	var genesis struct {
+		Config map[string]json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(genesisBytes, &genesis); err != nil {
+		return nil, fmt.Errorf("invalid Subnet-EVM genesis: %w", err)
+	}
+	allowLists := map[string]map[common.Address]string{}
+	for name, raw := range genesis.Config {
+		var precompileConfig map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &precompileConfig); err != nil {
+			// not an object, so not a precompile config
+			continue
+		}
+		for _, role := range allowListRoles {
+			raw, ok := precompileConfig[role.field]
+			if !ok {
+				continue
+			}
+			var addresses []common.Address
+			if err := json.Unmarshal(raw, &addresses); err != nil {
+				return nil, fmt.Errorf("invalid %s on genesis %s: %w", role.field, name, err)
+			}
+			for _, address := range addresses {
+				if allowLists[name] == nil {
+					allowLists[name] = map[common.Address]string{}
+				}
+				allowLists[name][address] = role.name
+			}
+		}
+	}
+	return allowLists, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	validatorManagerSDK "github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const addressPlanTestGenesis = `{
+	"config": {
+		"chainId": 8888,
+		"feeConfig": {"gasLimit": 12000000},
+		"contractNativeMinterConfig": {
+			"blockTimestamp": 0,
+			"adminAddresses": ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"],
+			"enabledAddresses": ["0x0FEEDC0DE0000000000000000000000000000000"]
+		}
+	},
+	"alloc": {
+		"8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC": {"balance": "0xd3c21bcecceda1000000"},
+		"0FEEDC0DE0000000000000000000000000000000": {"balance": "0x0", "code": "0x6080"},
+		"1111111111111111111111111111111111111111": {"balance": "0x0"}
+	},
+	"gasLimit": "0xb71b00",
+	"difficulty": "0x0"
+}`
+
+func TestGetAddressPlan(t *testing.T) {
+	require := require.New(t)
+	owner := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	icmKey := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	plan, err := GetAddressPlan([]byte(addressPlanTestGenesis), map[common.Address][]string{
+		owner:  {"Validator Manager owner"},
+		icmKey: {"ICM funding"},
+	})
+	require.NoError(err)
+	require.Len(plan, 4)
+
+	// contracts first
+	proxy := plan[0]
+	require.Equal(common.HexToAddress(validatorManagerSDK.ProxyContractAddress), proxy.Address)
+	require.True(proxy.Contract)
+	require.True(proxy.InGenesis)
+	require.Nil(proxy.Balance)
+	require.Equal([]string{"Validator Manager (transparent proxy)", "contractNativeMinter enabled"}, proxy.Roles)
+
+	require.Equal(owner, plan[1].Address)
+	require.Equal([]string{"Validator Manager owner"}, plan[1].Roles)
+	require.True(plan[1].InGenesis)
+	require.Nil(plan[1].Balance)
+
+	require.Equal(icmKey, plan[2].Address)
+	require.False(plan[2].InGenesis)
+
+	ewoq := plan[3]
+	require.Equal(PrefundedEwoqAddress, ewoq.Address)
+	require.Equal([]string{"ewoq test key", "contractNativeMinter admin"}, ewoq.Roles)
+	expectedBalance, ok := new(big.Int).SetString("1000000000000000000000000", 10)
+	require.True(ok)
+	require.Equal(expectedBalance, ewoq.Balance)
+
+	_, err = GetAddressPlan([]byte("{"), nil)
+	require.Error(err)
+}
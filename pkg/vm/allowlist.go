@@ -4,8 +4,10 @@
 package vm
 
 import (
+	"encoding/csv"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
@@ -17,32 +19,206 @@ import (
 	"golang.org/x/mod/semver"
 )
 
+const (
+	adminRole   = "admin"
+	managerRole = "manager"
+	enabledRole = "enabled"
+)
+
 type AllowList struct {
 	AdminAddresses   []common.Address
 	ManagerAddresses []common.Address
 	EnabledAddresses []common.Address
+	Aliases          map[common.Address]string // local names shown on previews, not part of the genesis
+}
+
+// AllowListLineError is a line of an allow list input that could not be applied
+type AllowListLineError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e AllowListLineError) Error() string {
+	return fmt.Sprintf("line %d %q: %s", e.Line, e.Text, e.Err)
+}
+
+// AddAllowListEntries adds to [allowList] the addresses given by [lines], one per line
+// as address[,role][,alias], eg: 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC,admin,alice
+// An address already aliased can be given by its alias. Lines without role get
+// [defaultRole], being required if it is empty. Empty lines, lines starting with # and a
+// CSV header are skipped. Valid lines are applied, and the invalid ones returned
+func AddAllowListEntries(
+	allowList AllowList,
+	lines []string,
+	defaultRole string,
+	managerRoleEnabled bool,
+) (AllowList, []AllowListLineError) {
+	aliases := map[common.Address]string{}
+	for address, alias := range allowList.Aliases {
+		aliases[address] = alias
+	}
+	allowList.Aliases = aliases
+	lineErrors := []AllowListLineError{}
+	for i, line := range lines {
+		text := strings.TrimSpace(line)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields, err := csv.NewReader(strings.NewReader(text)).Read()
+		if err == nil && i == 0 && strings.EqualFold(strings.TrimSpace(fields[0]), "address") {
+			continue
+		}
+		if err == nil {
+			allowList, err = addAllowListEntry(allowList, utils.Map(fields, strings.TrimSpace), defaultRole, managerRoleEnabled)
+		}
+		if err != nil {
+			lineErrors = append(lineErrors, AllowListLineError{Line: i + 1, Text: text, Err: err})
+		}
+	}
+	return allowList, lineErrors
+}
+
+func addAllowListEntry(
+	allowList AllowList,
+	fields []string,
+	defaultRole string,
+	managerRoleEnabled bool,
+) (AllowList, error) {
+	if len(fields) > 3 {
+		return allowList, fmt.Errorf("expected address[,role][,alias] but got %d fields", len(fields))
+	}
+	address, err := resolveAllowListAddress(allowList, fields[0])
+	if err != nil {
+		return allowList, err
+	}
+	role, alias := "", ""
+	for _, field := range fields[1:] {
+		switch {
+		case field == "":
+		case isAllowListRole(field):
+			if role != "" {
+				return allowList, fmt.Errorf("more than one role given")
+			}
+			role = strings.ToLower(field)
+		default:
+			if alias != "" {
+				return allowList, fmt.Errorf("more than one alias given")
+			}
+			alias = field
+		}
+	}
+	if role == "" {
+		role = defaultRole
+	}
+	if role == "" {
+		return allowList, fmt.Errorf("missing role. Expected one of %s, %s or %s", adminRole, managerRole, enabledRole)
+	}
+	if role == managerRole && !managerRoleEnabled {
+		return allowList, fmt.Errorf("manager role is not supported by this Subnet-EVM version")
+	}
+	if currentRole := getAllowListRole(allowList, address); currentRole != "" {
+		return allowList, fmt.Errorf("%s is already allowed as %s role", address.Hex(), currentRole)
+	}
+	if alias != "" {
+		if err := validateAllowListAlias(allowList, address, alias); err != nil {
+			return allowList, err
+		}
+		allowList.Aliases[address] = alias
+	}
+	switch role {
+	case adminRole:
+		allowList.AdminAddresses = append(allowList.AdminAddresses, address)
+	case managerRole:
+		allowList.ManagerAddresses = append(allowList.ManagerAddresses, address)
+	case enabledRole:
+		allowList.EnabledAddresses = append(allowList.EnabledAddresses, address)
+	}
+	return allowList, nil
+}
+
+// resolveAllowListAddress parses [addressOrAlias] as an hex address, or as the alias of one
+func resolveAllowListAddress(allowList AllowList, addressOrAlias string) (common.Address, error) {
+	if common.IsHexAddress(addressOrAlias) {
+		return common.HexToAddress(addressOrAlias), nil
+	}
+	for address, alias := range allowList.Aliases {
+		if alias == addressOrAlias {
+			return address, nil
+		}
+	}
+	if addressOrAlias == "" {
+		return common.Address{}, fmt.Errorf("invalid empty address")
+	}
+	return common.Address{}, fmt.Errorf("%q is not a valid address nor a known alias", addressOrAlias)
+}
+
+func validateAllowListAlias(allowList AllowList, address common.Address, alias string) error {
+	switch {
+	case strings.TrimSpace(alias) == "":
+		return fmt.Errorf("invalid empty alias")
+	case common.IsHexAddress(alias):
+		return fmt.Errorf("alias %q can't be an address", alias)
+	case isAllowListRole(alias):
+		return fmt.Errorf("alias %q can't be a role name", alias)
+	}
+	for aliasedAddress, existingAlias := range allowList.Aliases {
+		if existingAlias == alias && aliasedAddress != address {
+			return fmt.Errorf("alias %q is already used for %s", alias, aliasedAddress.Hex())
+		}
+	}
+	return nil
+}
+
+func isAllowListRole(s string) bool {
+	return slices.Contains([]string{adminRole, managerRole, enabledRole}, strings.ToLower(s))
+}
+
+// getAllowListRole returns the role of [address] on [allowList], or empty if it has none
+func getAllowListRole(allowList AllowList, address common.Address) string {
+	switch {
+	case utils.Belongs(allowList.AdminAddresses, address):
+		return adminRole
+	case utils.Belongs(allowList.ManagerAddresses, address):
+		return managerRole
+	case utils.Belongs(allowList.EnabledAddresses, address):
+		return enabledRole
+	}
+	return ""
+}
+
+// addressLabel returns the hex of [address], followed by its alias if it has one
+func addressLabel(allowList AllowList, address common.Address) string {
+	if alias, ok := allowList.Aliases[address]; ok {
+		return fmt.Sprintf("%s (%s)", address.Hex(), alias)
+	}
+	return address.Hex()
+}
+
+func isEmptyAllowList(allowList AllowList) bool {
+	return len(allowList.AdminAddresses) == 0 && len(allowList.ManagerAddresses) == 0 && len(allowList.EnabledAddresses) == 0
 }
 
 func preview(allowList AllowList) {
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetRowLine(true)
 	table.SetAutoMergeCellsByColumnIndex([]int{0})
-	addRoleToPreviewTable(table, "Admins", allowList.AdminAddresses)
-	addRoleToPreviewTable(table, "Manager", allowList.ManagerAddresses)
-	addRoleToPreviewTable(table, "Enabled", allowList.EnabledAddresses)
+	addRoleToPreviewTable(table, allowList, "Admins", allowList.AdminAddresses)
+	addRoleToPreviewTable(table, allowList, "Manager", allowList.ManagerAddresses)
+	addRoleToPreviewTable(table, allowList, "Enabled", allowList.EnabledAddresses)
 	table.Render()
 	fmt.Println()
-	if len(allowList.AdminAddresses) == 0 && len(allowList.ManagerAddresses) == 0 && len(allowList.EnabledAddresses) == 0 {
+	if isEmptyAllowList(allowList) {
 		fmt.Println(logging.Red.Wrap("Caution: Allow lists are empty. You will not be able to easily change the precompile settings in the future."))
 		fmt.Println()
 	}
 }
 
-func addRoleToPreviewTable(table *tablewriter.Table, name string, addresses []common.Address) {
+func addRoleToPreviewTable(table *tablewriter.Table, allowList AllowList, name string, addresses []common.Address) {
 	if len(addresses) == 0 {
 		table.Append([]string{name, strings.Repeat(" ", 11)})
 	} else {
-		addressesStr := strings.Join(utils.Map(addresses, func(a common.Address) string { return a.Hex() }), "\n")
+		addressesStr := strings.Join(utils.Map(addresses, func(a common.Address) string { return addressLabel(allowList, a) }), "\n")
 		table.Append([]string{name, addressesStr})
 	}
 }
@@ -57,14 +233,9 @@ func getNewAddresses(
 		return nil, err
 	}
 	for _, address := range addresses {
-		switch {
-		case utils.Belongs(allowList.AdminAddresses, address):
-			fmt.Println(address.Hex() + " is already allowed as admin role")
-		case utils.Belongs(allowList.ManagerAddresses, address):
-			fmt.Println(address.Hex() + " is already allowed as manager role")
-		case utils.Belongs(allowList.EnabledAddresses, address):
-			fmt.Println(address.Hex() + " is already allowed as enabled role")
-		default:
+		if role := getAllowListRole(allowList, address); role != "" {
+			fmt.Println(address.Hex() + " is already allowed as " + role + " role")
+		} else {
 			newAddresses = append(newAddresses, address)
 		}
 	}
@@ -73,6 +244,7 @@ func getNewAddresses(
 
 func removeAddress(
 	app *application.Avalanche,
+	allowList AllowList,
 	addresses []common.Address,
 	kind string,
 ) ([]common.Address, bool, error) {
@@ -83,19 +255,67 @@ func removeAddress(
 	}
 	cancelOption := "Cancel"
 	prompt := "Select the address you want to remove"
-	options := utils.Map(addresses, func(a common.Address) string { return a.Hex() })
+	options := utils.Map(addresses, func(a common.Address) string { return addressLabel(allowList, a) })
 	options = append(options, cancelOption)
 	opt, err := app.Prompt.CaptureList(prompt, options)
 	if err != nil {
 		return addresses, false, err
 	}
 	if opt != cancelOption {
-		addresses = utils.RemoveFromSlice(addresses, common.HexToAddress(opt))
+		addresses = utils.RemoveFromSlice(addresses, addresses[slices.Index(options, opt)])
 		return addresses, false, nil
 	}
 	return addresses, true, nil
 }
 
+// addAllowListLines adds the entries of [lines] to [allowList], showing the validation
+// errors of each invalid line
+func addAllowListLines(
+	allowList AllowList,
+	lines []string,
+	defaultRole string,
+	managerRoleEnabled bool,
+) AllowList {
+	previousSize := allowListSize(allowList)
+	allowList, lineErrors := AddAllowListEntries(allowList, lines, defaultRole, managerRoleEnabled)
+	for _, lineErr := range lineErrors {
+		fmt.Println(logging.Red.Wrap(lineErr.Error()))
+	}
+	fmt.Printf("%d addresses added, %d lines with errors\n", allowListSize(allowList)-previousSize, len(lineErrors))
+	fmt.Println()
+	return allowList
+}
+
+func allowListSize(allowList AllowList) int {
+	return len(allowList.AdminAddresses) + len(allowList.ManagerAddresses) + len(allowList.EnabledAddresses)
+}
+
+// setAlias asks for an address of [allowList] and the local alias to show for it
+func setAlias(app *application.Avalanche, allowList AllowList) (AllowList, error) {
+	cancelOption := "Cancel"
+	addresses := append(append(append([]common.Address{}, allowList.AdminAddresses...), allowList.ManagerAddresses...), allowList.EnabledAddresses...)
+	options := utils.Map(addresses, func(a common.Address) string { return addressLabel(allowList, a) })
+	options = append(options, cancelOption)
+	opt, err := app.Prompt.CaptureList("Select the address to set an alias for", options)
+	if err != nil || opt == cancelOption {
+		return allowList, err
+	}
+	address := addresses[slices.Index(options, opt)]
+	alias, err := app.Prompt.CaptureValidatedString("Enter the alias", func(s string) error {
+		return validateAllowListAlias(allowList, address, s)
+	})
+	if err != nil {
+		return allowList, err
+	}
+	aliases := map[common.Address]string{}
+	for aliasedAddress, existingAlias := range allowList.Aliases {
+		aliases[aliasedAddress] = existingAlias
+	}
+	aliases[address] = strings.TrimSpace(alias)
+	allowList.Aliases = aliases
+	return allowList, nil
+}
+
 func GenerateAllowList(
 	app *application.Avalanche,
 	allowList AllowList,
@@ -111,6 +331,9 @@ func GenerateAllowList(
 	prompt := fmt.Sprintf(promptTemplate, action)
 
 	addOption := "Add an address for a role to the allow list"
+	pasteOption := "Paste many addresses for a role at once"
+	importOption := "Import addresses from a CSV file"
+	aliasOption := "Set an alias for an address"
 	removeOption := "Remove address from the allow list"
 	previewOption := "Preview Allow List"
 	confirmOption := "Confirm Allow List"
@@ -121,15 +344,16 @@ func GenerateAllowList(
 	enabledOption := "Enabled"
 	explainOption := "Explain the difference"
 
-	if len(allowList.AdminAddresses) != 0 || len(allowList.ManagerAddresses) != 0 || len(allowList.EnabledAddresses) != 0 {
+	if !isEmptyAllowList(allowList) {
 		fmt.Println()
 		fmt.Printf(logging.Bold.Wrap("Addresses automatically allowed to %s\n"), action)
 		preview(allowList)
 	}
 
 	for {
-		options := []string{addOption, removeOption, previewOption, confirmOption, cancelOption}
-		if len(allowList.AdminAddresses) == 0 && len(allowList.ManagerAddresses) == 0 && len(allowList.EnabledAddresses) == 0 {
+		options := []string{addOption, pasteOption, importOption, aliasOption, removeOption, previewOption, confirmOption, cancelOption}
+		if isEmptyAllowList(allowList) {
+			options = utils.RemoveFromSlice(options, aliasOption)
 			options = utils.RemoveFromSlice(options, removeOption)
 		}
 		option, err := app.Prompt.CaptureList(prompt, options)
@@ -175,6 +399,38 @@ func GenerateAllowList(
 				}
 				break
 			}
+		case pasteOption:
+			options := []string{adminOption, managerOption, enabledOption, cancelOption}
+			if !managerRoleEnabled {
+				options = []string{adminOption, enabledOption, cancelOption}
+			}
+			roleOption, err := app.Prompt.CaptureList("What role should the addresses have?", options)
+			if err != nil {
+				return AllowList{}, false, err
+			}
+			if roleOption == cancelOption {
+				continue
+			}
+			lines, err := utils.ReadLines("Paste the addresses, one per line as address[,alias]. End with an empty line:\n")
+			if err != nil {
+				return AllowList{}, false, err
+			}
+			allowList = addAllowListLines(allowList, lines, strings.ToLower(roleOption), managerRoleEnabled)
+		case importOption:
+			path, err := app.Prompt.CaptureExistingFilepath("Enter the path of the CSV file, with one address[,role][,alias] per line")
+			if err != nil {
+				return AllowList{}, false, err
+			}
+			content, err := os.ReadFile(utils.ExpandHome(path))
+			if err != nil {
+				return AllowList{}, false, err
+			}
+			allowList = addAllowListLines(allowList, strings.Split(string(content), "\n"), "", managerRoleEnabled)
+		case aliasOption:
+			allowList, err = setAlias(app, allowList)
+			if err != nil {
+				return AllowList{}, false, err
+			}
 		case removeOption:
 			keepAsking := true
 			for keepAsking {
@@ -196,17 +452,17 @@ func GenerateAllowList(
 				}
 				switch roleOption {
 				case adminOption:
-					allowList.AdminAddresses, keepAsking, err = removeAddress(app, allowList.AdminAddresses, "admin")
+					allowList.AdminAddresses, keepAsking, err = removeAddress(app, allowList, allowList.AdminAddresses, "admin")
 					if err != nil {
 						return AllowList{}, false, err
 					}
 				case managerOption:
-					allowList.ManagerAddresses, keepAsking, err = removeAddress(app, allowList.ManagerAddresses, "manager")
+					allowList.ManagerAddresses, keepAsking, err = removeAddress(app, allowList, allowList.ManagerAddresses, "manager")
 					if err != nil {
 						return AllowList{}, false, err
 					}
 				case enabledOption:
-					allowList.EnabledAddresses, keepAsking, err = removeAddress(app, allowList.EnabledAddresses, "enabled")
+					allowList.EnabledAddresses, keepAsking, err = removeAddress(app, allowList, allowList.EnabledAddresses, "enabled")
 					if err != nil {
 						return AllowList{}, false, err
 					}
@@ -0,0 +1,51 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddAllowListEntries(t *testing.T) {
+	require := require.New(t)
+	alice := common.HexToAddress("0x00000000000000000000000000000000000A11CE")
+	bob := common.HexToAddress("0x0000000000000000000000000000000000000B0B")
+	carol := common.HexToAddress("0x00000000000000000000000000000000000CA201")
+	dave := common.HexToAddress("0x0000000000000000000000000000000000000DA7")
+
+	allowList := AllowList{AdminAddresses: []common.Address{alice}}
+	allowList, lineErrors := AddAllowListEntries(allowList, []string{
+		"address,role,alias",
+		bob.Hex() + ",manager,bob",
+		"",
+		"# enabled ones",
+		carol.Hex() + ", carol , Enabled",
+		"not-an-address,enabled",
+		alice.Hex() + ",enabled",
+		dave.Hex(),
+		dave.Hex() + ",admin,bob",
+	}, "", true)
+	require.Equal([]common.Address{alice}, allowList.AdminAddresses)
+	require.Equal([]common.Address{bob}, allowList.ManagerAddresses)
+	require.Equal([]common.Address{carol}, allowList.EnabledAddresses)
+	require.Equal(map[common.Address]string{bob: "bob", carol: "carol"}, allowList.Aliases)
+	require.Len(lineErrors, 4)
+	require.Equal(6, lineErrors[0].Line)
+	require.ErrorContains(lineErrors[0].Err, "not a valid address nor a known alias")
+	require.ErrorContains(lineErrors[1].Err, "already allowed as admin role")
+	require.ErrorContains(lineErrors[2].Err, "missing role")
+	require.ErrorContains(lineErrors[3].Err, "alias \"bob\" is already used")
+
+	// aliases can be used instead of addresses, and lines get the default role
+	allowList, lineErrors = AddAllowListEntries(AllowList{Aliases: map[common.Address]string{dave: "dave"}}, []string{
+		"dave",
+		bob.Hex() + ",manager",
+	}, enabledRole, false)
+	require.Equal([]common.Address{dave}, allowList.EnabledAddresses)
+	require.Len(lineErrors, 1)
+	require.Equal(2, lineErrors[0].Line)
+	require.ErrorContains(lineErrors[0].Err, "manager role is not supported")
+}
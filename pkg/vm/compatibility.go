@@ -135,31 +135,41 @@ func dumpProcessOutput(stdoutPipe io.ReadCloser, stderrPipe io.ReadCloser) error
 }
 
 func GetRPCProtocolVersion(app *application.Avalanche, vmType models.VMType, vmVersion string) (int, error) {
+	rpcVersions, err := GetRPCProtocolVersions(app, vmType)
+	if err != nil {
+		return 0, err
+	}
+	version, ok := rpcVersions[vmVersion]
+	if !ok {
+		return 0, errors.New("no RPC version found")
+	}
+
+	return version, nil
+}
+
+// GetRPCProtocolVersions returns the RPC protocol version of every release of [vmType],
+// indexed by release version
+func GetRPCProtocolVersions(app *application.Avalanche, vmType models.VMType) (map[string]int, error) {
 	var url string
 
 	switch vmType {
 	case models.SubnetEvm:
 		url = constants.SubnetEVMRPCCompatibilityURL
 	default:
-		return 0, errors.New("unknown VM type")
+		return nil, errors.New("unknown VM type")
 	}
 
 	compatibilityBytes, err := app.Downloader.Download(url)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	var parsedCompat models.VMCompatibility
 	if err = json.Unmarshal(compatibilityBytes, &parsedCompat); err != nil {
-		return 0, err
-	}
-
-	version, ok := parsedCompat.RPCChainVMProtocolVersion[vmVersion]
-	if !ok {
-		return 0, errors.New("no RPC version found")
+		return nil, err
 	}
 
-	return version, nil
+	return parsedCompat.RPCChainVMProtocolVersion, nil
 }
 
 // GetAvalancheGoVersionsForRPC returns list of compatible avalanche go versions for a specified rpcVersion
@@ -101,15 +101,11 @@ func CreateEVMGenesis(
 
 	// Add the ICM deployer to the initial token allocation if necessary.
 	if params.UseICM || params.UseExternalGasToken {
-		balance := icmBalance
-		if params.UseExternalGasToken {
-			balance = externalGasTokenBalance
-		}
 		if params.initialTokenAllocation == nil {
 			params.initialTokenAllocation = core.GenesisAlloc{}
 		}
 		params.initialTokenAllocation[common.HexToAddress(icmInfo.FundedAddress)] = core.GenesisAccount{
-			Balance: balance,
+			Balance: icmFundingBalance(params.UseExternalGasToken),
 		}
 		if !params.DisableICMOnGenesis {
 			icmgenesis.AddICMMessengerContractToAllocations(params.initialTokenAllocation)
@@ -129,10 +125,18 @@ func CreateEVMGenesis(
 		validatormanager.AddPoAValidatorManagerContractToAllocations(params.initialTokenAllocation)
 		validatormanager.AddTransparentProxyContractToAllocations(params.initialTokenAllocation, proxyOwner)
 	} else if params.UsePoSValidatorManager {
-		validatormanager.AddPoSValidatorManagerContractToAllocations(params.initialTokenAllocation)
+		if params.UseERC20StakingManager {
+			validatormanager.AddERC20PoSValidatorManagerContractToAllocations(params.initialTokenAllocation)
+		} else {
+			validatormanager.AddPoSValidatorManagerContractToAllocations(params.initialTokenAllocation)
+			params.enableNativeMinterPrecompile = true
+		}
 		validatormanager.AddTransparentProxyContractToAllocations(params.initialTokenAllocation, proxyOwner)
 		validatormanager.AddRewardCalculatorToAllocations(params.initialTokenAllocation, rewardBasisPoints)
-		params.enableNativeMinterPrecompile = true
+	}
+
+	if err := CheckSupplyCap(params.initialTokenAllocation, nil, MaxSupplyFromTokens(params.MaxSupply, params.tokenDecimals()), params.tokenDecimals()); err != nil {
+		return nil, err
 	}
 
 	if params.UseExternalGasToken {
@@ -196,3 +200,39 @@ func someAllowedHasBalance(allowList AllowList, allocations core.GenesisAlloc) b
 	}
 	return false
 }
+
+// CreateScratchEVMGenesis creates the genesis of a scratch Subnet-EVM chain, using the
+// test defaults, and prefunding [fundedAddresses] in addition to the ewoq key
+func CreateScratchEVMGenesis(
+	app *application.Avalanche,
+	version string,
+	chainName string,
+	chainID uint64,
+	tokenSymbol string,
+	fundedAddresses []common.Address,
+) ([]byte, error) {
+	useICM := false
+	params, _, err := PromptSubnetEVMGenesisParams(
+		app,
+		&models.Sidecar{},
+		version,
+		chainID,
+		tokenSymbol,
+		chainName,
+		&useICM,
+		TestDefaults,
+		true,
+		false,
+		0,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range fundedAddresses {
+		params.initialTokenAllocation[address] = core.GenesisAccount{
+			Balance: TokensToBaseUnits(defaultEVMAirdropTokens, params.tokenDecimals()),
+		}
+	}
+	return CreateEVMGenesis(params, nil, false, "", 0)
+}
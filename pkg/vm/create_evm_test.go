@@ -3,6 +3,7 @@
 package vm
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
 
@@ -93,3 +94,18 @@ func Test_ensureAdminsFunded(t *testing.T) {
 		})
 	}
 }
+
+func Test_CreateScratchEVMGenesis(t *testing.T) {
+	require := require.New(t)
+	addrs, err := testutils.GenerateEthAddrs(2)
+	require.NoError(err)
+	genesisBytes, err := CreateScratchEVMGenesis(nil, "v0.7.0", "scratch", 31337, "TEST", addrs)
+	require.NoError(err)
+	var genesis core.Genesis
+	require.NoError(json.Unmarshal(genesisBytes, &genesis))
+	require.Equal(big.NewInt(31337), genesis.Config.ChainID)
+	for _, addr := range append(addrs, PrefundedEwoqAddress) {
+		require.Contains(genesis.Alloc, addr)
+		require.Positive(genesis.Alloc[addr].Balance.Sign())
+	}
+}
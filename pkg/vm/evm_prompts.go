@@ -48,6 +48,9 @@ const (
 	addAddressAllocationOption     = "Add an address to the initial token allocation"
 	changeAddressAllocationOption  = "Edit the amount of an address in the initial token allocation"
 	removeAddressAllocationOption  = "Remove an address from the initial token allocation"
+	addVestingScheduleOption       = "Add a vesting schedule to the initial token allocation"
+	removeVestingScheduleOption    = "Remove a vesting schedule from the initial token allocation"
+	setMaxSupplyOption             = "Set the maximum total supply of the native token"
 	previewAddressAllocationOption = "Preview the initial token allocation"
 	confirmAddressAllocationOption = "Confirm and finalize the initial token allocation"
 )
@@ -86,7 +89,19 @@ type SubnetEVMGenesisParams struct {
 	enableWarpPrecompile                bool
 	UsePoAValidatorManager              bool
 	UsePoSValidatorManager              bool
+	UseERC20StakingManager              bool
 	DisableICMOnGenesis                 bool
+	// declared maximum total supply of the native token, in token units. 0 means no cap
+	MaxSupply uint64
+	// decimals of the native token, used to convert token units. 0 means the default of 18
+	TokenDecimals uint8
+}
+
+func (params SubnetEVMGenesisParams) tokenDecimals() uint8 {
+	if params.TokenDecimals == 0 {
+		return constants.DefaultTokenDecimals
+	}
+	return params.TokenDecimals
 }
 
 func PromptTokenSymbol(
@@ -154,7 +169,8 @@ func PromptVMType(
 // as such, is returned separately from the genesis params
 //
 // prompts the user for chainID, tokenSymbol, and useICM, unless
-// provided in call args
+// provided in call args. If [airdrop] is given, it is used as the initial
+// token allocation instead of the default or prompted one
 func PromptSubnetEVMGenesisParams(
 	app *application.Avalanche,
 	sc *models.Sidecar,
@@ -166,28 +182,37 @@ func PromptSubnetEVMGenesisParams(
 	defaultsKind DefaultsKind,
 	useWarp bool,
 	useExternalGasToken bool,
+	maxSupply uint64,
+	airdrop core.GenesisAlloc,
 ) (SubnetEVMGenesisParams, string, error) {
 	var (
 		err    error
 		params SubnetEVMGenesisParams
 	)
 	params.initialTokenAllocation = core.GenesisAlloc{}
+	params.MaxSupply = maxSupply
+	params.TokenDecimals = sc.GetTokenDecimals()
 
 	if sc.PoA() {
 		params.UsePoAValidatorManager = true
 		params.initialTokenAllocation[common.HexToAddress(sc.ValidatorManagerOwner)] = core.GenesisAccount{
-			Balance: defaultPoAOwnerBalance,
+			Balance: TokensToBaseUnits(defaultPoAOwnerTokens, params.tokenDecimals()),
 		}
 	}
 
 	if sc.PoS() {
 		params.UsePoSValidatorManager = true
-
-		params.enableNativeMinterPrecompile = true
-		params.nativeMinterPrecompileAllowList.EnabledAddresses = append(
-			params.nativeMinterPrecompileAllowList.EnabledAddresses,
-			common.HexToAddress(validatorManagerSDK.ProxyContractAddress),
-		)
+		params.UseERC20StakingManager = sc.ERC20Staking()
+
+		// native token staking managers mint the staking rewards. ERC20 token staking
+		// managers mint them on the staking token instead
+		if !params.UseERC20StakingManager {
+			params.enableNativeMinterPrecompile = true
+			params.nativeMinterPrecompileAllowList.EnabledAddresses = append(
+				params.nativeMinterPrecompileAllowList.EnabledAddresses,
+				common.HexToAddress(validatorManagerSDK.ProxyContractAddress),
+			)
+		}
 		params.enableRewardManagerPrecompile = true
 	}
 
@@ -208,7 +233,7 @@ func PromptSubnetEVMGenesisParams(
 
 	// Native Gas Details
 	if !params.UseExternalGasToken {
-		params, tokenSymbol, err = promptNativeGasToken(app, version, tokenSymbol, blockchainName, defaultsKind, params)
+		params, tokenSymbol, err = promptNativeGasToken(app, version, tokenSymbol, blockchainName, defaultsKind, useICM, airdrop, params)
 		if err != nil {
 			return SubnetEVMGenesisParams{}, "", err
 		}
@@ -226,6 +251,16 @@ func PromptSubnetEVMGenesisParams(
 		return SubnetEVMGenesisParams{}, "", err
 	}
 
+	// Supply cap, including the ICM funding added on genesis creation
+	if err := CheckSupplyCap(
+		params.initialTokenAllocation,
+		params.reservedSupply(),
+		MaxSupplyFromTokens(params.MaxSupply, params.tokenDecimals()),
+		params.tokenDecimals(),
+	); err != nil {
+		return SubnetEVMGenesisParams{}, "", err
+	}
+
 	// Warp
 	params.enableWarpPrecompile = useWarp
 	if (params.UseICM || params.UseExternalGasToken) && !params.enableWarpPrecompile {
@@ -329,7 +364,16 @@ func PromptDefaults(
 	return defaultsKind, nil
 }
 
-func displayAllocations(alloc core.GenesisAlloc) {
+// reservedSupply is the supply allocated automatically on genesis creation, not yet
+// present on the initial token allocation
+func (params SubnetEVMGenesisParams) reservedSupply() *big.Int {
+	if params.UseICM || params.UseExternalGasToken {
+		return icmFundingBalance(params.UseExternalGasToken)
+	}
+	return nil
+}
+
+func displayAllocations(alloc core.GenesisAlloc, decimals uint8) {
 	header := []string{"Address", "Balance"}
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetHeader(header)
@@ -337,63 +381,141 @@ func displayAllocations(alloc core.GenesisAlloc) {
 	table.SetAutoMergeCells(true)
 	table.SetRowLine(true)
 	for address, account := range alloc {
-		table.Append([]string{address.Hex(), utils.FormatAmount(account.Balance, 18)})
+		table.Append([]string{address.Hex(), utils.FormatAmount(account.Balance, decimals)})
+	}
+	table.Render()
+}
+
+func displayVestingSchedules(alloc core.GenesisAlloc, tokenSymbol string, decimals uint8) {
+	schedules := GetVestingSchedules(alloc)
+	if len(schedules) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println(logging.Bold.Wrap(fmt.Sprintf("Vesting schedules (locked at %s)", VestingContractAddress)))
+	header := []string{"Beneficiary", fmt.Sprintf("Amount (%s)", tokenSymbol), "Start", "Cliff", "End"}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetRowLine(true)
+	for _, schedule := range schedules {
+		table.Append([]string{
+			schedule.Beneficiary.Hex(),
+			utils.FormatAmount(schedule.Amount, decimals),
+			formatVestingTime(schedule.Start),
+			formatVestingTime(schedule.Cliff),
+			formatVestingTime(schedule.End()),
+		})
 	}
 	table.Render()
 }
 
-func addNewKeyAllocation(allocations core.GenesisAlloc, app *application.Avalanche, subnetName string) error {
+// promptVestingSchedule asks for a beneficiary, an amount and the vesting timing.
+// Amounts vest linearly from start to end, with nothing released before the cliff
+func promptVestingSchedule(app *application.Avalanche, tokenSymbol string, decimals uint8) (VestingSchedule, error) {
+	beneficiary, err := app.Prompt.CaptureAddress("Beneficiary address")
+	if err != nil {
+		return VestingSchedule{}, err
+	}
+	amount, err := app.Prompt.CaptureUint64(fmt.Sprintf("Amount to vest (in %s units)", tokenSymbol))
+	if err != nil {
+		return VestingSchedule{}, err
+	}
+	start, err := app.Prompt.CaptureDate("Vesting start (UTC)")
+	if err != nil {
+		return VestingSchedule{}, err
+	}
+	cliff, err := app.Prompt.CaptureDuration("Cliff duration from vesting start (nothing can be released before the cliff)")
+	if err != nil {
+		return VestingSchedule{}, err
+	}
+	duration, err := app.Prompt.CaptureDuration("Total vesting duration from vesting start")
+	if err != nil {
+		return VestingSchedule{}, err
+	}
+	if cliff < 0 || duration < 0 {
+		return VestingSchedule{}, fmt.Errorf("vesting durations must not be negative")
+	}
+	startTimestamp := uint64(start.Unix())
+	return VestingSchedule{
+		Beneficiary: beneficiary,
+		Amount:      TokensToBaseUnits(amount, decimals),
+		Start:       startTimestamp,
+		Cliff:       startTimestamp + uint64(cliff.Seconds()),
+		Duration:    uint64(duration.Seconds()),
+	}, nil
+}
+
+func addNewKeyAllocation(allocations core.GenesisAlloc, app *application.Avalanche, subnetName string, tokenSymbol string, decimals uint8) error {
 	keyName := utils.GetDefaultBlockchainAirdropKeyName(subnetName)
-	k, err := app.GetKey(keyName, models.NewLocalNetwork(), true)
+	k, err := app.GetKey(keyName, app.GetLocalNetwork(), true)
 	if err != nil {
 		return err
 	}
-	ux.Logger.PrintToUser("prefunding address %s with balance %s", k.C(), defaultEVMAirdropAmount)
+	balance := TokensToBaseUnits(defaultEVMAirdropTokens, decimals)
+	printPrefunding(common.HexToAddress(k.C()), balance, tokenSymbol, decimals)
 	allocations[common.HexToAddress(k.C())] = core.GenesisAccount{
-		Balance: defaultEVMAirdropAmount,
+		Balance: balance,
 	}
 	return nil
 }
 
-func addEwoqAllocation(allocations core.GenesisAlloc) {
+func addEwoqAllocation(allocations core.GenesisAlloc, decimals uint8) {
 	allocations[PrefundedEwoqAddress] = core.GenesisAccount{
-		Balance: defaultEVMAirdropAmount,
+		Balance: TokensToBaseUnits(defaultEVMAirdropTokens, decimals),
 	}
 }
 
+func printPrefunding(address common.Address, balance *big.Int, tokenSymbol string, decimals uint8) {
+	ux.Logger.PrintToUser("prefunding address %s with balance %s %s", address.Hex(), utils.FormatAmount(balance, decimals), tokenSymbol)
+}
+
+// prompts for the initial token allocation. If a maximum supply is declared, the custom
+// allocation editor shows the remaining headroom and refuses to finalize allocations
+// exceeding it, taking into account the [reserved] supply to be automatically allocated later.
+// Returns the maximum supply, as it can be changed on the editor
 func getNativeGasTokenAllocationConfig(
 	allocations core.GenesisAlloc,
 	app *application.Avalanche,
 	subnetName string,
 	tokenSymbol string,
-) error {
+	decimals uint8,
+	maxSupply uint64,
+	reserved *big.Int,
+) (uint64, error) {
 	// Get the type of initial token allocation from the user prompt.
 	allocOption, err := app.Prompt.CaptureList(
 		"How should the initial token allocation be structured?",
 		[]string{allocateToNewKeyOption, allocateToEwoqOption, customAllocationOption},
 	)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// If the user chooses to allocate to a new key, generate a new key and allocate the default amount to it.
 	if allocOption == allocateToNewKeyOption {
-		return addNewKeyAllocation(allocations, app, subnetName)
+		if err := addNewKeyAllocation(allocations, app, subnetName, tokenSymbol, decimals); err != nil {
+			return 0, err
+		}
+		return maxSupply, CheckSupplyCap(allocations, reserved, MaxSupplyFromTokens(maxSupply, decimals), decimals)
 	}
 
 	if allocOption == allocateToEwoqOption {
-		ux.Logger.PrintToUser("prefunding address %s with balance %s", PrefundedEwoqAddress, defaultEVMAirdropAmount)
-		addEwoqAllocation(allocations)
-		return nil
+		addEwoqAllocation(allocations, decimals)
+		printPrefunding(PrefundedEwoqAddress, allocations[PrefundedEwoqAddress].Balance, tokenSymbol, decimals)
+		return maxSupply, CheckSupplyCap(allocations, reserved, MaxSupplyFromTokens(maxSupply, decimals), decimals)
 	}
 
 	if allocOption == customAllocationOption {
 		if len(allocations) != 0 {
 			fmt.Println()
 			fmt.Println(logging.Bold.Wrap("Addresses automatically allocated"))
-			displayAllocations(allocations)
+			displayAllocations(allocations, decimals)
+		}
+		if reserved != nil {
+			ux.Logger.PrintToUser("%s %s will be automatically allocated to fund ICM operations", utils.FormatAmount(reserved, decimals), tokenSymbol)
 		}
 		for {
+			displaySupplyHeadroom(allocations, reserved, MaxSupplyFromTokens(maxSupply, decimals), tokenSymbol, decimals)
 			// Prompt for the action the user wants to take on the allocation list.
 			action, err := app.Prompt.CaptureList(
 				"How would you like to modify the initial token allocation?",
@@ -401,19 +523,22 @@ func getNativeGasTokenAllocationConfig(
 					addAddressAllocationOption,
 					changeAddressAllocationOption,
 					removeAddressAllocationOption,
+					addVestingScheduleOption,
+					removeVestingScheduleOption,
+					setMaxSupplyOption,
 					previewAddressAllocationOption,
 					confirmAddressAllocationOption,
 				},
 			)
 			if err != nil {
-				return err
+				return 0, err
 			}
 
 			switch action {
 			case addAddressAllocationOption:
 				address, err := app.Prompt.CaptureAddress("Address to allocate to")
 				if err != nil {
-					return err
+					return 0, err
 				}
 
 				// Check if the address already has an allocation entry.
@@ -424,16 +549,16 @@ func getNativeGasTokenAllocationConfig(
 
 				balance, err := app.Prompt.CaptureUint64(fmt.Sprintf("Amount to allocate (in %s units)", tokenSymbol))
 				if err != nil {
-					return err
+					return 0, err
 				}
 
 				allocations[address] = core.GenesisAccount{
-					Balance: new(big.Int).Mul(new(big.Int).SetUint64(balance), OneAvax),
+					Balance: TokensToBaseUnits(balance, decimals),
 				}
 			case changeAddressAllocationOption:
 				address, err := app.Prompt.CaptureAddress("Address to update the allocation of")
 				if err != nil {
-					return err
+					return 0, err
 				}
 
 				// Check the address has an existing allocation entry.
@@ -444,15 +569,15 @@ func getNativeGasTokenAllocationConfig(
 
 				balance, err := app.Prompt.CaptureUint64(fmt.Sprintf("Updated amount to allocate (in %s units)", tokenSymbol))
 				if err != nil {
-					return err
+					return 0, err
 				}
 				allocations[address] = core.GenesisAccount{
-					Balance: new(big.Int).Mul(new(big.Int).SetUint64(balance), OneAvax),
+					Balance: TokensToBaseUnits(balance, decimals),
 				}
 			case removeAddressAllocationOption:
 				address, err := app.Prompt.CaptureAddress("Address to remove from the allocation list")
 				if err != nil {
-					return err
+					return 0, err
 				}
 
 				// Check the address has an existing allocation entry.
@@ -462,23 +587,52 @@ func getNativeGasTokenAllocationConfig(
 				}
 
 				delete(allocations, address)
+			case addVestingScheduleOption:
+				schedule, err := promptVestingSchedule(app, tokenSymbol, decimals)
+				if err != nil {
+					return 0, err
+				}
+				if err := AddVestingScheduleToAllocations(allocations, schedule); err != nil {
+					ux.Logger.PrintToUser("%s", err)
+					continue
+				}
+			case removeVestingScheduleOption:
+				address, err := app.Prompt.CaptureAddress("Beneficiary address of the vesting schedule to remove")
+				if err != nil {
+					return 0, err
+				}
+				if err := RemoveVestingScheduleFromAllocations(allocations, address); err != nil {
+					ux.Logger.PrintToUser("%s", err)
+					continue
+				}
+			case setMaxSupplyOption:
+				maxSupply, err = app.Prompt.CaptureUint64(fmt.Sprintf("Maximum total supply (in %s units, 0 for no cap)", tokenSymbol))
+				if err != nil {
+					return 0, err
+				}
 			case previewAddressAllocationOption:
-				displayAllocations(allocations)
+				displayAllocations(allocations, decimals)
+				displayVestingSchedules(allocations, tokenSymbol, decimals)
 			case confirmAddressAllocationOption:
-				displayAllocations(allocations)
+				displayAllocations(allocations, decimals)
+				displayVestingSchedules(allocations, tokenSymbol, decimals)
+				if err := CheckSupplyCap(allocations, reserved, MaxSupplyFromTokens(maxSupply, decimals), decimals); err != nil {
+					ux.Logger.RedXToUser("%s. Reduce the allocations or raise the maximum supply to continue", err)
+					continue
+				}
 				confirm, err := app.Prompt.CaptureYesNo("Are you sure you want to finalize this allocation list?")
 				if err != nil {
-					return err
+					return 0, err
 				}
 				if confirm {
-					return nil
+					return maxSupply, nil
 				}
 			default:
-				return fmt.Errorf("invalid allocation modification option")
+				return 0, fmt.Errorf("invalid allocation modification option")
 			}
 		}
 	}
-	return fmt.Errorf("invalid allocation option")
+	return 0, fmt.Errorf("invalid allocation option")
 }
 
 func getNativeMinterPrecompileConfig(
@@ -533,6 +687,8 @@ func promptNativeGasToken(
 	tokenSymbol string,
 	blockchainName string,
 	defaultsKind DefaultsKind,
+	useICM *bool,
+	airdrop core.GenesisAlloc,
 	params SubnetEVMGenesisParams,
 ) (SubnetEVMGenesisParams, string, error) {
 	var err error
@@ -541,20 +697,46 @@ func promptNativeGasToken(
 		return SubnetEVMGenesisParams{}, "", err
 	}
 
+	if len(airdrop) > 0 {
+		for address, account := range airdrop {
+			printPrefunding(address, account.Balance, tokenSymbol, params.tokenDecimals())
+			params.initialTokenAllocation[address] = account
+		}
+		if defaultsKind != NoDefaults {
+			return params, tokenSymbol, nil
+		}
+	}
+
 	if defaultsKind == TestDefaults {
-		ux.Logger.PrintToUser("prefunding address %s with balance %s", PrefundedEwoqAddress, defaultEVMAirdropAmount)
-		addEwoqAllocation(params.initialTokenAllocation)
+		addEwoqAllocation(params.initialTokenAllocation, params.tokenDecimals())
+		printPrefunding(PrefundedEwoqAddress, params.initialTokenAllocation[PrefundedEwoqAddress].Balance, tokenSymbol, params.tokenDecimals())
 		return params, tokenSymbol, nil
 	}
 
 	if defaultsKind == ProductionDefaults {
-		err = addNewKeyAllocation(params.initialTokenAllocation, app, blockchainName)
+		err = addNewKeyAllocation(params.initialTokenAllocation, app, blockchainName, tokenSymbol, params.tokenDecimals())
 		return params, tokenSymbol, err
 	}
 
 	// No defaults case. Prompt for initial token allocation and native minter precompile options.
-	if err := getNativeGasTokenAllocationConfig(params.initialTokenAllocation, app, blockchainName, tokenSymbol); err != nil {
-		return SubnetEVMGenesisParams{}, "", err
+	// ICM funding is reserved if ICM was already requested
+	if len(airdrop) == 0 {
+		var reserved *big.Int
+		if useICM != nil && *useICM {
+			reserved = icmFundingBalance(false)
+		}
+		params.MaxSupply, err = getNativeGasTokenAllocationConfig(
+			params.initialTokenAllocation,
+			app,
+			blockchainName,
+			tokenSymbol,
+			params.tokenDecimals(),
+			params.MaxSupply,
+			reserved,
+		)
+		if err != nil {
+			return SubnetEVMGenesisParams{}, "", err
+		}
 	}
 
 	allowList, nativeMinterEnabled, err := getNativeMinterPrecompileConfig(
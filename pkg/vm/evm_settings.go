@@ -30,7 +30,11 @@ var (
 	PrefundedEwoqAddress = common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
 	PrefundedEwoqPrivate = "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
 
-	OneAvax                 = new(big.Int).SetUint64(1000000000000000000)
-	defaultEVMAirdropAmount = new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil) // 10^24
-	defaultPoAOwnerBalance  = new(big.Int).Mul(OneAvax, big.NewInt(10))             // 10 Native Tokens
+	OneAvax = new(big.Int).SetUint64(1000000000000000000)
+)
+
+const (
+	// in token units, scaled by the token decimals
+	defaultEVMAirdropTokens = 1_000_000
+	defaultPoAOwnerTokens   = 10
 )
@@ -0,0 +1,334 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ava-labs/subnet-evm/commontype"
+)
+
+const (
+	// rolling window, in seconds, used by the dynamic fee algorithm to compare
+	// gas usage against the target gas
+	feeWindowSeconds = 10
+	// recommended headroom of the target gas over the peak window gas usage, in percent
+	targetGasHeadroom = 25
+	// base fee relative change between two blocks above which fees are considered volatile
+	maxBaseFeeChange = 0.125
+	// target gas and denominator recommendations are rounded to these values
+	targetGasRounding        = 1_000_000
+	minBaseFeeDenominator    = 8
+	maxBaseFeeDenominator    = 1_024
+	fullBlockUtilizationRate = 0.9
+)
+
+// BlockFeeSample holds the fee related data of a block
+type BlockFeeSample struct {
+	Number  uint64
+	Time    uint64
+	GasUsed uint64
+	BaseFee *big.Int
+}
+
+// FeeUsage summarizes the utilization of a chain on a set of sampled blocks
+type FeeUsage struct {
+	NumBlocks int
+	// seconds between the first and last sampled blocks
+	Period uint64
+	// average seconds between blocks
+	AvgBlockTime float64
+	// average ratio of block gas used over gas limit
+	AvgBlockUtilization float64
+	// blocks using almost all the gas limit
+	FullBlocks int
+	// gas used on the rolling window of the dynamic fee algorithm
+	AvgWindowGas  uint64
+	PeakWindowGas uint64
+	BaseFees      BaseFeeStats
+	// largest relative change of the base fee between consecutive blocks
+	MaxBaseFeeChange float64
+	// ratio of blocks with base fee above twice the min base fee
+	HighBaseFeeRatio float64
+}
+
+// BaseFeeStats summarizes a series of base fees
+type BaseFeeStats struct {
+	Min *big.Int
+	Avg *big.Int
+	Max *big.Int
+}
+
+// FeeRecommendation is a fee config suggested for the observed usage, together
+// with the reasons for each change
+type FeeRecommendation struct {
+	FeeConfig commontype.FeeConfig
+	Reasons   []string
+}
+
+// GetBaseFeeStats returns min, average and max of [baseFees]
+func GetBaseFeeStats(baseFees []*big.Int) BaseFeeStats {
+	stats := BaseFeeStats{}
+	if len(baseFees) == 0 {
+		return stats
+	}
+	sum := big.NewInt(0)
+	for _, baseFee := range baseFees {
+		if stats.Min == nil || baseFee.Cmp(stats.Min) < 0 {
+			stats.Min = baseFee
+		}
+		if stats.Max == nil || baseFee.Cmp(stats.Max) > 0 {
+			stats.Max = baseFee
+		}
+		sum.Add(sum, baseFee)
+	}
+	stats.Avg = sum.Div(sum, big.NewInt(int64(len(baseFees))))
+	return stats
+}
+
+// windowGas returns the gas used by the blocks previous to [samples][i] inside
+// the dynamic fee rolling window
+func windowGas(samples []BlockFeeSample, i int) uint64 {
+	gas := uint64(0)
+	for j := i - 1; j >= 0 && samples[j].Time+feeWindowSeconds > samples[i].Time; j-- {
+		gas += samples[j].GasUsed
+	}
+	return gas
+}
+
+// GetFeeUsage computes the usage statistics of [samples], sorted by block number,
+// for a chain using [feeConfig]
+func GetFeeUsage(samples []BlockFeeSample, feeConfig commontype.FeeConfig) FeeUsage {
+	usage := FeeUsage{NumBlocks: len(samples)}
+	if len(samples) == 0 {
+		return usage
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	usage.Period = last.Time - first.Time
+	if len(samples) > 1 {
+		usage.AvgBlockTime = float64(usage.Period) / float64(len(samples)-1)
+	}
+	gasLimit := float64(feeConfig.GasLimit.Uint64())
+	utilization := 0.0
+	baseFees := []*big.Int{}
+	highBaseFees := 0
+	highBaseFee := new(big.Int).Mul(feeConfig.MinBaseFee, big.NewInt(2))
+	for i, sample := range samples {
+		blockUtilization := float64(sample.GasUsed) / gasLimit
+		utilization += blockUtilization
+		if blockUtilization >= fullBlockUtilizationRate {
+			usage.FullBlocks++
+		}
+		if sample.BaseFee == nil {
+			continue
+		}
+		baseFees = append(baseFees, sample.BaseFee)
+		if sample.BaseFee.Cmp(highBaseFee) > 0 {
+			highBaseFees++
+		}
+		if i > 0 && samples[i-1].BaseFee != nil && samples[i-1].BaseFee.Sign() > 0 {
+			change := new(big.Float).Quo(
+				new(big.Float).SetInt(new(big.Int).Sub(sample.BaseFee, samples[i-1].BaseFee)),
+				new(big.Float).SetInt(samples[i-1].BaseFee),
+			)
+			changeFlt, _ := change.Abs(change).Float64()
+			if changeFlt > usage.MaxBaseFeeChange {
+				usage.MaxBaseFeeChange = changeFlt
+			}
+		}
+	}
+	usage.AvgBlockUtilization = utilization / float64(len(samples))
+	usage.BaseFees = GetBaseFeeStats(baseFees)
+	if len(baseFees) > 0 {
+		usage.HighBaseFeeRatio = float64(highBaseFees) / float64(len(baseFees))
+	}
+	// only consider blocks with a complete window behind, if possible
+	windowGases := []uint64{}
+	for i := range samples {
+		if samples[i].Time >= first.Time+feeWindowSeconds {
+			windowGases = append(windowGases, windowGas(samples, i))
+		}
+	}
+	if len(windowGases) == 0 {
+		for i := range samples {
+			windowGases = append(windowGases, windowGas(samples, i))
+		}
+	}
+	sum := uint64(0)
+	for _, gas := range windowGases {
+		sum += gas
+	}
+	usage.AvgWindowGas = sum / uint64(len(windowGases))
+	sort.Slice(windowGases, func(i, j int) bool { return windowGases[i] < windowGases[j] })
+	usage.PeakWindowGas = windowGases[len(windowGases)*9/10]
+	return usage
+}
+
+func roundUp(n *big.Int, to int64) *big.Int {
+	step := big.NewInt(to)
+	rounded := new(big.Int).Add(n, big.NewInt(to-1))
+	rounded.Div(rounded, step)
+	return rounded.Mul(rounded, step)
+}
+
+func copyFeeConfig(feeConfig commontype.FeeConfig) commontype.FeeConfig {
+	copyInt := func(n *big.Int) *big.Int {
+		if n == nil {
+			return nil
+		}
+		return new(big.Int).Set(n)
+	}
+	return commontype.FeeConfig{
+		GasLimit:                 copyInt(feeConfig.GasLimit),
+		TargetBlockRate:          feeConfig.TargetBlockRate,
+		MinBaseFee:               copyInt(feeConfig.MinBaseFee),
+		TargetGas:                copyInt(feeConfig.TargetGas),
+		BaseFeeChangeDenominator: copyInt(feeConfig.BaseFeeChangeDenominator),
+		MinBlockGasCost:          copyInt(feeConfig.MinBlockGasCost),
+		MaxBlockGasCost:          copyInt(feeConfig.MaxBlockGasCost),
+		BlockGasCostStep:         copyInt(feeConfig.BlockGasCostStep),
+	}
+}
+
+// RecommendFeeConfig suggests target gas and base fee change denominator
+// adjustments to [feeConfig] for the observed [usage]:
+// - target gas is raised when regular load is above it, so fees only increase on
+// spikes, and lowered when load is far below it, so fees react to spikes
+// - denominator is raised when fees are volatile, and lowered when fees stay high
+// while load is below target
+func RecommendFeeConfig(feeConfig commontype.FeeConfig, usage FeeUsage) FeeRecommendation {
+	recommendation := FeeRecommendation{
+		FeeConfig: copyFeeConfig(feeConfig),
+	}
+	if usage.NumBlocks < 2 {
+		return recommendation
+	}
+	targetGas := feeConfig.TargetGas
+	peakWindowGas := new(big.Int).SetUint64(usage.PeakWindowGas)
+	targetBlockRate := feeConfig.TargetBlockRate
+	if targetBlockRate == 0 {
+		targetBlockRate = 1
+	}
+	// max gas that can be consumed in a window when producing blocks at the target rate
+	windowCapacity := new(big.Int).Mul(feeConfig.GasLimit, big.NewInt(int64(feeWindowSeconds/targetBlockRate)))
+	if windowCapacity.Sign() == 0 {
+		windowCapacity = new(big.Int).Set(feeConfig.GasLimit)
+	}
+	desiredTargetGas := new(big.Int).Mul(peakWindowGas, big.NewInt(100+targetGasHeadroom))
+	desiredTargetGas = roundUp(desiredTargetGas.Div(desiredTargetGas, big.NewInt(100)), targetGasRounding)
+	switch {
+	case peakWindowGas.Cmp(targetGas) > 0:
+		newTargetGas := desiredTargetGas
+		if newTargetGas.Cmp(windowCapacity) > 0 {
+			newTargetGas = windowCapacity
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf(
+				"target gas is bounded by the gas limit (%d) and the target block rate (%ds): consider increasing the gas limit",
+				feeConfig.GasLimit,
+				feeConfig.TargetBlockRate,
+			))
+		}
+		if newTargetGas.Cmp(targetGas) > 0 {
+			recommendation.FeeConfig.TargetGas = newTargetGas
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf(
+				"regular gas usage per %ds window (%d) is above target gas (%d): base fee increases on regular load",
+				feeWindowSeconds,
+				peakWindowGas,
+				targetGas,
+			))
+		}
+	case new(big.Int).Mul(peakWindowGas, big.NewInt(4)).Cmp(targetGas) < 0:
+		// lower target gas at most by half
+		newTargetGas := new(big.Int).Mul(desiredTargetGas, big.NewInt(2))
+		if halfTargetGas := new(big.Int).Div(targetGas, big.NewInt(2)); newTargetGas.Cmp(halfTargetGas) < 0 {
+			newTargetGas = roundUp(halfTargetGas, targetGasRounding)
+		}
+		if newTargetGas.Cmp(targetGas) < 0 {
+			recommendation.FeeConfig.TargetGas = newTargetGas
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf(
+				"regular gas usage per %ds window (%d) is below 25%% of target gas (%d): base fee barely reacts to load spikes",
+				feeWindowSeconds,
+				peakWindowGas,
+				targetGas,
+			))
+		}
+	}
+	denominator := feeConfig.BaseFeeChangeDenominator
+	switch {
+	case usage.MaxBaseFeeChange > maxBaseFeeChange:
+		newDenominator := new(big.Int).Mul(denominator, big.NewInt(2))
+		if newDenominator.Cmp(big.NewInt(maxBaseFeeDenominator)) > 0 {
+			newDenominator = big.NewInt(maxBaseFeeDenominator)
+		}
+		if newDenominator.Cmp(denominator) > 0 {
+			recommendation.FeeConfig.BaseFeeChangeDenominator = newDenominator
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf(
+				"base fee changed up to %.1f%% between consecutive blocks: a bigger denominator smooths fee changes",
+				usage.MaxBaseFeeChange*100,
+			))
+		}
+	case usage.HighBaseFeeRatio > 0.5 && new(big.Int).SetUint64(usage.AvgWindowGas).Cmp(recommendation.FeeConfig.TargetGas) < 0:
+		newDenominator := new(big.Int).Div(denominator, big.NewInt(2))
+		if newDenominator.Cmp(big.NewInt(minBaseFeeDenominator)) < 0 {
+			newDenominator = big.NewInt(minBaseFeeDenominator)
+		}
+		if newDenominator.Cmp(denominator) < 0 {
+			recommendation.FeeConfig.BaseFeeChangeDenominator = newDenominator
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf(
+				"base fee stayed above twice the min base fee on %.0f%% of the blocks while usage was below target: a smaller denominator lets fees decrease faster",
+				usage.HighBaseFeeRatio*100,
+			))
+		}
+	}
+	return recommendation
+}
+
+// SimulateBaseFees replays the gas usage of [samples] under [feeConfig], returning
+// the base fee each block would have had. It approximates the dynamic fee
+// algorithm, ignoring block gas cost, to predict the impact of fee config changes
+func SimulateBaseFees(samples []BlockFeeSample, feeConfig commontype.FeeConfig) []*big.Int {
+	if len(samples) == 0 {
+		return nil
+	}
+	baseFee := new(big.Int).Set(feeConfig.MinBaseFee)
+	if samples[0].BaseFee != nil && samples[0].BaseFee.Cmp(baseFee) > 0 {
+		baseFee.Set(samples[0].BaseFee)
+	}
+	baseFees := []*big.Int{new(big.Int).Set(baseFee)}
+	targetGas := feeConfig.TargetGas
+	for i := 1; i < len(samples); i++ {
+		gas := new(big.Int).SetUint64(windowGas(samples, i))
+		delta := new(big.Int)
+		switch gas.Cmp(targetGas) {
+		case 1:
+			delta.Sub(gas, targetGas)
+		case -1:
+			delta.Sub(targetGas, gas)
+		}
+		if delta.Sign() != 0 {
+			increase := gas.Cmp(targetGas) > 0
+			delta.Mul(delta, baseFee)
+			delta.Div(delta, targetGas)
+			delta.Div(delta, feeConfig.BaseFeeChangeDenominator)
+			if delta.Sign() == 0 {
+				delta.SetInt64(1)
+			}
+			if increase {
+				baseFee.Add(baseFee, delta)
+			} else {
+				// fees decrease faster when no blocks were produced for a whole window
+				if elapsed := samples[i].Time - samples[i-1].Time; elapsed > feeWindowSeconds {
+					delta.Mul(delta, big.NewInt(int64(elapsed/feeWindowSeconds)))
+				}
+				baseFee.Sub(baseFee, delta)
+			}
+		}
+		if baseFee.Cmp(feeConfig.MinBaseFee) < 0 {
+			baseFee.Set(feeConfig.MinBaseFee)
+		}
+		baseFees = append(baseFees, new(big.Int).Set(baseFee))
+	}
+	return baseFees
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/stretchr/testify/require"
+)
+
+func testFeeConfig() commontype.FeeConfig {
+	return commontype.FeeConfig{
+		GasLimit:                 big.NewInt(8_000_000),
+		MinBaseFee:               big.NewInt(25_000_000_000),
+		TargetGas:                big.NewInt(15_000_000),
+		BaseFeeChangeDenominator: big.NewInt(36),
+		MinBlockGasCost:          big.NewInt(0),
+		MaxBlockGasCost:          big.NewInt(1_000_000),
+		TargetBlockRate:          2,
+		BlockGasCostStep:         big.NewInt(200_000),
+	}
+}
+
+// testSamples returns [numBlocks] blocks produced every [blockTime] seconds, each
+// using [gasUsed], with base fees as given by the fee algorithm
+func testSamples(numBlocks int, blockTime uint64, gasUsed uint64, feeConfig commontype.FeeConfig) []BlockFeeSample {
+	samples := []BlockFeeSample{}
+	for i := 0; i < numBlocks; i++ {
+		samples = append(samples, BlockFeeSample{
+			Number:  uint64(i),
+			Time:    uint64(i) * blockTime,
+			GasUsed: gasUsed,
+		})
+	}
+	for i, baseFee := range SimulateBaseFees(samples, feeConfig) {
+		samples[i].BaseFee = baseFee
+	}
+	return samples
+}
+
+func TestGetFeeUsage(t *testing.T) {
+	feeConfig := testFeeConfig()
+	samples := testSamples(100, 2, 4_000_000, feeConfig)
+	usage := GetFeeUsage(samples, feeConfig)
+	require.Equal(t, 100, usage.NumBlocks)
+	require.Equal(t, uint64(198), usage.Period)
+	require.Equal(t, 2.0, usage.AvgBlockTime)
+	require.Equal(t, 0.5, usage.AvgBlockUtilization)
+	require.Equal(t, 0, usage.FullBlocks)
+	// 4 previous blocks in each 10s window
+	require.Equal(t, uint64(16_000_000), usage.AvgWindowGas)
+	require.Equal(t, uint64(16_000_000), usage.PeakWindowGas)
+	require.Equal(t, feeConfig.MinBaseFee, usage.BaseFees.Min)
+	require.Equal(t, 1, usage.BaseFees.Max.Cmp(feeConfig.MinBaseFee))
+}
+
+func TestSimulateBaseFees(t *testing.T) {
+	feeConfig := testFeeConfig()
+	// below target, base fee stays at min
+	for _, baseFee := range SimulateBaseFees(testSamples(20, 2, 1_000_000, feeConfig), feeConfig) {
+		require.Equal(t, feeConfig.MinBaseFee, baseFee)
+	}
+	// above target, base fee keeps increasing
+	baseFees := SimulateBaseFees(testSamples(20, 2, 8_000_000, feeConfig), feeConfig)
+	for i := 6; i < len(baseFees); i++ {
+		require.Equal(t, 1, baseFees[i].Cmp(baseFees[i-1]))
+	}
+}
+
+func TestRecommendFeeConfig(t *testing.T) {
+	feeConfig := testFeeConfig()
+
+	// load above target: target gas is raised and base fee stays at min
+	samples := testSamples(100, 2, 4_000_000, feeConfig)
+	recommendation := RecommendFeeConfig(feeConfig, GetFeeUsage(samples, feeConfig))
+	require.Equal(t, big.NewInt(20_000_000), recommendation.FeeConfig.TargetGas)
+	require.Equal(t, feeConfig.BaseFeeChangeDenominator, recommendation.FeeConfig.BaseFeeChangeDenominator)
+	require.Len(t, recommendation.Reasons, 1)
+	stats := GetBaseFeeStats(SimulateBaseFees(samples, recommendation.FeeConfig))
+	require.Equal(t, feeConfig.MinBaseFee, stats.Max)
+	// the given fee config is not modified
+	require.Equal(t, big.NewInt(15_000_000), feeConfig.TargetGas)
+
+	// load above window capacity: target gas is bounded by gas limit
+	samples = testSamples(100, 1, 8_000_000, feeConfig)
+	recommendation = RecommendFeeConfig(feeConfig, GetFeeUsage(samples, feeConfig))
+	require.Equal(t, big.NewInt(40_000_000), recommendation.FeeConfig.TargetGas)
+	require.Contains(t, recommendation.Reasons[0], "consider increasing the gas limit")
+
+	// load far below target: target gas is lowered at most by half
+	samples = testSamples(100, 2, 100_000, feeConfig)
+	recommendation = RecommendFeeConfig(feeConfig, GetFeeUsage(samples, feeConfig))
+	require.Equal(t, big.NewInt(8_000_000), recommendation.FeeConfig.TargetGas)
+
+	// load around target: no changes
+	samples = testSamples(100, 2, 2_500_000, feeConfig)
+	recommendation = RecommendFeeConfig(feeConfig, GetFeeUsage(samples, feeConfig))
+	require.Equal(t, feeConfig, recommendation.FeeConfig)
+	require.Empty(t, recommendation.Reasons)
+
+	// volatile base fee: denominator is raised
+	samples = testSamples(10, 2, 2_500_000, feeConfig)
+	samples[5].BaseFee = new(big.Int).Mul(samples[4].BaseFee, big.NewInt(2))
+	recommendation = RecommendFeeConfig(feeConfig, GetFeeUsage(samples, feeConfig))
+	require.Equal(t, big.NewInt(72), recommendation.FeeConfig.BaseFeeChangeDenominator)
+
+	// high base fee under low load: denominator is lowered
+	samples = testSamples(10, 2, 2_500_000, feeConfig)
+	for i := range samples {
+		samples[i].BaseFee = new(big.Int).Mul(feeConfig.MinBaseFee, big.NewInt(3))
+	}
+	recommendation = RecommendFeeConfig(feeConfig, GetFeeUsage(samples, feeConfig))
+	require.Equal(t, big.NewInt(18), recommendation.FeeConfig.BaseFeeChangeDenominator)
+}
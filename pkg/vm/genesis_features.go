@@ -0,0 +1,167 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"golang.org/x/mod/semver"
+)
+
+const allowListManagerRoleFeature = "allow list manager role"
+
+// subnetEVMPrecompileMinVersions maps precompile genesis config keys to the first
+// subnet-evm release supporting them
+var subnetEVMPrecompileMinVersions = map[string]string{
+	deployerallowlist.ConfigKey: "v0.1.0",
+	txallowlist.ConfigKey:       "v0.2.0",
+	nativeminter.ConfigKey:      "v0.2.0",
+	feemanager.ConfigKey:        "v0.2.5",
+	rewardmanager.ConfigKey:     "v0.4.2",
+	warp.ConfigKey:              "v0.5.0",
+}
+
+// subnetEVMFeatureMinVersions maps genesis features that are not a precompile by
+// themselves to the first subnet-evm release supporting them
+var subnetEVMFeatureMinVersions = map[string]string{
+	allowListManagerRoleFeature: "v0.6.4",
+}
+
+// GetSubnetEVMGenesisFeatures returns the precompiles and features used by a subnet-evm
+// genesis, mapped to the first subnet-evm release supporting each of them
+func GetSubnetEVMGenesisFeatures(genesisBytes []byte) (map[string]string, error) {
+	var genesis map[string]interface{}
+	if err := json.Unmarshal(genesisBytes, &genesis); err != nil {
+		return nil, fmt.Errorf("failed parsing genesis: %w", err)
+	}
+	config, _ := genesis["config"].(map[string]interface{})
+	features := map[string]string{}
+	for configKey, minVersion := range subnetEVMPrecompileMinVersions {
+		precompileConfig, ok := config[configKey].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		features[configKey] = minVersion
+		if managers, ok := precompileConfig["managerAddresses"].([]interface{}); ok && len(managers) > 0 {
+			features[allowListManagerRoleFeature] = subnetEVMFeatureMinVersions[allowListManagerRoleFeature]
+		}
+	}
+	return features, nil
+}
+
+// GetSubnetEVMVersionConstraint returns the subnet-evm version range allowed by the
+// features used by a subnet-evm genesis
+func GetSubnetEVMVersionConstraint(genesisBytes []byte) (models.VMVersionConstraint, error) {
+	features, err := GetSubnetEVMGenesisFeatures(genesisBytes)
+	if err != nil {
+		return models.VMVersionConstraint{}, err
+	}
+	constraint := models.VMVersionConstraint{}
+	for feature, minVersion := range features {
+		if constraint.MinVersion == "" || semver.Compare(minVersion, constraint.MinVersion) > 0 {
+			constraint.MinVersion = minVersion
+		}
+		constraint.Features = append(constraint.Features, feature)
+	}
+	sort.Strings(constraint.Features)
+	return constraint, nil
+}
+
+// CheckSubnetEVMVersion returns an error if subnet-evm [version] lacks genesis features
+// required by [constraint]. Non semantic versions (eg latest) are not checked
+func CheckSubnetEVMVersion(constraint models.VMVersionConstraint, version string) error {
+	if constraint.MinVersion == "" || !semver.IsValid(version) {
+		return nil
+	}
+	if semver.Compare(version, constraint.MinVersion) < 0 {
+		return fmt.Errorf(
+			"subnet-evm %s lacks genesis features used by this blockchain (%s): %s or later is required",
+			version,
+			strings.Join(constraint.Features, ", "),
+			constraint.MinVersion,
+		)
+	}
+	return nil
+}
+
+// SetSubnetEVMVersionConstraint computes the subnet-evm version constraint of [genesisBytes],
+// checks that the VM version of [sc] satisfies it, and stores it on [sc]
+func SetSubnetEVMVersionConstraint(sc *models.Sidecar, genesisBytes []byte) error {
+	constraint, err := GetSubnetEVMVersionConstraint(genesisBytes)
+	if err != nil {
+		return err
+	}
+	if err := CheckSubnetEVMVersion(constraint, sc.VMVersion); err != nil {
+		return err
+	}
+	sc.VMVersionConstraint = constraint
+	return nil
+}
+
+// CheckSidecarSubnetEVMVersion returns an error if [version] can't be used as the
+// subnet-evm version of [sc]. For sidecars created before constraints were stored,
+// the constraint is computed from the blockchain genesis
+func CheckSidecarSubnetEVMVersion(app *application.Avalanche, sc models.Sidecar, version string) error {
+	if sc.VM != models.SubnetEvm {
+		return nil
+	}
+	constraint := sc.VMVersionConstraint
+	if constraint.MinVersion == "" {
+		genesisBytes, err := app.LoadRawGenesis(sc.Name)
+		if err != nil {
+			return err
+		}
+		constraint, err = GetSubnetEVMVersionConstraint(genesisBytes)
+		if err != nil {
+			return err
+		}
+	}
+	return CheckSubnetEVMVersion(constraint, version)
+}
+
+// GetSubnetEVMUpgradeConstraint returns the subnet-evm version range required by the
+// precompiles activated or configured on a subnet-evm upgrade file
+func GetSubnetEVMUpgradeConstraint(upgradeBytes []byte) (models.VMVersionConstraint, error) {
+	var upgrade struct {
+		PrecompileUpgrades []map[string]interface{} `json:"precompileUpgrades"`
+	}
+	if err := json.Unmarshal(upgradeBytes, &upgrade); err != nil {
+		return models.VMVersionConstraint{}, fmt.Errorf("failed parsing upgrade file: %w", err)
+	}
+	// precompile upgrades share the genesis config format, so features are detected
+	// as in a genesis config
+	constraint := models.VMVersionConstraint{}
+	for _, precompileUpgrade := range upgrade.PrecompileUpgrades {
+		genesisBytes, err := json.Marshal(map[string]interface{}{"config": precompileUpgrade})
+		if err != nil {
+			return models.VMVersionConstraint{}, err
+		}
+		upgradeConstraint, err := GetSubnetEVMVersionConstraint(genesisBytes)
+		if err != nil {
+			return models.VMVersionConstraint{}, err
+		}
+		if constraint.MinVersion == "" || semver.Compare(upgradeConstraint.MinVersion, constraint.MinVersion) > 0 {
+			constraint.MinVersion = upgradeConstraint.MinVersion
+		}
+		for _, feature := range upgradeConstraint.Features {
+			if !slices.Contains(constraint.Features, feature) {
+				constraint.Features = append(constraint.Features, feature)
+			}
+		}
+	}
+	sort.Strings(constraint.Features)
+	return constraint, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSubnetEVMVersionConstraint(t *testing.T) {
+	type test struct {
+		name       string
+		genesis    string
+		constraint models.VMVersionConstraint
+		shouldFail bool
+	}
+	tests := []test{
+		{
+			name:       "no precompiles",
+			genesis:    `{"config":{"chainId":1}}`,
+			constraint: models.VMVersionConstraint{},
+		},
+		{
+			name:    "warp and fee manager",
+			genesis: `{"config":{"chainId":1,"warpConfig":{"blockTimestamp":0},"feeManagerConfig":{"adminAddresses":["0x01"]}}}`,
+			constraint: models.VMVersionConstraint{
+				MinVersion: "v0.5.0",
+				Features:   []string{"feeManagerConfig", "warpConfig"},
+			},
+		},
+		{
+			name:    "allow list manager role",
+			genesis: `{"config":{"chainId":1,"txAllowListConfig":{"adminAddresses":["0x01"],"managerAddresses":["0x02"]}}}`,
+			constraint: models.VMVersionConstraint{
+				MinVersion: "v0.6.4",
+				Features:   []string{allowListManagerRoleFeature, "txAllowListConfig"},
+			},
+		},
+		{
+			name:       "invalid genesis",
+			genesis:    `not a genesis`,
+			shouldFail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			constraint, err := GetSubnetEVMVersionConstraint([]byte(tt.genesis))
+			if tt.shouldFail {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.constraint.MinVersion, constraint.MinVersion)
+			require.ElementsMatch(t, tt.constraint.Features, constraint.Features)
+		})
+	}
+}
+
+func TestCheckSubnetEVMVersion(t *testing.T) {
+	constraint := models.VMVersionConstraint{MinVersion: "v0.6.4", Features: []string{allowListManagerRoleFeature}}
+	require.NoError(t, CheckSubnetEVMVersion(constraint, "v0.6.4"))
+	require.NoError(t, CheckSubnetEVMVersion(constraint, "v0.7.0"))
+	require.NoError(t, CheckSubnetEVMVersion(constraint, "latest"))
+	require.NoError(t, CheckSubnetEVMVersion(models.VMVersionConstraint{}, "v0.1.0"))
+	require.Error(t, CheckSubnetEVMVersion(constraint, "v0.6.3"))
+}
+
+func TestGetSubnetEVMUpgradeConstraint(t *testing.T) {
+	upgrade := `{"precompileUpgrades":[
+{"feeManagerConfig":{"blockTimestamp":1700000000,"adminAddresses":["0x01"]}},
+{"txAllowListConfig":{"blockTimestamp":1700000100,"adminAddresses":["0x01"],"managerAddresses":["0x02"]}},
+{"feeManagerConfig":{"blockTimestamp":1700000200,"disable":true}}
+]}`
+	constraint, err := GetSubnetEVMUpgradeConstraint([]byte(upgrade))
+	require.NoError(t, err)
+	require.Equal(t, "v0.6.4", constraint.MinVersion)
+	require.Equal(t, []string{allowListManagerRoleFeature, "feeManagerConfig", "txAllowListConfig"}, constraint.Features)
+
+	constraint, err = GetSubnetEVMUpgradeConstraint([]byte(`{"precompileUpgrades":[]}`))
+	require.NoError(t, err)
+	require.Empty(t, constraint.MinVersion)
+
+	_, err = GetSubnetEVMUpgradeConstraint([]byte("not an upgrade"))
+	require.Error(t, err)
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/mod/semver"
+)
+
+type MigrationKind int
+
+const (
+	// no special step is needed to upgrade
+	MigrationNone MigrationKind = iota
+	// the VM migrates its database on first start. A backup is needed to be able to roll back
+	MigrationOnStart
+	// the existing chain state can't be used by the new version, so the chain must be resynced
+	MigrationResync
+)
+
+func (k MigrationKind) String() string {
+	switch k {
+	case MigrationNone:
+		return "none"
+	case MigrationOnStart:
+		return "database migration"
+	case MigrationResync:
+		return "resync"
+	}
+	return "unknown"
+}
+
+// VMMigration describes the steps required by a VM upgrade
+type VMMigration struct {
+	Kind MigrationKind
+	// version introducing the migration
+	Version     string
+	Description string
+}
+
+// subnetEVMMigrations lists the subnet-evm releases that require a database migration, or
+// that are state incompatible with previous releases. No release so far needs one, as
+// subnet-evm migrates its database in place. New entries are to be added here as releases
+// require it
+var subnetEVMMigrations = []VMMigration{}
+
+// ErrDowngrade is returned when asked for the migration to an older version. Databases
+// written by newer versions may not be readable by older ones, so downgrades are not supported
+var ErrDowngrade = errors.New("VM downgrades are not supported")
+
+// GetSubnetEVMMigration returns the most demanding migration needed when upgrading
+// subnet-evm from [fromVersion] to [toVersion]. Fails with ErrDowngrade if [toVersion]
+// is older than [fromVersion]
+func GetSubnetEVMMigration(fromVersion string, toVersion string) (VMMigration, error) {
+	return getMigration(subnetEVMMigrations, fromVersion, toVersion)
+}
+
+func getMigration(migrations []VMMigration, fromVersion string, toVersion string) (VMMigration, error) {
+	if !semver.IsValid(fromVersion) {
+		return VMMigration{}, fmt.Errorf("invalid version %q", fromVersion)
+	}
+	if !semver.IsValid(toVersion) {
+		return VMMigration{}, fmt.Errorf("invalid version %q", toVersion)
+	}
+	if semver.Compare(toVersion, fromVersion) < 0 {
+		return VMMigration{}, fmt.Errorf("%w: %s is older than the current version %s", ErrDowngrade, toVersion, fromVersion)
+	}
+	required := VMMigration{Kind: MigrationNone}
+	for _, migration := range migrations {
+		if semver.Compare(migration.Version, fromVersion) > 0 &&
+			semver.Compare(migration.Version, toVersion) <= 0 &&
+			migration.Kind > required.Kind {
+			required = migration
+		}
+	}
+	return required, nil
+}
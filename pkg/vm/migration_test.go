@@ -0,0 +1,46 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getMigration(t *testing.T) {
+	migrations := []VMMigration{
+		{Kind: MigrationOnStart, Version: "v0.6.0"},
+		{Kind: MigrationResync, Version: "v0.7.0"},
+	}
+	type test struct {
+		name         string
+		from         string
+		to           string
+		expectedKind MigrationKind
+		shouldFail   bool
+		expectedErr  error
+	}
+	tests := []test{
+		{name: "no migration in range", from: "v0.6.0", to: "v0.6.5", expectedKind: MigrationNone},
+		{name: "migration on start", from: "v0.5.9", to: "v0.6.5", expectedKind: MigrationOnStart},
+		{name: "most demanding migration", from: "v0.5.9", to: "v0.7.0", expectedKind: MigrationResync},
+		{name: "downgrade", from: "v0.6.5", to: "v0.6.4", shouldFail: true, expectedErr: ErrDowngrade},
+		{name: "same version", from: "v0.7.0", to: "v0.7.0", expectedKind: MigrationNone},
+		{name: "invalid version", from: "latest", to: "v0.7.0", shouldFail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, err := getMigration(migrations, tt.from, tt.to)
+			if tt.shouldFail {
+				require.Error(t, err)
+				if tt.expectedErr != nil {
+					require.ErrorIs(t, err, tt.expectedErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedKind, migration.Kind)
+		})
+	}
+}
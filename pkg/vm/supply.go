@@ -0,0 +1,102 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/core"
+)
+
+// GetAllocationsTotal returns the sum of the balances allocated on [allocs],
+// that is, the initial supply of the native token
+func GetAllocationsTotal(allocs core.GenesisAlloc) *big.Int {
+	total := big.NewInt(0)
+	for _, account := range allocs {
+		if account.Balance != nil {
+			total.Add(total, account.Balance)
+		}
+	}
+	return total
+}
+
+// GetSupplyHeadroom returns how much can still be allocated on [allocs] without exceeding
+// [maxSupply], once [reserved] is also allocated. It is negative if the cap is already exceeded
+func GetSupplyHeadroom(allocs core.GenesisAlloc, reserved *big.Int, maxSupply *big.Int) *big.Int {
+	headroom := new(big.Int).Sub(maxSupply, GetAllocationsTotal(allocs))
+	if reserved != nil {
+		headroom.Sub(headroom, reserved)
+	}
+	return headroom
+}
+
+// CheckSupplyCap fails if the allocations on [allocs], plus [reserved], exceed [maxSupply].
+// A nil [maxSupply] means no cap was declared. Amounts are reported with [decimals]
+func CheckSupplyCap(allocs core.GenesisAlloc, reserved *big.Int, maxSupply *big.Int, decimals uint8) error {
+	if maxSupply == nil {
+		return nil
+	}
+	headroom := GetSupplyHeadroom(allocs, reserved, maxSupply)
+	if headroom.Sign() < 0 {
+		return fmt.Errorf(
+			"initial token allocation exceeds the maximum supply of %s by %s",
+			utils.FormatAmount(maxSupply, decimals),
+			utils.FormatAmount(new(big.Int).Neg(headroom), decimals),
+		)
+	}
+	return nil
+}
+
+// TokenUnit returns the amount of base units of one token with [decimals]
+func TokenUnit(decimals uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+}
+
+// TokensToBaseUnits converts [amount] tokens with [decimals] into base units
+func TokensToBaseUnits(amount uint64, decimals uint8) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(amount), TokenUnit(decimals))
+}
+
+// MaxSupplyFromTokens converts a maximum supply given in token units into base units.
+// Zero means no cap was declared
+func MaxSupplyFromTokens(maxSupply uint64, decimals uint8) *big.Int {
+	if maxSupply == 0 {
+		return nil
+	}
+	return TokensToBaseUnits(maxSupply, decimals)
+}
+
+// icmFundingBalance is the balance allocated on genesis to the ICM funded address
+func icmFundingBalance(useExternalGasToken bool) *big.Int {
+	if useExternalGasToken {
+		return externalGasTokenBalance
+	}
+	return icmBalance
+}
+
+func displaySupplyHeadroom(allocs core.GenesisAlloc, reserved *big.Int, maxSupply *big.Int, tokenSymbol string, decimals uint8) {
+	if maxSupply == nil {
+		return
+	}
+	headroom := GetSupplyHeadroom(allocs, reserved, maxSupply)
+	if headroom.Sign() < 0 {
+		ux.Logger.RedXToUser(
+			"Maximum supply of %s %s exceeded by %s %s",
+			utils.FormatAmount(maxSupply, decimals),
+			tokenSymbol,
+			utils.FormatAmount(new(big.Int).Neg(headroom), decimals),
+			tokenSymbol,
+		)
+		return
+	}
+	ux.Logger.PrintToUser(
+		"Remaining supply headroom: %s %s (maximum supply %s %s)",
+		utils.FormatAmount(headroom, decimals),
+		tokenSymbol,
+		utils.FormatAmount(maxSupply, decimals),
+		tokenSymbol,
+	)
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSupplyCap(t *testing.T) {
+	require := require.New(t)
+
+	allocs := core.GenesisAlloc{
+		common.HexToAddress("0x00000000000000000000000000000000000A11CE"): {Balance: new(big.Int).Mul(big.NewInt(600), OneAvax)},
+		common.HexToAddress("0x0000000000000000000000000000000000000B0B"): {Balance: new(big.Int).Mul(big.NewInt(300), OneAvax)},
+		// contract accounts may have no balance
+		common.HexToAddress("0x0000000000000000000000000000000000C0DE00"): {Code: []byte{0x00}},
+	}
+	require.Equal(new(big.Int).Mul(big.NewInt(900), OneAvax), GetAllocationsTotal(allocs))
+
+	// no cap declared
+	require.Nil(MaxSupplyFromTokens(0, 18))
+	require.NoError(CheckSupplyCap(allocs, nil, MaxSupplyFromTokens(0, 18), 18))
+
+	require.NoError(CheckSupplyCap(allocs, nil, MaxSupplyFromTokens(1000, 18), 18))
+	require.Equal(new(big.Int).Mul(big.NewInt(100), OneAvax), GetSupplyHeadroom(allocs, nil, MaxSupplyFromTokens(1000, 18)))
+
+	// reserved supply counts against the cap
+	reserved := new(big.Int).Mul(big.NewInt(100), OneAvax)
+	require.NoError(CheckSupplyCap(allocs, reserved, MaxSupplyFromTokens(1000, 18), 18))
+	require.ErrorContains(CheckSupplyCap(allocs, reserved, MaxSupplyFromTokens(999, 18), 18), "exceeds the maximum supply")
+
+	// amounts are shown with the token decimals
+	require.ErrorContains(
+		CheckSupplyCap(allocs, reserved, MaxSupplyFromTokens(999, 18), 6),
+		"exceeds the maximum supply of 999000000000000.000000",
+	)
+}
+
+func TestTokensToBaseUnits(t *testing.T) {
+	require := require.New(t)
+	require.Equal(OneAvax, TokenUnit(18))
+	require.Equal(big.NewInt(1_000_000), TokenUnit(6))
+	require.Equal(big.NewInt(250_000_000), TokensToBaseUnits(250, 6))
+	require.Equal(big.NewInt(0), TokensToBaseUnits(0, 6))
+	require.Equal(big.NewInt(2_000_000), MaxSupplyFromTokens(2, 6))
+}
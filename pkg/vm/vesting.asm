@@ -0,0 +1,178 @@
+;; Source of the genesis vesting contract deployed at VestingContractAddress.
+;; deployed_vesting_bytecode.txt is the assembly of this file, as checked by
+;; TestVestingBytecodeSource. After changing it, regenerate the bytecode with
+;; that test and update the txt file.
+;;
+;; The schedule of a beneficiary is stored at slots (beneficiary << 8) | field:
+;;   0: amount, 1: start, 2: cliff, 3: duration, 4: released
+;;
+;; Syntax: one opcode per line, PUSHn takes a hex argument or a @label, and
+;; "label:" lines assemble into a JUMPDEST.
+
+;; dispatch on the function selector
+    PUSH1 0x00
+    CALLDATALOAD
+    PUSH1 0xe0
+    SHR
+    DUP1
+    PUSH4 0x86d1a69f ;; release()
+    EQ
+    PUSH2 @release
+    JUMPI
+    DUP1
+    PUSH4 0xa3f8eace ;; releasable(address)
+    EQ
+    PUSH2 @releasable
+    JUMPI
+    DUP1
+    PUSH4 0x9852595c ;; released(address)
+    EQ
+    PUSH2 @released
+    JUMPI
+revert:
+    PUSH1 0x00
+    DUP1
+    REVERT
+
+;; release(): sends the caller its releasable amount
+release:
+    CALLVALUE
+    PUSH2 @revert
+    JUMPI
+    CALLER
+    PUSH1 0x08
+    SHL
+    PUSH2 @release_amount
+    SWAP1
+    PUSH2 @compute_releasable
+    JUMP
+release_amount:
+    ;; stack from the top: schedule base, releasable
+    DUP2
+    ISZERO
+    PUSH2 @revert
+    JUMPI
+    ;; released += releasable, before the transfer
+    PUSH1 0x04
+    OR
+    DUP1
+    SLOAD
+    DUP3
+    ADD
+    SWAP1
+    SSTORE
+    PUSH1 0x00
+    DUP1
+    DUP1
+    DUP1
+    DUP5
+    CALLER
+    GAS
+    CALL
+    ISZERO
+    PUSH2 @revert
+    JUMPI
+    ;; emit Released(address indexed beneficiary, uint256 amount)
+    PUSH1 0x00
+    MSTORE
+    CALLER
+    PUSH32 0xb21fb52d5749b80f3182f8c6992236b5e5576681880914484d7f4c9b062e619e
+    PUSH1 0x20
+    PUSH1 0x00
+    LOG2
+    STOP
+
+;; releasable(address) returns (uint256)
+releasable:
+    PUSH1 0x04
+    CALLDATALOAD
+    PUSH20 0xffffffffffffffffffffffffffffffffffffffff
+    AND
+    PUSH1 0x08
+    SHL
+    PUSH2 @return_releasable
+    SWAP1
+    PUSH2 @compute_releasable
+    JUMP
+return_releasable:
+    POP
+    PUSH1 0x00
+    MSTORE
+    PUSH1 0x20
+    PUSH1 0x00
+    RETURN
+
+;; released(address) returns (uint256)
+released:
+    PUSH1 0x04
+    CALLDATALOAD
+    PUSH20 0xffffffffffffffffffffffffffffffffffffffff
+    AND
+    PUSH1 0x08
+    SHL
+    PUSH1 0x04
+    OR
+    SLOAD
+    PUSH1 0x00
+    MSTORE
+    PUSH1 0x20
+    PUSH1 0x00
+    RETURN
+
+;; compute_releasable takes the schedule base and the return address from the top of
+;; the stack, and leaves the base and the releasable amount: zero before the cliff, then
+;; amount * (now - start) / duration - released until start + duration, and
+;; amount - released after it
+compute_releasable:
+    DUP1
+    PUSH1 0x02
+    OR
+    SLOAD
+    TIMESTAMP
+    LT
+    PUSH2 @before_cliff
+    JUMPI
+    DUP1
+    SLOAD
+    DUP2
+    PUSH1 0x01
+    OR
+    SLOAD
+    DUP3
+    PUSH1 0x03
+    OR
+    SLOAD
+    DUP2
+    DUP2
+    ADD
+    TIMESTAMP
+    LT
+    ISZERO
+    PUSH2 @fully_vested
+    JUMPI
+    SWAP1
+    TIMESTAMP
+    SUB
+    DUP3
+    MUL
+    DIV
+    SWAP1
+    POP
+    PUSH2 @subtract_released
+    JUMP
+fully_vested:
+    POP
+    POP
+subtract_released:
+    DUP2
+    PUSH1 0x04
+    OR
+    SLOAD
+    SWAP1
+    SUB
+    SWAP2
+    JUMP
+before_cliff:
+    PUSH1 0x00
+    SWAP2
+    JUMP
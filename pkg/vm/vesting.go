@@ -0,0 +1,175 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// VestingContractAddress is the genesis address of the vesting contract.
+//
+// The contract holds the vested balances and exposes:
+//   - release(): sends the caller the amount vested and not yet released
+//   - releasable(address) returns (uint256): amount that can be currently released for a beneficiary
+//   - released(address) returns (uint256): amount already released to a beneficiary
+//   - event Released(address indexed beneficiary, uint256 amount)
+//
+// Nothing is vested before the cliff. After it, the amount vests linearly from start
+// to start + duration.
+const VestingContractAddress = "0x0E57ED0000000000000000000000000000000000"
+
+// schedule fields, stored at slot (beneficiary << 8) | field. The contract
+// keeps the released amount at field 4
+const (
+	vestingAmountField = iota
+	vestingStartField
+	vestingCliffField
+	vestingDurationField
+)
+
+// deployedVestingBytecode is assembled from vesting.asm
+//
+//go:embed deployed_vesting_bytecode.txt
+var deployedVestingBytecode []byte
+
+type VestingSchedule struct {
+	Beneficiary common.Address
+	Amount      *big.Int
+	// unix timestamps
+	Start uint64
+	Cliff uint64
+	// seconds from start until the full amount is vested
+	Duration uint64
+}
+
+func (s VestingSchedule) End() uint64 {
+	return s.Start + s.Duration
+}
+
+func (s VestingSchedule) Validate() error {
+	if s.Amount == nil || s.Amount.Sign() <= 0 {
+		return fmt.Errorf("vesting amount must be positive")
+	}
+	if s.Cliff < s.Start {
+		return fmt.Errorf("vesting cliff must not be before vesting start")
+	}
+	if s.Cliff > s.End() {
+		return fmt.Errorf("vesting cliff must not be after vesting end")
+	}
+	return nil
+}
+
+func vestingSlot(beneficiary common.Address, field int64) common.Hash {
+	slot := new(big.Int).Lsh(new(big.Int).SetBytes(beneficiary.Bytes()), 8)
+	return common.BigToHash(slot.Or(slot, big.NewInt(field)))
+}
+
+func deployedVestingBytes() []byte {
+	return common.FromHex(strings.TrimSpace(string(deployedVestingBytecode)))
+}
+
+// AddVestingScheduleToAllocations adds [schedule] to the genesis vesting contract, deploying
+// it into [allocs] if needed. The vested amount is locked into the contract balance
+func AddVestingScheduleToAllocations(allocs core.GenesisAlloc, schedule VestingSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	contractAddress := common.HexToAddress(VestingContractAddress)
+	account, ok := allocs[contractAddress]
+	if !ok {
+		account = core.GenesisAccount{
+			Balance: big.NewInt(0),
+			Code:    deployedVestingBytes(),
+			Nonce:   1,
+			Storage: map[common.Hash]common.Hash{},
+		}
+	}
+	if _, ok := account.Storage[vestingSlot(schedule.Beneficiary, vestingAmountField)]; ok {
+		return fmt.Errorf("address %s already has a vesting schedule", schedule.Beneficiary.Hex())
+	}
+	account.Balance = new(big.Int).Add(account.Balance, schedule.Amount)
+	account.Storage[vestingSlot(schedule.Beneficiary, vestingAmountField)] = common.BigToHash(schedule.Amount)
+	account.Storage[vestingSlot(schedule.Beneficiary, vestingStartField)] = common.BigToHash(new(big.Int).SetUint64(schedule.Start))
+	account.Storage[vestingSlot(schedule.Beneficiary, vestingCliffField)] = common.BigToHash(new(big.Int).SetUint64(schedule.Cliff))
+	account.Storage[vestingSlot(schedule.Beneficiary, vestingDurationField)] = common.BigToHash(new(big.Int).SetUint64(schedule.Duration))
+	allocs[contractAddress] = account
+	return nil
+}
+
+// RemoveVestingScheduleFromAllocations removes the vesting schedule of [beneficiary] from [allocs],
+// removing the vesting contract if no schedule is left
+func RemoveVestingScheduleFromAllocations(allocs core.GenesisAlloc, beneficiary common.Address) error {
+	contractAddress := common.HexToAddress(VestingContractAddress)
+	account, ok := allocs[contractAddress]
+	if !ok {
+		return fmt.Errorf("address %s has no vesting schedule", beneficiary.Hex())
+	}
+	amount, ok := account.Storage[vestingSlot(beneficiary, vestingAmountField)]
+	if !ok {
+		return fmt.Errorf("address %s has no vesting schedule", beneficiary.Hex())
+	}
+	account.Balance = new(big.Int).Sub(account.Balance, amount.Big())
+	for _, field := range []int64{vestingAmountField, vestingStartField, vestingCliffField, vestingDurationField} {
+		delete(account.Storage, vestingSlot(beneficiary, field))
+	}
+	if len(account.Storage) == 0 {
+		delete(allocs, contractAddress)
+		return nil
+	}
+	allocs[contractAddress] = account
+	return nil
+}
+
+// GetVestingSchedules returns the vesting schedules set on the genesis vesting contract
+// of [allocs], sorted by beneficiary. It returns nil if the contract is not present
+func GetVestingSchedules(allocs core.GenesisAlloc) []VestingSchedule {
+	account, ok := allocs[common.HexToAddress(VestingContractAddress)]
+	if !ok || !bytes.Equal(account.Code, deployedVestingBytes()) {
+		return nil
+	}
+	schedules := []VestingSchedule{}
+	for slot, value := range account.Storage {
+		slotInt := slot.Big()
+		if new(big.Int).And(slotInt, big.NewInt(0xff)).Int64() != vestingAmountField {
+			continue
+		}
+		beneficiary := common.BigToAddress(new(big.Int).Rsh(slotInt, 8))
+		schedules = append(schedules, VestingSchedule{
+			Beneficiary: beneficiary,
+			Amount:      value.Big(),
+			Start:       account.Storage[vestingSlot(beneficiary, vestingStartField)].Big().Uint64(),
+			Cliff:       account.Storage[vestingSlot(beneficiary, vestingCliffField)].Big().Uint64(),
+			Duration:    account.Storage[vestingSlot(beneficiary, vestingDurationField)].Big().Uint64(),
+		})
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return bytes.Compare(schedules[i].Beneficiary.Bytes(), schedules[j].Beneficiary.Bytes()) < 0
+	})
+	return schedules
+}
+
+// VestingClaimInstructions describes how a beneficiary can claim vested tokens
+func VestingClaimInstructions(rpcURL string) []string {
+	if rpcURL == "" {
+		rpcURL = "<rpc-url>"
+	}
+	return []string{
+		fmt.Sprintf("Vested tokens are released by the beneficiary calling release() on the vesting contract %s:", VestingContractAddress),
+		fmt.Sprintf("  cast send --rpc-url %s --private-key <beneficiary-private-key> %s \"release()\"", rpcURL, VestingContractAddress),
+		"The amount currently releasable for a beneficiary can be checked with:",
+		fmt.Sprintf("  cast call --rpc-url %s %s \"releasable(address)(uint256)\" <beneficiary-address>", rpcURL, VestingContractAddress),
+	}
+}
+
+func formatVestingTime(timestamp uint64) string {
+	return time.Unix(int64(timestamp), 0).UTC().Format(time.RFC3339)
+}
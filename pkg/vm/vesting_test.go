@@ -0,0 +1,142 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+	evm "github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVestingSchedulesAllocations(t *testing.T) {
+	require := require.New(t)
+
+	alice := common.HexToAddress("0x00000000000000000000000000000000000A11CE")
+	bob := common.HexToAddress("0x0000000000000000000000000000000000000B0B")
+	aliceSchedule := VestingSchedule{
+		Beneficiary: alice,
+		Amount:      big.NewInt(1000),
+		Start:       1000,
+		Cliff:       1100,
+		Duration:    1000,
+	}
+	bobSchedule := VestingSchedule{
+		Beneficiary: bob,
+		Amount:      big.NewInt(2000),
+		Start:       500,
+		Cliff:       500,
+		Duration:    0,
+	}
+	allocs := core.GenesisAlloc{}
+	require.Nil(GetVestingSchedules(allocs))
+
+	require.NoError(AddVestingScheduleToAllocations(allocs, bobSchedule))
+	require.NoError(AddVestingScheduleToAllocations(allocs, aliceSchedule))
+	require.Error(AddVestingScheduleToAllocations(allocs, aliceSchedule))
+
+	contractAccount := allocs[common.HexToAddress(VestingContractAddress)]
+	require.Equal(big.NewInt(3000), contractAccount.Balance)
+	require.NotEmpty(contractAccount.Code)
+	require.Equal([]VestingSchedule{bobSchedule, aliceSchedule}, GetVestingSchedules(allocs))
+
+	require.NoError(RemoveVestingScheduleFromAllocations(allocs, bob))
+	require.Error(RemoveVestingScheduleFromAllocations(allocs, bob))
+	require.Equal(big.NewInt(1000), allocs[common.HexToAddress(VestingContractAddress)].Balance)
+	require.Equal([]VestingSchedule{aliceSchedule}, GetVestingSchedules(allocs))
+
+	require.NoError(RemoveVestingScheduleFromAllocations(allocs, alice))
+	require.Empty(allocs)
+}
+
+func TestVestingScheduleValidate(t *testing.T) {
+	require := require.New(t)
+
+	schedule := VestingSchedule{Amount: big.NewInt(1), Start: 100, Cliff: 150, Duration: 100}
+	require.NoError(schedule.Validate())
+	schedule.Cliff = 50
+	require.Error(schedule.Validate())
+	schedule.Cliff = 250
+	require.Error(schedule.Validate())
+	schedule.Cliff = 150
+	schedule.Amount = big.NewInt(0)
+	require.Error(schedule.Validate())
+}
+
+// assembleVestingSource assembles the syntax described in vesting.asm
+func assembleVestingSource(source string) ([]byte, error) {
+	type instruction struct {
+		op  evm.OpCode
+		arg string
+	}
+	instructions := []instruction{}
+	labels := map[string]int{}
+	pc := 0
+	for _, line := range strings.Split(source, "\n") {
+		line, _, _ = strings.Cut(line, ";;")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case len(fields) == 1 && strings.HasSuffix(fields[0], ":"):
+			labels[strings.TrimSuffix(fields[0], ":")] = pc
+			instructions = append(instructions, instruction{op: evm.JUMPDEST})
+			pc++
+			continue
+		}
+		op := evm.StringToOp(fields[0])
+		if op.String() != fields[0] {
+			return nil, fmt.Errorf("unknown opcode %s", fields[0])
+		}
+		inst := instruction{op: op}
+		pc++
+		if op.IsPush() {
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("%s expects an argument", fields[0])
+			}
+			inst.arg = fields[1]
+			pc += int(op-evm.PUSH1) + 1
+		} else if len(fields) != 1 {
+			return nil, fmt.Errorf("%s takes no argument", fields[0])
+		}
+		instructions = append(instructions, inst)
+	}
+	code := []byte{}
+	for _, inst := range instructions {
+		code = append(code, byte(inst.op))
+		if !inst.op.IsPush() {
+			continue
+		}
+		size := int(inst.op-evm.PUSH1) + 1
+		var arg []byte
+		if label, ok := strings.CutPrefix(inst.arg, "@"); ok {
+			target, ok := labels[label]
+			if !ok {
+				return nil, fmt.Errorf("unknown label %s", label)
+			}
+			arg = big.NewInt(int64(target)).Bytes()
+		} else {
+			arg = common.FromHex(inst.arg)
+		}
+		if len(arg) > size {
+			return nil, fmt.Errorf("argument %s does not fit into %s", inst.arg, inst.op)
+		}
+		code = append(code, common.LeftPadBytes(arg, size)...)
+	}
+	return code, nil
+}
+
+func TestVestingBytecodeSource(t *testing.T) {
+	require := require.New(t)
+	source, err := os.ReadFile("vesting.asm")
+	require.NoError(err)
+	code, err := assembleVestingSource(string(source))
+	require.NoError(err)
+	require.Equal(common.Bytes2Hex(deployedVestingBytes()), common.Bytes2Hex(code), "deployed_vesting_bytecode.txt does not match vesting.asm")
+}
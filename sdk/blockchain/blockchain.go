@@ -12,9 +12,9 @@ import (
 	"os"
 	"time"
 
-	"github.com/ava-labs/avalanche-cli/pkg/evm"
-	"github.com/ava-labs/avalanche-cli/pkg/models"
-	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/sdk/evm"
+	"github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanche-cli/sdk/network"
 	"github.com/ava-labs/avalanche-cli/sdk/validatormanager"
 	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -32,6 +32,7 @@ import (
 	commonAvago "github.com/ava-labs/avalanchego/wallet/subnet/primary/common"
 	"github.com/ava-labs/subnet-evm/commontype"
 	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/params"
 )
 
@@ -135,6 +136,37 @@ type Subnet struct {
 	// BootstrapValidators are bootstrap validators that are included in the ConvertSubnetToL1Tx call
 	// that made Subnet a sovereign L1
 	BootstrapValidators []*txs.ConvertSubnetToL1Validator
+
+	// Logger is used to report non fatal conditions found while operating on the Subnet.
+	// If not set, nothing is logged
+	Logger logging.Logger
+
+	// ValidatorManagerAddress is the address of the Validator Manager Contract.
+	// If not set, the proxy predeployed on the genesis is used
+	ValidatorManagerAddress *common.Address
+
+	// SignatureAggregators is used to aggregate the signatures of the subnet conversion
+	// message, so that the peer network can be shared with other Subnets.
+	// If not set, a new signature aggregator is created
+	SignatureAggregators *interchain.SignatureAggregatorPool
+
+	// AggregationConfig sets the quorum and how validators are queried when aggregating
+	// the signatures of the subnet conversion message. Zero value means the defaults
+	AggregationConfig interchain.AggregationConfig
+}
+
+func (c *Subnet) logger() logging.Logger {
+	if c.Logger == nil {
+		return logging.NoLog{}
+	}
+	return c.Logger
+}
+
+func (c *Subnet) managerAddress() common.Address {
+	if c.ValidatorManagerAddress == nil {
+		return common.HexToAddress(validatormanager.ProxyContractAddress)
+	}
+	return *c.ValidatorManagerAddress
 }
 
 func (c *Subnet) SetParams(controlKeys []ids.ShortID, subnetAuthKeys []ids.ShortID, threshold uint32) {
@@ -342,7 +374,7 @@ func (c *Subnet) Commit(ms multisig.Multisig, wallet wallet.Wallet, waitForTxAcc
 // [convertSubnetValidators], together with an evm [ownerAddress]
 // to set as the owner of the PoA manager
 func (c *Subnet) InitializeProofOfAuthority(
-	network models.Network,
+	network network.Network,
 	privateKey string,
 	aggregatorExtraPeerEndpoints []info.Peer,
 	aggregatorAllowPrivatePeers bool,
@@ -375,7 +407,7 @@ func (c *Subnet) InitializeProofOfAuthority(
 		return err
 	}
 
-	managerAddress := common.HexToAddress(validatormanager.ProxyContractAddress)
+	managerAddress := c.managerAddress()
 	tx, _, err := validatormanager.PoAValidatorManagerInitialize(
 		c.RPC,
 		managerAddress,
@@ -387,13 +419,14 @@ func (c *Subnet) InitializeProofOfAuthority(
 		if !errors.Is(err, validatormanager.ErrAlreadyInitialized) {
 			return evm.TransactionError(tx, err, "failure initializing poa validator manager")
 		}
-		ux.Logger.PrintToUser("Warning: the PoA contract is already initialized.")
+		c.logger().Warn("the PoA contract is already initialized")
 	}
 
 	subnetConversionSignedMessage, err := validatormanager.GetPChainSubnetConversionWarpMessage(
+		c.SignatureAggregators,
 		network,
 		aggregatorLogLevel,
-		0,
+		c.AggregationConfig,
 		aggregatorAllowPrivatePeers,
 		aggregatorExtraPeerEndpoints,
 		c.SubnetID,
@@ -422,7 +455,7 @@ func (c *Subnet) InitializeProofOfAuthority(
 }
 
 func (c *Subnet) InitializeProofOfStake(
-	network models.Network,
+	network network.Network,
 	privateKey string,
 	aggregatorExtraPeerEndpoints []info.Peer,
 	aggregatorAllowPrivatePeers bool,
@@ -435,24 +468,40 @@ func (c *Subnet) InitializeProofOfStake(
 	); err != nil {
 		return err
 	}
-	managerAddress := common.HexToAddress(validatormanager.ProxyContractAddress)
-	tx, _, err := validatormanager.PoSValidatorManagerInitialize(
-		c.RPC,
-		managerAddress,
-		privateKey,
-		c.SubnetID,
-		posParams,
+	managerAddress := c.managerAddress()
+	var (
+		tx  *types.Transaction
+		err error
 	)
+	if posParams.StakingTokenAddress != "" {
+		tx, _, err = validatormanager.ERC20PoSValidatorManagerInitialize(
+			c.RPC,
+			managerAddress,
+			privateKey,
+			c.SubnetID,
+			c.BlockchainID,
+			posParams,
+		)
+	} else {
+		tx, _, err = validatormanager.PoSValidatorManagerInitialize(
+			c.RPC,
+			managerAddress,
+			privateKey,
+			c.SubnetID,
+			posParams,
+		)
+	}
 	if err != nil {
 		if !errors.Is(err, validatormanager.ErrAlreadyInitialized) {
-			return evm.TransactionError(tx, err, "failure initializing native PoS validator manager")
+			return evm.TransactionError(tx, err, "failure initializing PoS validator manager")
 		}
-		ux.Logger.PrintToUser("Warning: the PoS contract is already initialized.")
+		c.logger().Warn("the PoS contract is already initialized")
 	}
 	subnetConversionSignedMessage, err := validatormanager.GetPChainSubnetConversionWarpMessage(
+		c.SignatureAggregators,
 		network,
 		aggregatorLogLevel,
-		0,
+		c.AggregationConfig,
 		aggregatorAllowPrivatePeers,
 		aggregatorExtraPeerEndpoints,
 		c.SubnetID,
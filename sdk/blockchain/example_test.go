@@ -0,0 +1,121 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/sdk/blockchain"
+	"github.com/ava-labs/avalanche-cli/sdk/keychain"
+	"github.com/ava-labs/avalanche-cli/sdk/network"
+	"github.com/ava-labs/avalanche-cli/sdk/validatormanager"
+	"github.com/ava-labs/avalanche-cli/sdk/vm"
+	"github.com/ava-labs/avalanche-cli/sdk/wallet"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Creates a Subnet and a Subnet-EVM blockchain on Fuji, paying fees with the key
+// stored at KEY_PATH, which is also used as the Subnet control key
+func Example() {
+	allocation := core.GenesisAlloc{}
+	defaultAmount, _ := new(big.Int).SetString(vm.DefaultEvmAirdropAmount, 10)
+	allocation[common.HexToAddress("INITIAL_ALLOCATION_ADDRESS")] = core.GenesisAccount{
+		Balance: defaultAmount,
+	}
+	subnet, err := blockchain.New(&blockchain.SubnetParams{
+		SubnetEVM: &blockchain.SubnetEVMParams{
+			ChainID:     big.NewInt(123456),
+			FeeConfig:   vm.StarterFeeConfig,
+			Allocation:  allocation,
+			Precompiles: params.Precompiles{},
+			Timestamp:   utils.TimeToNewUint64(time.Now()),
+		},
+		Name: "MyBlockchain",
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fuji := network.FujiNetwork()
+	kc, err := keychain.NewKeychain(fuji, "KEY_PATH", nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	w, err := wallet.New(
+		context.Background(),
+		fuji.Endpoint,
+		kc.Keychain,
+		primary.WalletConfig{},
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	subnet.SetSubnetControlParams(kc.Addresses().List(), 1)
+	createSubnetTx, err := subnet.CreateSubnetTx(w)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	subnetID, err := subnet.Commit(*createSubnetTx, w, true)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	subnet.SetSubnetAuthKeys(kc.Addresses().List())
+	createChainTx, err := subnet.CreateBlockchainTx(w)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	blockchainID, err := subnet.Commit(*createChainTx, w, true)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("SubnetID %s BlockchainID %s\n", subnetID, blockchainID)
+}
+
+// Initializes the PoA validator manager of an L1, after its Subnet was converted with
+// ConvertSubnetToL1Tx using [bootstrapValidators]
+func ExampleSubnet_InitializeProofOfAuthority() {
+	subnetID, _ := ids.FromString("SUBNET_ID")
+	blockchainID, _ := ids.FromString("BLOCKCHAIN_ID")
+	ownerAddress := common.HexToAddress("VALIDATOR_MANAGER_OWNER_ADDRESS")
+	bootstrapValidators := []*txs.ConvertSubnetToL1Validator{}
+
+	subnet := blockchain.Subnet{
+		SubnetID:            subnetID,
+		BlockchainID:        blockchainID,
+		OwnerAddress:        &ownerAddress,
+		RPC:                 "L1_RPC_URL",
+		BootstrapValidators: bootstrapValidators,
+		Logger:              logging.NoLog{},
+	}
+	if err := subnet.InitializeProofOfAuthority(
+		network.FujiNetwork(),
+		"GENESIS_PRIVATE_KEY",
+		nil,
+		false,
+		logging.Off,
+	); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("PoA validator manager initialized at %s\n", validatormanager.ProxyContractAddress)
+}
@@ -9,6 +9,9 @@ import (
 	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
 )
 
+// network ID of the local networks started by the CLI
+const LocalNetworkID = 1337
+
 var EtnaActivationTime = map[uint32]time.Time{
 	avagoconstants.FujiID:    time.Date(2024, time.November, 25, 16, 0, 0, 0, time.UTC),
 	avagoconstants.MainnetID: time.Date(2024, time.December, 16, 17, 0, 0, 0, time.UTC),
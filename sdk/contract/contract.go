@@ -0,0 +1,500 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package contract calls methods of the L1 contracts given by their solidity
+// signature, eg "initialize((bytes32,uint64,uint8),address)"
+package contract
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/sdk/evm"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var ErrFailedReceiptStatus = fmt.Errorf("failed receipt status")
+
+func removeSurroundingParenthesis(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 0 {
+		if string(s[0]) != "(" || string(s[len(s)-1]) != ")" {
+			return "", fmt.Errorf("expected esp %q to be surrounded by parenthesis", s)
+		}
+		s = s[1 : len(s)-1]
+	}
+	return s, nil
+}
+
+func removeSurroundingBrackets(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 0 {
+		if string(s[0]) != "[" || string(s[len(s)-1]) != "]" {
+			return "", fmt.Errorf("expected esp %q to be surrounded by parenthesis", s)
+		}
+		s = s[1 : len(s)-1]
+	}
+	return s, nil
+}
+
+func getWords(s string) []string {
+	words := []string{}
+	word := ""
+	parenthesisCount := 0
+	insideBrackets := false
+	for _, rune := range s {
+		c := string(rune)
+		if parenthesisCount > 0 {
+			word += c
+			if c == "(" {
+				parenthesisCount++
+			}
+			if c == ")" {
+				parenthesisCount--
+				if parenthesisCount == 0 {
+					words = append(words, word)
+					word = ""
+				}
+			}
+			continue
+		}
+		if insideBrackets {
+			word += c
+			if c == "]" {
+				words = append(words, word)
+				word = ""
+				insideBrackets = false
+			}
+			continue
+		}
+		if c == " " || c == "," || c == "(" || c == "[" {
+			if word != "" {
+				words = append(words, word)
+				word = ""
+			}
+		}
+		if c == " " || c == "," {
+			continue
+		}
+		if c == "(" {
+			parenthesisCount++
+		}
+		if c == "[" {
+			insideBrackets = true
+		}
+		word += c
+	}
+	if word != "" {
+		words = append(words, word)
+	}
+	return words
+}
+
+func getMap(
+	types []string,
+	params interface{},
+) ([]map[string]interface{}, error) {
+	r := []map[string]interface{}{}
+	for i, t := range types {
+		var (
+			param      interface{}
+			name       string
+			structName string
+		)
+		rt := reflect.ValueOf(params)
+		if rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+		if rt.Kind() == reflect.Slice {
+			if rt.Len() != len(types) {
+				if rt.Len() == 1 {
+					return getMap(types, rt.Index(0).Interface())
+				} else {
+					return nil, fmt.Errorf(
+						"inconsistency in slice len between method esp %q and given params %#v: expected %d got %d",
+						types,
+						params,
+						len(types),
+						rt.Len(),
+					)
+				}
+			}
+			param = rt.Index(i).Interface()
+		} else if rt.Kind() == reflect.Struct {
+			if rt.NumField() < len(types) {
+				return nil, fmt.Errorf(
+					"inconsistency in struct len between method esp %q and given params %#v: expected %d got %d",
+					types,
+					params,
+					len(types),
+					rt.NumField(),
+				)
+			}
+			name = rt.Type().Field(i).Name
+			structName = rt.Type().Field(i).Type.Name()
+			param = rt.Field(i).Interface()
+		}
+		m := map[string]interface{}{}
+		switch {
+		case string(t[0]) == "(":
+			// struct type
+			var err error
+			t, err = removeSurroundingParenthesis(t)
+			if err != nil {
+				return nil, err
+			}
+			m["components"], err = getMap(getWords(t), param)
+			if err != nil {
+				return nil, err
+			}
+			if structName != "" {
+				m["internalType"] = "struct " + structName
+			} else {
+				m["internalType"] = "tuple"
+			}
+			m["type"] = "tuple"
+			m["name"] = name
+		case string(t[0]) == "[":
+			var err error
+			t, err = removeSurroundingBrackets(t)
+			if err != nil {
+				return nil, err
+			}
+			if string(t[0]) == "(" {
+				t, err = removeSurroundingParenthesis(t)
+				if err != nil {
+					return nil, err
+				}
+				rt := reflect.ValueOf(param)
+				if rt.Kind() != reflect.Slice {
+					return nil, fmt.Errorf("expected param for field %d of esp %q to be an slice", i, types)
+				}
+				param = reflect.Zero(rt.Type().Elem()).Interface()
+				structName = rt.Type().Elem().Name()
+				m["components"], err = getMap(getWords(t), param)
+				if err != nil {
+					return nil, err
+				}
+				if structName != "" {
+					m["internalType"] = "struct " + structName + "[]"
+				} else {
+					m["internalType"] = "tuple[]"
+				}
+				m["type"] = "tuple[]"
+				m["name"] = name
+			} else {
+				m["internalType"] = fmt.Sprintf("%s[]", t)
+				m["type"] = fmt.Sprintf("%s[]", t)
+				m["name"] = name
+			}
+		default:
+			m["internalType"] = t
+			m["type"] = t
+			m["name"] = name
+		}
+		r = append(r, m)
+	}
+	return r, nil
+}
+
+// ParseSpec returns the method name and the json ABI of the solidity method signature
+// [esp], eg "transfer(address,uint256)->(bool)". Struct params are given by tuples, and
+// the field names are taken from [params]
+func ParseSpec(
+	esp string,
+	indexedFields []int,
+	constructor bool,
+	event bool,
+	paid bool,
+	view bool,
+	params ...interface{},
+) (string, string, error) {
+	index := strings.Index(esp, "(")
+	if index == -1 {
+		return esp, "", nil
+	}
+	name := esp[:index]
+	types := esp[index:]
+	inputs := ""
+	outputs := ""
+	index = strings.Index(types, "->")
+	if index == -1 {
+		inputs = types
+	} else {
+		inputs = types[:index]
+		outputs = types[index+2:]
+	}
+	var err error
+	inputs, err = removeSurroundingParenthesis(inputs)
+	if err != nil {
+		return "", "", err
+	}
+	outputs, err = removeSurroundingParenthesis(outputs)
+	if err != nil {
+		return "", "", err
+	}
+	inputTypes := getWords(inputs)
+	outputTypes := getWords(outputs)
+	inputsMaps, err := getMap(inputTypes, params)
+	if err != nil {
+		return "", "", err
+	}
+	outputsMaps, err := getMap(outputTypes, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if event {
+		for i := range inputsMaps {
+			if slices.Contains(indexedFields, i) {
+				inputsMaps[i]["indexed"] = true
+			}
+		}
+	}
+	abiMap := []map[string]interface{}{
+		{
+			"inputs": inputsMaps,
+		},
+	}
+	switch {
+	case paid:
+		abiMap[0]["stateMutability"] = "payable"
+	case view:
+		abiMap[0]["stateMutability"] = "view"
+	default:
+		abiMap[0]["stateMutability"] = "nonpayable"
+	}
+	switch {
+	case constructor:
+		abiMap[0]["type"] = "constructor"
+	case event:
+		abiMap[0]["type"] = "event"
+		abiMap[0]["name"] = name
+		delete(abiMap[0], "stateMutability")
+	default:
+		abiMap[0]["type"] = "function"
+		abiMap[0]["outputs"] = outputsMaps
+		abiMap[0]["name"] = name
+	}
+	abiBytes, err := json.MarshalIndent(abiMap, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+	return name, string(abiBytes), nil
+}
+
+// TxToMethod gets method name and types from [methodSpec], then calls it
+// at the smart contract [contractAddress] with the given [params].
+// also sends [payment] tokens to it.
+// On failure, the error is matched against [errorSignatureToError] using the
+// call trace, if the debug API is enabled on the node
+func TxToMethod(
+	rpcURL string,
+	privateKey string,
+	contractAddress common.Address,
+	payment *big.Int,
+	description string,
+	errorSignatureToError map[string]error,
+	methodSpec string,
+	params ...interface{},
+) (*types.Transaction, *types.Receipt, error) {
+	methodName, methodABI, err := ParseSpec(methodSpec, nil, false, false, payment != nil, false, params...)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata := &bind.MetaData{
+		ABI: methodABI,
+	}
+	abi, err := metadata.GetAbi()
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer client.Close()
+	contract := bind.NewBoundContract(contractAddress, *abi, client, client, client)
+	txOpts, err := evm.GetTxOptsWithSigner(client, privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	txOpts.Value = payment
+	tx, err := contract.Transact(txOpts, methodName, params...)
+	if err != nil {
+		toTrace, traceErr := getCallToTrace(privateKey, contractAddress, payment, methodSpec, params...)
+		if traceErr != nil {
+			return tx, nil, fmt.Errorf("%s: %w", description, err)
+		}
+		rpcClient, traceErr := evm.GetRPCClient(rpcURL)
+		if traceErr != nil {
+			return tx, nil, fmt.Errorf("%s: %w", description, err)
+		}
+		defer rpcClient.Close()
+		trace, traceErr := evm.DebugTraceCall(rpcClient, toTrace)
+		if traceErr != nil {
+			return tx, nil, fmt.Errorf("%s: %w", description, err)
+		}
+		if errorFromSignature, _ := evm.GetErrorFromTrace(trace, errorSignatureToError); errorFromSignature != nil {
+			return tx, nil, errorFromSignature
+		}
+		return tx, nil, fmt.Errorf("%s: %w", description, err)
+	}
+	receipt, success, err := evm.WaitForTransaction(client, tx)
+	if err != nil {
+		return tx, nil, err
+	} else if !success {
+		return handleFailedReceiptStatus(
+			rpcURL,
+			description,
+			errorSignatureToError,
+			tx,
+			receipt,
+		)
+	}
+	return tx, receipt, nil
+}
+
+// TxToMethodWithWarpMessage gets method name and types from [methodSpec], then calls it
+// at the smart contract [contractAddress] with the given [params].
+// sends [warpMessage] on the same call, whose signature is
+// going to be verified previously to pass it to the method.
+// also sends [payment] tokens to it
+func TxToMethodWithWarpMessage(
+	rpcURL string,
+	privateKey string,
+	contractAddress common.Address,
+	warpMessage *avalancheWarp.Message,
+	payment *big.Int,
+	description string,
+	errorSignatureToError map[string]error,
+	methodSpec string,
+	params ...interface{},
+) (*types.Transaction, *types.Receipt, error) {
+	methodName, methodABI, err := ParseSpec(methodSpec, nil, false, false, false, false, params...)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata := &bind.MetaData{
+		ABI: methodABI,
+	}
+	abi, err := metadata.GetAbi()
+	if err != nil {
+		return nil, nil, err
+	}
+	callData, err := abi.Pack(methodName, params...)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer client.Close()
+	tx, err := evm.GetSignedTxToMethodWithWarpMessage(
+		client,
+		privateKey,
+		warpMessage,
+		contractAddress,
+		callData,
+		payment,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := evm.SendTransaction(client, tx); err != nil {
+		return tx, nil, err
+	}
+	receipt, success, err := evm.WaitForTransaction(client, tx)
+	if err != nil {
+		return tx, receipt, err
+	} else if !success {
+		return handleFailedReceiptStatus(
+			rpcURL,
+			description,
+			errorSignatureToError,
+			tx,
+			receipt,
+		)
+	}
+	return tx, receipt, nil
+}
+
+// handleFailedReceiptStatus returns the error of the failed [tx], as matched on its
+// trace against [errorSignatureToError], or ErrFailedReceiptStatus
+func handleFailedReceiptStatus(
+	rpcURL string,
+	description string,
+	errorSignatureToError map[string]error,
+	tx *types.Transaction,
+	receipt *types.Receipt,
+) (*types.Transaction, *types.Receipt, error) {
+	client, err := evm.GetRPCClient(rpcURL)
+	if err != nil {
+		return tx, receipt, fmt.Errorf("%s: %w", description, ErrFailedReceiptStatus)
+	}
+	defer client.Close()
+	trace, err := evm.DebugTraceTransaction(client, tx.Hash().String())
+	if err != nil {
+		return tx, receipt, fmt.Errorf("%s: %w (%w)", description, ErrFailedReceiptStatus, err)
+	}
+	errorFromSignature, err := evm.GetErrorFromTrace(trace, errorSignatureToError)
+	if errorFromSignature != nil {
+		return tx, receipt, errorFromSignature
+	}
+	if err != nil && !errors.Is(err, evm.ErrUnknownErrorSelector) {
+		return tx, receipt, fmt.Errorf("%s: %w (%w)", description, ErrFailedReceiptStatus, err)
+	}
+	return tx, receipt, fmt.Errorf("%s: %w", description, ErrFailedReceiptStatus)
+}
+
+// getCallToTrace returns the debug_traceCall argument for calling [methodSpec] at
+// [contractAddress] with [params] and [payment], from the address of [privateKey]
+func getCallToTrace(
+	privateKey string,
+	contractAddress common.Address,
+	payment *big.Int,
+	methodSpec string,
+	params ...interface{},
+) (map[string]string, error) {
+	methodName, methodABI, err := ParseSpec(methodSpec, nil, false, false, false, false, params...)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &bind.MetaData{
+		ABI: methodABI,
+	}
+	abi, err := metadata.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	callData, err := abi.Pack(methodName, params...)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := crypto.HexToECDSA(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	from := crypto.PubkeyToAddress(pk.PublicKey)
+	data := map[string]string{
+		"from":  from.Hex(),
+		"to":    contractAddress.Hex(),
+		"input": "0x" + hex.EncodeToString(callData),
+	}
+	if payment != nil {
+		hexBytes, _ := hexutil.Big(*payment).MarshalText()
+		data["value"] = string(hexBytes)
+	}
+	return data, nil
+}
@@ -0,0 +1,404 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package evm has the EVM RPC helpers used by the SDK to operate on the L1 contracts.
+// Errors are returned to the caller, nothing is printed
+package evm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/sdk/utils"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ava-labs/subnet-evm/predicate"
+	"github.com/ava-labs/subnet-evm/rpc"
+	subnetEvmUtils "github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	BaseFeeFactor        = 2
+	MaxPriorityFeePerGas = 2500000000 // 2.5 gwei
+	// gas limit used when the one of a tx with a warp message can not be estimated
+	defaultWarpTxGasLimit = 2_000_000
+)
+
+var (
+	ErrUnknownErrorSelector = fmt.Errorf("unknown error selector")
+
+	// RPCHeaders gives the headers to send on the connections to [rpcURL], eg an
+	// authorization token. By default no headers are added
+	RPCHeaders = func(rpcURL string) http.Header {
+		return nil
+	}
+)
+
+// DialRPCContext connects a raw RPC client to [rawURL], either HTTP or WebSocket,
+// sending the headers given by [RPCHeaders]
+func DialRPCContext(ctx context.Context, rawURL string) (*rpc.Client, error) {
+	return rpc.DialOptions(ctx, rawURL, rpc.WithHeaders(RPCHeaders(rawURL)))
+}
+
+// GetClient connects to the EVM RPC at [rpcURL]
+func GetClient(rpcURL string) (ethclient.Client, error) {
+	client, err := GetRPCClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// GetRPCClient connects a raw RPC client to [rpcURL]
+func GetRPCClient(rpcURL string) (*rpc.Client, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	client, err := DialRPCContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failure connecting to %s: %w", rpcURL, err)
+	}
+	return client, nil
+}
+
+func GetChainID(client ethclient.Client) (*big.Int, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failure getting chain id: %w", err)
+	}
+	return chainID, nil
+}
+
+func GetTxOptsWithSigner(
+	client ethclient.Client,
+	privateKeyStr string,
+) (*bind.TransactOpts, error) {
+	privateKey, err := crypto.HexToECDSA(privateKeyStr)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := GetChainID(client)
+	if err != nil {
+		return nil, fmt.Errorf("failure generating signer: %w", err)
+	}
+	return bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+}
+
+// CalculateTxParams returns the gasFeeCap, gasTipCap, and nonce to be used when
+// constructing a transaction from [address]
+func CalculateTxParams(
+	client ethclient.Client,
+	address common.Address,
+) (*big.Int, *big.Int, uint64, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	baseFee, err := client.EstimateBaseFee(ctx)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failure estimating base fee: %w", err)
+	}
+	gasTipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failure obtaining gas tip cap: %w", err)
+	}
+	nonce, err := client.NonceAt(ctx, address, nil)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failure obtaining nonce for %s: %w", address, err)
+	}
+	gasFeeCap := baseFee.Mul(baseFee, big.NewInt(BaseFeeFactor))
+	gasFeeCap.Add(gasFeeCap, big.NewInt(MaxPriorityFeePerGas))
+	return gasFeeCap, gasTipCap, nonce, nil
+}
+
+// GetSignedTxToMethodWithWarpMessage returns a tx calling [contract] with [callData] and
+// [value], signed by [privateKeyStr], that includes [warpMessage] on its access list
+func GetSignedTxToMethodWithWarpMessage(
+	client ethclient.Client,
+	privateKeyStr string,
+	warpMessage *avalancheWarp.Message,
+	contract common.Address,
+	callData []byte,
+	value *big.Int,
+) (*types.Transaction, error) {
+	privateKey, err := crypto.HexToECDSA(privateKeyStr)
+	if err != nil {
+		return nil, err
+	}
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	gasFeeCap, gasTipCap, nonce, err := CalculateTxParams(client, address)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := GetChainID(client)
+	if err != nil {
+		return nil, err
+	}
+	accessList := types.AccessList{
+		types.AccessTuple{
+			Address:     warp.ContractAddress,
+			StorageKeys: subnetEvmUtils.BytesToHashSlice(predicate.PackPredicate(warpMessage.Bytes())),
+		},
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	gasLimit, err := client.EstimateGas(ctx, interfaces.CallMsg{
+		From:       address,
+		To:         &contract,
+		GasTipCap:  gasTipCap,
+		GasFeeCap:  gasFeeCap,
+		Value:      value,
+		Data:       callData,
+		AccessList: accessList,
+	})
+	if err != nil {
+		// the tx is probably going to fail, issue it anyway so the
+		// failure can be traced
+		gasLimit = defaultWarpTxGasLimit
+	}
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:    chainID,
+		Nonce:      nonce,
+		To:         &contract,
+		Gas:        gasLimit,
+		GasFeeCap:  gasFeeCap,
+		GasTipCap:  gasTipCap,
+		Value:      value,
+		Data:       callData,
+		AccessList: accessList,
+	})
+	txSigner := types.LatestSignerForChainID(chainID)
+	return types.SignTx(tx, txSigner, privateKey)
+}
+
+func SendTransaction(
+	client ethclient.Client,
+	tx *types.Transaction,
+) error {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("failure sending transaction %s: %w", tx.Hash(), err)
+	}
+	return nil
+}
+
+// WaitForTransaction waits for [tx] to be accepted, and returns its receipt
+// together with a flag that is true if the tx succeeded
+func WaitForTransaction(
+	client ethclient.Client,
+	tx *types.Transaction,
+) (*types.Receipt, bool, error) {
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failure waiting for tx %s: %w", tx.Hash(), err)
+	}
+	return receipt, receipt.Status == types.ReceiptStatusSuccessful, nil
+}
+
+// SetupProposerVM issues the txs needed to activate the ProposerVM fork on the
+// chain at [endpoint], paid by [privKeyStr]
+func SetupProposerVM(
+	endpoint string,
+	privKeyStr string,
+) error {
+	privKey, err := crypto.HexToECDSA(privKeyStr)
+	if err != nil {
+		return err
+	}
+	client, err := GetClient(endpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	chainID, err := GetChainID(client)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	if err := IssueTxsToActivateProposerVMFork(ctx, client, chainID, privKey); err != nil {
+		return fmt.Errorf("failure issuing txs to activate proposer VM fork on %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// IssueTxsToActivateProposerVMFork issues transactions at the current
+// timestamp, which should be after the ProposerVM activation time (aka
+// ApricotPhase4). This should generate a PostForkBlock because its parent block
+// (genesis) has a timestamp (0) that is greater than or equal to the fork
+// activation time of 0. Therefore, subsequent blocks should be built with
+// BuildBlockWithContext.
+func IssueTxsToActivateProposerVMFork(
+	ctx context.Context,
+	client ethclient.Client,
+	chainID *big.Int,
+	fundedKey *ecdsa.PrivateKey,
+) error {
+	const numTriggerTxs = 2 // Number of txs needed to activate the proposer VM fork
+	addr := crypto.PubkeyToAddress(fundedKey.PublicKey)
+	gasPrice := big.NewInt(params.MinGasPrice)
+	txSigner := types.LatestSignerForChainID(chainID)
+	for i := 0; i < numTriggerTxs; i++ {
+		prevBlockNumber, err := client.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		nonce, err := client.NonceAt(ctx, addr, nil)
+		if err != nil {
+			return err
+		}
+		tx := types.NewTransaction(
+			nonce, addr, common.Big1, params.TxGas, gasPrice, nil)
+		triggerTx, err := types.SignTx(tx, txSigner, fundedKey)
+		if err != nil {
+			return err
+		}
+		if err := client.SendTransaction(ctx, triggerTx); err != nil {
+			return err
+		}
+		if err := WaitForNewBlock(ctx, client, prevBlockNumber, 0, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WaitForNewBlock waits up to [totalDuration] for a block after [prevBlockNumber],
+// checking every [stepDuration]. Zero values mean 10 seconds and 1 second respectively
+func WaitForNewBlock(
+	ctx context.Context,
+	client ethclient.Client,
+	prevBlockNumber uint64,
+	totalDuration time.Duration,
+	stepDuration time.Duration,
+) error {
+	if stepDuration == 0 {
+		stepDuration = 1 * time.Second
+	}
+	if totalDuration == 0 {
+		totalDuration = 10 * time.Second
+	}
+	steps := totalDuration / stepDuration
+	for seconds := 0; seconds < int(steps); seconds++ {
+		blockNumber, err := client.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		if blockNumber > prevBlockNumber {
+			return nil
+		}
+		time.Sleep(stepDuration)
+	}
+	return fmt.Errorf("new block not produced in %f seconds", totalDuration.Seconds())
+}
+
+// DebugTraceTransaction returns the call trace of [txID]. Needs the debug API to be
+// enabled on the node
+func DebugTraceTransaction(
+	client *rpc.Client,
+	txID string,
+) (map[string]interface{}, error) {
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	var trace map[string]interface{}
+	if err := client.CallContext(
+		ctx,
+		&trace,
+		"debug_traceTransaction",
+		txID,
+		map[string]string{"tracer": "callTracer"},
+	); err != nil {
+		return nil, fmt.Errorf("failure tracing tx %s: %w", txID, err)
+	}
+	return trace, nil
+}
+
+// DebugTraceCall returns the call trace of executing [toTrace] on top of the latest
+// state. Needs the debug API to be enabled on the node
+func DebugTraceCall(
+	client *rpc.Client,
+	toTrace map[string]string,
+) (map[string]interface{}, error) {
+	ctx, cancel := utils.GetAPILargeContext()
+	defer cancel()
+	var trace map[string]interface{}
+	if err := client.CallContext(
+		ctx,
+		&trace,
+		"debug_traceCall",
+		toTrace,
+		"latest",
+		map[string]interface{}{
+			"tracer": "callTracer",
+			"tracerConfig": map[string]interface{}{
+				"onlyTopCall": false,
+			},
+		},
+	); err != nil {
+		return nil, fmt.Errorf("failure tracing call: %w", err)
+	}
+	return trace, nil
+}
+
+func GetFunctionSelector(functionSignature string) string {
+	return "0x" + hex.EncodeToString(crypto.Keccak256([]byte(functionSignature))[:4])
+}
+
+// GetErrorFromTrace matches the error selector on the output of [trace] against the
+// signatures of [functionSignatureToError], and returns the associated error. The second
+// error is set when there is no match
+func GetErrorFromTrace(
+	trace map[string]interface{},
+	functionSignatureToError map[string]error,
+) (error, error) {
+	traceOutputI, ok := trace["output"]
+	if !ok {
+		return nil, fmt.Errorf("trace does not contain output field")
+	}
+	traceOutput, ok := traceOutputI.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected type string for trace output, got %T", traceOutputI)
+	}
+	traceOutputBytes, err := hex.DecodeString(strings.TrimPrefix(traceOutput, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failure decoding trace output: %w", err)
+	}
+	if len(traceOutputBytes) < 4 {
+		return nil, fmt.Errorf("less than 4 bytes in trace output")
+	}
+	traceErrorSelector := "0x" + hex.EncodeToString(traceOutputBytes[:4])
+	for errorSignature, err := range functionSignatureToError {
+		errorSelector := GetFunctionSelector(errorSignature)
+		if traceErrorSelector == errorSelector {
+			return err, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownErrorSelector, traceErrorSelector)
+}
+
+// TransactionError wraps [err] with [msg] and the hash of [tx], if it was submitted
+func TransactionError(tx *types.Transaction, err error, msg string, args ...interface{}) error {
+	msgSuffix := ": %w"
+	if tx != nil {
+		msgSuffix += fmt.Sprintf(" (txHash=%s)", tx.Hash().String())
+	} else {
+		msgSuffix += " (tx failed to be submitted)"
+	}
+	args = append(args, err)
+	return fmt.Errorf(msg+msgSuffix, args...)
+}
@@ -0,0 +1,392 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/icm-services/peers"
+)
+
+type RetryStrategy string
+
+const (
+	// wait the same delay before each retry
+	RetryStrategyFixed RetryStrategy = "fixed"
+	// double the delay on each retry
+	RetryStrategyExponential RetryStrategy = "exponential"
+)
+
+// outcome of the signature request made to a validator
+const (
+	ValidatorSignatureStatusSigned       = "Signed"
+	ValidatorSignatureStatusResponded    = "Responded"
+	ValidatorSignatureStatusTimedOut     = "TimedOut"
+	ValidatorSignatureStatusFailed       = "Failed"
+	ValidatorSignatureStatusSendFailed   = "SendFailed"
+	ValidatorSignatureStatusNotRequested = "NotRequested"
+)
+
+// AggregationConfig configures how validator signatures are aggregated
+type AggregationConfig struct {
+	// percentage of the validators weight that must sign. 0 means DefaultQuorumPercentage
+	QuorumPercentage uint64
+	// max time to wait for the signature of a validator. 0 means the p2p network timeout,
+	// which is also the upper bound
+	ValidatorTimeout time.Duration
+	// max number of validators queried at the same time. 0 means all of them
+	MaxParallelRequests int
+	// number of aggregation rounds done before failing. On each round, all
+	// validators that did not sign yet are queried several times. 0 means 1
+	MaxAttempts int
+	// wait before the first retry round, and how it grows on further ones.
+	// Empty strategy means RetryStrategyFixed
+	RetryDelay    time.Duration
+	RetryStrategy RetryStrategy
+	// if set, it is called after each aggregation with the outcome of each validator
+	ResultsHandler func([]ValidatorSignatureResult)
+}
+
+var DefaultAggregationConfig = AggregationConfig{
+	QuorumPercentage: DefaultQuorumPercentage,
+	MaxAttempts:      1,
+	RetryDelay:       5 * time.Second,
+	RetryStrategy:    RetryStrategyFixed,
+}
+
+// Validate checks that the values of [c] are in range. Zero values are valid,
+// and stand for the defaults
+func (c AggregationConfig) Validate() error {
+	if c.QuorumPercentage > 100 {
+		return fmt.Errorf("quorum percentage cannot be greater than 100")
+	}
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("aggregation max attempts cannot be negative")
+	}
+	if c.MaxParallelRequests < 0 {
+		return fmt.Errorf("aggregation parallel requests cannot be negative")
+	}
+	switch c.RetryStrategy {
+	case "", RetryStrategyFixed, RetryStrategyExponential:
+	default:
+		return fmt.Errorf("invalid retry strategy %q: must be one of [%s, %s]", c.RetryStrategy, RetryStrategyFixed, RetryStrategyExponential)
+	}
+	return nil
+}
+
+// withDefaults returns [c] with its zero values replaced by the defaults
+func (c AggregationConfig) withDefaults() AggregationConfig {
+	if c.QuorumPercentage == 0 {
+		c.QuorumPercentage = DefaultQuorumPercentage
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = DefaultAggregationConfig.MaxAttempts
+	}
+	if c.RetryStrategy == "" {
+		c.RetryStrategy = DefaultAggregationConfig.RetryStrategy
+	}
+	return c
+}
+
+// retryDelay returns how long to wait after the failed aggregation [attempt]
+func (c AggregationConfig) retryDelay(attempt int) time.Duration {
+	if c.RetryStrategy != RetryStrategyExponential {
+		return c.RetryDelay
+	}
+	return c.RetryDelay * time.Duration(1<<(attempt-1))
+}
+
+// ValidatorSignatureResult is the outcome of the signature request made to a validator
+type ValidatorSignatureResult struct {
+	NodeID ids.NodeID
+	Weight uint64
+	Status string
+	// number of signature requests sent to the validator
+	Requests int
+	// time it took the last request to be answered or to time out
+	Latency time.Duration
+}
+
+// instrumentedNetwork wraps the p2p network used by the aggregator to limit how many
+// validators are queried at the same time, to time out slow validators, and to
+// keep track of the outcome of each validator request
+type instrumentedNetwork struct {
+	peers.AppRequestNetwork
+	config AggregationConfig
+
+	lock       sync.Mutex
+	validators *peers.ConnectedCanonicalValidators
+	results    map[ids.NodeID]*validatorRequests
+	// app requests to register on the network once the request is sent
+	appRequests map[ids.NodeID]ids.RequestID
+	request     *requestRound
+}
+
+type validatorRequests struct {
+	requests int
+	sentAt   time.Time
+	latency  time.Duration
+	status   string
+}
+
+// requestRound tracks a signature request sent to a set of validators
+type requestRound struct {
+	requestID uint32
+	inbound   chan message.InboundMessage
+	outbound  chan message.InboundMessage
+	done      chan struct{}
+	msg       message.OutboundMessage
+	subnetID  ids.ID
+	allower   subnets.Allower
+	pending   []ids.NodeID
+	resolved  set.Set[ids.NodeID]
+	stopped   bool
+}
+
+func newInstrumentedNetwork(network peers.AppRequestNetwork) *instrumentedNetwork {
+	return &instrumentedNetwork{
+		AppRequestNetwork: network,
+		config:            DefaultAggregationConfig,
+		results:           map[ids.NodeID]*validatorRequests{},
+		appRequests:       map[ids.NodeID]ids.RequestID{},
+	}
+}
+
+func (n *instrumentedNetwork) ConnectToCanonicalValidators(subnetID ids.ID) (*peers.ConnectedCanonicalValidators, error) {
+	validators, err := n.AppRequestNetwork.ConnectToCanonicalValidators(subnetID)
+	if err != nil {
+		return nil, err
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.validators = validators
+	return validators, nil
+}
+
+func (n *instrumentedNetwork) RegisterAppRequest(reqID ids.RequestID) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	// the request timeout starts when the request is effectively sent
+	n.appRequests[reqID.NodeID] = reqID
+}
+
+func (n *instrumentedNetwork) RegisterRequestID(requestID uint32, numExpectedResponses int) chan message.InboundMessage {
+	inbound := n.AppRequestNetwork.RegisterRequestID(requestID, numExpectedResponses)
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.stopRound()
+	n.request = &requestRound{
+		requestID: requestID,
+		inbound:   inbound,
+		outbound:  make(chan message.InboundMessage, numExpectedResponses),
+		done:      make(chan struct{}),
+		resolved:  set.NewSet[ids.NodeID](numExpectedResponses),
+	}
+	return n.request.outbound
+}
+
+// Send queries [nodeIDs], at most MaxParallelRequests at a time. All of them are
+// reported as sent: the ones that fail to be sent get an error response instead
+func (n *instrumentedNetwork) Send(
+	msg message.OutboundMessage,
+	nodeIDs set.Set[ids.NodeID],
+	subnetID ids.ID,
+	allower subnets.Allower,
+) set.Set[ids.NodeID] {
+	n.lock.Lock()
+	round := n.request
+	if round == nil || round.stopped {
+		n.lock.Unlock()
+		return n.AppRequestNetwork.Send(msg, nodeIDs, subnetID, allower)
+	}
+	round.msg = msg
+	round.subnetID = subnetID
+	round.allower = allower
+	round.pending = nodeIDs.List()
+	parallelRequests := n.config.MaxParallelRequests
+	n.lock.Unlock()
+	go n.forward(round)
+	if parallelRequests == 0 || parallelRequests > nodeIDs.Len() {
+		parallelRequests = nodeIDs.Len()
+	}
+	for i := 0; i < parallelRequests; i++ {
+		n.sendNext(round)
+	}
+	return nodeIDs
+}
+
+// forward passes the responses of the validators to the aggregator
+func (n *instrumentedNetwork) forward(round *requestRound) {
+	for {
+		select {
+		case <-round.done:
+			return
+		case response, ok := <-round.inbound:
+			if !ok {
+				return
+			}
+			status := ValidatorSignatureStatusResponded
+			if response.Op() == message.AppErrorOp {
+				status = appErrorStatus(response)
+			}
+			n.resolve(round, response.NodeID(), status, response)
+		}
+	}
+}
+
+// sendNext sends the request to the next pending validator of [round]
+func (n *instrumentedNetwork) sendNext(round *requestRound) {
+	n.lock.Lock()
+	if round.stopped || len(round.pending) == 0 {
+		n.lock.Unlock()
+		return
+	}
+	nodeID := round.pending[0]
+	round.pending = round.pending[1:]
+	reqID, registered := n.appRequests[nodeID]
+	delete(n.appRequests, nodeID)
+	result, ok := n.results[nodeID]
+	if !ok {
+		result = &validatorRequests{}
+		n.results[nodeID] = result
+	}
+	result.requests++
+	result.sentAt = time.Now()
+	validatorTimeout := n.config.ValidatorTimeout
+	n.lock.Unlock()
+
+	if !registered {
+		reqID = ids.RequestID{
+			NodeID:    nodeID,
+			RequestID: round.requestID,
+			Op:        byte(message.AppResponseOp),
+		}
+	} else {
+		n.AppRequestNetwork.RegisterAppRequest(reqID)
+	}
+	sentTo := n.AppRequestNetwork.Send(round.msg, set.Of(nodeID), round.subnetID, round.allower)
+	if !sentTo.Contains(nodeID) {
+		n.resolve(round, nodeID, ValidatorSignatureStatusSendFailed, requestFailure(reqID))
+		return
+	}
+	if validatorTimeout > 0 {
+		time.AfterFunc(validatorTimeout, func() {
+			n.resolve(round, nodeID, ValidatorSignatureStatusTimedOut, requestFailure(reqID))
+		})
+	}
+}
+
+// resolve records the outcome of the request to [nodeID] and passes [response]
+// to the aggregator, if it is the first one for [nodeID] on [round]
+func (n *instrumentedNetwork) resolve(
+	round *requestRound,
+	nodeID ids.NodeID,
+	status string,
+	response message.InboundMessage,
+) {
+	n.lock.Lock()
+	if round.stopped || round.resolved.Contains(nodeID) {
+		n.lock.Unlock()
+		response.OnFinishedHandling()
+		return
+	}
+	round.resolved.Add(nodeID)
+	if result, ok := n.results[nodeID]; ok {
+		result.status = status
+		result.latency = time.Since(result.sentAt)
+	}
+	n.lock.Unlock()
+	round.outbound <- response
+	n.sendNext(round)
+}
+
+// stopRound stops sending requests for the current round. Must be called with the lock held
+func (n *instrumentedNetwork) stopRound() {
+	if n.request != nil && !n.request.stopped {
+		n.request.stopped = true
+		close(n.request.done)
+	}
+}
+
+// setConfig sets how the validators are queried on the next aggregations. The network
+// can be shared by aggregators with different settings
+func (n *instrumentedNetwork) setConfig(config AggregationConfig) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.config = config
+}
+
+// finish stops the requests of the current aggregation
+func (n *instrumentedNetwork) finish() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.stopRound()
+	n.appRequests = map[ids.NodeID]ids.RequestID{}
+}
+
+// getResults returns the outcome of each validator request. Validators that contributed
+// to [signedMessage], if given, are reported as signed
+func (n *instrumentedNetwork) getResults(signedMessage *warp.Message) []ValidatorSignatureResult {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.validators == nil {
+		return nil
+	}
+	signers := set.NewBits()
+	if signedMessage != nil {
+		if signature, ok := signedMessage.Signature.(*warp.BitSetSignature); ok {
+			signers = set.BitsFromBytes(signature.Signers)
+		}
+	}
+	results := []ValidatorSignatureResult{}
+	for i, validator := range n.validators.ValidatorSet {
+		for _, nodeID := range validator.NodeIDs {
+			result := ValidatorSignatureResult{
+				NodeID: nodeID,
+				Weight: validator.Weight,
+				Status: ValidatorSignatureStatusNotRequested,
+			}
+			if requests, ok := n.results[nodeID]; ok {
+				result.Requests = requests.requests
+				result.Latency = requests.latency
+				if requests.status != "" {
+					result.Status = requests.status
+				}
+			}
+			if signers.Contains(i) {
+				result.Status = ValidatorSignatureStatusSigned
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// appErrorStatus tells apart the app errors due to the request timing out
+// from the ones sent by the validator
+func appErrorStatus(response message.InboundMessage) string {
+	appError, ok := response.Message().(*p2p.AppError)
+	if ok && appError.ErrorCode == common.ErrTimeout.Code {
+		return ValidatorSignatureStatusTimedOut
+	}
+	return ValidatorSignatureStatusFailed
+}
+
+func requestFailure(reqID ids.RequestID) message.InboundMessage {
+	return message.InboundAppError(
+		reqID.NodeID,
+		reqID.ChainID,
+		reqID.RequestID,
+		common.ErrTimeout.Code,
+		common.ErrTimeout.Message,
+	)
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/icm-services/peers"
+	"github.com/stretchr/testify/require"
+)
+
+// silentNetwork accepts all requests but never answers them
+type silentNetwork struct {
+	peers.AppRequestNetwork
+	lock   sync.Mutex
+	sentTo []ids.NodeID
+	failTo set.Set[ids.NodeID]
+}
+
+func (*silentNetwork) RegisterAppRequest(ids.RequestID) {}
+
+func (*silentNetwork) RegisterRequestID(_ uint32, numExpectedResponses int) chan message.InboundMessage {
+	return make(chan message.InboundMessage, numExpectedResponses)
+}
+
+func (n *silentNetwork) Send(
+	_ message.OutboundMessage,
+	nodeIDs set.Set[ids.NodeID],
+	_ ids.ID,
+	_ subnets.Allower,
+) set.Set[ids.NodeID] {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	sentTo := set.NewSet[ids.NodeID](nodeIDs.Len())
+	for nodeID := range nodeIDs {
+		n.sentTo = append(n.sentTo, nodeID)
+		if !n.failTo.Contains(nodeID) {
+			sentTo.Add(nodeID)
+		}
+	}
+	return sentTo
+}
+
+func TestAggregationConfigValidate(t *testing.T) {
+	require := require.New(t)
+	require.NoError(AggregationConfig{}.Validate())
+	require.Error(AggregationConfig{QuorumPercentage: 101}.Validate())
+	require.Error(AggregationConfig{MaxAttempts: -1}.Validate())
+	require.Error(AggregationConfig{RetryStrategy: "linear"}.Validate())
+	config := AggregationConfig{QuorumPercentage: 80}.withDefaults()
+	require.Equal(uint64(80), config.QuorumPercentage)
+	require.Equal(DefaultAggregationConfig.MaxAttempts, config.MaxAttempts)
+	require.Equal(RetryStrategyFixed, config.RetryStrategy)
+	require.Equal(DefaultQuorumPercentage, AggregationConfig{}.withDefaults().QuorumPercentage)
+}
+
+func TestAppErrorStatus(t *testing.T) {
+	require := require.New(t)
+	nodeID := ids.GenerateTestNodeID()
+	timeout := requestFailure(ids.RequestID{NodeID: nodeID})
+	require.Equal(ValidatorSignatureStatusTimedOut, appErrorStatus(timeout))
+	refused := message.InboundAppError(nodeID, ids.Empty, 1, 1, "refused")
+	require.Equal(ValidatorSignatureStatusFailed, appErrorStatus(refused))
+}
+
+func TestAggregationRetryDelay(t *testing.T) {
+	require := require.New(t)
+	config := AggregationConfig{RetryDelay: time.Second, RetryStrategy: RetryStrategyFixed}
+	require.Equal(time.Second, config.retryDelay(3))
+	config.RetryStrategy = RetryStrategyExponential
+	require.Equal(time.Second, config.retryDelay(1))
+	require.Equal(4*time.Second, config.retryDelay(3))
+}
+
+func TestInstrumentedNetwork(t *testing.T) {
+	require := require.New(t)
+	nodeIDs := []ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()}
+	silent := &silentNetwork{failTo: set.Of(nodeIDs[2])}
+	network := newInstrumentedNetwork(silent)
+	network.setConfig(AggregationConfig{
+		ValidatorTimeout:    10 * time.Millisecond,
+		MaxParallelRequests: 1,
+	})
+	network.validators = &peers.ConnectedCanonicalValidators{
+		ValidatorSet: []*warp.Validator{
+			{NodeIDs: []ids.NodeID{nodeIDs[0]}, Weight: 10},
+			{NodeIDs: []ids.NodeID{nodeIDs[1]}, Weight: 20},
+			{NodeIDs: []ids.NodeID{nodeIDs[2]}, Weight: 30},
+		},
+	}
+	responses := network.RegisterRequestID(1, 2)
+	sentTo := network.Send(nil, set.Of(nodeIDs[0], nodeIDs[1]), ids.Empty, subnets.NoOpAllower)
+	require.Equal(set.Of(nodeIDs[0], nodeIDs[1]), sentTo)
+	// requests are sent one at a time, as previous ones time out
+	for i := 0; i < 2; i++ {
+		select {
+		case response := <-responses:
+			require.Equal(message.AppErrorOp, response.Op())
+		case <-time.After(time.Second):
+			require.FailNow("validator request did not time out")
+		}
+	}
+	network.finish()
+	require.Len(silent.sentTo, 2)
+
+	responses = network.RegisterRequestID(2, 1)
+	network.Send(nil, set.Of(nodeIDs[2]), ids.Empty, subnets.NoOpAllower)
+	response := <-responses
+	require.Equal(nodeIDs[2], response.NodeID())
+	network.finish()
+
+	results := network.getResults(nil)
+	require.Len(results, 3)
+	require.Equal(ValidatorSignatureStatusTimedOut, results[0].Status)
+	require.Equal(ValidatorSignatureStatusTimedOut, results[1].Status)
+	require.Equal(ValidatorSignatureStatusSendFailed, results[2].Status)
+	require.Equal(1, results[2].Requests)
+}
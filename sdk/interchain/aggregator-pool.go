@@ -0,0 +1,111 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/sdk/network"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+)
+
+// SignatureAggregatorPool reuses the peer network of a signature aggregator for all the
+// messages signed on the same network with the same peer settings, so that signing for
+// several subnets in a row connects to the primary network validators only once.
+//
+// A nil pool is valid, and creates a new aggregator for each message
+type SignatureAggregatorPool struct {
+	lock        sync.Mutex
+	aggregators map[string]*SignatureAggregator
+}
+
+// NewSignatureAggregatorPool creates an empty pool. Call Shutdown once done with it
+func NewSignatureAggregatorPool() *SignatureAggregatorPool {
+	return &SignatureAggregatorPool{
+		aggregators: map[string]*SignatureAggregator{},
+	}
+}
+
+func signatureAggregatorPoolKey(
+	network network.Network,
+	logLevel logging.Level,
+	allowPrivatePeers bool,
+	extraPeerEndpoints []info.Peer,
+) string {
+	peers := make([]string, 0, len(extraPeerEndpoints))
+	for _, peer := range extraPeerEndpoints {
+		peers = append(peers, fmt.Sprintf("%s@%s", peer.ID, peer.PublicIP))
+	}
+	sort.Strings(peers)
+	return fmt.Sprintf("%s|%s|%t|%s", network.Endpoint, logLevel, allowPrivatePeers, strings.Join(peers, ","))
+}
+
+// Get returns an aggregator for [subnetID] and [config] that shares the peer
+// network of the previous aggregators with the same network and peer settings
+func (p *SignatureAggregatorPool) Get(
+	network network.Network,
+	logLevel logging.Level,
+	subnetID ids.ID,
+	config AggregationConfig,
+	allowPrivatePeers bool,
+	extraPeerEndpoints []info.Peer,
+) (*SignatureAggregator, error) {
+	if p == nil {
+		return NewSignatureAggregator(network, logLevel, subnetID, config, allowPrivatePeers, extraPeerEndpoints)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	key := signatureAggregatorPoolKey(network, logLevel, allowPrivatePeers, extraPeerEndpoints)
+	if sa, ok := p.aggregators[key]; ok {
+		return sa.forSubnet(subnetID, config)
+	}
+	sa, err := NewSignatureAggregator(network, logLevel, subnetID, config, allowPrivatePeers, extraPeerEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	p.aggregators[key] = sa
+	return sa, nil
+}
+
+// SignMessage is the same as the package level SignMessage, but aggregates the
+// signatures with an aggregator from the pool
+func (p *SignatureAggregatorPool) SignMessage(
+	network network.Network,
+	logLevel logging.Level,
+	subnetID ids.ID,
+	config AggregationConfig,
+	allowPrivatePeers bool,
+	extraPeerEndpoints []info.Peer,
+	msg *warp.UnsignedMessage,
+	justification []byte,
+) (*warp.Message, error) {
+	return signMessage(
+		func(config AggregationConfig) (*SignatureAggregator, error) {
+			return p.Get(network, logLevel, subnetID, config, allowPrivatePeers, extraPeerEndpoints)
+		},
+		network,
+		subnetID,
+		config,
+		msg,
+		justification,
+	)
+}
+
+// Shutdown closes the peer networks of all the aggregators in the pool
+func (p *SignatureAggregatorPool) Shutdown() {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for key, sa := range p.aggregators {
+		sa.network.Shutdown()
+		delete(p.aggregators, key)
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/sdk/network"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	awmUtils "github.com/ava-labs/icm-services/utils"
+)
+
+var (
+	presignedMessagesLock sync.Mutex
+	// presignedMessages holds manually signed messages, by unsigned message ID
+	presignedMessages = map[ids.ID]*warp.Message{}
+)
+
+// AggregationError is returned by SignMessage when not enough signatures could be
+// aggregated for a message. It contains what is needed to manually sign it
+type AggregationError struct {
+	Request ManualSigningRequest
+	Err     error
+}
+
+func (e *AggregationError) Error() string {
+	return fmt.Sprintf("failure aggregating signatures for warp message %s: %s", e.Request.MessageID, e.Err)
+}
+
+func (e *AggregationError) Unwrap() error {
+	return e.Err
+}
+
+// ManualSigningRequest describes a warp message to be manually signed by the
+// validators of [SubnetID], and the resulting signed message once the
+// signature shares are merged
+type ManualSigningRequest struct {
+	NetworkEndpoint  string `json:"networkEndpoint"`
+	SubnetID         ids.ID `json:"subnetID"`
+	QuorumPercentage uint64 `json:"quorumPercentage"`
+	MessageID        ids.ID `json:"messageID"`
+	UnsignedMessage  string `json:"unsignedMessage"`
+	Justification    string `json:"justification,omitempty"`
+	SignedMessage    string `json:"signedMessage,omitempty"`
+}
+
+// GetUnsignedMessage parses the unsigned message of the request
+func (r ManualSigningRequest) GetUnsignedMessage() (*warp.UnsignedMessage, error) {
+	msgBytes, err := hex.DecodeString(awmUtils.SanitizeHexString(r.UnsignedMessage))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode unsigned message: %w", err)
+	}
+	msg, err := warp.ParseUnsignedMessage(msgBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse unsigned message: %w", err)
+	}
+	if msg.ID() != r.MessageID {
+		return nil, fmt.Errorf("unsigned message ID %s does not match request message ID %s", msg.ID(), r.MessageID)
+	}
+	return msg, nil
+}
+
+// GetSignedMessage parses the signed message of the request, if any
+func (r ManualSigningRequest) GetSignedMessage() (*warp.Message, error) {
+	if r.SignedMessage == "" {
+		return nil, fmt.Errorf("warp message %s has not been signed yet", r.MessageID)
+	}
+	msgBytes, err := hex.DecodeString(awmUtils.SanitizeHexString(r.SignedMessage))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signed message: %w", err)
+	}
+	msg, err := warp.ParseMessage(msgBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signed message: %w", err)
+	}
+	if msg.UnsignedMessage.ID() != r.MessageID {
+		return nil, fmt.Errorf("signed message ID %s does not match request message ID %s", msg.UnsignedMessage.ID(), r.MessageID)
+	}
+	return msg, nil
+}
+
+// SignatureShare is the signature of a warp message made by a single validator
+// with its node BLS key
+type SignatureShare struct {
+	MessageID ids.ID `json:"messageID"`
+	PublicKey string `json:"publicKey"`
+	Signature string `json:"signature"`
+}
+
+// UsePresignedMessage makes SignMessage return [msg] instead of aggregating
+// signatures for its unsigned message
+func UsePresignedMessage(msg *warp.Message) {
+	presignedMessagesLock.Lock()
+	defer presignedMessagesLock.Unlock()
+	presignedMessages[msg.UnsignedMessage.ID()] = msg
+}
+
+func getPresignedMessage(msg *warp.UnsignedMessage) (*warp.Message, bool) {
+	presignedMessagesLock.Lock()
+	defer presignedMessagesLock.Unlock()
+	signedMessage, ok := presignedMessages[msg.ID()]
+	return signedMessage, ok
+}
+
+// SignMessage gets [msg] signed by the validators of [subnetID], by aggregating their
+// signatures as set by [config]. If a manually signed message was provided for [msg],
+// it is returned instead.
+//
+// On failure to aggregate signatures, an *AggregationError is returned
+func SignMessage(
+	network network.Network,
+	logLevel logging.Level,
+	subnetID ids.ID,
+	config AggregationConfig,
+	allowPrivatePeers bool,
+	extraPeerEndpoints []info.Peer,
+	msg *warp.UnsignedMessage,
+	justification []byte,
+) (*warp.Message, error) {
+	var pool *SignatureAggregatorPool
+	return pool.SignMessage(
+		network,
+		logLevel,
+		subnetID,
+		config,
+		allowPrivatePeers,
+		extraPeerEndpoints,
+		msg,
+		justification,
+	)
+}
+
+// signMessage aggregates signatures for [msg] with the aggregator given by [getAggregator],
+// unless a manually signed message was provided for it
+func signMessage(
+	getAggregator func(config AggregationConfig) (*SignatureAggregator, error),
+	network network.Network,
+	subnetID ids.ID,
+	config AggregationConfig,
+	msg *warp.UnsignedMessage,
+	justification []byte,
+) (*warp.Message, error) {
+	if signedMessage, ok := getPresignedMessage(msg); ok {
+		return signedMessage, nil
+	}
+	quorumPercentage := config.withDefaults().QuorumPercentage
+	aggregationError := func(err error) error {
+		return &AggregationError{
+			Request: ManualSigningRequest{
+				NetworkEndpoint:  network.Endpoint,
+				SubnetID:         subnetID,
+				QuorumPercentage: quorumPercentage,
+				MessageID:        msg.ID(),
+				UnsignedMessage:  hex.EncodeToString(msg.Bytes()),
+				Justification:    hex.EncodeToString(justification),
+			},
+			Err: err,
+		}
+	}
+	signatureAggregator, err := getAggregator(config)
+	if err != nil {
+		return nil, aggregationError(err)
+	}
+	signedMessage, err := signatureAggregator.Sign(msg, justification)
+	if err != nil {
+		return nil, aggregationError(err)
+	}
+	return signedMessage, nil
+}
+
+// SignShare signs [msg] with the node BLS secret key [sk]
+func SignShare(msg *warp.UnsignedMessage, sk *bls.SecretKey) SignatureShare {
+	return SignatureShare{
+		MessageID: msg.ID(),
+		PublicKey: hex.EncodeToString(bls.PublicKeyToCompressedBytes(bls.PublicFromSecretKey(sk))),
+		Signature: hex.EncodeToString(bls.SignatureToBytes(bls.Sign(sk, msg.Bytes()))),
+	}
+}
+
+// MergeShares verifies the signature [shares] of [msg] against the canonical validator
+// set [validators] (as given by warp.FlattenValidatorSet), and aggregates them into a
+// signed message. At least [quorumPercentage] of [totalWeight] must have signed
+func MergeShares(
+	msg *warp.UnsignedMessage,
+	validators []*warp.Validator,
+	totalWeight uint64,
+	quorumPercentage uint64,
+	shares []SignatureShare,
+) (*warp.Message, error) {
+	if quorumPercentage == 0 {
+		quorumPercentage = DefaultQuorumPercentage
+	} else if quorumPercentage > 100 {
+		return nil, fmt.Errorf("quorum percentage cannot be greater than 100")
+	}
+	validatorIndex := map[string]int{}
+	for i, validator := range validators {
+		validatorIndex[string(bls.PublicKeyToCompressedBytes(validator.PublicKey))] = i
+	}
+	signers := set.NewBits()
+	signatures := []*bls.Signature{}
+	signersWeight := uint64(0)
+	for _, share := range shares {
+		if share.MessageID != msg.ID() {
+			return nil, fmt.Errorf("share of public key %s signs message %s, expected %s", share.PublicKey, share.MessageID, msg.ID())
+		}
+		pkBytes, err := hex.DecodeString(awmUtils.SanitizeHexString(share.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode share public key %s: %w", share.PublicKey, err)
+		}
+		pk, err := bls.PublicKeyFromCompressedBytes(pkBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse share public key %s: %w", share.PublicKey, err)
+		}
+		i, ok := validatorIndex[string(bls.PublicKeyToCompressedBytes(pk))]
+		if !ok {
+			return nil, fmt.Errorf("share public key %s does not belong to a current validator", share.PublicKey)
+		}
+		if signers.Contains(i) {
+			return nil, fmt.Errorf("duplicated share for public key %s", share.PublicKey)
+		}
+		sigBytes, err := hex.DecodeString(awmUtils.SanitizeHexString(share.Signature))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode share signature of public key %s: %w", share.PublicKey, err)
+		}
+		sig, err := bls.SignatureFromBytes(sigBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse share signature of public key %s: %w", share.PublicKey, err)
+		}
+		if !bls.Verify(pk, sig, msg.Bytes()) {
+			return nil, fmt.Errorf("invalid share signature for public key %s", share.PublicKey)
+		}
+		signers.Add(i)
+		signatures = append(signatures, sig)
+		signersWeight += validators[i].Weight
+	}
+	if len(signatures) == 0 {
+		return nil, errors.New("no signature shares given")
+	}
+	if err := warp.VerifyWeight(signersWeight, totalWeight, quorumPercentage, 100); err != nil {
+		return nil, fmt.Errorf("not enough validator weight signed the message: %w", err)
+	}
+	aggregatedSignature, err := bls.AggregateSignatures(signatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate signatures: %w", err)
+	}
+	bitSetSignature := &warp.BitSetSignature{
+		Signers: signers.Bytes(),
+	}
+	copy(bitSetSignature.Signature[:], bls.SignatureToBytes(aggregatedSignature))
+	return warp.NewMessage(msg, bitSetSignature)
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved
+// See the file LICENSE for licensing terms.
+package interchain
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeShares(t *testing.T) {
+	require := require.New(t)
+
+	weights := []uint64{40, 30, 20, 10}
+	secretKeys := []*bls.SecretKey{}
+	vdrSet := map[ids.NodeID]*validators.GetValidatorOutput{}
+	for _, weight := range weights {
+		sk, err := bls.NewSecretKey()
+		require.NoError(err)
+		secretKeys = append(secretKeys, sk)
+		nodeID := ids.GenerateTestNodeID()
+		vdrSet[nodeID] = &validators.GetValidatorOutput{
+			NodeID:    nodeID,
+			PublicKey: bls.PublicFromSecretKey(sk),
+			Weight:    weight,
+		}
+	}
+	vdrs, totalWeight, err := warp.FlattenValidatorSet(vdrSet)
+	require.NoError(err)
+	require.Equal(uint64(100), totalWeight)
+
+	msg, err := warp.NewUnsignedMessage(1, ids.GenerateTestID(), []byte("payload"))
+	require.NoError(err)
+	shares := []SignatureShare{}
+	for _, sk := range secretKeys {
+		shares = append(shares, SignShare(msg, sk))
+	}
+
+	// 70% of the weight signs
+	signedMessage, err := MergeShares(msg, vdrs, totalWeight, 0, shares[:2])
+	require.NoError(err)
+	require.Equal(msg.ID(), signedMessage.UnsignedMessage.ID())
+	bitSetSignature, ok := signedMessage.Signature.(*warp.BitSetSignature)
+	require.True(ok)
+	signers, err := warp.FilterValidators(set.BitsFromBytes(bitSetSignature.Signers), vdrs)
+	require.NoError(err)
+	signersWeight, err := warp.SumWeight(signers)
+	require.NoError(err)
+	require.Equal(uint64(70), signersWeight)
+	aggregatedPublicKey, err := warp.AggregatePublicKeys(signers)
+	require.NoError(err)
+	aggregatedSignature, err := bls.SignatureFromBytes(bitSetSignature.Signature[:])
+	require.NoError(err)
+	require.True(bls.Verify(aggregatedPublicKey, aggregatedSignature, msg.Bytes()))
+
+	// 60% of the weight is not enough for the default quorum
+	_, err = MergeShares(msg, vdrs, totalWeight, 0, []SignatureShare{shares[1], shares[2], shares[3]})
+	require.ErrorIs(err, warp.ErrInsufficientWeight)
+	_, err = MergeShares(msg, vdrs, totalWeight, 60, []SignatureShare{shares[1], shares[2], shares[3]})
+	require.NoError(err)
+
+	// duplicated shares do not add weight
+	_, err = MergeShares(msg, vdrs, totalWeight, 0, []SignatureShare{shares[0], shares[0], shares[1]})
+	require.ErrorContains(err, "duplicated share")
+
+	// shares of another message are rejected
+	otherMsg, err := warp.NewUnsignedMessage(1, ids.GenerateTestID(), []byte("other payload"))
+	require.NoError(err)
+	_, err = MergeShares(msg, vdrs, totalWeight, 0, []SignatureShare{SignShare(otherMsg, secretKeys[0]), shares[1]})
+	require.ErrorContains(err, "signs message")
+
+	// a share with a signature for another message is rejected
+	badShare := SignShare(otherMsg, secretKeys[0])
+	badShare.MessageID = msg.ID()
+	_, err = MergeShares(msg, vdrs, totalWeight, 0, []SignatureShare{badShare, shares[1]})
+	require.ErrorContains(err, "invalid share signature")
+
+	// shares of non validators are rejected
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	_, err = MergeShares(msg, vdrs, totalWeight, 0, []SignatureShare{SignShare(msg, sk), shares[0], shares[1]})
+	require.ErrorContains(err, "does not belong to a current validator")
+}
+
+func TestManualSigningRequest(t *testing.T) {
+	require := require.New(t)
+
+	msg, err := warp.NewUnsignedMessage(1, ids.GenerateTestID(), []byte("payload"))
+	require.NoError(err)
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	nodeID := ids.GenerateTestNodeID()
+	vdrs, totalWeight, err := warp.FlattenValidatorSet(map[ids.NodeID]*validators.GetValidatorOutput{
+		nodeID: {NodeID: nodeID, PublicKey: bls.PublicFromSecretKey(sk), Weight: 1},
+	})
+	require.NoError(err)
+	signedMessage, err := MergeShares(msg, vdrs, totalWeight, 0, []SignatureShare{SignShare(msg, sk)})
+	require.NoError(err)
+
+	request := ManualSigningRequest{
+		MessageID:       msg.ID(),
+		UnsignedMessage: "0x" + hex.EncodeToString(msg.Bytes()),
+	}
+	parsedMsg, err := request.GetUnsignedMessage()
+	require.NoError(err)
+	require.Equal(msg.Bytes(), parsedMsg.Bytes())
+	_, err = request.GetSignedMessage()
+	require.Error(err)
+	request.SignedMessage = hex.EncodeToString(signedMessage.Bytes())
+	parsedSignedMessage, err := request.GetSignedMessage()
+	require.NoError(err)
+	require.Equal(signedMessage.Bytes(), parsedSignedMessage.Bytes())
+
+	request.MessageID = ids.GenerateTestID()
+	_, err = request.GetUnsignedMessage()
+	require.Error(err)
+	_, err = request.GetSignedMessage()
+	require.Error(err)
+}
@@ -11,8 +11,8 @@ import (
 	"github.com/ava-labs/icm-services/signature-aggregator/aggregator"
 	"github.com/ava-labs/icm-services/signature-aggregator/metrics"
 
-	"github.com/ava-labs/avalanche-cli/pkg/constants"
-	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/sdk/constants"
+	"github.com/ava-labs/avalanche-cli/sdk/network"
 	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
@@ -36,19 +36,21 @@ type SignatureAggregator struct {
 	subnetID         ids.ID
 	quorumPercentage uint64
 	aggregator       *aggregator.SignatureAggregator
+	network          *instrumentedNetwork
+	config           AggregationConfig
 }
 
 // createAppRequestNetwork creates a new AppRequestNetwork for the given network and log level.
 //
 // Parameters:
-// - network: The network for which the AppRequestNetwork is created. It should be of type models.Network.
+// - network: The network for which the AppRequestNetwork is created. It should be of type network.Network.
 // - logLevel: The log level for the AppRequestNetwork. It should be of type logging.Level.
 //
 // Returns:
 // - peers.AppRequestNetwork: The created AppRequestNetwork, or nil if an error occurred.
 // - error: An error if the creation of the AppRequestNetwork failed.
 func createAppRequestNetwork(
-	network models.Network,
+	network network.Network,
 	logLevel logging.Level,
 	registerer prometheus.Registerer,
 	allowPrivatePeers bool,
@@ -80,7 +82,7 @@ func createAppRequestNetwork(
 // network is the network to create the aggregator for.
 // logger is the logger to use for logging.
 // subnetID is the subnet ID to create the aggregator for.
-// quorumPercentage is the quorum percentage to use for the aggregator.
+// config is the quorum and the validator requests settings to use for the aggregator.
 //
 // Returns a new SignatureAggregator instance, or an error if initialization fails.
 func initSignatureAggregator(
@@ -88,18 +90,14 @@ func initSignatureAggregator(
 	logger logging.Logger,
 	registerer prometheus.Registerer,
 	subnetID ids.ID,
-	quorumPercentage uint64,
+	config AggregationConfig,
 	etnaTime time.Time,
 ) (*SignatureAggregator, error) {
 	sa := &SignatureAggregator{}
-	// set quorum percentage
-	sa.quorumPercentage = quorumPercentage
-	if quorumPercentage == 0 {
-		sa.quorumPercentage = DefaultQuorumPercentage
-	} else if quorumPercentage > 100 {
-		return nil, fmt.Errorf("quorum percentage cannot be greater than 100")
+	if err := sa.setTarget(subnetID, config); err != nil {
+		return nil, err
 	}
-	sa.subnetID = subnetID
+	sa.network = newInstrumentedNetwork(network)
 
 	messageCreator, err := message.NewCreator(
 		logger,
@@ -113,7 +111,7 @@ func initSignatureAggregator(
 
 	metricsInstance := metrics.NewSignatureAggregatorMetrics(registerer)
 	signatureAggregator, err := aggregator.NewSignatureAggregator(
-		network,
+		sa.network,
 		logger,
 		messageCreator,
 		DefaultSignatureCacheSize,
@@ -127,20 +125,42 @@ func initSignatureAggregator(
 	return sa, nil
 }
 
+// setTarget sets the subnet whose validators sign, the percentage of its stake
+// needed for the signature to be valid, and how the validators are queried
+func (s *SignatureAggregator) setTarget(subnetID ids.ID, config AggregationConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	s.config = config.withDefaults()
+	s.quorumPercentage = s.config.QuorumPercentage
+	s.subnetID = subnetID
+	return nil
+}
+
+// forSubnet returns an aggregator for [subnetID] and [config] that
+// shares the peer network and the signature cache of [s]
+func (s *SignatureAggregator) forSubnet(subnetID ids.ID, config AggregationConfig) (*SignatureAggregator, error) {
+	sa := *s
+	if err := sa.setTarget(subnetID, config); err != nil {
+		return nil, err
+	}
+	return &sa, nil
+}
+
 // NewSignatureAggregator creates a new signature aggregator instance.
 //
 // network is the network to create the aggregator for.
 // logger is the logger to use for logging.
 // logLevel is the log level to use for logging.
 // subnetID is the subnet ID to create the aggregator for.
-// quorumPercentage is the quorum percentage to use for the aggregator.
+// config is the quorum and the validator requests settings to use for the aggregator.
 //
 // Returns a new signature aggregator instance, or an error if creation fails.
 func NewSignatureAggregator(
-	network models.Network,
+	network network.Network,
 	logLevel logging.Level,
 	subnetID ids.ID,
-	quorumPercentage uint64,
+	config AggregationConfig,
 	allowPrivatePeers bool,
 	extraPeerEndpoints []info.Peer,
 ) (*SignatureAggregator, error) {
@@ -158,7 +178,7 @@ func NewSignatureAggregator(
 		),
 	)
 	etnaTime := constants.EtnaActivationTime[network.ID]
-	return initSignatureAggregator(peerNetwork, logger, registerer, subnetID, quorumPercentage, etnaTime)
+	return initSignatureAggregator(peerNetwork, logger, registerer, subnetID, config, etnaTime)
 }
 
 // AggregateSignatures aggregates signatures for a given message and justification.
@@ -202,6 +222,7 @@ func (s *SignatureAggregator) AggregateSignatures(
 }
 
 // Sign aggregates signatures for a given message and justification.
+// If the quorum is not reached, it retries as indicated by the aggregation config.
 //
 // msg is the message to be signed
 // justification is the justification for the signature.
@@ -210,10 +231,26 @@ func (s *SignatureAggregator) Sign(
 	msg *warp.UnsignedMessage,
 	justification []byte,
 ) (*warp.Message, error) {
-	return s.aggregator.CreateSignedMessage(
-		msg,
-		justification,
-		s.subnetID,
-		s.quorumPercentage,
+	var (
+		signedMessage *warp.Message
+		err           error
 	)
+	s.network.setConfig(s.config)
+	for attempt := 1; attempt <= s.config.MaxAttempts; attempt++ {
+		signedMessage, err = s.aggregator.CreateSignedMessage(
+			msg,
+			justification,
+			s.subnetID,
+			s.quorumPercentage,
+		)
+		s.network.finish()
+		if err == nil || attempt == s.config.MaxAttempts {
+			break
+		}
+		time.Sleep(s.config.retryDelay(attempt))
+	}
+	if s.config.ResultsHandler != nil {
+		s.config.ResultsHandler(s.network.getResults(signedMessage))
+	}
+	return signedMessage, err
 }
@@ -6,7 +6,10 @@ import (
 	"testing"
 	"time"
 
+	networkSDK "github.com/ava-labs/avalanche-cli/sdk/network"
+	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/peer"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/icm-services/peers/mocks"
@@ -27,9 +30,9 @@ func instantiateAggregator(t *testing.T) (
 	aggregator, err := initSignatureAggregator(
 		mockNetwork,
 		logging.NoLog{},
-		prometheus.DefaultRegisterer,
+		prometheus.NewRegistry(),
 		subnetID,
-		DefaultQuorumPercentage,
+		AggregationConfig{},
 		time.Time{},
 	)
 	require.Equal(t, err, nil)
@@ -47,3 +50,53 @@ func TestSignatureAggregator(t *testing.T) {
 	require.Nil(t, err)
 	require.NotNil(t, msg)
 }
+
+func TestSignatureAggregatorPool(t *testing.T) {
+	require := require.New(t)
+	sa, mockNetwork, err := instantiateAggregator(t)
+	require.NoError(err)
+	network := networkSDK.FujiNetwork()
+	pool := NewSignatureAggregatorPool()
+	pool.aggregators[signatureAggregatorPoolKey(network, logging.Off, true, nil)] = sa
+
+	otherSubnetID := ids.GenerateTestID()
+	other, err := pool.Get(network, logging.Off, otherSubnetID, AggregationConfig{QuorumPercentage: 80}, true, nil)
+	require.NoError(err)
+	require.Equal(otherSubnetID, other.subnetID)
+	require.Equal(uint64(80), other.quorumPercentage)
+	require.Same(sa.aggregator, other.aggregator)
+	require.Same(sa.network, other.network)
+	// the pooled aggregator keeps its own target
+	require.Equal(subnetID, sa.subnetID)
+	require.Equal(DefaultQuorumPercentage, sa.quorumPercentage)
+
+	_, err = pool.Get(network, logging.Off, otherSubnetID, AggregationConfig{QuorumPercentage: 101}, true, nil)
+	require.ErrorContains(err, "quorum percentage cannot be greater than 100")
+
+	mockNetwork.EXPECT().Shutdown()
+	pool.Shutdown()
+	require.Empty(pool.aggregators)
+}
+
+func TestSignatureAggregatorPoolKey(t *testing.T) {
+	require := require.New(t)
+	network := networkSDK.FujiNetwork()
+	peer1 := info.Peer{Info: peer.Info{ID: ids.GenerateTestNodeID()}}
+	peer2 := info.Peer{Info: peer.Info{ID: ids.GenerateTestNodeID()}}
+	require.Equal(
+		signatureAggregatorPoolKey(network, logging.Off, true, []info.Peer{peer1, peer2}),
+		signatureAggregatorPoolKey(network, logging.Off, true, []info.Peer{peer2, peer1}),
+	)
+	require.NotEqual(
+		signatureAggregatorPoolKey(network, logging.Off, true, []info.Peer{peer1}),
+		signatureAggregatorPoolKey(network, logging.Off, true, []info.Peer{peer1, peer2}),
+	)
+	require.NotEqual(
+		signatureAggregatorPoolKey(network, logging.Off, true, nil),
+		signatureAggregatorPoolKey(network, logging.Off, false, nil),
+	)
+	require.NotEqual(
+		signatureAggregatorPoolKey(network, logging.Off, true, nil),
+		signatureAggregatorPoolKey(networkSDK.MainnetNetwork(), logging.Off, true, nil),
+	)
+}
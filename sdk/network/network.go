@@ -14,6 +14,7 @@ const (
 	Mainnet
 	Fuji
 	Devnet
+	Local
 )
 
 const (
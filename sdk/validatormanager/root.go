@@ -7,9 +7,9 @@ import (
 	"fmt"
 	"math/big"
 
-	"github.com/ava-labs/avalanche-cli/pkg/contract"
-	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/sdk/contract"
 	"github.com/ava-labs/avalanche-cli/sdk/interchain"
+	"github.com/ava-labs/avalanche-cli/sdk/network"
 	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/ids"
 	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
@@ -22,6 +22,13 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// churn settings validator managers are initialized with: no churn period, so each validator
+// set change can be of up to 20% of the L1 weight
+const (
+	DefaultChurnPeriodSeconds     = uint64(0)
+	DefaultMaximumChurnPercentage = uint8(20)
+)
+
 type ValidatorManagerSettings struct {
 	SubnetID               [32]byte
 	ChurnPeriodSeconds     uint64
@@ -39,11 +46,28 @@ type NativeTokenValidatorManagerSettings struct {
 	RewardCalculator         common.Address
 }
 
+// ERC20TokenValidatorManagerSettings are the initialize settings of the ERC20 token staking
+// manager, built from a newer ICM contracts commit than the native token one, that adds
+// UptimeBlockchainID
+type ERC20TokenValidatorManagerSettings struct {
+	BaseSettings             ValidatorManagerSettings
+	MinimumStakeAmount       *big.Int
+	MaximumStakeAmount       *big.Int
+	MinimumStakeDuration     uint64
+	MinimumDelegationFeeBips uint16
+	MaximumStakeMultiplier   uint8
+	WeightToValueFactor      *big.Int
+	RewardCalculator         common.Address
+	UptimeBlockchainID       [32]byte
+}
+
 const (
 	ValidatorContractAddress  = "0xC0DEBA5E0000000000000000000000000000000"
 	ProxyContractAddress      = "0xFEEDC0DE0000000000000000000000000000000"
 	ProxyAdminContractAddress = "0xC0FFEE1234567890aBcDEF1234567890AbCdEf34"
 	RewardCalculatorAddress   = "0xDEADC0DE0000000000000000000000000000000"
+	// ValidatorMessages library, linked by the ERC20 token staking manager bytecode at this address
+	ValidatorMessagesContractAddress = "0x9C00629cE712B0255b17A4a657171Acd15720B8C"
 
 	DefaultPoSMinimumStakeAmount     = 1
 	DefaultPoSMaximumStakeAmount     = 1000
@@ -117,7 +141,7 @@ var (
 		"InvalidConversionID(bytes32,bytes32)":         ErrInvalidConversionID,
 		"InvalidDelegationFee(uint16)":                 ErrInvalidDelegationFee,
 		"InvalidDelegationID(bytes32)":                 ErrInvalidDelegationID,
-		"InvalidDelegatorStatus(DelegatorStatus)":      ErrInvalidDelegatorStatus,
+		"InvalidDelegatorStatus(uint8)":                ErrInvalidDelegatorStatus,
 		"InvalidMessageLength(uint32,uint32)":          ErrInvalidMessageLength,
 		"InvalidMessageType()":                         ErrInvalidMessageType,
 		"InvalidMinStakeDuration(uint64)":              ErrInvalidMinStakeDuration,
@@ -146,6 +170,8 @@ type PoSParams struct {
 	MaximumStakeMultiplier  uint8
 	WeightToValueFactor     *big.Int
 	RewardCalculatorAddress string
+	// ERC20 token staked on an ERC20 token staking manager. Empty for native token staking
+	StakingTokenAddress string
 }
 
 func (p PoSParams) Verify() error {
@@ -164,6 +190,9 @@ func (p PoSParams) Verify() error {
 	if p.RewardCalculatorAddress == "" {
 		return fmt.Errorf("reward calculator address cannot be empty")
 	}
+	if p.StakingTokenAddress != "" && !common.IsHexAddress(p.StakingTokenAddress) {
+		return fmt.Errorf("invalid staking token address %s", p.StakingTokenAddress)
+	}
 	return nil
 }
 
@@ -172,11 +201,13 @@ func (p PoSParams) Verify() error {
 // initializing validators set
 // the message specifies [subnetID] that is being converted
 // together with the validator's manager [managerBlockchainID],
-// [managerAddress], and the initial list of [validators]
+// [managerAddress], and the initial list of [validators].
+// Signatures are aggregated with [aggregatorPool], that may be nil
 func GetPChainSubnetConversionWarpMessage(
-	network models.Network,
+	aggregatorPool *interchain.SignatureAggregatorPool,
+	network network.Network,
 	aggregatorLogLevel logging.Level,
-	aggregatorQuorumPercentage uint64,
+	aggregationConfig interchain.AggregationConfig,
 	aggregatorAllowPrivateIPs bool,
 	aggregatorExtraPeerEndpoints []info.Peer,
 	subnetID ids.ID,
@@ -221,18 +252,16 @@ func GetPChainSubnetConversionWarpMessage(
 	if err != nil {
 		return nil, err
 	}
-	signatureAggregator, err := interchain.NewSignatureAggregator(
+	return aggregatorPool.SignMessage(
 		network,
 		aggregatorLogLevel,
 		subnetID,
-		aggregatorQuorumPercentage,
+		aggregationConfig,
 		aggregatorAllowPrivateIPs,
 		aggregatorExtraPeerEndpoints,
+		subnetConversionUnsignedMessage,
+		subnetID[:],
 	)
-	if err != nil {
-		return nil, err
-	}
-	return signatureAggregator.Sign(subnetConversionUnsignedMessage, subnetID[:])
 }
 
 // InitializeValidatorsSet calls poa manager validators set init method,
@@ -0,0 +1,82 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validatormanager
+
+import (
+	"sort"
+
+	"golang.org/x/exp/maps"
+)
+
+// MethodSignatures lists the methods of the validator manager contracts, and of the
+// ownable and proxy admin contracts that govern them, so their calldata can be decoded
+var MethodSignatures = []string{
+	// PoA validator manager
+	"initialize((bytes32,uint64,uint8),address)",
+	// PoS validator manager
+	"initialize(((bytes32,uint64,uint8),uint256,uint256,uint64,uint16,uint8,uint256,address))",
+	"initializeValidatorSet((bytes32,bytes32,address,[(bytes,bytes,uint64)]),uint32)",
+	"initializeValidatorRegistration((bytes,bytes,uint64,(uint32,[address]),(uint32,[address])),uint64)",
+	"initializeValidatorRegistration((bytes,bytes,uint64,(uint32,[address]),(uint32,[address])),uint16,uint64)",
+	"completeValidatorRegistration(uint32)",
+	"initializeEndValidation(bytes32)",
+	"initializeEndValidation(bytes32,bool,uint32)",
+	"forceInitializeEndValidation(bytes32,bool,uint32)",
+	"completeEndValidation(uint32)",
+	"initializeDelegatorRegistration(bytes32)",
+	"completeDelegatorRegistration(bytes32,uint32)",
+	"initializeEndDelegation(bytes32,bool,uint32)",
+	"forceInitializeEndDelegation(bytes32,bool,uint32)",
+	"completeEndDelegation(bytes32,uint32)",
+	// ownable
+	"transferOwnership(address)",
+	"renounceOwnership()",
+	// proxy admin
+	"upgrade(address,address)",
+	"upgradeAndCall(address,address,bytes)",
+}
+
+// signatures of the events emitted by the validator manager contracts along the lifecycle
+// of validators and delegations
+const (
+	InitialValidatorCreatedEventSignature     = "InitialValidatorCreated(bytes32 indexed,bytes indexed,uint64)"
+	ValidationPeriodCreatedEventSignature     = "ValidationPeriodCreated(bytes32 indexed,bytes indexed,bytes32 indexed,uint64,uint64)"
+	ValidationPeriodRegisteredEventSignature  = "ValidationPeriodRegistered(bytes32 indexed,uint64,uint256)"
+	ValidatorRemovalInitializedEventSignature = "ValidatorRemovalInitialized(bytes32 indexed,bytes32 indexed,uint64,uint256)"
+	ValidationPeriodEndedEventSignature       = "ValidationPeriodEnded(bytes32 indexed,uint8 indexed)"
+	ValidatorWeightUpdateEventSignature       = "ValidatorWeightUpdate(bytes32 indexed,uint64 indexed,uint64,bytes32)"
+	UptimeUpdatedEventSignature               = "UptimeUpdated(bytes32 indexed,uint64)"
+	DelegatorAddedEventSignature              = "DelegatorAdded(bytes32 indexed,bytes32 indexed,address indexed,uint64,uint64,uint64,bytes32)"
+	DelegatorRegisteredEventSignature         = "DelegatorRegistered(bytes32 indexed,bytes32 indexed,uint256)"
+	DelegatorRemovalInitializedEventSignature = "DelegatorRemovalInitialized(bytes32 indexed,bytes32 indexed)"
+	DelegationEndedEventSignature             = "DelegationEnded(bytes32 indexed,bytes32 indexed,uint256,uint256)"
+)
+
+// EventSignatures lists the events of the validator manager contracts, and of the
+// ownable and proxy contracts that govern them, so their logs can be decoded
+var EventSignatures = []string{
+	InitialValidatorCreatedEventSignature,
+	ValidationPeriodCreatedEventSignature,
+	ValidationPeriodRegisteredEventSignature,
+	ValidatorRemovalInitializedEventSignature,
+	ValidationPeriodEndedEventSignature,
+	ValidatorWeightUpdateEventSignature,
+	UptimeUpdatedEventSignature,
+	DelegatorAddedEventSignature,
+	DelegatorRegisteredEventSignature,
+	DelegatorRemovalInitializedEventSignature,
+	DelegationEndedEventSignature,
+	"Initialized(uint64)",
+	"OwnershipTransferred(address indexed,address indexed)",
+	"Upgraded(address indexed)",
+	"AdminChanged(address,address)",
+}
+
+// ErrorSignatures returns the signatures of the validator manager errors, so
+// revert data can be decoded
+func ErrorSignatures() []string {
+	signatures := maps.Keys(ErrorSignatureToError)
+	sort.Strings(signatures)
+	return signatures
+}
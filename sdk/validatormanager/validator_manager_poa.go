@@ -4,7 +4,7 @@
 package validatormanager
 
 import (
-	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"github.com/ava-labs/avalanche-cli/sdk/contract"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ethereum/go-ethereum/common"
 
@@ -21,14 +21,10 @@ func PoAValidatorManagerInitialize(
 	subnetID ids.ID,
 	ownerAddress common.Address,
 ) (*types.Transaction, *types.Receipt, error) {
-	const (
-		defaultChurnPeriodSeconds     = uint64(0)
-		defaultMaximumChurnPercentage = uint8(20)
-	)
 	params := ValidatorManagerSettings{
 		SubnetID:               subnetID,
-		ChurnPeriodSeconds:     defaultChurnPeriodSeconds,
-		MaximumChurnPercentage: defaultMaximumChurnPercentage,
+		ChurnPeriodSeconds:     DefaultChurnPeriodSeconds,
+		MaximumChurnPercentage: DefaultMaximumChurnPercentage,
 	}
 	return contract.TxToMethod(
 		rpcURL,
@@ -4,7 +4,9 @@
 package validatormanager
 
 import (
-	"github.com/ava-labs/avalanche-cli/pkg/contract"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/sdk/contract"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -21,15 +23,10 @@ func PoSValidatorManagerInitialize(
 	if err := posParams.Verify(); err != nil {
 		return nil, nil, err
 	}
-	var (
-		defaultChurnPeriodSeconds     = uint64(0) // no churn period
-		defaultMaximumChurnPercentage = uint8(20) // 20% of the validator set can be churned per churn period
-	)
-
 	baseSettings := ValidatorManagerSettings{
 		SubnetID:               subnetID,
-		ChurnPeriodSeconds:     defaultChurnPeriodSeconds,
-		MaximumChurnPercentage: defaultMaximumChurnPercentage,
+		ChurnPeriodSeconds:     DefaultChurnPeriodSeconds,
+		MaximumChurnPercentage: DefaultMaximumChurnPercentage,
 	}
 
 	params := NativeTokenValidatorManagerSettings{
@@ -54,3 +51,51 @@ func PoSValidatorManagerInitialize(
 		params,
 	)
 }
+
+// initializes the ERC20 token staking manager [managerAddress] at [rpcURL], to
+// manage validators on [subnetID] using PoS specific settings, and the staking token
+// of [posParams]. Uptime proofs are expected to come from [blockchainID]
+func ERC20PoSValidatorManagerInitialize(
+	rpcURL string,
+	managerAddress common.Address,
+	privateKey string,
+	subnetID [32]byte,
+	blockchainID [32]byte,
+	posParams PoSParams,
+) (*types.Transaction, *types.Receipt, error) {
+	if err := posParams.Verify(); err != nil {
+		return nil, nil, err
+	}
+	if posParams.StakingTokenAddress == "" {
+		return nil, nil, fmt.Errorf("staking token address cannot be empty")
+	}
+	baseSettings := ValidatorManagerSettings{
+		SubnetID:               subnetID,
+		ChurnPeriodSeconds:     DefaultChurnPeriodSeconds,
+		MaximumChurnPercentage: DefaultMaximumChurnPercentage,
+	}
+
+	params := ERC20TokenValidatorManagerSettings{
+		BaseSettings:             baseSettings,
+		MinimumStakeAmount:       posParams.MinimumStakeAmount,
+		MaximumStakeAmount:       posParams.MaximumStakeAmount,
+		MinimumStakeDuration:     posParams.MinimumStakeDuration,
+		MinimumDelegationFeeBips: posParams.MinimumDelegationFee,
+		MaximumStakeMultiplier:   posParams.MaximumStakeMultiplier,
+		WeightToValueFactor:      posParams.WeightToValueFactor,
+		RewardCalculator:         common.HexToAddress(posParams.RewardCalculatorAddress),
+		UptimeBlockchainID:       blockchainID,
+	}
+
+	return contract.TxToMethod(
+		rpcURL,
+		privateKey,
+		managerAddress,
+		nil,
+		"initialize ERC20 Token PoS manager",
+		ErrorSignatureToError,
+		"initialize(((bytes32,uint64,uint8),uint256,uint256,uint64,uint16,uint8,uint256,address,bytes32),address)",
+		params,
+		common.HexToAddress(posParams.StakingTokenAddress),
+	)
+}
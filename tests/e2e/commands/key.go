@@ -164,3 +164,17 @@ func KeyTransferReceive(keyName string, amount string, recoveryStep string) (str
 	out, err := cmd.CombinedOutput()
 	return string(out), err
 }
+
+/* #nosec G204 */
+func FundKeyOnFuji(keyName string) (string, error) {
+	cmd := exec.Command(
+		CLIBinary,
+		KeyCmd,
+		"fund",
+		keyName,
+		"--fuji",
+		"--"+constants.SkipUpdateFlag,
+	)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
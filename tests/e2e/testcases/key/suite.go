@@ -306,4 +306,19 @@ var _ = ginkgo.Describe("[Key]", func() {
 		gomega.Expect(ewoqKeyBalance1 - ewoqKeyBalance3).Should(gomega.Equal(feeNAvax + amountNAvax))
 		gomega.Expect(keyBalance3 - keyBalance1).Should(gomega.Equal(amountNAvax))
 	})
+
+	ginkgo.It("gets an answer from the real Fuji faucet", func() {
+		_, err := commands.CreateKey(keyName)
+		gomega.Expect(err).Should(gomega.BeNil())
+
+		// without a captcha answer the faucet must refuse to send funds, answering
+		// with its own rejection message instead of an unexpected response
+		output, err := commands.FundKeyOnFuji(keyName)
+		gomega.Expect(err).ShouldNot(gomega.BeNil())
+		gomega.Expect(output).Should(gomega.Or(
+			gomega.ContainSubstring("faucet request rejected"),
+			gomega.ContainSubstring("faucet rate limit reached"),
+		))
+		gomega.Expect(output).ShouldNot(gomega.ContainSubstring("unexpected faucet response"))
+	})
 })